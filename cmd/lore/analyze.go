@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze the world's facts",
+	}
+
+	cmd.AddCommand(
+		newAnalyzeClustersCmd(),
+		newAnalyzeSubjectsCmd(),
+		newAnalyzeTranslationsCmd(),
+	)
+
+	return cmd
+}
+
+type analyzeClustersFlags struct {
+	k               int
+	limit           int
+	representatives int
+	minSize         int
+	output          string
+}
+
+func newAnalyzeClustersCmd() *cobra.Command {
+	var flags analyzeClustersFlags
+
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Group facts into themes by their embeddings",
+		Long: `Clusters the world's facts by their embeddings and names each cluster
+with the LLM, revealing the themes the world covers and the orphaned topics
+only a handful of facts touch.
+
+The report is Markdown, listing for each cluster its most common subjects
+and the facts closest to its centre. Clustering is repeatable: the same facts
+and --k always give the same clusters.
+
+Examples:
+  lore analyze clusters -w myworld
+  lore analyze clusters -w myworld --k 12 -o themes.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := services.ClusterOptions{
+				K:               flags.k,
+				Limit:           flags.limit,
+				Representatives: flags.representatives,
+				MinTopicSize:    flags.minSize,
+			}
+
+			return withClusterService(func(svc *services.ClusterService, alias string) error {
+				report, err := svc.Cluster(ctx, alias, opts)
+				if err != nil {
+					return err
+				}
+				return writeClusterReport(flags.output, globalWorld, report)
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&flags.k, "k", "k", 0, "Number of clusters (0 = chosen from the fact count)")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", services.DefaultClusterLimit, "Maximum number of facts to cluster")
+	cmd.Flags().IntVarP(&flags.representatives, "representatives", "r", services.DefaultClusterRepresentatives, "Facts shown per cluster")
+	cmd.Flags().IntVar(&flags.minSize, "min-size", services.DefaultMinTopicSize, "Clusters with fewer facts are reported as orphaned topics")
+	cmd.Flags().StringVarP(&flags.output, "output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+// writeClusterReport writes the report as Markdown to output, or stdout if
+// output is empty.
+func writeClusterReport(output, world string, report *services.ClusterReport) (err error) {
+	if output == "" {
+		return formatClusterReport(os.Stdout, world, report)
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing file: %w", cerr)
+		}
+	}()
+
+	if err := formatClusterReport(f, world, report); err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+
+	fmt.Printf("Wrote %d clusters to %s\n", len(report.Clusters), output)
+	return nil
+}
+
+// formatClusterReport writes the report as Markdown, with topics first and
+// orphaned topics in a section of their own.
+func formatClusterReport(w io.Writer, world string, report *services.ClusterReport) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Themes in %s\n\n", world)
+	if report.Facts == 0 {
+		b.WriteString("No facts with embeddings to cluster.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "Clustered %d facts into %d clusters.", report.Facts, len(report.Clusters))
+	if report.Truncated {
+		b.WriteString(" More facts exist; raise --limit to include them.")
+	}
+	b.WriteString("\n")
+
+	var topics, orphans []services.Cluster
+	for _, c := range report.Clusters {
+		if c.Orphaned {
+			orphans = append(orphans, c)
+		} else {
+			topics = append(topics, c)
+		}
+	}
+
+	for i := range topics {
+		writeCluster(&b, "##", i+1, &topics[i])
+	}
+
+	if len(orphans) > 0 {
+		b.WriteString("\n## Orphaned topics\n\n")
+		b.WriteString("Themes only a few facts touch. They may be underdeveloped or belong with a larger topic.\n")
+		for i := range orphans {
+			writeCluster(&b, "###", len(topics)+i+1, &orphans[i])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCluster writes one cluster under a heading of the given level.
+func writeCluster(b *strings.Builder, heading string, num int, c *services.Cluster) {
+	label := c.Label
+	if label == "" {
+		label = "Untitled"
+	}
+	noun := "facts"
+	if c.Size == 1 {
+		noun = "fact"
+	}
+	fmt.Fprintf(b, "\n%s %d. %s (%d %s)\n\n", heading, num, escapeMarkdown(label), c.Size, noun)
+
+	subjects := make([]string, len(c.Subjects))
+	for i, s := range c.Subjects {
+		subjects[i] = fmt.Sprintf("%s (%d)", escapeMarkdown(s.Subject), s.Count)
+	}
+	fmt.Fprintf(b, "Subjects: %s\n\n", strings.Join(subjects, ", "))
+
+	for i := range c.Representatives {
+		b.WriteString("- " + clusterFactLine(&c.Representatives[i]) + "\n")
+	}
+}
+
+// clusterFactLine renders a fact as a single Markdown list item.
+func clusterFactLine(f *entities.Fact) string {
+	line := fmt.Sprintf("**%s** %s %s", escapeMarkdown(f.Subject), escapeMarkdown(f.Predicate), escapeMarkdown(f.Object))
+	if f.SourceFile != "" {
+		line += fmt.Sprintf(" _(%s)_", escapeMarkdown(f.SourceFile))
+	}
+	return line
+}
+
+type analyzeSubjectsFlags struct {
+	exact  bool
+	apply  bool
+	groups []int
+	yes    bool
+}
+
+func newAnalyzeSubjectsCmd() *cobra.Command {
+	var flags analyzeSubjectsFlags
+
+	cmd := &cobra.Command{
+		Use:   "subjects",
+		Short: "Find subjects written several ways and merge them",
+		Long: `Groups subjects that differ only in case, punctuation, a leading article,
+or small typos ("The Shire", "Shire", "the shire") and proposes the most used
+spelling of each group as its canonical name.
+
+With --apply, every fact whose subject or object is another spelling is
+rewritten to the canonical name (each change is versioned), and the
+spellings' entities are merged into one, keeping their relationships.
+--group applies only the numbered groups; --exact leaves out groups that
+only match allowing for typos.
+
+Examples:
+  lore analyze subjects -w myworld
+  lore analyze subjects -w myworld --apply
+  lore analyze subjects -w myworld --apply --group 1,3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(flags.groups) > 0 && !flags.apply {
+				return entities.Errorf(entities.ErrValidation, "--group requires --apply")
+			}
+			ctx := cmd.Context()
+
+			return withCanonicalService(func(svc *services.CanonicalService) error {
+				report, err := svc.Report(ctx, services.SubjectReportOptions{Exact: flags.exact})
+				if err != nil {
+					return err
+				}
+				displaySubjectReport(report)
+				if !flags.apply || len(report.Groups) == 0 {
+					if len(report.Groups) > 0 {
+						fmt.Println("\nRun with --apply to rename them all, or --apply --group <n> to pick groups.")
+					}
+					return nil
+				}
+
+				groups, err := selectSubjectGroups(report.Groups, flags.groups)
+				if err != nil {
+					return err
+				}
+				renamed := 0
+				for i := range groups {
+					renamed += groups[i].Renamed()
+				}
+				if !flags.yes && !confirmAction(fmt.Sprintf("\nRename %d facts in %d groups?", renamed, len(groups))) {
+					fmt.Println("Cancelled.")
+					return nil
+				}
+
+				result, err := svc.Apply(ctx, globalWorld, groups)
+				fmt.Printf("Rewrote %d facts, merged %d entities, renamed %d entities.\n",
+					result.FactsRewritten, result.EntitiesMerged, result.EntitiesRenamed)
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.exact, "exact", false, "Only group subjects that match without typos")
+	cmd.Flags().BoolVar(&flags.apply, "apply", false, "Rename every spelling to its group's canonical name")
+	cmd.Flags().IntSliceVarP(&flags.groups, "group", "g", nil, "With --apply, only apply these group numbers")
+	cmd.Flags().BoolVarP(&flags.yes, "yes", "y", false, "Apply without asking for confirmation")
+
+	return cmd
+}
+
+// displaySubjectReport prints each group with its proposed canonical name
+// and how many facts use each spelling.
+func displaySubjectReport(report *services.SubjectReport) {
+	if len(report.Groups) == 0 {
+		fmt.Printf("No duplicate subjects among %d subjects in %d facts.\n", report.Subjects, report.Facts)
+		return
+	}
+
+	fmt.Printf("Found %d subjects written several ways (%d subjects in %d facts):\n", len(report.Groups), report.Subjects, report.Facts)
+	for i := range report.Groups {
+		g := &report.Groups[i]
+		var fuzzy string
+		if g.Fuzzy {
+			fuzzy = " [fuzzy]"
+		}
+		fmt.Printf("\n%d. %s%s\n", i+1, g.Canonical, fuzzy)
+		for _, v := range g.Variants {
+			marker := "  "
+			if v.Name == g.Canonical {
+				marker = "* "
+			}
+			fmt.Printf("   %s%-30s %d facts\n", marker, v.Name, v.Facts)
+		}
+	}
+}
+
+type analyzeTranslationsFlags struct {
+	threshold float64
+	limit     int
+	dryRun    bool
+}
+
+func newAnalyzeTranslationsCmd() *cobra.Command {
+	var flags analyzeTranslationsFlags
+
+	cmd := &cobra.Command{
+		Use:   "translations",
+		Short: "Link facts that state the same thing in different languages",
+		Long: `For bilingual worlds, finds facts that say the same thing in two languages,
+such as "Jean vit à Paris" and "Jean lives in Paris", and links them as
+translations of each other.
+
+Each fact is compared with its nearest facts by embedding, of any type, and
+pairs at or above --threshold are confirmed by the LLM. Linked translations
+are no longer recorded as contradictions by lore check. A multilingual
+embedding model finds the most pairs; lower --threshold if few are found.
+
+Examples:
+  lore analyze translations -w myworld
+  lore analyze translations -w myworld --threshold 0.75 --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := services.TranslationOptions{
+				Threshold: flags.threshold,
+				Limit:     flags.limit,
+				DryRun:    flags.dryRun,
+			}
+
+			return withTranslationService(func(svc *services.TranslationService, alias string) error {
+				result, err := svc.Detect(ctx, alias, opts)
+				if err != nil {
+					return err
+				}
+				displayTranslations(result, flags.dryRun)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().Float64Var(&flags.threshold, "threshold", services.DefaultTranslationThreshold, "Minimum similarity (0-1) of pairs checked by the LLM")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", 0, "Maximum number of facts to scan (0 = all)")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Show translations found without linking them")
+
+	return cmd
+}
+
+// displayTranslations prints the confirmed translation pairs and how many
+// were linked.
+func displayTranslations(result *services.TranslationResult, dryRun bool) {
+	if len(result.Confirmed) == 0 {
+		fmt.Printf("No new translations among %d facts (%d pairs checked).\n", result.Checked, result.Candidates)
+		return
+	}
+
+	fmt.Printf("Found %d translations among %d facts (%d pairs checked):\n\n", len(result.Confirmed), result.Checked, result.Candidates)
+	for i := range result.Confirmed {
+		c := &result.Confirmed[i]
+		fmt.Printf("  %s %s %s\n", c.Fact.Subject, c.Fact.Predicate, c.Fact.Object)
+		fmt.Printf("  = %s %s %s  (%.2f)\n\n", c.Other.Subject, c.Other.Predicate, c.Other.Object, c.Similarity)
+	}
+
+	if dryRun {
+		fmt.Println("Run without --dry-run to link them.")
+		return
+	}
+	fmt.Printf("Linked %d translations.\n", result.Linked)
+}
+
+// selectSubjectGroups returns the groups with the given 1-based numbers,
+// or every group if none are given.
+func selectSubjectGroups(groups []services.SubjectGroup, numbers []int) ([]services.SubjectGroup, error) {
+	if len(numbers) == 0 {
+		return groups, nil
+	}
+
+	for _, n := range numbers {
+		if n < 1 || n > len(groups) {
+			return nil, entities.Errorf(entities.ErrValidation, "no group %d (there are %d groups)", n, len(groups))
+		}
+	}
+
+	var selected []services.SubjectGroup
+	for i := range groups {
+		if slices.Contains(numbers, i+1) {
+			selected = append(selected, groups[i])
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestFormatClusterReport(t *testing.T) {
+	report := &services.ClusterReport{
+		Facts:     5,
+		Truncated: true,
+		Clusters: []services.Cluster{
+			{
+				Label:    "Elven realms",
+				Size:     4,
+				Subjects: []services.SubjectCount{{Subject: "Rivendell", Count: 3}, {Subject: "Lothlórien", Count: 1}},
+				Representatives: []entities.Fact{
+					{Subject: "Rivendell", Predicate: "ruled_by", Object: "Elrond", SourceFile: "ch2.md"},
+				},
+			},
+			{
+				Label:           "Old | Forest",
+				Size:            1,
+				Subjects:        []services.SubjectCount{{Subject: "Tom Bombadil", Count: 1}},
+				Representatives: []entities.Fact{{Subject: "Tom Bombadil", Predicate: "lives_in", Object: "Old Forest"}},
+				Orphaned:        true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatClusterReport(&buf, "middle-earth", report))
+	out := buf.String()
+
+	assert.Contains(t, out, "# Themes in middle-earth")
+	assert.Contains(t, out, "Clustered 5 facts into 2 clusters. More facts exist")
+	assert.Contains(t, out, "## 1. Elven realms (4 facts)")
+	assert.Contains(t, out, "Subjects: Rivendell (3), Lothlórien (1)")
+	assert.Contains(t, out, "- **Rivendell** ruled_by Elrond _(ch2.md)_")
+	assert.Contains(t, out, "## Orphaned topics")
+	assert.Contains(t, out, "### 2. Old \\| Forest (1 fact)")
+	assert.Less(t, strings.Index(out, "Elven realms"), strings.Index(out, "## Orphaned topics"))
+}
+
+func TestFormatClusterReport_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, formatClusterReport(&buf, "empty", &services.ClusterReport{}))
+
+	assert.Contains(t, buf.String(), "No facts with embeddings to cluster.")
+	assert.NotContains(t, buf.String(), "Orphaned")
+}
+
+func TestSelectSubjectGroups(t *testing.T) {
+	groups := []services.SubjectGroup{{Canonical: "Shire"}, {Canonical: "Frodo"}, {Canonical: "Mordor"}}
+
+	tests := []struct {
+		name    string
+		numbers []int
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", want: []string{"Shire", "Frodo", "Mordor"}},
+		{name: "some", numbers: []int{3, 1}, want: []string{"Shire", "Mordor"}},
+		{name: "out of range", numbers: []int{4}, wantErr: true},
+		{name: "zero", numbers: []int{0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectSubjectGroups(groups, tt.numbers)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, len(selected))
+			for i := range selected {
+				names[i] = selected[i].Canonical
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/documents"
+	"github.com/ersonp/lore-core/internal/infrastructure/git"
+)
+
+type checkFlags struct {
+	batchSize      int
+	concurrency    int
+	limit          int
+	strategy       string
+	retrievalLimit int
+	staged         bool
+	gitDiff        string
+	pattern        string
+	failOnCritical bool
+	ci             string
+}
+
+func newCheckCmd() *cobra.Command {
+	var flags checkFlags
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check all facts for contradictions",
+		Long: `Checks every stored fact for contradictions with the facts most similar to
+it. Facts are sent to the LLM in batches of --batch-size.
+
+--retrieval chooses which stored facts each fact is checked against,
+overriding consistency.retrieval in config.yaml:
+  type     the most similar facts of the same type (default)
+  subject  the most similar facts with the same subject, of any type
+  global   the most similar facts of any type
+  graph    facts about the subject or an entity related to it
+--retrieval-limit sets how many (default 5, or consistency.limit).
+
+Contradictions found are recorded as conflicts: the facts involved are
+flagged in list, query, and export output until the conflict is resolved
+with 'lore conflicts resolve'. Contradictions already recorded, open or
+resolved, are not recorded again.
+
+Facts pending review are not checked.
+
+Facts that spell a term differently from the style sheet are reported too;
+see 'lore style'.
+
+--staged checks the manuscript changes staged in git instead: facts are
+extracted from the staged contents of the files under the current
+directory matching --pattern, and checked against the stored facts without
+saving anything. Contradictions with a file's own earlier facts are left
+out, since ingesting it would replace them. --git-diff does the same for
+the files changed since a revision, as they are on disk. 'lore hooks
+install' runs these before each commit or push.
+
+--fail-on-critical exits with status 5 when a critical contradiction is
+found, to stop a commit or fail a build. --ci junit or --ci sarif prints
+the contradictions as a JUnit or SARIF report for pipeline annotations,
+and the usual output to stderr. --ci lsp prints them as LSP diagnostics
+for editors, each at the sentence in the draft that states the new fact
+and related to where the fact it contradicts is stated.
+
+Examples:
+  lore check -w myworld
+  lore check -w myworld --limit 200 --batch-size 10
+  lore check -w myworld --retrieval graph --retrieval-limit 10
+  lore check -w myworld --staged --fail-on-critical
+  lore check -w myworld --git-diff origin/main --ci sarif > lore.sarif
+  lore check -w myworld --git-diff HEAD --ci lsp`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCheck(cmd.Context(), flags)
+		},
+	}
+
+	cmd.Flags().IntVar(&flags.batchSize, "batch-size", 0, "Facts checked per LLM call (0 = from config)")
+	cmd.Flags().IntVar(&flags.concurrency, "concurrency", 0, "LLM calls made at once (0 = from config)")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", 0, "Maximum number of facts to check (0 = all)")
+	cmd.Flags().StringVar(&flags.strategy, "retrieval", "", "How facts to check against are found: type, subject, global, or graph (default from config)")
+	cmd.Flags().IntVar(&flags.retrievalLimit, "retrieval-limit", 0, "Stored facts each fact is checked against (0 = from config)")
+	cmd.Flags().BoolVar(&flags.staged, "staged", false, "Check the manuscript changes staged in git instead of the stored facts")
+	cmd.Flags().StringVar(&flags.gitDiff, "git-diff", "", "Check the manuscript files changed since this git revision instead")
+	cmd.Flags().StringVarP(&flags.pattern, "pattern", "p", "*.txt", "Manuscript files checked with --staged or --git-diff")
+	cmd.Flags().BoolVar(&flags.failOnCritical, "fail-on-critical", false, "Exit with status 5 if a critical contradiction is found")
+	cmd.Flags().StringVar(&flags.ci, "ci", "", "Print a report instead: junit, sarif, or lsp")
+
+	return cmd
+}
+
+func runCheck(ctx context.Context, flags checkFlags) error {
+	if flags.batchSize < 0 {
+		return entities.Errorf(entities.ErrValidation, "--batch-size must not be negative")
+	}
+	if flags.concurrency < 0 {
+		return entities.Errorf(entities.ErrValidation, "--concurrency must not be negative")
+	}
+	if flags.limit < 0 {
+		return entities.Errorf(entities.ErrValidation, "--limit must not be negative")
+	}
+	if flags.retrievalLimit < 0 {
+		return entities.Errorf(entities.ErrValidation, "--retrieval-limit must not be negative")
+	}
+	if _, err := services.ParseRetrievalStrategy(flags.strategy); err != nil {
+		return err
+	}
+	if flags.staged && flags.gitDiff != "" {
+		return entities.Errorf(entities.ErrValidation, "--staged and --git-diff cannot be combined")
+	}
+	report, err := ciReporter(flags.ci)
+	if err != nil {
+		return err
+	}
+
+	// A CI report is the output; everything else goes to stderr
+	out := io.Writer(os.Stdout)
+	if report != nil {
+		out = os.Stderr
+	}
+
+	return withInternalDeps(func(d *internalDeps) error {
+		opts := retrieval(d)
+		if flags.strategy != "" {
+			opts.Strategy = services.RetrievalStrategy(flags.strategy)
+		}
+		if flags.retrievalLimit > 0 {
+			opts.Limit = flags.retrievalLimit
+		}
+		if flags.batchSize > 0 {
+			opts.BatchSize = flags.batchSize
+		}
+		if flags.concurrency > 0 {
+			opts.Concurrency = flags.concurrency
+		}
+
+		var (
+			issues []ports.ConsistencyIssue
+			files  []string
+		)
+		if flags.staged || flags.gitDiff != "" {
+			issues, files, err = checkChanges(ctx, d, out, flags, opts)
+		} else {
+			issues, err = checkStored(ctx, d, out, flags, opts)
+		}
+		if err != nil {
+			return err
+		}
+
+		if report != nil {
+			if err := report(os.Stdout, files, issues); err != nil {
+				return fmt.Errorf("writing %s report: %w", flags.ci, err)
+			}
+		}
+		if flags.failOnCritical && hasCriticalIssues(issues) {
+			return errContradictionFound
+		}
+		return nil
+	})
+}
+
+// checkStored checks the stored facts, recording the contradictions found.
+func checkStored(ctx context.Context, d *internalDeps, out io.Writer, flags checkFlags, opts services.Retrieval) ([]ports.ConsistencyIssue, error) {
+	sheet, err := d.styleService.Sheet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := handlers.NewConflictHandler(d.conflictService)
+	result, err := handler.HandleCheck(ctx, &services.CheckOptions{
+		Limit:     flags.limit,
+		Retrieval: opts,
+		Style:     sheet,
+		Progress: func(checked int) {
+			fmt.Fprintf(out, "  Checked %d facts\n", checked)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if out == os.Stdout {
+		if len(result.Issues) > 0 {
+			fmt.Println()
+			displayConsistencyIssues(result.Issues)
+		}
+		if len(result.StyleIssues) > 0 {
+			fmt.Println()
+			displayStyleIssues(result.StyleIssues)
+		}
+	}
+
+	fmt.Fprintf(out, "\nChecked %d facts: %d contradictions found, %d newly recorded\n",
+		result.Checked, len(result.Issues), result.Recorded)
+	if len(result.Issues) > 0 {
+		fmt.Fprintln(out, "See 'lore conflicts list' to review them.")
+	}
+	if len(result.StyleIssues) > 0 {
+		fmt.Fprintf(out, "%d spelling(s) differ from the style sheet\n", len(result.StyleIssues))
+	}
+	return result.Issues, nil
+}
+
+// checkChanges checks the facts of the manuscript files staged or changed
+// since --git-diff without saving them, returning the contradictions found
+// and the files checked.
+func checkChanges(ctx context.Context, d *internalDeps, out io.Writer, flags checkFlags, opts services.Retrieval) ([]ports.ConsistencyIssue, []string, error) {
+	match, err := handlers.MatchFiles(".", flags.pattern, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changesOpts := handlers.ChangesOptions{
+		Match: match,
+		Progress: func(file string) {
+			fmt.Fprintf(out, "  Checking: %s\n", file)
+		},
+		Ingest: handlers.IngestOptions{
+			CheckConsistency: true,
+			CheckOnly:        true,
+			Reliable:         d.Config.Claims.Reliable,
+			WorldID:          globalWorld,
+			Retrieval:        opts,
+			ChooseEntity:     keepSubject,
+			MaxFileSize:      d.Config.Ingest.MaxFileSize,
+			Documents:        documents.NewReader(),
+		},
+	}
+	var changes []git.Change
+	if flags.staged {
+		changes, err = git.StagedFiles(ctx, ".")
+		changesOpts.Read = git.Staged
+	} else {
+		changes, err = git.ChangedFiles(ctx, ".", flags.gitDiff)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler := handlers.NewChangesHandler(d.IngestHandler, services.NewDeletionService(d.vectorDB, d.relationalDB))
+	result, err := handler.HandleChanges(ctx, changes, &changesOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		issues []ports.ConsistencyIssue
+		files  []string
+	)
+	for _, fileResult := range result.FileResults {
+		issues = append(issues, fileResult.Issues...)
+		files = append(files, fileResult.FilePath)
+	}
+	if len(issues) > 0 && out == os.Stdout {
+		fmt.Println()
+		displayConsistencyIssues(issues)
+	}
+	for _, e := range result.Errors {
+		fmt.Fprintf(out, "  error: %v\n", e)
+	}
+
+	fmt.Fprintf(out, "\nChecked %d changed files: %d contradictions found\n", result.TotalFiles, len(issues))
+	if len(result.Errors) > 0 {
+		return nil, nil, fmt.Errorf("checking %d of the changed files failed", len(result.Errors))
+	}
+	return issues, files, nil
+}
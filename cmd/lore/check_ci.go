@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/application/lsp"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// ciReport writes contradictions found in files as a report for CI.
+type ciReport func(w io.Writer, files []string, issues []ports.ConsistencyIssue) error
+
+// ciReporter returns the report writer for a --ci format, or nil for none.
+func ciReporter(format string) (ciReport, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "junit":
+		return writeJUnit, nil
+	case "sarif":
+		return writeSARIF, nil
+	case "lsp":
+		return writeLSP, nil
+	default:
+		return nil, entities.Errorf(entities.ErrValidation, "invalid --ci format %q (valid: junit, sarif, lsp)", format)
+	}
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a JUnit report with a test case for each file, failing
+// with the contradictions found in it. Files with contradictions are
+// included even if not listed.
+func writeJUnit(w io.Writer, files []string, issues []ports.ConsistencyIssue) error {
+	byFile := make(map[string][]ports.ConsistencyIssue)
+	order := append([]string(nil), files...)
+	for i := range issues {
+		file := issues[i].NewFact.SourceFile
+		if _, ok := byFile[file]; !ok && !slices.Contains(files, file) {
+			order = append(order, file)
+		}
+		byFile[file] = append(byFile[file], issues[i])
+	}
+
+	suite := junitSuite{Name: "lore check"}
+	for _, file := range order {
+		tc := junitCase{Name: reportPath(file), Classname: "lore.consistency"}
+		if found := byFile[file]; len(found) > 0 {
+			var text strings.Builder
+			for i := range found {
+				fmt.Fprintf(&text, "%s\n", describeIssue(&found[i]))
+			}
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("%d contradiction(s)", len(found)),
+				Type:    worstSeverity(found),
+				Text:    text.String(),
+			}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// sarifVersion and sarifSchema identify the SARIF format written.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// writeSARIF writes a SARIF log with a result for each contradiction, at
+// the text of the new fact's source it was extracted from.
+func writeSARIF(w io.Writer, _ []string, issues []ports.ConsistencyIssue) error {
+	sources := sourceFiles{}
+	results := make([]map[string]any, 0, len(issues))
+	for i := range issues {
+		issue := &issues[i]
+		location := map[string]any{
+			"artifactLocation": map[string]any{"uri": filepath.ToSlash(reportPath(issue.NewFact.SourceFile))},
+		}
+		// SARIF counts lines and columns from 1
+		if r, ok := lsp.Locate(sources.text, &issue.NewFact); ok {
+			location["region"] = map[string]any{
+				"startLine":   r.Start.Line + 1,
+				"startColumn": r.Start.Character + 1,
+				"endLine":     r.End.Line + 1,
+				"endColumn":   r.End.Character + 1,
+			}
+		} else if issue.NewFact.SourceLine > 0 {
+			location["region"] = map[string]any{"startLine": issue.NewFact.SourceLine}
+		}
+		results = append(results, map[string]any{
+			"ruleId":    "contradiction",
+			"level":     sarifLevel(issue.Severity),
+			"message":   map[string]any{"text": describeIssue(issue)},
+			"locations": []any{map[string]any{"physicalLocation": location}},
+		})
+	}
+
+	report := map[string]any{
+		"version": sarifVersion,
+		"$schema": sarifSchema,
+		"runs": []any{map[string]any{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "lore",
+				"informationUri": "https://github.com/ersonp/lore-core",
+				"rules": []any{map[string]any{
+					"id":               "contradiction",
+					"shortDescription": map[string]any{"text": "Fact contradicts an established fact"},
+				}},
+			}},
+			"results": results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// writeLSP writes the contradictions as LSP diagnostics for editors, in a
+// JSON array of the textDocument/publishDiagnostics parameters of each
+// file: each at the text the new fact was extracted from and related to
+// where the fact it contradicts is stated.
+func writeLSP(w io.Writer, _ []string, issues []ports.ConsistencyIssue) error {
+	sources := sourceFiles{}
+	files := []*lsp.PublishDiagnosticsParams{}
+	byURI := make(map[string]*lsp.PublishDiagnosticsParams)
+	for i := range issues {
+		issue := &issues[i]
+		uri := lsp.FileURI(issue.NewFact.SourceFile)
+		file, ok := byURI[uri]
+		if !ok {
+			file = &lsp.PublishDiagnosticsParams{URI: uri}
+			byURI[uri] = file
+			files = append(files, file)
+		}
+		file.Diagnostics = append(file.Diagnostics, lsp.IssueDiagnostic(issue, sources.text))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(files)
+}
+
+// sourceFiles holds the text of the source files facts are placed in, read
+// once each. A file that cannot be read is held as nil.
+type sourceFiles map[string]*string
+
+// text returns the text of a source file. It reports false if the source
+// is not a readable file.
+func (s sourceFiles) text(source string) (string, bool) {
+	text, ok := s[source]
+	if !ok {
+		if filepath.IsAbs(source) {
+			if data, err := os.ReadFile(source); err == nil {
+				content := string(data)
+				text = &content
+			}
+		}
+		s[source] = text
+	}
+	if text == nil {
+		return "", false
+	}
+	return *text, true
+}
+
+// sarifLevel maps a contradiction's severity to a SARIF level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical":
+		return "error"
+	case "minor":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// worstSeverity returns the most severe of the issues' severities.
+func worstSeverity(issues []ports.ConsistencyIssue) string {
+	rank := map[string]int{"minor": 1, "major": 2, "critical": 3}
+	worst := ""
+	for i := range issues {
+		if rank[issues[i].Severity] > rank[worst] {
+			worst = issues[i].Severity
+		}
+	}
+	return worst
+}
+
+// describeIssue describes a contradiction in one line.
+func describeIssue(issue *ports.ConsistencyIssue) string {
+	return fmt.Sprintf("%s: %s (new: %s %s %s; existing: %s %s %s, %s)",
+		formatSeverity(issue.Severity), issue.Description,
+		issue.NewFact.Subject, issue.NewFact.Predicate, issue.NewFact.Object,
+		issue.ExistingFact.Subject, issue.ExistingFact.Predicate, issue.ExistingFact.Object,
+		describeSources(&issue.ExistingFact))
+}
+
+// reportPath returns a source file relative to the working directory, as
+// CI tools expect, or unchanged if it is elsewhere.
+func reportPath(file string) string {
+	if !filepath.IsAbs(file) {
+		return file
+	}
+	wd, err := filepath.Abs(".")
+	if err != nil {
+		return file
+	}
+	rel, err := filepath.Rel(wd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return file
+	}
+	return rel
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/lsp"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func ciTestIssues(t *testing.T) []ports.ConsistencyIssue {
+	wd, err := filepath.Abs(".")
+	require.NoError(t, err)
+	return []ports.ConsistencyIssue{{
+		NewFact:      entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Rivendell", SourceFile: filepath.Join(wd, "book", "ch2.txt"), SourceLine: 12},
+		ExistingFact: entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", SourceFile: "ch1.txt"},
+		Description:  "Frodo cannot live in two places",
+		Severity:     "critical",
+	}}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeJUnit(&buf, []string{"ch1.txt"}, ciTestIssues(t)))
+
+	var suites junitSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &suites))
+	require.Len(t, suites.Suites, 1)
+	suite := suites.Suites[0]
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Nil(t, suite.Cases[0].Failure, "files without contradictions pass")
+	assert.Equal(t, filepath.Join("book", "ch2.txt"), suite.Cases[1].Name, "paths are relative to the working directory")
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Equal(t, "critical", suite.Cases[1].Failure.Type)
+	assert.Contains(t, suite.Cases[1].Failure.Text, "Frodo cannot live in two places")
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeSARIF(&buf, nil, ciTestIssues(t)))
+
+	var report struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "2.1.0", report.Version)
+	require.Len(t, report.Runs, 1)
+	require.Len(t, report.Runs[0].Results, 1)
+	result := report.Runs[0].Results[0]
+	assert.Equal(t, "contradiction", result.RuleID)
+	assert.Equal(t, "error", result.Level)
+	assert.Equal(t, "book/ch2.txt", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 12, result.Locations[0].PhysicalLocation.Region.StartLine)
+
+	_, err := ciReporter("html")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestWriteLSP(t *testing.T) {
+	dir := t.TempDir()
+	draft := filepath.Join(dir, "ch2.txt")
+	canon := filepath.Join(dir, "ch1.txt")
+	require.NoError(t, os.WriteFile(draft, []byte("Chapter 2\n\nThe éclair-loving Frodo moved to Rivendell.\n"), 0o600))
+	require.NoError(t, os.WriteFile(canon, []byte("Frodo lived in Bag End.\n"), 0o600))
+
+	issues := []ports.ConsistencyIssue{{
+		NewFact:      entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Rivendell", SourceFile: draft},
+		ExistingFact: entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", SourceFile: canon},
+		Description:  "Frodo cannot live in two places",
+		Severity:     "major",
+	}}
+	var buf bytes.Buffer
+	require.NoError(t, writeLSP(&buf, nil, issues))
+
+	var files []lsp.PublishDiagnosticsParams
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &files))
+	require.Len(t, files, 1)
+	assert.Equal(t, "file://"+filepath.ToSlash(draft), files[0].URI)
+	require.Len(t, files[0].Diagnostics, 1)
+
+	d := files[0].Diagnostics[0]
+	assert.Equal(t, lsp.SeverityWarning, d.Severity)
+	assert.Equal(t, "lore", d.Source)
+	assert.Equal(t, lsp.Range{Start: lsp.Position{Line: 2, Character: 0}, End: lsp.Position{Line: 2, Character: 43}}, d.Range,
+		"the sentence stating the fact, in UTF-16 columns")
+	require.Len(t, d.RelatedInformation, 1)
+	assert.Equal(t, "file://"+filepath.ToSlash(canon), d.RelatedInformation[0].Location.URI)
+	assert.Equal(t, 0, d.RelatedInformation[0].Location.Range.Start.Line)
+}
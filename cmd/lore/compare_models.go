@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newCompareModelsCmd() *cobra.Command {
+	var models []string
+
+	cmd := &cobra.Command{
+		Use:   "compare-models <file>",
+		Short: "Compare the facts different LLMs extract from the same text",
+		Long: `Extracts the facts of a file with each model listed, using the configured
+LLM provider, and compares the results: how many facts each model
+extracted, how many another model extracted too, and the facts only one
+model found. Facts match when their subject, predicate, and object do,
+ignoring case.
+
+Nothing is saved, but each model's calls are charged to the world's quota
+and recorded in its extraction statistics (see 'lore stats models').
+Chunks a model fails to extract are counted and skipped.
+
+Example:
+  lore compare-models chapter1.txt -w myworld --models gpt-4o-mini,gpt-4o`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCompareModels(cmd.Context(), args[0], models)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&models, "models", nil, "Models to compare, comma-separated (at least two)")
+
+	return cmd
+}
+
+// modelRun is what extracting a file with one model produced.
+type modelRun struct {
+	model       string
+	facts       []entities.Fact
+	quarantined int
+}
+
+func runCompareModels(ctx context.Context, path string, models []string) error {
+	models = distinctModels(models)
+	if len(models) < 2 {
+		return entities.Errorf(entities.ErrValidation, "--models must name at least two different models")
+	}
+
+	return withInternalDeps(func(d *internalDeps) error {
+		w, err := d.container.World(ctx, globalWorld)
+		if err != nil {
+			return err
+		}
+
+		runs := make([]modelRun, 0, len(models))
+		for _, model := range models {
+			extraction, err := d.container.ExtractionWithModel(w, model)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Extracting with %s...\n", model)
+			run, err := extractWithModel(ctx, extraction, path, model)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, run)
+		}
+
+		displayModelComparison(os.Stdout, runs)
+		return nil
+	})
+}
+
+// extractWithModel extracts the facts of the file at path with extraction,
+// skipping the chunks that fail.
+func extractWithModel(ctx context.Context, extraction *services.ExtractionService, path, model string) (modelRun, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return modelRun{}, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	result, err := extraction.Extract(ctx, f, path, &services.ExtractionOptions{
+		Quarantine: func(context.Context, *entities.FailedChunk) error { return nil },
+	})
+	if err != nil {
+		return modelRun{}, fmt.Errorf("extracting with %s: %w", model, err)
+	}
+	return modelRun{model: model, facts: result.Facts, quarantined: result.Quarantined}, nil
+}
+
+// distinctModels returns the models named, trimmed, without blanks or
+// repeats.
+func distinctModels(models []string) []string {
+	var distinct []string
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		if model != "" && !slices.Contains(distinct, model) {
+			distinct = append(distinct, model)
+		}
+	}
+	return distinct
+}
+
+// displayModelComparison writes how far the models' facts agree, followed
+// by the facts only one model extracted.
+func displayModelComparison(out io.Writer, runs []modelRun) {
+	extractions := make([]services.ModelExtraction, len(runs))
+	for i, run := range runs {
+		extractions[i] = services.ModelExtraction{Model: run.model, Facts: run.facts}
+	}
+	comparison := services.CompareExtractions(extractions)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tFACTS\tSHARED\tUNIQUE\tFAILED CHUNKS\tAVG CONFIDENCE\t")
+	for i, model := range comparison.Models {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.2f\t\n",
+			model.Model, model.Facts, model.Shared, len(model.Unique), runs[i].quarantined, model.AverageConfidence)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nAll %d models agree on %d of %d facts (%.0f%%).\n",
+		len(runs), len(comparison.Agreed), comparison.Total, 100*comparison.Agreement())
+
+	for _, model := range comparison.Models {
+		if len(model.Unique) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nOnly %s:\n", model.Model)
+		for i := range model.Unique {
+			fact := &model.Unique[i]
+			fmt.Fprintf(out, "  - %s %s %s (%.2f)\n", fact.Subject, fact.Predicate, fact.Object, fact.Confidence)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestDistinctModels(t *testing.T) {
+	assert.Equal(t, []string{"gpt-4o-mini", "llama3"}, distinctModels([]string{" gpt-4o-mini", "", "llama3", "gpt-4o-mini"}))
+}
+
+func TestDisplayModelComparison(t *testing.T) {
+	livesIn := entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", Confidence: 0.9}
+	uncle := entities.Fact{Subject: "Bilbo", Predicate: "uncle_of", Object: "Frodo", Confidence: 0.6}
+
+	var out bytes.Buffer
+	displayModelComparison(&out, []modelRun{
+		{model: "gpt-4o-mini", facts: []entities.Fact{livesIn, uncle}},
+		{model: "llama3", facts: []entities.Fact{livesIn}, quarantined: 2},
+	})
+	assert.Contains(t, out.String(), "gpt-4o-mini  2      1       1       0              0.75")
+	assert.Contains(t, out.String(), "llama3       1      1       0       2              0.90")
+	assert.Contains(t, out.String(), "All 2 models agree on 1 of 2 facts (50%).")
+	assert.Contains(t, out.String(), "Only gpt-4o-mini:\n  - Bilbo uncle_of Frodo (0.60)")
+	assert.NotContains(t, out.String(), "Only llama3")
+}
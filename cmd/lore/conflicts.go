@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newConflictsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conflicts",
+		Short: "Manage recorded contradictions between facts",
+		Long: `Manages contradictions recorded by 'lore check' and 'lore ingest --check'.
+
+Facts involved in an open conflict are flagged in list, query, and export
+output until the conflict is resolved.`,
+	}
+
+	cmd.AddCommand(
+		newConflictsListCmd(),
+		newConflictsResolveCmd(),
+	)
+
+	return cmd
+}
+
+type conflictsListFlags struct {
+	status string
+	all    bool
+	factID string
+	limit  int
+}
+
+func newConflictsListCmd() *cobra.Command {
+	var flags conflictsListFlags
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded conflicts",
+		Long: `Lists recorded conflicts, newest first. Only open conflicts are listed
+unless --status or --all is given.
+
+Examples:
+  lore conflicts list -w myworld
+  lore conflicts list -w myworld --status resolved
+  lore conflicts list -w myworld --fact <fact-id> --all`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := buildConflictListOptions(flags)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			return withConflictHandler(func(handler *handlers.ConflictHandler) error {
+				conflicts, err := handler.HandleList(ctx, opts)
+				if err != nil {
+					return err
+				}
+
+				if len(conflicts) == 0 {
+					fmt.Println("No conflicts found.")
+					return nil
+				}
+
+				fmt.Printf("%d conflict(s):\n\n", len(conflicts))
+				for i := range conflicts {
+					displayConflict(&conflicts[i])
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.status, "status", "", "Filter by status: open, resolved (default open)")
+	cmd.Flags().BoolVar(&flags.all, "all", false, "List conflicts of any status")
+	cmd.Flags().StringVar(&flags.factID, "fact", "", "Only conflicts involving this fact ID")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", DefaultListLimit, "Maximum number of conflicts to list")
+
+	return cmd
+}
+
+// buildConflictListOptions converts list flags into query options.
+func buildConflictListOptions(flags conflictsListFlags) (ports.ConflictListOptions, error) {
+	opts := ports.ConflictListOptions{
+		Status: entities.ConflictOpen,
+		FactID: flags.factID,
+		Limit:  flags.limit,
+	}
+	switch {
+	case flags.all && flags.status != "":
+		return opts, entities.Errorf(entities.ErrValidation, "--all cannot be combined with --status")
+	case flags.all:
+		opts.Status = ""
+	case flags.status != "":
+		opts.Status = entities.ConflictStatus(flags.status)
+		if !opts.Status.IsValid() {
+			return opts, entities.Errorf(entities.ErrValidation, "invalid status %q (valid: open, resolved)", flags.status)
+		}
+	}
+	if flags.limit < 0 {
+		return opts, entities.Errorf(entities.ErrValidation, "--limit must not be negative")
+	}
+	return opts, nil
+}
+
+func displayConflict(c *services.ConflictDetail) {
+	fmt.Printf("ID: %s\n", c.ID)
+	fmt.Printf("  %s: %s\n", formatSeverity(c.Severity), c.Description)
+	displayConflictFact(c.FactID, c.Fact)
+	displayConflictFact(c.OtherFactID, c.Other)
+	if c.Status == entities.ConflictResolved {
+		fmt.Printf("  Resolved: %s", c.ResolvedAt.Format(time.DateTime))
+		if c.Resolution != "" {
+			fmt.Printf(" (%s)", c.Resolution)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("  Found: %s\n", c.CreatedAt.Format(time.DateTime))
+	}
+	fmt.Println()
+}
+
+func displayConflictFact(id string, fact *entities.Fact) {
+	if fact == nil {
+		fmt.Printf("  - %s (deleted)\n", id)
+		return
+	}
+	fmt.Printf("  - [%s] %s %s %s", fact.Type, fact.Subject, fact.Predicate, fact.Object)
+	if fact.SourceFile != "" {
+		fmt.Printf(" (%s)", fact.SourceFile)
+	}
+	fmt.Printf(" ID %s\n", fact.ID)
+}
+
+func newConflictsResolveCmd() *cobra.Command {
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "resolve <id>...",
+		Short: "Mark conflicts as resolved",
+		Long: `Marks conflicts as resolved, with an optional note on how. The facts
+involved are no longer flagged for them. A resolved conflict is not
+recorded again by later checks.
+
+Examples:
+  lore conflicts resolve <conflict-id> -w myworld
+  lore conflicts resolve <conflict-id> -w myworld --note "eye color changes in book 2"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withConflictHandler(func(handler *handlers.ConflictHandler) error {
+				for _, id := range args {
+					if err := handler.HandleResolve(ctx, id, note); err != nil {
+						return err
+					}
+					fmt.Printf("Resolved %s\n", id)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&note, "note", "", "How the conflict was resolved")
+
+	return cmd
+}
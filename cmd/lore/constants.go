@@ -5,8 +5,9 @@ const (
 	DefaultQueryLimit  = 10
 	DefaultListLimit   = 50
 	DefaultExportLimit = 1000
+	DefaultExportDepth = 1
 	MaxDeleteBatchSize = 1000
 )
 
 // Valid export formats.
-var validFormats = []string{"json", "csv", "markdown"}
+var validFormats = []string{"json", "csv", "markdown", "turtle", "jsonld"}
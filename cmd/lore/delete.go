@@ -3,25 +3,29 @@ package main
 import (
 	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
 )
 
 type deleteFlags struct {
-	sourceFile string
-	all        bool
-	force      bool
+	sourceFile  string
+	all         bool
+	force       bool
+	keepOrphans bool
 }
 
 type deleter struct {
-	repo  ports.VectorDB
-	force bool
+	repo        ports.VectorDB
+	deletions   *services.DeletionService
+	force       bool
+	keepOrphans bool
 }
 
 func newDeleteCmd() *cobra.Command {
@@ -30,7 +34,12 @@ func newDeleteCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete [fact-id]",
 		Short: "Delete facts",
-		Long:  "Deletes facts by ID, source file, or all facts.",
+		Long: `Deletes facts by ID, source file, or all facts.
+
+Before deleting by source, shows the facts, the entities that would lose their
+last referencing fact, and the relationships left dangling, then offers to
+remove those orphans from the relational database too. Use --keep-orphans to
+leave entities and relationships in place.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDelete(cmd, args, flags)
 		},
@@ -39,6 +48,7 @@ func newDeleteCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&flags.sourceFile, "source", "s", "", "Delete all facts from source file")
 	cmd.Flags().BoolVarP(&flags.all, "all", "a", false, "Delete all facts")
 	cmd.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&flags.keepOrphans, "keep-orphans", false, "Keep entities and relationships orphaned by --source")
 
 	return cmd
 }
@@ -46,10 +56,12 @@ func newDeleteCmd() *cobra.Command {
 func runDelete(cmd *cobra.Command, args []string, flags deleteFlags) error {
 	ctx := cmd.Context()
 
-	return withRepo(func(repo ports.VectorDB) error {
+	return withDeletionService(func(deletions *services.DeletionService, repo ports.VectorDB) error {
 		d := &deleter{
-			repo:  repo,
-			force: flags.force,
+			repo:        repo,
+			deletions:   deletions,
+			force:       flags.force,
+			keepOrphans: flags.keepOrphans,
 		}
 
 		switch {
@@ -60,7 +72,7 @@ func runDelete(cmd *cobra.Command, args []string, flags deleteFlags) error {
 		case len(args) > 0:
 			return d.deleteByID(ctx, args[0])
 		default:
-			return errors.New("specify a fact ID, --source, or --all")
+			return entities.Errorf(entities.ErrValidation, "specify a fact ID, --source, or --all")
 		}
 	})
 }
@@ -90,28 +102,62 @@ func (d *deleter) buildDeleteAllPrompt(ctx context.Context) string {
 }
 
 func (d *deleter) deleteBySource(ctx context.Context, sourceFile string) error {
-	facts, err := d.repo.ListBySource(ctx, sourceFile, MaxDeleteBatchSize)
+	plan, err := d.deletions.PlanSourceDeletion(ctx, globalWorld, sourceFile, MaxDeleteBatchSize)
 	if err != nil {
-		return fmt.Errorf("listing facts by source: %w", err)
+		return fmt.Errorf("planning deletion: %w", err)
 	}
 
-	if len(facts) == 0 {
+	if len(plan.Facts) == 0 {
 		fmt.Printf("No facts found from source: %s\n", sourceFile)
 		return nil
 	}
 
-	if !d.force && !confirmAction(fmt.Sprintf("Delete %d facts from %s?", len(facts), sourceFile)) {
+	displayDeletionPlan(plan)
+
+	if !d.force && !confirmAction(fmt.Sprintf("Delete %d facts from %s?", len(plan.Facts), sourceFile)) {
 		fmt.Println("Cancelled.")
 		return nil
 	}
 
-	if err := d.repo.DeleteBySource(ctx, sourceFile); err != nil {
-		return fmt.Errorf("deleting facts by source: %w", err)
+	cleanup := plan.HasOrphans() && !d.keepOrphans
+	if cleanup && !d.force {
+		cleanup = confirmAction(fmt.Sprintf("Also remove %d orphaned entities and %d relationships?",
+			len(plan.OrphanedEntities), len(plan.Relationships)))
+	}
+
+	if err := d.deletions.DeleteSource(ctx, plan, cleanup); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted %d facts from %s\n", len(plan.Facts), sourceFile)
+	if cleanup {
+		fmt.Printf("Removed %d orphaned entities and %d relationships\n",
+			len(plan.OrphanedEntities), len(plan.Relationships))
 	}
-	fmt.Printf("Deleted %d facts from %s\n", len(facts), sourceFile)
 	return nil
 }
 
+func displayDeletionPlan(plan *services.SourceDeletionPlan) {
+	fmt.Printf("Deleting facts from %s affects:\n", plan.SourceFile)
+	fmt.Printf("  %d facts\n", len(plan.Facts))
+
+	if len(plan.OrphanedEntities) > 0 {
+		names := make([]string, 0, len(plan.OrphanedEntities))
+		for _, e := range plan.OrphanedEntities {
+			names = append(names, e.Name)
+		}
+		fmt.Printf("  %d entities losing their last reference: %s\n", len(names), strings.Join(names, ", "))
+	}
+
+	if len(plan.Relationships) > 0 {
+		fmt.Printf("  %d relationships:\n", len(plan.Relationships))
+		for i := range plan.Relationships {
+			rel := &plan.Relationships[i]
+			fmt.Printf("    %s %s %s\n", rel.Source, rel.Relationship.Type, rel.Target)
+		}
+	}
+	fmt.Println()
+}
+
 func (d *deleter) deleteByID(ctx context.Context, factID string) error {
 	if err := d.repo.Delete(ctx, factID); err != nil {
 		return fmt.Errorf("deleting fact: %w", err)
@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ersonp/lore-core/internal/application/container"
 	"github.com/ersonp/lore-core/internal/application/handlers"
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/domain/services"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
-	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
-	llm "github.com/ersonp/lore-core/internal/infrastructure/llm/openai"
+	"github.com/ersonp/lore-core/internal/infrastructure/dictionary"
+	"github.com/ersonp/lore-core/internal/infrastructure/notify"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/cache"
 	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
+	"github.com/ersonp/lore-core/internal/infrastructure/snapshots"
+	"github.com/ersonp/lore-core/internal/infrastructure/tracing"
 	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/qdrant"
 )
 
@@ -30,118 +34,179 @@ type Deps struct {
 // Used internally by helper functions.
 type internalDeps struct {
 	Deps
+	container         *container.Container // Closes every connection below
+	configDir         string
 	repo              *qdrant.Repository
-	relationalDB      *sqlite.Repository
-	embedder          *embedder.Embedder
+	vectorDB          ports.VectorDB // repo, recording its writes in the change feed, bounded by its budget, and checked against the world's quota
+	quota             *services.QuotaService
+	relationalDB      *cache.EntityCache
+	sqlite            *sqlite.Repository // Unwrapped, for backups
+	embedder          ports.Embedder
+	llm               ports.LLMClient
 	extractionService *services.ExtractionService
 	entityTypeService *services.EntityTypeService
+	conflictService   *services.ConflictService
+	styleService      *services.StyleService
+	sourceService     *services.SourceService
 }
 
-// withDeps loads config and builds dependencies, then calls the provided function.
-// It handles cleanup automatically.
-func withDeps(fn func(*Deps) error) error {
-	return withInternalDeps(func(d *internalDeps) error {
-		return fn(&d.Deps)
-	})
-}
-
-// withInternalDeps provides access to all dependencies including low-level components.
-// Used by commands that need direct repository or service access.
-func withInternalDeps(fn func(*internalDeps) error) error {
+// findConfigDir resolves the config directory from --config-dir, $LORE_HOME,
+// or the nearest .lore directory above the working directory.
+func findConfigDir() (string, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return "", fmt.Errorf("getting current directory: %w", err)
 	}
 
-	cfg, err := config.Load(cwd)
-	if err != nil {
-		return fmt.Errorf("loading config: %w", err)
+	configDir, err := config.FindConfigDir(globalConfigDir, cwd)
+	if errors.Is(err, config.ErrConfigDirNotFound) {
+		return "", fmt.Errorf("%w (run 'lore worlds create' first)", err)
 	}
+	return configDir, err
+}
 
-	worlds, err := config.LoadWorlds(cwd)
+// loadConfig loads the config from configDir with the profile selected by
+// --profile, $LORE_PROFILE, or the config's default_profile. --strict
+// makes quotas strict. Failures exit with the config status.
+func loadConfig(configDir string) (*config.Config, error) {
+	cfg, err := config.LoadProfile(configDir, globalProfile)
 	if err != nil {
-		return fmt.Errorf("loading worlds: %w", err)
+		return nil, withConfigKind(err)
 	}
-
-	if globalWorld == "" {
-		return errors.New("world is required (use --world flag)")
+	if globalStrict {
+		cfg.Quota.Strict = true
 	}
+	return cfg, nil
+}
 
-	collection, err := worlds.GetCollection(globalWorld)
+// loadWorlds loads the worlds registered in configDir. Failures exit with the
+// config status.
+func loadWorlds(configDir string) (*config.WorldsConfig, error) {
+	worlds, err := config.LoadWorlds(configDir)
 	if err != nil {
-		return err
+		return nil, withConfigKind(fmt.Errorf("loading worlds: %w", err))
 	}
+	return worlds, nil
+}
 
-	qdrantCfg := cfg.Qdrant
-	qdrantCfg.Collection = collection
-
-	repo, err := qdrant.NewRepository(qdrantCfg)
+// initConfigDir resolves the config directory like findConfigDir, but falls
+// back to .lore in the working directory so a new project can be set up there.
+func initConfigDir() (string, error) {
+	cwd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("creating qdrant repository: %w", err)
+		return "", fmt.Errorf("getting current directory: %w", err)
 	}
-	defer repo.Close()
 
-	// Initialize RelationalDB (SQLite)
-	sqlitePath := config.SQLitePathForWorld(cwd, globalWorld)
-	relationalDB, err := sqlite.NewRepository(config.SQLiteConfig{Path: sqlitePath})
-	if err != nil {
-		return fmt.Errorf("creating sqlite repository: %w", err)
+	configDir, err := config.FindConfigDir(globalConfigDir, cwd)
+	if errors.Is(err, config.ErrConfigDirNotFound) {
+		return config.ConfigDir(cwd), nil
 	}
-	defer relationalDB.Close()
+	return configDir, err
+}
 
-	// Ensure schema exists
-	ctx := context.Background()
-	if err := relationalDB.EnsureSchema(ctx); err != nil {
-		return fmt.Errorf("ensuring sqlite schema: %w", err)
-	}
+// withDeps loads config and builds dependencies, then calls the provided function.
+// It handles cleanup automatically.
+func withDeps(fn func(*Deps) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(&d.Deps)
+	})
+}
 
-	// Auto-migrate: seed default types if table is empty
-	if err := migrateDefaultEntityTypes(ctx, relationalDB); err != nil {
-		return fmt.Errorf("migrating entity types: %w", err)
-	}
+// withInternalDeps provides access to all dependencies including low-level components.
+// Used by commands that need direct repository or service access.
+func withInternalDeps(fn func(*internalDeps) error) error {
+	return withContainer(func(c *container.Container) error {
+		if globalWorld == "" {
+			return entities.Errorf(entities.ErrValidation, "world is required (use --world flag)")
+		}
+
+		w, err := c.World(context.Background(), globalWorld)
+		if err != nil {
+			return err
+		}
+
+		ingest := handlers.NewIngestHandler(w.Extraction,
+			handlers.WithDisambiguation(w.Disambiguation),
+			handlers.WithConflicts(w.Conflicts),
+			handlers.WithStyle(w.Style),
+			handlers.WithSources(w.Sources),
+		)
+		deps := &internalDeps{
+			Deps: Deps{
+				Config:        c.Config(),
+				Worlds:        c.Worlds(),
+				IngestHandler: ingest,
+				QueryHandler:  handlers.NewQueryHandler(w.Query),
+			},
+			container:         c,
+			configDir:         c.ConfigDir(),
+			repo:              w.Repo,
+			vectorDB:          w.VectorDB,
+			quota:             w.Quota,
+			relationalDB:      w.RelationalDB,
+			sqlite:            w.SQLite,
+			embedder:          w.Embedder,
+			llm:               w.LLM,
+			extractionService: w.Extraction,
+			entityTypeService: w.EntityTypes,
+			conflictService:   w.Conflicts,
+			styleService:      w.Style,
+			sourceService:     w.Sources,
+		}
 
-	emb, err := embedder.NewEmbedder(cfg.Embedder)
+		return fn(deps)
+	})
+}
+
+// withContainer loads config and provides a container that builds the
+// project's worlds on demand. Every connection it opened is closed when fn
+// returns, and a failure to close is reported. With --trace, the calls of its
+// backends are traced to a new file under the config directory's traces.
+func withContainer(fn func(*container.Container) error) (err error) {
+	configDir, err := findConfigDir()
 	if err != nil {
-		return fmt.Errorf("creating embedder: %w", err)
+		return err
 	}
 
-	llmClient, err := llm.NewClient(cfg.LLM)
+	cfg, err := loadConfig(configDir)
 	if err != nil {
-		return fmt.Errorf("creating llm client: %w", err)
+		return fmt.Errorf("loading config: %w", err)
+	}
+	if err := cfg.ValidateCredentials(); err != nil {
+		return err
 	}
 
-	entityTypeService := services.NewEntityTypeService(relationalDB)
-	extractionService := services.NewExtractionService(llmClient, emb, repo, entityTypeService)
-	queryService := services.NewQueryService(emb, repo)
-
-	deps := &internalDeps{
-		Deps: Deps{
-			Config:        cfg,
-			Worlds:        worlds,
-			IngestHandler: handlers.NewIngestHandler(extractionService),
-			QueryHandler:  handlers.NewQueryHandler(queryService),
-		},
-		repo:              repo,
-		relationalDB:      relationalDB,
-		embedder:          emb,
-		extractionService: extractionService,
-		entityTypeService: entityTypeService,
+	worlds, err := loadWorlds(configDir)
+	if err != nil {
+		return err
 	}
 
-	return fn(deps)
-}
+	c := container.New(cfg, configDir, worlds)
+	if globalTrace {
+		tracer, err := tracing.Open(config.TraceDir(configDir))
+		if err != nil {
+			return err
+		}
+		c.SetTracer(tracer)
+		// Deferred first so it runs after the container closes.
+		defer func() {
+			tracer.Close()
+			fmt.Fprintf(os.Stderr, "Trace written to %s\n", tracer.Path())
+		}()
+	}
+	defer func() {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
 
-// withRepo provides direct repository access for commands that need it.
-func withRepo(fn func(ports.VectorDB) error) error {
-	return withInternalDeps(func(d *internalDeps) error {
-		return fn(d.repo)
-	})
+	return fn(c)
 }
 
-// withExtractionService provides direct service access for commands like watch.
-func withExtractionService(fn func(*services.ExtractionService, ports.VectorDB) error) error {
+// withDeletionService provides a DeletionService and the vector repository for delete commands.
+func withDeletionService(fn func(*services.DeletionService, ports.VectorDB) error) error {
 	return withInternalDeps(func(d *internalDeps) error {
-		return fn(d.extractionService, d.repo)
+		return fn(services.NewDeletionService(d.vectorDB, d.relationalDB), d.vectorDB)
 	})
 }
 
@@ -157,36 +222,283 @@ func withRelationalDB(fn func(ports.RelationalDB) error) error {
 // withRelationshipHandler provides access to the RelationshipHandler for relationship commands.
 func withRelationshipHandler(fn func(*handlers.RelationshipHandler) error) error {
 	return withInternalDeps(func(d *internalDeps) error {
-		relationshipService := services.NewRelationshipService(d.repo, d.relationalDB, d.embedder)
-		handler := handlers.NewRelationshipHandler(relationshipService, d.relationalDB)
-		return fn(handler)
+		return fn(newRelationshipHandler(d))
 	})
 }
 
 // withEntityHandler provides access to the EntityHandler for entity commands.
 func withEntityHandler(fn func(*handlers.EntityHandler) error) error {
 	return withInternalDeps(func(d *internalDeps) error {
-		entityService := services.NewEntityService(d.relationalDB)
-		handler := handlers.NewEntityHandler(entityService)
-		return fn(handler)
+		return fn(newEntityHandler(d))
+	})
+}
+
+// newEntityHandler creates an EntityHandler for the current world's databases.
+func newEntityHandler(d *internalDeps) *handlers.EntityHandler {
+	return handlers.NewEntityHandler(services.NewEntityService(d.relationalDB, d.vectorDB))
+}
+
+// newCardHandler creates a CardHandler for the current world's entity
+// cards.
+func newCardHandler(d *internalDeps) *handlers.CardHandler {
+	return handlers.NewCardHandler(newEntityHandler(d), d.QueryHandler, newRelationshipHandler(d), 0)
+}
+
+// newRelationshipHandler creates a RelationshipHandler for the current
+// world's databases.
+func newRelationshipHandler(d *internalDeps) *handlers.RelationshipHandler {
+	relationshipService := services.NewRelationshipService(d.vectorDB, d.relationalDB, d.embedder)
+	return handlers.NewRelationshipHandler(relationshipService, d.relationalDB)
+}
+
+// withReviewHandler provides access to the ReviewHandler for review commands.
+func withReviewHandler(fn func(*handlers.ReviewHandler) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		factService := services.NewFactService(d.embedder, d.vectorDB, d.relationalDB, d.entityTypeService)
+		reviewService := services.NewReviewService(d.vectorDB, factService)
+		return fn(handlers.NewReviewHandler(reviewService))
+	})
+}
+
+// withFactHandler provides access to the FactHandler for manual fact commands.
+func withFactHandler(fn func(*handlers.FactHandler) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(newFactHandler(d))
+	})
+}
+
+// newFactHandler creates a FactHandler for the current world's facts.
+func newFactHandler(d *internalDeps) *handlers.FactHandler {
+	factService := services.NewFactService(d.embedder, d.vectorDB, d.relationalDB, d.entityTypeService)
+	return handlers.NewFactHandler(factService)
+}
+
+// withCanonicalService provides a CanonicalService for commands that find
+// and merge subjects written several ways.
+func withCanonicalService(fn func(*services.CanonicalService) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		factService := services.NewFactService(d.embedder, d.vectorDB, d.relationalDB, d.entityTypeService)
+		return fn(services.NewCanonicalService(d.vectorDB, d.relationalDB, factService))
+	})
+}
+
+// withEntitySearchService provides an EntitySearchService over the current
+// world's entity index.
+func withEntitySearchService(fn func(*services.EntitySearchService) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(newEntitySearchService(d))
+	})
+}
+
+// newEntitySearchService builds an EntitySearchService over the current
+// world's entity index, for 'lore entities search' and the serve-mode
+// refresh job.
+func newEntitySearchService(d *internalDeps) *services.EntitySearchService {
+	return services.NewEntitySearchService(d.embedder, d.vectorDB, d.relationalDB, d.repo.EntityIndex(), config.EmbeddingVectorSize)
+}
+
+// withConflictHandler provides access to the ConflictHandler for check and
+// conflict commands.
+func withConflictHandler(fn func(*handlers.ConflictHandler) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(handlers.NewConflictHandler(d.conflictService))
+	})
+}
+
+// withStyleHandler provides access to the StyleHandler for style commands.
+func withStyleHandler(fn func(*handlers.StyleHandler) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(handlers.NewStyleHandler(d.styleService))
+	})
+}
+
+// withSourceService provides the SourceService for source statistics.
+func withSourceService(fn func(*services.SourceService) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(d.sourceService)
 	})
 }
 
-// migrateDefaultEntityTypes seeds default entity types if the table is empty.
-// This provides transparent migration for worlds created before dynamic entity types.
-func migrateDefaultEntityTypes(ctx context.Context, db ports.RelationalDB) error {
-	existingTypes, err := db.ListEntityTypes(ctx)
+// retrieval finds the stored facts consistency checks compare against as
+// configured, looking up related entities in the current world.
+func retrieval(d *internalDeps) services.Retrieval {
+	return services.Retrieval{
+		Strategy:    services.RetrievalStrategy(d.Config.Consistency.Retrieval),
+		Limit:       d.Config.Consistency.Limit,
+		Related:     services.RelatedEntityNames(d.relationalDB, globalWorld),
+		BatchSize:   d.Config.Consistency.BatchSize,
+		Concurrency: d.Config.Consistency.Concurrency,
+		Audit:       d.relationalDB,
+		Prefilter:   prefilter(d.Config.Consistency),
+	}
+}
+
+// prefilter settles the consistency questions declared in cfg without the
+// LLM.
+func prefilter(cfg config.ConsistencyConfig) services.Prefilter {
+	p := services.Prefilter{SingleValued: cfg.SingleValued, KeepUnrelated: cfg.KeepUnrelated}
+	for _, pair := range cfg.Exclusive {
+		if len(pair) == 2 { // Anything else fails config validation
+			p.Exclusive = append(p.Exclusive, [2]string{pair[0], pair[1]})
+		}
+	}
+	return p
+}
+
+// sourceRules loads the rules giving ingested facts the metadata of their
+// source, from the config directory's sources file.
+func sourceRules(d *internalDeps) (services.SourceRules, error) {
+	cfg, err := config.LoadSources(d.configDir)
 	if err != nil {
-		return fmt.Errorf("listing entity types: %w", err)
+		return nil, err
+	}
+	rules := make(services.SourceRules, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		rules = append(rules, services.SourceRule{
+			Pattern:  rule.Pattern,
+			Metadata: rule.Metadata,
+			Tags:     rule.Tags,
+			Draft:    rule.Draft,
+			DraftTTL: rule.TTL,
+		})
 	}
-	if len(existingTypes) > 0 {
-		return nil
+	if err := rules.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", config.SourcesFilePath(d.configDir), err)
 	}
-	for _, et := range entities.DefaultEntityTypes {
-		etCopy := et
-		if err := db.SaveEntityType(ctx, &etCopy); err != nil {
-			return fmt.Errorf("seeding entity type %s: %w", et.Name, err)
+	return rules, nil
+}
+
+// withHealthHandler provides access to the HealthHandler for health commands.
+func withHealthHandler(fn func(*handlers.HealthHandler, services.HealthOptions) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(newHealthHandler(d), healthOptions(d))
+	})
+}
+
+func newHealthHandler(d *internalDeps) *handlers.HealthHandler {
+	entityService := services.NewEntityService(d.relationalDB, d.repo)
+	return handlers.NewHealthHandler(services.NewHealthService(d.repo, d.relationalDB, entityService))
+}
+
+// healthOptions measures health of the current world, counting facts below
+// the review threshold, if one is configured, as low confidence.
+func healthOptions(d *internalDeps) services.HealthOptions {
+	return services.HealthOptions{
+		WorldID:       globalWorld,
+		LowConfidence: d.Config.Review.Threshold,
+	}
+}
+
+// newSweepHandler checks the current world's added facts and sends the
+// digest to the configured notifiers.
+func newSweepHandler(d *internalDeps) *handlers.SweepHandler {
+	return handlers.NewSweepHandler(services.NewSweepService(d.repo, d.conflictService, notify.New(d.Config.Notify)))
+}
+
+// withMigrationService provides a MigrationService and the current world's
+// collection alias for commands that rebuild collections.
+func withMigrationService(fn func(d *internalDeps, svc *services.MigrationService, alias string) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		alias, err := d.Worlds.GetCollection(globalWorld)
+		if err != nil {
+			return err
+		}
+
+		admin, err := d.container.CollectionAdmin()
+		if err != nil {
+			return err
 		}
+
+		return fn(d, services.NewMigrationService(admin, d.embedder), alias)
+	})
+}
+
+// withGlossaryService provides a GlossaryService that reads ordinary words
+// from the word list at dictionaryPath, or the built-in English list if
+// dictionaryPath is empty.
+func withGlossaryService(dictionaryPath string, fn func(*services.GlossaryService) error) error {
+	var words ports.Dictionary = dictionary.English()
+	if dictionaryPath != "" {
+		list, err := dictionary.Load(dictionaryPath)
+		if err != nil {
+			return err
+		}
+		words = list
 	}
-	return nil
+
+	return withInternalDeps(func(d *internalDeps) error {
+		return fn(services.NewGlossaryService(d.repo, words))
+	})
+}
+
+// withClusterService provides a ClusterService and the current world's
+// collection alias for commands that analyze fact embeddings.
+func withClusterService(fn func(svc *services.ClusterService, alias string) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		alias, err := d.Worlds.GetCollection(globalWorld)
+		if err != nil {
+			return err
+		}
+
+		admin, err := d.container.CollectionAdmin()
+		if err != nil {
+			return err
+		}
+
+		return fn(services.NewClusterService(admin, d.llm), alias)
+	})
+}
+
+// withTranslationService provides a TranslationService and the current
+// world's collection alias for commands that compare fact embeddings.
+func withTranslationService(fn func(svc *services.TranslationService, alias string) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		alias, err := d.Worlds.GetCollection(globalWorld)
+		if err != nil {
+			return err
+		}
+
+		admin, err := d.container.CollectionAdmin()
+		if err != nil {
+			return err
+		}
+
+		return fn(services.NewTranslationService(admin, d.repo, d.relationalDB, d.llm), alias)
+	})
+}
+
+// withGraphService provides a GraphService for graph commands, connected to
+// the configured graph database if there is one.
+func withGraphService(fn func(*services.GraphService) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		if !d.Config.Graph.Enabled() {
+			return fn(services.NewGraphService(d.relationalDB, nil))
+		}
+
+		client, err := d.container.GraphClient()
+		if err != nil {
+			return err
+		}
+
+		return fn(services.NewGraphService(d.relationalDB, client))
+	})
+}
+
+// withSnapshotHandler provides the SnapshotHandler and config for snapshot commands.
+func withSnapshotHandler(fn func(*handlers.SnapshotHandler, *config.Config) error) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		handler, err := newSnapshotHandler(d)
+		if err != nil {
+			return err
+		}
+		return fn(handler, d.Config)
+	})
+}
+
+// newSnapshotHandler builds a SnapshotHandler that stores snapshots under
+// the current world's directory.
+func newSnapshotHandler(d *internalDeps) (*handlers.SnapshotHandler, error) {
+	store := snapshots.NewFileStore(config.SnapshotDirForWorld(d.configDir, globalWorld))
+	snapshotService := services.NewSnapshotService(globalWorld, d.repo, d.sqlite, store)
+	return handlers.NewSnapshotHandler(snapshotService), nil
 }
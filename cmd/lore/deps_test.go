@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/container"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// useFakeProject points commands at a new project with one world and
+// offline providers. Qdrant is connected to lazily, so none needs to run.
+func useFakeProject(t *testing.T, world string) {
+	t.Helper()
+	configDir := t.TempDir()
+	cfg := "llm:\n  provider: fake\nembedder:\n  provider: fake\nqdrant:\n  host: localhost\n  port: 6334\n"
+	require.NoError(t, os.WriteFile(config.ConfigFilePath(configDir), []byte(cfg), 0644))
+
+	worlds := &config.WorldsConfig{}
+	worlds.Add(world, config.WorldEntry{Collection: config.GenerateCollectionName(world)})
+	require.NoError(t, worlds.Save(configDir))
+	require.NoError(t, os.MkdirAll(config.WorldDir(configDir, world), 0755))
+
+	oldDir, oldWorld := globalConfigDir, globalWorld
+	globalConfigDir, globalWorld = configDir, world
+	t.Cleanup(func() { globalConfigDir, globalWorld = oldDir, oldWorld })
+}
+
+func TestWithInternalDeps_ClosesConnections(t *testing.T) {
+	useFakeProject(t, "shire")
+
+	var c *container.Container
+	err := withInternalDeps(func(d *internalDeps) error {
+		c = d.container
+		_, err := d.container.CollectionAdmin()
+		require.NoError(t, err)
+		assert.Len(t, c.Open(), 3)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Empty(t, c.Open(), "connections are closed when the command returns")
+
+	failed := errors.New("command failed")
+	err = withInternalDeps(func(d *internalDeps) error {
+		c = d.container
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.Empty(t, c.Open(), "connections are closed when the command fails")
+}
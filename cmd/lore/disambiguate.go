@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// stdinIsTerminal reports whether standard input is interactive.
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// keepSubject is an EntityChooser that leaves ambiguous subjects as written.
+func keepSubject(context.Context, *entities.Fact, []services.EntityCandidate) (int, error) {
+	return -1, nil
+}
+
+// newEntityPrompt returns an EntityChooser that asks on out and reads the
+// answer from in. Entering nothing picks the first candidate; 0 keeps the
+// subject as written.
+func newEntityPrompt(in io.Reader, out io.Writer) services.EntityChooser {
+	reader := bufio.NewReader(in)
+
+	return func(_ context.Context, fact *entities.Fact, candidates []services.EntityCandidate) (int, error) {
+		fmt.Fprintf(out, "\n%q is ambiguous in: %s %s %s\n", fact.Subject, fact.Subject, fact.Predicate, fact.Object)
+		if fact.Context != "" {
+			fmt.Fprintf(out, "  Context: %s\n", fact.Context)
+		}
+		for i, c := range candidates {
+			fmt.Fprintf(out, "  %d. %s", i+1, c.Entity.Name)
+			if len(c.Related) > 0 {
+				fmt.Fprintf(out, " (related: %s)", strings.Join(c.Related, ", "))
+			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "  0. Keep %q\n", fact.Subject)
+
+		for {
+			fmt.Fprint(out, "Choose [1]: ")
+			line, err := reader.ReadString('\n')
+			if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+				return 0, fmt.Errorf("reading choice: %w", err)
+			}
+
+			answer := strings.TrimSpace(line)
+			if answer == "" {
+				return 0, nil
+			}
+			n, convErr := strconv.Atoi(answer)
+			if convErr == nil && n >= 0 && n <= len(candidates) {
+				return n - 1, nil
+			}
+			fmt.Fprintf(out, "Enter a number from 0 to %d.\n", len(candidates))
+			if err != nil {
+				return 0, fmt.Errorf("reading choice: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestNewEntityPrompt(t *testing.T) {
+	fact := &entities.Fact{Subject: "John", Predicate: "is", Object: "tall"}
+	candidates := []services.EntityCandidate{
+		{Entity: &entities.Entity{Name: "John the Baker"}},
+		{Entity: &entities.Entity{Name: "King John"}, Related: []string{"Northern Kingdom"}},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"default", "\n", 0, false},
+		{"second", "2\n", 1, false},
+		{"keep", "0\n", -1, false},
+		{"retry after invalid", "x\n7\n2\n", 1, false},
+		{"no trailing newline", "2", 1, false},
+		{"eof", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			choose := newEntityPrompt(strings.NewReader(tt.input), &out)
+
+			got, err := choose(t.Context(), fact, candidates)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, out.String(), "2. King John (related: Northern Kingdom)")
+		})
+	}
+}
@@ -2,50 +2,104 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
 )
 
+type entitiesFlags struct {
+	search     string
+	limit      int
+	withCounts bool
+	orphans    bool
+	delete     bool
+	force      bool
+}
+
 func newEntitiesCmd() *cobra.Command {
-	var searchQuery string
-	var limit int
+	var flags entitiesFlags
 
 	cmd := &cobra.Command{
 		Use:   "entities",
 		Short: "List entities in a world",
 		Long: `List all tracked entities in a world.
 
-Entities are subjects that have been used in relationships.
-Use --search to filter by name.
+Entities are the subjects of facts and the ends of relationships.
+Use --search to filter by name, or 'lore entities search --semantic' to
+find entities by what is known about them.
+
+Use --with-counts to show how many facts have each entity as subject and how
+many relationships it takes part in. Use --orphans to list entities with no
+facts and no relationships; add --delete to remove them.
 
 Examples:
   lore entities
   lore entities --search "Ali"
-  lore entities --limit 50`,
+  lore entities --limit 50
+  lore entities list --with-counts
+  lore entities list --orphans --delete`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runEntities(cmd, searchQuery, limit)
+			return runEntities(cmd, flags)
 		},
 	}
+	addEntitiesFlags(cmd, &flags)
+
+	cmd.AddCommand(newEntitiesListCmd())
+	cmd.AddCommand(newEntitiesSearchCmd())
+	cmd.AddCommand(newEntitiesDeleteCmd())
+	cmd.AddCommand(newEntitiesRenameCmd())
+
+	return cmd
+}
+
+func newEntitiesListCmd() *cobra.Command {
+	var flags entitiesFlags
 
-	cmd.Flags().StringVar(&searchQuery, "search", "", "Search entities by name")
-	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of entities to return")
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List entities in a world",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEntities(cmd, flags)
+		},
+	}
+	addEntitiesFlags(cmd, &flags)
 
 	return cmd
 }
 
-func runEntities(cmd *cobra.Command, searchQuery string, limit int) error {
+func addEntitiesFlags(cmd *cobra.Command, flags *entitiesFlags) {
+	cmd.Flags().StringVar(&flags.search, "search", "", "Search entities by name")
+	cmd.Flags().IntVar(&flags.limit, "limit", 100, "Maximum number of entities to return")
+	cmd.Flags().BoolVar(&flags.withCounts, "with-counts", false, "Show fact and relationship counts per entity")
+	cmd.Flags().BoolVar(&flags.orphans, "orphans", false, "List entities with no facts and no relationships")
+	cmd.Flags().BoolVar(&flags.delete, "delete", false, "Delete the listed orphans (with --orphans)")
+	cmd.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+}
+
+func runEntities(cmd *cobra.Command, flags entitiesFlags) error {
+	if flags.delete && !flags.orphans {
+		return entities.Errorf(entities.ErrValidation, "--delete only applies to --orphans")
+	}
 	ctx := cmd.Context()
 
 	return withEntityHandler(func(handler *handlers.EntityHandler) error {
+		if flags.orphans {
+			return runEntityOrphans(cmd, handler, flags)
+		}
+
 		var result *handlers.EntityListResult
 		var err error
 
-		if searchQuery != "" {
-			result, err = handler.HandleSearch(ctx, globalWorld, searchQuery, limit)
+		if flags.search != "" {
+			result, err = handler.HandleSearch(ctx, globalWorld, flags.search, flags.limit)
 		} else {
-			result, err = handler.HandleList(ctx, globalWorld, limit, 0)
+			result, err = handler.HandleList(ctx, globalWorld, flags.limit, 0)
 		}
 
 		if err != nil {
@@ -60,14 +114,238 @@ func runEntities(cmd *cobra.Command, searchQuery string, limit int) error {
 		fmt.Printf("Entities (%d total):\n", result.Total)
 		fmt.Println()
 
-		for _, entity := range result.Entities {
-			idDisplay := entity.ID
-			if len(entity.ID) > 8 {
-				idDisplay = entity.ID[:8] + "..."
+		if !flags.withCounts {
+			for _, entity := range result.Entities {
+				fmt.Printf("  %-40s %s\n", shortEntityID(entity), entity.Name)
 			}
-			fmt.Printf("  %-40s %s\n", idDisplay, entity.Name)
+			return nil
+		}
+
+		usage, err := handler.HandleUsage(ctx, globalWorld, result.Entities)
+		if err != nil {
+			return fmt.Errorf("counting entity usage: %w", err)
+		}
+
+		fmt.Printf("  %-40s %-30s %6s %14s\n", "ID", "NAME", "FACTS", "RELATIONSHIPS")
+		for _, u := range usage {
+			fmt.Printf("  %-40s %-30s %6d %14d\n", shortEntityID(u.Entity), u.Entity.Name, u.Facts, u.Relationships)
+		}
+
+		return nil
+	})
+}
+
+func runEntityOrphans(cmd *cobra.Command, handler *handlers.EntityHandler, flags entitiesFlags) error {
+	ctx := cmd.Context()
+
+	orphans, err := handler.HandleOrphans(ctx, globalWorld)
+	if err != nil {
+		return fmt.Errorf("finding orphaned entities: %w", err)
+	}
+
+	if len(orphans) == 0 {
+		fmt.Println("No orphaned entities.")
+		return nil
+	}
+
+	fmt.Printf("Orphaned entities (%d):\n", len(orphans))
+	fmt.Println()
+	for _, entity := range orphans {
+		fmt.Printf("  %-40s %s\n", shortEntityID(entity), entity.Name)
+	}
+
+	if !flags.delete {
+		return nil
+	}
+
+	if !flags.force && !confirmAction(fmt.Sprintf("\nDelete %d orphaned entities?", len(orphans))) {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	for _, entity := range orphans {
+		if err := handler.HandleDelete(ctx, entity.ID); err != nil {
+			return fmt.Errorf("deleting entity %s: %w", entity.Name, err)
+		}
+	}
+	fmt.Printf("Deleted %d orphaned entities\n", len(orphans))
+
+	return nil
+}
+
+func newEntitiesSearchCmd() *cobra.Command {
+	var (
+		semantic bool
+		limit    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search entities by name or description",
+		Long: `Searches the world's entities by name, or with --semantic by meaning:
+each entity is embedded from its name and a summary of the facts about it,
+so "the grumpy blacksmith" finds Durin if his facts say he is one.
+
+The first semantic search embeds every entity, one embedding call per 64
+entities; later searches embed only the entities whose facts changed since.
+Drafts, facts pending review, and characters' claims are left out of the
+summaries.
+
+Examples:
+  lore entities search Ali
+  lore entities search --semantic "the grumpy blacksmith"
+  lore entities search --semantic "a port city in the south" --limit 5`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !semantic {
+				return runEntities(cmd, entitiesFlags{search: args[0], limit: limit})
+			}
+			return runEntitiesSemanticSearch(cmd, args[0], limit)
+		},
+	}
+
+	cmd.Flags().BoolVar(&semantic, "semantic", false, "Search by meaning, using each entity's facts")
+	cmd.Flags().IntVar(&limit, "limit", 10, "Maximum number of entities to return")
+
+	return cmd
+}
+
+func runEntitiesSemanticSearch(cmd *cobra.Command, query string, limit int) error {
+	ctx := cmd.Context()
+
+	return withEntitySearchService(func(svc *services.EntitySearchService) error {
+		stats, err := svc.Refresh(ctx, globalWorld, services.EntityRefreshOptions{})
+		if err != nil {
+			return fmt.Errorf("updating entity index: %w", err)
+		}
+		if stats.Embedded > 0 || stats.Removed > 0 {
+			fmt.Fprintf(os.Stderr, "Updated entity index: %d embedded, %d removed\n", stats.Embedded, stats.Removed)
+		}
+
+		results, err := svc.Search(ctx, query, limit)
+		if err != nil {
+			return fmt.Errorf("searching entities: %w", err)
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No entities found.")
+			return nil
 		}
 
+		fmt.Printf("Entities matching %q:\n", query)
+		fmt.Println()
+		for _, result := range results {
+			fmt.Printf("  %-40s %-30s %.3f\n", shortEntityID(result.Entity), result.Entity.Name, result.Score)
+		}
 		return nil
 	})
 }
+
+func newEntitiesDeleteCmd() *cobra.Command {
+	var (
+		dryRun bool
+		force  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete an entity and its relationships",
+		Long: `Deletes an entity along with every relationship it takes part in and the
+facts representing those relationships, recording each deletion in the
+audit log. Facts about the entity extracted from sources are kept.
+
+Use --dry-run to see what would be deleted without changing anything.
+
+Examples:
+  lore entities delete "Tom Bombadil" --dry-run
+  lore entities delete "Tom Bombadil" --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEntitiesDelete(cmd, args[0], dryRun, force)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be deleted without deleting it")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runEntitiesDelete(cmd *cobra.Command, name string, dryRun, force bool) error {
+	ctx := cmd.Context()
+
+	return withEntityHandler(func(handler *handlers.EntityHandler) error {
+		plan, err := handler.HandlePlanDelete(ctx, globalWorld, name)
+		if err != nil {
+			return err
+		}
+
+		displayEntityDeletionPlan(plan)
+		if dryRun {
+			fmt.Println("Run without --dry-run to delete them.")
+			return nil
+		}
+
+		if !force && !confirmAction(fmt.Sprintf("Delete %s and %d relationships?", plan.Entity.Name, len(plan.Relationships))) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		if err := handler.HandleDeletePlan(ctx, plan); err != nil {
+			return fmt.Errorf("deleting entity %s: %w", plan.Entity.Name, err)
+		}
+		fmt.Printf("Deleted %s and %d relationships\n", plan.Entity.Name, len(plan.Relationships))
+		return nil
+	})
+}
+
+func newEntitiesRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename an entity and the facts naming it",
+		Long: `Renames an entity and rewrites every fact whose subject or object names it,
+in any spelling that normalizes to the old name. Rewritten facts are
+embedded again and each change is versioned. The entity keeps its
+relationships.
+
+If a rename is interrupted, run it again to finish rewriting the facts.
+
+Examples:
+  lore entities rename Strider Aragorn -w myworld
+  lore entities rename "Mr. Underhill" "Frodo Baggins" -w myworld`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withCanonicalService(func(svc *services.CanonicalService) error {
+				result, err := svc.Rename(ctx, globalWorld, args[0], args[1])
+				if err != nil && result.Entity == nil && result.FactsRewritten == 0 {
+					return err
+				}
+				if result.Entity != nil {
+					fmt.Printf("Renamed entity %s (%s)\n", result.Entity.Name, shortEntityID(result.Entity))
+				}
+				fmt.Printf("Rewrote %d facts\n", result.FactsRewritten)
+				return err
+			})
+		},
+	}
+}
+
+func displayEntityDeletionPlan(plan *services.EntityDeletionPlan) {
+	fmt.Printf("Deleting %s (%s) affects:\n", plan.Entity.Name, shortEntityID(plan.Entity))
+	fmt.Printf("  %d relationships, with their facts\n", len(plan.Relationships))
+	for i := range plan.Relationships {
+		rel := &plan.Relationships[i]
+		fmt.Printf("    %s %s %s\n", rel.Source, rel.Relationship.Type, rel.Target)
+	}
+	fmt.Println()
+}
+
+// shortEntityID truncates an entity ID for display.
+func shortEntityID(entity *entities.Entity) string {
+	if len(entity.ID) > 8 {
+		return entity.ID[:8] + "..."
+	}
+	return entity.ID
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// Exit codes, so scripts can react to the kind of failure. They are stable:
+// new kinds get new codes rather than renumbering these.
+const (
+	exitOK                 = 0
+	exitError              = 1 // Unclassified failure
+	exitConfig             = 2 // Config missing, unreadable, or invalid
+	exitUnavailable        = 3 // Qdrant or the LLM provider is unreachable or timed out
+	exitValidation         = 4 // Invalid flags, arguments, or input
+	exitContradictionFound = 5 // lore check --fail-on-critical found a critical contradiction
+	exitNotFound           = 6 // World, fact, entity, type, or file does not exist
+	exitConflict           = 7 // Clashes with existing state, such as a duplicate name
+)
+
+// Error formats accepted by --error-format.
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// errContradictionFound is returned by lore check --fail-on-critical when a
+// critical contradiction is found.
+var errContradictionFound = entities.Errorf(entities.ErrConflict, "critical contradictions found")
+
+// configError marks a failure to find, read, or validate the config, keeping
+// its message.
+type configError struct {
+	err error
+}
+
+func (e *configError) Error() string { return e.err.Error() }
+func (e *configError) Unwrap() error { return e.err }
+
+// withConfigKind marks err as a config failure. A nil err stays nil.
+func withConfigKind(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &configError{err: err}
+}
+
+// exitCode maps err to the process exit code for its kind.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var cfgErr *configError
+	var validationErr *config.ValidationError
+	switch {
+	case errors.As(err, &cfgErr), errors.As(err, &validationErr), errors.Is(err, config.ErrConfigDirNotFound):
+		return exitConfig
+	case errors.Is(err, errContradictionFound):
+		return exitContradictionFound
+	}
+
+	switch entities.ErrorKind(err) {
+	case entities.ErrValidation:
+		return exitValidation
+	case entities.ErrNotFound:
+		return exitNotFound
+	case entities.ErrConflict:
+		return exitConflict
+	case entities.ErrBackendUnavailable:
+		return exitUnavailable
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return exitNotFound
+	}
+	return exitError
+}
+
+// exitKinds names each exit code in --error-format json output.
+var exitKinds = map[int]string{
+	exitError:              "error",
+	exitConfig:             "config",
+	exitUnavailable:        "backend_unavailable",
+	exitValidation:         "validation",
+	exitContradictionFound: "contradiction_found",
+	exitNotFound:           "not_found",
+	exitConflict:           "conflict",
+}
+
+// errorReport is an error as written by --error-format json.
+type errorReport struct {
+	Error    string `json:"error"`
+	Kind     string `json:"kind"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// reportError writes err to w in format, as a line of text or a JSON object,
+// and returns the exit code for it.
+func reportError(w io.Writer, format string, err error) int {
+	code := exitCode(err)
+	if format != errorFormatJSON {
+		fmt.Fprintf(w, "error: %v\n", err)
+		return code
+	}
+
+	data, merr := json.Marshal(errorReport{Error: err.Error(), Kind: exitKinds[code], ExitCode: code})
+	if merr != nil {
+		fmt.Fprintf(w, "error: %v\n", err)
+		return code
+	}
+	fmt.Fprintf(w, "%s\n", data)
+	return code
+}
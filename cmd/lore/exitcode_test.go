@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+func TestExitCode(t *testing.T) {
+	_, statErr := os.Stat("/nonexistent/lore/file")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"plain", errors.New("boom"), exitError},
+		{"validation", entities.Errorf(entities.ErrValidation, "depth must be between 1 and 5"), exitValidation},
+		{"invalid config", fmt.Errorf("loading config: %w", &config.ValidationError{Problems: []string{"qdrant.port: must be between 1 and 65535, got 0"}}), exitConfig},
+		{"unreadable config", fmt.Errorf("loading config: %w", withConfigKind(errors.New("parsing config file: yaml: line 3"))), exitConfig},
+		{"missing config dir", fmt.Errorf("%w (run 'lore worlds create' first)", config.ErrConfigDirNotFound), exitConfig},
+		{"contradiction found", errContradictionFound, exitContradictionFound},
+		{"not found", fmt.Errorf("loading worlds: %w", entities.Errorf(entities.ErrNotFound, "world %q not found", "x")), exitNotFound},
+		{"missing file", fmt.Errorf("accessing file: %w", statErr), exitNotFound},
+		{"conflict", entities.Errorf(entities.ErrConflict, "world %q already exists", "x"), exitConflict},
+		{"unavailable", entities.WithKind(entities.ErrBackendUnavailable, errors.New("connection refused")), exitUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+func TestReportError(t *testing.T) {
+	err := fmt.Errorf("loading worlds: %w", entities.Errorf(entities.ErrNotFound, "world %q not found", "x"))
+
+	var text bytes.Buffer
+	assert.Equal(t, exitNotFound, reportError(&text, errorFormatText, err))
+	assert.Equal(t, "error: loading worlds: world \"x\" not found\n", text.String())
+
+	var out bytes.Buffer
+	assert.Equal(t, exitNotFound, reportError(&out, errorFormatJSON, err))
+
+	var report errorReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, errorReport{Error: `loading worlds: world "x" not found`, Kind: "not_found", ExitCode: exitNotFound}, report)
+}
+
+func TestExitKinds(t *testing.T) {
+	for code := exitError; code <= exitConflict; code++ {
+		assert.NotEmpty(t, exitKinds[code], "exit code %d has no kind", code)
+	}
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
 )
 
 type exportFlags struct {
@@ -21,13 +25,21 @@ type exportFlags struct {
 	output     string
 	factType   string
 	sourceFile string
+	entity     string
+	depth      int
 	limit      int
+	namespace  string
+	profile    string
+	changes    bool
+	since      string
 }
 
 type exporter struct {
-	repo   ports.VectorDB
-	format string
-	output string
+	repo      ports.VectorDB
+	format    string
+	output    string
+	title     string
+	namespace string // IRI prefix for RDF formats
 }
 
 func newExportCmd() *cobra.Command {
@@ -36,24 +48,68 @@ func newExportCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "export",
 		Short: "Export facts to file",
-		Long:  "Exports facts to JSON, CSV, or markdown format.",
+		Long: `Exports facts to JSON, CSV, markdown, or RDF (Turtle or JSON-LD) format.
+
+RDF exports make each subject an entity and each predicate a property under
+--namespace (or export.namespace in config.yaml; default urn:lore:<world>:),
+and describe each fact as an rdf:Statement with its type, confidence, and
+source, ready to load into a triple store.
+
+With --entity, exports a dossier of the facts whose subject or object is
+the entity or one of the entities related to it within --depth hops of
+the relationship graph.
+
+With --for, exports only the facts an export profile in config.yaml
+reveals, so a world can be shared with beta readers without spoilers:
+
+  export:
+    profiles:
+      book1:
+        sources: ["book1/*", "book2/*"]  # source files in reading order
+        through: "book1/*"               # last source revealed
+        types: [character, location]     # optional
+
+Facts from sources not listed are left out. through may also name a single
+source, such as book2/ch12.md. --limit counts facts before the profile is
+applied.
+
+With --changes, exports the world's change feed instead: one JSON object
+a line for each fact added, updated, or deleted and each relationship
+changed, oldest first. Each has a "seq"; pass the cursor printed to stderr
+as --since next time to export only what changed since. --limit caps the
+changes exported, all by default. 'lore serve' can append the feed to a
+file on a schedule:
+
+  serve:
+    changes:
+      schedule: "@hourly"
+      path: changes.jsonl  # cursor kept in changes.jsonl.cursor`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runExport(cmd, flags)
+			return runExport(cmd, &flags)
 		},
 	}
 
-	cmd.Flags().StringVarP(&flags.format, "format", "f", "json", "Output format (json, csv, markdown)")
+	cmd.Flags().StringVarP(&flags.format, "format", "f", "json", "Output format (json, csv, markdown, turtle, jsonld)")
 	cmd.Flags().StringVarP(&flags.output, "output", "o", "", "Output file (default: stdout)")
 	cmd.Flags().StringVarP(&flags.factType, "type", "t", "", "Filter by fact type")
 	cmd.Flags().StringVarP(&flags.sourceFile, "source", "s", "", "Filter by source file")
+	cmd.Flags().StringVarP(&flags.entity, "entity", "e", "", "Export facts about an entity and its neighborhood")
+	cmd.Flags().IntVarP(&flags.depth, "depth", "d", DefaultExportDepth, "Relationship hops to include with --entity")
 	cmd.Flags().IntVarP(&flags.limit, "limit", "l", DefaultExportLimit, "Maximum number of facts to export")
+	cmd.Flags().StringVar(&flags.profile, "for", "", "Export only what an export profile in config.yaml reveals")
+	cmd.Flags().BoolVar(&flags.changes, "changes", false, "Export the change feed as JSON lines")
+	cmd.Flags().StringVar(&flags.since, "since", "", "With --changes, export only changes after this cursor")
+	cmd.Flags().StringVar(&flags.namespace, "namespace", "", "IRI prefix for turtle and jsonld output (default: export.namespace or urn:lore:<world>:)")
 
 	return cmd
 }
 
-func runExport(cmd *cobra.Command, flags exportFlags) error {
-	if !contains(validFormats, flags.format) {
-		return fmt.Errorf("invalid format %q, valid formats: %v", flags.format, validFormats)
+func runExport(cmd *cobra.Command, flags *exportFlags) error {
+	if flags.changes {
+		return exportChanges(cmd, flags)
+	}
+	if err := validateExportFlags(flags); err != nil {
+		return err
 	}
 
 	ctx := cmd.Context()
@@ -66,25 +122,129 @@ func runExport(cmd *cobra.Command, flags exportFlags) error {
 				if err != nil {
 					return fmt.Errorf("getting valid types: %w", err)
 				}
-				return fmt.Errorf("invalid type %q, valid types: %s", flags.factType, strings.Join(validTypes, ", "))
+				return entities.Errorf(entities.ErrValidation, "invalid type %q, valid types: %s", flags.factType, strings.Join(validTypes, ", "))
+			}
+		}
+
+		var profile *services.ExportProfile
+		if flags.profile != "" {
+			p, err := exportProfile(d.Config.Export, flags.profile)
+			if err != nil {
+				return err
 			}
+			profile = &p
 		}
 
 		e := &exporter{
-			repo:   d.repo,
-			format: flags.format,
-			output: flags.output,
+			repo:      d.repo,
+			format:    flags.format,
+			output:    flags.output,
+			title:     "Exported Facts",
+			namespace: exportNamespace(flags.namespace, d.Config.Export.Namespace, globalWorld),
 		}
 
-		facts, err := e.fetchFacts(ctx, flags.factType, flags.sourceFile, flags.limit)
+		var facts []entities.Fact
+		var err error
+		if flags.entity != "" {
+			relationships := services.NewRelationshipService(d.repo, d.relationalDB, d.embedder)
+			e.title = "Dossier: " + flags.entity
+			facts, err = e.fetchEntityFacts(ctx, relationships, flags.entity, flags.depth, flags.limit)
+		} else {
+			facts, err = e.fetchFacts(ctx, flags.factType, flags.sourceFile, flags.limit)
+		}
 		if err != nil {
 			return err
 		}
+		if profile != nil {
+			facts = profile.Filter(facts)
+			if len(facts) == 0 {
+				return entities.Errorf(entities.ErrNotFound, "export profile %q reveals none of the facts found", flags.profile)
+			}
+		}
+
+		conflicts, err := d.conflictService.OpenCounts(ctx, facts)
+		if err != nil {
+			return err
+		}
+
+		return e.export(facts, conflicts)
+	})
+}
 
-		return e.export(facts)
+// exportChanges exports the world's change feed, for 'lore export
+// --changes'.
+func exportChanges(cmd *cobra.Command, flags *exportFlags) error {
+	if cmd.Flags().Changed("format") || flags.factType != "" || flags.sourceFile != "" || flags.entity != "" || flags.profile != "" {
+		return entities.Errorf(entities.ErrValidation, "--changes cannot be combined with --format, --type, --source, --entity, or --for")
+	}
+	if _, err := services.ParseChangeCursor(flags.since); err != nil {
+		return err
+	}
+	if flags.limit < 1 {
+		return entities.Errorf(entities.ErrValidation, "--limit must be at least 1")
+	}
+	return withInternalDeps(func(d *internalDeps) error {
+		return runExportChanges(cmd.Context(), d, flags, cmd.Flags().Changed("limit"))
 	})
 }
 
+// validateExportFlags checks the flags of a fact export.
+func validateExportFlags(flags *exportFlags) error {
+	if flags.since != "" {
+		return entities.Errorf(entities.ErrValidation, "--since requires --changes")
+	}
+	if !contains(validFormats, flags.format) {
+		return entities.Errorf(entities.ErrValidation, "invalid format %q, valid formats: %v", flags.format, validFormats)
+	}
+	if flags.entity != "" && (flags.factType != "" || flags.sourceFile != "") {
+		return entities.Errorf(entities.ErrValidation, "--entity cannot be combined with --type or --source")
+	}
+	if flags.depth < 0 {
+		return entities.Errorf(entities.ErrValidation, "--depth must not be negative")
+	}
+	if err := config.ValidateNamespace(flags.namespace); err != nil {
+		return entities.Errorf(entities.ErrValidation, "invalid --namespace: %v", err)
+	}
+	return nil
+}
+
+// exportProfile returns the named export profile from config.yaml.
+func exportProfile(cfg config.ExportConfig, name string) (services.ExportProfile, error) {
+	configured, ok := cfg.Profiles[name]
+	if !ok {
+		names := slices.Sorted(maps.Keys(cfg.Profiles))
+		if len(names) == 0 {
+			return services.ExportProfile{}, entities.Errorf(entities.ErrNotFound, "export profile %q not found: none are defined under export.profiles in config.yaml", name)
+		}
+		return services.ExportProfile{}, entities.Errorf(entities.ErrNotFound, "export profile %q not found (defined: %s)", name, strings.Join(names, ", "))
+	}
+
+	profile := services.ExportProfile{
+		Sources: configured.Sources,
+		Through: configured.Through,
+	}
+	for _, t := range configured.Types {
+		profile.Types = append(profile.Types, entities.FactType(t))
+	}
+	if err := profile.Validate(); err != nil {
+		return services.ExportProfile{}, fmt.Errorf("export profile %q: %w", name, err)
+	}
+	return profile, nil
+}
+
+// exportNamespace returns the RDF namespace to export with: the flag, then
+// the configured namespace, then the world's default.
+func exportNamespace(flag, configured, world string) string {
+	switch {
+	case flag != "":
+		return flag
+	case configured != "":
+		return configured
+	default:
+		return defaultNamespace(world)
+	}
+}
+
 func (e *exporter) fetchFacts(ctx context.Context, factType, sourceFile string, limit int) ([]entities.Fact, error) {
 	var facts []entities.Fact
 	var err error
@@ -103,13 +263,42 @@ func (e *exporter) fetchFacts(ctx context.Context, factType, sourceFile string,
 	}
 
 	if len(facts) == 0 {
-		return nil, errors.New("no facts found to export")
+		return nil, entities.Errorf(entities.ErrNotFound, "no facts found to export")
+	}
+
+	return facts, nil
+}
+
+// fetchEntityFacts lists facts about an entity and the entities related to
+// it within depth hops.
+func (e *exporter) fetchEntityFacts(ctx context.Context, relationships *services.RelationshipService, entity string, depth, limit int) ([]entities.Fact, error) {
+	neighborhood, err := relationships.Neighborhood(ctx, globalWorld, entity, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(neighborhood)+1)
+	names = append(names, entity)
+	for _, ent := range neighborhood {
+		if !contains(names, ent.Name) {
+			names = append(names, ent.Name)
+		}
+	}
+
+	facts, err := e.repo.ListByEntities(ctx, names, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts: %w", err)
+	}
+
+	if len(facts) == 0 {
+		return nil, entities.Errorf(entities.ErrNotFound, "no facts found about %q", entity)
 	}
 
 	return facts, nil
 }
 
-func (e *exporter) export(facts []entities.Fact) (err error) {
+// export writes facts with their open conflict counts, keyed by fact ID.
+func (e *exporter) export(facts []entities.Fact, conflicts map[string]int) (err error) {
 	var w io.Writer
 	var f *os.File
 
@@ -128,7 +317,7 @@ func (e *exporter) export(facts []entities.Fact) (err error) {
 		w = os.Stdout
 	}
 
-	if err := e.formatFacts(w, facts); err != nil {
+	if err := e.formatFacts(w, facts, conflicts); err != nil {
 		return fmt.Errorf("formatting output: %w", err)
 	}
 
@@ -139,42 +328,48 @@ func (e *exporter) export(facts []entities.Fact) (err error) {
 	return nil
 }
 
-func (e *exporter) formatFacts(w io.Writer, facts []entities.Fact) error {
+func (e *exporter) formatFacts(w io.Writer, facts []entities.Fact, conflicts map[string]int) error {
 	switch e.format {
 	case "json":
-		return formatJSON(w, facts)
+		return formatJSON(w, facts, conflicts)
 	case "csv":
-		return formatCSV(w, facts)
+		return formatCSV(w, facts, conflicts)
 	case "markdown":
-		return formatMarkdown(w, facts)
+		return formatMarkdown(w, e.title, facts, conflicts)
+	case "turtle":
+		return formatTurtle(w, e.namespace, facts, conflicts)
+	case "jsonld":
+		return formatJSONLD(w, e.namespace, facts, conflicts)
 	default:
 		return fmt.Errorf("unknown format: %s", e.format)
 	}
 }
 
-func formatJSON(w io.Writer, facts []entities.Fact) error {
+func formatJSON(w io.Writer, facts []entities.Fact, conflicts map[string]int) error {
 	type exportFact struct {
-		ID         string  `json:"id"`
-		Type       string  `json:"type"`
-		Subject    string  `json:"subject"`
-		Predicate  string  `json:"predicate"`
-		Object     string  `json:"object"`
-		Context    string  `json:"context,omitempty"`
-		SourceFile string  `json:"source_file,omitempty"`
-		Confidence float64 `json:"confidence"`
+		ID            string  `json:"id"`
+		Type          string  `json:"type"`
+		Subject       string  `json:"subject"`
+		Predicate     string  `json:"predicate"`
+		Object        string  `json:"object"`
+		Context       string  `json:"context,omitempty"`
+		SourceFile    string  `json:"source_file,omitempty"`
+		Confidence    float64 `json:"confidence"`
+		OpenConflicts int     `json:"open_conflicts,omitempty"`
 	}
 
 	exportFacts := make([]exportFact, 0, len(facts))
 	for i := range facts {
 		exportFacts = append(exportFacts, exportFact{
-			ID:         facts[i].ID,
-			Type:       string(facts[i].Type),
-			Subject:    facts[i].Subject,
-			Predicate:  facts[i].Predicate,
-			Object:     facts[i].Object,
-			Context:    facts[i].Context,
-			SourceFile: facts[i].SourceFile,
-			Confidence: facts[i].Confidence,
+			ID:            facts[i].ID,
+			Type:          string(facts[i].Type),
+			Subject:       facts[i].Subject,
+			Predicate:     facts[i].Predicate,
+			Object:        facts[i].Object,
+			Context:       facts[i].Context,
+			SourceFile:    facts[i].SourceFile,
+			Confidence:    facts[i].Confidence,
+			OpenConflicts: conflicts[facts[i].ID],
 		})
 	}
 
@@ -183,10 +378,10 @@ func formatJSON(w io.Writer, facts []entities.Fact) error {
 	return encoder.Encode(exportFacts)
 }
 
-func formatCSV(w io.Writer, facts []entities.Fact) error {
+func formatCSV(w io.Writer, facts []entities.Fact, conflicts map[string]int) error {
 	writer := csv.NewWriter(w)
 
-	header := []string{"id", "type", "subject", "predicate", "object", "context", "source_file", "confidence"}
+	header := []string{"id", "type", "subject", "predicate", "object", "context", "source_file", "confidence", "open_conflicts"}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
@@ -201,6 +396,7 @@ func formatCSV(w io.Writer, facts []entities.Fact) error {
 			facts[i].Context,
 			facts[i].SourceFile,
 			fmt.Sprintf("%.2f", facts[i].Confidence),
+			strconv.Itoa(conflicts[facts[i].ID]),
 		}
 		if err := writer.Write(row); err != nil {
 			return err
@@ -211,15 +407,15 @@ func formatCSV(w io.Writer, facts []entities.Fact) error {
 	return writer.Error()
 }
 
-func formatMarkdown(w io.Writer, facts []entities.Fact) error {
-	if _, err := fmt.Fprintf(w, "# Exported Facts\n\nTotal: %d facts\n\n", len(facts)); err != nil {
+func formatMarkdown(w io.Writer, title string, facts []entities.Fact, conflicts map[string]int) error {
+	if _, err := fmt.Fprintf(w, "# %s\n\nTotal: %d facts\n\n", title, len(facts)); err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprint(w, "| Type | Subject | Predicate | Object | Source |\n"); err != nil {
+	if _, err := fmt.Fprint(w, "| Type | Subject | Predicate | Object | Source | Conflicts |\n"); err != nil {
 		return err
 	}
-	if _, err := fmt.Fprint(w, "|------|---------|-----------|--------|--------|\n"); err != nil {
+	if _, err := fmt.Fprint(w, "|------|---------|-----------|--------|--------|-----------|\n"); err != nil {
 		return err
 	}
 
@@ -228,12 +424,17 @@ func formatMarkdown(w io.Writer, facts []entities.Fact) error {
 		if len(source) > 30 {
 			source = "..." + source[len(source)-27:]
 		}
-		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+		var badge string
+		if n := conflicts[facts[i].ID]; n > 0 {
+			badge = fmt.Sprintf("%d open", n)
+		}
+		if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s | %s |\n",
 			facts[i].Type,
 			escapeMarkdown(facts[i].Subject),
 			escapeMarkdown(facts[i].Predicate),
 			escapeMarkdown(facts[i].Object),
 			escapeMarkdown(source),
+			badge,
 		); err != nil {
 			return err
 		}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// changeCursorSuffix is appended to a change feed file's path to name the
+// file holding the cursor to resume it from.
+const changeCursorSuffix = ".cursor"
+
+// runExportChanges writes the change feed after --since as JSON lines, and
+// the cursor to resume from to stderr.
+func runExportChanges(ctx context.Context, d *internalDeps, flags *exportFlags, limitSet bool) (err error) {
+	limit := 0
+	if limitSet {
+		limit = flags.limit
+	}
+
+	w := io.Writer(os.Stdout)
+	if flags.output != "" {
+		f, err := os.OpenFile(flags.output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return fmt.Errorf("creating file: %w", err)
+		}
+		defer func() {
+			if cerr := f.Close(); cerr != nil && err == nil {
+				err = fmt.Errorf("closing file: %w", cerr)
+			}
+		}()
+		w = f
+	}
+
+	feed := services.NewChangeFeedService(d.relationalDB)
+	written, cursor, err := writeChanges(ctx, feed, w, flags.since, limit)
+	if err != nil {
+		return err
+	}
+	if flags.output != "" {
+		fmt.Printf("Exported %d changes to %s\n", written, flags.output)
+	}
+	fmt.Fprintf(os.Stderr, "Next cursor: %s\n", cursor)
+	return nil
+}
+
+// writeChanges writes up to limit changes made after cursor (0 = all) to w,
+// one JSON object a line, and returns how many it wrote and the cursor to
+// resume from.
+func writeChanges(ctx context.Context, feed *services.ChangeFeedService, w io.Writer, cursor string, limit int) (int, string, error) {
+	enc := json.NewEncoder(w)
+	written := 0
+	for limit == 0 || written < limit {
+		page := services.DefaultChangeFeedLimit
+		if limit > 0 {
+			page = min(page, limit-written)
+		}
+		changes, next, err := feed.Changes(ctx, cursor, page)
+		if err != nil {
+			return written, cursor, err
+		}
+		for i := range changes {
+			if err := enc.Encode(changes[i]); err != nil {
+				return written, cursor, fmt.Errorf("writing change %d: %w", changes[i].Seq, err)
+			}
+			written++
+		}
+		cursor = next
+		if len(changes) < page {
+			break
+		}
+	}
+	return written, cursor, nil
+}
+
+// appendChanges appends the changes made since the last call to the JSONL
+// file at path, resuming from the cursor kept beside it. The cursor only
+// moves once the changes are written, so a failed run is retried in full.
+func appendChanges(ctx context.Context, feed *services.ChangeFeedService, path string) (int, error) {
+	cursorPath := path + changeCursorSuffix
+	cursor, err := readChangeCursor(cursorPath)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("opening change feed file: %w", err)
+	}
+	written, next, err := writeChanges(ctx, feed, f, cursor, 0)
+	if cerr := f.Close(); cerr != nil && err == nil {
+		err = fmt.Errorf("closing change feed file: %w", cerr)
+	}
+	if err != nil {
+		return written, err
+	}
+
+	if err := os.WriteFile(cursorPath, []byte(next+"\n"), 0644); err != nil {
+		return written, fmt.Errorf("saving change feed cursor: %w", err)
+	}
+	return written, nil
+}
+
+// readChangeCursor reads a change feed cursor file. A missing file starts
+// from the beginning of the feed.
+func readChangeCursor(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading change feed cursor: %w", err)
+	}
+	cursor := strings.TrimSpace(string(data))
+	if _, err := services.ParseChangeCursor(cursor); err != nil {
+		return "", entities.Errorf(entities.ErrValidation, "change feed cursor file %s: %w", path, err)
+	}
+	return cursor, nil
+}
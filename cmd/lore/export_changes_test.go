@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// readChangeLines decodes a JSONL change feed file.
+func readChangeLines(t *testing.T, path string) []entities.Change {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var changes []entities.Change
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var c entities.Change
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &c))
+		changes = append(changes, c)
+	}
+	require.NoError(t, scanner.Err())
+	return changes
+}
+
+func TestAppendChanges(t *testing.T) {
+	ctx := context.Background()
+	db := mocks.NewRelationalDB()
+	feed := services.NewChangeFeedService(db)
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+
+	require.NoError(t, db.RecordChanges(ctx, []entities.Change{
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "1", Fact: &entities.Fact{ID: "1", Subject: "Frodo"}},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, ID: "1"},
+	}))
+	written, err := appendChanges(ctx, feed, path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, written)
+
+	cursor, err := readChangeCursor(path + changeCursorSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, "2", cursor)
+
+	written, err = appendChanges(ctx, feed, path)
+	require.NoError(t, err)
+	assert.Zero(t, written, "nothing changed since")
+
+	require.NoError(t, db.RecordChanges(ctx, []entities.Change{{Kind: entities.ChangeKindFact, Op: entities.ChangeOpCleared}}))
+	written, err = appendChanges(ctx, feed, path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, written)
+
+	changes := readChangeLines(t, path)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "Frodo", changes[0].Fact.Subject)
+	assert.Equal(t, int64(3), changes[2].Seq)
+	assert.Equal(t, entities.ChangeOpCleared, changes[2].Op)
+}
+
+func TestWriteChanges_Limit(t *testing.T) {
+	ctx := context.Background()
+	db := mocks.NewRelationalDB()
+	require.NoError(t, db.RecordChanges(ctx, []entities.Change{
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "1"},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "2"},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "3"},
+	}))
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	written, cursor, err := writeChanges(ctx, services.NewChangeFeedService(db), f, "1", 1)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Equal(t, 1, written)
+	assert.Equal(t, "2", cursor)
+
+	changes := readChangeLines(t, path)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "2", changes[0].ID)
+}
+
+func TestReadChangeCursor_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl.cursor")
+	require.NoError(t, os.WriteFile(path, []byte("not-a-cursor\n"), 0644))
+
+	_, err := readChangeCursor(path)
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// Well-known RDF namespaces.
+const (
+	rdfNS  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rdfsNS = "http://www.w3.org/2000/01/rdf-schema#"
+	xsdNS  = "http://www.w3.org/2001/XMLSchema#"
+)
+
+// defaultNamespace is the namespace RDF exports use when none is configured.
+func defaultNamespace(world string) string {
+	return "urn:lore:" + world + ":"
+}
+
+// rdfTerm is the object of an RDF triple: an IRI, or a literal with an
+// optional datatype IRI.
+type rdfTerm struct {
+	IRI      string
+	Value    string
+	Datatype string
+}
+
+// rdfNode is a subject with its predicates and objects, in the order they
+// were added.
+type rdfNode struct {
+	IRI        string
+	Predicates []string
+	Objects    map[string][]rdfTerm
+}
+
+func (n *rdfNode) add(predicate string, object rdfTerm) {
+	if _, ok := n.Objects[predicate]; !ok {
+		n.Predicates = append(n.Predicates, predicate)
+	}
+	n.Objects[predicate] = append(n.Objects[predicate], object)
+}
+
+// rdfGraph builds the triples for a set of facts. Each fact's subject is an
+// entity, its predicate a property, and its object an entity if some fact
+// has it as subject, or a plain literal otherwise. Each fact is also
+// described as an rdf:Statement carrying its type, confidence, source,
+// context, and open conflicts.
+type rdfGraph struct {
+	namespace string
+	nodes     []*rdfNode
+	byIRI     map[string]*rdfNode
+	entities  map[string]string // Normalized name to entity IRI
+}
+
+func newRDFGraph(namespace string, facts []entities.Fact, conflicts map[string]int) *rdfGraph {
+	g := &rdfGraph{
+		namespace: namespace,
+		byIRI:     make(map[string]*rdfNode),
+		entities:  make(map[string]string),
+	}
+
+	// Subjects are entities; the first spelling seen names the IRI.
+	for i := range facts {
+		g.entity(facts[i].Subject)
+	}
+
+	for i := range facts {
+		f := &facts[i]
+		subject := g.entity(f.Subject)
+		predicate := g.iri("property", f.Predicate)
+		object := rdfTerm{Value: f.Object}
+		if iri, ok := g.entities[entities.NormalizeName(f.Object)]; ok {
+			object = rdfTerm{IRI: iri}
+		}
+		g.node(subject).add(predicate, object)
+
+		statement := g.node(g.iri("fact", f.ID))
+		statement.add(rdfNS+"type", rdfTerm{IRI: rdfNS + "Statement"})
+		statement.add(rdfNS+"subject", rdfTerm{IRI: subject})
+		statement.add(rdfNS+"predicate", rdfTerm{IRI: predicate})
+		statement.add(rdfNS+"object", object)
+		statement.add(g.vocab("factType"), rdfTerm{Value: string(f.Type)})
+		statement.add(g.vocab("confidence"), rdfTerm{Value: strconv.FormatFloat(f.Confidence, 'f', 2, 64), Datatype: xsdNS + "decimal"})
+		if f.SourceFile != "" {
+			statement.add(g.vocab("sourceFile"), rdfTerm{Value: f.SourceFile})
+		}
+		if f.Context != "" {
+			statement.add(g.vocab("context"), rdfTerm{Value: f.Context})
+		}
+		if n := conflicts[f.ID]; n > 0 {
+			statement.add(g.vocab("openConflicts"), rdfTerm{Value: strconv.Itoa(n), Datatype: xsdNS + "integer"})
+		}
+	}
+	return g
+}
+
+// entity returns the IRI of the entity named name, labelling it the first
+// time it is seen.
+func (g *rdfGraph) entity(name string) string {
+	key := entities.NormalizeName(name)
+	if iri, ok := g.entities[key]; ok {
+		return iri
+	}
+	iri := g.iri("entity", strings.TrimSpace(name))
+	g.entities[key] = iri
+	g.node(iri).add(rdfsNS+"label", rdfTerm{Value: strings.TrimSpace(name)})
+	return iri
+}
+
+// node returns the node for iri, creating it if needed.
+func (g *rdfGraph) node(iri string) *rdfNode {
+	if n, ok := g.byIRI[iri]; ok {
+		return n
+	}
+	n := &rdfNode{IRI: iri, Objects: make(map[string][]rdfTerm)}
+	g.nodes = append(g.nodes, n)
+	g.byIRI[iri] = n
+	return n
+}
+
+// iri returns the IRI for a name of the given kind, with spaces as
+// underscores and anything not allowed in an IRI percent-encoded.
+func (g *rdfGraph) iri(kind, name string) string {
+	return g.namespace + kind + "/" + url.PathEscape(strings.ReplaceAll(name, " ", "_"))
+}
+
+// vocab returns the IRI of a term describing facts.
+func (g *rdfGraph) vocab(term string) string {
+	return g.namespace + "vocab/" + term
+}
+
+// formatTurtle writes facts as RDF in Turtle syntax.
+func formatTurtle(w io.Writer, namespace string, facts []entities.Fact, conflicts map[string]int) error {
+	var b strings.Builder
+	for _, p := range turtlePrefixes {
+		fmt.Fprintf(&b, "@prefix %s: <%s> .\n", p.prefix, p.ns)
+	}
+
+	for _, n := range newRDFGraph(namespace, facts, conflicts).nodes {
+		fmt.Fprintf(&b, "\n%s", turtleIRI(n.IRI))
+		for i, p := range n.Predicates {
+			if i > 0 {
+				b.WriteString(" ;")
+			}
+			fmt.Fprintf(&b, "\n    %s ", turtleIRI(p))
+			for j, o := range n.Objects[p] {
+				if j > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(turtleTerm(o))
+			}
+		}
+		b.WriteString(" .\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// turtlePrefixes are the prefixes declared in Turtle output.
+var turtlePrefixes = []struct{ prefix, ns string }{
+	{"rdf", rdfNS}, {"rdfs", rdfsNS}, {"xsd", xsdNS},
+}
+
+// turtleIRI writes an IRI, using the declared prefixes.
+func turtleIRI(iri string) string {
+	if iri == rdfNS+"type" {
+		return "a"
+	}
+	for _, p := range turtlePrefixes {
+		if local, ok := strings.CutPrefix(iri, p.ns); ok {
+			return p.prefix + ":" + local
+		}
+	}
+	return "<" + iri + ">"
+}
+
+// turtleTerm writes an object as an IRI or a quoted literal.
+func turtleTerm(t rdfTerm) string {
+	if t.IRI != "" {
+		return turtleIRI(t.IRI)
+	}
+	literal := turtleString(t.Value)
+	if t.Datatype != "" {
+		literal += "^^" + turtleIRI(t.Datatype)
+	}
+	return literal
+}
+
+// turtleString quotes a literal, escaping what Turtle requires.
+func turtleString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// formatJSONLD writes facts as RDF in expanded JSON-LD, which needs no
+// context to be read by linked-data tools.
+func formatJSONLD(w io.Writer, namespace string, facts []entities.Fact, conflicts map[string]int) error {
+	graph := make([]map[string]any, 0)
+	for _, n := range newRDFGraph(namespace, facts, conflicts).nodes {
+		node := map[string]any{"@id": n.IRI}
+		for _, p := range n.Predicates {
+			if p == rdfNS+"type" {
+				types := make([]string, len(n.Objects[p]))
+				for i, o := range n.Objects[p] {
+					types[i] = o.IRI
+				}
+				node["@type"] = types
+				continue
+			}
+			values := make([]map[string]string, len(n.Objects[p]))
+			for i, o := range n.Objects[p] {
+				switch {
+				case o.IRI != "":
+					values[i] = map[string]string{"@id": o.IRI}
+				case o.Datatype != "":
+					values[i] = map[string]string{"@value": o.Value, "@type": o.Datatype}
+				default:
+					values[i] = map[string]string{"@value": o.Value}
+				}
+			}
+			node[p] = values
+		}
+		graph = append(graph, node)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]any{"@graph": graph})
+}
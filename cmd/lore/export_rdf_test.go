@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func rdfTestFacts() []entities.Fact {
+	return []entities.Fact{
+		{ID: "f1", Type: entities.FactTypeCharacter, Subject: "Frodo Baggins", Predicate: "lives_in", Object: "the shire", SourceFile: "ch1.md", Confidence: 0.9},
+		{ID: "f2", Type: entities.FactTypeLocation, Subject: "The Shire", Predicate: "motto", Object: `"Second breakfast"`, Context: "line one\nline two", Confidence: 1},
+	}
+}
+
+func TestFormatTurtle(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, formatTurtle(&buf, "https://example.org/me/", rdfTestFacts(), map[string]int{"f1": 2}))
+	out := buf.String()
+
+	assert.Contains(t, out, "@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .")
+	assert.Contains(t, out, "<https://example.org/me/entity/Frodo_Baggins>\n    rdfs:label \"Frodo Baggins\" ;\n"+
+		"    <https://example.org/me/property/lives_in> <https://example.org/me/entity/The_Shire> .",
+		"object naming a subject becomes that entity")
+	assert.Contains(t, out, `<https://example.org/me/property/motto> "\"Second breakfast\"" .`)
+	assert.Contains(t, out, "<https://example.org/me/fact/f1>\n    a rdf:Statement ;")
+	assert.Contains(t, out, `<https://example.org/me/vocab/confidence> "0.90"^^xsd:decimal`)
+	assert.Contains(t, out, `<https://example.org/me/vocab/sourceFile> "ch1.md"`)
+	assert.Contains(t, out, `<https://example.org/me/vocab/openConflicts> "2"^^xsd:integer`)
+	assert.Contains(t, out, `<https://example.org/me/vocab/context> "line one\nline two"`)
+}
+
+func TestFormatJSONLD(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, formatJSONLD(&buf, "urn:lore:me:", rdfTestFacts(), nil))
+
+	var doc struct {
+		Graph []map[string]any `json:"@graph"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	nodes := make(map[string]map[string]any)
+	for _, n := range doc.Graph {
+		nodes[n["@id"].(string)] = n
+	}
+	require.Len(t, nodes, 4, "two entities and two statements")
+
+	frodo := nodes["urn:lore:me:entity/Frodo_Baggins"]
+	require.NotNil(t, frodo)
+	assert.Equal(t, []any{map[string]any{"@id": "urn:lore:me:entity/The_Shire"}}, frodo["urn:lore:me:property/lives_in"])
+	assert.Equal(t, []any{map[string]any{"@value": "Frodo Baggins"}}, frodo[rdfsNS+"label"])
+
+	statement := nodes["urn:lore:me:fact/f2"]
+	require.NotNil(t, statement)
+	assert.Equal(t, []any{rdfNS + "Statement"}, statement["@type"])
+	assert.Equal(t, []any{map[string]any{"@value": "1.00", "@type": xsdNS + "decimal"}}, statement["urn:lore:me:vocab/confidence"])
+	assert.NotContains(t, statement, "urn:lore:me:vocab/openConflicts")
+}
+
+func TestRDFGraph_EscapesNames(t *testing.T) {
+	g := newRDFGraph("urn:lore:me:", nil, nil)
+	assert.Equal(t, "urn:lore:me:entity/Helm%27s_Deep%3F", g.iri("entity", "Helm's Deep?"))
+	assert.Equal(t, "urn:lore:me:property/a%2Fb%3Cc%3E", g.iri("property", "a/b<c>"))
+}
+
+func TestExportNamespace(t *testing.T) {
+	assert.Equal(t, "https://flag/", exportNamespace("https://flag/", "https://config/", "me"))
+	assert.Equal(t, "https://config/", exportNamespace("", "https://config/", "me"))
+	assert.Equal(t, "urn:lore:me:", exportNamespace("", "", "me"))
+}
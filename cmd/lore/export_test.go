@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -10,6 +11,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
 )
 
 func TestFormatJSON(t *testing.T) {
@@ -27,7 +31,7 @@ func TestFormatJSON(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatJSON(&buf, facts)
+	err := formatJSON(&buf, facts, nil)
 	require.NoError(t, err)
 
 	result := buf.String()
@@ -52,7 +56,7 @@ func TestFormatJSON_EmptyFacts(t *testing.T) {
 	facts := []entities.Fact{}
 
 	var buf bytes.Buffer
-	err := formatJSON(&buf, facts)
+	err := formatJSON(&buf, facts, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "[]\n", buf.String())
 }
@@ -72,7 +76,7 @@ func TestFormatCSV(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatCSV(&buf, facts)
+	err := formatCSV(&buf, facts, nil)
 	require.NoError(t, err)
 
 	result := buf.String()
@@ -80,7 +84,7 @@ func TestFormatCSV(t *testing.T) {
 	require.Len(t, lines, 2)
 
 	// Check header
-	assert.Equal(t, "id,type,subject,predicate,object,context,source_file,confidence", lines[0])
+	assert.Equal(t, "id,type,subject,predicate,object,context,source_file,confidence,open_conflicts", lines[0])
 
 	// Check data row
 	assert.Contains(t, lines[1], "test-id-1")
@@ -89,6 +93,33 @@ func TestFormatCSV(t *testing.T) {
 	assert.Contains(t, lines[1], "0.95")
 }
 
+func TestFormatFacts_OpenConflicts(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue"},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "is", Object: "loyal"},
+	}
+	conflicts := map[string]int{"a": 2}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatJSON(&buf, facts, conflicts))
+	var parsed []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, 2.0, parsed[0]["open_conflicts"])
+	assert.NotContains(t, parsed[1], "open_conflicts", "omitted without conflicts")
+
+	buf.Reset()
+	require.NoError(t, formatCSV(&buf, facts, conflicts))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasSuffix(lines[1], ",2"))
+	assert.True(t, strings.HasSuffix(lines[2], ",0"))
+
+	buf.Reset()
+	require.NoError(t, formatMarkdown(&buf, "Exported Facts", facts, conflicts))
+	assert.Contains(t, buf.String(), "| character | Frodo | eye_color | blue |  | 2 open |")
+	assert.Contains(t, buf.String(), "| character | Sam | is | loyal |  |  |")
+}
+
 func TestFormatCSV_SpecialCharacters(t *testing.T) {
 	facts := []entities.Fact{
 		{
@@ -101,7 +132,7 @@ func TestFormatCSV_SpecialCharacters(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatCSV(&buf, facts)
+	err := formatCSV(&buf, facts, nil)
 	require.NoError(t, err)
 
 	result := buf.String()
@@ -123,7 +154,7 @@ func TestFormatMarkdown(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatMarkdown(&buf, facts)
+	err := formatMarkdown(&buf, "Exported Facts", facts, nil)
 	require.NoError(t, err)
 
 	result := buf.String()
@@ -147,7 +178,7 @@ func TestFormatMarkdown_LongSourceTruncated(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	err := formatMarkdown(&buf, facts)
+	err := formatMarkdown(&buf, "Exported Facts", facts, nil)
 	require.NoError(t, err)
 
 	result := buf.String()
@@ -202,3 +233,45 @@ func TestContains(t *testing.T) {
 	assert.False(t, contains(slice, ""))
 	assert.False(t, contains(slice, "JSON")) // case sensitive
 }
+
+func TestFetchEntityFacts(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	_, err := relationalDB.FindOrCreateEntity(context.Background(), globalWorld, "Frodo")
+	require.NoError(t, err)
+
+	repo := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Subject: "Frodo", Predicate: "carries", Object: "the Ring"},
+		{ID: "2", Subject: "Sam", Predicate: "follows", Object: "Frodo"},
+		{ID: "3", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
+	}}
+	relationships := services.NewRelationshipService(repo, relationalDB, &mocks.Embedder{})
+	e := &exporter{repo: repo}
+
+	facts, err := e.fetchEntityFacts(context.Background(), relationships, "frodo", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, facts, 2)
+	assert.Equal(t, "1", facts[0].ID)
+	assert.Equal(t, "2", facts[1].ID)
+
+	_, err = e.fetchEntityFacts(context.Background(), relationships, "Sauron", 1, 10)
+	require.Error(t, err)
+}
+
+func TestExportProfile(t *testing.T) {
+	cfg := config.ExportConfig{Profiles: map[string]config.ExportProfileConfig{
+		"book1": {Sources: []string{"book1/*", "book2/*"}, Through: "book1/*", Types: []string{"character"}},
+		"typo":  {Sources: []string{"book1/*"}, Through: "book3/ch1.md"},
+	}}
+
+	profile, err := exportProfile(cfg, "book1")
+	require.NoError(t, err)
+	assert.Equal(t, []entities.FactType{entities.FactTypeCharacter}, profile.Types)
+
+	_, err = exportProfile(cfg, "typo")
+	require.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), `export profile "typo"`)
+
+	_, err = exportProfile(cfg, "book2")
+	require.ErrorIs(t, err, entities.ErrNotFound)
+	assert.Contains(t, err.Error(), "defined: book1, typo")
+}
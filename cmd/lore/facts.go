@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newFactsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "facts",
+		Short: "Look up, add, and edit facts",
+	}
+
+	cmd.AddCommand(
+		newFactsFindCmd(),
+		newFactsAddCmd(),
+		newFactsEditCmd(),
+		newFactsKnownByCmd(),
+		newFactsPromoteCmd(),
+		newFactsPurgeDraftsCmd(),
+	)
+
+	return cmd
+}
+
+func newFactsFindCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "find <subject>",
+		Short: "Find facts about a subject",
+		Long: `Finds facts about a subject, trying progressively looser matches until
+enough facts are found:
+
+  entity    Facts linked to the entity by that name, under any spelling
+            merged into it
+  exact     The subject exactly as written
+  fuzzy     The subject ignoring case, with extra words, or with small typos
+  semantic  Facts semantically related to the subject
+
+Each result is labeled with the tier that found it.
+
+Examples:
+  lore facts find Frodo -w myworld
+  lore facts find "frodo baggins" -w myworld --limit 50`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withInternalDeps(func(d *internalDeps) error {
+				result, err := d.QueryHandler.HandleFindSubject(ctx, globalWorld, args[0], limit)
+				if err != nil {
+					return err
+				}
+
+				facts := make([]entities.Fact, len(result.Matches))
+				for i := range result.Matches {
+					facts[i] = result.Matches[i].Fact
+				}
+				conflicts, err := d.conflictService.OpenCounts(ctx, facts)
+				if err != nil {
+					return err
+				}
+
+				printSubjectMatches(result, conflicts)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", DefaultQueryLimit, "Maximum number of results")
+
+	return cmd
+}
+
+type factsAddFlags struct {
+	factType   string
+	subject    string
+	predicate  string
+	object     string
+	context    string
+	source     string
+	confidence float64
+	force      bool
+	draft      bool
+	ttl        time.Duration
+}
+
+func newFactsAddCmd() *cobra.Command {
+	var flags factsAddFlags
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a fact by hand",
+		Long: `Adds a single fact without calling the LLM. The type must be a known
+entity type (see 'lore types list'). The fact is embedded, versioned, and
+recorded in the audit log like any other change.
+
+If similar facts already exist, they are shown and you are asked before the
+fact is added. --force adds it without asking.
+
+--draft adds a speculative fact, as while brainstorming: it is kept out of
+consistency checks until promoted with 'lore facts promote', or removed
+with 'lore facts purge-drafts'. --ttl makes it a draft that lapses after
+that long, left out of query results until purged.
+
+Examples:
+  lore facts add --type character --subject Frodo --predicate eye_color --object blue -w myworld
+  lore facts add --type location --subject Rivendell --predicate located_in --object Eriador \
+    --source notes/geography.md -w myworld
+  lore facts add --type character --subject Sam --predicate marries --object Rosie --ttl 336h -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := services.NewFact{
+				Type:       entities.FactType(flags.factType),
+				Subject:    flags.subject,
+				Predicate:  flags.predicate,
+				Object:     flags.object,
+				Context:    flags.context,
+				SourceFile: flags.source,
+				Confidence: flags.confidence,
+				Draft:      flags.draft || flags.ttl != 0,
+				TTL:        flags.ttl,
+			}
+			ctx := cmd.Context()
+
+			return withFactHandler(func(handler *handlers.FactHandler) error {
+				fact, similar, err := handler.HandleCheck(ctx, input)
+				if err != nil {
+					return err
+				}
+
+				if len(similar) > 0 {
+					printSimilarFacts(similar)
+					if !flags.force && !confirmAction("Add anyway?") {
+						fmt.Println("Fact not added.")
+						return nil
+					}
+				}
+
+				if err := handler.HandleSave(ctx, fact); err != nil {
+					return err
+				}
+
+				fmt.Println("Added fact:")
+				fmt.Println()
+				displayFact(os.Stdout, fact, 0)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.factType, "type", "t", "", "Entity type of the subject (required)")
+	cmd.Flags().StringVar(&flags.subject, "subject", "", "Subject of the fact (required)")
+	cmd.Flags().StringVar(&flags.predicate, "predicate", "", "Predicate of the fact (required)")
+	cmd.Flags().StringVar(&flags.object, "object", "", "Object of the fact (required)")
+	cmd.Flags().StringVar(&flags.context, "context", "", "Supporting context")
+	cmd.Flags().StringVar(&flags.source, "source", services.ManualSource, "Source to record for the fact")
+	cmd.Flags().Float64Var(&flags.confidence, "confidence", 1.0, "Confidence between 0 and 1")
+	cmd.Flags().BoolVarP(&flags.force, "force", "f", false, "Add even if similar facts exist")
+	cmd.Flags().BoolVar(&flags.draft, "draft", false, "Add as a draft, kept out of consistency checks")
+	cmd.Flags().DurationVar(&flags.ttl, "ttl", 0, "Add as a draft that lapses after this long (e.g. 72h)")
+
+	return cmd
+}
+
+func newFactsKnownByCmd() *cobra.Command {
+	var common bool
+
+	cmd := &cobra.Command{
+		Use:   "known-by <fact-id> [entity...]",
+		Short: "Record which characters know a fact",
+		Long: `Records the entities that know a fact, replacing any recorded before.
+Facts without such a list are common knowledge. Whatever the list, the
+subject and object of a fact always know it.
+
+'lore query --pov <entity>' leaves out facts the entity does not know.
+Entities not yet recorded are created. --clear makes the fact common
+knowledge again.
+
+Examples:
+  lore facts known-by 3f2a... Gandalf Bilbo -w myworld
+  lore facts known-by 3f2a... --clear -w myworld`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, names := args[0], args[1:]
+			if common && len(names) > 0 {
+				return entities.Errorf(entities.ErrValidation, "--clear cannot be combined with entity names")
+			}
+			if !common && len(names) == 0 {
+				return entities.Errorf(entities.ErrValidation, "name at least one entity, or use --clear")
+			}
+			ctx := cmd.Context()
+
+			return withFactHandler(func(handler *handlers.FactHandler) error {
+				fact, err := handler.HandleSetKnownBy(ctx, id, globalWorld, names)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("%s %s %s\n", fact.Subject, fact.Predicate, fact.Object)
+				if len(fact.KnownBy) == 0 {
+					fmt.Println("is now common knowledge.")
+					return nil
+				}
+				fmt.Printf("is now known to: %s\n", strings.Join(names, ", "))
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&common, "clear", false, "Make the fact common knowledge")
+
+	return cmd
+}
+
+// printSimilarFacts shows existing facts that are likely duplicates.
+func printSimilarFacts(similar []services.SimilarFact) {
+	for i := range similar {
+		fact := &similar[i].Fact
+		fmt.Printf("A similar fact exists: %s %s %s", fact.Subject, fact.Predicate, fact.Object)
+		if fact.SourceFile != "" {
+			fmt.Printf(" from %s", fact.SourceFile)
+		}
+		fmt.Printf(" (%.0f%% similar, ID %s)\n", similar[i].Similarity*100, fact.ID)
+	}
+}
+
+func printSubjectMatches(result *handlers.SubjectResult, conflicts map[string]int) {
+	if len(result.Matches) == 0 {
+		fmt.Printf("No facts found about %q.\n", result.Subject)
+		return
+	}
+
+	fmt.Printf("Found %d facts about %q:\n\n", len(result.Matches), result.Subject)
+
+	var tier services.MatchTier
+	for i := range result.Matches {
+		if result.Matches[i].Tier != tier {
+			tier = result.Matches[i].Tier
+			fmt.Printf("-- %s matches --\n", tier)
+		}
+		fact := &result.Matches[i].Fact
+		printFact(i+1, fact, conflicts[fact.ID])
+	}
+}
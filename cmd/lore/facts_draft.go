@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newFactsPromoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote <fact-id>...",
+		Short: "Make draft facts canon",
+		Long: `Promotes drafts to canon: they no longer expire, and are checked for
+contradictions, and checked against, like any other fact. Run 'lore check'
+afterwards to find what they contradict. Each promotion is versioned.
+
+Examples:
+  lore facts promote 3f2a... -w myworld`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withFactHandler(func(handler *handlers.FactHandler) error {
+				for _, id := range args {
+					fact, err := handler.HandlePromote(ctx, id)
+					if err != nil {
+						return fmt.Errorf("promoting %s: %w", id, err)
+					}
+					fmt.Printf("Promoted %s: %s %s %s\n", fact.ID, fact.Subject, fact.Predicate, fact.Object)
+				}
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
+
+type purgeDraftsFlags struct {
+	source  string
+	expired bool
+	dryRun  bool
+	force   bool
+}
+
+func newFactsPurgeDraftsCmd() *cobra.Command {
+	var flags purgeDraftsFlags
+
+	cmd := &cobra.Command{
+		Use:   "purge-drafts",
+		Short: "Remove draft facts in bulk",
+		Long: `Removes draft facts, as when a draft direction is abandoned: every draft,
+those from one source with --source, or only those past their TTL with
+--expired. Each removal is versioned, so a purged draft can still be found
+in its history. --dry-run lists the drafts without removing them.
+
+Examples:
+  lore facts purge-drafts --expired -w myworld
+  lore facts purge-drafts --source notes/brainstorm.md --dry-run -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			opts := services.DraftPurge{SourceFile: flags.source, ExpiredOnly: flags.expired, DryRun: true}
+
+			return withFactHandler(func(handler *handlers.FactHandler) error {
+				drafts, err := handler.HandlePurgeDrafts(ctx, opts)
+				if err != nil {
+					return err
+				}
+				if len(drafts) == 0 {
+					fmt.Println("No drafts to purge.")
+					return nil
+				}
+
+				for i := range drafts {
+					fmt.Printf("  %s: %s %s %s\n", drafts[i].ID, drafts[i].Subject, drafts[i].Predicate, drafts[i].Object)
+				}
+				if flags.dryRun {
+					fmt.Printf("Would purge %d draft(s).\n", len(drafts))
+					return nil
+				}
+				if !flags.force && !confirmAction(fmt.Sprintf("Purge %d draft(s)?", len(drafts))) {
+					fmt.Println("Nothing purged.")
+					return nil
+				}
+
+				opts.DryRun = false
+				purged, err := handler.HandlePurgeDrafts(ctx, opts)
+				fmt.Printf("Purged %d draft(s).\n", len(purged))
+				return err
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.source, "source", "s", "", "Only drafts from this source file")
+	cmd.Flags().BoolVar(&flags.expired, "expired", false, "Only drafts past their TTL")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "List the drafts without removing them")
+	cmd.Flags().BoolVarP(&flags.force, "force", "f", false, "Purge without asking")
+
+	return cmd
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
+)
+
+// defaultEditor is run when neither $VISUAL nor $EDITOR is set.
+const defaultEditor = "vi"
+
+func newFactsEditCmd() *cobra.Command {
+	var (
+		source string
+		limit  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Edit a source's facts in your editor",
+		Long: `Opens the facts recorded for a source in $VISUAL or $EDITOR as a YAML
+list. When you save and close the editor:
+
+  - rows you changed are updated
+  - rows you removed are deleted
+  - rows you added without an id are added to the source
+
+Every row is validated before anything is changed. If the file has errors,
+nothing is applied and the edited file is kept so your changes are not
+lost.
+
+For a source with no facts yet, the file is an empty scaffold to fill in.
+
+Examples:
+  lore facts edit --source notes -w myworld
+  EDITOR=nano lore facts edit --source chapter1.txt -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(source) == "" {
+				return entities.Errorf(entities.ErrValidation, "--source is required")
+			}
+			ctx := cmd.Context()
+
+			return withFactHandler(func(handler *handlers.FactHandler) error {
+				return runFactsEdit(ctx, handler, source, limit)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&source, "source", "s", "", "Source whose facts to edit (required)")
+	cmd.Flags().IntVarP(&limit, "limit", "l", DefaultExportLimit, "Maximum number of facts to load")
+
+	return cmd
+}
+
+func runFactsEdit(ctx context.Context, handler *handlers.FactHandler, source string, limit int) error {
+	before, err := handler.HandleListSource(ctx, source, limit)
+	if err != nil {
+		return err
+	}
+	if len(before) == limit {
+		fmt.Printf("Showing the first %d facts from %s; raise --limit to edit more.\n", limit, source)
+	}
+
+	scaffold, err := factsEditScaffold(source, before)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "lore-facts-*.yaml")
+	if err != nil {
+		return fmt.Errorf("creating edit file: %w", err)
+	}
+	path := file.Name()
+	_, err = file.Write(scaffold)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("writing edit file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading edit file: %w", err)
+	}
+	if bytes.Equal(edited, scaffold) {
+		os.Remove(path)
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	batch, err := planFactsEdit(ctx, handler, source, before, edited)
+	if err != nil {
+		return fmt.Errorf("%w\nnothing was changed; your edits are saved in %s", err, path)
+	}
+	os.Remove(path)
+
+	if batch.IsEmpty() {
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	result, err := handler.HandleApplyEdit(ctx, batch)
+	fmt.Printf("%s: %d added, %d updated, %d deleted\n", source, result.Added, result.Updated, result.Deleted)
+	return err
+}
+
+func planFactsEdit(ctx context.Context, handler *handlers.FactHandler, source string, before []entities.Fact, edited []byte) (*services.FactBatch, error) {
+	parser := &parsers.YAMLParser{}
+	rows, err := parser.Parse(bytes.NewReader(edited))
+	if err != nil {
+		return nil, entities.WithKind(entities.ErrValidation, err)
+	}
+	return handler.HandlePlanEdit(ctx, source, before, rows)
+}
+
+// factsEditScaffold renders a source's facts as the YAML list edited by
+// 'lore facts edit', with instructions in a leading comment.
+func factsEditScaffold(source string, facts []entities.Fact) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Facts from %s. Save and close to apply:\n", source)
+	buf.WriteString(`#   - change a row to update the fact
+#   - remove a row to delete the fact
+#   - add a row without an id to add a fact, for example:
+#
+#   - type: character
+#     subject: Frodo
+#     predicate: eye_color
+#     object: blue
+#     context: optional supporting text
+#
+# Leave the file unchanged to cancel.
+`)
+	if len(facts) == 0 {
+		return buf.Bytes(), nil
+	}
+	buf.WriteString("\n")
+
+	rows := make([]entities.RawFact, len(facts))
+	for i := range facts {
+		rows[i] = entities.RawFact{
+			ID:        facts[i].ID,
+			Type:      string(facts[i].Type),
+			Subject:   facts[i].Subject,
+			Predicate: facts[i].Predicate,
+			Object:    facts[i].Object,
+			Context:   facts[i].Context,
+		}
+		if facts[i].SourceFile != source {
+			rows[i].SourceFile = facts[i].SourceFile
+		}
+		if facts[i].Confidence != 1.0 {
+			rows[i].Confidence = &facts[i].Confidence
+		}
+	}
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(rows); err != nil {
+		return nil, fmt.Errorf("rendering facts: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("rendering facts: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// runEditor opens path in the user's editor and waits for it to exit.
+func runEditor(path string) error {
+	args := editorCommand()
+	editor := exec.Command(args[0], append(args[1:], path)...)
+	editor.Stdin = os.Stdin
+	editor.Stdout = os.Stdout
+	editor.Stderr = os.Stderr
+	if err := editor.Run(); err != nil {
+		return fmt.Errorf("running editor %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// editorCommand returns the editor to run, split into program and
+// arguments, from $VISUAL, then $EDITOR, then defaultEditor.
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(env)); len(args) > 0 {
+			return args
+		}
+	}
+	return []string{defaultEditor}
+}
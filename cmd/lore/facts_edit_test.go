@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
+)
+
+func TestFactsEditScaffold(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue", SourceFile: "notes", Confidence: 1},
+		{ID: "b", Type: entities.FactTypeLocation, Subject: "Bree", Predicate: "located_in", Object: "Eriador", Context: "an inn town", SourceFile: "maps", Confidence: 0.8},
+	}
+
+	scaffold, err := factsEditScaffold("notes", facts)
+	require.NoError(t, err)
+	assert.Contains(t, string(scaffold), "# Facts from notes.")
+
+	rows, err := (&parsers.YAMLParser{}).Parse(bytes.NewReader(scaffold))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, "a", rows[0].ID)
+	assert.Equal(t, "blue", rows[0].Object)
+	assert.Empty(t, rows[0].SourceFile, "the edited source is implied")
+	assert.Nil(t, rows[0].Confidence, "full confidence is implied")
+
+	assert.Equal(t, "an inn town", rows[1].Context)
+	assert.Equal(t, "maps", rows[1].SourceFile)
+	require.NotNil(t, rows[1].Confidence)
+	assert.InDelta(t, 0.8, *rows[1].Confidence, 0.001)
+}
+
+func TestFactsEditScaffold_Empty(t *testing.T) {
+	scaffold, err := factsEditScaffold("notes", nil)
+	require.NoError(t, err)
+
+	rows, err := (&parsers.YAMLParser{}).Parse(bytes.NewReader(scaffold))
+	require.NoError(t, err)
+	assert.Empty(t, rows, "the scaffold for a new source is only comments")
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		visual string
+		editor string
+		want   []string
+	}{
+		{name: "default", want: []string{defaultEditor}},
+		{name: "EDITOR", editor: "nano", want: []string{"nano"}},
+		{name: "VISUAL wins", visual: "code --wait", editor: "nano", want: []string{"code", "--wait"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			assert.Equal(t, tt.want, editorCommand())
+		})
+	}
+}
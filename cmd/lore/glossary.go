@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+type glossaryFlags struct {
+	output      string
+	limit       int
+	minMentions int
+	dictionary  string
+}
+
+func newGlossaryCmd() *cobra.Command {
+	var flags glossaryFlags
+
+	cmd := &cobra.Command{
+		Use:   "glossary",
+		Short: "Generate a glossary of the world's invented terms",
+		Long: `Finds the invented terms among the subjects and objects of the world's
+facts: names with a word that is not an ordinary English word. Each term is
+defined from the most confident facts about it and counted across facts and
+source files.
+
+The glossary is Markdown, with a table per fact type, ready to use as an
+appendix. Ordinary words are read from a built-in list of common English
+words; use --dictionary to read a fuller list, one word per line, such as
+/usr/share/dict/words.
+
+Examples:
+  lore glossary -w myworld
+  lore glossary -w myworld --min-mentions 3 -o glossary.md
+  lore glossary -w myworld --dictionary /usr/share/dict/words`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if flags.limit < 0 {
+				return entities.Errorf(entities.ErrValidation, "--limit must not be negative")
+			}
+			if flags.minMentions < 0 {
+				return entities.Errorf(entities.ErrValidation, "--min-mentions must not be negative")
+			}
+			ctx := cmd.Context()
+
+			return withGlossaryService(flags.dictionary, func(svc *services.GlossaryService) error {
+				glossary, err := svc.Build(ctx, services.GlossaryOptions{
+					Limit:       flags.limit,
+					MinMentions: flags.minMentions,
+				})
+				if err != nil {
+					return err
+				}
+				return writeGlossary(flags.output, globalWorld, glossary)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.output, "output", "o", "", "Output file (default: stdout)")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", services.DefaultGlossaryLimit, "Maximum number of facts to read")
+	cmd.Flags().IntVar(&flags.minMentions, "min-mentions", 1, "Leave out terms mentioned by fewer facts")
+	cmd.Flags().StringVar(&flags.dictionary, "dictionary", "", "Word list of ordinary words, one per line (default: built-in English)")
+
+	return cmd
+}
+
+// writeGlossary writes the glossary as Markdown to output, or stdout if
+// output is empty.
+func writeGlossary(output, world string, glossary *services.Glossary) (err error) {
+	if output == "" {
+		return formatGlossary(os.Stdout, world, glossary)
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing file: %w", cerr)
+		}
+	}()
+
+	if err := formatGlossary(f, world, glossary); err != nil {
+		return fmt.Errorf("formatting output: %w", err)
+	}
+
+	fmt.Printf("Wrote %d terms to %s\n", len(glossary.Entries), output)
+	return nil
+}
+
+// formatGlossary writes the glossary as Markdown, one table per fact type
+// in alphabetical order, with terms that are never a subject last.
+func formatGlossary(w io.Writer, world string, glossary *services.Glossary) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Glossary of %s\n", world)
+	if len(glossary.Entries) == 0 {
+		b.WriteString("\nNo invented terms found.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	if glossary.Truncated {
+		fmt.Fprintf(&b, "\nRead the first %d facts. More facts exist; raise --limit to include them.\n", glossary.Facts)
+	}
+
+	byType := make(map[entities.FactType][]services.GlossaryEntry)
+	var types []entities.FactType
+	for _, e := range glossary.Entries {
+		if _, ok := byType[e.Type]; !ok && e.Type != "" {
+			types = append(types, e.Type)
+		}
+		byType[e.Type] = append(byType[e.Type], e)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	if _, ok := byType[""]; ok {
+		types = append(types, "")
+	}
+
+	for _, t := range types {
+		fmt.Fprintf(&b, "\n## %s\n\n", glossaryHeading(t))
+		b.WriteString("| Term | Definition | Mentions | Sources |\n")
+		b.WriteString("|------|------------|----------|---------|\n")
+		for _, e := range byType[t] {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d |\n", escapeMarkdown(e.Term), escapeMarkdown(e.Definition), e.Mentions, len(e.Sources))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// glossaryHeading names the section for a fact type, such as "Character"
+// for character facts and "Other" for terms that are never a subject.
+func glossaryHeading(t entities.FactType) string {
+	if t == "" {
+		return "Other"
+	}
+	name := strings.ReplaceAll(string(t), "_", " ")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
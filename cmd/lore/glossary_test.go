@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestFormatGlossary(t *testing.T) {
+	glossary := &services.Glossary{
+		Facts:     10,
+		Truncated: true,
+		Entries: []services.GlossaryEntry{
+			{Term: "Dragonlord", Type: entities.FactTypeCharacter, Definition: "Rules the north.", Mentions: 3, Sources: []string{"ch1.md", "ch2.md"}},
+			{Term: "Hobbiton", Definition: "Frodo born in Hobbiton.", Mentions: 1},
+			{Term: "Ember | Hall", Type: entities.FactTypeLocation, Definition: "Lies in the north.", Mentions: 2, Sources: []string{"ch2.md"}},
+			{Term: "Frodo", Type: entities.FactTypeCharacter, Definition: "Is a hobbit.", Mentions: 4, Sources: []string{"ch3.md"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, formatGlossary(&buf, "middle-earth", glossary))
+	out := buf.String()
+
+	assert.Contains(t, out, "# Glossary of middle-earth")
+	assert.Contains(t, out, "Read the first 10 facts. More facts exist")
+	assert.Contains(t, out, "## Character\n\n| Term | Definition | Mentions | Sources |")
+	assert.Contains(t, out, "| Dragonlord | Rules the north. | 3 | 2 |")
+	assert.Contains(t, out, "| Ember \\| Hall | Lies in the north. | 2 | 1 |")
+	assert.Less(t, strings.Index(out, "Dragonlord"), strings.Index(out, "Frodo"))
+	assert.Less(t, strings.Index(out, "## Character"), strings.Index(out, "## Location"))
+	assert.Less(t, strings.Index(out, "## Location"), strings.Index(out, "## Other"))
+	assert.Contains(t, out, "| Hobbiton | Frodo born in Hobbiton. | 1 | 0 |")
+}
+
+func TestFormatGlossary_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, formatGlossary(&buf, "empty", &services.Glossary{}))
+	assert.Contains(t, buf.String(), "No invented terms found.")
+}
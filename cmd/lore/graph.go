@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/graphdb/neo4j"
+)
+
+func newGraphCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Export the entity graph to Neo4j or query it there",
+		Long: `Exports the world's entities and relationships as Cypher, or keeps a
+Neo4j database in step with them for graph queries lore cannot run itself.
+
+Syncing and querying need a graph database in the config:
+
+  graph:
+    provider: neo4j
+    url: http://localhost:7474
+    username: neo4j
+
+The password is read from NEO4J_PASSWORD.`,
+	}
+
+	cmd.AddCommand(
+		newGraphExportCmd(),
+		newGraphSyncCmd(),
+		newGraphQueryCmd(),
+	)
+
+	return cmd
+}
+
+func newGraphExportCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the entity graph as a Cypher script",
+		Long: `Writes the world's entities and relationships as Cypher MERGE
+statements. Loading the script again after the world changes updates the
+graph rather than duplicating it. No graph database is needed.
+
+Examples:
+  lore graph export -w myworld -o world.cypher
+  cypher-shell -u neo4j -f world.cypher`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withGraphService(func(svc *services.GraphService) error {
+				graph, err := svc.Load(ctx, globalWorld)
+				if err != nil {
+					return err
+				}
+				return writeCypher(output, globalWorld, graph)
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file (default: stdout)")
+
+	return cmd
+}
+
+func newGraphSyncCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sync",
+		Short: "Make the graph database match the world",
+		Long: `Copies the world's entities and relationships to the configured graph
+database, and removes those deleted from the world since the last sync.
+
+Examples:
+  lore graph sync -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withGraphService(func(svc *services.GraphService) error {
+				graph, err := svc.Sync(ctx, globalWorld)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Synced %d entities and %d relationships\n", len(graph.Entities), len(graph.Relationships))
+				return nil
+			})
+		},
+	}
+}
+
+func newGraphQueryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "query <cypher>",
+		Short: "Run a read-only Cypher query against the graph database",
+		Long: `Runs a read-only Cypher query against the configured graph database and
+prints the rows as JSON. Entities are nodes labelled Entity with id, world,
+name, and normalized_name properties; relationships have upper-case types,
+such as LOCATED_IN for located_in. Sync the world first.
+
+Examples:
+  lore graph query "MATCH p = shortestPath((a:Entity {name: 'Alice'})-[*]-(b:Entity {name: 'Bob'})) RETURN [n IN nodes(p) | n.name] AS path"
+  lore graph query "MATCH (e:Entity {world: 'myworld'}) RETURN e.name AS name, COUNT { (e)--() } AS degree ORDER BY degree DESC LIMIT 10"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withGraphService(func(svc *services.GraphService) error {
+				rows, err := svc.Query(ctx, args[0], nil)
+				if err != nil {
+					return err
+				}
+				if rows == nil {
+					rows = []map[string]any{}
+				}
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(rows)
+			})
+		},
+	}
+}
+
+// writeCypher writes the graph as a Cypher script to output, or to stdout if
+// output is empty.
+func writeCypher(output, world string, graph *services.Graph) (err error) {
+	if output == "" {
+		return neo4j.WriteScript(os.Stdout, world, graph.Entities, graph.Relationships)
+	}
+
+	f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing file: %w", cerr)
+		}
+	}()
+
+	if err := neo4j.WriteScript(f, world, graph.Entities, graph.Relationships); err != nil {
+		return fmt.Errorf("writing cypher: %w", err)
+	}
+
+	fmt.Printf("Wrote %d entities and %d relationships to %s\n", len(graph.Entities), len(graph.Relationships), output)
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/git"
+)
+
+// hookMarker marks hooks written by 'lore hooks install', which it may
+// replace without --force.
+const hookMarker = "# Installed by 'lore hooks install'"
+
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage git hooks that check manuscript changes",
+	}
+
+	cmd.AddCommand(newHooksInstallCmd())
+
+	return cmd
+}
+
+func newHooksInstallCmd() *cobra.Command {
+	var (
+		hook    string
+		pattern string
+		force   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install a git hook that stops commits with critical contradictions",
+		Long: `Installs a git hook in the repository containing the current directory.
+
+The pre-commit hook runs 'lore check --staged', which extracts facts from
+the staged manuscript files matching --pattern and checks them against the
+world's facts, and stops the commit if a critical contradiction is found.
+The pre-push hook checks the files changed since the branch's upstream with
+'lore check --git-diff' instead, and stops the push. Either can be skipped
+once with --no-verify.
+
+The hook runs 'lore' from the PATH with the current world. An existing hook
+that lore did not write is kept unless --force is given.
+
+Examples:
+  lore hooks install -w myworld
+  lore hooks install -w myworld --hook pre-push --pattern "*.md"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if globalWorld == "" {
+				return entities.Errorf(entities.ErrValidation, "world is required (use --world flag)")
+			}
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return entities.Errorf(entities.ErrValidation, "invalid pattern %q", pattern)
+			}
+			script, err := hookScript(hook, globalWorld, pattern)
+			if err != nil {
+				return err
+			}
+
+			dir, err := git.HooksDir(cmd.Context(), ".")
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(dir, hook)
+			if err := writeHook(path, script, force); err != nil {
+				return err
+			}
+			fmt.Printf("Installed %s hook: %s\n", hook, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&hook, "hook", "pre-commit", "Hook to install: pre-commit or pre-push")
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "*.txt", "Manuscript files to check")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an existing hook")
+
+	return cmd
+}
+
+// hookScript returns the script of a hook checking the world's manuscript
+// files matching pattern.
+func hookScript(hook, world, pattern string) (string, error) {
+	check := fmt.Sprintf("lore check -w %s --pattern %s --fail-on-critical", shellQuote(world), shellQuote(pattern))
+
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + "\n")
+	switch hook {
+	case "pre-commit":
+		b.WriteString("# Stops the commit if staged manuscript changes contradict the world.\n")
+		fmt.Fprintf(&b, "exec %s --staged\n", check)
+	case "pre-push":
+		b.WriteString("# Stops the push if manuscript changes since the upstream contradict\n")
+		b.WriteString("# the world. A branch without an upstream is not checked.\n")
+		b.WriteString("upstream=$(git rev-parse --abbrev-ref --symbolic-full-name '@{upstream}' 2>/dev/null) || exit 0\n")
+		fmt.Fprintf(&b, "exec %s --git-diff \"$upstream\"\n", check)
+	default:
+		return "", entities.Errorf(entities.ErrValidation, "invalid hook %q (valid: pre-commit, pre-push)", hook)
+	}
+	return b.String(), nil
+}
+
+// writeHook writes an executable hook, replacing an existing hook only if
+// lore wrote it or force is set.
+func writeHook(path, script string, force bool) error {
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if !force && !strings.Contains(string(existing), hookMarker) {
+			return entities.Errorf(entities.ErrConflict, "%s already exists (use --force to replace it)", path)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("reading existing hook: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return fmt.Errorf("writing hook: %w", err)
+	}
+	// WriteFile keeps the mode of a file it replaces
+	return os.Chmod(path, 0o755)
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
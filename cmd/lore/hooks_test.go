@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestHookScript(t *testing.T) {
+	script, err := hookScript("pre-commit", "bob's world", "*.md")
+	require.NoError(t, err)
+	assert.Contains(t, script, "#!/bin/sh\n"+hookMarker)
+	assert.Contains(t, script, `exec lore check -w 'bob'\''s world' --pattern '*.md' --fail-on-critical --staged`)
+
+	script, err = hookScript("pre-push", "w", "*.txt")
+	require.NoError(t, err)
+	assert.Contains(t, script, `--git-diff "$upstream"`)
+
+	_, err = hookScript("post-merge", "w", "*.txt")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestWriteHook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks", "pre-commit")
+
+	require.NoError(t, writeHook(path, "#!/bin/sh\n"+hookMarker+"\n", false))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	require.NoError(t, writeHook(path, "#!/bin/sh\n"+hookMarker+"\nexit 0\n", false), "lore's own hook is replaced")
+
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nmake lint\n"), 0o600))
+	err = writeHook(path, "#!/bin/sh\n", false)
+	assert.ErrorIs(t, err, entities.ErrConflict, "someone else's hook is kept")
+	require.NoError(t, writeHook(path, "#!/bin/sh\n", true))
+	info, err = os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm(), "a replaced hook is made executable")
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func newHoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hover <entity>",
+		Short: "Print an entity's card for editor tooltips",
+		Long: `Prints a compact JSON card of an entity, for editor tooltips: its type, its
+top facts, its relationships, and its last appearance, the source of its
+most recently recorded fact. An entity the world does not have exits with
+status 6.
+
+Editors that show cards as the cursor moves should ask 'lore serve' at
+GET /api/entities/<name>/card, or run 'lore lsp', instead: both keep cards
+cached for a minute, names that are not entities included, and the
+endpoint sends an ETag and Cache-Control so clients can cache them too.
+
+Example:
+  lore hover -w myworld "Aragorn"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if globalWorld == "" {
+				return entities.Errorf(entities.ErrValidation, "world is required (use --world flag)")
+			}
+			return withInternalDeps(func(d *internalDeps) error {
+				card, err := newCardHandler(d).HandleCard(cmd.Context(), globalWorld, args[0])
+				if err != nil {
+					return err
+				}
+				return json.NewEncoder(os.Stdout).Encode(card)
+			})
+		},
+	}
+
+	return cmd
+}
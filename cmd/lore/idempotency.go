@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// idempotencyKeyUsage describes the --idempotency-key flag of commands
+// that save.
+const idempotencyKeyUsage = "Run at most once per key: a retry with the same key within 24h reports the first run's outcome instead"
+
+// idempotent runs fn at most once per idempotency key in the current world,
+// reporting whether the outcome recorded by an earlier run was returned
+// instead. An empty key always runs fn.
+func idempotent[T any](ctx context.Context, d *internalDeps, key, operation string, request any, fn func() (T, error)) (T, bool, error) {
+	return services.Idempotent(ctx, services.NewIdempotencyService(d.relationalDB, 0), key, operation, request, fn)
+}
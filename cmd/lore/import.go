@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
 )
 
 type importFlags struct {
-	format     string
-	dryRun     bool
-	onConflict string
+	format       string
+	dryRun       bool
+	onConflict   string
+	strict       bool
+	checkSimilar bool
+	idemKey      string
 }
 
 func newImportCmd() *cobra.Command {
@@ -20,21 +29,58 @@ func newImportCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "import <file>",
-		Short: "Import facts from JSON or CSV",
-		Long:  "Imports facts from a structured file. Generates embeddings automatically.",
-		Args:  cobra.ExactArgs(1),
+		Short: "Import facts from JSON, YAML, or CSV",
+		Long: `Imports facts from a structured file. Generates embeddings automatically.
+
+JSON and YAML files hold either a list of facts or a whole world bible
+with facts, entities, and relationships sections:
+
+  entities:
+    - Frodo
+    - name: The Shire
+  facts:
+    - {type: character, subject: Frodo, predicate: lives_in, object: The Shire}
+  relationships:
+    - {source: Frodo, type: located_in, target: The Shire}
+
+Relationships must name entities declared in the document or already in
+the world.
+
+Facts are checked against the world's validation rules, configured in
+worlds.yaml:
+
+  worlds:
+    middle-earth:
+      validation:
+        predicates: [lives_in, wields, member_of]
+        strict: true    # subjects must match existing entities
+
+With --check-similar, facts that closely match existing facts are listed
+and you are asked before anything is imported.
+
+With --idempotency-key, retrying an import of the same file with the same
+options and key within 24 hours reports the first run's outcome instead of
+importing again.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runImport(cmd, args[0], flags)
 		},
 	}
 
-	cmd.Flags().StringVarP(&flags.format, "format", "f", "auto", "File format (json, csv, auto)")
+	cmd.Flags().StringVarP(&flags.format, "format", "f", "auto", "File format (json, yaml, csv, auto)")
 	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Validate without saving")
-	cmd.Flags().StringVar(&flags.onConflict, "on-conflict", "overwrite", "Conflict handling: overwrite (update existing) or skip")
+	cmd.Flags().StringVar(&flags.onConflict, "on-conflict", "overwrite", "Conflict handling: overwrite (update existing), skip, or merge (combine fields, keep history)")
+	cmd.Flags().BoolVar(&flags.strict, "strict", false, "Require fact subjects to match existing entities")
+	cmd.Flags().BoolVar(&flags.checkSimilar, "check-similar", false, "List facts similar to existing ones and ask before importing")
+	cmd.Flags().StringVar(&flags.idemKey, "idempotency-key", "", idempotencyKeyUsage)
 
 	return cmd
 }
 
+// errImportCancelled is returned when the user declines to import facts
+// similar to existing ones, so the import isn't recorded as done.
+var errImportCancelled = errors.New("import cancelled")
+
 func runImport(cmd *cobra.Command, filePath string, flags importFlags) error {
 	// Parse and validate on-conflict flag
 	strategy, err := parseConflictStrategy(flags.onConflict)
@@ -42,50 +88,157 @@ func runImport(cmd *cobra.Command, filePath string, flags importFlags) error {
 		return err
 	}
 
+	if flags.idemKey != "" && flags.dryRun {
+		return entities.Errorf(entities.ErrValidation, "--idempotency-key has no effect with --dry-run, which saves nothing")
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	request := map[string]any{"path": absPath, "format": flags.format, "on_conflict": flags.onConflict, "strict": flags.strict}
+
 	ctx := cmd.Context()
 
-	return withImportHandler(func(handler *handlers.ImportHandler) error {
+	return withImportHandler(flags.strict, func(d *internalDeps, handler *handlers.ImportHandler, rules []services.ImportRule) error {
 		opts := handlers.ImportOptions{
+			WorldID:    globalWorld,
 			Format:     flags.format,
 			DryRun:     flags.dryRun,
 			OnConflict: strategy,
+			Rules:      rules,
 		}
 
-		fmt.Printf("Importing %s...\n", filePath)
-
-		result, err := handler.Handle(ctx, filePath, opts)
+		result, replayed, err := idempotent(ctx, d, flags.idemKey, "import", request, func() (*handlers.ImportResult, error) {
+			return importFile(ctx, handler, filePath, opts, flags.checkSimilar)
+		})
+		if errors.Is(err, errImportCancelled) {
+			return nil
+		}
 		if err != nil {
-			return fmt.Errorf("importing file: %w", err)
+			return err
 		}
-
-		// Display errors
-		if len(result.Errors) > 0 {
-			fmt.Printf("\nValidation errors (%d):\n", len(result.Errors))
-			for _, e := range result.Errors {
-				fmt.Printf("  %s\n", e.Error())
-			}
+		if replayed {
+			fmt.Printf("Already imported %s with idempotency key %q; nothing imported again\n", filePath, flags.idemKey)
 		}
+		displayImportResult(result, flags.dryRun)
+		return nil
+	})
+}
 
-		// Display summary
-		fmt.Println()
-		if flags.dryRun {
-			fmt.Printf("Dry run: %d facts would be imported", result.Imported)
-		} else {
-			fmt.Printf("Imported: %d facts", result.Imported)
+// importFile imports a file with opts. If checkSimilar, a dry run lists
+// the facts similar to existing ones, and an import first asks whether to
+// go ahead if there are any.
+func importFile(ctx context.Context, handler *handlers.ImportHandler, filePath string, opts handlers.ImportOptions, checkSimilar bool) (*handlers.ImportResult, error) {
+	if checkSimilar {
+		if opts.DryRun {
+			opts.CheckSimilar = true
+		} else if proceed, err := confirmSimilarImport(ctx, handler, filePath, opts); err != nil {
+			return nil, err
+		} else if !proceed {
+			return nil, errImportCancelled
 		}
+	}
 
-		if result.Skipped > 0 {
-			fmt.Printf(", %d skipped (already exist)", result.Skipped)
-		}
+	fmt.Printf("Importing %s...\n", filePath)
+
+	result, err := handler.Handle(ctx, filePath, opts)
+	if err != nil {
+		return nil, fmt.Errorf("importing file: %w", err)
+	}
+	return result, nil
+}
 
-		if len(result.Errors) > 0 {
-			fmt.Printf(", %d errors", len(result.Errors))
+// displayImportResult prints the errors, similar facts and totals of an
+// import, as what would be imported if dryRun.
+func displayImportResult(result *handlers.ImportResult, dryRun bool) {
+	// Display errors
+	if len(result.Errors) > 0 {
+		fmt.Printf("\nValidation errors (%d):\n", len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("  %s\n", e.Error())
 		}
+	}
 
+	if len(result.Similar) > 0 {
 		fmt.Println()
+		printSimilarImports(result.Similar)
+	}
 
-		return nil
-	})
+	// Display summary
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("Dry run: %d facts would be imported", result.Imported)
+	} else {
+		fmt.Printf("Imported: %d facts", result.Imported)
+	}
+
+	if result.Entities > 0 {
+		fmt.Printf(", %d entities", result.Entities)
+	}
+
+	if result.Relationships > 0 {
+		fmt.Printf(", %d relationships", result.Relationships)
+	}
+
+	if result.Merged > 0 {
+		fmt.Printf(", %d merged into existing facts", result.Merged)
+	}
+
+	if result.Skipped > 0 {
+		fmt.Printf(", %d skipped (already exist)", result.Skipped)
+	}
+
+	if len(result.Errors) > 0 {
+		fmt.Printf(", %d errors", len(result.Errors))
+	}
+
+	fmt.Println()
+}
+
+// confirmSimilarImport checks a file for facts similar to existing ones
+// with a dry run and, if there are any, asks whether to import anyway.
+func confirmSimilarImport(ctx context.Context, handler *handlers.ImportHandler, filePath string, opts handlers.ImportOptions) (bool, error) {
+	opts.DryRun = true
+	opts.CheckSimilar = true
+
+	fmt.Printf("Checking %s for similar facts...\n", filePath)
+	result, err := handler.Handle(ctx, filePath, opts)
+	if err != nil {
+		return false, fmt.Errorf("checking file: %w", err)
+	}
+	if len(result.Similar) == 0 {
+		return true, nil
+	}
+
+	fmt.Println()
+	printSimilarImports(result.Similar)
+	if !confirmAction("\nImport anyway?") {
+		fmt.Println("Import cancelled.")
+		return false, nil
+	}
+	return true, nil
+}
+
+// printSimilarImports shows imported facts that closely match existing ones.
+func printSimilarImports(similar []services.SimilarImport) {
+	fmt.Printf("Similar facts (%d):\n", len(similar))
+	for i := range similar {
+		fact := &similar[i].Fact
+		if similar[i].Line > 0 {
+			fmt.Printf("  line %d: ", similar[i].Line)
+		} else {
+			fmt.Print("  ")
+		}
+		fmt.Printf("%s %s %s\n", fact.Subject, fact.Predicate, fact.Object)
+		for j := range similar[i].Matches {
+			match := &similar[i].Matches[j]
+			fmt.Printf("    similar to %s %s %s", match.Fact.Subject, match.Fact.Predicate, match.Fact.Object)
+			if match.Fact.SourceFile != "" {
+				fmt.Printf(" from %s", match.Fact.SourceFile)
+			}
+			fmt.Printf(" (%.0f%%)\n", match.Similarity*100)
+		}
+	}
 }
 
 // parseConflictStrategy converts a string to ConflictStrategy.
@@ -95,16 +248,37 @@ func parseConflictStrategy(s string) (services.ConflictStrategy, error) {
 		return services.ConflictSkip, nil
 	case string(services.ConflictOverwrite):
 		return services.ConflictOverwrite, nil
+	case string(services.ConflictMerge):
+		return services.ConflictMerge, nil
 	default:
-		return "", fmt.Errorf("invalid --on-conflict value %q (valid: skip, overwrite)", s)
+		return "", entities.Errorf(entities.ErrValidation, "invalid --on-conflict value %q (valid: skip, overwrite, merge)", s)
 	}
 }
 
-// withImportHandler creates an ImportHandler and calls the provided function.
-func withImportHandler(fn func(*handlers.ImportHandler) error) error {
+// withImportHandler creates an ImportHandler and the current world's
+// validation rules, then calls the provided function. The strict flag
+// enables subject checks even when the world config does not.
+func withImportHandler(strict bool, fn func(*internalDeps, *handlers.ImportHandler, []services.ImportRule) error) error {
 	return withInternalDeps(func(d *internalDeps) error {
-		importService := services.NewImportService(d.embedder, d.repo, d.entityTypeService)
+		world, err := d.Worlds.Get(globalWorld)
+		if err != nil {
+			return err
+		}
+
+		importService := services.NewImportService(d.embedder, d.vectorDB, d.relationalDB, d.entityTypeService)
 		handler := handlers.NewImportHandler(importService)
-		return fn(handler)
+		return fn(d, handler, importRules(world.Validation, strict, d.relationalDB))
 	})
 }
+
+// importRules builds the validation rules for a world's import config.
+func importRules(cfg config.ValidationConfig, strict bool, db ports.RelationalDB) []services.ImportRule {
+	var rules []services.ImportRule
+	if len(cfg.Predicates) > 0 {
+		rules = append(rules, services.NewPredicateRule(cfg.Predicates))
+	}
+	if strict || cfg.Strict {
+		rules = append(rules, services.NewKnownSubjectRule(db, globalWorld))
+	}
+	return rules
+}
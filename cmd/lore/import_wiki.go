@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/wiki"
+)
+
+type importWikiFlags struct {
+	pages       []string
+	category    string
+	limit       int
+	infoboxOnly bool
+	dryRun      bool
+	check       bool
+	onConflict  string
+}
+
+// wikiTotals sums the results of importing wiki pages.
+type wikiTotals struct {
+	pages     int
+	imported  int
+	extracted int
+	errors    int
+	issues    int
+}
+
+func newImportWikiCmd() *cobra.Command {
+	var flags importWikiFlags
+
+	cmd := &cobra.Command{
+		Use:   "import-wiki <api-url|export>",
+		Short: "Import pages from a MediaWiki or a World Anvil export",
+		Long: `Imports the pages of an existing wiki. Each page's title becomes an entity,
+the fields of its infobox become facts about it directly, and facts are
+extracted from its text as 'lore ingest' would. Facts from both are
+recorded with the page's URL as their source.
+
+An http or https URL is a MediaWiki's API endpoint, such as
+https://lotr.fandom.com/api.php. Every article is read unless --page or
+--category selects some.
+
+Anything else is a World Anvil export: the .zip, the directory it was
+unzipped into, or a single article's .json file. An article's short
+fields, such as a person's species, are its infobox.
+
+Infobox fields become facts of a type guessed from the infobox or article
+template: locations for settlements and countries, events for battles,
+and characters otherwise. Long fields are extracted with the text.
+
+--dry-run validates the infobox facts without saving anything, and does
+not extract from the text.
+
+Examples:
+  lore import-wiki https://lotr.fandom.com/api.php --category Hobbits -w myworld
+  lore import-wiki https://lotr.fandom.com/api.php --page "Frodo Baggins" --page "Bag End"
+  lore import-wiki world-anvil-export.zip --infobox-only -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runImportWiki(cmd.Context(), args[0], flags)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&flags.pages, "page", nil, "MediaWiki page to import (repeatable)")
+	cmd.Flags().StringVar(&flags.category, "category", "", "Import the MediaWiki articles in this category")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", 0, "Maximum pages to import (0 = all)")
+	cmd.Flags().BoolVar(&flags.infoboxOnly, "infobox-only", false, "Import infobox fields without extracting from the text (no LLM calls)")
+	cmd.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Validate infobox facts without saving")
+	cmd.Flags().BoolVarP(&flags.check, "check", "c", false, "Check extracted facts for consistency with existing facts")
+	cmd.Flags().StringVar(&flags.onConflict, "on-conflict", "overwrite", "Existing infobox facts: overwrite, skip, or merge")
+
+	return cmd
+}
+
+func runImportWiki(ctx context.Context, source string, flags importWikiFlags) error {
+	strategy, err := parseConflictStrategy(flags.onConflict)
+	if err != nil {
+		return err
+	}
+	if flags.limit < 0 {
+		return entities.Errorf(entities.ErrValidation, "--limit must not be negative")
+	}
+
+	isAPI := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	if !isAPI && (len(flags.pages) > 0 || flags.category != "") {
+		return entities.Errorf(entities.ErrValidation, "--page and --category select MediaWiki pages; %s is not a URL", source)
+	}
+	if len(flags.pages) > 0 && flags.category != "" {
+		return entities.Errorf(entities.ErrValidation, "--page and --category cannot be combined")
+	}
+
+	return withInternalDeps(func(d *internalDeps) error {
+		opts, err := wikiOptions(d, flags, strategy)
+		if err != nil {
+			return err
+		}
+		importService := services.NewImportService(d.embedder, d.vectorDB, d.relationalDB, d.entityTypeService)
+		handler := handlers.NewWikiHandler(importService, d.IngestHandler)
+
+		var totals wikiTotals
+		fmt.Printf("Importing %s...\n", source)
+		err = readWikiPages(ctx, source, isAPI, flags, func(page wiki.Page) error {
+			result, err := handler.HandlePage(ctx, page, &opts)
+			if err != nil {
+				return err
+			}
+			displayWikiPage(os.Stdout, result)
+			totals.add(result)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		totals.print(os.Stdout, flags.dryRun)
+		return nil
+	})
+}
+
+// wikiOptions returns the options pages are imported with.
+func wikiOptions(d *internalDeps, flags importWikiFlags, strategy services.ConflictStrategy) (handlers.WikiOptions, error) {
+	world, err := d.Worlds.Get(globalWorld)
+	if err != nil {
+		return handlers.WikiOptions{}, err
+	}
+	sources, err := sourceRules(d)
+	if err != nil {
+		return handlers.WikiOptions{}, err
+	}
+	return handlers.WikiOptions{
+		WorldID:     globalWorld,
+		DryRun:      flags.dryRun,
+		InfoboxOnly: flags.infoboxOnly,
+		OnConflict:  strategy,
+		Rules:       importRules(world.Validation, false, d.relationalDB),
+		Ingest: handlers.IngestOptions{
+			CheckConsistency: flags.check,
+			WorldID:          globalWorld,
+			ReviewThreshold:  d.Config.Review.Threshold,
+			Retrieval:        retrieval(d),
+			Sources:          sources,
+			ChooseEntity:     keepSubject,
+			MaxFileSize:      d.Config.Ingest.MaxFileSize,
+		},
+	}, nil
+}
+
+// readWikiPages calls fn with each page of source selected by flags: the
+// pages of a MediaWiki if isAPI, or else of a World Anvil export.
+func readWikiPages(ctx context.Context, source string, isAPI bool, flags importWikiFlags, fn func(wiki.Page) error) error {
+	if isAPI {
+		mw, err := wiki.NewMediaWiki(source)
+		if err != nil {
+			return err
+		}
+		return mw.Pages(ctx, wiki.MediaWikiOptions{Titles: flags.pages, Category: flags.category, Limit: flags.limit}, fn)
+	}
+
+	read := 0
+	err := wiki.ReadWorldAnvil(source, func(page wiki.Page) error {
+		if flags.limit > 0 && read >= flags.limit {
+			return errStopWiki
+		}
+		read++
+		return fn(page)
+	})
+	if err != nil && !errors.Is(err, errStopWiki) {
+		return err
+	}
+	return nil
+}
+
+// errStopWiki stops reading an export once --limit pages are imported.
+var errStopWiki = errors.New("page limit reached")
+
+// add adds a page's result to the totals.
+func (t *wikiTotals) add(result *handlers.WikiPageResult) {
+	t.pages++
+	t.imported += result.Imported
+	t.extracted += result.Extracted
+	t.errors += len(result.Errors)
+	t.issues += len(result.Issues)
+}
+
+// print prints the totals, as what would be imported if dryRun.
+func (t *wikiTotals) print(w io.Writer, dryRun bool) {
+	verb := "Imported"
+	if dryRun {
+		verb = "Dry run: would import"
+	}
+	fmt.Fprintf(w, "%s %d pages: %d infobox facts, %d extracted facts", verb, t.pages, t.imported, t.extracted)
+	if t.errors > 0 {
+		fmt.Fprintf(w, ", %d errors", t.errors)
+	}
+	if t.issues > 0 {
+		fmt.Fprintf(w, ", %d consistency issues", t.issues)
+	}
+	fmt.Fprintln(w)
+}
+
+// displayWikiPage prints what was imported from a page, with its errors
+// and consistency issues.
+func displayWikiPage(w io.Writer, result *handlers.WikiPageResult) {
+	fmt.Fprintf(w, "  %s: %d infobox facts, %d extracted (%s)\n", result.Title, result.Imported, result.Extracted, result.Source)
+	for _, e := range result.Errors {
+		fmt.Fprintf(w, "    error: %s\n", e.Error())
+	}
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		fmt.Fprintf(w, "    %s: %s\n", formatSeverity(issue.Severity), issue.Description)
+	}
+}
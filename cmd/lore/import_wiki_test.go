@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestDisplayWikiPage(t *testing.T) {
+	var out bytes.Buffer
+	displayWikiPage(&out, &handlers.WikiPageResult{
+		Title:     "Frodo",
+		Source:    "https://wiki.example/Frodo",
+		Imported:  2,
+		Extracted: 5,
+		Errors:    []services.ImportError{{Line: 1, Field: "predicate", Value: "race", Message: "not allowed"}},
+		Issues:    []ports.ConsistencyIssue{{Severity: "major", Description: "two birthplaces"}},
+	})
+	assert.Contains(t, out.String(), "  Frodo: 2 infobox facts, 5 extracted (https://wiki.example/Frodo)\n")
+	assert.Contains(t, out.String(), "    error: ")
+	assert.Contains(t, out.String(), "two birthplaces")
+}
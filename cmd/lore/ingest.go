@@ -3,70 +3,319 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/documents"
+	"github.com/ersonp/lore-core/internal/infrastructure/git"
 )
 
 type ingestFlags struct {
-	recursive bool
-	pattern   string
-	check     bool
-	checkOnly bool
+	recursive   bool
+	pattern     string
+	check       bool
+	checkOnly   bool
+	assumeFirst bool
+	pronouns    bool
+	dialogue    bool
+	narrator    string
+	carry       bool
+	focus       []string
+	chunker     string
+	reviewBelow float64
+	gitDiff     string
+	idemKey     string
+	archived    bool
+	maxSize     string
 }
 
+// ingestOutcome is what a saving ingest recorded, reported again when it
+// is retried with the same idempotency key.
+type ingestOutcome struct {
+	Files int `json:"files"`
+	Facts int `json:"facts"`
+}
+
+// ingestLong is the help of 'lore ingest'.
+const ingestLong = `Reads text files, extracts facts using LLM, generates embeddings, and stores them in Qdrant.
+
+When a subject matches several entities (e.g. "John" with both "John the Baker"
+and "King John"), the entity is picked from the fact's context and the
+entities' relationships. Remaining ties are asked interactively, or left as
+written when input is not a terminal; use --assume-first to take the
+best-ranked entity without asking.
+
+Use --resolve-pronouns for pronoun-heavy prose: facts about "he" or "her
+sister" are rewritten to the names they refer to in the surrounding text.
+This costs an extra LLM call for each chunk that yields such facts.
+
+Use --carry-context when facts span chunk boundaries: each chunk is extracted
+with a running summary of the text before it, so characters introduced earlier
+in the file are recognized. This costs one extra LLM call per chunk.
+
+Use --focus to add specialized passes for the kinds of facts you care most
+about; each adds one LLM call per chunk. Focus areas: events, relationships,
+rules, characters, locations.
+
+Use --dialogue for screenplays and scripts: lines after a character cue
+(a name in capitals on its own line) or a speaker tag ("Gimli: ...") are
+extracted as asserted by that character, and stored as their claims rather
+than canon unless the character is listed under claims.reliable in the
+config. Use --narrator for an unreliable narrator: the narration becomes
+their claims. Claims never contradict canon; they are only checked against
+earlier claims of the same character. This costs an extra LLM call per
+speaker in each chunk.
+
+Use --chunker to split text to suit its format: paragraph (the default)
+packs whole paragraphs; sentence-window packs whole sentences, for text
+with few paragraph breaks; markdown-heading starts a chunk at each Markdown
+heading, for wikis and world bibles; fixed-token cuts chunks of a fixed
+number of words regardless of structure, for screenplays.
+
+Facts that spell a term differently from the style sheet are reported with
+the canonical spelling; see 'lore style'.
+
+A chunk whose facts cannot be extracted, because the LLM call fails or its
+reply cannot be parsed, is quarantined and the rest of the file is still
+ingested; retry it later with 'lore retry-failed'.
+
+Facts with confidence below review.threshold in the config, or --review-below,
+are held for review: they are saved but not searchable until accepted with
+'lore review'.
+
+Use --git-diff in a pre-commit hook or CI to ingest only the files that
+changed in the git repository since a revision, staged or not. Each is
+ingested again in place of its earlier version: the facts extracted from
+it before are deleted first. The facts of deleted files are deleted, with
+the entities and relationships only they named, and a renamed file's facts
+move to its new name. Untracked files are not ingested until added.
+
+PDF and DOCX files are ingested as their text (e.g. -p "*.pdf"), and
+facts from a PDF record the page they came from, as do those from a DOCX
+that records its page breaks. Scanned PDFs without a text layer yield no
+facts, and encrypted ones are refused.
+
+Text in UTF-8, UTF-16, or Windows-1252 is read as such; archives, images,
+and other binary files are refused, as are files larger than
+ingest.max_file_size in the config (default 64MB), or --max-file-size. A
+line, paragraph, or Markdown section longer than ingest.max_chunk_size
+(default 1MB) stops the ingest of its file.
+
+Archived sources (see 'lore sources archive') are refused, skipped when
+ingesting a directory, and left alone by --git-diff; use --include-archived
+to ingest them anyway. Their facts stay labeled as archived.
+
+Use --idempotency-key when a script or CI job may retry the command: an
+ingest with the same path, options, and key within 24 hours reports the
+first run's outcome instead of ingesting the files again.
+
+Examples:
+  lore ingest chapter1.txt -w myworld
+  lore ingest manuscript/ -r -w myworld --git-diff HEAD~1 --check
+  lore ingest books/ -w myworld --focus events,rules
+  lore ingest bible.md -w myworld --chunker markdown-heading
+  lore ingest script.txt -w myworld --dialogue --chunker fixed-token
+  lore ingest notes.txt -w myworld --review-below 0.8
+  lore ingest books/ -w myworld --carry-context --resolve-pronouns --assume-first
+  lore ingest chapter1.txt -w myworld --idempotency-key "$CI_JOB_ID"`
+
 func newIngestCmd() *cobra.Command {
 	var flags ingestFlags
 
 	cmd := &cobra.Command{
 		Use:   "ingest <path>",
 		Short: "Extract facts from a file or directory",
-		Long:  "Reads text files, extracts facts using LLM, generates embeddings, and stores them in Qdrant.",
+		Long:  ingestLong,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runIngest(cmd, args[0], flags)
+			return runIngest(cmd, args[0], &flags)
 		},
 	}
 
+	addIngestFlags(cmd, &flags)
+
+	return cmd
+}
+
+func addIngestFlags(cmd *cobra.Command, flags *ingestFlags) {
 	cmd.Flags().BoolVarP(&flags.recursive, "recursive", "r", false, "Process subdirectories recursively")
 	cmd.Flags().StringVarP(&flags.pattern, "pattern", "p", "*.txt", "File pattern to match (default: *.txt)")
 	cmd.Flags().BoolVarP(&flags.check, "check", "c", false, "Check for consistency with existing facts")
 	cmd.Flags().BoolVar(&flags.checkOnly, "check-only", false, "Check consistency without saving (dry run)")
-
-	return cmd
+	cmd.Flags().BoolVar(&flags.pronouns, "resolve-pronouns", false, "Resolve pronoun subjects and objects to names (extra LLM calls)")
+	cmd.Flags().StringSliceVar(&flags.focus, "focus", nil, "Extra extraction passes: events, relationships, rules, characters, locations")
+	cmd.Flags().BoolVar(&flags.dialogue, "dialogue", false, "Extract speakers' lines in screenplays and scripts as their claims (extra LLM calls)")
+	cmd.Flags().StringVar(&flags.narrator, "narrator", "", "Store the narration as claims of this unreliable narrator")
+	cmd.Flags().StringVar(&flags.chunker, "chunker", string(ports.ChunkParagraph), "How to split text: paragraph, sentence-window, markdown-heading, fixed-token")
+	cmd.Flags().BoolVar(&flags.carry, "carry-context", false, "Pass a running summary of earlier chunks to each chunk (extra LLM calls)")
+	cmd.Flags().Float64Var(&flags.reviewBelow, "review-below", 0, "Hold facts below this confidence for review (default: review.threshold)")
+	cmd.Flags().BoolVar(&flags.assumeFirst, "assume-first", false, "Resolve ambiguous subjects to the best-ranked entity without prompting")
+	cmd.Flags().StringVar(&flags.gitDiff, "git-diff", "", "Only ingest files changed since this git revision, replacing their facts")
+	cmd.Flags().StringVar(&flags.gitDiff, "since-commit", "", "Same as --git-diff")
+	cmd.Flags().StringVar(&flags.idemKey, "idempotency-key", "", idempotencyKeyUsage)
+	cmd.Flags().BoolVar(&flags.archived, "include-archived", false, "Ingest archived sources too")
+	cmd.Flags().StringVar(&flags.maxSize, "max-file-size", "", "Largest file to ingest, such as 256MB (default: ingest.max_file_size)")
 }
 
-func runIngest(cmd *cobra.Command, path string, flags ingestFlags) error {
+func runIngest(cmd *cobra.Command, path string, flags *ingestFlags) error {
 	ctx := cmd.Context()
 
-	return withDeps(func(d *Deps) error {
-		opts := handlers.IngestOptions{
-			CheckConsistency: flags.check || flags.checkOnly,
-			CheckOnly:        flags.checkOnly,
+	focus, err := parseFocus(flags.focus)
+	if err != nil {
+		return err
+	}
+	var maxSize entities.ByteSize
+	if flags.maxSize != "" {
+		if maxSize, err = entities.ParseByteSize(flags.maxSize); err != nil {
+			return err
 		}
+	}
+	if flags.reviewBelow < 0 || flags.reviewBelow > 1 {
+		return entities.Errorf(entities.ErrValidation, "review-below must be between 0 and 1, got %v", flags.reviewBelow)
+	}
+	if flags.idemKey != "" && flags.checkOnly {
+		return entities.Errorf(entities.ErrValidation, "--idempotency-key has no effect with --check-only, which saves nothing")
+	}
 
-		if handlers.IsDirectory(path) {
-			return runIngestDirectory(ctx, d.IngestHandler, path, flags.pattern, flags.recursive, opts)
+	return withInternalDeps(func(d *internalDeps) error {
+		opts, err := ingestOptions(cmd, d, flags, focus, maxSize)
+		if err != nil {
+			return err
 		}
 
-		return runIngestFile(ctx, d.IngestHandler, path, opts)
+		request, err := ingestRequest(path, flags)
+		if err != nil {
+			return err
+		}
+		outcome, replayed, err := idempotent(ctx, d, flags.idemKey, "ingest", request, func() (ingestOutcome, error) {
+			if flags.gitDiff != "" {
+				return runIngestChanges(ctx, d, path, flags, &opts)
+			}
+			if handlers.IsDirectory(path) {
+				return runIngestDirectory(ctx, d.IngestHandler, path, flags.pattern, flags.recursive, &opts)
+			}
+			return runIngestFile(ctx, d.IngestHandler, path, &opts)
+		})
+		if err != nil {
+			return err
+		}
+		if replayed {
+			fmt.Printf("Already ingested with idempotency key %q: %d facts from %d files saved; nothing ingested again\n",
+				flags.idemKey, outcome.Facts, outcome.Files)
+		}
+		return nil
 	})
 }
 
-func runIngestFile(ctx context.Context, handler *handlers.IngestHandler, filePath string, opts handlers.IngestOptions) error {
+// ingestOptions returns the options files are ingested with: those of
+// flags, and those of the config flags leave unset.
+func ingestOptions(cmd *cobra.Command, d *internalDeps, flags *ingestFlags, focus []ports.ExtractionFocus, maxSize entities.ByteSize) (handlers.IngestOptions, error) {
+	sources, err := sourceRules(d)
+	if err != nil {
+		return handlers.IngestOptions{}, err
+	}
+	chunker, err := services.NewChunkerWithLimit(ports.ChunkStrategy(flags.chunker), d.Config.Ingest.MaxChunkSize)
+	if err != nil {
+		return handlers.IngestOptions{}, err
+	}
+	if maxSize == 0 {
+		maxSize = d.Config.Ingest.MaxFileSize
+	}
+	opts := handlers.IngestOptions{
+		CheckConsistency: flags.check || flags.checkOnly,
+		CheckOnly:        flags.checkOnly,
+		ResolvePronouns:  flags.pronouns,
+		CarryContext:     flags.carry,
+		Focus:            focus,
+		Dialogue:         flags.dialogue,
+		Narrator:         flags.narrator,
+		Reliable:         d.Config.Claims.Reliable,
+		Chunker:          chunker,
+		MaxFileSize:      maxSize,
+		Documents:        documents.NewReader(),
+		WorldID:          globalWorld,
+		ReviewThreshold:  d.Config.Review.Threshold,
+		Retrieval:        retrieval(d),
+		Sources:          sources,
+		IncludeArchived:  flags.archived,
+	}
+	if cmd.Flags().Changed("review-below") {
+		opts.ReviewThreshold = flags.reviewBelow
+	}
+	if !flags.assumeFirst {
+		opts.ChooseEntity = keepSubject
+		if stdinIsTerminal() {
+			opts.ChooseEntity = newEntityPrompt(os.Stdin, os.Stdout)
+		}
+	}
+	return opts, nil
+}
+
+// ingestRequest identifies an ingest for its idempotency key: the same key
+// may only be retried for the same path and options.
+func ingestRequest(path string, flags *ingestFlags) (map[string]any, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+	return map[string]any{
+		"path":      abs,
+		"pattern":   flags.pattern,
+		"recursive": flags.recursive,
+		"check":     flags.check,
+		"focus":     flags.focus,
+		"dialogue":  flags.dialogue,
+		"narrator":  flags.narrator,
+		"chunker":   flags.chunker,
+		"git_diff":  flags.gitDiff,
+		"archived":  flags.archived,
+	}, nil
+}
+
+// parseFocus validates --focus values, dropping duplicates.
+func parseFocus(values []string) ([]ports.ExtractionFocus, error) {
+	var focus []ports.ExtractionFocus
+	for _, v := range values {
+		f := ports.ExtractionFocus(strings.ToLower(strings.TrimSpace(v)))
+		if !f.IsValid() {
+			return nil, entities.Errorf(entities.ErrValidation, "invalid focus %q (valid: events, relationships, rules, characters, locations)", v)
+		}
+		if !slices.Contains(focus, f) {
+			focus = append(focus, f)
+		}
+	}
+	return focus, nil
+}
+
+func runIngestFile(ctx context.Context, handler *handlers.IngestHandler, filePath string, opts *handlers.IngestOptions) (ingestOutcome, error) {
 	fmt.Printf("Ingesting %s...\n", filePath)
 
 	result, err := handler.HandleWithOptions(ctx, filePath, opts)
 	if err != nil {
-		return fmt.Errorf("ingesting file: %w", err)
+		return ingestOutcome{}, fmt.Errorf("ingesting file: %w", err)
 	}
 
 	fmt.Printf("Found %d facts\n", result.FactsCount)
+	displayDisambiguations(result.Disambiguations)
 
 	for i := range result.Facts {
-		fmt.Printf("  %d. [%s] %s %s %s\n", i+1, result.Facts[i].Type, result.Facts[i].Subject, result.Facts[i].Predicate, result.Facts[i].Object)
+		asserted, pending := "", ""
+		if result.Facts[i].AssertedBy != "" {
+			asserted = fmt.Sprintf(" (asserted by %s)", describeAssertion(&result.Facts[i]))
+		}
+		if result.Facts[i].IsPending() {
+			pending = " (pending review)"
+		}
+		fmt.Printf("  %d. [%s] %s %s %s%s%s\n", i+1, result.Facts[i].Type, result.Facts[i].Subject, result.Facts[i].Predicate, result.Facts[i].Object, asserted, pending)
 	}
 
 	// Display consistency issues if any
@@ -74,18 +323,25 @@ func runIngestFile(ctx context.Context, handler *handlers.IngestHandler, filePat
 		fmt.Println()
 		displayConsistencyIssues(result.Issues)
 	}
+	if len(result.StyleIssues) > 0 {
+		fmt.Println()
+		displayStyleIssues(result.StyleIssues)
+	}
 
 	// Show save status
 	if opts.CheckOnly {
 		fmt.Printf("\nDry run - no facts saved (use --check to save with warnings)\n")
 	} else {
 		fmt.Printf("\nSaved %d facts to database\n", result.FactsCount)
+		displayConflictsRecorded(len(result.Issues))
 	}
+	displayPendingReview(result.PendingCount, opts.CheckOnly)
+	displayQuarantined(result.Quarantined)
 
-	return nil
+	return ingestOutcome{Files: 1, Facts: result.FactsCount}, nil
 }
 
-func runIngestDirectory(ctx context.Context, handler *handlers.IngestHandler, dirPath string, pattern string, recursive bool, opts handlers.IngestOptions) error {
+func runIngestDirectory(ctx context.Context, handler *handlers.IngestHandler, dirPath string, pattern string, recursive bool, opts *handlers.IngestOptions) (ingestOutcome, error) {
 	fmt.Printf("Ingesting directory %s (pattern: %s, recursive: %v)...\n", dirPath, pattern, recursive)
 
 	progressFn := func(file string) {
@@ -94,26 +350,92 @@ func runIngestDirectory(ctx context.Context, handler *handlers.IngestHandler, di
 
 	result, err := handler.HandleDirectoryWithOptions(ctx, dirPath, pattern, recursive, progressFn, opts)
 	if err != nil {
-		return fmt.Errorf("ingesting directory: %w", err)
+		return ingestOutcome{}, fmt.Errorf("ingesting directory: %w", err)
+	}
+
+	displayBatchResult(result, opts)
+	return ingestOutcome{Files: result.TotalFiles, Facts: result.TotalFacts}, nil
+}
+
+// runIngestChanges ingests the files under path that changed since the
+// --git-diff revision, replacing their facts.
+func runIngestChanges(ctx context.Context, d *internalDeps, path string, flags *ingestFlags, opts *handlers.IngestOptions) (ingestOutcome, error) {
+	match, err := handlers.MatchFiles(path, flags.pattern, flags.recursive)
+	if err != nil {
+		return ingestOutcome{}, err
+	}
+	repoDir := path
+	if !handlers.IsDirectory(path) {
+		repoDir = filepath.Dir(path)
 	}
+	changes, err := git.ChangedFiles(ctx, repoDir, flags.gitDiff)
+	if err != nil {
+		return ingestOutcome{}, err
+	}
+
+	fmt.Printf("Ingesting files in %s changed since %s...\n", path, flags.gitDiff)
+	handler := handlers.NewChangesHandler(d.IngestHandler, services.NewDeletionService(d.vectorDB, d.relationalDB))
+	result, err := handler.HandleChanges(ctx, changes, &handlers.ChangesOptions{
+		Match: match,
+		Progress: func(file string) {
+			fmt.Printf("  Processing: %s\n", file)
+		},
+		Ingest: *opts,
+	})
+	if err != nil {
+		return ingestOutcome{}, fmt.Errorf("ingesting changes: %w", err)
+	}
+
+	for _, file := range result.Removed {
+		if opts.CheckOnly {
+			fmt.Printf("  Removed: %s (facts not deleted)\n", file)
+		} else {
+			fmt.Printf("  Removed: %s (facts deleted)\n", file)
+		}
+	}
+	if result.TotalFiles == 0 && len(result.Removed) == 0 && len(result.Archived) == 0 && len(result.Errors) == 0 {
+		fmt.Println("No matching files changed.")
+		return ingestOutcome{}, nil
+	}
+	displayBatchResult(&result.IngestBatchResult, opts)
+	return ingestOutcome{Files: result.TotalFiles, Facts: result.TotalFacts}, nil
+}
 
-	// Collect all issues from all files
+// displayBatchResult prints the issues, summary, and errors of ingesting
+// several files.
+func displayBatchResult(result *handlers.IngestBatchResult, opts *handlers.IngestOptions) {
+	// Collect all issues and resolved subjects from all files
 	var allIssues []ports.ConsistencyIssue
+	var allStyleIssues []entities.StyleIssue
+	var allResolved []services.Disambiguation
 	for _, fileResult := range result.FileResults {
 		allIssues = append(allIssues, fileResult.Issues...)
+		allStyleIssues = append(allStyleIssues, fileResult.StyleIssues...)
+		allResolved = append(allResolved, fileResult.Disambiguations...)
 	}
+	displayDisambiguations(allResolved)
 
 	// Display consistency issues if any
 	if len(allIssues) > 0 {
 		fmt.Println()
 		displayConsistencyIssues(allIssues)
 	}
+	if len(allStyleIssues) > 0 {
+		fmt.Println()
+		displayStyleIssues(allStyleIssues)
+	}
 
 	// Show summary
 	if opts.CheckOnly {
 		fmt.Printf("\nDry run: %d files, %d facts found (not saved)\n", result.TotalFiles, result.TotalFacts)
 	} else {
 		fmt.Printf("\nCompleted: %d files, %d facts saved\n", result.TotalFiles, result.TotalFacts)
+		displayConflictsRecorded(len(allIssues))
+	}
+	displayPendingReview(result.TotalPending, opts.CheckOnly)
+	displayQuarantined(result.TotalQuarantined)
+	if len(result.Archived) > 0 {
+		fmt.Printf("Skipped %d archived source(s) (use --include-archived to ingest them)\n", len(result.Archived))
 	}
 
 	if len(result.Errors) > 0 {
@@ -122,8 +444,59 @@ func runIngestDirectory(ctx context.Context, handler *handlers.IngestHandler, di
 			fmt.Printf("  - %v\n", e)
 		}
 	}
+}
+
+func displayPendingReview(count int, checkOnly bool) {
+	if count == 0 || checkOnly {
+		return
+	}
+	fmt.Printf("%d fact(s) held for review (see 'lore review')\n", count)
+}
+
+// describeAssertion returns who asserts a fact, and whether it is only
+// their claim.
+func describeAssertion(fact *entities.Fact) string {
+	if fact.IsClaim() {
+		return fact.AssertedBy + ", as a claim"
+	}
+	return fact.AssertedBy
+}
+
+// describeMetadata returns a fact's metadata as key=value pairs sorted by
+// key.
+func describeMetadata(fact *entities.Fact) string {
+	pairs := make([]string, 0, len(fact.Metadata))
+	for _, key := range slices.Sorted(maps.Keys(fact.Metadata)) {
+		pairs = append(pairs, key+"="+fact.Metadata[key])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+func displayQuarantined(chunks int) {
+	if chunks == 0 {
+		return
+	}
+	fmt.Printf("%d chunk(s) failed to extract and were quarantined (see 'lore retry-failed')\n", chunks)
+}
+
+func displayConflictsRecorded(issues int) {
+	if issues == 0 {
+		return
+	}
+	fmt.Println("Contradictions were recorded as conflicts (see 'lore conflicts list')")
+}
 
-	return nil
+func displayDisambiguations(resolved []services.Disambiguation) {
+	for _, d := range resolved {
+		switch {
+		case d.Entity == "":
+			fmt.Printf("  Kept ambiguous subject %q\n", d.Subject)
+		case d.Automatic:
+			fmt.Printf("  Resolved %q to %s (from context)\n", d.Subject, d.Entity)
+		default:
+			fmt.Printf("  Resolved %q to %s\n", d.Subject, d.Entity)
+		}
+	}
 }
 
 func displayConsistencyIssues(issues []ports.ConsistencyIssue) {
@@ -135,8 +508,17 @@ func displayConsistencyIssues(issues []ports.ConsistencyIssue) {
 		fmt.Printf("  New:      %s %s %s (%s)\n",
 			issues[i].NewFact.Subject, issues[i].NewFact.Predicate, issues[i].NewFact.Object, issues[i].NewFact.SourceFile)
 		fmt.Printf("  Existing: %s %s %s (%s)\n\n",
-			issues[i].ExistingFact.Subject, issues[i].ExistingFact.Predicate, issues[i].ExistingFact.Object, issues[i].ExistingFact.SourceFile)
+			issues[i].ExistingFact.Subject, issues[i].ExistingFact.Predicate, issues[i].ExistingFact.Object, describeSources(&issues[i].ExistingFact))
+	}
+}
+
+// describeSources names the fact's source file, noting how many sources
+// corroborate it when there are several.
+func describeSources(fact *entities.Fact) string {
+	if fact.SourceCount() < 2 {
+		return fact.SourceFile
 	}
+	return fmt.Sprintf("%s; %d sources", fact.SourceFile, fact.SourceCount())
 }
 
 func formatSeverity(severity string) string {
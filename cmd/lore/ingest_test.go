@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestParseFocus(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []ports.ExtractionFocus
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{"several", []string{"events", "rules"}, []ports.ExtractionFocus{ports.FocusEvents, ports.FocusRules}, false},
+		{"case and spaces", []string{" Events "}, []ports.ExtractionFocus{ports.FocusEvents}, false},
+		{"duplicates", []string{"rules", "rules"}, []ports.ExtractionFocus{ports.FocusRules}, false},
+		{"unknown", []string{"weather"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFocus(tt.values)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
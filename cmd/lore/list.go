@@ -1,96 +1,395 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
+type listFlags struct {
+	limit      int
+	factType   string
+	sourceFile string
+	since      string
+	until      string
+	sort       string
+	output     string
+	cursor     string
+	all        bool
+	noPager    bool
+	drafts     bool
+}
+
 func newListCmd() *cobra.Command {
-	var (
-		limit      int
-		factType   string
-		sourceFile string
-	)
+	var flags listFlags
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all facts",
-		Long:  "Lists all facts stored in the database with optional filtering.",
+		Long: `Lists all facts stored in the database with optional filtering.
+
+Time filters accept a date (2024-01-02) or an RFC3339 timestamp. They apply
+to the update time when --sort updated is used, and to the creation time
+otherwise. A date passed to --until includes the whole day.
+
+--output table or wide prints the facts as a table, wide adding their
+source, status, tags, conflicts, and update time. --output json prints
+them as a JSON array, and --output go-template=TEMPLATE runs a Go template
+on that array.
+
+Facts are listed a page of --limit at a time. When there are more, the
+command ends with the --cursor that lists the next page; --all lists every
+page instead. --drafts lists only draft facts. The time filters and --sort
+cannot be paged. On a terminal,
+output goes through $LORE_PAGER, $PAGER, or less; --no-pager turns it off.
+
+Examples:
+  lore list --type character
+  lore list --since 2024-01-01 --sort updated
+  lore list --since 2024-01-01 --until 2024-01-31 --sort created
+  lore list --output wide
+  lore list --type character --cursor 5c1d0e9a-3b7f-4e2a-9a41-0b6a4b9f2c10
+  lore list --all --no-pager > facts.txt
+  lore list --drafts
+  lore list --output go-template='{{range .}}{{.Subject}}: {{.Object}}{{"\n"}}{{end}}'`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(cmd, limit, factType, sourceFile)
+			return runList(cmd, &flags)
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", DefaultListLimit, "Maximum number of facts to display")
-	cmd.Flags().StringVarP(&factType, "type", "t", "", "Filter by fact type")
-	cmd.Flags().StringVarP(&sourceFile, "source", "s", "", "Filter by source file")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", DefaultListLimit, "Number of facts per page")
+	cmd.Flags().StringVarP(&flags.factType, "type", "t", "", "Filter by fact type")
+	cmd.Flags().StringVarP(&flags.sourceFile, "source", "s", "", "Filter by source file")
+	cmd.Flags().StringVar(&flags.since, "since", "", "Only facts on or after this date")
+	cmd.Flags().StringVar(&flags.until, "until", "", "Only facts on or before this date")
+	cmd.Flags().StringVar(&flags.sort, "sort", "", "Sort newest first by: created, updated")
+	cmd.Flags().StringVar(&flags.cursor, "cursor", "", "Start at this cursor, printed after a page with more facts")
+	cmd.Flags().BoolVar(&flags.all, "all", false, "List every page")
+	cmd.Flags().BoolVar(&flags.noPager, "no-pager", false, "Do not page the output on a terminal")
+	cmd.Flags().BoolVar(&flags.drafts, "drafts", false, "Only draft facts")
+	addOutputFlag(cmd, &flags.output)
 
 	return cmd
 }
 
-func runList(cmd *cobra.Command, limit int, factType string, sourceFile string) error {
+// buildFactListOptions converts time-related list flags into query options.
+// The second return value reports whether any time option was requested.
+func buildFactListOptions(flags *listFlags) (ports.FactListOptions, bool, error) {
+	opts := ports.FactListOptions{
+		Type:  entities.FactType(flags.factType),
+		Sort:  ports.FactSort(flags.sort),
+		Limit: flags.limit,
+	}
+	if !opts.Sort.IsValid() {
+		return opts, false, entities.Errorf(entities.ErrValidation, "invalid sort: %s (valid: created, updated)", flags.sort)
+	}
+
+	var err error
+	if flags.since != "" {
+		if opts.Since, err = parseTimeFlag(flags.since, false); err != nil {
+			return opts, false, fmt.Errorf("parsing --since: %w", err)
+		}
+	}
+	if flags.until != "" {
+		if opts.Until, err = parseTimeFlag(flags.until, true); err != nil {
+			return opts, false, fmt.Errorf("parsing --until: %w", err)
+		}
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && opts.Until.Before(opts.Since) {
+		return opts, false, entities.Errorf(entities.ErrValidation, "--until must not be before --since")
+	}
+
+	requested := flags.since != "" || flags.until != "" || flags.sort != ""
+	return opts, requested, nil
+}
+
+// parseTimeFlag parses a date or RFC3339 timestamp. Plain dates are
+// interpreted in local time; endOfDay extends them to the last instant of the day.
+func parseTimeFlag(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, entities.Errorf(entities.ErrValidation, "invalid time %q (use YYYY-MM-DD or RFC3339)", value)
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+func runList(cmd *cobra.Command, flags *listFlags) error {
 	ctx := cmd.Context()
+	factType := flags.factType
+	sourceFile := flags.sourceFile
+
+	timeOpts, timeFiltered, err := buildFactListOptions(flags)
+	if err != nil {
+		return err
+	}
+	if timeFiltered && sourceFile != "" {
+		return entities.Errorf(entities.ErrValidation, "--source cannot be combined with --since, --until, or --sort")
+	}
+	if timeFiltered && (flags.cursor != "" || flags.all || flags.drafts) {
+		return entities.Errorf(entities.ErrValidation, "--cursor, --all, and --drafts cannot be combined with --since, --until, or --sort")
+	}
+	if !timeFiltered && flags.limit <= 0 {
+		return entities.Errorf(entities.ErrValidation, "--limit must be positive")
+	}
+	output, err := parseOutput(flags.output)
+	if err != nil {
+		return err
+	}
 
 	return withInternalDeps(func(d *internalDeps) error {
-		var facts []entities.Fact
-		var err error
-
-		switch {
-		case factType != "":
-			// Validate type flag
-			if !d.entityTypeService.IsValid(ctx, factType) {
-				validTypes, verr := d.entityTypeService.GetValidTypes(ctx)
-				if verr != nil {
-					return fmt.Errorf("getting valid types: %w", verr)
-				}
-				return fmt.Errorf("invalid type %q, valid types: %s", factType, strings.Join(validTypes, ", "))
+		if factType != "" && !d.entityTypeService.IsValid(ctx, factType) {
+			validTypes, verr := d.entityTypeService.GetValidTypes(ctx)
+			if verr != nil {
+				return fmt.Errorf("getting valid types: %w", verr)
 			}
-			facts, err = d.repo.ListByType(ctx, entities.FactType(factType), limit)
-		case sourceFile != "":
-			facts, err = d.repo.ListBySource(ctx, sourceFile, limit)
-		default:
-			facts, err = d.repo.List(ctx, limit, 0)
+			return entities.Errorf(entities.ErrValidation, "invalid type %q, valid types: %s", factType, strings.Join(validTypes, ", "))
+		}
+
+		if timeFiltered {
+			facts, err := d.repo.ListFiltered(ctx, timeOpts)
+			if err != nil {
+				return fmt.Errorf("listing facts: %w", err)
+			}
+			return withPager(flags.noPager, func(w io.Writer) error {
+				return showFacts(ctx, w, d, output, facts)
+			})
+		}
+
+		opts := ports.FactPageOptions{
+			Type:       entities.FactType(factType),
+			SourceFile: sourceFile,
+			Drafts:     flags.drafts,
+			Limit:      flags.limit,
+			Cursor:     flags.cursor,
+		}
+		if flags.all {
+			return withPager(flags.noPager, func(w io.Writer) error {
+				return listAllPages(ctx, w, d, output, opts)
+			})
 		}
 
+		facts, next, err := d.vectorDB.ListPage(ctx, opts)
 		if err != nil {
 			return fmt.Errorf("listing facts: %w", err)
 		}
-
-		if len(facts) == 0 {
-			fmt.Println("No facts found.")
+		return withPager(flags.noPager, func(w io.Writer) error {
+			if err := showFacts(ctx, w, d, output, facts); err != nil {
+				return err
+			}
+			if next != "" {
+				// The hint stays out of machine-readable output.
+				hint := w
+				if output.isSet() {
+					hint = os.Stderr
+				}
+				fmt.Fprintf(hint, "More facts: lore list %s\n", nextPageArgs(flags, next))
+			}
 			return nil
-		}
+		})
+	})
+}
 
-		count, _ := d.repo.Count(ctx)
-		displayFacts(facts, count)
+// showFacts writes a list of facts to w, as --output asks or in the usual
+// form.
+func showFacts(ctx context.Context, w io.Writer, d *internalDeps, output outputFormat, facts []entities.Fact) error {
+	if len(facts) == 0 && !output.isSet() {
+		fmt.Fprintln(w, "No facts found.")
 		return nil
-	})
+	}
+
+	conflicts, err := d.conflictService.OpenCounts(ctx, facts)
+	if err != nil {
+		return err
+	}
+	if output.isSet() {
+		return renderFacts(w, output, facts, conflicts)
+	}
+
+	count, _ := d.repo.Count(ctx)
+	displayFacts(w, facts, count, conflicts)
+	return nil
+}
+
+// listAllPages writes every page of facts from opts on to w. Tables and the
+// usual output are written a page at a time, and stop when the user quits
+// the pager; JSON and templates need the whole list first.
+func listAllPages(ctx context.Context, w io.Writer, d *internalDeps, output outputFormat, opts ports.FactPageOptions) error {
+	var all []entities.Fact
+	listed := 0
+	for {
+		facts, next, err := d.vectorDB.ListPage(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("listing facts: %w", err)
+		}
+		listed += len(facts)
+
+		switch output.kind {
+		case outputJSON, outputGoTemplate:
+			all = append(all, facts...)
+		default:
+			conflicts, err := d.conflictService.OpenCounts(ctx, facts)
+			if err != nil {
+				return err
+			}
+			if output.isSet() {
+				// Each page is its own table, so its columns fit it.
+				err = renderFacts(w, output, facts, conflicts)
+			} else {
+				for i := range facts {
+					displayFact(w, &facts[i], conflicts[facts[i].ID])
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		if next == "" || stopped(w) {
+			break
+		}
+		opts.Cursor = next
+	}
+
+	switch {
+	case output.kind == outputJSON || output.kind == outputGoTemplate:
+		return showFacts(ctx, w, d, output, all)
+	case listed == 0 && !output.isSet():
+		fmt.Fprintln(w, "No facts found.")
+	}
+	return nil
+}
+
+// nextPageArgs returns the list flags that show the page at cursor.
+func nextPageArgs(flags *listFlags, cursor string) string {
+	args := []string{"--cursor", cursor}
+	if flags.factType != "" {
+		args = append(args, "--type", flags.factType)
+	}
+	if flags.sourceFile != "" {
+		args = append(args, "--source", strconv.Quote(flags.sourceFile))
+	}
+	if flags.drafts {
+		args = append(args, "--drafts")
+	}
+	if flags.limit != DefaultListLimit {
+		args = append(args, "--limit", strconv.Itoa(flags.limit))
+	}
+	if flags.output != "" {
+		args = append(args, "--output", strconv.Quote(flags.output))
+	}
+	return strings.Join(args, " ")
 }
 
-func displayFacts(facts []entities.Fact, totalCount uint64) {
+// displayFacts prints facts with their open conflict counts, keyed by fact ID.
+func displayFacts(w io.Writer, facts []entities.Fact, totalCount uint64, conflicts map[string]int) {
 	if totalCount > 0 {
-		fmt.Printf("Showing %d of %d facts:\n\n", len(facts), totalCount)
+		fmt.Fprintf(w, "Showing %d of %d facts:\n\n", len(facts), totalCount)
 	} else {
-		fmt.Printf("Showing %d facts:\n\n", len(facts))
+		fmt.Fprintf(w, "Showing %d facts:\n\n", len(facts))
 	}
 
 	for i := range facts {
-		displayFact(&facts[i])
+		displayFact(w, &facts[i], conflicts[facts[i].ID])
 	}
 }
 
-func displayFact(fact *entities.Fact) {
-	fmt.Printf("ID: %s\n", fact.ID)
-	fmt.Printf("  [%s] %s %s %s\n", fact.Type, fact.Subject, fact.Predicate, fact.Object)
+// renderFacts writes facts as --output asks, with their open conflict
+// counts, keyed by fact ID, in the wide table. Embeddings are left out.
+func renderFacts(w io.Writer, output outputFormat, facts []entities.Fact, conflicts map[string]int) error {
+	out := make([]entities.Fact, len(facts))
+	for i := range facts {
+		out[i] = facts[i]
+		out[i].Embedding, out[i].TextEmbedding = nil, nil
+	}
+
+	return render(w, output, out, []column[entities.Fact]{
+		{header: "ID", value: func(f entities.Fact) string { return f.ID }},
+		{header: "TYPE", value: func(f entities.Fact) string { return string(f.Type) }},
+		{header: "SUBJECT", value: func(f entities.Fact) string { return f.Subject }},
+		{header: "PREDICATE", value: func(f entities.Fact) string { return f.Predicate }},
+		{header: "OBJECT", value: func(f entities.Fact) string { return f.Object }},
+		{header: "SOURCE", wide: true, value: func(f entities.Fact) string {
+			if f.SourceFile == "" {
+				return ""
+			}
+			return describeSource(&f)
+		}},
+		{header: "STATUS", wide: true, value: func(f entities.Fact) string {
+			if f.IsDraft() {
+				return "draft"
+			}
+			if f.Status == "" {
+				return string(entities.FactStatusActive)
+			}
+			return string(f.Status)
+		}},
+		{header: "TAGS", wide: true, value: func(f entities.Fact) string { return strings.Join(f.Tags, ",") }},
+		{header: "CONFLICTS", wide: true, value: func(f entities.Fact) string { return strconv.Itoa(conflicts[f.ID]) }},
+		{header: "UPDATED", wide: true, value: func(f entities.Fact) string {
+			if f.UpdatedAt.IsZero() {
+				return ""
+			}
+			return f.UpdatedAt.Format(time.DateTime)
+		}},
+	})
+}
+
+func displayFact(w io.Writer, fact *entities.Fact, openConflicts int) {
+	fmt.Fprintf(w, "ID: %s\n", fact.ID)
+	fmt.Fprintf(w, "  [%s] %s %s %s\n", fact.Type, fact.Subject, fact.Predicate, fact.Object)
 	if fact.Context != "" {
-		fmt.Printf("  Context: %s\n", fact.Context)
+		fmt.Fprintf(w, "  Context: %s\n", fact.Context)
 	}
 	if fact.SourceFile != "" {
-		fmt.Printf("  Source: %s\n", fact.SourceFile)
+		fmt.Fprintf(w, "  Source: %s\n", describeSource(fact))
+	}
+	if fact.AssertedBy != "" {
+		fmt.Fprintf(w, "  Asserted by: %s\n", describeAssertion(fact))
+	}
+	if len(fact.Metadata) > 0 {
+		fmt.Fprintf(w, "  Metadata: %s\n", describeMetadata(fact))
+	}
+	if len(fact.Tags) > 0 {
+		fmt.Fprintf(w, "  Tags: %s\n", strings.Join(fact.Tags, ", "))
+	}
+	if fact.IsPending() {
+		fmt.Fprintf(w, "  Status: pending review (confidence %.2f)\n", fact.Confidence)
+	}
+	if fact.IsDraft() {
+		fmt.Fprintf(w, "  Draft: %s\n", describeDraft(fact))
+	}
+	if openConflicts > 0 {
+		fmt.Fprintf(w, "  Conflicts: %d open (see 'lore conflicts list --fact %s')\n", openConflicts, fact.ID)
+	}
+	if !fact.UpdatedAt.IsZero() {
+		fmt.Fprintf(w, "  Updated: %s\n", fact.UpdatedAt.Format(time.DateTime))
+	}
+	fmt.Fprintln(w)
+}
+
+// describeDraft says how long a draft lasts.
+func describeDraft(fact *entities.Fact) string {
+	switch {
+	case fact.ExpiresAt.IsZero():
+		return "until promoted (see 'lore facts promote')"
+	case fact.IsExpired(time.Now()):
+		return fmt.Sprintf("expired %s", fact.ExpiresAt.Local().Format(time.DateTime))
+	default:
+		return fmt.Sprintf("expires %s", fact.ExpiresAt.Local().Format(time.DateTime))
 	}
-	fmt.Println()
 }
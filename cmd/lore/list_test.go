@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestParseTimeFlag(t *testing.T) {
+	t.Run("date start of day", func(t *testing.T) {
+		got, err := parseTimeFlag("2024-01-02", false)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local), got)
+	})
+
+	t.Run("date end of day", func(t *testing.T) {
+		got, err := parseTimeFlag("2024-01-02", true)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 23, 59, 59, 999999999, time.Local), got)
+	})
+
+	t.Run("rfc3339", func(t *testing.T) {
+		got, err := parseTimeFlag("2024-01-02T10:30:00Z", true)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC), got.UTC())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseTimeFlag("last week", false)
+		require.Error(t, err)
+	})
+}
+
+func TestBuildFactListOptions(t *testing.T) {
+	t.Run("no time options", func(t *testing.T) {
+		_, requested, err := buildFactListOptions(&listFlags{limit: 10})
+		require.NoError(t, err)
+		assert.False(t, requested)
+	})
+
+	t.Run("since with updated sort", func(t *testing.T) {
+		opts, requested, err := buildFactListOptions(&listFlags{limit: 10, since: "2024-01-01", sort: "updated"})
+		require.NoError(t, err)
+		assert.True(t, requested)
+		assert.Equal(t, ports.FactSortUpdated, opts.Sort)
+		assert.Equal(t, "updated_at", opts.TimeField())
+		assert.Equal(t, 10, opts.Limit)
+		assert.False(t, opts.Since.IsZero())
+		assert.True(t, opts.Until.IsZero())
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		_, _, err := buildFactListOptions(&listFlags{sort: "name"})
+		require.Error(t, err)
+	})
+
+	t.Run("until before since", func(t *testing.T) {
+		_, _, err := buildFactListOptions(&listFlags{since: "2024-02-01", until: "2024-01-01"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/lsp"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newLSPCmd() *cobra.Command {
+	var chunker string
+
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a language server that checks manuscripts in your editor",
+		Long: `Runs a Language Server Protocol server on stdin and stdout, for editors to
+start for manuscript files.
+
+When a file is opened or saved, facts are extracted from it and checked
+against the world's facts, without saving anything, and contradictions are
+shown as diagnostics at the sentences stating them, related to where the
+facts they contradict are stated. Only the chunks of the file changed
+since its last check are sent to the LLM again. Contradictions with the
+file's own earlier facts are left out, since ingesting it would replace
+them.
+
+Hovering over an entity's name shows its card, as 'lore hover' prints it.
+
+Example editor setup (Neovim):
+  vim.lsp.start({ name = "lore", cmd = { "lore", "lsp", "-w", "myworld" } })`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if globalWorld == "" {
+				return entities.Errorf(entities.ErrValidation, "world is required (use --world flag)")
+			}
+			return withInternalDeps(func(d *internalDeps) error {
+				chunks, err := services.NewChunkerWithLimit(ports.ChunkStrategy(chunker), d.Config.Ingest.MaxChunkSize)
+				if err != nil {
+					return err
+				}
+				server := lsp.NewServer(&lsp.Options{
+					World:  globalWorld,
+					Ingest: d.IngestHandler,
+					IngestOptions: handlers.IngestOptions{
+						Reliable:     d.Config.Claims.Reliable,
+						WorldID:      globalWorld,
+						Retrieval:    retrieval(d),
+						ChooseEntity: keepSubject,
+						MaxFileSize:  d.Config.Ingest.MaxFileSize,
+					},
+					Chunker: chunks,
+					Cards:   newCardHandler(d),
+				})
+				return server.Serve(cmd.Context(), os.Stdin, os.Stdout)
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&chunker, "chunker", string(ports.ChunkParagraph), "How to split text: paragraph, sentence-window, markdown-heading, fixed-token")
+
+	return cmd
+}
@@ -3,17 +3,23 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 var (
-	version     = "0.1.0-dev"
-	globalWorld string
+	version         = "0.1.0-dev"
+	globalWorld     string
+	globalConfigDir string
+	globalProfile   string
+	globalStrict    bool
+	globalErrFormat string
+	globalTrace     bool
 )
 
 func main() {
@@ -21,8 +27,7 @@ func main() {
 	defer cancel()
 
 	if err := run(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+		os.Exit(reportError(os.Stderr, globalErrFormat, err))
 	}
 }
 
@@ -31,24 +36,93 @@ func run(ctx context.Context) error {
 		Use:     "lore",
 		Short:   "A factual knowledge base powered by vector search and LLM analysis",
 		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return checkErrorFormat(cmd)
+		},
 	}
 
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		silenceForJSON(cmd)
+		return entities.WithKind(entities.ErrValidation, err)
+	})
+
 	rootCmd.PersistentFlags().StringVarP(&globalWorld, "world", "w", "", "World to operate on (required)")
+	rootCmd.PersistentFlags().StringVar(&globalConfigDir, "config-dir", "",
+		"Config directory (default: $LORE_HOME, or the nearest .lore in this or a parent directory)")
+	rootCmd.PersistentFlags().StringVar(&globalProfile, "profile", "",
+		"Config profile to use (default: $LORE_PROFILE, or default_profile in config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&globalStrict, "strict", false,
+		"Refuse, rather than warn about, going beyond the world's quota (see quota in config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&globalTrace, "trace", false,
+		"Log LLM, Qdrant, and SQLite calls, redacted and with timings, to a trace file under .lore/traces")
+	rootCmd.PersistentFlags().StringVar(&globalErrFormat, "error-format", errorFormatText,
+		"How to print a failure to stderr: text, or json for scripts and editor plugins")
 
 	rootCmd.AddCommand(
 		newIngestCmd(),
+		newRetryFailedCmd(),
+		newCompareModelsCmd(),
 		newQueryCmd(),
 		newListCmd(),
+		newFactsCmd(),
 		newDeleteCmd(),
+		newReviewCmd(),
+		newCheckCmd(),
+		newHooksCmd(),
+		newAnalyzeCmd(),
+		newGlossaryCmd(),
+		newConflictsCmd(),
 		newExportCmd(),
+		newGraphCmd(),
 		newImportCmd(),
+		newImportWikiCmd(),
 		newWatchCmd(),
+		newSessionsCmd(),
 		newWorldsCmd(),
 		newTypesCmd(),
+		newStyleCmd(),
+		newSourcesCmd(),
 		newRelateCmd(),
 		newRelationsCmd(),
 		newEntitiesCmd(),
+		newRefreshCmd(),
+		newHoverCmd(),
+		newMigrateCmd(),
+		newServeCmd(),
+		newLSPCmd(),
+		newTokensCmd(),
+		newSnapshotsCmd(),
+		newVerifyBackupCmd(),
+		newStatsCmd(),
+		newDemoCmd(),
 	)
 
 	return rootCmd.ExecuteContext(ctx)
 }
+
+// checkErrorFormat validates --error-format. With json, cobra's own error
+// and usage output is silenced so stderr holds only the JSON report.
+func checkErrorFormat(cmd *cobra.Command) error {
+	switch globalErrFormat {
+	case errorFormatText:
+		return nil
+	case errorFormatJSON:
+		silenceForJSON(cmd)
+		return nil
+	default:
+		format := globalErrFormat
+		globalErrFormat = errorFormatText
+		return entities.Errorf(entities.ErrValidation, "invalid --error-format %q (valid: text, json)", format)
+	}
+}
+
+// silenceForJSON stops cobra from printing errors and usage when failures
+// are reported as JSON.
+func silenceForJSON(cmd *cobra.Command) {
+	if globalErrFormat != errorFormatJSON {
+		return
+	}
+	root := cmd.Root()
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+}
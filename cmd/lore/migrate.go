@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
+)
+
+type reindexFlags struct {
+	reEmbed   bool
+	keepOld   bool
+	batchSize int
+}
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate world data",
+		Long:  "Rebuild or upgrade stored data for the current world.",
+	}
+
+	cmd.AddCommand(newMigrateReindexCmd())
+
+	return cmd
+}
+
+func newMigrateReindexCmd() *cobra.Command {
+	var flags reindexFlags
+
+	cmd := &cobra.Command{
+		Use:   "reindex",
+		Short: "Rebuild the world's vector collection without downtime",
+		Long: `Copies all facts into a new Qdrant collection and then atomically
+switches the world's collection alias to it. Queries keep using the old
+collection until the new one is fully populated.
+
+Use --re-embed after changing the embedding model or embedder.template so
+every fact gets a fresh vector. The first reindex of a world created before aliases existed
+replaces the original collection, so queries fail briefly during the switch,
+and --keep-old cannot keep it.
+
+Examples:
+  lore migrate reindex -w myworld
+  lore migrate reindex -w myworld --re-embed
+  lore migrate reindex -w myworld --keep-old`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateReindex(cmd, flags)
+		},
+	}
+
+	cmd.Flags().BoolVar(&flags.reEmbed, "re-embed", false, "Regenerate embeddings with the configured model")
+	cmd.Flags().BoolVar(&flags.keepOld, "keep-old", false, "Keep the previous collection after switching")
+	cmd.Flags().IntVar(&flags.batchSize, "batch-size", services.DefaultReindexBatchSize, "Facts copied per batch")
+
+	return cmd
+}
+
+func runMigrateReindex(cmd *cobra.Command, flags reindexFlags) error {
+	if flags.batchSize <= 0 {
+		return entities.Errorf(entities.ErrValidation, "batch-size must be positive")
+	}
+
+	ctx := cmd.Context()
+
+	return withMigrationService(func(d *internalDeps, svc *services.MigrationService, alias string) error {
+		fmt.Printf("Reindexing %s...\n", alias)
+
+		result, err := svc.Reindex(ctx, alias, services.ReindexOptions{
+			VectorSize: embedder.VectorSize,
+			ReEmbed:    flags.reEmbed,
+			KeepOld:    flags.keepOld,
+			BatchSize:  flags.batchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("reindexing: %w", err)
+		}
+
+		fmt.Printf("Copied %d facts from %s to %s\n", result.Copied, result.OldCollection, result.NewCollection)
+		fmt.Printf("Alias %s now points to %s\n", result.Alias, result.NewCollection)
+		if !result.OldDeleted {
+			fmt.Printf("Previous collection %s was kept\n", result.OldCollection)
+		} else if flags.keepOld && result.Legacy {
+			fmt.Printf("Previous collection %s was not kept: it predates aliases, and the alias took its name\n", result.OldCollection)
+		}
+		if flags.reEmbed {
+			return recordEmbeddingTemplate(d)
+		}
+		return nil
+	})
+}
+
+// recordEmbeddingTemplate records that the current world's facts are now
+// embedded with the configured template.
+func recordEmbeddingTemplate(d *internalDeps) error {
+	entry, err := d.Worlds.Get(globalWorld)
+	if err != nil {
+		return err
+	}
+	if entry.EmbeddingTemplate == d.Config.Embedder.Template {
+		return nil
+	}
+	entry.EmbeddingTemplate = d.Config.Embedder.Template
+	d.Worlds.Add(globalWorld, *entry)
+	if err := d.Worlds.Save(d.configDir); err != nil {
+		return fmt.Errorf("saving worlds: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// Formats accepted by --output.
+const (
+	outputTable      = "table"
+	outputWide       = "wide"
+	outputJSON       = "json"
+	outputGoTemplate = "go-template"
+)
+
+// outputFormat is a parsed --output value. The zero value keeps a command's
+// usual output.
+type outputFormat struct {
+	kind     string
+	template *template.Template
+}
+
+// addOutputFlag adds --output to cmd.
+func addOutputFlag(cmd *cobra.Command, value *string) {
+	cmd.Flags().StringVarP(value, "output", "o", "",
+		"Output format: table, wide, json, or go-template=TEMPLATE (default: the usual output)")
+}
+
+// parseOutput parses an --output value. A go-template is parsed now, so a
+// mistake in it is reported before anything is run.
+func parseOutput(value string) (outputFormat, error) {
+	switch value {
+	case "":
+		return outputFormat{}, nil
+	case outputTable, outputWide, outputJSON:
+		return outputFormat{kind: value}, nil
+	}
+
+	text, ok := strings.CutPrefix(value, outputGoTemplate+"=")
+	if !ok {
+		return outputFormat{}, entities.Errorf(entities.ErrValidation, "invalid --output %q (valid: table, wide, json, go-template=TEMPLATE)", value)
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return outputFormat{}, entities.Errorf(entities.ErrValidation, "invalid --output template: %w", err)
+	}
+	return outputFormat{kind: outputGoTemplate, template: tmpl}, nil
+}
+
+// isSet reports whether --output was given.
+func (f outputFormat) isSet() bool {
+	return f.kind != ""
+}
+
+// templateFuncs are the functions available to --output go-template, besides
+// text/template's own.
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// column is a column of table output. Wide columns are only shown by
+// --output wide.
+type column[T any] struct {
+	header string
+	wide   bool
+	value  func(T) string
+}
+
+// render writes items to w as f asks: a table of columns, a JSON array, or
+// the template run once on the whole list.
+func render[T any](w io.Writer, f outputFormat, items []T, columns []column[T]) error {
+	switch f.kind {
+	case outputJSON:
+		if items == nil {
+			items = []T{}
+		}
+		data, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshaling JSON: %w", err)
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+	case outputGoTemplate:
+		if err := f.template.Execute(w, items); err != nil {
+			return fmt.Errorf("executing --output template: %w", err)
+		}
+		return nil
+	}
+
+	var shown []column[T]
+	for _, c := range columns {
+		if !c.wide || f.kind == outputWide {
+			shown = append(shown, c)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	cells := make([]string, len(shown))
+	for i, c := range shown {
+		cells[i] = c.header
+	}
+	fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	for _, item := range items {
+		for i, c := range shown {
+			cells[i] = tableCell(c.value(item))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// tableCell keeps a value on one line of its column.
+func tableCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestParseOutput(t *testing.T) {
+	for _, value := range []string{"", "table", "wide", "json", "go-template={{len .}}"} {
+		_, err := parseOutput(value)
+		assert.NoError(t, err, value)
+	}
+
+	got, err := parseOutput("")
+	require.NoError(t, err)
+	assert.False(t, got.isSet(), "no --output keeps the usual output")
+
+	for _, value := range []string{"yaml", "go-template", "go-template={{.Subject"} {
+		_, err := parseOutput(value)
+		assert.ErrorIs(t, err, entities.ErrValidation, value)
+	}
+}
+
+func TestRenderFacts(t *testing.T) {
+	facts := []entities.Fact{
+		{
+			ID: "f1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "carries", Object: "the One Ring",
+			SourceFile: "/book/ch1.md", Tags: []string{"quest"}, Embedding: []float32{0.1},
+			UpdatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{ID: "f2", Type: entities.FactTypeLocation, Subject: "Mordor", Predicate: "lies", Object: "east of\nGondor", Status: entities.FactStatusPending},
+	}
+	conflicts := map[string]int{"f2": 1}
+
+	run := func(t *testing.T, value string) string {
+		t.Helper()
+		output, err := parseOutput(value)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, renderFacts(&buf, output, facts, conflicts))
+		return buf.String()
+	}
+
+	t.Run("table", func(t *testing.T) {
+		assert.Equal(t, ""+
+			"ID  TYPE       SUBJECT  PREDICATE  OBJECT\n"+
+			"f1  character  Frodo    carries    the One Ring\n"+
+			"f2  location   Mordor   lies       east of Gondor\n",
+			run(t, "table"))
+	})
+
+	t.Run("wide", func(t *testing.T) {
+		assert.Equal(t, ""+
+			"ID  TYPE       SUBJECT  PREDICATE  OBJECT          SOURCE        STATUS   TAGS   CONFLICTS  UPDATED\n"+
+			"f1  character  Frodo    carries    the One Ring    /book/ch1.md  active   quest  0          2024-01-02 03:04:05\n"+
+			"f2  location   Mordor   lies       east of Gondor  -             pending  -      1          -\n",
+			run(t, "wide"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var got []entities.Fact
+		require.NoError(t, json.Unmarshal([]byte(run(t, "json")), &got))
+		require.Len(t, got, 2)
+		assert.Equal(t, "Frodo", got[0].Subject)
+		assert.Nil(t, got[0].Embedding, "embeddings are left out")
+		assert.Equal(t, []float32{0.1}, facts[0].Embedding, "the caller's facts are unchanged")
+	})
+
+	t.Run("go-template", func(t *testing.T) {
+		assert.Equal(t, "Frodo carries [quest]\nMordor lies []\n",
+			run(t, `go-template={{range .}}{{.Subject}} {{.Predicate}} [{{join .Tags ","}}]{{"\n"}}{{end}}`))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		output, err := parseOutput("json")
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, renderFacts(&buf, output, nil, nil))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+}
+
+func TestRenderRelations(t *testing.T) {
+	relationships := []handlers.RelationshipInfo{{
+		Relationship: entities.Relationship{ID: "r1", Type: "ally", Bidirectional: true},
+		SourceEntity: &entities.Entity{Name: "Frodo"},
+		TargetEntity: &entities.Entity{Name: "Sam"},
+	}}
+
+	output, err := parseOutput("wide")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, renderRelations(&buf, output, relationships))
+	assert.Equal(t, ""+
+		"SOURCE  TYPE  TARGET  DIRECTION  ID  CREATED\n"+
+		"Frodo   ally  Sam     both       r1  -\n",
+		buf.String())
+
+	output, err = parseOutput(`go-template={{range .}}{{.SourceEntity.Name}}->{{.TargetEntity.Name}}{{end}}`)
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, renderRelations(&buf, output, relationships))
+	assert.Equal(t, "Frodo->Sam", buf.String())
+}
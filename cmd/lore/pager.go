@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// defaultPager is the pager used when neither $LORE_PAGER nor $PAGER is set.
+// less searches with /, and its -F quits at once if the output fits on one
+// screen.
+const defaultPager = "less"
+
+// stdoutIsTerminal reports whether standard output is interactive.
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// pagerCommand returns the pager command line: $LORE_PAGER, then $PAGER,
+// then less. An empty result means no pager.
+func pagerCommand(getenv func(string) string) []string {
+	for _, name := range []string{"LORE_PAGER", "PAGER"} {
+		if value := getenv(name); value != "" {
+			return pagerFields(value)
+		}
+	}
+	return []string{defaultPager}
+}
+
+// pagerFields splits a pager command line. "cat" turns paging off.
+func pagerFields(value string) []string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || fields[0] == "cat" {
+		return nil
+	}
+	return fields
+}
+
+// pagerEnv returns the environment for the pager: less, unless $LESS says
+// otherwise, quits if the output fits on one screen, keeps colors, and
+// leaves the output on screen.
+func pagerEnv(env []string) []string {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, "LESS=") {
+			return env
+		}
+	}
+	return append(env, "LESS=FRX")
+}
+
+// withPager runs fn with a writer that pages its output when standard
+// output is a terminal and noPager is false, and with standard output
+// otherwise. If the pager cannot be started, the output is not paged.
+// fn should stop writing once the writer fails: the user has quit the
+// pager.
+func withPager(noPager bool, fn func(w io.Writer) error) error {
+	if noPager || !stdoutIsTerminal() {
+		return fn(os.Stdout)
+	}
+	args := pagerCommand(os.Getenv)
+	if len(args) == 0 {
+		return fn(os.Stdout)
+	}
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fn(os.Stdout)
+	}
+
+	pager := exec.Command(path, args[1:]...)
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	pager.Env = pagerEnv(os.Environ())
+	in, err := pager.StdinPipe()
+	if err != nil {
+		return fn(os.Stdout)
+	}
+	if err := pager.Start(); err != nil {
+		return fn(os.Stdout)
+	}
+
+	w := &pagerWriter{w: in}
+	err = fn(w)
+	in.Close()
+	_ = pager.Wait()
+	if w.closed() && (err == nil || errors.Is(err, syscall.EPIPE)) {
+		return nil
+	}
+	return err
+}
+
+// pagerWriter writes to a pager and remembers the first error, which
+// usually means the user quit it.
+type pagerWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (p *pagerWriter) Write(b []byte) (int, error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+	n, err := p.w.Write(b)
+	p.err = err
+	return n, err
+}
+
+// closed reports whether a write to the pager failed.
+func (p *pagerWriter) closed() bool {
+	return p.err != nil
+}
+
+// stopped reports whether w is a pager the user has quit.
+func stopped(w io.Writer) bool {
+	p, ok := w.(*pagerWriter)
+	return ok && p.closed()
+}
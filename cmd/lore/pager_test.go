@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerCommand(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(name string) string { return vars[name] }
+	}
+
+	assert.Equal(t, []string{"less"}, pagerCommand(env(nil)))
+	assert.Equal(t, []string{"more"}, pagerCommand(env(map[string]string{"PAGER": "more"})))
+	assert.Equal(t, []string{"less", "-S"}, pagerCommand(env(map[string]string{"PAGER": "more", "LORE_PAGER": "less -S"})))
+	assert.Empty(t, pagerCommand(env(map[string]string{"LORE_PAGER": "cat"})), "cat turns paging off")
+}
+
+func TestPagerEnv(t *testing.T) {
+	assert.Equal(t, []string{"HOME=/root", "LESS=FRX"}, pagerEnv([]string{"HOME=/root"}))
+	assert.Equal(t, []string{"LESS=R"}, pagerEnv([]string{"LESS=R"}), "the user's LESS is kept")
+}
+
+func TestNextPageArgs(t *testing.T) {
+	assert.Equal(t, "--cursor abc", nextPageArgs(&listFlags{limit: DefaultListLimit}, "abc"))
+	assert.Equal(t, `--cursor abc --type character --source "ch 1.md" --limit 5 --output "wide"`,
+		nextPageArgs(&listFlags{limit: 5, factType: "character", sourceFile: "ch 1.md", output: "wide"}, "abc"))
+}
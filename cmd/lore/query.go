@@ -3,69 +3,168 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
 )
 
+// queryFlags holds the flags of the query command.
+type queryFlags struct {
+	limit    int
+	factType string
+	mode     string
+	asOf     string
+	pov      string
+	claims   bool
+	output   string
+}
+
 func newQueryCmd() *cobra.Command {
-	var (
-		limit    int
-		factType string
-	)
+	var flags queryFlags
 
 	cmd := &cobra.Command{
 		Use:   "query <question>",
 		Short: "Search for facts",
-		Long:  "Performs semantic search to find facts matching your question.",
-		Args:  cobra.ExactArgs(1),
+		Long: `Performs semantic search to find facts matching your question.
+
+Search modes:
+  context  Match facts together with their context (default)
+  text     Match only subject, predicate, and object; best for phrasing
+  fused    Combine both rankings
+  hybrid   Combine context matches with keyword matches; best for rare names
+
+Use --as-of to answer from facts as they stood at a past time, rebuilt from
+their version history. A plain date means the end of that day.
+
+Use --pov to answer from a character's point of view: facts only some
+entities know (see 'lore facts known-by') are left out unless the character
+is one of them or the fact is about them.
+
+Facts that are claims, such as a character's words or an unreliable
+narrator's account (see 'lore ingest --dialogue'), are left out unless
+--include-claims is given.
+
+--output table, wide, json, or go-template=TEMPLATE prints the matching
+facts, best first, as 'lore list' does.
+
+Examples:
+  lore query "Who rules Mordor?"
+  lore query "What is Sauron?" --as-of 2024-03-01
+  lore query "Where is the Ring?" --pov Frodo
+  lore query "What are elves like?" --include-claims
+  lore query "Who rules Mordor?" --output json`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runQuery(cmd, args[0], limit, factType)
+			return runQuery(cmd, args[0], flags)
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", DefaultQueryLimit, "Maximum number of results")
-	cmd.Flags().StringVarP(&factType, "type", "t", "", "Filter by fact type (character, location, event, relationship, rule, timeline)")
+	cmd.Flags().IntVarP(&flags.limit, "limit", "l", DefaultQueryLimit, "Maximum number of results")
+	cmd.Flags().StringVarP(&flags.factType, "type", "t", "", "Filter by fact type (character, location, event, relationship, rule, timeline)")
+	cmd.Flags().StringVar(&flags.mode, "mode", string(services.SearchModeContext), "Search mode: context, text, fused, hybrid")
+	cmd.Flags().StringVar(&flags.asOf, "as-of", "", "Answer as of a date (YYYY-MM-DD) or RFC3339 time")
+	cmd.Flags().StringVar(&flags.pov, "pov", "", "Only facts this character knows")
+	cmd.Flags().BoolVar(&flags.claims, "include-claims", false, "Also show claims, not only canon facts")
+	addOutputFlag(cmd, &flags.output)
 
 	return cmd
 }
 
-func runQuery(cmd *cobra.Command, query string, limit int, factType string) error {
+func runQuery(cmd *cobra.Command, query string, flags queryFlags) error {
 	ctx := cmd.Context()
 
+	searchMode := services.SearchMode(flags.mode)
+	if !searchMode.IsValid() {
+		return entities.Errorf(entities.ErrValidation, "invalid mode: %s (valid: context, text, fused, hybrid)", flags.mode)
+	}
+	output, err := parseOutput(flags.output)
+	if err != nil {
+		return err
+	}
+
+	var asOfTime time.Time
+	if flags.asOf != "" {
+		t, err := parseTimeFlag(flags.asOf, true)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of: %w", err)
+		}
+		asOfTime = t
+	}
+
 	return withInternalDeps(func(d *internalDeps) error {
 		// Validate type flag if provided
-		if factType != "" {
-			if !d.entityTypeService.IsValid(ctx, factType) {
+		if flags.factType != "" {
+			if !d.entityTypeService.IsValid(ctx, flags.factType) {
 				validTypes, err := d.entityTypeService.GetValidTypes(ctx)
 				if err != nil {
 					return fmt.Errorf("getting valid types: %w", err)
 				}
-				return fmt.Errorf("invalid type %q, valid types: %s", factType, strings.Join(validTypes, ", "))
+				return entities.Errorf(entities.ErrValidation, "invalid type %q, valid types: %s", flags.factType, strings.Join(validTypes, ", "))
 			}
 		}
 
-		result, err := executeQuery(ctx, &d.Deps, query, limit, factType)
+		pov, err := povEntity(ctx, d.relationalDB, flags.pov)
+		if err != nil {
+			return err
+		}
+
+		result, err := d.QueryHandler.HandleWithOptions(ctx, query, handlers.QueryOptions{
+			Type:  entities.FactType(flags.factType),
+			Limit: flags.limit,
+			Mode:  searchMode,
+			AsOf:  asOfTime,
+			POV:   pov,
+
+			IncludeClaims: flags.claims,
+		})
 		if err != nil {
 			return fmt.Errorf("querying facts: %w", err)
 		}
 
-		printQueryResults(result)
+		conflicts, err := d.conflictService.OpenCounts(ctx, result.Facts)
+		if err != nil {
+			return err
+		}
+		if output.isSet() {
+			return renderFacts(os.Stdout, output, result.Facts, conflicts)
+		}
+
+		if !asOfTime.IsZero() {
+			fmt.Printf("As of %s:\n", asOfTime.Format(time.DateTime))
+		}
+		if pov != nil {
+			fmt.Printf("As known to %s:\n", pov.Name)
+		}
+		printQueryResults(result, conflicts)
 		return nil
 	})
 }
 
-func executeQuery(ctx context.Context, d *Deps, query string, limit int, factType string) (*handlers.QueryResult, error) {
-	if factType != "" {
-		return d.QueryHandler.HandleByType(ctx, query, entities.FactType(factType), limit)
+// povEntity returns the entity named by --pov, or nil without one. A name
+// not yet recorded as an entity still knows common knowledge and the facts
+// about itself.
+func povEntity(ctx context.Context, relationalDB ports.RelationalDB, name string) (*entities.Entity, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
 	}
-	return d.QueryHandler.Handle(ctx, query, limit)
+	entity, err := relationalDB.FindEntityByName(ctx, globalWorld, name)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity %q: %w", name, err)
+	}
+	if entity == nil {
+		entity = &entities.Entity{WorldID: globalWorld, Name: strings.TrimSpace(name)}
+	}
+	return entity, nil
 }
 
-func printQueryResults(result *handlers.QueryResult) {
+func printQueryResults(result *handlers.QueryResult, conflicts map[string]int) {
 	if len(result.Facts) == 0 {
 		fmt.Println("No facts found.")
 		return
@@ -74,17 +173,29 @@ func printQueryResults(result *handlers.QueryResult) {
 	fmt.Printf("Found %d facts:\n\n", len(result.Facts))
 
 	for i := range result.Facts {
-		printFact(i+1, &result.Facts[i])
+		printFact(i+1, &result.Facts[i], conflicts[result.Facts[i].ID])
 	}
 }
 
-func printFact(num int, fact *entities.Fact) {
+func printFact(num int, fact *entities.Fact, openConflicts int) {
 	fmt.Printf("%d. [%s] %s %s %s\n", num, fact.Type, fact.Subject, fact.Predicate, fact.Object)
 	if fact.Context != "" {
 		fmt.Printf("   Context: %s\n", fact.Context)
 	}
 	if fact.SourceFile != "" {
-		fmt.Printf("   Source: %s\n", fact.SourceFile)
+		fmt.Printf("   Source: %s\n", describeSource(fact))
+	}
+	if fact.AssertedBy != "" {
+		fmt.Printf("   Asserted by: %s\n", describeAssertion(fact))
+	}
+	if len(fact.Metadata) > 0 {
+		fmt.Printf("   Metadata: %s\n", describeMetadata(fact))
+	}
+	if len(fact.Tags) > 0 {
+		fmt.Printf("   Tags: %s\n", strings.Join(fact.Tags, ", "))
+	}
+	if openConflicts > 0 {
+		fmt.Printf("   Conflicts: %d open\n", openConflicts)
 	}
 	fmt.Println()
 }
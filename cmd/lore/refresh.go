@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newRefreshCmd() *cobra.Command {
+	var (
+		maxEntities int
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Re-embed entity summaries whose facts changed",
+		Long: `Brings the entity index used by 'lore entities search --semantic' up to
+date. Each entity is embedded from a summary of the facts about it; an
+entity is stale when it has never been embedded, or when its facts have
+changed since it was. Only stale entities are embedded again, and
+entities that no longer exist are removed from the index.
+
+At most --max entities are embedded per run (serve.refresh.max_entities
+by default; 0 embeds every stale entity). Entities never embedded go
+first, and the rest stay stale until the next run. 'lore serve' does the
+same on serve.refresh.schedule.
+
+Use --dry-run to list the stale entities without embedding them.
+
+Examples:
+  lore refresh -w myworld
+  lore refresh -w myworld --max 50
+  lore refresh -w myworld --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if maxEntities < 0 {
+				return entities.Errorf(entities.ErrValidation, "--max must not be negative, got %d", maxEntities)
+			}
+
+			return withInternalDeps(func(d *internalDeps) error {
+				svc := newEntitySearchService(d)
+
+				if dryRun {
+					stale, err := svc.Stale(ctx, globalWorld)
+					if err != nil {
+						return err
+					}
+					if len(stale) == 0 {
+						fmt.Println("No stale entities.")
+						return nil
+					}
+					fmt.Printf("%d stale entities:\n\n", len(stale))
+					for _, entity := range stale {
+						state := "changed"
+						if entity.New {
+							state = "new"
+						}
+						fmt.Printf("  %-40s %-30s %s\n", shortEntityID(entity.Entity), entity.Entity.Name, state)
+					}
+					return nil
+				}
+
+				opts := services.EntityRefreshOptions{Limit: d.Config.Serve.Refresh.MaxEntities}
+				if cmd.Flags().Changed("max") {
+					opts.Limit = maxEntities
+				}
+				stats, err := svc.Refresh(ctx, globalWorld, opts)
+				if err != nil {
+					return fmt.Errorf("refreshing entity index: %w", err)
+				}
+
+				fmt.Printf("Refreshed %d entity summaries (%d unchanged, %d removed)\n", stats.Embedded, stats.Unchanged, stats.Removed)
+				if stats.Stale > 0 {
+					fmt.Printf("%d still stale (run again to continue)\n", stats.Stale)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&maxEntities, "max", 0, "Most entities to embed (default from serve.refresh.max_entities; 0 = all)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List stale entities without embedding them")
+
+	return cmd
+}
@@ -6,10 +6,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 func newRelateCmd() *cobra.Command {
-	var bidirectional bool
+	var (
+		bidirectional bool
+		idemKey       string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "relate <source-entity> <type> <target-entity>",
@@ -26,33 +30,43 @@ Valid relationship types:
 Examples:
   lore relate Alice ally Bob
   lore relate "Northern Kingdom" located_in "The Realm"
-  lore relate Alice enemy "Dark Lord" --bidirectional=false`,
+  lore relate Alice enemy "Dark Lord" --bidirectional=false
+  lore relate Alice ally Bob --idempotency-key import-42`,
 		Args: cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRelate(cmd, args, bidirectional)
+			return runRelate(cmd, args, bidirectional, idemKey)
 		},
 	}
 
 	cmd.Flags().BoolVar(&bidirectional, "bidirectional", true, "Create bidirectional relationship")
+	cmd.Flags().StringVar(&idemKey, "idempotency-key", "", idempotencyKeyUsage)
 
 	cmd.AddCommand(newRelateDeleteCmd())
 
 	return cmd
 }
 
-func runRelate(cmd *cobra.Command, args []string, bidirectional bool) error {
+func runRelate(cmd *cobra.Command, args []string, bidirectional bool, idemKey string) error {
 	ctx := cmd.Context()
 	sourceEntity := args[0]
 	relType := args[1]
 	targetEntity := args[2]
+	request := map[string]any{"source": sourceEntity, "type": relType, "target": targetEntity, "bidirectional": bidirectional}
 
-	return withRelationshipHandler(func(handler *handlers.RelationshipHandler) error {
-		rel, err := handler.HandleCreate(ctx, globalWorld, sourceEntity, relType, targetEntity, bidirectional)
+	return withInternalDeps(func(d *internalDeps) error {
+		handler := newRelationshipHandler(d)
+		rel, replayed, err := idempotent(ctx, d, idemKey, "relate", request, func() (*entities.Relationship, error) {
+			return handler.HandleCreate(ctx, globalWorld, sourceEntity, relType, targetEntity, bidirectional)
+		})
 		if err != nil {
 			return fmt.Errorf("creating relationship: %w", err)
 		}
 
-		fmt.Printf("Created relationship: %s\n", rel.ID)
+		if replayed {
+			fmt.Printf("Already created with idempotency key %q: %s\n", idemKey, rel.ID)
+		} else {
+			fmt.Printf("Created relationship: %s\n", rel.ID)
+		}
 		fmt.Printf("  %s -[%s]-> %s\n", sourceEntity, rel.Type, targetEntity)
 		if rel.Bidirectional {
 			fmt.Println("  (bidirectional)")
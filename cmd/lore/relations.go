@@ -2,20 +2,27 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/application/handlers"
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
 type relationsFlags struct {
 	relType string
 	depth   int
 	format  string
+	sort    string
+	limit   int
+	offset  int
+	output  string
 }
 
 func newRelationsCmd() *cobra.Command {
@@ -26,10 +33,25 @@ func newRelationsCmd() *cobra.Command {
 		Short: "List relationships for an entity",
 		Long: `Shows all relationships connected to an entity, with optional filtering.
 
+--sort orders the relationships and --limit and --offset page through
+them:
+  created  Newest relationships first (default)
+  type     Grouped by relationship type
+  target   Alphabetically by the connected entity's name
+
+--output table, wide, json, or go-template=TEMPLATE prints the
+relationships in place of --format: a table of source, type, and target
+(wide adds direction, ID, and creation time), a JSON array, or a Go
+template run on that array.
+
 Examples:
   lore relations Alice
   lore relations Alice --type ally
-  lore relations "Northern Kingdom" --format json`,
+  lore relations "Northern Kingdom" --format json
+  lore relations Alice --output wide
+  lore relations Alice --sort target --limit 20
+  lore relations Alice --sort type --limit 20 --offset 20
+  lore relations history Alice Bob --format mermaid`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRelations(cmd, args, flags)
@@ -39,6 +61,11 @@ Examples:
 	cmd.Flags().StringVar(&flags.relType, "type", "", "Filter by relationship type")
 	cmd.Flags().IntVar(&flags.depth, "depth", 1, "Traversal depth (1-5)")
 	cmd.Flags().StringVar(&flags.format, "format", "tree", "Output format: tree, list, json")
+	cmd.Flags().StringVar(&flags.sort, "sort", "", "Sort order: created, type, target")
+	cmd.Flags().IntVar(&flags.limit, "limit", 0, "Maximum relationships to show (0 = all)")
+	cmd.Flags().IntVar(&flags.offset, "offset", 0, "Number of relationships to skip")
+	addOutputFlag(cmd, &flags.output)
+	cmd.AddCommand(newRelationsHistoryCmd())
 
 	return cmd
 }
@@ -49,19 +76,30 @@ func runRelations(cmd *cobra.Command, args []string, flags relationsFlags) error
 
 	// Validate depth
 	if flags.depth < 1 || flags.depth > 5 {
-		return errors.New("depth must be between 1 and 5")
+		return entities.Errorf(entities.ErrValidation, "depth must be between 1 and 5")
 	}
 
 	// Validate format
 	validFormats := map[string]bool{"tree": true, "list": true, "json": true}
 	if !validFormats[flags.format] {
-		return fmt.Errorf("invalid format: %s (valid: tree, list, json)", flags.format)
+		return entities.Errorf(entities.ErrValidation, "invalid format: %s (valid: tree, list, json)", flags.format)
+	}
+
+	output, err := parseOutput(flags.output)
+	if err != nil {
+		return err
+	}
+	if output.isSet() && cmd.Flags().Changed("format") {
+		return entities.Errorf(entities.ErrValidation, "--format cannot be combined with --output")
 	}
 
 	return withRelationshipHandler(func(handler *handlers.RelationshipHandler) error {
 		opts := handlers.ListOptions{
-			Type:  flags.relType,
-			Depth: flags.depth,
+			Type:   flags.relType,
+			Depth:  flags.depth,
+			Sort:   ports.RelationshipSort(flags.sort),
+			Limit:  flags.limit,
+			Offset: flags.offset,
 		}
 
 		result, err := handler.HandleList(ctx, globalWorld, entityName, opts)
@@ -69,12 +107,24 @@ func runRelations(cmd *cobra.Command, args []string, flags relationsFlags) error
 			return fmt.Errorf("listing relationships: %w", err)
 		}
 
+		if output.isSet() {
+			return renderRelations(os.Stdout, output, result.Relationships)
+		}
+
 		if len(result.Relationships) == 0 {
 			fmt.Printf("No relationships found for entity: %s\n", entityName)
 			return nil
 		}
 
-		return printRelations(entityName, result, flags.format)
+		if err := printRelations(entityName, result, flags.format); err != nil {
+			return err
+		}
+
+		if flags.format != "json" && len(result.Relationships) < result.Total {
+			fmt.Printf("\nShowing %d-%d of %d relationships\n",
+				flags.offset+1, flags.offset+len(result.Relationships), result.Total)
+		}
+		return nil
 	})
 }
 
@@ -91,6 +141,28 @@ func printRelations(entityName string, result *handlers.ListResult, format strin
 	}
 }
 
+// renderRelations writes relationships as --output asks.
+func renderRelations(w io.Writer, output outputFormat, relationships []handlers.RelationshipInfo) error {
+	return render(w, output, relationships, []column[handlers.RelationshipInfo]{
+		{header: "SOURCE", value: func(info handlers.RelationshipInfo) string { return getEntityName(info.SourceEntity) }},
+		{header: "TYPE", value: func(info handlers.RelationshipInfo) string { return string(info.Relationship.Type) }},
+		{header: "TARGET", value: func(info handlers.RelationshipInfo) string { return getEntityName(info.TargetEntity) }},
+		{header: "DIRECTION", wide: true, value: func(info handlers.RelationshipInfo) string {
+			if info.Relationship.Bidirectional {
+				return "both"
+			}
+			return "one-way"
+		}},
+		{header: "ID", wide: true, value: func(info handlers.RelationshipInfo) string { return info.Relationship.ID }},
+		{header: "CREATED", wide: true, value: func(info handlers.RelationshipInfo) string {
+			if info.Relationship.CreatedAt.IsZero() {
+				return ""
+			}
+			return info.Relationship.CreatedAt.Local().Format(time.DateTime)
+		}},
+	})
+}
+
 func printRelationsJSON(result *handlers.ListResult) error {
 	data, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
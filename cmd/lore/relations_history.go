@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// Times in Mermaid gantt charts: ganttTimeLayout writes them as their
+// dateFormat reads them, and the axis shows dates.
+const (
+	ganttTimeLayout = "2006-01-02 15:04"
+	ganttDateFormat = "YYYY-MM-DD HH:mm"
+	ganttAxisFormat = "%Y-%m-%d"
+)
+
+func newRelationsHistoryCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "history <entity> <entity>",
+		Short: "Show how the relationship between two entities changed",
+		Long: `Reconstructs how the relationship between two entities changed over the
+books and sessions ingested: every fact linking them, the relationship's own
+among them, with each correction, retcon, and update from its history,
+oldest first.
+
+Formats:
+  list     A chronological list, with each change's source and reason (default)
+  mermaid  A Mermaid gantt chart, a section per source, each state running
+           until the next change
+  json     The relationship and its events
+
+Examples:
+  lore relations history Frodo Sam
+  lore relations history Frodo Gollum --format mermaid > frodo-gollum.mmd`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRelationsHistory(cmd, args, format)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "list", "Output format: list, mermaid, json")
+
+	return cmd
+}
+
+func runRelationsHistory(cmd *cobra.Command, args []string, format string) error {
+	ctx := cmd.Context()
+
+	switch format {
+	case "list", "mermaid", "json":
+	default:
+		return entities.Errorf(entities.ErrValidation, "invalid format: %s (valid: list, mermaid, json)", format)
+	}
+
+	return withRelationshipHandler(func(handler *handlers.RelationshipHandler) error {
+		history, err := handler.HandleHistory(ctx, globalWorld, args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("reading relationship history: %w", err)
+		}
+
+		switch format {
+		case "json":
+			data, err := json.MarshalIndent(history, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		case "mermaid":
+			writeHistoryGantt(os.Stdout, history, time.Now())
+		default:
+			writeHistoryList(os.Stdout, history)
+		}
+		return nil
+	})
+}
+
+// writeHistoryList writes the events of history as a chronological list.
+func writeHistoryList(w io.Writer, history *services.RelationshipHistory) {
+	fmt.Fprintf(w, "History of %s and %s\n", history.Source.Name, history.Target.Name)
+	if rel := history.Relationship; rel != nil {
+		fmt.Fprintf(w, "Current relationship: %s\n", describeRelationship(history, rel))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 60))
+
+	if len(history.Events) == 0 {
+		fmt.Fprintln(w, "No facts link them.")
+		return
+	}
+
+	for i := range history.Events {
+		event := &history.Events[i]
+		fact := &event.Fact
+		fmt.Fprintf(w, "%s  %-10s %s %s %s\n",
+			event.Time.Local().Format(time.DateTime), event.Change, fact.Subject, fact.Predicate, fact.Object)
+		if fact.SourceFile != "" {
+			fmt.Fprintf(w, "  Source: %s\n", fact.SourceFile)
+		}
+		if event.Reason != "" {
+			fmt.Fprintf(w, "  Reason: %s\n", event.Reason)
+		}
+		fmt.Fprintf(w, "  Fact: %s (version %d)\n", event.FactID, event.Version)
+	}
+}
+
+// describeRelationship returns rel in the form printRelationsList uses.
+func describeRelationship(history *services.RelationshipHistory, rel *entities.Relationship) string {
+	source, target := history.Source.Name, history.Target.Name
+	if rel.SourceEntityID == history.Target.ID {
+		source, target = target, source
+	}
+	direction := "->"
+	if rel.Bidirectional {
+		direction = "<->"
+	}
+	return fmt.Sprintf("%s %s [%s] %s %s", source, direction, rel.Type, direction, target)
+}
+
+// writeHistoryGantt writes the events of history as a Mermaid gantt chart,
+// with a section per source file in order of first appearance. Each state
+// runs until the next version of its fact, or until now if it is current;
+// deletions and states replaced at once are milestones.
+func writeHistoryGantt(w io.Writer, history *services.RelationshipHistory, now time.Time) {
+	fmt.Fprintln(w, "gantt")
+	fmt.Fprintf(w, "    title %s and %s\n", ganttText(history.Source.Name), ganttText(history.Target.Name))
+	fmt.Fprintf(w, "    dateFormat %s\n", ganttDateFormat)
+	fmt.Fprintf(w, "    axisFormat %s\n", ganttAxisFormat)
+
+	var sources []string
+	bySource := make(map[string][]int)
+	for i := range history.Events {
+		source := history.Events[i].Fact.SourceFile
+		if source == "" {
+			source = "unknown source"
+		}
+		if _, ok := bySource[source]; !ok {
+			sources = append(sources, source)
+		}
+		bySource[source] = append(bySource[source], i)
+	}
+
+	for _, source := range sources {
+		fmt.Fprintf(w, "    section %s\n", ganttText(source))
+		for _, i := range bySource[source] {
+			event := &history.Events[i]
+			fact := &event.Fact
+			name := ganttText(fmt.Sprintf("%s %s %s", fact.Subject, fact.Predicate, fact.Object))
+			start := event.Time.Local().Format(ganttTimeLayout)
+
+			end := now
+			if event.Until != nil {
+				end = *event.Until
+			}
+			if event.Change == entities.ChangeDeletion || !end.After(event.Time) {
+				mark := ""
+				if event.Change == entities.ChangeDeletion {
+					mark = " (deleted)"
+				}
+				fmt.Fprintf(w, "    %s%s :milestone, e%d, %s, 0d\n", name, mark, i+1, start)
+				continue
+			}
+			fmt.Fprintf(w, "    %s :e%d, %s, %s\n", name, i+1, start, end.Local().Format(ganttTimeLayout))
+		}
+	}
+}
+
+// ganttText keeps text from breaking a line of a gantt chart: colons end a
+// task name, and # and ; start entities and comments.
+func ganttText(text string) string {
+	text = strings.NewReplacer(":", " ", "#", " ", ";", ",").Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func testHistory() *services.RelationshipHistory {
+	day := time.Date(2024, 1, 1, 9, 0, 0, 0, time.Local)
+	retcon := day.AddDate(0, 0, 5)
+	return &services.RelationshipHistory{
+		Source:       &entities.Entity{ID: "e1", Name: "Sam"},
+		Target:       &entities.Entity{ID: "e2", Name: "Frodo"},
+		Relationship: &entities.Relationship{SourceEntityID: "e2", TargetEntityID: "e1", Type: entities.RelationAlly, Bidirectional: true},
+		Events: []services.RelationshipEvent{
+			{Time: day, FactID: "f1", Version: 1, Change: entities.ChangeCreation, Until: &retcon,
+				Fact: entities.Fact{Subject: "Sam", Predicate: "serves", Object: "Frodo", SourceFile: "book1.md"}},
+			{Time: day.AddDate(0, 0, 2), FactID: "f2", Version: 1, Change: entities.ChangeCreation,
+				Fact: entities.Fact{Subject: "Frodo", Predicate: "trusts", Object: "Sam", SourceFile: "book2.md"}},
+			{Time: retcon, FactID: "f1", Version: 2, Change: entities.ChangeRetcon, Reason: "friends, not servants",
+				Fact: entities.Fact{Subject: "Sam", Predicate: "befriends", Object: "Frodo: truly", SourceFile: "book1.md"}},
+		},
+	}
+}
+
+func TestWriteHistoryList(t *testing.T) {
+	var buf bytes.Buffer
+	writeHistoryList(&buf, testHistory())
+
+	out := buf.String()
+	assert.Contains(t, out, "Current relationship: Frodo <-> [ally] <-> Sam\n")
+	assert.Contains(t, out, "2024-01-01 09:00:00  creation   Sam serves Frodo\n  Source: book1.md\n")
+	assert.Contains(t, out, "2024-01-06 09:00:00  retcon     Sam befriends Frodo: truly\n  Source: book1.md\n  Reason: friends, not servants\n")
+}
+
+func TestWriteHistoryGantt(t *testing.T) {
+	var buf bytes.Buffer
+	writeHistoryGantt(&buf, testHistory(), time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local))
+
+	assert.Equal(t, ""+
+		"gantt\n"+
+		"    title Sam and Frodo\n"+
+		"    dateFormat YYYY-MM-DD HH:mm\n"+
+		"    axisFormat %Y-%m-%d\n"+
+		"    section book1.md\n"+
+		"    Sam serves Frodo :e1, 2024-01-01 09:00, 2024-01-06 09:00\n"+
+		"    Sam befriends Frodo truly :e3, 2024-01-06 09:00, 2024-02-01 00:00\n"+
+		"    section book2.md\n"+
+		"    Frodo trusts Sam :e2, 2024-01-03 09:00, 2024-02-01 00:00\n",
+		buf.String())
+}
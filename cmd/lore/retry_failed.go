@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func newRetryFailedCmd() *cobra.Command {
+	var (
+		source string
+		list   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry-failed",
+		Short: "Retry extracting chunks that failed during ingest",
+		Long: `When extracting the facts of a chunk fails during ingest, because the LLM
+call fails or its reply cannot be parsed, the chunk is quarantined and the
+rest of the file is ingested. This extracts the quarantined chunks again.
+Chunks that succeed are released; those that fail again stay quarantined
+with the new error.
+
+Ingesting a source again replaces its quarantined chunks.
+
+Examples:
+  lore retry-failed -w myworld --list
+  lore retry-failed -w myworld
+  lore retry-failed -w myworld --source chapter1.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRetryFailed(cmd, source, list)
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", "", "Only retry chunks of this source file")
+	cmd.Flags().BoolVar(&list, "list", false, "List quarantined chunks without retrying them")
+
+	return cmd
+}
+
+func runRetryFailed(cmd *cobra.Command, source string, list bool) error {
+	ctx := cmd.Context()
+
+	if source != "" {
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return fmt.Errorf("resolving path: %w", err)
+		}
+		source = abs
+	}
+
+	return withInternalDeps(func(d *internalDeps) error {
+		if list {
+			return listFailedChunks(ctx, d, source)
+		}
+
+		sources, err := sourceRules(d)
+		if err != nil {
+			return err
+		}
+		result, err := d.IngestHandler.HandleRetryFailed(ctx, source, &handlers.IngestOptions{
+			CheckConsistency: true,
+			ReviewThreshold:  d.Config.Review.Threshold,
+			Retrieval:        retrieval(d),
+			Sources:          sources,
+		})
+		if err != nil {
+			return err
+		}
+		if result.Retried == 0 {
+			fmt.Println("No quarantined chunks.")
+			return nil
+		}
+
+		fmt.Printf("Retried %d chunk(s): %d recovered, %d still failing\n",
+			result.Retried, result.Retried-len(result.Failed), len(result.Failed))
+		fmt.Printf("Saved %d facts to database\n", result.FactsCount)
+		if len(result.Issues) > 0 {
+			fmt.Println()
+			displayConsistencyIssues(result.Issues)
+			displayConflictsRecorded(len(result.Issues))
+		}
+		displayPendingReview(result.PendingCount, false)
+		if len(result.Failed) > 0 {
+			fmt.Println()
+			displayFailedChunks(os.Stdout, result.Failed)
+		}
+		return nil
+	})
+}
+
+// listFailedChunks lists the quarantined chunks of source, or of every
+// source if it is empty.
+func listFailedChunks(ctx context.Context, d *internalDeps, source string) error {
+	chunks, err := d.sourceService.Quarantined(ctx, source)
+	if err != nil {
+		return err
+	}
+	if len(chunks) == 0 {
+		fmt.Println("No quarantined chunks.")
+		return nil
+	}
+	displayFailedChunks(os.Stdout, chunks)
+	return nil
+}
+
+// displayFailedChunks writes the quarantined chunks as a table with their
+// latest error.
+func displayFailedChunks(out io.Writer, chunks []entities.FailedChunk) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tCHUNK\tATTEMPTS\tERROR\t")
+	for i := range chunks {
+		c := &chunks[i]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t\n", c.Source, c.Index, c.Attempts, firstLine(c.Error))
+	}
+	w.Flush()
+}
+
+// firstLine returns s up to its first line break.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
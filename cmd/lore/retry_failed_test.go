@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestDisplayFailedChunks(t *testing.T) {
+	var out bytes.Buffer
+	displayFailedChunks(&out, []entities.FailedChunk{
+		{Source: "ch1.md", Index: 4, Attempts: 2, Error: "parsing response: invalid character\nraw: {"},
+	})
+	assert.Contains(t, out.String(), "SOURCE  CHUNK  ATTEMPTS  ERROR")
+	assert.Contains(t, out.String(), "ch1.md  4      2         parsing response: invalid character")
+	assert.NotContains(t, out.String(), "raw:")
+}
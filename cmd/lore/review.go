@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newReviewCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "List facts held for review",
+		Long: `Lists facts held for review because their confidence was below the
+review threshold at ingest time. Pending facts are stored but not searchable
+until they are accepted.
+
+The threshold is review.threshold in the config (0 disables review), or
+--review-below on 'lore ingest'.
+
+Examples:
+  lore review -w myworld
+  lore review accept <id> <id> -w myworld
+  lore review edit <id> --object "the Shire" -w myworld
+  lore review reject <id> -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withReviewHandler(func(handler *handlers.ReviewHandler) error {
+				facts, err := handler.HandleList(ctx, limit)
+				if err != nil {
+					return err
+				}
+
+				if len(facts) == 0 {
+					fmt.Println("No facts pending review.")
+					return nil
+				}
+
+				fmt.Printf("%d fact(s) pending review:\n\n", len(facts))
+				for i := range facts {
+					displayFact(os.Stdout, &facts[i], 0)
+				}
+				fmt.Println("Accept, edit, or reject with 'lore review accept|edit|reject <id>'.")
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", DefaultListLimit, "Maximum number of facts to list")
+
+	cmd.AddCommand(
+		newReviewAcceptCmd(),
+		newReviewEditCmd(),
+		newReviewRejectCmd(),
+	)
+
+	return cmd
+}
+
+func newReviewAcceptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "accept <id>...",
+		Short: "Make pending facts searchable",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withReviewHandler(func(handler *handlers.ReviewHandler) error {
+				accepted, err := handler.HandleAccept(ctx, args)
+				for i := range accepted {
+					fmt.Printf("Accepted %s: %s %s %s\n", accepted[i].ID, accepted[i].Subject, accepted[i].Predicate, accepted[i].Object)
+				}
+				return err
+			})
+		},
+	}
+}
+
+type reviewEditFlags struct {
+	subject   string
+	predicate string
+	object    string
+	context   string
+}
+
+func newReviewEditCmd() *cobra.Command {
+	var flags reviewEditFlags
+
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Correct a pending fact and accept it",
+		Long: `Corrects a pending fact and accepts it. Only the fields given are changed;
+the fact is re-embedded so searches match the corrected text.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			edit := services.FactEdit{
+				Subject:   flags.subject,
+				Predicate: flags.predicate,
+				Object:    flags.object,
+				Context:   flags.context,
+			}
+			if edit.IsEmpty() {
+				return entities.Errorf(entities.ErrValidation, "nothing to edit: set --subject, --predicate, --object, or --context")
+			}
+			ctx := cmd.Context()
+
+			return withReviewHandler(func(handler *handlers.ReviewHandler) error {
+				fact, err := handler.HandleEdit(ctx, args[0], edit)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("Accepted %s: %s %s %s\n", fact.ID, fact.Subject, fact.Predicate, fact.Object)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&flags.subject, "subject", "", "Corrected subject")
+	cmd.Flags().StringVar(&flags.predicate, "predicate", "", "Corrected predicate")
+	cmd.Flags().StringVar(&flags.object, "object", "", "Corrected object")
+	cmd.Flags().StringVar(&flags.context, "context", "", "Corrected context")
+
+	return cmd
+}
+
+func newReviewRejectCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reject <id>...",
+		Short: "Delete pending facts",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withReviewHandler(func(handler *handlers.ReviewHandler) error {
+				rejected, err := handler.HandleReject(ctx, args)
+				for _, id := range rejected {
+					fmt.Printf("Rejected %s\n", id)
+				}
+				return err
+			})
+		},
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/api"
+	"github.com/ersonp/lore-core/internal/application/demo"
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/readiness"
+	"github.com/ersonp/lore-core/internal/application/scheduler"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/memory"
+)
+
+// Names of scheduled jobs in status output.
+const (
+	snapshotJobName  = "snapshot"
+	healthJobName    = "health"
+	sweepJobName     = "sweep"
+	changesJobName   = "changes"
+	readCacheJobName = "read_cache"
+	refreshJobName   = "refresh"
+)
+
+// serveLong is the help of 'lore serve', a format taking the readiness
+// check interval and the demo's rate and query limits.
+const serveLong = `Starts an HTTP API for the current world and runs background jobs.
+
+Endpoints:
+  GET  /healthz         Liveness: the server is up
+  GET  /readyz          Readiness: Qdrant, SQLite, and the LLM and embedder
+                        credentials were reachable at the latest check
+  GET  /api/status      World, background job, entity cache, and readiness status
+  GET  /api/query       Search facts (q, limit, mode, type)
+  GET  /api/entities    List entities (limit, offset) or find them by name (q)
+  GET  /api/entities/{name}
+                        An entity with the facts about it and its relationships
+  GET  /api/facts/{id}  A fact, with its revision as the ETag
+  PATCH /api/facts/{id} Edit a fact's subject, predicate, object, or context
+  GET  /api/snapshots   List snapshots and snapshot job status
+  POST /api/snapshots   Create a snapshot now
+  POST /api/ingest/stream
+                        Ingest text, streaming progress as server-sent events
+
+Snapshots are created on the cron schedule in serve.snapshots.schedule
+and pruned to serve.snapshots.keep. Set the schedule to "" to disable.
+World health is recorded on the schedule in serve.health.schedule for
+'lore stats health'.
+
+Facts added since the last sweep are checked for contradictions on the
+schedule in serve.sweep.schedule (nightly by default), as 'lore check'
+would check them. New conflicts are recorded and, if notify.webhook or
+notify.email is configured, sent there as a digest.
+
+If serve.changes.schedule is set, the world's change feed is appended to
+serve.changes.path on that schedule, as 'lore export --changes' would
+write it, resuming from the cursor kept in serve.changes.path plus
+".cursor".
+
+If the world has an entity index, made by 'lore entities search
+--semantic', entities whose facts changed since they were last embedded
+are embedded again on serve.refresh.schedule (hourly by default), at
+most serve.refresh.max_entities at a time, as 'lore refresh' would.
+
+Once 'lore tokens create' has made a token, every request except /healthz
+and /readyz must send one as "Authorization: Bearer <token>". Read tokens
+may only make GET requests.
+
+POST /api/ingest/stream takes a JSON body such as {"text": "...",
+"source": "chapter1.md", "check_consistency": true} and answers with
+server-sent events as the text is ingested: "chunk" with the facts of
+each chunk as it is extracted, "issue" for each consistency issue, then
+"done" with the totals or "error". Set "check_only" to check without
+saving.
+
+PATCH /api/facts/{id} takes a JSON body with the fields to change and
+must send the ETag of the fact it edited as If-Match. If the fact has
+changed since, nothing is saved and it answers 412 Precondition Failed
+with both versions, "current" and "attempted", to resolve the conflict
+from.
+
+Backends are checked every %s. While a check fails, the service is
+degraded: /readyz answers 503 and other API requests fail at once with
+503 and the failed checks, instead of each waiting out its own timeout.
+
+With --ui, also serves a web UI at / for browsing the world: search,
+entity pages with a relationship graph, and a drop zone for ingesting
+files. It asks for a token if the server needs one.
+
+With --read-cache, serves as a query-only instance: facts are copied
+into memory at start and refreshed on serve.read_cache.schedule (every
+minute by default), and queries, entities, and facts are answered from
+the copy instead of Qdrant. Endpoints that write are not served, and
+scheduled jobs are left to the primary instance. Every API response
+carries the copy's age in seconds as Age, when it was taken as
+X-Lore-Synced-At, and serve.read_cache.max_staleness as
+X-Lore-Max-Staleness; once refreshing has failed for longer than that,
+the instance is degraded until a refresh succeeds.
+
+With --demo, serves a bundled Middle-earth sample world from memory
+instead. No config, world, API keys, or Qdrant are needed. Only status,
+queries, and entities are served, each client IP may make %d requests a minute, and
+queries return at most %d facts.
+
+Examples:
+  lore serve -w myworld
+  lore serve -w myworld --addr :8080
+  lore serve -w myworld --ui
+  lore serve -w myworld --read-cache --addr :8081
+  lore serve --demo`
+
+func newServeCmd() *cobra.Command {
+	var (
+		addr      string
+		demoMode  bool
+		ui        bool
+		readCache bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the world over HTTP",
+		Long:  fmt.Sprintf(serveLong, readiness.DefaultInterval, demo.RateLimit, demo.MaxQueryLimit),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if demoMode {
+				if readCache {
+					return entities.Errorf(entities.ErrValidation, "--read-cache cannot be combined with --demo")
+				}
+				if !cmd.Flags().Changed("addr") {
+					addr = config.Default().Serve.Addr
+				}
+				return runDemo(cmd.Context(), addr, ui)
+			}
+			return runServe(cmd.Context(), cmd.Flags().Changed("addr"), addr, ui, readCache)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "", "Listen address (default from serve.addr)")
+	cmd.Flags().BoolVar(&demoMode, "demo", false, "Serve the bundled sample world with strict rate limits")
+	cmd.Flags().BoolVar(&ui, "ui", false, "Serve a web UI for browsing the world at /")
+	cmd.Flags().BoolVar(&readCache, "read-cache", false, "Answer reads from an in-memory copy of the facts; serve no writes")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, addrSet bool, addr string, ui, readCache bool) error {
+	return withInternalDeps(func(d *internalDeps) error {
+		if !addrSet {
+			addr = d.Config.Serve.Addr
+		}
+
+		// A read cache answers from its copy of the facts in place of Qdrant
+		var replica *memory.Replica
+		if readCache {
+			var err error
+			if replica, err = newReadCache(ctx, d); err != nil {
+				return err
+			}
+		}
+
+		opts, err := serveOptions(d, replica)
+		if err != nil {
+			return err
+		}
+
+		jobs := scheduler.New()
+		if replica != nil {
+			err = scheduleReadCache(jobs, d, replica, opts.Cards)
+		} else {
+			err = scheduleJobs(jobs, d, opts.Snapshots)
+		}
+		if err != nil {
+			return err
+		}
+		opts.Jobs = jobs.Status
+
+		monitor, err := newServeMonitor(ctx, d, replica)
+		if err != nil {
+			return err
+		}
+		opts.Readiness = monitor
+		opts.UI = ui
+
+		fmt.Printf("Serving world %s on http://%s\n", globalWorld, addr)
+		if ui {
+			fmt.Printf("Web UI at http://%s/\n", addr)
+		}
+		return serveWithJobs(ctx, api.NewServer(&opts), addr, jobs, monitor)
+	})
+}
+
+// serveOptions assembles the handlers and settings the server answers
+// with, reading from replica instead of Qdrant if it is set. Jobs,
+// Readiness and UI are left for the caller.
+func serveOptions(d *internalDeps, replica *memory.Replica) (api.Options, error) {
+	snapshotHandler, err := newSnapshotHandler(d)
+	if err != nil {
+		return api.Options{}, err
+	}
+	auth, err := config.LoadAuth(d.configDir)
+	if err != nil {
+		return api.Options{}, err
+	}
+	if !auth.Enabled() {
+		fmt.Println("Warning: no API tokens; anyone who can reach the server can use it (see 'lore tokens create')")
+	}
+	sources, err := sourceRules(d)
+	if err != nil {
+		return api.Options{}, err
+	}
+
+	opts := api.Options{
+		World:           globalWorld,
+		Query:           d.QueryHandler,
+		Snapshots:       snapshotHandler,
+		Ingest:          d.IngestHandler,
+		Entities:        newEntityHandler(d),
+		Relationships:   newRelationshipHandler(d),
+		Cards:           newCardHandler(d),
+		Facts:           newFactHandler(d),
+		Idempotency:     services.NewIdempotencyService(d.relationalDB, 0),
+		SnapshotKeep:    d.Config.Serve.Snapshots.Keep,
+		EntityCache:     d.relationalDB.Stats,
+		ReviewThreshold: d.Config.Review.Threshold,
+		Retrieval:       retrieval(d),
+		Sources:         sources,
+		Auth:            tokenAuthorizer{auth},
+		ReadOnly:        replica != nil,
+	}
+	if replica != nil {
+		opts.ReadCache = replica.Status
+	}
+	return opts, nil
+}
+
+// newServeMonitor creates the readiness monitor of the world's backends.
+// A read cache checks its copy of the facts in place of Qdrant.
+func newServeMonitor(ctx context.Context, d *internalDeps, replica *memory.Replica) (*readiness.Monitor, error) {
+	world, err := d.container.World(ctx, globalWorld)
+	if err != nil {
+		return nil, err
+	}
+	checks := d.container.ReadinessChecks(world)
+	if replica != nil {
+		checks = readCacheChecks(checks, replica)
+	}
+	return readiness.NewMonitor(checks, readiness.DefaultTimeout), nil
+}
+
+// serveWithJobs serves on addr until ctx is canceled, running the
+// scheduled jobs and readiness checks alongside, and waits for both to
+// stop before returning.
+func serveWithJobs(ctx context.Context, server *api.Server, addr string, jobs *scheduler.Scheduler, monitor *readiness.Monitor) error {
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Run(jobsCtx)
+		close(jobsDone)
+	}()
+	monitorDone := make(chan struct{})
+	go func() {
+		monitor.Run(jobsCtx, readiness.DefaultInterval)
+		close(monitorDone)
+	}()
+
+	err := server.ListenAndServe(ctx, addr)
+
+	stopJobs()
+	<-jobsDone
+	<-monitorDone
+	return err
+}
+
+// scheduleJobs registers the background jobs configured under serve.
+func scheduleJobs(jobs *scheduler.Scheduler, d *internalDeps, snapshotHandler *handlers.SnapshotHandler) error {
+	serveCfg := d.Config.Serve
+	if schedule := serveCfg.Snapshots.Schedule; schedule != "" {
+		if err := addSnapshotJob(jobs, schedule, snapshotHandler, serveCfg.Snapshots.Keep); err != nil {
+			return fmt.Errorf("invalid serve.snapshots.schedule: %w", err)
+		}
+	}
+	if schedule := serveCfg.Health.Schedule; schedule != "" {
+		if err := addHealthJob(jobs, schedule, d); err != nil {
+			return fmt.Errorf("invalid serve.health.schedule: %w", err)
+		}
+	}
+	if schedule := serveCfg.Sweep.Schedule; schedule != "" {
+		if err := addSweepJob(jobs, schedule, d); err != nil {
+			return fmt.Errorf("invalid serve.sweep.schedule: %w", err)
+		}
+	}
+	if schedule := serveCfg.Changes.Schedule; schedule != "" {
+		if err := addChangesJob(jobs, schedule, d); err != nil {
+			return fmt.Errorf("invalid serve.changes.schedule: %w", err)
+		}
+	}
+	if schedule := serveCfg.Refresh.Schedule; schedule != "" {
+		if err := addRefreshJob(jobs, schedule, d); err != nil {
+			return fmt.Errorf("invalid serve.refresh.schedule: %w", err)
+		}
+	}
+	return nil
+}
+
+// addSnapshotJob snapshots the world on schedule, keeping the newest keep.
+func addSnapshotJob(jobs *scheduler.Scheduler, schedule string, snapshotHandler *handlers.SnapshotHandler, keep int) error {
+	return jobs.Add(snapshotJobName, schedule, func(ctx context.Context) error {
+		result, err := snapshotHandler.HandleCreate(ctx, keep)
+		if err != nil {
+			log.Printf("scheduled snapshot failed: %v", err)
+			return err
+		}
+		log.Printf("created snapshot %s, pruned %d", result.Snapshot.Name, len(result.Pruned))
+		return nil
+	})
+}
+
+// addHealthJob records the world's health on schedule.
+func addHealthJob(jobs *scheduler.Scheduler, schedule string, d *internalDeps) error {
+	healthHandler := newHealthHandler(d)
+	opts := healthOptions(d)
+	return jobs.Add(healthJobName, schedule, func(ctx context.Context) error {
+		sample, err := healthHandler.HandleRecord(ctx, opts)
+		if err != nil {
+			log.Printf("scheduled health measurement failed: %v", err)
+			return err
+		}
+		log.Printf("recorded world health %.1f", sample.Score)
+		return nil
+	})
+}
+
+// addSweepJob checks the facts added since the last sweep for
+// contradictions on schedule.
+func addSweepJob(jobs *scheduler.Scheduler, schedule string, d *internalDeps) error {
+	sweepHandler := newSweepHandler(d)
+	opts := &services.SweepOptions{World: globalWorld, Retrieval: retrieval(d)}
+	return jobs.Add(sweepJobName, schedule, func(ctx context.Context) error {
+		result, err := sweepHandler.HandleSweep(ctx, opts)
+		if err != nil {
+			log.Printf("scheduled consistency sweep failed: %v", err)
+			return err
+		}
+		log.Printf("swept %d facts added since %s: %d new conflicts", result.Checked, result.Since.Format(time.RFC3339), len(result.New))
+		return nil
+	})
+}
+
+// addChangesJob appends the world's change feed to serve.changes.path on
+// schedule.
+func addChangesJob(jobs *scheduler.Scheduler, schedule string, d *internalDeps) error {
+	feed := services.NewChangeFeedService(d.relationalDB)
+	path := d.Config.Serve.Changes.Path
+	return jobs.Add(changesJobName, schedule, func(ctx context.Context) error {
+		written, err := appendChanges(ctx, feed, path)
+		if err != nil {
+			log.Printf("scheduled change export failed: %v", err)
+			return err
+		}
+		log.Printf("appended %d changes to %s", written, path)
+		return nil
+	})
+}
+
+// addRefreshJob embeds stale entity summaries again on schedule, in
+// worlds with an entity index.
+func addRefreshJob(jobs *scheduler.Scheduler, schedule string, d *internalDeps) error {
+	search := newEntitySearchService(d)
+	opts := services.EntityRefreshOptions{Limit: d.Config.Serve.Refresh.MaxEntities}
+	return jobs.Add(refreshJobName, schedule, func(ctx context.Context) error {
+		// Only worlds searched semantically have summaries to keep fresh.
+		indexed, err := search.Indexed(ctx)
+		if err != nil || !indexed {
+			return err
+		}
+		stats, err := search.Refresh(ctx, globalWorld, opts)
+		if err != nil {
+			log.Printf("scheduled entity refresh failed: %v", err)
+			return err
+		}
+		log.Printf("refreshed %d entity summaries, %d still stale, %d removed", stats.Embedded, stats.Stale, stats.Removed)
+		return nil
+	})
+}
+
+// runDemo serves the bundled sample world until ctx is canceled, with the
+// web UI if ui is set.
+func runDemo(ctx context.Context, addr string, ui bool) (err error) {
+	d, err := demo.New(ctx)
+	if err != nil {
+		return fmt.Errorf("loading demo world: %w", err)
+	}
+	defer func() {
+		if cerr := d.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	fmt.Printf("Serving demo world %s (%d facts, %d entities) on http://%s\n", demo.World, d.Facts, d.Entities, addr)
+	opts := d.Options()
+	opts.UI = ui
+	if ui {
+		fmt.Printf("Web UI at http://%s/\n", addr)
+	}
+	return api.NewServer(&opts).ListenAndServe(ctx, addr)
+}
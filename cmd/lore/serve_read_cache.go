@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/readiness"
+	"github.com/ersonp/lore-core/internal/application/scheduler"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/memory"
+)
+
+// newReadCache copies the current world's facts into memory and has d
+// answer reads from the copy.
+func newReadCache(ctx context.Context, d *internalDeps) (*memory.Replica, error) {
+	alias, err := d.Worlds.GetCollection(globalWorld)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := d.container.CollectionAdmin()
+	if err != nil {
+		return nil, err
+	}
+
+	replica := memory.NewReplica(admin, alias, d.Config.Serve.ReadCache.MaxStaleness)
+	if err := replica.Sync(ctx); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Read cache holds %d facts\n", replica.Status().Facts)
+
+	d.vectorDB = replica
+	d.QueryHandler = handlers.NewQueryHandler(services.NewQueryService(d.embedder, replica, d.relationalDB))
+	return replica, nil
+}
+
+// scheduleReadCache refreshes the read cache on serve.read_cache.schedule,
+// dropping the entities and cards cached from the previous copy.
+func scheduleReadCache(jobs *scheduler.Scheduler, d *internalDeps, replica *memory.Replica, cards *handlers.CardHandler) error {
+	err := jobs.Add(readCacheJobName, d.Config.Serve.ReadCache.Schedule, func(ctx context.Context) error {
+		if err := replica.Sync(ctx); err != nil {
+			log.Printf("read cache refresh failed: %v", err)
+			return err
+		}
+		d.relationalDB.Purge()
+		cards.Invalidate()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid serve.read_cache.schedule: %w", err)
+	}
+	return nil
+}
+
+// readCacheChecks replaces the Qdrant readiness check with the read cache's
+// staleness, since the copy is answered from while Qdrant is away.
+func readCacheChecks(checks []readiness.Check, replica *memory.Replica) []readiness.Check {
+	kept := make([]readiness.Check, 0, len(checks))
+	for _, check := range checks {
+		if check.Name != "qdrant" {
+			kept = append(kept, check)
+		}
+	}
+	return append(kept, readiness.Check{Name: "read_cache", Check: replica.Check})
+}
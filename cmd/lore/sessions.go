@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/sessions"
+)
+
+func newSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "List and replay logged watch sessions",
+		Long: `Lists and replays the session logs 'lore watch' writes to .lore/sessions/.
+
+Each log is a JSON Lines file recording every input, the facts and conflicts
+found in it, and which facts were saved or discarded.`,
+	}
+
+	cmd.AddCommand(
+		newSessionsListCmd(),
+		newSessionsReplayCmd(),
+	)
+
+	return cmd
+}
+
+func newSessionsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List logged sessions, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configDir, err := findConfigDir()
+			if err != nil {
+				return err
+			}
+
+			paths, err := sessions.List(config.SessionDir(configDir))
+			if err != nil {
+				return err
+			}
+			if len(paths) == 0 {
+				fmt.Println("No sessions logged.")
+				return nil
+			}
+
+			for _, path := range paths {
+				events, err := sessions.Read(path)
+				if err != nil {
+					fmt.Printf("%s: %v\n", filepath.Base(path), err)
+					continue
+				}
+				displaySessionSummary(sessions.Summarize(path, events))
+			}
+			return nil
+		},
+	}
+}
+
+func displaySessionSummary(s sessions.Summary) {
+	fmt.Printf("%s\n", filepath.Base(s.Path))
+	fmt.Printf("  Started: %s (world %s, source %s)\n", s.Started.Local().Format(time.DateTime), s.World, s.Source)
+	fmt.Printf("  %d inputs, %d conflicts found, %d facts saved, %d discarded\n", s.Inputs, s.Conflicts, s.Saved, s.Discarded)
+}
+
+func newSessionsReplayCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay <file>",
+		Short: "Re-run a session's inputs against the current database",
+		Long: `Re-runs every input of a logged session through fact extraction and
+consistency checking against the current database, and compares the results
+with what the session originally found. Nothing is saved.
+
+The file is a path, or the name of a log in .lore/sessions/.
+
+Examples:
+  lore sessions replay 20240301-120000-1234.jsonl -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withInternalDeps(func(d *internalDeps) error {
+				path := resolveSessionPath(d.configDir, args[0])
+				events, err := sessions.Read(path)
+				if err != nil {
+					return err
+				}
+				return replaySession(ctx, d.extractionService, retrieval(d), events)
+			})
+		},
+	}
+}
+
+// resolveSessionPath returns name as a path if it exists, otherwise as a
+// log in the sessions directory.
+func resolveSessionPath(configDir, name string) string {
+	if _, err := os.Stat(name); err == nil || filepath.Base(name) != name {
+		return name
+	}
+	return filepath.Join(config.SessionDir(configDir), name)
+}
+
+// sessionInput is an input from a session log with what it originally found.
+type sessionInput struct {
+	Number    int
+	Text      string
+	Facts     int
+	Conflicts int
+}
+
+// sessionInputs collects the inputs of a session log, and its source.
+func sessionInputs(events []sessions.Event) ([]sessionInput, string) {
+	var (
+		inputs []sessionInput
+		source string
+	)
+	byNumber := make(map[int]int)
+	for i := range events {
+		switch events[i].Type {
+		case sessions.EventStart:
+			source = events[i].Source
+		case sessions.EventInput:
+			byNumber[events[i].Input] = len(inputs)
+			inputs = append(inputs, sessionInput{Number: events[i].Input, Text: events[i].Text})
+		case sessions.EventExtracted:
+			if j, ok := byNumber[events[i].Input]; ok {
+				inputs[j].Facts = len(events[i].Facts)
+				inputs[j].Conflicts = len(events[i].Conflicts)
+			}
+		}
+	}
+	return inputs, source
+}
+
+func replaySession(ctx context.Context, extractionService *services.ExtractionService, retrieval services.Retrieval, events []sessions.Event) error {
+	inputs, source := sessionInputs(events)
+	if len(inputs) == 0 {
+		fmt.Println("Session has no inputs to replay.")
+		return nil
+	}
+
+	var facts, conflicts, failed int
+	for _, input := range inputs {
+		fmt.Printf("Input %d:\n", input.Number)
+		fmt.Printf("  %s\n\n", input.Text)
+
+		result, err := checkWatchInput(ctx, extractionService, retrieval, input.Text, source)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			fmt.Printf("Error: %v\n\n", err)
+			failed++
+			continue
+		}
+
+		if len(result.Facts) == 0 {
+			fmt.Println("No facts found in input.")
+		} else {
+			displayWatchResult(result)
+		}
+		fmt.Printf("\nOriginally: %d facts, %d conflicts. Now: %d facts, %d conflicts.\n\n",
+			input.Facts, input.Conflicts, len(result.Facts), len(result.Issues))
+
+		facts += len(result.Facts)
+		conflicts += len(result.Issues)
+	}
+
+	fmt.Printf("Replayed %d inputs: %d facts, %d conflicts found (nothing saved)\n", len(inputs)-failed, facts, conflicts)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d inputs failed", failed, len(inputs))
+	}
+	return nil
+}
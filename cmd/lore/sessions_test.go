@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/infrastructure/sessions"
+)
+
+func TestSessionInputs(t *testing.T) {
+	events := []sessions.Event{
+		{Type: sessions.EventStart, Source: "notes"},
+		{Type: sessions.EventInput, Input: 1, Text: "Frodo has green eyes."},
+		{Type: sessions.EventExtracted, Input: 1, Facts: make([]sessions.Fact, 2), Conflicts: make([]sessions.Conflict, 1)},
+		{Type: sessions.EventSaved, Facts: make([]sessions.Fact, 2)},
+		{Type: sessions.EventInput, Input: 2, Text: "Gandalf"},
+		{Type: sessions.EventError, Input: 2, Error: "rate limited"},
+		{Type: sessions.EventEnd},
+	}
+
+	inputs, source := sessionInputs(events)
+
+	assert.Equal(t, "notes", source)
+	assert.Equal(t, []sessionInput{
+		{Number: 1, Text: "Frodo has green eyes.", Facts: 2, Conflicts: 1},
+		{Number: 2, Text: "Gandalf"},
+	}, inputs)
+}
+
+func TestResolveSessionPath(t *testing.T) {
+	configDir := t.TempDir()
+	local := filepath.Join(t.TempDir(), "session.jsonl")
+	require.NoError(t, os.WriteFile(local, nil, 0600))
+
+	tests := []struct {
+		name string
+		arg  string
+		want string
+	}{
+		{name: "existing path", arg: local, want: local},
+		{name: "bare name", arg: "20240301-120000-1.jsonl", want: filepath.Join(configDir, "sessions", "20240301-120000-1.jsonl")},
+		{name: "missing path kept", arg: "logs/missing.jsonl", want: "logs/missing.jsonl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveSessionPath(configDir, tt.arg))
+		})
+	}
+}
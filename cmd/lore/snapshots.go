@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/scheduler"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+func newSnapshotsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshots",
+		Short: "Manage world snapshots",
+		Long: `Snapshots back up a world's vector collection and relational database.
+
+'lore serve' creates snapshots automatically on the schedule in
+serve.snapshots.schedule and keeps the newest serve.snapshots.keep.`,
+	}
+
+	cmd.AddCommand(
+		newSnapshotsListCmd(),
+		newSnapshotsCreateCmd(),
+		newSnapshotsPruneCmd(),
+	)
+
+	return cmd
+}
+
+func newSnapshotsListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List snapshots and the snapshot schedule",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withSnapshotHandler(func(handler *handlers.SnapshotHandler, cfg *config.Config) error {
+				list, err := handler.HandleList(ctx)
+				if err != nil {
+					return err
+				}
+
+				printSnapshotSchedule(cfg.Serve.Snapshots)
+
+				if len(list) == 0 {
+					fmt.Println("No snapshots.")
+					return nil
+				}
+
+				fmt.Printf("%d snapshot(s):\n", len(list))
+				for _, snapshot := range list {
+					fmt.Printf("  %s  %s  (vectors: %s)\n",
+						snapshot.Name,
+						snapshot.CreatedAt.Local().Format(time.DateTime),
+						snapshot.VectorSnapshot)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func printSnapshotSchedule(cfg config.SnapshotsConfig) {
+	if cfg.Schedule == "" {
+		fmt.Println("Scheduled snapshots: disabled")
+		return
+	}
+
+	schedule, err := scheduler.Parse(cfg.Schedule)
+	if err != nil {
+		fmt.Printf("Scheduled snapshots: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Scheduled snapshots: %s, keeping %d (next: %s, while 'lore serve' runs)\n",
+		cfg.Schedule, cfg.Keep, schedule.Next(time.Now()).Format(time.DateTime))
+}
+
+func newSnapshotsCreateCmd() *cobra.Command {
+	var keep int
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a snapshot now",
+		Long: `Creates a snapshot of the current world. With --keep, older snapshots
+beyond that count are pruned afterwards.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if keep < 0 {
+				return entities.Errorf(entities.ErrValidation, "keep must not be negative")
+			}
+			ctx := cmd.Context()
+
+			return withSnapshotHandler(func(handler *handlers.SnapshotHandler, _ *config.Config) error {
+				result, err := handler.HandleCreate(ctx, keep)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("Created snapshot %s\n", result.Snapshot.Name)
+				printPruned(result.Pruned)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&keep, "keep", 0, "Prune to this many snapshots afterwards (0 = no pruning)")
+
+	return cmd
+}
+
+func newSnapshotsPruneCmd() *cobra.Command {
+	var keep int
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete old snapshots",
+		Long:  "Deletes all but the newest snapshots. Defaults to serve.snapshots.keep.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withSnapshotHandler(func(handler *handlers.SnapshotHandler, cfg *config.Config) error {
+				if !cmd.Flags().Changed("keep") {
+					keep = cfg.Serve.Snapshots.Keep
+				}
+
+				pruned, err := handler.HandlePrune(ctx, keep)
+				if err != nil {
+					return err
+				}
+
+				if len(pruned) == 0 {
+					fmt.Println("Nothing to prune.")
+					return nil
+				}
+				printPruned(pruned)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&keep, "keep", 0, "Number of snapshots to keep")
+
+	return cmd
+}
+
+func printPruned(pruned []string) {
+	for _, name := range pruned {
+		fmt.Printf("Pruned snapshot %s\n", name)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newSourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Archive sources that should not be processed again",
+		Long: `Manages archived sources: superseded drafts and other files whose facts
+are kept for provenance but which are never ingested again.
+
+Run without a subcommand to list the archived sources.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSourcesList(cmd)
+		},
+	}
+
+	cmd.AddCommand(
+		newSourcesArchiveCmd(),
+		newSourcesUnarchiveCmd(),
+		newSourcesListCmd(),
+	)
+
+	return cmd
+}
+
+func newSourcesArchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "archive <file>",
+		Short: "Archive a source, keeping its facts",
+		Long: `Archives a source. Its facts are kept and labeled as archived, but 'lore
+ingest' refuses the file unless given --include-archived, skips it when
+ingesting a directory, and --git-diff leaves its facts alone even if the
+file is changed or deleted. Its quarantined chunks are dropped.
+
+The file does not need to exist any more.
+
+Examples:
+  lore sources archive drafts/chapter1-v1.md -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			source, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			return withSourceService(func(svc *services.SourceService) error {
+				if err := svc.Archive(ctx, source); err != nil {
+					return err
+				}
+				fmt.Printf("Archived %s\n", source)
+				return nil
+			})
+		},
+	}
+}
+
+func newSourcesUnarchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unarchive <file>",
+		Short: "Let an archived source be ingested again",
+		Long: `Unarchives a source: the archived label is removed from its facts and the
+file is ingested again like any other.
+
+Examples:
+  lore sources unarchive drafts/chapter1-v1.md -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			source, err := filepath.Abs(args[0])
+			if err != nil {
+				return fmt.Errorf("resolving path: %w", err)
+			}
+
+			return withSourceService(func(svc *services.SourceService) error {
+				if err := svc.Unarchive(ctx, source); err != nil {
+					return err
+				}
+				fmt.Printf("Unarchived %s\n", source)
+				return nil
+			})
+		},
+	}
+}
+
+func newSourcesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the archived sources",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSourcesList(cmd)
+		},
+	}
+}
+
+// describeSource returns the source file of a fact, with its page if the
+// source is paged, marked if it is archived.
+func describeSource(fact *entities.Fact) string {
+	source := fact.SourceFile
+	if fact.SourcePage > 0 {
+		source += fmt.Sprintf(", page %d", fact.SourcePage)
+	}
+	return source + archivedNote(fact)
+}
+
+// archivedNote marks a fact from an archived source.
+func archivedNote(fact *entities.Fact) string {
+	if fact.Archived {
+		return " (archived)"
+	}
+	return ""
+}
+
+func runSourcesList(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+
+	return withSourceService(func(svc *services.SourceService) error {
+		sources, err := svc.Archived(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(sources) == 0 {
+			fmt.Println("No archived sources.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SOURCE\tARCHIVED")
+		for i := range sources {
+			fmt.Fprintf(w, "%s\t%s\n", sources[i].Source, sources[i].ArchivedAt.Local().Format("2006-01-02 15:04"))
+		}
+		w.Flush()
+
+		return nil
+	})
+}
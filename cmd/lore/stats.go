@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// statsTimeout bounds the status request to a running server.
+const statsTimeout = 5 * time.Second
+
+// serverStatus is the subset of GET /api/status that 'lore stats' shows.
+type serverStatus struct {
+	World       string            `json:"world"`
+	EntityCache *ports.CacheStats `json:"entity_cache"`
+}
+
+func newStatsCmd() *cobra.Command {
+	var addr string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show runtime statistics of a running server",
+		Long: `Shows runtime statistics reported by a running 'lore serve', such as the
+hit rate of its entity cache.
+
+Examples:
+  lore stats
+  lore stats --addr 127.0.0.1:8080`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cmd.Flags().Changed("addr") {
+				configDir, err := findConfigDir()
+				if err != nil {
+					return err
+				}
+				cfg, err := loadConfig(configDir)
+				if err != nil {
+					return fmt.Errorf("loading config: %w", err)
+				}
+				addr = cfg.Serve.Addr
+			}
+			return runStats(cmd.Context(), addr)
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "", "Server address (default from serve.addr)")
+
+	cmd.AddCommand(newStatsHealthCmd(), newStatsModelsCmd(), newStatsSourcesCmd(), newStatsUsageCmd())
+
+	return cmd
+}
+
+func runStats(ctx context.Context, addr string) error {
+	status, err := fetchServerStatus(ctx, addr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("World: %s\n", status.World)
+	if status.EntityCache == nil {
+		fmt.Println("Entity cache: disabled")
+		return nil
+	}
+
+	stats := status.EntityCache
+	fmt.Println("Entity cache:")
+	fmt.Printf("  Hits:      %d\n", stats.Hits)
+	fmt.Printf("  Misses:    %d\n", stats.Misses)
+	fmt.Printf("  Hit rate:  %.1f%%\n", stats.HitRate()*100)
+	fmt.Printf("  Evictions: %d\n", stats.Evictions)
+	fmt.Printf("  Size:      %d/%d\n", stats.Size, stats.Capacity)
+	return nil
+}
+
+// fetchServerStatus reads GET /api/status from the server listening on addr.
+func fetchServerStatus(ctx context.Context, addr string) (*serverStatus, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	url := "http://" + net.JoinHostPort(host, port) + "/api/status"
+
+	ctx, cancel := context.WithTimeout(ctx, statsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("contacting server (is 'lore serve' running?): %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	var status serverStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("decoding server status: %w", err)
+	}
+	return &status, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// DefaultHealthHistory is the number of recorded measurements charted by
+// 'lore stats health'.
+const DefaultHealthHistory = 30
+
+// sparkLevels are the bar heights used by sparkline, lowest first.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+func newStatsHealthCmd() *cobra.Command {
+	var (
+		last     int
+		noRecord bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Show the world's consistency health and how it is trending",
+		Long: `Measures the world's consistency health, records the measurement, and
+charts it against earlier measurements.
+
+The score runs from 0 to 100 and weighs the share of:
+  - facts in an open conflict (40%, see 'lore conflicts list')
+  - low-confidence facts (20%, below review.threshold or 0.7)
+  - stale facts (20%, not updated in a year)
+  - orphan entities (20%, see 'lore entities --orphans')
+
+'lore serve' also records a measurement on the serve.health.schedule.
+
+Examples:
+  lore stats health -w myworld
+  lore stats health -w myworld --last 90 --no-record`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if last < 0 {
+				return entities.Errorf(entities.ErrValidation, "--last must not be negative")
+			}
+			ctx := cmd.Context()
+
+			return withHealthHandler(func(handler *handlers.HealthHandler, opts services.HealthOptions) error {
+				var (
+					current *entities.HealthSample
+					err     error
+				)
+				if noRecord {
+					current, err = handler.HandleMeasure(ctx, opts)
+				} else {
+					current, err = handler.HandleRecord(ctx, opts)
+				}
+				if err != nil {
+					return err
+				}
+
+				history, err := handler.HandleHistory(ctx, last)
+				if err != nil {
+					return err
+				}
+				if noRecord {
+					history = append(history, *current)
+				}
+
+				displayHealth(current, history)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&last, "last", "n", DefaultHealthHistory, "Number of measurements to chart (0 = all)")
+	cmd.Flags().BoolVar(&noRecord, "no-record", false, "Measure without recording the measurement")
+
+	return cmd
+}
+
+// displayHealth prints the current measurement and, given earlier ones,
+// its change and trend. history is oldest first and ends with current.
+func displayHealth(current *entities.HealthSample, history []entities.HealthSample) {
+	fmt.Printf("World health: %.1f/100", current.Score)
+	if len(history) > 1 {
+		previous := history[len(history)-2]
+		fmt.Printf(" (%+.1f since %s)", current.Score-previous.Score, previous.RecordedAt.Local().Format(time.DateTime))
+	}
+	fmt.Println()
+	fmt.Println()
+
+	fmt.Printf("  Facts:            %d\n", current.Facts)
+	fmt.Printf("  Open conflicts:   %d (%d facts, %s)\n", current.OpenConflicts, current.ConflictingFacts, percent(current.ConflictingFacts, current.Facts))
+	fmt.Printf("  Low confidence:   %d (%s)\n", current.LowConfidenceFacts, percent(current.LowConfidenceFacts, current.Facts))
+	fmt.Printf("  Stale facts:      %d (%s)\n", current.StaleFacts, percent(current.StaleFacts, current.Facts))
+	fmt.Printf("  Corroborated:     %d (%s)\n", current.CorroboratedFacts, percent(current.CorroboratedFacts, current.Facts))
+	fmt.Printf("  Orphan entities:  %d of %d (%s)\n", current.OrphanEntities, current.Entities, percent(current.OrphanEntities, current.Entities))
+
+	if len(history) < 2 {
+		fmt.Println("\nRun again later to see how health is trending.")
+		return
+	}
+
+	first, latest := history[0].RecordedAt.Local(), history[len(history)-1].RecordedAt.Local()
+	fmt.Printf("\nTrend over %d measurements (%s to %s):\n", len(history), first.Format(time.DateOnly), latest.Format(time.DateOnly))
+	series := []struct {
+		label string
+		value func(*entities.HealthSample) float64
+	}{
+		{"Score", func(s *entities.HealthSample) float64 { return s.Score }},
+		{"Open conflicts", func(s *entities.HealthSample) float64 { return float64(s.OpenConflicts) }},
+		{"Low confidence", func(s *entities.HealthSample) float64 { return float64(s.LowConfidenceFacts) }},
+		{"Stale facts", func(s *entities.HealthSample) float64 { return float64(s.StaleFacts) }},
+		{"Corroborated", func(s *entities.HealthSample) float64 { return float64(s.CorroboratedFacts) }},
+		{"Orphan entities", func(s *entities.HealthSample) float64 { return float64(s.OrphanEntities) }},
+	}
+	for _, s := range series {
+		values := make([]float64, len(history))
+		for i := range history {
+			values[i] = s.value(&history[i])
+		}
+		fmt.Printf("  %-16s %s  %g\n", s.label, sparkline(values), values[len(values)-1])
+	}
+}
+
+// percent formats part as a percentage of total.
+func percent(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)/float64(total)*100)
+}
+
+// sparkline draws values as a row of bars scaled between their minimum and
+// maximum. A flat series is drawn at mid height.
+func sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+	lowest, highest := values[0], values[0]
+	for _, v := range values {
+		lowest = math.Min(lowest, v)
+		highest = math.Max(highest, v)
+	}
+
+	var b strings.Builder
+	top := len(sparkLevels) - 1
+	for _, v := range values {
+		level := top / 2
+		if highest > lowest {
+			level = int(math.Round((v - lowest) / (highest - lowest) * float64(top)))
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
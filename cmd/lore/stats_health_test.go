@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{name: "empty", values: nil, want: ""},
+		{name: "rising", values: []float64{0, 1, 2, 3, 4, 5, 6, 7}, want: "▁▂▃▄▅▆▇█"},
+		{name: "falling scaled", values: []float64{90, 50, 10}, want: "█▅▁"},
+		{name: "flat", values: []float64{3, 3, 3}, want: "▄▄▄"},
+		{name: "single", values: []float64{42}, want: "▄"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sparkline(tt.values))
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	assert.Equal(t, "25.0%", percent(1, 4))
+	assert.Equal(t, "0.0%", percent(0, 0))
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func newStatsModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "Compare the extraction quality of the LLMs used on the world",
+		Long: `Lists each model the world's facts were extracted with, by llm.model in
+config.yaml, with signals of how well it handles the world's material:
+
+  - the share of its extraction calls whose response could not be parsed
+  - the average confidence of the facts it extracted
+  - the facts it extracted per chunk
+
+To compare models, ingest the same sample with each, for example with
+'lore ingest --check-only' after changing llm.model, then run this command.
+
+Example:
+  lore stats models -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withInternalDeps(func(d *internalDeps) error {
+				stats, err := d.relationalDB.SumExtractionStats(ctx)
+				if err != nil {
+					return err
+				}
+				if len(stats) == 0 {
+					fmt.Println("No extractions recorded. Extractions are recorded when files are ingested.")
+					return nil
+				}
+
+				displayModelStats(os.Stdout, stats)
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
+
+// displayModelStats writes the extraction statistics of each model as a
+// table.
+func displayModelStats(out io.Writer, stats []entities.ModelStats) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tCHUNKS\tPARSE FAILURES\tFACTS\tAVG CONFIDENCE\tFACTS/CHUNK\t")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d (%.1f%%)\t%d\t%.2f\t%.1f\t\n",
+			s.Model, s.Calls, s.ParseFailures, 100*s.ParseFailureRate(), s.Facts, s.AverageConfidence(), s.FactsPerChunk())
+	}
+	w.Flush()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestDisplayModelStats(t *testing.T) {
+	stats := []entities.ModelStats{
+		{Model: "gpt-4o-mini", Calls: 10, ParseFailures: 1, Facts: 45, ConfidenceSum: 38.25},
+		{Model: "llama3", Calls: 4, ParseFailures: 4},
+	}
+
+	var out bytes.Buffer
+	displayModelStats(&out, stats)
+	assert.Contains(t, out.String(), "gpt-4o-mini  10      1 (10.0%)       45     0.85            5.0")
+	assert.Contains(t, out.String(), "llama3       4       4 (100.0%)      0      0.00            0.0")
+}
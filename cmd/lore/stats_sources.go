@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// densityNotes explain what a flagged fact density may mean.
+var densityNotes = map[services.DensityFlag]string{
+	services.DensityLow:  "low: check for failed extraction",
+	services.DensityHigh: "high: lore-dense",
+}
+
+func newStatsSourcesCmd() *cobra.Command {
+	var flagged bool
+
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Show word counts and fact density per ingested source",
+		Long: `Lists each ingested source with its word count, the facts extracted from
+it, and facts per 1000 words, as of its latest ingest.
+
+Sources of at least 200 words with less than half the median density are
+flagged low: part of them may have failed to extract. Those with more than
+twice the median are flagged high: they are unusually dense with lore.
+
+Examples:
+  lore stats sources -w myworld
+  lore stats sources -w myworld --flagged`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withSourceService(func(svc *services.SourceService) error {
+				report, err := svc.Report(ctx)
+				if err != nil {
+					return err
+				}
+				if len(report.Sources) == 0 {
+					fmt.Println("No sources recorded. Sources are recorded when they are ingested.")
+					return nil
+				}
+
+				displaySourceReport(os.Stdout, report, flagged)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&flagged, "flagged", false, "Show only sources with unusually low or high density")
+
+	return cmd
+}
+
+// displaySourceReport writes the sources as a table, followed by the
+// median density and how many were flagged.
+func displaySourceReport(out io.Writer, report *services.SourceReport, flaggedOnly bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tWORDS\tFACTS\tPER 1000 WORDS\t")
+	flagged := 0
+	for i := range report.Sources {
+		source := &report.Sources[i]
+		if source.Flag != "" {
+			flagged++
+		} else if flaggedOnly {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\n", source.Source, source.Words, source.Facts, source.Density(), densityNotes[source.Flag])
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nMedian: %.1f facts per 1000 words; %d of %d sources flagged\n", report.Median, flagged, len(report.Sources))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestDisplaySourceReport(t *testing.T) {
+	report := &services.SourceReport{
+		Sources: []services.SourceDensity{
+			{SourceStats: entities.SourceStats{Source: "ch1.md", Words: 2000, Facts: 20}},
+			{SourceStats: entities.SourceStats{Source: "ch2.md", Words: 2000, Facts: 2}, Flag: services.DensityLow},
+		},
+		Median: 10,
+	}
+
+	var out bytes.Buffer
+	displaySourceReport(&out, report, false)
+	assert.Contains(t, out.String(), "ch1.md  2000   20     10.0")
+	assert.Contains(t, out.String(), "1.0             low: check for failed extraction")
+	assert.Contains(t, out.String(), "Median: 10.0 facts per 1000 words; 1 of 2 sources flagged")
+
+	out.Reset()
+	displaySourceReport(&out, report, true)
+	assert.NotContains(t, out.String(), "ch1.md")
+	assert.Contains(t, out.String(), "ch2.md")
+}
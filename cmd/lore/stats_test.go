@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchServerStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/status", r.URL.Path)
+		_, _ = w.Write([]byte(`{"world":"middle-earth","entity_cache":{"hits":3,"misses":1,"size":2,"capacity":1024}}`))
+	}))
+	defer srv.Close()
+
+	status, err := fetchServerStatus(context.Background(), strings.TrimPrefix(srv.URL, "http://"))
+	require.NoError(t, err)
+	assert.Equal(t, "middle-earth", status.World)
+	require.NotNil(t, status.EntityCache)
+	assert.InDelta(t, 0.75, status.EntityCache.HitRate(), 1e-9)
+}
+
+func TestFetchServerStatus_InvalidAddr(t *testing.T) {
+	_, err := fetchServerStatus(context.Background(), "localhost")
+	assert.ErrorContains(t, err, `invalid server address "localhost"`)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newStatsUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show this month's API usage and cost against the world's quota",
+		Long: `Lists the tokens the world's LLM and embedding calls used this calendar
+month, by model, with their estimated cost, and how the spending and the
+world's facts compare to the limits set under quota in config.yaml.
+
+Costs are estimated from quota.prices, in USD per million tokens.
+
+Example:
+  lore stats usage -w myworld`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withInternalDeps(func(d *internalDeps) error {
+				totals, err := d.quota.Usage(ctx)
+				if err != nil {
+					return err
+				}
+				facts, err := d.repo.Count(ctx)
+				if err != nil {
+					return err
+				}
+
+				displayUsage(os.Stdout, totals, d.quota.Quota(), facts)
+				return nil
+			})
+		},
+	}
+
+	return cmd
+}
+
+// displayUsage writes the month's usage as a table, followed by the
+// spending and facts against their limits.
+func displayUsage(out io.Writer, totals []entities.UsageTotals, quota services.Quota, facts uint64) {
+	var spent float64
+	if len(totals) == 0 {
+		fmt.Fprintln(out, "No API calls recorded this month.")
+	} else {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "BACKEND\tMODEL\tCALLS\tPROMPT TOKENS\tCOMPLETION TOKENS\tCOST\t")
+		for _, t := range totals {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t$%.4f\t\n", t.Backend, t.Model, t.Calls, t.PromptTokens, t.CompletionTokens, t.Cost)
+			spent += t.Cost
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+	}
+
+	if quota.MonthlyBudget > 0 {
+		fmt.Fprintf(out, "Spent: $%.2f of $%.2f this month (%.0f%%)\n", spent, quota.MonthlyBudget, 100*spent/quota.MonthlyBudget)
+	} else {
+		fmt.Fprintf(out, "Spent: $%.2f this month (no budget set)\n", spent)
+	}
+	if quota.MaxFacts > 0 {
+		fmt.Fprintf(out, "Facts: %d of %d\n", facts, quota.MaxFacts)
+	} else {
+		fmt.Fprintf(out, "Facts: %d (no limit set)\n", facts)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func TestDisplayUsage(t *testing.T) {
+	totals := []entities.UsageTotals{
+		{Backend: "embedder", Model: "text-embedding-3-small", Calls: 4, PromptTokens: 50000, Cost: 0.001},
+		{Backend: "llm", Model: "gpt-4o-mini", Calls: 2, PromptTokens: 3000, CompletionTokens: 1000, Cost: 2.499},
+	}
+
+	var out bytes.Buffer
+	displayUsage(&out, totals, services.Quota{MonthlyBudget: 10, MaxFacts: 500}, 120)
+	assert.Contains(t, out.String(), "llm       gpt-4o-mini             2      3000           1000               $2.4990")
+	assert.Contains(t, out.String(), "Spent: $2.50 of $10.00 this month (25%)")
+	assert.Contains(t, out.String(), "Facts: 120 of 500")
+
+	out.Reset()
+	displayUsage(&out, nil, services.Quota{}, 3)
+	assert.Contains(t, out.String(), "No API calls recorded this month.")
+	assert.Contains(t, out.String(), "Spent: $0.00 this month (no budget set)")
+	assert.Contains(t, out.String(), "Facts: 3 (no limit set)")
+}
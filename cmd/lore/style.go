@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func newStyleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "style",
+		Short: "Manage the world's style sheet",
+		Long: `Manages the style sheet: the canonical spelling, capitalization, and
+hyphenation of the world's invented terms.
+
+'lore ingest' and 'lore check' report facts that spell a term differently,
+ignoring case, spaces, and hyphens, such as "Dragonlord" for "Dragon Lord".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStyleList(cmd)
+		},
+	}
+
+	cmd.AddCommand(
+		newStyleAddCmd(),
+		newStyleListCmd(),
+	)
+
+	return cmd
+}
+
+func newStyleAddCmd() *cobra.Command {
+	var source string
+
+	cmd := &cobra.Command{
+		Use:   "add <term>",
+		Short: "Add a term's canonical spelling",
+		Long: `Adds a term's canonical spelling to the style sheet. A term already spelled
+another way is respelled.
+
+Examples:
+  lore style add "Dragon Lord" -w myworld --source chapter2
+  lore style add Barad-dûr -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			return withStyleHandler(func(handler *handlers.StyleHandler) error {
+				term, err := handler.HandleAdd(ctx, args[0], source)
+				if err != nil {
+					return fmt.Errorf("adding style term: %w", err)
+				}
+
+				fmt.Printf("Added style term: %s\n", term.Term)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", "", "Where the spelling was established, such as a chapter")
+
+	return cmd
+}
+
+func newStyleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the style sheet",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStyleList(cmd)
+		},
+	}
+}
+
+func runStyleList(cmd *cobra.Command) error {
+	ctx := cmd.Context()
+
+	return withStyleHandler(func(handler *handlers.StyleHandler) error {
+		terms, err := handler.HandleList(ctx)
+		if err != nil {
+			return fmt.Errorf("listing style terms: %w", err)
+		}
+
+		if len(terms) == 0 {
+			fmt.Println("No style terms found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TERM\tSOURCE")
+		for i := range terms {
+			fmt.Fprintf(w, "%s\t%s\n", terms[i].Term, terms[i].Source)
+		}
+		w.Flush()
+
+		return nil
+	})
+}
+
+func displayStyleIssues(issues []entities.StyleIssue) {
+	fmt.Printf("Style Issues Found: %d\n\n", len(issues))
+
+	for i := range issues {
+		fmt.Printf("STYLE: %s\n", issues[i].Suggestion())
+		fmt.Printf("  Fact: %s %s %s (%s)\n\n",
+			issues[i].Fact.Subject, issues[i].Fact.Predicate, issues[i].Fact.Object, issues[i].Fact.SourceFile)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ersonp/lore-core/internal/application/api"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+func newTokensCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage API tokens for lore serve",
+		Long: `Manages the bearer tokens 'lore serve' accepts, stored in .lore/auth.yaml.
+
+Each token has a scope in each world it may use: read allows status,
+queries, and listing snapshots; write also allows requests that change the
+world. Once any token exists, every API request must send one in an
+"Authorization: Bearer <token>" header. Only a hash of each token is
+stored, so a lost token must be revoked and created again.`,
+	}
+
+	cmd.AddCommand(
+		newTokensCreateCmd(),
+		newTokensListCmd(),
+		newTokensRevokeCmd(),
+	)
+
+	return cmd
+}
+
+func newTokensCreateCmd() *cobra.Command {
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a token for the current world and print it",
+		Long: `Creates a token for the current world and prints it once. To let a token
+use more worlds, add them under its worlds in .lore/auth.yaml.
+
+Examples:
+  lore tokens create co-writer -w myworld
+  lore tokens create editor --scope write -w myworld`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if globalWorld == "" {
+				return entities.Errorf(entities.ErrValidation, "world is required (use --world flag)")
+			}
+
+			configDir, err := findConfigDir()
+			if err != nil {
+				return err
+			}
+			worlds, err := loadWorlds(configDir)
+			if err != nil {
+				return err
+			}
+			if _, err := worlds.Get(globalWorld); err != nil {
+				return err
+			}
+
+			auth, err := config.LoadAuth(configDir)
+			if err != nil {
+				return err
+			}
+			token, err := auth.Add(args[0], globalWorld, scope)
+			if err != nil {
+				return err
+			}
+			if err := auth.Save(configDir); err != nil {
+				return err
+			}
+
+			fmt.Printf("Created %s token %q for world %s:\n\n  %s\n\n", scope, args[0], globalWorld, token)
+			fmt.Println("Store it now; it cannot be shown again.")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", config.ScopeRead, "Token scope: read or write")
+
+	return cmd
+}
+
+func newTokensListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List tokens and the worlds they may use",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configDir, err := findConfigDir()
+			if err != nil {
+				return err
+			}
+			auth, err := config.LoadAuth(configDir)
+			if err != nil {
+				return err
+			}
+
+			if !auth.Enabled() {
+				fmt.Println("No tokens. The API is open to anyone who can reach it.")
+				return nil
+			}
+			for _, t := range auth.Tokens {
+				fmt.Printf("%-20s  %s  %s\n", t.Name, formatTokenWorlds(t.Worlds), formatTokenCreated(t.CreatedAt))
+			}
+			return nil
+		},
+	}
+}
+
+// formatTokenWorlds lists a token's worlds and scopes, such as
+// "myworld:read, other:write".
+func formatTokenWorlds(worlds map[string]string) string {
+	parts := make([]string, 0, len(worlds))
+	for _, world := range slices.Sorted(maps.Keys(worlds)) {
+		parts = append(parts, world+":"+worlds[world])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatTokenCreated(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return "created " + t.Local().Format(time.DateTime)
+}
+
+func newTokensRevokeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Revoke a token",
+		Long: `Revokes a token. A running 'lore serve' must be restarted to stop
+accepting it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configDir, err := findConfigDir()
+			if err != nil {
+				return err
+			}
+			auth, err := config.LoadAuth(configDir)
+			if err != nil {
+				return err
+			}
+
+			if !auth.Revoke(args[0]) {
+				return entities.Errorf(entities.ErrNotFound, "token %q not found", args[0])
+			}
+			if err := auth.Save(configDir); err != nil {
+				return err
+			}
+
+			fmt.Printf("Revoked token %q.\n", args[0])
+			return nil
+		},
+	}
+}
+
+// tokenAuthorizer checks API requests against the tokens of auth.yaml.
+type tokenAuthorizer struct {
+	*config.AuthConfig
+}
+
+var _ api.Authorizer = tokenAuthorizer{}
+
+// Authorize returns the name of token and whether its scope in world
+// allows writing.
+func (a tokenAuthorizer) Authorize(token, world string) (name string, canWrite, ok bool) {
+	name, scope, ok := a.AuthConfig.Authorize(token, world)
+	return name, scope == config.ScopeWrite, ok
+}
@@ -146,7 +146,7 @@ func runTypesDescribe(cmd *cobra.Command, name string) error {
 			return fmt.Errorf("describing type: %w", err)
 		}
 		if et == nil {
-			return fmt.Errorf("entity type %q not found", name)
+			return entities.Errorf(entities.ErrNotFound, "entity type %q not found", name)
 		}
 
 		fmt.Printf("Name:        %s\n", et.Name)
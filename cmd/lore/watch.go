@@ -12,11 +12,14 @@ import (
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/sessions"
 )
 
 type watchFlags struct {
 	sourceFile string
 	autoSave   bool
+	noLog      bool
 }
 
 func newWatchCmd() *cobra.Command {
@@ -25,7 +28,11 @@ func newWatchCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "watch",
 		Short: "Interactive mode with real-time consistency checking",
-		Long:  "Enter text interactively and get real-time fact extraction and consistency feedback.",
+		Long: `Enter text interactively and get real-time fact extraction and consistency feedback.
+
+Each session is logged to .lore/sessions/: every input, the facts and
+conflicts found in it, and which facts were saved or discarded. Replay a
+session's inputs against the current database with 'lore sessions replay'.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runWatch(cmd, flags)
 		},
@@ -33,6 +40,7 @@ func newWatchCmd() *cobra.Command {
 
 	cmd.Flags().StringVarP(&flags.sourceFile, "source", "s", "interactive", "Source name for facts")
 	cmd.Flags().BoolVar(&flags.autoSave, "save", false, "Auto-save facts (with confirmation on conflicts)")
+	cmd.Flags().BoolVar(&flags.noLog, "no-log", false, "Do not write a session log")
 
 	return cmd
 }
@@ -41,24 +49,46 @@ type watchState struct {
 	pendingFacts      []entities.Fact
 	pendingIssues     []ports.ConsistencyIssue
 	extractionService *services.ExtractionService
+	conflictService   *services.ConflictService
 	vectorDB          ports.VectorDB
+	retrieval         services.Retrieval
 	sourceFile        string
 	autoSave          bool
+	log               *sessions.Log // nil when logging is off
 }
 
 func runWatch(cmd *cobra.Command, flags watchFlags) error {
-	return withExtractionService(func(extractionService *services.ExtractionService, vectorDB ports.VectorDB) error {
+	return withInternalDeps(func(d *internalDeps) error {
 		state := &watchState{
-			extractionService: extractionService,
-			vectorDB:          vectorDB,
+			extractionService: d.extractionService,
+			conflictService:   d.conflictService,
+			vectorDB:          d.vectorDB,
+			retrieval:         retrieval(d),
 			sourceFile:        flags.sourceFile,
 			autoSave:          flags.autoSave,
 		}
 
+		if !flags.noLog {
+			log, err := sessions.Create(config.SessionDir(d.configDir), globalWorld, flags.sourceFile)
+			if err != nil {
+				return err
+			}
+			state.log = log
+			defer func() { state.logged(log.Close()) }()
+			fmt.Printf("Logging session to %s\n", log.Path())
+		}
+
 		return state.runInputLoop(cmd.Context())
 	})
 }
 
+// logged reports a session log write failure without ending the session.
+func (s *watchState) logged(err error) {
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
 func (s *watchState) runInputLoop(ctx context.Context) error {
 	fmt.Println("Lore interactive mode. Enter text and press Enter twice to check.")
 	fmt.Println("Commands: 'save' to save pending facts, 'discard' to clear, 'list' to show pending, 'quit' to exit")
@@ -101,6 +131,9 @@ func (s *watchState) handleCommand(ctx context.Context, input string, scanner *b
 		}
 		return true, false
 	case "discard":
+		if s.log != nil && len(s.pendingFacts) > 0 {
+			s.logged(s.log.Discarded(s.pendingFacts))
+		}
 		s.pendingFacts = nil
 		s.pendingIssues = nil
 		fmt.Println("Pending facts discarded.")
@@ -172,36 +205,29 @@ func processBufferedInput(ctx context.Context, state *watchState, inputBuffer *s
 func (s *watchState) processInput(ctx context.Context, text string) error {
 	fmt.Println("\nChecking...")
 
-	opts := services.ExtractionOptions{
-		CheckConsistency: true,
-		CheckOnly:        true, // Don't save yet
+	input := 0
+	if s.log != nil {
+		var err error
+		input, err = s.log.Input(text)
+		s.logged(err)
 	}
 
-	result, err := s.extractionService.ExtractAndStoreWithOptions(ctx, text, s.sourceFile, opts)
+	result, err := checkWatchInput(ctx, s.extractionService, s.retrieval, text, s.sourceFile)
 	if err != nil {
-		return fmt.Errorf("extracting facts: %w", err)
+		if s.log != nil {
+			s.logged(s.log.Failed(input, err))
+		}
+		return err
+	}
+	if s.log != nil {
+		s.logged(s.log.Extracted(input, result.Facts, result.Issues))
 	}
 
 	if len(result.Facts) == 0 {
 		fmt.Println("No facts found in input.")
 		return nil
 	}
-
-	fmt.Printf("Found %d facts:\n", len(result.Facts))
-	for i := range result.Facts {
-		fmt.Printf("  %d. [%s] %s %s %s\n", i+1, result.Facts[i].Type, result.Facts[i].Subject, result.Facts[i].Predicate, result.Facts[i].Object)
-	}
-
-	// Display consistency issues
-	if len(result.Issues) > 0 {
-		fmt.Println()
-		for i := range result.Issues {
-			severityLabel := formatSeverity(result.Issues[i].Severity)
-			fmt.Printf("%s: %s\n", severityLabel, result.Issues[i].Description)
-			fmt.Printf("  New:      %s %s %s\n", result.Issues[i].NewFact.Subject, result.Issues[i].NewFact.Predicate, result.Issues[i].NewFact.Object)
-			fmt.Printf("  Existing: %s %s %s (%s)\n", result.Issues[i].ExistingFact.Subject, result.Issues[i].ExistingFact.Predicate, result.Issues[i].ExistingFact.Object, result.Issues[i].ExistingFact.SourceFile)
-		}
-	}
+	displayWatchResult(result)
 
 	// Add to pending
 	s.pendingFacts = append(s.pendingFacts, result.Facts...)
@@ -238,8 +264,17 @@ func (s *watchState) savePendingFacts(ctx context.Context) error {
 	if err := s.vectorDB.SaveBatch(ctx, s.pendingFacts); err != nil {
 		return fmt.Errorf("saving facts: %w", err)
 	}
+	if s.log != nil {
+		s.logged(s.log.Saved(s.pendingFacts))
+	}
 
 	fmt.Printf("Saved %d facts.\n", len(s.pendingFacts))
+	if len(s.pendingIssues) > 0 {
+		if _, err := s.conflictService.Record(ctx, s.pendingIssues); err != nil {
+			return err
+		}
+		displayConflictsRecorded(len(s.pendingIssues))
+	}
 	s.pendingFacts = nil
 	s.pendingIssues = nil
 	return nil
@@ -264,6 +299,41 @@ func (s *watchState) showPendingFacts() {
 	}
 }
 
+// checkWatchInput extracts facts from text and checks them against the
+// database without saving them.
+func checkWatchInput(ctx context.Context, extractionService *services.ExtractionService, retrieval services.Retrieval, text, sourceFile string) (*services.ExtractionResult, error) {
+	opts := &services.ExtractionOptions{
+		CheckConsistency: true,
+		CheckOnly:        true, // Don't save yet
+		Retrieval:        retrieval,
+	}
+
+	result, err := extractionService.ExtractAndStoreWithOptions(ctx, text, sourceFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("extracting facts: %w", err)
+	}
+	return result, nil
+}
+
+// displayWatchResult prints the facts and consistency issues found in an input.
+func displayWatchResult(result *services.ExtractionResult) {
+	fmt.Printf("Found %d facts:\n", len(result.Facts))
+	for i := range result.Facts {
+		fmt.Printf("  %d. [%s] %s %s %s\n", i+1, result.Facts[i].Type, result.Facts[i].Subject, result.Facts[i].Predicate, result.Facts[i].Object)
+	}
+
+	// Display consistency issues
+	if len(result.Issues) > 0 {
+		fmt.Println()
+		for i := range result.Issues {
+			severityLabel := formatSeverity(result.Issues[i].Severity)
+			fmt.Printf("%s: %s\n", severityLabel, result.Issues[i].Description)
+			fmt.Printf("  New:      %s %s %s\n", result.Issues[i].NewFact.Subject, result.Issues[i].NewFact.Predicate, result.Issues[i].NewFact.Object)
+			fmt.Printf("  Existing: %s %s %s (%s)\n", result.Issues[i].ExistingFact.Subject, result.Issues[i].ExistingFact.Predicate, result.Issues[i].ExistingFact.Object, describeSources(&result.Issues[i].ExistingFact))
+		}
+	}
+}
+
 func hasCriticalIssues(issues []ports.ConsistencyIssue) bool {
 	for i := range issues {
 		if issues[i].Severity == "critical" {
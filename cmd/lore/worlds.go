@@ -2,13 +2,21 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
 	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
@@ -31,28 +39,48 @@ func newWorldsCmd() *cobra.Command {
 		newWorldsListCmd(),
 		newWorldsCreateCmd(),
 		newWorldsDeleteCmd(),
+		newWorldsRenameCmd(),
 	)
 
 	return cmd
 }
 
 func newWorldsListCmd() *cobra.Command {
-	return &cobra.Command{
+	var fast bool
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all worlds",
-		RunE:  runWorldsList,
+		Long: `List all worlds with their fact, entity, and relationship counts,
+the time of the last ingest, and storage details.
+
+Fact counts, the last ingest time, and collection info come from Qdrant.
+Use --fast to skip Qdrant and only show what is available locally.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listWorlds(cmd, fast)
+		},
 	}
+
+	cmd.Flags().BoolVar(&fast, "fast", false, "Skip Qdrant and show local statistics only")
+
+	return cmd
 }
 
 func runWorldsList(cmd *cobra.Command, args []string) error {
-	cwd, err := os.Getwd()
+	return listWorlds(cmd, false)
+}
+
+func listWorlds(cmd *cobra.Command, fast bool) error {
+	ctx := cmd.Context()
+
+	configDir, err := initConfigDir()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
-	worlds, err := config.LoadWorlds(cwd)
+	worlds, err := loadWorlds(configDir)
 	if err != nil {
-		return fmt.Errorf("loading worlds: %w", err)
+		return err
 	}
 
 	if len(worlds.Worlds) == 0 {
@@ -61,16 +89,113 @@ func runWorldsList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("%-20s %-25s %s\n", "NAME", "COLLECTION", "DESCRIPTION")
-	fmt.Printf("%-20s %-25s %s\n", "----", "----------", "-----------")
+	var mgr *worldManager
+	if !fast {
+		cfg, err := loadConfig(configDir)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		mgr = &worldManager{cfg: cfg}
+	}
+
+	const row = "%-20s %8s %8s %8s %-17s %-22s %8s  %s\n"
+	fmt.Printf(row, "NAME", "FACTS", "ENTITIES", "RELS", "LAST INGEST", "QDRANT", "SQLITE", "DESCRIPTION")
+	fmt.Printf(row, "----", "-----", "--------", "----", "-----------", "------", "------", "-----------")
+
+	var remoteErr error
+	for _, name := range slices.Sorted(maps.Keys(worlds.Worlds)) {
+		world := worlds.Worlds[name]
+		stats := localWorldStats(ctx, configDir, name)
+
+		facts, lastIngest, qdrantInfo := "-", "-", "-"
+		if mgr != nil {
+			remote, err := mgr.collectionStats(ctx, world.Collection)
+			if err != nil {
+				remoteErr = err
+				facts, lastIngest, qdrantInfo = "?", "?", "unavailable"
+			} else {
+				facts = strconv.FormatUint(remote.info.Points, 10)
+				lastIngest = formatLastIngest(remote.lastIngest)
+				qdrantInfo = fmt.Sprintf("%s, %d segments", remote.info.Status, remote.info.Segments)
+			}
+		}
+
+		fmt.Printf(row, name, facts, stats.entities, stats.relationships,
+			lastIngest, qdrantInfo, stats.size, world.Description)
+	}
 
-	for name, world := range worlds.Worlds {
-		fmt.Printf("%-20s %-25s %s\n", name, world.Collection, world.Description)
+	if remoteErr != nil {
+		fmt.Printf("\nWarning: could not reach Qdrant: %v\n", remoteErr)
 	}
 
 	return nil
 }
 
+// worldStats holds the statistics of a world read from its SQLite database.
+type worldStats struct {
+	entities      string
+	relationships string
+	size          string
+}
+
+// localWorldStats reads entity and relationship counts and the database size
+// of a world. Missing or unreadable databases are shown as "-" or "?".
+func localWorldStats(ctx context.Context, configDir, worldName string) worldStats {
+	stats := worldStats{entities: "-", relationships: "-", size: "-"}
+
+	sqlitePath := config.SQLitePathForWorld(configDir, worldName)
+	size, err := sqliteSize(sqlitePath)
+	if err != nil {
+		return stats
+	}
+	stats.size = entities.ByteSize(size).String()
+
+	repo, err := sqlite.NewRepository(config.SQLiteConfig{Path: sqlitePath})
+	if err != nil {
+		stats.entities, stats.relationships = "?", "?"
+		return stats
+	}
+	defer repo.Close()
+
+	if n, err := repo.CountEntities(ctx, worldName); err == nil {
+		stats.entities = strconv.Itoa(n)
+	} else {
+		stats.entities = "?"
+	}
+	if n, err := repo.CountRelationships(ctx); err == nil {
+		stats.relationships = strconv.Itoa(n)
+	} else {
+		stats.relationships = "?"
+	}
+
+	return stats
+}
+
+// sqliteSize returns the combined size of a SQLite database and its journal
+// files. It fails if the database itself does not exist.
+func sqliteSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+
+	total := info.Size()
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if journal, err := os.Stat(path + suffix); err == nil {
+			total += journal.Size()
+		}
+	}
+	return total, nil
+}
+
+// formatLastIngest renders the time of the newest fact, or "never".
+func formatLastIngest(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
 func newWorldsCreateCmd() *cobra.Command {
 	var description string
 
@@ -91,45 +216,49 @@ func newWorldsCreateCmd() *cobra.Command {
 func runWorldsCreate(cmd *cobra.Command, name string, description string) error {
 	ctx := cmd.Context()
 
-	cwd, err := os.Getwd()
+	configDir, err := initConfigDir()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
 	collection := config.GenerateCollectionName(name)
 	initialized := false
 
 	// Check if config exists, if not initialize
-	if !config.Exists(cwd) {
-		if err := config.WriteDefaultWithWorld(cwd, name, description); err != nil {
+	if !config.Exists(configDir) {
+		if err := config.WriteDefaultWithWorld(configDir, name, description); err != nil {
 			return fmt.Errorf("initializing config: %w", err)
 		}
-		fmt.Printf("Initialized lore in %s\n", config.ConfigDir(cwd))
+		fmt.Printf("Initialized lore in %s\n", configDir)
 		initialized = true
 	}
 
-	cfg, err := config.Load(cwd)
+	cfg, err := loadConfig(configDir)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
 	// If not initialized, add world to existing config
 	if !initialized {
-		worlds, err := config.LoadWorlds(cwd)
+		worlds, err := loadWorlds(configDir)
 		if err != nil {
-			return fmt.Errorf("loading worlds: %w", err)
+			return err
 		}
 
 		if worlds.Exists(name) {
-			return fmt.Errorf("world %q already exists", name)
+			return entities.Errorf(entities.ErrConflict, "world %q already exists", name)
+		}
+		if other, ok := worlds.FindByCollection(collection); ok {
+			return entities.Errorf(entities.ErrConflict, "world %q already uses collection %q; choose a different name", other, collection)
 		}
 
 		worlds.Add(name, config.WorldEntry{
-			Collection:  collection,
-			Description: description,
+			Collection:        collection,
+			Description:       description,
+			EmbeddingTemplate: cfg.Embedder.Template,
 		})
 
-		if err := worlds.Save(cwd); err != nil {
+		if err := worlds.Save(configDir); err != nil {
 			return fmt.Errorf("saving worlds: %w", err)
 		}
 	}
@@ -140,7 +269,7 @@ func runWorldsCreate(cmd *cobra.Command, name string, description string) error
 	}
 
 	// Create SQLite database for the world
-	if err := initWorldSQLite(ctx, cwd, name); err != nil {
+	if err := initWorldSQLite(ctx, configDir, name); err != nil {
 		return fmt.Errorf("initializing sqlite database: %w", err)
 	}
 
@@ -169,24 +298,24 @@ func newWorldsDeleteCmd() *cobra.Command {
 func runWorldsDelete(cmd *cobra.Command, name string, force bool) error {
 	ctx := cmd.Context()
 
-	cwd, err := os.Getwd()
+	configDir, err := findConfigDir()
 	if err != nil {
-		return fmt.Errorf("getting current directory: %w", err)
+		return err
 	}
 
-	cfg, err := config.Load(cwd)
+	cfg, err := loadConfig(configDir)
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	worlds, err := config.LoadWorlds(cwd)
+	worlds, err := loadWorlds(configDir)
 	if err != nil {
-		return fmt.Errorf("loading worlds: %w", err)
+		return err
 	}
 
 	world, err := worlds.Get(name)
 	if err != nil {
-		return fmt.Errorf("world %q not found", name)
+		return entities.Errorf(entities.ErrNotFound, "world %q not found", name)
 	}
 
 	mgr := &worldManager{cfg: cfg}
@@ -194,20 +323,23 @@ func runWorldsDelete(cmd *cobra.Command, name string, force bool) error {
 	if !force {
 		count, err := mgr.getCollectionCount(ctx, world.Collection)
 		if err == nil && count > 0 {
-			return fmt.Errorf("world %q contains %d facts, use --force to delete", name, count)
+			return entities.Errorf(entities.ErrConflict, "world %q contains %d facts, use --force to delete", name, count)
 		}
 	}
 
 	if err := mgr.deleteCollection(ctx, world.Collection); err != nil {
 		fmt.Printf("Warning: could not delete collection %q: %v\n", world.Collection, err)
 	}
+	if err := mgr.deleteEntityIndex(ctx, world.Collection); err != nil {
+		fmt.Printf("Warning: could not delete entity index of %q: %v\n", world.Collection, err)
+	}
 
 	// Delete SQLite database files
-	cleanupWorldSQLite(cwd, name)
+	cleanupWorldSQLite(configDir, name)
 
 	worlds.Remove(name)
 
-	if err := worlds.Save(cwd); err != nil {
+	if err := worlds.Save(configDir); err != nil {
 		return fmt.Errorf("saving worlds: %w", err)
 	}
 
@@ -216,11 +348,156 @@ func runWorldsDelete(cmd *cobra.Command, name string, force bool) error {
 	return nil
 }
 
+func newWorldsRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename OLD NEW",
+		Short: "Rename a world, keeping its data",
+		Long: `Rename a world without re-ingesting its facts.
+
+The Qdrant collection is kept and reached through an alias named after the
+new world, the world's SQLite directory is moved, and worlds.yaml is updated.
+If any step fails, the earlier steps are rolled back.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorldsRename(cmd, args[0], args[1])
+		},
+	}
+}
+
+func runWorldsRename(cmd *cobra.Command, oldName, newName string) error {
+	configDir, err := findConfigDir()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(configDir)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	worlds, err := loadWorlds(configDir)
+	if err != nil {
+		return err
+	}
+
+	svc := services.NewWorldService(
+		worldRegistry{worlds: worlds, configDir: configDir},
+		worldStorage{mgr: &worldManager{cfg: cfg}, configDir: configDir},
+	)
+	result, err := svc.Rename(cmd.Context(), oldName, newName)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+
+	fmt.Printf("Renamed world %q to %q\n", oldName, newName)
+
+	return nil
+}
+
+// worldRegistry keeps the worlds of worlds.yaml.
+type worldRegistry struct {
+	worlds    *config.WorldsConfig
+	configDir string
+}
+
+var _ ports.WorldRegistry = worldRegistry{}
+
+func (r worldRegistry) Collection(world string) (string, error) {
+	return r.worlds.GetCollection(world)
+}
+
+func (r worldRegistry) Exists(world string) bool {
+	return r.worlds.Exists(world)
+}
+
+func (r worldRegistry) CollectionOwner(collection string) (string, bool) {
+	return r.worlds.FindByCollection(collection)
+}
+
+func (r worldRegistry) CollectionName(world string) string {
+	return config.GenerateCollectionName(world)
+}
+
+func (r worldRegistry) Rename(oldName, newName, collection string) error {
+	entry, err := r.worlds.Get(oldName)
+	if err != nil {
+		return err
+	}
+	entry.Collection = collection
+	r.worlds.Remove(oldName)
+	r.worlds.Add(newName, *entry)
+	return r.worlds.Save(r.configDir)
+}
+
+// worldStorage keeps the data of worlds in Qdrant and under the config
+// directory.
+type worldStorage struct {
+	mgr       *worldManager
+	configDir string
+}
+
+var _ ports.WorldStorage = worldStorage{}
+
+func (s worldStorage) AliasCollection(ctx context.Context, collection, alias string) (bool, error) {
+	return s.mgr.aliasCollection(ctx, collection, alias)
+}
+
+func (s worldStorage) DeleteAlias(ctx context.Context, alias string) error {
+	return s.mgr.deleteAlias(ctx, alias)
+}
+
+func (s worldStorage) DeleteEntityIndex(ctx context.Context, collection string) error {
+	return s.mgr.deleteEntityIndex(ctx, collection)
+}
+
+func (s worldStorage) CanMoveDir(oldName, newName string) (bool, error) {
+	oldDir, newDir := config.WorldDir(s.configDir, oldName), config.WorldDir(s.configDir, newName)
+	if oldDir == newDir || !pathExists(oldDir) {
+		return false, nil
+	}
+	if pathExists(newDir) {
+		return false, entities.Errorf(entities.ErrConflict, "world directory %s already exists", newDir)
+	}
+	return true, nil
+}
+
+func (s worldStorage) MoveDir(oldName, newName string) error {
+	return os.Rename(config.WorldDir(s.configDir, oldName), config.WorldDir(s.configDir, newName))
+}
+
+func (s worldStorage) RenameEntities(ctx context.Context, world, oldID, newID string) (bool, error) {
+	sqlitePath := config.SQLitePathForWorld(s.configDir, world)
+	if !pathExists(sqlitePath) {
+		return false, nil
+	}
+	return true, renameWorldEntities(ctx, sqlitePath, oldID, newID)
+}
+
+// renameWorldEntities moves a world's entities to a new world ID.
+func renameWorldEntities(ctx context.Context, sqlitePath, oldName, newName string) error {
+	repo, err := sqlite.NewRepository(config.SQLiteConfig{Path: sqlitePath})
+	if err != nil {
+		return err
+	}
+	defer repo.Close()
+
+	return repo.RenameWorld(ctx, oldName, newName)
+}
+
+// pathExists reports whether a file or directory exists at path.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
 func (m *worldManager) createCollection(ctx context.Context, collection string) error {
 	qdrantCfg := m.cfg.Qdrant
 	qdrantCfg.Collection = collection
 
-	repo, err := qdrant.NewRepository(qdrantCfg)
+	repo, err := qdrant.NewRepository(&qdrantCfg)
 	if err != nil {
 		return err
 	}
@@ -233,7 +510,7 @@ func (m *worldManager) getCollectionCount(ctx context.Context, collection string
 	qdrantCfg := m.cfg.Qdrant
 	qdrantCfg.Collection = collection
 
-	repo, err := qdrant.NewRepository(qdrantCfg)
+	repo, err := qdrant.NewRepository(&qdrantCfg)
 	if err != nil {
 		return 0, err
 	}
@@ -242,22 +519,141 @@ func (m *worldManager) getCollectionCount(ctx context.Context, collection string
 	return repo.Count(ctx)
 }
 
-func (m *worldManager) deleteCollection(ctx context.Context, collection string) error {
+// collectionStats is what Qdrant reports about a world's collection.
+type collectionStats struct {
+	info       qdrant.CollectionInfo
+	lastIngest time.Time
+}
+
+// collectionStats fetches collection info and the creation time of the
+// newest fact in a world's collection.
+func (m *worldManager) collectionStats(ctx context.Context, collection string) (collectionStats, error) {
 	qdrantCfg := m.cfg.Qdrant
 	qdrantCfg.Collection = collection
 
-	repo, err := qdrant.NewRepository(qdrantCfg)
+	repo, err := qdrant.NewRepository(&qdrantCfg)
 	if err != nil {
-		return err
+		return collectionStats{}, err
 	}
 	defer repo.Close()
 
-	return repo.DeleteCollection(ctx)
+	info, err := repo.Info(ctx)
+	if err != nil {
+		return collectionStats{}, err
+	}
+
+	stats := collectionStats{info: info}
+	if info.Points == 0 {
+		return stats, nil
+	}
+
+	newest, err := repo.ListFiltered(ctx, ports.FactListOptions{Sort: ports.FactSortCreated, Limit: 1})
+	if err != nil {
+		return collectionStats{}, err
+	}
+	if len(newest) > 0 {
+		stats.lastIngest = newest[0].CreatedAt
+	}
+
+	return stats, nil
+}
+
+// deleteCollection removes a world's collection. Reindexed worlds reach their
+// data through an alias, so the collection behind the alias is deleted instead.
+func (m *worldManager) deleteCollection(ctx context.Context, collection string) error {
+	admin, err := qdrant.NewCollectionAdmin(&m.cfg.Qdrant)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	target, err := admin.ResolveAlias(ctx, collection)
+	if err != nil {
+		return err
+	}
+	if target == "" {
+		target = collection
+	}
+
+	return admin.DeleteCollection(ctx, target)
+}
+
+// deleteEntityIndex removes the collection of a world's entity embeddings,
+// if it has one.
+func (m *worldManager) deleteEntityIndex(ctx context.Context, collection string) error {
+	admin, err := qdrant.NewCollectionAdmin(&m.cfg.Qdrant)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	err = admin.DeleteCollection(ctx, collection+qdrant.EntityIndexSuffix)
+	if errors.Is(err, entities.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// aliasCollection makes alias point at the collection holding a world's
+// facts. isAlias reports whether collection was itself an alias rather than
+// a physical collection created before aliases were introduced.
+func (m *worldManager) aliasCollection(ctx context.Context, collection, alias string) (isAlias bool, err error) {
+	admin, err := qdrant.NewCollectionAdmin(&m.cfg.Qdrant)
+	if err != nil {
+		return false, err
+	}
+	defer admin.Close()
+
+	target, err := admin.ResolveAlias(ctx, collection)
+	if err != nil {
+		return false, err
+	}
+	isAlias = target != ""
+	if !isAlias {
+		exists, err := admin.CollectionExists(ctx, collection)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, entities.Errorf(entities.ErrNotFound, "collection %q not found", collection)
+		}
+		target = collection
+	}
+
+	taken, err := admin.ResolveAlias(ctx, alias)
+	if err != nil {
+		return false, err
+	}
+	if taken == "" {
+		exists, err := admin.CollectionExists(ctx, alias)
+		if err != nil {
+			return false, err
+		}
+		if exists {
+			taken = alias
+		}
+	}
+	if taken != "" {
+		return false, entities.Errorf(entities.ErrConflict, "collection %q already exists", alias)
+	}
+
+	return isAlias, admin.SwitchAlias(ctx, alias, target)
+}
+
+// deleteAlias removes an alias, leaving its collection in place.
+func (m *worldManager) deleteAlias(ctx context.Context, alias string) error {
+	admin, err := qdrant.NewCollectionAdmin(&m.cfg.Qdrant)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	return admin.DeleteAlias(ctx, alias)
 }
 
 // initWorldSQLite creates the SQLite database and schema for a world.
-func initWorldSQLite(ctx context.Context, basePath, worldName string) error {
-	sqlitePath := config.SQLitePathForWorld(basePath, worldName)
+func initWorldSQLite(ctx context.Context, configDir, worldName string) error {
+	sqlitePath := config.SQLitePathForWorld(configDir, worldName)
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(sqlitePath), 0755); err != nil {
@@ -287,19 +683,42 @@ func initWorldSQLite(ctx context.Context, basePath, worldName string) error {
 }
 
 // cleanupWorldSQLite removes SQLite database files for a world.
-func cleanupWorldSQLite(basePath, worldName string) {
-	sqlitePath := config.SQLitePathForWorld(basePath, worldName)
+func cleanupWorldSQLite(configDir, worldName string) {
+	sqlitePath := config.SQLitePathForWorld(configDir, worldName)
 
-	// Delete main database file
-	if err := os.Remove(sqlitePath); err != nil && !os.IsNotExist(err) {
+	// Delete main database file. The journal files are only removed once it
+	// is gone, since they may hold committed data not yet in the database.
+	if err := removeFile(sqlitePath); err != nil {
 		fmt.Printf("Warning: could not delete sqlite database: %v\n", err)
+		return
 	}
 
-	// Delete WAL and SHM files (SQLite journal files)
-	os.Remove(sqlitePath + "-wal")
-	os.Remove(sqlitePath + "-shm")
+	// Delete WAL, SHM, and rollback journal files
+	for _, suffix := range []string{"-wal", "-shm", "-journal"} {
+		if err := removeFile(sqlitePath + suffix); err != nil {
+			fmt.Printf("Warning: could not delete %s: %v\n", filepath.Base(sqlitePath+suffix), err)
+		}
+	}
 
 	// Remove world directory if empty
 	worldDir := filepath.Dir(sqlitePath)
 	os.Remove(worldDir) // Fails silently if not empty
 }
+
+// removeFileAttempts bounds the retries in removeFile.
+const removeFileAttempts = 5
+
+// removeFile deletes a file, treating a missing file as success. Windows
+// refuses to delete files that are still open, and SQLite files can stay
+// locked briefly after the database is closed, so removal is retried.
+func removeFile(path string) error {
+	var err error
+	for attempt := 1; attempt <= removeFileAttempts; attempt++ {
+		err = os.Remove(path)
+		if err == nil || errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		time.Sleep(time.Duration(attempt) * 20 * time.Millisecond)
+	}
+	return err
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,7 +9,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ersonp/lore-core/internal/domain/entities"
+
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
 )
 
 func TestWorldsConfig_Add(t *testing.T) {
@@ -45,12 +49,12 @@ func TestWorldsConfig_AddToEmptyConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create config directory
-	configDir := filepath.Join(tmpDir, ".lore")
+	configDir := config.ConfigDir(tmpDir)
 	err := os.MkdirAll(configDir, 0755)
 	require.NoError(t, err)
 
 	// Load worlds (should return empty)
-	worlds, err := config.LoadWorlds(tmpDir)
+	worlds, err := config.LoadWorlds(configDir)
 	require.NoError(t, err)
 	assert.Empty(t, worlds.Worlds)
 
@@ -60,11 +64,11 @@ func TestWorldsConfig_AddToEmptyConfig(t *testing.T) {
 		Description: "New world",
 	})
 
-	err = worlds.Save(tmpDir)
+	err = worlds.Save(configDir)
 	require.NoError(t, err)
 
 	// Reload and verify
-	worlds, err = config.LoadWorlds(tmpDir)
+	worlds, err = config.LoadWorlds(configDir)
 	require.NoError(t, err)
 	assert.True(t, worlds.Exists("new-world"))
 }
@@ -167,12 +171,139 @@ func TestLoadWorlds_NoFile(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create config directory only
-	configDir := filepath.Join(tmpDir, ".lore")
+	configDir := config.ConfigDir(tmpDir)
 	err := os.MkdirAll(configDir, 0755)
 	require.NoError(t, err)
 
 	// Load worlds without file (should return empty)
-	worlds, err := config.LoadWorlds(tmpDir)
+	worlds, err := config.LoadWorlds(configDir)
 	require.NoError(t, err)
 	assert.Empty(t, worlds.Worlds)
 }
+
+func TestSQLiteSize(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "lore.db")
+
+	_, err := sqliteSize(dbPath)
+	assert.Error(t, err, "missing database")
+
+	require.NoError(t, os.WriteFile(dbPath, make([]byte, 100), 0644))
+	require.NoError(t, os.WriteFile(dbPath+"-wal", make([]byte, 20), 0644))
+
+	size, err := sqliteSize(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(120), size)
+}
+
+func TestRunWorldsRename_SameStorage(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+	t.Setenv(config.HomeEnv, "")
+	configDir := config.ConfigDir(projectDir)
+	ctx := context.Background()
+
+	require.NoError(t, config.WriteDefaultWithWorld(configDir, "Middle Earth", "Tolkien"))
+	require.NoError(t, initWorldSQLite(ctx, configDir, "Middle Earth"))
+
+	repo, err := sqlite.NewRepository(config.SQLiteConfig{Path: config.SQLitePathForWorld(configDir, "Middle Earth")})
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveEntity(ctx, &entities.Entity{
+		ID: "frodo", WorldID: "Middle Earth", Name: "Frodo", NormalizedName: "frodo",
+	}))
+	require.NoError(t, repo.Close())
+
+	// Both names map to the same collection and directory, so only the
+	// world ID changes.
+	cmd := newWorldsRenameCmd()
+	cmd.SetContext(ctx)
+	require.NoError(t, runWorldsRename(cmd, "Middle Earth", "middle-earth"))
+
+	worlds, err := config.LoadWorlds(configDir)
+	require.NoError(t, err)
+	assert.False(t, worlds.Exists("Middle Earth"))
+	entry, err := worlds.Get("middle-earth")
+	require.NoError(t, err)
+	assert.Equal(t, "lore_middle_earth", entry.Collection)
+	assert.Equal(t, "Tolkien", entry.Description)
+
+	repo, err = sqlite.NewRepository(config.SQLiteConfig{Path: config.SQLitePathForWorld(configDir, "middle-earth")})
+	require.NoError(t, err)
+	defer repo.Close()
+	count, err := repo.CountEntities(ctx, "middle-earth")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRunWorldsRename_Errors(t *testing.T) {
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+	t.Setenv(config.HomeEnv, "")
+	configDir := config.ConfigDir(projectDir)
+
+	require.NoError(t, config.WriteDefaultWithWorld(configDir, "alpha", ""))
+	worlds, err := config.LoadWorlds(configDir)
+	require.NoError(t, err)
+	worlds.Add("beta", config.WorldEntry{Collection: "lore_beta"})
+	require.NoError(t, worlds.Save(configDir))
+
+	tests := []struct {
+		name    string
+		oldName string
+		newName string
+		wantErr string
+	}{
+		{"missing world", "gamma", "delta", "not found"},
+		{"same name", "alpha", "alpha", "already named"},
+		{"name taken", "alpha", "beta", "already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newWorldsRenameCmd()
+			cmd.SetContext(context.Background())
+			err := runWorldsRename(cmd, tt.oldName, tt.newName)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWorldsConfig_FindByCollection(t *testing.T) {
+	worlds := &config.WorldsConfig{Worlds: map[string]config.WorldEntry{
+		"Мир": {Collection: config.GenerateCollectionName("Мир")},
+	}}
+
+	name, ok := worlds.FindByCollection("lore_mir")
+	assert.True(t, ok)
+	assert.Equal(t, "Мир", name)
+
+	_, ok = worlds.FindByCollection("lore_other")
+	assert.False(t, ok)
+}
+
+func TestCleanupWorldSQLite(t *testing.T) {
+	configDir := t.TempDir()
+	ctx := context.Background()
+
+	require.NoError(t, initWorldSQLite(ctx, configDir, "Мир"))
+	sqlitePath := config.SQLitePathForWorld(configDir, "Мир")
+	assert.Equal(t, filepath.Join(configDir, "worlds", "mir", "lore.db"), sqlitePath)
+	for _, suffix := range []string{"-wal", "-shm"} {
+		require.NoError(t, os.WriteFile(sqlitePath+suffix, nil, 0644))
+	}
+
+	cleanupWorldSQLite(configDir, "Мир")
+
+	assert.NoDirExists(t, config.WorldDir(configDir, "Мир"))
+}
+
+func TestRemoveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lore.db")
+
+	assert.NoError(t, removeFile(path), "missing files count as removed")
+
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	require.NoError(t, removeFile(path))
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// Page sizes for entity listings.
+const (
+	defaultEntityLimit = 50
+	maxEntityLimit     = 500
+)
+
+// Bounds on what an entity page includes.
+const (
+	entityFactLimit         = 50
+	entityRelationshipLimit = 100
+)
+
+func (s *Server) handleListEntities(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	limit, err := intParam(params, "limit", defaultEntityLimit, 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit = min(limit, maxEntityLimit)
+	offset, err := intParam(params, "offset", 0, 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var result *handlers.EntityListResult
+	if q := params.Get("q"); q != "" {
+		result, err = s.opts.Entities.HandleSearch(r.Context(), s.opts.World, q, limit)
+	} else {
+		result, err = s.opts.Entities.HandleList(r.Context(), s.opts.World, limit, offset)
+	}
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	if result.Entities == nil {
+		result.Entities = []*entities.Entity{}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+type entityFact struct {
+	entities.Fact
+	Tier services.MatchTier `json:"tier"` // How the fact matched the entity's name
+}
+
+type entityResponse struct {
+	Entity             *entities.Entity            `json:"entity"`
+	Facts              []entityFact                `json:"facts"`
+	Relationships      []handlers.RelationshipInfo `json:"relationships"`
+	TotalRelationships int                         `json:"total_relationships"`
+}
+
+// handleGetEntity returns an entity with the facts about it and its
+// relationships, for an entity page.
+func (s *Server) handleGetEntity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	entity, err := s.opts.Entities.HandleGet(ctx, s.opts.World, r.PathValue("name"))
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	resp := entityResponse{
+		Entity:        entity,
+		Facts:         []entityFact{},
+		Relationships: []handlers.RelationshipInfo{},
+	}
+
+	if s.opts.Query != nil {
+		found, err := s.opts.Query.HandleFindSubject(ctx, s.opts.World, entity.Name, entityFactLimit)
+		if err != nil {
+			writeError(w, statusFor(err), err)
+			return
+		}
+		for i := range found.Matches {
+			m := &found.Matches[i]
+			fact := withoutEmbeddings([]entities.Fact{m.Fact})[0]
+			resp.Facts = append(resp.Facts, entityFact{Fact: fact, Tier: m.Tier})
+		}
+	}
+
+	if s.opts.Relationships != nil {
+		rels, err := s.opts.Relationships.HandleList(ctx, s.opts.World, entity.Name, handlers.ListOptions{Limit: entityRelationshipLimit})
+		if err != nil {
+			writeError(w, statusFor(err), err)
+			return
+		}
+		resp.Relationships = rels.Relationships
+		resp.TotalRelationships = rels.Total
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleEntityCard returns an entity's hover card. Clients may cache it
+// as long as the server does, and revalidate it with its ETag.
+func (s *Server) handleEntityCard(w http.ResponseWriter, r *http.Request) {
+	card, err := s.opts.Cards.HandleCard(r.Context(), s.opts.World, r.PathValue("name"))
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	body, err := json.Marshal(card)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(s.opts.Cards.TTL().Seconds())))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, card)
+}
+
+// intParam parses the named query parameter, which must be at least lowest,
+// returning def if it is absent.
+func intParam(params url.Values, name string, def, lowest int) (int, error) {
+	raw := params.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lowest {
+		return 0, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	return n, nil
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newEntityServer() *Server {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "has_trait", Object: "brave", Embedding: []float32{0.1}},
+	}}
+	relationalDB := mocks.NewRelationalDB()
+	for _, e := range []*entities.Entity{
+		{ID: "e1", WorldID: "middle-earth", Name: "Frodo", NormalizedName: "frodo"},
+		{ID: "e2", WorldID: "middle-earth", Name: "Samwise", NormalizedName: "samwise"},
+		{ID: "e3", WorldID: "narnia", Name: "Aslan", NormalizedName: "aslan"},
+	} {
+		relationalDB.Entities[e.ID] = e
+	}
+
+	query := handlers.NewQueryHandler(services.NewQueryService(emb, db, relationalDB))
+	entityHandler := handlers.NewEntityHandler(services.NewEntityService(relationalDB, db))
+	relationships := handlers.NewRelationshipHandler(services.NewRelationshipService(db, relationalDB, emb), relationalDB)
+	return NewServer(&Options{
+		World:         "middle-earth",
+		Query:         query,
+		Entities:      entityHandler,
+		Relationships: relationships,
+		Cards:         handlers.NewCardHandler(entityHandler, query, relationships, time.Minute),
+	})
+}
+
+func TestServer_ListEntities(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		want   []string
+	}{
+		{"all in world", "/api/entities", []string{"Frodo", "Samwise"}},
+		{"by name", "/api/entities?q=sam", []string{"Samwise"}},
+		{"no match", "/api/entities?q=gollum", []string{}},
+	}
+
+	srv := newEntityServer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp handlers.EntityListResult
+			rec := doRequest(t, srv, http.MethodGet, tt.target, &resp)
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			names := []string{}
+			for _, e := range resp.Entities {
+				names = append(names, e.Name)
+			}
+			assert.ElementsMatch(t, tt.want, names)
+		})
+	}
+}
+
+func TestServer_ListEntities_BadRequest(t *testing.T) {
+	srv := newEntityServer()
+	for _, target := range []string{"/api/entities?limit=0", "/api/entities?offset=-1", "/api/entities?limit=x"} {
+		var resp errorResponse
+		rec := doRequest(t, srv, http.MethodGet, target, &resp)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		assert.Contains(t, resp.Error, "invalid")
+	}
+}
+
+func TestServer_GetEntity(t *testing.T) {
+	srv := newEntityServer()
+
+	var resp entityResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/entities/frodo", &resp)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Frodo", resp.Entity.Name)
+	require.Len(t, resp.Facts, 1)
+	assert.Equal(t, "brave", resp.Facts[0].Object)
+	assert.Equal(t, services.MatchExact, resp.Facts[0].Tier)
+	assert.Nil(t, resp.Facts[0].Embedding)
+	assert.NotNil(t, resp.Relationships)
+
+	var errResp errorResponse
+	rec = doRequest(t, srv, http.MethodGet, "/api/entities/aslan", &errResp)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "entities of other worlds are not found")
+	assert.Contains(t, errResp.Error, `entity "aslan" not found`)
+}
+
+func TestServer_EntityCard(t *testing.T) {
+	srv := newEntityServer()
+
+	var card handlers.EntityCard
+	rec := doRequest(t, srv, http.MethodGet, "/api/entities/frodo/card", &card)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "Frodo", card.Name)
+	assert.Equal(t, entities.FactTypeCharacter, card.Type)
+	require.Len(t, card.Facts, 1)
+	assert.Equal(t, "brave", card.Facts[0].Object)
+	assert.Equal(t, "private, max-age=60", rec.Header().Get("Cache-Control"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/entities/frodo/card", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/api/entities/gollum/card", nil).Code)
+}
+
+func TestServer_EntitiesNotServedWithoutHandler(t *testing.T) {
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	assert.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/api/entities", nil).Code)
+}
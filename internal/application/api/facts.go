@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// maxFactEditBytes bounds the body of a fact edit.
+const maxFactEditBytes = 64 << 10
+
+// factEditRequest holds the fields a fact edit changes. Empty fields keep
+// the fact's current value.
+type factEditRequest struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+	Context   string `json:"context"`
+}
+
+// staleFactResponse answers an edit based on a revision no longer current,
+// with both versions of the fact to resolve the conflict from.
+type staleFactResponse struct {
+	Error     string        `json:"error"`
+	Revision  string        `json:"revision"` // The current revision, to retry with
+	Current   entities.Fact `json:"current"`
+	Attempted entities.Fact `json:"attempted"`
+}
+
+// handleGetFact returns a fact, with its revision as the ETag.
+func (s *Server) handleGetFact(w http.ResponseWriter, r *http.Request) {
+	fact, err := s.opts.Facts.HandleGet(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	writeFact(w, fact)
+}
+
+// handleUpdateFact edits a fact. The request must send the ETag of the
+// revision it edited as If-Match, or "*" to edit whatever is current; an
+// edit to a fact changed since is refused with 412 Precondition Failed and
+// both versions.
+func (s *Server) handleUpdateFact(w http.ResponseWriter, r *http.Request) {
+	revision, ok := ifMatch(r)
+	if !ok {
+		writeError(w, http.StatusPreconditionRequired, errors.New("missing If-Match header with the fact's ETag"))
+		return
+	}
+
+	var req factEditRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFactEditBytes))
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	fact, err := s.opts.Facts.HandleUpdate(r.Context(), r.PathValue("id"), revision, services.FactEdit{
+		Subject:   strings.TrimSpace(req.Subject),
+		Predicate: strings.TrimSpace(req.Predicate),
+		Object:    strings.TrimSpace(req.Object),
+		Context:   strings.TrimSpace(req.Context),
+	})
+	var stale *entities.StaleFactError
+	if errors.As(err, &stale) {
+		w.Header().Set("ETag", etag(stale.Current.Revision()))
+		writeJSON(w, http.StatusPreconditionFailed, staleFactResponse{
+			Error:     err.Error(),
+			Revision:  stale.Current.Revision(),
+			Current:   stale.Current,
+			Attempted: stale.Attempted,
+		})
+		return
+	}
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+	if s.opts.Cards != nil {
+		s.opts.Cards.Invalidate()
+	}
+	writeFact(w, fact)
+}
+
+// writeFact sends a fact without its embeddings, with its revision as the
+// ETag.
+func writeFact(w http.ResponseWriter, fact *entities.Fact) {
+	w.Header().Set("ETag", etag(fact.Revision()))
+	writeJSON(w, http.StatusOK, withoutEmbeddings([]entities.Fact{*fact})[0])
+}
+
+// ifMatch returns the revision a request's If-Match header names, empty
+// for "*", and whether it has one.
+func ifMatch(r *http.Request) (string, bool) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return "", false
+	}
+	if value == "*" {
+		return "", true
+	}
+	value = strings.TrimPrefix(value, "W/")
+	return strings.Trim(value, `"`), true
+}
+
+// etag quotes a revision as an entity tag.
+func etag(revision string) string {
+	return `"` + revision + `"`
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newFactServer() (*Server, *mocks.VectorDB) {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "has_trait", Object: "brave", Embedding: []float32{0.1}},
+	}}
+	facts := handlers.NewFactHandler(services.NewFactService(emb, db, mocks.NewRelationalDB(), nil))
+	return NewServer(&Options{World: "middle-earth", Facts: facts}), db
+}
+
+func patchFact(t *testing.T, srv *Server, id, ifMatch, body string, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPatch, "/api/facts/"+id, strings.NewReader(body))
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+	return rec
+}
+
+func TestServer_GetFact(t *testing.T) {
+	srv, db := newFactServer()
+
+	var fact entities.Fact
+	rec := doRequest(t, srv, http.MethodGet, "/api/facts/1", &fact)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "brave", fact.Object)
+	assert.Nil(t, fact.Embedding)
+	assert.Equal(t, `"`+db.Facts[0].Revision()+`"`, rec.Header().Get("ETag"))
+
+	assert.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/api/facts/2", nil).Code)
+}
+
+func TestServer_UpdateFact(t *testing.T) {
+	srv, db := newFactServer()
+	etag := doRequest(t, srv, http.MethodGet, "/api/facts/1", nil).Header().Get("ETag")
+
+	var fact entities.Fact
+	rec := patchFact(t, srv, "1", etag, `{"object": "bold"}`, &fact)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "bold", fact.Object)
+	assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	require.Len(t, db.SavedFacts, 1)
+
+	rec = patchFact(t, srv, "1", "", `{"object": "bold"}`, nil)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+	rec = patchFact(t, srv, "1", "*", `{"object": "bold"}`, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, "* edits whatever is current")
+
+	rec = patchFact(t, srv, "2", etag, `{"object": "bold"}`, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_UpdateFact_Stale(t *testing.T) {
+	srv, db := newFactServer()
+	etag := doRequest(t, srv, http.MethodGet, "/api/facts/1", nil).Header().Get("ETag")
+
+	// Another writer changes the fact after it was read
+	db.Facts[0].Object = "fearless"
+
+	var resp staleFactResponse
+	rec := patchFact(t, srv, "1", etag, `{"object": "bold"}`, &resp)
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "fearless", resp.Current.Object)
+	assert.Equal(t, "bold", resp.Attempted.Object)
+	assert.Nil(t, resp.Current.Embedding)
+	assert.Equal(t, db.Facts[0].Revision(), resp.Revision)
+	assert.Equal(t, `"`+resp.Revision+`"`, rec.Header().Get("ETag"))
+	assert.Empty(t, db.SavedFacts, "nothing is saved")
+
+	rec = patchFact(t, srv, "1", rec.Header().Get("ETag"), `{"object": "bold"}`, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, "retrying with the current revision succeeds")
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// maxIngestBytes bounds the body of an ingest request.
+const maxIngestBytes = 10 << 20
+
+// defaultIngestSource is recorded as the source file of facts ingested
+// without a source.
+const defaultIngestSource = "api"
+
+// idempotencyHeader carries an ingest request's idempotency key.
+const idempotencyHeader = "Idempotency-Key"
+
+// Events sent by the streaming ingest endpoint, in order: one chunk event
+// per chunk, one issue event per consistency issue, then done or error.
+const (
+	eventChunk = "chunk"
+	eventIssue = "issue"
+	eventDone  = "done"
+	eventError = "error"
+)
+
+type ingestRequest struct {
+	Text             string `json:"text"`
+	Source           string `json:"source"`
+	CheckConsistency bool   `json:"check_consistency"`
+	CheckOnly        bool   `json:"check_only"` // Check without saving; implies check_consistency
+	ResolvePronouns  bool   `json:"resolve_pronouns"`
+	CarryContext     bool   `json:"carry_context"`
+}
+
+type chunkEvent struct {
+	Index int             `json:"index"`
+	Facts []entities.Fact `json:"facts"`
+}
+
+type doneEvent struct {
+	Source  string `json:"source"`
+	Facts   int    `json:"facts"`
+	Pending int    `json:"pending"`
+	Issues  int    `json:"issues"`
+	Saved   bool   `json:"saved"`
+
+	// Replayed marks the outcome of an earlier request with the same
+	// idempotency key, sent instead of ingesting the text again.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// ingestOutcome is what an ingest sends after its chunk events, recorded
+// for its idempotency key.
+type ingestOutcome struct {
+	Issues []ports.ConsistencyIssue `json:"issues"`
+	Done   doneEvent                `json:"done"`
+}
+
+type errorEvent struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"` // HTTP status the error would have had
+}
+
+// handleIngestStream ingests the posted text and streams progress as
+// server-sent events while it runs. Requests that are malformed fail
+// before the stream starts, with an ordinary JSON error. A request retried
+// with the same Idempotency-Key header is sent the issues and done events
+// of the first instead of ingesting again.
+func (s *Server) handleIngestStream(w http.ResponseWriter, r *http.Request) {
+	req, ok := decodeIngestRequest(w, r)
+	if !ok {
+		return
+	}
+	send, ok := startEventStream(w)
+	if !ok {
+		return
+	}
+
+	// Checks save nothing, so they are safe to repeat
+	key := r.Header.Get(idempotencyHeader)
+	if req.CheckOnly {
+		key = ""
+	}
+	outcome, replayed, err := services.Idempotent(r.Context(), s.opts.Idempotency, key, "ingest", req, func() (ingestOutcome, error) {
+		return s.ingestText(r.Context(), req, send)
+	})
+	if err != nil {
+		send(eventError, errorEvent{Error: err.Error(), Status: statusFor(err)})
+		return
+	}
+
+	if !req.CheckOnly && !replayed && s.opts.Cards != nil {
+		s.opts.Cards.Invalidate()
+	}
+	for i := range outcome.Issues {
+		send(eventIssue, &outcome.Issues[i])
+	}
+	outcome.Done.Replayed = replayed
+	send(eventDone, outcome.Done)
+}
+
+// decodeIngestRequest reads an ingest request, answering with an error
+// and returning false if it is malformed.
+func decodeIngestRequest(w http.ResponseWriter, r *http.Request) (ingestRequest, bool) {
+	var req ingestRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIngestBytes))
+	if err := dec.Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+			return req, false
+		}
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return req, false
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing text"))
+		return req, false
+	}
+	if req.Source == "" {
+		req.Source = defaultIngestSource
+	}
+	return req, true
+}
+
+// startEventStream starts answering with server-sent events and returns
+// a function sending each one as it is written. It answers with an error
+// and returns false if w cannot stream.
+func startEventStream(w http.ResponseWriter) (send func(event string, v any), ok bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return func(event string, v any) {
+		if err := writeEvent(w, event, v); err != nil {
+			log.Printf("warning: failed to write %s event: %v", event, err)
+			return
+		}
+		flusher.Flush()
+	}, true
+}
+
+// ingestText ingests the text of req, sending a chunk event as each chunk
+// is extracted, and returns the events to send once it is done.
+func (s *Server) ingestText(ctx context.Context, req ingestRequest, send func(event string, v any)) (ingestOutcome, error) {
+	result, err := s.opts.Ingest.HandleReader(ctx, strings.NewReader(req.Text), req.Source, &handlers.IngestOptions{
+		CheckConsistency: req.CheckConsistency || req.CheckOnly,
+		CheckOnly:        req.CheckOnly,
+		ResolvePronouns:  req.ResolvePronouns,
+		CarryContext:     req.CarryContext,
+		WorldID:          s.opts.World,
+		ReviewThreshold:  s.opts.ReviewThreshold,
+		Retrieval:        s.opts.Retrieval,
+		Sources:          s.opts.Sources,
+		OnChunk: func(p services.ChunkProgress) {
+			send(eventChunk, chunkEvent{Index: p.Index, Facts: withoutEmbeddings(p.Facts)})
+		},
+	})
+	if err != nil {
+		return ingestOutcome{}, err
+	}
+	outcome := ingestOutcome{
+		Issues: make([]ports.ConsistencyIssue, len(result.Issues)),
+		Done: doneEvent{
+			Source:  result.FilePath,
+			Facts:   result.FactsCount,
+			Pending: result.PendingCount,
+			Issues:  len(result.Issues),
+			Saved:   !req.CheckOnly,
+		},
+	}
+	for i := range result.Issues {
+		outcome.Issues[i] = withoutIssueEmbeddings(&result.Issues[i])
+	}
+	return outcome, nil
+}
+
+// writeEvent writes v as one server-sent event.
+func writeEvent(w http.ResponseWriter, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// withoutEmbeddings returns copies of facts without their vectors, which
+// clients have no use for.
+func withoutEmbeddings(facts []entities.Fact) []entities.Fact {
+	out := make([]entities.Fact, len(facts))
+	for i := range facts {
+		out[i] = facts[i]
+		out[i].Embedding = nil
+		out[i].TextEmbedding = nil
+	}
+	return out
+}
+
+func withoutIssueEmbeddings(issue *ports.ConsistencyIssue) ports.ConsistencyIssue {
+	stripped := *issue
+	stripped.NewFact.Embedding, stripped.NewFact.TextEmbedding = nil, nil
+	stripped.ExistingFact.Embedding, stripped.ExistingFact.TextEmbedding = nil, nil
+	return stripped
+}
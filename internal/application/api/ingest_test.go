@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// newIngestServer creates a server that ingests with llm into db, which
+// already holds a fact about Frodo's eyes.
+func newIngestServer(llm *mocks.LLMClient) (*Server, *mocks.VectorDB) {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue", Embedding: []float32{0.1}},
+	}}
+	relationalDB := mocks.NewRelationalDB()
+	for _, et := range entities.DefaultEntityTypes {
+		relationalDB.Types[et.Name] = &et
+	}
+
+	extraction := services.NewExtractionService(llm, emb, db, services.NewEntityTypeService(relationalDB))
+	return NewServer(&Options{
+		World:  "middle-earth",
+		Ingest: handlers.NewIngestHandler(extraction, handlers.WithConflicts(services.NewConflictService(llm, db, relationalDB))),
+	}), db
+}
+
+func postIngest(t *testing.T, srv *Server, body string) (*httptest.ResponseRecorder, []sseEvent) {
+	t.Helper()
+	return serveIngest(t, srv, httptest.NewRequest(http.MethodPost, "/api/ingest/stream", strings.NewReader(body)))
+}
+
+// serveIngest serves an ingest request and parses the events sent.
+func serveIngest(t *testing.T, srv *Server, req *http.Request) (*httptest.ResponseRecorder, []sseEvent) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Header().Get("Content-Type") != "text/event-stream" {
+		return rec, nil
+	}
+
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.Data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return rec, events
+}
+
+func TestServer_IngestStream(t *testing.T) {
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "green"},
+		},
+		Issues: []ports.ConsistencyIssue{
+			{ExistingFact: entities.Fact{ID: "1", Subject: "Frodo", Embedding: []float32{0.1}}, Description: "eye colors differ", Severity: "major"},
+		},
+	}
+	srv, db := newIngestServer(llm)
+
+	text := "Frodo has green eyes. " + strings.Repeat("a", 1500) + "\n\n" + "Frodo left the Shire. " + strings.Repeat("b", 1500)
+	body, err := json.Marshal(ingestRequest{Text: text, Source: "chapter1.md", CheckConsistency: true})
+	require.NoError(t, err)
+
+	rec, events := postIngest(t, srv, string(body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, events, 4)
+	assert.Equal(t, []string{eventChunk, eventChunk, eventIssue, eventDone}, []string{
+		events[0].Event, events[1].Event, events[2].Event, events[3].Event,
+	})
+
+	var chunk chunkEvent
+	require.NoError(t, json.Unmarshal([]byte(events[1].Data), &chunk))
+	assert.Equal(t, 1, chunk.Index)
+	require.Len(t, chunk.Facts, 1)
+	assert.Equal(t, "chapter1.md", chunk.Facts[0].SourceFile)
+
+	var issue ports.ConsistencyIssue
+	require.NoError(t, json.Unmarshal([]byte(events[2].Data), &issue))
+	assert.Equal(t, "eye colors differ", issue.Description)
+	assert.NotContains(t, events[2].Data, "embedding")
+
+	var done doneEvent
+	require.NoError(t, json.Unmarshal([]byte(events[3].Data), &done))
+	assert.Equal(t, doneEvent{Source: "chapter1.md", Facts: 2, Issues: 1, Saved: true}, done)
+	assert.Equal(t, 1, db.SaveBatchCallCount)
+}
+
+func TestServer_IngestStream_CheckOnly(t *testing.T) {
+	llm := &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a hobbit"},
+	}}
+	srv, db := newIngestServer(llm)
+
+	_, events := postIngest(t, srv, `{"text": "Frodo is a hobbit.", "check_only": true}`)
+	require.Len(t, events, 2)
+
+	var done doneEvent
+	require.NoError(t, json.Unmarshal([]byte(events[1].Data), &done))
+	assert.Equal(t, doneEvent{Source: defaultIngestSource, Facts: 1}, done)
+	assert.Zero(t, db.SaveBatchCallCount)
+	assert.Equal(t, 1, llm.CheckConsistencyCallCount, "check_only implies check_consistency")
+}
+
+func TestServer_IngestStream_IdempotencyKey(t *testing.T) {
+	llm := &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a hobbit"},
+	}}
+	srv, db := newIngestServer(llm)
+	srv.opts.Idempotency = services.NewIdempotencyService(mocks.NewRelationalDB(), 0)
+
+	post := func(body, key string) []sseEvent {
+		req := httptest.NewRequest(http.MethodPost, "/api/ingest/stream", strings.NewReader(body))
+		req.Header.Set(idempotencyHeader, key)
+		_, events := serveIngest(t, srv, req)
+		return events
+	}
+
+	events := post(`{"text": "Frodo is a hobbit."}`, "job-1")
+	require.Len(t, events, 2)
+	assert.Equal(t, 1, db.SaveBatchCallCount)
+
+	events = post(`{"text": "Frodo is a hobbit."}`, "job-1")
+	require.Len(t, events, 1, "no chunks are extracted again")
+	var done doneEvent
+	require.NoError(t, json.Unmarshal([]byte(events[0].Data), &done))
+	assert.Equal(t, doneEvent{Source: defaultIngestSource, Facts: 1, Saved: true, Replayed: true}, done)
+	assert.Equal(t, 1, db.SaveBatchCallCount)
+	assert.Equal(t, 1, llm.ExtractFactsCallCount)
+
+	events = post(`{"text": "Sam is a gardener."}`, "job-1")
+	require.Len(t, events, 1)
+	assert.Equal(t, eventError, events[0].Event)
+	assert.Contains(t, events[0].Data, `"status":409`)
+}
+
+func TestServer_IngestStream_Error(t *testing.T) {
+	llm := &mocks.LLMClient{ExtractErr: entities.WithKind(entities.ErrBackendUnavailable, errors.New("rate limited"))}
+	srv, _ := newIngestServer(llm)
+
+	rec, events := postIngest(t, srv, `{"text": "Frodo is a hobbit."}`)
+	assert.Equal(t, http.StatusOK, rec.Code, "the stream has started when extraction fails")
+	require.Len(t, events, 1)
+	assert.Equal(t, eventError, events[0].Event)
+
+	var resp errorEvent
+	require.NoError(t, json.Unmarshal([]byte(events[0].Data), &resp))
+	assert.Contains(t, resp.Error, "rate limited")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Status)
+}
+
+func TestServer_IngestStream_BadRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"malformed", `{"text":`, http.StatusBadRequest},
+		{"missing text", `{"source": "chapter1.md"}`, http.StatusBadRequest},
+		{"blank text", `{"text": "  \n"}`, http.StatusBadRequest},
+		{"too large", `{"text": "` + strings.Repeat("a", maxIngestBytes) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+
+	srv, _ := newIngestServer(&mocks.LLMClient{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, events := postIngest(t, srv, tt.body)
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Nil(t, events)
+		})
+	}
+}
+
+func TestServer_IngestStream_NotServedWithoutHandler(t *testing.T) {
+	srv := newTestServer(&mocks.SnapshotStorage{})
+
+	rec, _ := postIngest(t, srv, `{"text": "Frodo is a hobbit."}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxTrackedClients bounds the limiter's memory. Beyond it, clients whose
+// allowance has fully refilled are forgotten.
+const maxTrackedClients = 10000
+
+// rateLimiter gives each client a token bucket holding up to limit
+// requests, refilled evenly over period.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   float64
+	perSec  float64
+	clients map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit int, period time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   float64(limit),
+		perSec:  float64(limit) / period.Seconds(),
+		clients: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// allow takes a token from the client's bucket. If it is empty, allow
+// returns false and how long until a token is available.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= maxTrackedClients {
+			l.forgetIdle(now)
+		}
+		b = &bucket{tokens: l.limit, last: now}
+		l.clients[client] = b
+	}
+
+	b.tokens = math.Min(l.limit, b.tokens+now.Sub(b.last).Seconds()*l.perSec)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.perSec * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// forgetIdle drops clients whose buckets would be full by now.
+func (l *rateLimiter) forgetIdle(now time.Time) {
+	for client, b := range l.clients {
+		if b.tokens+now.Sub(b.last).Seconds()*l.perSec >= l.limit {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// clientKey identifies the client of a request by its IP address.
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
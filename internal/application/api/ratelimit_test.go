@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for range 3 {
+		ok, _ := l.allow("10.0.0.1")
+		assert.True(t, ok)
+	}
+	ok, wait := l.allow("10.0.0.1")
+	assert.False(t, ok)
+	assert.Equal(t, 20*time.Second, wait)
+
+	// Other clients have their own allowance.
+	ok, _ = l.allow("10.0.0.2")
+	assert.True(t, ok)
+
+	now = now.Add(20 * time.Second)
+	ok, _ = l.allow("10.0.0.1")
+	assert.True(t, ok)
+	ok, _ = l.allow("10.0.0.1")
+	assert.False(t, ok)
+}
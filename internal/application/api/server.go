@@ -0,0 +1,429 @@
+// Package api exposes lore over HTTP for 'lore serve'.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/readiness"
+	"github.com/ersonp/lore-core/internal/application/scheduler"
+	"github.com/ersonp/lore-core/internal/application/webui"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// Authorizer checks the bearer tokens of API requests.
+type Authorizer interface {
+	// Enabled reports whether requests must present a token.
+	Enabled() bool
+
+	// Authorize returns the name of token and whether it may change world.
+	// name is empty if the token is unknown, and ok is false if it may
+	// not use world at all.
+	Authorize(token, world string) (name string, canWrite, ok bool)
+}
+
+// defaultQueryLimit is used when a query request has no limit parameter.
+const defaultQueryLimit = 10
+
+// shutdownTimeout bounds how long in-flight requests may run after the
+// server is asked to stop.
+const shutdownTimeout = 10 * time.Second
+
+// Options configures a Server.
+type Options struct {
+	World        string
+	Query        *handlers.QueryHandler
+	Snapshots    *handlers.SnapshotHandler    // Snapshot endpoints are not served if nil
+	Ingest       *handlers.IngestHandler      // Streaming ingest is not served if nil
+	Entities     *handlers.EntityHandler      // Entity endpoints are not served if nil
+	SnapshotKeep int                          // Retention applied after on-demand snapshots
+	Jobs         func() []scheduler.JobStatus // Background job status (nil = none)
+	EntityCache  func() ports.CacheStats      // Entity cache statistics (nil = none)
+
+	// Relationships are listed on entity pages (nil = none).
+	Relationships *handlers.RelationshipHandler
+
+	// Cards serves entity hover cards for editors (nil = not served).
+	// Ingesting through the API drops the cached cards.
+	Cards *handlers.CardHandler
+
+	// Facts serves facts by ID and edits to them (nil = not served).
+	// Edits must send the revision they were based on as If-Match.
+	Facts *handlers.FactHandler
+
+	// Idempotency remembers ingests sent with an Idempotency-Key header,
+	// so retries don't ingest twice (nil = the header is ignored).
+	Idempotency *services.IdempotencyService
+
+	// ReviewThreshold holds ingested facts with lower confidence for
+	// review (0 = off).
+	ReviewThreshold float64
+
+	// Retrieval selects the stored facts ingested facts are checked
+	// against for consistency.
+	Retrieval services.Retrieval
+
+	// Sources give ingested facts the metadata and tags of their source.
+	Sources services.SourceRules
+
+	// Auth checks the bearer tokens allowed to use World. Tokens that may
+	// not write may only make GET requests. Nil or disabled means no
+	// authentication.
+	Auth Authorizer
+
+	// ReadCache reports how current the facts being served are, when they
+	// are answered from a copy of the world (nil = answered live). API
+	// responses then carry the copy's age: an Age header in seconds,
+	// X-Lore-Synced-At, and X-Lore-Max-Staleness in seconds.
+	ReadCache func() ports.ReplicaStatus
+
+	// ReadOnly leaves out the endpoints that write: ingest, fact edits,
+	// and on-demand snapshots.
+	ReadOnly bool
+
+	// Readiness reports whether the backends are reachable, for /readyz.
+	// While it is degraded, API requests other than status fail fast with
+	// 503 Service Unavailable. Nil means always ready.
+	Readiness *readiness.Monitor
+
+	UI            bool // Serve the web UI under /ui/
+	Demo          bool // Serving the bundled sample world, reported in status
+	RateLimit     int  // Requests per minute per client IP (0 = unlimited)
+	MaxQueryLimit int  // Largest limit a query may ask for (0 = no cap)
+}
+
+// Server serves the lore HTTP API.
+type Server struct {
+	opts    Options
+	mux     *http.ServeMux
+	limiter *rateLimiter // nil when unlimited
+}
+
+// NewServer creates a new API server.
+func NewServer(opts *Options) *Server {
+	s := &Server{opts: *opts, mux: http.NewServeMux()}
+	if opts.RateLimit > 0 {
+		s.limiter = newRateLimiter(opts.RateLimit, time.Minute)
+	}
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+	s.mux.HandleFunc("GET /readyz", s.handleReadyz)
+	s.mux.HandleFunc("GET /api/status", s.handleStatus)
+	s.mux.HandleFunc("GET /api/query", s.handleQuery)
+	if opts.Snapshots != nil {
+		s.mux.HandleFunc("GET /api/snapshots", s.handleListSnapshots)
+		if !opts.ReadOnly {
+			s.mux.HandleFunc("POST /api/snapshots", s.handleCreateSnapshot)
+		}
+	}
+	if opts.Ingest != nil && !opts.ReadOnly {
+		s.mux.HandleFunc("POST /api/ingest/stream", s.handleIngestStream)
+	}
+	if opts.Entities != nil {
+		s.mux.HandleFunc("GET /api/entities", s.handleListEntities)
+		s.mux.HandleFunc("GET /api/entities/{name}", s.handleGetEntity)
+	}
+	if opts.Cards != nil {
+		s.mux.HandleFunc("GET /api/entities/{name}/card", s.handleEntityCard)
+	}
+	if opts.Facts != nil {
+		s.mux.HandleFunc("GET /api/facts/{id}", s.handleGetFact)
+		if !opts.ReadOnly {
+			s.mux.HandleFunc("PATCH /api/facts/{id}", s.handleUpdateFact)
+		}
+	}
+	if opts.UI {
+		s.mux.Handle("GET /ui/", http.StripPrefix("/ui/", webui.Handler()))
+		s.mux.Handle("GET /{$}", http.RedirectHandler("/ui/", http.StatusFound))
+	}
+	return s
+}
+
+// ServeHTTP implements http.Handler. Clients over the rate limit are
+// answered 429 Too Many Requests with a Retry-After header, requests
+// without a valid token 401 Unauthorized or 403 Forbidden, and API
+// requests while the backends are degraded 503 Service Unavailable.
+// Health probes and the web UI's files are exempt from all three; the UI
+// asks for a token itself.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isPublic(r.URL.Path) {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	if s.limiter != nil {
+		if ok, wait := s.limiter.allow(clientKey(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, fmt.Errorf("rate limit of %d requests per minute exceeded", s.opts.RateLimit))
+			return
+		}
+	}
+	if s.opts.Auth != nil && s.opts.Auth.Enabled() {
+		if status, err := s.authorize(r); err != nil {
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="lore"`)
+			}
+			writeError(w, status, err)
+			return
+		}
+	}
+	if s.opts.Readiness != nil && r.URL.Path != "/api/status" {
+		if err := s.opts.Readiness.Err(); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+	}
+	if s.opts.ReadCache != nil {
+		setStalenessHeaders(w, s.opts.ReadCache())
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// setStalenessHeaders tells clients how old the copy answering them is.
+func setStalenessHeaders(w http.ResponseWriter, status ports.ReplicaStatus) {
+	w.Header().Set("Age", strconv.FormatInt(status.AgeSeconds, 10))
+	if !status.SyncedAt.IsZero() {
+		w.Header().Set("X-Lore-Synced-At", status.SyncedAt.UTC().Format(time.RFC3339))
+	}
+	if status.MaxStalenessSeconds > 0 {
+		w.Header().Set("X-Lore-Max-Staleness", strconv.FormatInt(status.MaxStalenessSeconds, 10))
+	}
+}
+
+// isPublic reports whether path is a health probe or part of the web UI.
+func isPublic(path string) bool {
+	switch path {
+	case "/healthz", "/readyz", "/", "/ui":
+		return true
+	}
+	return strings.HasPrefix(path, "/ui/")
+}
+
+// authorize checks the request's bearer token may use the world for the
+// request's method, returning the status to answer with if not.
+func (s *Server) authorize(r *http.Request) (int, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, errors.New("missing bearer token")
+	}
+
+	name, canWrite, ok := s.opts.Auth.Authorize(token, s.opts.World)
+	switch {
+	case name == "":
+		return http.StatusUnauthorized, errors.New("invalid bearer token")
+	case !ok:
+		return http.StatusForbidden, fmt.Errorf("token %q has no access to world %s", name, s.opts.World)
+	}
+	if !canWrite && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return http.StatusForbidden, fmt.Errorf("token %q may only read world %s", name, s.opts.World)
+	}
+	return 0, nil
+}
+
+// ListenAndServe serves on addr until ctx is canceled, then shuts down
+// gracefully.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serving http: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down http server: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving http: %w", err)
+	}
+	return nil
+}
+
+// handleHealthz reports the server is up, whatever the backends' state.
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": readiness.StatusOK})
+}
+
+// handleReadyz reports the latest readiness checks, with 503 Service
+// Unavailable until every check has passed.
+func (s *Server) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	report := s.readiness()
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+func (s *Server) readiness() readiness.Report {
+	if s.opts.Readiness == nil {
+		return readiness.Report{Status: readiness.StatusOK, Checks: []readiness.CheckResult{}}
+	}
+	return s.opts.Readiness.Report()
+}
+
+type statusResponse struct {
+	World       string                `json:"world"`
+	Demo        bool                  `json:"demo,omitempty"`
+	ReadOnly    bool                  `json:"read_only,omitempty"`
+	Jobs        []scheduler.JobStatus `json:"jobs"`
+	EntityCache *ports.CacheStats     `json:"entity_cache,omitempty"`
+	ReadCache   *ports.ReplicaStatus  `json:"read_cache,omitempty"`
+	Readiness   readiness.Report      `json:"readiness"`
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	resp := statusResponse{
+		World:     s.opts.World,
+		Demo:      s.opts.Demo,
+		ReadOnly:  s.opts.ReadOnly,
+		Jobs:      s.jobs(),
+		Readiness: s.readiness(),
+	}
+	if s.opts.EntityCache != nil {
+		stats := s.opts.EntityCache()
+		resp.EntityCache = &stats
+	}
+	if s.opts.ReadCache != nil {
+		status := s.opts.ReadCache()
+		resp.ReadCache = &status
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type queryResponse struct {
+	Query string          `json:"query"`
+	Facts []entities.Fact `json:"facts"`
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+
+	query := params.Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("missing query parameter q"))
+		return
+	}
+
+	limit, err := intParam(params, "limit", defaultQueryLimit, 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if s.opts.MaxQueryLimit > 0 {
+		limit = min(limit, s.opts.MaxQueryLimit)
+	}
+
+	mode := services.SearchMode(params.Get("mode"))
+	if mode == "" {
+		mode = services.SearchModeContext
+	}
+	if !mode.IsValid() {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid mode %q", mode))
+		return
+	}
+
+	result, err := s.opts.Query.HandleWithOptions(r.Context(), query, handlers.QueryOptions{
+		Type:  entities.FactType(params.Get("type")),
+		Limit: limit,
+		Mode:  mode,
+	})
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	facts := result.Facts
+	if facts == nil {
+		facts = []entities.Fact{}
+	}
+	writeJSON(w, http.StatusOK, queryResponse{Query: result.Query, Facts: facts})
+}
+
+type snapshotsResponse struct {
+	Snapshots []entities.Snapshot   `json:"snapshots"`
+	Jobs      []scheduler.JobStatus `json:"jobs"`
+}
+
+func (s *Server) handleListSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := s.opts.Snapshots.HandleList(r.Context())
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, snapshotsResponse{
+		Snapshots: snapshots,
+		Jobs:      s.jobs(),
+	})
+}
+
+func (s *Server) handleCreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	result, err := s.opts.Snapshots.HandleCreate(r.Context(), s.opts.SnapshotKeep)
+	if err != nil {
+		writeError(w, statusFor(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, result)
+}
+
+func (s *Server) jobs() []scheduler.JobStatus {
+	if s.opts.Jobs == nil {
+		return []scheduler.JobStatus{}
+	}
+	return s.opts.Jobs()
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// statusFor maps an error to the HTTP status for its kind.
+func statusFor(err error) int {
+	switch entities.ErrorKind(err) {
+	case entities.ErrValidation:
+		return http.StatusBadRequest
+	case entities.ErrNotFound:
+		return http.StatusNotFound
+	case entities.ErrConflict:
+		return http.StatusConflict
+	case entities.ErrBackendUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("warning: failed to write response: %v", err)
+	}
+}
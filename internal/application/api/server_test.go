@@ -0,0 +1,365 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/application/readiness"
+	"github.com/ersonp/lore-core/internal/application/scheduler"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newTestServer(storage *mocks.SnapshotStorage) *Server {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "has_trait", Object: "brave"},
+	}}
+
+	return NewServer(&Options{
+		World:        "middle-earth",
+		Query:        handlers.NewQueryHandler(services.NewQueryService(emb, db, &mocks.RelationalDB{})),
+		Snapshots:    handlers.NewSnapshotHandler(services.NewSnapshotService("middle-earth", storage, storage, storage)),
+		SnapshotKeep: 1,
+		Jobs: func() []scheduler.JobStatus {
+			return []scheduler.JobStatus{{Name: "snapshot", Schedule: "0 3 * * *"}}
+		},
+		EntityCache: func() ports.CacheStats {
+			return ports.CacheStats{Hits: 3, Misses: 1, Size: 1, Capacity: 1024}
+		},
+	})
+}
+
+func doRequest(t *testing.T, srv *Server, method, target string, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	}
+	return rec
+}
+
+func TestServer_Query(t *testing.T) {
+	srv := newTestServer(&mocks.SnapshotStorage{})
+
+	var resp queryResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/query?q=brave&limit=5", &resp)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "brave", resp.Query)
+	require.Len(t, resp.Facts, 1)
+	assert.Equal(t, "Frodo", resp.Facts[0].Subject)
+}
+
+func TestServer_Query_BadRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+	}{
+		{"missing query", "/api/query"},
+		{"invalid limit", "/api/query?q=x&limit=-1"},
+		{"invalid mode", "/api/query?q=x&mode=nope"},
+	}
+
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp errorResponse
+			rec := doRequest(t, srv, http.MethodGet, tt.target, &resp)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.NotEmpty(t, resp.Error)
+		})
+	}
+}
+
+func TestServer_Snapshots(t *testing.T) {
+	storage := &mocks.SnapshotStorage{
+		Snapshots: map[string]entities.Snapshot{
+			"old": {Name: "old", CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	srv := newTestServer(storage)
+
+	var created handlers.SnapshotResult
+	rec := doRequest(t, srv, http.MethodPost, "/api/snapshots", &created)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, []string{"old"}, created.Pruned)
+
+	var list snapshotsResponse
+	rec = doRequest(t, srv, http.MethodGet, "/api/snapshots", &list)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, list.Snapshots, 1)
+	assert.Equal(t, created.Snapshot.Name, list.Snapshots[0].Name)
+	require.Len(t, list.Jobs, 1)
+	assert.Equal(t, "snapshot", list.Jobs[0].Name)
+}
+
+func TestServer_Status(t *testing.T) {
+	srv := newTestServer(&mocks.SnapshotStorage{})
+
+	var resp statusResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/status", &resp)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "middle-earth", resp.World)
+	assert.Len(t, resp.Jobs, 1)
+	require.NotNil(t, resp.EntityCache)
+	assert.Equal(t, uint64(3), resp.EntityCache.Hits)
+}
+
+func TestServer_ReadCache(t *testing.T) {
+	syncedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	srv.opts.ReadOnly = true
+	srv.opts.ReadCache = func() ports.ReplicaStatus {
+		return ports.ReplicaStatus{Facts: 1, SyncedAt: syncedAt, AgeSeconds: 42, MaxStalenessSeconds: 300}
+	}
+	srv = NewServer(&srv.opts)
+
+	rec := doRequest(t, srv, http.MethodGet, "/api/query?q=brave", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "42", rec.Header().Get("Age"))
+	assert.Equal(t, "2024-03-01T12:00:00Z", rec.Header().Get("X-Lore-Synced-At"))
+	assert.Equal(t, "300", rec.Header().Get("X-Lore-Max-Staleness"))
+
+	var status statusResponse
+	doRequest(t, srv, http.MethodGet, "/api/status", &status)
+	assert.True(t, status.ReadOnly)
+	require.NotNil(t, status.ReadCache)
+	assert.Equal(t, 1, status.ReadCache.Facts)
+
+	assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/api/snapshots", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, doRequest(t, srv, http.MethodPost, "/api/snapshots", nil).Code, "writes are not served")
+}
+
+func TestServer_ErrorStatus(t *testing.T) {
+	storage := &mocks.SnapshotStorage{
+		BackupErr: entities.WithKind(entities.ErrBackendUnavailable, errors.New("database is locked")),
+	}
+	srv := newTestServer(storage)
+
+	var resp errorResponse
+	rec := doRequest(t, srv, http.MethodPost, "/api/snapshots", &resp)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, resp.Error, "database is locked")
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{entities.Errorf(entities.ErrValidation, "bad"), http.StatusBadRequest},
+		{entities.Errorf(entities.ErrNotFound, "missing"), http.StatusNotFound},
+		{entities.Errorf(entities.ErrConflict, "exists"), http.StatusConflict},
+		{entities.Errorf(entities.ErrBackendUnavailable, "down"), http.StatusServiceUnavailable},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, statusFor(tt.err), tt.err.Error())
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	srv := NewServer(&Options{World: "middle-earth", RateLimit: 2})
+
+	assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/api/status", nil).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/api/status", nil).Code)
+
+	var resp errorResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/status", &resp)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+	assert.Contains(t, resp.Error, "rate limit")
+}
+
+func TestServer_MaxQueryLimit(t *testing.T) {
+	facts := make([]entities.Fact, 5)
+	for i := range facts {
+		facts[i] = entities.Fact{ID: string(rune('a' + i)), Subject: "Frodo", Predicate: "has_trait", Object: "brave"}
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	srv := NewServer(&Options{
+		World:         "middle-earth",
+		Query:         handlers.NewQueryHandler(services.NewQueryService(emb, &mocks.VectorDB{Facts: facts}, &mocks.RelationalDB{})),
+		MaxQueryLimit: 2,
+	})
+
+	var resp queryResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/query?q=brave&limit=5", &resp)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, resp.Facts, 2)
+
+	rec = doRequest(t, srv, http.MethodGet, "/api/snapshots", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServer_Readiness(t *testing.T) {
+	var qdrantErr error
+	monitor := readiness.NewMonitor([]readiness.Check{
+		{Name: "qdrant", Check: func(context.Context) error { return qdrantErr }},
+	}, time.Second)
+
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	srv.opts.Readiness = monitor
+
+	var report readiness.Report
+	rec := doRequest(t, srv, http.MethodGet, "/readyz", &report)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "not ready before the first check")
+	assert.Equal(t, readiness.StatusStarting, report.Status)
+
+	monitor.Check(context.Background())
+	rec = doRequest(t, srv, http.MethodGet, "/readyz", &report)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, report.Checks, 1)
+	assert.True(t, report.Checks[0].OK)
+	assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/api/query?q=brave", nil).Code)
+
+	qdrantErr = errors.New("connection refused")
+	monitor.Check(context.Background())
+	rec = doRequest(t, srv, http.MethodGet, "/readyz", &report)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, readiness.StatusDegraded, report.Status)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+
+	rec = doRequest(t, srv, http.MethodGet, "/healthz", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, "the server is alive while degraded")
+}
+
+func TestServer_ProbesAreNotRateLimited(t *testing.T) {
+	srv := NewServer(&Options{World: "middle-earth", RateLimit: 1})
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/healthz", nil).Code)
+		assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/readyz", nil).Code)
+	}
+	assert.Equal(t, http.StatusOK, doRequest(t, srv, http.MethodGet, "/api/status", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(t, srv, http.MethodGet, "/api/status", nil).Code)
+}
+
+func TestServer_FailsFastWhileDegraded(t *testing.T) {
+	monitor := readiness.NewMonitor([]readiness.Check{
+		{Name: "qdrant", Check: func(context.Context) error { return errors.New("connection refused") }},
+	}, time.Second)
+	monitor.Check(context.Background())
+
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	srv.opts.Readiness = monitor
+
+	var resp errorResponse
+	rec := doRequest(t, srv, http.MethodGet, "/api/query?q=brave", &resp)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, resp.Error, "service degraded (qdrant: connection refused")
+
+	var status statusResponse
+	rec = doRequest(t, srv, http.MethodGet, "/api/status", &status)
+	assert.Equal(t, http.StatusOK, rec.Code, "status explains why")
+	assert.Equal(t, readiness.StatusDegraded, status.Readiness.Status)
+}
+
+// fakeGrant is what a token may do in fakeAuth.
+type fakeGrant struct {
+	name     string
+	world    string
+	canWrite bool
+}
+
+// fakeAuth authorizes the tokens it maps to grants.
+type fakeAuth map[string]fakeGrant
+
+func (a fakeAuth) Enabled() bool {
+	return len(a) > 0
+}
+
+func (a fakeAuth) Authorize(token, world string) (string, bool, bool) {
+	grant, ok := a[token]
+	if !ok {
+		return "", false, false
+	}
+	return grant.name, grant.canWrite, grant.world == world
+}
+
+func TestServer_Auth(t *testing.T) {
+	reader, writer, elsewhere := "lore_reader", "lore_writer", "lore_elsewhere"
+	auth := fakeAuth{
+		reader:    {name: "co-writer", world: "middle-earth"},
+		writer:    {name: "editor", world: "middle-earth", canWrite: true},
+		elsewhere: {name: "stranger", world: "narnia", canWrite: true},
+	}
+
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	srv.opts.Auth = auth
+
+	request := func(method, target, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		srv.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		token  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "/api/query?q=brave", "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "/api/status", "lore_guess", http.StatusUnauthorized},
+		{"other world", http.MethodGet, "/api/status", elsewhere, http.StatusForbidden},
+		{"reader queries", http.MethodGet, "/api/query?q=brave", reader, http.StatusOK},
+		{"reader lists snapshots", http.MethodGet, "/api/snapshots", reader, http.StatusOK},
+		{"reader cannot write", http.MethodPost, "/api/snapshots", reader, http.StatusForbidden},
+		{"writer creates snapshot", http.MethodPost, "/api/snapshots", writer, http.StatusCreated},
+		{"probes need no token", http.MethodGet, "/healthz", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := request(tt.method, tt.target, tt.token)
+			assert.Equal(t, tt.want, rec.Code, rec.Body.String())
+			if tt.want == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="lore"`, rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestServer_UI(t *testing.T) {
+	auth := fakeAuth{"lore_reader": {name: "co-writer", world: "middle-earth"}}
+
+	srv := newTestServer(&mocks.SnapshotStorage{})
+	srv.opts.Auth = auth
+	assert.Equal(t, http.StatusNotFound, doRequest(t, srv, http.MethodGet, "/ui/", nil).Code, "off by default")
+
+	srv = NewServer(&Options{World: "middle-earth", UI: true, Auth: auth})
+
+	rec := doRequest(t, srv, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/ui/", rec.Header().Get("Location"))
+
+	rec = doRequest(t, srv, http.MethodGet, "/ui/", nil)
+	assert.Equal(t, http.StatusOK, rec.Code, "the UI needs no token")
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, rec.Body.String(), `<script src="app.js">`)
+
+	rec = doRequest(t, srv, http.MethodGet, "/ui/app.js", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/api/ingest/stream")
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, srv, http.MethodGet, "/api/status", nil).Code, "the API still does")
+}
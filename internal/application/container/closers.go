@@ -0,0 +1,60 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Closers closes a group of connections together, in the reverse of the
+// order they were opened. It is safe for concurrent use.
+type Closers struct {
+	mu      sync.Mutex
+	names   []string
+	closers []io.Closer
+	closed  bool
+}
+
+// Add registers c, named for error messages, to be closed by Close. If the
+// group is already closed, c is closed immediately.
+func (s *Closers) Add(name string, c io.Closer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("closing %s: %w", name, err)
+		}
+		return ErrClosed
+	}
+	s.names = append(s.names, name)
+	s.closers = append(s.closers, c)
+	return nil
+}
+
+// Open returns the names of the connections not yet closed, oldest first.
+// Tests use it to check nothing leaks.
+func (s *Closers) Open() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+// Close closes every connection, newest first, and reports all failures.
+// Closing again does nothing.
+func (s *Closers) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if err := s.closers[i].Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", s.names[i], err))
+		}
+	}
+	s.names = nil
+	s.closers = nil
+	return errors.Join(errs...)
+}
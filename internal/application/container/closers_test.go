@@ -0,0 +1,43 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestClosers(t *testing.T) {
+	var order []string
+	track := func(name string, err error) closerFunc {
+		return func() error {
+			order = append(order, name)
+			return err
+		}
+	}
+
+	var s Closers
+	require.NoError(t, s.Add("first", track("first", nil)))
+	require.NoError(t, s.Add("second", track("second", errors.New("broken pipe"))))
+	require.NoError(t, s.Add("third", track("third", nil)))
+	assert.Equal(t, []string{"first", "second", "third"}, s.Open())
+
+	err := s.Close()
+	require.Error(t, err)
+	assert.Equal(t, "closing second: broken pipe", err.Error())
+	assert.Equal(t, []string{"third", "second", "first"}, order, "closed newest first, past failures")
+	assert.Empty(t, s.Open())
+
+	assert.NoError(t, s.Close(), "closing again does nothing")
+	assert.Len(t, order, 3)
+
+	err = s.Add("late", track("late", nil))
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.Equal(t, "late", order[3], "added after Close is closed at once")
+	assert.Empty(t, s.Open())
+}
@@ -0,0 +1,504 @@
+// Package container builds the repositories and services of each world
+// once and shares them, so CLI commands and long-running modes such as
+// serve and watch wire dependencies the same way. A Container is safe for
+// concurrent use.
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/ersonp/lore-core/internal/application/readiness"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/embedder/hashing"
+	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
+	"github.com/ersonp/lore-core/internal/infrastructure/graphdb/neo4j"
+	fakellm "github.com/ersonp/lore-core/internal/infrastructure/llm/fake"
+	llm "github.com/ersonp/lore-core/internal/infrastructure/llm/openai"
+	"github.com/ersonp/lore-core/internal/infrastructure/recording"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/cache"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
+	"github.com/ersonp/lore-core/internal/infrastructure/tracing"
+	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/qdrant"
+)
+
+// ErrClosed is returned for connections asked for after Close.
+var ErrClosed = errors.New("container is closed")
+
+// World holds the repositories and services of one world.
+type World struct {
+	Name string
+
+	Repo         *qdrant.Repository
+	RelationalDB *cache.EntityCache
+	SQLite       *sqlite.Repository // Unwrapped, for backups
+
+	// Backends bounded by their configured timeouts, logging slow calls
+	// to the world's audit log.
+	Embedder ports.Embedder
+	LLM      ports.LLMClient
+	VectorDB ports.VectorDB
+
+	EntityTypes    *services.EntityTypeService
+	Extraction     *services.ExtractionService
+	Query          *services.QueryService
+	Conflicts      *services.ConflictService
+	Disambiguation *services.DisambiguationService
+	Style          *services.StyleService
+	Sources        *services.SourceService
+
+	// Quota records the cost of the world's API calls and warns about, or
+	// refuses, going beyond its limits.
+	Quota *services.QuotaService
+}
+
+// Container builds each world on first use and caches it until Close,
+// which closes every connection it opened.
+type Container struct {
+	cfg       *config.Config
+	configDir string
+	worlds    *config.WorldsConfig
+	closers   Closers
+	tracer    *tracing.Tracer // Nil unless tracing
+
+	mu       sync.Mutex
+	embedder ports.Embedder // Shared by all worlds; built on first use
+	llm      ports.LLMClient
+	built    map[string]*World
+	admin    *qdrant.CollectionAdmin
+	graph    *neo4j.Client
+	closed   bool
+}
+
+// New creates a Container for the worlds of the project in configDir.
+func New(cfg *config.Config, configDir string, worlds *config.WorldsConfig) *Container {
+	return &Container{
+		cfg:       cfg,
+		configDir: configDir,
+		worlds:    worlds,
+		built:     make(map[string]*World),
+	}
+}
+
+// SetTracer traces the calls of the backends the container opens from now
+// on to tracer. Call it before asking for a world.
+func (c *Container) SetTracer(tracer *tracing.Tracer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tracer = tracer
+}
+
+// Config returns the configuration the container was created with.
+func (c *Container) Config() *config.Config {
+	return c.cfg
+}
+
+// ConfigDir returns the project's config directory.
+func (c *Container) ConfigDir() string {
+	return c.configDir
+}
+
+// Worlds returns the project's worlds.
+func (c *Container) Worlds() *config.WorldsConfig {
+	return c.worlds
+}
+
+// World returns the named world, opening its databases the first time it
+// is asked for. Concurrent callers share one World.
+func (c *Container) World(ctx context.Context, name string) (*World, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if w, ok := c.built[name]; ok {
+		return w, nil
+	}
+
+	if err := c.buildBackends(); err != nil {
+		return nil, err
+	}
+	w, err := c.buildWorld(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	c.built[name] = w
+	return w, nil
+}
+
+// CollectionAdmin returns a client for managing Qdrant collections
+// directly, connecting the first time it is asked for.
+func (c *Container) CollectionAdmin() (*qdrant.CollectionAdmin, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.admin != nil {
+		return c.admin, nil
+	}
+
+	admin, err := qdrant.NewCollectionAdmin(&c.cfg.Qdrant, c.tracer.DialOption())
+	if err != nil {
+		return nil, fmt.Errorf("creating qdrant collection admin: %w", err)
+	}
+	if err := c.closers.Add("qdrant collection admin", admin); err != nil {
+		return nil, err
+	}
+	c.admin = admin
+	return admin, nil
+}
+
+// GraphClient returns a client for the configured graph database,
+// connecting the first time it is asked for. It returns nil if no graph
+// database is configured.
+func (c *Container) GraphClient() (*neo4j.Client, error) {
+	if !c.cfg.Graph.Enabled() {
+		return nil, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.graph != nil {
+		return c.graph, nil
+	}
+
+	client, err := neo4j.NewClient(c.cfg.Graph)
+	if err != nil {
+		return nil, fmt.Errorf("creating graph database client: %w", err)
+	}
+	if err := c.closers.Add("graph database client", client); err != nil {
+		return nil, err
+	}
+	c.graph = client
+	return client, nil
+}
+
+// ReadinessChecks returns checks that the world's databases are reachable
+// and that the LLM and embedder accept their credentials. Providers that
+// need no network, such as fake ones, are not checked.
+func (c *Container) ReadinessChecks(w *World) []readiness.Check {
+	checks := []readiness.Check{
+		readiness.PingCheck("qdrant", w.Repo),
+		readiness.PingCheck("sqlite", w.SQLite),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p, ok := c.llm.(readiness.Pinger); ok {
+		checks = append(checks, readiness.PingCheck("llm", p))
+	}
+	if p, ok := c.embedder.(readiness.Pinger); ok {
+		checks = append(checks, readiness.PingCheck("embedder", p))
+	}
+	return checks
+}
+
+// Open returns the names of the connections not yet closed, so tests can
+// check none outlive Close.
+func (c *Container) Open() []string {
+	return c.closers.Open()
+}
+
+// Close closes every connection the container opened, newest first, and
+// reports all failures. Worlds and clients must not be used afterwards.
+func (c *Container) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.built = nil
+	return c.closers.Close()
+}
+
+// buildBackends creates the embedder and LLM client shared by all worlds.
+func (c *Container) buildBackends() error {
+	if c.embedder != nil {
+		return nil
+	}
+
+	httpClient := c.httpClient()
+
+	emb, err := newEmbedder(c.cfg.Embedder, httpClient)
+	if err != nil {
+		return fmt.Errorf("creating embedder: %w", err)
+	}
+
+	llmClient, err := newLLMClient(c.cfg.LLM, httpClient)
+	if err != nil {
+		return fmt.Errorf("creating llm client: %w", err)
+	}
+
+	c.embedder = emb
+	c.llm = llmClient
+	return nil
+}
+
+func (c *Container) buildWorld(ctx context.Context, name string) (_ *World, err error) {
+	repo, err := c.openQdrant(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			repo.Close()
+		}
+	}()
+
+	sqliteRepo, err := c.openSQLite(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			sqliteRepo.Close()
+		}
+	}()
+
+	w := &World{
+		Name: name,
+		Repo: repo,
+		// Cache entity lookups, which relationship listings repeat per row
+		RelationalDB: cache.NewEntityCache(sqliteRepo, cache.DefaultEntityCapacity),
+		SQLite:       sqliteRepo,
+	}
+	if err := c.wrapBackends(w); err != nil {
+		return nil, err
+	}
+	w.buildServices()
+
+	if err := c.closers.Add("qdrant connection for world "+name, repo); err != nil {
+		return nil, err
+	}
+	if err := c.closers.Add("sqlite database for world "+name, sqliteRepo); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// openQdrant connects to the collection of world name.
+func (c *Container) openQdrant(name string) (*qdrant.Repository, error) {
+	collection, err := c.worlds.GetCollection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	qdrantCfg := c.cfg.Qdrant
+	qdrantCfg.Collection = collection
+
+	repo, err := qdrant.NewRepository(&qdrantCfg, c.tracer.DialOption())
+	if err != nil {
+		return nil, fmt.Errorf("creating qdrant repository: %w", err)
+	}
+	return repo, nil
+}
+
+// openSQLite opens the database of world name, bringing its schema and
+// entity types up to date.
+func (c *Container) openSQLite(ctx context.Context, name string) (_ *sqlite.Repository, err error) {
+	sqlitePath := config.SQLitePathForWorld(c.configDir, name)
+	sqliteRepo, err := sqlite.NewRepository(config.SQLiteConfig{Path: sqlitePath, BusyTimeout: c.cfg.SQLite.BusyTimeout}, c.tracer.WrapDriver)
+	if err != nil {
+		return nil, fmt.Errorf("creating sqlite repository: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			sqliteRepo.Close()
+		}
+	}()
+
+	if err := sqliteRepo.EnsureSchema(ctx); err != nil {
+		return nil, fmt.Errorf("ensuring sqlite schema: %w", err)
+	}
+
+	// Auto-migrate: seed default types if table is empty
+	if err := migrateDefaultEntityTypes(ctx, sqliteRepo); err != nil {
+		return nil, fmt.Errorf("migrating entity types: %w", err)
+	}
+	return sqliteRepo, nil
+}
+
+// wrapBackends sets the quota of w and its embedder, LLM client and
+// vector database: the shared ones and w's repository, bounded by their
+// timeouts and metered against the quota.
+func (c *Container) wrapBackends(w *World) error {
+	w.Quota = services.NewQuotaService(w.RelationalDB, newQuota(c.cfg.Quota), func(message string) {
+		fmt.Fprintf(os.Stderr, "Warning: world %s: %s\n", w.Name, message)
+	})
+	embBudget := budget(c.cfg.Embedder.TimeoutConfig)
+	embBudget.Meter = w.Quota
+
+	w.Embedder = services.NewBudgetedEmbedder(c.embedder, embBudget, w.RelationalDB)
+	if c.cfg.Embedder.Template != "" {
+		tmpl, err := services.ParseFactTemplate(c.cfg.Embedder.Template)
+		if err != nil {
+			return fmt.Errorf("embedder.template: %w", err)
+		}
+		w.Embedder = services.NewTemplatedEmbedder(w.Embedder, tmpl)
+	}
+	if entry, err := c.worlds.Get(w.Name); err == nil && entry.EmbeddingTemplate != c.cfg.Embedder.Template {
+		fmt.Fprintf(os.Stderr, "Warning: world %s: embedder.template has changed since its facts were embedded; run 'lore migrate reindex --re-embed'\n", w.Name)
+	}
+
+	w.LLM = worldLLM(c.llm, c.cfg.LLM, w.RelationalDB, w.Quota)
+	if ttl := c.cfg.LLM.ConsistencyCacheTTL; ttl > 0 {
+		w.LLM = services.NewCachedConsistencyLLM(w.LLM, w.RelationalDB, ttl)
+	}
+
+	// Record fact writes in the change feed before anything reads them
+	var vectorDB ports.VectorDB = services.NewChangeFeedVectorDB(w.Repo, w.RelationalDB)
+	vectorDB = services.NewBudgetedVectorDB(vectorDB, budget(c.cfg.Qdrant.TimeoutConfig), w.RelationalDB)
+	vectorDB = services.NewQuotaVectorDB(vectorDB, w.Quota)
+	w.VectorDB = services.NewFactSubjectsVectorDB(vectorDB, w.RelationalDB, w.Name)
+	return nil
+}
+
+// buildServices creates the services of w over its backends.
+func (w *World) buildServices() {
+	w.EntityTypes = services.NewEntityTypeService(w.RelationalDB)
+	w.Extraction = services.NewExtractionService(w.LLM, w.Embedder, w.VectorDB, w.EntityTypes)
+	w.Query = services.NewQueryService(w.Embedder, w.VectorDB, w.RelationalDB)
+	w.Conflicts = services.NewConflictService(w.LLM, w.VectorDB, w.RelationalDB)
+	w.Disambiguation = services.NewDisambiguationService(w.RelationalDB)
+	w.Style = services.NewStyleService(w.RelationalDB)
+	w.Sources = services.NewSourceService(w.RelationalDB, w.VectorDB)
+}
+
+// replayAPIKey stands in for the API key when calls are replayed, since
+// the OpenAI clients require one but nothing is sent. Credentials are
+// validated first, so a recording client without a key is replaying.
+const replayAPIKey = "replay"
+
+// ExtractionWithModel returns an extraction service for w whose LLM calls go
+// to model instead of the configured one, with the configured provider,
+// timeouts, and the world's quota. Its calls are recorded in the world's
+// extraction statistics under model.
+func (c *Container) ExtractionWithModel(w *World, model string) (*services.ExtractionService, error) {
+	cfg := c.cfg.LLM
+	cfg.Model = model
+	client, err := newLLMClient(cfg, c.httpClient())
+	if err != nil {
+		return nil, fmt.Errorf("creating llm client for %s: %w", model, err)
+	}
+	return services.NewExtractionService(worldLLM(client, cfg, w.RelationalDB, w.Quota), w.Embedder, w.VectorDB, w.EntityTypes), nil
+}
+
+// worldLLM bounds client's calls by the timeouts in cfg, charges them to
+// quota, and records its extraction statistics and slow calls in
+// relationalDB.
+func worldLLM(client ports.LLMClient, cfg config.LLMConfig, relationalDB ports.RelationalDB, quota *services.QuotaService) ports.LLMClient {
+	llmBudget := budget(cfg.TimeoutConfig)
+	llmBudget.Meter = quota
+	return services.NewExtractionStatsLLM(services.NewBudgetedLLM(client, llmBudget, relationalDB), relationalDB, llmModel(cfg))
+}
+
+// httpClient returns an HTTP client that records or replays OpenAI calls
+// as configured, and traces them when tracing, or nil to use the default.
+func (c *Container) httpClient() *http.Client {
+	client := recordingClient(c.cfg.Recording, c.configDir)
+	if c.tracer == nil {
+		return client
+	}
+	if client == nil {
+		client = &http.Client{}
+	}
+	client.Transport = c.tracer.Transport(client.Transport)
+	return client
+}
+
+// recordingClient returns an HTTP client that records or replays OpenAI
+// calls as configured, or nil to use the default.
+func recordingClient(cfg config.RecordingConfig, configDir string) *http.Client {
+	if !cfg.Enabled() {
+		return nil
+	}
+	dir := config.RecordingDir(configDir, cfg)
+	return recording.NewTransport(recording.Mode(cfg.Mode), dir, nil).Client()
+}
+
+// newEmbedder creates the embedder for the configured provider, sending
+// OpenAI requests with httpClient if it is not nil.
+func newEmbedder(cfg config.EmbedderConfig, httpClient *http.Client) (ports.Embedder, error) {
+	if cfg.Provider == config.ProviderFake {
+		return hashing.NewEmbedder(config.EmbeddingVectorSize), nil
+	}
+	if cfg.APIKey == "" && httpClient != nil {
+		cfg.APIKey = replayAPIKey
+	}
+	return embedder.NewEmbedderWithHTTPClient(cfg, httpClient)
+}
+
+// newLLMClient creates the LLM client for the configured provider, sending
+// OpenAI requests with httpClient if it is not nil.
+func newLLMClient(cfg config.LLMConfig, httpClient *http.Client) (ports.LLMClient, error) {
+	if cfg.Provider == config.ProviderFake {
+		return fakellm.NewClient(), nil
+	}
+	if cfg.APIKey == "" && httpClient != nil {
+		cfg.APIKey = replayAPIKey
+	}
+	return llm.NewClientWithHTTPClient(cfg, httpClient)
+}
+
+// llmModel names the model the configured LLM provider calls, as its
+// extraction statistics are recorded under.
+func llmModel(cfg config.LLMConfig) string {
+	if cfg.Provider == config.ProviderFake || cfg.Model == "" {
+		return cfg.Provider
+	}
+	return cfg.Model
+}
+
+// budget converts a backend's timeout settings into a services.Budget.
+func budget(c config.TimeoutConfig) services.Budget {
+	return services.Budget{Timeout: c.Timeout, SlowThreshold: c.SlowThreshold}
+}
+
+// newQuota converts the quota settings into a services.Quota.
+func newQuota(c config.QuotaConfig) services.Quota {
+	return services.Quota{
+		MonthlyBudget: c.MonthlyBudget,
+		MaxFacts:      c.MaxFacts,
+		Strict:        c.Strict,
+		Prices: services.Prices{
+			LLMInput:  c.Prices.LLMInput,
+			LLMOutput: c.Prices.LLMOutput,
+			Embedding: c.Prices.Embedding,
+		},
+	}
+}
+
+// migrateDefaultEntityTypes seeds default entity types if the table is empty.
+// This provides transparent migration for worlds created before dynamic entity types.
+func migrateDefaultEntityTypes(ctx context.Context, db ports.RelationalDB) error {
+	existingTypes, err := db.ListEntityTypes(ctx)
+	if err != nil {
+		return fmt.Errorf("listing entity types: %w", err)
+	}
+	if len(existingTypes) > 0 {
+		return nil
+	}
+	for _, et := range entities.DefaultEntityTypes {
+		etCopy := et
+		if err := db.SaveEntityType(ctx, &etCopy); err != nil {
+			return fmt.Errorf("seeding entity type %s: %w", et.Name, err)
+		}
+	}
+	return nil
+}
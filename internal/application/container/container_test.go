@@ -0,0 +1,153 @@
+package container
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// newTestContainer creates a container for two worlds with offline
+// providers. Qdrant is connected to lazily, so none needs to be running.
+func newTestContainer(t *testing.T) *Container {
+	t.Helper()
+	cfg := config.Default()
+	cfg.LLM.Provider = config.ProviderFake
+	cfg.Embedder.Provider = config.ProviderFake
+
+	configDir := t.TempDir()
+	worlds := &config.WorldsConfig{}
+	for _, name := range []string{"shire", "gondor"} {
+		worlds.Add(name, config.WorldEntry{Collection: config.GenerateCollectionName(name)})
+		require.NoError(t, os.MkdirAll(config.WorldDir(configDir, name), 0755))
+	}
+
+	c := New(cfg, configDir, worlds)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestContainer_World(t *testing.T) {
+	ctx := context.Background()
+	c := newTestContainer(t)
+
+	shire, err := c.World(ctx, "shire")
+	require.NoError(t, err)
+	assert.Equal(t, "shire", shire.Name)
+	assert.NotNil(t, shire.Extraction)
+	assert.NotNil(t, shire.Query)
+
+	types, err := shire.RelationalDB.ListEntityTypes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, types, len(entities.DefaultEntityTypes), "default entity types are seeded")
+
+	again, err := c.World(ctx, "shire")
+	require.NoError(t, err)
+	assert.Same(t, shire, again, "worlds are built once")
+
+	gondor, err := c.World(ctx, "gondor")
+	require.NoError(t, err)
+	assert.NotSame(t, shire.SQLite, gondor.SQLite, "each world has its own database")
+
+	_, err = c.World(ctx, "mordor")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestContainer_ConcurrentWorld(t *testing.T) {
+	ctx := context.Background()
+	c := newTestContainer(t)
+
+	const callers = 16
+	got := make([]*World, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := "shire"
+			if i%2 == 1 {
+				name = "gondor"
+			}
+			w, err := c.World(ctx, name)
+			assert.NoError(t, err)
+			got[i] = w
+		}()
+	}
+	wg.Wait()
+
+	for i := 2; i < callers; i++ {
+		assert.Same(t, got[i%2], got[i])
+	}
+}
+
+func TestContainer_Close(t *testing.T) {
+	ctx := context.Background()
+	c := newTestContainer(t)
+
+	w, err := c.World(ctx, "shire")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Close())
+	assert.NoError(t, c.Close(), "closing twice is harmless")
+
+	_, err = w.RelationalDB.ListEntityTypes(ctx)
+	assert.Error(t, err, "the world's database is closed")
+
+	_, err = c.World(ctx, "shire")
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestContainer_ClosesEveryConnection(t *testing.T) {
+	ctx := context.Background()
+	c := newTestContainer(t)
+
+	_, err := c.World(ctx, "shire")
+	require.NoError(t, err)
+	_, err = c.World(ctx, "gondor")
+	require.NoError(t, err)
+	admin, err := c.CollectionAdmin()
+	require.NoError(t, err)
+	again, err := c.CollectionAdmin()
+	require.NoError(t, err)
+	assert.Same(t, admin, again)
+
+	graph, err := c.GraphClient()
+	require.NoError(t, err)
+	assert.Nil(t, graph, "no graph database is configured")
+
+	assert.Equal(t, []string{
+		"qdrant connection for world shire",
+		"sqlite database for world shire",
+		"qdrant connection for world gondor",
+		"sqlite database for world gondor",
+		"qdrant collection admin",
+	}, c.Open())
+
+	require.NoError(t, c.Close())
+	assert.Empty(t, c.Open(), "no connection outlives Close")
+
+	_, err = c.CollectionAdmin()
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestContainer_ReadinessChecks(t *testing.T) {
+	ctx := context.Background()
+	c := newTestContainer(t)
+
+	w, err := c.World(ctx, "shire")
+	require.NoError(t, err)
+
+	checks := c.ReadinessChecks(w)
+	names := make([]string, len(checks))
+	for i, check := range checks {
+		names[i] = check.Name
+	}
+	assert.Equal(t, []string{"qdrant", "sqlite"}, names, "fake providers need no credentials check")
+	assert.NoError(t, checks[1].Check(ctx))
+}
@@ -0,0 +1,129 @@
+// Package demo builds the self-contained sample world served by
+// 'lore serve --demo'. Facts live in memory and are embedded locally, so
+// the demo needs no configuration, API keys, or Qdrant.
+package demo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ersonp/lore-core/internal/application/api"
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/embedder/hashing"
+	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
+	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/memory"
+)
+
+// World is the name of the sample world.
+const World = "middle-earth"
+
+// Limits applied to the public demo server.
+const (
+	RateLimit     = 30 // Requests per minute per client IP
+	MaxQueryLimit = 20 // Largest number of facts a query may return
+)
+
+//go:embed sample.yaml
+var sample []byte
+
+// Demo is the sample world loaded into memory.
+type Demo struct {
+	Query         *handlers.QueryHandler
+	Facts         int
+	Entities      int
+	Relationships int
+
+	entityHandler       *handlers.EntityHandler
+	relationshipHandler *handlers.RelationshipHandler
+
+	db  *sqlite.Repository
+	dir string
+}
+
+// New loads the sample world. Close releases it.
+func New(ctx context.Context) (*Demo, error) {
+	// Entities and relationships need SQLite. An in-memory database would
+	// be private to each pooled connection, so use a throwaway file.
+	dir, err := os.MkdirTemp("", "lore-demo-")
+	if err != nil {
+		return nil, fmt.Errorf("creating demo directory: %w", err)
+	}
+	db, err := sqlite.NewRepository(config.SQLiteConfig{Path: filepath.Join(dir, World+".db")})
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("opening demo database: %w", err)
+	}
+	d := &Demo{db: db, dir: dir}
+
+	if err := d.load(ctx); err != nil {
+		d.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Demo) load(ctx context.Context) error {
+	if err := d.db.EnsureSchema(ctx); err != nil {
+		return fmt.Errorf("creating demo schema: %w", err)
+	}
+
+	entityTypes := services.NewEntityTypeService(d.db)
+	if err := entityTypes.LoadDefaults(ctx); err != nil {
+		return fmt.Errorf("loading entity types: %w", err)
+	}
+
+	doc, err := (&parsers.YAMLParser{}).ParseDocument(bytes.NewReader(sample))
+	if err != nil {
+		return fmt.Errorf("parsing sample world: %w", err)
+	}
+
+	embedder := hashing.NewEmbedder(hashing.DefaultDimensions)
+	vectorDB := services.NewFactSubjectsVectorDB(memory.NewRepository(), d.db, World)
+
+	importer := services.NewImportService(embedder, vectorDB, d.db, entityTypes)
+	result, err := importer.ImportDocument(ctx, World, doc, services.ImportOptions{})
+	if err != nil {
+		return fmt.Errorf("importing sample world: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("sample world has %d invalid entries, first: %w", len(result.Errors), result.Errors[0])
+	}
+
+	d.Query = handlers.NewQueryHandler(services.NewQueryService(embedder, vectorDB, d.db))
+	d.entityHandler = handlers.NewEntityHandler(services.NewEntityService(d.db, vectorDB))
+	d.relationshipHandler = handlers.NewRelationshipHandler(services.NewRelationshipService(vectorDB, d.db, embedder), d.db)
+	d.Facts = result.Imported
+	d.Entities = result.Entities
+	d.Relationships = result.Relationships
+	return nil
+}
+
+// Options returns server options for the sample world: queries and entity
+// pages only, with the demo's rate and result limits.
+func (d *Demo) Options() api.Options {
+	return api.Options{
+		World:         World,
+		Query:         d.Query,
+		Entities:      d.entityHandler,
+		Relationships: d.relationshipHandler,
+		Demo:          true,
+		RateLimit:     RateLimit,
+		MaxQueryLimit: MaxQueryLimit,
+	}
+}
+
+// Close releases the sample world.
+func (d *Demo) Close() error {
+	err := d.db.Close()
+	if rerr := os.RemoveAll(d.dir); rerr != nil && err == nil {
+		err = fmt.Errorf("removing demo directory: %w", rerr)
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package demo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/api"
+)
+
+func TestNew(t *testing.T) {
+	d, err := New(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, d.Close()) })
+
+	assert.Positive(t, d.Facts)
+	assert.Positive(t, d.Entities)
+	assert.Positive(t, d.Relationships)
+}
+
+func TestDemo_Serve(t *testing.T) {
+	d, err := New(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, d.Close()) })
+
+	opts := d.Options()
+	srv := api.NewServer(&opts)
+	get := func(target string, out any) int {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if out != nil {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+		}
+		return rec.Code
+	}
+
+	var status struct {
+		World string `json:"world"`
+		Demo  bool   `json:"demo"`
+	}
+	require.Equal(t, http.StatusOK, get("/api/status", &status))
+	assert.Equal(t, World, status.World)
+	assert.True(t, status.Demo)
+
+	var query struct {
+		Facts []struct {
+			Subject string `json:"subject"`
+			Object  string `json:"object"`
+		} `json:"facts"`
+	}
+	require.Equal(t, http.StatusOK, get("/api/query?q=who+forged+the+ring&limit=100", &query))
+	require.NotEmpty(t, query.Facts)
+	assert.LessOrEqual(t, len(query.Facts), MaxQueryLimit)
+	assert.Equal(t, "Sauron", query.Facts[0].Subject)
+
+	var page struct {
+		Entity struct {
+			Name string `json:"name"`
+		} `json:"entity"`
+		Facts         []json.RawMessage `json:"facts"`
+		Relationships []json.RawMessage `json:"relationships"`
+	}
+	require.Equal(t, http.StatusOK, get("/api/entities/frodo%20baggins", &page))
+	assert.Equal(t, "Frodo Baggins", page.Entity.Name)
+	assert.NotEmpty(t, page.Facts)
+	assert.Len(t, page.Relationships, 5)
+
+	assert.Equal(t, http.StatusNotFound, get("/api/snapshots", nil))
+
+	// Four requests so far; use up the rest of the allowance.
+	for i := 5; i <= RateLimit; i++ {
+		require.Equal(t, http.StatusOK, get("/api/status", nil), "request %d", i)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/status", nil))
+}
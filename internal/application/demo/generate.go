@@ -0,0 +1,519 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// GeneratedSource is the source file recorded on generated facts, so a
+// generated world can be removed with 'lore delete --source'.
+const GeneratedSource = "lore-demo-seed"
+
+// minGeneratedEntities is one each of a location, a faction, and a
+// character.
+const minGeneratedEntities = 3
+
+// seedBatchSize is how many facts Seed embeds and saves at a time.
+const seedBatchSize = 256
+
+// GenerateOptions sizes a generated world.
+type GenerateOptions struct {
+	Facts    int    // Facts to generate
+	Entities int    // Locations, factions, and characters to generate
+	Seed     uint64 // The same options always generate the same world
+}
+
+// Generate builds a synthetic world as an import document. The world is
+// internally consistent: every character lives in one place, belongs to at
+// most one faction, and is born after their parents, and the facts agree
+// with the relationships. A small world may hold fewer distinct facts than
+// asked for; Generate then returns all it has.
+func Generate(opts GenerateOptions) (*entities.ImportDocument, error) {
+	if opts.Entities < minGeneratedEntities {
+		return nil, entities.Errorf(entities.ErrValidation, "entities must be at least %d, got %d", minGeneratedEntities, opts.Entities)
+	}
+	if opts.Facts < 0 {
+		return nil, entities.Errorf(entities.ErrValidation, "facts must not be negative, got %d", opts.Facts)
+	}
+
+	g := &generator{
+		opts:  opts,
+		rng:   rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15)),
+		names: make(map[string]bool),
+		doc:   &entities.ImportDocument{},
+	}
+	g.build()
+	g.generateFacts()
+	return g.doc, nil
+}
+
+// Seed imports a generated document into a world: entities and
+// relationships first, then facts in batches, calling progress with the
+// number of facts saved so far after each batch.
+func Seed(ctx context.Context, importer *services.ImportService, worldID string, doc *entities.ImportDocument, progress func(saved int)) (*services.ImportResult, error) {
+	result, err := importer.ImportDocument(ctx, worldID, &entities.ImportDocument{
+		Entities:      doc.Entities,
+		Relationships: doc.Relationships,
+	}, services.ImportOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("importing entities: %w", err)
+	}
+
+	for start := 0; start < len(doc.Facts); start += seedBatchSize {
+		end := min(start+seedBatchSize, len(doc.Facts))
+		batch, err := importer.Import(ctx, doc.Facts[start:end], services.ImportOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("importing facts: %w", err)
+		}
+		result.Imported += batch.Imported
+		result.Skipped += batch.Skipped
+		result.Errors = append(result.Errors, batch.Errors...)
+		if progress != nil {
+			progress(end)
+		}
+	}
+	return result, nil
+}
+
+// Word lists the generator draws from.
+var (
+	nameStarts    = []string{"Al", "Bel", "Cor", "Dar", "El", "Fen", "Gal", "Hal", "Ith", "Jor", "Kel", "Lor", "Mar", "Nor", "Or", "Per", "Quen", "Ros", "Sel", "Tor", "Ul", "Val", "Wyn", "Yor", "Zar"}
+	nameMiddles   = []string{"", "a", "e", "i", "o", "ae", "ia", "en", "ar", "or"}
+	nameEnds      = []string{"dric", "wen", "mir", "thas", "ric", "lin", "dor", "ven", "ra", "nor", "wyn", "rith", "gar", "mund", "sa"}
+	placeEnds     = []string{"holm", "ford", "mere", "gard", "vale", "wick", "haven", "moor", "crest", "fell", "march", "reach"}
+	factionNouns  = []string{"Order", "Guild", "Circle", "Brotherhood", "League", "Council", "Company", "Covenant", "Wardens", "Legion"}
+	factionAdjs   = []string{"Silver", "Ashen", "Hidden", "Crimson", "Iron", "Silent", "Golden", "Burning", "Pale", "Verdant", "Sunken", "Broken"}
+	factionThings = []string{"Hand", "Lantern", "Crown", "Thorn", "Tide", "Star", "Oath", "Flame", "Key", "Veil", "Spear", "Root"}
+
+	races       = []string{"human", "elf", "dwarf", "halfling", "orc", "giant"}
+	occupations = []string{"smith", "scholar", "merchant", "soldier", "healer", "farmer", "sailor", "scout", "priest", "bard", "mason", "hunter"}
+	eyeColors   = []string{"brown", "blue", "green", "grey", "amber", "black"}
+	traits      = []string{"brave", "cunning", "patient", "reckless", "loyal", "proud", "curious", "stubborn", "generous", "secretive", "cheerful", "ambitious", "pious", "gentle", "suspicious", "honest"}
+	climates    = []string{"temperate", "arid", "frozen", "humid", "windswept", "misty"}
+	goods       = []string{"wool", "iron", "timber", "salt", "wine", "horses", "grain", "silver", "glass", "spices", "furs", "amber"}
+	practices   = []string{"blood magic", "usury", "grave robbing", "oath breaking", "slavery", "dueling", "necromancy", "idol worship", "smuggling", "poisoning"}
+	duties      = []string{"a yearly tithe", "a vow of silence", "service at arms", "a pilgrimage", "guarding the roads", "tending the sick", "keeping the archives", "feeding the poor"}
+)
+
+type location struct {
+	name       string
+	realm      *location // nil for realms
+	climate    string
+	population int
+	founded    int
+	ruler      *character // nil if no one lives there
+	offset     int        // Where the location's picks from shared lists start
+}
+
+type faction struct {
+	name    string
+	seat    *location
+	founder *character
+	founded int
+	offset  int
+}
+
+type character struct {
+	index      int
+	name       string
+	race       string
+	occupation string
+	eyes       string
+	born       int
+	birthplace *location
+	home       *location
+	faction    *faction
+	spouse     *character
+	parents    []*character
+	offset     int
+}
+
+// factionPair is an alliance or feud between two factions.
+type factionPair struct {
+	a, b   *faction
+	allied bool
+}
+
+type generator struct {
+	opts  GenerateOptions
+	rng   *rand.Rand
+	names map[string]bool
+	doc   *entities.ImportDocument
+
+	locations  []*location
+	factions   []*faction
+	characters []*character
+	pairs      []factionPair
+}
+
+func (g *generator) pick(list []string) string {
+	return list[g.rng.IntN(len(list))]
+}
+
+// unique returns a name from next not used before, numbering it if the
+// word lists run out.
+func (g *generator) unique(next func() string) string {
+	name := next()
+	for i := 0; g.names[name] && i < 20; i++ {
+		name = next()
+	}
+	for n := 2; g.names[name]; n++ {
+		if candidate := fmt.Sprintf("%s %d", name, n); !g.names[candidate] {
+			name = candidate
+		}
+	}
+	g.names[name] = true
+	return name
+}
+
+func (g *generator) word() string {
+	return g.pick(nameStarts) + g.pick(nameMiddles) + g.pick(nameEnds)
+}
+
+// build lays out the world: a quarter locations, some factions, and
+// families of characters for the rest.
+func (g *generator) build() {
+	n := g.opts.Entities
+	nLocations := max(1, n/4)
+	nFactions := max(1, n*15/100)
+	nCharacters := n - nLocations - nFactions
+
+	g.buildLocations(nLocations)
+	g.buildCharacters(nCharacters)
+	g.buildFactions(nFactions)
+
+	// Most characters belong to a faction.
+	for _, c := range g.characters {
+		if g.rng.IntN(10) < 7 {
+			c.faction = g.factions[g.rng.IntN(len(g.factions))]
+		}
+	}
+
+	residents := make(map[*location][]*character)
+	for _, c := range g.characters {
+		residents[c.home] = append(residents[c.home], c)
+	}
+	for _, loc := range g.locations {
+		if r := residents[loc]; len(r) > 0 {
+			loc.ruler = r[g.rng.IntN(len(r))]
+		}
+	}
+
+	for _, loc := range g.locations {
+		g.doc.Entities = append(g.doc.Entities, entities.RawEntity{Name: loc.name})
+		if loc.realm != nil {
+			g.relate(loc.name, entities.RelationLocatedIn, loc.realm.name, false)
+		}
+	}
+	for _, f := range g.factions {
+		g.doc.Entities = append(g.doc.Entities, entities.RawEntity{Name: f.name})
+		g.relate(f.name, entities.RelationLocatedIn, f.seat.name, false)
+		g.relate(f.founder.name, entities.RelationCreated, f.name, false)
+	}
+	for _, p := range g.pairs {
+		relType := entities.RelationEnemy
+		if p.allied {
+			relType = entities.RelationAlly
+		}
+		g.relate(p.a.name, relType, p.b.name, true)
+	}
+	for _, c := range g.characters {
+		g.doc.Entities = append(g.doc.Entities, entities.RawEntity{Name: c.name})
+		g.relate(c.name, entities.RelationLocatedIn, c.home.name, false)
+		if c.faction != nil {
+			g.relate(c.name, entities.RelationMemberOf, c.faction.name, false)
+		}
+		if c.spouse != nil && c.index < c.spouse.index {
+			g.relate(c.name, entities.RelationSpouse, c.spouse.name, true)
+		}
+		for _, p := range c.parents {
+			g.relate(p.name, entities.RelationParent, c.name, false)
+		}
+	}
+	g.relateSiblings()
+}
+
+func (g *generator) buildLocations(n int) {
+	realms := max(1, n/5)
+	for i := range n {
+		loc := &location{
+			name:       g.unique(func() string { return g.pick(nameStarts) + g.pick(nameMiddles) + g.pick(placeEnds) }),
+			climate:    g.pick(climates),
+			population: 100 * (1 + g.rng.IntN(500)),
+			founded:    1 + g.rng.IntN(200),
+			offset:     g.rng.IntN(1 << 16),
+		}
+		if i >= realms {
+			loc.realm = g.locations[g.rng.IntN(realms)]
+			loc.climate = loc.realm.climate
+			loc.founded = loc.realm.founded + 1 + g.rng.IntN(300)
+		}
+		g.locations = append(g.locations, loc)
+	}
+}
+
+// buildCharacters creates families of one to four: a couple and their
+// children, born at least eighteen years after both parents.
+func (g *generator) buildCharacters(n int) {
+	for len(g.characters) < n {
+		size := min(1+g.rng.IntN(4), n-len(g.characters))
+		surname := g.word()
+		race := g.pick(races)
+		home := g.locations[g.rng.IntN(len(g.locations))]
+		born := 700 + g.rng.IntN(150)
+
+		var family []*character
+		for i := range size {
+			c := &character{
+				index:      len(g.characters),
+				name:       g.unique(func() string { return g.word() + " " + surname }),
+				race:       race,
+				occupation: g.pick(occupations),
+				eyes:       g.pick(eyeColors),
+				born:       born + g.rng.IntN(6),
+				birthplace: g.locations[g.rng.IntN(len(g.locations))],
+				home:       home,
+				offset:     g.rng.IntN(1 << 16),
+			}
+			switch {
+			case i == 1:
+				c.spouse, family[0].spouse = family[0], c
+			case i >= 2:
+				c.parents = []*character{family[0], family[1]}
+				c.born = max(family[0].born, family[1].born) + 18 + g.rng.IntN(22)
+				c.birthplace = home
+				if g.rng.IntN(2) == 0 {
+					c.home = g.locations[g.rng.IntN(len(g.locations))]
+				}
+			}
+			family = append(family, c)
+			g.characters = append(g.characters, c)
+		}
+	}
+}
+
+// buildFactions seats each faction somewhere, has a grown character found
+// it, and sets it against or beside one earlier faction.
+func (g *generator) buildFactions(n int) {
+	for i := range n {
+		founder := g.characters[g.rng.IntN(len(g.characters))]
+		f := &faction{
+			name: g.unique(func() string {
+				return fmt.Sprintf("The %s of the %s %s", g.pick(factionNouns), g.pick(factionAdjs), g.pick(factionThings))
+			}),
+			seat:    g.locations[g.rng.IntN(len(g.locations))],
+			founder: founder,
+			founded: founder.born + 20 + g.rng.IntN(30),
+			offset:  g.rng.IntN(1 << 16),
+		}
+		if i > 0 {
+			g.pairs = append(g.pairs, factionPair{a: g.factions[g.rng.IntN(i)], b: f, allied: g.rng.IntN(2) == 0})
+		}
+		g.factions = append(g.factions, f)
+	}
+}
+
+func (g *generator) relateSiblings() {
+	children := make(map[*character][]*character)
+	for _, c := range g.characters {
+		if len(c.parents) > 0 {
+			children[c.parents[0]] = append(children[c.parents[0]], c)
+		}
+	}
+	for _, c := range g.characters {
+		kids := children[c]
+		for i := range kids {
+			for j := i + 1; j < len(kids); j++ {
+				g.relate(kids[i].name, entities.RelationSibling, kids[j].name, true)
+			}
+		}
+	}
+}
+
+func (g *generator) relate(source string, relType entities.RelationType, target string, bidirectional bool) {
+	g.doc.Relationships = append(g.doc.Relationships, entities.RawRelationship{
+		Source:        source,
+		Type:          string(relType),
+		Target:        target,
+		Bidirectional: bidirectional,
+	})
+}
+
+// factSource yields an entity's facts: fixed ones first, then as many as
+// each series holds, taking from the series in turn.
+type factSource struct {
+	fixed  []entities.RawFact
+	series []factSeries
+	used   []int
+	next   int
+}
+
+// factSeries is a run of distinct facts of one kind, such as the places a
+// character has visited.
+type factSeries struct {
+	n    int
+	fact func(i int) entities.RawFact
+}
+
+func (s *factSource) take() (entities.RawFact, bool) {
+	if s.used == nil {
+		s.used = make([]int, len(s.series))
+	}
+	if len(s.fixed) > 0 {
+		f := s.fixed[0]
+		s.fixed = s.fixed[1:]
+		return f, true
+	}
+	for range s.series {
+		k := s.next
+		s.next = (s.next + 1) % len(s.series)
+		if s.used[k] < s.series[k].n {
+			s.used[k]++
+			return s.series[k].fact(s.used[k] - 1), true
+		}
+	}
+	return entities.RawFact{}, false
+}
+
+// generateFacts takes facts from every entity in turn, so they are spread
+// evenly, until there are enough or every entity is exhausted.
+func (g *generator) generateFacts() {
+	var sources []*factSource
+	for _, loc := range g.locations {
+		sources = append(sources, g.locationFacts(loc))
+	}
+	for _, f := range g.factions {
+		sources = append(sources, g.factionFacts(f))
+	}
+	for _, c := range g.characters {
+		sources = append(sources, g.characterFacts(c))
+	}
+	for len(g.doc.Facts) < g.opts.Facts {
+		added := false
+		for _, s := range sources {
+			if len(g.doc.Facts) == g.opts.Facts {
+				break
+			}
+			if f, ok := s.take(); ok {
+				g.doc.Facts = append(g.doc.Facts, f)
+				added = true
+			}
+		}
+		if !added {
+			return
+		}
+	}
+}
+
+func (g *generator) fact(factType entities.FactType, subject, predicate, object string) entities.RawFact {
+	key := fmt.Sprintf("%s/%d/%s/%s/%s", GeneratedSource, g.opts.Seed, subject, predicate, object)
+	return entities.RawFact{
+		ID:         uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String(),
+		Type:       string(factType),
+		Subject:    subject,
+		Predicate:  predicate,
+		Object:     object,
+		Context:    fmt.Sprintf("%s %s %s.", subject, strings.ReplaceAll(predicate, "_", " "), object),
+		SourceFile: GeneratedSource,
+	}
+}
+
+func year(y int) string {
+	return fmt.Sprintf("Year %d", y)
+}
+
+// others returns the i-th of the n entities after index, wrapping around
+// and skipping index itself.
+func others(index, i, n int) int {
+	return (index + 1 + i) % n
+}
+
+func (g *generator) characterFacts(c *character) *factSource {
+	s := &factSource{fixed: []entities.RawFact{
+		g.fact(entities.FactTypeCharacter, c.name, "race", c.race),
+		g.fact(entities.FactTypeCharacter, c.name, "lives_in", c.home.name),
+		g.fact(entities.FactTypeTimeline, c.name, "born_in_year", year(c.born)),
+		g.fact(entities.FactTypeCharacter, c.name, "born_in", c.birthplace.name),
+		g.fact(entities.FactTypeCharacter, c.name, "occupation", c.occupation),
+		g.fact(entities.FactTypeCharacter, c.name, "eye_color", c.eyes),
+	}}
+	if c.faction != nil {
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeCharacter, c.name, "member_of", c.faction.name))
+	}
+	if c.spouse != nil {
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeRelationship, c.name, "married_to", c.spouse.name))
+	}
+	for _, p := range c.parents {
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeRelationship, c.name, "child_of", p.name))
+	}
+
+	s.series = []factSeries{
+		{n: len(traits), fact: func(i int) entities.RawFact {
+			return g.fact(entities.FactTypeCharacter, c.name, "has_trait", traits[(c.offset+i)%len(traits)])
+		}},
+		{n: len(g.characters) - 1, fact: func(i int) entities.RawFact {
+			other := g.characters[others(c.index, (c.offset+i)%(len(g.characters)-1), len(g.characters))]
+			return g.fact(entities.FactTypeRelationship, c.name, "knows", other.name)
+		}},
+		{n: len(g.locations), fact: func(i int) entities.RawFact {
+			return g.fact(entities.FactTypeEvent, c.name, "visited", g.locations[(c.offset+i)%len(g.locations)].name)
+		}},
+	}
+	return s
+}
+
+func (g *generator) locationFacts(loc *location) *factSource {
+	s := &factSource{fixed: []entities.RawFact{
+		g.fact(entities.FactTypeLocation, loc.name, "climate", loc.climate),
+		g.fact(entities.FactTypeTimeline, loc.name, "founded_in_year", year(loc.founded)),
+		g.fact(entities.FactTypeLocation, loc.name, "population", fmt.Sprint(loc.population)),
+	}}
+	if loc.realm != nil {
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeLocation, loc.name, "located_in", loc.realm.name))
+	}
+	if loc.ruler != nil {
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeLocation, loc.name, "ruled_by", loc.ruler.name))
+	}
+
+	s.series = []factSeries{
+		{n: len(goods), fact: func(i int) entities.RawFact {
+			return g.fact(entities.FactTypeLocation, loc.name, "exports", goods[(loc.offset+i)%len(goods)])
+		}},
+	}
+	return s
+}
+
+func (g *generator) factionFacts(f *faction) *factSource {
+	s := &factSource{fixed: []entities.RawFact{
+		g.fact(entities.FactTypeEvent, f.name, "founded_by", f.founder.name),
+		g.fact(entities.FactTypeTimeline, f.name, "founded_in_year", year(f.founded)),
+		g.fact(entities.FactTypeLocation, f.name, "headquartered_in", f.seat.name),
+	}}
+	for _, p := range g.pairs {
+		if p.b != f {
+			continue
+		}
+		predicate := "at_war_with"
+		if p.allied {
+			predicate = "allied_with"
+		}
+		s.fixed = append(s.fixed, g.fact(entities.FactTypeRelationship, f.name, predicate, p.a.name))
+	}
+
+	s.series = []factSeries{
+		{n: len(practices), fact: func(i int) entities.RawFact {
+			return g.fact(entities.FactTypeRule, f.name, "forbids", practices[(f.offset+i)%len(practices)])
+		}},
+		{n: len(duties), fact: func(i int) entities.RawFact {
+			return g.fact(entities.FactTypeRule, f.name, "requires", duties[(f.offset+i)%len(duties)])
+		}},
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package demo
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/embedder/hashing"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
+	"github.com/ersonp/lore-core/internal/infrastructure/vectordb/memory"
+)
+
+func TestGenerate(t *testing.T) {
+	doc, err := Generate(GenerateOptions{Facts: 5000, Entities: 300, Seed: 1})
+	require.NoError(t, err)
+
+	assert.Len(t, doc.Facts, 5000)
+	assert.Len(t, doc.Entities, 300)
+	assert.NotEmpty(t, doc.Relationships)
+
+	again, err := Generate(GenerateOptions{Facts: 5000, Entities: 300, Seed: 1})
+	require.NoError(t, err)
+	assert.Equal(t, doc, again, "same seed should generate the same world")
+
+	other, err := Generate(GenerateOptions{Facts: 5000, Entities: 300, Seed: 2})
+	require.NoError(t, err)
+	assert.NotEqual(t, doc.Entities, other.Entities)
+}
+
+func TestGenerate_Consistent(t *testing.T) {
+	doc, err := Generate(GenerateOptions{Facts: 3000, Entities: 200, Seed: 7})
+	require.NoError(t, err)
+
+	declared := make(map[string]bool)
+	for _, e := range doc.Entities {
+		assert.False(t, declared[e.Name], "entity %q declared twice", e.Name)
+		declared[e.Name] = true
+	}
+
+	ids := make(map[string]bool)
+	values := make(map[string]string) // subject/predicate -> object, for single-valued predicates
+	born := make(map[string]int)
+	for _, f := range doc.Facts {
+		assert.False(t, ids[f.ID], "duplicate fact %s %s %s", f.Subject, f.Predicate, f.Object)
+		ids[f.ID] = true
+		assert.True(t, declared[f.Subject], "fact subject %q not declared", f.Subject)
+		assert.True(t, entities.IsDefaultType(f.Type), "fact type %q", f.Type)
+
+		switch f.Predicate {
+		case "has_trait", "knows", "visited", "exports", "forbids", "requires", "child_of", "allied_with", "at_war_with":
+			continue
+		case "born_in_year":
+			n, err := strconv.Atoi(strings.TrimPrefix(f.Object, "Year "))
+			require.NoError(t, err)
+			born[f.Subject] = n
+		}
+		key := f.Subject + "/" + f.Predicate
+		_, seen := values[key]
+		assert.False(t, seen, "%s stated twice", key)
+		values[key] = f.Object
+	}
+
+	for _, r := range doc.Relationships {
+		assert.True(t, declared[r.Source], "relationship source %q not declared", r.Source)
+		assert.True(t, declared[r.Target], "relationship target %q not declared", r.Target)
+		assert.True(t, entities.RelationType(r.Type).IsValid())
+
+		switch entities.RelationType(r.Type) {
+		case entities.RelationLocatedIn:
+			if home, ok := values[r.Source+"/lives_in"]; ok {
+				assert.Equal(t, home, r.Target)
+			}
+		case entities.RelationMemberOf:
+			assert.Equal(t, values[r.Source+"/member_of"], r.Target)
+		case entities.RelationParent:
+			if parentBorn, childBorn := born[r.Source], born[r.Target]; parentBorn > 0 && childBorn > 0 {
+				assert.Greater(t, childBorn, parentBorn+17, "%s born too soon after parent %s", r.Target, r.Source)
+			}
+		}
+	}
+}
+
+func TestGenerate_SmallWorld(t *testing.T) {
+	doc, err := Generate(GenerateOptions{Facts: 100000, Entities: 3})
+	require.NoError(t, err)
+
+	assert.Len(t, doc.Entities, 3)
+	assert.NotEmpty(t, doc.Facts)
+	assert.Less(t, len(doc.Facts), 100000)
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenerateOptions
+	}{
+		{"too few entities", GenerateOptions{Facts: 10, Entities: 2}},
+		{"negative facts", GenerateOptions{Facts: -1, Entities: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(tt.opts)
+			assert.ErrorIs(t, err, entities.ErrValidation)
+		})
+	}
+}
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.NewRepository(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "seed.db")})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, db.EnsureSchema(ctx))
+	entityTypes := services.NewEntityTypeService(db)
+	require.NoError(t, entityTypes.LoadDefaults(ctx))
+
+	doc, err := Generate(GenerateOptions{Facts: 600, Entities: 40, Seed: 3})
+	require.NoError(t, err)
+
+	vectorDB := memory.NewRepository()
+	importer := services.NewImportService(hashing.NewEmbedder(hashing.DefaultDimensions), vectorDB, db, entityTypes)
+	var progress []int
+	result, err := Seed(ctx, importer, "generated", doc, func(saved int) { progress = append(progress, saved) })
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 600, result.Imported)
+	assert.Equal(t, 40, result.Entities)
+	assert.Equal(t, []int{256, 512, 600}, progress)
+
+	// Seeding again overwrites rather than duplicating.
+	count, err := vectorDB.Count(ctx)
+	require.NoError(t, err)
+	_, err = Seed(ctx, importer, "generated", doc, nil)
+	require.NoError(t, err)
+	again, err := vectorDB.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, count, again)
+}
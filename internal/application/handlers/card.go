@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// Defaults for entity cards.
+const (
+	// DefaultCardTTL is how long a card is served from the cache before it
+	// is built again.
+	DefaultCardTTL = time.Minute
+
+	// cardCapacity is the most cards cached; the card expiring soonest is
+	// dropped first.
+	cardCapacity = 1024
+
+	cardFacts         = 5
+	cardRelationships = 5
+
+	// cardCandidates is how many facts are looked at to pick the top
+	// facts and the last appearance.
+	cardCandidates = 50
+)
+
+// EntityCard is a compact summary of an entity for editor tooltips.
+type EntityCard struct {
+	Name string `json:"name"`
+
+	// Type is the most common type of the facts about the entity.
+	Type entities.FactType `json:"type,omitempty"`
+
+	Facts         []CardFact         `json:"facts"`
+	Relationships []CardRelationship `json:"relationships"`
+
+	// LastAppearance is the source of the entity's most recently recorded
+	// fact.
+	LastAppearance string `json:"last_appearance,omitempty"`
+}
+
+// CardFact is a fact on an entity card.
+type CardFact struct {
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+	Source    string `json:"source,omitempty"`
+	ClaimedBy string `json:"claimed_by,omitempty"` // Who claims it, if not canon
+}
+
+// CardRelationship is a relationship on an entity card, from the entity's
+// side.
+type CardRelationship struct {
+	Type   entities.RelationType `json:"type"`
+	Entity string                `json:"entity"`
+
+	// Incoming marks a one-way relationship the other entity has to this
+	// one, as in "Bilbo parent Frodo" on Frodo's card.
+	Incoming bool `json:"incoming,omitempty"`
+}
+
+// cachedCard is a card, or nil if no entity has the name, and when it
+// stops being served.
+type cachedCard struct {
+	card    *EntityCard
+	expires time.Time
+}
+
+type cardKey struct {
+	worldID string
+	name    string
+}
+
+// CardHandler builds entity cards and caches them, since editors ask for
+// one each time the cursor rests on a word. Names that are not entities
+// are cached too.
+type CardHandler struct {
+	entityHandler       *EntityHandler
+	queryHandler        *QueryHandler
+	relationshipHandler *RelationshipHandler // Cards list no relationships if nil
+	ttl                 time.Duration
+
+	mu    sync.Mutex
+	cards map[cardKey]cachedCard
+	now   func() time.Time
+}
+
+// NewCardHandler creates a new CardHandler caching cards for ttl
+// (0 = DefaultCardTTL).
+func NewCardHandler(entityHandler *EntityHandler, queryHandler *QueryHandler, relationshipHandler *RelationshipHandler, ttl time.Duration) *CardHandler {
+	if ttl <= 0 {
+		ttl = DefaultCardTTL
+	}
+	return &CardHandler{
+		entityHandler:       entityHandler,
+		queryHandler:        queryHandler,
+		relationshipHandler: relationshipHandler,
+		ttl:                 ttl,
+		cards:               make(map[cardKey]cachedCard),
+		now:                 time.Now,
+	}
+}
+
+// HandleCard returns the card of the named entity, or an
+// entities.ErrNotFound error if the world has none by that name.
+func (h *CardHandler) HandleCard(ctx context.Context, worldID, name string) (*EntityCard, error) {
+	key := cardKey{worldID: worldID, name: entities.NormalizeName(name)}
+
+	h.mu.Lock()
+	cached, ok := h.cards[key]
+	h.mu.Unlock()
+	if !ok || !h.now().Before(cached.expires) {
+		card, err := h.build(ctx, worldID, name)
+		if err != nil {
+			return nil, err
+		}
+		cached = cachedCard{card: card, expires: h.now().Add(h.ttl)}
+		h.store(key, cached)
+	}
+
+	if cached.card == nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "entity %q not found in world %s", name, worldID)
+	}
+	return cached.card, nil
+}
+
+// TTL returns how long cards are cached.
+func (h *CardHandler) TTL() time.Duration {
+	return h.ttl
+}
+
+// Invalidate drops every cached card, so changes to the facts show at once.
+func (h *CardHandler) Invalidate() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	clear(h.cards)
+}
+
+// store caches a card, first dropping expired cards, and the one expiring
+// soonest, if the cache is full.
+func (h *CardHandler) store(key cardKey, cached cachedCard) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.cards) >= cardCapacity {
+		now := h.now()
+		var soonest cardKey
+		for k, c := range h.cards {
+			if !now.Before(c.expires) {
+				delete(h.cards, k)
+			} else if _, ok := h.cards[soonest]; !ok || c.expires.Before(h.cards[soonest].expires) {
+				soonest = k
+			}
+		}
+		if len(h.cards) >= cardCapacity {
+			delete(h.cards, soonest)
+		}
+	}
+	h.cards[key] = cached
+}
+
+// build builds the card of the named entity, or returns nil if the world
+// has none by that name.
+func (h *CardHandler) build(ctx context.Context, worldID, name string) (*EntityCard, error) {
+	entity, err := h.entityHandler.HandleGet(ctx, worldID, name)
+	if errors.Is(err, entities.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	card := &EntityCard{
+		Name:          entity.Name,
+		Facts:         []CardFact{},
+		Relationships: []CardRelationship{},
+	}
+
+	found, err := h.queryHandler.HandleFindSubject(ctx, worldID, entity.Name, cardCandidates)
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[entities.FactType]int)
+	var last *entities.Fact
+	for i := range found.Matches {
+		match := &found.Matches[i]
+		fact := &match.Fact
+		// Semantic matches may be about anything
+		if match.Tier == services.MatchSemantic || fact.IsPending() {
+			continue
+		}
+
+		types[fact.Type]++
+		if types[fact.Type] > types[card.Type] {
+			card.Type = fact.Type
+		}
+		if fact.SourceFile != "" && (last == nil || fact.CreatedAt.After(last.CreatedAt)) {
+			last = fact
+		}
+		if len(card.Facts) < cardFacts {
+			cardFact := CardFact{Predicate: fact.Predicate, Object: fact.Object, Source: fact.SourceFile}
+			if fact.IsClaim() {
+				cardFact.ClaimedBy = fact.AssertedBy
+			}
+			card.Facts = append(card.Facts, cardFact)
+		}
+	}
+	if last != nil {
+		card.LastAppearance = last.SourceFile
+	}
+
+	if h.relationshipHandler != nil {
+		rels, err := h.relationshipHandler.HandleList(ctx, worldID, entity.Name, ListOptions{Limit: cardRelationships})
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range rels.Relationships {
+			rel := CardRelationship{Type: info.Relationship.Type}
+			other := info.TargetEntity
+			if info.Relationship.TargetEntityID == entity.ID {
+				other = info.SourceEntity
+				rel.Incoming = !info.Relationship.Bidirectional
+			}
+			if other == nil {
+				continue
+			}
+			rel.Entity = other.Name
+			card.Relationships = append(card.Relationships, rel)
+		}
+	}
+
+	return card, nil
+}
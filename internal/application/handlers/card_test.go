@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newTestCardHandler() (*CardHandler, *mocks.VectorDB) {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "has_trait", Object: "brave", SourceFile: "ch1.txt", CreatedAt: day},
+		{ID: "2", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", SourceFile: "ch3.txt", CreatedAt: day.Add(48 * time.Hour)},
+		{ID: "3", Type: entities.FactTypeLocation, Subject: "Frodo", Predicate: "owns", Object: "Sting", SourceFile: "ch2.txt", CreatedAt: day.Add(24 * time.Hour)},
+		{ID: "4", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a thief", AssertedBy: "Gollum", Claim: true, SourceFile: "ch2.txt"},
+		{ID: "5", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "tall", Status: entities.FactStatusPending},
+	}}
+	relationalDB := mocks.NewRelationalDB()
+	for _, e := range []*entities.Entity{
+		{ID: "e1", WorldID: "shire", Name: "Frodo", NormalizedName: "frodo"},
+		{ID: "e2", WorldID: "shire", Name: "Samwise", NormalizedName: "samwise"},
+		{ID: "e3", WorldID: "shire", Name: "Bilbo", NormalizedName: "bilbo"},
+	} {
+		relationalDB.Entities[e.ID] = e
+	}
+	relationalDB.Relationships = []entities.Relationship{
+		{ID: "r1", SourceEntityID: "e1", TargetEntityID: "e2", Type: entities.RelationAlly, Bidirectional: true},
+		{ID: "r2", SourceEntityID: "e3", TargetEntityID: "e1", Type: entities.RelationParent},
+	}
+
+	return NewCardHandler(
+		NewEntityHandler(services.NewEntityService(relationalDB, db)),
+		NewQueryHandler(services.NewQueryService(emb, db, relationalDB)),
+		NewRelationshipHandler(services.NewRelationshipService(db, relationalDB, emb), relationalDB),
+		time.Minute,
+	), db
+}
+
+func TestCardHandler_HandleCard(t *testing.T) {
+	handler, _ := newTestCardHandler()
+
+	card, err := handler.HandleCard(context.Background(), "shire", "frodo")
+	require.NoError(t, err)
+	assert.Equal(t, "Frodo", card.Name)
+	assert.Equal(t, entities.FactTypeCharacter, card.Type)
+	assert.Equal(t, "ch3.txt", card.LastAppearance)
+
+	require.Len(t, card.Facts, 4, "pending facts are left out")
+	assert.Equal(t, CardFact{Predicate: "has_trait", Object: "brave", Source: "ch1.txt"}, card.Facts[0])
+	assert.Equal(t, "Gollum", card.Facts[3].ClaimedBy)
+
+	assert.Equal(t, []CardRelationship{
+		{Type: entities.RelationAlly, Entity: "Samwise"},
+		{Type: entities.RelationParent, Entity: "Bilbo", Incoming: true},
+	}, card.Relationships)
+
+	_, err = handler.HandleCard(context.Background(), "shire", "Gandalf")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestCardHandler_Cache(t *testing.T) {
+	handler, db := newTestCardHandler()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	card, err := handler.HandleCard(ctx, "shire", "Frodo")
+	require.NoError(t, err)
+	require.Len(t, card.Facts, 4)
+
+	db.Facts = db.Facts[:1]
+	card, err = handler.HandleCard(ctx, "shire", "FRODO")
+	require.NoError(t, err)
+	assert.Len(t, card.Facts, 4, "served from the cache")
+
+	now = now.Add(time.Minute)
+	card, err = handler.HandleCard(ctx, "shire", "Frodo")
+	require.NoError(t, err)
+	assert.Len(t, card.Facts, 1, "rebuilt once expired")
+
+	db.Facts = nil
+	handler.Invalidate()
+	card, err = handler.HandleCard(ctx, "shire", "Frodo")
+	require.NoError(t, err)
+	assert.Empty(t, card.Facts, "rebuilt once invalidated")
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/git"
+)
+
+// maxRemovedFacts caps the facts of a removed file inspected for entities
+// left without facts; all of its facts are deleted regardless.
+const maxRemovedFacts = 1000
+
+// ChangesHandler ingests the files that changed in a git repository again,
+// replacing the facts extracted from their earlier versions.
+type ChangesHandler struct {
+	ingestHandler *IngestHandler
+	deletions     *services.DeletionService
+}
+
+// NewChangesHandler creates a new changes handler.
+func NewChangesHandler(ingestHandler *IngestHandler, deletions *services.DeletionService) *ChangesHandler {
+	return &ChangesHandler{
+		ingestHandler: ingestHandler,
+		deletions:     deletions,
+	}
+}
+
+// ChangesOptions controls how changed files are ingested.
+type ChangesOptions struct {
+	// Match reports whether a changed file is ingested, and the source name
+	// its facts are recorded under (nil = every file, under its own path).
+	Match func(file string) (string, bool)
+
+	// Read returns a changed file's contents (nil = read it from disk), such
+	// as its staged contents when checking a commit before it is made.
+	Read func(ctx context.Context, file string) ([]byte, error)
+
+	// Progress is called with each file before it is ingested (nil = off).
+	Progress func(file string)
+
+	// Ingest controls extraction from the files.
+	Ingest IngestOptions
+}
+
+// ChangesResult contains the result of ingesting changed files.
+type ChangesResult struct {
+	IngestBatchResult
+
+	Removed []string // Deleted or renamed files whose facts were deleted
+}
+
+// HandleChanges applies the changes that match: the facts of deleted files
+// are deleted, with the entities and relationships only they named, and
+// added or modified files are ingested after deleting the facts of their
+// earlier version. A renamed file's facts move to its new path. Changes to
+// archived sources are skipped unless Ingest.IncludeArchived.
+//
+// With Ingest.CheckOnly nothing is deleted and changed files are only
+// checked; contradictions with the facts of a file's earlier version are
+// left out, since ingesting it would replace them.
+func (h *ChangesHandler) HandleChanges(ctx context.Context, changes []git.Change, opts *ChangesOptions) (*ChangesResult, error) {
+	checkOnly := opts.Ingest.CheckOnly
+	result := &ChangesResult{}
+	match, err := h.matcher(ctx, opts, result)
+	if err != nil {
+		return nil, err
+	}
+
+	// Facts of a file's earlier version are deleted before any file is
+	// ingested, so they are not reported as contradicting its new version
+	type changed struct {
+		path    string // Where git reports the file
+		source  string // The source its facts are recorded under
+		earlier string // The source of its earlier version's facts
+	}
+	var ingest []changed
+	for _, c := range changes {
+		old := ""
+		switch c.Status {
+		case git.Deleted:
+			old = c.Path
+		case git.Renamed:
+			old = c.OldPath
+		}
+		earlier := ""
+		if old != "" {
+			earlier = h.removeMatching(ctx, old, match, &opts.Ingest, result)
+		}
+
+		source, ok := match(c.Path)
+		if c.Status == git.Deleted || !ok {
+			continue
+		}
+		if c.Status != git.Renamed {
+			earlier = source
+			if !checkOnly {
+				if err := h.deletions.DeleteSource(ctx, &services.SourceDeletionPlan{SourceFile: source}, false); err != nil {
+					result.Errors = append(result.Errors, fmt.Errorf("%s: %w", source, err))
+					continue
+				}
+			}
+		}
+		ingest = append(ingest, changed{path: c.Path, source: source, earlier: earlier})
+	}
+
+	for _, file := range ingest {
+		if opts.Progress != nil {
+			opts.Progress(file.source)
+		}
+		fileResult, err := h.ingestFile(ctx, file.path, file.source, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", file.source, err))
+			continue
+		}
+		if checkOnly && file.earlier != "" {
+			fileResult.Issues = slices.DeleteFunc(fileResult.Issues, func(issue ports.ConsistencyIssue) bool {
+				return issue.ExistingFact.SourceFile == file.earlier
+			})
+		}
+		result.add(fileResult)
+	}
+	return result, nil
+}
+
+// matcher returns opts.Match, leaving out archived sources unless
+// opts.Ingest includes them: they are left as they are, their facts kept
+// even if the file is gone, and listed in result.Archived.
+func (h *ChangesHandler) matcher(ctx context.Context, opts *ChangesOptions, result *ChangesResult) (func(string) (string, bool), error) {
+	match := opts.Match
+	if match == nil {
+		match = func(file string) (string, bool) { return file, true }
+	}
+
+	archived, err := h.ingestHandler.archivedSources(ctx, &opts.Ingest)
+	if err != nil || len(archived) == 0 {
+		return match, err
+	}
+	return func(file string) (string, bool) {
+		source, ok := match(file)
+		if ok && archived[source] {
+			if !slices.Contains(result.Archived, source) {
+				result.Archived = append(result.Archived, source)
+			}
+			return "", false
+		}
+		return source, ok
+	}, nil
+}
+
+// ingestFile ingests a changed file, read from disk or by opts.Read.
+func (h *ChangesHandler) ingestFile(ctx context.Context, path, source string, opts *ChangesOptions) (*IngestResult, error) {
+	if opts.Read == nil {
+		return h.ingestHandler.HandleWithOptions(ctx, source, &opts.Ingest)
+	}
+	data, err := opts.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if docs := opts.Ingest.Documents; docs != nil && docs.CanRead(source) {
+		pages, err := docs.Read(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", source, err)
+		}
+		return h.ingestHandler.HandleDocument(ctx, pages, source, &opts.Ingest)
+	}
+	return h.ingestHandler.HandleReader(ctx, bytes.NewReader(data), source, &opts.Ingest)
+}
+
+// removeMatching deletes the facts of a file that is gone if it matches,
+// and the entities and relationships left behind, returning the source its
+// facts were recorded under, or "" if it does not match.
+func (h *ChangesHandler) removeMatching(ctx context.Context, old string, match func(string) (string, bool), opts *IngestOptions, result *ChangesResult) string {
+	file, ok := match(old)
+	if !ok {
+		return ""
+	}
+	if !opts.CheckOnly {
+		plan, err := h.deletions.PlanSourceDeletion(ctx, opts.WorldID, file, maxRemovedFacts)
+		if err == nil {
+			err = h.deletions.DeleteSource(ctx, plan, true)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("%s: %w", file, err))
+			return file
+		}
+	}
+	result.Removed = append(result.Removed, file)
+	return file
+}
+
+// add adds a file's result to the batch.
+func (r *IngestBatchResult) add(fileResult *IngestResult) {
+	r.FileResults = append(r.FileResults, fileResult)
+	r.TotalFiles++
+	r.TotalFacts += fileResult.FactsCount
+	r.TotalPending += fileResult.PendingCount
+	r.TotalIssues += len(fileResult.Issues)
+	r.TotalQuarantined += fileResult.Quarantined
+}
+
+// MatchFiles returns a function reporting whether a file is one 'lore
+// ingest path' would read: path itself if it is a file, or else a file
+// under the directory path whose name matches pattern, in a subdirectory
+// only if recursive. Files are compared with symlinks resolved, as git
+// reports them, and returned as the ingest would name them, so they match
+// the sources of facts ingested before.
+func MatchFiles(path, pattern string, recursive bool) (func(file string) (string, bool), error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+	root := resolvePath(abs)
+	isDir := IsDirectory(root)
+
+	return func(file string) (string, bool) {
+		resolved := resolvePath(file)
+		if !isDir {
+			return abs, resolved == root
+		}
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", false
+		}
+		if !recursive && filepath.Dir(rel) != "." {
+			return "", false
+		}
+		matched, _ := filepath.Match(pattern, filepath.Base(rel))
+		return filepath.Join(abs, rel), matched
+	}, nil
+}
+
+// resolvePath returns an absolute path with symlinks resolved in as much of
+// it as exists: a deleted file's directory may still be a symlink.
+func resolvePath(abs string) string {
+	dir, rest := abs, ""
+	for {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+		dir = parent
+	}
+}
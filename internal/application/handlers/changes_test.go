@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/git"
+)
+
+func TestChangesHandler_HandleChanges(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"one.txt", "moved.txt", "notes.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("Frodo lived in Bag End."), 0o600))
+	}
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+	ingest := NewIngestHandler(newTestExtractionService(llm, emb, db))
+	handler := NewChangesHandler(ingest, services.NewDeletionService(db, mocks.NewRelationalDB()))
+
+	match, err := MatchFiles(dir, "*.txt", false)
+	require.NoError(t, err)
+
+	changes := []git.Change{
+		{Status: git.Modified, Path: filepath.Join(dir, "one.txt")},
+		{Status: git.Deleted, Path: filepath.Join(dir, "gone.txt")},
+		{Status: git.Renamed, Path: filepath.Join(dir, "moved.txt"), OldPath: filepath.Join(dir, "old.txt")},
+		{Status: git.Modified, Path: filepath.Join(dir, "notes.md")},
+		{Status: git.Modified, Path: filepath.Join(filepath.Dir(dir), "elsewhere.txt")},
+	}
+	var progress []string
+	result, err := handler.HandleChanges(t.Context(), changes, &ChangesOptions{
+		Match: match,
+		Progress: func(file string) {
+			progress = append(progress, file)
+		},
+		Ingest: IngestOptions{WorldID: "w"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "one.txt"), filepath.Join(dir, "moved.txt")}, progress, "only matching files are ingested")
+	assert.Equal(t, []string{filepath.Join(dir, "gone.txt"), filepath.Join(dir, "old.txt")}, result.Removed)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "one.txt"),
+		filepath.Join(dir, "gone.txt"),
+		filepath.Join(dir, "old.txt"),
+	}, db.DeletedSources, "earlier facts are replaced")
+	assert.Equal(t, 2, result.TotalFiles)
+	assert.Equal(t, 2, result.TotalFacts)
+	assert.Empty(t, result.Errors)
+}
+
+func TestChangesHandler_HandleChanges_Archived(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"one.txt", "draft.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("Frodo lived in Bag End."), 0o600))
+	}
+
+	llm := &mocks.LLMClient{Facts: []entities.Fact{{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}}}
+	db := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+	relationalDB.Archived = []entities.ArchivedSource{
+		{Source: filepath.Join(dir, "draft.txt")},
+		{Source: filepath.Join(dir, "old-draft.txt")},
+	}
+	ingest := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{EmbeddingResult: []float32{0.1}}, db), WithSources(services.NewSourceService(relationalDB, db)))
+	handler := NewChangesHandler(ingest, services.NewDeletionService(db, relationalDB))
+
+	match, err := MatchFiles(dir, "*.txt", false)
+	require.NoError(t, err)
+	result, err := handler.HandleChanges(t.Context(), []git.Change{
+		{Status: git.Modified, Path: filepath.Join(dir, "one.txt")},
+		{Status: git.Modified, Path: filepath.Join(dir, "draft.txt")},
+		{Status: git.Deleted, Path: filepath.Join(dir, "old-draft.txt")},
+	}, &ChangesOptions{Match: match, Ingest: IngestOptions{WorldID: "w"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(dir, "one.txt")}, db.DeletedSources, "archived sources' facts are kept")
+	assert.Empty(t, result.Removed)
+	assert.Equal(t, []string{filepath.Join(dir, "draft.txt"), filepath.Join(dir, "old-draft.txt")}, result.Archived)
+	assert.Equal(t, 1, result.TotalFiles)
+}
+
+func TestChangesHandler_HandleChanges_CheckOnly(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "one.txt"), []byte("Frodo lived in Bag End."), 0o600))
+
+	llm := &mocks.LLMClient{Facts: []entities.Fact{{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}}}
+	db := &mocks.VectorDB{}
+	ingest := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{EmbeddingResult: []float32{0.1}}, db))
+	handler := NewChangesHandler(ingest, services.NewDeletionService(db, mocks.NewRelationalDB()))
+
+	match, err := MatchFiles(dir, "*.txt", false)
+	require.NoError(t, err)
+	result, err := handler.HandleChanges(t.Context(), []git.Change{
+		{Status: git.Modified, Path: filepath.Join(dir, "one.txt")},
+		{Status: git.Deleted, Path: filepath.Join(dir, "gone.txt")},
+	}, &ChangesOptions{Match: match, Ingest: IngestOptions{CheckOnly: true}})
+	require.NoError(t, err)
+
+	assert.Empty(t, db.DeletedSources, "nothing is deleted in a dry run")
+	assert.Equal(t, []string{filepath.Join(dir, "gone.txt")}, result.Removed)
+	assert.Equal(t, 1, result.TotalFiles)
+}
+
+func TestChangesHandler_HandleChanges_Staged(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "one.txt")
+	require.NoError(t, os.WriteFile(file, []byte("unstaged edits"), 0o600))
+
+	earlier := entities.Fact{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", SourceFile: file}
+	other := entities.Fact{ID: "2", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Rivendell", SourceFile: "other.txt"}
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Crickhollow"}},
+		Issues: []ports.ConsistencyIssue{
+			{ExistingFact: earlier, Severity: "critical"},
+			{ExistingFact: other, Severity: "critical"},
+		},
+	}
+	db := &mocks.VectorDB{Facts: []entities.Fact{earlier, other}}
+	ingest := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{EmbeddingResult: []float32{0.1}}, db))
+	handler := NewChangesHandler(ingest, services.NewDeletionService(db, mocks.NewRelationalDB()))
+
+	var read []string
+	result, err := handler.HandleChanges(t.Context(), []git.Change{{Status: git.Modified, Path: file}}, &ChangesOptions{
+		Read: func(_ context.Context, path string) ([]byte, error) {
+			read = append(read, path)
+			return []byte("Frodo moved to Crickhollow."), nil
+		},
+		Ingest: IngestOptions{CheckOnly: true, CheckConsistency: true},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{file}, read, "the staged contents are checked")
+	require.Len(t, result.FileResults, 1)
+	require.Len(t, result.FileResults[0].Issues, 1, "the file's earlier facts are not contradictions")
+	assert.Equal(t, "other.txt", result.FileResults[0].Issues[0].ExistingFact.SourceFile)
+	assert.Equal(t, 1, result.TotalIssues)
+}
+
+func TestMatchFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "book"), 0o755))
+	file := filepath.Join(dir, "one.txt")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	match, err := MatchFiles(dir, "*.txt", false)
+	require.NoError(t, err)
+	got, ok := match(file)
+	assert.True(t, ok)
+	assert.Equal(t, file, got)
+	_, ok = match(filepath.Join(dir, "book", "two.txt"))
+	assert.False(t, ok, "subdirectories need recursive")
+	_, ok = match(filepath.Join(dir, "one.md"))
+	assert.False(t, ok)
+	_, ok = match(filepath.Join(filepath.Dir(dir), "other.txt"))
+	assert.False(t, ok)
+
+	match, err = MatchFiles(dir, "*.txt", true)
+	require.NoError(t, err)
+	_, ok = match(filepath.Join(dir, "book", "deleted.txt"))
+	assert.True(t, ok, "files that no longer exist still match")
+
+	match, err = MatchFiles(file, "*.md", false)
+	require.NoError(t, err)
+	_, ok = match(file)
+	assert.True(t, ok, "a file matches itself whatever the pattern")
+	_, ok = match(filepath.Join(dir, "two.txt"))
+	assert.False(t, ok)
+
+	_, err = MatchFiles(dir, "[", false)
+	assert.Error(t, err)
+}
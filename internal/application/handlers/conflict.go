@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// ConflictHandler handles contradictions recorded between facts.
+type ConflictHandler struct {
+	conflictService *services.ConflictService
+}
+
+// NewConflictHandler creates a new conflict handler.
+func NewConflictHandler(conflictService *services.ConflictService) *ConflictHandler {
+	return &ConflictHandler{
+		conflictService: conflictService,
+	}
+}
+
+// HandleCheck analyzes the whole database for contradictions and records them.
+func (h *ConflictHandler) HandleCheck(ctx context.Context, opts *services.CheckOptions) (*services.CheckResult, error) {
+	return h.conflictService.Check(ctx, opts)
+}
+
+// HandleList returns recorded conflicts with the facts they involve.
+func (h *ConflictHandler) HandleList(ctx context.Context, opts ports.ConflictListOptions) ([]services.ConflictDetail, error) {
+	return h.conflictService.List(ctx, opts)
+}
+
+// HandleResolve marks a conflict as resolved.
+func (h *ConflictHandler) HandleResolve(ctx context.Context, id string, resolution string) error {
+	return h.conflictService.Resolve(ctx, id, resolution)
+}
+
+// HandleOpenCounts returns the number of open conflicts each fact is part of,
+// for flagging facts in listings.
+func (h *ConflictHandler) HandleOpenCounts(ctx context.Context, facts []entities.Fact) (map[string]int, error) {
+	return h.conflictService.OpenCounts(ctx, facts)
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/services"
@@ -43,6 +44,19 @@ func (h *EntityHandler) HandleList(ctx context.Context, worldID string, limit, o
 	}, nil
 }
 
+// HandleGet returns the named entity, or an entities.ErrNotFound error if
+// the world has none by that name.
+func (h *EntityHandler) HandleGet(ctx context.Context, worldID, name string) (*entities.Entity, error) {
+	entity, err := h.entityService.FindByName(ctx, worldID, name)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity: %w", err)
+	}
+	if entity == nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "entity %q not found in world %s", name, worldID)
+	}
+	return entity, nil
+}
+
 // HandleSearch searches entities by name pattern.
 func (h *EntityHandler) HandleSearch(ctx context.Context, worldID, query string, limit int) (*EntityListResult, error) {
 	entitiesList, err := h.entityService.Search(ctx, worldID, query, limit)
@@ -56,11 +70,36 @@ func (h *EntityHandler) HandleSearch(ctx context.Context, worldID, query string,
 	}, nil
 }
 
-// HandleDelete removes an entity and its relationships.
+// HandleUsage counts the facts and relationships referring to each entity.
+func (h *EntityHandler) HandleUsage(ctx context.Context, worldID string, list []*entities.Entity) ([]services.EntityUsage, error) {
+	return h.entityService.Usage(ctx, worldID, list)
+}
+
+// HandleOrphans returns entities with no facts and no relationships.
+func (h *EntityHandler) HandleOrphans(ctx context.Context, worldID string) ([]*entities.Entity, error) {
+	return h.entityService.Orphans(ctx, worldID)
+}
+
+// HandleDelete removes an entity, its relationships, and the facts
+// representing them.
 func (h *EntityHandler) HandleDelete(ctx context.Context, entityID string) error {
 	return h.entityService.Delete(ctx, entityID)
 }
 
+// HandlePlanDelete previews deleting the named entity.
+func (h *EntityHandler) HandlePlanDelete(ctx context.Context, worldID, name string) (*services.EntityDeletionPlan, error) {
+	entity, err := h.HandleGet(ctx, worldID, name)
+	if err != nil {
+		return nil, err
+	}
+	return h.entityService.PlanDelete(ctx, entity.ID)
+}
+
+// HandleDeletePlan carries out a deletion previewed by HandlePlanDelete.
+func (h *EntityHandler) HandleDeletePlan(ctx context.Context, plan *services.EntityDeletionPlan) error {
+	return h.entityService.DeletePlan(ctx, plan)
+}
+
 // HandleCount returns the number of entities in a world.
 func (h *EntityHandler) HandleCount(ctx context.Context, worldID string) (int, error) {
 	return h.entityService.Count(ctx, worldID)
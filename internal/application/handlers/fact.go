@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// FactHandler handles facts entered by hand.
+type FactHandler struct {
+	factService *services.FactService
+}
+
+// NewFactHandler creates a new fact handler.
+func NewFactHandler(factService *services.FactService) *FactHandler {
+	return &FactHandler{
+		factService: factService,
+	}
+}
+
+// HandleCheck validates and embeds a fact without storing it, returning
+// existing facts that are likely duplicates of it.
+func (h *FactHandler) HandleCheck(ctx context.Context, input services.NewFact) (*entities.Fact, []services.SimilarFact, error) {
+	fact, err := h.factService.Prepare(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	similar, err := h.factService.FindSimilar(ctx, fact)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fact, similar, nil
+}
+
+// HandleSave stores a fact returned by HandleCheck.
+func (h *FactHandler) HandleSave(ctx context.Context, fact *entities.Fact) error {
+	return h.factService.Save(ctx, fact)
+}
+
+// HandleGet returns a fact by ID.
+func (h *FactHandler) HandleGet(ctx context.Context, id string) (*entities.Fact, error) {
+	return h.factService.Get(ctx, id)
+}
+
+// HandleUpdate edits a fact. A non-empty revision is the fact's Revision
+// the edit was based on; if the fact has changed since, the edit fails with
+// an *entities.StaleFactError holding both versions.
+func (h *FactHandler) HandleUpdate(ctx context.Context, id, revision string, edit services.FactEdit) (*entities.Fact, error) {
+	return h.factService.Update(ctx, id, revision, edit, "")
+}
+
+// HandleSetKnownBy records which entities of a world know a fact. No names
+// makes it common knowledge.
+func (h *FactHandler) HandleSetKnownBy(ctx context.Context, id, worldID string, names []string) (*entities.Fact, error) {
+	return h.factService.SetKnownBy(ctx, id, worldID, names, "")
+}
+
+// HandleListSource returns up to limit facts recorded for a source.
+func (h *FactHandler) HandleListSource(ctx context.Context, source string, limit int) ([]entities.Fact, error) {
+	return h.factService.ListBySource(ctx, source, limit)
+}
+
+// HandlePlanEdit compares a source's facts with an edited list of them.
+func (h *FactHandler) HandlePlanEdit(ctx context.Context, source string, before []entities.Fact, edited []entities.RawFact) (*services.FactBatch, error) {
+	return h.factService.PlanBatch(ctx, source, before, edited)
+}
+
+// HandleApplyEdit applies a planned edit, returning what was applied even
+// when it stops early.
+func (h *FactHandler) HandleApplyEdit(ctx context.Context, batch *services.FactBatch) (services.FactBatchResult, error) {
+	return h.factService.ApplyBatch(ctx, batch, "")
+}
+
+// HandlePromote makes a draft canon.
+func (h *FactHandler) HandlePromote(ctx context.Context, id string) (*entities.Fact, error) {
+	return h.factService.Promote(ctx, id, "")
+}
+
+// HandlePurgeDrafts removes the drafts opts selects and returns them.
+func (h *FactHandler) HandlePurgeDrafts(ctx context.Context, opts services.DraftPurge) ([]entities.Fact, error) {
+	return h.factService.PurgeDrafts(ctx, opts)
+}
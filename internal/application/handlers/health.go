@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// HealthHandler handles world health measurements.
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+// NewHealthHandler creates a new health handler.
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// HandleMeasure computes the current health of a world without recording it.
+func (h *HealthHandler) HandleMeasure(ctx context.Context, opts services.HealthOptions) (*entities.HealthSample, error) {
+	return h.healthService.Measure(ctx, opts)
+}
+
+// HandleRecord measures the current health of a world and records it.
+func (h *HealthHandler) HandleRecord(ctx context.Context, opts services.HealthOptions) (*entities.HealthSample, error) {
+	return h.healthService.Record(ctx, opts)
+}
+
+// HandleHistory returns the last limit recorded measurements, oldest first.
+func (h *HealthHandler) HandleHistory(ctx context.Context, limit int) ([]entities.HealthSample, error) {
+	return h.healthService.History(ctx, limit)
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/services"
 	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
 )
@@ -23,19 +25,29 @@ func NewImportHandler(service *services.ImportService) *ImportHandler {
 
 // ImportOptions controls import behavior.
 type ImportOptions struct {
-	Format     string                    // "json", "csv", or "auto"
+	WorldID    string                    // World that entities and relationships belong to
+	Format     string                    // "json", "yaml", "csv", or "auto"
 	DryRun     bool                      // Validate without saving
 	OnConflict services.ConflictStrategy // How to handle existing facts
+	Rules      []services.ImportRule     // Extra validation rules
+
+	CheckSimilar bool // Report existing facts similar to imported ones
 }
 
 // ImportResult contains the result of an import operation.
 type ImportResult struct {
 	Imported int
 	Skipped  int
+	Merged   int
 	Errors   []services.ImportError
+	Similar  []services.SimilarImport
+
+	Entities      int // Entities created from a document's entities section
+	Relationships int // Relationships created from a document's relationships section
 }
 
-// Handle imports facts from a file.
+// Handle imports facts from a file. JSON and YAML files may also hold a
+// document with entities and relationships sections.
 func (h *ImportHandler) Handle(ctx context.Context, filePath string, opts ImportOptions) (*ImportResult, error) {
 	// Get parser
 	var parser parsers.Parser
@@ -46,7 +58,7 @@ func (h *ImportHandler) Handle(ctx context.Context, filePath string, opts Import
 	}
 
 	if parser == nil {
-		return nil, fmt.Errorf("unsupported format for file: %s", filePath)
+		return nil, entities.Errorf(entities.ErrValidation, "unsupported format for file: %s", filePath)
 	}
 
 	// Open file
@@ -56,23 +68,25 @@ func (h *ImportHandler) Handle(ctx context.Context, filePath string, opts Import
 	}
 	defer file.Close()
 
-	// Parse facts
-	rawFacts, err := parser.Parse(file)
+	doc, err := parseDocument(parser, file)
 	if err != nil {
 		return nil, fmt.Errorf("parsing file: %w", err)
 	}
 
-	if len(rawFacts) == 0 {
+	if doc.IsEmpty() {
 		return &ImportResult{}, nil
 	}
 
-	// Import facts
+	// Import document
 	serviceOpts := services.ImportOptions{
 		DryRun:     opts.DryRun,
 		OnConflict: opts.OnConflict,
+		Rules:      opts.Rules,
+
+		CheckSimilar: opts.CheckSimilar,
 	}
 
-	serviceResult, err := h.service.Import(ctx, rawFacts, serviceOpts)
+	serviceResult, err := h.service.ImportDocument(ctx, opts.WorldID, doc, serviceOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +94,25 @@ func (h *ImportHandler) Handle(ctx context.Context, filePath string, opts Import
 	return &ImportResult{
 		Imported: serviceResult.Imported,
 		Skipped:  serviceResult.Skipped,
+		Merged:   serviceResult.Merged,
 		Errors:   serviceResult.Errors,
+		Similar:  serviceResult.Similar,
+
+		Entities:      serviceResult.Entities,
+		Relationships: serviceResult.Relationships,
 	}, nil
 }
+
+// parseDocument parses a full document when the parser supports one,
+// otherwise a document holding only facts.
+func parseDocument(parser parsers.Parser, r io.Reader) (*entities.ImportDocument, error) {
+	if dp, ok := parser.(parsers.DocumentParser); ok {
+		return dp.ParseDocument(r)
+	}
+
+	rawFacts, err := parser.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.ImportDocument{Facts: rawFacts}, nil
+}
@@ -28,7 +28,7 @@ func newTestEntityTypeService() *services.EntityTypeService {
 func TestImportHandler_Handle_JSONFile(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp JSON file
@@ -50,7 +50,7 @@ func TestImportHandler_Handle_JSONFile(t *testing.T) {
 func TestImportHandler_Handle_CSVFile(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp CSV file
@@ -70,7 +70,7 @@ func TestImportHandler_Handle_CSVFile(t *testing.T) {
 func TestImportHandler_Handle_AutoFormat(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp JSON file
@@ -92,7 +92,7 @@ func TestImportHandler_Handle_AutoFormat(t *testing.T) {
 func TestImportHandler_Handle_ExplicitFormat(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp file with .txt extension but JSON content
@@ -114,7 +114,7 @@ func TestImportHandler_Handle_ExplicitFormat(t *testing.T) {
 func TestImportHandler_Handle_UnsupportedFormat(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp file with unsupported extension
@@ -131,7 +131,7 @@ func TestImportHandler_Handle_UnsupportedFormat(t *testing.T) {
 func TestImportHandler_Handle_FileNotFound(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	_, err := handler.Handle(context.Background(), "/nonexistent/file.json", ImportOptions{})
@@ -143,7 +143,7 @@ func TestImportHandler_Handle_FileNotFound(t *testing.T) {
 func TestImportHandler_Handle_DryRun(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp JSON file
@@ -164,7 +164,7 @@ func TestImportHandler_Handle_DryRun(t *testing.T) {
 func TestImportHandler_Handle_EmptyFile(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
-	service := services.NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := services.NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	handler := NewImportHandler(service)
 
 	// Create temp empty JSON file
@@ -179,3 +179,37 @@ func TestImportHandler_Handle_EmptyFile(t *testing.T) {
 	assert.Equal(t, 0, result.Skipped)
 	assert.Empty(t, result.Errors)
 }
+
+func TestImportHandler_Handle_YAMLDocument(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+	service := services.NewImportService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	handler := NewImportHandler(service)
+
+	tmpDir := t.TempDir()
+	yamlFile := filepath.Join(tmpDir, "bible.yaml")
+	content := `entities:
+  - Frodo
+  - The Shire
+facts:
+  - {type: character, subject: Frodo, predicate: lives_in, object: The Shire}
+relationships:
+  - {source: Frodo, type: located_in, target: The Shire}
+  - {source: Frodo, type: ally, target: Sam}
+`
+	require.NoError(t, os.WriteFile(yamlFile, []byte(content), 0644))
+
+	result, err := handler.Handle(context.Background(), yamlFile, ImportOptions{
+		WorldID:    "middle-earth",
+		OnConflict: services.ConflictOverwrite,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Entities)
+	assert.Equal(t, 1, result.Relationships)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 8, result.Errors[0].Line)
+	assert.Len(t, relationalDB.Entities, 2)
+}
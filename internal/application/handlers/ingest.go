@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,47 +15,138 @@ import (
 
 // IngestHandler handles file ingestion.
 type IngestHandler struct {
-	extractionService *services.ExtractionService
+	extractionService     *services.ExtractionService
+	disambiguationService *services.DisambiguationService
+	conflictService       *services.ConflictService
+	styleService          *services.StyleService
+	sourceService         *services.SourceService
 }
 
-// NewIngestHandler creates a new ingest handler.
-func NewIngestHandler(extractionService *services.ExtractionService) *IngestHandler {
-	return &IngestHandler{
-		extractionService: extractionService,
+// IngestHandlerOption configures an IngestHandler.
+type IngestHandlerOption func(*IngestHandler)
+
+// WithDisambiguation matches extracted subjects to the world's entities.
+// Without it, subjects are left as extracted.
+func WithDisambiguation(svc *services.DisambiguationService) IngestHandlerOption {
+	return func(h *IngestHandler) {
+		h.disambiguationService = svc
+	}
+}
+
+// WithConflicts records the consistency issues found. Without it, issues
+// are reported but not recorded.
+func WithConflicts(svc *services.ConflictService) IngestHandlerOption {
+	return func(h *IngestHandler) {
+		h.conflictService = svc
 	}
 }
 
+// WithStyle checks ingested facts against the world's style sheet.
+// Without it, the style sheet is not checked.
+func WithStyle(svc *services.StyleService) IngestHandlerOption {
+	return func(h *IngestHandler) {
+		h.styleService = svc
+	}
+}
+
+// WithSources records the word and fact counts of ingested sources, and
+// quarantines chunks whose facts cannot be extracted. Without it, such a
+// chunk aborts the ingest.
+func WithSources(svc *services.SourceService) IngestHandlerOption {
+	return func(h *IngestHandler) {
+		h.sourceService = svc
+	}
+}
+
+// NewIngestHandler creates a new ingest handler extracting facts with
+// extractionService.
+func NewIngestHandler(extractionService *services.ExtractionService, opts ...IngestHandlerOption) *IngestHandler {
+	h := &IngestHandler{extractionService: extractionService}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 // IngestOptions controls ingestion behavior.
 type IngestOptions struct {
-	CheckConsistency bool // Check for contradictions with existing facts
-	CheckOnly        bool // Only check, don't save facts
+	CheckConsistency bool   // Check for contradictions with existing facts
+	CheckOnly        bool   // Only check, don't save facts
+	ResolvePronouns  bool   // Replace pronoun subjects and objects with names
+	CarryContext     bool   // Give each chunk a summary of the text before it
+	Dialogue         bool   // Extract speakers' lines as asserted by them
+	Narrator         string // Unreliable narrator whose claims the narration is (empty = canon)
+	WorldID          string // World whose entities subjects are matched against (empty = no disambiguation)
+	IncludeArchived  bool   // Ingest archived sources too, their facts still labeled archived
+
+	// ReviewThreshold holds facts with lower confidence for review (0 = off).
+	ReviewThreshold float64
+
+	// Retrieval selects the stored facts new facts are checked against.
+	Retrieval services.Retrieval
+
+	// Focus adds a specialized extraction pass for each kind of fact listed.
+	Focus []ports.ExtractionFocus
+
+	// Reliable lists the speakers whose lines are canon rather than claims.
+	Reliable []string
+
+	// Chunker splits the text into chunks (nil = whole paragraphs).
+	Chunker ports.Chunker
+
+	// MaxFileSize is the largest source ingested (0 =
+	// services.DefaultMaxFileSize).
+	MaxFileSize entities.ByteSize
+
+	// Documents extracts the text of the document files it can read, such
+	// as PDFs (nil = text files only).
+	Documents ports.DocumentReader
+
+	// Sources give facts the metadata and tags of their source (nil = none).
+	Sources services.SourceRules
+
+	// ChooseEntity is asked when a subject matches several entities equally
+	// well. Nil picks the best-ranked entity.
+	ChooseEntity services.EntityChooser
+
+	// OnChunk is called with the facts of each chunk as it is extracted,
+	// before they are embedded or saved (nil = off).
+	OnChunk func(services.ChunkProgress)
 }
 
 // IngestResult contains the result of ingestion.
 type IngestResult struct {
-	FilePath   string
-	FactsCount int
-	Facts      []entities.Fact
-	Issues     []ports.ConsistencyIssue
+	FilePath        string
+	FactsCount      int
+	PendingCount    int // Facts held for review
+	Facts           []entities.Fact
+	Issues          []ports.ConsistencyIssue
+	StyleIssues     []entities.StyleIssue // Spellings differing from the style sheet
+	Disambiguations []services.Disambiguation
+	Quarantined     int // Chunks whose facts could not be extracted, kept to retry
 }
 
 // IngestBatchResult contains the result of batch ingestion.
 type IngestBatchResult struct {
-	TotalFiles  int
-	TotalFacts  int
-	TotalIssues int
-	FileResults []*IngestResult
-	Errors      []error
+	TotalFiles       int
+	TotalFacts       int
+	TotalPending     int
+	TotalIssues      int
+	TotalQuarantined int
+	FileResults      []*IngestResult
+	Archived         []string // Files skipped because their source is archived
+	Errors           []error
 }
 
 // Handle ingests a file and extracts facts.
 func (h *IngestHandler) Handle(ctx context.Context, filePath string) (*IngestResult, error) {
-	return h.HandleWithOptions(ctx, filePath, IngestOptions{})
+	return h.HandleWithOptions(ctx, filePath, &IngestOptions{})
 }
 
 // HandleWithOptions ingests a file with consistency checking options.
-// Uses streaming to avoid loading entire file into memory.
-func (h *IngestHandler) HandleWithOptions(ctx context.Context, filePath string, opts IngestOptions) (*IngestResult, error) {
+// Uses streaming to avoid loading entire file into memory. Documents
+// opts.Documents can read, such as PDFs, are ingested as their text.
+func (h *IngestHandler) HandleWithOptions(ctx context.Context, filePath string, opts *IngestOptions) (*IngestResult, error) {
 	absPath, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("resolving path: %w", err)
@@ -66,7 +158,11 @@ func (h *IngestHandler) HandleWithOptions(ctx context.Context, filePath string,
 	}
 
 	if info.IsDir() {
-		return nil, fmt.Errorf("path is a directory, not a file: %s", absPath)
+		return nil, entities.Errorf(entities.ErrValidation, "path is a directory, not a file: %s", absPath)
+	}
+	if maxSize := maxFileSize(opts); entities.ByteSize(info.Size()) > maxSize {
+		return nil, entities.Errorf(entities.ErrValidation, "%s is %s, larger than the %s limit for one source",
+			absPath, entities.ByteSize(info.Size()), maxSize)
 	}
 
 	file, err := os.Open(absPath)
@@ -75,31 +171,268 @@ func (h *IngestHandler) HandleWithOptions(ctx context.Context, filePath string,
 	}
 	defer file.Close()
 
-	extractOpts := services.ExtractionOptions{
-		CheckConsistency: opts.CheckConsistency,
-		CheckOnly:        opts.CheckOnly,
+	if opts.Documents != nil && opts.Documents.CanRead(absPath) {
+		pages, err := opts.Documents.Read(file, info.Size())
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", absPath, err)
+		}
+		return h.HandleDocument(ctx, pages, absPath, opts)
+	}
+	return h.HandleReader(ctx, file, absPath, opts)
+}
+
+// HandleReader ingests text read from r, recording source as the facts'
+// source file. UTF-16 and Windows-1252 text is converted to UTF-8, and
+// binary content and text larger than opts.MaxFileSize are rejected.
+func (h *IngestHandler) HandleReader(ctx context.Context, r io.Reader, source string, opts *IngestOptions) (*IngestResult, error) {
+	archived, err := h.checkArchived(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err = services.ReadText(r, maxFileSize(opts))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+
+	return h.ingest(ctx, source, archived, opts, func(extractOpts *services.ExtractionOptions) (*services.ExtractionResult, error) {
+		return h.extractionService.ExtractFromReader(ctx, r, source, extractOpts)
+	})
+}
+
+// HandleDocument ingests the pages of a document read from source, as
+// HandleReader ingests text, recording the page each fact was found on.
+func (h *IngestHandler) HandleDocument(ctx context.Context, pages []ports.DocumentPage, source string, opts *IngestOptions) (*IngestResult, error) {
+	archived, err := h.checkArchived(ctx, source, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.ingest(ctx, source, archived, opts, func(extractOpts *services.ExtractionOptions) (*services.ExtractionResult, error) {
+		return h.extractionService.ExtractFromPages(ctx, pages, source, extractOpts)
+	})
+}
+
+// checkArchived reports whether source is archived, refusing it unless
+// opts includes archived sources.
+func (h *IngestHandler) checkArchived(ctx context.Context, source string, opts *IngestOptions) (bool, error) {
+	archived, err := h.isArchived(ctx, source)
+	if err != nil {
+		return false, err
+	}
+	if archived && !opts.IncludeArchived {
+		return false, entities.Errorf(entities.ErrConflict, "source %s is archived; unarchive it to ingest it again", source)
+	}
+	return archived, nil
+}
+
+// ingest extracts the facts of source with extract and records them.
+func (h *IngestHandler) ingest(ctx context.Context, source string, archived bool, opts *IngestOptions, extract func(*services.ExtractionOptions) (*services.ExtractionResult, error)) (*IngestResult, error) {
+	var disambiguations []services.Disambiguation
+	extractOpts, err := h.extractOptions(ctx, source, opts, &disambiguations)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := h.extractionService.ExtractFromReader(ctx, file, absPath, extractOpts)
+	result, err := extract(&extractOpts)
 	if err != nil {
 		return nil, fmt.Errorf("extracting facts: %w", err)
 	}
+	return h.record(ctx, source, archived, opts, result, disambiguations)
+}
+
+// extractOptions returns the options facts are extracted from source
+// with. Chunks that fail are quarantined, replacing those left from an
+// earlier ingest, and the subjects matched to entities are stored in
+// disambiguations.
+func (h *IngestHandler) extractOptions(ctx context.Context, source string, opts *IngestOptions, disambiguations *[]services.Disambiguation) (services.ExtractionOptions, error) {
+	extractOpts := extractionOptions(opts)
+
+	// A chunk that fails is quarantined rather than aborting the ingest;
+	// those left from an earlier ingest of the source are replaced
+	if h.sourceService != nil && !opts.CheckOnly {
+		if err := h.sourceService.ClearQuarantine(ctx, source); err != nil {
+			return extractOpts, err
+		}
+		extractOpts.Quarantine = h.sourceService.Quarantine
+	}
+
+	if h.disambiguationService != nil && opts.WorldID != "" {
+		extractOpts.Disambiguate = func(ctx context.Context, facts []entities.Fact) error {
+			resolved, err := h.disambiguationService.Resolve(ctx, opts.WorldID, facts, opts.ChooseEntity)
+			*disambiguations = resolved
+			return err
+		}
+	}
+	return extractOpts, nil
+}
+
+// record records what was extracted from source: its consistency issues,
+// its word and fact counts, and, if it is archived, the archived label of
+// its facts. It returns the result of the ingest, with the facts' style
+// issues.
+func (h *IngestHandler) record(ctx context.Context, source string, archived bool, opts *IngestOptions, result *services.ExtractionResult, disambiguations []services.Disambiguation) (*IngestResult, error) {
+	// Issues between saved facts are kept so they can be flagged and
+	// resolved later
+	if h.conflictService != nil && !opts.CheckOnly && len(result.Issues) > 0 {
+		if _, err := h.conflictService.Record(ctx, result.Issues); err != nil {
+			return nil, err
+		}
+	}
+
+	if h.sourceService != nil && !opts.CheckOnly {
+		if err := h.sourceService.Record(ctx, source, result.Words, len(result.Facts)); err != nil {
+			return nil, err
+		}
+		if archived {
+			if err := h.sourceService.LabelArchived(ctx, source); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var styleIssues []entities.StyleIssue
+	if h.styleService != nil {
+		sheet, err := h.styleService.Sheet(ctx)
+		if err != nil {
+			return nil, err
+		}
+		styleIssues = sheet.Check(result.Facts)
+	}
+
+	pending := 0
+	for i := range result.Facts {
+		if result.Facts[i].IsPending() {
+			pending++
+		}
+	}
 
 	return &IngestResult{
-		FilePath:   absPath,
-		FactsCount: len(result.Facts),
-		Facts:      result.Facts,
-		Issues:     result.Issues,
+		FilePath:     source,
+		FactsCount:   len(result.Facts),
+		PendingCount: pending,
+		Facts:        result.Facts,
+		Issues:       result.Issues,
+		StyleIssues:  styleIssues,
+
+		Disambiguations: disambiguations,
+		Quarantined:     result.Quarantined,
 	}, nil
 }
 
+// isArchived reports whether source is archived. Without a source service
+// no source is.
+func (h *IngestHandler) isArchived(ctx context.Context, source string) (bool, error) {
+	archived, err := h.archivedSources(ctx, &IngestOptions{})
+	return archived[source], err
+}
+
+// archivedSources returns the archived sources an ingest with opts skips:
+// none if it includes them or there is no source service.
+func (h *IngestHandler) archivedSources(ctx context.Context, opts *IngestOptions) (map[string]bool, error) {
+	if h.sourceService == nil || opts.IncludeArchived {
+		return nil, nil
+	}
+	return h.sourceService.ArchivedSet(ctx)
+}
+
+// maxFileSize returns the largest source an ingest with opts reads.
+func maxFileSize(opts *IngestOptions) entities.ByteSize {
+	if opts.MaxFileSize > 0 {
+		return opts.MaxFileSize
+	}
+	return services.DefaultMaxFileSize
+}
+
+// extractionOptions returns the extraction options matching opts.
+func extractionOptions(opts *IngestOptions) services.ExtractionOptions {
+	return services.ExtractionOptions{
+		CheckConsistency: opts.CheckConsistency,
+		CheckOnly:        opts.CheckOnly,
+		ResolvePronouns:  opts.ResolvePronouns,
+		CarryContext:     opts.CarryContext,
+		Dialogue:         opts.Dialogue,
+		Narrator:         opts.Narrator,
+		Reliable:         opts.Reliable,
+		Focus:            opts.Focus,
+		ReviewThreshold:  opts.ReviewThreshold,
+		Retrieval:        opts.Retrieval,
+		OnChunk:          opts.OnChunk,
+		Chunker:          opts.Chunker,
+		Sources:          opts.Sources,
+	}
+}
+
+// RetryResult contains the result of retrying quarantined chunks.
+type RetryResult struct {
+	Retried      int
+	FactsCount   int
+	PendingCount int // Facts held for review
+	Issues       []ports.ConsistencyIssue
+	Failed       []entities.FailedChunk // Chunks that failed again, still quarantined
+}
+
+// HandleRetryFailed extracts the facts of the quarantined chunks again, of
+// one source or of all if source is empty. Chunks that succeed are released
+// from quarantine; those that fail again stay, with the new error.
+// Disambiguation is not applied, since a chunk's facts are retried apart
+// from the rest of its source.
+func (h *IngestHandler) HandleRetryFailed(ctx context.Context, source string, opts *IngestOptions) (*RetryResult, error) {
+	if h.sourceService == nil {
+		return nil, entities.Errorf(entities.ErrValidation, "quarantined chunks are not available")
+	}
+
+	chunks, err := h.sourceService.Quarantined(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	extractOpts := extractionOptions(opts)
+	extractOpts.CheckOnly = false
+	result := &RetryResult{}
+	for i := range chunks {
+		chunk := &chunks[i]
+		extracted, err := h.extractionService.RetryChunk(ctx, chunk, &extractOpts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err := h.sourceService.RetryFailed(ctx, chunk, err); err != nil {
+				return nil, err
+			}
+			result.Retried++
+			result.Failed = append(result.Failed, *chunk)
+			continue
+		}
+
+		if h.conflictService != nil && len(extracted.Issues) > 0 {
+			if _, err := h.conflictService.Record(ctx, extracted.Issues); err != nil {
+				return nil, err
+			}
+		}
+		if err := h.sourceService.RetrySucceeded(ctx, chunk, len(extracted.Facts)); err != nil {
+			return nil, err
+		}
+
+		result.Retried++
+		result.FactsCount += len(extracted.Facts)
+		result.Issues = append(result.Issues, extracted.Issues...)
+		for j := range extracted.Facts {
+			if extracted.Facts[j].IsPending() {
+				result.PendingCount++
+			}
+		}
+	}
+	return result, nil
+}
+
 // HandleDirectory ingests all matching files in a directory.
 func (h *IngestHandler) HandleDirectory(ctx context.Context, dirPath string, pattern string, recursive bool, progressFn func(file string)) (*IngestBatchResult, error) {
-	return h.HandleDirectoryWithOptions(ctx, dirPath, pattern, recursive, progressFn, IngestOptions{})
+	return h.HandleDirectoryWithOptions(ctx, dirPath, pattern, recursive, progressFn, &IngestOptions{})
 }
 
 // HandleDirectoryWithOptions ingests all matching files with consistency checking options.
-func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath string, pattern string, recursive bool, progressFn func(file string), opts IngestOptions) (*IngestBatchResult, error) {
+func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath string, pattern string, recursive bool, progressFn func(file string), opts *IngestOptions) (*IngestBatchResult, error) {
 	absPath, err := filepath.Abs(dirPath)
 	if err != nil {
 		return nil, fmt.Errorf("resolving path: %w", err)
@@ -111,7 +444,7 @@ func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath
 	}
 
 	if !info.IsDir() {
-		return nil, fmt.Errorf("path is not a directory: %s", absPath)
+		return nil, entities.Errorf(entities.ErrValidation, "path is not a directory: %s", absPath)
 	}
 
 	files, err := h.findFiles(absPath, pattern, recursive)
@@ -120,7 +453,12 @@ func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath
 	}
 
 	if len(files) == 0 {
-		return nil, fmt.Errorf("no files matching pattern %q found in %s", pattern, absPath)
+		return nil, entities.Errorf(entities.ErrNotFound, "no files matching pattern %q found in %s", pattern, absPath)
+	}
+
+	archived, err := h.archivedSources(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	result := &IngestBatchResult{
@@ -128,6 +466,10 @@ func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath
 	}
 
 	for _, file := range files {
+		if archived[file] {
+			result.Archived = append(result.Archived, file)
+			continue
+		}
 		if progressFn != nil {
 			progressFn(file)
 		}
@@ -138,10 +480,7 @@ func (h *IngestHandler) HandleDirectoryWithOptions(ctx context.Context, dirPath
 			continue
 		}
 
-		result.FileResults = append(result.FileResults, fileResult)
-		result.TotalFiles++
-		result.TotalFacts += fileResult.FactsCount
-		result.TotalIssues += len(fileResult.Issues)
+		result.add(fileResult)
 	}
 
 	return result, nil
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +14,7 @@ import (
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/domain/services"
 )
 
@@ -86,7 +91,7 @@ func TestIngestHandler_HandleWithOptions_CheckOnly(t *testing.T) {
 	handler := NewIngestHandler(svc)
 
 	opts := IngestOptions{CheckOnly: true}
-	result, err := handler.HandleWithOptions(t.Context(), testFile, opts)
+	result, err := handler.HandleWithOptions(t.Context(), testFile, &opts)
 
 	require.NoError(t, err)
 	assert.Equal(t, 1, result.FactsCount)
@@ -94,6 +99,226 @@ func TestIngestHandler_HandleWithOptions_CheckOnly(t *testing.T) {
 	assert.Equal(t, 0, db.SaveBatchCallCount, "SaveBatch should not be called in check-only mode")
 }
 
+func TestIngestHandler_HandleWithOptions_Disambiguation(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	require.NoError(t, os.WriteFile(testFile, []byte("John baked bread."), 0644))
+
+	relationalDB := mocks.NewRelationalDB()
+	for id, name := range map[string]string{"baker": "John the Baker", "king": "King John"} {
+		relationalDB.Entities[id] = &entities.Entity{ID: id, WorldID: "w", Name: name, NormalizedName: entities.NormalizeName(name)}
+	}
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "John", Predicate: "is", Object: "tall"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+
+	svc := newTestExtractionService(llm, emb, db)
+	handler := NewIngestHandler(svc, WithDisambiguation(services.NewDisambiguationService(relationalDB)))
+
+	var asked []string
+	opts := IngestOptions{
+		WorldID: "w",
+		ChooseEntity: func(_ context.Context, _ *entities.Fact, candidates []services.EntityCandidate) (int, error) {
+			for _, c := range candidates {
+				asked = append(asked, c.Entity.Name)
+			}
+			return 1, nil
+		},
+	}
+	result, err := handler.HandleWithOptions(t.Context(), testFile, &opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"John the Baker", "King John"}, asked)
+	assert.Equal(t, "King John", result.Facts[0].Subject)
+	require.Len(t, result.Disambiguations, 1)
+	assert.Equal(t, "King John", result.Disambiguations[0].Entity)
+	assert.Equal(t, "King John", db.SaveBatchLastFacts[0].Subject)
+}
+
+func TestIngestHandler_HandleWithOptions_RecordsConflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		checkOnly bool
+		want      int
+	}{
+		{name: "saved facts recorded", want: 1},
+		{name: "dry run not recorded", checkOnly: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			testFile := filepath.Join(tmpDir, "test.txt")
+			require.NoError(t, os.WriteFile(testFile, []byte("Frodo has green eyes."), 0644))
+
+			existing := entities.Fact{ID: "existing", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue"}
+			llm := &mocks.LLMClient{
+				Facts: []entities.Fact{
+					{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "green"},
+				},
+				Issues: []ports.ConsistencyIssue{
+					{NewFact: entities.Fact{ID: "new"}, ExistingFact: existing, Description: "eye colors differ", Severity: "major"},
+				},
+			}
+			emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+			db := &mocks.VectorDB{Facts: []entities.Fact{existing}}
+			relationalDB := mocks.NewRelationalDB()
+
+			svc := newTestExtractionService(llm, emb, db)
+			handler := NewIngestHandler(svc, WithConflicts(services.NewConflictService(llm, db, relationalDB)))
+
+			result, err := handler.HandleWithOptions(t.Context(), testFile, &IngestOptions{CheckConsistency: true, CheckOnly: tt.checkOnly})
+			require.NoError(t, err)
+
+			assert.Len(t, result.Issues, 1)
+			assert.Len(t, relationalDB.Conflicts, tt.want)
+		})
+	}
+}
+
+func TestIngestHandler_HandleWithOptions_ReviewThreshold(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	require.NoError(t, os.WriteFile(testFile, []byte("Gandalf is a wizard. He may be a Maia."), 0644))
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Gandalf", Predicate: "is a", Object: "wizard", Confidence: 0.95},
+			{Type: entities.FactTypeCharacter, Subject: "Gandalf", Predicate: "is a", Object: "Maia", Confidence: 0.5},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, db))
+
+	result, err := handler.HandleWithOptions(t.Context(), testFile, &IngestOptions{ReviewThreshold: 0.7})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.FactsCount)
+	assert.Equal(t, 1, result.PendingCount)
+	assert.True(t, result.Facts[1].IsPending())
+}
+
+func TestIngestHandler_HandleReader(t *testing.T) {
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is a", Object: "hobbit"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, db))
+
+	var chunks []services.ChunkProgress
+	result, err := handler.HandleReader(t.Context(), strings.NewReader("Frodo is a hobbit."), "api", &IngestOptions{
+		OnChunk: func(p services.ChunkProgress) { chunks = append(chunks, p) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "api", result.FilePath)
+	assert.Equal(t, 1, result.FactsCount)
+	assert.Equal(t, "api", db.SaveBatchLastFacts[0].SourceFile)
+	require.Len(t, chunks, 1)
+	assert.Len(t, chunks[0].Facts, 1)
+}
+
+// fakeDocuments reads every .pdf file as its pages.
+type fakeDocuments struct {
+	pages []ports.DocumentPage
+}
+
+func (d fakeDocuments) CanRead(name string) bool {
+	return strings.HasSuffix(name, ".pdf")
+}
+
+func (d fakeDocuments) Read(io.ReaderAt, int64) ([]ports.DocumentPage, error) {
+	return d.pages, nil
+}
+
+func TestIngestHandler_SourceGuards(t *testing.T) {
+	tmpDir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+
+	t.Run("UTF-16 is converted", func(t *testing.T) {
+		llm := &mocks.LLMClient{}
+		handler := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{}, &mocks.VectorDB{}))
+
+		// "Éowyn rides." in UTF-16LE with a byte order mark.
+		data := []byte{0xFF, 0xFE}
+		for _, r := range "Éowyn rides." {
+			data = append(data, byte(r), byte(r>>8))
+		}
+		_, err := handler.Handle(t.Context(), write("utf16.txt", data))
+		require.NoError(t, err)
+		assert.Equal(t, "Éowyn rides.", llm.ExtractFactsLastText)
+	})
+
+	t.Run("binary files are rejected", func(t *testing.T) {
+		llm := &mocks.LLMClient{}
+		handler := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{}, &mocks.VectorDB{}))
+
+		_, err := handler.Handle(t.Context(), write("book.pdf", []byte("%PDF-1.7\n...")))
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "a PDF document")
+		assert.Zero(t, llm.ExtractFactsCallCount)
+	})
+
+	t.Run("documents are read as their pages", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Éowyn", Predicate: "rides", Object: "to war"},
+		}}
+		db := &mocks.VectorDB{}
+		handler := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{}, db))
+		path := write("notes.pdf", []byte("%PDF-1.7\n..."))
+
+		docs := fakeDocuments{pages: []ports.DocumentPage{{Number: 3, Text: "Éowyn rides."}}}
+		_, err := handler.HandleWithOptions(t.Context(), path, &IngestOptions{Documents: docs})
+		require.NoError(t, err)
+		assert.Equal(t, "Éowyn rides.", llm.ExtractFactsLastText)
+		require.Len(t, db.SaveBatchLastFacts, 1)
+		assert.Equal(t, 3, db.SaveBatchLastFacts[0].SourcePage)
+	})
+
+	t.Run("text files have no pages", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Éowyn", Predicate: "rides", Object: "to war"},
+		}}
+		db := &mocks.VectorDB{}
+		handler := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{}, db))
+
+		_, err := handler.Handle(t.Context(), write("notes.txt", []byte("[[lore:page=3]]\n\nÉowyn rides.")))
+		require.NoError(t, err)
+		assert.Equal(t, "[[lore:page=3]]\n\nÉowyn rides.", llm.ExtractFactsLastText, "text is passed as written")
+		require.Len(t, db.SaveBatchLastFacts, 1)
+		assert.Zero(t, db.SaveBatchLastFacts[0].SourcePage)
+	})
+
+	t.Run("files over the limit are rejected", func(t *testing.T) {
+		llm := &mocks.LLMClient{}
+		handler := NewIngestHandler(newTestExtractionService(llm, &mocks.Embedder{}, &mocks.VectorDB{}))
+		path := write("big.txt", []byte(strings.Repeat("Frodo walks. ", 200)))
+
+		_, err := handler.HandleWithOptions(t.Context(), path, &IngestOptions{MaxFileSize: 1024})
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "larger than the 1.0 KiB limit")
+		assert.Zero(t, llm.ExtractFactsCallCount)
+
+		_, err = handler.HandleReader(t.Context(), strings.NewReader(strings.Repeat("x\n\n", 1000)), "api", &IngestOptions{MaxFileSize: 1024})
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "larger than 1.0 KiB")
+	})
+}
+
 func TestIngestHandler_Handle_FileNotFound(t *testing.T) {
 	llm := &mocks.LLMClient{}
 	emb := &mocks.Embedder{}
@@ -230,3 +455,107 @@ func TestIsGlobPattern(t *testing.T) {
 		})
 	}
 }
+
+func TestIngestHandler_HandleWithOptions_Style(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "test.txt")
+	require.NoError(t, os.WriteFile(testFile, []byte("The Dragonlord rules the north."), 0644))
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Dragonlord", Predicate: "rules", Object: "the north"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	style := services.NewStyleService(mocks.NewRelationalDB())
+	_, err := style.Add(t.Context(), "Dragon Lord", "chapter2")
+	require.NoError(t, err)
+
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, &mocks.VectorDB{}), WithStyle(style))
+	result, err := handler.HandleWithOptions(t.Context(), testFile, &IngestOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, result.StyleIssues, 1)
+	assert.Equal(t, "'Dragonlord' previously spelled 'Dragon Lord' in chapter2", result.StyleIssues[0].Suggestion())
+}
+
+func TestIngestHandler_RecordsSource(t *testing.T) {
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is a", Object: "hobbit"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	relationalDB := mocks.NewRelationalDB()
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, &mocks.VectorDB{}), WithSources(services.NewSourceService(relationalDB, &mocks.VectorDB{})))
+
+	text := "Frodo is a hobbit.\n\nHe lives in the Shire."
+	_, err := handler.HandleReader(t.Context(), strings.NewReader(text), "ch1.md", &IngestOptions{})
+	require.NoError(t, err)
+	require.Len(t, relationalDB.SourceStats, 1)
+	assert.Equal(t, "ch1.md", relationalDB.SourceStats[0].Source)
+	assert.Equal(t, 9, relationalDB.SourceStats[0].Words)
+	assert.Equal(t, 1, relationalDB.SourceStats[0].Facts)
+
+	_, err = handler.HandleReader(t.Context(), strings.NewReader(text), "ch2.md", &IngestOptions{CheckOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, relationalDB.SourceStats, 1, "checks without saving are not recorded")
+}
+
+func TestIngestHandler_ArchivedSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	draft := filepath.Join(tmpDir, "draft.txt")
+	require.NoError(t, os.WriteFile(draft, []byte("Frodo is a hobbit."), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "ch1.txt"), []byte("Frodo is a hobbit."), 0644))
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is a", Object: "hobbit"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	relationalDB := mocks.NewRelationalDB()
+	relationalDB.Archived = []entities.ArchivedSource{{Source: draft}}
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, &mocks.VectorDB{}), WithSources(services.NewSourceService(relationalDB, &mocks.VectorDB{})))
+
+	_, err := handler.HandleWithOptions(t.Context(), draft, &IngestOptions{})
+	assert.ErrorIs(t, err, entities.ErrConflict)
+
+	result, err := handler.HandleDirectoryWithOptions(t.Context(), tmpDir, "*.txt", false, nil, &IngestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TotalFiles)
+	assert.Equal(t, []string{draft}, result.Archived)
+
+	result, err = handler.HandleDirectoryWithOptions(t.Context(), tmpDir, "*.txt", false, nil, &IngestOptions{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalFiles)
+	assert.Empty(t, result.Archived)
+}
+
+func TestIngestHandler_QuarantineAndRetry(t *testing.T) {
+	llm := &mocks.LLMClient{ExtractErr: errors.New("parsing response: invalid character")}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	relationalDB := mocks.NewRelationalDB()
+	handler := NewIngestHandler(newTestExtractionService(llm, emb, &mocks.VectorDB{}), WithSources(services.NewSourceService(relationalDB, &mocks.VectorDB{})))
+
+	result, err := handler.HandleReader(t.Context(), strings.NewReader("Frodo is a hobbit."), "ch1.md", &IngestOptions{})
+	require.NoError(t, err, "a failed chunk does not abort the ingest")
+	assert.Equal(t, 1, result.Quarantined)
+	require.Len(t, relationalDB.FailedChunks, 1)
+
+	retried, err := handler.HandleRetryFailed(t.Context(), "", &IngestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried.Retried)
+	require.Len(t, retried.Failed, 1)
+	assert.Equal(t, 2, retried.Failed[0].Attempts)
+
+	llm.ExtractErr = nil
+	llm.Facts = []entities.Fact{{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is a", Object: "hobbit"}}
+	retried, err = handler.HandleRetryFailed(t.Context(), "ch1.md", &IngestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried.Retried)
+	assert.Empty(t, retried.Failed)
+	assert.Equal(t, 1, retried.FactsCount)
+	assert.Empty(t, relationalDB.FailedChunks)
+	assert.Equal(t, 1, relationalDB.SourceStats[0].Facts)
+}
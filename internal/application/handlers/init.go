@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
@@ -29,16 +30,16 @@ type InitResult struct {
 }
 
 // Handle initializes the lore database.
-func (h *InitHandler) Handle(ctx context.Context, basePath string) (*InitResult, error) {
-	if config.Exists(basePath) {
-		return nil, fmt.Errorf("lore already initialized in %s", basePath)
+func (h *InitHandler) Handle(ctx context.Context, configDir string) (*InitResult, error) {
+	if config.Exists(configDir) {
+		return nil, entities.Errorf(entities.ErrConflict, "lore already initialized in %s", configDir)
 	}
 
-	if err := config.WriteDefault(basePath); err != nil {
+	if err := config.WriteDefault(configDir); err != nil {
 		return nil, fmt.Errorf("writing default config: %w", err)
 	}
 
-	cfg, err := config.Load(basePath)
+	cfg, err := config.Load(configDir)
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
@@ -48,7 +49,7 @@ func (h *InitHandler) Handle(ctx context.Context, basePath string) (*InitResult,
 	}
 
 	return &InitResult{
-		ConfigPath:     config.ConfigFilePath(basePath),
+		ConfigPath:     config.ConfigFilePath(configDir),
 		CollectionName: cfg.Qdrant.Collection,
 	}, nil
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/services"
@@ -20,6 +21,17 @@ func NewQueryHandler(queryService *services.QueryService) *QueryHandler {
 	}
 }
 
+// QueryOptions configures a query.
+type QueryOptions struct {
+	Type  entities.FactType   // Filter by fact type (empty = all)
+	Limit int                 // Maximum results
+	Mode  services.SearchMode // Embedding to match: context, text, or fused
+	AsOf  time.Time           // Answer as of this time (zero = now)
+	POV   *entities.Entity    // Only facts this entity knows (nil = all)
+
+	IncludeClaims bool // Also return claims, not only canon facts
+}
+
 // QueryResult contains the result of a query.
 type QueryResult struct {
 	Query string
@@ -51,3 +63,44 @@ func (h *QueryHandler) HandleByType(ctx context.Context, query string, factType
 		Facts: facts,
 	}, nil
 }
+
+// HandleWithOptions searches for facts using the given type filter and search mode.
+func (h *QueryHandler) HandleWithOptions(ctx context.Context, query string, opts QueryOptions) (*QueryResult, error) {
+	facts, err := h.queryService.SearchWithOptions(ctx, query, services.SearchOptions{
+		Type:  opts.Type,
+		Limit: opts.Limit,
+		Mode:  opts.Mode,
+		AsOf:  opts.AsOf,
+		POV:   opts.POV,
+
+		IncludeClaims: opts.IncludeClaims,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching facts: %w", err)
+	}
+
+	return &QueryResult{
+		Query: query,
+		Facts: facts,
+	}, nil
+}
+
+// SubjectResult contains the facts found about a subject.
+type SubjectResult struct {
+	Subject string
+	Matches []services.SubjectMatch
+}
+
+// HandleFindSubject finds facts about a subject, from the facts linked to
+// the world's entity by that name down to semantic search.
+func (h *QueryHandler) HandleFindSubject(ctx context.Context, worldID, subject string, limit int) (*SubjectResult, error) {
+	matches, err := h.queryService.FindBySubject(ctx, worldID, subject, limit)
+	if err != nil {
+		return nil, fmt.Errorf("finding facts by subject: %w", err)
+	}
+
+	return &SubjectResult{
+		Subject: subject,
+		Matches: matches,
+	}, nil
+}
@@ -24,7 +24,7 @@ func TestQueryHandler_Handle(t *testing.T) {
 
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: facts}
-	queryService := services.NewQueryService(emb, db)
+	queryService := services.NewQueryService(emb, db, &mocks.RelationalDB{})
 	handler := NewQueryHandler(queryService)
 
 	result, err := handler.Handle(t.Context(), "Who is brave?", 10)
@@ -37,7 +37,7 @@ func TestQueryHandler_Handle(t *testing.T) {
 func TestQueryHandler_Handle_NoResults(t *testing.T) {
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: []entities.Fact{}}
-	queryService := services.NewQueryService(emb, db)
+	queryService := services.NewQueryService(emb, db, &mocks.RelationalDB{})
 	handler := NewQueryHandler(queryService)
 
 	result, err := handler.Handle(t.Context(), "Unknown query", 10)
@@ -65,7 +65,7 @@ func TestQueryHandler_HandleByType(t *testing.T) {
 
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: facts}
-	queryService := services.NewQueryService(emb, db)
+	queryService := services.NewQueryService(emb, db, &mocks.RelationalDB{})
 	handler := NewQueryHandler(queryService)
 
 	result, err := handler.HandleByType(t.Context(), "characters", entities.FactTypeCharacter, 10)
@@ -74,10 +74,28 @@ func TestQueryHandler_HandleByType(t *testing.T) {
 	assert.Equal(t, entities.FactTypeCharacter, result.Facts[0].Type)
 }
 
+func TestQueryHandler_HandleFindSubject(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "1", Subject: "Frodo", Predicate: "has_trait", Object: "brave"},
+		{ID: "2", Subject: "Sam", Predicate: "has_trait", Object: "loyal"},
+	}
+
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: facts}
+	handler := NewQueryHandler(services.NewQueryService(emb, db, &mocks.RelationalDB{}))
+
+	result, err := handler.HandleFindSubject(t.Context(), "w", "Frodo", 10)
+	require.NoError(t, err)
+	assert.Equal(t, "Frodo", result.Subject)
+	require.Len(t, result.Matches, 2)
+	assert.Equal(t, services.MatchExact, result.Matches[0].Tier)
+	assert.Equal(t, services.MatchSemantic, result.Matches[1].Tier)
+}
+
 func TestNewQueryHandler(t *testing.T) {
 	emb := &mocks.Embedder{}
 	db := &mocks.VectorDB{}
-	queryService := services.NewQueryService(emb, db)
+	queryService := services.NewQueryService(emb, db, &mocks.RelationalDB{})
 
 	handler := NewQueryHandler(queryService)
 	assert.NotNil(t, handler)
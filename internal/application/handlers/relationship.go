@@ -32,8 +32,11 @@ func NewRelationshipHandler(service *services.RelationshipService, relationalDB
 
 // ListOptions configures relationship listing behavior.
 type ListOptions struct {
-	Type  string // Filter by relationship type (empty = all)
-	Depth int    // Graph traversal depth (default 1)
+	Type   string                 // Filter by relationship type (empty = all)
+	Depth  int                    // Graph traversal depth (default 1)
+	Sort   ports.RelationshipSort // Sort order (empty = newest first)
+	Limit  int                    // Maximum relationships to return (0 = no limit)
+	Offset int                    // Number of relationships to skip
 }
 
 // RelationshipInfo contains a relationship with entity details.
@@ -47,6 +50,7 @@ type RelationshipInfo struct {
 type ListResult struct {
 	Relationships   []RelationshipInfo `json:"relationships"`
 	RelatedEntities []string           `json:"related_entities,omitempty"`
+	Total           int                `json:"total"` // Matching relationships before pagination
 }
 
 // HandleCreate creates a new relationship between two entities.
@@ -83,21 +87,28 @@ func (h *RelationshipHandler) HandleList(ctx context.Context, worldID, entityNam
 		return &ListResult{Relationships: []RelationshipInfo{}}, nil
 	}
 
-	// Get relationships
-	relationships, err := h.service.List(ctx, entity.ID)
+	// Get the requested page of relationships
+	relationships, err := h.service.ListWithOptions(ctx, entity.ID, ports.RelationshipListOptions{
+		Type:   opts.Type,
+		Sort:   opts.Sort,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("listing relationships: %w", err)
 	}
 
+	total, err := h.service.CountByEntity(ctx, entity.ID, opts.Type)
+	if err != nil {
+		return nil, fmt.Errorf("counting relationships: %w", err)
+	}
+
 	// Get related entities if depth > 1
 	relatedEntityNames, err := h.fetchRelatedEntityNames(ctx, entity.ID, opts.Depth)
 	if err != nil {
 		return nil, err
 	}
 
-	// Filter by type if specified
-	relationships = filterByType(relationships, opts.Type)
-
 	// Build entity lookup map for relationship info
 	entityMap, err := h.buildEntityMap(ctx, relationships)
 	if err != nil {
@@ -105,7 +116,9 @@ func (h *RelationshipHandler) HandleList(ctx context.Context, worldID, entityNam
 	}
 
 	// Build result with entity details
-	return h.buildListResult(relationships, entityMap, relatedEntityNames), nil
+	result := h.buildListResult(relationships, entityMap, relatedEntityNames)
+	result.Total = total
+	return result, nil
 }
 
 // fetchRelatedEntityNames fetches names of entities connected at the given depth.
@@ -132,21 +145,6 @@ func (h *RelationshipHandler) fetchRelatedEntityNames(ctx context.Context, entit
 	return names, nil
 }
 
-// filterByType filters relationships by type, returning all if typeFilter is empty.
-func filterByType(relationships []entities.Relationship, typeFilter string) []entities.Relationship {
-	if typeFilter == "" {
-		return relationships
-	}
-
-	filtered := make([]entities.Relationship, 0, len(relationships))
-	for i := range relationships {
-		if string(relationships[i].Type) == typeFilter {
-			filtered = append(filtered, relationships[i])
-		}
-	}
-	return filtered
-}
-
 // buildEntityMap fetches all entities referenced in relationships and builds a lookup map.
 func (h *RelationshipHandler) buildEntityMap(ctx context.Context, relationships []entities.Relationship) (map[string]*entities.Entity, error) {
 	// Collect unique entity IDs
@@ -199,6 +197,12 @@ func (h *RelationshipHandler) HandleFindBetween(ctx context.Context, sourceEntit
 	return h.service.FindBetween(ctx, sourceEntityID, targetEntityID)
 }
 
+// HandleHistory returns how the relationship between two entities changed
+// over time.
+func (h *RelationshipHandler) HandleHistory(ctx context.Context, worldID, sourceName, targetName string) (*services.RelationshipHistory, error) {
+	return h.service.History(ctx, worldID, sourceName, targetName)
+}
+
 // HandleCount returns the total number of relationships.
 func (h *RelationshipHandler) HandleCount(ctx context.Context) (int, error) {
 	return h.service.Count(ctx)
@@ -223,5 +227,5 @@ func parseRelationType(s string) (entities.RelationType, error) {
 	if rt, ok := relationTypeMap[s]; ok {
 		return rt, nil
 	}
-	return "", fmt.Errorf("invalid relationship type: %s (valid: parent, child, sibling, spouse, ally, enemy, located_in, owns, member_of, created)", s)
+	return "", entities.Errorf(entities.ErrValidation, "invalid relationship type: %s (valid: parent, child, sibling, spouse, ally, enemy, located_in, owns, member_of, created)", s)
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/domain/services"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -68,6 +69,9 @@ func (m *relHandlerVectorDB) Search(_ context.Context, _ []float32, _ int) ([]en
 func (m *relHandlerVectorDB) SearchByType(_ context.Context, _ []float32, _ entities.FactType, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
+func (m *relHandlerVectorDB) SearchBySubject(_ context.Context, _ []float32, _ string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
 
 func (m *relHandlerVectorDB) Delete(_ context.Context, id string) error {
 	delete(m.facts, id)
@@ -77,15 +81,43 @@ func (m *relHandlerVectorDB) Delete(_ context.Context, id string) error {
 func (m *relHandlerVectorDB) List(_ context.Context, _ int, _ uint64) ([]entities.Fact, error) {
 	return nil, nil
 }
+func (m *relHandlerVectorDB) ListPage(_ context.Context, _ ports.FactPageOptions) ([]entities.Fact, string, error) {
+	return nil, "", nil
+}
 func (m *relHandlerVectorDB) ListByType(_ context.Context, _ entities.FactType, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
+func (m *relHandlerVectorDB) SearchVector(_ context.Context, _ ports.VectorName, _ []float32, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) SearchKeywords(_ context.Context, _ string, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) ListFiltered(_ context.Context, _ ports.FactListOptions) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) ListByEntities(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) ListPending(_ context.Context, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) ListBySubject(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relHandlerVectorDB) ListByClaimKeys(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
 func (m *relHandlerVectorDB) ListBySource(_ context.Context, _ string, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
-func (m *relHandlerVectorDB) DeleteBySource(_ context.Context, _ string) error { return nil }
-func (m *relHandlerVectorDB) DeleteAll(_ context.Context) error                { return nil }
-func (m *relHandlerVectorDB) Count(_ context.Context) (uint64, error)          { return 0, nil }
+func (m *relHandlerVectorDB) CountBySubject(_ context.Context, _ []string) (uint64, error) {
+	return 0, nil
+}
+func (m *relHandlerVectorDB) DeleteBySource(_ context.Context, _ string) error            { return nil }
+func (m *relHandlerVectorDB) SetSourceArchived(_ context.Context, _ string, _ bool) error { return nil }
+func (m *relHandlerVectorDB) DeleteAll(_ context.Context) error                           { return nil }
+func (m *relHandlerVectorDB) Count(_ context.Context) (uint64, error)                     { return 0, nil }
 
 // relHandlerRelationalDB is a test mock for RelationalDB.
 type relHandlerRelationalDB struct {
@@ -160,6 +192,10 @@ func (m *relHandlerRelationalDB) SearchEntities(_ context.Context, _, _ string,
 	return nil, nil
 }
 
+func (m *relHandlerRelationalDB) RenameEntity(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *relHandlerRelationalDB) DeleteEntity(_ context.Context, entityID string) error {
 	delete(m.entities, entityID)
 	return nil
@@ -190,6 +226,58 @@ func (m *relHandlerRelationalDB) FindRelationshipsByEntity(_ context.Context, en
 	return result, nil
 }
 
+func (m *relHandlerRelationalDB) ListRelationshipsByEntity(_ context.Context, entityID string, opts ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	var result []entities.Relationship
+	for _, rel := range m.relationships {
+		if opts.Type != "" && string(rel.Type) != opts.Type {
+			continue
+		}
+		if rel.SourceEntityID == entityID || (rel.TargetEntityID == entityID && rel.Bidirectional) {
+			result = append(result, *rel)
+		}
+	}
+	otherName := func(rel entities.Relationship) string {
+		otherID := rel.SourceEntityID
+		if rel.SourceEntityID == entityID {
+			otherID = rel.TargetEntityID
+		}
+		if e, ok := m.entities[otherID]; ok {
+			return e.NormalizedName
+		}
+		return ""
+	}
+	sort.Slice(result, func(i, j int) bool {
+		switch opts.Sort {
+		case ports.RelationshipSortType:
+			if result[i].Type != result[j].Type {
+				return result[i].Type < result[j].Type
+			}
+		case ports.RelationshipSortTarget:
+			if ni, nj := otherName(result[i]), otherName(result[j]); ni != nj {
+				return ni < nj
+			}
+		}
+		return result[i].ID < result[j].ID
+	})
+	if opts.Offset >= len(result) {
+		return []entities.Relationship{}, nil
+	}
+	result = result[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(result) {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+func (m *relHandlerRelationalDB) CountRelationshipsByEntity(ctx context.Context, entityID string, relType string) (int, error) {
+	rels, err := m.ListRelationshipsByEntity(ctx, entityID, ports.RelationshipListOptions{Type: relType})
+	return len(rels), err
+}
+
+func (m *relHandlerRelationalDB) CountRelationshipsPerEntity(_ context.Context, _ string) (map[string]int, error) {
+	return nil, nil
+}
+
 func (m *relHandlerRelationalDB) FindRelationshipsByType(_ context.Context, relType string) ([]entities.Relationship, error) {
 	var result []entities.Relationship
 	for _, rel := range m.relationships {
@@ -209,6 +297,10 @@ func (m *relHandlerRelationalDB) DeleteRelationshipsByEntity(_ context.Context,
 	return nil
 }
 
+func (m *relHandlerRelationalDB) MergeEntities(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *relHandlerRelationalDB) FindRelationshipBetween(_ context.Context, sourceID, targetID string) (*entities.Relationship, error) {
 	for _, rel := range m.relationships {
 		if rel.SourceEntityID == sourceID && rel.TargetEntityID == targetID {
@@ -265,6 +357,9 @@ func (m *relHandlerRelationalDB) FindLatestVersion(_ context.Context, _ string)
 func (m *relHandlerRelationalDB) CountVersions(_ context.Context, _ string) (int, error) {
 	return 0, nil
 }
+func (m *relHandlerRelationalDB) ListVersionsAsOf(_ context.Context, _ time.Time) ([]entities.FactVersion, error) {
+	return nil, nil
+}
 func (m *relHandlerRelationalDB) LogAction(_ context.Context, _ string, _ string, _ map[string]any) error {
 	return nil
 }
@@ -274,6 +369,121 @@ func (m *relHandlerRelationalDB) FindAuditLog(_ context.Context, _ string) ([]en
 func (m *relHandlerRelationalDB) FindAuditLogByAction(_ context.Context, _ string, _ int) ([]entities.AuditEntry, error) {
 	return nil, nil
 }
+func (m *relHandlerRelationalDB) SaveConflict(_ context.Context, _ *entities.Conflict) (bool, error) {
+	return true, nil
+}
+func (m *relHandlerRelationalDB) ListConflicts(_ context.Context, _ ports.ConflictListOptions) ([]entities.Conflict, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) ResolveConflict(_ context.Context, _ string, _ string, _ time.Time) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) CountOpenConflicts(_ context.Context, _ []string) (map[string]int, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveTranslation(_ context.Context, _ *entities.Translation) (bool, error) {
+	return true, nil
+}
+func (m *relHandlerRelationalDB) ListTranslations(_ context.Context, _ []string) ([]entities.Translation, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveHealthSample(_ context.Context, _ *entities.HealthSample) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) ListHealthSamples(_ context.Context, _ int) ([]entities.HealthSample, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) FindConsistencyVerdicts(_ context.Context, _ []string, _ time.Time) (map[string]entities.ConsistencyVerdict, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveConsistencyVerdicts(_ context.Context, _ []entities.ConsistencyVerdict) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) PruneConsistencyVerdicts(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+func (m *relHandlerRelationalDB) SaveStyleTerm(_ context.Context, _ *entities.StyleTerm) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) ListStyleTerms(_ context.Context) ([]entities.StyleTerm, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveSourceStats(_ context.Context, _ *entities.SourceStats) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) ListSourceStats(_ context.Context) ([]entities.SourceStats, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveArchivedSource(_ context.Context, _ *entities.ArchivedSource) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) DeleteArchivedSource(_ context.Context, _ string) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) ListArchivedSources(_ context.Context) ([]entities.ArchivedSource, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) SaveFailedChunk(_ context.Context, _ *entities.FailedChunk) error {
+	return nil
+}
+func (m *relHandlerRelationalDB) ListFailedChunks(_ context.Context) ([]entities.FailedChunk, error) {
+	return nil, nil
+}
+func (m *relHandlerRelationalDB) DeleteFailedChunk(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) SaveUsage(_ context.Context, _ *entities.UsageRecord) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) SumUsage(_ context.Context, _ time.Time) ([]entities.UsageTotals, error) {
+	return nil, nil
+}
+
+func (m *relHandlerRelationalDB) SaveFactSubjects(_ context.Context, _ []entities.FactSubject) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) DeleteFactSubjects(_ context.Context, _ ports.FactSubjectFilter) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) ListFactIDsByEntities(_ context.Context, _ []string, _ int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *relHandlerRelationalDB) SaveExtractionStat(_ context.Context, _ *entities.ExtractionStat) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) SumExtractionStats(_ context.Context) ([]entities.ModelStats, error) {
+	return nil, nil
+}
+
+func (m *relHandlerRelationalDB) FindIdempotencyRecord(_ context.Context, _ string) (*entities.IdempotencyRecord, error) {
+	return nil, nil
+}
+
+func (m *relHandlerRelationalDB) SaveIdempotencyRecord(_ context.Context, _ *entities.IdempotencyRecord) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) ClaimIdempotencyKey(_ context.Context, _ *entities.IdempotencyRecord, _ time.Time) (bool, error) {
+	return true, nil
+}
+
+func (m *relHandlerRelationalDB) ReleaseIdempotencyKey(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) RecordChanges(_ context.Context, _ []entities.Change) error {
+	return nil
+}
+
+func (m *relHandlerRelationalDB) ListChanges(_ context.Context, _ int64, _ int) ([]entities.Change, error) {
+	return nil, nil
+}
 
 // relHandlerEmbedder is a test mock for Embedder.
 type relHandlerEmbedder struct{}
@@ -408,6 +618,27 @@ func TestRelationshipHandler_HandleList(t *testing.T) {
 		assert.Equal(t, entities.RelationAlly, result.Relationships[0].Relationship.Type)
 	})
 
+	t.Run("sorts and paginates", func(t *testing.T) {
+		handler, _, _ := setupRelationshipHandlerTest()
+		ctx := context.Background()
+
+		for _, target := range []string{"Zed", "Bob", "Mia"} {
+			_, err := handler.HandleCreate(ctx, worldID, "Alice", "ally", target, true)
+			require.NoError(t, err)
+		}
+
+		result, err := handler.HandleList(ctx, worldID, "Alice", ListOptions{
+			Sort:   ports.RelationshipSortTarget,
+			Limit:  2,
+			Offset: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		require.Len(t, result.Relationships, 2)
+		assert.Equal(t, "Mia", result.Relationships[0].TargetEntity.Name)
+		assert.Equal(t, "Zed", result.Relationships[1].TargetEntity.Name)
+	})
+
 	t.Run("empty results", func(t *testing.T) {
 		handler, _, _ := setupRelationshipHandlerTest()
 		ctx := context.Background()
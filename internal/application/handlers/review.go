@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// ReviewHandler handles the queue of facts held for review.
+type ReviewHandler struct {
+	reviewService *services.ReviewService
+}
+
+// NewReviewHandler creates a new review handler.
+func NewReviewHandler(reviewService *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// HandleList returns facts awaiting review.
+func (h *ReviewHandler) HandleList(ctx context.Context, limit int) ([]entities.Fact, error) {
+	return h.reviewService.Pending(ctx, limit)
+}
+
+// HandleAccept makes pending facts searchable, stopping at the first failure.
+// It returns the facts accepted so far.
+func (h *ReviewHandler) HandleAccept(ctx context.Context, ids []string) ([]entities.Fact, error) {
+	accepted := make([]entities.Fact, 0, len(ids))
+	for _, id := range ids {
+		fact, err := h.reviewService.Accept(ctx, id)
+		if err != nil {
+			return accepted, fmt.Errorf("accepting %s: %w", id, err)
+		}
+		accepted = append(accepted, *fact)
+	}
+	return accepted, nil
+}
+
+// HandleEdit corrects a pending fact and accepts it.
+func (h *ReviewHandler) HandleEdit(ctx context.Context, id string, edit services.FactEdit) (*entities.Fact, error) {
+	fact, err := h.reviewService.Edit(ctx, id, edit)
+	if err != nil {
+		return nil, fmt.Errorf("editing %s: %w", id, err)
+	}
+	return fact, nil
+}
+
+// HandleReject deletes pending facts, stopping at the first failure.
+// It returns the IDs rejected so far.
+func (h *ReviewHandler) HandleReject(ctx context.Context, ids []string) ([]string, error) {
+	rejected := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if err := h.reviewService.Reject(ctx, id); err != nil {
+			return rejected, fmt.Errorf("rejecting %s: %w", id, err)
+		}
+		rejected = append(rejected, id)
+	}
+	return rejected, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newTestReviewHandler(facts ...entities.Fact) (*ReviewHandler, *mocks.VectorDB) {
+	vectorDB := &mocks.VectorDB{Facts: facts}
+	factService := services.NewFactService(&mocks.Embedder{EmbeddingResult: []float32{1}}, vectorDB, mocks.NewRelationalDB(), nil)
+	svc := services.NewReviewService(vectorDB, factService)
+	return NewReviewHandler(svc), vectorDB
+}
+
+func TestReviewHandler_HandleAccept(t *testing.T) {
+	handler, vectorDB := newTestReviewHandler(
+		entities.Fact{ID: "a", Status: entities.FactStatusPending},
+		entities.Fact{ID: "b"},
+		entities.Fact{ID: "c", Status: entities.FactStatusPending},
+	)
+
+	accepted, err := handler.HandleAccept(t.Context(), []string{"a", "b", "c"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "accepting b")
+	require.Len(t, accepted, 1, "stops at the first fact that is not pending")
+	assert.Equal(t, "a", accepted[0].ID)
+	assert.Len(t, vectorDB.SavedFacts, 1)
+}
+
+func TestReviewHandler_HandleReject(t *testing.T) {
+	handler, vectorDB := newTestReviewHandler(
+		entities.Fact{ID: "a", Status: entities.FactStatusPending},
+		entities.Fact{ID: "b", Status: entities.FactStatusPending},
+	)
+
+	rejected, err := handler.HandleReject(t.Context(), []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, rejected)
+	assert.Equal(t, []string{"a", "b"}, vectorDB.DeletedIDs)
+}
+
+func TestReviewHandler_HandleEdit(t *testing.T) {
+	handler, _ := newTestReviewHandler(entities.Fact{
+		ID: "a", Subject: "Frodo", Predicate: "owns", Object: "Sting", Status: entities.FactStatusPending,
+	})
+
+	fact, err := handler.HandleEdit(t.Context(), "a", services.FactEdit{Subject: "Bilbo"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bilbo", fact.Subject)
+	assert.Equal(t, "Sting", fact.Object)
+	assert.False(t, fact.IsPending())
+}
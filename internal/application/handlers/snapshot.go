@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// SnapshotHandler handles world snapshot operations.
+type SnapshotHandler struct {
+	snapshotService *services.SnapshotService
+}
+
+// NewSnapshotHandler creates a new snapshot handler.
+func NewSnapshotHandler(snapshotService *services.SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// SnapshotResult contains the result of creating a snapshot.
+type SnapshotResult struct {
+	Snapshot *entities.Snapshot `json:"snapshot"`
+	Pruned   []string           `json:"pruned"` // Snapshots removed by the retention policy
+}
+
+// HandleCreate creates a snapshot and then prunes all but the newest keep
+// snapshots. A keep of zero disables pruning.
+func (h *SnapshotHandler) HandleCreate(ctx context.Context, keep int) (*SnapshotResult, error) {
+	snapshot, err := h.snapshotService.Create(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating snapshot: %w", err)
+	}
+
+	result := &SnapshotResult{Snapshot: snapshot, Pruned: []string{}}
+	if keep == 0 {
+		return result, nil
+	}
+
+	result.Pruned, err = h.snapshotService.Prune(ctx, keep)
+	if err != nil {
+		return result, fmt.Errorf("pruning snapshots: %w", err)
+	}
+
+	return result, nil
+}
+
+// HandleList returns the world's snapshots, newest first.
+func (h *SnapshotHandler) HandleList(ctx context.Context) ([]entities.Snapshot, error) {
+	return h.snapshotService.List(ctx)
+}
+
+// HandleVerify checks that a snapshot could be restored, without touching
+// the live world.
+func (h *SnapshotHandler) HandleVerify(ctx context.Context, snapshot *entities.Snapshot) (*services.BackupVerification, error) {
+	return h.snapshotService.Verify(ctx, snapshot)
+}
+
+// HandlePrune deletes all but the newest keep snapshots.
+func (h *SnapshotHandler) HandlePrune(ctx context.Context, keep int) ([]string, error) {
+	pruned, err := h.snapshotService.Prune(ctx, keep)
+	if err != nil {
+		return pruned, fmt.Errorf("pruning snapshots: %w", err)
+	}
+	return pruned, nil
+}
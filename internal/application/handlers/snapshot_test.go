@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+func newTestSnapshotHandler(storage *mocks.SnapshotStorage) *SnapshotHandler {
+	svc := services.NewSnapshotService("middle-earth", storage, storage, storage)
+	return NewSnapshotHandler(svc)
+}
+
+func TestSnapshotHandler_HandleCreate(t *testing.T) {
+	storage := &mocks.SnapshotStorage{
+		Snapshots: map[string]entities.Snapshot{
+			"old": {Name: "old", VectorSnapshot: "vector-old", CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		VectorNames: map[string]bool{"vector-old": true},
+	}
+	handler := newTestSnapshotHandler(storage)
+
+	result, err := handler.HandleCreate(t.Context(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "middle-earth", result.Snapshot.World)
+	assert.Equal(t, []string{"old"}, result.Pruned)
+	assert.Len(t, storage.Snapshots, 1)
+	assert.NotContains(t, storage.VectorNames, "vector-old")
+
+	list, err := handler.HandleList(t.Context())
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, result.Snapshot.Name, list[0].Name)
+}
+
+func TestSnapshotHandler_HandleCreate_NoPrune(t *testing.T) {
+	storage := &mocks.SnapshotStorage{
+		Snapshots: map[string]entities.Snapshot{
+			"old": {Name: "old", CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	handler := newTestSnapshotHandler(storage)
+
+	result, err := handler.HandleCreate(t.Context(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, result.Pruned)
+	assert.Len(t, storage.Snapshots, 2)
+}
+
+func TestSnapshotHandler_HandleCreate_Error(t *testing.T) {
+	storage := &mocks.SnapshotStorage{BackupErr: errors.New("disk full")}
+	handler := newTestSnapshotHandler(storage)
+
+	_, err := handler.HandleCreate(t.Context(), 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Empty(t, storage.VectorNames)
+}
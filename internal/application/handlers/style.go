@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// StyleHandler handles a world's style sheet of canonical spellings.
+type StyleHandler struct {
+	service *services.StyleService
+}
+
+// NewStyleHandler creates a new StyleHandler.
+func NewStyleHandler(service *services.StyleService) *StyleHandler {
+	return &StyleHandler{
+		service: service,
+	}
+}
+
+// HandleAdd records a term's canonical spelling.
+func (h *StyleHandler) HandleAdd(ctx context.Context, term, source string) (*entities.StyleTerm, error) {
+	return h.service.Add(ctx, term, source)
+}
+
+// HandleList returns the style sheet, ordered by term.
+func (h *StyleHandler) HandleList(ctx context.Context) ([]entities.StyleTerm, error) {
+	return h.service.List(ctx)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// SweepHandler handles scheduled consistency sweeps.
+type SweepHandler struct {
+	sweepService *services.SweepService
+}
+
+// NewSweepHandler creates a new sweep handler.
+func NewSweepHandler(sweepService *services.SweepService) *SweepHandler {
+	return &SweepHandler{
+		sweepService: sweepService,
+	}
+}
+
+// HandleSweep checks the facts added since the last sweep for
+// contradictions and sends a digest of the new ones.
+func (h *SweepHandler) HandleSweep(ctx context.Context, opts *services.SweepOptions) (*services.SweepResult, error) {
+	return h.sweepService.Sweep(ctx, opts)
+}
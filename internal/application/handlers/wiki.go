@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/wiki"
+)
+
+// WikiHandler handles importing pages from wiki platforms.
+type WikiHandler struct {
+	importService *services.ImportService
+	ingestHandler *IngestHandler
+}
+
+// NewWikiHandler creates a new wiki handler. A nil ingest handler imports
+// only the pages' infobox fields.
+func NewWikiHandler(importService *services.ImportService, ingestHandler *IngestHandler) *WikiHandler {
+	return &WikiHandler{
+		importService: importService,
+		ingestHandler: ingestHandler,
+	}
+}
+
+// WikiOptions controls how wiki pages are imported.
+type WikiOptions struct {
+	WorldID     string                    // World the pages' entities belong to
+	DryRun      bool                      // Validate infobox facts without saving, and skip extraction
+	InfoboxOnly bool                      // Import infobox fields without extracting from the text
+	OnConflict  services.ConflictStrategy // How to handle existing infobox facts
+	Rules       []services.ImportRule     // Extra validation rules for infobox facts
+
+	// Ingest controls extraction from the pages' text.
+	Ingest IngestOptions
+}
+
+// WikiPageResult contains the result of importing one wiki page.
+type WikiPageResult struct {
+	Title  string
+	Source string // The page's URL, recorded as its facts' source
+
+	Imported  int // Facts imported from the infobox
+	Skipped   int
+	Merged    int
+	Extracted int // Facts extracted from the text
+	Errors    []services.ImportError
+	Issues    []ports.ConsistencyIssue
+}
+
+// HandlePage imports a page's infobox fields as facts about the page's
+// subject and extracts facts from its text, recording the page's URL as
+// the source of both.
+func (h *WikiHandler) HandlePage(ctx context.Context, page wiki.Page, opts *WikiOptions) (*WikiPageResult, error) {
+	result := &WikiPageResult{Title: page.Title, Source: page.Source()}
+
+	imported, err := h.importService.ImportDocument(ctx, opts.WorldID, page.Document(), services.ImportOptions{
+		DryRun:     opts.DryRun,
+		OnConflict: opts.OnConflict,
+		Rules:      opts.Rules,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("importing infobox of %s: %w", page.Title, err)
+	}
+	result.Imported = imported.Imported
+	result.Skipped = imported.Skipped
+	result.Merged = imported.Merged
+	result.Errors = imported.Errors
+
+	if opts.DryRun || opts.InfoboxOnly || h.ingestHandler == nil || strings.TrimSpace(page.Text) == "" {
+		return result, nil
+	}
+	ingested, err := h.ingestHandler.HandleReader(ctx, strings.NewReader(page.Text), result.Source, &opts.Ingest)
+	if err != nil {
+		return nil, fmt.Errorf("extracting from %s: %w", page.Title, err)
+	}
+	result.Extracted = ingested.FactsCount
+	result.Issues = ingested.Issues
+	return result, nil
+}
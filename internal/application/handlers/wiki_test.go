@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/wiki"
+)
+
+func TestWikiHandler_HandlePage(t *testing.T) {
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "inherited", Object: "Bag End"},
+		},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+
+	importService := services.NewImportService(emb, db, relationalDB, newTestEntityTypeService())
+	ingest := NewIngestHandler(newTestExtractionService(llm, emb, db))
+	handler := NewWikiHandler(importService, ingest)
+
+	page := wiki.Page{
+		Title:  "Frodo",
+		URL:    "https://wiki.example/Frodo",
+		Type:   "Infobox character",
+		Fields: []wiki.Field{{Name: "race", Value: "Hobbit"}},
+		Text:   "Frodo inherited Bag End.",
+	}
+	result, err := handler.HandlePage(t.Context(), page, &WikiOptions{WorldID: "w", OnConflict: services.ConflictOverwrite})
+	require.NoError(t, err)
+
+	assert.Equal(t, page.URL, result.Source)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.Extracted)
+	assert.Empty(t, result.Errors)
+	require.Len(t, db.SaveBatchLastFacts, 1)
+	assert.Equal(t, page.URL, db.SaveBatchLastFacts[0].SourceFile, "extracted facts cite the page")
+
+	entity, err := relationalDB.FindEntityByName(t.Context(), "w", "Frodo")
+	require.NoError(t, err)
+	assert.NotNil(t, entity, "the page's subject becomes an entity")
+}
+
+func TestWikiHandler_HandlePage_InfoboxOnly(t *testing.T) {
+	llm := &mocks.LLMClient{}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{}
+
+	importService := services.NewImportService(emb, db, mocks.NewRelationalDB(), newTestEntityTypeService())
+	handler := NewWikiHandler(importService, NewIngestHandler(newTestExtractionService(llm, emb, db)))
+
+	page := wiki.Page{Title: "Frodo", Fields: []wiki.Field{{Name: "race", Value: "Hobbit"}}, Text: "Frodo inherited Bag End."}
+	for _, opts := range []WikiOptions{{InfoboxOnly: true}, {DryRun: true}} {
+		result, err := handler.HandlePage(t.Context(), page, &opts)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		assert.Zero(t, result.Extracted)
+	}
+	assert.Zero(t, llm.ExtractFactsCallCount, "the text is not extracted")
+}
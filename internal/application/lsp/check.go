@@ -0,0 +1,93 @@
+package lsp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// startCheck checks an open document in the background, cancelling its
+// earlier check if still running, and publishes its diagnostics.
+func (s *Server) startCheck(ctx context.Context, uri string) {
+	s.mu.Lock()
+	doc, ok := s.docs[uri]
+	if !ok || s.opts.Ingest == nil {
+		s.mu.Unlock()
+		return
+	}
+	if doc.cancel != nil {
+		doc.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	doc.cancel = cancel
+	path, text, checked := doc.path, doc.text, doc.checked
+	s.checks.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.checks.Done()
+		defer cancel()
+
+		issues, chunks, err := s.check(ctx, path, text, checked)
+		if ctx.Err() != nil {
+			// A newer check or the document's closing replaced this one
+			return
+		}
+		if err != nil {
+			s.logError("checking %s: %v", path, err)
+			return
+		}
+
+		s.mu.Lock()
+		doc.checked = chunks
+		s.mu.Unlock()
+
+		// The new facts are placed in the text as checked, which later
+		// edits may have changed
+		sourceText := func(source string) (string, bool) {
+			if source == path {
+				return text, true
+			}
+			return s.sourceText(source)
+		}
+		diagnostics := make([]Diagnostic, 0, len(issues))
+		for i := range issues {
+			diagnostics = append(diagnostics, IssueDiagnostic(&issues[i], sourceText))
+		}
+		s.publish(uri, diagnostics)
+	}()
+}
+
+// check checks the text of the document at path chunk by chunk, reusing
+// the issues found in chunks checked before, and returns the issues and
+// the issues of each chunk. Contradictions with the document's own stored
+// facts are left out, since ingesting it would replace them.
+func (s *Server) check(ctx context.Context, path, text string, checked map[string][]ports.ConsistencyIssue) ([]ports.ConsistencyIssue, map[string][]ports.ConsistencyIssue, error) {
+	var (
+		issues []ports.ConsistencyIssue
+		chunks = make(map[string][]ports.ConsistencyIssue)
+	)
+	err := s.opts.Chunker.Chunk(strings.NewReader(text), func(chunk string) error {
+		chunkIssues, ok := checked[chunk]
+		if !ok {
+			result, err := s.opts.Ingest.HandleReader(ctx, strings.NewReader(chunk), path, &s.opts.IngestOptions)
+			if err != nil {
+				return err
+			}
+			chunkIssues = []ports.ConsistencyIssue{}
+			for i := range result.Issues {
+				if result.Issues[i].ExistingFact.SourceFile != path {
+					chunkIssues = append(chunkIssues, result.Issues[i])
+				}
+			}
+		}
+		chunks[chunk] = chunkIssues
+		issues = append(issues, chunkIssues...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return issues, chunks, nil
+}
@@ -0,0 +1,56 @@
+package lsp
+
+import (
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// SourceText returns the text of a fact's source file, or false if it has
+// none that can be read.
+type SourceText func(source string) (string, bool)
+
+// Locate returns the range of its source where a fact is stated. It
+// reports false if the source cannot be read or the fact is not found in
+// it.
+func Locate(text SourceText, fact *entities.Fact) (Range, bool) {
+	source, ok := text(fact.SourceFile)
+	if !ok {
+		return Range{}, false
+	}
+	start, end, ok := services.LocateFact(source, fact)
+	if !ok {
+		return Range{}, false
+	}
+	return Range{Start: PositionAt(source, start), End: PositionAt(source, end)}, true
+}
+
+// IssueDiagnostic returns the diagnostic of a contradiction, at the text
+// the new fact was extracted from and related to where the fact it
+// contradicts is stated. A new fact that cannot be placed is marked at the
+// start of its file.
+func IssueDiagnostic(issue *ports.ConsistencyIssue, text SourceText) Diagnostic {
+	existing := &issue.ExistingFact
+	sources := existing.SourceFile
+	if n := existing.SourceCount(); n > 1 {
+		sources = fmt.Sprintf("%s; %d sources", sources, n)
+	}
+
+	d := Diagnostic{
+		Severity: Severity(issue.Severity),
+		Code:     "contradiction",
+		Source:   "lore",
+		Message: fmt.Sprintf("%s\nContradicts: %s %s %s (%s)", issue.Description,
+			existing.Subject, existing.Predicate, existing.Object, sources),
+	}
+	d.Range, _ = Locate(text, &issue.NewFact)
+	if r, ok := Locate(text, existing); ok {
+		d.RelatedInformation = []RelatedInformation{{
+			Location: Location{URI: FileURI(existing.SourceFile), Range: r},
+			Message:  "Established here",
+		}}
+	}
+	return d
+}
@@ -0,0 +1,164 @@
+package lsp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// maxNameWords is the most words of an entity name looked up around the
+// cursor.
+const maxNameWords = 4
+
+// hoverResult is the result of a textDocument/hover request.
+type hoverResult struct {
+	Contents markupContent `json:"contents"`
+	Range    Range         `json:"range"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// word is the byte offsets of a word in a line.
+type word struct {
+	start, end int
+}
+
+// hover shows the card of the entity named at a position, or returns nil
+// if no entity is named there.
+func (s *Server) hover(ctx context.Context, params textDocumentPositionParams) (*hoverResult, error) {
+	if s.opts.Cards == nil {
+		return nil, nil
+	}
+	s.mu.Lock()
+	doc, ok := s.docs[params.TextDocument.URI]
+	var text string
+	if ok {
+		text = doc.text
+	}
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	offset := OffsetAt(text, params.Position)
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	lineEnd := len(text)
+	if i := strings.IndexByte(text[offset:], '\n'); i >= 0 {
+		lineEnd = offset + i
+	}
+	line := text[lineStart:lineEnd]
+
+	words := lineWords(line)
+	at := -1
+	for i, w := range words {
+		if w.start <= offset-lineStart && offset-lineStart <= w.end {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return nil, nil
+	}
+
+	// The longest name around the cursor is the most specific: "Bag End"
+	// rather than "End"
+	for n := maxNameWords; n >= 1; n-- {
+		for first := at - n + 1; first <= at; first++ {
+			if first < 0 || first+n > len(words) {
+				continue
+			}
+			start, end := lineStart+words[first].start, lineStart+words[first+n-1].end
+			card, err := s.opts.Cards.HandleCard(ctx, s.opts.World, text[start:end])
+			if errors.Is(err, entities.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &hoverResult{
+				Contents: markupContent{Kind: "markdown", Value: describeCard(card)},
+				Range:    Range{Start: PositionAt(text, start), End: PositionAt(text, end)},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// describeCard returns an entity card as Markdown.
+func describeCard(card *handlers.EntityCard) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**", card.Name)
+	if card.Type != "" {
+		fmt.Fprintf(&b, " _(%s)_", card.Type)
+	}
+	b.WriteString("\n\n")
+
+	if len(card.Facts) == 0 {
+		b.WriteString("No facts known.\n")
+	}
+	for _, fact := range card.Facts {
+		fmt.Fprintf(&b, "- %s %s", strings.ReplaceAll(fact.Predicate, "_", " "), fact.Object)
+		if fact.ClaimedBy != "" {
+			fmt.Fprintf(&b, " (claimed by %s)", fact.ClaimedBy)
+		}
+		if fact.Source != "" {
+			fmt.Fprintf(&b, " — %s", filepath.Base(fact.Source))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(card.Relationships) > 0 {
+		related := make([]string, len(card.Relationships))
+		for i, rel := range card.Relationships {
+			related[i] = fmt.Sprintf("%s %s", strings.ReplaceAll(string(rel.Type), "_", " "), rel.Entity)
+			if rel.Incoming {
+				related[i] = fmt.Sprintf("%s (%s this)", rel.Entity, strings.ReplaceAll(string(rel.Type), "_", " "))
+			}
+		}
+		fmt.Fprintf(&b, "\nRelated: %s\n", strings.Join(related, ", "))
+	}
+	if card.LastAppearance != "" {
+		fmt.Fprintf(&b, "\nLast appears in %s\n", filepath.Base(card.LastAppearance))
+	}
+	return b.String()
+}
+
+// lineWords returns the words of a line: runs of letters, digits,
+// apostrophes, and hyphens, without a trailing possessive "'s".
+func lineWords(line string) []word {
+	var words []word
+	start := -1
+	end := func(i int) {
+		w := line[start:i]
+		for _, suffix := range []string{"'s", "’s"} {
+			if len(w) > len(suffix) && strings.HasSuffix(w, suffix) {
+				i -= len(suffix)
+				break
+			}
+		}
+		words = append(words, word{start: start, end: i})
+		start = -1
+	}
+	for i, r := range line {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '’' || r == '-'
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			end(i)
+		}
+	}
+	if start >= 0 {
+		end(len(line))
+	}
+	return words
+}
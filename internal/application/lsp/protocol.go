@@ -0,0 +1,212 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxMessageSize bounds the body of a message, which holds at most a whole
+// manuscript file.
+const maxMessageSize = 64 << 20
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// message is a JSON-RPC 2.0 request, notification, or response.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// conn reads and writes messages framed by Content-Length headers, as LSP
+// sends them over stdio. Writes may come from several goroutines.
+type conn struct {
+	r  *bufio.Reader
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{r: bufio.NewReader(r), w: w}
+}
+
+// read returns the next message.
+func (c *conn) read() (*message, error) {
+	header, err := textproto.NewReader(c.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > maxMessageSize {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return nil, err
+	}
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return &message{}, fmt.Errorf("%w: %w", errParse, err)
+	}
+	return &msg, nil
+}
+
+// errParse marks a message whose body is not JSON; reading can go on.
+var errParse = errors.New("parsing message")
+
+// write sends a message.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// notify sends a notification.
+func (c *conn) notify(method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return c.write(&message{Method: method, Params: raw})
+}
+
+// Position is a position in a text document: a zero-based line, and a
+// column in UTF-16 code units, as LSP counts them by default.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range of a text document.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range of a document.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// Diagnostic severities.
+const (
+	SeverityError       = 1
+	SeverityWarning     = 2
+	SeverityInformation = 3
+)
+
+// Diagnostic is a problem found in a document, shown as an underline.
+type Diagnostic struct {
+	Range              Range                `json:"range"`
+	Severity           int                  `json:"severity"`
+	Code               string               `json:"code"`
+	Source             string               `json:"source"`
+	Message            string               `json:"message"`
+	RelatedInformation []RelatedInformation `json:"relatedInformation,omitempty"`
+}
+
+// RelatedInformation points a diagnostic at another place involved.
+type RelatedInformation struct {
+	Location Location `json:"location"`
+	Message  string   `json:"message"`
+}
+
+// PublishDiagnosticsParams are the diagnostics of a document.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// Severity maps a contradiction's severity to a diagnostic severity.
+func Severity(severity string) int {
+	switch severity {
+	case "critical":
+		return SeverityError
+	case "minor":
+		return SeverityInformation
+	default:
+		return SeverityWarning
+	}
+}
+
+// PositionAt returns the position of a byte offset in text.
+func PositionAt(text string, offset int) Position {
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	return Position{
+		Line:      strings.Count(text[:lineStart], "\n"),
+		Character: len(utf16.Encode([]rune(text[lineStart:offset]))),
+	}
+}
+
+// OffsetAt returns the byte offset of a position in text, clamped to the
+// end of its line and of the text.
+func OffsetAt(text string, pos Position) int {
+	offset := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strings.IndexByte(text[offset:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		offset += i + 1
+	}
+	for units := 0; offset < len(text) && text[offset] != '\n' && units < pos.Character; {
+		r, size := utf8.DecodeRuneInString(text[offset:])
+		units += utf16.RuneLen(r)
+		offset += size
+	}
+	return offset
+}
+
+// FileURI returns the file URI of a source file, or the source unchanged
+// if it is not a file path, such as a wiki page's URL.
+func FileURI(source string) string {
+	if !filepath.IsAbs(source) {
+		return source
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(source)}).String()
+}
+
+// FilePath returns the path of a file URI, or false if it is not one.
+func FilePath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
@@ -0,0 +1,277 @@
+// Package lsp serves lore to editors over the Language Server Protocol, for
+// 'lore lsp': open manuscript files are checked for contradictions with
+// the world's facts, and hovering an entity shows what is known about it.
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// Options configures a Server.
+type Options struct {
+	World  string
+	Ingest *handlers.IngestHandler // Extracts and checks the facts of open documents
+
+	// IngestOptions controls extraction; facts are always only checked,
+	// never saved.
+	IngestOptions handlers.IngestOptions
+
+	// Chunker splits documents into the chunks checked separately, so an
+	// edit only checks the chunks it changed again (nil = paragraphs).
+	Chunker ports.Chunker
+
+	Cards *handlers.CardHandler // Hovers are not served if nil
+}
+
+// Server is a language server for one world. It serves one client.
+type Server struct {
+	opts Options
+	conn *conn
+
+	mu       sync.Mutex
+	docs     map[string]*document // Open documents by URI
+	checks   sync.WaitGroup
+	shutdown bool
+}
+
+// document is an open text document.
+type document struct {
+	uri    string
+	path   string // The file the document is, recorded as its facts' source
+	text   string
+	cancel context.CancelFunc // Cancels the running check, if any
+
+	// checked caches the issues found in each chunk of the text, so a
+	// check skips the chunks an edit left unchanged.
+	checked map[string][]ports.ConsistencyIssue
+}
+
+// NewServer creates a new language server.
+func NewServer(opts *Options) *Server {
+	s := &Server{opts: *opts, docs: make(map[string]*document)}
+	if s.opts.Chunker == nil {
+		s.opts.Chunker, _ = services.NewChunker(ports.ChunkParagraph)
+	}
+	s.opts.IngestOptions.CheckConsistency = true
+	s.opts.IngestOptions.CheckOnly = true
+	return s
+}
+
+// Serve reads requests from r and writes responses and notifications to w
+// until the client sends exit or r ends.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		s.checks.Wait()
+	}()
+	s.conn = newConn(r, w)
+
+	for {
+		msg, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if errors.Is(err, errParse) {
+			if err := s.conn.write(&message{ID: nullID(), Error: &responseError{Code: codeParseError, Message: err.Error()}}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading message: %w", err)
+		}
+
+		if msg.Method == "exit" {
+			s.mu.Lock()
+			shutdown := s.shutdown
+			s.mu.Unlock()
+			if !shutdown {
+				return errors.New("client exited without shutting down")
+			}
+			return nil
+		}
+		if msg.ID == nil {
+			s.handleNotification(ctx, msg)
+			continue
+		}
+
+		result, rpcErr := s.handleRequest(ctx, msg)
+		resp := &message{ID: msg.ID, Result: result, Error: rpcErr}
+		if rpcErr == nil && result == nil {
+			resp.Result = json.RawMessage("null")
+		}
+		if err := s.conn.write(resp); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+	}
+}
+
+// nullID is the ID of a response to a request whose ID could not be read.
+func nullID() *json.RawMessage {
+	id := json.RawMessage("null")
+	return &id
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// handleRequest answers a request.
+func (s *Server) handleRequest(ctx context.Context, msg *message) (any, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1, // Full text on each change
+					"save":      map[string]any{"includeText": true},
+				},
+				"hoverProvider": s.opts.Cards != nil,
+			},
+			"serverInfo": map[string]any{"name": "lore"},
+		}, nil
+	case "shutdown":
+		s.mu.Lock()
+		s.shutdown = true
+		s.mu.Unlock()
+		return nil, nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		result, err := s.hover(ctx, params)
+		if err != nil {
+			return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+		if result == nil {
+			return nil, nil
+		}
+		return result, nil
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+// handleNotification acts on a notification. Unknown notifications are
+// ignored, as LSP requires.
+func (s *Server) handleNotification(ctx context.Context, msg *message) {
+	var params struct {
+		TextDocument struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"textDocument"`
+		ContentChanges []struct {
+			Text string `json:"text"`
+		} `json:"contentChanges"`
+		Text *string `json:"text"`
+	}
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			s.logError("invalid %s params: %v", msg.Method, err)
+			return
+		}
+	}
+	uri := params.TextDocument.URI
+
+	switch msg.Method {
+	case "textDocument/didOpen":
+		path, ok := FilePath(uri)
+		if !ok {
+			return
+		}
+		s.mu.Lock()
+		s.docs[uri] = &document{uri: uri, path: path, text: params.TextDocument.Text}
+		s.mu.Unlock()
+		s.startCheck(ctx, uri)
+	case "textDocument/didChange":
+		// Edits are checked when saved, not on every keystroke
+		if n := len(params.ContentChanges); n > 0 {
+			s.setText(uri, params.ContentChanges[n-1].Text)
+		}
+	case "textDocument/didSave":
+		if params.Text != nil {
+			s.setText(uri, *params.Text)
+		}
+		s.startCheck(ctx, uri)
+	case "textDocument/didClose":
+		s.mu.Lock()
+		doc, ok := s.docs[uri]
+		if ok {
+			if doc.cancel != nil {
+				doc.cancel()
+			}
+			delete(s.docs, uri)
+		}
+		s.mu.Unlock()
+		if ok {
+			s.publish(uri, nil)
+		}
+	}
+}
+
+// setText replaces the text of an open document.
+func (s *Server) setText(uri, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if doc, ok := s.docs[uri]; ok {
+		doc.text = text
+	}
+}
+
+// publish sends the diagnostics of a document.
+func (s *Server) publish(uri string, diagnostics []Diagnostic) {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+	if err := s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: uri, Diagnostics: diagnostics}); err != nil {
+		s.logError("publishing diagnostics: %v", err)
+	}
+}
+
+// logError shows an error in the client's log.
+func (s *Server) logError(format string, args ...any) {
+	// A client that cannot be written to cannot be told either
+	_ = s.conn.notify("window/logMessage", map[string]any{"type": 1, "message": fmt.Sprintf(format, args...)})
+}
+
+// sourceText returns the text of a source file: the open document's, or
+// else the file's on disk. It reports false if neither can be read.
+func (s *Server) sourceText(path string) (string, bool) {
+	s.mu.Lock()
+	for _, doc := range s.docs {
+		if doc.path == path {
+			text := doc.text
+			s.mu.Unlock()
+			return text, true
+		}
+	}
+	s.mu.Unlock()
+
+	if !strings.HasPrefix(path, "/") && !strings.Contains(path, ":") {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
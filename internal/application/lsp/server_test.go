@@ -0,0 +1,255 @@
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/domain/services"
+)
+
+// blankLineChunker splits text at blank lines, so each paragraph of a test
+// document is a chunk.
+type blankLineChunker struct{}
+
+func (blankLineChunker) Chunk(r io.Reader, emit func(chunk string) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range strings.Split(string(data), "\n\n") {
+		if err := emit(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testClient drives a Server over pipes.
+type testClient struct {
+	t        *testing.T
+	conn     *conn
+	messages chan *message
+	done     chan error
+}
+
+func startServer(t *testing.T, server *Server) *testClient {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &testClient{t: t, conn: newConn(clientR, clientW), messages: make(chan *message, 16), done: make(chan error, 1)}
+	go func() {
+		err := server.Serve(context.Background(), serverR, serverW)
+		serverW.Close()
+		c.done <- err
+	}()
+	go func() {
+		for {
+			msg, err := c.conn.read()
+			if err != nil {
+				close(c.messages)
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	t.Cleanup(func() { clientW.Close() })
+	return c
+}
+
+func (c *testClient) request(id int, method string, params any) {
+	c.t.Helper()
+	raw, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	rawID := json.RawMessage(strconv.Itoa(id))
+	require.NoError(c.t, c.conn.write(&message{ID: &rawID, Method: method, Params: raw}))
+}
+
+func (c *testClient) notify(method string, params any) {
+	c.t.Helper()
+	require.NoError(c.t, c.conn.notify(method, params))
+}
+
+// next returns the next message that is a response or the named
+// notification, skipping others.
+func (c *testClient) next(method string) *message {
+	c.t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.messages:
+			require.True(c.t, ok, "server closed the connection")
+			if msg.Method == method || (method == "" && msg.ID != nil) {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("timed out waiting for %q", method)
+		}
+	}
+}
+
+func (c *testClient) diagnostics() PublishDiagnosticsParams {
+	c.t.Helper()
+	var params PublishDiagnosticsParams
+	require.NoError(c.t, json.Unmarshal(c.next("textDocument/publishDiagnostics").Params, &params))
+	return params
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	canon := filepath.Join(dir, "ch1.txt")
+	draft := filepath.Join(dir, "ch2.txt")
+	require.NoError(t, os.WriteFile(canon, []byte("Frodo lived in Bag End.\n"), 0o600))
+
+	existing := entities.Fact{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", SourceFile: canon}
+	newFact := entities.Fact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Rivendell", SourceFile: draft}
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{newFact},
+		Issues: []ports.ConsistencyIssue{{
+			NewFact: newFact, ExistingFact: existing, Description: "Frodo cannot live in two places", Severity: "critical",
+		}},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	db := &mocks.VectorDB{Facts: []entities.Fact{existing}}
+	relational := mocks.NewRelationalDB()
+	relational.Entities["e1"] = &entities.Entity{ID: "e1", WorldID: "shire", Name: "Frodo", NormalizedName: "frodo"}
+	types := mocks.NewRelationalDB()
+	for _, et := range entities.DefaultEntityTypes {
+		types.Types[et.Name] = &et
+	}
+
+	extraction := services.NewExtractionService(llm, emb, db, services.NewEntityTypeService(types))
+	client := startServer(t, NewServer(&Options{
+		World:   "shire",
+		Ingest:  handlers.NewIngestHandler(extraction),
+		Chunker: blankLineChunker{},
+		Cards: handlers.NewCardHandler(
+			handlers.NewEntityHandler(services.NewEntityService(relational, db)),
+			handlers.NewQueryHandler(services.NewQueryService(emb, db, relational)),
+			nil, 0),
+	}))
+
+	client.request(1, "initialize", map[string]any{})
+	var init struct {
+		Capabilities struct {
+			HoverProvider bool `json:"hoverProvider"`
+		} `json:"capabilities"`
+	}
+	require.NoError(t, json.Unmarshal(mustJSON(t, client.next("").Result), &init))
+	assert.True(t, init.Capabilities.HoverProvider)
+
+	uri := FileURI(draft)
+	text := "Chapter 2\n\nFrodo moved to Rivendell."
+	client.notify("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": uri, "text": text}})
+
+	params := client.diagnostics()
+	assert.Equal(t, uri, params.URI)
+	require.Len(t, params.Diagnostics, 2, "an issue for each chunk checked")
+	d := params.Diagnostics[0]
+	assert.Equal(t, SeverityError, d.Severity)
+	assert.Equal(t, Range{Start: Position{Line: 2}, End: Position{Line: 2, Character: 25}}, d.Range)
+	require.Len(t, d.RelatedInformation, 1)
+	assert.Equal(t, FileURI(canon), d.RelatedInformation[0].Location.URI)
+	assert.Equal(t, 2, llm.ExtractFactsCallCount)
+
+	// Only the paragraph added is checked again
+	text += "\n\nSam followed him."
+	client.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri},
+		"contentChanges": []any{map[string]any{"text": text}},
+	})
+	client.notify("textDocument/didSave", map[string]any{"textDocument": map[string]any{"uri": uri}, "text": text})
+	assert.Len(t, client.diagnostics().Diagnostics, 3)
+	assert.Equal(t, 3, llm.ExtractFactsCallCount)
+
+	client.request(2, "textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": 2, "character": 2},
+	})
+	var hover hoverResult
+	require.NoError(t, json.Unmarshal(mustJSON(t, client.next("").Result), &hover))
+	assert.Contains(t, hover.Contents.Value, "**Frodo** _(character)_")
+	assert.Contains(t, hover.Contents.Value, "- lives in Bag End — ch1.txt")
+	assert.Contains(t, hover.Contents.Value, "Last appears in ch1.txt")
+	assert.Equal(t, Range{Start: Position{Line: 2}, End: Position{Line: 2, Character: 5}}, hover.Range)
+
+	client.notify("textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": uri}})
+	assert.Empty(t, client.diagnostics().Diagnostics)
+
+	client.request(3, "shutdown", nil)
+	client.next("")
+	client.notify("exit", nil)
+	require.NoError(t, <-client.done)
+}
+
+func TestServer_Errors(t *testing.T) {
+	client := startServer(t, NewServer(&Options{World: "shire"}))
+
+	client.request(1, "textDocument/definition", map[string]any{})
+	resp := client.next("")
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, codeMethodNotFound, resp.Error.Code)
+
+	client.request(2, "textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": "file:///closed.txt"},
+		"position":     map[string]any{"line": 0, "character": 0},
+	})
+	resp = client.next("")
+	assert.Nil(t, resp.Error)
+	assert.Nil(t, resp.Result, "no hover without entities")
+
+	client.notify("exit", nil)
+	assert.Error(t, <-client.done, "exit before shutdown")
+}
+
+func TestPositionAt(t *testing.T) {
+	text := "one\ntwo é😀x\n"
+	offset := strings.Index(text, "x")
+	pos := PositionAt(text, offset)
+	assert.Equal(t, Position{Line: 1, Character: 7}, pos, "columns count UTF-16 code units")
+	assert.Equal(t, offset, OffsetAt(text, pos))
+	assert.Equal(t, strings.Index(text, "\n"), OffsetAt(text, Position{Line: 0, Character: 99}), "clamped to the line")
+	assert.Equal(t, len(text), OffsetAt(text, Position{Line: 9}))
+}
+
+func TestFileURI(t *testing.T) {
+	uri := FileURI("/books/my draft.txt")
+	assert.Equal(t, "file:///books/my%20draft.txt", uri)
+	path, ok := FilePath(uri)
+	assert.True(t, ok)
+	assert.Equal(t, filepath.FromSlash("/books/my draft.txt"), path)
+
+	assert.Equal(t, "https://wiki/Frodo", FileURI("https://wiki/Frodo"))
+	_, ok = FilePath("untitled:Untitled-1")
+	assert.False(t, ok)
+}
+
+func TestLineWords(t *testing.T) {
+	line := "Then Frodo's friend Sam-wise left."
+	var got []string
+	for _, w := range lineWords(line) {
+		got = append(got, line[w.start:w.end])
+	}
+	assert.Equal(t, []string{"Then", "Frodo", "friend", "Sam-wise", "left"}, got)
+}
@@ -0,0 +1,171 @@
+// Package readiness checks that the backends 'lore serve' depends on are
+// reachable, in the background, so requests can fail fast while one is
+// down instead of each waiting out its own timeout.
+package readiness
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// Defaults for a Monitor.
+const (
+	DefaultInterval = 30 * time.Second // Between background checks
+	DefaultTimeout  = 5 * time.Second  // For each check
+)
+
+// Status of a Report.
+const (
+	StatusStarting = "starting" // Not checked yet
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // At least one check failed
+)
+
+// Pinger is implemented by backends that can check they are reachable,
+// and that their credentials are accepted, without doing any work.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Check is one named readiness check.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// PingCheck returns a Check that pings p.
+func PingCheck(name string, p Pinger) Check {
+	return Check{Name: name, Check: p.Ping}
+}
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Report is the outcome of the latest round of checks.
+type Report struct {
+	Status    string        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at,omitzero"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// Ready reports whether every check passed.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Monitor runs checks periodically and keeps the latest report. It is safe
+// for concurrent use.
+type Monitor struct {
+	checks  []Check
+	timeout time.Duration
+	now     func() time.Time
+
+	mu     sync.RWMutex
+	report Report
+}
+
+// NewMonitor creates a Monitor for checks, each bounded by timeout. A
+// zero timeout uses DefaultTimeout.
+func NewMonitor(checks []Check, timeout time.Duration) *Monitor {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Monitor{
+		checks:  checks,
+		timeout: timeout,
+		now:     time.Now,
+		report:  Report{Status: StatusStarting, Checks: []CheckResult{}},
+	}
+}
+
+// Check runs every check concurrently, records the report, and returns it.
+func (m *Monitor) Check(ctx context.Context) Report {
+	results := make([]CheckResult, len(m.checks))
+	var wg sync.WaitGroup
+	for i, c := range m.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, CheckedAt: m.now(), Checks: results}
+	for _, r := range results {
+		if !r.OK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	m.mu.Lock()
+	m.report = report
+	m.mu.Unlock()
+	return report
+}
+
+func (m *Monitor) run(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	start := m.now()
+	err := c.Check(ctx)
+	result := CheckResult{Name: c.Name, OK: err == nil, DurationMS: m.now().Sub(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Run checks immediately and then every interval until ctx is canceled.
+// A zero interval uses DefaultInterval.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report returns the latest report.
+func (m *Monitor) Report() Report {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.report
+}
+
+// Err returns an entities.ErrBackendUnavailable error naming the failed
+// checks if the latest report is degraded, and nil otherwise.
+func (m *Monitor) Err() error {
+	report := m.Report()
+	if report.Status != StatusDegraded {
+		return nil
+	}
+
+	var failed []string
+	for _, r := range report.Checks {
+		if !r.OK {
+			failed = append(failed, r.Name+": "+r.Error)
+		}
+	}
+	return entities.Errorf(entities.ErrBackendUnavailable, "service degraded (%s, checked %s); try again once it recovers",
+		strings.Join(failed, "; "), report.CheckedAt.Format(time.RFC3339))
+}
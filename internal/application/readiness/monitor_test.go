@@ -0,0 +1,81 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestMonitor_Check(t *testing.T) {
+	var sqliteErr error
+	m := NewMonitor([]Check{
+		{Name: "qdrant", Check: func(context.Context) error { return nil }},
+		{Name: "sqlite", Check: func(context.Context) error { return sqliteErr }},
+	}, time.Second)
+
+	assert.Equal(t, StatusStarting, m.Report().Status)
+	assert.NoError(t, m.Err(), "requests are not refused before the first check")
+
+	report := m.Check(context.Background())
+	assert.True(t, report.Ready())
+	assert.Equal(t, report, m.Report())
+	assert.NoError(t, m.Err())
+
+	sqliteErr = errors.New("disk I/O error")
+	report = m.Check(context.Background())
+	assert.Equal(t, StatusDegraded, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.True(t, report.Checks[0].OK)
+	assert.Equal(t, CheckResult{Name: "sqlite", Error: "disk I/O error"}, report.Checks[1])
+
+	err := m.Err()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, entities.ErrBackendUnavailable)
+	assert.Contains(t, err.Error(), "service degraded (sqlite: disk I/O error, checked ")
+
+	sqliteErr = nil
+	m.Check(context.Background())
+	assert.NoError(t, m.Err(), "recovers on the next check")
+}
+
+func TestMonitor_CheckTimeout(t *testing.T) {
+	m := NewMonitor([]Check{
+		{Name: "llm", Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, 10*time.Millisecond)
+
+	report := m.Check(context.Background())
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestMonitor_Run(t *testing.T) {
+	var calls atomic.Int32
+	m := NewMonitor([]Check{
+		{Name: "qdrant", Check: func(context.Context) error {
+			calls.Add(1)
+			return nil
+		}},
+	}, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Run(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return calls.Load() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.True(t, m.Report().Ready())
+}
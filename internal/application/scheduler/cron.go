@@ -0,0 +1,199 @@
+// Package scheduler runs background jobs on cron schedules.
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearchYears bounds the search for the next run of a schedule that can
+// never match, such as February 31st.
+const maxSearchYears = 5
+
+// cronMacros maps shorthand schedules to their cron expressions.
+var cronMacros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// Schedule is a parsed five-field cron expression: minute, hour,
+// day of month, month, and day of week.
+type Schedule struct {
+	spec   string
+	minute uint64 // Bit i set when minute i matches
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool // Day-of-month field was "*"
+	anyDow bool // Day-of-week field was "*"
+}
+
+// field describes the bounds of one cron field.
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse parses a cron expression or one of the macros @hourly, @daily,
+// @midnight, @weekly, and @monthly. Fields accept *, numbers, ranges
+// (a-b), steps (*/n, a-b/n), and comma-separated lists. In the
+// day-of-week field both 0 and 7 mean Sunday.
+func Parse(spec string) (*Schedule, error) {
+	expr := strings.TrimSpace(spec)
+	if macro, ok := cronMacros[expr]; ok {
+		expr = macro
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(parts))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	// Sunday may be written as 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		spec:   spec,
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		anyDom: parts[2] == "*",
+		anyDow: parts[4] == "*",
+	}, nil
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first time after t that matches the schedule, or the
+// zero time if none occurs within the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			// Not t.Truncate(time.Hour): it rounds in UTC, which is off
+			// the local hour in zones such as India's (UTC+5:30)
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: when both day fields are
+// restricted, a day matches if either field does.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dowMatch
+	case s.anyDow:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// parseField parses one comma-separated cron field into a bit set.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		lo, hi, step, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseRange parses *, n, a-b, and their /step forms.
+func parseRange(expr string, f field) (lo, hi, step int, err error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+
+	step = 1
+	if hasStep {
+		step, err = strconv.Atoi(stepExpr)
+		if err != nil || step < 1 {
+			return 0, 0, 0, fmt.Errorf("invalid %s step %q", f.name, stepExpr)
+		}
+	}
+
+	switch {
+	case rangeExpr == "*":
+		return f.min, f.max, step, nil
+	case strings.Contains(rangeExpr, "-"):
+		loExpr, hiExpr, _ := strings.Cut(rangeExpr, "-")
+		if lo, err = parseValue(loExpr, f); err != nil {
+			return 0, 0, 0, err
+		}
+		if hi, err = parseValue(hiExpr, f); err != nil {
+			return 0, 0, 0, err
+		}
+		if lo > hi {
+			return 0, 0, 0, fmt.Errorf("invalid %s range %q", f.name, rangeExpr)
+		}
+		return lo, hi, step, nil
+	default:
+		if lo, err = parseValue(rangeExpr, f); err != nil {
+			return 0, 0, 0, err
+		}
+		if hasStep {
+			return lo, f.max, step, nil
+		}
+		return lo, lo, step, nil
+	}
+}
+
+func parseValue(expr string, f field) (int, error) {
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q (valid: %d-%d)", f.name, expr, f.min, f.max)
+	}
+	return v, nil
+}
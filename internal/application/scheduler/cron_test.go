@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"empty", ""},
+		{"too few fields", "0 3 * *"},
+		{"too many fields", "0 3 * * * *"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"reversed range", "0 5-1 * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"not a number", "a * * * *"},
+		{"unknown macro", "@yearly"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Thursday
+	from := time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"daily later today", "0 12 * * *", time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"daily tomorrow", "0 3 * * *", time.Date(2026, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"step minutes", "*/20 * * * *", time.Date(2026, 1, 15, 10, 40, 0, 0, time.UTC)},
+		{"range with step", "0 9-17/4 * * *", time.Date(2026, 1, 15, 13, 0, 0, 0, time.UTC)},
+		{"list", "15,45 * * * *", time.Date(2026, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"next month", "0 0 1 * *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of week", "0 0 * * 1", time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"sunday as seven", "0 0 * * 7", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"either day field matches", "0 0 20 * 6", time.Date(2026, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"specific month", "0 0 1 6 *", time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"hourly macro", "@hourly", time.Date(2026, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"daily macro", "@daily", time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"weekly macro", "@weekly", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"monthly macro", "@monthly", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestSchedule_Next_HalfHourZone(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	schedule, err := Parse("0 3 * * *")
+	require.NoError(t, err)
+	from := time.Date(2026, 1, 15, 10, 30, 0, 0, kolkata)
+	assert.Equal(t, time.Date(2026, 1, 16, 3, 0, 0, 0, kolkata), schedule.Next(from))
+
+	schedule, err = Parse("@hourly")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 15, 11, 0, 0, 0, kolkata), schedule.Next(from))
+}
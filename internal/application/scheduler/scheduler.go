@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// JobFunc is the work a scheduled job performs.
+type JobFunc func(ctx context.Context) error
+
+// JobStatus reports the state of a scheduled job.
+type JobStatus struct {
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Running   bool      `json:"running"`
+	NextRun   time.Time `json:"next_run,omitzero"`
+	LastRun   time.Time `json:"last_run,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type job struct {
+	schedule *Schedule
+	fn       JobFunc
+	status   JobStatus
+}
+
+// Scheduler runs jobs on cron schedules. Each job runs in its own
+// goroutine, and a job never overlaps with itself.
+type Scheduler struct {
+	mu    sync.Mutex
+	jobs  []*job
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// New creates an empty Scheduler.
+func New() *Scheduler {
+	return &Scheduler{
+		now:   time.Now,
+		after: time.After,
+	}
+}
+
+// Add registers a job under a cron schedule. Jobs must be added before Run.
+// A schedule that never matches, such as February 31st, is an error.
+func (s *Scheduler) Add(name, spec string, fn JobFunc) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("scheduling %s: %w", name, err)
+	}
+	if schedule.Next(s.now()).IsZero() {
+		return fmt.Errorf("scheduling %s: schedule %q never runs", name, spec)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, &job{
+		schedule: schedule,
+		fn:       fn,
+		status: JobStatus{
+			Name:     name,
+			Schedule: schedule.String(),
+		},
+	})
+	return nil
+}
+
+// Run runs the registered jobs until ctx is canceled, then waits for any
+// running job to return.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := append([]*job(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runJob(ctx, j)
+		}()
+	}
+	wg.Wait()
+}
+
+// Status returns the state of every job, in the order they were added.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, len(s.jobs))
+	for i, j := range s.jobs {
+		statuses[i] = j.status
+	}
+	return statuses
+}
+
+// runJob waits for each scheduled time and runs the job.
+func (s *Scheduler) runJob(ctx context.Context, j *job) {
+	for {
+		next := j.schedule.Next(s.now())
+		if next.IsZero() {
+			s.update(j, func(st *JobStatus) {
+				st.NextRun = time.Time{}
+				st.LastError = "schedule has no further runs"
+			})
+			return
+		}
+		s.update(j, func(st *JobStatus) { st.NextRun = next })
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(next.Sub(s.now())):
+		}
+
+		s.update(j, func(st *JobStatus) { st.Running = true })
+		err := j.fn(ctx)
+		s.update(j, func(st *JobStatus) {
+			st.Running = false
+			st.LastRun = s.now()
+			st.LastError = ""
+			if err != nil {
+				st.LastError = err.Error()
+			}
+		})
+	}
+}
+
+func (s *Scheduler) update(j *job, fn func(*JobStatus)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&j.status)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Add_InvalidSchedule(t *testing.T) {
+	s := New()
+	err := s.Add("backup", "not a schedule", func(context.Context) error { return nil })
+	assert.Error(t, err)
+	assert.Empty(t, s.Status())
+}
+
+func TestScheduler_Add_NeverRuns(t *testing.T) {
+	s := New()
+	err := s.Add("backup", "0 0 31 2 *", func(context.Context) error { return nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "never runs")
+	assert.Empty(t, s.Status())
+}
+
+func TestScheduler_Run(t *testing.T) {
+	now := time.Date(2026, 1, 15, 2, 59, 30, 0, time.UTC)
+
+	ticks := make(chan time.Time)
+	s := New()
+	s.now = func() time.Time { return now }
+	s.after = func(time.Duration) <-chan time.Time { return ticks }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs := make(chan struct{}, 2)
+	calls := 0
+	require.NoError(t, s.Add("backup", "0 3 * * *", func(context.Context) error {
+		calls++
+		runs <- struct{}{}
+		if calls == 1 {
+			return errors.New("disk full")
+		}
+		return nil
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	// First run fails and the error is recorded
+	ticks <- now
+	<-runs
+	require.Eventually(t, func() bool {
+		return s.Status()[0].LastError == "disk full"
+	}, time.Second, time.Millisecond)
+
+	status := s.Status()[0]
+	assert.Equal(t, "backup", status.Name)
+	assert.Equal(t, "0 3 * * *", status.Schedule)
+	assert.Equal(t, now, status.LastRun)
+	assert.Equal(t, time.Date(2026, 1, 15, 3, 0, 0, 0, time.UTC), status.NextRun)
+
+	// Second run succeeds and clears the error
+	ticks <- now
+	<-runs
+	require.Eventually(t, func() bool {
+		return s.Status()[0].LastError == "" && !s.Status()[0].Running
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+	assert.Equal(t, 2, calls)
+}
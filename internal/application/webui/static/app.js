@@ -0,0 +1,306 @@
+// Lore web UI: a thin client of the 'lore serve' API with no dependencies.
+"use strict";
+
+const PAGE_SIZE = 50;
+const TOKEN_KEY = "lore-token";
+
+const $ = (id) => document.getElementById(id);
+
+function token() {
+  return localStorage.getItem(TOKEN_KEY) || "";
+}
+
+function headers(extra) {
+  const h = Object.assign({}, extra);
+  if (token()) {
+    h.Authorization = "Bearer " + token();
+  }
+  return h;
+}
+
+function notice(message) {
+  const bar = $("notice");
+  bar.textContent = message || "";
+  bar.hidden = !message;
+}
+
+// api fetches a JSON endpoint, throwing the server's error message.
+async function api(path) {
+  const resp = await fetch(path, { headers: headers() });
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    if (resp.status === 401) {
+      throw new Error("This server needs an API token. Enter one above.");
+    }
+    throw new Error(body.error || resp.status + " " + resp.statusText);
+  }
+  return body;
+}
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    node.setAttribute(k, v);
+  }
+  for (const c of children) {
+    node.append(c);
+  }
+  return node;
+}
+
+function entityLink(name) {
+  return el("a", { href: "#entity/" + encodeURIComponent(name) }, name);
+}
+
+function factItem(f) {
+  const item = el("li", {},
+    el("span", { class: "type" }, f.type),
+    " ", entityLink(f.subject), " ", el("em", {}, f.predicate), " ", f.object);
+  if (f.status === "pending") {
+    item.append(" ", el("span", { class: "badge" }, "pending review"));
+  }
+  if (f.tier && f.tier !== "exact") {
+    item.append(" ", el("span", { class: "muted" }, "(" + f.tier + " match)"));
+  }
+  if (f.source_file) {
+    item.append(el("div", { class: "muted" }, f.source_file));
+  }
+  return item;
+}
+
+// Views
+
+async function showSearch(q) {
+  $("search-q").value = q || "";
+  $("search-results").replaceChildren();
+  if (!q) {
+    return;
+  }
+  const mode = $("search-mode").value;
+  const result = await api("/api/query?" + new URLSearchParams({ q, mode }));
+  $("search-results").replaceChildren(...result.facts.map(factItem));
+  if (result.facts.length === 0) {
+    $("search-results").append(el("li", { class: "muted" }, "No facts found."));
+  }
+}
+
+let entitiesOffset = 0;
+
+async function showEntities() {
+  const q = $("entities-q").value;
+  const params = new URLSearchParams({ limit: PAGE_SIZE, offset: entitiesOffset });
+  if (q) {
+    params.set("q", q);
+  }
+  const result = await api("/api/entities?" + params);
+  $("entities-list").replaceChildren(...result.entities.map((e) => el("li", {}, entityLink(e.name))));
+  const last = Math.min(entitiesOffset + result.entities.length, result.total);
+  $("entities-page").textContent = result.total ? (entitiesOffset + 1) + "–" + last + " of " + result.total : "No entities.";
+  $("entities-prev").disabled = q !== "" || entitiesOffset === 0;
+  $("entities-next").disabled = q !== "" || last >= result.total;
+}
+
+async function showEntity(name) {
+  const page = await api("/api/entities/" + encodeURIComponent(name));
+  $("entity-name").textContent = page.entity.name;
+  $("entity-facts").replaceChildren(...page.facts.map(factItem));
+  $("entity-rel-count").textContent = page.total_relationships > page.relationships.length
+    ? "(" + page.relationships.length + " of " + page.total_relationships + ")" : "";
+  $("entity-relationships").replaceChildren(...page.relationships.map((r) => el("li", {},
+    entityLink(r.source_entity ? r.source_entity.name : "?"),
+    " ", el("em", {}, r.relationship.type + (r.relationship.bidirectional ? " ↔" : " →")), " ",
+    entityLink(r.target_entity ? r.target_entity.name : "?"))));
+  drawGraph(page.entity, page.relationships);
+}
+
+// drawGraph lays the entity's neighbors out in a circle around it.
+function drawGraph(entity, relationships) {
+  const svg = $("graph");
+  const ns = "http://www.w3.org/2000/svg";
+  const svgEl = (tag, attrs, text) => {
+    const node = document.createElementNS(ns, tag);
+    for (const [k, v] of Object.entries(attrs)) {
+      node.setAttribute(k, v);
+    }
+    if (text) {
+      node.textContent = text;
+    }
+    return node;
+  };
+
+  const neighbors = new Map();
+  for (const r of relationships) {
+    const other = r.relationship.source_entity_id === entity.id ? r.target_entity : r.source_entity;
+    if (!other || other.id === entity.id) {
+      continue;
+    }
+    const edge = neighbors.get(other.id) || { entity: other, types: [] };
+    edge.types.push(r.relationship.type);
+    neighbors.set(other.id, edge);
+  }
+
+  const nodes = [...neighbors.values()];
+  const radius = 150;
+  svg.replaceChildren();
+  nodes.forEach((n, i) => {
+    const angle = (2 * Math.PI * i) / nodes.length - Math.PI / 2;
+    n.x = radius * Math.cos(angle);
+    n.y = radius * Math.sin(angle);
+    svg.append(svgEl("line", { x1: 0, y1: 0, x2: n.x, y2: n.y, class: "edge" }));
+    svg.append(svgEl("text", { x: n.x / 2, y: n.y / 2 - 4, class: "edge-label" }, n.types.join(", ")));
+  });
+  for (const n of nodes) {
+    const link = svgEl("a", { href: "#entity/" + encodeURIComponent(n.entity.name) });
+    link.append(svgEl("circle", { cx: n.x, cy: n.y, r: 8, class: "node" }));
+    link.append(svgEl("text", { x: n.x, y: n.y + 22, class: "node-label" }, n.entity.name));
+    svg.append(link);
+  }
+  svg.append(svgEl("circle", { cx: 0, cy: 0, r: 12, class: "node center" }));
+  svg.append(svgEl("text", { x: 0, y: 30, class: "node-label center" }, entity.name));
+  if (nodes.length === 0) {
+    svg.append(svgEl("text", { x: 0, y: 60, class: "edge-label" }, "No relationships yet."));
+  }
+}
+
+// ingest posts text and logs each server-sent event as it arrives.
+async function ingest(text, source) {
+  const log = $("ingest-log");
+  log.replaceChildren();
+  const logLine = (cls, ...parts) => log.append(el("li", { class: cls }, ...parts));
+
+  const resp = await fetch("/api/ingest/stream", {
+    method: "POST",
+    headers: headers({ "Content-Type": "application/json" }),
+    body: JSON.stringify({
+      text,
+      source: source || undefined,
+      check_consistency: $("ingest-check").checked,
+      check_only: $("ingest-check-only").checked,
+    }),
+  });
+  if (!resp.ok) {
+    const body = await resp.json().catch(() => ({}));
+    throw new Error(body.error || resp.status + " " + resp.statusText);
+  }
+
+  const reader = resp.body.pipeThrough(new TextDecoderStream()).getReader();
+  let buffer = "";
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) {
+      break;
+    }
+    buffer += value;
+    let end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      const raw = buffer.slice(0, end);
+      buffer = buffer.slice(end + 2);
+      const event = (raw.match(/^event: (.*)$/m) || [])[1];
+      const data = JSON.parse((raw.match(/^data: (.*)$/m) || [, "{}"])[1]);
+      switch (event) {
+        case "chunk":
+          logLine("chunk", "Chunk " + (data.index + 1) + ": " + data.facts.length + " facts");
+          for (const f of data.facts) {
+            const item = factItem(f);
+            item.classList.add("fact");
+            log.append(item);
+          }
+          break;
+        case "issue":
+          logLine("issue " + data.severity, data.severity + ": " + data.description);
+          break;
+        case "done":
+          logLine("done", data.facts + " facts" + (data.saved ? " saved" : " checked") +
+            ", " + data.pending + " held for review, " + data.issues + " issues.");
+          break;
+        case "error":
+          logLine("issue", "Error: " + data.error);
+          break;
+      }
+    }
+  }
+}
+
+// Routing
+
+const views = ["search", "entities", "entity", "ingest"];
+
+async function route() {
+  const [view, arg] = (location.hash.slice(1) || "search").split("/");
+  for (const v of views) {
+    $(v + "-view").hidden = v !== view;
+  }
+  notice("");
+  try {
+    switch (view) {
+      case "search":
+        await showSearch(arg ? decodeURIComponent(arg) : "");
+        break;
+      case "entities":
+        await showEntities();
+        break;
+      case "entity":
+        await showEntity(decodeURIComponent(arg));
+        break;
+    }
+  } catch (err) {
+    notice(err.message);
+  }
+}
+
+$("token").value = token();
+$("token-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  localStorage.setItem(TOKEN_KEY, $("token").value.trim());
+  route();
+});
+
+$("search-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  location.hash = "search/" + encodeURIComponent($("search-q").value);
+});
+
+$("entities-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  entitiesOffset = 0;
+  showEntities().catch((err) => notice(err.message));
+});
+$("entities-prev").addEventListener("click", () => {
+  entitiesOffset = Math.max(0, entitiesOffset - PAGE_SIZE);
+  showEntities().catch((err) => notice(err.message));
+});
+$("entities-next").addEventListener("click", () => {
+  entitiesOffset += PAGE_SIZE;
+  showEntities().catch((err) => notice(err.message));
+});
+
+const dropzone = $("dropzone");
+dropzone.addEventListener("dragover", (e) => {
+  e.preventDefault();
+  dropzone.classList.add("over");
+});
+dropzone.addEventListener("dragleave", () => dropzone.classList.remove("over"));
+dropzone.addEventListener("drop", async (e) => {
+  e.preventDefault();
+  dropzone.classList.remove("over");
+  const file = e.dataTransfer.files[0];
+  if (file) {
+    $("ingest-text").value = await file.text();
+    $("ingest-source").value = file.name;
+  }
+});
+
+$("ingest-form").addEventListener("submit", (e) => {
+  e.preventDefault();
+  notice("");
+  ingest($("ingest-text").value, $("ingest-source").value.trim()).catch((err) => notice(err.message));
+});
+
+window.addEventListener("hashchange", route);
+
+api("/api/status")
+  .then((status) => {
+    $("world").textContent = status.world + (status.demo ? " (demo)" : "");
+  })
+  .catch(() => {});
+route();
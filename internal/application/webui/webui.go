@@ -0,0 +1,23 @@
+// Package webui is a minimal single-page UI for browsing a world over the
+// 'lore serve' API: search, entity pages with a relationship graph, and a
+// drop zone for ingesting text. It has no build step; the files under
+// static are served as they are.
+package webui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the UI's files, with index.html at the root.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+	return http.FileServerFS(files)
+}
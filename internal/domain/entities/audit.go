@@ -2,6 +2,27 @@ package entities
 
 import "time"
 
+// Audit log actions.
+const (
+	// AuditActionSlowQuery records a backend call that exceeded its
+	// slow-query threshold or timed out.
+	AuditActionSlowQuery = "slow_query"
+	// AuditActionFactCreate records a fact added by hand.
+	AuditActionFactCreate = "fact_create"
+	// AuditActionFactUpdate records a fact changed by hand.
+	AuditActionFactUpdate = "fact_update"
+	// AuditActionFactDelete records a fact deleted by hand.
+	AuditActionFactDelete = "fact_delete"
+	// AuditActionEntityDelete records an entity deleted by hand.
+	AuditActionEntityDelete = "entity_delete"
+	// AuditActionRelationshipDelete records a relationship, and the fact
+	// representing it, deleted along with one of its entities.
+	AuditActionRelationshipDelete = "relationship_delete"
+	// AuditActionConsistencyBatch records one LLM call of a consistency
+	// check and how long it took.
+	AuditActionConsistencyBatch = "consistency_batch"
+)
+
 // AuditEntry represents a logged action in the system.
 type AuditEntry struct {
 	ID        int64          `json:"id"`
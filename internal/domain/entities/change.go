@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+// ChangeKind is what a change feed entry changed.
+type ChangeKind string
+
+const (
+	ChangeKindFact         ChangeKind = "fact"
+	ChangeKindRelationship ChangeKind = "relationship"
+)
+
+// ChangeOp is how a change feed entry changed it.
+type ChangeOp string
+
+const (
+	ChangeOpAdded   ChangeOp = "added"
+	ChangeOpUpdated ChangeOp = "updated"
+	ChangeOpDeleted ChangeOp = "deleted"
+	// ChangeOpCleared means every fact was deleted.
+	ChangeOpCleared ChangeOp = "cleared"
+)
+
+// Change is one entry of a world's change feed, for keeping other systems
+// in sync without exporting everything again.
+type Change struct {
+	Seq  int64      `json:"seq"` // Increases with every change; the feed's cursor
+	Kind ChangeKind `json:"kind"`
+	Op   ChangeOp   `json:"op"`
+
+	// ID is the fact or relationship changed. It is empty when every fact
+	// of Source was deleted at once, or every fact was cleared.
+	ID     string `json:"id,omitempty"`
+	Source string `json:"source,omitempty"`
+
+	// Fact or Relationship is the state after the change; neither is set
+	// for deletions.
+	Fact         *Fact         `json:"fact,omitempty"`
+	Relationship *Relationship `json:"relationship,omitempty"`
+
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -0,0 +1,46 @@
+package entities
+
+import "time"
+
+// ConflictStatus tracks whether a detected contradiction still needs attention.
+type ConflictStatus string
+
+const (
+	ConflictOpen     ConflictStatus = "open"
+	ConflictResolved ConflictStatus = "resolved"
+)
+
+// IsValid reports whether the status is a known status.
+func (s ConflictStatus) IsValid() bool {
+	return s == ConflictOpen || s == ConflictResolved
+}
+
+// Conflict records a contradiction detected between two stored facts.
+// The pair is stored in ID order, so each pair is recorded once.
+type Conflict struct {
+	ID          string         `json:"id"`
+	FactID      string         `json:"fact_id"`
+	OtherFactID string         `json:"other_fact_id"`
+	Description string         `json:"description"`
+	Severity    string         `json:"severity"`
+	Status      ConflictStatus `json:"status"`
+	Resolution  string         `json:"resolution,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	ResolvedAt  time.Time      `json:"resolved_at,omitempty"`
+}
+
+// Involves reports whether factID is one of the conflicting facts.
+func (c *Conflict) Involves(factID string) bool {
+	return c.FactID == factID || c.OtherFactID == factID
+}
+
+// ConsistencyVerdict is the cached outcome of checking a new fact against
+// an existing one. Key identifies the pair by content, so editing either
+// fact means the pair is checked again.
+type ConsistencyVerdict struct {
+	Key         string    `json:"key"`
+	Conflicting bool      `json:"conflicting"`
+	Description string    `json:"description,omitempty"`
+	Severity    string    `json:"severity,omitempty"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
@@ -3,6 +3,9 @@ package entities
 import (
 	"strings"
 	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // Entity represents a named subject (character, location, etc.) that can
@@ -16,7 +19,27 @@ type Entity struct {
 	CreatedAt      time.Time `json:"created_at"`
 }
 
-// NormalizeName converts a name to lowercase for case-insensitive matching.
+// NormalizeName converts a name to a case-insensitive form for matching.
+// Names are first put in Unicode normal form C, so an accented letter
+// typed as one character matches the same letter typed as a base letter
+// plus accent, and letters with several lowercase forms, such as Greek
+// final sigma, are folded to one.
 func NormalizeName(name string) string {
-	return strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(foldCase, norm.NFC.String(strings.TrimSpace(name)))
+}
+
+// foldCase lowercases r by way of its uppercase form, so "ς" and "σ" both
+// become "σ".
+func foldCase(r rune) rune {
+	return unicode.ToLower(unicode.ToUpper(r))
+}
+
+// FactSubject links a fact to the entity it is about, so the facts about an
+// entity are found by its ID rather than by matching subject text. Merging
+// entities moves their links, so an entity's facts include those written
+// under the spellings merged into it.
+type FactSubject struct {
+	FactID     string `json:"fact_id"`
+	EntityID   string `json:"entity_id"`
+	SourceFile string `json:"source_file,omitempty"`
 }
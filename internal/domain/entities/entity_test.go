@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "case and spaces", a: " Frodo Baggins ", b: "frodo baggins"},
+		{name: "accented capitals", a: "ÉOWYN", b: "éowyn"},
+		{name: "composed and decomposed accents", a: "Zo\u00eb", b: "Zoe\u0308"},
+		{name: "greek final sigma", a: "ΟΔΥΣΣΕΥΣ", b: "οδυσσευς"},
+		{name: "cyrillic", a: "Москва", b: "МОСКВА"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, NormalizeName(tt.a), NormalizeName(tt.b))
+		})
+	}
+
+	assert.Equal(t, "frodo baggins", NormalizeName(" Frodo Baggins "))
+	assert.Equal(t, "東京", NormalizeName("東京"))
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds shared by services, handlers, and adapters. Match them with
+// errors.Is to tell failures apart, for example to pick an exit code or an
+// HTTP status.
+var (
+	// ErrNotFound means the requested fact, entity, type, or world does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrValidation means the input was rejected before anything was changed.
+	ErrValidation = errors.New("invalid input")
+	// ErrConflict means the request clashes with existing state, such as a
+	// duplicate name.
+	ErrConflict = errors.New("conflict")
+	// ErrBackendUnavailable means a backend (Qdrant, SQLite, or the LLM
+	// provider) could not be reached or did not answer in time. Retrying
+	// later may succeed.
+	ErrBackendUnavailable = errors.New("backend unavailable")
+)
+
+// errorKinds lists the error kinds in the order ErrorKind checks them.
+var errorKinds = []error{ErrNotFound, ErrValidation, ErrConflict, ErrBackendUnavailable}
+
+// kindError is an error of a given kind whose message is unchanged by it.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string   { return e.err.Error() }
+func (e *kindError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// Errorf formats an error like fmt.Errorf, including %w wrapping, and marks
+// it as kind so errors.Is(err, kind) reports true. The kind is not added to
+// the message.
+func Errorf(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// WithKind marks err as kind, keeping its message. A nil err stays nil.
+func WithKind(kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// ErrorKind returns the kind err was marked with, or nil if it has none.
+func ErrorKind(err error) error {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorf(t *testing.T) {
+	err := Errorf(ErrNotFound, "entity %q not found", "Frodo")
+	assert.EqualError(t, err, `entity "Frodo" not found`)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrValidation)
+
+	wrapped := fmt.Errorf("relating entities: %w", err)
+	assert.Equal(t, ErrNotFound, ErrorKind(wrapped), "the kind survives further wrapping")
+
+	withCause := Errorf(ErrBackendUnavailable, "calling OpenAI: %w", io.ErrUnexpectedEOF)
+	assert.ErrorIs(t, withCause, io.ErrUnexpectedEOF)
+	assert.ErrorIs(t, withCause, ErrBackendUnavailable)
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"plain", errors.New("boom"), nil},
+		{"sentinel", ErrConflict, ErrConflict},
+		{"marked", WithKind(ErrValidation, errors.New("bad limit")), ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ErrorKind(tt.err))
+		})
+	}
+	assert.NoError(t, WithKind(ErrNotFound, nil))
+}
@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// ExtractionStat is how one extraction call to a model went: the facts it
+// extracted and how confident the model was in them, or that its response
+// could not be parsed.
+type ExtractionStat struct {
+	ID            int64     `json:"id"`
+	RecordedAt    time.Time `json:"recorded_at"`
+	Model         string    `json:"model"`
+	Facts         int       `json:"facts"`
+	ConfidenceSum float64   `json:"confidence_sum"` // Sum of the facts' confidences
+	ParseFailed   bool      `json:"parse_failed"`
+}
+
+// ModelStats sums the extraction calls made to one model, to compare the
+// models tried on a world's material.
+type ModelStats struct {
+	Model         string  `json:"model"`
+	Calls         int     `json:"calls"`
+	ParseFailures int     `json:"parse_failures"`
+	Facts         int     `json:"facts"`
+	ConfidenceSum float64 `json:"confidence_sum"`
+}
+
+// ParseFailureRate returns the share of calls whose response could not be
+// parsed.
+func (s ModelStats) ParseFailureRate() float64 {
+	if s.Calls == 0 {
+		return 0
+	}
+	return float64(s.ParseFailures) / float64(s.Calls)
+}
+
+// FactsPerChunk returns the facts extracted per call that was parsed.
+func (s ModelStats) FactsPerChunk() float64 {
+	parsed := s.Calls - s.ParseFailures
+	if parsed <= 0 {
+		return 0
+	}
+	return float64(s.Facts) / float64(parsed)
+}
+
+// AverageConfidence returns the mean confidence of the facts extracted.
+func (s ModelStats) AverageConfidence() float64 {
+	if s.Facts == 0 {
+		return 0
+	}
+	return s.ConfidenceSum / float64(s.Facts)
+}
@@ -1,7 +1,14 @@
 // Package entities contains core domain data structures.
 package entities
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
 
 // FactType represents the category of a fact.
 // Validation of fact types is now handled by EntityTypeService, which supports
@@ -18,18 +25,156 @@ const (
 	FactTypeTimeline     FactType = "timeline"
 )
 
+// FactStatus records whether a fact has been accepted into the knowledge base.
+type FactStatus string
+
+const (
+	// FactStatusActive facts are searchable. An empty status means active.
+	FactStatusActive FactStatus = "active"
+	// FactStatusPending facts await review and are excluded from searches.
+	FactStatusPending FactStatus = "pending"
+)
+
 // Fact represents a single piece of factual information about a fictional world.
 type Fact struct {
-	ID         string    `json:"id"`
-	Type       FactType  `json:"type"`
-	Subject    string    `json:"subject"`
-	Predicate  string    `json:"predicate"`
-	Object     string    `json:"object"`
-	Context    string    `json:"context"`
-	SourceFile string    `json:"source_file"`
-	SourceLine int       `json:"source_line"`
-	Confidence float64   `json:"confidence"`
-	Embedding  []float32 `json:"embedding,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         string     `json:"id"`
+	Type       FactType   `json:"type"`
+	Subject    string     `json:"subject"`
+	Predicate  string     `json:"predicate"`
+	Object     string     `json:"object"`
+	Context    string     `json:"context"`
+	SourceFile string     `json:"source_file"`
+	SourceLine int        `json:"source_line"`
+	SourcePage int        `json:"source_page,omitempty"` // Page of a paged source, such as a PDF (0 = unpaged)
+	Confidence float64    `json:"confidence"`
+	Status     FactStatus `json:"status,omitempty"`
+	Embedding  []float32  `json:"embedding,omitempty"` // Triple plus context
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// TextEmbedding embeds the subject, predicate, and object alone, so
+	// searches about phrasing are not diluted by situational context.
+	// Empty means the same as Embedding.
+	TextEmbedding []float32 `json:"text_embedding,omitempty"`
+
+	// Corroboration is the number of distinct source files asserting
+	// roughly the same fact, this one's included. Zero means it has not
+	// been counted; see SourceCount.
+	Corroboration int `json:"corroboration,omitempty"`
+
+	// ObjectType is the kind of value Object holds, detected when the
+	// fact is extracted or imported. Empty means it was never detected;
+	// see ObjectValue.
+	ObjectType ObjectType `json:"object_type,omitempty"`
+
+	// KnownBy lists the IDs of the entities that know the fact. Empty
+	// means common knowledge; see KnownTo.
+	KnownBy []string `json:"known_by,omitempty"`
+
+	// AssertedBy names who states the fact: the character speaking it in
+	// dialogue, or the narrator of the source. Empty means the text itself.
+	AssertedBy string `json:"asserted_by,omitempty"`
+
+	// Claim marks a statement AssertedBy makes that is not canon, such as
+	// a character's opinion or an unreliable narrator's account. Claims
+	// are stored but do not contradict canon facts; see IsClaim.
+	Claim bool `json:"claim,omitempty"`
+
+	// Metadata holds organizational fields the fact inherits from its
+	// source, such as the book or point-of-view character; see
+	// services.SourceRules.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Tags label the fact, such as "draft", inherited from its source like
+	// Metadata.
+	Tags []string `json:"tags,omitempty"`
+
+	// Archived marks a fact from an archived source: kept for provenance,
+	// but its source is not ingested or replaced again.
+	Archived bool `json:"archived,omitempty"`
+
+	// Draft marks a speculative fact captured while brainstorming. Drafts
+	// are stored and searchable, but are neither checked for
+	// contradictions nor checked against until promoted; see IsDraft.
+	Draft bool `json:"draft,omitempty"`
+
+	// ExpiresAt is when a draft lapses. Expired drafts are left out of
+	// searches until purged. Zero means the draft does not expire.
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// IsPending reports whether the fact is awaiting review.
+func (f *Fact) IsPending() bool {
+	return f.Status == FactStatusPending
 }
+
+// ClaimKey identifies what a fact states about its subject: the subject
+// and predicate, normalized. Facts with the same claim key but different
+// objects are the likeliest contradictions.
+func (f *Fact) ClaimKey() string {
+	return NormalizeName(f.Subject) + "|" + NormalizeName(f.Predicate)
+}
+
+// IsDraft reports whether the fact is a draft rather than canon.
+func (f *Fact) IsDraft() bool {
+	return f.Draft
+}
+
+// IsExpired reports whether the fact is a draft that lapsed by now.
+func (f *Fact) IsExpired(now time.Time) bool {
+	return f.Draft && !f.ExpiresAt.IsZero() && !now.Before(f.ExpiresAt)
+}
+
+// IsClaim reports whether the fact is a claim rather than canon.
+func (f *Fact) IsClaim() bool {
+	return f.Claim
+}
+
+// KnownTo reports whether entity knows the fact. Common knowledge is known
+// to everyone, and an entity always knows the facts it is the subject or
+// object of; other facts are known only to the entities in KnownBy.
+func (f *Fact) KnownTo(entity *Entity) bool {
+	if len(f.KnownBy) == 0 {
+		return true
+	}
+	if entity.ID != "" && slices.Contains(f.KnownBy, entity.ID) {
+		return true
+	}
+	name := NormalizeName(entity.Name)
+	return name != "" && (NormalizeName(f.Subject) == name || NormalizeName(f.Object) == name)
+}
+
+// SourceCount returns the number of sources asserting the fact, counting
+// facts never corroborated as asserted by their own source alone.
+func (f *Fact) SourceCount() int {
+	return max(f.Corroboration, 1)
+}
+
+// Revision identifies the state of a fact's fields, so a writer can tell
+// whether the fact changed since it was read. Embeddings and timestamps are
+// left out: they are derived, and stores may keep timestamps less precisely
+// than they were set.
+func (f *Fact) Revision() string {
+	fact := *f
+	fact.Embedding, fact.TextEmbedding = nil, nil
+	fact.CreatedAt, fact.UpdatedAt = time.Time{}, time.Time{}
+	data, _ := json.Marshal(fact) // A Fact always encodes
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// StaleFactError is returned when an update was based on a revision of a
+// fact that is no longer current, so applying it would overwrite someone
+// else's change. It holds both versions so the writer can resolve the
+// conflict. It is an ErrConflict.
+type StaleFactError struct {
+	Revision  string // Revision the update was based on
+	Current   Fact   // The fact as stored
+	Attempted Fact   // The fact as the update would have left it
+}
+
+func (e *StaleFactError) Error() string {
+	return fmt.Sprintf("fact %s was changed since revision %s (now %s)", e.Current.ID, e.Revision, e.Current.Revision())
+}
+
+func (e *StaleFactError) Unwrap() error { return ErrConflict }
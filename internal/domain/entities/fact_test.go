@@ -2,6 +2,7 @@ package entities
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -15,3 +16,67 @@ func TestFactTypeConstants(t *testing.T) {
 	assert.Equal(t, FactType("rule"), FactTypeRule)
 	assert.Equal(t, FactType("timeline"), FactTypeTimeline)
 }
+
+func TestFact_IsPending(t *testing.T) {
+	tests := []struct {
+		name   string
+		status FactStatus
+		want   bool
+	}{
+		{"unset", "", false},
+		{"active", FactStatusActive, false},
+		{"pending", FactStatusPending, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := Fact{Status: tt.status}
+			assert.Equal(t, tt.want, fact.IsPending())
+		})
+	}
+}
+
+func TestFact_KnownTo(t *testing.T) {
+	frodo := &Entity{ID: "e1", Name: "Frodo"}
+	tests := []struct {
+		name string
+		fact Fact
+		want bool
+	}{
+		{"common knowledge", Fact{Subject: "Sauron", Object: "the Ring"}, true},
+		{"listed", Fact{Subject: "Sauron", Object: "the Ring", KnownBy: []string{"e2", "e1"}}, true},
+		{"not listed", Fact{Subject: "Gollum", Object: "Shelob", KnownBy: []string{"e2"}}, false},
+		{"subject", Fact{Subject: "frodo", Object: "Sting", KnownBy: []string{"e2"}}, true},
+		{"object", Fact{Subject: "Bilbo", Object: "Frodo", KnownBy: []string{"e2"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.fact.KnownTo(frodo))
+		})
+	}
+}
+
+func TestFact_ClaimKey(t *testing.T) {
+	a := Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}
+	b := Fact{Subject: " frodo", Predicate: "Lives_In", Object: "Rivendell"}
+	c := Fact{Subject: "Frodo", Predicate: "born_in", Object: "Bag End"}
+
+	assert.Equal(t, "frodo|lives_in", a.ClaimKey())
+	assert.Equal(t, a.ClaimKey(), b.ClaimKey(), "the object is not part of the claim")
+	assert.NotEqual(t, a.ClaimKey(), c.ClaimKey())
+}
+
+func TestFact_Revision(t *testing.T) {
+	fact := Fact{ID: "1", Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}
+	revision := fact.Revision()
+
+	stored := fact
+	stored.Embedding = []float32{0.1}
+	stored.UpdatedAt = time.Now()
+	assert.Equal(t, revision, stored.Revision(), "embeddings and timestamps are left out")
+
+	edited := fact
+	edited.Object = "Rivendell"
+	assert.NotEqual(t, revision, edited.Revision())
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// FailedChunk is a chunk of a source whose facts could not be extracted,
+// such as when the LLM call failed or returned unparseable JSON. It is
+// quarantined so the rest of the source can be ingested and the chunk
+// retried later.
+type FailedChunk struct {
+	ID           string    `json:"id"`
+	Source       string    `json:"source"`
+	Index        int       `json:"index"`                   // Zero-based position of the chunk in the source
+	Page         int       `json:"page,omitempty"`          // Page of a paged source the chunk is on (0 = unpaged)
+	Text         string    `json:"text"`                    // The chunk as sent to the LLM
+	PriorContext string    `json:"prior_context,omitempty"` // Summary of the text before the chunk, if carried
+	Error        string    `json:"error"`                   // Why the latest attempt failed
+	Attempts     int       `json:"attempts"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// HealthSample is a measurement of a world's consistency at a point in time.
+type HealthSample struct {
+	ID                 int64     `json:"id"`
+	RecordedAt         time.Time `json:"recorded_at"`
+	Facts              int       `json:"facts"`
+	OpenConflicts      int       `json:"open_conflicts"`
+	ConflictingFacts   int       `json:"conflicting_facts"` // Facts in at least one open conflict
+	LowConfidenceFacts int       `json:"low_confidence_facts"`
+	StaleFacts         int       `json:"stale_facts"`
+	CorroboratedFacts  int       `json:"corroborated_facts"` // Facts asserted by at least two sources
+	Entities           int       `json:"entities"`
+	OrphanEntities     int       `json:"orphan_entities"`
+	Score              float64   `json:"score"` // 0 (messy) to 100 (clean)
+}
@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// IdempotencyRecord is the outcome of an operation run with an idempotency
+// key, so a retry with the same key returns it instead of running again.
+type IdempotencyRecord struct {
+	Key         string          `json:"key"`
+	Operation   string          `json:"operation"`    // e.g. "ingest", "import", "relate"
+	RequestHash string          `json:"request_hash"` // Tells a retry from a different request reusing the key
+	Result      json.RawMessage `json:"result"`
+	Pending     bool            `json:"pending"` // Claimed by an operation still running; Result is not set yet
+	CreatedAt   time.Time       `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+// RawFact is a fact read from an import file, before it is validated.
+type RawFact struct {
+	ID         string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Type       string   `json:"type" yaml:"type"`
+	Subject    string   `json:"subject" yaml:"subject"`
+	Predicate  string   `json:"predicate" yaml:"predicate"`
+	Object     string   `json:"object" yaml:"object"`
+	Context    string   `json:"context,omitempty" yaml:"context,omitempty"`
+	SourceFile string   `json:"source_file,omitempty" yaml:"source_file,omitempty"`
+	Confidence *float64 `json:"confidence,omitempty" yaml:"confidence,omitempty"` // Pointer to distinguish 0 from unset
+	LineNum    int      `json:"-" yaml:"-"`                                       // Line number in source file (set by parser)
+}
+
+// RawEntity is an entity declared in an import document.
+type RawEntity struct {
+	Name    string `json:"name" yaml:"name"`
+	LineNum int    `json:"-" yaml:"-"` // Line number in source file (set by parser)
+}
+
+// RawRelationship is a relationship declared in an import document.
+// Source and Target are entity names.
+type RawRelationship struct {
+	Source        string `json:"source" yaml:"source"`
+	Type          string `json:"type" yaml:"type"`
+	Target        string `json:"target" yaml:"target"`
+	Bidirectional bool   `json:"bidirectional,omitempty" yaml:"bidirectional,omitempty"`
+	LineNum       int    `json:"-" yaml:"-"` // Line number in source file (set by parser)
+}
+
+// ImportDocument is a complete import document, such as a world bible,
+// with optional facts, entities, and relationships sections. A file
+// holding a bare list of facts is an ImportDocument with only Facts set.
+type ImportDocument struct {
+	Facts         []RawFact         `json:"facts,omitempty" yaml:"facts,omitempty"`
+	Entities      []RawEntity       `json:"entities,omitempty" yaml:"entities,omitempty"`
+	Relationships []RawRelationship `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+}
+
+// IsEmpty reports whether the document has nothing to import.
+func (d *ImportDocument) IsEmpty() bool {
+	return len(d.Facts) == 0 && len(d.Entities) == 0 && len(d.Relationships) == 0
+}
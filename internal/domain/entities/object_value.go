@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ObjectType is the kind of value a fact's object holds.
+type ObjectType string
+
+const (
+	// ObjectTypeText objects are free text, compared only by the LLM.
+	ObjectTypeText ObjectType = "text"
+	// ObjectTypeNumber objects are a number, optionally followed by a unit,
+	// such as "30" or "6 feet".
+	ObjectTypeNumber ObjectType = "number"
+	// ObjectTypeDate objects are a calendar date, such as "1418-09-22" or
+	// "September 22, 1418".
+	ObjectTypeDate ObjectType = "date"
+	// ObjectTypeBoolean objects are true, false, yes, or no.
+	ObjectTypeBoolean ObjectType = "boolean"
+)
+
+// maxUnitWords is the most words after a number read as its unit, so
+// "30 years old" is a number but "3 rings of power" is text.
+const maxUnitWords = 2
+
+// dateLayouts are the date formats objects are read in.
+var dateLayouts = []string{
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// ObjectValue is a fact's object read as a typed value, so contradictions
+// between values can be found without the LLM.
+type ObjectValue struct {
+	Type   ObjectType
+	Number float64   // For ObjectTypeNumber
+	Unit   string    // Lowercase words after the number, if any
+	Date   time.Time // For ObjectTypeDate, at midnight UTC
+	Bool   bool      // For ObjectTypeBoolean
+}
+
+// ParseObjectValue reads object as a boolean, a date, or a number with an
+// optional unit. Anything else is text.
+func ParseObjectValue(object string) ObjectValue {
+	s := strings.TrimSpace(object)
+
+	switch strings.ToLower(s) {
+	case "true", "yes":
+		return ObjectValue{Type: ObjectTypeBoolean, Bool: true}
+	case "false", "no":
+		return ObjectValue{Type: ObjectTypeBoolean, Bool: false}
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return ObjectValue{Type: ObjectTypeDate, Date: t}
+		}
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 1+maxUnitWords {
+		return ObjectValue{Type: ObjectTypeText}
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(fields[0], ",", ""), 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return ObjectValue{Type: ObjectTypeText}
+	}
+	return ObjectValue{
+		Type:   ObjectTypeNumber,
+		Number: n,
+		Unit:   strings.ToLower(strings.Join(fields[1:], " ")),
+	}
+}
+
+// IsTyped reports whether the value is a number, date, or boolean.
+func (v ObjectValue) IsTyped() bool {
+	return v.Type != "" && v.Type != ObjectTypeText
+}
+
+// Contradicts reports whether v and other are typed values of the same
+// type that differ. Numbers are compared when they have the same unit, or
+// known units of the same dimension; see measurementsContradict.
+func (v ObjectValue) Contradicts(other ObjectValue) bool {
+	if !v.IsTyped() || v.Type != other.Type {
+		return false
+	}
+	switch v.Type {
+	case ObjectTypeNumber:
+		if contradicts, ok := measurementsContradict(v, other); ok {
+			return contradicts
+		}
+		return v.Unit == other.Unit && v.Number != other.Number
+	case ObjectTypeDate:
+		return !v.Date.Equal(other.Date)
+	default:
+		return v.Bool != other.Bool
+	}
+}
+
+// ObjectValue reads the fact's object as a typed value. Objects detected
+// as text stay text.
+func (f *Fact) ObjectValue() ObjectValue {
+	if f.ObjectType == ObjectTypeText {
+		return ObjectValue{Type: ObjectTypeText}
+	}
+	return ParseObjectValue(f.Object)
+}
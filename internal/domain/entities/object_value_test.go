@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObjectValue(t *testing.T) {
+	shireReckoning := time.Date(1418, time.September, 22, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		object string
+		want   ObjectValue
+	}{
+		{"integer", "33", ObjectValue{Type: ObjectTypeNumber, Number: 33}},
+		{"decimal with unit", "4.5 feet", ObjectValue{Type: ObjectTypeNumber, Number: 4.5, Unit: "feet"}},
+		{"thousands separator", "1,000 Orcs", ObjectValue{Type: ObjectTypeNumber, Number: 1000, Unit: "orcs"}},
+		{"two-word unit", "111 Years Old", ObjectValue{Type: ObjectTypeNumber, Number: 111, Unit: "years old"}},
+		{"too many words", "3 rings of power", ObjectValue{Type: ObjectTypeText}},
+		{"infinity is text", "Inf", ObjectValue{Type: ObjectTypeText}},
+		{"iso date", "1418-09-22", ObjectValue{Type: ObjectTypeDate, Date: shireReckoning}},
+		{"written date", "September 22, 1418", ObjectValue{Type: ObjectTypeDate, Date: shireReckoning}},
+		{"day first date", "22 sep 1418", ObjectValue{Type: ObjectTypeDate, Date: shireReckoning}},
+		{"yes", " Yes ", ObjectValue{Type: ObjectTypeBoolean, Bool: true}},
+		{"false", "false", ObjectValue{Type: ObjectTypeBoolean}},
+		{"text", "Bag End", ObjectValue{Type: ObjectTypeText}},
+		{"empty", "", ObjectValue{Type: ObjectTypeText}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseObjectValue(tt.object))
+		})
+	}
+}
+
+func TestObjectValue_Contradicts(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"different numbers", "33", "50", true},
+		{"same number written differently", "1,000", "1000.0", false},
+		{"different dimensions", "6 feet", "6 days", false},
+		{"unknown units", "6 feet", "6 ponies", false},
+		{"same unknown unit", "6 ponies", "7 ponies", true},
+		{"converted height agrees", "6 feet", "183 cm", false},
+		{"rounded conversion agrees", "6 ft", "1.8 m", false},
+		{"converted height differs", "6 feet", "6 meters", true},
+		{"same unit spelled differently", "6 ft", "6 feet", false},
+		{"same unit compared exactly", "111 years", "112 yrs", true},
+		{"converted duration agrees", "2 weeks", "14 days", false},
+		{"converted distance differs", "3 leagues", "5 miles", true},
+		{"different dates", "1418-09-22", "September 23, 1418", true},
+		{"same date", "1418-09-22", "22 September 1418", false},
+		{"different booleans", "yes", "false", true},
+		{"number and date", "1418", "1418-09-22", false},
+		{"text never contradicts", "Bag End", "Rivendell", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseObjectValue(tt.a).Contradicts(ParseObjectValue(tt.b)))
+		})
+	}
+}
+
+func TestFact_ObjectValue(t *testing.T) {
+	undetected := Fact{Object: "33"}
+	assert.Equal(t, ObjectTypeNumber, undetected.ObjectValue().Type)
+
+	text := Fact{Object: "33", ObjectType: ObjectTypeText}
+	assert.False(t, text.ObjectValue().IsTyped())
+}
+
+func TestObjectValue_Measure(t *testing.T) {
+	dimension, amount, ok := ParseObjectValue("6 feet").Measure()
+	assert.True(t, ok)
+	assert.Equal(t, DimensionLength, dimension)
+	assert.InDelta(t, 1.8288, amount, 1e-9)
+
+	_, _, ok = ParseObjectValue("6 ponies").Measure()
+	assert.False(t, ok)
+	_, _, ok = ParseObjectValue("Bag End").Measure()
+	assert.False(t, ok)
+}
@@ -18,6 +18,18 @@ const (
 	RelationCreated   RelationType = "created"
 )
 
+// IsValid reports whether the relationship type is a known type.
+func (t RelationType) IsValid() bool {
+	switch t {
+	case RelationParent, RelationChild, RelationSibling, RelationSpouse,
+		RelationAlly, RelationEnemy, RelationLocatedIn, RelationOwns,
+		RelationMemberOf, RelationCreated:
+		return true
+	default:
+		return false
+	}
+}
+
 // Relationship represents a directed connection between two entities.
 type Relationship struct {
 	ID             string       `json:"id"`
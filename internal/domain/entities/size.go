@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes. It is written and parsed with binary
+// units, so 1MB and 1MiB are both 1,048,576 bytes.
+type ByteSize int64
+
+// byteUnits maps each unit suffix, upper-cased, to its size.
+var byteUnits = map[string]ByteSize{
+	"":    1,
+	"B":   1,
+	"K":   1 << 10,
+	"KB":  1 << 10,
+	"KIB": 1 << 10,
+	"M":   1 << 20,
+	"MB":  1 << 20,
+	"MIB": 1 << 20,
+	"G":   1 << 30,
+	"GB":  1 << 30,
+	"GIB": 1 << 30,
+}
+
+// ParseByteSize parses a size such as "512", "64KB", "1.5 MiB", or "2G".
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	end := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(trimmed)
+	}
+
+	n, err := strconv.ParseFloat(trimmed[:end], 64)
+	unit, ok := byteUnits[strings.ToUpper(strings.TrimSpace(trimmed[end:]))]
+	if err != nil || !ok || n < 0 {
+		return 0, Errorf(ErrValidation, "invalid size %q (use bytes or a unit: 512KB, 64MB, 1GB)", s)
+	}
+	return ByteSize(n * float64(unit)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so config files can
+// give sizes with units.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// String renders the size with a binary unit, e.g. "1.5 MiB".
+func (b ByteSize) String() string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := ByteSize(unit), 0
+	for m := b / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
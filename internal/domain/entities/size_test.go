@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		name string
+		n    ByteSize
+		want string
+	}{
+		{"bytes", 512, "512 B"},
+		{"kibibytes", 1536, "1.5 KiB"},
+		{"mebibytes", 5 * 1024 * 1024, "5.0 MiB"},
+		{"gibibytes", 3 * 1024 * 1024 * 1024, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.n.String())
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+	}{
+		{"512", 512},
+		{"512B", 512},
+		{"64KB", 64 << 10},
+		{"1.5 MiB", 3 << 19},
+		{"2g", 2 << 30},
+		{" 10 mb ", 10 << 20},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "MB", "ten MB", "5TB", "-1MB", "1.2.3K"} {
+		_, err := ParseByteSize(in)
+		assert.ErrorIs(t, err, ErrValidation, in)
+	}
+}
+
+func TestByteSize_UnmarshalText(t *testing.T) {
+	var b ByteSize
+	require.NoError(t, b.UnmarshalText([]byte("8MB")))
+	assert.Equal(t, ByteSize(8<<20), b)
+	assert.Error(t, b.UnmarshalText([]byte("lots")))
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// Snapshot is a point-in-time backup of a world: a snapshot of its vector
+// collection plus a copy of its relational database.
+type Snapshot struct {
+	Name           string    `json:"name"`
+	World          string    `json:"world"`
+	VectorSnapshot string    `json:"vector_snapshot"` // Name of the vector collection snapshot
+	RelationalPath string    `json:"relational_path"` // Path of the relational database backup
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Recorded when the snapshot is taken, so the backup can be verified
+	// later. Snapshots taken by older versions do not have them.
+	Checksum      string         `json:"checksum,omitempty"`       // SHA-256 of the relational backup, hex encoded
+	SchemaVersion int            `json:"schema_version,omitempty"` // Relational schema version of the backup
+	Counts        map[string]int `json:"counts,omitempty"`         // Records per relational table
+}
+
+// BackupInspection describes a relational backup restored into a temporary
+// database.
+type BackupInspection struct {
+	Checksum      string         // SHA-256 of the backup file, hex encoded
+	Opened        bool           // Whether the copy opened as a database
+	SchemaVersion int            // Schema version the backup was written with
+	Integrity     []string       // Problems found by the integrity check; empty if none
+	Counts        map[string]int // Records per table after the test restore
+	RestoreErr    error          // Why the test restore failed, if it did
+}
+
+// OK reports whether the backup passed the integrity check and restored.
+func (b *BackupInspection) OK() bool {
+	return len(b.Integrity) == 0 && b.RestoreErr == nil
+}
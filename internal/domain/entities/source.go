@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// SourceStats records how long a source was and how many facts were
+// extracted from it when it was last ingested.
+type SourceStats struct {
+	Source     string    `json:"source"`
+	Words      int       `json:"words"`
+	Facts      int       `json:"facts"`
+	IngestedAt time.Time `json:"ingested_at"`
+}
+
+// ArchivedSource is a source whose facts are kept but which is not
+// ingested or replaced again, such as a superseded draft.
+type ArchivedSource struct {
+	Source     string    `json:"source"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// Density returns the facts extracted per 1000 words, or 0 for a source
+// without words.
+func (s *SourceStats) Density() float64 {
+	if s.Words == 0 {
+		return 0
+	}
+	return float64(s.Facts) / float64(s.Words) * 1000
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// StyleTerm is a style sheet entry: the canonical spelling of an invented
+// term, including its capitalization and hyphenation.
+type StyleTerm struct {
+	Term      string    `json:"term"`
+	Key       string    `json:"key"`              // StyleKey of Term
+	Source    string    `json:"source,omitempty"` // Where the spelling was established
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StyleKey folds a term to the form its variant spellings share, ignoring
+// case, spaces, and hyphens, so "Dragon Lord", "dragon-lord", and
+// "Dragonlord" all have the same key.
+func StyleKey(term string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+		return r
+	}, NormalizeName(term))
+}
+
+// StyleIssue is a spelling in a fact that differs from the style sheet.
+type StyleIssue struct {
+	Fact  Fact      `json:"fact"`
+	Found string    `json:"found"` // The spelling used in the fact
+	Term  StyleTerm `json:"term"`
+}
+
+// Suggestion describes the issue, such as "'Dragonlord' previously spelled
+// 'Dragon Lord' in chapter2".
+func (i *StyleIssue) Suggestion() string {
+	if i.Term.Source == "" {
+		return fmt.Sprintf("'%s' is spelled '%s' in the style sheet", i.Found, i.Term.Term)
+	}
+	return fmt.Sprintf("'%s' previously spelled '%s' in %s", i.Found, i.Term.Term, i.Term.Source)
+}
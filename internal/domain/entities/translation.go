@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// Translation links two facts that state the same thing in different
+// languages, so they are not treated as duplicates or contradictions.
+// The pair is stored in ID order, so each pair is linked once.
+type Translation struct {
+	FactID      string    `json:"fact_id"`
+	OtherFactID string    `json:"other_fact_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
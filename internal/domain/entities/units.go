@@ -0,0 +1,92 @@
+package entities
+
+import "math"
+
+// Dimension is the physical quantity a unit measures.
+type Dimension string
+
+const (
+	// DimensionLength units measure heights and distances, in meters.
+	DimensionLength Dimension = "length"
+	// DimensionDuration units measure spans of time, in seconds.
+	DimensionDuration Dimension = "duration"
+)
+
+// unitTolerance is how far apart, as a fraction of the larger, two
+// measurements in different units may be and still agree, so "6 feet"
+// matches "183 cm" and "1.8 m" despite rounding in the conversion.
+const unitTolerance = 0.03
+
+// unit is a known unit of measure.
+type unit struct {
+	name      string // Canonical name, shared by every spelling
+	dimension Dimension
+	factor    float64 // Base units per unit
+}
+
+const (
+	secondsPerDay  = 24 * 60 * 60
+	secondsPerYear = 365.25 * secondsPerDay
+)
+
+// units maps each spelling of a known unit to it, pre-computed at package
+// init.
+var units = func() map[string]unit {
+	m := make(map[string]unit)
+	for _, u := range []struct {
+		unit
+		spellings []string
+	}{
+		{unit{"mm", DimensionLength, 0.001}, []string{"mm", "millimeter", "millimeters", "millimetre", "millimetres"}},
+		{unit{"cm", DimensionLength, 0.01}, []string{"cm", "centimeter", "centimeters", "centimetre", "centimetres"}},
+		{unit{"m", DimensionLength, 1}, []string{"m", "meter", "meters", "metre", "metres"}},
+		{unit{"km", DimensionLength, 1000}, []string{"km", "kilometer", "kilometers", "kilometre", "kilometres"}},
+		{unit{"in", DimensionLength, 0.0254}, []string{"in", "inch", "inches"}},
+		{unit{"ft", DimensionLength, 0.3048}, []string{"ft", "foot", "feet"}},
+		{unit{"yd", DimensionLength, 0.9144}, []string{"yd", "yard", "yards"}},
+		{unit{"mi", DimensionLength, 1609.344}, []string{"mi", "mile", "miles"}},
+		{unit{"league", DimensionLength, 4828.032}, []string{"league", "leagues"}},
+		{unit{"s", DimensionDuration, 1}, []string{"s", "sec", "secs", "second", "seconds"}},
+		{unit{"min", DimensionDuration, 60}, []string{"min", "mins", "minute", "minutes"}},
+		{unit{"h", DimensionDuration, 60 * 60}, []string{"h", "hr", "hrs", "hour", "hours"}},
+		{unit{"day", DimensionDuration, secondsPerDay}, []string{"day", "days"}},
+		{unit{"week", DimensionDuration, 7 * secondsPerDay}, []string{"week", "weeks"}},
+		{unit{"month", DimensionDuration, secondsPerYear / 12}, []string{"month", "months"}},
+		{unit{"year", DimensionDuration, secondsPerYear}, []string{"year", "years", "years old", "yr", "yrs"}},
+	} {
+		for _, s := range u.spellings {
+			m[s] = u.unit
+		}
+	}
+	return m
+}()
+
+// Measure returns the value's dimension and its amount in the dimension's
+// base unit. ok is false for numbers without a known unit.
+func (v ObjectValue) Measure() (dimension Dimension, amount float64, ok bool) {
+	if v.Type != ObjectTypeNumber {
+		return "", 0, false
+	}
+	u, ok := units[v.Unit]
+	if !ok {
+		return "", 0, false
+	}
+	return u.dimension, v.Number * u.factor, true
+}
+
+// measurementsContradict compares numbers in units of the same dimension,
+// exactly in the same unit and within unitTolerance across units. ok is
+// false when either unit is unknown or the dimensions differ.
+func measurementsContradict(a, b ObjectValue) (contradicts, ok bool) {
+	ua, okA := units[a.Unit]
+	ub, okB := units[b.Unit]
+	if !okA || !okB || ua.dimension != ub.dimension {
+		return false, false
+	}
+	if ua.name == ub.name {
+		return a.Number != b.Number, true
+	}
+
+	x, y := a.Number*ua.factor, b.Number*ub.factor
+	return math.Abs(x-y) > unitTolerance*math.Max(math.Abs(x), math.Abs(y)), true
+}
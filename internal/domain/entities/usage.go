@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// UsageRecord is the tokens one call to a paid API used, and what it cost.
+type UsageRecord struct {
+	ID               int64     `json:"id"`
+	RecordedAt       time.Time `json:"recorded_at"`
+	Backend          string    `json:"backend"` // "llm" or "embedder"
+	Model            string    `json:"model"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	Cost             float64   `json:"cost"` // USD, estimated from the configured prices
+}
+
+// UsageTotals sums the usage of one backend and model over a period.
+type UsageTotals struct {
+	Backend          string  `json:"backend"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
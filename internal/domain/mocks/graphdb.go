@@ -0,0 +1,44 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// GraphDB is a mock implementation of ports.GraphDB.
+type GraphDB struct {
+	Rows []map[string]any
+	Err  error
+
+	// Call tracking
+	SyncedWorld         string
+	SyncedEntities      []*entities.Entity
+	SyncedRelationships []entities.Relationship
+	Queries             []string
+}
+
+// SyncGraph records the graph it is given.
+func (m *GraphDB) SyncGraph(_ context.Context, worldID string, ents []*entities.Entity, rels []entities.Relationship) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.SyncedWorld = worldID
+	m.SyncedEntities = ents
+	m.SyncedRelationships = rels
+	return nil
+}
+
+// Query records the query and returns Rows.
+func (m *GraphDB) Query(_ context.Context, query string, _ map[string]any) ([]map[string]any, error) {
+	m.Queries = append(m.Queries, query)
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	return m.Rows, nil
+}
+
+// Close does nothing.
+func (m *GraphDB) Close() error {
+	return nil
+}
@@ -3,6 +3,7 @@ package mocks
 
 import (
 	"context"
+	"sync"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
@@ -18,16 +19,52 @@ type LLMClient struct {
 	Issues         []ports.ConsistencyIssue
 	ConsistencyErr error
 
+	// ExtractFocused return values by focus
+	FocusedFacts map[ports.ExtractionFocus][]entities.Fact
+
+	// SummarizeChunk return values (nil = the text itself is the summary)
+	Summarize    func(summary, text string) string
+	SummarizeErr error
+
+	// ResolveCoreferences return values
+	Coreferences []ports.CoreferenceResolution
+	CorefErr     error
+
+	// LabelTopic return values (nil = the first fact's subject is the label)
+	Label    func(facts []entities.Fact) string
+	LabelErr error
+
+	// ConfirmTranslations return values (nil = no pair is a translation)
+	Translations   func(pairs []ports.FactPair) []int
+	TranslationErr error
+
 	// Call tracking
 	ExtractFactsCallCount      int
 	ExtractFactsLastText       string
 	ExtractFactsLastValidTypes []string
+	ExtractFactsPriorContexts  []string
+	SummarizeCallCount         int
+	ExtractFocusedCalls        []ports.ExtractionFocus
 	CheckConsistencyCallCount  int
+	CheckConsistencyLastNew    []entities.Fact
+	CheckConsistencyLastOld    []entities.Fact
+	ResolveCorefCallCount      int
+	ResolveCorefLastFacts      []entities.Fact
+	LabelTopicCallCount        int
+	ConfirmTranslationsPairs   [][]ports.FactPair
+
+	mu sync.Mutex // Guards consistency call tracking, as checks run concurrently
 }
 
 // ExtractFacts returns the configured facts or error.
 func (m *LLMClient) ExtractFacts(ctx context.Context, text string, validTypes []string) ([]entities.Fact, error) {
+	return m.ExtractFactsWithContext(ctx, text, "", validTypes)
+}
+
+// ExtractFactsWithContext returns the configured facts or error and records the prior context.
+func (m *LLMClient) ExtractFactsWithContext(ctx context.Context, text string, priorContext string, validTypes []string) ([]entities.Fact, error) {
 	m.ExtractFactsCallCount++
+	m.ExtractFactsPriorContexts = append(m.ExtractFactsPriorContexts, priorContext)
 	m.ExtractFactsLastText = text
 	m.ExtractFactsLastValidTypes = validTypes
 	if m.ExtractErr != nil {
@@ -38,9 +75,71 @@ func (m *LLMClient) ExtractFacts(ctx context.Context, text string, validTypes []
 
 // CheckConsistency returns the configured issues or error.
 func (m *LLMClient) CheckConsistency(ctx context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.CheckConsistencyCallCount++
+	m.CheckConsistencyLastNew = newFacts
+	m.CheckConsistencyLastOld = existingFacts
 	if m.ConsistencyErr != nil {
 		return nil, m.ConsistencyErr
 	}
 	return m.Issues, nil
 }
+
+// ExtractFocused returns the configured facts for the focus or error.
+func (m *LLMClient) ExtractFocused(ctx context.Context, text string, priorContext string, focus ports.ExtractionFocus, validTypes []string) ([]entities.Fact, error) {
+	m.ExtractFocusedCalls = append(m.ExtractFocusedCalls, focus)
+	if m.ExtractErr != nil {
+		return nil, m.ExtractErr
+	}
+	return append([]entities.Fact(nil), m.FocusedFacts[focus]...), nil
+}
+
+// SummarizeChunk returns the configured summary or error.
+func (m *LLMClient) SummarizeChunk(ctx context.Context, summary string, text string) (string, error) {
+	m.SummarizeCallCount++
+	if m.SummarizeErr != nil {
+		return "", m.SummarizeErr
+	}
+	if m.Summarize != nil {
+		return m.Summarize(summary, text), nil
+	}
+	return text, nil
+}
+
+// ResolveCoreferences returns the configured resolutions or error.
+func (m *LLMClient) ResolveCoreferences(ctx context.Context, text string, facts []entities.Fact) ([]ports.CoreferenceResolution, error) {
+	m.ResolveCorefCallCount++
+	m.ResolveCorefLastFacts = facts
+	if m.CorefErr != nil {
+		return nil, m.CorefErr
+	}
+	return m.Coreferences, nil
+}
+
+// LabelTopic returns the configured label or error.
+func (m *LLMClient) LabelTopic(ctx context.Context, facts []entities.Fact) (string, error) {
+	m.LabelTopicCallCount++
+	if m.LabelErr != nil {
+		return "", m.LabelErr
+	}
+	if m.Label != nil {
+		return m.Label(facts), nil
+	}
+	if len(facts) == 0 {
+		return "", nil
+	}
+	return facts[0].Subject, nil
+}
+
+// ConfirmTranslations returns the configured indexes or error.
+func (m *LLMClient) ConfirmTranslations(ctx context.Context, pairs []ports.FactPair) ([]int, error) {
+	m.ConfirmTranslationsPairs = append(m.ConfirmTranslationsPairs, pairs)
+	if m.TranslationErr != nil {
+		return nil, m.TranslationErr
+	}
+	if m.Translations != nil {
+		return m.Translations(pairs), nil
+	}
+	return nil, nil
+}
@@ -2,16 +2,36 @@ package mocks
 
 import (
 	"context"
+	"slices"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
 // RelationalDB is a mock implementation of ports.RelationalDB.
 type RelationalDB struct {
-	Types    map[string]*entities.EntityType
-	Entities map[string]*entities.Entity
-	Err      error
+	Types         map[string]*entities.EntityType
+	Entities      map[string]*entities.Entity
+	Relationships []entities.Relationship
+	FactSubjects  []entities.FactSubject
+	Versions      []entities.FactVersion
+	Conflicts     []entities.Conflict
+	Translations  []entities.Translation
+	HealthSamples []entities.HealthSample
+	Verdicts      map[string]entities.ConsistencyVerdict
+	StyleTerms    []entities.StyleTerm
+	SourceStats   []entities.SourceStats
+	Archived      []entities.ArchivedSource
+	FailedChunks  []entities.FailedChunk
+	Usage         []entities.UsageRecord
+	Extractions   []entities.ExtractionStat
+	Idempotency   map[string]entities.IdempotencyRecord
+	Changes       []entities.Change
+	AuditLog      []entities.AuditEntry
+	Err           error
 }
 
 // NewRelationalDB creates a new mock RelationalDB.
@@ -120,7 +140,20 @@ func (m *RelationalDB) SearchEntities(_ context.Context, worldID, query string,
 	if m.Err != nil {
 		return nil, m.Err
 	}
-	return nil, nil
+	normalizedQuery := entities.NormalizeName(query)
+	var result []*entities.Entity
+	for _, e := range m.Entities {
+		if e.WorldID == worldID && strings.Contains(e.NormalizedName, normalizedQuery) {
+			result = append(result, e)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
 }
 
 // DeleteEntity deletes an entity by ID.
@@ -129,9 +162,98 @@ func (m *RelationalDB) DeleteEntity(_ context.Context, entityID string) error {
 		return m.Err
 	}
 	delete(m.Entities, entityID)
+	m.FactSubjects = slices.DeleteFunc(m.FactSubjects, func(link entities.FactSubject) bool {
+		return link.EntityID == entityID
+	})
+	return nil
+}
+
+// RenameEntity changes an entity's name and normalized name.
+func (m *RelationalDB) RenameEntity(_ context.Context, entityID, name string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	entity, ok := m.Entities[entityID]
+	if !ok {
+		return entities.Errorf(entities.ErrNotFound, "entity not found: %s", entityID)
+	}
+	entity.Name = name
+	entity.NormalizedName = entities.NormalizeName(name)
+	return nil
+}
+
+// MergeEntities moves an entity's relationships and fact subject links to
+// another and deletes it.
+func (m *RelationalDB) MergeEntities(_ context.Context, fromID, toID string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if _, ok := m.Entities[fromID]; !ok {
+		return entities.Errorf(entities.ErrNotFound, "entity not found: %s", fromID)
+	}
+	for i := range m.Relationships {
+		rel := &m.Relationships[i]
+		if rel.SourceEntityID == fromID {
+			rel.SourceEntityID = toID
+		}
+		if rel.TargetEntityID == fromID {
+			rel.TargetEntityID = toID
+		}
+	}
+	m.Relationships = slices.DeleteFunc(m.Relationships, func(rel entities.Relationship) bool {
+		return rel.SourceEntityID == toID && rel.TargetEntityID == toID
+	})
+	for i := range m.FactSubjects {
+		if m.FactSubjects[i].EntityID == fromID {
+			m.FactSubjects[i].EntityID = toID
+		}
+	}
+	delete(m.Entities, fromID)
+	return nil
+}
+
+// SaveFactSubjects links facts to entities, replacing earlier links.
+func (m *RelationalDB) SaveFactSubjects(_ context.Context, links []entities.FactSubject) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for _, link := range links {
+		i := slices.IndexFunc(m.FactSubjects, func(l entities.FactSubject) bool { return l.FactID == link.FactID })
+		if i >= 0 {
+			m.FactSubjects[i] = link
+		} else {
+			m.FactSubjects = append(m.FactSubjects, link)
+		}
+	}
+	return nil
+}
+
+// DeleteFactSubjects removes the links the filter selects.
+func (m *RelationalDB) DeleteFactSubjects(_ context.Context, filter ports.FactSubjectFilter) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.FactSubjects = slices.DeleteFunc(m.FactSubjects, func(link entities.FactSubject) bool {
+		return (len(filter.FactIDs) == 0 || slices.Contains(filter.FactIDs, link.FactID)) &&
+			(filter.SourceFile == "" || link.SourceFile == filter.SourceFile)
+	})
 	return nil
 }
 
+// ListFactIDsByEntities returns the IDs of facts linked to the entities.
+func (m *RelationalDB) ListFactIDsByEntities(_ context.Context, entityIDs []string, limit int) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var ids []string
+	for _, link := range m.FactSubjects {
+		if slices.Contains(entityIDs, link.EntityID) && len(ids) < limit {
+			ids = append(ids, link.FactID)
+		}
+	}
+	return ids, nil
+}
+
 // CountEntities returns the total number of entities for a world.
 func (m *RelationalDB) CountEntities(_ context.Context, worldID string) (int, error) {
 	if m.Err != nil {
@@ -190,16 +312,66 @@ func (m *RelationalDB) DeleteEntityType(_ context.Context, name string) error {
 	return nil
 }
 
-// Relationship methods - no-op implementations.
+// Relationship methods - saved relationships are kept in memory; queries
+// other than lookups and listings by entity are no-ops.
 
 // SaveRelationship saves or updates a relationship.
-func (m *RelationalDB) SaveRelationship(_ context.Context, _ *entities.Relationship) error {
-	return m.Err
+func (m *RelationalDB) SaveRelationship(_ context.Context, rel *entities.Relationship) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Relationships = append(m.Relationships, *rel)
+	return nil
 }
 
 // FindRelationshipsByEntity finds all relationships involving an entity.
-func (m *RelationalDB) FindRelationshipsByEntity(_ context.Context, _ string) ([]entities.Relationship, error) {
-	return nil, m.Err
+func (m *RelationalDB) FindRelationshipsByEntity(_ context.Context, entityID string) ([]entities.Relationship, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var result []entities.Relationship
+	for _, rel := range m.Relationships {
+		if rel.SourceEntityID == entityID || rel.TargetEntityID == entityID {
+			result = append(result, rel)
+		}
+	}
+	return result, nil
+}
+
+// ListRelationshipsByEntity lists relationships involving an entity, in
+// the order saved, up to opts.Limit. Other options are ignored.
+func (m *RelationalDB) ListRelationshipsByEntity(ctx context.Context, entityID string, opts ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	result, err := m.FindRelationshipsByEntity(ctx, entityID)
+	if opts.Limit > 0 && len(result) > opts.Limit {
+		result = result[:opts.Limit]
+	}
+	return result, err
+}
+
+// CountRelationshipsByEntity counts relationships involving an entity.
+func (m *RelationalDB) CountRelationshipsByEntity(_ context.Context, _ string, _ string) (int, error) {
+	return 0, m.Err
+}
+
+// CountRelationshipsPerEntity returns the relationship degree of every entity in a world.
+func (m *RelationalDB) CountRelationshipsPerEntity(_ context.Context, worldID string) (map[string]int, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	degrees := make(map[string]int)
+	for _, e := range m.Entities {
+		if e.WorldID == worldID {
+			degrees[e.ID] = 0
+		}
+	}
+	for _, rel := range m.Relationships {
+		for _, id := range []string{rel.SourceEntityID, rel.TargetEntityID} {
+			if _, ok := degrees[id]; ok {
+				degrees[id]++
+			}
+		}
+	}
+	return degrees, nil
 }
 
 // FindRelationshipsByType finds all relationships of a given type.
@@ -208,13 +380,25 @@ func (m *RelationalDB) FindRelationshipsByType(_ context.Context, _ string) ([]e
 }
 
 // DeleteRelationship deletes a relationship by ID.
-func (m *RelationalDB) DeleteRelationship(_ context.Context, _ string) error {
-	return m.Err
+func (m *RelationalDB) DeleteRelationship(_ context.Context, id string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Relationships = slices.DeleteFunc(m.Relationships, func(rel entities.Relationship) bool {
+		return rel.ID == id
+	})
+	return nil
 }
 
 // DeleteRelationshipsByEntity deletes all relationships involving an entity.
-func (m *RelationalDB) DeleteRelationshipsByEntity(_ context.Context, _ string) error {
-	return m.Err
+func (m *RelationalDB) DeleteRelationshipsByEntity(_ context.Context, entityID string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Relationships = slices.DeleteFunc(m.Relationships, func(rel entities.Relationship) bool {
+		return rel.SourceEntityID == entityID || rel.TargetEntityID == entityID
+	})
+	return nil
 }
 
 // FindRelationshipBetween finds a direct relationship between two entities.
@@ -222,9 +406,22 @@ func (m *RelationalDB) FindRelationshipBetween(_ context.Context, _, _ string) (
 	return nil, m.Err
 }
 
-// FindRelatedEntities finds all entity IDs connected to the given entity up to the specified depth.
-func (m *RelationalDB) FindRelatedEntities(_ context.Context, _ string, _ int) ([]string, error) {
-	return nil, m.Err
+// FindRelatedEntities returns the IDs of entities directly connected to the
+// given entity, whatever the depth.
+func (m *RelationalDB) FindRelatedEntities(_ context.Context, entityID string, depth int) ([]string, error) {
+	if m.Err != nil || depth < 1 {
+		return nil, m.Err
+	}
+	var ids []string
+	for _, r := range m.Relationships {
+		switch {
+		case r.SourceEntityID == entityID:
+			ids = append(ids, r.TargetEntityID)
+		case r.Bidirectional && r.TargetEntityID == entityID:
+			ids = append(ids, r.SourceEntityID)
+		}
+	}
+	return ids, nil
 }
 
 // CountRelationships returns the total number of relationships in the database.
@@ -232,33 +429,90 @@ func (m *RelationalDB) CountRelationships(_ context.Context) (int, error) {
 	return 0, m.Err
 }
 
-// Version methods - no-op implementations.
+// Version methods.
 
 // SaveVersion saves a new fact version.
-func (m *RelationalDB) SaveVersion(_ context.Context, _ *entities.FactVersion) error {
-	return m.Err
+func (m *RelationalDB) SaveVersion(_ context.Context, version *entities.FactVersion) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Versions = append(m.Versions, *version)
+	return nil
 }
 
-// FindVersionsByFact finds all versions of a fact.
-func (m *RelationalDB) FindVersionsByFact(_ context.Context, _ string) ([]entities.FactVersion, error) {
-	return nil, m.Err
+// FindVersionsByFact finds all versions of a fact, ordered by version descending.
+func (m *RelationalDB) FindVersionsByFact(_ context.Context, factID string) ([]entities.FactVersion, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var versions []entities.FactVersion
+	for i := range m.Versions {
+		if m.Versions[i].FactID == factID {
+			versions = append(versions, m.Versions[i])
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
 }
 
 // FindLatestVersion finds the most recent version of a fact.
-func (m *RelationalDB) FindLatestVersion(_ context.Context, _ string) (*entities.FactVersion, error) {
-	return nil, m.Err
+func (m *RelationalDB) FindLatestVersion(ctx context.Context, factID string) (*entities.FactVersion, error) {
+	versions, err := m.FindVersionsByFact(ctx, factID)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	return &versions[0], nil
 }
 
 // CountVersions counts how many versions a fact has.
-func (m *RelationalDB) CountVersions(_ context.Context, _ string) (int, error) {
-	return 0, m.Err
+func (m *RelationalDB) CountVersions(ctx context.Context, factID string) (int, error) {
+	versions, err := m.FindVersionsByFact(ctx, factID)
+	return len(versions), err
+}
+
+// ListVersionsAsOf returns the latest version at or before asOf of each
+// fact changed after asOf.
+func (m *RelationalDB) ListVersionsAsOf(_ context.Context, asOf time.Time) ([]entities.FactVersion, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	changed := make(map[string]bool)
+	for i := range m.Versions {
+		if m.Versions[i].CreatedAt.After(asOf) {
+			changed[m.Versions[i].FactID] = true
+		}
+	}
+
+	latest := make(map[string]int)
+	var versions []entities.FactVersion
+	for i := range m.Versions {
+		v := &m.Versions[i]
+		if v.CreatedAt.After(asOf) || !changed[v.FactID] {
+			continue
+		}
+		if j, ok := latest[v.FactID]; ok {
+			if v.Version > versions[j].Version {
+				versions[j] = *v
+			}
+			continue
+		}
+		latest[v.FactID] = len(versions)
+		versions = append(versions, *v)
+	}
+	return versions, nil
 }
 
 // Audit log methods - no-op implementations.
 
 // LogAction logs an action to the audit log.
-func (m *RelationalDB) LogAction(_ context.Context, _ string, _ string, _ map[string]any) error {
-	return m.Err
+func (m *RelationalDB) LogAction(_ context.Context, action string, factID string, details map[string]any) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.AuditLog = append(m.AuditLog, entities.AuditEntry{Action: action, FactID: factID, Details: details})
+	return nil
 }
 
 // FindAuditLog finds audit log entries for a specific fact.
@@ -270,3 +524,449 @@ func (m *RelationalDB) FindAuditLog(_ context.Context, _ string) ([]entities.Aud
 func (m *RelationalDB) FindAuditLogByAction(_ context.Context, _ string, _ int) ([]entities.AuditEntry, error) {
 	return nil, m.Err
 }
+
+// SaveConflict records a conflict unless its pair is already recorded.
+func (m *RelationalDB) SaveConflict(_ context.Context, conflict *entities.Conflict) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Conflicts {
+		if m.Conflicts[i].FactID == conflict.FactID && m.Conflicts[i].OtherFactID == conflict.OtherFactID {
+			return false, nil
+		}
+	}
+	m.Conflicts = append(m.Conflicts, *conflict)
+	return true, nil
+}
+
+// ListConflicts lists conflicts matching opts, newest first.
+func (m *RelationalDB) ListConflicts(_ context.Context, opts ports.ConflictListOptions) ([]entities.Conflict, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var conflicts []entities.Conflict
+	for i := len(m.Conflicts) - 1; i >= 0; i-- {
+		c := m.Conflicts[i]
+		if opts.Status != "" && c.Status != opts.Status {
+			continue
+		}
+		if opts.FactID != "" && !c.Involves(opts.FactID) {
+			continue
+		}
+		conflicts = append(conflicts, c)
+		if opts.Limit > 0 && len(conflicts) == opts.Limit {
+			break
+		}
+	}
+	return conflicts, nil
+}
+
+// ResolveConflict marks a conflict as resolved.
+func (m *RelationalDB) ResolveConflict(_ context.Context, id string, resolution string, resolvedAt time.Time) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range m.Conflicts {
+		if m.Conflicts[i].ID == id {
+			m.Conflicts[i].Status = entities.ConflictResolved
+			m.Conflicts[i].Resolution = resolution
+			m.Conflicts[i].ResolvedAt = resolvedAt
+			return nil
+		}
+	}
+	return entities.Errorf(entities.ErrNotFound, "conflict not found: %s", id)
+}
+
+// CountOpenConflicts counts open conflicts per fact.
+func (m *RelationalDB) CountOpenConflicts(_ context.Context, factIDs []string) (map[string]int, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	counts := make(map[string]int)
+	for i := range m.Conflicts {
+		if m.Conflicts[i].Status != entities.ConflictOpen {
+			continue
+		}
+		for _, id := range factIDs {
+			if m.Conflicts[i].Involves(id) {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// SaveTranslation links a pair of facts unless it is already linked.
+func (m *RelationalDB) SaveTranslation(_ context.Context, translation *entities.Translation) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	for i := range m.Translations {
+		if m.Translations[i].FactID == translation.FactID && m.Translations[i].OtherFactID == translation.OtherFactID {
+			return false, nil
+		}
+	}
+	m.Translations = append(m.Translations, *translation)
+	return true, nil
+}
+
+// ListTranslations lists links involving any of factIDs, or all links.
+func (m *RelationalDB) ListTranslations(_ context.Context, factIDs []string) ([]entities.Translation, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if len(factIDs) == 0 {
+		return m.Translations, nil
+	}
+	var translations []entities.Translation
+	for i := range m.Translations {
+		t := m.Translations[i]
+		if slices.Contains(factIDs, t.FactID) || slices.Contains(factIDs, t.OtherFactID) {
+			translations = append(translations, t)
+		}
+	}
+	return translations, nil
+}
+
+// SaveHealthSample records a health sample and sets its ID.
+func (m *RelationalDB) SaveHealthSample(_ context.Context, sample *entities.HealthSample) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	sample.ID = int64(len(m.HealthSamples) + 1)
+	m.HealthSamples = append(m.HealthSamples, *sample)
+	return nil
+}
+
+// ListHealthSamples returns the last limit health samples, oldest first.
+func (m *RelationalDB) ListHealthSamples(_ context.Context, limit int) ([]entities.HealthSample, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	samples := m.HealthSamples
+	if limit > 0 && len(samples) > limit {
+		samples = samples[len(samples)-limit:]
+	}
+	return samples, nil
+}
+
+// FindConsistencyVerdicts returns the stored verdicts with the given keys
+// checked at or after since.
+func (m *RelationalDB) FindConsistencyVerdicts(_ context.Context, keys []string, since time.Time) (map[string]entities.ConsistencyVerdict, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	found := make(map[string]entities.ConsistencyVerdict)
+	for _, key := range keys {
+		if v, ok := m.Verdicts[key]; ok && !v.CheckedAt.Before(since) {
+			found[key] = v
+		}
+	}
+	return found, nil
+}
+
+// SaveConsistencyVerdicts stores verdicts by key.
+func (m *RelationalDB) SaveConsistencyVerdicts(_ context.Context, verdicts []entities.ConsistencyVerdict) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Verdicts == nil {
+		m.Verdicts = make(map[string]entities.ConsistencyVerdict)
+	}
+	for _, v := range verdicts {
+		m.Verdicts[v.Key] = v
+	}
+	return nil
+}
+
+// PruneConsistencyVerdicts deletes verdicts checked before cutoff.
+func (m *RelationalDB) PruneConsistencyVerdicts(_ context.Context, cutoff time.Time) (int, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	pruned := 0
+	for key, v := range m.Verdicts {
+		if v.CheckedAt.Before(cutoff) {
+			delete(m.Verdicts, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
+
+// SaveStyleTerm stores a style sheet entry, replacing any with the same key.
+func (m *RelationalDB) SaveStyleTerm(_ context.Context, term *entities.StyleTerm) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range m.StyleTerms {
+		if m.StyleTerms[i].Key == term.Key {
+			m.StyleTerms[i] = *term
+			return nil
+		}
+	}
+	m.StyleTerms = append(m.StyleTerms, *term)
+	return nil
+}
+
+// ListStyleTerms returns the style sheet, ordered by term.
+func (m *RelationalDB) ListStyleTerms(_ context.Context) ([]entities.StyleTerm, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	terms := slices.Clone(m.StyleTerms)
+	slices.SortFunc(terms, func(a, b entities.StyleTerm) int { return strings.Compare(a.Term, b.Term) })
+	return terms, nil
+}
+
+// SaveSourceStats records a source's latest ingest, replacing any earlier
+// record of the same source.
+func (m *RelationalDB) SaveSourceStats(_ context.Context, stats *entities.SourceStats) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range m.SourceStats {
+		if m.SourceStats[i].Source == stats.Source {
+			m.SourceStats[i] = *stats
+			return nil
+		}
+	}
+	m.SourceStats = append(m.SourceStats, *stats)
+	return nil
+}
+
+// ListSourceStats returns the recorded sources, ordered by source.
+func (m *RelationalDB) ListSourceStats(_ context.Context) ([]entities.SourceStats, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	sources := slices.Clone(m.SourceStats)
+	slices.SortFunc(sources, func(a, b entities.SourceStats) int { return strings.Compare(a.Source, b.Source) })
+	return sources, nil
+}
+
+// SaveArchivedSource records a source as archived, replacing any earlier
+// record of the same source.
+func (m *RelationalDB) SaveArchivedSource(_ context.Context, source *entities.ArchivedSource) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.Archived = slices.DeleteFunc(m.Archived, func(a entities.ArchivedSource) bool { return a.Source == source.Source })
+	m.Archived = append(m.Archived, *source)
+	return nil
+}
+
+// DeleteArchivedSource removes a source's archived record.
+func (m *RelationalDB) DeleteArchivedSource(_ context.Context, source string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	i := slices.IndexFunc(m.Archived, func(a entities.ArchivedSource) bool { return a.Source == source })
+	if i < 0 {
+		return entities.Errorf(entities.ErrNotFound, "source is not archived: %s", source)
+	}
+	m.Archived = slices.Delete(m.Archived, i, i+1)
+	return nil
+}
+
+// ListArchivedSources returns the archived sources, ordered by source.
+func (m *RelationalDB) ListArchivedSources(_ context.Context) ([]entities.ArchivedSource, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	sources := slices.Clone(m.Archived)
+	slices.SortFunc(sources, func(a, b entities.ArchivedSource) int { return strings.Compare(a.Source, b.Source) })
+	return sources, nil
+}
+
+// SaveFailedChunk stores a quarantined chunk, replacing any with the same ID.
+func (m *RelationalDB) SaveFailedChunk(_ context.Context, chunk *entities.FailedChunk) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range m.FailedChunks {
+		if m.FailedChunks[i].ID == chunk.ID {
+			m.FailedChunks[i] = *chunk
+			return nil
+		}
+	}
+	m.FailedChunks = append(m.FailedChunks, *chunk)
+	return nil
+}
+
+// ListFailedChunks returns the quarantined chunks, ordered by source and
+// position.
+func (m *RelationalDB) ListFailedChunks(_ context.Context) ([]entities.FailedChunk, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	chunks := slices.Clone(m.FailedChunks)
+	slices.SortFunc(chunks, func(a, b entities.FailedChunk) int {
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return a.Index - b.Index
+	})
+	return chunks, nil
+}
+
+// DeleteFailedChunk removes a quarantined chunk.
+func (m *RelationalDB) DeleteFailedChunk(_ context.Context, id string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	n := len(m.FailedChunks)
+	m.FailedChunks = slices.DeleteFunc(m.FailedChunks, func(c entities.FailedChunk) bool { return c.ID == id })
+	if len(m.FailedChunks) == n {
+		return entities.Errorf(entities.ErrNotFound, "failed chunk not found: %s", id)
+	}
+	return nil
+}
+
+// SaveUsage records a call's API usage and sets its ID.
+func (m *RelationalDB) SaveUsage(_ context.Context, record *entities.UsageRecord) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	record.ID = int64(len(m.Usage) + 1)
+	m.Usage = append(m.Usage, *record)
+	return nil
+}
+
+// SumUsage totals the usage recorded at or after since, by backend and model.
+func (m *RelationalDB) SumUsage(_ context.Context, since time.Time) ([]entities.UsageTotals, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var totals []entities.UsageTotals
+	for _, r := range m.Usage {
+		if r.RecordedAt.Before(since) {
+			continue
+		}
+		i := slices.IndexFunc(totals, func(t entities.UsageTotals) bool {
+			return t.Backend == r.Backend && t.Model == r.Model
+		})
+		if i < 0 {
+			totals = append(totals, entities.UsageTotals{Backend: r.Backend, Model: r.Model})
+			i = len(totals) - 1
+		}
+		totals[i].Calls++
+		totals[i].PromptTokens += r.PromptTokens
+		totals[i].CompletionTokens += r.CompletionTokens
+		totals[i].Cost += r.Cost
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Backend != totals[j].Backend {
+			return totals[i].Backend < totals[j].Backend
+		}
+		return totals[i].Model < totals[j].Model
+	})
+	return totals, nil
+}
+
+// SaveExtractionStat records how an extraction call went and sets its ID.
+func (m *RelationalDB) SaveExtractionStat(_ context.Context, stat *entities.ExtractionStat) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	stat.ID = int64(len(m.Extractions) + 1)
+	m.Extractions = append(m.Extractions, *stat)
+	return nil
+}
+
+// SumExtractionStats totals the extraction calls recorded, by model.
+func (m *RelationalDB) SumExtractionStats(_ context.Context) ([]entities.ModelStats, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var totals []entities.ModelStats
+	for _, s := range m.Extractions {
+		i := slices.IndexFunc(totals, func(t entities.ModelStats) bool { return t.Model == s.Model })
+		if i < 0 {
+			totals = append(totals, entities.ModelStats{Model: s.Model})
+			i = len(totals) - 1
+		}
+		totals[i].Calls++
+		if s.ParseFailed {
+			totals[i].ParseFailures++
+		}
+		totals[i].Facts += s.Facts
+		totals[i].ConfidenceSum += s.ConfidenceSum
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Model < totals[j].Model })
+	return totals, nil
+}
+
+// FindIdempotencyRecord returns the record of an idempotency key, or nil.
+func (m *RelationalDB) FindIdempotencyRecord(_ context.Context, key string) (*entities.IdempotencyRecord, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	record, ok := m.Idempotency[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// SaveIdempotencyRecord stores a record, replacing any with the same key.
+func (m *RelationalDB) SaveIdempotencyRecord(_ context.Context, record *entities.IdempotencyRecord) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Idempotency == nil {
+		m.Idempotency = make(map[string]entities.IdempotencyRecord)
+	}
+	m.Idempotency[record.Key] = *record
+	return nil
+}
+
+// ClaimIdempotencyKey stores record unless its key has a record created
+// after cutoff.
+func (m *RelationalDB) ClaimIdempotencyKey(ctx context.Context, record *entities.IdempotencyRecord, cutoff time.Time) (bool, error) {
+	if m.Err != nil {
+		return false, m.Err
+	}
+	if existing, ok := m.Idempotency[record.Key]; ok && existing.CreatedAt.After(cutoff) {
+		return false, nil
+	}
+	return true, m.SaveIdempotencyRecord(ctx, record)
+}
+
+// ReleaseIdempotencyKey deletes the record of a key if it is pending.
+func (m *RelationalDB) ReleaseIdempotencyKey(_ context.Context, key string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	if m.Idempotency[key].Pending {
+		delete(m.Idempotency, key)
+	}
+	return nil
+}
+
+// RecordChanges appends changes to the change feed, setting each one's
+// Seq. Relationship changes are not recorded.
+func (m *RelationalDB) RecordChanges(_ context.Context, changes []entities.Change) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range changes {
+		changes[i].Seq = int64(len(m.Changes) + 1)
+		m.Changes = append(m.Changes, changes[i])
+	}
+	return nil
+}
+
+// ListChanges returns up to limit changes after seq after, oldest first.
+func (m *RelationalDB) ListChanges(_ context.Context, after int64, limit int) ([]entities.Change, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var changes []entities.Change
+	for _, c := range m.Changes {
+		if c.Seq > after && len(changes) < limit {
+			changes = append(changes, c)
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,128 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// SnapshotStorage is a mock implementation of ports.VectorSnapshotter,
+// ports.RelationalBackup, and ports.SnapshotStore backed by memory.
+type SnapshotStorage struct {
+	mu             sync.Mutex
+	Snapshots      map[string]entities.Snapshot
+	VectorNames    map[string]bool
+	BackupPaths    []string
+	BackupErr      error
+	Inspection     *entities.BackupInspection // Returned by InspectBackup; a passing inspection if nil
+	InspectErr     error
+	vectorSequence int
+}
+
+// CreateSnapshot records a new vector snapshot.
+func (m *SnapshotStorage) CreateSnapshot(_ context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.VectorNames == nil {
+		m.VectorNames = make(map[string]bool)
+	}
+	m.vectorSequence++
+	name := fmt.Sprintf("vector-%d", m.vectorSequence)
+	m.VectorNames[name] = true
+	return name, nil
+}
+
+// DeleteSnapshot removes a vector snapshot.
+func (m *SnapshotStorage) DeleteSnapshot(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.VectorNames, name)
+	return nil
+}
+
+// ListSnapshots returns the names of the vector snapshots.
+func (m *SnapshotStorage) ListSnapshots(_ context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.VectorNames))
+	for name := range m.VectorNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// BackupTo records the backup path or returns BackupErr.
+func (m *SnapshotStorage) BackupTo(_ context.Context, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.BackupErr != nil {
+		return m.BackupErr
+	}
+	m.BackupPaths = append(m.BackupPaths, path)
+	return nil
+}
+
+// InspectBackup returns Inspection or InspectErr.
+func (m *SnapshotStorage) InspectBackup(_ context.Context, _ string) (*entities.BackupInspection, error) {
+	if m.InspectErr != nil {
+		return nil, m.InspectErr
+	}
+	if m.Inspection != nil {
+		return m.Inspection, nil
+	}
+	return &entities.BackupInspection{Checksum: "checksum", Opened: true, SchemaVersion: 1, Counts: map[string]int{}}, nil
+}
+
+// SchemaVersion returns 1.
+func (m *SnapshotStorage) SchemaVersion() int {
+	return 1
+}
+
+// BackupPath returns a fake backup path for a snapshot.
+func (m *SnapshotStorage) BackupPath(name string) string {
+	return "/snapshots/" + name + ".db"
+}
+
+// Save records a snapshot.
+func (m *SnapshotStorage) Save(_ context.Context, snapshot *entities.Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.Snapshots == nil {
+		m.Snapshots = make(map[string]entities.Snapshot)
+	}
+	m.Snapshots[snapshot.Name] = *snapshot
+	return nil
+}
+
+// List returns recorded snapshots, newest first.
+func (m *SnapshotStorage) List(_ context.Context) ([]entities.Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	list := make([]entities.Snapshot, 0, len(m.Snapshots))
+	for _, snapshot := range m.Snapshots {
+		list = append(list, snapshot)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.After(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+// Delete removes a snapshot record.
+func (m *SnapshotStorage) Delete(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.Snapshots, name)
+	return nil
+}
@@ -2,9 +2,12 @@ package mocks
 
 import (
 	"context"
-	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
 // VectorDB is a mock implementation of ports.VectorDB.
@@ -12,6 +15,9 @@ type VectorDB struct {
 	Facts []entities.Fact
 	Err   error
 
+	// VectorResults overrides SearchVector results per vector name.
+	VectorResults map[ports.VectorName][]entities.Fact
+
 	// Collection errors (separate from Err for fine-grained control)
 	EnsureCollectionErr error
 	DeleteCollectionErr error
@@ -23,6 +29,9 @@ type VectorDB struct {
 	EnsureCollectionCallCount int
 	DeleteCollectionCallCount int
 	FindByIDCallCount         int
+	SavedFacts                []entities.Fact // Facts passed to Save
+	DeletedIDs                []string        // IDs passed to Delete
+	DeletedSources            []string        // Sources passed to DeleteBySource
 }
 
 // EnsureCollection creates the collection if it doesn't exist.
@@ -39,6 +48,7 @@ func (m *VectorDB) DeleteCollection(ctx context.Context) error {
 
 // Save stores a single fact.
 func (m *VectorDB) Save(ctx context.Context, fact *entities.Fact) error {
+	m.SavedFacts = append(m.SavedFacts, *fact)
 	return m.Err
 }
 
@@ -64,7 +74,7 @@ func (m *VectorDB) FindByID(ctx context.Context, id string) (entities.Fact, erro
 	if m.FindByIDErr != nil {
 		return entities.Fact{}, m.FindByIDErr
 	}
-	return entities.Fact{}, fmt.Errorf("fact not found: %s", id)
+	return entities.Fact{}, entities.Errorf(entities.ErrNotFound, "fact not found: %s", id)
 }
 
 // ExistsByIDs checks which IDs exist in the mock database.
@@ -129,19 +139,110 @@ func (m *VectorDB) SearchByType(ctx context.Context, embedding []float32, factTy
 	return filtered[:limit], nil
 }
 
+// SearchBySubject finds facts by embedding and exact subject.
+func (m *VectorDB) SearchBySubject(ctx context.Context, embedding []float32, subject string, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var filtered []entities.Fact
+	for i := range m.Facts {
+		if m.Facts[i].Subject == subject {
+			filtered = append(filtered, m.Facts[i])
+		}
+	}
+	if limit > len(filtered) {
+		return filtered, nil
+	}
+	return filtered[:limit], nil
+}
+
+// SearchVector finds facts by embedding, optionally filtered by type.
+// Results come from VectorResults when set for the vector name.
+func (m *VectorDB) SearchVector(ctx context.Context, vector ports.VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if results, ok := m.VectorResults[vector]; ok {
+		return results, nil
+	}
+	if factType == "" {
+		return m.Search(ctx, embedding, limit)
+	}
+	return m.SearchByType(ctx, embedding, factType, limit)
+}
+
+// SearchKeywords returns VectorResults for the keywords vector when set,
+// otherwise facts whose subject or object contains the query.
+func (m *VectorDB) SearchKeywords(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if results, ok := m.VectorResults[ports.VectorKeywords]; ok {
+		return results, nil
+	}
+	var matched []entities.Fact
+	for i := range m.Facts {
+		if factType != "" && m.Facts[i].Type != factType {
+			continue
+		}
+		if strings.Contains(m.Facts[i].Subject, query) || strings.Contains(m.Facts[i].Object, query) {
+			matched = append(matched, m.Facts[i])
+		}
+	}
+	if limit < len(matched) {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
 // Delete removes a fact by ID.
 func (m *VectorDB) Delete(ctx context.Context, id string) error {
+	m.DeletedIDs = append(m.DeletedIDs, id)
 	return m.Err
 }
 
-// List returns facts with pagination.
+// List returns up to limit facts. As in Qdrant, offset names the ID of
+// the first fact rather than a count of facts to skip, so a count gives
+// the first page again.
 func (m *VectorDB) List(ctx context.Context, limit int, offset uint64) ([]entities.Fact, error) {
 	if m.Err != nil {
 		return nil, m.Err
 	}
+	if limit > 0 && len(m.Facts) > limit {
+		return m.Facts[:limit], nil
+	}
 	return m.Facts, nil
 }
 
+// ListPage returns a page of the facts matching the filters. As in
+// Qdrant, the cursor of the next page is the ID of its first fact.
+func (m *VectorDB) ListPage(ctx context.Context, opts ports.FactPageOptions) ([]entities.Fact, string, error) {
+	if m.Err != nil {
+		return nil, "", m.Err
+	}
+	var page []entities.Fact
+	for i := range m.Facts {
+		f := &m.Facts[i]
+		if (opts.Type == "" || f.Type == opts.Type) &&
+			(opts.SourceFile == "" || f.SourceFile == opts.SourceFile) &&
+			(!opts.Drafts || f.IsDraft()) {
+			page = append(page, *f)
+		}
+	}
+
+	if opts.Cursor != "" {
+		start := slices.IndexFunc(page, func(f entities.Fact) bool { return f.ID == opts.Cursor })
+		if start < 0 {
+			return nil, "", entities.Errorf(entities.ErrValidation, "invalid cursor %q", opts.Cursor)
+		}
+		page = page[start:]
+	}
+	if opts.Limit <= 0 || len(page) <= opts.Limit {
+		return page, "", nil
+	}
+	return page[:opts.Limit], page[opts.Limit].ID, nil
+}
+
 // ListByType returns facts filtered by type.
 func (m *VectorDB) ListByType(ctx context.Context, factType entities.FactType, limit int) ([]entities.Fact, error) {
 	if m.Err != nil {
@@ -156,6 +257,93 @@ func (m *VectorDB) ListByType(ctx context.Context, factType entities.FactType, l
 	return filtered, nil
 }
 
+// ListFiltered returns facts matching the type and time range options.
+func (m *VectorDB) ListFiltered(ctx context.Context, opts ports.FactListOptions) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	filtered := make([]entities.Fact, 0, len(m.Facts))
+	for i := range m.Facts {
+		if opts.Type != "" && m.Facts[i].Type != opts.Type {
+			continue
+		}
+		ts := m.Facts[i].CreatedAt
+		if opts.Sort == ports.FactSortUpdated {
+			ts = m.Facts[i].UpdatedAt
+		}
+		if !opts.Since.IsZero() && ts.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && ts.After(opts.Until) {
+			continue
+		}
+		filtered = append(filtered, m.Facts[i])
+	}
+	if opts.Sort != "" {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if opts.Sort == ports.FactSortUpdated {
+				return filtered[i].UpdatedAt.After(filtered[j].UpdatedAt)
+			}
+			return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+		})
+	}
+	if opts.Limit > 0 && opts.Limit < len(filtered) {
+		filtered = filtered[:opts.Limit]
+	}
+	return filtered, nil
+}
+
+// ListPending returns facts awaiting review.
+func (m *VectorDB) ListPending(ctx context.Context, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var pending []entities.Fact
+	for i := range m.Facts {
+		if m.Facts[i].IsPending() {
+			pending = append(pending, m.Facts[i])
+		}
+	}
+	if limit > 0 && limit < len(pending) {
+		pending = pending[:limit]
+	}
+	return pending, nil
+}
+
+// ListBySubject returns active facts whose subject is one of the names.
+func (m *VectorDB) ListBySubject(ctx context.Context, subjects []string, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var filtered []entities.Fact
+	for i := range m.Facts {
+		if !m.Facts[i].IsPending() && slices.Contains(subjects, m.Facts[i].Subject) {
+			filtered = append(filtered, m.Facts[i])
+		}
+	}
+	if limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
+// ListByClaimKeys returns active facts whose claim key is one of keys.
+func (m *VectorDB) ListByClaimKeys(ctx context.Context, keys []string, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var filtered []entities.Fact
+	for i := range m.Facts {
+		if !m.Facts[i].IsPending() && slices.Contains(keys, m.Facts[i].ClaimKey()) {
+			filtered = append(filtered, m.Facts[i])
+		}
+	}
+	if limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
 // ListBySource returns facts filtered by source file.
 func (m *VectorDB) ListBySource(ctx context.Context, sourceFile string, limit int) ([]entities.Fact, error) {
 	if m.Err != nil {
@@ -170,11 +358,57 @@ func (m *VectorDB) ListBySource(ctx context.Context, sourceFile string, limit in
 	return filtered, nil
 }
 
+// ListByEntities returns facts whose subject or object is one of names.
+func (m *VectorDB) ListByEntities(ctx context.Context, names []string, limit int) ([]entities.Fact, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var filtered []entities.Fact
+	for i := range m.Facts {
+		if slices.Contains(names, m.Facts[i].Subject) || slices.Contains(names, m.Facts[i].Object) {
+			filtered = append(filtered, m.Facts[i])
+		}
+	}
+	if limit < len(filtered) {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
 // DeleteBySource removes all facts from a source file.
 func (m *VectorDB) DeleteBySource(ctx context.Context, sourceFile string) error {
+	m.DeletedSources = append(m.DeletedSources, sourceFile)
 	return m.Err
 }
 
+// SetSourceArchived marks or unmarks the facts from a source file as
+// archived.
+func (m *VectorDB) SetSourceArchived(ctx context.Context, sourceFile string, archived bool) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	for i := range m.Facts {
+		if m.Facts[i].SourceFile == sourceFile {
+			m.Facts[i].Archived = archived
+		}
+	}
+	return nil
+}
+
+// CountBySubject counts facts whose subject is one of the names.
+func (m *VectorDB) CountBySubject(ctx context.Context, subjects []string) (uint64, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	var count uint64
+	for i := range m.Facts {
+		if slices.Contains(subjects, m.Facts[i].Subject) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // DeleteAll removes all facts.
 func (m *VectorDB) DeleteAll(ctx context.Context) error {
 	return m.Err
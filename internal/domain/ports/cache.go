@@ -0,0 +1,35 @@
+package ports
+
+import "time"
+
+// CacheStats reports the effectiveness of an in-process cache.
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`     // Entries currently cached
+	Capacity  int    `json:"capacity"` // Maximum entries cached
+}
+
+// HitRate returns the fraction of lookups served from the cache, or 0 if
+// there were no lookups.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// ReplicaStatus reports how current a read replica's copy of a world is.
+type ReplicaStatus struct {
+	Facts    int       `json:"facts"`              // Facts in the copy
+	SyncedAt time.Time `json:"synced_at,omitzero"` // When the copy was taken
+	// AgeSeconds is how long ago the copy was taken, and
+	// MaxStalenessSeconds how old it may be before the replica stops
+	// answering (0 = no limit).
+	AgeSeconds          int64  `json:"age_seconds"`
+	MaxStalenessSeconds int64  `json:"max_staleness_seconds,omitempty"`
+	Stale               bool   `json:"stale"`
+	LastError           string `json:"last_error,omitempty"` // Of the latest failed sync
+}
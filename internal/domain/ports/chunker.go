@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"io"
+	"slices"
+)
+
+// Chunker splits text into the chunks facts are extracted from, one LLM
+// call each.
+type Chunker interface {
+	// Chunk reads text from r and calls emit with each chunk in order. It
+	// stops at the first error emit returns and returns it.
+	Chunk(r io.Reader, emit func(chunk string) error) error
+}
+
+// ChunkStrategy names a way of splitting text into chunks.
+type ChunkStrategy string
+
+const (
+	// ChunkParagraph packs whole paragraphs into each chunk, overlapping
+	// the end of the previous chunk. Suits prose.
+	ChunkParagraph ChunkStrategy = "paragraph"
+	// ChunkSentenceWindow packs whole sentences into each chunk,
+	// overlapping the last sentences of the previous chunk. Suits text with
+	// few paragraph breaks, such as transcripts.
+	ChunkSentenceWindow ChunkStrategy = "sentence-window"
+	// ChunkMarkdownHeading starts a chunk at each Markdown heading, so a
+	// section's facts are extracted together. Suits wikis and world bibles.
+	ChunkMarkdownHeading ChunkStrategy = "markdown-heading"
+	// ChunkFixedToken cuts chunks of a fixed number of tokens regardless of
+	// structure. Suits screenplays and other formats where lines are short
+	// and paragraphs say little.
+	ChunkFixedToken ChunkStrategy = "fixed-token"
+)
+
+// ChunkStrategies lists the supported chunking strategies.
+var ChunkStrategies = []ChunkStrategy{
+	ChunkParagraph, ChunkSentenceWindow, ChunkMarkdownHeading, ChunkFixedToken,
+}
+
+// IsValid reports whether the strategy is supported.
+func (s ChunkStrategy) IsValid() bool {
+	return slices.Contains(ChunkStrategies, s)
+}
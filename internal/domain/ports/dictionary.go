@@ -0,0 +1,9 @@
+package ports
+
+// Dictionary knows the ordinary words of a natural language, so terms
+// invented for a world can be told apart from them.
+type Dictionary interface {
+	// Contains reports whether word, in lowercase, is an ordinary word or
+	// an inflection of one.
+	Contains(word string) bool
+}
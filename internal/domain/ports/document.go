@@ -0,0 +1,20 @@
+package ports
+
+import "io"
+
+// DocumentPage is the text of one page of a document.
+type DocumentPage struct {
+	Number int // Page number, from 1 (0 = the document is not paged)
+	Text   string
+}
+
+// DocumentReader extracts the text of documents that are not plain text,
+// such as PDF and Word files, so they can be ingested like text.
+type DocumentReader interface {
+	// CanRead reports whether the reader extracts the text of the file
+	// with the given name, judged by its extension.
+	CanRead(name string) bool
+	// Read returns the text of the document in r, of size bytes, a page
+	// at a time. A document that is not paged is one page numbered 0.
+	Read(r io.ReaderAt, size int64) ([]DocumentPage, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// GraphDB defines the interface for an optional graph database that mirrors
+// a world's entities and relationships, for graph queries beyond what the
+// relational database's recursive queries can do.
+type GraphDB interface {
+	// SyncGraph makes the world's graph match the given entities and
+	// relationships, removing any the graph has that are not given.
+	SyncGraph(ctx context.Context, worldID string, entities []*entities.Entity, relationships []entities.Relationship) error
+
+	// Query runs a read-only query and returns its rows, keyed by column name.
+	Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error)
+
+	// Close releases the connection.
+	Close() error
+}
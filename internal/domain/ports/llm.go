@@ -3,6 +3,8 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 )
@@ -13,8 +15,75 @@ type LLMClient interface {
 	// validTypes specifies which entity types are valid for extraction.
 	ExtractFacts(ctx context.Context, text string, validTypes []string) ([]entities.Fact, error)
 
+	// ExtractFactsWithContext extracts facts from text, using priorContext (a
+	// summary of earlier text) only to understand who or what text refers to.
+	ExtractFactsWithContext(ctx context.Context, text string, priorContext string, validTypes []string) ([]entities.Fact, error)
+
+	// ExtractFocused runs an extraction pass specialized for one kind of
+	// fact, trading an extra call for higher recall on that kind.
+	ExtractFocused(ctx context.Context, text string, priorContext string, focus ExtractionFocus, validTypes []string) ([]entities.Fact, error)
+
+	// SummarizeChunk folds text into a running summary of the characters,
+	// places, and events introduced so far, and returns the new summary.
+	SummarizeChunk(ctx context.Context, summary string, text string) (string, error)
+
 	// CheckConsistency checks if new facts are consistent with existing facts.
 	CheckConsistency(ctx context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ConsistencyIssue, error)
+
+	// ResolveCoreferences finds the names that pronouns in facts refer to,
+	// using the text the facts were extracted from.
+	ResolveCoreferences(ctx context.Context, text string, facts []entities.Fact) ([]CoreferenceResolution, error)
+
+	// LabelTopic names the theme the given facts share in a few words.
+	LabelTopic(ctx context.Context, facts []entities.Fact) (string, error)
+
+	// ConfirmTranslations returns the indexes of the pairs whose facts state
+	// the same thing in different languages.
+	ConfirmTranslations(ctx context.Context, pairs []FactPair) ([]int, error)
+}
+
+// ErrMalformedResponse means the LLM answered, but with something that could
+// not be parsed, such as truncated JSON. It measures the model rather than
+// the connection to it.
+var ErrMalformedResponse = errors.New("malformed LLM response")
+
+// FactPair is two facts compared with each other.
+type FactPair struct {
+	Fact  entities.Fact
+	Other entities.Fact
+}
+
+// ExtractionFocus names a specialized extraction pass.
+type ExtractionFocus string
+
+const (
+	// FocusEvents looks for events and their order in time.
+	FocusEvents ExtractionFocus = "events"
+	// FocusRelationships looks for how characters, groups, and places relate.
+	FocusRelationships ExtractionFocus = "relationships"
+	// FocusRules looks for laws of the world: magic, physics, customs.
+	FocusRules ExtractionFocus = "rules"
+	// FocusCharacters looks for character traits, roles, and backgrounds.
+	FocusCharacters ExtractionFocus = "characters"
+	// FocusLocations looks for places, geography, and what is where.
+	FocusLocations ExtractionFocus = "locations"
+)
+
+// ExtractionFocuses lists the supported focus passes.
+var ExtractionFocuses = []ExtractionFocus{
+	FocusEvents, FocusRelationships, FocusRules, FocusCharacters, FocusLocations,
+}
+
+// IsValid reports whether the focus is supported.
+func (f ExtractionFocus) IsValid() bool {
+	return slices.Contains(ExtractionFocuses, f)
+}
+
+// CoreferenceResolution names what a fact's subject or object refers to.
+type CoreferenceResolution struct {
+	FactIndex int    `json:"index"`             // Index into the facts passed in
+	Subject   string `json:"subject,omitempty"` // Resolved subject (empty = unchanged)
+	Object    string `json:"object,omitempty"`  // Resolved object (empty = unchanged)
 }
 
 // ConsistencyIssue represents a detected inconsistency between facts.
@@ -0,0 +1,10 @@
+package ports
+
+import "context"
+
+// Notifier delivers messages to the people working on a world, such as by
+// email or to a chat webhook.
+type Notifier interface {
+	// Notify sends a message with a one-line subject and a plain-text body.
+	Notify(ctx context.Context, subject, body string) error
+}
@@ -2,10 +2,56 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
+// RelationshipSort defines the ordering of relationship listings.
+type RelationshipSort string
+
+const (
+	// RelationshipSortCreated orders by creation time, newest first (default).
+	RelationshipSortCreated RelationshipSort = "created"
+	// RelationshipSortType orders by relationship type, then newest first.
+	RelationshipSortType RelationshipSort = "type"
+	// RelationshipSortTarget orders by the name of the entity on the other end.
+	RelationshipSortTarget RelationshipSort = "target"
+)
+
+// IsValid reports whether the sort order is supported. Empty means default.
+func (s RelationshipSort) IsValid() bool {
+	switch s {
+	case "", RelationshipSortCreated, RelationshipSortType, RelationshipSortTarget:
+		return true
+	default:
+		return false
+	}
+}
+
+// RelationshipListOptions controls filtering, ordering, and pagination of
+// relationship listings.
+type RelationshipListOptions struct {
+	Type   string           // Filter by relationship type (empty = all)
+	Sort   RelationshipSort // Ordering (empty = created)
+	Limit  int              // Maximum results (0 = no limit)
+	Offset int              // Number of results to skip
+}
+
+// FactSubjectFilter selects fact subject links to delete. The zero value
+// selects every link.
+type FactSubjectFilter struct {
+	FactIDs    []string // Links of these facts (empty = any fact)
+	SourceFile string   // Links of facts from this source (empty = any source)
+}
+
+// ConflictListOptions controls filtering of conflict listings.
+type ConflictListOptions struct {
+	Status entities.ConflictStatus // Filter by status (empty = all)
+	FactID string                  // Only conflicts involving this fact (empty = all)
+	Limit  int                     // Maximum results (0 = no limit)
+}
+
 // RelationalDB defines the interface for relational database operations.
 // This interface handles data that requires transactions, relationships,
 // and complex queries - complementing VectorDB for semantic search.
@@ -42,9 +88,33 @@ type RelationalDB interface {
 	// DeleteEntity deletes an entity by ID.
 	DeleteEntity(ctx context.Context, entityID string) error
 
+	// RenameEntity changes an entity's name and the normalized name it is
+	// found by.
+	RenameEntity(ctx context.Context, entityID, name string) error
+
 	// CountEntities returns the total number of entities for a world.
 	CountEntities(ctx context.Context, worldID string) (int, error)
 
+	// MergeEntities moves every relationship and fact subject link of the
+	// entity fromID to the entity toID and deletes fromID. Relationships
+	// that would connect toID to itself or repeat one toID already has are
+	// dropped.
+	MergeEntities(ctx context.Context, fromID, toID string) error
+
+	// Fact subject operations
+
+	// SaveFactSubjects links facts to the entities they are about,
+	// replacing the facts' earlier links.
+	SaveFactSubjects(ctx context.Context, links []entities.FactSubject) error
+
+	// DeleteFactSubjects removes the links the filter selects. Deleting an
+	// entity removes its links too.
+	DeleteFactSubjects(ctx context.Context, filter FactSubjectFilter) error
+
+	// ListFactIDsByEntities returns the IDs of up to limit facts linked to
+	// any of the entities, in the order they were linked.
+	ListFactIDsByEntities(ctx context.Context, entityIDs []string, limit int) ([]string, error)
+
 	// Relationship operations
 
 	// SaveRelationship saves or updates a relationship.
@@ -54,6 +124,19 @@ type RelationalDB interface {
 	// Returns relationships where the entity is source, or target if bidirectional.
 	FindRelationshipsByEntity(ctx context.Context, entityID string) ([]entities.Relationship, error)
 
+	// ListRelationshipsByEntity lists relationships involving an entity with
+	// filtering, ordering, and pagination applied by the database.
+	ListRelationshipsByEntity(ctx context.Context, entityID string, opts RelationshipListOptions) ([]entities.Relationship, error)
+
+	// CountRelationshipsByEntity counts relationships involving an entity,
+	// optionally filtered by type (empty = all types).
+	CountRelationshipsByEntity(ctx context.Context, entityID string, relType string) (int, error)
+
+	// CountRelationshipsPerEntity returns the relationship degree of every
+	// entity in a world, keyed by entity ID: the number of relationships it
+	// takes part in as source or target. Entities without relationships map to zero.
+	CountRelationshipsPerEntity(ctx context.Context, worldID string) (map[string]int, error)
+
 	// FindRelationshipsByType finds all relationships of a given type.
 	FindRelationshipsByType(ctx context.Context, relType string) ([]entities.Relationship, error)
 
@@ -86,6 +169,11 @@ type RelationalDB interface {
 	// CountVersions counts how many versions a fact has.
 	CountVersions(ctx context.Context, factID string) (int, error)
 
+	// ListVersionsAsOf returns, for every fact changed or deleted after
+	// asOf, the latest version created at or before asOf. Facts whose
+	// history starts after asOf, and facts unchanged since, are omitted.
+	ListVersionsAsOf(ctx context.Context, asOf time.Time) ([]entities.FactVersion, error)
+
 	// SaveEntityType saves or updates a custom entity type.
 	SaveEntityType(ctx context.Context, entityType *entities.EntityType) error
 
@@ -106,4 +194,139 @@ type RelationalDB interface {
 
 	// FindAuditLogByAction finds audit log entries by action type.
 	FindAuditLogByAction(ctx context.Context, action string, limit int) ([]entities.AuditEntry, error)
+
+	// Conflict operations
+
+	// SaveConflict records a conflict. A pair of facts that is already
+	// recorded is left as is, so resolved conflicts stay resolved. It
+	// reports whether the conflict was new.
+	SaveConflict(ctx context.Context, conflict *entities.Conflict) (bool, error)
+
+	// ListConflicts lists conflicts, newest first.
+	ListConflicts(ctx context.Context, opts ConflictListOptions) ([]entities.Conflict, error)
+
+	// ResolveConflict marks a conflict as resolved.
+	ResolveConflict(ctx context.Context, id string, resolution string, resolvedAt time.Time) error
+
+	// CountOpenConflicts returns the number of open conflicts each of the
+	// given facts is part of. Facts without open conflicts are omitted.
+	CountOpenConflicts(ctx context.Context, factIDs []string) (map[string]int, error)
+
+	// Translation operations
+
+	// SaveTranslation links two facts as translations of each other. A pair
+	// that is already linked is left as is. It reports whether the link was new.
+	SaveTranslation(ctx context.Context, translation *entities.Translation) (bool, error)
+
+	// ListTranslations lists the translation links involving any of the
+	// given facts, or every link if no facts are given, oldest first.
+	ListTranslations(ctx context.Context, factIDs []string) ([]entities.Translation, error)
+
+	// Health operations
+
+	// SaveHealthSample records a health measurement and sets its ID.
+	SaveHealthSample(ctx context.Context, sample *entities.HealthSample) error
+
+	// ListHealthSamples returns the most recent health measurements,
+	// oldest first. A limit of 0 returns all of them.
+	ListHealthSamples(ctx context.Context, limit int) ([]entities.HealthSample, error)
+
+	// Consistency verdict operations
+
+	// FindConsistencyVerdicts returns the verdicts with the given keys
+	// checked at or after since, by key. Missing keys are left out.
+	FindConsistencyVerdicts(ctx context.Context, keys []string, since time.Time) (map[string]entities.ConsistencyVerdict, error)
+
+	// SaveConsistencyVerdicts stores verdicts, replacing any with the same key.
+	SaveConsistencyVerdicts(ctx context.Context, verdicts []entities.ConsistencyVerdict) error
+
+	// PruneConsistencyVerdicts deletes verdicts checked before cutoff and
+	// returns how many were deleted.
+	PruneConsistencyVerdicts(ctx context.Context, cutoff time.Time) (int, error)
+
+	// Style sheet operations
+
+	// SaveStyleTerm stores a style sheet entry, replacing any with the same key.
+	SaveStyleTerm(ctx context.Context, term *entities.StyleTerm) error
+
+	// ListStyleTerms returns the style sheet, ordered by term.
+	ListStyleTerms(ctx context.Context) ([]entities.StyleTerm, error)
+
+	// Source operations
+
+	// SaveSourceStats records a source's latest ingest, replacing any
+	// earlier record of the same source.
+	SaveSourceStats(ctx context.Context, stats *entities.SourceStats) error
+
+	// ListSourceStats returns the recorded sources, ordered by source.
+	ListSourceStats(ctx context.Context) ([]entities.SourceStats, error)
+
+	// SaveArchivedSource records a source as archived, replacing any
+	// earlier record of the same source.
+	SaveArchivedSource(ctx context.Context, source *entities.ArchivedSource) error
+
+	// DeleteArchivedSource removes a source's archived record. A source
+	// that is not archived returns an ErrNotFound error.
+	DeleteArchivedSource(ctx context.Context, source string) error
+
+	// ListArchivedSources returns the archived sources, ordered by source.
+	ListArchivedSources(ctx context.Context) ([]entities.ArchivedSource, error)
+
+	// SaveFailedChunk stores a quarantined chunk, replacing any with the
+	// same ID.
+	SaveFailedChunk(ctx context.Context, chunk *entities.FailedChunk) error
+
+	// ListFailedChunks returns the quarantined chunks, ordered by source
+	// and position.
+	ListFailedChunks(ctx context.Context) ([]entities.FailedChunk, error)
+
+	// DeleteFailedChunk removes a quarantined chunk.
+	DeleteFailedChunk(ctx context.Context, id string) error
+
+	// Usage operations
+
+	// SaveUsage records a call's API usage and sets its ID.
+	SaveUsage(ctx context.Context, record *entities.UsageRecord) error
+
+	// SumUsage totals the usage recorded at or after since, by backend and
+	// model, ordered by backend and model.
+	SumUsage(ctx context.Context, since time.Time) ([]entities.UsageTotals, error)
+
+	// Extraction statistics operations
+
+	// SaveExtractionStat records how an extraction call went and sets its ID.
+	SaveExtractionStat(ctx context.Context, stat *entities.ExtractionStat) error
+
+	// SumExtractionStats totals the extraction calls recorded, by model,
+	// ordered by model.
+	SumExtractionStats(ctx context.Context) ([]entities.ModelStats, error)
+
+	// Idempotency operations
+
+	// FindIdempotencyRecord returns the record of an idempotency key, or nil
+	// if the key has not been used.
+	FindIdempotencyRecord(ctx context.Context, key string) (*entities.IdempotencyRecord, error)
+
+	// SaveIdempotencyRecord stores a record, replacing any with the same key.
+	SaveIdempotencyRecord(ctx context.Context, record *entities.IdempotencyRecord) error
+
+	// ClaimIdempotencyKey stores record unless its key has a record created
+	// after cutoff, reporting whether it was stored. Records created at or
+	// before cutoff have expired and are replaced. Of concurrent claims of
+	// a key, only one succeeds.
+	ClaimIdempotencyKey(ctx context.Context, record *entities.IdempotencyRecord, cutoff time.Time) (bool, error)
+
+	// ReleaseIdempotencyKey deletes the record of a key if it is pending.
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+
+	// Change feed operations
+
+	// RecordChanges appends fact changes to the change feed, setting each
+	// one's Seq. Relationship changes are recorded by the store itself as
+	// relationships are saved and deleted.
+	RecordChanges(ctx context.Context, changes []entities.Change) error
+
+	// ListChanges returns up to limit changes recorded after the one with
+	// seq after, oldest first.
+	ListChanges(ctx context.Context, after int64, limit int) ([]entities.Change, error)
 }
@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// VectorSnapshotter creates and removes snapshots of a world's vector collection.
+type VectorSnapshotter interface {
+	// CreateSnapshot snapshots the collection and returns the snapshot name.
+	CreateSnapshot(ctx context.Context) (string, error)
+
+	// DeleteSnapshot removes a collection snapshot by name.
+	DeleteSnapshot(ctx context.Context, name string) error
+
+	// ListSnapshots returns the names of the collection's snapshots.
+	ListSnapshots(ctx context.Context) ([]string, error)
+}
+
+// RelationalBackup writes and checks copies of the relational database.
+type RelationalBackup interface {
+	// BackupTo writes a copy of the database to path, which must not exist.
+	BackupTo(ctx context.Context, path string) error
+
+	// InspectBackup checks the backup at path by restoring a copy of it into
+	// a temporary database. The backup itself is not modified. An error
+	// means the file could not be read; integrity and restore problems are
+	// reported in the inspection.
+	InspectBackup(ctx context.Context, path string) (*entities.BackupInspection, error)
+
+	// SchemaVersion returns the schema version this build reads and writes.
+	SchemaVersion() int
+}
+
+// SnapshotStore keeps the catalog of a world's snapshots.
+type SnapshotStore interface {
+	// BackupPath returns where the relational backup for a snapshot is written.
+	BackupPath(name string) string
+
+	// Save records a snapshot.
+	Save(ctx context.Context, snapshot *entities.Snapshot) error
+
+	// List returns all recorded snapshots, newest first.
+	List(ctx context.Context) ([]entities.Snapshot, error)
+
+	// Delete removes a snapshot record and its relational backup.
+	Delete(ctx context.Context, name string) error
+}
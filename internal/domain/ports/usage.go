@@ -0,0 +1,38 @@
+package ports
+
+import "context"
+
+// TokenUsage is the tokens one call to a paid API used.
+type TokenUsage struct {
+	Backend          string // "llm" or "embedder"
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageMeter tracks what calls to paid APIs cost, against a budget.
+type UsageMeter interface {
+	// Allow returns an error if no more calls should be made, such as
+	// when the budget is spent.
+	Allow(ctx context.Context) error
+
+	// Record records the tokens a call used.
+	Record(ctx context.Context, usage TokenUsage) error
+}
+
+type usageMeterKey struct{}
+
+// WithUsageMeter returns a context carrying meter, for clients shared by
+// several worlds to record their calls' usage against the calling world.
+func WithUsageMeter(ctx context.Context, meter UsageMeter) context.Context {
+	return context.WithValue(ctx, usageMeterKey{}, meter)
+}
+
+// RecordUsage records usage with the context's UsageMeter, if it has one.
+func RecordUsage(ctx context.Context, usage TokenUsage) error {
+	meter, ok := ctx.Value(usageMeterKey{}).(UsageMeter)
+	if !ok {
+		return nil
+	}
+	return meter.Record(ctx, usage)
+}
@@ -2,10 +2,71 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
+// VectorName selects which stored embedding a search compares against.
+type VectorName string
+
+const (
+	// VectorContext is the embedding of the fact triple plus its context.
+	VectorContext VectorName = "context"
+	// VectorText is the embedding of the fact triple alone.
+	VectorText VectorName = "text"
+	// VectorKeywords is the sparse BM25-style keyword vector of the fact.
+	VectorKeywords VectorName = "keywords"
+)
+
+// FactSort defines which timestamp a fact listing is ordered and filtered by.
+type FactSort string
+
+const (
+	// FactSortCreated orders by creation time, newest first.
+	FactSortCreated FactSort = "created"
+	// FactSortUpdated orders by last update time, newest first.
+	FactSortUpdated FactSort = "updated"
+)
+
+// IsValid reports whether the sort order is supported. Empty means unordered.
+func (s FactSort) IsValid() bool {
+	switch s {
+	case "", FactSortCreated, FactSortUpdated:
+		return true
+	default:
+		return false
+	}
+}
+
+// FactListOptions controls filtering and ordering of fact listings.
+// Since and Until apply to updated_at when Sort is FactSortUpdated,
+// and to created_at otherwise. Zero times leave the range open.
+type FactListOptions struct {
+	Type  entities.FactType // Filter by fact type (empty = all)
+	Since time.Time         // Inclusive lower bound
+	Until time.Time         // Inclusive upper bound
+	Sort  FactSort          // Ordering (empty = storage order)
+	Limit int               // Maximum results
+}
+
+// TimeField returns the payload field the options filter and sort on.
+func (o FactListOptions) TimeField() string {
+	if o.Sort == FactSortUpdated {
+		return "updated_at"
+	}
+	return "created_at"
+}
+
+// FactPageOptions selects a page of facts in storage order.
+type FactPageOptions struct {
+	Type       entities.FactType // Filter by fact type (empty = all)
+	SourceFile string            // Filter by source file (empty = all)
+	Drafts     bool              // Only draft facts
+	Limit      int               // Maximum results
+	Cursor     string            // Where the page starts: empty for the first, else a cursor ListPage returned
+}
+
 // VectorDB defines the interface for vector database operations.
 type VectorDB interface {
 	// EnsureCollection creates the collection if it doesn't exist.
@@ -35,29 +96,150 @@ type VectorDB interface {
 	FindByIDs(ctx context.Context, ids []string) ([]entities.Fact, error)
 
 	// Search performs a semantic search and returns similar facts.
+	// All searches skip facts that are pending review.
 	Search(ctx context.Context, embedding []float32, limit int) ([]entities.Fact, error)
 
 	// SearchByType performs a semantic search filtered by fact type.
 	SearchByType(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error)
 
+	// SearchBySubject performs a semantic search filtered to facts whose
+	// subject exactly matches subject.
+	SearchBySubject(ctx context.Context, embedding []float32, subject string, limit int) ([]entities.Fact, error)
+
+	// SearchVector performs a semantic search against a specific stored
+	// embedding, optionally filtered by fact type (empty = all types).
+	// Collections without named vectors search their single embedding.
+	SearchVector(ctx context.Context, vector VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error)
+
+	// SearchKeywords performs a BM25-style keyword search over fact text,
+	// optionally filtered by fact type (empty = all types). Collections
+	// created without a keyword index return no results.
+	SearchKeywords(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error)
+
 	// Delete removes a fact by its ID.
 	Delete(ctx context.Context, id string) error
 
-	// List returns all facts with pagination.
+	// List returns up to limit facts. What offset means depends on the
+	// store (Qdrant reads it as a point ID, not a count), so callers
+	// reading every fact page by page use ListPage instead.
 	List(ctx context.Context, limit int, offset uint64) ([]entities.Fact, error)
 
+	// ListPage returns a page of facts and the cursor of the next page, or
+	// an empty cursor after the last. Facts saved while paging may be
+	// missed, but none is listed twice.
+	ListPage(ctx context.Context, opts FactPageOptions) ([]entities.Fact, string, error)
+
 	// ListByType returns facts filtered by type.
 	ListByType(ctx context.Context, factType entities.FactType, limit int) ([]entities.Fact, error)
 
+	// ListFiltered returns facts matching the given type and time range,
+	// optionally ordered newest first by creation or update time.
+	ListFiltered(ctx context.Context, opts FactListOptions) ([]entities.Fact, error)
+
+	// ListPending returns facts awaiting review.
+	ListPending(ctx context.Context, limit int) ([]entities.Fact, error)
+
+	// ListBySubject returns facts whose subject exactly matches one of the
+	// given names. Facts pending review are skipped.
+	ListBySubject(ctx context.Context, subjects []string, limit int) ([]entities.Fact, error)
+
+	// ListByClaimKeys returns facts whose claim key (see
+	// entities.Fact.ClaimKey) is one of keys. Facts pending review are
+	// skipped, as are facts stored before claim keys were, until the
+	// collection is reindexed.
+	ListByClaimKeys(ctx context.Context, keys []string, limit int) ([]entities.Fact, error)
+
 	// ListBySource returns facts filtered by source file.
 	ListBySource(ctx context.Context, sourceFile string, limit int) ([]entities.Fact, error)
 
+	// ListByEntities retrieves facts whose subject or object exactly matches
+	// one of the given entity names.
+	ListByEntities(ctx context.Context, names []string, limit int) ([]entities.Fact, error)
+
 	// DeleteBySource removes all facts from a source file.
 	DeleteBySource(ctx context.Context, sourceFile string) error
 
+	// SetSourceArchived marks or unmarks every fact from a source file as
+	// archived, leaving the rest of each fact as it is.
+	SetSourceArchived(ctx context.Context, sourceFile string, archived bool) error
+
 	// DeleteAll removes all facts.
 	DeleteAll(ctx context.Context) error
 
+	// CountBySubject returns the number of facts whose subject exactly
+	// matches one of the given names.
+	CountBySubject(ctx context.Context, subjects []string) (uint64, error)
+
 	// Count returns the total number of facts.
 	Count(ctx context.Context) (uint64, error)
 }
+
+// VectorCollectionAdmin manages physical collections and the aliases that
+// point to them. Collections are addressed by name rather than bound at
+// construction, so a single admin can copy data between collections.
+type VectorCollectionAdmin interface {
+	// ResolveAlias returns the collection an alias points to,
+	// or an empty string if the alias does not exist.
+	ResolveAlias(ctx context.Context, alias string) (string, error)
+
+	// CollectionExists reports whether a physical collection exists.
+	CollectionExists(ctx context.Context, name string) (bool, error)
+
+	// CreateCollection creates an empty collection with the given vector size.
+	CreateCollection(ctx context.Context, name string, vectorSize uint64) error
+
+	// DeleteCollection removes a physical collection and any aliases to it.
+	DeleteCollection(ctx context.Context, name string) error
+
+	// SwitchAlias atomically points an alias at a collection,
+	// creating the alias if it does not exist.
+	SwitchAlias(ctx context.Context, alias, collection string) error
+
+	// ScrollFacts calls fn with successive batches of facts, embeddings included.
+	ScrollFacts(ctx context.Context, collection string, batchSize int, fn func([]entities.Fact) error) error
+
+	// SaveFacts stores facts with their embeddings in the named collection.
+	SaveFacts(ctx context.Context, collection string, facts []entities.Fact) error
+}
+
+// IndexedEntity is an entity's embedding in an EntityIndex.
+type IndexedEntity struct {
+	EntityID    string
+	Name        string
+	Fingerprint string // Identifies the text embedded, to tell when it is stale
+	Embedding   []float32
+}
+
+// EntityMatch is an entity found by an EntityIndex search.
+type EntityMatch struct {
+	EntityID string
+	Name     string
+	Score    float32 // Similarity to the query, higher is closer
+}
+
+// EntityIndex stores an embedding of each entity of a world, made from its
+// name and a summary of its facts, so entities can be found by what is
+// known about them rather than by name.
+type EntityIndex interface {
+	// Exists reports whether the index has been created.
+	Exists(ctx context.Context) (bool, error)
+
+	// EnsureIndex creates the index if it doesn't exist.
+	EnsureIndex(ctx context.Context, vectorSize uint64) error
+
+	// DeleteIndex removes the index and every embedding in it.
+	DeleteIndex(ctx context.Context) error
+
+	// SaveEntities stores entities' embeddings, replacing earlier ones.
+	SaveEntities(ctx context.Context, list []IndexedEntity) error
+
+	// DeleteEntities removes the embeddings of entities by ID.
+	DeleteEntities(ctx context.Context, ids []string) error
+
+	// Fingerprints returns the fingerprint of every indexed entity, by ID.
+	Fingerprints(ctx context.Context) (map[string]string, error)
+
+	// SearchEntities returns the entities whose embeddings are most similar
+	// to embedding, closest first.
+	SearchEntities(ctx context.Context, embedding []float32, limit int) ([]EntityMatch, error)
+}
@@ -0,0 +1,52 @@
+package ports
+
+import "context"
+
+// WorldRegistry is the list of worlds and the collections they use.
+type WorldRegistry interface {
+	// Collection returns the collection of a world, or ErrNotFound.
+	Collection(world string) (string, error)
+
+	// Exists reports whether a world is registered.
+	Exists(world string) bool
+
+	// CollectionOwner returns the world using collection, if any.
+	CollectionOwner(collection string) (string, bool)
+
+	// CollectionName returns the collection a new world of the given name
+	// uses.
+	CollectionName(world string) string
+
+	// Rename registers world oldName as newName, using collection, keeping
+	// its other settings, and saves the registry.
+	Rename(oldName, newName, collection string) error
+}
+
+// WorldStorage keeps the data of worlds: the vector collections of their
+// facts and the local directories of their databases.
+type WorldStorage interface {
+	// AliasCollection makes alias point at the collection holding the
+	// facts behind collection. isAlias reports whether collection was an
+	// alias itself, rather than a collection made before aliases were.
+	// ErrConflict means alias is taken.
+	AliasCollection(ctx context.Context, collection, alias string) (isAlias bool, err error)
+
+	// DeleteAlias removes an alias, leaving its collection in place.
+	DeleteAlias(ctx context.Context, alias string) error
+
+	// DeleteEntityIndex removes the entity index of a collection, if it
+	// has one.
+	DeleteEntityIndex(ctx context.Context, collection string) error
+
+	// CanMoveDir reports whether world oldName has a directory of its own
+	// to move for newName. ErrConflict means newName's directory exists.
+	CanMoveDir(oldName, newName string) (bool, error)
+
+	// MoveDir moves the directory of world oldName to that of newName.
+	MoveDir(oldName, newName string) error
+
+	// RenameEntities moves the entities in the database kept in world's
+	// directory from world ID oldID to newID. found is false if world has
+	// no database.
+	RenameEntities(ctx context.Context, world, oldID, newID string) (found bool, err error)
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Backend names recorded in the slow-query log.
+const (
+	BackendVectorDB = "vectordb"
+	BackendLLM      = "llm"
+	BackendEmbedder = "embedder"
+)
+
+// Budget limits how long a single backend call may take, and meters what
+// calls to paid APIs cost.
+type Budget struct {
+	Timeout       time.Duration    // Per-call timeout (0 = none)
+	SlowThreshold time.Duration    // Calls at least this slow are logged (0 = never)
+	Meter         ports.UsageMeter // Allows calls and records their usage (nil = none)
+}
+
+// auditLogger records actions in the audit log.
+type auditLogger interface {
+	LogAction(ctx context.Context, action string, factID string, details map[string]any) error
+}
+
+// budgeter enforces a Budget on calls to one backend and records slow and
+// timed-out calls in the audit log.
+type budgeter struct {
+	backend string
+	budget  Budget
+	audit   auditLogger // nil = don't log
+	now     func() time.Time
+}
+
+func newBudgeter(backend string, budget Budget, audit auditLogger) *budgeter {
+	return &budgeter{backend: backend, budget: budget, audit: audit, now: time.Now}
+}
+
+// withBudget runs fn with the budget's timeout applied to its context,
+// and its meter attached, if the meter allows the call.
+func withBudget[T any](ctx context.Context, b *budgeter, op string, fn func(context.Context) (T, error)) (T, error) {
+	callCtx, cancel := ctx, context.CancelFunc(func() {})
+	if meter := b.budget.Meter; meter != nil {
+		if err := meter.Allow(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		callCtx = ports.WithUsageMeter(callCtx, meter)
+	}
+	if b.budget.Timeout > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, b.budget.Timeout)
+	}
+	defer cancel()
+
+	start := b.now()
+	result, err := fn(callCtx)
+	elapsed := b.now().Sub(start)
+
+	// Only our deadline counts; the caller's own cancellation is passed on as is.
+	timedOut := err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded)
+	if timedOut || (b.budget.SlowThreshold > 0 && elapsed >= b.budget.SlowThreshold) {
+		b.logSlow(ctx, op, elapsed, timedOut)
+	}
+	if timedOut {
+		return result, entities.Errorf(entities.ErrBackendUnavailable, "%s %s timed out after %s: %w", b.backend, op, b.budget.Timeout, err)
+	}
+	return result, err
+}
+
+func (b *budgeter) logSlow(ctx context.Context, op string, elapsed time.Duration, timedOut bool) {
+	if b.audit == nil {
+		return
+	}
+	details := map[string]any{
+		"backend":     b.backend,
+		"operation":   op,
+		"duration_ms": elapsed.Milliseconds(),
+		"timed_out":   timedOut,
+	}
+	// Best effort: failing to log a slow call shouldn't fail the call.
+	_ = b.audit.LogAction(context.WithoutCancel(ctx), entities.AuditActionSlowQuery, "", details)
+}
+
+// BudgetedVectorDB is a ports.VectorDB whose reads are bounded by a Budget.
+// Writes and collection management pass through unchanged, so a timeout
+// never interrupts them halfway.
+type BudgetedVectorDB struct {
+	ports.VectorDB
+	b *budgeter
+}
+
+// NewBudgetedVectorDB wraps db so its reads honor budget. Slow reads are
+// recorded in audit, if not nil.
+func NewBudgetedVectorDB(db ports.VectorDB, budget Budget, audit auditLogger) *BudgetedVectorDB {
+	return &BudgetedVectorDB{VectorDB: db, b: newBudgeter(BackendVectorDB, budget, audit)}
+}
+
+// FindByID retrieves a fact by its ID.
+func (d *BudgetedVectorDB) FindByID(ctx context.Context, id string) (entities.Fact, error) {
+	return withBudget(ctx, d.b, "find_by_id", func(ctx context.Context) (entities.Fact, error) {
+		return d.VectorDB.FindByID(ctx, id)
+	})
+}
+
+// ExistsByIDs reports which of the given IDs exist.
+func (d *BudgetedVectorDB) ExistsByIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	return withBudget(ctx, d.b, "exists_by_ids", func(ctx context.Context) (map[string]bool, error) {
+		return d.VectorDB.ExistsByIDs(ctx, ids)
+	})
+}
+
+// FindByIDs retrieves facts by their IDs.
+func (d *BudgetedVectorDB) FindByIDs(ctx context.Context, ids []string) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "find_by_ids", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.FindByIDs(ctx, ids)
+	})
+}
+
+// Search finds facts similar to the given embedding.
+func (d *BudgetedVectorDB) Search(ctx context.Context, embedding []float32, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "search", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.Search(ctx, embedding, limit)
+	})
+}
+
+// SearchByType finds facts of a type similar to the given embedding.
+func (d *BudgetedVectorDB) SearchByType(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "search_by_type", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.SearchByType(ctx, embedding, factType, limit)
+	})
+}
+
+// SearchBySubject finds facts about a subject similar to the given embedding.
+func (d *BudgetedVectorDB) SearchBySubject(ctx context.Context, embedding []float32, subject string, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "search_by_subject", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.SearchBySubject(ctx, embedding, subject, limit)
+	})
+}
+
+// SearchVector searches one named vector.
+func (d *BudgetedVectorDB) SearchVector(ctx context.Context, vector ports.VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "search_vector", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.SearchVector(ctx, vector, embedding, factType, limit)
+	})
+}
+
+// SearchKeywords finds facts matching the query's keywords.
+func (d *BudgetedVectorDB) SearchKeywords(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "search_keywords", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.SearchKeywords(ctx, query, factType, limit)
+	})
+}
+
+// List returns facts with pagination.
+func (d *BudgetedVectorDB) List(ctx context.Context, limit int, offset uint64) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.List(ctx, limit, offset)
+	})
+}
+
+// ListPage returns a page of facts and the cursor of the next page.
+func (d *BudgetedVectorDB) ListPage(ctx context.Context, opts ports.FactPageOptions) ([]entities.Fact, string, error) {
+	var next string
+	facts, err := withBudget(ctx, d.b, "list_page", func(ctx context.Context) ([]entities.Fact, error) {
+		facts, cursor, err := d.VectorDB.ListPage(ctx, opts)
+		next = cursor
+		return facts, err
+	})
+	return facts, next, err
+}
+
+// ListByType returns facts of a specific type.
+func (d *BudgetedVectorDB) ListByType(ctx context.Context, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_by_type", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListByType(ctx, factType, limit)
+	})
+}
+
+// ListFiltered returns facts matching the filter options.
+func (d *BudgetedVectorDB) ListFiltered(ctx context.Context, opts ports.FactListOptions) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_filtered", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListFiltered(ctx, opts)
+	})
+}
+
+// ListPending returns facts held for review.
+func (d *BudgetedVectorDB) ListPending(ctx context.Context, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_pending", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListPending(ctx, limit)
+	})
+}
+
+// ListBySubject returns facts about any of the given subjects.
+func (d *BudgetedVectorDB) ListBySubject(ctx context.Context, subjects []string, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_by_subject", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListBySubject(ctx, subjects, limit)
+	})
+}
+
+// ListBySource returns facts extracted from a source file.
+func (d *BudgetedVectorDB) ListBySource(ctx context.Context, sourceFile string, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_by_source", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListBySource(ctx, sourceFile, limit)
+	})
+}
+
+// ListByClaimKeys returns facts with any of the given claim keys.
+func (d *BudgetedVectorDB) ListByClaimKeys(ctx context.Context, keys []string, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_by_claim_keys", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListByClaimKeys(ctx, keys, limit)
+	})
+}
+
+// ListByEntities returns facts mentioning any of the given entities.
+func (d *BudgetedVectorDB) ListByEntities(ctx context.Context, names []string, limit int) ([]entities.Fact, error) {
+	return withBudget(ctx, d.b, "list_by_entities", func(ctx context.Context) ([]entities.Fact, error) {
+		return d.VectorDB.ListByEntities(ctx, names, limit)
+	})
+}
+
+// CountBySubject counts facts about any of the given subjects.
+func (d *BudgetedVectorDB) CountBySubject(ctx context.Context, subjects []string) (uint64, error) {
+	return withBudget(ctx, d.b, "count_by_subject", func(ctx context.Context) (uint64, error) {
+		return d.VectorDB.CountBySubject(ctx, subjects)
+	})
+}
+
+// Count returns the total number of facts.
+func (d *BudgetedVectorDB) Count(ctx context.Context) (uint64, error) {
+	return withBudget(ctx, d.b, "count", func(ctx context.Context) (uint64, error) {
+		return d.VectorDB.Count(ctx)
+	})
+}
+
+// BudgetedLLM is a ports.LLMClient whose calls are bounded by a Budget.
+type BudgetedLLM struct {
+	llm ports.LLMClient
+	b   *budgeter
+}
+
+// NewBudgetedLLM wraps llm so its calls honor budget. Slow calls are
+// recorded in audit, if not nil.
+func NewBudgetedLLM(llm ports.LLMClient, budget Budget, audit auditLogger) *BudgetedLLM {
+	return &BudgetedLLM{llm: llm, b: newBudgeter(BackendLLM, budget, audit)}
+}
+
+// ExtractFacts extracts facts from the given text.
+func (l *BudgetedLLM) ExtractFacts(ctx context.Context, text string, validTypes []string) ([]entities.Fact, error) {
+	return withBudget(ctx, l.b, "extract_facts", func(ctx context.Context) ([]entities.Fact, error) {
+		return l.llm.ExtractFacts(ctx, text, validTypes)
+	})
+}
+
+// ExtractFactsWithContext extracts facts from text using prior context.
+func (l *BudgetedLLM) ExtractFactsWithContext(ctx context.Context, text string, priorContext string, validTypes []string) ([]entities.Fact, error) {
+	return withBudget(ctx, l.b, "extract_facts", func(ctx context.Context) ([]entities.Fact, error) {
+		return l.llm.ExtractFactsWithContext(ctx, text, priorContext, validTypes)
+	})
+}
+
+// ExtractFocused runs a specialized extraction pass.
+func (l *BudgetedLLM) ExtractFocused(ctx context.Context, text string, priorContext string, focus ports.ExtractionFocus, validTypes []string) ([]entities.Fact, error) {
+	return withBudget(ctx, l.b, "extract_"+string(focus), func(ctx context.Context) ([]entities.Fact, error) {
+		return l.llm.ExtractFocused(ctx, text, priorContext, focus, validTypes)
+	})
+}
+
+// SummarizeChunk folds text into a running summary.
+func (l *BudgetedLLM) SummarizeChunk(ctx context.Context, summary string, text string) (string, error) {
+	return withBudget(ctx, l.b, "summarize_chunk", func(ctx context.Context) (string, error) {
+		return l.llm.SummarizeChunk(ctx, summary, text)
+	})
+}
+
+// CheckConsistency checks if new facts are consistent with existing facts.
+func (l *BudgetedLLM) CheckConsistency(ctx context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
+	return withBudget(ctx, l.b, "check_consistency", func(ctx context.Context) ([]ports.ConsistencyIssue, error) {
+		return l.llm.CheckConsistency(ctx, newFacts, existingFacts)
+	})
+}
+
+// ResolveCoreferences finds the names that pronouns in facts refer to.
+func (l *BudgetedLLM) ResolveCoreferences(ctx context.Context, text string, facts []entities.Fact) ([]ports.CoreferenceResolution, error) {
+	return withBudget(ctx, l.b, "resolve_coreferences", func(ctx context.Context) ([]ports.CoreferenceResolution, error) {
+		return l.llm.ResolveCoreferences(ctx, text, facts)
+	})
+}
+
+// LabelTopic names the theme the given facts share.
+func (l *BudgetedLLM) LabelTopic(ctx context.Context, facts []entities.Fact) (string, error) {
+	return withBudget(ctx, l.b, "label_topic", func(ctx context.Context) (string, error) {
+		return l.llm.LabelTopic(ctx, facts)
+	})
+}
+
+// ConfirmTranslations returns the pairs that are translations of each other.
+func (l *BudgetedLLM) ConfirmTranslations(ctx context.Context, pairs []ports.FactPair) ([]int, error) {
+	return withBudget(ctx, l.b, "confirm_translations", func(ctx context.Context) ([]int, error) {
+		return l.llm.ConfirmTranslations(ctx, pairs)
+	})
+}
+
+// BudgetedEmbedder is a ports.Embedder whose calls are bounded by a Budget.
+type BudgetedEmbedder struct {
+	embedder ports.Embedder
+	b        *budgeter
+}
+
+// NewBudgetedEmbedder wraps embedder so its calls honor budget. Slow calls
+// are recorded in audit, if not nil.
+func NewBudgetedEmbedder(embedder ports.Embedder, budget Budget, audit auditLogger) *BudgetedEmbedder {
+	return &BudgetedEmbedder{embedder: embedder, b: newBudgeter(BackendEmbedder, budget, audit)}
+}
+
+// Embed generates a vector embedding for the given text.
+func (e *BudgetedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return withBudget(ctx, e.b, "embed", func(ctx context.Context) ([]float32, error) {
+		return e.embedder.Embed(ctx, text)
+	})
+}
+
+// EmbedBatch generates vector embeddings for multiple texts.
+func (e *BudgetedEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	return withBudget(ctx, e.b, "embed_batch", func(ctx context.Context) ([][]float32, error) {
+		return e.embedder.EmbedBatch(ctx, texts)
+	})
+}
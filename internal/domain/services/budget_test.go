@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+// recordingAudit keeps the audit log entries it is given.
+type recordingAudit struct {
+	mu      sync.Mutex
+	entries []entities.AuditEntry
+}
+
+func (a *recordingAudit) LogAction(_ context.Context, action string, factID string, details map[string]any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, entities.AuditEntry{Action: action, FactID: factID, Details: details})
+	return nil
+}
+
+// blockingVectorDB blocks searches until their context is done.
+type blockingVectorDB struct {
+	*mocks.VectorDB
+}
+
+func (d *blockingVectorDB) Search(ctx context.Context, _ []float32, _ int) ([]entities.Fact, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBudgetedVectorDB_Timeout(t *testing.T) {
+	audit := &recordingAudit{}
+	db := NewBudgetedVectorDB(&blockingVectorDB{&mocks.VectorDB{}}, Budget{Timeout: 10 * time.Millisecond}, audit)
+
+	_, err := db.Search(context.Background(), []float32{0.1}, 5)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, entities.ErrBackendUnavailable)
+	assert.Contains(t, err.Error(), "vectordb search timed out after 10ms")
+
+	require.Len(t, audit.entries, 1)
+	assert.Equal(t, entities.AuditActionSlowQuery, audit.entries[0].Action)
+	assert.Equal(t, "search", audit.entries[0].Details["operation"])
+	assert.Equal(t, true, audit.entries[0].Details["timed_out"])
+}
+
+func TestBudgetedVectorDB_CallerCancel(t *testing.T) {
+	audit := &recordingAudit{}
+	db := NewBudgetedVectorDB(&blockingVectorDB{&mocks.VectorDB{}}, Budget{Timeout: time.Hour}, audit)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := db.Search(ctx, []float32{0.1}, 5)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NotContains(t, err.Error(), "timed out")
+	assert.Empty(t, audit.entries)
+}
+
+func TestBudgeter_SlowThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		elapsed   time.Duration
+		wantLog   bool
+	}{
+		{name: "fast", threshold: time.Second, elapsed: 100 * time.Millisecond},
+		{name: "slow", threshold: time.Second, elapsed: 2 * time.Second, wantLog: true},
+		{name: "disabled", elapsed: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &recordingAudit{}
+			emb := NewBudgetedEmbedder(&mocks.Embedder{EmbeddingResult: []float32{0.1}}, Budget{SlowThreshold: tt.threshold}, audit)
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			calls := 0
+			emb.b.now = func() time.Time {
+				calls++
+				if calls == 1 {
+					return start
+				}
+				return start.Add(tt.elapsed)
+			}
+
+			//nolint:loopcall // One call per case
+			embedding, err := emb.Embed(context.Background(), "Frodo")
+			require.NoError(t, err)
+			assert.Equal(t, []float32{0.1}, embedding)
+
+			if !tt.wantLog {
+				assert.Empty(t, audit.entries)
+				return
+			}
+			require.Len(t, audit.entries, 1)
+			assert.Equal(t, map[string]any{
+				"backend":     BackendEmbedder,
+				"operation":   "embed",
+				"duration_ms": tt.elapsed.Milliseconds(),
+				"timed_out":   false,
+			}, audit.entries[0].Details)
+		})
+	}
+}
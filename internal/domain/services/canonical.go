@@ -0,0 +1,452 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// canonicalPageSize is the number of facts read per page when grouping subjects.
+const canonicalPageSize = 256
+
+// leadingArticles are dropped from the front of a subject when grouping, so
+// "The Shire" and "Shire" are recognised as the same place.
+var leadingArticles = []string{"the", "a", "an"}
+
+// SubjectVariant is one spelling of a subject and how many facts use it.
+type SubjectVariant struct {
+	Name  string
+	Facts int
+}
+
+// SubjectGroup is a set of spellings that likely name the same subject.
+type SubjectGroup struct {
+	Canonical string           // Proposed name for every variant
+	Variants  []SubjectVariant // Every spelling, canonical included, most used first
+	Fuzzy     bool             // Some variants only match allowing for typos
+}
+
+// Facts returns the number of facts using any variant.
+func (g *SubjectGroup) Facts() int {
+	total := 0
+	for _, v := range g.Variants {
+		total += v.Facts
+	}
+	return total
+}
+
+// Renamed returns the number of facts whose subject changes when the group
+// is applied.
+func (g *SubjectGroup) Renamed() int {
+	renamed := 0
+	for _, v := range g.Variants {
+		if v.Name != g.Canonical {
+			renamed += v.Facts
+		}
+	}
+	return renamed
+}
+
+// SubjectReportOptions controls how subjects are grouped.
+type SubjectReportOptions struct {
+	Exact bool // Group by normalized name only, without typo tolerance
+}
+
+// SubjectReport lists the groups of subjects that likely name the same thing.
+type SubjectReport struct {
+	Facts    int // Facts scanned
+	Subjects int // Distinct subjects seen
+	Groups   []SubjectGroup
+}
+
+// CanonicalizeResult counts the changes made by applying subject groups.
+type CanonicalizeResult struct {
+	FactsRewritten  int
+	EntitiesMerged  int // Variant entities folded into the canonical one
+	EntitiesRenamed int // Canonical entities respelled to the canonical name
+}
+
+// CanonicalService finds subjects written several ways and rewrites them
+// to a single canonical name.
+type CanonicalService struct {
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
+	facts        *FactService
+}
+
+// NewCanonicalService creates a new CanonicalService.
+func NewCanonicalService(vectorDB ports.VectorDB, relationalDB ports.RelationalDB, facts *FactService) *CanonicalService {
+	return &CanonicalService{
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
+		facts:        facts,
+	}
+}
+
+// Report groups the world's subjects that differ only in case, punctuation,
+// a leading article, or (unless opts.Exact) small typos, and proposes the
+// most used spelling of each as its canonical name. Groups are returned
+// with the most facts to rename first.
+func (s *CanonicalService) Report(ctx context.Context, opts SubjectReportOptions) (*SubjectReport, error) {
+	counts := make(map[string]int)
+	report := &SubjectReport{}
+	err := s.eachPage(ctx, func(facts []entities.Fact) error {
+		for i := range facts {
+			if subject := strings.TrimSpace(facts[i].Subject); subject != "" {
+				counts[subject]++
+			}
+		}
+		report.Facts += len(facts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Subjects = len(counts)
+	report.Groups = groupSubjects(counts, !opts.Exact)
+	return report, nil
+}
+
+// Apply rewrites every fact whose subject or object is a non-canonical
+// variant of a group, then merges the variants' entities into the
+// canonical entity. The facts of each page are embedded and saved in one
+// batch, and each rewritten fact is versioned. It stops at the first
+// failure and returns what was changed so far.
+func (s *CanonicalService) Apply(ctx context.Context, worldID string, groups []SubjectGroup) (CanonicalizeResult, error) {
+	var result CanonicalizeResult
+
+	canonical := make(map[string]string)
+	for _, g := range groups {
+		for _, v := range g.Variants {
+			if v.Name != g.Canonical {
+				canonical[v.Name] = g.Canonical
+			}
+		}
+	}
+	if len(canonical) == 0 {
+		return result, nil
+	}
+
+	err := s.eachPage(ctx, func(facts []entities.Fact) error {
+		var originals, updated []entities.Fact
+		var reasons []string
+		for i := range facts {
+			fact := facts[i]
+			var renamed []string
+			if name, ok := canonical[strings.TrimSpace(fact.Subject)]; ok {
+				renamed = append(renamed, fmt.Sprintf("%q as %q", fact.Subject, name))
+				fact.Subject = name
+			}
+			if name, ok := canonical[strings.TrimSpace(fact.Object)]; ok {
+				renamed = append(renamed, fmt.Sprintf("%q as %q", fact.Object, name))
+				fact.Object = name
+			}
+			if len(renamed) == 0 {
+				continue
+			}
+			originals = append(originals, facts[i])
+			updated = append(updated, fact)
+			reasons = append(reasons, "canonicalized "+strings.Join(renamed, ", "))
+		}
+		if len(updated) == 0 {
+			return nil
+		}
+
+		if err := s.facts.replaceBatch(ctx, originals, updated, reasons); err != nil {
+			return fmt.Errorf("rewriting facts: %w", err)
+		}
+		result.FactsRewritten += len(updated)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for _, g := range groups {
+		if err := s.mergeEntities(ctx, worldID, &g, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// EntityRenameResult reports what renaming an entity changed.
+type EntityRenameResult struct {
+	Entity         *entities.Entity // The renamed entity; nil if none had the old name
+	FactsRewritten int
+}
+
+// Rename renames the entity called oldName to newName and rewrites every
+// fact whose subject or object names it, in batches, regenerating their
+// embeddings and versioning each change. The entity is renamed first, so
+// running Rename again finishes the facts of an interrupted rename. It
+// stops at the first failure and returns what was changed so far.
+func (s *CanonicalService) Rename(ctx context.Context, worldID, oldName, newName string) (EntityRenameResult, error) {
+	var result EntityRenameResult
+	oldName, newName = strings.TrimSpace(oldName), strings.TrimSpace(newName)
+	if oldName == "" || newName == "" {
+		return result, entities.Errorf(entities.ErrValidation, "both the old and the new name are required")
+	}
+	if oldName == newName {
+		return result, entities.Errorf(entities.ErrValidation, "%q is already the name", newName)
+	}
+
+	entity, err := s.relationalDB.FindEntityByName(ctx, worldID, oldName)
+	if err != nil {
+		return result, fmt.Errorf("finding entity %s: %w", oldName, err)
+	}
+	if entity != nil {
+		existing, err := s.relationalDB.FindEntityByName(ctx, worldID, newName)
+		if err != nil {
+			return result, fmt.Errorf("finding entity %s: %w", newName, err)
+		}
+		if existing != nil && existing.ID != entity.ID {
+			return result, entities.Errorf(entities.ErrConflict, "an entity named %q already exists", existing.Name)
+		}
+		if err := s.relationalDB.RenameEntity(ctx, entity.ID, newName); err != nil {
+			return result, fmt.Errorf("renaming entity %s: %w", entity.Name, err)
+		}
+		entity.Name, entity.NormalizedName = newName, entities.NormalizeName(newName)
+		result.Entity = entity
+	}
+
+	key := entities.NormalizeName(oldName)
+	renames := func(name string) bool {
+		name = strings.TrimSpace(name)
+		return name != newName && entities.NormalizeName(name) == key
+	}
+	err = s.eachPage(ctx, func(facts []entities.Fact) error {
+		var originals, updated []entities.Fact
+		for i := range facts {
+			fact := facts[i]
+			if renames(fact.Subject) {
+				fact.Subject = newName
+			}
+			if renames(fact.Object) {
+				fact.Object = newName
+			}
+			if fact.Subject != facts[i].Subject || fact.Object != facts[i].Object {
+				originals = append(originals, facts[i])
+				updated = append(updated, fact)
+			}
+		}
+		if len(updated) == 0 {
+			return nil
+		}
+
+		reason := fmt.Sprintf("renamed %q as %q", oldName, newName)
+		reasons := make([]string, len(updated))
+		for i := range reasons {
+			reasons[i] = reason
+		}
+		if err := s.facts.replaceBatch(ctx, originals, updated, reasons); err != nil {
+			return fmt.Errorf("rewriting facts: %w", err)
+		}
+		result.FactsRewritten += len(updated)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if entity == nil && result.FactsRewritten == 0 {
+		return result, entities.Errorf(entities.ErrNotFound, "no entity or fact is named %q", oldName)
+	}
+	return result, nil
+}
+
+// mergeEntities folds the entities of a group's variants into the entity
+// named by its canonical name, creating that entity if a variant has one
+// and it does not exist yet.
+func (s *CanonicalService) mergeEntities(ctx context.Context, worldID string, g *SubjectGroup, result *CanonicalizeResult) error {
+	target, err := s.relationalDB.FindEntityByName(ctx, worldID, g.Canonical)
+	if err != nil {
+		return fmt.Errorf("finding entity %s: %w", g.Canonical, err)
+	}
+
+	for _, v := range g.Variants {
+		entity, err := s.relationalDB.FindEntityByName(ctx, worldID, v.Name)
+		if err != nil {
+			return fmt.Errorf("finding entity %s: %w", v.Name, err)
+		}
+		if entity == nil || (target != nil && entity.ID == target.ID) {
+			continue
+		}
+
+		if target == nil {
+			target, err = s.relationalDB.FindOrCreateEntity(ctx, worldID, g.Canonical)
+			if err != nil {
+				return fmt.Errorf("creating entity %s: %w", g.Canonical, err)
+			}
+		}
+		if err := s.relationalDB.MergeEntities(ctx, entity.ID, target.ID); err != nil {
+			return fmt.Errorf("merging entity %s into %s: %w", entity.Name, target.Name, err)
+		}
+		result.EntitiesMerged++
+	}
+
+	// An entity matching the canonical name up to case takes its spelling.
+	if target != nil && target.Name != g.Canonical {
+		target.Name = g.Canonical
+		if err := s.relationalDB.SaveEntity(ctx, target); err != nil {
+			return fmt.Errorf("renaming entity to %s: %w", g.Canonical, err)
+		}
+		result.EntitiesRenamed++
+	}
+	return nil
+}
+
+// eachPage calls fn with successive pages of the world's facts.
+func (s *CanonicalService) eachPage(ctx context.Context, fn func([]entities.Fact) error) error {
+	cursor := ""
+	for {
+		facts, next, err := s.vectorDB.ListPage(ctx, ports.FactPageOptions{Limit: canonicalPageSize, Cursor: cursor})
+		if err != nil {
+			return fmt.Errorf("listing facts: %w", err)
+		}
+		if err := fn(facts); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// groupSubjects groups subjects by their identity key and, if fuzzy, joins
+// groups whose keys differ only by small typos. Only groups with more than
+// one spelling are returned.
+func groupSubjects(counts map[string]int, fuzzy bool) []SubjectGroup {
+	byKey := make(map[string][]string)
+	for subject := range counts {
+		key := subjectKey(subject)
+		if key == "" {
+			continue
+		}
+		byKey[key] = append(byKey[key], subject)
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// Union-find over keys, joining keys within typos of each other.
+	parent := make(map[string]string, len(keys))
+	for _, key := range keys {
+		parent[key] = key
+	}
+	var find func(string) string
+	find = func(key string) string {
+		if p := parent[key]; p != key {
+			parent[key] = find(p)
+		}
+		return parent[key]
+	}
+	if fuzzy {
+		words := make(map[string][]string, len(keys))
+		for _, key := range keys {
+			words[key] = strings.Fields(key)
+		}
+		for i, a := range keys {
+			for _, b := range keys[i+1:] {
+				if wordsWithinTypos(words[a], words[b]) {
+					if ra, rb := find(a), find(b); ra != rb {
+						parent[rb] = ra
+					}
+				}
+			}
+		}
+	}
+
+	members := make(map[string][]string)
+	memberKeys := make(map[string]int)
+	for _, key := range keys {
+		root := find(key)
+		members[root] = append(members[root], byKey[key]...)
+		memberKeys[root]++
+	}
+
+	var groups []SubjectGroup
+	for root, names := range members {
+		if len(names) < 2 {
+			continue
+		}
+		// Differing keys can only have been joined by a typo match.
+		group := SubjectGroup{Fuzzy: memberKeys[root] > 1}
+		for _, name := range names {
+			group.Variants = append(group.Variants, SubjectVariant{Name: name, Facts: counts[name]})
+		}
+		sort.Slice(group.Variants, func(i, j int) bool {
+			return preferSubject(group.Variants[i], group.Variants[j])
+		})
+		group.Canonical = group.Variants[0].Name
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if ri, rj := groups[i].Renamed(), groups[j].Renamed(); ri != rj {
+			return ri > rj
+		}
+		return groups[i].Canonical < groups[j].Canonical
+	})
+	return groups
+}
+
+// subjectKey reduces a subject to the words that identify it: normalized,
+// without punctuation, and without a leading article.
+func subjectKey(subject string) string {
+	words := strings.FieldsFunc(entities.NormalizeName(subject), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	if len(words) > 1 && slices.Contains(leadingArticles, words[0]) {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// wordsWithinTypos reports whether two keys have the same number of words
+// and each pair of words is within the shorter word's typo budget. Words
+// too short to allow typos, such as numbers, must match exactly.
+func wordsWithinTypos(a, b []string) bool {
+	if len(a) != len(b) || len(a) == 0 {
+		return false
+	}
+	for i := range a {
+		if levenshtein(a[i], b[i]) > min(typoBudget(a[i]), typoBudget(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// preferSubject orders variants by how good a canonical name they make:
+// the most used first, then capitalized over lowercase, then the shortest.
+func preferSubject(a, b SubjectVariant) bool {
+	if a.Facts != b.Facts {
+		return a.Facts > b.Facts
+	}
+	if ca, cb := isCapitalized(a.Name), isCapitalized(b.Name); ca != cb {
+		return ca
+	}
+	if len(a.Name) != len(b.Name) {
+		return len(a.Name) < len(b.Name)
+	}
+	return a.Name < b.Name
+}
+
+// isCapitalized reports whether a name starts with an upper-case letter.
+func isCapitalized(name string) bool {
+	for _, r := range name {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
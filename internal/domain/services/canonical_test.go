@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func subjectFacts(subject string, n int) []entities.Fact {
+	facts := make([]entities.Fact, n)
+	for i := range facts {
+		facts[i] = entities.Fact{
+			ID:        subject + "-" + string(rune('a'+i)),
+			Type:      entities.FactTypeLocation,
+			Subject:   subject,
+			Predicate: "is",
+			Object:    "green",
+		}
+	}
+	return facts
+}
+
+func TestCanonicalService_Report(t *testing.T) {
+	var facts []entities.Fact
+	facts = append(facts, subjectFacts("Shire", 3)...)
+	facts = append(facts, subjectFacts("The Shire", 2)...)
+	facts = append(facts, subjectFacts("the shire", 1)...)
+	facts = append(facts, subjectFacts("Frodo Baggins", 2)...)
+	facts = append(facts, subjectFacts("Frodo Bagins", 1)...)
+	facts = append(facts, subjectFacts("Book 1", 1)...)
+	facts = append(facts, subjectFacts("Book 2", 1)...)
+	facts = append(facts, subjectFacts("Gandalf", 4)...)
+
+	tests := []struct {
+		name       string
+		opts       SubjectReportOptions
+		wantGroups []SubjectGroup
+	}{
+		{
+			name: "fuzzy",
+			wantGroups: []SubjectGroup{
+				{Canonical: "Shire", Variants: []SubjectVariant{{"Shire", 3}, {"The Shire", 2}, {"the shire", 1}}},
+				{Canonical: "Frodo Baggins", Variants: []SubjectVariant{{"Frodo Baggins", 2}, {"Frodo Bagins", 1}}, Fuzzy: true},
+			},
+		},
+		{
+			name: "exact",
+			opts: SubjectReportOptions{Exact: true},
+			wantGroups: []SubjectGroup{
+				{Canonical: "Shire", Variants: []SubjectVariant{{"Shire", 3}, {"The Shire", 2}, {"the shire", 1}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newFactTestService(facts...)
+			canonical := NewCanonicalService(svc.vectorDB, svc.relationalDB, svc)
+
+			report, err := canonical.Report(context.Background(), tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, len(facts), report.Facts)
+			assert.Equal(t, 8, report.Subjects)
+			assert.Equal(t, tt.wantGroups, report.Groups)
+		})
+	}
+}
+
+func TestCanonicalService_Apply(t *testing.T) {
+	facts := append(subjectFacts("Shire", 1), subjectFacts("The Shire", 1)...)
+	facts = append(facts, entities.Fact{
+		ID: "frodo", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "the shire",
+	})
+	svc, vectorDB, relationalDB := newFactTestService(facts...)
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+	ctx := context.Background()
+
+	shire, err := relationalDB.FindOrCreateEntity(ctx, "world", "Shire")
+	require.NoError(t, err)
+	theShire, err := relationalDB.FindOrCreateEntity(ctx, "world", "The Shire")
+	require.NoError(t, err)
+	frodo, err := relationalDB.FindOrCreateEntity(ctx, "world", "Frodo")
+	require.NoError(t, err)
+	require.NoError(t, relationalDB.SaveRelationship(ctx, &entities.Relationship{
+		ID: "rel", SourceEntityID: frodo.ID, TargetEntityID: theShire.ID, Type: entities.RelationLocatedIn,
+	}))
+
+	groups := []SubjectGroup{{
+		Canonical: "Shire",
+		Variants:  []SubjectVariant{{"Shire", 1}, {"The Shire", 1}, {"the shire", 0}},
+	}}
+	result, err := canonical.Apply(ctx, "world", groups)
+	require.NoError(t, err)
+
+	assert.Equal(t, CanonicalizeResult{FactsRewritten: 2, EntitiesMerged: 1}, result)
+
+	assert.Equal(t, 1, vectorDB.SaveBatchCallCount, "facts are saved in one batch")
+	saved := vectorDB.SaveBatchLastFacts
+	require.Len(t, saved, 2)
+	assert.Equal(t, "Shire", saved[0].Subject)
+	assert.Equal(t, "Frodo", saved[1].Subject)
+	assert.Equal(t, "Shire", saved[1].Object)
+
+	latest, err := relationalDB.FindLatestVersion(ctx, "frodo")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, `canonicalized "the shire" as "Shire"`, latest.Reason)
+
+	assert.NotContains(t, relationalDB.Entities, theShire.ID)
+	require.Len(t, relationalDB.Relationships, 1)
+	assert.Equal(t, shire.ID, relationalDB.Relationships[0].TargetEntityID)
+}
+
+func TestCanonicalService_Apply_CreatesCanonicalEntity(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService()
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+	ctx := context.Background()
+
+	variant, err := relationalDB.FindOrCreateEntity(ctx, "world", "The Shire")
+	require.NoError(t, err)
+
+	groups := []SubjectGroup{{
+		Canonical: "Shire",
+		Variants:  []SubjectVariant{{"Shire", 3}, {"The Shire", 2}},
+	}}
+	result, err := canonical.Apply(ctx, "world", groups)
+	require.NoError(t, err)
+
+	assert.Equal(t, CanonicalizeResult{EntitiesMerged: 1}, result)
+	assert.NotContains(t, relationalDB.Entities, variant.ID)
+	shire, err := relationalDB.FindEntityByName(ctx, "world", "Shire")
+	require.NoError(t, err)
+	require.NotNil(t, shire)
+	assert.Equal(t, "Shire", shire.Name)
+}
+
+func TestCanonicalService_Apply_RespellsEntity(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService()
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+	ctx := context.Background()
+
+	entity, err := relationalDB.FindOrCreateEntity(ctx, "world", "the shire")
+	require.NoError(t, err)
+
+	groups := []SubjectGroup{{
+		Canonical: "The Shire",
+		Variants:  []SubjectVariant{{"The Shire", 3}, {"the shire", 2}},
+	}}
+	result, err := canonical.Apply(ctx, "world", groups)
+	require.NoError(t, err)
+
+	assert.Equal(t, CanonicalizeResult{EntitiesRenamed: 1}, result)
+	assert.Equal(t, "The Shire", relationalDB.Entities[entity.ID].Name)
+}
+
+func TestSubjectKey(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{subject: "The Shire", want: "shire"},
+		{subject: "  the   shire. ", want: "shire"},
+		{subject: "The", want: "the"},
+		{subject: "Helm's Deep", want: "helm's deep"},
+		{subject: "Minas-Tirith", want: "minas tirith"},
+		{subject: "ΟΔΥΣΣΕΥΣ", want: "οδυσσευσ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			assert.Equal(t, tt.want, subjectKey(tt.subject))
+		})
+	}
+}
+
+func TestCanonicalService_Rename(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Strider", Predicate: "wields", Object: "Anduril"},
+		{ID: "2", Type: entities.FactTypeCharacter, Subject: "strider", Predicate: "guides", Object: "the hobbits"},
+		{ID: "3", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "trusts", Object: "Strider"},
+		{ID: "4", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "doubts", Object: "Bill Ferny"},
+	}
+	svc, vectorDB, relationalDB := newFactTestService(facts...)
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+	ctx := context.Background()
+
+	strider, err := relationalDB.FindOrCreateEntity(ctx, "world", "Strider")
+	require.NoError(t, err)
+
+	result, err := canonical.Rename(ctx, "world", "Strider", "Aragorn")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.FactsRewritten)
+	require.NotNil(t, result.Entity)
+	assert.Equal(t, strider.ID, result.Entity.ID)
+
+	renamed, err := relationalDB.FindEntityByName(ctx, "world", "Aragorn")
+	require.NoError(t, err)
+	require.NotNil(t, renamed)
+	assert.Equal(t, strider.ID, renamed.ID)
+
+	assert.Equal(t, 1, vectorDB.SaveBatchCallCount, "facts are saved in one batch")
+	saved := vectorDB.SaveBatchLastFacts
+	require.Len(t, saved, 3)
+	assert.Equal(t, "Aragorn", saved[0].Subject)
+	assert.Equal(t, "Aragorn", saved[1].Subject)
+	assert.Equal(t, "Aragorn", saved[2].Object)
+	assert.NotEmpty(t, saved[2].Embedding, "rewritten facts are embedded again")
+
+	latest, err := relationalDB.FindLatestVersion(ctx, "2")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, `renamed "Strider" as "Aragorn"`, latest.Reason)
+	assert.Equal(t, "Aragorn", latest.Data.Subject)
+}
+
+func TestCanonicalService_Rename_Pages(t *testing.T) {
+	// The mock pages like Qdrant, whose cursors are fact IDs, not counts
+	facts := make([]entities.Fact, canonicalPageSize+1)
+	for i := range facts {
+		facts[i] = entities.Fact{ID: uuid.NewString(), Type: entities.FactTypeCharacter, Subject: "Strider", Predicate: "walks", Object: "north"}
+	}
+	svc, vectorDB, relationalDB := newFactTestService(facts...)
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+
+	result, err := canonical.Rename(context.Background(), "world", "Strider", "Aragorn")
+	require.NoError(t, err)
+	assert.Equal(t, len(facts), result.FactsRewritten, "facts on every page are rewritten")
+	assert.Equal(t, 2, vectorDB.SaveBatchCallCount, "facts are saved a page at a time")
+}
+
+func TestCanonicalService_Rename_Errors(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService()
+	canonical := NewCanonicalService(vectorDB, relationalDB, svc)
+	ctx := context.Background()
+
+	_, err := relationalDB.FindOrCreateEntity(ctx, "world", "Strider")
+	require.NoError(t, err)
+	_, err = relationalDB.FindOrCreateEntity(ctx, "world", "Aragorn")
+	require.NoError(t, err)
+
+	_, err = canonical.Rename(ctx, "world", "Strider", "aragorn")
+	assert.ErrorIs(t, err, entities.ErrConflict)
+
+	_, err = canonical.Rename(ctx, "world", "Gollum", "Smeagol")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+
+	_, err = canonical.Rename(ctx, "world", "Strider", " ")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
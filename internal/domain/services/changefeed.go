@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultChangeFeedLimit is how many changes a page of the change feed
+// holds when no limit is given.
+const DefaultChangeFeedLimit = 1000
+
+// ChangeFeedVectorDB is a ports.VectorDB that records every fact it saves
+// or deletes in the change feed.
+type ChangeFeedVectorDB struct {
+	ports.VectorDB
+	relationalDB ports.RelationalDB
+	now          func() time.Time
+}
+
+// NewChangeFeedVectorDB wraps db so its writes are recorded in relationalDB's
+// change feed.
+func NewChangeFeedVectorDB(db ports.VectorDB, relationalDB ports.RelationalDB) *ChangeFeedVectorDB {
+	return &ChangeFeedVectorDB{VectorDB: db, relationalDB: relationalDB, now: time.Now}
+}
+
+// Save stores a fact with its embedding.
+func (d *ChangeFeedVectorDB) Save(ctx context.Context, fact *entities.Fact) error {
+	exists, err := d.exists(ctx, []entities.Fact{*fact})
+	if err != nil {
+		return err
+	}
+	if err := d.VectorDB.Save(ctx, fact); err != nil {
+		return err
+	}
+	return d.recordSaved(ctx, []entities.Fact{*fact}, exists)
+}
+
+// SaveBatch stores multiple facts.
+func (d *ChangeFeedVectorDB) SaveBatch(ctx context.Context, facts []entities.Fact) error {
+	exists, err := d.exists(ctx, facts)
+	if err != nil {
+		return err
+	}
+	if err := d.VectorDB.SaveBatch(ctx, facts); err != nil {
+		return err
+	}
+	return d.recordSaved(ctx, facts, exists)
+}
+
+// Delete removes a fact by its ID.
+func (d *ChangeFeedVectorDB) Delete(ctx context.Context, id string) error {
+	if err := d.VectorDB.Delete(ctx, id); err != nil {
+		return err
+	}
+	return d.record(ctx, []entities.Change{{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, ID: id, ChangedAt: d.now()}})
+}
+
+// DeleteBySource removes all facts from a source file.
+func (d *ChangeFeedVectorDB) DeleteBySource(ctx context.Context, sourceFile string) error {
+	if err := d.VectorDB.DeleteBySource(ctx, sourceFile); err != nil {
+		return err
+	}
+	return d.record(ctx, []entities.Change{{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, Source: sourceFile, ChangedAt: d.now()}})
+}
+
+// DeleteAll removes all facts.
+func (d *ChangeFeedVectorDB) DeleteAll(ctx context.Context) error {
+	if err := d.VectorDB.DeleteAll(ctx); err != nil {
+		return err
+	}
+	return d.record(ctx, []entities.Change{{Kind: entities.ChangeKindFact, Op: entities.ChangeOpCleared, ChangedAt: d.now()}})
+}
+
+// exists reports which of the facts are already stored, so saving them is
+// an update rather than an addition.
+func (d *ChangeFeedVectorDB) exists(ctx context.Context, facts []entities.Fact) (map[string]bool, error) {
+	ids := make([]string, 0, len(facts))
+	for i := range facts {
+		if facts[i].ID != "" {
+			ids = append(ids, facts[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return d.VectorDB.ExistsByIDs(ctx, ids)
+}
+
+// recordSaved records saved facts, without their embeddings.
+func (d *ChangeFeedVectorDB) recordSaved(ctx context.Context, facts []entities.Fact, exists map[string]bool) error {
+	now := d.now()
+	changes := make([]entities.Change, len(facts))
+	for i := range facts {
+		fact := facts[i]
+		fact.Embedding = nil
+		fact.TextEmbedding = nil
+		op := entities.ChangeOpAdded
+		if exists[fact.ID] {
+			op = entities.ChangeOpUpdated
+		}
+		changes[i] = entities.Change{Kind: entities.ChangeKindFact, Op: op, ID: fact.ID, Source: fact.SourceFile, Fact: &fact, ChangedAt: now}
+	}
+	return d.record(ctx, changes)
+}
+
+// record appends changes to the feed. The write they describe is done, so
+// it is recorded even if the caller has gone.
+func (d *ChangeFeedVectorDB) record(ctx context.Context, changes []entities.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := d.relationalDB.RecordChanges(context.WithoutCancel(ctx), changes); err != nil {
+		return fmt.Errorf("recording changes: %w", err)
+	}
+	return nil
+}
+
+// ChangeFeedService reads a world's change feed page by page, so other
+// systems can follow its facts and relationships without exporting
+// everything again.
+type ChangeFeedService struct {
+	relationalDB ports.RelationalDB
+}
+
+// NewChangeFeedService creates a new ChangeFeedService.
+func NewChangeFeedService(relationalDB ports.RelationalDB) *ChangeFeedService {
+	return &ChangeFeedService{relationalDB: relationalDB}
+}
+
+// Changes returns up to limit changes made after cursor (0 =
+// DefaultChangeFeedLimit), oldest first, and the cursor to resume from. An
+// empty cursor starts from the beginning of the feed. With no new changes
+// the cursor returned is the one given.
+func (s *ChangeFeedService) Changes(ctx context.Context, cursor string, limit int) ([]entities.Change, string, error) {
+	after, err := ParseChangeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = DefaultChangeFeedLimit
+	}
+	changes, err := s.relationalDB.ListChanges(ctx, after, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("listing changes: %w", err)
+	}
+	if len(changes) > 0 {
+		after = changes[len(changes)-1].Seq
+	}
+	return changes, ChangeCursor(after), nil
+}
+
+// ChangeCursor returns the cursor resuming the change feed after seq.
+func ChangeCursor(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// ParseChangeCursor returns the seq a change feed cursor resumes after.
+func ParseChangeCursor(cursor string) (int64, error) {
+	cursor = strings.TrimSpace(cursor)
+	if cursor == "" {
+		return 0, nil
+	}
+	seq, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, entities.Errorf(entities.ErrValidation, "invalid change cursor %q", cursor)
+	}
+	return seq, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestChangeFeedVectorDB(t *testing.T) {
+	ctx := context.Background()
+	db := &mocks.VectorDB{Facts: []entities.Fact{{ID: "1", Subject: "Frodo"}}}
+	relationalDB := mocks.NewRelationalDB()
+	recorder := NewChangeFeedVectorDB(db, relationalDB)
+
+	require.NoError(t, recorder.Save(ctx, &entities.Fact{ID: "1", Subject: "Frodo", Object: "bold", Embedding: []float32{0.1}}))
+	require.NoError(t, recorder.SaveBatch(ctx, []entities.Fact{{ID: "2", Subject: "Sam", SourceFile: "ch1.md"}}))
+	require.NoError(t, recorder.Delete(ctx, "1"))
+	require.NoError(t, recorder.DeleteBySource(ctx, "ch1.md"))
+	require.NoError(t, recorder.DeleteAll(ctx))
+
+	changes := relationalDB.Changes
+	require.Len(t, changes, 5)
+	assert.Equal(t, entities.ChangeOpUpdated, changes[0].Op, "fact 1 was already stored")
+	assert.Equal(t, "bold", changes[0].Fact.Object)
+	assert.Nil(t, changes[0].Fact.Embedding, "embeddings are left out")
+	assert.Equal(t, entities.ChangeOpAdded, changes[1].Op)
+	assert.Equal(t, "ch1.md", changes[1].Source)
+	assert.Equal(t, entities.Change{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, ID: "1"}, withoutTimes(changes[2]))
+	assert.Equal(t, entities.Change{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, Source: "ch1.md"}, withoutTimes(changes[3]))
+	assert.Equal(t, entities.ChangeOpCleared, changes[4].Op)
+
+	db.Err = errors.New("qdrant down")
+	require.Error(t, recorder.Save(ctx, &entities.Fact{ID: "3"}))
+	assert.Len(t, relationalDB.Changes, 5, "failed writes aren't recorded")
+}
+
+// withoutTimes clears the seq and time a change was recorded with.
+func withoutTimes(c entities.Change) entities.Change {
+	c.Seq = 0
+	c.ChangedAt = time.Time{}
+	return c
+}
+
+func TestChangeFeedService_Changes(t *testing.T) {
+	ctx := context.Background()
+	relationalDB := mocks.NewRelationalDB()
+	require.NoError(t, relationalDB.RecordChanges(ctx, []entities.Change{
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "1"},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "2"},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, ID: "1"},
+	}))
+	feed := NewChangeFeedService(relationalDB)
+
+	changes, cursor, err := feed.Changes(ctx, "", 2)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, "2", cursor)
+
+	changes, cursor, err = feed.Changes(ctx, cursor, 2)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, entities.ChangeOpDeleted, changes[0].Op)
+	assert.Equal(t, "3", cursor)
+
+	changes, cursor, err = feed.Changes(ctx, cursor, 0)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, "3", cursor, "no new changes keeps the cursor")
+
+	_, _, err = feed.Changes(ctx, "yesterday", 0)
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
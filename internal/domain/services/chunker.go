@@ -0,0 +1,455 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// DefaultSentenceOverlap is how many sentences a sentence-window chunk
+	// repeats from the end of the previous one.
+	DefaultSentenceOverlap = 2
+	// DefaultChunkTokens is the size of a fixed-token chunk. Words count as
+	// tokens, so this is about DefaultChunkSize characters of prose.
+	DefaultChunkTokens = 400
+	// DefaultChunkTokenOverlap is how many tokens a fixed-token chunk
+	// repeats from the end of the previous one.
+	DefaultChunkTokenOverlap = 40
+	// DefaultMaxChunkBytes bounds the memory a chunker holds for one
+	// chunk: the longest line, paragraph, or section it reads before
+	// splitting. Text with none this long is prose; text with one is
+	// usually binary or minified.
+	DefaultMaxChunkBytes entities.ByteSize = 1 << 20
+)
+
+// NewChunker returns the chunker for a strategy, with the default sizes.
+func NewChunker(strategy ports.ChunkStrategy) (ports.Chunker, error) {
+	return NewChunkerWithLimit(strategy, DefaultMaxChunkBytes)
+}
+
+// NewChunkerWithLimit returns the chunker for a strategy that fails with
+// a validation error, rather than hold more than limit bytes, at a line,
+// paragraph, or section too long to split (0 = DefaultMaxChunkBytes).
+func NewChunkerWithLimit(strategy ports.ChunkStrategy, limit entities.ByteSize) (ports.Chunker, error) {
+	if limit <= 0 {
+		limit = DefaultMaxChunkBytes
+	}
+	switch strategy {
+	case ports.ChunkParagraph:
+		return paragraphChunker{limit: int(limit)}, nil
+	case ports.ChunkSentenceWindow:
+		return sentenceWindowChunker{size: DefaultChunkSize, overlap: DefaultSentenceOverlap, limit: int(limit)}, nil
+	case ports.ChunkMarkdownHeading:
+		return markdownHeadingChunker{size: DefaultChunkSize, limit: int(limit)}, nil
+	case ports.ChunkFixedToken:
+		return fixedTokenChunker{tokens: DefaultChunkTokens, overlap: DefaultChunkTokenOverlap, limit: int(limit)}, nil
+	}
+
+	names := make([]string, len(ports.ChunkStrategies))
+	for i, s := range ports.ChunkStrategies {
+		names[i] = string(s)
+	}
+	return nil, entities.Errorf(entities.ErrValidation, "invalid chunker %q (valid: %s)", strategy, strings.Join(names, ", "))
+}
+
+// chunkLimit returns limit, or DefaultMaxChunkBytes if it is unset.
+func chunkLimit(limit int) int {
+	if limit <= 0 {
+		return int(DefaultMaxChunkBytes)
+	}
+	return limit
+}
+
+// newLineScanner returns a scanner of the lines of r that holds lines of
+// up to limit bytes.
+func newLineScanner(r io.Reader, limit int) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(64*1024, limit)), limit)
+	return scanner
+}
+
+// scanErr describes the error of a scanner stopped after line lines.
+func scanErr(err error, line, limit int) error {
+	if errors.Is(err, bufio.ErrTooLong) {
+		return entities.Errorf(entities.ErrValidation,
+			"line %d is longer than %s, the most one chunk may hold; the file may be binary or minified", line+1, entities.ByteSize(limit))
+	}
+	return fmt.Errorf("reading input: %w", err)
+}
+
+// errTooLong reports a paragraph or section that would make a chunk
+// larger than limit bytes, ending at line.
+func errTooLong(what string, line, limit int) error {
+	return entities.Errorf(entities.ErrValidation,
+		"the %s ending at line %d is longer than %s, the most one chunk may hold; split it with blank lines", what, line, entities.ByteSize(limit))
+}
+
+// scanLines calls fn with each line read from r, failing at a line longer
+// than limit bytes.
+func scanLines(r io.Reader, limit int, fn func(line string) error) error {
+	limit = chunkLimit(limit)
+	scanner := newLineScanner(r, limit)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return scanErr(err, lines, limit)
+	}
+	return nil
+}
+
+// paragraphChunker packs whole paragraphs into chunks of up to
+// DefaultChunkSize characters, each starting with the last
+// DefaultChunkOverlap characters of the one before. A paragraph longer
+// than limit bytes is an error.
+type paragraphChunker struct {
+	limit int
+}
+
+// Chunk implements ports.Chunker.
+func (c paragraphChunker) Chunk(r io.Reader, emit func(chunk string) error) error {
+	chunker := newStreamChunker(r, chunkLimit(c.limit))
+	for chunker.scanner.Scan() {
+		if err := chunker.processLine(chunker.scanner.Text(), emit); err != nil {
+			return err
+		}
+	}
+	if err := chunker.scanner.Err(); err != nil {
+		return scanErr(err, chunker.lines, chunker.limit)
+	}
+	return chunker.flush(emit)
+}
+
+// sentenceWindowChunker packs whole sentences into chunks of up to size
+// characters, each starting with the last overlap sentences of the one
+// before. A paragraph longer than limit bytes is an error.
+type sentenceWindowChunker struct {
+	size    int
+	overlap int
+	limit   int
+}
+
+// sentence is a sentence and whether it starts a paragraph.
+type sentence struct {
+	text      string
+	paragraph bool
+}
+
+// Chunk implements ports.Chunker.
+func (c sentenceWindowChunker) Chunk(r io.Reader, emit func(chunk string) error) error {
+	var (
+		window    []sentence
+		length    int
+		fresh     bool // Whether the window has sentences not yet emitted
+		paragraph strings.Builder
+		lines     int
+		limit     = chunkLimit(c.limit)
+	)
+
+	add := func(s sentence) error {
+		if length+len(s.text)+2 > c.size && fresh {
+			if err := emit(joinSentences(window)); err != nil {
+				return err
+			}
+			keep := min(c.overlap, len(window)-1)
+			window = append(window[:0], window[len(window)-keep:]...)
+			length = 0
+			for _, w := range window {
+				length += len(w.text) + 2
+			}
+		}
+		window = append(window, s)
+		length += len(s.text) + 2
+		fresh = true
+		return nil
+	}
+
+	endParagraph := func() error {
+		for i, text := range splitSentences(paragraph.String()) {
+			if err := add(sentence{text: text, paragraph: i == 0}); err != nil {
+				return err
+			}
+		}
+		paragraph.Reset()
+		return nil
+	}
+
+	err := scanLines(r, limit, func(line string) error {
+		lines++
+		if strings.TrimSpace(line) == "" {
+			return endParagraph()
+		}
+		if paragraph.Len()+len(line)+1 > limit {
+			return errTooLong("paragraph", lines, limit)
+		}
+		if paragraph.Len() > 0 {
+			paragraph.WriteString(" ")
+		}
+		paragraph.WriteString(strings.TrimSpace(line))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := endParagraph(); err != nil {
+		return err
+	}
+
+	if fresh {
+		return emit(joinSentences(window))
+	}
+	return nil
+}
+
+// joinSentences joins sentences with a space, or a blank line before one
+// that starts a paragraph.
+func joinSentences(sentences []sentence) string {
+	var b strings.Builder
+	for i, s := range sentences {
+		if i > 0 {
+			if s.paragraph {
+				b.WriteString("\n\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
+
+// splitSentences splits text after each '.', '!', '?', or '…' that is
+// followed, after any closing quotes or brackets, by the end or by a space
+// and a word not starting in lowercase, so "\"Run!\" he said." is one
+// sentence.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !strings.ContainsRune(".!?…", r) {
+			continue
+		}
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if !strings.ContainsRune(".!?…\"')]”’", r) {
+				break
+			}
+			i += size
+		}
+		if r, _ := utf8.DecodeRuneInString(text[i:]); i == len(text) || unicode.IsSpace(r) && !nextIsLower(text[i:]) {
+			if s := strings.TrimSpace(text[start:i]); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = i
+		}
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// nextIsLower reports whether the first letter after leading spaces in
+// text is lowercase.
+func nextIsLower(text string) bool {
+	r, _ := utf8.DecodeRuneInString(strings.TrimLeftFunc(text, unicode.IsSpace))
+	return unicode.IsLower(r)
+}
+
+// markdownHeadingChunker starts a chunk at each Markdown heading. Each
+// chunk begins with the headings of the sections enclosing it, and a
+// section longer than size is split by paragraphs with its headings
+// repeated at the start of every part. A section longer than limit bytes
+// is an error.
+type markdownHeadingChunker struct {
+	size  int
+	limit int
+}
+
+// Chunk implements ports.Chunker.
+func (c markdownHeadingChunker) Chunk(r io.Reader, emit func(chunk string) error) error {
+	var (
+		headings [6]string // Enclosing heading of each level
+		trail    string    // Enclosing headings of the current section
+		body     strings.Builder
+		fenced   bool
+		lines    int
+		limit    = chunkLimit(c.limit)
+	)
+
+	flush := func() error {
+		text := strings.TrimSpace(body.String())
+		body.Reset()
+		if text == "" || text == trail {
+			return nil
+		}
+		for i, part := range ChunkText(text, c.size, DefaultChunkOverlap) {
+			if i > 0 && trail != "" {
+				part = trail + "\n\n" + part
+			}
+			if err := emit(part); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := scanLines(r, limit, func(line string) error {
+		lines++
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		level := headingLevel(line)
+		if fenced || level == 0 {
+			if strings.TrimSpace(line) == "" && strings.HasSuffix(body.String(), "\n\n") {
+				return nil
+			}
+			if body.Len()+len(line)+1 > limit {
+				return errTooLong("section", lines, limit)
+			}
+			body.WriteString(line)
+			body.WriteString("\n")
+			return nil
+		}
+
+		if err := flush(); err != nil {
+			return err
+		}
+		headings[level-1] = strings.TrimSpace(line)
+		clear(headings[level:])
+		var enclosing []string
+		for _, h := range headings {
+			if h != "" {
+				enclosing = append(enclosing, h)
+			}
+		}
+		trail = strings.Join(enclosing, "\n")
+		body.WriteString(trail)
+		body.WriteString("\n\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// headingLevel returns the level of a Markdown ATX heading line, such as 2
+// for "## Gondor", or 0 if line is not a heading.
+func headingLevel(line string) int {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return 0
+	}
+	level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+	if level == 0 || level > 6 {
+		return 0
+	}
+	if rest := trimmed[level:]; rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0
+	}
+	return level
+}
+
+// fixedTokenChunker cuts chunks of tokens tokens, each starting with the
+// last overlap tokens of the one before. Whitespace-separated words count
+// as tokens, and line breaks are kept. A line longer than limit bytes is
+// an error.
+type fixedTokenChunker struct {
+	tokens  int
+	overlap int
+	limit   int
+}
+
+// Chunk implements ports.Chunker.
+func (c fixedTokenChunker) Chunk(r io.Reader, emit func(chunk string) error) error {
+	var (
+		window []string // Tokens, each with the line break before it if any
+		fresh  int      // Tokens in the window not yet emitted
+	)
+
+	err := scanLines(r, c.limit, func(line string) error {
+		for i, word := range strings.Fields(line) {
+			if i == 0 && len(window) > 0 {
+				word = "\n" + word
+			}
+			window = append(window, word)
+			fresh++
+			if len(window) < c.tokens {
+				continue
+			}
+			if err := emit(joinTokens(window)); err != nil {
+				return err
+			}
+			window = append(window[:0], window[len(window)-c.overlap:]...)
+			fresh = 0
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if fresh > 0 {
+		return emit(joinTokens(window))
+	}
+	return nil
+}
+
+// joinTokens joins tokens with a space, except after a line break.
+func joinTokens(tokens []string) string {
+	var b strings.Builder
+	for i, token := range tokens {
+		if i > 0 && !strings.HasPrefix(token, "\n") {
+			b.WriteString(" ")
+		}
+		b.WriteString(token)
+	}
+	return strings.TrimPrefix(b.String(), "\n")
+}
+
+// wordCounter counts the whitespace-separated words written to it, as
+// strings.Fields would split the whole text.
+type wordCounter struct {
+	words   int
+	inWord  bool
+	partial []byte // Start of a rune split across writes
+}
+
+// Write implements io.Writer.
+func (w *wordCounter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(w.partial) > 0 {
+		p = append(w.partial, p...)
+		w.partial = nil
+	}
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		if r == utf8.RuneError && size == 1 && !utf8.FullRune(p) {
+			w.partial = append([]byte(nil), p...)
+			break
+		}
+		p = p[size:]
+		if unicode.IsSpace(r) {
+			w.inWord = false
+		} else if !w.inWord {
+			w.inWord = true
+			w.words++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// chunk returns the chunks c splits text into.
+func chunk(t *testing.T, c ports.Chunker, text string) []string {
+	t.Helper()
+	var chunks []string
+	require.NoError(t, c.Chunk(strings.NewReader(text), func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	return chunks
+}
+
+func TestNewChunker(t *testing.T) {
+	for _, strategy := range ports.ChunkStrategies {
+		c, err := NewChunker(strategy)
+		require.NoError(t, err, strategy)
+		assert.NotNil(t, c)
+	}
+
+	_, err := NewChunker("scene")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), "sentence-window")
+}
+
+func TestParagraphChunker_MatchesChunkText(t *testing.T) {
+	text := strings.Join([]string{
+		"Frodo left the Shire. " + strings.Repeat("a", 1500),
+		"Sam followed him. " + strings.Repeat("b", 1500),
+	}, "\n\n")
+
+	assert.Equal(t, ChunkText(text, DefaultChunkSize, DefaultChunkOverlap), chunk(t, paragraphChunker{}, text))
+}
+
+func TestSentenceWindowChunker(t *testing.T) {
+	c := sentenceWindowChunker{size: 60, overlap: 1}
+	text := "Frodo left the Shire. Sam followed him.\nThey walked east!\n\nAt Bree they met Strider. He was a ranger."
+
+	chunks := chunk(t, c, text)
+	assert.Equal(t, []string{
+		"Frodo left the Shire. Sam followed him.",
+		"Sam followed him. They walked east!",
+		"They walked east!\n\nAt Bree they met Strider.",
+		"At Bree they met Strider. He was a ranger.",
+	}, chunks)
+}
+
+func TestSplitSentences(t *testing.T) {
+	assert.Equal(t,
+		[]string{`"Run!" he said.`, "It was 3.5 leagues… to go?", "(Yes.)", "Then"},
+		splitSentences(`"Run!" he said. It was 3.5 leagues… to go? (Yes.) Then`))
+	assert.Empty(t, splitSentences("  "))
+}
+
+func TestMarkdownHeadingChunker(t *testing.T) {
+	c := markdownHeadingChunker{size: DefaultChunkSize}
+	text := strings.Join([]string{
+		"Intro text.",
+		"# Gondor",
+		"A kingdom of men.",
+		"## Minas Tirith",
+		"The white city.",
+		"```",
+		"# not a heading",
+		"```",
+		"## Osgiliath",
+		"# Rohan",
+		"Horse lords.",
+	}, "\n")
+
+	assert.Equal(t, []string{
+		"Intro text.",
+		"# Gondor\n\nA kingdom of men.",
+		"# Gondor\n## Minas Tirith\n\nThe white city.\n```\n# not a heading\n```",
+		"# Rohan\n\nHorse lords.",
+	}, chunk(t, c, text), "empty sections are skipped")
+
+	long := "# Gondor\n\n" + strings.Repeat("a", 30) + "\n\n" + strings.Repeat("b", 30)
+	chunks := chunk(t, markdownHeadingChunker{size: 50}, long)
+	require.Len(t, chunks, 2)
+	assert.True(t, strings.HasPrefix(chunks[1], "# Gondor\n\n"), "split sections repeat their headings")
+}
+
+func TestHeadingLevel(t *testing.T) {
+	assert.Equal(t, 1, headingLevel("# Gondor"))
+	assert.Equal(t, 3, headingLevel("   ### Gondor"))
+	assert.Equal(t, 2, headingLevel("##"))
+	assert.Zero(t, headingLevel("#hashtag"))
+	assert.Zero(t, headingLevel("    # code"))
+	assert.Zero(t, headingLevel("####### seven"))
+	assert.Zero(t, headingLevel("plain"))
+}
+
+func TestFixedTokenChunker(t *testing.T) {
+	c := fixedTokenChunker{tokens: 4, overlap: 1}
+	text := "INT. BAG END - DAY\n\nFRODO\nWhere is Gandalf?"
+
+	assert.Equal(t, []string{
+		"INT. BAG END -",
+		"- DAY\nFRODO\nWhere",
+		"Where is Gandalf?",
+	}, chunk(t, c, text))
+	assert.Equal(t, []string{"one two"}, chunk(t, c, "one two"))
+	assert.Empty(t, chunk(t, c, "\n\n"))
+}
+
+func TestChunker_StopsOnError(t *testing.T) {
+	text := "# A\n\nFirst.\n\n# B\n\nSecond."
+	for _, strategy := range ports.ChunkStrategies {
+		c, err := NewChunker(strategy)
+		require.NoError(t, err)
+
+		calls := 0
+		err = c.Chunk(strings.NewReader(text), func(string) error {
+			calls++
+			return errors.New("stop")
+		})
+		require.Error(t, err, strategy)
+		assert.Equal(t, 1, calls, strategy)
+	}
+}
+
+func TestChunker_Limit(t *testing.T) {
+	const limit = 256
+	longLine := strings.Repeat("x", limit+1)
+	longParagraph := strings.Repeat("Frodo walked on.\n", 20)
+
+	for _, strategy := range ports.ChunkStrategies {
+		c, err := NewChunkerWithLimit(strategy, limit)
+		require.NoError(t, err)
+
+		text := "# A\n\nFirst.\n\n" + longLine
+		err = c.Chunk(strings.NewReader(text), func(string) error { return nil })
+		require.ErrorIs(t, err, entities.ErrValidation, strategy)
+		assert.Contains(t, err.Error(), "line 5 is longer than 256 B", strategy)
+
+		err = c.Chunk(strings.NewReader("# A\n\n"+longParagraph), func(string) error { return nil })
+		if strategy == ports.ChunkFixedToken {
+			assert.NoError(t, err, "fixed-token chunks hold no more than a line")
+			continue
+		}
+		require.ErrorIs(t, err, entities.ErrValidation, strategy)
+		assert.Contains(t, err.Error(), "ending at line", strategy)
+	}
+
+	c, err := NewChunkerWithLimit(ports.ChunkParagraph, 0)
+	require.NoError(t, err)
+	assert.Len(t, chunk(t, c, longParagraph), 1, "the default limit holds the paragraph")
+}
+
+func TestWordCounter(t *testing.T) {
+	text := "Frodo  left\nthe Shire — at dawn. Éowyn"
+	w := &wordCounter{}
+	// Write a byte at a time to split words and runes across writes
+	for i := range len(text) {
+		_, err := w.Write([]byte{text[i]})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, len(strings.Fields(text)), w.words)
+}
+
+// textRecordingLLM records the chunks facts are extracted from, and returns
+// a copy of the facts for each.
+type textRecordingLLM struct {
+	*mocks.LLMClient
+	texts []string
+}
+
+func (l *textRecordingLLM) ExtractFactsWithContext(ctx context.Context, text, priorContext string, validTypes []string) ([]entities.Fact, error) {
+	l.texts = append(l.texts, text)
+	facts, err := l.LLMClient.ExtractFactsWithContext(ctx, text, priorContext, validTypes)
+	return slices.Clone(facts), err
+}
+
+func TestExtractionService_Chunker(t *testing.T) {
+	text := "# Gondor\n\nA kingdom.\n\n# Rohan\n\nHorse lords."
+
+	tests := []struct {
+		name    string
+		extract func(*ExtractionService, ExtractionOptions) (*ExtractionResult, error)
+	}{
+		{"stream", func(svc *ExtractionService, opts ExtractionOptions) (*ExtractionResult, error) {
+			return svc.ExtractFromReader(t.Context(), strings.NewReader(text), "world.md", &opts)
+		}},
+		{"string", func(svc *ExtractionService, opts ExtractionOptions) (*ExtractionResult, error) {
+			return svc.ExtractAndStoreWithOptions(t.Context(), text, "world.md", &opts)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &textRecordingLLM{LLMClient: &mocks.LLMClient{}}
+			base, vectorDB := newMockExtractionService(llm.LLMClient)
+			svc := NewExtractionService(llm, base.embedder, vectorDB, base.entityTypeService)
+
+			result, err := tt.extract(svc, ExtractionOptions{Chunker: markdownHeadingChunker{size: DefaultChunkSize}})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"# Gondor\n\nA kingdom.", "# Rohan\n\nHorse lords."}, llm.texts)
+			assert.Equal(t, 8, result.Words)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// DefaultClusterLimit caps how many facts are clustered.
+	DefaultClusterLimit = 2000
+
+	// DefaultClusterRepresentatives is the number of facts shown per cluster.
+	DefaultClusterRepresentatives = 5
+
+	// DefaultMinTopicSize is the smallest cluster not reported as orphaned.
+	DefaultMinTopicSize = 3
+
+	// maxAutoClusters bounds the cluster count chosen when none is given.
+	maxAutoClusters = 20
+
+	// maxClusterIterations bounds k-means refinement.
+	maxClusterIterations = 50
+
+	// labelSampleSize is the number of facts sent to the LLM per label.
+	labelSampleSize = 10
+
+	// clusterTopSubjects is the number of subjects listed per cluster.
+	clusterTopSubjects = 5
+
+	// clusterScrollBatch is the number of facts read per scroll request.
+	clusterScrollBatch = 256
+
+	// clusterSeed makes clustering repeatable across runs.
+	clusterSeed = 42
+)
+
+// errClusterLimit stops the scroll once enough facts have been read.
+var errClusterLimit = errors.New("cluster limit reached")
+
+// ClusterOptions controls how facts are clustered.
+type ClusterOptions struct {
+	K               int // Number of clusters (0 = chosen from the fact count)
+	Limit           int // Maximum facts to cluster (0 = DefaultClusterLimit)
+	Representatives int // Facts kept per cluster (0 = DefaultClusterRepresentatives)
+	MinTopicSize    int // Clusters smaller than this are orphaned (0 = DefaultMinTopicSize)
+}
+
+// Cluster is a group of facts whose embeddings lie close together.
+type Cluster struct {
+	Label           string
+	Size            int
+	Subjects        []SubjectCount  // Most common subjects, most frequent first
+	Representatives []entities.Fact // Facts closest to the cluster centre
+	Orphaned        bool            // Too small to be a real topic
+}
+
+// SubjectCount is a subject and how many facts in a cluster mention it.
+type SubjectCount struct {
+	Subject string
+	Count   int
+}
+
+// ClusterReport is the result of clustering a world's facts.
+type ClusterReport struct {
+	Facts     int  // Facts clustered
+	Truncated bool // More facts exist than were clustered
+	Clusters  []Cluster
+}
+
+// ClusterService groups a world's facts into themes by their embeddings.
+type ClusterService struct {
+	admin ports.VectorCollectionAdmin
+	llm   ports.LLMClient
+}
+
+// NewClusterService creates a new ClusterService.
+func NewClusterService(admin ports.VectorCollectionAdmin, llm ports.LLMClient) *ClusterService {
+	return &ClusterService{
+		admin: admin,
+		llm:   llm,
+	}
+}
+
+// Cluster reads facts with their embeddings from collection, groups them
+// with spherical k-means, and asks the LLM to name each group. Clusters are
+// returned largest first, with orphaned clusters last.
+func (s *ClusterService) Cluster(ctx context.Context, collection string, opts ClusterOptions) (*ClusterReport, error) {
+	if opts.K < 0 {
+		return nil, entities.Errorf(entities.ErrValidation, "cluster count must not be negative")
+	}
+	opts = withClusterDefaults(opts)
+
+	facts, truncated, err := s.loadFacts(ctx, collection, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	report := &ClusterReport{Facts: len(facts), Truncated: truncated}
+	if len(facts) == 0 {
+		return report, nil
+	}
+
+	k := opts.K
+	if k == 0 {
+		k = autoClusterCount(len(facts))
+	}
+	k = min(k, len(facts))
+
+	vectors := make([][]float64, len(facts))
+	for i := range facts {
+		vectors[i] = normalize(facts[i].Embedding)
+	}
+	assignments, centroids := kmeans(vectors, k, rand.New(rand.NewSource(clusterSeed)))
+
+	members := make([][]int, k)
+	for i, c := range assignments {
+		members[c] = append(members[c], i)
+	}
+
+	for c, idx := range members {
+		if len(idx) == 0 {
+			continue
+		}
+		sort.SliceStable(idx, func(a, b int) bool {
+			return dot(vectors[idx[a]], centroids[c]) > dot(vectors[idx[b]], centroids[c])
+		})
+
+		sample := make([]entities.Fact, 0, min(labelSampleSize, len(idx)))
+		for _, i := range idx[:min(labelSampleSize, len(idx))] {
+			sample = append(sample, facts[i])
+		}
+		label, err := s.llm.LabelTopic(ctx, sample)
+		if err != nil {
+			return nil, fmt.Errorf("labeling cluster %d: %w", c+1, err)
+		}
+
+		clusterFacts := make([]entities.Fact, len(idx))
+		for j, i := range idx {
+			clusterFacts[j] = facts[i]
+		}
+		report.Clusters = append(report.Clusters, Cluster{
+			Label:           label,
+			Size:            len(idx),
+			Subjects:        topSubjects(clusterFacts, clusterTopSubjects),
+			Representatives: sample[:min(opts.Representatives, len(sample))],
+			Orphaned:        len(idx) < opts.MinTopicSize,
+		})
+	}
+
+	sort.SliceStable(report.Clusters, func(i, j int) bool {
+		a, b := report.Clusters[i], report.Clusters[j]
+		if a.Orphaned != b.Orphaned {
+			return !a.Orphaned
+		}
+		return a.Size > b.Size
+	})
+
+	return report, nil
+}
+
+// withClusterDefaults returns opts with the defaults for the sizes it
+// leaves unset.
+func withClusterDefaults(opts ClusterOptions) ClusterOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultClusterLimit
+	}
+	if opts.Representatives <= 0 {
+		opts.Representatives = DefaultClusterRepresentatives
+	}
+	if opts.MinTopicSize <= 0 {
+		opts.MinTopicSize = DefaultMinTopicSize
+	}
+	return opts
+}
+
+// loadFacts reads up to limit active facts that have an embedding,
+// reporting whether more were available.
+func (s *ClusterService) loadFacts(ctx context.Context, collection string, limit int) ([]entities.Fact, bool, error) {
+	var (
+		facts     []entities.Fact
+		truncated bool
+	)
+	err := s.admin.ScrollFacts(ctx, collection, clusterScrollBatch, func(batch []entities.Fact) error {
+		for i := range batch {
+			if batch[i].IsPending() || len(batch[i].Embedding) == 0 {
+				continue
+			}
+			if len(facts) == limit {
+				truncated = true
+				return errClusterLimit
+			}
+			batch[i].Embedding = append([]float32(nil), batch[i].Embedding...)
+			batch[i].TextEmbedding = nil
+			facts = append(facts, batch[i])
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errClusterLimit) {
+		return nil, false, fmt.Errorf("reading facts: %w", err)
+	}
+	return facts, truncated, nil
+}
+
+// autoClusterCount picks a cluster count from the rule of thumb sqrt(n/2).
+func autoClusterCount(n int) int {
+	k := int(math.Round(math.Sqrt(float64(n) / 2)))
+	return max(2, min(k, maxAutoClusters))
+}
+
+// kmeans groups unit vectors into k clusters by cosine similarity, seeding
+// centroids with k-means++. It returns each vector's cluster and the
+// unit-length centroids.
+func kmeans(vectors [][]float64, k int, rng *rand.Rand) ([]int, [][]float64) {
+	centroids := seedCentroids(vectors, k, rng)
+	assignments := make([]int, len(vectors))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+
+	for iter := 0; iter < maxClusterIterations; iter++ {
+		changed := false
+		for i, v := range vectors {
+			best := nearestCentroid(v, centroids)
+			if best != assignments[i] {
+				assignments[i] = best
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][]float64, k)
+		for c := range sums {
+			sums[c] = make([]float64, len(vectors[0]))
+		}
+		for i, v := range vectors {
+			for d, x := range v {
+				sums[assignments[i]][d] += x
+			}
+		}
+		for c := range centroids {
+			// An emptied cluster keeps its previous centroid.
+			if norm := magnitude(sums[c]); norm > 0 {
+				for d := range sums[c] {
+					sums[c][d] /= norm
+				}
+				centroids[c] = sums[c]
+			}
+		}
+	}
+
+	return assignments, centroids
+}
+
+// seedCentroids chooses k initial centroids with k-means++, which favours
+// vectors far from the centroids chosen so far.
+func seedCentroids(vectors [][]float64, k int, rng *rand.Rand) [][]float64 {
+	centroids := [][]float64{vectors[rng.Intn(len(vectors))]}
+	distances := make([]float64, len(vectors))
+	for len(centroids) < k {
+		total := 0.0
+		for i, v := range vectors {
+			d := 1 - dot(v, centroids[nearestCentroid(v, centroids)])
+			distances[i] = max(d, 0) * max(d, 0)
+			total += distances[i]
+		}
+		if total == 0 {
+			// Every vector coincides with a centroid; any choice will do.
+			centroids = append(centroids, vectors[rng.Intn(len(vectors))])
+			continue
+		}
+
+		target := rng.Float64() * total
+		chosen := len(vectors) - 1
+		for i, d := range distances {
+			target -= d
+			if target <= 0 {
+				chosen = i
+				break
+			}
+		}
+		centroids = append(centroids, vectors[chosen])
+	}
+
+	out := make([][]float64, k)
+	for c := range centroids {
+		out[c] = append([]float64(nil), centroids[c]...)
+	}
+	return out
+}
+
+// nearestCentroid returns the index of the centroid most similar to v.
+func nearestCentroid(v []float64, centroids [][]float64) int {
+	best, bestSim := 0, math.Inf(-1)
+	for c, centroid := range centroids {
+		if sim := dot(v, centroid); sim > bestSim {
+			best, bestSim = c, sim
+		}
+	}
+	return best
+}
+
+// normalize converts an embedding to a unit-length float64 vector.
+func normalize(embedding []float32) []float64 {
+	v := make([]float64, len(embedding))
+	for i, x := range embedding {
+		v[i] = float64(x)
+	}
+	if norm := magnitude(v); norm > 0 {
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return v
+}
+
+func dot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+func magnitude(v []float64) float64 {
+	return math.Sqrt(dot(v, v))
+}
+
+// topSubjects returns the n most common subjects among facts.
+func topSubjects(facts []entities.Fact, n int) []SubjectCount {
+	counts := make(map[string]int)
+	for i := range facts {
+		counts[facts[i].Subject]++
+	}
+	subjects := make([]SubjectCount, 0, len(counts))
+	for subject, count := range counts {
+		subjects = append(subjects, SubjectCount{Subject: subject, Count: count})
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		if subjects[i].Count != subjects[j].Count {
+			return subjects[i].Count > subjects[j].Count
+		}
+		return subjects[i].Subject < subjects[j].Subject
+	})
+	return subjects[:min(n, len(subjects))]
+}
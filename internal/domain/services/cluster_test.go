@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+// clusterFacts returns n facts about subject whose embeddings point near axis.
+func clusterFacts(subject string, axis, n int) []entities.Fact {
+	facts := make([]entities.Fact, n)
+	for i := range facts {
+		embedding := make([]float32, 4)
+		embedding[axis] = 1
+		embedding[(axis+1)%4] = float32(i) * 0.01
+		facts[i] = entities.Fact{
+			ID:        fmt.Sprintf("%s-%d", subject, i),
+			Subject:   subject,
+			Predicate: "is",
+			Object:    "known",
+			Embedding: embedding,
+		}
+	}
+	return facts
+}
+
+func TestClusterService_Cluster(t *testing.T) {
+	var facts []entities.Fact
+	facts = append(facts, clusterFacts("Rivendell", 0, 6)...)
+	facts = append(facts, clusterFacts("Mordor", 1, 4)...)
+	facts = append(facts, clusterFacts("Tom Bombadil", 2, 1)...)
+	facts = append(facts, entities.Fact{ID: "pending", Subject: "Draft", Status: entities.FactStatusPending, Embedding: []float32{0, 0, 0, 1}})
+	facts = append(facts, entities.Fact{ID: "bare", Subject: "Bare"})
+
+	admin := newFakeCollectionAdmin()
+	admin.collections["world"] = facts
+	llm := &mocks.LLMClient{}
+
+	svc := NewClusterService(admin, llm)
+	report, err := svc.Cluster(context.Background(), "world", ClusterOptions{K: 3, Representatives: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, 11, report.Facts)
+	assert.False(t, report.Truncated)
+	require.Len(t, report.Clusters, 3)
+	assert.Equal(t, 3, llm.LabelTopicCallCount)
+
+	assert.Equal(t, "Rivendell", report.Clusters[0].Label)
+	assert.Equal(t, 6, report.Clusters[0].Size)
+	assert.Len(t, report.Clusters[0].Representatives, 2)
+	assert.Equal(t, []SubjectCount{{Subject: "Rivendell", Count: 6}}, report.Clusters[0].Subjects)
+	assert.False(t, report.Clusters[0].Orphaned)
+
+	assert.Equal(t, "Mordor", report.Clusters[1].Label)
+	assert.Equal(t, 4, report.Clusters[1].Size)
+
+	assert.Equal(t, "Tom Bombadil", report.Clusters[2].Label)
+	assert.True(t, report.Clusters[2].Orphaned)
+}
+
+func TestClusterService_Cluster_Limit(t *testing.T) {
+	admin := newFakeCollectionAdmin()
+	admin.collections["world"] = clusterFacts("Shire", 0, 10)
+
+	svc := NewClusterService(admin, &mocks.LLMClient{})
+	report, err := svc.Cluster(context.Background(), "world", ClusterOptions{Limit: 4})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Facts)
+	assert.True(t, report.Truncated)
+}
+
+func TestClusterService_Cluster_Empty(t *testing.T) {
+	admin := newFakeCollectionAdmin()
+	llm := &mocks.LLMClient{}
+
+	report, err := NewClusterService(admin, llm).Cluster(context.Background(), "world", ClusterOptions{})
+	require.NoError(t, err)
+
+	assert.Zero(t, report.Facts)
+	assert.Empty(t, report.Clusters)
+	assert.Zero(t, llm.LabelTopicCallCount)
+}
+
+func TestClusterService_Cluster_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ClusterOptions
+		llm     *mocks.LLMClient
+		wantErr string
+	}{
+		{
+			name:    "negative k",
+			opts:    ClusterOptions{K: -1},
+			llm:     &mocks.LLMClient{},
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "label failure",
+			llm:     &mocks.LLMClient{LabelErr: errors.New("rate limited")},
+			wantErr: "labeling cluster",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			admin := newFakeCollectionAdmin()
+			admin.collections["world"] = clusterFacts("Shire", 0, 3)
+
+			_, err := NewClusterService(admin, tt.llm).Cluster(context.Background(), "world", tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestKmeans_SeparatesGroups(t *testing.T) {
+	vectors := [][]float64{
+		normalize([]float32{1, 0.1, 0}),
+		normalize([]float32{1, 0, 0.1}),
+		normalize([]float32{0, 1, 0.1}),
+		normalize([]float32{0.1, 1, 0}),
+	}
+
+	assignments, centroids := kmeans(vectors, 2, rand.New(rand.NewSource(1)))
+
+	require.Len(t, centroids, 2)
+	assert.Equal(t, assignments[0], assignments[1])
+	assert.Equal(t, assignments[2], assignments[3])
+	assert.NotEqual(t, assignments[0], assignments[2])
+}
+
+func TestAutoClusterCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{n: 1, want: 2},
+		{n: 50, want: 5},
+		{n: 100000, want: maxAutoClusters},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.n), func(t *testing.T) {
+			assert.Equal(t, tt.want, autoClusterCount(tt.n))
+		})
+	}
+}
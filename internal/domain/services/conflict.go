@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultCheckBatchSize is the number of facts sent to the LLM per
+// consistency check call when no batch size is set.
+const DefaultCheckBatchSize = 20
+
+// CheckOptions controls a whole-database consistency check.
+type CheckOptions struct {
+	BatchSize int // Facts checked per LLM call (0 = the retrieval's batch size)
+	Limit     int // Maximum facts to check (0 = all)
+
+	// Retrieval selects the stored facts each fact is checked against.
+	Retrieval Retrieval
+
+	// Style also checks facts' spellings against the style sheet (nil = off).
+	Style *StyleSheet
+
+	// Progress is called after each page of batches with the number of facts checked so far.
+	Progress func(checked int)
+}
+
+// CheckResult contains the result of a whole-database consistency check.
+type CheckResult struct {
+	Checked  int // Facts checked
+	Issues   []ports.ConsistencyIssue
+	Recorded int // Issues recorded as new conflicts
+
+	// New are the issues recorded as new conflicts.
+	New []ports.ConsistencyIssue
+
+	StyleIssues []entities.StyleIssue
+}
+
+// ConflictDetail is a recorded conflict with the facts it involves. A fact
+// deleted since the conflict was recorded is nil.
+type ConflictDetail struct {
+	entities.Conflict
+	Fact  *entities.Fact
+	Other *entities.Fact
+}
+
+// ConflictService records contradictions between stored facts so they can
+// be flagged in listings and resolved over time.
+type ConflictService struct {
+	llm          ports.LLMClient
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
+	now          func() time.Time
+}
+
+// NewConflictService creates a new conflict service.
+func NewConflictService(llm ports.LLMClient, vectorDB ports.VectorDB, relationalDB ports.RelationalDB) *ConflictService {
+	return &ConflictService{
+		llm:          llm,
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
+		now:          time.Now,
+	}
+}
+
+// Record stores consistency issues between saved facts as open conflicts
+// and returns how many were new. Pairs already recorded, open or resolved,
+// are left as they are, and pairs linked as translations are skipped.
+func (s *ConflictService) Record(ctx context.Context, issues []ports.ConsistencyIssue) (int, error) {
+	recorded, err := s.record(ctx, issues)
+	return len(recorded), err
+}
+
+// record stores consistency issues as Record does and returns the ones
+// recorded as new conflicts.
+func (s *ConflictService) record(ctx context.Context, issues []ports.ConsistencyIssue) ([]ports.ConsistencyIssue, error) {
+	issues, err := s.withoutTranslations(ctx, issues)
+	if err != nil {
+		return nil, err
+	}
+
+	var recorded []ports.ConsistencyIssue
+	for i := range issues {
+		a, b := issues[i].NewFact.ID, issues[i].ExistingFact.ID
+		if a == "" || b == "" || a == b {
+			continue
+		}
+		if a > b {
+			a, b = b, a
+		}
+
+		created, err := s.relationalDB.SaveConflict(ctx, &entities.Conflict{
+			ID:          uuid.New().String(),
+			FactID:      a,
+			OtherFactID: b,
+			Description: issues[i].Description,
+			Severity:    issues[i].Severity,
+			Status:      entities.ConflictOpen,
+			CreatedAt:   s.now(),
+		})
+		if err != nil {
+			return recorded, fmt.Errorf("recording conflict between %s and %s: %w", a, b, err)
+		}
+		if created {
+			recorded = append(recorded, issues[i])
+		}
+	}
+	return recorded, nil
+}
+
+// Check analyzes every stored fact in batches, checking each batch against
+// the facts most similar to it, and records the contradictions found.
+// Facts pending review are skipped. Facts are read a page at a time, each
+// page as many batches as the retrieval's concurrency checks at once.
+func (s *ConflictService) Check(ctx context.Context, opts *CheckOptions) (*CheckResult, error) {
+	batchSize := checkPageSize(opts)
+
+	result := &CheckResult{}
+	cursor := ""
+	for {
+		size := batchSize
+		if opts.Limit > 0 && result.Checked+size > opts.Limit {
+			size = opts.Limit - result.Checked
+		}
+		if size <= 0 {
+			break
+		}
+
+		facts, next, err := s.vectorDB.ListPage(ctx, ports.FactPageOptions{Limit: size, Cursor: cursor})
+		if err != nil {
+			return nil, fmt.Errorf("listing facts: %w", err)
+		}
+
+		if err := s.checkBatch(ctx, facts, opts, result); err != nil {
+			return nil, err
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	return result, nil
+}
+
+// CheckFacts checks the given facts in batches, as Check does every stored
+// fact, and records the contradictions found.
+func (s *ConflictService) CheckFacts(ctx context.Context, facts []entities.Fact, opts *CheckOptions) (*CheckResult, error) {
+	batchSize := checkPageSize(opts)
+
+	result := &CheckResult{}
+	for start := 0; start < len(facts); start += batchSize {
+		if err := s.checkBatch(ctx, facts[start:min(start+batchSize, len(facts))], opts, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// checkBatch checks a page of facts, skipping those pending review, and
+// adds what it finds to result.
+func (s *ConflictService) checkBatch(ctx context.Context, facts []entities.Fact, opts *CheckOptions, result *CheckResult) error {
+	retrieval := opts.Retrieval
+	retrieval.BatchSize = checkBatchSize(opts)
+
+	var active []entities.Fact
+	for i := range facts {
+		if !facts[i].IsPending() {
+			active = append(active, facts[i])
+		}
+	}
+
+	if len(active) > 0 {
+		issues, err := findConsistencyIssues(ctx, s.llm, s.vectorDB, active, retrieval)
+		if err != nil {
+			return fmt.Errorf("checking consistency: %w", err)
+		}
+		issues, err = s.withoutTranslations(ctx, issues)
+		if err != nil {
+			return err
+		}
+		recorded, err := s.record(ctx, issues)
+		if err != nil {
+			return err
+		}
+		result.Issues = append(result.Issues, issues...)
+		result.Recorded += len(recorded)
+		result.New = append(result.New, recorded...)
+	}
+
+	result.StyleIssues = append(result.StyleIssues, opts.Style.Check(active)...)
+	result.Checked += len(facts)
+	if opts.Progress != nil {
+		opts.Progress(result.Checked)
+	}
+	return nil
+}
+
+// checkBatchSize returns the batch size of opts, or its retrieval's.
+func checkBatchSize(opts *CheckOptions) int {
+	if opts.BatchSize <= 0 {
+		return opts.Retrieval.batchSize()
+	}
+	return opts.BatchSize
+}
+
+// checkPageSize returns how many facts of opts are checked at once: a batch
+// for each concurrent LLM call.
+func checkPageSize(opts *CheckOptions) int {
+	return checkBatchSize(opts) * opts.Retrieval.concurrency()
+}
+
+// withoutTranslations drops issues between facts linked as translations of
+// each other, which state the same thing in different languages.
+func (s *ConflictService) withoutTranslations(ctx context.Context, issues []ports.ConsistencyIssue) ([]ports.ConsistencyIssue, error) {
+	if len(issues) == 0 {
+		return issues, nil
+	}
+
+	ids := make([]string, 0, len(issues))
+	for i := range issues {
+		if issues[i].NewFact.ID != "" {
+			ids = append(ids, issues[i].NewFact.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return issues, nil
+	}
+	links, err := s.relationalDB.ListTranslations(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("listing translations: %w", err)
+	}
+	if len(links) == 0 {
+		return issues, nil
+	}
+
+	linked := make(map[[2]string]bool, len(links))
+	for i := range links {
+		linked[factPairKey(links[i].FactID, links[i].OtherFactID)] = true
+	}
+	var kept []ports.ConsistencyIssue
+	for i := range issues {
+		if !linked[factPairKey(issues[i].NewFact.ID, issues[i].ExistingFact.ID)] {
+			kept = append(kept, issues[i])
+		}
+	}
+	return kept, nil
+}
+
+// List returns recorded conflicts with the facts they involve.
+func (s *ConflictService) List(ctx context.Context, opts ports.ConflictListOptions) ([]ConflictDetail, error) {
+	conflicts, err := s.relationalDB.ListConflicts(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing conflicts: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, 2*len(conflicts))
+	for i := range conflicts {
+		ids = append(ids, conflicts[i].FactID, conflicts[i].OtherFactID)
+	}
+	facts, err := s.vectorDB.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("looking up conflicting facts: %w", err)
+	}
+	byID := make(map[string]*entities.Fact, len(facts))
+	for i := range facts {
+		byID[facts[i].ID] = &facts[i]
+	}
+
+	details := make([]ConflictDetail, len(conflicts))
+	for i := range conflicts {
+		details[i] = ConflictDetail{
+			Conflict: conflicts[i],
+			Fact:     byID[conflicts[i].FactID],
+			Other:    byID[conflicts[i].OtherFactID],
+		}
+	}
+	return details, nil
+}
+
+// Resolve marks a conflict as resolved, with an optional note on how.
+func (s *ConflictService) Resolve(ctx context.Context, id string, resolution string) error {
+	if err := s.relationalDB.ResolveConflict(ctx, id, resolution, s.now()); err != nil {
+		return fmt.Errorf("resolving conflict %s: %w", id, err)
+	}
+	return nil
+}
+
+// OpenCounts returns the number of open conflicts each fact is part of.
+// Facts without open conflicts are omitted.
+func (s *ConflictService) OpenCounts(ctx context.Context, facts []entities.Fact) (map[string]int, error) {
+	ids := make([]string, len(facts))
+	for i := range facts {
+		ids[i] = facts[i].ID
+	}
+	counts, err := s.relationalDB.CountOpenConflicts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("counting open conflicts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var conflictTestNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newConflictTestService(llm *mocks.LLMClient, vectorDB *mocks.VectorDB, relationalDB *mocks.RelationalDB) *ConflictService {
+	svc := NewConflictService(llm, vectorDB, relationalDB)
+	svc.now = func() time.Time { return conflictTestNow }
+	return svc
+}
+
+func conflictIssue(a, b string) ports.ConsistencyIssue {
+	return ports.ConsistencyIssue{
+		NewFact:      entities.Fact{ID: a},
+		ExistingFact: entities.Fact{ID: b},
+		Description:  "eye colors differ",
+		Severity:     "major",
+	}
+}
+
+func TestConflictService_Record(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	svc := newConflictTestService(&mocks.LLMClient{}, &mocks.VectorDB{}, relationalDB)
+
+	recorded, err := svc.Record(context.Background(), []ports.ConsistencyIssue{
+		conflictIssue("b", "a"),
+		conflictIssue("a", "b"),
+		conflictIssue("a", "a"),
+		conflictIssue("", "a"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, recorded, "reversed pair, self pair, and unsaved fact not recorded")
+	require.Len(t, relationalDB.Conflicts, 1)
+	conflict := relationalDB.Conflicts[0]
+	assert.Equal(t, "a", conflict.FactID, "pair stored in ID order")
+	assert.Equal(t, "b", conflict.OtherFactID)
+	assert.Equal(t, entities.ConflictOpen, conflict.Status)
+	assert.Equal(t, "major", conflict.Severity)
+	assert.Equal(t, conflictTestNow, conflict.CreatedAt)
+	assert.NotEmpty(t, conflict.ID)
+}
+
+// saveConflictFailingDB fails to save conflicts but otherwise works.
+type saveConflictFailingDB struct {
+	*mocks.RelationalDB
+}
+
+func (saveConflictFailingDB) SaveConflict(_ context.Context, _ *entities.Conflict) (bool, error) {
+	return false, errors.New("disk full")
+}
+
+func TestConflictService_Record_Error(t *testing.T) {
+	svc := NewConflictService(&mocks.LLMClient{}, &mocks.VectorDB{}, saveConflictFailingDB{mocks.NewRelationalDB()})
+
+	_, err := svc.Record(context.Background(), []ports.ConsistencyIssue{conflictIssue("a", "b")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "recording conflict between a and b")
+}
+
+func TestConflictService_Record_SkipsTranslations(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	relationalDB.Translations = []entities.Translation{{FactID: "a", OtherFactID: "b"}}
+	svc := newConflictTestService(&mocks.LLMClient{}, &mocks.VectorDB{}, relationalDB)
+
+	recorded, err := svc.Record(context.Background(), []ports.ConsistencyIssue{
+		conflictIssue("b", "a"),
+		conflictIssue("a", "c"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, recorded)
+	require.Len(t, relationalDB.Conflicts, 1)
+	assert.Equal(t, "c", relationalDB.Conflicts[0].OtherFactID)
+}
+
+func TestConflictService_Check(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue"},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "green"},
+		{ID: "c", Type: entities.FactTypeCharacter, Subject: "Frodo", Status: entities.FactStatusPending},
+	}}
+	llm := &mocks.LLMClient{Issues: []ports.ConsistencyIssue{
+		conflictIssue("b", "a"),
+		conflictIssue("a", "a"),
+	}}
+	relationalDB := mocks.NewRelationalDB()
+	svc := newConflictTestService(llm, vectorDB, relationalDB)
+
+	var progress []int
+	result, err := svc.Check(context.Background(), &CheckOptions{
+		BatchSize: 10,
+		Progress:  func(checked int) { progress = append(progress, checked) },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Checked)
+	assert.Len(t, result.Issues, 1, "a fact is never reported against itself")
+	assert.Equal(t, 1, result.Recorded)
+	assert.Equal(t, []int{3}, progress)
+	require.Len(t, relationalDB.Conflicts, 1)
+
+	// A second check finds the same contradiction without recording it again
+	result, err = svc.Check(context.Background(), &CheckOptions{BatchSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, result.Issues, 1)
+	assert.Equal(t, 0, result.Recorded)
+	assert.Len(t, relationalDB.Conflicts, 1)
+}
+
+func TestConflictService_Check_Limit(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{{ID: "a", Subject: "Frodo"}, {ID: "b", Subject: "Frodo"}}}
+	llm := &mocks.LLMClient{}
+	svc := newConflictTestService(llm, vectorDB, mocks.NewRelationalDB())
+
+	result, err := svc.Check(context.Background(), &CheckOptions{BatchSize: 10, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, llm.CheckConsistencyCallCount)
+	assert.Equal(t, 1, result.Checked)
+}
+
+func TestConflictService_Check_Pages(t *testing.T) {
+	// The mock pages like Qdrant, whose cursors are fact IDs, not counts
+	facts := make([]entities.Fact, 5)
+	for i := range facts {
+		facts[i] = entities.Fact{ID: uuid.NewString(), Subject: "Frodo"}
+	}
+	vectorDB := &mocks.VectorDB{Facts: facts}
+	llm := &mocks.LLMClient{}
+	svc := newConflictTestService(llm, vectorDB, mocks.NewRelationalDB())
+
+	var progress []int
+	result, err := svc.Check(context.Background(), &CheckOptions{
+		BatchSize: 2,
+		Retrieval: Retrieval{Concurrency: 1},
+		Progress:  func(checked int) { progress = append(progress, checked) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Checked, "every page is checked once")
+	assert.Equal(t, []int{2, 4, 5}, progress)
+}
+
+func TestConflictService_Check_Retrieval(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "born_in", Object: "the Shire"},
+		{ID: "b", Type: entities.FactTypeEvent, Subject: "Frodo", Predicate: "born_during", Object: "the Third Age"},
+	}}
+	llm := &mocks.LLMClient{}
+	svc := newConflictTestService(llm, vectorDB, mocks.NewRelationalDB())
+
+	_, err := svc.Check(context.Background(), &CheckOptions{BatchSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, llm.CheckConsistencyLastOld, 2, "each fact is compared with facts of its type")
+
+	_, err = svc.Check(context.Background(), &CheckOptions{BatchSize: 10, Retrieval: Retrieval{Strategy: RetrievalGlobal, Limit: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Fact{vectorDB.Facts[0]}, llm.CheckConsistencyLastOld, "the most similar fact of any type")
+}
+
+func TestConflictService_Check_LLMError(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo"},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Frodo"},
+	}}
+	llm := &mocks.LLMClient{ConsistencyErr: errors.New("rate limited")}
+	svc := newConflictTestService(llm, vectorDB, mocks.NewRelationalDB())
+
+	_, err := svc.Check(context.Background(), &CheckOptions{BatchSize: 10})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checking consistency")
+}
+
+func TestConflictService_ListResolveAndCount(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Subject: "Frodo"},
+		{ID: "b", Subject: "Frodo"},
+	}}
+	relationalDB := mocks.NewRelationalDB()
+	svc := newConflictTestService(&mocks.LLMClient{}, vectorDB, relationalDB)
+	ctx := context.Background()
+
+	_, err := svc.Record(ctx, []ports.ConsistencyIssue{conflictIssue("a", "b"), conflictIssue("a", "deleted")})
+	require.NoError(t, err)
+
+	details, err := svc.List(ctx, ports.ConflictListOptions{Status: entities.ConflictOpen})
+	require.NoError(t, err)
+	require.Len(t, details, 2)
+	assert.Equal(t, "deleted", details[0].OtherFactID, "newest first")
+	assert.Nil(t, details[0].Other, "deleted facts are nil")
+	require.NotNil(t, details[1].Other)
+	assert.Equal(t, "b", details[1].Other.ID)
+
+	counts, err := svc.OpenCounts(ctx, vectorDB.Facts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, counts)
+
+	require.NoError(t, svc.Resolve(ctx, details[1].ID, "b is from an old draft"))
+	assert.Equal(t, entities.ConflictResolved, relationalDB.Conflicts[0].Status)
+	assert.Equal(t, "b is from an old draft", relationalDB.Conflicts[0].Resolution)
+	assert.Equal(t, conflictTestNow, relationalDB.Conflicts[0].ResolvedAt)
+
+	counts, err = svc.OpenCounts(ctx, vectorDB.Facts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, counts, "resolved conflicts no longer counted")
+
+	err = svc.Resolve(ctx, "missing", "")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestConflictService_Check_TypedObjects(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "age", Object: "33"},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "age", Object: "50"},
+	}}
+	llm := &mocks.LLMClient{}
+	relationalDB := mocks.NewRelationalDB()
+	svc := newConflictTestService(llm, vectorDB, relationalDB)
+
+	result, err := svc.Check(context.Background(), &CheckOptions{BatchSize: 10})
+	require.NoError(t, err)
+	assert.Len(t, result.Issues, 1, "found without the LLM reporting it")
+	assert.Equal(t, 1, result.Recorded)
+}
+
+func TestConflictService_Check_Style(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Dragonlord", Predicate: "rules", Object: "the north"},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Dragon Lord", Predicate: "lives_in", Object: "Ember Hall"},
+		{ID: "c", Type: entities.FactTypeCharacter, Subject: "dragonlord", Predicate: "breathes", Object: "fire", Status: entities.FactStatusPending},
+	}}
+	svc := newConflictTestService(&mocks.LLMClient{}, vectorDB, mocks.NewRelationalDB())
+	sheet, err := newStyleTestService(t, map[string]string{"Dragon Lord": "chapter2"}).Sheet(context.Background())
+	require.NoError(t, err)
+
+	result, err := svc.Check(context.Background(), &CheckOptions{BatchSize: 10, Style: sheet})
+	require.NoError(t, err)
+	require.Len(t, result.StyleIssues, 1, "facts pending review are skipped")
+	assert.Equal(t, "a", result.StyleIssues[0].Fact.ID)
+}
+
+func TestFindConsistencyIssues_Claims(t *testing.T) {
+	canon := entities.Fact{ID: "a", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "fair"}
+	gimli := entities.Fact{ID: "b", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "liars", AssertedBy: "Gimli", Claim: true}
+	legolas := entities.Fact{ID: "c", Type: entities.FactTypeCharacter, Subject: "Dwarves", Predicate: "are", Object: "stubborn", AssertedBy: "Legolas", Claim: true}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{canon, gimli, legolas}}
+
+	newCanon := entities.Fact{ID: "n1", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "immortal"}
+	newClaim := entities.Fact{ID: "n2", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "honest", AssertedBy: "Gimli", Claim: true}
+
+	llm := &mocks.LLMClient{}
+	_, err := findConsistencyIssues(context.Background(), llm, vectorDB, []entities.Fact{newCanon}, Retrieval{})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Fact{canon}, llm.CheckConsistencyLastOld, "canon is checked against canon only")
+
+	llm = &mocks.LLMClient{Issues: []ports.ConsistencyIssue{
+		{NewFact: newClaim, ExistingFact: legolas},
+		{NewFact: newClaim, ExistingFact: gimli},
+	}}
+	issues, err := findConsistencyIssues(context.Background(), llm, vectorDB, []entities.Fact{newClaim}, Retrieval{})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Fact{gimli}, llm.CheckConsistencyLastOld, "claims are checked against the asserter's claims")
+	require.Len(t, issues, 1)
+	assert.Equal(t, "b", issues[0].ExistingFact.ID)
+}
+
+func TestFindConsistencyIssues_Drafts(t *testing.T) {
+	canon := entities.Fact{ID: "a", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "fair"}
+	draft := entities.Fact{ID: "b", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "fallen", Draft: true}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{canon, draft}}
+
+	llm := &mocks.LLMClient{}
+	newCanon := entities.Fact{ID: "n1", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "immortal"}
+	_, err := findConsistencyIssues(context.Background(), llm, vectorDB, []entities.Fact{newCanon}, Retrieval{})
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Fact{canon}, llm.CheckConsistencyLastOld, "canon is not checked against drafts")
+
+	llm = &mocks.LLMClient{}
+	newDraft := entities.Fact{ID: "n2", Type: entities.FactTypeCharacter, Subject: "Elves", Predicate: "are", Object: "mortal", Draft: true}
+	issues, err := findConsistencyIssues(context.Background(), llm, vectorDB, []entities.Fact{newDraft}, Retrieval{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+	assert.Nil(t, llm.CheckConsistencyLastOld, "drafts are not checked")
+}
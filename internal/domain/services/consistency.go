@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultConsistencyConcurrency is how many LLM consistency calls run at a
+// time when no concurrency is set.
+const DefaultConsistencyConcurrency = 4
+
+// consistencyBatch is the new facts one LLM call checks and the stored
+// facts it checks them against.
+type consistencyBatch struct {
+	newFacts   []entities.Fact
+	candidates []entities.Fact
+	pruned     int // Candidates the prefilter left out
+	issues     []ports.ConsistencyIssue
+}
+
+// findIssuesAmong checks facts against the stored facts retrieval finds for
+// them that compare reports true for. Typed objects with the same subject
+// and predicate, and predicates the prefilter declares, are compared
+// directly; everything else is split into batches of retrieval's batch
+// size, each checked against its own related candidates by one LLM call,
+// several at a time.
+func findIssuesAmong(ctx context.Context, llm ports.LLMClient, vectorDB ports.VectorDB, newFacts []entities.Fact, retrieval Retrieval, compare func(*entities.Fact) bool) ([]ports.ConsistencyIssue, error) {
+	if len(newFacts) == 0 {
+		return nil, nil
+	}
+
+	// Step 1: Collect each batch's candidate facts from DB (fast calls)
+	var batches []*consistencyBatch
+	size := retrieval.batchSize()
+	for start := 0; start < len(newFacts); start += size {
+		batch := &consistencyBatch{newFacts: newFacts[start:min(start+size, len(newFacts))]}
+		candidates, err := retrieval.candidates(ctx, vectorDB, batch.newFacts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range candidates {
+			if compare(&candidates[i]) {
+				batch.candidates = append(batch.candidates, candidates[i])
+			}
+		}
+		if len(batch.candidates) > 0 {
+			batches = append(batches, batch)
+		}
+	}
+	if len(batches) == 0 {
+		return nil, nil
+	}
+
+	// Step 2: Exact contradictions between typed objects and declared
+	// predicates
+	var filtered []ports.ConsistencyIssue
+	reported := make(map[string]bool)
+	for _, batch := range batches {
+		issues := typedContradictions(batch.newFacts, batch.candidates)
+		issues = append(issues, retrieval.Prefilter.contradictions(batch.newFacts, batch.candidates)...)
+		for i := range issues {
+			if key := issuePairKey(&issues[i]); !reported[key] {
+				reported[key] = true
+				filtered = append(filtered, issues[i])
+			}
+		}
+	}
+
+	// Step 3: Leave out of the LLM calls what step 2 settled and pairs
+	// sharing no entity
+	if !retrieval.Prefilter.KeepUnrelated && retrieval.Strategy != RetrievalGraph {
+		related := batches[:0]
+		for _, batch := range batches {
+			candidates := len(batch.candidates)
+			batch.newFacts, batch.candidates = retrieval.Prefilter.related(batch.newFacts, batch.candidates, reported)
+			batch.pruned = candidates - len(batch.candidates)
+			if len(batch.candidates) > 0 {
+				related = append(related, batch)
+			}
+		}
+		batches = related
+	}
+
+	// Step 4: One LLM call per batch, a bounded number at a time
+	if err := checkBatches(ctx, llm, batches, retrieval); err != nil {
+		return nil, fmt.Errorf("LLM consistency check: %w", err)
+	}
+
+	for _, batch := range batches {
+		for i := range batch.issues {
+			key := issuePairKey(&batch.issues[i])
+			if batch.issues[i].NewFact.ID != batch.issues[i].ExistingFact.ID && !reported[key] {
+				reported[key] = true
+				filtered = append(filtered, batch.issues[i])
+			}
+		}
+	}
+	return filtered, nil
+}
+
+// checkBatches checks every batch with the LLM, running up to retrieval's
+// concurrency calls at a time, and records each call's timing in
+// retrieval's audit log. The first call to fail cancels the rest.
+func checkBatches(ctx context.Context, llm ports.LLMClient, batches []*consistencyBatch, retrieval Retrieval) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, retrieval.concurrency())
+	for i, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			start := time.Now()
+			//nolint:loopcall // Batches already split the facts and run concurrently
+			issues, err := llm.CheckConsistency(ctx, batch.newFacts, batch.candidates)
+			logConsistencyBatch(ctx, retrieval.Audit, i+1, len(batches), batch, len(issues), time.Since(start), err)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				cancel()
+				return
+			}
+			batch.issues = issues
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// logConsistencyBatch records an LLM consistency call in audit, if not nil.
+func logConsistencyBatch(ctx context.Context, audit auditLogger, n, of int, batch *consistencyBatch, issues int, elapsed time.Duration, err error) {
+	if audit == nil {
+		return
+	}
+	details := map[string]any{
+		"batch":       n,
+		"batches":     of,
+		"facts":       len(batch.newFacts),
+		"candidates":  len(batch.candidates),
+		"pruned":      batch.pruned,
+		"issues":      issues,
+		"duration_ms": elapsed.Milliseconds(),
+	}
+	if err != nil {
+		details["error"] = err.Error()
+	}
+	// Best effort: failing to log a call shouldn't fail the check.
+	_ = audit.LogAction(context.WithoutCancel(ctx), entities.AuditActionConsistencyBatch, "", details)
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// verdictStore stores consistency verdicts.
+type verdictStore interface {
+	FindConsistencyVerdicts(ctx context.Context, keys []string, since time.Time) (map[string]entities.ConsistencyVerdict, error)
+	SaveConsistencyVerdicts(ctx context.Context, verdicts []entities.ConsistencyVerdict) error
+	PruneConsistencyVerdicts(ctx context.Context, cutoff time.Time) (int, error)
+}
+
+// CachedConsistencyLLM is a ports.LLMClient that remembers the verdict for
+// each pair of new and existing fact it checks for consistency, so
+// re-ingesting a chapter only asks the LLM about pairs it has not seen.
+// Pairs are keyed by the facts' normalized content: editing either fact
+// means the pair is checked again. Its other calls go to the wrapped client.
+type CachedConsistencyLLM struct {
+	ports.LLMClient
+	store verdictStore
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewCachedConsistencyLLM wraps llm so consistency verdicts are stored in
+// store and reused for ttl.
+func NewCachedConsistencyLLM(llm ports.LLMClient, store verdictStore, ttl time.Duration) *CachedConsistencyLLM {
+	return &CachedConsistencyLLM{LLMClient: llm, store: store, ttl: ttl, now: time.Now}
+}
+
+// CheckConsistency returns the cached issues between newFacts and
+// existingFacts, and asks the LLM only about the pairs not cached.
+func (l *CachedConsistencyLLM) CheckConsistency(ctx context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
+	now := l.now()
+
+	keys := make([][]string, len(newFacts))
+	var allKeys []string
+	for i := range newFacts {
+		keys[i] = make([]string, len(existingFacts))
+		for j := range existingFacts {
+			keys[i][j] = consistencyPairKey(&newFacts[i], &existingFacts[j])
+			allKeys = append(allKeys, keys[i][j])
+		}
+	}
+
+	cached, err := l.store.FindConsistencyVerdicts(ctx, allKeys, now.Add(-l.ttl))
+	if err != nil {
+		return nil, fmt.Errorf("reading cached verdicts: %w", err)
+	}
+
+	var issues []ports.ConsistencyIssue
+	var uncheckedNew, uncheckedExisting []entities.Fact
+	existingUnchecked := make([]bool, len(existingFacts))
+	for i := range newFacts {
+		unchecked := false
+		for j := range existingFacts {
+			v, ok := cached[keys[i][j]]
+			if !ok {
+				unchecked = true
+				existingUnchecked[j] = true
+				continue
+			}
+			if v.Conflicting {
+				issues = append(issues, ports.ConsistencyIssue{
+					NewFact:      newFacts[i],
+					ExistingFact: existingFacts[j],
+					Description:  v.Description,
+					Severity:     v.Severity,
+				})
+			}
+		}
+		if unchecked {
+			uncheckedNew = append(uncheckedNew, newFacts[i])
+		}
+	}
+	if len(uncheckedNew) == 0 {
+		return issues, nil
+	}
+	for j := range existingFacts {
+		if existingUnchecked[j] {
+			uncheckedExisting = append(uncheckedExisting, existingFacts[j])
+		}
+	}
+
+	found, err := l.LLMClient.CheckConsistency(ctx, uncheckedNew, uncheckedExisting)
+	if err != nil {
+		return nil, err
+	}
+
+	l.remember(ctx, now, uncheckedNew, uncheckedExisting, cached, found)
+	return append(issues, found...), nil
+}
+
+// remember stores a verdict for every pair the LLM was just asked about.
+// Best effort: failing to cache a verdict shouldn't fail the check.
+func (l *CachedConsistencyLLM) remember(ctx context.Context, now time.Time, newFacts, existingFacts []entities.Fact, cached map[string]entities.ConsistencyVerdict, issues []ports.ConsistencyIssue) {
+	verdicts := make(map[string]entities.ConsistencyVerdict)
+	for i := range newFacts {
+		for j := range existingFacts {
+			key := consistencyPairKey(&newFacts[i], &existingFacts[j])
+			if _, ok := cached[key]; !ok {
+				verdicts[key] = entities.ConsistencyVerdict{Key: key, CheckedAt: now}
+			}
+		}
+	}
+	for i := range issues {
+		key := consistencyPairKey(&issues[i].NewFact, &issues[i].ExistingFact)
+		verdicts[key] = entities.ConsistencyVerdict{
+			Key:         key,
+			Conflicting: true,
+			Description: issues[i].Description,
+			Severity:    issues[i].Severity,
+			CheckedAt:   now,
+		}
+	}
+
+	batch := make([]entities.ConsistencyVerdict, 0, len(verdicts))
+	for _, v := range verdicts {
+		batch = append(batch, v)
+	}
+	ctx = context.WithoutCancel(ctx)
+	_ = l.store.SaveConsistencyVerdicts(ctx, batch)
+	_, _ = l.store.PruneConsistencyVerdicts(ctx, now.Add(-l.ttl))
+}
+
+// consistencyPairKey identifies a pair of facts by their normalized type,
+// subject, predicate, object, and context.
+func consistencyPairKey(newFact, existing *entities.Fact) string {
+	h := sha256.New()
+	for _, f := range []*entities.Fact{newFact, existing} {
+		for _, field := range []string{string(f.Type), f.Subject, f.Predicate, f.Object, f.Context} {
+			h.Write([]byte(entities.NormalizeName(field)))
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
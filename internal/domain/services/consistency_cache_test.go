@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var (
+	cacheTestNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	livesInBagEnd      = entities.Fact{ID: "e1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}
+	uncleOfFrodo       = entities.Fact{ID: "e2", Type: entities.FactTypeCharacter, Subject: "Bilbo", Predicate: "uncle_of", Object: "Frodo"}
+	livesInMinasTirith = entities.Fact{ID: "n1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Minas Tirith"}
+	carriesRing        = entities.Fact{ID: "n2", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "carries", Object: "the One Ring"}
+)
+
+func newCachedConsistencyLLM() (*CachedConsistencyLLM, *mocks.LLMClient, *mocks.RelationalDB) {
+	llm := &mocks.LLMClient{Issues: []ports.ConsistencyIssue{
+		{NewFact: livesInMinasTirith, ExistingFact: livesInBagEnd, Description: "Frodo lives in Bag End", Severity: "major"},
+	}}
+	store := mocks.NewRelationalDB()
+	cached := NewCachedConsistencyLLM(llm, store, time.Hour)
+	cached.now = func() time.Time { return cacheTestNow }
+	return cached, llm, store
+}
+
+func TestCachedConsistencyLLM_ReusesVerdicts(t *testing.T) {
+	cached, llm, store := newCachedConsistencyLLM()
+	ctx := context.Background()
+	existing := []entities.Fact{livesInBagEnd, uncleOfFrodo}
+	newFacts := []entities.Fact{livesInMinasTirith, carriesRing}
+
+	issues, err := cached.CheckConsistency(ctx, newFacts, existing)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Len(t, store.Verdicts, 4, "a verdict per pair")
+
+	// Re-ingested: same content, new IDs
+	again := []entities.Fact{livesInMinasTirith, carriesRing}
+	again[0].ID, again[1].ID = "n3", "n4"
+	issues, err = cached.CheckConsistency(ctx, again, existing)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, llm.CheckConsistencyCallCount, "every pair came from the cache")
+	require.Len(t, issues, 1)
+	assert.Equal(t, "n3", issues[0].NewFact.ID)
+	assert.Equal(t, "e1", issues[0].ExistingFact.ID)
+	assert.Equal(t, "Frodo lives in Bag End", issues[0].Description)
+	assert.Equal(t, "major", issues[0].Severity)
+}
+
+func TestCachedConsistencyLLM_ChecksOnlyUncachedPairs(t *testing.T) {
+	cached, llm, _ := newCachedConsistencyLLM()
+	ctx := context.Background()
+
+	_, err := cached.CheckConsistency(ctx, []entities.Fact{livesInMinasTirith, carriesRing}, []entities.Fact{livesInBagEnd})
+	require.NoError(t, err)
+
+	// A new existing fact, and an edit to a checked one
+	edited := carriesRing
+	edited.Object = "Sting"
+	llm.Issues = nil
+	issues, err := cached.CheckConsistency(ctx, []entities.Fact{livesInMinasTirith, edited}, []entities.Fact{livesInBagEnd, uncleOfFrodo})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, llm.CheckConsistencyCallCount)
+	assert.Equal(t, []entities.Fact{livesInMinasTirith, edited}, llm.CheckConsistencyLastNew)
+	assert.Equal(t, []entities.Fact{livesInBagEnd, uncleOfFrodo}, llm.CheckConsistencyLastOld,
+		"the edited fact is rechecked against both")
+	require.Len(t, issues, 1, "the cached issue is still reported")
+	assert.Equal(t, "Minas Tirith", issues[0].NewFact.Object)
+}
+
+func TestCachedConsistencyLLM_Expiry(t *testing.T) {
+	cached, llm, store := newCachedConsistencyLLM()
+	ctx := context.Background()
+	newFacts := []entities.Fact{livesInMinasTirith}
+	existing := []entities.Fact{livesInBagEnd}
+
+	_, err := cached.CheckConsistency(ctx, newFacts, existing)
+	require.NoError(t, err)
+
+	cached.now = func() time.Time { return cacheTestNow.Add(2 * time.Hour) }
+	_, err = cached.CheckConsistency(ctx, newFacts, existing)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, llm.CheckConsistencyCallCount, "expired verdicts are checked again")
+	require.Len(t, store.Verdicts, 1)
+	for _, v := range store.Verdicts {
+		assert.Equal(t, cacheTestNow.Add(2*time.Hour), v.CheckedAt)
+	}
+}
+
+func TestCachedConsistencyLLM_Errors(t *testing.T) {
+	t.Run("reading the cache", func(t *testing.T) {
+		cached, llm, store := newCachedConsistencyLLM()
+		store.Err = entities.ErrBackendUnavailable
+
+		_, err := cached.CheckConsistency(context.Background(), []entities.Fact{carriesRing}, []entities.Fact{livesInBagEnd})
+		require.ErrorIs(t, err, entities.ErrBackendUnavailable)
+		assert.Contains(t, err.Error(), "reading cached verdicts")
+		assert.Zero(t, llm.CheckConsistencyCallCount)
+	})
+
+	t.Run("checking uncached pairs", func(t *testing.T) {
+		cached, llm, store := newCachedConsistencyLLM()
+		llm.ConsistencyErr = entities.ErrBackendUnavailable
+
+		_, err := cached.CheckConsistency(context.Background(), []entities.Fact{carriesRing}, []entities.Fact{livesInBagEnd})
+		require.ErrorIs(t, err, entities.ErrBackendUnavailable)
+		assert.Empty(t, store.Verdicts, "nothing is cached for a failed check")
+	})
+}
+
+func TestConsistencyPairKey(t *testing.T) {
+	key := consistencyPairKey(&livesInMinasTirith, &livesInBagEnd)
+
+	respelled := livesInMinasTirith
+	respelled.ID, respelled.Subject = "other", " FRODO "
+	assert.Equal(t, key, consistencyPairKey(&respelled, &livesInBagEnd), "IDs, case, and surrounding space don't matter")
+	assert.NotEqual(t, key, consistencyPairKey(&livesInBagEnd, &livesInMinasTirith), "order matters")
+
+	withContext := livesInBagEnd
+	withContext.Context = "after the war"
+	assert.NotEqual(t, key, consistencyPairKey(&livesInMinasTirith, &withContext))
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// concurrentLLM reports each new fact as contradicting the first existing
+// one, and tracks how many consistency calls are in flight at once.
+type concurrentLLM struct {
+	*mocks.LLMClient
+	failOn string // A call checking this fact fails
+
+	mu          sync.Mutex
+	calls       [][]entities.Fact
+	inFlight    int
+	maxInFlight int
+}
+
+func (l *concurrentLLM) CheckConsistency(ctx context.Context, newFacts []entities.Fact, existing []entities.Fact) ([]ports.ConsistencyIssue, error) {
+	l.mu.Lock()
+	l.calls = append(l.calls, newFacts)
+	l.inFlight++
+	l.maxInFlight = max(l.maxInFlight, l.inFlight)
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(10 * time.Millisecond):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var issues []ports.ConsistencyIssue
+	for i := range newFacts {
+		if newFacts[i].ID == l.failOn {
+			return nil, errors.New("rate limited")
+		}
+		issues = append(issues, ports.ConsistencyIssue{NewFact: newFacts[i], ExistingFact: existing[0], Description: "differs"})
+	}
+	return issues, nil
+}
+
+func consistencyTestFacts(n int) []entities.Fact {
+	facts := make([]entities.Fact, n)
+	for i := range facts {
+		facts[i] = entities.Fact{ID: fmt.Sprintf("n%d", i), Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: fmt.Sprintf("place %d", i)}
+	}
+	return facts
+}
+
+func TestFindIssuesAmong_Batches(t *testing.T) {
+	llm := &concurrentLLM{LLMClient: &mocks.LLMClient{}}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{livesInBagEnd}}
+	audit := &recordingAudit{}
+	retrieval := Retrieval{BatchSize: 2, Concurrency: 2, Audit: audit}
+
+	issues, err := findIssuesAmong(context.Background(), llm, vectorDB, consistencyTestFacts(7), retrieval, func(*entities.Fact) bool { return true })
+	require.NoError(t, err)
+
+	require.Len(t, llm.calls, 4, "seven facts in batches of two")
+	for _, call := range llm.calls {
+		assert.LessOrEqual(t, len(call), 2)
+	}
+	assert.LessOrEqual(t, llm.maxInFlight, 2, "no more calls at once than the concurrency")
+
+	require.Len(t, issues, 7, "every batch's issues are merged")
+	for i := range issues {
+		assert.Equal(t, fmt.Sprintf("n%d", i), issues[i].NewFact.ID, "in batch order")
+	}
+
+	require.Len(t, audit.entries, 4, "each call is recorded")
+	batches := make(map[any]bool)
+	for _, entry := range audit.entries {
+		assert.Equal(t, entities.AuditActionConsistencyBatch, entry.Action)
+		assert.Equal(t, 4, entry.Details["batches"])
+		assert.Equal(t, 1, entry.Details["candidates"])
+		assert.Contains(t, entry.Details, "duration_ms")
+		batches[entry.Details["batch"]] = true
+	}
+	assert.Len(t, batches, 4)
+}
+
+func TestFindIssuesAmong_BatchError(t *testing.T) {
+	llm := &concurrentLLM{LLMClient: &mocks.LLMClient{}, failOn: "n4"}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{livesInBagEnd}}
+	audit := &recordingAudit{}
+	retrieval := Retrieval{BatchSize: 1, Concurrency: 3, Audit: audit}
+
+	_, err := findIssuesAmong(context.Background(), llm, vectorDB, consistencyTestFacts(6), retrieval, func(*entities.Fact) bool { return true })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limited")
+
+	var failed int
+	for _, entry := range audit.entries {
+		if entry.Details["error"] != nil {
+			failed++
+		}
+	}
+	assert.GreaterOrEqual(t, failed, 1, "the failed call is recorded")
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// pronouns mark subjects and objects that refer to someone named elsewhere.
+var pronouns = map[string]bool{
+	"he": true, "him": true, "his": true, "himself": true,
+	"she": true, "her": true, "hers": true, "herself": true,
+	"they": true, "them": true, "their": true, "theirs": true, "themselves": true,
+	"it": true, "its": true, "itself": true,
+}
+
+// hasPronoun reports whether text contains a pronoun word, as in "he" or
+// "his sister".
+func hasPronoun(text string) bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	for _, w := range words {
+		if pronouns[strings.TrimSuffix(w, "'s")] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCoreferences replaces pronoun subjects and objects with the names
+// they refer to in text. Only facts containing pronouns are sent to the LLM.
+func (s *ExtractionService) resolveCoreferences(ctx context.Context, text string, facts []entities.Fact) error {
+	var pending []entities.Fact
+	var positions []int
+	for i := range facts {
+		if hasPronoun(facts[i].Subject) || hasPronoun(facts[i].Object) {
+			pending = append(pending, facts[i])
+			positions = append(positions, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	resolutions, err := s.llm.ResolveCoreferences(ctx, text, pending)
+	if err != nil {
+		return fmt.Errorf("resolving coreferences: %w", err)
+	}
+
+	for _, r := range resolutions {
+		if r.FactIndex < 0 || r.FactIndex >= len(positions) {
+			continue
+		}
+		fact := &facts[positions[r.FactIndex]]
+		if r.Subject != "" && hasPronoun(fact.Subject) {
+			fact.Subject = r.Subject
+		}
+		if r.Object != "" && hasPronoun(fact.Object) {
+			fact.Object = r.Object
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestHasPronoun(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"he", true},
+		{"She", true},
+		{"his sister", true},
+		{"their home", true},
+		{"it's", true},
+		{"Frodo", false},
+		{"the Shire", false},
+		{"Hermione", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, hasPronoun(tt.text))
+		})
+	}
+}
+
+func newMockExtractionService(llm *mocks.LLMClient) (*ExtractionService, *mocks.VectorDB) {
+	db := mocks.NewRelationalDB()
+	for _, et := range entities.DefaultEntityTypes {
+		etCopy := et
+		db.Types[etCopy.Name] = &etCopy
+	}
+	vectorDB := &mocks.VectorDB{}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	return NewExtractionService(llm, emb, vectorDB, NewEntityTypeService(db)), vectorDB
+}
+
+func TestExtractionService_ResolvePronouns(t *testing.T) {
+	text := "Frodo left the Shire. He carried the ring to his uncle."
+	extracted := func() []entities.Fact {
+		return []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "left", Object: "the Shire"},
+			{Type: entities.FactTypeCharacter, Subject: "He", Predicate: "carried", Object: "the ring"},
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "visited", Object: "his uncle"},
+		}
+	}
+
+	t.Run("resolves only facts with pronouns", func(t *testing.T) {
+		llm := &mocks.LLMClient{
+			Facts: extracted(),
+			Coreferences: []ports.CoreferenceResolution{
+				{FactIndex: 0, Subject: "Frodo"},
+				{FactIndex: 1, Object: "Frodo's uncle"},
+				{FactIndex: 1, Subject: "Bilbo"}, // Subject is not a pronoun; ignored
+				{FactIndex: 9, Subject: "Sam"},   // Out of range; ignored
+			},
+		}
+		svc, _ := newMockExtractionService(llm)
+
+		result, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &ExtractionOptions{ResolvePronouns: true})
+		require.NoError(t, err)
+
+		require.Len(t, llm.ResolveCorefLastFacts, 2)
+		assert.Equal(t, "Frodo", result.Facts[1].Subject)
+		assert.Equal(t, "Frodo", result.Facts[2].Subject)
+		assert.Equal(t, "Frodo's uncle", result.Facts[2].Object)
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: extracted()}
+		svc, _ := newMockExtractionService(llm)
+
+		result, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &ExtractionOptions{})
+		require.NoError(t, err)
+		assert.Zero(t, llm.ResolveCorefCallCount)
+		assert.Equal(t, "He", result.Facts[1].Subject)
+	})
+
+	t.Run("no pronouns skips llm call", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: extracted()[:1]}
+		svc, _ := newMockExtractionService(llm)
+
+		_, err := svc.ExtractAndStoreWithOptions(context.Background(), text, "book.txt", &ExtractionOptions{ResolvePronouns: true})
+		require.NoError(t, err)
+		assert.Zero(t, llm.ResolveCorefCallCount)
+	})
+
+	t.Run("llm error", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: extracted(), CorefErr: errors.New("rate limited")}
+		svc, vectorDB := newMockExtractionService(llm)
+
+		_, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &ExtractionOptions{ResolvePronouns: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rate limited")
+		assert.Zero(t, vectorDB.SaveBatchCallCount)
+	})
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Ranking weights for corroboration.
+const (
+	// corroborationWeight is how much each source beyond the first raises
+	// a search result's score, as a fraction of its relevance score. Small,
+	// since relevance scores of nearby ranks differ by under two percent.
+	corroborationWeight = 0.02
+	// maxCorroboratingSources caps the sources that count toward ranking,
+	// so a fact repeated in every chapter cannot outrank relevant ones.
+	maxCorroboratingSources = 5
+)
+
+// corroborate counts the distinct sources asserting each new fact,
+// matching stored facts whose triples are at least DefaultSimilarityThreshold
+// similar, and sets each fact's Corroboration. Stored facts corroborated by
+// a new source are returned with their count raised, to be saved with the
+// new facts. facts must already be embedded.
+func corroborate(ctx context.Context, vectorDB ports.VectorDB, facts []entities.Fact) ([]entities.Fact, error) {
+	raised := make(map[string]int) // Stored fact ID to its new count
+
+	for i := range facts {
+		similar, err := findSimilarFacts(ctx, vectorDB, &facts[i], DefaultSimilarityThreshold)
+		if err != nil {
+			return nil, err
+		}
+
+		count := corroborationCount(&facts[i], similar)
+		facts[i].Corroboration = count
+		for j := range similar {
+			if f := &similar[j].Fact; f.SourceCount() < count && raised[f.ID] < count {
+				raised[f.ID] = count
+			}
+		}
+	}
+
+	if len(raised) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(raised))
+	for id := range raised {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	updated := make([]entities.Fact, 0, len(ids))
+	for _, id := range ids {
+		// Fetched one at a time, since only FindByID includes embeddings
+		//nolint:loopcall // Few facts are corroborated per ingest
+		stored, err := vectorDB.FindByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("loading corroborated fact: %w", err)
+		}
+		stored.Corroboration = raised[id]
+		updated = append(updated, stored)
+	}
+	return updated, nil
+}
+
+// corroborationCount returns the number of distinct sources asserting fact,
+// given the stored facts similar to it. Each similar fact may already have
+// been corroborated by sources not among them; fact's source adds one more
+// unless one of them already came from it, as when a file is re-ingested.
+func corroborationCount(fact *entities.Fact, similar []SimilarFact) int {
+	sources := map[string]bool{fact.SourceFile: true}
+	known, sameSource := 0, false
+	for i := range similar {
+		other := &similar[i].Fact
+		sources[other.SourceFile] = true
+		known = max(known, other.SourceCount())
+		if other.SourceFile == fact.SourceFile {
+			sameSource = true
+		}
+	}
+
+	if !sameSource && len(similar) > 0 {
+		known++
+	}
+	return max(len(sources), known)
+}
+
+// rankByCorroboration reorders relevance-ranked facts so facts asserted by
+// several sources rise above one-off mentions of similar relevance. Each
+// fact scores 1/(rrfK+rank), raised by corroborationWeight per extra
+// source. Ties keep relevance order.
+func rankByCorroboration(facts []entities.Fact) []entities.Fact {
+	type scored struct {
+		fact  entities.Fact
+		score float64
+	}
+	ranked := make([]scored, len(facts))
+	for rank := range facts {
+		sources := min(facts[rank].SourceCount(), maxCorroboratingSources)
+		boost := 1 + corroborationWeight*float64(sources-1)
+		ranked[rank] = scored{fact: facts[rank], score: boost / float64(rrfK+rank+1)}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	for i := range ranked {
+		facts[i] = ranked[i].fact
+	}
+	return facts
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestCorroborationCount(t *testing.T) {
+	similar := func(source string, corroboration int) SimilarFact {
+		return SimilarFact{Fact: entities.Fact{SourceFile: source, Corroboration: corroboration}}
+	}
+
+	tests := []struct {
+		name    string
+		similar []SimilarFact
+		want    int
+	}{
+		{"no similar facts", nil, 1},
+		{"one other source", []SimilarFact{similar("ch1.md", 0)}, 2},
+		{"two other sources", []SimilarFact{similar("ch1.md", 0), similar("ch2.md", 0)}, 3},
+		{"already corroborated elsewhere", []SimilarFact{similar("ch1.md", 3)}, 4},
+		{"re-ingested source", []SimilarFact{similar("ch3.md", 0)}, 1},
+		{"re-ingested corroborated source", []SimilarFact{similar("ch3.md", 2)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := &entities.Fact{SourceFile: "ch3.md"}
+			assert.Equal(t, tt.want, corroborationCount(fact, tt.similar))
+		})
+	}
+}
+
+func TestCorroborate(t *testing.T) {
+	vectorDB := &mocks.VectorDB{
+		Facts: []entities.Fact{
+			{ID: "stored", Type: entities.FactTypeCharacter, SourceFile: "ch1.md", TextEmbedding: []float32{1, 0}},
+		},
+		VectorResults: map[ports.VectorName][]entities.Fact{
+			ports.VectorText: {
+				{ID: "stored", Type: entities.FactTypeCharacter, SourceFile: "ch1.md", TextEmbedding: []float32{1, 0}},
+				{ID: "unrelated", Type: entities.FactTypeCharacter, SourceFile: "ch1.md", TextEmbedding: []float32{0, 1}},
+			},
+		},
+	}
+	facts := []entities.Fact{{ID: "new", Type: entities.FactTypeCharacter, SourceFile: "ch2.md", TextEmbedding: []float32{1, 0}}}
+
+	updated, err := corroborate(context.Background(), vectorDB, facts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, facts[0].Corroboration)
+	require.Len(t, updated, 1, "only the matching stored fact is raised")
+	assert.Equal(t, "stored", updated[0].ID)
+	assert.Equal(t, 2, updated[0].Corroboration)
+	assert.Equal(t, []float32{1, 0}, updated[0].TextEmbedding, "stored fact keeps its embeddings")
+}
+
+func TestCorroborate_Uncorroborated(t *testing.T) {
+	vectorDB := &mocks.VectorDB{}
+	facts := []entities.Fact{{ID: "new", Type: entities.FactTypeCharacter, SourceFile: "ch2.md", TextEmbedding: []float32{1, 0}}}
+
+	updated, err := corroborate(context.Background(), vectorDB, facts)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+	assert.Equal(t, 1, facts[0].Corroboration)
+}
+
+func TestRankByCorroboration(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "a"},
+		{ID: "b", Corroboration: 3},
+		{ID: "c"},
+		{ID: "d", Corroboration: 2},
+		{ID: "e", Corroboration: 50},
+	}
+
+	ranked := rankByCorroboration(facts)
+
+	ids := make([]string, len(ranked))
+	for i := range ranked {
+		ids[i] = ranked[i].ID
+	}
+	assert.Equal(t, []string{"b", "e", "a", "d", "c"}, ids, "corroboration outweighs a few ranks, capped at five sources")
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// AffectedRelationship is a relationship touched by a deletion, with the
+// names of the entities it connects.
+type AffectedRelationship struct {
+	Relationship entities.Relationship
+	Source       string
+	Target       string
+}
+
+// SourceDeletionPlan describes what deleting a source file's facts affects.
+type SourceDeletionPlan struct {
+	SourceFile string
+	Facts      []entities.Fact
+
+	// OrphanedEntities are only referenced by the facts being deleted.
+	OrphanedEntities []*entities.Entity
+
+	// Relationships are backed by a deleted fact or involve an orphaned entity.
+	Relationships []AffectedRelationship
+}
+
+// HasOrphans reports whether the deletion leaves records to clean up in the
+// relational database.
+func (p *SourceDeletionPlan) HasOrphans() bool {
+	return len(p.OrphanedEntities) > 0 || len(p.Relationships) > 0
+}
+
+// DeletionService deletes facts together with the relational records they
+// leave behind.
+type DeletionService struct {
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
+}
+
+// NewDeletionService creates a new deletion service.
+func NewDeletionService(vectorDB ports.VectorDB, relationalDB ports.RelationalDB) *DeletionService {
+	return &DeletionService{
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
+	}
+}
+
+// PlanSourceDeletion previews deleting up to limit facts from a source file:
+// the facts themselves, the entities that would lose their last referencing
+// fact, and the relationships that would be left dangling.
+func (s *DeletionService) PlanSourceDeletion(ctx context.Context, worldID, sourceFile string, limit int) (*SourceDeletionPlan, error) {
+	facts, err := s.vectorDB.ListBySource(ctx, sourceFile, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts by source: %w", err)
+	}
+
+	plan := &SourceDeletionPlan{SourceFile: sourceFile, Facts: facts}
+	if len(facts) == 0 {
+		return plan, nil
+	}
+
+	removed := make(map[string]bool, len(facts))
+	for i := range facts {
+		removed[facts[i].ID] = true
+	}
+
+	referenced, err := s.referencedEntities(ctx, worldID, facts)
+	if err != nil {
+		return nil, err
+	}
+
+	orphaned := make(map[string]bool)
+	for _, ref := range referenced {
+		isOrphan, err := s.onlyReferencedBy(ctx, ref, removed)
+		if err != nil {
+			return nil, err
+		}
+		if isOrphan {
+			orphaned[ref.entity.ID] = true
+			plan.OrphanedEntities = append(plan.OrphanedEntities, ref.entity)
+		}
+	}
+
+	plan.Relationships, err = s.affectedRelationships(ctx, referenced, removed, orphaned)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// entityReference is an entity named by the facts being deleted, with the
+// spellings those facts use and how often they mention it.
+type entityReference struct {
+	entity    *entities.Entity
+	spellings []string
+	mentions  int
+}
+
+// referencedEntities finds the entities named as subject or object of the
+// facts, in order of first mention.
+func (s *DeletionService) referencedEntities(ctx context.Context, worldID string, facts []entities.Fact) ([]entityReference, error) {
+	var order []string
+	refs := make(map[string]*entityReference)
+	for i := range facts {
+		for _, name := range []string{facts[i].Subject, facts[i].Object} {
+			key := entities.NormalizeName(name)
+			if key == "" {
+				continue
+			}
+			ref, ok := refs[key]
+			if !ok {
+				ref = &entityReference{}
+				refs[key] = ref
+				order = append(order, key)
+			}
+			if !slices.Contains(ref.spellings, name) {
+				ref.spellings = append(ref.spellings, name)
+			}
+			ref.mentions++
+		}
+	}
+
+	referenced := make([]entityReference, 0, len(order))
+	for _, key := range order {
+		entity, err := s.relationalDB.FindEntityByName(ctx, worldID, key)
+		if err != nil {
+			return nil, fmt.Errorf("finding entity %q: %w", key, err)
+		}
+		if entity == nil {
+			continue
+		}
+		ref := refs[key]
+		ref.entity = entity
+		if !slices.Contains(ref.spellings, entity.Name) {
+			ref.spellings = append(ref.spellings, entity.Name)
+		}
+		referenced = append(referenced, *ref)
+	}
+	return referenced, nil
+}
+
+// onlyReferencedBy reports whether every fact naming the entity is in the
+// removed set. Fetching one more fact than the removed facts mention it
+// guarantees a surviving fact shows up if there is one.
+func (s *DeletionService) onlyReferencedBy(ctx context.Context, ref entityReference, removed map[string]bool) (bool, error) {
+	facts, err := s.vectorDB.ListByEntities(ctx, ref.spellings, ref.mentions+1)
+	if err != nil {
+		return false, fmt.Errorf("listing facts for %s: %w", ref.entity.Name, err)
+	}
+	return !slices.ContainsFunc(facts, func(f entities.Fact) bool {
+		return !removed[f.ID]
+	}), nil
+}
+
+// affectedRelationships collects relationships of the referenced entities
+// that are backed by a removed fact or touch an orphaned entity.
+func (s *DeletionService) affectedRelationships(ctx context.Context, referenced []entityReference, removed, orphaned map[string]bool) ([]AffectedRelationship, error) {
+	names := make(map[string]string, len(referenced))
+	for _, ref := range referenced {
+		names[ref.entity.ID] = ref.entity.Name
+	}
+
+	seen := make(map[string]bool)
+	var affected []entities.Relationship
+	var unnamed []string
+	for _, ref := range referenced {
+		rels, err := s.relationalDB.FindRelationshipsByEntity(ctx, ref.entity.ID)
+		if err != nil {
+			return nil, fmt.Errorf("finding relationships of %s: %w", ref.entity.Name, err)
+		}
+		for _, rel := range rels {
+			if seen[rel.ID] || !(removed[rel.ID] || orphaned[rel.SourceEntityID] || orphaned[rel.TargetEntityID]) {
+				continue
+			}
+			seen[rel.ID] = true
+			affected = append(affected, rel)
+			for _, id := range []string{rel.SourceEntityID, rel.TargetEntityID} {
+				if _, ok := names[id]; !ok && !slices.Contains(unnamed, id) {
+					unnamed = append(unnamed, id)
+				}
+			}
+		}
+	}
+
+	if len(unnamed) > 0 {
+		others, err := s.relationalDB.FindEntitiesByIDs(ctx, unnamed)
+		if err != nil {
+			return nil, fmt.Errorf("finding related entities: %w", err)
+		}
+		for _, e := range others {
+			names[e.ID] = e.Name
+		}
+	}
+
+	result := make([]AffectedRelationship, 0, len(affected))
+	for _, rel := range affected {
+		result = append(result, AffectedRelationship{
+			Relationship: rel,
+			Source:       names[rel.SourceEntityID],
+			Target:       names[rel.TargetEntityID],
+		})
+	}
+	return result, nil
+}
+
+// DeleteSource deletes all facts from the plan's source file and, if
+// cleanupOrphans is set, the orphaned entities and relationships the plan found.
+func (s *DeletionService) DeleteSource(ctx context.Context, plan *SourceDeletionPlan, cleanupOrphans bool) error {
+	if err := s.vectorDB.DeleteBySource(ctx, plan.SourceFile); err != nil {
+		return fmt.Errorf("deleting facts by source: %w", err)
+	}
+
+	if !cleanupOrphans {
+		return nil
+	}
+	return s.CleanupOrphans(ctx, plan)
+}
+
+// CleanupOrphans removes the plan's affected relationships, with the facts
+// representing them, and its orphaned entities.
+func (s *DeletionService) CleanupOrphans(ctx context.Context, plan *SourceDeletionPlan) error {
+	for i := range plan.Relationships {
+		if err := deleteRelationship(ctx, s.relationalDB, s.vectorDB, &plan.Relationships[i]); err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range plan.OrphanedEntities {
+		if err := s.relationalDB.DeleteEntity(ctx, entity.ID); err != nil {
+			return fmt.Errorf("deleting entity %s: %w", entity.Name, err)
+		}
+	}
+
+	return nil
+}
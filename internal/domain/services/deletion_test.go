@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func newDeletionTestService(t *testing.T) (*DeletionService, *mocks.RelationalDB) {
+	t.Helper()
+
+	relationalDB := mocks.NewRelationalDB()
+	for _, name := range []string{"Frodo", "Sam", "Gandalf", "Shire"} {
+		relationalDB.Entities["e-"+name] = &entities.Entity{
+			ID: "e-" + name, WorldID: "w", Name: name, NormalizedName: entities.NormalizeName(name),
+		}
+	}
+	relationalDB.Relationships = []entities.Relationship{
+		{ID: "r-ally", SourceEntityID: "e-Sam", TargetEntityID: "e-Gandalf", Type: entities.RelationAlly},
+		{ID: "r-visits", SourceEntityID: "e-Gandalf", TargetEntityID: "e-Shire", Type: entities.RelationLocatedIn},
+	}
+
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "f1", Subject: "Frodo", Predicate: "lives_in", Object: "shire", SourceFile: "book.txt"},
+		{ID: "f2", Subject: "Sam", Predicate: "is", Object: "a gardener", SourceFile: "book.txt"},
+		{ID: "f3", Subject: "Frodo", Predicate: "owns", Object: "Sting", SourceFile: "other.txt"},
+		{ID: "r-ally", Subject: "Sam", Predicate: "ally", Object: "Gandalf", SourceFile: "relationship"},
+	}}
+
+	return NewDeletionService(vectorDB, relationalDB), relationalDB
+}
+
+func TestDeletionService_PlanSourceDeletion(t *testing.T) {
+	tests := []struct {
+		name              string
+		source            string
+		wantFacts         int
+		wantOrphans       []string
+		wantRelationships []string
+	}{
+		{
+			name:              "entity loses its last fact",
+			source:            "book.txt",
+			wantFacts:         2,
+			wantOrphans:       []string{"Shire"},
+			wantRelationships: []string{"Gandalf located_in Shire"},
+		},
+		{
+			name:              "relationship backed by a deleted fact",
+			source:            "relationship",
+			wantFacts:         1,
+			wantOrphans:       []string{"Gandalf"},
+			wantRelationships: []string{"Sam ally Gandalf", "Gandalf located_in Shire"},
+		},
+		{
+			name:      "nothing from source",
+			source:    "missing.txt",
+			wantFacts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newDeletionTestService(t)
+
+			plan, err := svc.PlanSourceDeletion(context.Background(), "w", tt.source, 100)
+			require.NoError(t, err)
+
+			assert.Len(t, plan.Facts, tt.wantFacts)
+
+			var orphans []string
+			for _, e := range plan.OrphanedEntities {
+				orphans = append(orphans, e.Name)
+			}
+			assert.Equal(t, tt.wantOrphans, orphans)
+
+			var rels []string
+			for _, rel := range plan.Relationships {
+				rels = append(rels, rel.Source+" "+string(rel.Relationship.Type)+" "+rel.Target)
+			}
+			assert.Equal(t, tt.wantRelationships, rels)
+			assert.Equal(t, len(tt.wantOrphans) > 0, plan.HasOrphans())
+		})
+	}
+}
+
+func TestDeletionService_DeleteSource(t *testing.T) {
+	tests := []struct {
+		name          string
+		cleanup       bool
+		wantEntities  int
+		wantRelations int
+	}{
+		{"with cleanup", true, 3, 1},
+		{"without cleanup", false, 4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, relationalDB := newDeletionTestService(t)
+
+			plan, err := svc.PlanSourceDeletion(context.Background(), "w", "book.txt", 100)
+			require.NoError(t, err)
+			require.NoError(t, svc.DeleteSource(context.Background(), plan, tt.cleanup))
+
+			assert.Len(t, relationalDB.Entities, tt.wantEntities)
+			assert.Len(t, relationalDB.Relationships, tt.wantRelations)
+			if tt.cleanup {
+				assert.NotContains(t, relationalDB.Entities, "e-Shire")
+				assert.Equal(t, "r-ally", relationalDB.Relationships[0].ID)
+				assert.Equal(t, []string{"r-visits"}, svc.vectorDB.(*mocks.VectorDB).DeletedIDs, "the relationship's fact goes with it")
+			}
+		})
+	}
+}
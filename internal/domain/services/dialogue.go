@@ -0,0 +1,158 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// maxSpeakerLength is the longest speaker tag recognized, so a shouted
+// line of narration is not taken for one.
+const maxSpeakerLength = 30
+
+// Speech is what one character says in a passage of dialogue.
+type Speech struct {
+	Speaker string // As in the text's speaker tags, e.g. "Gimli"
+	Text    string // Their lines, in order
+}
+
+var (
+	// scriptLine matches a script line with an inline speaker tag, such as
+	// "GIMLI: Never trust an elf." or "Gimli: Never trust an elf."
+	scriptLine = regexp.MustCompile(`^\s*([\p{Lu}][\p{L}'.-]*(?: [\p{Lu}][\p{L}'.-]*){0,2})\s*(?:\([^)]*\))?:\s+(\S.*)$`)
+
+	// sceneHeading matches screenplay lines in capitals that are not
+	// character cues, such as "INT. BAG END - DAY" or "CUT TO:".
+	sceneHeading = regexp.MustCompile(`^(?:INT|EXT|EST|I/E)[. ]|^(?:FADE|CUT|DISSOLVE|SMASH CUT|MATCH CUT)\b|TO:$|^THE END$`)
+
+	// extension matches a cue's extension, such as " (V.O.)" or " (CONT'D)".
+	extension = regexp.MustCompile(`\s*\([^)]*\)\s*$`)
+)
+
+// SplitDialogue separates the dialogue in text from its narration. Speakers
+// are recognized by screenplay character cues, a line in capitals followed
+// by their lines, or by script tags such as "Gimli: ...". Each speaker's
+// lines are joined in the order they first speak; the narration is
+// returned with the dialogue removed.
+func SplitDialogue(text string) (string, []Speech) {
+	var (
+		narration []string
+		speeches  []Speech
+		index     = make(map[string]int)
+		speaker   string // Speaker of the screenplay block being read
+	)
+
+	say := func(name, line string) {
+		i, ok := index[name]
+		if !ok {
+			i = len(speeches)
+			index[name] = i
+			speeches = append(speeches, Speech{Speaker: name})
+		}
+		if speeches[i].Text != "" {
+			speeches[i].Text += " "
+		}
+		speeches[i].Text += line
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			speaker = ""
+			narration = append(narration, line)
+		case speaker != "":
+			// Parentheticals such as "(quietly)" direct the actor
+			if !(strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")")) {
+				say(speaker, trimmed)
+			}
+		case isCue(trimmed):
+			speaker = speakerName(extension.ReplaceAllString(trimmed, ""))
+		default:
+			if m := scriptLine.FindStringSubmatch(trimmed); m != nil {
+				say(speakerName(m[1]), m[2])
+				continue
+			}
+			narration = append(narration, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(narration, "\n")), speeches
+}
+
+// isCue reports whether line is a screenplay character cue: a short line
+// in capitals, possibly with an extension, that is not a scene heading or
+// transition.
+func isCue(line string) bool {
+	name := extension.ReplaceAllString(line, "")
+	if name == "" || len(name) > maxSpeakerLength || sceneHeading.MatchString(name) {
+		return false
+	}
+	// Periods are allowed within a name, as in "DR. JONES", but a line
+	// ending in one is a sentence
+	if strings.ContainsAny(name, "!?:,;") || strings.HasSuffix(name, ".") {
+		return false
+	}
+	letters := false
+	for _, r := range name {
+		if unicode.IsLower(r) || unicode.IsDigit(r) {
+			return false
+		}
+		letters = letters || unicode.IsLetter(r)
+	}
+	return letters
+}
+
+// speakerName returns a speaker tag as a name, with words in capitals
+// title-cased, so "GIMLI" and "Gimli" are the same speaker.
+func speakerName(tag string) string {
+	words := strings.Fields(tag)
+	for i, word := range words {
+		if strings.ToUpper(word) != word {
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// firstPerson are the pronouns a speaker uses for themself.
+var firstPerson = map[string]bool{"i": true, "me": true, "myself": true}
+
+// attribute marks facts as asserted by speaker, as claims unless they are
+// reliable, and replaces the pronouns speaker uses for themself with their
+// name.
+func attribute(facts []entities.Fact, speaker string, claim bool) {
+	for i := range facts {
+		facts[i].AssertedBy = speaker
+		facts[i].Claim = claim
+		if firstPerson[strings.ToLower(facts[i].Subject)] {
+			facts[i].Subject = speaker
+		}
+		if firstPerson[strings.ToLower(facts[i].Object)] {
+			facts[i].Object = speaker
+		}
+	}
+}
+
+// speakerContext returns the context to extract text with, telling the LLM
+// whose words it is: how is "dialogue spoken by" or "narrated by".
+func speakerContext(priorContext, how, speaker string) string {
+	note := "The following text is " + how + " " + speaker + ". \"I\" and \"me\" refer to " + speaker + "."
+	if priorContext == "" {
+		return note
+	}
+	return priorContext + "\n\n" + note
+}
+
+// isReliable reports whether speaker is one of reliable, ignoring case and
+// spacing.
+func isReliable(speaker string, reliable []string) bool {
+	name := entities.NormalizeName(speaker)
+	return slices.ContainsFunc(reliable, func(r string) bool { return entities.NormalizeName(r) == name })
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestSplitDialogue_Screenplay(t *testing.T) {
+	text := strings.Join([]string{
+		"INT. THE PRANCING PONY - NIGHT",
+		"",
+		"Gimli slams his axe on the table.",
+		"",
+		"GIMLI (CONT'D)",
+		"(growling)",
+		"Never trust an elf.",
+		"",
+		"LEGOLAS",
+		"And yet here I stand.",
+		"",
+		"GIMLI",
+		"Aye, for now.",
+		"",
+		"CUT TO:",
+	}, "\n")
+
+	narration, speeches := SplitDialogue(text)
+	assert.Equal(t, "INT. THE PRANCING PONY - NIGHT\n\nGimli slams his axe on the table.\n\n\n\n\nCUT TO:", narration)
+	assert.Equal(t, []Speech{
+		{Speaker: "Gimli", Text: "Never trust an elf. Aye, for now."},
+		{Speaker: "Legolas", Text: "And yet here I stand."},
+	}, speeches)
+}
+
+func TestSplitDialogue_Script(t *testing.T) {
+	text := "The fellowship rests.\nGIMLI: Elves are untrustworthy.\nLegolas (quietly): Dwarves are stubborn.\nDr. Jones: Both are right."
+
+	narration, speeches := SplitDialogue(text)
+	assert.Equal(t, "The fellowship rests.", narration)
+	assert.Equal(t, []Speech{
+		{Speaker: "Gimli", Text: "Elves are untrustworthy."},
+		{Speaker: "Legolas", Text: "Dwarves are stubborn."},
+		{Speaker: "Dr. Jones", Text: "Both are right."},
+	}, speeches)
+}
+
+func TestSplitDialogue_Prose(t *testing.T) {
+	text := "Frodo left the Shire.\n\n\"Wait,\" said Sam. \"I'm coming too.\""
+
+	narration, speeches := SplitDialogue(text)
+	assert.Equal(t, text, narration)
+	assert.Empty(t, speeches)
+}
+
+func TestIsCue(t *testing.T) {
+	assert.True(t, isCue("GIMLI"))
+	assert.True(t, isCue("DR. JONES (V.O.)"))
+	assert.True(t, isCue("GANDALF THE GREY"))
+	assert.False(t, isCue("INT. BAG END - DAY"))
+	assert.False(t, isCue("EXT MORIA"))
+	assert.False(t, isCue("FADE IN:"))
+	assert.False(t, isCue("SMASH CUT TO:"))
+	assert.False(t, isCue("BOOM."))
+	assert.False(t, isCue("Gimli"))
+	assert.False(t, isCue("GUARD 2"))
+	assert.False(t, isCue("(CONT'D)"))
+	assert.False(t, isCue("THE ORCS POUR OUT OF THE GATE AND OVER THE WALLS"))
+}
+
+func TestSpeakerName(t *testing.T) {
+	assert.Equal(t, "Gimli", speakerName("GIMLI"))
+	assert.Equal(t, "Dr. Jones", speakerName("DR. JONES"))
+	assert.Equal(t, "Gimli", speakerName("Gimli"))
+	assert.Equal(t, "McGregor", speakerName("McGregor"))
+}
+
+func TestAttribute(t *testing.T) {
+	facts := []entities.Fact{
+		{Subject: "I", Predicate: "distrusts", Object: "elves"},
+		{Subject: "Legolas", Predicate: "follows", Object: "me"},
+	}
+
+	attribute(facts, "Gimli", true)
+	assert.Equal(t, "Gimli", facts[0].Subject)
+	assert.Equal(t, "Gimli", facts[1].Object)
+	for _, f := range facts {
+		assert.Equal(t, "Gimli", f.AssertedBy)
+		assert.True(t, f.IsClaim())
+	}
+
+	attribute(facts, "Gandalf", false)
+	assert.Equal(t, "Gandalf", facts[0].AssertedBy)
+	assert.False(t, facts[0].IsClaim())
+}
+
+func TestIsReliable(t *testing.T) {
+	assert.True(t, isReliable("Gandalf", []string{"gandalf"}))
+	assert.False(t, isReliable("Gimli", []string{"Gandalf"}))
+	assert.False(t, isReliable("Gimli", nil))
+}
+
+func TestExtractionService_Dialogue(t *testing.T) {
+	text := "The fellowship rests.\nGIMLI: Elves are untrustworthy."
+
+	llm := &textRecordingLLM{LLMClient: &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "elves", Predicate: "are", Object: "untrustworthy"},
+	}}}
+	base, vectorDB := newMockExtractionService(llm.LLMClient)
+	svc := NewExtractionService(llm, base.embedder, vectorDB, base.entityTypeService)
+
+	result, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "script.txt", &ExtractionOptions{Dialogue: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"The fellowship rests.", "Elves are untrustworthy."}, llm.texts)
+	assert.Contains(t, llm.ExtractFactsPriorContexts[1], "dialogue spoken by Gimli")
+	require.Len(t, result.Facts, 2)
+	assert.Empty(t, result.Facts[0].AssertedBy, "narration is canon")
+	assert.False(t, result.Facts[0].IsClaim())
+	assert.Equal(t, "Gimli", result.Facts[1].AssertedBy)
+	assert.True(t, result.Facts[1].IsClaim())
+	for _, f := range result.Facts {
+		assert.NotEmpty(t, f.ID)
+		assert.Equal(t, "script.txt", f.SourceFile)
+	}
+
+	llm.texts = nil
+	_, err = svc.ExtractFromReader(context.Background(), strings.NewReader(text), "script.txt", &ExtractionOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{text}, llm.texts, "dialogue is extracted as narration unless asked")
+}
+
+func TestExtractionService_Reliability(t *testing.T) {
+	text := "I never lost the ring.\nGANDALF: The ring was lost.\nGIMLI: Elves are untrustworthy."
+
+	llm := &textRecordingLLM{LLMClient: &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "I", Predicate: "lost", Object: "the ring"},
+	}}}
+	base, vectorDB := newMockExtractionService(llm.LLMClient)
+	svc := NewExtractionService(llm, base.embedder, vectorDB, base.entityTypeService)
+
+	opts := ExtractionOptions{Dialogue: true, Narrator: "Bilbo", Reliable: []string{"gandalf"}}
+	result, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "memoir.txt", &opts)
+	require.NoError(t, err)
+
+	assert.Contains(t, llm.ExtractFactsPriorContexts[0], "narrated by Bilbo")
+	require.Len(t, result.Facts, 3)
+	assert.Equal(t, "Bilbo", result.Facts[0].Subject, "first person refers to the narrator")
+	assert.Equal(t, []bool{true, false, true}, []bool{
+		result.Facts[0].IsClaim(), result.Facts[1].IsClaim(), result.Facts[2].IsClaim(),
+	}, "the unreliable narrator and unlisted speakers make claims")
+	assert.Equal(t, []string{"Bilbo", "Gandalf", "Gimli"}, []string{
+		result.Facts[0].AssertedBy, result.Facts[1].AssertedBy, result.Facts[2].AssertedBy,
+	})
+}
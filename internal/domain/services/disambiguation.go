@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// maxEntityCandidates bounds how many entities are considered for one subject.
+const maxEntityCandidates = 20
+
+// nameFillerWords are ignored when comparing names with fact text.
+var nameFillerWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "and": true, "in": true, "on": true, "at": true,
+}
+
+// EntityCandidate is an entity a fact subject may refer to.
+type EntityCandidate struct {
+	Entity  *entities.Entity
+	Related []string // Names of entities related to this one
+	Score   int      // Clues in the fact pointing to this entity
+}
+
+// EntityChooser picks the entity an ambiguous subject refers to. It returns
+// the index of the chosen candidate, or -1 to keep the subject as written.
+type EntityChooser func(ctx context.Context, fact *entities.Fact, candidates []EntityCandidate) (int, error)
+
+// Disambiguation records how an ambiguous subject was resolved.
+type Disambiguation struct {
+	FactID    string
+	Subject   string // Subject as extracted
+	Entity    string // Entity name chosen, empty if the subject was kept
+	Automatic bool   // Resolved from context and relationships without asking
+}
+
+// DisambiguationService resolves fact subjects that match several entities,
+// such as "John" when both "John the Baker" and "King John" exist.
+type DisambiguationService struct {
+	relationalDB ports.RelationalDB
+}
+
+// NewDisambiguationService creates a new DisambiguationService.
+func NewDisambiguationService(relationalDB ports.RelationalDB) *DisambiguationService {
+	return &DisambiguationService{
+		relationalDB: relationalDB,
+	}
+}
+
+// Candidates returns the entities a fact's subject may refer to, best match
+// first. It returns nil when the subject names an entity exactly or matches
+// at most one entity.
+func (s *DisambiguationService) Candidates(ctx context.Context, worldID string, fact *entities.Fact) ([]EntityCandidate, error) {
+	subject := entities.NormalizeName(fact.Subject)
+	subjectWords := nameWords(subject)
+	if len(subjectWords) == 0 {
+		return nil, nil
+	}
+
+	found, err := s.relationalDB.SearchEntities(ctx, worldID, subject, maxEntityCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("searching entities for %q: %w", fact.Subject, err)
+	}
+
+	var matches []*entities.Entity
+	for _, entity := range found {
+		if entity.NormalizedName == subject {
+			return nil, nil
+		}
+		if containsWords(nameWords(entity.NormalizedName), subjectWords) {
+			matches = append(matches, entity)
+		}
+	}
+	if len(matches) < 2 {
+		return nil, nil
+	}
+
+	clues := factClueWords(fact, subjectWords)
+	text := entities.NormalizeName(fact.Object + " " + fact.Context)
+
+	candidates := make([]EntityCandidate, 0, len(matches))
+	for _, entity := range matches {
+		related, err := s.relatedNames(ctx, entity.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		score := 0
+		for _, word := range nameWords(entity.NormalizedName) {
+			if clues[word] {
+				score++
+			}
+		}
+		for _, name := range related {
+			if strings.Contains(text, entities.NormalizeName(name)) {
+				score++
+			}
+		}
+
+		candidates = append(candidates, EntityCandidate{Entity: entity, Related: related, Score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates, nil
+}
+
+// Resolve rewrites ambiguous fact subjects to the entity they refer to.
+// A candidate with more clues than any other is chosen automatically;
+// remaining ties are passed to choose, or resolved to the best-ranked
+// candidate when choose is nil.
+func (s *DisambiguationService) Resolve(ctx context.Context, worldID string, facts []entities.Fact, choose EntityChooser) ([]Disambiguation, error) {
+	var resolved []Disambiguation
+	for i := range facts {
+		fact := &facts[i]
+
+		candidates, err := s.Candidates(ctx, worldID, fact)
+		if err != nil {
+			return resolved, err
+		}
+		if len(candidates) == 0 {
+			continue
+		}
+
+		d := Disambiguation{FactID: fact.ID, Subject: fact.Subject}
+		choice := 0
+		switch {
+		case candidates[0].Score > candidates[1].Score:
+			d.Automatic = true
+		case choose != nil:
+			choice, err = choose(ctx, fact, candidates)
+			if err != nil {
+				return resolved, fmt.Errorf("choosing entity for %q: %w", fact.Subject, err)
+			}
+			if choice >= len(candidates) {
+				return resolved, entities.Errorf(entities.ErrValidation, "invalid choice %d for %q", choice, fact.Subject)
+			}
+		}
+
+		if choice >= 0 {
+			d.Entity = candidates[choice].Entity.Name
+			fact.Subject = d.Entity
+		}
+		resolved = append(resolved, d)
+	}
+	return resolved, nil
+}
+
+// relatedNames returns the names of entities directly related to an entity.
+func (s *DisambiguationService) relatedNames(ctx context.Context, entityID string) ([]string, error) {
+	rels, err := s.relationalDB.FindRelationshipsByEntity(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("finding relationships: %w", err)
+	}
+	if len(rels) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		if rel.SourceEntityID == entityID {
+			ids = append(ids, rel.TargetEntityID)
+		} else {
+			ids = append(ids, rel.SourceEntityID)
+		}
+	}
+
+	related, err := s.relationalDB.FindEntitiesByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("finding related entities: %w", err)
+	}
+
+	names := make([]string, 0, len(related))
+	for _, entity := range related {
+		names = append(names, entity.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// factClueWords returns the words of a fact's predicate, object, and
+// context, excluding the subject's own words.
+func factClueWords(fact *entities.Fact, subjectWords []string) map[string]bool {
+	text := strings.Join([]string{fact.Predicate, fact.Object, fact.Context}, " ")
+	clues := make(map[string]bool)
+	for _, word := range nameWords(entities.NormalizeName(text)) {
+		clues[word] = true
+	}
+	for _, word := range subjectWords {
+		delete(clues, word)
+	}
+	return clues
+}
+
+// nameWords splits lowercase text into words, dropping filler words.
+func nameWords(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if !nameFillerWords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// containsWords reports whether every word in want appears in words.
+func containsWords(words, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(words, w) {
+			return false
+		}
+	}
+	return true
+}
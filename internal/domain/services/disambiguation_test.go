@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func newDisambiguationDB() *mocks.RelationalDB {
+	db := mocks.NewRelationalDB()
+	for id, name := range map[string]string{
+		"baker":   "John the Baker",
+		"king":    "King John",
+		"realm":   "Northern Kingdom",
+		"alice":   "Alice",
+		"johnson": "Johnson",
+	} {
+		db.Entities[id] = &entities.Entity{ID: id, WorldID: "w", Name: name, NormalizedName: entities.NormalizeName(name)}
+	}
+	db.Relationships = []entities.Relationship{
+		{SourceEntityID: "king", TargetEntityID: "realm", Type: entities.RelationOwns},
+	}
+	return db
+}
+
+func TestDisambiguationService_Candidates(t *testing.T) {
+	svc := NewDisambiguationService(newDisambiguationDB())
+
+	tests := []struct {
+		name string
+		fact entities.Fact
+		want []string
+	}{
+		{
+			name: "unique subject",
+			fact: entities.Fact{Subject: "Alice", Predicate: "is", Object: "brave"},
+			want: nil,
+		},
+		{
+			name: "exact match",
+			fact: entities.Fact{Subject: "king john", Predicate: "is", Object: "old"},
+			want: nil,
+		},
+		{
+			name: "ambiguous without clues",
+			fact: entities.Fact{Subject: "John", Predicate: "is", Object: "tall"},
+			want: []string{"John the Baker", "King John"},
+		},
+		{
+			name: "name word in context",
+			fact: entities.Fact{Subject: "John", Predicate: "sells", Object: "bread", Context: "the king praised the baker"},
+			want: []string{"John the Baker", "King John"},
+		},
+		{
+			name: "related entity in context",
+			fact: entities.Fact{Subject: "John", Predicate: "rules", Object: "the Northern Kingdom"},
+			want: []string{"King John", "John the Baker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := svc.Candidates(context.Background(), "w", &tt.fact)
+			require.NoError(t, err)
+
+			var got []string
+			for _, c := range candidates {
+				got = append(got, c.Entity.Name)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDisambiguationService_Resolve(t *testing.T) {
+	svc := NewDisambiguationService(newDisambiguationDB())
+
+	newFacts := func() []entities.Fact {
+		return []entities.Fact{
+			{ID: "1", Subject: "John", Predicate: "rules", Object: "the Northern Kingdom"},
+			{ID: "2", Subject: "John", Predicate: "is", Object: "tall"},
+			{ID: "3", Subject: "Alice", Predicate: "is", Object: "brave"},
+		}
+	}
+
+	t.Run("assume first", func(t *testing.T) {
+		facts := newFacts()
+		resolved, err := svc.Resolve(context.Background(), "w", facts, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "King John", facts[0].Subject)
+		assert.Equal(t, "John the Baker", facts[1].Subject)
+		assert.Equal(t, "Alice", facts[2].Subject)
+		assert.Equal(t, []Disambiguation{
+			{FactID: "1", Subject: "John", Entity: "King John", Automatic: true},
+			{FactID: "2", Subject: "John", Entity: "John the Baker"},
+		}, resolved)
+	})
+
+	t.Run("chooser asked only for ties", func(t *testing.T) {
+		facts := newFacts()
+		var asked []string
+		choose := func(_ context.Context, fact *entities.Fact, candidates []EntityCandidate) (int, error) {
+			asked = append(asked, fact.ID)
+			return 1, nil
+		}
+
+		_, err := svc.Resolve(context.Background(), "w", facts, choose)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2"}, asked)
+		assert.Equal(t, "King John", facts[1].Subject)
+	})
+
+	t.Run("chooser keeps subject", func(t *testing.T) {
+		facts := newFacts()
+		keep := func(context.Context, *entities.Fact, []EntityCandidate) (int, error) { return -1, nil }
+
+		resolved, err := svc.Resolve(context.Background(), "w", facts, keep)
+		require.NoError(t, err)
+		assert.Equal(t, "John", facts[1].Subject)
+		assert.Empty(t, resolved[1].Entity)
+	})
+
+	t.Run("chooser error", func(t *testing.T) {
+		fail := func(context.Context, *entities.Fact, []EntityCandidate) (int, error) {
+			return 0, errors.New("aborted")
+		}
+
+		_, err := svc.Resolve(context.Background(), "w", newFacts(), fail)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "aborted")
+	})
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Version reasons recorded for draft changes without an explicit reason.
+const (
+	draftPromoteReason = "promoted from draft"
+	draftPurgeReason   = "draft purged"
+)
+
+// draftPageSize is the number of drafts read at a time when purging.
+const draftPageSize = 256
+
+// DraftPurge selects the drafts PurgeDrafts removes. The zero value
+// selects every draft.
+type DraftPurge struct {
+	SourceFile  string // Only drafts from this source (empty = all)
+	ExpiredOnly bool   // Only drafts that have lapsed
+	DryRun      bool   // Report the drafts without removing them
+}
+
+// Promote makes a draft canon: it is checked for contradictions, and
+// checked against, like any other fact, and no longer expires. An empty
+// reason records a generic one in the fact's history.
+func (s *FactService) Promote(ctx context.Context, id, reason string) (*entities.Fact, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding fact: %w", err)
+	}
+	if !fact.IsDraft() {
+		return nil, entities.Errorf(entities.ErrValidation, "fact %s is not a draft", id)
+	}
+
+	updated := fact
+	updated.Draft = false
+	updated.ExpiresAt = time.Time{}
+	if reason == "" {
+		reason = draftPromoteReason
+	}
+	if err := s.replace(ctx, &fact, &updated, reason); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// PurgeDrafts removes the drafts opts selects, as when a draft direction is
+// abandoned, and returns them. Each removal is versioned like a manual
+// delete, so a purged draft can still be looked up in its history.
+func (s *FactService) PurgeDrafts(ctx context.Context, opts DraftPurge) ([]entities.Fact, error) {
+	// Every page is read before anything is removed, so removals don't
+	// move the cursor.
+	var drafts []entities.Fact
+	page := ports.FactPageOptions{SourceFile: opts.SourceFile, Drafts: true, Limit: draftPageSize}
+	now := s.now()
+	for {
+		facts, next, err := s.vectorDB.ListPage(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing drafts: %w", err)
+		}
+		for i := range facts {
+			if !opts.ExpiredOnly || facts[i].IsExpired(now) {
+				drafts = append(drafts, facts[i])
+			}
+		}
+		if next == "" {
+			break
+		}
+		page.Cursor = next
+	}
+
+	if opts.DryRun {
+		return drafts, nil
+	}
+	for i := range drafts {
+		if err := s.remove(ctx, &drafts[i], draftPurgeReason); err != nil {
+			return drafts[:i], err
+		}
+	}
+	return drafts, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestFactService_CreateDraft(t *testing.T) {
+	svc, _, _ := newFactTestService()
+	input := NewFact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "sails", Object: "west", Draft: true, TTL: 48 * time.Hour}
+
+	fact, err := svc.Create(context.Background(), input)
+	require.NoError(t, err)
+	assert.True(t, fact.IsDraft())
+	assert.Equal(t, factTestNow.Add(48*time.Hour), fact.ExpiresAt)
+	assert.False(t, fact.IsExpired(factTestNow))
+	assert.True(t, fact.IsExpired(fact.ExpiresAt))
+
+	input.Draft = false
+	_, err = svc.Create(context.Background(), input)
+	require.ErrorIs(t, err, entities.ErrValidation, "a TTL is only for drafts")
+}
+
+func TestFactService_Promote(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService(
+		entities.Fact{ID: "a", Subject: "Frodo", Predicate: "sails", Object: "west", Draft: true, ExpiresAt: factTestNow.Add(time.Hour)},
+		entities.Fact{ID: "b", Subject: "Sam", Predicate: "stays", Object: "Shire"},
+	)
+
+	fact, err := svc.Promote(context.Background(), "a", "")
+	require.NoError(t, err)
+	assert.False(t, fact.IsDraft())
+	assert.True(t, fact.ExpiresAt.IsZero())
+	require.Len(t, vectorDB.SavedFacts, 1)
+	assert.False(t, vectorDB.SavedFacts[0].Draft)
+	require.Len(t, relationalDB.Versions, 2)
+	assert.Equal(t, draftPromoteReason, relationalDB.Versions[1].Reason)
+
+	_, err = svc.Promote(context.Background(), "b", "")
+	require.ErrorIs(t, err, entities.ErrValidation, "canon cannot be promoted")
+}
+
+func TestFactService_PurgeDrafts(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "a", SourceFile: "notes.md", Draft: true, ExpiresAt: factTestNow.Add(-time.Hour)},
+		{ID: "b", SourceFile: "notes.md", Draft: true},
+		{ID: "c", SourceFile: "ideas.md", Draft: true},
+		{ID: "d", SourceFile: "notes.md"},
+	}
+
+	svc, vectorDB, _ := newFactTestService(facts...)
+	purged, err := svc.PurgeDrafts(context.Background(), DraftPurge{SourceFile: "notes.md", DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, purged, 2)
+	assert.Empty(t, vectorDB.DeletedIDs, "a dry run removes nothing")
+
+	purged, err = svc.PurgeDrafts(context.Background(), DraftPurge{ExpiredOnly: true})
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, []string{"a"}, vectorDB.DeletedIDs)
+
+	svc, vectorDB, relationalDB := newFactTestService(facts...)
+	_, err = svc.PurgeDrafts(context.Background(), DraftPurge{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, vectorDB.DeletedIDs, "canon is kept")
+	assert.Equal(t, draftPurgeReason, relationalDB.Versions[len(relationalDB.Versions)-1].Reason)
+}
+
+func TestUnexpired(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "a"},
+		{ID: "b", Draft: true, ExpiresAt: factTestNow.Add(-time.Hour)},
+		{ID: "c", Draft: true, ExpiresAt: factTestNow.Add(time.Hour)},
+		{ID: "d", Draft: true},
+	}
+	var ids []string
+	for _, f := range unexpired(facts, factTestNow) {
+		ids = append(ids, f.ID)
+	}
+	assert.Equal(t, []string{"a", "c", "d"}, ids)
+}
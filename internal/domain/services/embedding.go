@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// FactTemplate renders the text a fact is embedded as, from a text/template
+// over the fact's fields, such as:
+//
+//	{{title .Type}} fact: {{.Subject}} {{.Predicate}} {{.Object}}{{with .Context}}. {{.}}{{end}}
+type FactTemplate struct {
+	source string
+	tmpl   *template.Template
+}
+
+// ParseFactTemplate parses a fact template and checks it renders a fact.
+func ParseFactTemplate(source string) (*FactTemplate, error) {
+	tmpl, err := template.New("fact").
+		Funcs(template.FuncMap{"title": titleCase}).
+		Option("missingkey=zero").
+		Parse(source)
+	if err != nil {
+		return nil, entities.Errorf(entities.ErrValidation, "parsing fact template: %w", err)
+	}
+	t := &FactTemplate{source: source, tmpl: tmpl}
+
+	sample := entities.Fact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", Context: "In the Shire"}
+	if _, err := t.render(&sample); err != nil {
+		return nil, entities.Errorf(entities.ErrValidation, "fact template: %w", err)
+	}
+	return t, nil
+}
+
+// String returns the template's source.
+func (t *FactTemplate) String() string {
+	return t.source
+}
+
+// Text renders fact, falling back to the default text if the template
+// fails on it.
+func (t *FactTemplate) Text(fact *entities.Fact) string {
+	text, err := t.render(fact)
+	if err != nil || strings.TrimSpace(text) == "" {
+		return factToText(fact)
+	}
+	return text
+}
+
+func (t *FactTemplate) render(fact *entities.Fact) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, fact); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// titleCase upper-cases the first letter of s, for fact types in
+// templates.
+func titleCase(s any) string {
+	str := fmt.Sprint(s)
+	r, size := utf8.DecodeRuneInString(str)
+	if r == utf8.RuneError {
+		return str
+	}
+	return string(unicode.ToUpper(r)) + str[size:]
+}
+
+// TemplatedEmbedder is a ports.Embedder whose facts are embedded as its
+// template renders them. The second vector of a fact, matching its triple
+// alone, is unchanged.
+type TemplatedEmbedder struct {
+	ports.Embedder
+	template *FactTemplate
+}
+
+// NewTemplatedEmbedder wraps embedder so facts are embedded as tmpl renders
+// them.
+func NewTemplatedEmbedder(embedder ports.Embedder, tmpl *FactTemplate) *TemplatedEmbedder {
+	return &TemplatedEmbedder{Embedder: embedder, template: tmpl}
+}
+
+// FactText returns the text fact is embedded as.
+func (e *TemplatedEmbedder) FactText(fact *entities.Fact) string {
+	return e.template.Text(fact)
+}
+
+// factTexter is an embedder that renders the facts it embeds its own way.
+type factTexter interface {
+	FactText(fact *entities.Fact) string
+}
+
+// embeddingText returns the text embedder embeds fact as.
+func embeddingText(embedder ports.Embedder, fact *entities.Fact) string {
+	if t, ok := embedder.(factTexter); ok {
+		return t.FactText(fact)
+	}
+	return factToText(fact)
+}
+
+// factToText converts a fact to searchable text for embedding.
+func factToText(fact *entities.Fact) string {
+	if fact.Context == "" {
+		return factTripleText(fact)
+	}
+	return factTripleText(fact) + " " + fact.Context
+}
+
+// factTripleText converts only the subject, predicate, and object to text.
+func factTripleText(fact *entities.Fact) string {
+	return strings.Join([]string{fact.Subject, fact.Predicate, fact.Object}, " ")
+}
+
+// embedFacts fills both embeddings of every fact with a single batch call.
+// Facts embedded as their triple alone, such as those without context,
+// share one embedding for both vectors.
+func embedFacts(ctx context.Context, embedder ports.Embedder, facts []entities.Fact) error {
+	texts := make([]string, 0, 2*len(facts))
+	textIndex := make([]int, len(facts))
+	for i := range facts {
+		text, triple := embeddingText(embedder, &facts[i]), factTripleText(&facts[i])
+		texts = append(texts, text)
+		textIndex[i] = -1
+		if text != triple {
+			textIndex[i] = len(texts)
+			texts = append(texts, triple)
+		}
+	}
+
+	embeddings, err := embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("generating embeddings: %w", err)
+	}
+	if len(embeddings) != len(texts) {
+		return fmt.Errorf("embedding count mismatch: got %d, want %d", len(embeddings), len(texts))
+	}
+
+	next := 0
+	for i := range facts {
+		facts[i].Embedding = embeddings[next]
+		facts[i].TextEmbedding = embeddings[next]
+		next++
+		if textIndex[i] >= 0 {
+			facts[i].TextEmbedding = embeddings[textIndex[i]]
+			next++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestEmbedFacts(t *testing.T) {
+	facts := []entities.Fact{
+		{Subject: "Frodo", Predicate: "carries", Object: "the Ring", Context: "On the road to Mordor"},
+		{Subject: "Sam", Predicate: "is", Object: "loyal"},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.5}}
+
+	err := embedFacts(t.Context(), emb, facts)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, emb.EmbedBatchCallCount)
+	assert.Equal(t, []string{
+		"Frodo carries the Ring On the road to Mordor",
+		"Frodo carries the Ring",
+		"Sam is loyal",
+	}, emb.EmbedBatchLastTexts)
+
+	for i := range facts {
+		assert.NotEmpty(t, facts[i].Embedding)
+		assert.NotEmpty(t, facts[i].TextEmbedding)
+	}
+}
+
+func TestEmbedFacts_Template(t *testing.T) {
+	tmpl, err := ParseFactTemplate("{{title .Type}} fact: {{.Subject}} {{.Predicate}} {{.Object}}{{with .Context}}. {{.}}{{end}}")
+	require.NoError(t, err)
+
+	facts := []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"},
+		{Type: entities.FactTypeLocation, Subject: "Bag End", Predicate: "is_in", Object: "the Shire", Context: "Under the Hill"},
+	}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.5}}
+
+	err = embedFacts(t.Context(), NewTemplatedEmbedder(emb, tmpl), facts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Character fact: Frodo lives_in Bag End",
+		"Frodo lives_in Bag End",
+		"Location fact: Bag End is_in the Shire. Under the Hill",
+		"Bag End is_in the Shire",
+	}, emb.EmbedBatchLastTexts)
+}
+
+func TestParseFactTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr bool
+	}{
+		{name: "fields", source: "{{.Subject}} {{.Predicate}} {{.Object}}"},
+		{name: "title", source: "{{title .Type}}: {{.Subject}}"},
+		{name: "syntax error", source: "{{.Subject", wantErr: true},
+		{name: "unknown field", source: "{{.Speaker}}", wantErr: true},
+		{name: "unknown function", source: "{{upper .Subject}}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseFactTemplate(tt.source)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, entities.ErrValidation)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.source, tmpl.String())
+		})
+	}
+}
+
+func TestFactTemplate_TextFallsBack(t *testing.T) {
+	tmpl, err := ParseFactTemplate("{{with .Context}}{{.}}{{end}}")
+	require.NoError(t, err)
+
+	fact := entities.Fact{Subject: "Sam", Predicate: "is", Object: "loyal"}
+	assert.Equal(t, "Sam is loyal", tmpl.Text(&fact))
+}
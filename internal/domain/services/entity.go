@@ -3,20 +3,36 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
+// EntityUsage counts how much of a world refers to an entity.
+type EntityUsage struct {
+	Entity        *entities.Entity `json:"entity"`
+	Facts         uint64           `json:"facts"`         // Facts with the entity as subject
+	Relationships int              `json:"relationships"` // Relationships the entity takes part in
+}
+
+// IsOrphan reports whether nothing refers to the entity.
+func (u EntityUsage) IsOrphan() bool {
+	return u.Facts == 0 && u.Relationships == 0
+}
+
 // EntityService manages entity operations.
 type EntityService struct {
 	relationalDB ports.RelationalDB
+	vectorDB     ports.VectorDB
 }
 
-// NewEntityService creates a new EntityService.
-func NewEntityService(relationalDB ports.RelationalDB) *EntityService {
+// NewEntityService creates a new EntityService. The vector database is
+// used to count the facts about each entity.
+func NewEntityService(relationalDB ports.RelationalDB, vectorDB ports.VectorDB) *EntityService {
 	return &EntityService{
 		relationalDB: relationalDB,
+		vectorDB:     vectorDB,
 	}
 }
 
@@ -45,22 +61,166 @@ func (s *EntityService) Search(ctx context.Context, worldID, query string, limit
 	return s.relationalDB.SearchEntities(ctx, worldID, query, limit)
 }
 
-// Delete removes an entity and its relationships.
+// EntityDeletionPlan describes what deleting an entity removes: the entity
+// and every relationship it takes part in, with the facts representing
+// them.
+type EntityDeletionPlan struct {
+	Entity        *entities.Entity
+	Relationships []AffectedRelationship
+}
+
+// PlanDelete previews deleting an entity, without changing anything.
+func (s *EntityService) PlanDelete(ctx context.Context, entityID string) (*EntityDeletionPlan, error) {
+	entity, err := s.relationalDB.FindEntityByID(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity: %w", err)
+	}
+	if entity == nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "entity not found: %s", entityID)
+	}
+
+	rels, err := s.relationalDB.FindRelationshipsByEntity(ctx, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("finding relationships of %s: %w", entity.Name, err)
+	}
+
+	names := map[string]string{entity.ID: entity.Name}
+	var others []string
+	for _, rel := range rels {
+		for _, id := range []string{rel.SourceEntityID, rel.TargetEntityID} {
+			if _, ok := names[id]; !ok && !slices.Contains(others, id) {
+				others = append(others, id)
+			}
+		}
+	}
+	if len(others) > 0 {
+		related, err := s.relationalDB.FindEntitiesByIDs(ctx, others)
+		if err != nil {
+			return nil, fmt.Errorf("finding related entities: %w", err)
+		}
+		for _, e := range related {
+			names[e.ID] = e.Name
+		}
+	}
+
+	plan := &EntityDeletionPlan{Entity: entity, Relationships: make([]AffectedRelationship, 0, len(rels))}
+	for _, rel := range rels {
+		plan.Relationships = append(plan.Relationships, AffectedRelationship{
+			Relationship: rel,
+			Source:       names[rel.SourceEntityID],
+			Target:       names[rel.TargetEntityID],
+		})
+	}
+	return plan, nil
+}
+
+// Delete removes an entity and cascades to everything referring to it:
+// each relationship it takes part in is deleted first, along with the fact
+// representing it, and every deletion is recorded in the audit log.
 func (s *EntityService) Delete(ctx context.Context, entityID string) error {
-	// First delete all relationships involving this entity
-	if err := s.relationalDB.DeleteRelationshipsByEntity(ctx, entityID); err != nil {
-		return fmt.Errorf("deleting entity relationships: %w", err)
+	plan, err := s.PlanDelete(ctx, entityID)
+	if err != nil {
+		return err
 	}
+	return s.DeletePlan(ctx, plan)
+}
 
-	// Then delete the entity
-	if err := s.relationalDB.DeleteEntity(ctx, entityID); err != nil {
+// DeletePlan carries out a deletion previewed by PlanDelete. Relationships
+// go first, so a failure part way never leaves one pointing at a missing
+// entity.
+func (s *EntityService) DeletePlan(ctx context.Context, plan *EntityDeletionPlan) error {
+	for i := range plan.Relationships {
+		if err := deleteRelationship(ctx, s.relationalDB, s.vectorDB, &plan.Relationships[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := s.relationalDB.DeleteEntity(ctx, plan.Entity.ID); err != nil {
 		return fmt.Errorf("deleting entity: %w", err)
 	}
+	_ = s.relationalDB.LogAction(ctx, entities.AuditActionEntityDelete, "", map[string]any{
+		"entity_id":     plan.Entity.ID,
+		"name":          plan.Entity.Name,
+		"relationships": len(plan.Relationships),
+	})
 
 	return nil
 }
 
+// deleteRelationship removes a relationship and the fact representing it,
+// and records the deletion in the audit log. The fact goes first, so a
+// failure leaves the relationship to find and retry.
+func deleteRelationship(ctx context.Context, relationalDB ports.RelationalDB, vectorDB ports.VectorDB, rel *AffectedRelationship) error {
+	if err := vectorDB.Delete(ctx, rel.Relationship.ID); err != nil {
+		return fmt.Errorf("deleting fact of relationship %s: %w", rel.Relationship.ID, err)
+	}
+	if err := relationalDB.DeleteRelationship(ctx, rel.Relationship.ID); err != nil {
+		return fmt.Errorf("deleting relationship %s: %w", rel.Relationship.ID, err)
+	}
+	_ = relationalDB.LogAction(ctx, entities.AuditActionRelationshipDelete, rel.Relationship.ID, map[string]any{
+		"type":   string(rel.Relationship.Type),
+		"source": rel.Source,
+		"target": rel.Target,
+	})
+	return nil
+}
+
 // Count returns the number of entities in a world.
 func (s *EntityService) Count(ctx context.Context, worldID string) (int, error) {
 	return s.relationalDB.CountEntities(ctx, worldID)
 }
+
+// Usage counts the facts and relationships referring to each entity.
+// Facts are matched by subject, under the entity's name as stored or normalized.
+func (s *EntityService) Usage(ctx context.Context, worldID string, list []*entities.Entity) ([]EntityUsage, error) {
+	degrees, err := s.relationalDB.CountRelationshipsPerEntity(ctx, worldID)
+	if err != nil {
+		return nil, fmt.Errorf("counting relationships: %w", err)
+	}
+
+	usage := make([]EntityUsage, 0, len(list))
+	for _, entity := range list {
+		subjects := []string{entity.Name}
+		if entity.NormalizedName != entity.Name {
+			subjects = append(subjects, entity.NormalizedName)
+		}
+
+		facts, err := s.vectorDB.CountBySubject(ctx, subjects)
+		if err != nil {
+			return nil, fmt.Errorf("counting facts about %s: %w", entity.Name, err)
+		}
+
+		usage = append(usage, EntityUsage{
+			Entity:        entity,
+			Facts:         facts,
+			Relationships: degrees[entity.ID],
+		})
+	}
+	return usage, nil
+}
+
+// Orphans returns the entities in a world that no fact or relationship refers to.
+func (s *EntityService) Orphans(ctx context.Context, worldID string) ([]*entities.Entity, error) {
+	total, err := s.relationalDB.CountEntities(ctx, worldID)
+	if err != nil {
+		return nil, fmt.Errorf("counting entities: %w", err)
+	}
+
+	all, err := s.relationalDB.ListEntities(ctx, worldID, total, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing entities: %w", err)
+	}
+
+	usage, err := s.Usage(ctx, worldID, all)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []*entities.Entity
+	for _, u := range usage {
+		if u.IsOrphan() {
+			orphans = append(orphans, u.Entity)
+		}
+	}
+	return orphans, nil
+}
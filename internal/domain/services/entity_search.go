@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// maxSummaryFacts bounds how many facts describe an entity in its
+	// embedding, keeping the text within what the embedder reads.
+	maxSummaryFacts = 40
+	// entityEmbedBatch is how many entity summaries are embedded per call.
+	entityEmbedBatch = 64
+	// entityPageSize is how many entities or facts are read per call while
+	// refreshing the index.
+	entityPageSize = 500
+)
+
+// EntitySearchResult is an entity found by a semantic search.
+type EntitySearchResult struct {
+	Entity *entities.Entity `json:"entity"`
+	Score  float32          `json:"score"`
+}
+
+// EntityIndexStats counts what refreshing the entity index changed.
+type EntityIndexStats struct {
+	Embedded  int `json:"embedded"`  // Entities new to the index or whose facts changed
+	Unchanged int `json:"unchanged"` // Entities whose embeddings were current
+	Removed   int `json:"removed"`   // Embeddings of entities no longer in the world
+	Stale     int `json:"stale"`     // Entities left stale by the refresh's limit
+}
+
+// StaleEntity is an entity whose embedding is missing, or was made from
+// facts that have changed since.
+type StaleEntity struct {
+	Entity *entities.Entity `json:"entity"`
+	New    bool             `json:"new"` // Never embedded
+
+	summary     string
+	fingerprint string
+}
+
+// EntityRefreshOptions bounds a refresh of the entity index.
+type EntityRefreshOptions struct {
+	// Limit is the most entities embedded (0 = all). The rest stay stale
+	// until the next refresh; entities never embedded go first.
+	Limit int
+}
+
+// EntitySearchService finds entities by what is known about them: each
+// entity is embedded from its name and a summary of the facts about it.
+type EntitySearchService struct {
+	embedder     ports.Embedder
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
+	index        ports.EntityIndex
+	vectorSize   uint64
+}
+
+// NewEntitySearchService creates an EntitySearchService keeping the
+// entities of relationalDB, summarized from the facts in vectorDB, in
+// index. vectorSize is the size of the embedder's vectors.
+func NewEntitySearchService(embedder ports.Embedder, vectorDB ports.VectorDB, relationalDB ports.RelationalDB, index ports.EntityIndex, vectorSize uint64) *EntitySearchService {
+	return &EntitySearchService{
+		embedder:     embedder,
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
+		index:        index,
+		vectorSize:   vectorSize,
+	}
+}
+
+// Indexed reports whether the world has an entity index, made by an
+// earlier search or refresh.
+func (s *EntitySearchService) Indexed(ctx context.Context) (bool, error) {
+	return s.index.Exists(ctx)
+}
+
+// entityIndexPlan is what bringing the index up to date takes.
+type entityIndexPlan struct {
+	stale     []StaleEntity
+	unchanged int
+	removed   []string // IDs of embeddings whose entities are gone
+}
+
+// plan compares the index with the world's entities and facts.
+func (s *EntitySearchService) plan(ctx context.Context, worldID string) (*entityIndexPlan, error) {
+	exists, err := s.index.Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexed := map[string]string{}
+	if exists {
+		if indexed, err = s.index.Fingerprints(ctx); err != nil {
+			return nil, err
+		}
+	}
+	list, err := s.allEntities(ctx, worldID)
+	if err != nil {
+		return nil, err
+	}
+	facts, err := s.factsBySubject(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &entityIndexPlan{}
+	current := make(map[string]bool, len(list))
+	for _, entity := range list {
+		current[entity.ID] = true
+		summary := entitySummary(entity, facts[entity.NormalizedName])
+		fingerprint := summaryFingerprint(summary)
+		old, isIndexed := indexed[entity.ID]
+		if old == fingerprint {
+			plan.unchanged++
+			continue
+		}
+		plan.stale = append(plan.stale, StaleEntity{Entity: entity, New: !isIndexed, summary: summary, fingerprint: fingerprint})
+	}
+	// Entities never embedded can't be found at all, so they go first.
+	slices.SortStableFunc(plan.stale, func(a, b StaleEntity) int {
+		switch {
+		case a.New == b.New:
+			return 0
+		case a.New:
+			return -1
+		}
+		return 1
+	})
+
+	for id := range indexed {
+		if !current[id] {
+			plan.removed = append(plan.removed, id)
+		}
+	}
+	return plan, nil
+}
+
+// Stale returns the entities whose embeddings are missing or out of date,
+// in the order Refresh embeds them, without changing the index.
+func (s *EntitySearchService) Stale(ctx context.Context, worldID string) ([]StaleEntity, error) {
+	plan, err := s.plan(ctx, worldID)
+	if err != nil {
+		return nil, err
+	}
+	return plan.stale, nil
+}
+
+// Refresh brings the index up to date with the world's entities and
+// facts, or as far as opts.Limit allows. Only entities whose summary
+// changed since they were last embedded are embedded again.
+func (s *EntitySearchService) Refresh(ctx context.Context, worldID string, opts EntityRefreshOptions) (EntityIndexStats, error) {
+	var stats EntityIndexStats
+
+	if err := s.index.EnsureIndex(ctx, s.vectorSize); err != nil {
+		return stats, err
+	}
+	plan, err := s.plan(ctx, worldID)
+	if err != nil {
+		return stats, err
+	}
+	stats.Unchanged = plan.unchanged
+
+	stale := plan.stale
+	if opts.Limit > 0 && len(stale) > opts.Limit {
+		stats.Stale = len(stale) - opts.Limit
+		stale = stale[:opts.Limit]
+	}
+
+	for start := 0; start < len(stale); start += entityEmbedBatch {
+		batch := stale[start:min(start+entityEmbedBatch, len(stale))]
+		texts := make([]string, len(batch))
+		for i, entity := range batch {
+			texts[i] = entity.summary
+		}
+		embeddings, err := s.embedder.EmbedBatch(ctx, texts)
+		if err != nil {
+			return stats, fmt.Errorf("embedding entities: %w", err)
+		}
+
+		indexed := make([]ports.IndexedEntity, len(batch))
+		for i, entity := range batch {
+			indexed[i] = ports.IndexedEntity{
+				EntityID:    entity.Entity.ID,
+				Name:        entity.Entity.Name,
+				Fingerprint: entity.fingerprint,
+				Embedding:   embeddings[i],
+			}
+		}
+		if err := s.index.SaveEntities(ctx, indexed); err != nil {
+			return stats, err
+		}
+		stats.Embedded += len(batch)
+	}
+
+	if err := s.index.DeleteEntities(ctx, plan.removed); err != nil {
+		return stats, err
+	}
+	stats.Removed = len(plan.removed)
+
+	return stats, nil
+}
+
+// Search returns the entities best described by query, closest first.
+// The index is searched as it is; call Refresh first to include changes.
+func (s *EntitySearchService) Search(ctx context.Context, query string, limit int) ([]EntitySearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, entities.Errorf(entities.ErrValidation, "search query is empty")
+	}
+
+	embedding, err := s.embedder.Embed(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("embedding query: %w", err)
+	}
+	matches, err := s.index.SearchEntities(ctx, embedding, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].EntityID
+	}
+	found, err := s.relationalDB.FindEntitiesByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("finding entities: %w", err)
+	}
+	byID := make(map[string]*entities.Entity, len(found))
+	for _, entity := range found {
+		byID[entity.ID] = entity
+	}
+
+	results := make([]EntitySearchResult, 0, len(matches))
+	for i := range matches {
+		entity, ok := byID[matches[i].EntityID]
+		// Deleted since the index was refreshed.
+		if !ok {
+			continue
+		}
+		results = append(results, EntitySearchResult{Entity: entity, Score: matches[i].Score})
+	}
+	return results, nil
+}
+
+// allEntities returns every entity of a world.
+func (s *EntitySearchService) allEntities(ctx context.Context, worldID string) ([]*entities.Entity, error) {
+	var all []*entities.Entity
+	for offset := 0; ; offset += entityPageSize {
+		page, err := s.relationalDB.ListEntities(ctx, worldID, entityPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("listing entities: %w", err)
+		}
+		all = append(all, page...)
+		if len(page) < entityPageSize {
+			return all, nil
+		}
+	}
+}
+
+// factsBySubject returns the accepted canon facts of the world, by the
+// normalized name of their subject. Drafts, facts pending review, and
+// characters' claims do not describe an entity.
+func (s *EntitySearchService) factsBySubject(ctx context.Context) (map[string][]entities.Fact, error) {
+	bySubject := make(map[string][]entities.Fact)
+
+	opts := ports.FactPageOptions{Limit: entityPageSize}
+	for {
+		page, next, err := s.vectorDB.ListPage(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing facts: %w", err)
+		}
+		for i := range page {
+			if page[i].IsDraft() || page[i].IsPending() || page[i].IsClaim() {
+				continue
+			}
+			name := entities.NormalizeName(page[i].Subject)
+			bySubject[name] = append(bySubject[name], page[i])
+		}
+		if next == "" {
+			return bySubject, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// entitySummary returns the text an entity is embedded from: its name,
+// then what its facts say about it, in a stable order so an unchanged
+// entity has an unchanged summary.
+func entitySummary(entity *entities.Entity, facts []entities.Fact) string {
+	lines := make([]string, 0, len(facts))
+	for i := range facts {
+		lines = append(lines, strings.TrimSpace(facts[i].Predicate+" "+facts[i].Object))
+	}
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+	if len(lines) > maxSummaryFacts {
+		lines = lines[:maxSummaryFacts]
+	}
+
+	if len(lines) == 0 {
+		return entity.Name
+	}
+	return entity.Name + ": " + strings.Join(lines, "; ") + "."
+}
+
+// summaryFingerprint identifies a summary, to tell whether an entity's
+// embedding is current.
+func summaryFingerprint(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// keywordEmbedder embeds text as how often it uses each of its words.
+type keywordEmbedder struct {
+	words []string
+	texts []string // Every text embedded in a batch
+}
+
+func (e *keywordEmbedder) Embed(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float32, len(e.words)+1)
+	vector[len(e.words)] = 0.1
+	for i, word := range e.words {
+		vector[i] = float32(strings.Count(strings.ToLower(text), word))
+	}
+	return vector, nil
+}
+
+func (e *keywordEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	e.texts = append(e.texts, texts...)
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		//nolint:loopcall // Keyword vectors are computed locally
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+// memoryEntityIndex keeps entity embeddings in a map.
+type memoryEntityIndex struct {
+	entities map[string]ports.IndexedEntity
+}
+
+func (x *memoryEntityIndex) Exists(context.Context) (bool, error) {
+	return x.entities != nil, nil
+}
+
+func (x *memoryEntityIndex) EnsureIndex(context.Context, uint64) error {
+	if x.entities == nil {
+		x.entities = make(map[string]ports.IndexedEntity)
+	}
+	return nil
+}
+
+func (x *memoryEntityIndex) DeleteIndex(context.Context) error {
+	x.entities = nil
+	return nil
+}
+
+func (x *memoryEntityIndex) SaveEntities(_ context.Context, list []ports.IndexedEntity) error {
+	for _, entity := range list {
+		x.entities[entity.EntityID] = entity
+	}
+	return nil
+}
+
+func (x *memoryEntityIndex) DeleteEntities(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(x.entities, id)
+	}
+	return nil
+}
+
+func (x *memoryEntityIndex) Fingerprints(context.Context) (map[string]string, error) {
+	fingerprints := make(map[string]string, len(x.entities))
+	for id, entity := range x.entities {
+		fingerprints[id] = entity.Fingerprint
+	}
+	return fingerprints, nil
+}
+
+func (x *memoryEntityIndex) SearchEntities(_ context.Context, embedding []float32, limit int) ([]ports.EntityMatch, error) {
+	var matches []ports.EntityMatch
+	for id, entity := range x.entities {
+		matches = append(matches, ports.EntityMatch{EntityID: id, Name: entity.Name, Score: cosine(embedding, entity.Embedding)})
+	}
+	slices.SortFunc(matches, func(a, b ports.EntityMatch) int {
+		return -cmpFloat(a.Score, b.Score)
+	})
+	return matches[:min(limit, len(matches))], nil
+}
+
+func cosine(a, b []float32) float32 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i] * b[i])
+		na += float64(a[i] * a[i])
+		nb += float64(b[i] * b[i])
+	}
+	return float32(dot / math.Sqrt(na*nb))
+}
+
+func cmpFloat(a, b float32) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func TestEntitySearchService(t *testing.T) {
+	ctx := context.Background()
+	relationalDB := mocks.NewRelationalDB()
+	durin, err := relationalDB.FindOrCreateEntity(ctx, "middle-earth", "Durin")
+	require.NoError(t, err)
+	gandalf, err := relationalDB.FindOrCreateEntity(ctx, "middle-earth", "Gandalf")
+	require.NoError(t, err)
+
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Subject: "Durin", Predicate: "works as", Object: "a blacksmith"},
+		{ID: "2", Subject: "durin", Predicate: "is", Object: "grumpy"},
+		{ID: "3", Subject: "Gandalf", Predicate: "is a", Object: "wizard"},
+		{ID: "4", Subject: "Gandalf", Predicate: "is", Object: "a grumpy blacksmith", Draft: true},
+		{ID: "5", Subject: "Gandalf", Predicate: "is", Object: "a grumpy blacksmith", Status: entities.FactStatusPending},
+		{ID: "6", Subject: "Gandalf", Predicate: "is", Object: "a grumpy blacksmith", AssertedBy: "Saruman", Claim: true},
+	}}
+	embedder := &keywordEmbedder{words: []string{"blacksmith", "grumpy", "wizard", "pipe"}}
+	index := &memoryEntityIndex{}
+	svc := NewEntitySearchService(embedder, vectorDB, relationalDB, index, 5)
+
+	indexed, err := svc.Indexed(ctx)
+	require.NoError(t, err)
+	assert.False(t, indexed)
+	stale, err := svc.Stale(ctx, "middle-earth")
+	require.NoError(t, err)
+	assert.Len(t, stale, 2)
+	assert.Nil(t, index.entities, "listing stale entities doesn't create the index")
+
+	stats, err := svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, EntityIndexStats{Embedded: 2}, stats)
+	assert.ElementsMatch(t, []string{"Durin: is grumpy; works as a blacksmith.", "Gandalf: is a wizard."}, embedder.texts,
+		"drafts, facts pending review, and claims are left out")
+
+	results, err := svc.Search(ctx, "the grumpy blacksmith", 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, durin.ID, results[0].Entity.ID)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	t.Run("unchanged entities are not embedded again", func(t *testing.T) {
+		embedder.texts = nil
+		stats, err := svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, EntityIndexStats{Unchanged: 2}, stats)
+		assert.Empty(t, embedder.texts)
+	})
+
+	t.Run("entities whose facts changed are embedded again", func(t *testing.T) {
+		embedder.texts = nil
+		vectorDB.Facts = append(vectorDB.Facts, entities.Fact{ID: "7", Subject: "Gandalf", Predicate: "smokes", Object: "a pipe"})
+		stats, err := svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, EntityIndexStats{Embedded: 1, Unchanged: 1}, stats)
+		assert.Equal(t, []string{"Gandalf: is a wizard; smokes a pipe."}, embedder.texts)
+
+		results, err := svc.Search(ctx, "pipe", 1)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, gandalf.ID, results[0].Entity.ID)
+	})
+
+	t.Run("deleted entities are removed", func(t *testing.T) {
+		require.NoError(t, relationalDB.DeleteEntity(ctx, gandalf.ID))
+		stats, err := svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, EntityIndexStats{Unchanged: 1, Removed: 1}, stats)
+		assert.NotContains(t, index.entities, gandalf.ID)
+	})
+
+	t.Run("a limit leaves the rest stale, entities never embedded first", func(t *testing.T) {
+		_, err := relationalDB.FindOrCreateEntity(ctx, "middle-earth", "Balin")
+		require.NoError(t, err)
+		vectorDB.Facts = append(vectorDB.Facts, entities.Fact{ID: "8", Subject: "Durin", Predicate: "lives in", Object: "Moria"})
+
+		stale, err := svc.Stale(ctx, "middle-earth")
+		require.NoError(t, err)
+		require.Len(t, stale, 2)
+		assert.Equal(t, "Balin", stale[0].Entity.Name)
+		assert.True(t, stale[0].New)
+		assert.Equal(t, "Durin", stale[1].Entity.Name)
+		assert.False(t, stale[1].New)
+
+		embedder.texts = nil
+		stats, err := svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, EntityIndexStats{Embedded: 1, Stale: 1}, stats)
+		assert.Equal(t, []string{"Balin"}, embedder.texts)
+
+		stats, err = svc.Refresh(ctx, "middle-earth", EntityRefreshOptions{Limit: 1})
+		require.NoError(t, err)
+		assert.Equal(t, EntityIndexStats{Embedded: 1, Unchanged: 1}, stats)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		_, err := svc.Search(ctx, "  ", 5)
+		require.ErrorIs(t, err, entities.ErrValidation)
+	})
+}
+
+func TestEntitySummary(t *testing.T) {
+	entity := &entities.Entity{Name: "Bree"}
+	assert.Equal(t, "Bree", entitySummary(entity, nil))
+
+	facts := []entities.Fact{
+		{Predicate: "has", Object: "an inn"},
+		{Predicate: "is in", Object: "Eriador"},
+		{Predicate: "has", Object: "an inn"},
+	}
+	assert.Equal(t, "Bree: has an inn; is in Eriador.", entitySummary(entity, facts), "duplicates are summarized once")
+
+	many := make([]entities.Fact, maxSummaryFacts+10)
+	for i := range many {
+		many[i] = entities.Fact{Predicate: "borders", Object: strings.Repeat("x", i+1)}
+	}
+	assert.Equal(t, maxSummaryFacts, strings.Count(entitySummary(entity, many), "borders"))
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func newEntityUsageTestService() *EntityService {
+	relationalDB := mocks.NewRelationalDB()
+	for _, name := range []string{"Frodo", "Sam", "Tom Bombadil"} {
+		relationalDB.Entities["e-"+name] = &entities.Entity{
+			ID: "e-" + name, WorldID: "w", Name: name, NormalizedName: entities.NormalizeName(name),
+		}
+	}
+	relationalDB.Relationships = []entities.Relationship{
+		{ID: "r1", SourceEntityID: "e-Frodo", TargetEntityID: "e-Sam", Type: entities.RelationAlly},
+	}
+
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "f1", Subject: "Frodo", Predicate: "is", Object: "a hobbit"},
+		{ID: "f2", Subject: "frodo", Predicate: "owns", Object: "Sting"},
+		{ID: "f3", Subject: "Gandalf", Predicate: "visits", Object: "Frodo"},
+	}}
+
+	return NewEntityService(relationalDB, vectorDB)
+}
+
+func TestEntityService_Usage(t *testing.T) {
+	svc := newEntityUsageTestService()
+	relationalDB := svc.relationalDB.(*mocks.RelationalDB)
+
+	list := []*entities.Entity{
+		relationalDB.Entities["e-Frodo"],
+		relationalDB.Entities["e-Sam"],
+		relationalDB.Entities["e-Tom Bombadil"],
+	}
+	usage, err := svc.Usage(context.Background(), "w", list)
+	require.NoError(t, err)
+	require.Len(t, usage, 3)
+
+	tests := []struct {
+		name          string
+		facts         uint64
+		relationships int
+		orphan        bool
+	}{
+		{"Frodo", 2, 1, false},
+		{"Sam", 0, 1, false},
+		{"Tom Bombadil", 0, 0, true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.name, usage[i].Entity.Name)
+			assert.Equal(t, tt.facts, usage[i].Facts)
+			assert.Equal(t, tt.relationships, usage[i].Relationships)
+			assert.Equal(t, tt.orphan, usage[i].IsOrphan())
+		})
+	}
+}
+
+func TestEntityService_Orphans(t *testing.T) {
+	svc := newEntityUsageTestService()
+
+	orphans, err := svc.Orphans(context.Background(), "w")
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "Tom Bombadil", orphans[0].Name)
+}
+
+func TestEntityService_Delete(t *testing.T) {
+	svc := newEntityUsageTestService()
+	relationalDB := svc.relationalDB.(*mocks.RelationalDB)
+	vectorDB := svc.vectorDB.(*mocks.VectorDB)
+	ctx := context.Background()
+
+	plan, err := svc.PlanDelete(ctx, "e-Frodo")
+	require.NoError(t, err)
+	assert.Equal(t, "Frodo", plan.Entity.Name)
+	require.Len(t, plan.Relationships, 1)
+	assert.Equal(t, "Frodo", plan.Relationships[0].Source)
+	assert.Equal(t, "Sam", plan.Relationships[0].Target)
+	assert.Len(t, relationalDB.Relationships, 1, "planning changes nothing")
+	assert.Empty(t, vectorDB.DeletedIDs)
+
+	require.NoError(t, svc.DeletePlan(ctx, plan))
+	assert.NotContains(t, relationalDB.Entities, "e-Frodo")
+	assert.Empty(t, relationalDB.Relationships)
+	assert.Equal(t, []string{"r1"}, vectorDB.DeletedIDs, "the relationship's fact is deleted")
+
+	require.Len(t, relationalDB.AuditLog, 2)
+	assert.Equal(t, entities.AuditActionRelationshipDelete, relationalDB.AuditLog[0].Action)
+	assert.Equal(t, "r1", relationalDB.AuditLog[0].FactID)
+	assert.Equal(t, entities.AuditActionEntityDelete, relationalDB.AuditLog[1].Action)
+	assert.Equal(t, "Frodo", relationalDB.AuditLog[1].Details["name"])
+
+	err = svc.Delete(ctx, "e-Frodo")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
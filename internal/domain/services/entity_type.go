@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -72,7 +71,7 @@ func (s *EntityTypeService) Add(ctx context.Context, name, description string) e
 	name = strings.ToLower(strings.TrimSpace(name))
 
 	if !validTypeNameRegex.MatchString(name) {
-		return errors.New("invalid type name: must be lowercase alphanumeric with underscores, starting with a letter")
+		return entities.Errorf(entities.ErrValidation, "invalid type name: must be lowercase alphanumeric with underscores, starting with a letter")
 	}
 
 	existing, err := s.relationalDB.FindEntityType(ctx, name)
@@ -80,7 +79,7 @@ func (s *EntityTypeService) Add(ctx context.Context, name, description string) e
 		return fmt.Errorf("checking entity type: %w", err)
 	}
 	if existing != nil {
-		return fmt.Errorf("entity type '%s' already exists", name)
+		return entities.Errorf(entities.ErrConflict, "entity type '%s' already exists", name)
 	}
 
 	et := &entities.EntityType{
@@ -98,7 +97,7 @@ func (s *EntityTypeService) Add(ctx context.Context, name, description string) e
 // Remove deletes a custom entity type.
 func (s *EntityTypeService) Remove(ctx context.Context, name string) error {
 	if entities.IsDefaultType(name) {
-		return fmt.Errorf("cannot remove default entity type '%s'", name)
+		return entities.Errorf(entities.ErrValidation, "cannot remove default entity type '%s'", name)
 	}
 
 	existing, err := s.relationalDB.FindEntityType(ctx, name)
@@ -106,7 +105,7 @@ func (s *EntityTypeService) Remove(ctx context.Context, name string) error {
 		return fmt.Errorf("checking entity type: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("entity type '%s' not found", name)
+		return entities.Errorf(entities.ErrNotFound, "entity type '%s' not found", name)
 	}
 
 	if err := s.relationalDB.DeleteEntityType(ctx, name); err != nil {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -117,6 +119,10 @@ func (m *mockRelationalDB) SearchEntities(_ context.Context, _, _ string, _ int)
 	return nil, nil
 }
 
+func (m *mockRelationalDB) RenameEntity(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *mockRelationalDB) DeleteEntity(_ context.Context, entityID string) error {
 	delete(m.entities, entityID)
 	return nil
@@ -136,6 +142,18 @@ func (m *mockRelationalDB) FindRelationshipsByEntity(_ context.Context, _ string
 	return nil, nil
 }
 
+func (m *mockRelationalDB) ListRelationshipsByEntity(_ context.Context, _ string, _ ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) CountRelationshipsByEntity(_ context.Context, _ string, _ string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockRelationalDB) CountRelationshipsPerEntity(_ context.Context, _ string) (map[string]int, error) {
+	return nil, nil
+}
+
 func (m *mockRelationalDB) FindRelationshipsByType(_ context.Context, _ string) ([]entities.Relationship, error) {
 	return nil, nil
 }
@@ -148,6 +166,10 @@ func (m *mockRelationalDB) DeleteRelationshipsByEntity(_ context.Context, _ stri
 	return nil
 }
 
+func (m *mockRelationalDB) MergeEntities(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *mockRelationalDB) FindRelationshipBetween(_ context.Context, _, _ string) (*entities.Relationship, error) {
 	return nil, nil
 }
@@ -178,6 +200,10 @@ func (m *mockRelationalDB) CountVersions(_ context.Context, _ string) (int, erro
 	return 0, nil
 }
 
+func (m *mockRelationalDB) ListVersionsAsOf(_ context.Context, _ time.Time) ([]entities.FactVersion, error) {
+	return nil, nil
+}
+
 // Audit log methods.
 
 func (m *mockRelationalDB) LogAction(_ context.Context, _ string, _ string, _ map[string]any) error {
@@ -192,6 +218,131 @@ func (m *mockRelationalDB) FindAuditLogByAction(_ context.Context, _ string, _ i
 	return nil, nil
 }
 
+func (m *mockRelationalDB) SaveConflict(_ context.Context, _ *entities.Conflict) (bool, error) {
+	return true, nil
+}
+
+func (m *mockRelationalDB) ListConflicts(_ context.Context, _ ports.ConflictListOptions) ([]entities.Conflict, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) ResolveConflict(_ context.Context, _ string, _ string, _ time.Time) error {
+	return nil
+}
+
+func (m *mockRelationalDB) CountOpenConflicts(_ context.Context, _ []string) (map[string]int, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) SaveTranslation(_ context.Context, _ *entities.Translation) (bool, error) {
+	return true, nil
+}
+
+func (m *mockRelationalDB) ListTranslations(_ context.Context, _ []string) ([]entities.Translation, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) SaveHealthSample(_ context.Context, _ *entities.HealthSample) error {
+	return nil
+}
+
+func (m *mockRelationalDB) ListHealthSamples(_ context.Context, _ int) ([]entities.HealthSample, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) FindConsistencyVerdicts(_ context.Context, _ []string, _ time.Time) (map[string]entities.ConsistencyVerdict, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) SaveConsistencyVerdicts(_ context.Context, _ []entities.ConsistencyVerdict) error {
+	return nil
+}
+func (m *mockRelationalDB) PruneConsistencyVerdicts(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockRelationalDB) SaveStyleTerm(_ context.Context, _ *entities.StyleTerm) error {
+	return nil
+}
+
+func (m *mockRelationalDB) ListStyleTerms(_ context.Context) ([]entities.StyleTerm, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) SaveSourceStats(_ context.Context, _ *entities.SourceStats) error {
+	return nil
+}
+func (m *mockRelationalDB) ListSourceStats(_ context.Context) ([]entities.SourceStats, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) SaveArchivedSource(_ context.Context, _ *entities.ArchivedSource) error {
+	return nil
+}
+func (m *mockRelationalDB) DeleteArchivedSource(_ context.Context, _ string) error {
+	return nil
+}
+func (m *mockRelationalDB) ListArchivedSources(_ context.Context) ([]entities.ArchivedSource, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) SaveFailedChunk(_ context.Context, _ *entities.FailedChunk) error {
+	return nil
+}
+func (m *mockRelationalDB) ListFailedChunks(_ context.Context) ([]entities.FailedChunk, error) {
+	return nil, nil
+}
+func (m *mockRelationalDB) DeleteFailedChunk(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *mockRelationalDB) SaveUsage(_ context.Context, _ *entities.UsageRecord) error {
+	return nil
+}
+
+func (m *mockRelationalDB) SumUsage(_ context.Context, _ time.Time) ([]entities.UsageTotals, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) SaveFactSubjects(_ context.Context, _ []entities.FactSubject) error {
+	return nil
+}
+
+func (m *mockRelationalDB) DeleteFactSubjects(_ context.Context, _ ports.FactSubjectFilter) error {
+	return nil
+}
+
+func (m *mockRelationalDB) ListFactIDsByEntities(_ context.Context, _ []string, _ int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) SaveExtractionStat(_ context.Context, _ *entities.ExtractionStat) error {
+	return nil
+}
+
+func (m *mockRelationalDB) SumExtractionStats(_ context.Context) ([]entities.ModelStats, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) FindIdempotencyRecord(_ context.Context, _ string) (*entities.IdempotencyRecord, error) {
+	return nil, nil
+}
+
+func (m *mockRelationalDB) SaveIdempotencyRecord(_ context.Context, _ *entities.IdempotencyRecord) error {
+	return nil
+}
+
+func (m *mockRelationalDB) ClaimIdempotencyKey(_ context.Context, _ *entities.IdempotencyRecord, _ time.Time) (bool, error) {
+	return true, nil
+}
+
+func (m *mockRelationalDB) ReleaseIdempotencyKey(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *mockRelationalDB) RecordChanges(_ context.Context, _ []entities.Change) error {
+	return nil
+}
+
+func (m *mockRelationalDB) ListChanges(_ context.Context, _ int64, _ int) ([]entities.Change, error) {
+	return nil, nil
+}
+
 // Tests
 
 func TestEntityTypeService_LoadDefaults(t *testing.T) {
@@ -290,6 +441,7 @@ func TestEntityTypeService_Add_Duplicate(t *testing.T) {
 	err = svc.Add(context.Background(), "weapon", "Second")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "already exists")
+	assert.ErrorIs(t, err, entities.ErrConflict)
 }
 
 func TestEntityTypeService_Remove(t *testing.T) {
@@ -326,6 +478,7 @@ func TestEntityTypeService_Remove_NotFound(t *testing.T) {
 	err := svc.Remove(context.Background(), "nonexistent")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
 }
 
 func TestEntityTypeService_IsValid(t *testing.T) {
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// ExportProfile selects the facts a reader may see, so a world can be
+// shared without revealing what comes later in the story.
+type ExportProfile struct {
+	// Sources are path.Match patterns for the source files in reading
+	// order, such as "book1/*" then "book2/*". Facts from sources matching
+	// none of them are left out. Empty means every source.
+	Sources []string
+
+	// Through is the last source revealed: a source file, or one of the
+	// Sources patterns to reveal everything it matches. Facts from sources
+	// that come later in reading order are left out. Within one pattern,
+	// sources are ordered by name with numbers compared by value, so ch2
+	// comes before ch10. Empty means every source in Sources.
+	Through string
+
+	// Types are the fact types revealed. Empty means every type.
+	Types []entities.FactType
+}
+
+// Validate checks the source patterns are well formed and Through is
+// among them.
+func (p ExportProfile) Validate() error {
+	for _, pattern := range p.Sources {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return entities.Errorf(entities.ErrValidation, "invalid source pattern %q: %v", pattern, err)
+		}
+	}
+	if p.Through == "" {
+		return nil
+	}
+	if len(p.Sources) == 0 {
+		return entities.Errorf(entities.ErrValidation, "through %q needs sources listed in reading order", p.Through)
+	}
+	if _, ok := p.position(p.Through); !ok && !slices.Contains(p.Sources, p.Through) {
+		return entities.Errorf(entities.ErrValidation, "through %q matches none of the sources", p.Through)
+	}
+	return nil
+}
+
+// Filter returns the facts the profile reveals, in order.
+func (p ExportProfile) Filter(facts []entities.Fact) []entities.Fact {
+	var revealed []entities.Fact
+	for i := range facts {
+		if p.Reveals(&facts[i]) {
+			revealed = append(revealed, facts[i])
+		}
+	}
+	return revealed
+}
+
+// Reveals reports whether the profile reveals a fact.
+func (p ExportProfile) Reveals(fact *entities.Fact) bool {
+	if len(p.Types) > 0 && !slices.Contains(p.Types, fact.Type) {
+		return false
+	}
+	if len(p.Sources) == 0 {
+		return true
+	}
+
+	index, ok := p.position(fact.SourceFile)
+	if !ok {
+		return false
+	}
+	if p.Through == "" {
+		return true
+	}
+	if last := slices.Index(p.Sources, p.Through); last >= 0 {
+		return index <= last
+	}
+	last, _ := p.position(p.Through)
+	if index != last {
+		return index < last
+	}
+	return compareNatural(fact.SourceFile, p.Through) <= 0
+}
+
+// position returns the index of the first Sources pattern matching source.
+func (p ExportProfile) position(source string) (int, bool) {
+	for i, pattern := range p.Sources {
+		if matched, _ := path.Match(pattern, source); matched {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// compareNatural compares two names as strings, except that runs of digits
+// are compared by value, so "ch2" sorts before "ch10".
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, nb := strings.TrimLeft(da, "0"), strings.TrimLeft(db, "0")
+			if c := len(na) - len(nb); c != 0 {
+				return c
+			}
+			if c := strings.Compare(na, nb); c != 0 {
+				return c
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+// leadingDigits returns the run of ASCII digits s starts with.
+func leadingDigits(s string) string {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i]
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestExportProfile_Reveals(t *testing.T) {
+	sources := []string{"book1/*", "book2/*"}
+	tests := []struct {
+		name    string
+		profile ExportProfile
+		source  string
+		want    bool
+	}{
+		{"no sources reveals all", ExportProfile{}, "notes/twist.md", true},
+		{"listed source", ExportProfile{Sources: sources}, "book2/ch01.md", true},
+		{"unlisted source", ExportProfile{Sources: sources}, "notes/twist.md", false},
+		{"through a pattern", ExportProfile{Sources: sources, Through: "book1/*"}, "book1/ch30.md", true},
+		{"after a pattern", ExportProfile{Sources: sources, Through: "book1/*"}, "book2/ch01.md", false},
+		{"through a chapter", ExportProfile{Sources: sources, Through: "book2/ch10.md"}, "book2/ch9.md", true},
+		{"earlier book", ExportProfile{Sources: sources, Through: "book2/ch10.md"}, "book1/ch99.md", true},
+		{"after a chapter", ExportProfile{Sources: sources, Through: "book2/ch10.md"}, "book2/ch11.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := entities.Fact{Type: entities.FactTypeCharacter, SourceFile: tt.source}
+			assert.Equal(t, tt.want, tt.profile.Reveals(&fact))
+		})
+	}
+}
+
+func TestExportProfile_Types(t *testing.T) {
+	profile := ExportProfile{Types: []entities.FactType{entities.FactTypeLocation}}
+	facts := []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter},
+		{ID: "b", Type: entities.FactTypeLocation},
+	}
+
+	revealed := profile.Filter(facts)
+	assert.Len(t, revealed, 1)
+	assert.Equal(t, "b", revealed[0].ID)
+}
+
+func TestExportProfile_Validate(t *testing.T) {
+	assert.NoError(t, ExportProfile{}.Validate())
+	assert.NoError(t, ExportProfile{Sources: []string{"book1/*"}, Through: "book1/ch3.md"}.Validate())
+	assert.ErrorIs(t, ExportProfile{Sources: []string{"book1/["}}.Validate(), entities.ErrValidation)
+	assert.ErrorIs(t, ExportProfile{Through: "book1/ch3.md"}.Validate(), entities.ErrValidation)
+	assert.ErrorIs(t, ExportProfile{Sources: []string{"book1/*"}, Through: "book2/ch1.md"}.Validate(), entities.ErrValidation)
+}
+
+func TestCompareNatural(t *testing.T) {
+	assert.Negative(t, compareNatural("ch2", "ch10"))
+	assert.Negative(t, compareNatural("ch02", "ch10"))
+	assert.Zero(t, compareNatural("ch7.md", "ch7.md"))
+	assert.Positive(t, compareNatural("ch7b", "ch7a"))
+	assert.Negative(t, compareNatural("ch7", "ch7a"))
+}
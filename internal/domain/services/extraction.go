@@ -19,12 +19,67 @@ import (
 type ExtractionOptions struct {
 	CheckConsistency bool // Check for contradictions with existing facts
 	CheckOnly        bool // Only check, don't save facts
+	ResolvePronouns  bool // Replace pronoun subjects and objects with names from the chunk
+	CarryContext     bool // Give each chunk a running summary of the chunks before it
+
+	// ReviewThreshold holds facts with lower confidence for review
+	// instead of making them searchable (0 = off).
+	ReviewThreshold float64
+
+	// Focus adds a specialized extraction pass per chunk for each kind of fact listed.
+	Focus []ports.ExtractionFocus
+
+	// Retrieval selects the stored facts new facts are checked against.
+	Retrieval Retrieval
+
+	// Disambiguate rewrites ambiguous subjects before facts are embedded (nil = off).
+	Disambiguate func(ctx context.Context, facts []entities.Fact) error
+
+	// OnChunk is called after each chunk is extracted, before facts are
+	// embedded or saved (nil = off).
+	OnChunk func(ChunkProgress)
+
+	// Dialogue extracts the lines of speakers tagged as in a screenplay or
+	// script apart from the narration, as asserted by the speaker.
+	Dialogue bool
+
+	// Reliable lists the speakers whose lines are canon. Other speakers'
+	// lines are their claims.
+	Reliable []string
+
+	// Narrator attributes the narration to an unreliable narrator, so its
+	// facts are their claims (empty = the narration is canon).
+	Narrator string
+
+	// Chunker splits the text into chunks (nil = whole paragraphs, see
+	// ports.ChunkParagraph).
+	Chunker ports.Chunker
+
+	// Sources give facts the metadata and tags of the source they were
+	// extracted from (nil = none).
+	Sources SourceRules
+
+	// Quarantine keeps a chunk whose facts could not be extracted, and
+	// extraction goes on with the next chunk (nil = the failure aborts
+	// extraction).
+	Quarantine func(ctx context.Context, chunk *entities.FailedChunk) error
+}
+
+// ChunkProgress reports the facts extracted from one chunk.
+type ChunkProgress struct {
+	Index int // Zero-based position of the chunk in the text
+	Facts []entities.Fact
 }
 
 // ExtractionResult contains the result of extraction.
 type ExtractionResult struct {
 	Facts  []entities.Fact
 	Issues []ports.ConsistencyIssue
+	Words  int // Words in the text facts were extracted from
+
+	// Quarantined is the number of chunks whose facts could not be
+	// extracted and were handed to ExtractionOptions.Quarantine.
+	Quarantined int
 }
 
 const (
@@ -55,33 +110,166 @@ func NewExtractionService(llm ports.LLMClient, embedder ports.Embedder, vectorDB
 // extractFromChunks extracts facts from text chunks.
 // Note: LLM calls in loop are intentional - LLMs have token limits, so text
 // must be chunked and each chunk processed separately. Cannot be batched.
-func (s *ExtractionService) extractFromChunks(ctx context.Context, text string, sourceFile string, validTypes []string) ([]entities.Fact, error) {
+func (s *ExtractionService) extractFromChunks(ctx context.Context, text string, sourceFile string, validTypes []string, opts *ExtractionOptions) ([]entities.Fact, int, error) {
 	chunks := ChunkText(text, DefaultChunkSize, DefaultChunkOverlap)
+	if opts.Chunker != nil {
+		chunks = nil
+		err := opts.Chunker.Chunk(strings.NewReader(text), func(chunk string) error {
+			chunks = append(chunks, chunk)
+			return nil
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("chunking text: %w", err)
+		}
+	}
+
+	carrier := &contextCarrier{llm: s.llm, enabled: opts.CarryContext}
 
 	var allFacts []entities.Fact
+	quarantined := 0
 	for i, chunk := range chunks {
 		//nolint:loopcall // LLM has token limits, must process chunks separately
-		facts, err := s.llm.ExtractFacts(ctx, chunk, validTypes)
+		priorContext, err := carrier.next(ctx, chunk)
 		if err != nil {
-			return nil, fmt.Errorf("extracting facts from chunk %d: %w", i, err)
+			return nil, 0, fmt.Errorf("chunk %d: %w", i, err)
 		}
 
-		for j := range facts {
-			facts[j].ID = uuid.New().String()
-			facts[j].SourceFile = sourceFile
-			facts[j].CreatedAt = time.Now()
-			facts[j].UpdatedAt = time.Now()
+		facts, err := s.extractChunk(ctx, chunk, priorContext, sourceFile, 0, validTypes, opts)
+		if err != nil {
+			failed := entities.FailedChunk{Source: sourceFile, Index: i, Text: chunk, PriorContext: priorContext}
+			if err := quarantineChunk(ctx, opts, &failed, err); err != nil {
+				return nil, 0, fmt.Errorf("chunk %d: %w", i, err)
+			}
+			quarantined++
+			continue
+		}
+		if opts.OnChunk != nil {
+			opts.OnChunk(ChunkProgress{Index: i, Facts: facts})
 		}
-
 		allFacts = append(allFacts, facts...)
 	}
 
-	return allFacts, nil
+	return allFacts, quarantined, nil
+}
+
+// quarantineChunk hands a chunk whose extraction failed with err to
+// opts.Quarantine. It returns err if there is no quarantine or ctx is done,
+// since a cancelled ingest should stop rather than quarantine every chunk.
+func quarantineChunk(ctx context.Context, opts *ExtractionOptions, chunk *entities.FailedChunk, err error) error {
+	if opts.Quarantine == nil || ctx.Err() != nil {
+		return err
+	}
+	chunk.Error = err.Error()
+	if qerr := opts.Quarantine(ctx, chunk); qerr != nil {
+		return fmt.Errorf("quarantining failed chunk (%w): %w", err, qerr)
+	}
+	return nil
+}
+
+// contextCarrier keeps a running summary of earlier chunks so references to
+// characters introduced before a chunk boundary can be resolved.
+type contextCarrier struct {
+	llm     ports.LLMClient
+	enabled bool
+	summary string
+	pending string // Previous chunk, not yet folded into the summary
+}
+
+// next folds the previous chunk into the summary and returns the summary to
+// extract chunk with. Chunks are folded lazily, so the last chunk of a file
+// costs no summary call.
+func (c *contextCarrier) next(ctx context.Context, chunk string) (string, error) {
+	if !c.enabled {
+		return "", nil
+	}
+
+	if c.pending != "" {
+		summary, err := c.llm.SummarizeChunk(ctx, c.summary, c.pending)
+		if err != nil {
+			return "", fmt.Errorf("summarizing previous chunk: %w", err)
+		}
+		c.summary = summary
+	}
+	c.pending = chunk
+
+	return c.summary, nil
+}
+
+// extractChunk extracts facts from one chunk and stamps them with IDs and
+// source information. priorContext summarizes the text before the chunk.
+// With opts.Dialogue, each speaker's lines are extracted apart from the
+// narration, as asserted by them.
+func (s *ExtractionService) extractChunk(ctx context.Context, chunk string, priorContext string, sourceFile string, page int, validTypes []string, opts *ExtractionOptions) ([]entities.Fact, error) {
+	text, speeches := chunk, []Speech(nil)
+	if opts.Dialogue {
+		text, speeches = SplitDialogue(chunk)
+	}
+
+	var facts []entities.Fact
+	if text != "" || len(speeches) == 0 {
+		narrationContext := priorContext
+		if opts.Narrator != "" {
+			narrationContext = speakerContext(priorContext, "narrated by", opts.Narrator)
+		}
+		extracted, err := s.extractText(ctx, text, narrationContext, validTypes, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Narrator != "" {
+			attribute(extracted, opts.Narrator, !isReliable(opts.Narrator, opts.Reliable))
+		}
+		facts = extracted
+	}
+
+	for _, speech := range speeches {
+		said, err := s.llm.ExtractFactsWithContext(ctx, speech.Text, speakerContext(priorContext, "dialogue spoken by", speech.Speaker), validTypes)
+		if err != nil {
+			return nil, fmt.Errorf("extracting the lines of %s: %w", speech.Speaker, err)
+		}
+		attribute(said, speech.Speaker, !isReliable(speech.Speaker, opts.Reliable))
+		facts = append(facts, said...)
+	}
+
+	for i := range facts {
+		facts[i].ID = uuid.New().String()
+		facts[i].SourceFile = sourceFile
+		facts[i].SourcePage = page
+		facts[i].CreatedAt = time.Now()
+		facts[i].UpdatedAt = time.Now()
+	}
+	opts.Sources.Apply(facts)
+
+	return facts, nil
+}
+
+// extractText extracts facts from text with the focus passes and pronoun
+// resolution opts asks for.
+func (s *ExtractionService) extractText(ctx context.Context, text string, priorContext string, validTypes []string, opts *ExtractionOptions) ([]entities.Fact, error) {
+	facts, err := s.llm.ExtractFactsWithContext(ctx, text, priorContext, validTypes)
+	if err != nil {
+		return nil, fmt.Errorf("extracting facts: %w", err)
+	}
+
+	for _, focus := range opts.Focus {
+		focused, err := s.llm.ExtractFocused(ctx, text, priorContext, focus, validTypes)
+		if err != nil {
+			return nil, fmt.Errorf("extracting %s facts: %w", focus, err)
+		}
+		facts = mergeExtracted(facts, focused)
+	}
+
+	if opts.ResolvePronouns {
+		if err := s.resolveCoreferences(ctx, text, facts); err != nil {
+			return nil, err
+		}
+	}
+
+	return facts, nil
 }
 
 // ExtractAndStore extracts facts from text, generates embeddings, and stores them.
 func (s *ExtractionService) ExtractAndStore(ctx context.Context, text string, sourceFile string) ([]entities.Fact, error) {
-	result, err := s.ExtractAndStoreWithOptions(ctx, text, sourceFile, ExtractionOptions{})
+	result, err := s.ExtractAndStoreWithOptions(ctx, text, sourceFile, &ExtractionOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -89,56 +277,29 @@ func (s *ExtractionService) ExtractAndStore(ctx context.Context, text string, so
 }
 
 // ExtractAndStoreWithOptions extracts facts with consistency checking options.
-func (s *ExtractionService) ExtractAndStoreWithOptions(ctx context.Context, text string, sourceFile string, opts ExtractionOptions) (*ExtractionResult, error) {
+func (s *ExtractionService) ExtractAndStoreWithOptions(ctx context.Context, text string, sourceFile string, opts *ExtractionOptions) (*ExtractionResult, error) {
 	// Get valid types for LLM prompt
 	validTypes, err := s.entityTypeService.GetValidTypes(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("getting valid types: %w", err)
 	}
 
-	allFacts, err := s.extractFromChunks(ctx, text, sourceFile, validTypes)
+	allFacts, quarantined, err := s.extractFromChunks(ctx, text, sourceFile, validTypes, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	words := len(strings.Fields(text))
 	if len(allFacts) == 0 {
-		return &ExtractionResult{}, nil
-	}
-
-	texts := make([]string, len(allFacts))
-	for i := range allFacts {
-		texts[i] = factToText(&allFacts[i])
+		return &ExtractionResult{Words: words, Quarantined: quarantined}, nil
 	}
 
-	embeddings, err := s.embedder.EmbedBatch(ctx, texts)
+	result, err := s.finalizeFacts(ctx, allFacts, opts)
 	if err != nil {
-		return nil, fmt.Errorf("generating embeddings: %w", err)
-	}
-
-	for i := range allFacts {
-		allFacts[i].Embedding = embeddings[i]
-	}
-
-	result := &ExtractionResult{
-		Facts: allFacts,
-	}
-
-	// Check consistency if requested
-	if opts.CheckConsistency {
-		issues, err := s.checkConsistency(ctx, allFacts)
-		if err != nil {
-			return nil, fmt.Errorf("checking consistency: %w", err)
-		}
-		result.Issues = issues
-	}
-
-	// Save facts unless check-only mode
-	if !opts.CheckOnly {
-		if err := s.vectorDB.SaveBatch(ctx, allFacts); err != nil {
-			return nil, fmt.Errorf("saving facts: %w", err)
-		}
+		return nil, err
 	}
-
+	result.Words = words
+	result.Quarantined = quarantined
 	return result, nil
 }
 
@@ -148,14 +309,14 @@ type streamChunker struct {
 	currentChunk  strings.Builder
 	lastParagraph strings.Builder
 	inParagraph   bool
+	lines         int // Lines processed
+	limit         int // Longest line or paragraph held, in bytes
 }
 
-// newStreamChunker creates a chunker for the given reader.
-func newStreamChunker(r io.Reader) *streamChunker {
-	scanner := bufio.NewScanner(r)
-	// Allow up to 1MB lines
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
-	return &streamChunker{scanner: scanner}
+// newStreamChunker creates a chunker for the given reader that holds
+// lines and paragraphs of up to limit bytes.
+func newStreamChunker(r io.Reader, limit int) *streamChunker {
+	return &streamChunker{scanner: newLineScanner(r, limit), limit: limit}
 }
 
 // addParagraphToChunk adds a completed paragraph to the current chunk.
@@ -193,6 +354,7 @@ func (c *streamChunker) addParagraphToChunk(para string, processChunk func(strin
 
 // processLine handles a single line, accumulating paragraphs.
 func (c *streamChunker) processLine(line string, processChunk func(string) error) error {
+	c.lines++
 	if strings.TrimSpace(line) == "" {
 		// Empty line marks paragraph boundary
 		if c.inParagraph && c.lastParagraph.Len() > 0 {
@@ -207,6 +369,9 @@ func (c *streamChunker) processLine(line string, processChunk func(string) error
 	}
 
 	// Non-empty line: add to current paragraph
+	if c.lastParagraph.Len()+len(line)+1 > c.limit {
+		return errTooLong("paragraph", c.lines, c.limit)
+	}
 	if c.inParagraph {
 		c.lastParagraph.WriteString("\n")
 	}
@@ -234,70 +399,215 @@ func (c *streamChunker) flush(processChunk func(string) error) error {
 
 // ExtractFromReader extracts facts by streaming from an io.Reader.
 // This reduces memory from O(file_size) to O(chunk_size) for large files.
-func (s *ExtractionService) ExtractFromReader(ctx context.Context, r io.Reader, sourceFile string, opts ExtractionOptions) (*ExtractionResult, error) {
-	// Get valid types for LLM prompt
-	validTypes, err := s.entityTypeService.GetValidTypes(ctx)
+func (s *ExtractionService) ExtractFromReader(ctx context.Context, r io.Reader, sourceFile string, opts *ExtractionOptions) (*ExtractionResult, error) {
+	extracted, err := s.Extract(ctx, r, sourceFile, opts)
 	if err != nil {
-		return nil, fmt.Errorf("getting valid types: %w", err)
+		return nil, err
 	}
+	return s.finalizeExtracted(ctx, extracted, opts)
+}
 
-	chunker := newStreamChunker(r)
-	var allFacts []entities.Fact
+// ExtractFromPages extracts facts from the pages of a document, as
+// ExtractFromReader does from text, recording the page each was found on.
+func (s *ExtractionService) ExtractFromPages(ctx context.Context, pages []ports.DocumentPage, sourceFile string, opts *ExtractionOptions) (*ExtractionResult, error) {
+	extracted, err := s.ExtractPages(ctx, pages, sourceFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.finalizeExtracted(ctx, extracted, opts)
+}
 
-	// processChunk is called per chunk - LLM calls in loop are intentional
-	// because LLMs have token limits and each chunk must be processed separately.
-	processChunk := func(chunkText string) error {
-		facts, err := s.llm.ExtractFacts(ctx, chunkText, validTypes)
-		if err != nil {
-			return fmt.Errorf("extracting facts: %w", err)
-		}
+// finalizeExtracted embeds, checks, and saves the facts extracted from a
+// source, keeping its word and quarantine counts.
+func (s *ExtractionService) finalizeExtracted(ctx context.Context, extracted *ExtractionResult, opts *ExtractionOptions) (*ExtractionResult, error) {
+	if len(extracted.Facts) == 0 {
+		return extracted, nil
+	}
 
-		for i := range facts {
-			facts[i].ID = uuid.New().String()
-			facts[i].SourceFile = sourceFile
-			facts[i].CreatedAt = time.Now()
-			facts[i].UpdatedAt = time.Now()
-		}
+	result, err := s.finalizeFacts(ctx, extracted.Facts, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.Words = extracted.Words
+	result.Quarantined = extracted.Quarantined
+	return result, nil
+}
 
-		allFacts = append(allFacts, facts...)
-		return nil
+// Extract extracts facts by streaming from an io.Reader, as
+// ExtractFromReader does, but neither embeds, checks, nor saves them.
+func (s *ExtractionService) Extract(ctx context.Context, r io.Reader, sourceFile string, opts *ExtractionOptions) (*ExtractionResult, error) {
+	e, err := s.newChunkExtractor(ctx, sourceFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.read(ctx, r, 0); err != nil {
+		return nil, err
 	}
+	return e.result(), nil
+}
 
-	for chunker.scanner.Scan() {
-		if err := chunker.processLine(chunker.scanner.Text(), processChunk); err != nil {
+// ExtractPages extracts facts from the pages of a document, as Extract
+// does from text. Each page is chunked on its own, so no chunk spans two
+// pages and every fact records the page it was found on.
+func (s *ExtractionService) ExtractPages(ctx context.Context, pages []ports.DocumentPage, sourceFile string, opts *ExtractionOptions) (*ExtractionResult, error) {
+	e, err := s.newChunkExtractor(ctx, sourceFile, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		if err := e.read(ctx, strings.NewReader(page.Text), page.Number); err != nil {
 			return nil, err
 		}
 	}
+	return e.result(), nil
+}
+
+// chunkExtractor extracts the facts of the chunks of one source in order,
+// carrying context from each chunk to the next and quarantining those
+// that fail.
+type chunkExtractor struct {
+	service     *ExtractionService
+	sourceFile  string
+	validTypes  []string
+	opts        *ExtractionOptions
+	chunker     ports.Chunker
+	carrier     *contextCarrier
+	counter     wordCounter
+	index       int
+	quarantined int
+	facts       []entities.Fact
+}
+
+// newChunkExtractor returns an extractor of the chunks of sourceFile.
+func (s *ExtractionService) newChunkExtractor(ctx context.Context, sourceFile string, opts *ExtractionOptions) (*chunkExtractor, error) {
+	// Get valid types for LLM prompt
+	validTypes, err := s.entityTypeService.GetValidTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting valid types: %w", err)
+	}
 
-	if err := chunker.scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading input: %w", err)
+	chunker := opts.Chunker
+	if chunker == nil {
+		chunker = paragraphChunker{}
 	}
+	return &chunkExtractor{
+		service:    s,
+		sourceFile: sourceFile,
+		validTypes: validTypes,
+		opts:       opts,
+		chunker:    chunker,
+		carrier:    &contextCarrier{llm: s.llm, enabled: opts.CarryContext},
+	}, nil
+}
 
-	if err := chunker.flush(processChunk); err != nil {
-		return nil, err
+// read chunks the text of r, found on page (0 = unpaged), and extracts
+// the facts of each chunk. LLM calls per chunk are intentional: LLMs have
+// token limits, so each chunk must be processed separately.
+func (e *chunkExtractor) read(ctx context.Context, r io.Reader, page int) error {
+	e.counter.inWord = false // A word does not run on from the page before
+	return e.chunker.Chunk(io.TeeReader(r, &e.counter), func(chunk string) error {
+		return e.extract(ctx, chunk, page)
+	})
+}
+
+// extract extracts the facts of the next chunk, found on page.
+func (e *chunkExtractor) extract(ctx context.Context, chunk string, page int) error {
+	priorContext, err := e.carrier.next(ctx, chunk)
+	if err != nil {
+		return err
 	}
 
-	if len(allFacts) == 0 {
-		return &ExtractionResult{}, nil
+	facts, err := e.service.extractChunk(ctx, chunk, priorContext, e.sourceFile, page, e.validTypes, e.opts)
+	if err != nil {
+		failed := entities.FailedChunk{Source: e.sourceFile, Index: e.index, Page: page, Text: chunk, PriorContext: priorContext}
+		if err := quarantineChunk(ctx, e.opts, &failed, err); err != nil {
+			return err
+		}
+		e.index++
+		e.quarantined++
+		return nil
+	}
+	if e.opts.OnChunk != nil {
+		e.opts.OnChunk(ChunkProgress{Index: e.index, Facts: facts})
 	}
+	e.index++
+	e.facts = append(e.facts, facts...)
+	return nil
+}
 
-	return s.finalizeFacts(ctx, allFacts, opts)
+// result returns what has been extracted so far.
+func (e *chunkExtractor) result() *ExtractionResult {
+	return &ExtractionResult{Facts: e.facts, Words: e.counter.words, Quarantined: e.quarantined}
 }
 
-// finalizeFacts generates embeddings, checks consistency, and saves facts.
-func (s *ExtractionService) finalizeFacts(ctx context.Context, facts []entities.Fact, opts ExtractionOptions) (*ExtractionResult, error) {
-	texts := make([]string, len(facts))
-	for i := range facts {
-		texts[i] = factToText(&facts[i])
+// RetryChunk extracts the facts of a quarantined chunk again, with the
+// summary of the text before it if one was carried, and stores them like
+// those of any other chunk.
+func (s *ExtractionService) RetryChunk(ctx context.Context, chunk *entities.FailedChunk, opts *ExtractionOptions) (*ExtractionResult, error) {
+	validTypes, err := s.entityTypeService.GetValidTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting valid types: %w", err)
 	}
 
-	embeddings, err := s.embedder.EmbedBatch(ctx, texts)
+	facts, err := s.extractChunk(ctx, chunk.Text, chunk.PriorContext, chunk.Source, chunk.Page, validTypes, opts)
 	if err != nil {
-		return nil, fmt.Errorf("generating embeddings: %w", err)
+		return nil, err
 	}
+	if len(facts) == 0 {
+		return &ExtractionResult{}, nil
+	}
+	return s.finalizeFacts(ctx, facts, opts)
+}
 
+// mergeExtracted adds facts from a focused pass to those already extracted.
+// A fact with the same subject, predicate, and object as an existing one
+// replaces it only if it has higher confidence.
+func mergeExtracted(facts, extra []entities.Fact) []entities.Fact {
+	index := make(map[string]int, len(facts))
 	for i := range facts {
-		facts[i].Embedding = embeddings[i]
+		index[tripleKey(&facts[i])] = i
+	}
+
+	for i := range extra {
+		key := tripleKey(&extra[i])
+		if j, ok := index[key]; ok {
+			if extra[i].Confidence > facts[j].Confidence {
+				facts[j] = extra[i]
+			}
+			continue
+		}
+		index[key] = len(facts)
+		facts = append(facts, extra[i])
+	}
+	return facts
+}
+
+// tripleKey identifies a fact by its normalized subject, predicate, and object.
+func tripleKey(fact *entities.Fact) string {
+	return entities.NormalizeName(fact.Subject) + "\x00" +
+		entities.NormalizeName(fact.Predicate) + "\x00" +
+		entities.NormalizeName(fact.Object)
+}
+
+// finalizeFacts resolves subjects, generates embeddings, holds low-confidence
+// facts for review, checks consistency, and saves facts.
+func (s *ExtractionService) finalizeFacts(ctx context.Context, facts []entities.Fact, opts *ExtractionOptions) (*ExtractionResult, error) {
+	if opts.Disambiguate != nil {
+		if err := opts.Disambiguate(ctx, facts); err != nil {
+			return nil, fmt.Errorf("disambiguating subjects: %w", err)
+		}
+	}
+
+	if err := embedFacts(ctx, s.embedder, facts); err != nil {
+		return nil, err
+	}
+
+	detectObjectTypes(facts)
+	holdForReview(facts, opts.ReviewThreshold)
+
+	corroborated, err := corroborate(ctx, s.vectorDB, facts)
+	if err != nil {
+		return nil, fmt.Errorf("counting corroborating sources: %w", err)
 	}
 
 	result := &ExtractionResult{
@@ -305,7 +615,7 @@ func (s *ExtractionService) finalizeFacts(ctx context.Context, facts []entities.
 	}
 
 	if opts.CheckConsistency {
-		issues, err := s.checkConsistency(ctx, facts)
+		issues, err := s.checkConsistency(ctx, facts, opts.Retrieval)
 		if err != nil {
 			return nil, fmt.Errorf("checking consistency: %w", err)
 		}
@@ -316,44 +626,74 @@ func (s *ExtractionService) finalizeFacts(ctx context.Context, facts []entities.
 		if err := s.vectorDB.SaveBatch(ctx, facts); err != nil {
 			return nil, fmt.Errorf("saving facts: %w", err)
 		}
+		if len(corroborated) > 0 {
+			if err := s.vectorDB.SaveBatch(ctx, corroborated); err != nil {
+				return nil, fmt.Errorf("saving corroborated facts: %w", err)
+			}
+		}
 	}
 
 	return result, nil
 }
 
+// holdForReview marks facts below the confidence threshold as pending review.
+func holdForReview(facts []entities.Fact, threshold float64) {
+	for i := range facts {
+		if facts[i].Confidence < threshold {
+			facts[i].Status = entities.FactStatusPending
+		}
+	}
+}
+
 // checkConsistency checks new facts against existing facts for contradictions.
-// Uses batched LLM call for efficiency - collects all similar facts first,
-// then makes a single LLM call instead of one per fact.
-func (s *ExtractionService) checkConsistency(ctx context.Context, newFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
-	// Step 1: Collect all similar facts from DB (fast calls)
-	var allSimilarFacts []entities.Fact
-	seenIDs := make(map[string]bool)
+func (s *ExtractionService) checkConsistency(ctx context.Context, newFacts []entities.Fact, retrieval Retrieval) ([]ports.ConsistencyIssue, error) {
+	return findConsistencyIssues(ctx, s.llm, s.vectorDB, newFacts, retrieval)
+}
 
+// findConsistencyIssues checks facts against the stored facts retrieval
+// finds for them. Typed objects with the same subject and predicate are
+// compared directly; everything else goes to a single batched LLM call
+// instead of one per fact. A stored fact is never reported as
+// contradicting itself, and a pair is reported once.
+func findConsistencyIssues(ctx context.Context, llm ports.LLMClient, vectorDB ports.VectorDB, newFacts []entities.Fact, retrieval Retrieval) ([]ports.ConsistencyIssue, error) {
+	// A claim may contradict canon without being an error, so canon is
+	// checked against canon only. A claim is checked against earlier claims
+	// of whoever asserts it, where a contradiction is a slip in the telling.
+	// Drafts are speculation, so they are neither checked nor checked
+	// against.
+	var canon, claims []entities.Fact
 	for i := range newFacts {
-		similarFacts, err := s.vectorDB.SearchByType(ctx, newFacts[i].Embedding, newFacts[i].Type, 5)
-		if err != nil {
-			return nil, fmt.Errorf("searching similar facts: %w", err)
-		}
-
-		// Deduplicate similar facts
-		for j := range similarFacts {
-			if !seenIDs[similarFacts[j].ID] {
-				seenIDs[similarFacts[j].ID] = true
-				allSimilarFacts = append(allSimilarFacts, similarFacts[j])
-			}
+		switch {
+		case newFacts[i].IsDraft():
+		case newFacts[i].IsClaim():
+			claims = append(claims, newFacts[i])
+		default:
+			canon = append(canon, newFacts[i])
 		}
 	}
 
-	if len(allSimilarFacts) == 0 {
-		return nil, nil
+	issues, err := findIssuesAmong(ctx, llm, vectorDB, canon, retrieval, func(f *entities.Fact) bool {
+		return !f.IsClaim() && !f.IsDraft()
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Step 2: Single batched LLM call for all facts
-	issues, err := s.llm.CheckConsistency(ctx, newFacts, allSimilarFacts)
+	asserters := make(map[string]bool)
+	for i := range claims {
+		asserters[claims[i].AssertedBy] = true
+	}
+	claimIssues, err := findIssuesAmong(ctx, llm, vectorDB, claims, retrieval, func(f *entities.Fact) bool {
+		return f.IsClaim() && !f.IsDraft() && asserters[f.AssertedBy]
+	})
 	if err != nil {
-		return nil, fmt.Errorf("LLM consistency check: %w", err)
+		return nil, err
+	}
+	for i := range claimIssues {
+		if claimIssues[i].NewFact.AssertedBy == claimIssues[i].ExistingFact.AssertedBy {
+			issues = append(issues, claimIssues[i])
+		}
 	}
-
 	return issues, nil
 }
 
@@ -405,16 +745,3 @@ func getOverlapText(text string, n int) string {
 	}
 	return text[len(text)-n:]
 }
-
-// factToText converts a fact to searchable text for embedding.
-func factToText(fact *entities.Fact) string {
-	parts := []string{
-		fact.Subject,
-		fact.Predicate,
-		fact.Object,
-	}
-	if fact.Context != "" {
-		parts = append(parts, fact.Context)
-	}
-	return strings.Join(parts, " ")
-}
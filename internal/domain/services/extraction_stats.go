@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// extractionStatStore stores extraction statistics.
+type extractionStatStore interface {
+	SaveExtractionStat(ctx context.Context, stat *entities.ExtractionStat) error
+}
+
+// ExtractionStatsLLM is a ports.LLMClient that records how each extraction
+// call to its model went: how many facts it extracted and with what
+// confidence, or that the model's response could not be parsed. Calls that
+// fail for other reasons, such as timeouts, say nothing about the model
+// and aren't recorded. Focused passes and its other calls go to the
+// wrapped client unrecorded.
+type ExtractionStatsLLM struct {
+	ports.LLMClient
+	store extractionStatStore
+	model string
+	now   func() time.Time
+}
+
+// NewExtractionStatsLLM wraps llm, which calls model, so its extraction
+// calls are recorded in store.
+func NewExtractionStatsLLM(llm ports.LLMClient, store extractionStatStore, model string) *ExtractionStatsLLM {
+	return &ExtractionStatsLLM{LLMClient: llm, store: store, model: model, now: time.Now}
+}
+
+// ExtractFacts extracts facts from text and records how the call went.
+func (l *ExtractionStatsLLM) ExtractFacts(ctx context.Context, text string, validTypes []string) ([]entities.Fact, error) {
+	facts, err := l.LLMClient.ExtractFacts(ctx, text, validTypes)
+	l.record(ctx, facts, err)
+	return facts, err
+}
+
+// ExtractFactsWithContext extracts facts from text and records how the call
+// went.
+func (l *ExtractionStatsLLM) ExtractFactsWithContext(ctx context.Context, text string, priorContext string, validTypes []string) ([]entities.Fact, error) {
+	facts, err := l.LLMClient.ExtractFactsWithContext(ctx, text, priorContext, validTypes)
+	l.record(ctx, facts, err)
+	return facts, err
+}
+
+// record stores how an extraction call went. Statistics are best effort:
+// failing to store them doesn't fail the extraction.
+func (l *ExtractionStatsLLM) record(ctx context.Context, facts []entities.Fact, err error) {
+	stat := &entities.ExtractionStat{RecordedAt: l.now(), Model: l.model}
+	switch {
+	case errors.Is(err, ports.ErrMalformedResponse):
+		stat.ParseFailed = true
+	case err != nil:
+		return
+	default:
+		stat.Facts = len(facts)
+		for i := range facts {
+			stat.ConfidenceSum += facts[i].Confidence
+		}
+	}
+	_ = l.store.SaveExtractionStat(context.WithoutCancel(ctx), stat)
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestExtractionStatsLLM(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	llm := &mocks.LLMClient{Facts: []entities.Fact{{Confidence: 0.9}, {Confidence: 0.6}}}
+	store := mocks.NewRelationalDB()
+	stats := NewExtractionStatsLLM(llm, store, "gpt-4o-mini")
+	stats.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	facts, err := stats.ExtractFactsWithContext(ctx, "text", "", nil)
+	require.NoError(t, err)
+	assert.Len(t, facts, 2)
+
+	llm.ExtractErr = fmt.Errorf("parsing facts JSON: %w: unexpected end of input", ports.ErrMalformedResponse)
+	_, err = stats.ExtractFacts(ctx, "text", nil)
+	require.ErrorIs(t, err, ports.ErrMalformedResponse)
+
+	llm.ExtractErr = errors.New("connection reset")
+	_, err = stats.ExtractFacts(ctx, "text", nil)
+	require.Error(t, err)
+
+	require.Len(t, store.Extractions, 2, "failures other than parsing say nothing about the model")
+	assert.Equal(t, entities.ExtractionStat{ID: 1, RecordedAt: now, Model: "gpt-4o-mini", Facts: 2, ConfidenceSum: 1.5}, store.Extractions[0])
+	assert.True(t, store.Extractions[1].ParseFailed)
+	assert.Zero(t, store.Extractions[1].Facts)
+}
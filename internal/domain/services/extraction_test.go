@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -8,6 +10,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
 func TestChunkText(t *testing.T) {
@@ -208,7 +212,7 @@ func TestFactToText(t *testing.T) {
 func TestStreamChunker_BasicChunking(t *testing.T) {
 	input := "First paragraph.\n\nSecond paragraph.\n\nThird paragraph."
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -238,7 +242,7 @@ func TestStreamChunker_ChunkOverflow(t *testing.T) {
 	input := para1 + "\n\n" + para2 + "\n\n" + para3
 
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -270,7 +274,7 @@ func TestStreamChunker_ParagraphNotLost(t *testing.T) {
 	input := para1 + "\n\n" + para2
 
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -298,7 +302,7 @@ func TestStreamChunker_ParagraphNotLost(t *testing.T) {
 
 func TestStreamChunker_EmptyInput(t *testing.T) {
 	reader := strings.NewReader("")
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -320,7 +324,7 @@ func TestStreamChunker_EmptyInput(t *testing.T) {
 func TestStreamChunker_SingleLine(t *testing.T) {
 	input := "This is a single line without any paragraph breaks."
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -343,7 +347,7 @@ func TestStreamChunker_NoParagraphBreaks(t *testing.T) {
 	// Multiple lines but no double newlines
 	input := "Line one.\nLine two.\nLine three.\nLine four."
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -371,7 +375,7 @@ func TestStreamChunker_OverlapIncluded(t *testing.T) {
 	input := para1 + "\n\n" + para2
 
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -399,7 +403,7 @@ func TestStreamChunker_OverlapIncluded(t *testing.T) {
 func TestStreamChunker_ConsecutiveEmptyLines(t *testing.T) {
 	input := "First paragraph.\n\n\n\n\nSecond paragraph."
 	reader := strings.NewReader(input)
-	chunker := newStreamChunker(reader)
+	chunker := newStreamChunker(reader, int(DefaultMaxChunkBytes))
 
 	var chunks []string
 	processChunk := func(chunk string) error {
@@ -418,3 +422,346 @@ func TestStreamChunker_ConsecutiveEmptyLines(t *testing.T) {
 	assert.Contains(t, chunks[0], "First paragraph")
 	assert.Contains(t, chunks[0], "Second paragraph")
 }
+
+func TestExtractionService_CarryContext(t *testing.T) {
+	// Three paragraphs that each fill most of a chunk
+	paragraphs := []string{
+		"Strider joined the hobbits. " + strings.Repeat("a", 1500),
+		"He led them to Weathertop. " + strings.Repeat("b", 1500),
+		"He revealed his name was Aragorn. " + strings.Repeat("c", 1500),
+	}
+	text := strings.Join(paragraphs, "\n\n")
+
+	summarize := func(summary, text string) string {
+		return summary + text[:strings.Index(text, ".")+1]
+	}
+
+	tests := []struct {
+		name    string
+		extract func(*ExtractionService, ExtractionOptions) error
+	}{
+		{"stream", func(svc *ExtractionService, opts ExtractionOptions) error {
+			_, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &opts)
+			return err
+		}},
+		{"string", func(svc *ExtractionService, opts ExtractionOptions) error {
+			_, err := svc.ExtractAndStoreWithOptions(context.Background(), text, "book.txt", &opts)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mocks.LLMClient{Summarize: summarize}
+			svc, _ := newMockExtractionService(llm)
+
+			require.NoError(t, tt.extract(svc, ExtractionOptions{CarryContext: true}))
+
+			contexts := llm.ExtractFactsPriorContexts
+			require.Len(t, contexts, 3)
+			assert.Empty(t, contexts[0])
+			assert.True(t, strings.HasPrefix(contexts[1], "Strider joined the hobbits."))
+			assert.Contains(t, contexts[2], "He led them to Weathertop.")
+			assert.Equal(t, 2, llm.SummarizeCallCount, "last chunk should not be summarized")
+		})
+	}
+
+	t.Run("off by default", func(t *testing.T) {
+		llm := &mocks.LLMClient{}
+		svc, _ := newMockExtractionService(llm)
+
+		_, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &ExtractionOptions{})
+		require.NoError(t, err)
+		assert.Zero(t, llm.SummarizeCallCount)
+		assert.Equal(t, []string{"", "", ""}, llm.ExtractFactsPriorContexts)
+	})
+
+	t.Run("summary error", func(t *testing.T) {
+		llm := &mocks.LLMClient{SummarizeErr: errors.New("timeout")}
+		svc, _ := newMockExtractionService(llm)
+
+		_, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &ExtractionOptions{CarryContext: true})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "summarizing previous chunk")
+	})
+}
+
+func TestMergeExtracted(t *testing.T) {
+	facts := []entities.Fact{
+		{Subject: "Frodo", Predicate: "lives_in", Object: "the Shire", Confidence: 0.9},
+		{Subject: "Sam", Predicate: "serves", Object: "Frodo", Confidence: 0.5},
+	}
+	extra := []entities.Fact{
+		{Subject: "frodo", Predicate: "Lives_In", Object: "The Shire", Confidence: 0.7}, // Lower confidence duplicate
+		{Subject: "Sam", Predicate: "serves", Object: "Frodo", Confidence: 0.8},         // Higher confidence duplicate
+		{Subject: "Frodo", Predicate: "left", Object: "the Shire", Confidence: 0.9},
+	}
+
+	merged := mergeExtracted(facts, extra)
+	require.Len(t, merged, 3)
+	assert.Equal(t, "Frodo", merged[0].Subject)
+	assert.InDelta(t, 0.9, merged[0].Confidence, 1e-9)
+	assert.InDelta(t, 0.8, merged[1].Confidence, 1e-9)
+	assert.Equal(t, "left", merged[2].Predicate)
+}
+
+func TestExtractionService_Focus(t *testing.T) {
+	general := entities.Fact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a hobbit"}
+	event := entities.Fact{Type: entities.FactTypeEvent, Subject: "Frodo", Predicate: "left", Object: "the Shire"}
+	rule := entities.Fact{Type: entities.FactTypeRule, Subject: "The Ring", Predicate: "corrupts", Object: "its bearer"}
+
+	llm := &mocks.LLMClient{
+		Facts: []entities.Fact{general},
+		FocusedFacts: map[ports.ExtractionFocus][]entities.Fact{
+			ports.FocusEvents: {event, general},
+			ports.FocusRules:  {rule},
+		},
+	}
+	svc, vectorDB := newMockExtractionService(llm)
+
+	opts := ExtractionOptions{Focus: []ports.ExtractionFocus{ports.FocusEvents, ports.FocusRules}}
+	result, err := svc.ExtractFromReader(context.Background(), strings.NewReader("Frodo left the Shire."), "book.txt", &opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ports.ExtractionFocus{ports.FocusEvents, ports.FocusRules}, llm.ExtractFocusedCalls)
+	require.Len(t, result.Facts, 3)
+	assert.Equal(t, []string{"is", "left", "corrupts"}, []string{
+		result.Facts[0].Predicate, result.Facts[1].Predicate, result.Facts[2].Predicate,
+	})
+	for _, fact := range result.Facts {
+		assert.NotEmpty(t, fact.ID)
+		assert.Equal(t, "book.txt", fact.SourceFile)
+	}
+	assert.Len(t, vectorDB.SaveBatchLastFacts, 3)
+}
+
+func TestExtractionService_ReviewThreshold(t *testing.T) {
+	llm := &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a hobbit", Confidence: 0.95},
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "likes", Object: "mushrooms", Confidence: 0.4},
+	}}
+
+	tests := []struct {
+		name      string
+		threshold float64
+		want      []entities.FactStatus
+	}{
+		{"off", 0, []entities.FactStatus{"", ""}},
+		{"holds low confidence", 0.7, []entities.FactStatus{"", entities.FactStatusPending}},
+		{"holds everything", 1, []entities.FactStatus{entities.FactStatusPending, entities.FactStatusPending}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, vectorDB := newMockExtractionService(llm)
+
+			opts := ExtractionOptions{ReviewThreshold: tt.threshold}
+			_, err := svc.ExtractFromReader(context.Background(), strings.NewReader("Frodo is a hobbit."), "book.txt", &opts)
+			require.NoError(t, err)
+
+			require.Len(t, vectorDB.SaveBatchLastFacts, 2)
+			assert.Equal(t, tt.want, []entities.FactStatus{
+				vectorDB.SaveBatchLastFacts[0].Status, vectorDB.SaveBatchLastFacts[1].Status,
+			})
+		})
+	}
+}
+
+func TestExtractionService_OnChunk(t *testing.T) {
+	text := strings.Join([]string{
+		"Frodo left the Shire. " + strings.Repeat("a", 1500),
+		"Sam followed him. " + strings.Repeat("b", 1500),
+	}, "\n\n")
+
+	tests := []struct {
+		name    string
+		extract func(*ExtractionService, ExtractionOptions) error
+	}{
+		{"stream", func(svc *ExtractionService, opts ExtractionOptions) error {
+			_, err := svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &opts)
+			return err
+		}},
+		{"string", func(svc *ExtractionService, opts ExtractionOptions) error {
+			_, err := svc.ExtractAndStoreWithOptions(context.Background(), text, "book.txt", &opts)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mocks.LLMClient{Facts: []entities.Fact{
+				{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "left", Object: "the Shire"},
+			}}
+			svc, vectorDB := newMockExtractionService(llm)
+
+			var progress []ChunkProgress
+			opts := ExtractionOptions{OnChunk: func(p ChunkProgress) {
+				assert.Empty(t, vectorDB.SaveBatchLastFacts, "chunks are reported before facts are saved")
+				progress = append(progress, p)
+			}}
+			require.NoError(t, tt.extract(svc, opts))
+
+			require.Len(t, progress, 2)
+			for i, p := range progress {
+				assert.Equal(t, i, p.Index)
+				require.Len(t, p.Facts, 1)
+				assert.Equal(t, "book.txt", p.Facts[0].SourceFile)
+			}
+		})
+	}
+}
+
+func TestExtractionService_Corroboration(t *testing.T) {
+	tests := []struct {
+		name      string
+		checkOnly bool
+		wantSaves int
+	}{
+		{"saves raised counts", false, 2},
+		{"check only saves nothing", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &mocks.LLMClient{Facts: []entities.Fact{
+				{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"},
+			}}
+			svc, vectorDB := newMockExtractionService(llm)
+			vectorDB.Facts = []entities.Fact{{
+				ID: "stored", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End",
+				SourceFile: "ch1.md", TextEmbedding: []float32{0.1, 0.2, 0.3},
+			}}
+
+			opts := ExtractionOptions{CheckOnly: tt.checkOnly}
+			result, err := svc.ExtractFromReader(context.Background(), strings.NewReader("Frodo lives in Bag End."), "ch2.md", &opts)
+			require.NoError(t, err)
+
+			require.Len(t, result.Facts, 1)
+			assert.Equal(t, 2, result.Facts[0].Corroboration)
+			assert.Equal(t, tt.wantSaves, vectorDB.SaveBatchCallCount)
+			if tt.wantSaves > 0 {
+				require.Len(t, vectorDB.SaveBatchLastFacts, 1)
+				assert.Equal(t, "stored", vectorDB.SaveBatchLastFacts[0].ID)
+				assert.Equal(t, 2, vectorDB.SaveBatchLastFacts[0].Corroboration)
+			}
+		})
+	}
+}
+
+// failingChunkLLM fails to extract facts from chunks containing marker.
+type failingChunkLLM struct {
+	*mocks.LLMClient
+	marker string
+}
+
+func (l *failingChunkLLM) ExtractFactsWithContext(ctx context.Context, text, priorContext string, validTypes []string) ([]entities.Fact, error) {
+	if strings.Contains(text, l.marker) {
+		return nil, errors.New("parsing response: unexpected end of JSON input")
+	}
+	return l.LLMClient.ExtractFactsWithContext(ctx, text, priorContext, validTypes)
+}
+
+func TestExtractionService_Quarantine(t *testing.T) {
+	text := strings.Join([]string{
+		"Frodo left the Shire. " + strings.Repeat("a", 1500),
+		"BROKEN " + strings.Repeat("b", 1500),
+		"Sam followed him. " + strings.Repeat("c", 1500),
+	}, "\n\n")
+
+	tests := []struct {
+		name    string
+		extract func(*ExtractionService, ExtractionOptions) (*ExtractionResult, error)
+	}{
+		{"stream", func(svc *ExtractionService, opts ExtractionOptions) (*ExtractionResult, error) {
+			return svc.ExtractFromReader(context.Background(), strings.NewReader(text), "book.txt", &opts)
+		}},
+		{"string", func(svc *ExtractionService, opts ExtractionOptions) (*ExtractionResult, error) {
+			return svc.ExtractAndStoreWithOptions(context.Background(), text, "book.txt", &opts)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := &failingChunkLLM{
+				LLMClient: &mocks.LLMClient{Facts: []entities.Fact{
+					{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "left", Object: "the Shire"},
+				}},
+				marker: "BROKEN",
+			}
+			base, vectorDB := newMockExtractionService(llm.LLMClient)
+			svc := NewExtractionService(llm, base.embedder, vectorDB, base.entityTypeService)
+
+			var quarantined []entities.FailedChunk
+			opts := ExtractionOptions{Quarantine: func(_ context.Context, chunk *entities.FailedChunk) error {
+				quarantined = append(quarantined, *chunk)
+				return nil
+			}}
+			result, err := tt.extract(svc, opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, result.Quarantined)
+			assert.Len(t, result.Facts, 2, "the other chunks are still extracted")
+			require.Len(t, quarantined, 1)
+			assert.Equal(t, "book.txt", quarantined[0].Source)
+			assert.Equal(t, 1, quarantined[0].Index)
+			assert.Contains(t, quarantined[0].Text, "BROKEN")
+			assert.Contains(t, quarantined[0].Error, "unexpected end of JSON input")
+
+			_, err = tt.extract(svc, ExtractionOptions{})
+			assert.Error(t, err, "without a quarantine the failure aborts extraction")
+		})
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		llm := &mocks.LLMClient{Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "follows", Object: "Frodo"},
+		}}
+		svc, vectorDB := newMockExtractionService(llm)
+
+		chunk := entities.FailedChunk{Source: "book.txt", Index: 1, Text: "Sam followed him.", PriorContext: "Frodo left."}
+		result, err := svc.RetryChunk(context.Background(), &chunk, &ExtractionOptions{})
+		require.NoError(t, err)
+		require.Len(t, result.Facts, 1)
+		assert.Equal(t, "book.txt", result.Facts[0].SourceFile)
+		assert.Equal(t, []string{"Frodo left."}, llm.ExtractFactsPriorContexts)
+		assert.Len(t, vectorDB.SaveBatchLastFacts, 1)
+	})
+}
+
+func TestExtractionService_SourcePage(t *testing.T) {
+	pages := []ports.DocumentPage{
+		{Number: 1, Text: "Frodo left the Shire."},
+		{Number: 2, Text: "BROKEN"},
+		{Number: 3, Text: "Sam followed him."},
+	}
+
+	llm := &failingChunkLLM{
+		LLMClient: &mocks.LLMClient{Facts: []entities.Fact{
+			{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "left", Object: "the Shire"},
+		}},
+		marker: "BROKEN",
+	}
+	base, vectorDB := newMockExtractionService(llm.LLMClient)
+	svc := NewExtractionService(llm, base.embedder, vectorDB, base.entityTypeService)
+
+	var quarantined []entities.FailedChunk
+	opts := ExtractionOptions{Quarantine: func(_ context.Context, chunk *entities.FailedChunk) error {
+		quarantined = append(quarantined, *chunk)
+		return nil
+	}}
+	result, err := svc.ExtractFromPages(context.Background(), pages, "book.pdf", &opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.Facts, 2, "each page is a chunk of its own")
+	assert.Equal(t, 1, result.Facts[0].SourcePage)
+	assert.Equal(t, 3, result.Facts[1].SourcePage)
+	assert.Equal(t, 8, result.Words)
+	require.Len(t, quarantined, 1)
+	assert.Equal(t, 2, quarantined[0].Page)
+	assert.Equal(t, "BROKEN", quarantined[0].Text)
+
+	retried, err := base.RetryChunk(context.Background(), &quarantined[0], &ExtractionOptions{})
+	require.NoError(t, err)
+	require.Len(t, retried.Facts, 1)
+	assert.Equal(t, 2, retried.Facts[0].SourcePage, "a retried chunk keeps its page")
+}
@@ -0,0 +1,439 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// ManualSource is the source recorded for facts entered by hand when no
+// other source is given.
+const ManualSource = "manual"
+
+// Version reasons recorded for manual changes without an explicit reason.
+const (
+	manualCreateReason = "added manually"
+	manualUpdateReason = "edited manually"
+	manualDeleteReason = "deleted manually"
+)
+
+// NewFact holds a fact entered by hand.
+type NewFact struct {
+	Type       entities.FactType
+	Subject    string
+	Predicate  string
+	Object     string
+	Context    string
+	SourceFile string  // Empty = ManualSource
+	Confidence float64 // Zero = 1.0, the author knows it to be true
+
+	Draft bool          // Store as a draft, kept out of consistency checks
+	TTL   time.Duration // How long the draft lasts (0 = until promoted or purged)
+}
+
+// FactService creates, updates, and deletes individual facts without the
+// LLM. Every change is embedded, versioned, and recorded in the audit log.
+type FactService struct {
+	embedder          ports.Embedder
+	vectorDB          ports.VectorDB
+	relationalDB      ports.RelationalDB
+	entityTypeService *EntityTypeService
+	now               func() time.Time
+
+	// writeMu serializes read-check-write updates, so two writers can't
+	// both pass a revision check before either saves. It only covers
+	// writers sharing this FactService: the vector store has no
+	// conditional write to check the revision against, so writers in
+	// other processes on the same world can still race.
+	writeMu sync.Mutex
+}
+
+// NewFactService creates a new fact service.
+func NewFactService(
+	embedder ports.Embedder,
+	vectorDB ports.VectorDB,
+	relationalDB ports.RelationalDB,
+	entityTypeService *EntityTypeService,
+) *FactService {
+	return &FactService{
+		embedder:          embedder,
+		vectorDB:          vectorDB,
+		relationalDB:      relationalDB,
+		entityTypeService: entityTypeService,
+		now:               time.Now,
+	}
+}
+
+// Create validates and stores a new fact.
+func (s *FactService) Create(ctx context.Context, input NewFact) (*entities.Fact, error) {
+	fact, err := s.Prepare(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Save(ctx, fact); err != nil {
+		return nil, err
+	}
+	return fact, nil
+}
+
+// Prepare validates and embeds a new fact without storing it, so it can be
+// checked with FindSimilar before Save.
+func (s *FactService) Prepare(ctx context.Context, input NewFact) (*entities.Fact, error) {
+	if err := s.validate(ctx, input); err != nil {
+		return nil, err
+	}
+
+	source := input.SourceFile
+	if source == "" {
+		source = ManualSource
+	}
+	confidence := input.Confidence
+	if confidence == 0 {
+		confidence = 1.0
+	}
+
+	now := s.now()
+	fact := entities.Fact{
+		ID:         uuid.New().String(),
+		Type:       input.Type,
+		Subject:    strings.TrimSpace(input.Subject),
+		Predicate:  strings.TrimSpace(input.Predicate),
+		Object:     strings.TrimSpace(input.Object),
+		Context:    strings.TrimSpace(input.Context),
+		SourceFile: source,
+		Confidence: confidence,
+		Status:     entities.FactStatusActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Draft:      input.Draft,
+	}
+	if input.TTL > 0 {
+		fact.ExpiresAt = now.Add(input.TTL)
+	}
+
+	if err := s.embed(ctx, &fact); err != nil {
+		return nil, err
+	}
+	return &fact, nil
+}
+
+// FindSimilar returns existing facts that are likely duplicates of a
+// prepared fact.
+func (s *FactService) FindSimilar(ctx context.Context, fact *entities.Fact) ([]SimilarFact, error) {
+	return findSimilarFacts(ctx, s.vectorDB, fact, DefaultSimilarityThreshold)
+}
+
+// Save stores a prepared fact as version 1.
+func (s *FactService) Save(ctx context.Context, fact *entities.Fact) error {
+	if err := s.save(ctx, fact); err != nil {
+		return err
+	}
+	if err := saveFactVersion(ctx, s.relationalDB, fact, 1, entities.ChangeCreation, manualCreateReason); err != nil {
+		return err
+	}
+	s.audit(ctx, entities.AuditActionFactCreate, fact)
+	return nil
+}
+
+// Get returns a fact by ID.
+func (s *FactService) Get(ctx context.Context, id string) (*entities.Fact, error) {
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding fact: %w", err)
+	}
+	return &fact, nil
+}
+
+// Update applies an edit to an existing fact and re-embeds it. A non-empty
+// revision is the fact's Revision the edit was based on; if the fact has
+// changed since, nothing is saved and an *entities.StaleFactError is
+// returned. An empty reason records a generic one in the fact's history.
+//
+// The check and the write are atomic only among updates through this
+// FactService. An update from another process, such as a second 'lore
+// serve' or a CLI command on the same world, can land between them and be
+// overwritten.
+func (s *FactService) Update(ctx context.Context, id, revision string, edit FactEdit, reason string) (*entities.Fact, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding fact: %w", err)
+	}
+
+	updated := fact
+	applyEdit(&updated, edit)
+	if err := checkRevision(&fact, &updated, revision); err != nil {
+		return nil, err
+	}
+	if edit.IsEmpty() {
+		return &fact, nil
+	}
+	if err := s.replace(ctx, &fact, &updated, reason); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// SetKnownBy records which entities know a fact, creating entities not yet
+// known in the world. No names makes the fact common knowledge again. An
+// empty reason records a generic one in the fact's history.
+func (s *FactService) SetKnownBy(ctx context.Context, id, worldID string, names []string, reason string) (*entities.Fact, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("finding fact: %w", err)
+	}
+
+	var knownBy []string
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return nil, entities.Errorf(entities.ErrValidation, "entity name is required")
+		}
+		entity, err := s.relationalDB.FindOrCreateEntity(ctx, worldID, name)
+		if err != nil {
+			return nil, fmt.Errorf("finding entity %q: %w", name, err)
+		}
+		if !slices.Contains(knownBy, entity.ID) {
+			knownBy = append(knownBy, entity.ID)
+		}
+	}
+	if slices.Equal(knownBy, fact.KnownBy) {
+		return &fact, nil
+	}
+
+	updated := fact
+	updated.KnownBy = knownBy
+	if err := s.replace(ctx, &fact, &updated, reason); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// checkRevision returns an *entities.StaleFactError if fact is no longer at
+// revision, the one updated was edited from. An empty revision skips the
+// check.
+func checkRevision(fact, updated *entities.Fact, revision string) error {
+	if revision == "" || revision == fact.Revision() {
+		return nil
+	}
+	current, attempted := *fact, *updated
+	current.Embedding, current.TextEmbedding = nil, nil
+	attempted.Embedding, attempted.TextEmbedding = nil, nil
+	return &entities.StaleFactError{Revision: revision, Current: current, Attempted: attempted}
+}
+
+// replace stores updated in place of fact, recording the change in the
+// fact's history.
+func (s *FactService) replace(ctx context.Context, fact, updated *entities.Fact, reason string) error {
+	updated.Embedding, updated.TextEmbedding = nil, nil
+	return s.store(ctx, fact, updated, reason)
+}
+
+// store stores updated in place of fact like replace, but keeps updated's
+// embeddings if it has them, for changes that leave the text alone.
+func (s *FactService) store(ctx context.Context, fact, updated *entities.Fact, reason string) error {
+	next, err := s.nextVersion(ctx, fact)
+	if err != nil {
+		return err
+	}
+
+	updated.UpdatedAt = s.now()
+	if err := s.save(ctx, updated); err != nil {
+		return err
+	}
+
+	if reason == "" {
+		reason = manualUpdateReason
+	}
+	if err := saveFactVersion(ctx, s.relationalDB, updated, next, entities.ChangeUpdate, reason); err != nil {
+		return err
+	}
+	s.audit(ctx, entities.AuditActionFactUpdate, updated)
+	return nil
+}
+
+// replaceBatch stores each of updated in place of the fact at the same
+// index of facts, embedding and saving them in one batch, and records the
+// changes in the facts' histories with the reason at the same index of
+// reasons.
+func (s *FactService) replaceBatch(ctx context.Context, facts, updated []entities.Fact, reasons []string) error {
+	versions := make([]int, len(facts))
+	for i := range facts {
+		next, err := s.nextVersion(ctx, &facts[i])
+		if err != nil {
+			return err
+		}
+		versions[i] = next
+	}
+
+	now := s.now()
+	for i := range updated {
+		updated[i].UpdatedAt = now
+	}
+	if err := embedFacts(ctx, s.embedder, updated); err != nil {
+		return err
+	}
+	if err := s.vectorDB.SaveBatch(ctx, updated); err != nil {
+		return fmt.Errorf("saving facts: %w", err)
+	}
+
+	for i := range updated {
+		if err := saveFactVersion(ctx, s.relationalDB, &updated[i], versions[i], entities.ChangeUpdate, reasons[i]); err != nil {
+			return err
+		}
+		s.audit(ctx, entities.AuditActionFactUpdate, &updated[i])
+	}
+	return nil
+}
+
+// Delete removes a fact, keeping its last state in the fact's history.
+// An empty reason records a generic one.
+func (s *FactService) Delete(ctx context.Context, id string, reason string) error {
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("finding fact: %w", err)
+	}
+	return s.remove(ctx, &fact, reason)
+}
+
+// remove deletes a fact, keeping its last state in the fact's history.
+func (s *FactService) remove(ctx context.Context, fact *entities.Fact, reason string) error {
+	next, err := s.nextVersion(ctx, fact)
+	if err != nil {
+		return err
+	}
+
+	if err := s.vectorDB.Delete(ctx, fact.ID); err != nil {
+		return fmt.Errorf("deleting fact: %w", err)
+	}
+
+	if reason == "" {
+		reason = manualDeleteReason
+	}
+	deleted := *fact
+	deleted.UpdatedAt = s.now()
+	if err := saveFactVersion(ctx, s.relationalDB, &deleted, next, entities.ChangeDeletion, reason); err != nil {
+		return err
+	}
+	s.audit(ctx, entities.AuditActionFactDelete, &deleted)
+
+	return nil
+}
+
+// validate checks a new fact's required fields, type, and confidence.
+func (s *FactService) validate(ctx context.Context, input NewFact) error {
+	for _, field := range []struct{ name, value string }{
+		{"type", string(input.Type)},
+		{"subject", input.Subject},
+		{"predicate", input.Predicate},
+		{"object", input.Object},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			return entities.Errorf(entities.ErrValidation, "%s is required", field.name)
+		}
+	}
+
+	if !s.entityTypeService.IsValid(ctx, string(input.Type)) {
+		validTypes, err := s.entityTypeService.GetValidTypes(ctx)
+		if err != nil {
+			return fmt.Errorf("listing entity types: %w", err)
+		}
+		return entities.Errorf(entities.ErrValidation, "invalid type %q (valid: %s)", input.Type, strings.Join(validTypes, ", "))
+	}
+
+	if input.Confidence < 0 || input.Confidence > 1 {
+		return entities.Errorf(entities.ErrValidation, "confidence must be between 0 and 1, got %v", input.Confidence)
+	}
+	if input.TTL < 0 || (input.TTL > 0 && !input.Draft) {
+		return entities.Errorf(entities.ErrValidation, "a TTL must be positive and is only for drafts")
+	}
+	return nil
+}
+
+// embed fills both embeddings of a fact.
+func (s *FactService) embed(ctx context.Context, fact *entities.Fact) error {
+	facts := []entities.Fact{*fact}
+	if err := embedFacts(ctx, s.embedder, facts); err != nil {
+		return err
+	}
+	*fact = facts[0]
+	return nil
+}
+
+// save stores a fact, embedding it first unless it already is.
+func (s *FactService) save(ctx context.Context, fact *entities.Fact) error {
+	if fact.Embedding == nil {
+		if err := s.embed(ctx, fact); err != nil {
+			return err
+		}
+	}
+
+	if err := s.vectorDB.Save(ctx, fact); err != nil {
+		return fmt.Errorf("saving fact: %w", err)
+	}
+	return nil
+}
+
+// nextVersion returns the version number for the next change to fact.
+// Facts without history, such as those extracted before versioning, first
+// get their current state recorded as version 1.
+func (s *FactService) nextVersion(ctx context.Context, fact *entities.Fact) (int, error) {
+	latest, err := s.relationalDB.FindLatestVersion(ctx, fact.ID)
+	if err != nil {
+		return 0, fmt.Errorf("finding latest version of %s: %w", fact.ID, err)
+	}
+	if latest != nil {
+		return latest.Version + 1, nil
+	}
+
+	if err := saveFactVersion(ctx, s.relationalDB, fact, 1, entities.ChangeCreation, ""); err != nil {
+		return 0, err
+	}
+	return 2, nil
+}
+
+// audit records a manual change. Like the slow-query log it is best
+// effort: the change itself has already been stored and versioned.
+func (s *FactService) audit(ctx context.Context, action string, fact *entities.Fact) {
+	_ = s.relationalDB.LogAction(ctx, action, fact.ID, map[string]any{
+		"type":      string(fact.Type),
+		"subject":   fact.Subject,
+		"predicate": fact.Predicate,
+		"object":    fact.Object,
+		"source":    fact.SourceFile,
+	})
+}
+
+// saveFactVersion stores a snapshot of fact, without its embeddings, as the
+// given version.
+func saveFactVersion(ctx context.Context, db ports.RelationalDB, fact *entities.Fact, version int, change entities.ChangeType, reason string) error {
+	snapshot := *fact
+	snapshot.Embedding = nil
+	snapshot.TextEmbedding = nil
+
+	err := db.SaveVersion(ctx, &entities.FactVersion{
+		ID:         uuid.New().String(),
+		FactID:     fact.ID,
+		Version:    version,
+		ChangeType: change,
+		Data:       snapshot,
+		Reason:     reason,
+		CreatedAt:  fact.UpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("saving version of %s: %w", fact.ID, err)
+	}
+	return nil
+}
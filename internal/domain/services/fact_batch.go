@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// FactBatch lists the changes a batch edit makes to a source's facts.
+type FactBatch struct {
+	Add    []NewFact
+	Update []FactUpdate
+	Delete []entities.Fact
+}
+
+// FactUpdate pairs a fact with its edited state.
+type FactUpdate struct {
+	Before entities.Fact
+	After  entities.Fact
+}
+
+// IsEmpty reports whether the batch changes nothing.
+func (b *FactBatch) IsEmpty() bool {
+	return len(b.Add) == 0 && len(b.Update) == 0 && len(b.Delete) == 0
+}
+
+// FactBatchResult counts the changes applied from a batch.
+type FactBatchResult struct {
+	Added   int
+	Updated int
+	Deleted int
+}
+
+// ListBySource returns up to limit facts recorded for a source.
+func (s *FactService) ListBySource(ctx context.Context, source string, limit int) ([]entities.Fact, error) {
+	facts, err := s.vectorDB.ListBySource(ctx, source, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts for %s: %w", source, err)
+	}
+	return facts, nil
+}
+
+// PlanBatch compares a source's facts with an edited list of the same
+// facts. Rows without an ID are added to the source, rows whose fields
+// changed are updated, and facts missing from the list are deleted. Every
+// row is validated, so a plan that is returned can be applied as a whole.
+func (s *FactService) PlanBatch(ctx context.Context, source string, before []entities.Fact, edited []entities.RawFact) (*FactBatch, error) {
+	existing := make(map[string]entities.Fact, len(before))
+	for i := range before {
+		existing[before[i].ID] = before[i]
+	}
+
+	batch := &FactBatch{}
+	seen := make(map[string]bool, len(edited))
+	for i := range edited {
+		raw := &edited[i]
+		id := strings.TrimSpace(raw.ID)
+
+		input := newFactFromRaw(raw, source)
+		if err := s.validate(ctx, input); err != nil {
+			return nil, fmt.Errorf("line %d: %w", raw.LineNum, err)
+		}
+
+		if id == "" {
+			batch.Add = append(batch.Add, input)
+			continue
+		}
+
+		fact, ok := existing[id]
+		if !ok {
+			return nil, entities.Errorf(entities.ErrValidation,
+				"line %d: fact %s is not from %s (remove its id to add it as a new fact)", raw.LineNum, id, source)
+		}
+		if seen[id] {
+			return nil, entities.Errorf(entities.ErrValidation, "line %d: fact %s is listed more than once", raw.LineNum, id)
+		}
+		seen[id] = true
+
+		updated := fact
+		updated.Type = input.Type
+		updated.Subject = input.Subject
+		updated.Predicate = input.Predicate
+		updated.Object = input.Object
+		updated.Context = input.Context
+		updated.SourceFile = input.SourceFile
+		if raw.Confidence != nil {
+			updated.Confidence = *raw.Confidence
+		}
+		if !sameFactContent(&fact, &updated) {
+			batch.Update = append(batch.Update, FactUpdate{Before: fact, After: updated})
+		}
+	}
+
+	for i := range before {
+		if !seen[before[i].ID] {
+			batch.Delete = append(batch.Delete, before[i])
+		}
+	}
+	return batch, nil
+}
+
+// ApplyBatch applies a planned batch: deletions, then updates, then
+// additions. It stops at the first failure and returns what was applied so
+// far; every applied change is already versioned. An update to a fact
+// changed since the batch was planned fails with an
+// *entities.StaleFactError.
+func (s *FactService) ApplyBatch(ctx context.Context, batch *FactBatch, reason string) (FactBatchResult, error) {
+	var result FactBatchResult
+
+	for i := range batch.Delete {
+		//nolint:loopcall // Each deletion is versioned and audited on its own
+		if err := s.Delete(ctx, batch.Delete[i].ID, reason); err != nil {
+			return result, fmt.Errorf("deleting %s: %w", batch.Delete[i].ID, err)
+		}
+		result.Deleted++
+	}
+
+	for i := range batch.Update {
+		update := &batch.Update[i]
+		if err := s.applyUpdate(ctx, update, reason); err != nil {
+			return result, fmt.Errorf("updating %s: %w", update.Before.ID, err)
+		}
+		result.Updated++
+	}
+
+	for i := range batch.Add {
+		if _, err := s.Create(ctx, batch.Add[i]); err != nil {
+			return result, fmt.Errorf("adding %s %s %s: %w", batch.Add[i].Subject, batch.Add[i].Predicate, batch.Add[i].Object, err)
+		}
+		result.Added++
+	}
+
+	return result, nil
+}
+
+// applyUpdate replaces a fact with its edited state, unless the fact changed
+// since the update was planned.
+func (s *FactService) applyUpdate(ctx context.Context, update *FactUpdate, reason string) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.vectorDB.FindByID(ctx, update.Before.ID)
+	if err != nil {
+		return fmt.Errorf("finding fact: %w", err)
+	}
+	if err := checkRevision(&current, &update.After, update.Before.Revision()); err != nil {
+		return err
+	}
+	return s.replace(ctx, &current, &update.After, reason)
+}
+
+// newFactFromRaw converts an edited row to a NewFact, trimmed and
+// defaulting its source to the one being edited.
+func newFactFromRaw(raw *entities.RawFact, source string) NewFact {
+	input := NewFact{
+		Type:       entities.FactType(strings.TrimSpace(raw.Type)),
+		Subject:    strings.TrimSpace(raw.Subject),
+		Predicate:  strings.TrimSpace(raw.Predicate),
+		Object:     strings.TrimSpace(raw.Object),
+		Context:    strings.TrimSpace(raw.Context),
+		SourceFile: strings.TrimSpace(raw.SourceFile),
+	}
+	if input.SourceFile == "" {
+		input.SourceFile = source
+	}
+	if raw.Confidence != nil {
+		input.Confidence = *raw.Confidence
+	}
+	return input
+}
+
+// sameFactContent reports whether two facts have the same editable fields.
+func sameFactContent(a, b *entities.Fact) bool {
+	return a.Type == b.Type &&
+		a.Subject == b.Subject &&
+		a.Predicate == b.Predicate &&
+		a.Object == b.Object &&
+		a.Context == b.Context &&
+		a.SourceFile == b.SourceFile &&
+		a.Confidence == b.Confidence
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func batchTestFacts() []entities.Fact {
+	return []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "brown", SourceFile: "notes", Confidence: 1},
+		{ID: "b", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "occupation", Object: "gardener", SourceFile: "notes", Confidence: 1},
+		{ID: "c", Type: entities.FactTypeLocation, Subject: "Bree", Predicate: "located_in", Object: "Eriador", SourceFile: "notes", Confidence: 1},
+	}
+}
+
+func TestFactService_PlanBatch(t *testing.T) {
+	svc, _, _ := newFactTestService()
+	before := batchTestFacts()
+
+	batch, err := svc.PlanBatch(context.Background(), "notes", before, []entities.RawFact{
+		{ID: "a", Type: "character", Subject: "Frodo", Predicate: "eye_color", Object: "blue", LineNum: 1},
+		{ID: "b", Type: "character", Subject: "Sam", Predicate: "occupation", Object: "gardener", LineNum: 6},
+		{Type: "character", Subject: "Merry", Predicate: "home", Object: "Buckland", LineNum: 11},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, batch.Update, 1, "unchanged rows are left alone")
+	assert.Equal(t, "a", batch.Update[0].Before.ID)
+	assert.Equal(t, "blue", batch.Update[0].After.Object)
+
+	require.Len(t, batch.Add, 1)
+	assert.Equal(t, "Merry", batch.Add[0].Subject)
+	assert.Equal(t, "notes", batch.Add[0].SourceFile, "new rows default to the edited source")
+
+	require.Len(t, batch.Delete, 1)
+	assert.Equal(t, "c", batch.Delete[0].ID)
+}
+
+func TestFactService_PlanBatch_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []entities.RawFact
+		wantErr string
+	}{
+		{
+			name:    "unknown id",
+			rows:    []entities.RawFact{{ID: "z", Type: "character", Subject: "Pippin", Predicate: "home", Object: "Tuckborough", LineNum: 3}},
+			wantErr: "line 3: fact z is not from notes",
+		},
+		{
+			name: "duplicate id",
+			rows: []entities.RawFact{
+				{ID: "a", Type: "character", Subject: "Frodo", Predicate: "eye_color", Object: "blue", LineNum: 1},
+				{ID: "a", Type: "character", Subject: "Frodo", Predicate: "eye_color", Object: "grey", LineNum: 6},
+			},
+			wantErr: "line 6: fact a is listed more than once",
+		},
+		{
+			name:    "missing object",
+			rows:    []entities.RawFact{{Type: "character", Subject: "Merry", Predicate: "home", LineNum: 4}},
+			wantErr: "line 4: object is required",
+		},
+		{
+			name:    "unknown type",
+			rows:    []entities.RawFact{{ID: "a", Type: "hobbit", Subject: "Frodo", Predicate: "eye_color", Object: "blue", LineNum: 1}},
+			wantErr: `line 1: invalid type "hobbit"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := newFactTestService()
+
+			_, err := svc.PlanBatch(context.Background(), "notes", batchTestFacts(), tt.rows)
+			require.ErrorIs(t, err, entities.ErrValidation)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestFactService_ApplyBatch(t *testing.T) {
+	before := batchTestFacts()
+	svc, vectorDB, relationalDB := newFactTestService(before...)
+
+	updated := before[0]
+	updated.Object = "blue"
+	batch := &FactBatch{
+		Add:    []NewFact{{Type: entities.FactTypeCharacter, Subject: "Merry", Predicate: "home", Object: "Buckland", SourceFile: "notes"}},
+		Update: []FactUpdate{{Before: before[0], After: updated}},
+		Delete: []entities.Fact{before[2]},
+	}
+
+	result, err := svc.ApplyBatch(context.Background(), batch, "")
+	require.NoError(t, err)
+	assert.Equal(t, FactBatchResult{Added: 1, Updated: 1, Deleted: 1}, result)
+
+	assert.Equal(t, []string{"c"}, vectorDB.DeletedIDs)
+	require.Len(t, vectorDB.SavedFacts, 2)
+	assert.Equal(t, "blue", vectorDB.SavedFacts[0].Object)
+	assert.Equal(t, "Merry", vectorDB.SavedFacts[1].Subject)
+
+	changes := make(map[entities.ChangeType]int)
+	for _, v := range relationalDB.Versions {
+		changes[v.ChangeType]++
+	}
+	assert.Equal(t, map[entities.ChangeType]int{
+		entities.ChangeCreation: 3, // c and a had no history, plus Merry
+		entities.ChangeUpdate:   1,
+		entities.ChangeDeletion: 1,
+	}, changes)
+}
+
+func TestFactService_ApplyBatch_StopsAtFirstFailure(t *testing.T) {
+	before := batchTestFacts()
+	svc, vectorDB, _ := newFactTestService(before[0])
+
+	result, err := svc.ApplyBatch(context.Background(), &FactBatch{Delete: []entities.Fact{before[0], before[1]}}, "")
+	require.ErrorIs(t, err, entities.ErrNotFound)
+	assert.Contains(t, err.Error(), "deleting b")
+	assert.Equal(t, FactBatchResult{Deleted: 1}, result)
+	assert.Equal(t, []string{"a"}, vectorDB.DeletedIDs)
+}
+
+func TestFactService_ApplyBatch_StaleUpdate(t *testing.T) {
+	before := batchTestFacts()
+	svc, vectorDB, _ := newFactTestService(before...)
+
+	planned := before[0]
+	updated := planned
+	updated.Object = "blue"
+	vectorDB.Facts[0].Object = "green" // Changed after the batch was planned
+
+	_, err := svc.ApplyBatch(context.Background(), &FactBatch{Update: []FactUpdate{{Before: planned, After: updated}}}, "")
+	require.ErrorIs(t, err, entities.ErrConflict)
+	var stale *entities.StaleFactError
+	require.ErrorAs(t, err, &stale)
+	assert.Equal(t, "green", stale.Current.Object)
+	assert.Equal(t, "blue", stale.Attempted.Object)
+	assert.Empty(t, vectorDB.SavedFacts)
+}
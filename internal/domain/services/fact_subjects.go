@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// FactSubjectsVectorDB is a ports.VectorDB that links every fact it saves
+// to the entity named by its subject, creating entities not yet known in
+// the world, and drops the links of the facts it deletes.
+type FactSubjectsVectorDB struct {
+	ports.VectorDB
+	relationalDB ports.RelationalDB
+	worldID      string
+}
+
+// NewFactSubjectsVectorDB wraps db so the facts it saves are linked to
+// their subjects' entities in worldID.
+func NewFactSubjectsVectorDB(db ports.VectorDB, relationalDB ports.RelationalDB, worldID string) *FactSubjectsVectorDB {
+	return &FactSubjectsVectorDB{VectorDB: db, relationalDB: relationalDB, worldID: worldID}
+}
+
+// Save stores a fact with its embedding.
+func (d *FactSubjectsVectorDB) Save(ctx context.Context, fact *entities.Fact) error {
+	if err := d.VectorDB.Save(ctx, fact); err != nil {
+		return err
+	}
+	return d.link(ctx, []entities.Fact{*fact})
+}
+
+// SaveBatch stores multiple facts.
+func (d *FactSubjectsVectorDB) SaveBatch(ctx context.Context, facts []entities.Fact) error {
+	if err := d.VectorDB.SaveBatch(ctx, facts); err != nil {
+		return err
+	}
+	return d.link(ctx, facts)
+}
+
+// Delete removes a fact by its ID.
+func (d *FactSubjectsVectorDB) Delete(ctx context.Context, id string) error {
+	if err := d.VectorDB.Delete(ctx, id); err != nil {
+		return err
+	}
+	return d.unlink(ctx, ports.FactSubjectFilter{FactIDs: []string{id}})
+}
+
+// DeleteBySource removes all facts from a source file.
+func (d *FactSubjectsVectorDB) DeleteBySource(ctx context.Context, sourceFile string) error {
+	if err := d.VectorDB.DeleteBySource(ctx, sourceFile); err != nil {
+		return err
+	}
+	return d.unlink(ctx, ports.FactSubjectFilter{SourceFile: sourceFile})
+}
+
+// DeleteAll removes all facts.
+func (d *FactSubjectsVectorDB) DeleteAll(ctx context.Context) error {
+	if err := d.VectorDB.DeleteAll(ctx); err != nil {
+		return err
+	}
+	return d.unlink(ctx, ports.FactSubjectFilter{})
+}
+
+// link records the entity each saved fact is about. The facts are saved,
+// so they are linked even if the caller has gone.
+func (d *FactSubjectsVectorDB) link(ctx context.Context, facts []entities.Fact) error {
+	ctx = context.WithoutCancel(ctx)
+	ids := make(map[string]string) // Normalized subject to entity ID, looked up once
+	links := make([]entities.FactSubject, 0, len(facts))
+	for i := range facts {
+		fact := &facts[i]
+		if fact.ID == "" || strings.TrimSpace(fact.Subject) == "" {
+			continue
+		}
+		key := entities.NormalizeName(fact.Subject)
+		id, ok := ids[key]
+		if !ok {
+			entity, err := d.relationalDB.FindOrCreateEntity(ctx, d.worldID, fact.Subject)
+			if err != nil {
+				return fmt.Errorf("linking fact subject %q: %w", fact.Subject, err)
+			}
+			id = entity.ID
+			ids[key] = id
+		}
+		links = append(links, entities.FactSubject{FactID: fact.ID, EntityID: id, SourceFile: fact.SourceFile})
+	}
+	if err := d.relationalDB.SaveFactSubjects(ctx, links); err != nil {
+		return fmt.Errorf("linking fact subjects: %w", err)
+	}
+	return nil
+}
+
+// unlink drops the links of deleted facts, even if the caller has gone.
+func (d *FactSubjectsVectorDB) unlink(ctx context.Context, filter ports.FactSubjectFilter) error {
+	if err := d.relationalDB.DeleteFactSubjects(context.WithoutCancel(ctx), filter); err != nil {
+		return fmt.Errorf("unlinking fact subjects: %w", err)
+	}
+	return nil
+}
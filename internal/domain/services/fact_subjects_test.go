@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestFactSubjectsVectorDB(t *testing.T) {
+	ctx := context.Background()
+	db := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+	linker := NewFactSubjectsVectorDB(db, relationalDB, "w")
+
+	require.NoError(t, linker.SaveBatch(ctx, []entities.Fact{
+		{ID: "1", Subject: "Frodo", SourceFile: "ch1.md"},
+		{ID: "2", Subject: "frodo ", SourceFile: "ch1.md"},
+		{ID: "3", Subject: "Sam", SourceFile: "ch2.md"},
+		{ID: "4", Subject: " ", SourceFile: "ch2.md"},
+	}))
+	require.NoError(t, linker.Save(ctx, &entities.Fact{ID: "5", Subject: "Sam", SourceFile: "ch3.md"}))
+
+	assert.Len(t, relationalDB.Entities, 2, "one entity per subject")
+	assert.Equal(t, []entities.FactSubject{
+		{FactID: "1", EntityID: "entity-Frodo", SourceFile: "ch1.md"},
+		{FactID: "2", EntityID: "entity-Frodo", SourceFile: "ch1.md"},
+		{FactID: "3", EntityID: "entity-Sam", SourceFile: "ch2.md"},
+		{FactID: "5", EntityID: "entity-Sam", SourceFile: "ch3.md"},
+	}, relationalDB.FactSubjects)
+
+	require.NoError(t, linker.Delete(ctx, "1"))
+	require.NoError(t, linker.DeleteBySource(ctx, "ch2.md"))
+	assert.Equal(t, []string{"2", "5"}, linkedFactIDs(relationalDB))
+
+	require.NoError(t, linker.DeleteAll(ctx))
+	assert.Empty(t, relationalDB.FactSubjects)
+
+	db.Err = errors.New("qdrant down")
+	require.Error(t, linker.Save(ctx, &entities.Fact{ID: "6", Subject: "Pippin"}))
+	assert.Empty(t, relationalDB.FactSubjects, "facts that were not saved are not linked")
+}
+
+func linkedFactIDs(relationalDB *mocks.RelationalDB) []string {
+	ids := make([]string, len(relationalDB.FactSubjects))
+	for i, link := range relationalDB.FactSubjects {
+		ids[i] = link.FactID
+	}
+	return ids
+}
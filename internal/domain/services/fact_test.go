@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+var factTestNow = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+func newFactTestService(facts ...entities.Fact) (*FactService, *mocks.VectorDB, *mocks.RelationalDB) {
+	vectorDB := &mocks.VectorDB{Facts: facts}
+	relationalDB := mocks.NewRelationalDB()
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.5, 0.5}}
+	svc := NewFactService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	svc.now = func() time.Time { return factTestNow }
+	return svc, vectorDB, relationalDB
+}
+
+func TestFactService_Create(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService()
+
+	fact, err := svc.Create(context.Background(), NewFact{
+		Type:      entities.FactTypeCharacter,
+		Subject:   " Frodo ",
+		Predicate: "eye_color",
+		Object:    "blue",
+	})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, fact.ID)
+	assert.Equal(t, "Frodo", fact.Subject)
+	assert.Equal(t, ManualSource, fact.SourceFile)
+	assert.InDelta(t, 1.0, fact.Confidence, 0.001)
+	assert.Equal(t, entities.FactStatusActive, fact.Status)
+	assert.Equal(t, factTestNow, fact.CreatedAt)
+	assert.NotEmpty(t, fact.Embedding)
+
+	require.Len(t, vectorDB.SavedFacts, 1)
+	assert.Equal(t, fact.ID, vectorDB.SavedFacts[0].ID)
+
+	require.Len(t, relationalDB.Versions, 1)
+	version := relationalDB.Versions[0]
+	assert.Equal(t, fact.ID, version.FactID)
+	assert.Equal(t, 1, version.Version)
+	assert.Equal(t, entities.ChangeCreation, version.ChangeType)
+	assert.Nil(t, version.Data.Embedding, "versions are stored without embeddings")
+}
+
+func TestFactService_Create_Invalid(t *testing.T) {
+	valid := NewFact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue"}
+
+	tests := []struct {
+		name    string
+		modify  func(*NewFact)
+		wantErr string
+	}{
+		{
+			name:    "missing subject",
+			modify:  func(f *NewFact) { f.Subject = "  " },
+			wantErr: "subject is required",
+		},
+		{
+			name:    "missing type",
+			modify:  func(f *NewFact) { f.Type = "" },
+			wantErr: "type is required",
+		},
+		{
+			name:    "unknown type",
+			modify:  func(f *NewFact) { f.Type = "spaceship" },
+			wantErr: `invalid type "spaceship" (valid: `,
+		},
+		{
+			name:    "confidence out of range",
+			modify:  func(f *NewFact) { f.Confidence = 1.5 },
+			wantErr: "confidence must be between 0 and 1, got 1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, vectorDB, relationalDB := newFactTestService()
+			input := valid
+			tt.modify(&input)
+
+			_, err := svc.Create(context.Background(), input)
+			require.Error(t, err)
+			require.ErrorIs(t, err, entities.ErrValidation)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Empty(t, vectorDB.SavedFacts)
+			assert.Empty(t, relationalDB.Versions)
+		})
+	}
+}
+
+func TestFactService_Update(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService(entities.Fact{
+		ID:        "a",
+		Type:      entities.FactTypeCharacter,
+		Subject:   "Frodo",
+		Predicate: "eye_color",
+		Object:    "brown",
+	})
+
+	fact, err := svc.Update(context.Background(), "a", "", FactEdit{Object: "blue"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "blue", fact.Object)
+	require.Len(t, vectorDB.SavedFacts, 1)
+
+	require.Len(t, relationalDB.Versions, 2, "facts without history get their prior state recorded first")
+	assert.Equal(t, entities.ChangeCreation, relationalDB.Versions[0].ChangeType)
+	assert.Equal(t, "brown", relationalDB.Versions[0].Data.Object)
+	assert.Equal(t, 2, relationalDB.Versions[1].Version)
+	assert.Equal(t, entities.ChangeUpdate, relationalDB.Versions[1].ChangeType)
+	assert.Equal(t, manualUpdateReason, relationalDB.Versions[1].Reason)
+}
+
+func TestFactService_Update_StaleRevision(t *testing.T) {
+	original := entities.Fact{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "brown"}
+	svc, vectorDB, _ := newFactTestService(original)
+	revision := original.Revision()
+
+	vectorDB.Facts[0].Object = "green" // Another writer's change
+
+	_, err := svc.Update(context.Background(), "a", revision, FactEdit{Object: "blue"}, "")
+	require.ErrorIs(t, err, entities.ErrConflict)
+	var stale *entities.StaleFactError
+	require.ErrorAs(t, err, &stale)
+	assert.Equal(t, revision, stale.Revision)
+	assert.Equal(t, "green", stale.Current.Object)
+	assert.Equal(t, "blue", stale.Attempted.Object)
+	assert.Empty(t, vectorDB.SavedFacts)
+
+	fact, err := svc.Update(context.Background(), "a", vectorDB.Facts[0].Revision(), FactEdit{Object: "blue"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "blue", fact.Object)
+}
+
+func TestFactService_SetKnownBy(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService(entities.Fact{
+		ID:        "a",
+		Type:      entities.FactTypeCharacter,
+		Subject:   "Gollum",
+		Predicate: "killed",
+		Object:    "Deagol",
+	})
+	ctx := context.Background()
+
+	fact, err := svc.SetKnownBy(ctx, "a", "middle-earth", []string{"Gandalf", "gandalf", "Bilbo"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"entity-Gandalf", "entity-Bilbo"}, fact.KnownBy, "names resolved once each")
+	assert.Len(t, relationalDB.Entities, 2)
+	require.Len(t, vectorDB.SavedFacts, 1)
+	require.Len(t, relationalDB.Versions, 2)
+	assert.Equal(t, entities.ChangeUpdate, relationalDB.Versions[1].ChangeType)
+
+	vectorDB.Facts[0] = *fact
+	fact, err = svc.SetKnownBy(ctx, "a", "middle-earth", nil, "")
+	require.NoError(t, err)
+	assert.Empty(t, fact.KnownBy, "no names makes the fact common knowledge")
+
+	_, err = svc.SetKnownBy(ctx, "a", "middle-earth", []string{" "}, "")
+	require.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestFactService_Delete(t *testing.T) {
+	svc, vectorDB, relationalDB := newFactTestService(entities.Fact{ID: "a", Subject: "Frodo"})
+	require.NoError(t, relationalDB.SaveVersion(context.Background(), &entities.FactVersion{FactID: "a", Version: 3}))
+
+	require.NoError(t, svc.Delete(context.Background(), "a", "duplicate"))
+	assert.Equal(t, []string{"a"}, vectorDB.DeletedIDs)
+
+	require.Len(t, relationalDB.Versions, 2)
+	assert.Equal(t, 4, relationalDB.Versions[1].Version)
+	assert.Equal(t, entities.ChangeDeletion, relationalDB.Versions[1].ChangeType)
+	assert.Equal(t, "duplicate", relationalDB.Versions[1].Reason)
+}
+
+func TestFactService_Delete_NotFound(t *testing.T) {
+	svc, vectorDB, _ := newFactTestService()
+
+	err := svc.Delete(context.Background(), "missing", "")
+	require.ErrorIs(t, err, entities.ErrNotFound)
+	assert.Empty(t, vectorDB.DeletedIDs)
+}
+
+func TestFactService_PrepareAndSave(t *testing.T) {
+	existing := entities.Fact{
+		ID:            "old",
+		Type:          entities.FactTypeCharacter,
+		Subject:       "Frodo",
+		Predicate:     "eye_color",
+		Object:        "blue",
+		SourceFile:    "chapter1.txt",
+		TextEmbedding: []float32{0.5, 0.5},
+	}
+	svc, vectorDB, relationalDB := newFactTestService(existing)
+
+	fact, err := svc.Prepare(context.Background(), NewFact{
+		Type:      entities.FactTypeCharacter,
+		Subject:   "Frodo",
+		Predicate: "eye_colour",
+		Object:    "blue",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, vectorDB.SavedFacts, "preparing a fact does not store it")
+
+	similar, err := svc.FindSimilar(context.Background(), fact)
+	require.NoError(t, err)
+	require.Len(t, similar, 1)
+	assert.Equal(t, "old", similar[0].Fact.ID)
+
+	require.NoError(t, svc.Save(context.Background(), fact))
+	require.Len(t, vectorDB.SavedFacts, 1)
+	assert.Equal(t, fact.ID, vectorDB.SavedFacts[0].ID)
+	require.Len(t, relationalDB.Versions, 1)
+	assert.Equal(t, entities.ChangeCreation, relationalDB.Versions[0].ChangeType)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// DefaultGlossaryLimit caps how many facts are read for a glossary.
+	DefaultGlossaryLimit = 5000
+
+	// glossaryPageSize is the number of facts read per page.
+	glossaryPageSize = 256
+
+	// maxGlossaryWords is the most words a glossary term may have; longer
+	// subjects and objects are descriptions, not names.
+	maxGlossaryWords = 4
+
+	// glossaryDefinitionFacts is the most facts a definition is built from.
+	glossaryDefinitionFacts = 3
+)
+
+// GlossaryOptions controls which terms a glossary includes.
+type GlossaryOptions struct {
+	Limit       int // Maximum facts to read (0 = DefaultGlossaryLimit)
+	MinMentions int // Terms mentioned by fewer facts are left out (0 = 1)
+}
+
+// GlossaryEntry is an invented term with a definition drawn from the facts
+// about it.
+type GlossaryEntry struct {
+	Term       string
+	Type       entities.FactType // Most common type of facts about the term; empty if it is only ever an object
+	Definition string
+	Mentions   int      // Facts naming the term as subject or object, or in their context
+	Sources    []string // Source files of those facts, sorted
+}
+
+// Glossary lists a world's invented terms.
+type Glossary struct {
+	Facts     int  // Facts read
+	Truncated bool // More facts exist than were read
+	Entries   []GlossaryEntry
+}
+
+// GlossaryService finds the terms a world invents, such as names of people,
+// places, and things, and defines them from the facts about them.
+type GlossaryService struct {
+	vectorDB   ports.VectorDB
+	dictionary ports.Dictionary
+}
+
+// NewGlossaryService creates a new GlossaryService. Words in dictionary
+// are ordinary; terms with a word outside it are invented.
+func NewGlossaryService(vectorDB ports.VectorDB, dictionary ports.Dictionary) *GlossaryService {
+	return &GlossaryService{
+		vectorDB:   vectorDB,
+		dictionary: dictionary,
+	}
+}
+
+// glossaryTerm collects the facts about one term while the glossary is built.
+type glossaryTerm struct {
+	key       string
+	spellings map[string]int
+	about     []entities.Fact // Facts with the term as subject
+	mentions  []entities.Fact // Facts with the term as object
+	sources   map[string]bool
+	count     int
+}
+
+// Build reads the world's facts and returns the invented terms among their
+// subjects and objects, sorted by term. Spellings differing only in case,
+// punctuation, or a leading article are one term, written the most common
+// way. Facts pending review are skipped.
+func (s *GlossaryService) Build(ctx context.Context, opts GlossaryOptions) (*Glossary, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultGlossaryLimit
+	}
+	minMentions := max(opts.MinMentions, 1)
+
+	facts, truncated, err := s.loadFacts(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make(map[string]*glossaryTerm)
+	for i := range facts {
+		s.collect(terms, &facts[i], facts[i].Subject, true)
+		s.collect(terms, &facts[i], facts[i].Object, false)
+	}
+	countContextMentions(terms, facts)
+
+	glossary := &Glossary{Facts: len(facts), Truncated: truncated}
+	for _, t := range terms {
+		if t.count >= minMentions {
+			glossary.Entries = append(glossary.Entries, t.entry())
+		}
+	}
+	sort.Slice(glossary.Entries, func(i, j int) bool {
+		a, b := strings.ToLower(glossary.Entries[i].Term), strings.ToLower(glossary.Entries[j].Term)
+		if a != b {
+			return a < b
+		}
+		return glossary.Entries[i].Term < glossary.Entries[j].Term
+	})
+	return glossary, nil
+}
+
+// loadFacts reads up to limit active facts, reporting whether more exist.
+func (s *GlossaryService) loadFacts(ctx context.Context, limit int) ([]entities.Fact, bool, error) {
+	var facts []entities.Fact
+	cursor := ""
+	for {
+		page, next, err := s.vectorDB.ListPage(ctx, ports.FactPageOptions{Limit: glossaryPageSize, Cursor: cursor})
+		if err != nil {
+			return nil, false, fmt.Errorf("listing facts: %w", err)
+		}
+		for i := range page {
+			if page[i].IsPending() {
+				continue
+			}
+			if len(facts) == limit {
+				return facts, true, nil
+			}
+			facts = append(facts, page[i])
+		}
+		if next == "" {
+			return facts, false, nil
+		}
+		cursor = next
+	}
+}
+
+// collect records fact against the term value names, if it is invented.
+func (s *GlossaryService) collect(terms map[string]*glossaryTerm, fact *entities.Fact, value string, subject bool) {
+	value = strings.TrimSpace(value)
+	key := subjectKey(value)
+	if key == "" || !s.invented(key) || entities.ParseObjectValue(value).IsTyped() {
+		return
+	}
+
+	t := terms[key]
+	if t == nil {
+		t = &glossaryTerm{key: key, spellings: make(map[string]int), sources: make(map[string]bool)}
+		terms[key] = t
+	}
+	if subject {
+		t.about = append(t.about, *fact)
+	} else {
+		t.mentions = append(t.mentions, *fact)
+	}
+	t.spellings[withoutArticle(value)]++
+	if fact.SourceFile != "" {
+		t.sources[fact.SourceFile] = true
+	}
+	t.count++
+}
+
+// invented reports whether a term key is short enough to be a name and has
+// a word of letters the dictionary does not know.
+func (s *GlossaryService) invented(key string) bool {
+	words := strings.Fields(key)
+	if len(words) > maxGlossaryWords {
+		return false
+	}
+	for _, w := range words {
+		letters := strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) })
+		if len(letters) > 1 && !strings.ContainsFunc(letters, unicode.IsDigit) && !s.dictionary.Contains(letters) {
+			return true
+		}
+	}
+	return false
+}
+
+// countContextMentions counts facts that mention a term only in their
+// context, adding their sources.
+func countContextMentions(terms map[string]*glossaryTerm, facts []entities.Fact) {
+	if len(terms) == 0 {
+		return
+	}
+	for i := range facts {
+		if facts[i].Context == "" {
+			continue
+		}
+		text := " " + contextKey(facts[i].Context) + " "
+		subject, object := subjectKey(facts[i].Subject), subjectKey(facts[i].Object)
+		for key, t := range terms {
+			if key == subject || key == object || !strings.Contains(text, " "+key+" ") {
+				continue
+			}
+			t.count++
+			if facts[i].SourceFile != "" {
+				t.sources[facts[i].SourceFile] = true
+			}
+		}
+	}
+}
+
+// contextKey normalizes text like subjectKey, keeping every word.
+func contextKey(text string) string {
+	return strings.Join(strings.FieldsFunc(entities.NormalizeName(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}), " ")
+}
+
+// withoutArticle drops a leading article from a multi-word value.
+func withoutArticle(value string) string {
+	first, rest, ok := strings.Cut(value, " ")
+	for _, article := range leadingArticles {
+		if ok && strings.EqualFold(first, article) {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return value
+}
+
+// entry turns the collected facts into a glossary entry.
+func (t *glossaryTerm) entry() GlossaryEntry {
+	variants := make([]SubjectVariant, 0, len(t.spellings))
+	for name, n := range t.spellings {
+		variants = append(variants, SubjectVariant{Name: name, Facts: n})
+	}
+	sort.Slice(variants, func(i, j int) bool { return preferSubject(variants[i], variants[j]) })
+
+	sources := make([]string, 0, len(t.sources))
+	for source := range t.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	return GlossaryEntry{
+		Term:       variants[0].Name,
+		Type:       mostCommonType(t.about),
+		Definition: define(t.about, t.mentions),
+		Mentions:   t.count,
+		Sources:    sources,
+	}
+}
+
+// mostCommonType returns the type most facts have, the first seen on ties.
+func mostCommonType(facts []entities.Fact) entities.FactType {
+	counts := make(map[entities.FactType]int)
+	var best entities.FactType
+	for i := range facts {
+		counts[facts[i].Type]++
+		if counts[facts[i].Type] > counts[best] {
+			best = facts[i].Type
+		}
+	}
+	return best
+}
+
+// define builds a definition from the most confident facts about a term,
+// such as "Rules the north; lives in Ember Hall." A term never a subject
+// is defined by the facts naming it, in full.
+func define(about, mentions []entities.Fact) string {
+	facts, full := about, false
+	if len(facts) == 0 {
+		facts, full = mentions, true
+	}
+	facts = append([]entities.Fact(nil), facts...)
+	sort.SliceStable(facts, func(i, j int) bool { return facts[i].Confidence > facts[j].Confidence })
+
+	var clauses []string
+	seen := make(map[string]bool)
+	for i := range facts {
+		clause := strings.ReplaceAll(facts[i].Predicate, "_", " ") + " " + facts[i].Object
+		if full {
+			clause = facts[i].Subject + " " + clause
+		}
+		if seen[clause] {
+			continue
+		}
+		seen[clause] = true
+		clauses = append(clauses, clause)
+		if len(clauses) == glossaryDefinitionFacts {
+			break
+		}
+	}
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	definition := strings.Join(clauses, "; ")
+	first := []rune(definition)
+	first[0] = unicode.ToUpper(first[0])
+	return string(first) + "."
+}
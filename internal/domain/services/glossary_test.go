@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+// wordSet is a ports.Dictionary of exactly its words.
+type wordSet map[string]bool
+
+func (w wordSet) Contains(word string) bool { return w[word] }
+
+var glossaryTestWords = wordSet{"the": true, "north": true, "hall": true, "ring": true, "one": true, "a": true, "hobbit": true, "tall": true}
+
+func TestGlossaryService_Build(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Dragonlord", Predicate: "rules", Object: "the North", Confidence: 0.8, SourceFile: "ch1.md"},
+		{ID: "2", Type: entities.FactTypeCharacter, Subject: "the dragonlord", Predicate: "lives_in", Object: "Ember Hall", Confidence: 0.9, SourceFile: "ch2.md"},
+		{ID: "3", Type: entities.FactTypeLocation, Subject: "Ember Hall", Predicate: "lies_in", Object: "the north", SourceFile: "ch2.md"},
+		{ID: "4", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is", Object: "a hobbit", Context: "Frodo met the Dragonlord.", SourceFile: "ch3.md"},
+		{ID: "5", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "age", Object: "33", SourceFile: "ch3.md"},
+		{ID: "6", Type: entities.FactTypeEvent, Subject: "Sam", Predicate: "carried", Object: "Frodo", SourceFile: "ch4.md"},
+		{ID: "7", Type: entities.FactTypeRule, Subject: "The One Ring", Predicate: "is", Object: "tall"},
+		{ID: "8", Type: entities.FactTypeCharacter, Subject: "Gollum", Predicate: "is", Object: "pending", Status: entities.FactStatusPending},
+	}}
+	svc := NewGlossaryService(vectorDB, glossaryTestWords)
+
+	glossary, err := svc.Build(context.Background(), GlossaryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 7, glossary.Facts)
+	assert.False(t, glossary.Truncated)
+
+	require.Len(t, glossary.Entries, 4, "ordinary words, numbers, and pending facts are left out")
+	byTerm := make(map[string]GlossaryEntry)
+	for _, e := range glossary.Entries {
+		byTerm[e.Term] = e
+	}
+	assert.Equal(t, []string{"Dragonlord", "Ember Hall", "Frodo", "Sam"},
+		[]string{glossary.Entries[0].Term, glossary.Entries[1].Term, glossary.Entries[2].Term, glossary.Entries[3].Term})
+
+	dragonlord := byTerm["Dragonlord"]
+	assert.Equal(t, entities.FactTypeCharacter, dragonlord.Type)
+	assert.Equal(t, "Lives in Ember Hall; rules the North.", dragonlord.Definition, "most confident facts first")
+	assert.Equal(t, 3, dragonlord.Mentions, "context mentions count")
+	assert.Equal(t, []string{"ch1.md", "ch2.md", "ch3.md"}, dragonlord.Sources)
+
+	assert.Equal(t, 2, byTerm["Ember Hall"].Mentions)
+	assert.Equal(t, entities.FactTypeLocation, byTerm["Ember Hall"].Type)
+	assert.Equal(t, 3, byTerm["Frodo"].Mentions)
+
+	sam := byTerm["Sam"]
+	assert.Equal(t, "Carried Frodo.", sam.Definition)
+
+	glossary, err = svc.Build(context.Background(), GlossaryOptions{MinMentions: 3})
+	require.NoError(t, err)
+	assert.Len(t, glossary.Entries, 2)
+
+	glossary, err = svc.Build(context.Background(), GlossaryOptions{Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, glossary.Truncated)
+	assert.Equal(t, 2, glossary.Facts)
+}
+
+func TestGlossaryService_Build_ObjectOnly(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "born_in", Object: "Hobbiton"},
+	}}
+	glossary, err := NewGlossaryService(vectorDB, glossaryTestWords).Build(context.Background(), GlossaryOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, glossary.Entries, 2)
+	assert.Equal(t, "Hobbiton", glossary.Entries[1].Term)
+	assert.Empty(t, glossary.Entries[1].Type)
+	assert.Equal(t, "Frodo born in Hobbiton.", glossary.Entries[1].Definition)
+}
+
+func TestGlossaryService_Build_Error(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Err: errors.New("qdrant down")}
+	_, err := NewGlossaryService(vectorDB, glossaryTestWords).Build(context.Background(), GlossaryOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listing facts")
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Graph is a world's entities and the relationships between them.
+type Graph struct {
+	Entities      []*entities.Entity
+	Relationships []entities.Relationship
+}
+
+// GraphService exports a world's entity graph and keeps an optional graph
+// database in step with it.
+type GraphService struct {
+	relationalDB ports.RelationalDB
+	graphDB      ports.GraphDB
+}
+
+// NewGraphService creates a new GraphService. graphDB may be nil when no
+// graph database is configured; Load still works without one.
+func NewGraphService(relationalDB ports.RelationalDB, graphDB ports.GraphDB) *GraphService {
+	return &GraphService{
+		relationalDB: relationalDB,
+		graphDB:      graphDB,
+	}
+}
+
+// Load reads a world's entities and the relationships between them, sorted
+// by name and ID so exports are stable.
+func (s *GraphService) Load(ctx context.Context, worldID string) (*Graph, error) {
+	total, err := s.relationalDB.CountEntities(ctx, worldID)
+	if err != nil {
+		return nil, fmt.Errorf("counting entities: %w", err)
+	}
+
+	ents, err := s.relationalDB.ListEntities(ctx, worldID, total, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing entities: %w", err)
+	}
+	sort.Slice(ents, func(i, j int) bool {
+		if ents[i].NormalizedName != ents[j].NormalizedName {
+			return ents[i].NormalizedName < ents[j].NormalizedName
+		}
+		return ents[i].ID < ents[j].ID
+	})
+
+	inWorld := make(map[string]bool, len(ents))
+	for _, e := range ents {
+		inWorld[e.ID] = true
+	}
+
+	seen := make(map[string]bool)
+	var rels []entities.Relationship
+	for _, e := range ents {
+		found, err := s.relationalDB.FindRelationshipsByEntity(ctx, e.ID)
+		if err != nil {
+			return nil, fmt.Errorf("finding relationships of %s: %w", e.Name, err)
+		}
+		for i := range found {
+			r := found[i]
+			if seen[r.ID] || !inWorld[r.SourceEntityID] || !inWorld[r.TargetEntityID] {
+				continue
+			}
+			seen[r.ID] = true
+			rels = append(rels, r)
+		}
+	}
+	sort.Slice(rels, func(i, j int) bool { return rels[i].ID < rels[j].ID })
+
+	return &Graph{Entities: ents, Relationships: rels}, nil
+}
+
+// Sync makes the graph database's copy of a world match the world,
+// removing entities and relationships that no longer exist.
+func (s *GraphService) Sync(ctx context.Context, worldID string) (*Graph, error) {
+	if s.graphDB == nil {
+		return nil, errNoGraphDB
+	}
+
+	graph, err := s.Load(ctx, worldID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.graphDB.SyncGraph(ctx, worldID, graph.Entities, graph.Relationships); err != nil {
+		return nil, fmt.Errorf("syncing graph database: %w", err)
+	}
+	return graph, nil
+}
+
+// Query runs a read-only query against the graph database.
+func (s *GraphService) Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	if s.graphDB == nil {
+		return nil, errNoGraphDB
+	}
+	if query == "" {
+		return nil, entities.Errorf(entities.ErrValidation, "query is required")
+	}
+
+	rows, err := s.graphDB.Query(ctx, query, params)
+	if err != nil {
+		return nil, fmt.Errorf("querying graph database: %w", err)
+	}
+	return rows, nil
+}
+
+// errNoGraphDB is returned by operations that need a graph database when
+// none is configured.
+var errNoGraphDB = entities.Errorf(entities.ErrValidation, "no graph database configured: set graph.provider in the config")
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+// graphTestDB returns a world with three entities, two relationships
+// between them, and one relationship to an entity in another world.
+func graphTestDB() *mocks.RelationalDB {
+	db := mocks.NewRelationalDB()
+	for _, e := range []*entities.Entity{
+		{ID: "e2", WorldID: "w", Name: "Paris", NormalizedName: "paris"},
+		{ID: "e1", WorldID: "w", Name: "Jean", NormalizedName: "jean"},
+		{ID: "e3", WorldID: "w", Name: "Marie", NormalizedName: "marie"},
+		{ID: "x1", WorldID: "other", Name: "Rome", NormalizedName: "rome"},
+	} {
+		db.Entities[e.ID] = e
+	}
+	db.Relationships = []entities.Relationship{
+		{ID: "r2", SourceEntityID: "e1", TargetEntityID: "e3", Type: entities.RelationType("knows"), Bidirectional: true},
+		{ID: "r1", SourceEntityID: "e1", TargetEntityID: "e2", Type: entities.RelationType("located_in")},
+		{ID: "r3", SourceEntityID: "e3", TargetEntityID: "x1", Type: entities.RelationType("located_in")},
+	}
+	return db
+}
+
+func TestGraphService_Load(t *testing.T) {
+	svc := NewGraphService(graphTestDB(), nil)
+
+	graph, err := svc.Load(context.Background(), "w")
+	require.NoError(t, err)
+
+	var names []string
+	for _, e := range graph.Entities {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"Jean", "Marie", "Paris"}, names, "entities sorted by name")
+
+	var ids []string
+	for _, r := range graph.Relationships {
+		ids = append(ids, r.ID)
+	}
+	assert.Equal(t, []string{"r1", "r2"}, ids, "relationships deduplicated, sorted, and kept within the world")
+}
+
+func TestGraphService_Sync(t *testing.T) {
+	graphDB := &mocks.GraphDB{}
+	svc := NewGraphService(graphTestDB(), graphDB)
+
+	graph, err := svc.Sync(context.Background(), "w")
+	require.NoError(t, err)
+
+	assert.Equal(t, "w", graphDB.SyncedWorld)
+	assert.Equal(t, graph.Entities, graphDB.SyncedEntities)
+	assert.Equal(t, graph.Relationships, graphDB.SyncedRelationships)
+}
+
+func TestGraphService_Query(t *testing.T) {
+	graphDB := &mocks.GraphDB{Rows: []map[string]any{{"name": "Jean"}}}
+	svc := NewGraphService(mocks.NewRelationalDB(), graphDB)
+
+	rows, err := svc.Query(context.Background(), "MATCH (e:Entity) RETURN e.name AS name", nil)
+	require.NoError(t, err)
+	assert.Equal(t, graphDB.Rows, rows)
+	assert.Equal(t, []string{"MATCH (e:Entity) RETURN e.name AS name"}, graphDB.Queries)
+}
+
+func TestGraphService_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		run     func(*GraphService) error
+		graphDB *mocks.GraphDB
+		wantErr string
+		kind    error
+	}{
+		{
+			name:    "sync without graph database",
+			run:     func(s *GraphService) error { _, err := s.Sync(ctx, "w"); return err },
+			wantErr: "no graph database configured",
+			kind:    entities.ErrValidation,
+		},
+		{
+			name:    "query without graph database",
+			run:     func(s *GraphService) error { _, err := s.Query(ctx, "RETURN 1", nil); return err },
+			wantErr: "no graph database configured",
+			kind:    entities.ErrValidation,
+		},
+		{
+			name:    "empty query",
+			run:     func(s *GraphService) error { _, err := s.Query(ctx, "", nil); return err },
+			graphDB: &mocks.GraphDB{},
+			wantErr: "query is required",
+			kind:    entities.ErrValidation,
+		},
+		{
+			name:    "sync failure",
+			run:     func(s *GraphService) error { _, err := s.Sync(ctx, "w"); return err },
+			graphDB: &mocks.GraphDB{Err: errors.New("connection refused")},
+			wantErr: "syncing graph database",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var svc *GraphService
+			if tt.graphDB != nil {
+				svc = NewGraphService(graphTestDB(), tt.graphDB)
+			} else {
+				svc = NewGraphService(graphTestDB(), nil)
+			}
+
+			err := tt.run(svc)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			if tt.kind != nil {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Default thresholds for health measurements.
+const (
+	// DefaultLowConfidence is the confidence below which a fact counts as
+	// low confidence when no review threshold is configured.
+	DefaultLowConfidence = 0.7
+	// DefaultStaleAfter is how long a fact can go without updates before it
+	// counts as stale.
+	DefaultStaleAfter = 365 * 24 * time.Hour
+)
+
+// healthPageSize is the number of facts read per page when measuring health.
+const healthPageSize = 256
+
+// Weights of each problem in the health score. They sum to 1, so a world
+// where every fact and entity has every problem scores 0.
+const (
+	conflictWeight      = 0.4
+	lowConfidenceWeight = 0.2
+	staleWeight         = 0.2
+	orphanWeight        = 0.2
+)
+
+// HealthOptions controls how a world's health is measured.
+type HealthOptions struct {
+	WorldID       string
+	LowConfidence float64       // Confidence below which facts count as low confidence (0 = DefaultLowConfidence)
+	StaleAfter    time.Duration // Age since last update at which facts count as stale (0 = DefaultStaleAfter)
+}
+
+// HealthService measures how consistent a world is and tracks the
+// measurements over time.
+type HealthService struct {
+	vectorDB      ports.VectorDB
+	relationalDB  ports.RelationalDB
+	entityService *EntityService
+	now           func() time.Time
+}
+
+// NewHealthService creates a new health service.
+func NewHealthService(vectorDB ports.VectorDB, relationalDB ports.RelationalDB, entityService *EntityService) *HealthService {
+	return &HealthService{
+		vectorDB:      vectorDB,
+		relationalDB:  relationalDB,
+		entityService: entityService,
+		now:           time.Now,
+	}
+}
+
+// Measure computes the current health of a world without recording it.
+func (s *HealthService) Measure(ctx context.Context, opts HealthOptions) (*entities.HealthSample, error) {
+	lowConfidence := opts.LowConfidence
+	if lowConfidence <= 0 {
+		lowConfidence = DefaultLowConfidence
+	}
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+
+	now := s.now()
+	staleBefore := now.Add(-staleAfter)
+	sample := &entities.HealthSample{RecordedAt: now}
+
+	cursor := ""
+	for {
+		facts, next, err := s.vectorDB.ListPage(ctx, ports.FactPageOptions{Limit: healthPageSize, Cursor: cursor})
+		if err != nil {
+			return nil, fmt.Errorf("listing facts: %w", err)
+		}
+
+		ids := make([]string, len(facts))
+		for i := range facts {
+			ids[i] = facts[i].ID
+			if facts[i].Confidence < lowConfidence {
+				sample.LowConfidenceFacts++
+			}
+			if isStale(&facts[i], staleBefore) {
+				sample.StaleFacts++
+			}
+			if facts[i].SourceCount() > 1 {
+				sample.CorroboratedFacts++
+			}
+		}
+		counts, err := s.relationalDB.CountOpenConflicts(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("counting open conflicts: %w", err)
+		}
+		sample.ConflictingFacts += len(counts)
+		sample.Facts += len(facts)
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	open, err := s.relationalDB.ListConflicts(ctx, ports.ConflictListOptions{Status: entities.ConflictOpen})
+	if err != nil {
+		return nil, fmt.Errorf("listing open conflicts: %w", err)
+	}
+	sample.OpenConflicts = len(open)
+
+	if sample.Entities, err = s.relationalDB.CountEntities(ctx, opts.WorldID); err != nil {
+		return nil, fmt.Errorf("counting entities: %w", err)
+	}
+	orphans, err := s.entityService.Orphans(ctx, opts.WorldID)
+	if err != nil {
+		return nil, err
+	}
+	sample.OrphanEntities = len(orphans)
+
+	sample.Score = healthScore(sample)
+	return sample, nil
+}
+
+// Record measures the current health of a world and stores the measurement.
+func (s *HealthService) Record(ctx context.Context, opts HealthOptions) (*entities.HealthSample, error) {
+	sample, err := s.Measure(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.relationalDB.SaveHealthSample(ctx, sample); err != nil {
+		return nil, fmt.Errorf("recording health: %w", err)
+	}
+	return sample, nil
+}
+
+// History returns the last limit recorded measurements, oldest first.
+// A limit of 0 returns all of them.
+func (s *HealthService) History(ctx context.Context, limit int) ([]entities.HealthSample, error) {
+	samples, err := s.relationalDB.ListHealthSamples(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing health history: %w", err)
+	}
+	return samples, nil
+}
+
+// isStale reports whether a fact was last changed before the cutoff. Facts
+// without timestamps are never stale.
+func isStale(fact *entities.Fact, cutoff time.Time) bool {
+	changed := fact.UpdatedAt
+	if changed.IsZero() {
+		changed = fact.CreatedAt
+	}
+	return !changed.IsZero() && changed.Before(cutoff)
+}
+
+// healthScore weighs the share of facts and entities with each problem into
+// a score from 0 (every fact and entity has every problem) to 100 (none do),
+// rounded to one decimal.
+func healthScore(sample *entities.HealthSample) float64 {
+	penalty := conflictWeight*ratio(sample.ConflictingFacts, sample.Facts) +
+		lowConfidenceWeight*ratio(sample.LowConfidenceFacts, sample.Facts) +
+		staleWeight*ratio(sample.StaleFacts, sample.Facts) +
+		orphanWeight*ratio(sample.OrphanEntities, sample.Entities)
+	return math.Round((1-penalty)*1000) / 10
+}
+
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Min(float64(part)/float64(total), 1)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+var healthTestNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newHealthTestService builds a world with three entities, one of them an
+// orphan, and four facts: one low confidence, one stale, one corroborated,
+// and two in an open conflict.
+func newHealthTestService() (*HealthService, *mocks.RelationalDB) {
+	entityService := newEntityUsageTestService()
+	relationalDB := entityService.relationalDB.(*mocks.RelationalDB)
+	vectorDB := entityService.vectorDB.(*mocks.VectorDB)
+
+	recent := healthTestNow.Add(-24 * time.Hour)
+	vectorDB.Facts = []entities.Fact{
+		{ID: "f1", Subject: "Frodo", Confidence: 0.95, UpdatedAt: recent},
+		{ID: "f2", Subject: "frodo", Confidence: 0.5, UpdatedAt: recent},
+		{ID: "f3", Subject: "Sam", Confidence: 1, CreatedAt: healthTestNow.AddDate(-2, 0, 0)},
+		{ID: "f4", Subject: "Sam", Confidence: 1, Corroboration: 2},
+	}
+	relationalDB.Conflicts = []entities.Conflict{
+		{ID: "c1", FactID: "f1", OtherFactID: "f4", Status: entities.ConflictOpen},
+		{ID: "c2", FactID: "f1", OtherFactID: "f3", Status: entities.ConflictResolved},
+	}
+
+	svc := NewHealthService(vectorDB, relationalDB, entityService)
+	svc.now = func() time.Time { return healthTestNow }
+	return svc, relationalDB
+}
+
+func TestHealthService_Measure(t *testing.T) {
+	svc, relationalDB := newHealthTestService()
+
+	sample, err := svc.Measure(context.Background(), HealthOptions{WorldID: "w"})
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.HealthSample{
+		RecordedAt:         healthTestNow,
+		Facts:              4,
+		OpenConflicts:      1,
+		ConflictingFacts:   2,
+		LowConfidenceFacts: 1,
+		StaleFacts:         1,
+		CorroboratedFacts:  1,
+		Entities:           3,
+		OrphanEntities:     1,
+		// 100 * (1 - (0.4*2/4 + 0.2*1/4 + 0.2*1/4 + 0.2*1/3))
+		Score: 63.3,
+	}, *sample)
+	assert.Empty(t, relationalDB.HealthSamples, "measuring does not record")
+}
+
+func TestHealthService_Measure_Options(t *testing.T) {
+	svc, _ := newHealthTestService()
+
+	sample, err := svc.Measure(context.Background(), HealthOptions{
+		WorldID:       "w",
+		LowConfidence: 0.99,
+		StaleAfter:    time.Hour,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, sample.LowConfidenceFacts)
+	assert.Equal(t, 3, sample.StaleFacts, "facts without timestamps are never stale")
+}
+
+func TestHealthService_RecordAndHistory(t *testing.T) {
+	svc, relationalDB := newHealthTestService()
+	ctx := context.Background()
+
+	for range 3 {
+		_, err := svc.Record(ctx, HealthOptions{WorldID: "w"})
+		require.NoError(t, err)
+	}
+	require.Len(t, relationalDB.HealthSamples, 3)
+
+	history, err := svc.History(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, int64(2), history[0].ID, "oldest of the last two first")
+	assert.Equal(t, int64(3), history[1].ID)
+}
+
+func TestHealthScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample entities.HealthSample
+		want   float64
+	}{
+		{name: "empty world", want: 100},
+		{name: "clean world", sample: entities.HealthSample{Facts: 10, Entities: 4}, want: 100},
+		{
+			name: "every problem everywhere",
+			sample: entities.HealthSample{
+				Facts: 10, ConflictingFacts: 10, LowConfidenceFacts: 10, StaleFacts: 10,
+				Entities: 4, OrphanEntities: 4,
+			},
+			want: 0,
+		},
+		{name: "half the facts conflicting", sample: entities.HealthSample{Facts: 10, ConflictingFacts: 5}, want: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, healthScore(&tt.sample), 0.001)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultIdempotencyTTL is how long an idempotency key is remembered. A key
+// used again after that runs the operation again.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyService remembers the outcome of operations run with an
+// idempotency key, so retried commands and requests don't repeat them.
+type IdempotencyService struct {
+	relationalDB ports.RelationalDB
+	ttl          time.Duration
+	now          func() time.Time
+}
+
+// NewIdempotencyService creates a new IdempotencyService remembering keys
+// for ttl (0 = DefaultIdempotencyTTL).
+func NewIdempotencyService(relationalDB ports.RelationalDB, ttl time.Duration) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &IdempotencyService{relationalDB: relationalDB, ttl: ttl, now: time.Now}
+}
+
+// idempotencyClaimAttempts bounds how often Idempotent tries to claim a
+// key whose record disappears between the claim and reading it, as when
+// the operation holding it fails and releases it.
+const idempotencyClaimAttempts = 3
+
+// Idempotent runs fn and records its result under key, unless the key
+// already recorded the outcome of the same operation and request, in which
+// case that result is returned with replayed set and fn is not run. The key
+// is claimed before fn runs, so of concurrent calls with the same key only
+// one runs fn; the others fail with entities.ErrConflict until it is done.
+// A key reused for a different operation or request is also an
+// entities.ErrConflict error. Failures are not recorded, so retrying them
+// runs fn again. An empty key always runs fn.
+//
+// A claim left pending by a process that stopped while fn ran holds the
+// key until it expires.
+func Idempotent[T any](ctx context.Context, s *IdempotencyService, key, operation string, request any, fn func() (T, error)) (result T, replayed bool, err error) {
+	if key == "" || s == nil {
+		result, err = fn()
+		return result, false, err
+	}
+
+	hash, err := requestHash(request)
+	if err != nil {
+		return result, false, err
+	}
+	record, err := s.claim(ctx, key, operation, hash)
+	if err != nil {
+		return result, false, err
+	}
+	if record != nil {
+		if err := json.Unmarshal(record.Result, &result); err != nil {
+			return result, false, fmt.Errorf("decoding result recorded for idempotency key %q: %w", key, err)
+		}
+		return result, true, nil
+	}
+
+	// Whatever fn does, the claim must be settled, even if the caller has
+	// gone.
+	settleCtx := context.WithoutCancel(ctx)
+	result, err = fn()
+	if err != nil {
+		if releaseErr := s.relationalDB.ReleaseIdempotencyKey(settleCtx, key); releaseErr != nil {
+			return result, false, errors.Join(err, fmt.Errorf("releasing idempotency key %q: %w", key, releaseErr))
+		}
+		return result, false, err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result, false, fmt.Errorf("encoding result for idempotency key %q: %w", key, err)
+	}
+	if err := s.relationalDB.SaveIdempotencyRecord(settleCtx, &entities.IdempotencyRecord{
+		Key:         key,
+		Operation:   operation,
+		RequestHash: hash,
+		Result:      data,
+		CreatedAt:   s.now(),
+	}); err != nil {
+		return result, false, fmt.Errorf("recording idempotency key %q: %w", key, err)
+	}
+	return result, false, nil
+}
+
+// claim claims key for an operation about to run, returning nil, or
+// returns the completed record of the same operation and request to
+// replay. A key held by a different request or by one still running is an
+// entities.ErrConflict error.
+func (s *IdempotencyService) claim(ctx context.Context, key, operation, hash string) (*entities.IdempotencyRecord, error) {
+	for range idempotencyClaimAttempts {
+		now := s.now()
+		claimed, err := s.relationalDB.ClaimIdempotencyKey(ctx, &entities.IdempotencyRecord{
+			Key:         key,
+			Operation:   operation,
+			RequestHash: hash,
+			Pending:     true,
+			CreatedAt:   now,
+		}, now.Add(-s.ttl))
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		record, err := s.relationalDB.FindIdempotencyRecord(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+		if record.Operation != operation || record.RequestHash != hash {
+			return nil, entities.Errorf(entities.ErrConflict,
+				"idempotency key %q was already used for a different %s request", key, record.Operation)
+		}
+		if record.Pending {
+			return nil, entities.Errorf(entities.ErrConflict,
+				"idempotency key %q is in use by a %s request still running; retry once it is done", key, operation)
+		}
+		return record, nil
+	}
+	return nil, entities.Errorf(entities.ErrConflict, "idempotency key %q is in use; retry later", key)
+}
+
+// requestHash fingerprints a request by its JSON encoding.
+func requestHash(request any) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("encoding request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
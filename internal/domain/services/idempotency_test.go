@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestIdempotent(t *testing.T) {
+	db := mocks.NewRelationalDB()
+	svc := NewIdempotencyService(db, time.Hour)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	runs := 0
+	create := func() (*entities.Relationship, error) {
+		runs++
+		return &entities.Relationship{ID: fmt.Sprintf("rel-%d", runs), Type: entities.RelationAlly}, nil
+	}
+	request := map[string]any{"source": "Frodo", "target": "Sam"}
+
+	rel, replayed, err := Idempotent(ctx, svc, "key-1", "relate", request, create)
+	require.NoError(t, err)
+	assert.False(t, replayed)
+	assert.Equal(t, "rel-1", rel.ID)
+
+	rel, replayed, err = Idempotent(ctx, svc, "key-1", "relate", request, create)
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, "rel-1", rel.ID, "the first run's result")
+	assert.Equal(t, 1, runs)
+
+	_, _, err = Idempotent(ctx, svc, "key-1", "relate", map[string]any{"source": "Frodo", "target": "Gollum"}, create)
+	require.ErrorIs(t, err, entities.ErrConflict, "a different request")
+	_, _, err = Idempotent(ctx, svc, "key-1", "import", request, create)
+	require.ErrorIs(t, err, entities.ErrConflict, "a different operation")
+	assert.Equal(t, 1, runs)
+
+	_, replayed, err = Idempotent(ctx, svc, "", "relate", request, create)
+	require.NoError(t, err)
+	assert.False(t, replayed, "no key")
+	assert.Equal(t, 2, runs)
+
+	now = now.Add(time.Hour)
+	rel, replayed, err = Idempotent(ctx, svc, "key-1", "relate", request, create)
+	require.NoError(t, err)
+	assert.False(t, replayed, "expired keys run again")
+	assert.Equal(t, "rel-3", rel.ID)
+}
+
+func TestIdempotent_FailuresAreNotRecorded(t *testing.T) {
+	db := mocks.NewRelationalDB()
+	svc := NewIdempotencyService(db, 0)
+	ctx := context.Background()
+
+	_, _, err := Idempotent(ctx, svc, "key-1", "ingest", "chapter1.md", func() (int, error) {
+		return 0, errors.New("LLM unavailable")
+	})
+	require.Error(t, err)
+	assert.Empty(t, db.Idempotency)
+
+	facts, replayed, err := Idempotent(ctx, svc, "key-1", "ingest", "chapter1.md", func() (int, error) {
+		return 12, nil
+	})
+	require.NoError(t, err)
+	assert.False(t, replayed, "the retry runs")
+	assert.Equal(t, 12, facts)
+}
+
+func TestIdempotent_RejectsKeysInFlight(t *testing.T) {
+	db := mocks.NewRelationalDB()
+	svc := NewIdempotencyService(db, 0)
+	ctx := context.Background()
+
+	var inFlightErr error
+	facts, _, err := Idempotent(ctx, svc, "key-1", "ingest", "chapter1.md", func() (int, error) {
+		assert.True(t, db.Idempotency["key-1"].Pending, "claimed before running")
+		_, _, inFlightErr = Idempotent(ctx, svc, "key-1", "ingest", "chapter1.md", func() (int, error) {
+			t.Fatal("ran while the first run was in flight")
+			return 0, nil
+		})
+		return 12, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12, facts)
+	require.ErrorIs(t, inFlightErr, entities.ErrConflict)
+	assert.Contains(t, inFlightErr.Error(), "still running")
+
+	assert.False(t, db.Idempotency["key-1"].Pending, "completed")
+	facts, replayed, err := Idempotent(ctx, svc, "key-1", "ingest", "chapter1.md", func() (int, error) {
+		return 0, errors.New("ran again")
+	})
+	require.NoError(t, err)
+	assert.True(t, replayed)
+	assert.Equal(t, 12, facts)
+}
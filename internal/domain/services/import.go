@@ -10,7 +10,6 @@ import (
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
-	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
 )
 
 // ConflictStrategy defines how to handle existing facts during import.
@@ -21,12 +20,23 @@ const (
 	ConflictSkip ConflictStrategy = "skip"
 	// ConflictOverwrite overwrites existing facts with new data.
 	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictMerge merges incoming fields into existing facts and records
+	// a version entry for each merged fact.
+	ConflictMerge ConflictStrategy = "merge"
+)
+
+// Version reasons recorded for facts merged on import.
+const (
+	mergeReason    = "merged on import"
+	preMergeReason = "recorded before import merge"
 )
 
 // ImportOptions controls import behavior.
 type ImportOptions struct {
-	DryRun     bool             // Validate without saving
-	OnConflict ConflictStrategy // How to handle existing facts
+	DryRun       bool             // Validate without saving
+	OnConflict   ConflictStrategy // How to handle existing facts
+	Rules        []ImportRule     // Extra validation rules, applied in order
+	CheckSimilar bool             // Report existing facts similar to imported ones
 }
 
 // ImportError represents an error for a specific fact during import.
@@ -48,31 +58,53 @@ func (e ImportError) Error() string {
 type ImportResult struct {
 	Imported int
 	Skipped  int
+	Merged   int // Imported facts merged into existing ones
 	Errors   []ImportError
+	Similar  []SimilarImport // Likely duplicates, when CheckSimilar is set
+
+	// Document imports only
+	Entities      int // Entities created
+	Relationships int // Relationships created
+}
+
+// SimilarImport lists existing facts that closely match an imported fact.
+type SimilarImport struct {
+	Line    int // Line number of the imported fact (0 if unknown)
+	Fact    entities.Fact
+	Matches []SimilarFact
 }
 
 // ImportService handles importing facts from external sources.
 type ImportService struct {
 	embedder          ports.Embedder
 	vectorDB          ports.VectorDB
+	relationalDB      ports.RelationalDB
 	entityTypeService *EntityTypeService
+	relationships     *RelationshipService
 }
 
 // NewImportService creates a new import service.
-func NewImportService(embedder ports.Embedder, vectorDB ports.VectorDB, entityTypeService *EntityTypeService) *ImportService {
+func NewImportService(
+	embedder ports.Embedder,
+	vectorDB ports.VectorDB,
+	relationalDB ports.RelationalDB,
+	entityTypeService *EntityTypeService,
+) *ImportService {
 	return &ImportService{
 		embedder:          embedder,
 		vectorDB:          vectorDB,
+		relationalDB:      relationalDB,
 		entityTypeService: entityTypeService,
+		relationships:     NewRelationshipService(vectorDB, relationalDB, embedder),
 	}
 }
 
 // Import validates and imports raw facts into the database.
-func (s *ImportService) Import(ctx context.Context, rawFacts []parsers.RawFact, opts ImportOptions) (*ImportResult, error) {
+func (s *ImportService) Import(ctx context.Context, rawFacts []entities.RawFact, opts ImportOptions) (*ImportResult, error) {
 	result := &ImportResult{}
 
 	// Validate all facts first
-	validFacts, validationErrors := s.validateFacts(ctx, rawFacts)
+	validFacts, validationErrors := s.validateFacts(ctx, rawFacts, opts.Rules)
 	result.Errors = validationErrors
 
 	if len(validFacts) == 0 {
@@ -82,11 +114,29 @@ func (s *ImportService) Import(ctx context.Context, rawFacts []parsers.RawFact,
 	// Convert to domain entities
 	facts := s.convertToEntities(validFacts)
 
+	// Merge before embedding so embeddings reflect the merged fields
+	var merged []mergedFact
+	if opts.OnConflict == ConflictMerge {
+		var err error
+		if merged, err = s.mergeExisting(ctx, facts); err != nil {
+			return nil, err
+		}
+		result.Merged = len(merged)
+	}
+
 	// Generate embeddings
 	if err := s.generateEmbeddings(ctx, facts); err != nil {
 		return nil, fmt.Errorf("generating embeddings: %w", err)
 	}
 
+	if opts.CheckSimilar {
+		similar, err := s.findSimilar(ctx, facts, validFacts)
+		if err != nil {
+			return nil, err
+		}
+		result.Similar = similar
+	}
+
 	// Handle dry run
 	if opts.DryRun {
 		result.Imported = len(facts)
@@ -102,22 +152,24 @@ func (s *ImportService) Import(ctx context.Context, rawFacts []parsers.RawFact,
 	result.Imported = imported
 	result.Skipped = skipped
 
+	if err := s.recordMergeVersions(ctx, facts, merged); err != nil {
+		return nil, fmt.Errorf("recording versions: %w", err)
+	}
+
 	return result, nil
 }
 
-// validateFacts validates raw facts and returns valid ones with any errors.
-func (s *ImportService) validateFacts(ctx context.Context, rawFacts []parsers.RawFact) ([]parsers.RawFact, []ImportError) {
+// validateFacts validates raw facts against the basic field checks, the
+// entity type rule, and any extra rules, returning valid facts and errors.
+func (s *ImportService) validateFacts(ctx context.Context, rawFacts []entities.RawFact, extraRules []ImportRule) ([]entities.RawFact, []ImportError) {
 	// Get valid types once for all validations
 	validTypes, err := s.entityTypeService.GetValidTypes(ctx)
 	if err != nil {
 		return nil, []ImportError{{Message: fmt.Sprintf("failed to get valid types: %v", err)}}
 	}
-	validTypeSet := make(map[string]bool, len(validTypes))
-	for _, t := range validTypes {
-		validTypeSet[t] = true
-	}
+	rules := append([]ImportRule{NewEntityTypeRule(validTypes)}, extraRules...)
 
-	valid := make([]parsers.RawFact, 0, len(rawFacts))
+	valid := make([]entities.RawFact, 0, len(rawFacts))
 	var errors []ImportError
 
 	for i := range rawFacts {
@@ -127,7 +179,7 @@ func (s *ImportService) validateFacts(ctx context.Context, rawFacts []parsers.Ra
 			lineNum = i + 1
 		}
 
-		if err := s.validateRawFact(raw, lineNum, validTypeSet, validTypes); err != nil {
+		if err := s.validateRawFact(ctx, raw, lineNum, rules); err != nil {
 			errors = append(errors, *err)
 			continue
 		}
@@ -139,7 +191,7 @@ func (s *ImportService) validateFacts(ctx context.Context, rawFacts []parsers.Ra
 }
 
 // validateRawFact validates a single raw fact and returns an error if invalid.
-func (s *ImportService) validateRawFact(raw *parsers.RawFact, lineNum int, validTypeSet map[string]bool, validTypes []string) *ImportError {
+func (s *ImportService) validateRawFact(ctx context.Context, raw *entities.RawFact, lineNum int, rules []ImportRule) *ImportError {
 	if raw.Type == "" {
 		return &ImportError{Line: lineNum, Field: "type", Message: "missing required field: type"}
 	}
@@ -153,16 +205,6 @@ func (s *ImportService) validateRawFact(raw *parsers.RawFact, lineNum int, valid
 		return &ImportError{Line: lineNum, Field: "object", Message: "missing required field: object"}
 	}
 
-	// Validate type against pre-fetched valid types
-	if !validTypeSet[raw.Type] {
-		return &ImportError{
-			Line:    lineNum,
-			Field:   "type",
-			Value:   raw.Type,
-			Message: fmt.Sprintf("invalid type %q (valid: %s)", raw.Type, strings.Join(validTypes, ", ")),
-		}
-	}
-
 	if raw.Confidence != nil && (*raw.Confidence < 0 || *raw.Confidence > 1) {
 		return &ImportError{
 			Line:    lineNum,
@@ -172,11 +214,18 @@ func (s *ImportService) validateRawFact(raw *parsers.RawFact, lineNum int, valid
 		}
 	}
 
+	for _, rule := range rules {
+		if err := rule.Check(ctx, raw); err != nil {
+			err.Line = lineNum
+			return err
+		}
+	}
+
 	return nil
 }
 
 // convertToEntities converts raw facts to domain entities.
-func (s *ImportService) convertToEntities(rawFacts []parsers.RawFact) []entities.Fact {
+func (s *ImportService) convertToEntities(rawFacts []entities.RawFact) []entities.Fact {
 	facts := make([]entities.Fact, 0, len(rawFacts))
 	now := time.Now()
 
@@ -209,30 +258,41 @@ func (s *ImportService) convertToEntities(rawFacts []parsers.RawFact) []entities
 		facts = append(facts, fact)
 	}
 
+	detectObjectTypes(facts)
 	return facts
 }
 
 // generateEmbeddings generates embeddings for all facts.
 func (s *ImportService) generateEmbeddings(ctx context.Context, facts []entities.Fact) error {
-	texts := make([]string, len(facts))
-	for i := range facts {
-		texts[i] = factToText(&facts[i])
-	}
-
-	embeddings, err := s.embedder.EmbedBatch(ctx, texts)
-	if err != nil {
-		return err
-	}
+	return embedFacts(ctx, s.embedder, facts)
+}
 
+// findSimilar looks up likely duplicates of embedded facts. rawFacts are
+// the facts' sources, in the same order, for line numbers.
+func (s *ImportService) findSimilar(ctx context.Context, facts []entities.Fact, rawFacts []entities.RawFact) ([]SimilarImport, error) {
+	var similar []SimilarImport
 	for i := range facts {
-		facts[i].Embedding = embeddings[i]
+		matches, err := findSimilarFacts(ctx, s.vectorDB, &facts[i], DefaultSimilarityThreshold)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 0 {
+			similar = append(similar, SimilarImport{Line: rawFacts[i].LineNum, Fact: facts[i], Matches: matches})
+		}
 	}
-
-	return nil
+	return similar, nil
 }
 
 // saveWithConflictHandling saves facts with conflict handling.
 func (s *ImportService) saveWithConflictHandling(ctx context.Context, facts []entities.Fact, onConflict ConflictStrategy) (imported, skipped int, err error) {
+	if onConflict == ConflictMerge {
+		// Merge mode: facts were already merged with their existing versions
+		if err := s.vectorDB.SaveBatch(ctx, facts); err != nil {
+			return 0, 0, err
+		}
+		return len(facts), 0, nil
+	}
+
 	if onConflict != ConflictSkip {
 		// Overwrite mode: preserve CreatedAt for existing facts
 		if err := s.preserveCreatedAt(ctx, facts); err != nil {
@@ -333,3 +393,111 @@ func (s *ImportService) filterExisting(ctx context.Context, facts []entities.Fac
 
 	return toSave, skipped, nil
 }
+
+// mergedFact pairs the index of a merged import fact with the existing
+// fact it was merged into.
+type mergedFact struct {
+	index    int
+	existing entities.Fact
+}
+
+// mergeExisting merges each fact into the existing fact with the same ID,
+// in place, and returns the facts that were merged.
+func (s *ImportService) mergeExisting(ctx context.Context, facts []entities.Fact) ([]mergedFact, error) {
+	ids := make([]string, len(facts))
+	for i := range facts {
+		ids[i] = facts[i].ID
+	}
+
+	existingFacts, err := s.vectorDB.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("looking up existing facts: %w", err)
+	}
+	existingByID := make(map[string]entities.Fact, len(existingFacts))
+	for i := range existingFacts {
+		existingByID[existingFacts[i].ID] = existingFacts[i]
+	}
+
+	var merged []mergedFact
+	for i := range facts {
+		existing, ok := existingByID[facts[i].ID]
+		if !ok {
+			continue
+		}
+		facts[i] = mergeFacts(&existing, &facts[i])
+		merged = append(merged, mergedFact{index: i, existing: existing})
+	}
+	return merged, nil
+}
+
+// mergeFacts merges incoming fields into an existing fact. Non-empty
+// incoming fields win, confidence takes the maximum, and distinct contexts
+// are concatenated. The existing CreatedAt is kept.
+func mergeFacts(existing, incoming *entities.Fact) entities.Fact {
+	merged := *existing
+	merged.Type = entities.FactType(firstNonEmpty(string(incoming.Type), string(existing.Type)))
+	merged.Subject = firstNonEmpty(incoming.Subject, existing.Subject)
+	merged.Predicate = firstNonEmpty(incoming.Predicate, existing.Predicate)
+	merged.Object = firstNonEmpty(incoming.Object, existing.Object)
+	merged.SourceFile = firstNonEmpty(incoming.SourceFile, existing.SourceFile)
+	merged.Confidence = max(existing.Confidence, incoming.Confidence)
+	merged.Context = mergeContexts(existing.Context, incoming.Context)
+	merged.UpdatedAt = incoming.UpdatedAt
+	merged.Embedding = nil
+	merged.TextEmbedding = nil
+	return merged
+}
+
+// mergeContexts concatenates two contexts, skipping an incoming context
+// the existing one already contains.
+func mergeContexts(existing, incoming string) string {
+	switch {
+	case incoming == "" || strings.Contains(existing, incoming):
+		return existing
+	case existing == "":
+		return incoming
+	default:
+		return existing + "\n" + incoming
+	}
+}
+
+func firstNonEmpty(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
+
+// recordMergeVersions saves a version entry for each merged fact. Facts
+// without history first get a creation version holding the pre-merge state.
+func (s *ImportService) recordMergeVersions(ctx context.Context, facts []entities.Fact, merged []mergedFact) error {
+	for i := range merged {
+		m := &merged[i]
+		fact := &facts[m.index]
+
+		latest, err := s.relationalDB.FindLatestVersion(ctx, fact.ID)
+		if err != nil {
+			return fmt.Errorf("finding latest version of %s: %w", fact.ID, err)
+		}
+
+		next := 1
+		if latest != nil {
+			next = latest.Version + 1
+		} else {
+			if err := s.saveVersion(ctx, &m.existing, next, entities.ChangeCreation, preMergeReason); err != nil {
+				return err
+			}
+			next++
+		}
+
+		if err := s.saveVersion(ctx, fact, next, entities.ChangeUpdate, mergeReason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveVersion stores a snapshot of fact without its embeddings.
+func (s *ImportService) saveVersion(ctx context.Context, fact *entities.Fact, version int, change entities.ChangeType, reason string) error {
+	return saveFactVersion(ctx, s.relationalDB, fact, version, change, reason)
+}
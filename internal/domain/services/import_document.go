@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// ImportDocument imports a document's entities, facts, and relationships
+// into a world, in that order. Relationships must reference entities that
+// are declared in the document or already exist in the world; those that
+// do not are reported as errors and skipped. Relationships that already
+// exist are counted as skipped.
+//
+// In a dry run nothing is created, so rules that look up existing entities
+// do not see the entities the document declares.
+func (s *ImportService) ImportDocument(ctx context.Context, worldID string, doc *entities.ImportDocument, opts ImportOptions) (*ImportResult, error) {
+	validEntities, declared, entityErrors := validateEntities(doc.Entities)
+	relationships, relErrors, err := s.validateRelationships(ctx, worldID, doc.Relationships, declared)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{}
+	result.Errors = append(result.Errors, entityErrors...)
+
+	created, err := s.importEntities(ctx, worldID, validEntities, opts.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("importing entities: %w", err)
+	}
+	result.Entities = created
+
+	if len(doc.Facts) > 0 {
+		factResult, err := s.Import(ctx, doc.Facts, opts)
+		if err != nil {
+			return nil, err
+		}
+		result.Imported = factResult.Imported
+		result.Skipped = factResult.Skipped
+		result.Merged = factResult.Merged
+		result.Errors = append(result.Errors, factResult.Errors...)
+		result.Similar = factResult.Similar
+	}
+
+	result.Errors = append(result.Errors, relErrors...)
+	createdRels, skippedRels, err := s.importRelationships(ctx, worldID, relationships, opts.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("importing relationships: %w", err)
+	}
+	result.Relationships = createdRels
+	result.Skipped += skippedRels
+
+	return result, nil
+}
+
+// validateEntities checks declared entities and returns the valid ones,
+// in order and keyed by normalized name.
+func validateEntities(rawEntities []entities.RawEntity) ([]entities.RawEntity, map[string]entities.RawEntity, []ImportError) {
+	valid := make([]entities.RawEntity, 0, len(rawEntities))
+	declared := make(map[string]entities.RawEntity, len(rawEntities))
+	var errors []ImportError
+
+	for _, raw := range rawEntities {
+		name := entities.NormalizeName(raw.Name)
+		if name == "" {
+			errors = append(errors, ImportError{Line: raw.LineNum, Field: "name", Message: "missing required field: entity name"})
+			continue
+		}
+		if first, ok := declared[name]; ok {
+			errors = append(errors, ImportError{
+				Line:    raw.LineNum,
+				Field:   "name",
+				Value:   raw.Name,
+				Message: fmt.Sprintf("entity %q is already declared on line %d", raw.Name, first.LineNum),
+			})
+			continue
+		}
+		declared[name] = raw
+		valid = append(valid, raw)
+	}
+
+	return valid, declared, errors
+}
+
+// validateRelationships checks relationship fields and that both ends
+// reference a declared or existing entity.
+func (s *ImportService) validateRelationships(
+	ctx context.Context,
+	worldID string,
+	rawRels []entities.RawRelationship,
+	declared map[string]entities.RawEntity,
+) ([]entities.RawRelationship, []ImportError, error) {
+	valid := make([]entities.RawRelationship, 0, len(rawRels))
+	var errors []ImportError
+
+	for _, raw := range rawRels {
+		if importErr := validateRawRelationship(raw); importErr != nil {
+			errors = append(errors, *importErr)
+			continue
+		}
+
+		importErr, err := s.checkEndpoints(ctx, worldID, raw, declared)
+		if err != nil {
+			return nil, nil, err
+		}
+		if importErr != nil {
+			errors = append(errors, *importErr)
+			continue
+		}
+
+		valid = append(valid, raw)
+	}
+
+	return valid, errors, nil
+}
+
+// validateRawRelationship checks a relationship's required fields and type.
+func validateRawRelationship(raw entities.RawRelationship) *ImportError {
+	if raw.Source == "" {
+		return &ImportError{Line: raw.LineNum, Field: "source", Message: "missing required field: relationship source"}
+	}
+	if raw.Target == "" {
+		return &ImportError{Line: raw.LineNum, Field: "target", Message: "missing required field: relationship target"}
+	}
+	if raw.Type == "" {
+		return &ImportError{Line: raw.LineNum, Field: "type", Message: "missing required field: relationship type"}
+	}
+	if !entities.RelationType(raw.Type).IsValid() {
+		return &ImportError{
+			Line:    raw.LineNum,
+			Field:   "type",
+			Value:   raw.Type,
+			Message: fmt.Sprintf("invalid relationship type %q", raw.Type),
+		}
+	}
+	return nil
+}
+
+// checkEndpoints reports an import error for each relationship end that
+// is neither declared in the document nor an existing entity.
+func (s *ImportService) checkEndpoints(
+	ctx context.Context,
+	worldID string,
+	raw entities.RawRelationship,
+	declared map[string]entities.RawEntity,
+) (*ImportError, error) {
+	endpoints := []struct{ field, name string }{
+		{"source", raw.Source},
+		{"target", raw.Target},
+	}
+
+	for _, end := range endpoints {
+		if _, ok := declared[entities.NormalizeName(end.name)]; ok {
+			continue
+		}
+		existing, err := s.relationalDB.FindEntityByName(ctx, worldID, end.name)
+		if err != nil {
+			return nil, fmt.Errorf("looking up entity %q: %w", end.name, err)
+		}
+		if existing == nil {
+			return &ImportError{
+				Line:    raw.LineNum,
+				Field:   end.field,
+				Value:   end.name,
+				Message: fmt.Sprintf("relationship %s %q is not a declared or existing entity", end.field, end.name),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// importEntities creates declared entities that do not exist yet and
+// returns how many were (or, in a dry run, would be) created.
+func (s *ImportService) importEntities(ctx context.Context, worldID string, rawEntities []entities.RawEntity, dryRun bool) (int, error) {
+	var created int
+	for _, raw := range rawEntities {
+		existing, err := s.relationalDB.FindEntityByName(ctx, worldID, raw.Name)
+		if err != nil {
+			return 0, fmt.Errorf("looking up entity %q: %w", raw.Name, err)
+		}
+		if existing != nil {
+			continue
+		}
+
+		created++
+		if dryRun {
+			continue
+		}
+		if _, err := s.relationalDB.FindOrCreateEntity(ctx, worldID, raw.Name); err != nil {
+			return 0, fmt.Errorf("creating entity %q: %w", raw.Name, err)
+		}
+	}
+	return created, nil
+}
+
+// importRelationships creates relationships that do not exist yet and
+// returns how many were (or would be) created and how many already existed.
+func (s *ImportService) importRelationships(ctx context.Context, worldID string, rels []entities.RawRelationship, dryRun bool) (created, skipped int, err error) {
+	for _, raw := range rels {
+		exists, err := s.relationshipExists(ctx, worldID, raw)
+		if err != nil {
+			return 0, 0, err
+		}
+		if exists {
+			skipped++
+			continue
+		}
+
+		created++
+		if dryRun {
+			continue
+		}
+		_, err = s.relationships.Create(ctx, worldID, raw.Source, entities.RelationType(raw.Type), raw.Target, raw.Bidirectional)
+		if err != nil {
+			return 0, 0, fmt.Errorf("line %d: creating relationship: %w", raw.LineNum, err)
+		}
+	}
+	return created, skipped, nil
+}
+
+// relationshipExists reports whether both ends exist and are already related.
+func (s *ImportService) relationshipExists(ctx context.Context, worldID string, raw entities.RawRelationship) (bool, error) {
+	source, err := s.relationalDB.FindEntityByName(ctx, worldID, raw.Source)
+	if err != nil {
+		return false, fmt.Errorf("looking up entity %q: %w", raw.Source, err)
+	}
+	target, err := s.relationalDB.FindEntityByName(ctx, worldID, raw.Target)
+	if err != nil {
+		return false, fmt.Errorf("looking up entity %q: %w", raw.Target, err)
+	}
+	if source == nil || target == nil {
+		return false, nil
+	}
+
+	existing, err := s.relationalDB.FindRelationshipBetween(ctx, source.ID, target.ID)
+	if err != nil {
+		return false, fmt.Errorf("checking existing relationship: %w", err)
+	}
+	return existing != nil, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// ImportRule validates a raw fact that has passed the basic field checks.
+// Check returns nil when the fact passes. The import service fills in the
+// line number of any error returned.
+type ImportRule interface {
+	Check(ctx context.Context, raw *entities.RawFact) *ImportError
+}
+
+// ImportRuleFunc adapts an ordinary function to an ImportRule.
+type ImportRuleFunc func(ctx context.Context, raw *entities.RawFact) *ImportError
+
+// Check calls f(ctx, raw).
+func (f ImportRuleFunc) Check(ctx context.Context, raw *entities.RawFact) *ImportError {
+	return f(ctx, raw)
+}
+
+// NewEntityTypeRule rejects facts whose type is not one of validTypes.
+// The import service always applies it before any configured rules.
+func NewEntityTypeRule(validTypes []string) ImportRule {
+	valid := make(map[string]bool, len(validTypes))
+	for _, t := range validTypes {
+		valid[t] = true
+	}
+
+	return ImportRuleFunc(func(_ context.Context, raw *entities.RawFact) *ImportError {
+		if valid[raw.Type] {
+			return nil
+		}
+		return &ImportError{
+			Field:   "type",
+			Value:   raw.Type,
+			Message: fmt.Sprintf("invalid type %q (valid: %s)", raw.Type, strings.Join(validTypes, ", ")),
+		}
+	})
+}
+
+// NewPredicateRule rejects facts whose predicate is not in the vocabulary.
+// Matching is case-insensitive. An empty vocabulary allows any predicate.
+func NewPredicateRule(vocabulary []string) ImportRule {
+	allowed := make(map[string]bool, len(vocabulary))
+	for _, p := range vocabulary {
+		allowed[strings.ToLower(strings.TrimSpace(p))] = true
+	}
+
+	return ImportRuleFunc(func(_ context.Context, raw *entities.RawFact) *ImportError {
+		if len(allowed) == 0 || allowed[strings.ToLower(strings.TrimSpace(raw.Predicate))] {
+			return nil
+		}
+		return &ImportError{
+			Field:   "predicate",
+			Value:   raw.Predicate,
+			Message: fmt.Sprintf("predicate %q is not in the vocabulary (valid: %s)", raw.Predicate, strings.Join(vocabulary, ", ")),
+		}
+	})
+}
+
+// NewKnownSubjectRule rejects facts whose subject does not match an
+// existing entity in the world.
+func NewKnownSubjectRule(db ports.RelationalDB, worldID string) ImportRule {
+	return ImportRuleFunc(func(ctx context.Context, raw *entities.RawFact) *ImportError {
+		entity, err := db.FindEntityByName(ctx, worldID, raw.Subject)
+		if err != nil {
+			return &ImportError{
+				Field:   "subject",
+				Value:   raw.Subject,
+				Message: fmt.Sprintf("looking up subject %q: %v", raw.Subject, err),
+			}
+		}
+		if entity != nil {
+			return nil
+		}
+		return &ImportError{
+			Field:   "subject",
+			Value:   raw.Subject,
+			Message: fmt.Sprintf("subject %q does not match an existing entity", raw.Subject),
+		}
+	})
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/mocks"
-	"github.com/ersonp/lore-core/internal/infrastructure/parsers"
 )
 
 // newTestEntityTypeService creates an EntityTypeService with default types for testing.
@@ -25,8 +24,8 @@ func TestImportService_Import_ValidFacts(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is a", Object: "wizard"},
 	}
 
@@ -43,8 +42,8 @@ func TestImportService_Import_ValidationErrors(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 		{Type: "character", Subject: "", Predicate: "is", Object: "wizard"},
 	}
@@ -62,8 +61,8 @@ func TestImportService_Import_InvalidFactType(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "invalid_type", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 	}
 
@@ -80,9 +79,9 @@ func TestImportService_Import_InvalidConfidence(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	invalidConf := 1.5
-	rawFacts := []parsers.RawFact{
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard", Confidence: &invalidConf},
 	}
 
@@ -98,9 +97,9 @@ func TestImportService_Import_ConfidenceZero(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
 	zeroConf := 0.0
-	rawFacts := []parsers.RawFact{
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard", Confidence: &zeroConf},
 	}
 
@@ -119,8 +118,8 @@ func TestImportService_Import_ConfidenceUnset(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"}, // No confidence
 	}
 
@@ -138,8 +137,8 @@ func TestImportService_Import_DryRun(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 	}
 
@@ -150,14 +149,36 @@ func TestImportService_Import_DryRun(t *testing.T) {
 	assert.Zero(t, vectorDB.SaveBatchCallCount, "SaveBatch should not be called in dry run")
 }
 
+func TestImportService_Import_CheckSimilar(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "old", Type: entities.FactTypeCharacter, Subject: "Gandalf", Predicate: "is_a", Object: "wizard", TextEmbedding: []float32{0.1, 0.2, 0.3}},
+	}}
+
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
+		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard", LineNum: 4},
+		{Type: "location", Subject: "Bree", Predicate: "located_in", Object: "Eriador", LineNum: 9},
+	}
+
+	result, err := service.Import(context.Background(), rawFacts, ImportOptions{DryRun: true, CheckSimilar: true})
+
+	require.NoError(t, err)
+	require.Len(t, result.Similar, 1, "only facts of the same type are compared")
+	assert.Equal(t, 4, result.Similar[0].Line)
+	assert.Equal(t, "Gandalf", result.Similar[0].Fact.Subject)
+	require.Len(t, result.Similar[0].Matches, 1)
+	assert.Equal(t, "old", result.Similar[0].Matches[0].Fact.ID)
+}
+
 func TestImportService_Import_SkipExisting(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{
 		Facts: []entities.Fact{{ID: "existing-id"}},
 	}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{ID: "existing-id", Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 		{ID: "new-id", Type: "character", Subject: "Frodo", Predicate: "is", Object: "hobbit"},
 	}
@@ -178,8 +199,8 @@ func TestImportService_Import_OverwritePreservesCreatedAt(t *testing.T) {
 		},
 	}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{ID: "existing-id", Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 		{ID: "new-id", Type: "character", Subject: "Frodo", Predicate: "is", Object: "hobbit"},
 	}
@@ -204,12 +225,95 @@ func TestImportService_Import_OverwritePreservesCreatedAt(t *testing.T) {
 	assert.True(t, newFact.CreatedAt.After(originalTime), "new fact should have recent CreatedAt")
 }
 
+func TestImportService_Import_Merge(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	originalTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	vectorDB := &mocks.VectorDB{
+		Facts: []entities.Fact{{
+			ID:         "existing-id",
+			Type:       entities.FactTypeCharacter,
+			Subject:    "Gandalf",
+			Predicate:  "is",
+			Object:     "wizard",
+			Context:    "Chapter 1",
+			SourceFile: "fellowship.txt",
+			Confidence: 0.9,
+			CreatedAt:  originalTime,
+			UpdatedAt:  originalTime,
+		}},
+	}
+	relationalDB := mocks.NewRelationalDB()
+	lowConfidence := 0.5
+
+	service := NewImportService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
+		{ID: "existing-id", Type: "character", Subject: "Gandalf", Predicate: "is", Object: "Istar", Context: "Appendix B", Confidence: &lowConfidence},
+		{ID: "new-id", Type: "character", Subject: "Frodo", Predicate: "is", Object: "hobbit"},
+	}
+
+	result, err := service.Import(context.Background(), rawFacts, ImportOptions{OnConflict: ConflictMerge})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 1, result.Merged)
+
+	var merged entities.Fact
+	for _, f := range vectorDB.SaveBatchLastFacts {
+		if f.ID == "existing-id" {
+			merged = f
+		}
+	}
+	assert.Equal(t, "Istar", merged.Object)
+	assert.Equal(t, "fellowship.txt", merged.SourceFile)
+	assert.InDelta(t, 0.9, merged.Confidence, 1e-9)
+	assert.Equal(t, "Chapter 1\nAppendix B", merged.Context)
+	assert.Equal(t, originalTime, merged.CreatedAt)
+	assert.NotEmpty(t, merged.Embedding)
+
+	versions, err := relationalDB.FindVersionsByFact(context.Background(), "existing-id")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, entities.ChangeUpdate, versions[0].ChangeType)
+	assert.Equal(t, "Istar", versions[0].Data.Object)
+	assert.Equal(t, "wizard", versions[1].Data.Object)
+
+	newVersions, err := relationalDB.FindVersionsByFact(context.Background(), "new-id")
+	require.NoError(t, err)
+	assert.Empty(t, newVersions)
+}
+
+func TestImportService_Import_MergeDryRunRecordsNoVersions(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{{ID: "existing-id", Object: "wizard"}}}
+	relationalDB := mocks.NewRelationalDB()
+
+	service := NewImportService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
+		{ID: "existing-id", Type: "character", Subject: "Gandalf", Predicate: "is", Object: "Istar"},
+	}
+
+	result, err := service.Import(context.Background(), rawFacts, ImportOptions{DryRun: true, OnConflict: ConflictMerge})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Merged)
+	assert.Equal(t, 0, vectorDB.SaveBatchCallCount)
+	assert.Empty(t, relationalDB.Versions)
+}
+
+func TestMergeContexts(t *testing.T) {
+	assert.Equal(t, "a", mergeContexts("a", ""))
+	assert.Equal(t, "b", mergeContexts("", "b"))
+	assert.Equal(t, "a b", mergeContexts("a b", "b"))
+	assert.Equal(t, "a\nb", mergeContexts("a", "b"))
+}
+
 func TestImportService_Import_EmptyInput(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	result, err := service.Import(context.Background(), []parsers.RawFact{}, ImportOptions{})
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	result, err := service.Import(context.Background(), []entities.RawFact{}, ImportOptions{})
 
 	require.NoError(t, err)
 	assert.Equal(t, 0, result.Imported)
@@ -221,8 +325,8 @@ func TestImportService_Import_EmbeddingError(t *testing.T) {
 	embedder := &mocks.Embedder{Err: assert.AnError}
 	vectorDB := &mocks.VectorDB{}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 	}
 
@@ -236,8 +340,8 @@ func TestImportService_Import_SaveError(t *testing.T) {
 	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	vectorDB := &mocks.VectorDB{Err: assert.AnError}
 
-	service := NewImportService(embedder, vectorDB, newTestEntityTypeService())
-	rawFacts := []parsers.RawFact{
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
 		{Type: "character", Subject: "Gandalf", Predicate: "is", Object: "wizard"},
 	}
 
@@ -247,6 +351,72 @@ func TestImportService_Import_SaveError(t *testing.T) {
 	assert.Contains(t, err.Error(), "saving facts")
 }
 
+func TestImportService_ImportDocument(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+	_, err := relationalDB.FindOrCreateEntity(context.Background(), "middle-earth", "Gandalf")
+	require.NoError(t, err)
+
+	service := NewImportService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	doc := &entities.ImportDocument{
+		Entities: []entities.RawEntity{
+			{Name: "Frodo", LineNum: 2},
+			{Name: "The Shire", LineNum: 3},
+			{Name: "gandalf", LineNum: 4},
+			{Name: "frodo", LineNum: 5},
+		},
+		Facts: []entities.RawFact{
+			{Type: "character", Subject: "Frodo", Predicate: "is a", Object: "hobbit", LineNum: 7},
+		},
+		Relationships: []entities.RawRelationship{
+			{Source: "Frodo", Type: "located_in", Target: "The Shire", LineNum: 9},
+			{Source: "Gandalf", Type: "ally", Target: "frodo", LineNum: 10},
+			{Source: "Frodo", Type: "ally", Target: "Sauron", LineNum: 11},
+			{Source: "Frodo", Type: "rival", Target: "Gandalf", LineNum: 12},
+		},
+	}
+
+	result, err := service.ImportDocument(context.Background(), "middle-earth", doc, ImportOptions{OnConflict: ConflictOverwrite})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Entities)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.Relationships)
+
+	require.Len(t, result.Errors, 3)
+	assert.Equal(t, 5, result.Errors[0].Line)
+	assert.Contains(t, result.Errors[0].Message, "already declared on line 2")
+	assert.Equal(t, 11, result.Errors[1].Line)
+	assert.Equal(t, "target", result.Errors[1].Field)
+	assert.Equal(t, 12, result.Errors[2].Line)
+	assert.Equal(t, "type", result.Errors[2].Field)
+
+	frodo, err := relationalDB.FindEntityByName(context.Background(), "middle-earth", "frodo")
+	require.NoError(t, err)
+	assert.NotNil(t, frodo)
+}
+
+func TestImportService_ImportDocument_DryRun(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+
+	service := NewImportService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	doc := &entities.ImportDocument{
+		Entities:      []entities.RawEntity{{Name: "Frodo"}, {Name: "Sam"}},
+		Relationships: []entities.RawRelationship{{Source: "Frodo", Type: "ally", Target: "Sam"}},
+	}
+
+	result, err := service.ImportDocument(context.Background(), "middle-earth", doc, ImportOptions{DryRun: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Entities)
+	assert.Equal(t, 1, result.Relationships)
+	assert.Empty(t, relationalDB.Entities)
+	assert.Empty(t, vectorDB.Facts)
+}
+
 func TestImportError_Error(t *testing.T) {
 	t.Run("with line number", func(t *testing.T) {
 		err := ImportError{Line: 5, Message: "invalid type"}
@@ -258,3 +428,58 @@ func TestImportError_Error(t *testing.T) {
 		assert.Equal(t, "general error", err.Error())
 	})
 }
+
+func TestImportService_Import_PredicateRule(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{}
+
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
+		{Type: "character", Subject: "Gandalf", Predicate: "Wields", Object: "Glamdring", LineNum: 2},
+		{Type: "character", Subject: "Gandalf", Predicate: "likes", Object: "fireworks", LineNum: 3},
+	}
+	opts := ImportOptions{
+		OnConflict: ConflictOverwrite,
+		Rules:      []ImportRule{NewPredicateRule([]string{"wields", "lives_in"})},
+	}
+
+	result, err := service.Import(context.Background(), rawFacts, opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Line)
+	assert.Equal(t, "predicate", result.Errors[0].Field)
+	assert.Equal(t, "likes", result.Errors[0].Value)
+}
+
+func TestImportService_Import_KnownSubjectRule(t *testing.T) {
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	vectorDB := &mocks.VectorDB{}
+	relationalDB := mocks.NewRelationalDB()
+	_, err := relationalDB.FindOrCreateEntity(context.Background(), "middle-earth", "Gandalf")
+	require.NoError(t, err)
+
+	service := NewImportService(embedder, vectorDB, mocks.NewRelationalDB(), newTestEntityTypeService())
+	rawFacts := []entities.RawFact{
+		{Type: "character", Subject: "gandalf", Predicate: "is", Object: "wizard"},
+		{Type: "character", Subject: "Saruman", Predicate: "is", Object: "wizard"},
+	}
+	opts := ImportOptions{
+		OnConflict: ConflictOverwrite,
+		Rules:      []ImportRule{NewKnownSubjectRule(relationalDB, "middle-earth")},
+	}
+
+	result, err := service.Import(context.Background(), rawFacts, opts)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Imported)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 2, result.Errors[0].Line)
+	assert.Contains(t, result.Errors[0].Message, "does not match an existing entity")
+}
+
+func TestNewPredicateRule_EmptyVocabulary(t *testing.T) {
+	rule := NewPredicateRule(nil)
+	assert.Nil(t, rule.Check(context.Background(), &entities.RawFact{Predicate: "anything"}))
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// minLocatedContext is the shortest fact context searched for verbatim;
+// shorter contexts match too much to place a fact.
+const minLocatedContext = 12
+
+// LocateFact returns the byte offsets in text, its source, of where the
+// fact is stated: its source line if recorded, else its context if quoted
+// verbatim, else the first sentence naming both its subject and object,
+// else the first mention of its object or subject. Matching ignores case.
+// It reports false if none is found.
+func LocateFact(text string, fact *entities.Fact) (start, end int, ok bool) {
+	if fact.SourceLine > 0 {
+		return locateLine(text, fact.SourceLine)
+	}
+
+	if context := strings.TrimSpace(fact.Context); len(context) >= minLocatedContext {
+		if i := indexFold(text, context); i >= 0 {
+			return i, i + len(context), true
+		}
+	}
+
+	subject, object := strings.TrimSpace(fact.Subject), strings.TrimSpace(fact.Object)
+	if subject != "" && object != "" {
+		for _, s := range sentences(text) {
+			sentence := text[s[0]:s[1]]
+			if indexFold(sentence, subject) >= 0 && indexFold(sentence, object) >= 0 {
+				return s[0], s[1], true
+			}
+		}
+	}
+
+	for _, name := range []string{object, subject} {
+		if name == "" {
+			continue
+		}
+		if i := indexFold(text, name); i >= 0 {
+			return i, i + len(name), true
+		}
+	}
+	return 0, 0, false
+}
+
+// locateLine returns the offsets of a line of text, numbered from 1,
+// without its indentation or line ending.
+func locateLine(text string, line int) (int, int, bool) {
+	start := 0
+	for n := 1; n < line; n++ {
+		i := strings.IndexByte(text[start:], '\n')
+		if i < 0 {
+			return 0, 0, false
+		}
+		start += i + 1
+	}
+	end := len(text)
+	if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+	content := strings.TrimRight(text[start:end], " \t\r")
+	trimmed := strings.TrimLeft(content, " \t")
+	start += len(content) - len(trimmed)
+	return start, start + len(trimmed), true
+}
+
+// sentences returns the offsets of the sentences of text: runs ending at
+// sentence punctuation or a blank line, without surrounding space.
+func sentences(text string) [][2]int {
+	var result [][2]int
+	start := 0
+	add := func(end int) {
+		s := text[start:end]
+		trimmed := strings.TrimLeft(s, " \t\r\n")
+		from := start + len(s) - len(trimmed)
+		to := from + len(strings.TrimRight(trimmed, " \t\r\n"))
+		if to > from {
+			result = append(result, [2]int{from, to})
+		}
+		start = end
+	}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '.' || text[i] == '!' || text[i] == '?':
+			add(i + 1)
+		case strings.HasPrefix(text[i:], "\n\n"):
+			add(i)
+		}
+	}
+	add(len(text))
+	return result
+}
+
+// indexFold returns the byte offset of the first match of substr in s,
+// ignoring case, or -1 if there is none.
+func indexFold(s, substr string) int {
+	if substr == "" {
+		return -1
+	}
+	for i := 0; i+len(substr) <= len(s); {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return -1
+}
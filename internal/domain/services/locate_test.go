@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+const locateText = `Chapter One
+
+Frodo lived quietly in the Shire. Years later, frodo moved to Crickhollow!
+  Sam stayed behind.`
+
+func TestLocateFact(t *testing.T) {
+	tests := []struct {
+		name string
+		fact entities.Fact
+		want string
+		ok   bool
+	}{
+		{
+			name: "source line without indentation",
+			fact: entities.Fact{Subject: "Sam", SourceLine: 4},
+			want: "Sam stayed behind.",
+			ok:   true,
+		},
+		{
+			name: "context quoted verbatim",
+			fact: entities.Fact{Subject: "Frodo", Object: "the Shire", Context: "lived quietly in the shire"},
+			want: "lived quietly in the Shire",
+			ok:   true,
+		},
+		{
+			name: "sentence naming subject and object",
+			fact: entities.Fact{Subject: "Frodo", Object: "Crickhollow", Context: "moved"},
+			want: "Years later, frodo moved to Crickhollow!",
+			ok:   true,
+		},
+		{
+			name: "object mention",
+			fact: entities.Fact{Subject: "Bilbo", Object: "Crickhollow"},
+			want: "Crickhollow",
+			ok:   true,
+		},
+		{
+			name: "not found",
+			fact: entities.Fact{Subject: "Bilbo", Object: "Rivendell"},
+		},
+		{
+			name: "line past the end",
+			fact: entities.Fact{Subject: "Sam", SourceLine: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := LocateFact(locateText, &tt.fact)
+			assert.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.want, locateText[start:end])
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultReindexBatchSize is the number of facts copied per batch during a reindex.
+const DefaultReindexBatchSize = 256
+
+// ReindexOptions controls how a collection is rebuilt.
+type ReindexOptions struct {
+	VectorSize uint64 // Vector size for the new collection (required)
+	ReEmbed    bool   // Regenerate embeddings instead of copying them
+	KeepOld    bool   // Keep the previous collection after switching (not possible for a legacy collection)
+	BatchSize  int    // Facts per batch (0 = default)
+}
+
+// ReindexResult describes a completed reindex.
+type ReindexResult struct {
+	Alias         string
+	OldCollection string
+	NewCollection string
+	Copied        int
+	OldDeleted    bool
+	Legacy        bool // The old collection predated aliases, so it was deleted whatever KeepOld said
+}
+
+// MigrationService rebuilds vector collections behind a stable alias.
+// Readers keep using the old collection until the new one is fully
+// populated, at which point the alias is switched atomically.
+type MigrationService struct {
+	admin    ports.VectorCollectionAdmin
+	embedder ports.Embedder
+	now      func() time.Time
+}
+
+// NewMigrationService creates a new MigrationService.
+func NewMigrationService(admin ports.VectorCollectionAdmin, embedder ports.Embedder) *MigrationService {
+	return &MigrationService{
+		admin:    admin,
+		embedder: embedder,
+		now:      time.Now,
+	}
+}
+
+// Reindex copies every fact reachable through alias into a freshly created
+// collection and then points alias at it.
+//
+// Worlds created before aliases were introduced store facts in a physical
+// collection named like the alias. For those, the old collection must be
+// deleted before the alias can take its name, so there is a brief window
+// in which queries fail; every later reindex switches atomically.
+func (s *MigrationService) Reindex(ctx context.Context, alias string, opts ReindexOptions) (*ReindexResult, error) {
+	if opts.VectorSize == 0 {
+		return nil, entities.Errorf(entities.ErrValidation, "vector size is required")
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultReindexBatchSize
+	}
+
+	source, legacy, err := s.resolveSource(ctx, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	target := fmt.Sprintf("%s_%s", alias, s.now().UTC().Format("20060102150405"))
+	if err := s.admin.CreateCollection(ctx, target, opts.VectorSize); err != nil {
+		return nil, fmt.Errorf("creating collection %s: %w", target, err)
+	}
+
+	copied, err := s.copyFacts(ctx, source, target, batchSize, opts.ReEmbed)
+	if err != nil {
+		s.dropCollection(ctx, target)
+		return nil, err
+	}
+
+	result := &ReindexResult{
+		Alias:         alias,
+		OldCollection: source,
+		NewCollection: target,
+		Copied:        copied,
+		Legacy:        legacy,
+	}
+
+	if legacy {
+		// The alias name is still taken by the physical collection.
+		if err := s.admin.DeleteCollection(ctx, source); err != nil {
+			s.dropCollection(ctx, target)
+			return nil, fmt.Errorf("removing legacy collection: %w", err)
+		}
+		result.OldDeleted = true
+	}
+
+	if err := s.admin.SwitchAlias(ctx, alias, target); err != nil {
+		return nil, fmt.Errorf("switching alias (facts are preserved in %s): %w", target, err)
+	}
+
+	if !legacy && !opts.KeepOld {
+		if err := s.admin.DeleteCollection(ctx, source); err != nil {
+			return result, fmt.Errorf("deleting old collection %s: %w", source, err)
+		}
+		result.OldDeleted = true
+	}
+
+	return result, nil
+}
+
+// resolveSource finds the collection currently serving alias. legacy is true
+// when the name refers to a physical collection rather than an alias.
+func (s *MigrationService) resolveSource(ctx context.Context, alias string) (source string, legacy bool, err error) {
+	source, err = s.admin.ResolveAlias(ctx, alias)
+	if err != nil {
+		return "", false, fmt.Errorf("resolving alias: %w", err)
+	}
+	if source != "" {
+		return source, false, nil
+	}
+
+	exists, err := s.admin.CollectionExists(ctx, alias)
+	if err != nil {
+		return "", false, fmt.Errorf("checking collection: %w", err)
+	}
+	if !exists {
+		return "", false, entities.Errorf(entities.ErrNotFound, "collection %s not found", alias)
+	}
+	return alias, true, nil
+}
+
+// copyFacts streams facts from source to target, optionally re-embedding them.
+func (s *MigrationService) copyFacts(ctx context.Context, source, target string, batchSize int, reEmbed bool) (int, error) {
+	copied := 0
+	err := s.admin.ScrollFacts(ctx, source, batchSize, func(facts []entities.Fact) error {
+		if reEmbed {
+			if err := embedFacts(ctx, s.embedder, facts); err != nil {
+				return err
+			}
+		}
+		if err := s.admin.SaveFacts(ctx, target, facts); err != nil {
+			return fmt.Errorf("saving facts to %s: %w", target, err)
+		}
+		copied += len(facts)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("copying facts: %w", err)
+	}
+	return copied, nil
+}
+
+// dropCollection removes a partially built collection, logging failures
+// since the caller is already returning a more relevant error.
+func (s *MigrationService) dropCollection(ctx context.Context, name string) {
+	if err := s.admin.DeleteCollection(ctx, name); err != nil {
+		log.Printf("warning: failed to remove collection %s: %v", name, err)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+// fakeCollectionAdmin is an in-memory VectorCollectionAdmin for migration tests.
+type fakeCollectionAdmin struct {
+	collections map[string][]entities.Fact
+	aliases     map[string]string
+	saveErr     error
+	switchErr   error
+	deleted     []string
+}
+
+func newFakeCollectionAdmin() *fakeCollectionAdmin {
+	return &fakeCollectionAdmin{
+		collections: make(map[string][]entities.Fact),
+		aliases:     make(map[string]string),
+	}
+}
+
+func (f *fakeCollectionAdmin) ResolveAlias(_ context.Context, alias string) (string, error) {
+	return f.aliases[alias], nil
+}
+
+func (f *fakeCollectionAdmin) CollectionExists(_ context.Context, name string) (bool, error) {
+	_, ok := f.collections[name]
+	return ok, nil
+}
+
+func (f *fakeCollectionAdmin) CreateCollection(_ context.Context, name string, _ uint64) error {
+	f.collections[name] = nil
+	return nil
+}
+
+func (f *fakeCollectionAdmin) DeleteCollection(_ context.Context, name string) error {
+	delete(f.collections, name)
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func (f *fakeCollectionAdmin) SwitchAlias(_ context.Context, alias, collection string) error {
+	if f.switchErr != nil {
+		return f.switchErr
+	}
+	if _, taken := f.collections[alias]; taken {
+		return errors.New("alias name collides with collection")
+	}
+	f.aliases[alias] = collection
+	return nil
+}
+
+func (f *fakeCollectionAdmin) ScrollFacts(_ context.Context, collection string, batchSize int, fn func([]entities.Fact) error) error {
+	facts := f.collections[collection]
+	for start := 0; start < len(facts); start += batchSize {
+		end := min(start+batchSize, len(facts))
+		batch := make([]entities.Fact, end-start)
+		copy(batch, facts[start:end])
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeCollectionAdmin) SaveFacts(_ context.Context, collection string, facts []entities.Fact) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.collections[collection] = append(f.collections[collection], facts...)
+	return nil
+}
+
+func newTestMigrationService(admin *fakeCollectionAdmin, emb *mocks.Embedder) *MigrationService {
+	svc := NewMigrationService(admin, emb)
+	svc.now = func() time.Time { return time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC) }
+	return svc
+}
+
+func sampleFacts(n int) []entities.Fact {
+	facts := make([]entities.Fact, 0, n)
+	for i := range n {
+		facts = append(facts, entities.Fact{
+			ID:        string(rune('a' + i)),
+			Subject:   "Subject",
+			Predicate: "is",
+			Object:    "Object",
+			Embedding: []float32{0.1},
+		})
+	}
+	return facts
+}
+
+func TestMigrationService_Reindex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("switches existing alias atomically", func(t *testing.T) {
+		admin := newFakeCollectionAdmin()
+		admin.collections["lore_w_old"] = sampleFacts(5)
+		admin.aliases["lore_w"] = "lore_w_old"
+		svc := newTestMigrationService(admin, &mocks.Embedder{})
+
+		result, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 1, BatchSize: 2})
+		require.NoError(t, err)
+
+		assert.Equal(t, "lore_w_old", result.OldCollection)
+		assert.Equal(t, "lore_w_20240501100000", result.NewCollection)
+		assert.Equal(t, 5, result.Copied)
+		assert.True(t, result.OldDeleted)
+		assert.Equal(t, "lore_w_20240501100000", admin.aliases["lore_w"])
+		assert.Len(t, admin.collections["lore_w_20240501100000"], 5)
+		assert.NotContains(t, admin.collections, "lore_w_old")
+	})
+
+	t.Run("keeps old collection when requested", func(t *testing.T) {
+		admin := newFakeCollectionAdmin()
+		admin.collections["lore_w_old"] = sampleFacts(1)
+		admin.aliases["lore_w"] = "lore_w_old"
+		svc := newTestMigrationService(admin, &mocks.Embedder{})
+
+		result, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 1, KeepOld: true})
+		require.NoError(t, err)
+		assert.False(t, result.OldDeleted)
+		assert.Contains(t, admin.collections, "lore_w_old")
+	})
+
+	t.Run("converts legacy collection to alias", func(t *testing.T) {
+		admin := newFakeCollectionAdmin()
+		admin.collections["lore_w"] = sampleFacts(3)
+		svc := newTestMigrationService(admin, &mocks.Embedder{})
+
+		result, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 1, KeepOld: true})
+		require.NoError(t, err)
+		assert.Equal(t, "lore_w", result.OldCollection)
+		assert.True(t, result.Legacy)
+		assert.True(t, result.OldDeleted, "the alias takes the legacy collection's name, so it can't be kept")
+		assert.Equal(t, result.NewCollection, admin.aliases["lore_w"])
+		assert.Len(t, admin.collections[result.NewCollection], 3)
+	})
+
+	t.Run("re-embeds facts", func(t *testing.T) {
+		admin := newFakeCollectionAdmin()
+		admin.collections["lore_w"] = sampleFacts(2)
+		emb := &mocks.Embedder{EmbeddingResult: []float32{0.9, 0.8}}
+		svc := newTestMigrationService(admin, emb)
+
+		result, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 2, ReEmbed: true})
+		require.NoError(t, err)
+		assert.Equal(t, 1, emb.EmbedBatchCallCount)
+		for _, f := range admin.collections[result.NewCollection] {
+			assert.Equal(t, []float32{0.9, 0.8}, f.Embedding)
+		}
+	})
+
+	t.Run("copy failure leaves alias untouched", func(t *testing.T) {
+		admin := newFakeCollectionAdmin()
+		admin.collections["lore_w_old"] = sampleFacts(2)
+		admin.aliases["lore_w"] = "lore_w_old"
+		admin.saveErr = errors.New("disk full")
+		svc := newTestMigrationService(admin, &mocks.Embedder{})
+
+		_, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 1})
+		require.Error(t, err)
+		assert.Equal(t, "lore_w_old", admin.aliases["lore_w"])
+		assert.Contains(t, admin.collections, "lore_w_old")
+		assert.NotContains(t, admin.collections, "lore_w_20240501100000")
+	})
+
+	t.Run("missing collection", func(t *testing.T) {
+		svc := newTestMigrationService(newFakeCollectionAdmin(), &mocks.Embedder{})
+
+		_, err := svc.Reindex(ctx, "lore_w", ReindexOptions{VectorSize: 1})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("requires vector size", func(t *testing.T) {
+		svc := newTestMigrationService(newFakeCollectionAdmin(), &mocks.Embedder{})
+
+		_, err := svc.Reindex(ctx, "lore_w", ReindexOptions{})
+		require.Error(t, err)
+	})
+}
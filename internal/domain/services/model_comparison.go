@@ -0,0 +1,96 @@
+package services
+
+import (
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// ModelExtraction is the facts one model extracted from a text.
+type ModelExtraction struct {
+	Model string
+	Facts []entities.Fact
+}
+
+// ModelAgreement is how one model's facts compare with the other models'.
+type ModelAgreement struct {
+	Model  string
+	Facts  int // Distinct facts extracted
+	Shared int // Of those, facts another model extracted too
+
+	// Unique are the facts no other model extracted.
+	Unique []entities.Fact
+
+	AverageConfidence float64
+}
+
+// ModelComparison is how far models extracting the same text agree. Facts
+// agree when their normalized subject, predicate, and object match.
+type ModelComparison struct {
+	Models []ModelAgreement
+	Total  int // Distinct facts extracted by any model
+
+	// Agreed are the facts every model extracted, as the first model
+	// stated them.
+	Agreed []entities.Fact
+}
+
+// Agreement returns the share of the distinct facts extracted that every
+// model extracted.
+func (c *ModelComparison) Agreement() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(len(c.Agreed)) / float64(c.Total)
+}
+
+// CompareExtractions compares the facts several models extracted from the
+// same text, in the order given.
+func CompareExtractions(extractions []ModelExtraction) *ModelComparison {
+	// Which models extracted each fact, in order of first extraction
+	extractedBy := make(map[string]map[int]bool)
+	var order []string
+	first := make(map[string]entities.Fact)
+	for m := range extractions {
+		for i := range extractions[m].Facts {
+			key := tripleKey(&extractions[m].Facts[i])
+			if extractedBy[key] == nil {
+				extractedBy[key] = make(map[int]bool)
+				order = append(order, key)
+				first[key] = extractions[m].Facts[i]
+			}
+			extractedBy[key][m] = true
+		}
+	}
+
+	comparison := &ModelComparison{Total: len(order)}
+	for _, key := range order {
+		if len(extractedBy[key]) == len(extractions) {
+			comparison.Agreed = append(comparison.Agreed, first[key])
+		}
+	}
+
+	for m := range extractions {
+		agreement := ModelAgreement{Model: extractions[m].Model}
+		seen := make(map[string]bool)
+		var confidence float64
+		for i := range extractions[m].Facts {
+			fact := &extractions[m].Facts[i]
+			key := tripleKey(fact)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			agreement.Facts++
+			confidence += fact.Confidence
+			if len(extractedBy[key]) > 1 {
+				agreement.Shared++
+			} else {
+				agreement.Unique = append(agreement.Unique, *fact)
+			}
+		}
+		if agreement.Facts > 0 {
+			agreement.AverageConfidence = confidence / float64(agreement.Facts)
+		}
+		comparison.Models = append(comparison.Models, agreement)
+	}
+	return comparison
+}
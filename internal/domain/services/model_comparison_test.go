@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestCompareExtractions(t *testing.T) {
+	livesIn := entities.Fact{Subject: "Frodo", Predicate: "lives_in", Object: "Bag End", Confidence: 0.9}
+	carries := entities.Fact{Subject: "Frodo", Predicate: "carries", Object: "the Ring", Confidence: 0.8}
+	uncle := entities.Fact{Subject: "Bilbo", Predicate: "uncle_of", Object: "Frodo", Confidence: 0.6}
+	sting := entities.Fact{Subject: "Bilbo", Predicate: "owns", Object: "Sting", Confidence: 0.5}
+
+	comparison := CompareExtractions([]ModelExtraction{
+		{Model: "gpt-4o-mini", Facts: []entities.Fact{livesIn, carries, uncle}},
+		{Model: "llama3", Facts: []entities.Fact{
+			{Subject: "frodo", Predicate: "Lives_In", Object: "bag end", Confidence: 0.7},
+			carries,
+			carries,
+			sting,
+		}},
+		{Model: "mistral", Facts: []entities.Fact{livesIn, carries}},
+	})
+
+	assert.Equal(t, 4, comparison.Total)
+	assert.Equal(t, []entities.Fact{livesIn, carries}, comparison.Agreed, "normalized matches agree, as the first model stated them")
+	assert.InDelta(t, 0.5, comparison.Agreement(), 1e-9)
+
+	require.Len(t, comparison.Models, 3)
+	gpt := comparison.Models[0]
+	assert.Equal(t, "gpt-4o-mini", gpt.Model)
+	assert.Equal(t, 3, gpt.Facts)
+	assert.Equal(t, 2, gpt.Shared)
+	assert.Equal(t, []entities.Fact{uncle}, gpt.Unique)
+	assert.InDelta(t, 0.7667, gpt.AverageConfidence, 1e-3)
+
+	llama := comparison.Models[1]
+	assert.Equal(t, 3, llama.Facts, "a fact extracted twice counts once")
+	assert.Equal(t, []entities.Fact{sting}, llama.Unique)
+
+	mistral := comparison.Models[2]
+	assert.Equal(t, 2, mistral.Shared)
+	assert.Empty(t, mistral.Unique)
+
+	assert.Zero(t, CompareExtractions(nil).Agreement())
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// prefilterSeverity is the severity of contradictions the prefilter finds.
+// A subject given two values of a single-valued property, or two
+// properties that rule each other out, is never a matter of interpretation.
+const prefilterSeverity = "major"
+
+// Prefilter settles what it can of a consistency check before the LLM sees
+// it. Contradictions between declared predicates are reported directly, and
+// pairs of facts that share no entity are left out of the LLM call.
+type Prefilter struct {
+	// SingleValued are predicates a subject has one object for, such as
+	// eye_color: the same subject with two objects is a contradiction.
+	SingleValued []string
+
+	// Exclusive are pairs of predicates a subject can't have both of, such
+	// as is_alive and died_in.
+	Exclusive [][2]string
+
+	// KeepUnrelated sends pairs of facts sharing no entity to the LLM
+	// anyway. Graph retrieval always does, as it finds facts through
+	// related entities.
+	KeepUnrelated bool
+}
+
+// contradictions reports new facts giving a single-valued predicate of a
+// subject another object than an existing fact does, or giving it a
+// predicate an existing fact's predicate excludes. Typed objects are left
+// to typedContradictions.
+func (p Prefilter) contradictions(newFacts, existingFacts []entities.Fact) []ports.ConsistencyIssue {
+	if len(p.SingleValued) == 0 && len(p.Exclusive) == 0 {
+		return nil
+	}
+
+	var issues []ports.ConsistencyIssue
+	for i := range newFacts {
+		for j := range existingFacts {
+			a, b := &newFacts[i], &existingFacts[j]
+			if a.ID == b.ID || entities.NormalizeName(a.Subject) != entities.NormalizeName(b.Subject) {
+				continue
+			}
+
+			var description string
+			switch {
+			case sameSubjectAndPredicate(a, b):
+				if !p.singleValued(a.Predicate) || a.ObjectValue().IsTyped() && b.ObjectValue().IsTyped() ||
+					entities.NormalizeName(a.Object) == entities.NormalizeName(b.Object) {
+					continue
+				}
+				description = fmt.Sprintf("%s %s %s, but an existing fact says %s", a.Subject, a.Predicate, a.Object, b.Object)
+			case p.excludes(a.Predicate, b.Predicate):
+				description = fmt.Sprintf("%s %s %s, but an existing fact says %s %s", a.Subject, a.Predicate, a.Object, b.Predicate, b.Object)
+			default:
+				continue
+			}
+			issues = append(issues, ports.ConsistencyIssue{
+				NewFact:      *a,
+				ExistingFact: *b,
+				Description:  description,
+				Severity:     prefilterSeverity,
+			})
+		}
+	}
+	return issues
+}
+
+// related returns the new facts and candidates worth an LLM call: those in
+// a pair sharing an entity that isn't already reported.
+func (p Prefilter) related(newFacts, candidates []entities.Fact, reported map[string]bool) ([]entities.Fact, []entities.Fact) {
+	keepNew := make([]bool, len(newFacts))
+	keepCandidate := make([]bool, len(candidates))
+	for i := range newFacts {
+		for j := range candidates {
+			if newFacts[i].ID == candidates[j].ID || !shareEntity(&newFacts[i], &candidates[j]) {
+				continue
+			}
+			pair := ports.ConsistencyIssue{NewFact: newFacts[i], ExistingFact: candidates[j]}
+			if reported[issuePairKey(&pair)] {
+				continue
+			}
+			keepNew[i], keepCandidate[j] = true, true
+		}
+	}
+
+	var relatedNew, relatedCandidates []entities.Fact
+	for i := range newFacts {
+		if keepNew[i] {
+			relatedNew = append(relatedNew, newFacts[i])
+		}
+	}
+	for j := range candidates {
+		if keepCandidate[j] {
+			relatedCandidates = append(relatedCandidates, candidates[j])
+		}
+	}
+	return relatedNew, relatedCandidates
+}
+
+// singleValued reports whether predicate is declared single-valued.
+func (p Prefilter) singleValued(predicate string) bool {
+	predicate = entities.NormalizeName(predicate)
+	return slices.ContainsFunc(p.SingleValued, func(declared string) bool {
+		return entities.NormalizeName(declared) == predicate
+	})
+}
+
+// excludes reports whether a and b are declared mutually exclusive.
+func (p Prefilter) excludes(a, b string) bool {
+	a, b = entities.NormalizeName(a), entities.NormalizeName(b)
+	return slices.ContainsFunc(p.Exclusive, func(pair [2]string) bool {
+		x, y := entities.NormalizeName(pair[0]), entities.NormalizeName(pair[1])
+		return x == a && y == b || x == b && y == a
+	})
+}
+
+// shareEntity reports whether a and b name a common entity as subject or
+// object.
+func shareEntity(a, b *entities.Fact) bool {
+	names := []string{entities.NormalizeName(a.Subject), entities.NormalizeName(a.Object)}
+	for _, name := range []string{entities.NormalizeName(b.Subject), entities.NormalizeName(b.Object)} {
+		if name != "" && slices.Contains(names, name) {
+			return true
+		}
+	}
+	return false
+}
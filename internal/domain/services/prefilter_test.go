@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestPrefilter_Contradictions(t *testing.T) {
+	p := Prefilter{
+		SingleValued: []string{"eye_color", "age"},
+		Exclusive:    [][2]string{{"is_alive", "died_in"}},
+	}
+	existing := []entities.Fact{
+		{ID: "e1", Subject: "Frodo", Predicate: "eye_color", Object: "blue"},
+		{ID: "e2", Subject: "Boromir", Predicate: "died_in", Object: "Amon Hen"},
+		{ID: "e3", Subject: "Frodo", Predicate: "friend_of", Object: "Sam"},
+		{ID: "e4", Subject: "Frodo", Predicate: "age", Object: "50"},
+	}
+	newFacts := []entities.Fact{
+		{ID: "n1", Subject: "frodo", Predicate: "Eye_Color", Object: "green"},
+		{ID: "n2", Subject: "Frodo", Predicate: "eye_color", Object: "Blue"},
+		{ID: "n3", Subject: "Boromir", Predicate: "is_alive", Object: "yes"},
+		{ID: "n4", Subject: "Frodo", Predicate: "friend_of", Object: "Merry"},
+		{ID: "n5", Subject: "Frodo", Predicate: "age", Object: "51"},
+	}
+
+	issues := p.contradictions(newFacts, existing)
+	require.Len(t, issues, 2, "same object, multi-valued predicates, and typed objects are not flagged")
+	assert.Equal(t, "n1", issues[0].NewFact.ID)
+	assert.Equal(t, "e1", issues[0].ExistingFact.ID)
+	assert.Equal(t, "frodo Eye_Color green, but an existing fact says blue", issues[0].Description)
+	assert.Equal(t, "n3", issues[1].NewFact.ID)
+	assert.Equal(t, "e2", issues[1].ExistingFact.ID)
+	assert.Equal(t, prefilterSeverity, issues[1].Severity)
+
+	assert.Empty(t, Prefilter{}.contradictions(newFacts, existing))
+}
+
+func TestFindIssuesAmong_Prefilter(t *testing.T) {
+	existing := []entities.Fact{
+		{ID: "e1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "blue"},
+		{ID: "e2", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"},
+		{ID: "e3", Type: entities.FactTypeCharacter, Subject: "Gandalf", Predicate: "carries", Object: "Glamdring"},
+	}
+	newFacts := []entities.Fact{
+		{ID: "n1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "green"},
+		{ID: "n2", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "carries", Object: "rope"},
+	}
+	llm := &mocks.LLMClient{}
+	vectorDB := &mocks.VectorDB{Facts: existing}
+	retrieval := Retrieval{Prefilter: Prefilter{SingleValued: []string{"eye_color"}}}
+	all := func(*entities.Fact) bool { return true }
+
+	issues, err := findIssuesAmong(context.Background(), llm, vectorDB, newFacts, retrieval, all)
+	require.NoError(t, err)
+	require.Len(t, issues, 1, "flagged without the LLM")
+	assert.Equal(t, "e1", issues[0].ExistingFact.ID)
+	assert.Equal(t, 1, llm.CheckConsistencyCallCount)
+	assert.Equal(t, []entities.Fact{newFacts[0]}, llm.CheckConsistencyLastNew, "Sam shares no entity with a stored fact")
+	assert.Equal(t, []entities.Fact{existing[1]}, llm.CheckConsistencyLastOld, "the flagged pair and Gandalf's fact are left out")
+
+	retrieval.Prefilter.KeepUnrelated = true
+	_, err = findIssuesAmong(context.Background(), llm, vectorDB, newFacts, retrieval, all)
+	require.NoError(t, err)
+	assert.Equal(t, newFacts, llm.CheckConsistencyLastNew)
+	assert.Equal(t, existing, llm.CheckConsistencyLastOld)
+}
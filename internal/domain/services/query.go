@@ -3,6 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
@@ -11,22 +15,101 @@ import (
 // DefaultSearchLimit is the default number of results to return.
 const DefaultSearchLimit = 10
 
+// rrfK dampens the influence of top ranks in reciprocal rank fusion.
+const rrfK = 60
+
+// asOfOversample widens the search of current facts for point-in-time
+// queries, since facts created after the cutoff are dropped afterwards.
+const asOfOversample = 4
+
+// asOfEmbedBatchSize is how many fact versions a point-in-time query embeds
+// per call, under the 2048 inputs OpenAI accepts in one request.
+const asOfEmbedBatchSize = 1024
+
+// claimOversample widens the search of canon facts, since claims are
+// dropped afterwards.
+const claimOversample = 2
+
+// povOversample widens the search for point-of-view queries, since facts
+// the character does not know are dropped afterwards.
+const povOversample = 4
+
+// SearchMode selects which stored embeddings a query is matched against.
+type SearchMode string
+
+const (
+	// SearchModeContext matches against fact text plus context (default).
+	// Best for questions about situations and circumstances.
+	SearchModeContext SearchMode = "context"
+	// SearchModeText matches against the fact triple alone.
+	// Best for questions about how something is phrased or named.
+	SearchModeText SearchMode = "text"
+	// SearchModeFused merges both rankings with reciprocal rank fusion.
+	SearchModeFused SearchMode = "fused"
+	// SearchModeHybrid merges the context ranking with a keyword ranking.
+	// Best for rare proper nouns that embeddings handle poorly.
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// IsValid reports whether the mode is supported. Empty means context.
+func (m SearchMode) IsValid() bool {
+	switch m {
+	case "", SearchModeContext, SearchModeText, SearchModeFused, SearchModeHybrid:
+		return true
+	default:
+		return false
+	}
+}
+
+// SearchOptions configures a semantic search.
+type SearchOptions struct {
+	Type  entities.FactType // Filter by fact type (empty = all)
+	Limit int               // Maximum results (0 = DefaultSearchLimit)
+	Mode  SearchMode        // Embedding to match against (empty = context)
+	AsOf  time.Time         // Answer from fact versions valid at this time (zero = now)
+
+	// POV keeps only the facts this entity knows (nil = all facts).
+	POV *entities.Entity
+
+	// IncludeClaims keeps facts that are claims rather than canon.
+	IncludeClaims bool
+}
+
 // QueryService handles fact querying and search.
 type QueryService struct {
-	embedder ports.Embedder
-	vectorDB ports.VectorDB
+	embedder     ports.Embedder
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
 }
 
 // NewQueryService creates a new query service.
-func NewQueryService(embedder ports.Embedder, vectorDB ports.VectorDB) *QueryService {
+func NewQueryService(embedder ports.Embedder, vectorDB ports.VectorDB, relationalDB ports.RelationalDB) *QueryService {
 	return &QueryService{
-		embedder: embedder,
-		vectorDB: vectorDB,
+		embedder:     embedder,
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
 	}
 }
 
 // Search finds facts semantically similar to the query.
 func (s *QueryService) Search(ctx context.Context, query string, limit int) ([]entities.Fact, error) {
+	return s.SearchWithOptions(ctx, query, SearchOptions{Limit: limit})
+}
+
+// SearchByType finds facts filtered by type.
+func (s *QueryService) SearchByType(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return s.SearchWithOptions(ctx, query, SearchOptions{Type: factType, Limit: limit})
+}
+
+// SearchWithOptions finds facts similar to the query using the requested
+// embedding, or a fusion of both. Current facts asserted by several sources
+// rank above one-off mentions of similar relevance. Claims are left out
+// unless opts.IncludeClaims is set.
+func (s *QueryService) SearchWithOptions(ctx context.Context, query string, opts SearchOptions) ([]entities.Fact, error) {
+	if !opts.Mode.IsValid() {
+		return nil, entities.Errorf(entities.ErrValidation, "invalid search mode: %s", opts.Mode)
+	}
+	limit := opts.Limit
 	if limit <= 0 {
 		limit = DefaultSearchLimit
 	}
@@ -36,29 +119,251 @@ func (s *QueryService) Search(ctx context.Context, query string, limit int) ([]e
 		return nil, fmt.Errorf("generating query embedding: %w", err)
 	}
 
-	facts, err := s.vectorDB.Search(ctx, embedding, limit)
+	if opts.IncludeClaims && opts.POV == nil {
+		return s.search(ctx, query, embedding, opts, limit)
+	}
+
+	fetch := limit * claimOversample
+	if opts.POV != nil {
+		fetch = limit * povOversample
+	}
+	facts, err := s.search(ctx, query, embedding, opts, fetch)
 	if err != nil {
-		return nil, fmt.Errorf("searching facts: %w", err)
+		return nil, err
+	}
+	if !opts.IncludeClaims {
+		facts = canonOnly(facts)
 	}
+	if opts.POV != nil {
+		return knownTo(facts, opts.POV, limit), nil
+	}
+	return facts[:min(len(facts), limit)], nil
+}
 
-	return facts, nil
+// canonOnly returns the facts that are not claims, in order.
+func canonOnly(facts []entities.Fact) []entities.Fact {
+	canon := make([]entities.Fact, 0, len(facts))
+	for i := range facts {
+		if !facts[i].IsClaim() {
+			canon = append(canon, facts[i])
+		}
+	}
+	return canon
 }
 
-// SearchByType finds facts filtered by type.
-func (s *QueryService) SearchByType(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
-	if limit <= 0 {
-		limit = DefaultSearchLimit
+// search runs the search for the current facts, or for those at opts.AsOf.
+func (s *QueryService) search(ctx context.Context, query string, embedding []float32, opts SearchOptions, limit int) ([]entities.Fact, error) {
+	if !opts.AsOf.IsZero() {
+		return s.searchAsOf(ctx, query, embedding, opts, limit)
 	}
+	facts, err := s.searchMode(ctx, query, embedding, opts.Mode, opts.Type, limit)
+	if err != nil {
+		return nil, err
+	}
+	return rankByCorroboration(unexpired(facts, time.Now())), nil
+}
 
-	embedding, err := s.embedder.Embed(ctx, query)
+// unexpired returns the facts that are not drafts lapsed by now, in order.
+func unexpired(facts []entities.Fact, now time.Time) []entities.Fact {
+	return slices.DeleteFunc(facts, func(f entities.Fact) bool { return f.IsExpired(now) })
+}
+
+// knownTo returns up to limit of the facts known to entity, in order.
+func knownTo(facts []entities.Fact, entity *entities.Entity, limit int) []entities.Fact {
+	known := make([]entities.Fact, 0, min(len(facts), limit))
+	for i := range facts {
+		if len(known) == limit {
+			break
+		}
+		if facts[i].KnownTo(entity) {
+			known = append(known, facts[i])
+		}
+	}
+	return known
+}
+
+// searchMode runs the search for one mode against the current facts.
+func (s *QueryService) searchMode(ctx context.Context, query string, embedding []float32, mode SearchMode, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	switch mode {
+	case SearchModeText:
+		return s.searchVector(ctx, ports.VectorText, embedding, factType, limit)
+	case SearchModeFused:
+		return s.searchFused(ctx, embedding, factType, limit)
+	case SearchModeHybrid:
+		return s.searchHybrid(ctx, query, embedding, factType, limit)
+	default:
+		return s.searchVector(ctx, ports.VectorContext, embedding, factType, limit)
+	}
+}
+
+// searchAsOf answers from the facts as they stood at opts.AsOf. Current
+// search results that have since changed are rolled back to their version
+// at that time, and facts that have since changed or been deleted are
+// ranked from their history. Other facts are taken as they are now if
+// they already existed.
+func (s *QueryService) searchAsOf(ctx context.Context, query string, embedding []float32, opts SearchOptions, limit int) ([]entities.Fact, error) {
+	versions, err := s.relationalDB.ListVersionsAsOf(ctx, opts.AsOf)
 	if err != nil {
-		return nil, fmt.Errorf("generating query embedding: %w", err)
+		return nil, fmt.Errorf("listing fact versions: %w", err)
+	}
+	history := make(map[string]*entities.FactVersion, len(versions))
+	for i := range versions {
+		history[versions[i].FactID] = &versions[i]
+	}
+
+	current, err := s.searchMode(ctx, query, embedding, opts.Mode, opts.Type, limit*asOfOversample)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make([]entities.Fact, 0, len(current))
+	for i := range current {
+		if v, ok := history[current[i].ID]; ok {
+			if v.ChangeType != entities.ChangeDeletion {
+				present = append(present, versionFact(v))
+			}
+			continue
+		}
+		if !current[i].CreatedAt.After(opts.AsOf) {
+			present = append(present, current[i])
+		}
 	}
 
-	facts, err := s.vectorDB.SearchByType(ctx, embedding, factType, limit)
+	past, err := s.rankVersions(ctx, embedding, versions, opts.Type, limit)
 	if err != nil {
-		return nil, fmt.Errorf("searching facts by type: %w", err)
+		return nil, err
+	}
+
+	return fuseRankings(limit, present, past), nil
+}
+
+// rankVersions embeds historical fact states and ranks them by similarity
+// to the query embedding.
+func (s *QueryService) rankVersions(ctx context.Context, embedding []float32, versions []entities.FactVersion, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	facts := make([]entities.Fact, 0, len(versions))
+	texts := make([]string, 0, len(versions))
+	for i := range versions {
+		if versions[i].ChangeType == entities.ChangeDeletion {
+			continue
+		}
+		if factType != "" && versions[i].Data.Type != factType {
+			continue
+		}
+		fact := versionFact(&versions[i])
+		facts = append(facts, fact)
+		texts = append(texts, embeddingText(s.embedder, &fact))
+	}
+	if len(facts) == 0 {
+		return facts, nil
 	}
 
+	scores := make(map[string]float64, len(facts))
+	for start := 0; start < len(texts); start += asOfEmbedBatchSize {
+		end := min(start+asOfEmbedBatchSize, len(texts))
+		//nolint:loopcall // Chunked to stay under the embedding API's input limit
+		embeddings, err := s.embedder.EmbedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("embedding fact versions: %w", err)
+		}
+		if len(embeddings) != end-start {
+			return nil, fmt.Errorf("embedding count mismatch: got %d, want %d", len(embeddings), end-start)
+		}
+		for i := range embeddings {
+			scores[facts[start+i].ID] = cosineSimilarity(embedding, embeddings[i])
+		}
+	}
+	sort.SliceStable(facts, func(i, j int) bool {
+		return scores[facts[i].ID] > scores[facts[j].ID]
+	})
+
+	if len(facts) > limit {
+		facts = facts[:limit]
+	}
 	return facts, nil
 }
+
+// versionFact returns the fact state recorded by a version.
+func versionFact(v *entities.FactVersion) entities.Fact {
+	fact := v.Data
+	fact.ID = v.FactID
+	return fact
+}
+
+// cosineSimilarity returns the cosine of the angle between two vectors,
+// or 0 when either is empty or their lengths differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// searchVector runs a single search against one stored embedding.
+func (s *QueryService) searchVector(ctx context.Context, vector ports.VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	facts, err := s.vectorDB.SearchVector(ctx, vector, embedding, factType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts by %s: %w", vector, err)
+	}
+	return facts, nil
+}
+
+// searchFused searches both embeddings and merges the rankings.
+func (s *QueryService) searchFused(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	contextFacts, err := s.searchVector(ctx, ports.VectorContext, embedding, factType, limit)
+	if err != nil {
+		return nil, err
+	}
+	textFacts, err := s.searchVector(ctx, ports.VectorText, embedding, factType, limit)
+	if err != nil {
+		return nil, err
+	}
+	return fuseRankings(limit, contextFacts, textFacts), nil
+}
+
+// searchHybrid merges dense context results with keyword results.
+func (s *QueryService) searchHybrid(ctx context.Context, query string, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	contextFacts, err := s.searchVector(ctx, ports.VectorContext, embedding, factType, limit)
+	if err != nil {
+		return nil, err
+	}
+	keywordFacts, err := s.vectorDB.SearchKeywords(ctx, query, factType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts by keywords: %w", err)
+	}
+	return fuseRankings(limit, contextFacts, keywordFacts), nil
+}
+
+// fuseRankings merges ranked fact lists with reciprocal rank fusion: each
+// fact scores the sum of 1/(rrfK+rank) across the lists it appears in.
+// Ties keep first-seen order.
+func fuseRankings(limit int, rankings ...[]entities.Fact) []entities.Fact {
+	scores := make(map[string]float64)
+	var order []entities.Fact
+	for _, ranking := range rankings {
+		for rank := range ranking {
+			id := ranking[rank].ID
+			if _, seen := scores[id]; !seen {
+				order = append(order, ranking[rank])
+			}
+			scores[id] += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i].ID] > scores[order[j].ID]
+	})
+
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	return order
+}
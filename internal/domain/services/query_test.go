@@ -2,12 +2,14 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 )
 
 func TestQueryService_Search(t *testing.T) {
@@ -31,7 +33,7 @@ func TestQueryService_Search(t *testing.T) {
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: facts}
 
-	svc := NewQueryService(emb, db)
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
 
 	result, err := svc.Search(t.Context(), "What color are Frodo's eyes?", 10)
 	require.NoError(t, err)
@@ -59,7 +61,7 @@ func TestQueryService_SearchByType(t *testing.T) {
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: facts}
 
-	svc := NewQueryService(emb, db)
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
 
 	result, err := svc.SearchByType(t.Context(), "characters", entities.FactTypeCharacter, 10)
 	require.NoError(t, err)
@@ -71,8 +73,236 @@ func TestQueryService_DefaultLimit(t *testing.T) {
 	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
 	db := &mocks.VectorDB{Facts: []entities.Fact{}}
 
-	svc := NewQueryService(emb, db)
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
 
 	_, err := svc.Search(t.Context(), "test", 0)
 	require.NoError(t, err)
 }
+
+func TestQueryService_SearchPOV(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Gollum", Predicate: "killed", Object: "Deagol", KnownBy: []string{"gollum"}},
+		{ID: "2", Type: entities.FactTypeCharacter, Subject: "Gollum", Predicate: "carries", Object: "the Ring"},
+		{ID: "3", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "is_heir_of", Object: "Bilbo", KnownBy: []string{"bilbo"}},
+		{ID: "4", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "loves", Object: "Rosie", KnownBy: []string{"sam", "frodo"}},
+	}
+
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	svc := NewQueryService(emb, &mocks.VectorDB{Facts: facts}, &mocks.RelationalDB{})
+
+	result, err := svc.SearchWithOptions(t.Context(), "Gollum", SearchOptions{
+		POV: &entities.Entity{ID: "frodo", Name: "Frodo"},
+	})
+	require.NoError(t, err)
+	ids := make([]string, len(result))
+	for i := range result {
+		ids[i] = result[i].ID
+	}
+	assert.ElementsMatch(t, []string{"2", "3", "4"}, ids, "secrets Frodo is not party to are hidden")
+
+	result, err = svc.SearchWithOptions(t.Context(), "Gollum", SearchOptions{
+		Limit: 1,
+		POV:   &entities.Entity{ID: "frodo", Name: "Frodo"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestQueryService_SearchClaims(t *testing.T) {
+	facts := []entities.Fact{
+		{ID: "1", Subject: "Elves", Predicate: "are", Object: "untrustworthy", AssertedBy: "Gimli", Claim: true},
+		{ID: "2", Subject: "Legolas", Predicate: "is", Object: "an elf"},
+		{ID: "3", Subject: "Legolas", Predicate: "is", Object: "a prince", AssertedBy: "Gandalf"},
+	}
+
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	svc := NewQueryService(emb, &mocks.VectorDB{Facts: facts}, &mocks.RelationalDB{})
+
+	result, err := svc.SearchWithOptions(t.Context(), "elves", SearchOptions{})
+	require.NoError(t, err)
+	ids := make([]string, len(result))
+	for i := range result {
+		ids[i] = result[i].ID
+	}
+	assert.ElementsMatch(t, []string{"2", "3"}, ids, "claims are left out by default")
+
+	result, err = svc.SearchWithOptions(t.Context(), "elves", SearchOptions{IncludeClaims: true})
+	require.NoError(t, err)
+	assert.Len(t, result, 3)
+
+	result, err = svc.SearchWithOptions(t.Context(), "elves", SearchOptions{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestQueryService_SearchModes(t *testing.T) {
+	a := entities.Fact{ID: "a", Subject: "A"}
+	b := entities.Fact{ID: "b", Subject: "B"}
+	c := entities.Fact{ID: "c", Subject: "C"}
+
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1}}
+	db := &mocks.VectorDB{
+		VectorResults: map[ports.VectorName][]entities.Fact{
+			ports.VectorContext: {a, b},
+			ports.VectorText:    {c, b},
+		},
+	}
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
+
+	t.Run("context is default", func(t *testing.T) {
+		result, err := svc.SearchWithOptions(t.Context(), "q", SearchOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []entities.Fact{a, b}, result)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		result, err := svc.SearchWithOptions(t.Context(), "q", SearchOptions{Mode: SearchModeText})
+		require.NoError(t, err)
+		assert.Equal(t, []entities.Fact{c, b}, result)
+	})
+
+	t.Run("fused ranks shared results first", func(t *testing.T) {
+		result, err := svc.SearchWithOptions(t.Context(), "q", SearchOptions{Mode: SearchModeFused, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "b", result[0].ID)
+		assert.Equal(t, "a", result[1].ID)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := svc.SearchWithOptions(t.Context(), "q", SearchOptions{Mode: "bm25"})
+		require.Error(t, err)
+	})
+}
+
+func TestQueryService_SearchHybrid(t *testing.T) {
+	a := entities.Fact{ID: "a", Subject: "A"}
+	b := entities.Fact{ID: "b", Subject: "B"}
+	c := entities.Fact{ID: "c", Subject: "Glorfindel"}
+
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1}}
+	db := &mocks.VectorDB{
+		VectorResults: map[ports.VectorName][]entities.Fact{
+			ports.VectorContext:  {a, c},
+			ports.VectorKeywords: {c},
+		},
+	}
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
+
+	result, err := svc.SearchWithOptions(t.Context(), "Glorfindel", SearchOptions{Mode: SearchModeHybrid})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, "c", result[0].ID)
+	assert.Equal(t, "a", result[1].ID)
+	assert.NotContains(t, result, b)
+}
+
+func TestQueryService_SearchAsOf(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	villain := func(object string) entities.Fact {
+		return entities.Fact{ID: "villain", Type: entities.FactTypeCharacter, Subject: "Sauron", Predicate: "is", Object: object}
+	}
+	ring := entities.Fact{ID: "ring", Type: entities.FactTypeEvent, Subject: "The Ring", Predicate: "is", Object: "lost"}
+
+	current := villain("redeemed")
+	current.CreatedAt = day(1)
+	db := &mocks.VectorDB{Facts: []entities.Fact{
+		current,
+		{ID: "old", Type: entities.FactTypeLocation, Subject: "Mordor", Predicate: "is", Object: "dark", CreatedAt: day(2)},
+		{ID: "new", Type: entities.FactTypeLocation, Subject: "Gondor", Predicate: "is", Object: "bright", CreatedAt: day(15)},
+	}}
+	relationalDB := &mocks.RelationalDB{Versions: []entities.FactVersion{
+		{FactID: "villain", Version: 1, ChangeType: entities.ChangeCreation, Data: villain("evil"), CreatedAt: day(1)},
+		{FactID: "villain", Version: 2, ChangeType: entities.ChangeRetcon, Data: villain("redeemed"), CreatedAt: day(20)},
+		{FactID: "ring", Version: 1, ChangeType: entities.ChangeCreation, Data: ring, CreatedAt: day(3)},
+		{FactID: "ring", Version: 2, ChangeType: entities.ChangeDeletion, Data: ring, CreatedAt: day(12)},
+	}}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	svc := NewQueryService(emb, db, relationalDB)
+
+	objects := func(facts []entities.Fact) map[string]string {
+		got := make(map[string]string)
+		for _, f := range facts {
+			got[f.ID] = f.Object
+		}
+		return got
+	}
+
+	tests := []struct {
+		name string
+		opts SearchOptions
+		want map[string]string
+	}{
+		{
+			name: "before retcon",
+			opts: SearchOptions{AsOf: day(10)},
+			want: map[string]string{"villain": "evil", "old": "dark", "ring": "lost"},
+		},
+		{
+			name: "after deletion",
+			opts: SearchOptions{AsOf: day(16)},
+			want: map[string]string{"villain": "evil", "old": "dark", "new": "bright"},
+		},
+		{
+			name: "after retcon",
+			opts: SearchOptions{AsOf: day(25)},
+			want: map[string]string{"villain": "redeemed", "old": "dark", "new": "bright"},
+		},
+		{
+			name: "type filter applies to history",
+			opts: SearchOptions{AsOf: day(10), Type: entities.FactTypeEvent},
+			want: map[string]string{"ring": "lost"},
+		},
+		{
+			name: "limit",
+			opts: SearchOptions{AsOf: day(10), Limit: 1},
+			want: map[string]string{"villain": "evil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts, err := svc.SearchWithOptions(t.Context(), "Who is Sauron?", tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, objects(facts))
+		})
+	}
+}
+
+func TestQueryService_SearchAsOf_RanksOnlyChangedFacts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	mordor := entities.Fact{ID: "mordor", Type: entities.FactTypeLocation, Subject: "Mordor", Predicate: "is", Object: "dark", CreatedAt: day(1)}
+	gondor := entities.Fact{ID: "gondor", Type: entities.FactTypeLocation, Subject: "Gondor", Predicate: "is", Object: "bright", CreatedAt: day(1)}
+	db := &mocks.VectorDB{Facts: []entities.Fact{mordor}}
+	relationalDB := &mocks.RelationalDB{Versions: []entities.FactVersion{
+		{FactID: "mordor", Version: 1, ChangeType: entities.ChangeCreation, Data: mordor, CreatedAt: day(1)},
+		{FactID: "gondor", Version: 1, ChangeType: entities.ChangeCreation, Data: gondor, CreatedAt: day(1)},
+		{FactID: "gondor", Version: 2, ChangeType: entities.ChangeDeletion, Data: gondor, CreatedAt: day(20)},
+	}}
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2, 0.3}}
+	svc := NewQueryService(emb, db, relationalDB)
+
+	_, err := svc.SearchWithOptions(t.Context(), "Which lands are there?", SearchOptions{AsOf: day(10)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gondor is bright"}, emb.EmbedBatchLastTexts, "facts unchanged since are searched as they are now")
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{"identical", []float32{1, 2}, []float32{1, 2}, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 1}, 0},
+		{"opposite", []float32{1, 0}, []float32{-1, 0}, -1},
+		{"length mismatch", []float32{1}, []float32{1, 2}, 0},
+		{"zero vector", []float32{0, 0}, []float32{1, 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, cosineSimilarity(tt.a, tt.b), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Prices are what the paid APIs charge, in USD per million tokens.
+type Prices struct {
+	LLMInput  float64
+	LLMOutput float64
+	Embedding float64
+}
+
+// Cost estimates what a call that used usage cost, in USD.
+func (p Prices) Cost(usage ports.TokenUsage) float64 {
+	var cost float64
+	switch usage.Backend {
+	case BackendLLM:
+		cost = float64(usage.PromptTokens)*p.LLMInput + float64(usage.CompletionTokens)*p.LLMOutput
+	case BackendEmbedder:
+		cost = float64(usage.PromptTokens) * p.Embedding
+	}
+	return cost / 1e6
+}
+
+// Quota holds soft limits on a world. Exceeding one is warned about once,
+// or refused if Strict.
+type Quota struct {
+	MonthlyBudget float64 // USD per calendar month of API calls (0 = no limit)
+	MaxFacts      int     // Facts stored in the world (0 = no limit)
+	Strict        bool    // Refuse calls and writes beyond a limit
+	Prices        Prices
+}
+
+// QuotaService records what a world's API calls cost and enforces its
+// Quota. It implements ports.UsageMeter.
+type QuotaService struct {
+	relationalDB ports.RelationalDB
+	quota        Quota
+	warn         func(message string) // nil = don't warn
+	now          func() time.Time
+
+	mu     sync.Mutex
+	month  time.Time // Start of the month spent covers; zero until loaded
+	spent  float64
+	warned map[string]bool
+}
+
+// NewQuotaService creates a new QuotaService. Limits exceeded outside
+// strict mode are passed to warn, if not nil, once each.
+func NewQuotaService(relationalDB ports.RelationalDB, quota Quota, warn func(message string)) *QuotaService {
+	return &QuotaService{
+		relationalDB: relationalDB,
+		quota:        quota,
+		warn:         warn,
+		now:          time.Now,
+		warned:       make(map[string]bool),
+	}
+}
+
+// Quota returns the limits enforced.
+func (s *QuotaService) Quota() Quota {
+	return s.quota
+}
+
+// Allow returns an entities.ErrConflict error in strict mode once this
+// month's budget is spent, and warns about it otherwise.
+func (s *QuotaService) Allow(ctx context.Context) error {
+	if s.quota.MonthlyBudget <= 0 {
+		return nil
+	}
+	spent, err := s.Spent(ctx)
+	if err != nil {
+		return err
+	}
+	if spent < s.quota.MonthlyBudget {
+		return nil
+	}
+	return s.exceeded("budget", fmt.Sprintf("this month's API budget of $%.2f is spent ($%.2f)", s.quota.MonthlyBudget, spent))
+}
+
+// Record records a call's usage and its cost.
+func (s *QuotaService) Record(ctx context.Context, usage ports.TokenUsage) error {
+	record := &entities.UsageRecord{
+		RecordedAt:       s.now(),
+		Backend:          usage.Backend,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Cost:             s.quota.Prices.Cost(usage),
+	}
+	if err := s.relationalDB.SaveUsage(ctx, record); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.month.Equal(monthStart(record.RecordedAt)) {
+		s.spent += record.Cost
+	}
+	return nil
+}
+
+// Spent returns what this month's API calls cost, in USD.
+func (s *QuotaService) Spent(ctx context.Context) (float64, error) {
+	month := monthStart(s.now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.month.Equal(month) {
+		return s.spent, nil
+	}
+
+	totals, err := s.relationalDB.SumUsage(ctx, month)
+	if err != nil {
+		return 0, err
+	}
+	s.spent = 0
+	for _, t := range totals {
+		s.spent += t.Cost
+	}
+	s.month = month
+	return s.spent, nil
+}
+
+// Usage totals this month's API calls by backend and model.
+func (s *QuotaService) Usage(ctx context.Context) ([]entities.UsageTotals, error) {
+	return s.relationalDB.SumUsage(ctx, monthStart(s.now()))
+}
+
+// CheckFacts returns an entities.ErrConflict error in strict mode if
+// storing added new facts in a world holding count would exceed MaxFacts,
+// and warns about it otherwise.
+func (s *QuotaService) CheckFacts(count uint64, added int) error {
+	if s.quota.MaxFacts <= 0 || count+uint64(added) <= uint64(s.quota.MaxFacts) {
+		return nil
+	}
+	return s.exceeded("facts", fmt.Sprintf("the world would hold %d facts, more than its limit of %d", count+uint64(added), s.quota.MaxFacts))
+}
+
+// exceeded refuses in strict mode, or warns once about the limit named
+// key.
+func (s *QuotaService) exceeded(key, message string) error {
+	if s.quota.Strict {
+		return entities.Errorf(entities.ErrConflict, "quota exceeded: %s", message)
+	}
+
+	s.mu.Lock()
+	warned := s.warned[key]
+	s.warned[key] = true
+	s.mu.Unlock()
+	if !warned && s.warn != nil {
+		s.warn(message)
+	}
+	return nil
+}
+
+// monthStart returns the start of the calendar month t is in.
+func monthStart(t time.Time) time.Time {
+	year, month, _ := t.Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+}
+
+// QuotaVectorDB is a ports.VectorDB whose saves are checked against a
+// QuotaService's limit on facts.
+type QuotaVectorDB struct {
+	ports.VectorDB
+	quota *QuotaService
+}
+
+// NewQuotaVectorDB wraps db so saves that would grow the world beyond the
+// quota's MaxFacts are warned about or refused.
+func NewQuotaVectorDB(db ports.VectorDB, quota *QuotaService) *QuotaVectorDB {
+	return &QuotaVectorDB{VectorDB: db, quota: quota}
+}
+
+// Save stores a fact with its embedding.
+func (d *QuotaVectorDB) Save(ctx context.Context, fact *entities.Fact) error {
+	if err := d.check(ctx, []entities.Fact{*fact}); err != nil {
+		return err
+	}
+	return d.VectorDB.Save(ctx, fact)
+}
+
+// SaveBatch stores multiple facts.
+func (d *QuotaVectorDB) SaveBatch(ctx context.Context, facts []entities.Fact) error {
+	if err := d.check(ctx, facts); err != nil {
+		return err
+	}
+	return d.VectorDB.SaveBatch(ctx, facts)
+}
+
+// check checks the facts not yet stored fit within the limit. Facts that
+// replace stored ones don't count.
+func (d *QuotaVectorDB) check(ctx context.Context, facts []entities.Fact) error {
+	if d.quota.quota.MaxFacts <= 0 || len(facts) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(facts))
+	for i := range facts {
+		if facts[i].ID != "" {
+			ids = append(ids, facts[i].ID)
+		}
+	}
+	exists, err := d.VectorDB.ExistsByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	added := 0
+	for i := range facts {
+		if !exists[facts[i].ID] {
+			added++
+		}
+	}
+	if added == 0 {
+		return nil
+	}
+	count, err := d.VectorDB.Count(ctx)
+	if err != nil {
+		return err
+	}
+	return d.quota.CheckFacts(count, added)
+}
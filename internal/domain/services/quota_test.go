@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// meteredEmbedder records usage for each call, as the OpenAI embedder does.
+type meteredEmbedder struct {
+	*mocks.Embedder
+}
+
+func (e *meteredEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	if err := ports.RecordUsage(ctx, ports.TokenUsage{Backend: BackendEmbedder, Model: "small", PromptTokens: 1_000_000}); err != nil {
+		return nil, err
+	}
+	return e.Embedder.Embed(ctx, text)
+}
+
+func TestPrices_Cost(t *testing.T) {
+	prices := Prices{LLMInput: 0.15, LLMOutput: 0.60, Embedding: 0.02}
+
+	assert.InDelta(t, 0.75, prices.Cost(ports.TokenUsage{Backend: BackendLLM, PromptTokens: 1_000_000, CompletionTokens: 1_000_000}), 1e-9)
+	assert.InDelta(t, 0.01, prices.Cost(ports.TokenUsage{Backend: BackendEmbedder, PromptTokens: 500_000}), 1e-9)
+	assert.Zero(t, prices.Cost(ports.TokenUsage{Backend: "unknown", PromptTokens: 1000}))
+}
+
+func TestQuotaService_Budget(t *testing.T) {
+	db := mocks.NewRelationalDB()
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	db.Usage = []entities.UsageRecord{
+		{RecordedAt: now.AddDate(0, 0, -31), Backend: BackendLLM, Cost: 100},
+		{RecordedAt: now.Add(-time.Hour), Backend: BackendLLM, Cost: 0.99},
+	}
+	var warnings []string
+	quota := NewQuotaService(db, Quota{MonthlyBudget: 1, Prices: Prices{Embedding: 0.02}}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	quota.now = func() time.Time { return now }
+
+	emb := NewBudgetedEmbedder(&meteredEmbedder{&mocks.Embedder{EmbeddingResult: []float32{0.1}}}, Budget{Meter: quota}, nil)
+	ctx := context.Background()
+
+	_, err := emb.Embed(ctx, "one")
+	require.NoError(t, err)
+	require.Len(t, db.Usage, 3)
+	assert.InDelta(t, 0.02, db.Usage[2].Cost, 1e-9)
+	assert.Empty(t, warnings, "last month's spending doesn't count")
+
+	for range 2 {
+		//nolint:loopcall // Repeated calls are the point of the test
+		_, err = emb.Embed(ctx, "again")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"this month's API budget of $1.00 is spent ($1.01)"}, warnings, "warned once")
+
+	strict := NewQuotaService(db, Quota{MonthlyBudget: 1, Strict: true}, nil)
+	strict.now = quota.now
+	emb = NewBudgetedEmbedder(&meteredEmbedder{&mocks.Embedder{}}, Budget{Meter: strict}, nil)
+	_, err = emb.Embed(ctx, "refused")
+	require.ErrorIs(t, err, entities.ErrConflict)
+	assert.Contains(t, err.Error(), "quota exceeded")
+	assert.Len(t, db.Usage, 5, "refused calls aren't made")
+
+	// A new month starts afresh
+	now = now.AddDate(0, 0, 1)
+	_, err = emb.Embed(ctx, "april")
+	assert.NoError(t, err)
+}
+
+func TestQuotaVectorDB(t *testing.T) {
+	db := &mocks.VectorDB{Facts: []entities.Fact{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+	var warnings []string
+	quota := NewQuotaService(mocks.NewRelationalDB(), Quota{MaxFacts: 3}, func(message string) {
+		warnings = append(warnings, message)
+	})
+	ctx := context.Background()
+
+	require.NoError(t, NewQuotaVectorDB(db, quota).SaveBatch(ctx, []entities.Fact{{ID: "2"}, {ID: "3"}}))
+	assert.Empty(t, warnings, "replaced facts don't count")
+
+	require.NoError(t, NewQuotaVectorDB(db, quota).Save(ctx, &entities.Fact{ID: "4"}))
+	assert.Equal(t, []string{"the world would hold 4 facts, more than its limit of 3"}, warnings)
+	assert.Len(t, db.SavedFacts, 1, "saved anyway")
+
+	strict := NewQuotaService(mocks.NewRelationalDB(), Quota{MaxFacts: 3, Strict: true}, nil)
+	err := NewQuotaVectorDB(db, strict).SaveBatch(ctx, []entities.Fact{{ID: "5"}})
+	require.ErrorIs(t, err, entities.ErrConflict)
+	assert.Equal(t, 1, db.SaveBatchCallCount, "refused saves aren't made")
+}
@@ -66,7 +66,7 @@ func (s *RelationshipService) Create(
 		return nil, fmt.Errorf("checking existing relationship: %w", err)
 	}
 	if existing != nil {
-		return nil, fmt.Errorf("relationship already exists between these entities (id: %s)", existing.ID)
+		return nil, entities.Errorf(entities.ErrConflict, "relationship already exists between these entities (id: %s)", existing.ID)
 	}
 
 	// Create relationship
@@ -146,6 +146,24 @@ func (s *RelationshipService) List(ctx context.Context, entityID string) ([]enti
 	return s.relationalDB.FindRelationshipsByEntity(ctx, entityID)
 }
 
+// ListWithOptions returns a page of relationships for an entity,
+// optionally filtered by type and sorted as requested.
+func (s *RelationshipService) ListWithOptions(ctx context.Context, entityID string, opts ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	if !opts.Sort.IsValid() {
+		return nil, entities.Errorf(entities.ErrValidation, "invalid sort order: %s (valid: created, type, target)", opts.Sort)
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return nil, entities.Errorf(entities.ErrValidation, "limit and offset must not be negative")
+	}
+	return s.relationalDB.ListRelationshipsByEntity(ctx, entityID, opts)
+}
+
+// CountByEntity returns the number of relationships for an entity,
+// optionally restricted to a single type.
+func (s *RelationshipService) CountByEntity(ctx context.Context, entityID, relType string) (int, error) {
+	return s.relationalDB.CountRelationshipsByEntity(ctx, entityID, relType)
+}
+
 // ListByName returns all relationships for an entity by name.
 func (s *RelationshipService) ListByName(ctx context.Context, worldID, entityName string) ([]entities.Relationship, error) {
 	entity, err := s.relationalDB.FindEntityByName(ctx, worldID, entityName)
@@ -184,6 +202,37 @@ func (s *RelationshipService) ListWithDepth(ctx context.Context, entityID string
 	return result, nil
 }
 
+// Neighborhood returns the named entity followed by the entities related to
+// it within depth hops. Depth 0 returns only the entity itself.
+func (s *RelationshipService) Neighborhood(ctx context.Context, worldID, entityName string, depth int) ([]*entities.Entity, error) {
+	entity, err := s.relationalDB.FindEntityByName(ctx, worldID, entityName)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity: %w", err)
+	}
+	if entity == nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "entity %q not found", entityName)
+	}
+
+	related, err := s.ListWithDepth(ctx, entity.ID, depth)
+	if err != nil {
+		return nil, err
+	}
+	if len(related) == 0 {
+		return []*entities.Entity{entity}, nil
+	}
+
+	ids := make([]string, len(related))
+	for i, r := range related {
+		ids[i] = r.EntityID
+	}
+	neighbors, err := s.relationalDB.FindEntitiesByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("finding related entities: %w", err)
+	}
+
+	return append([]*entities.Entity{entity}, neighbors...), nil
+}
+
 // FindBetween finds a direct relationship between two entities.
 func (s *RelationshipService) FindBetween(ctx context.Context, sourceEntityID, targetEntityID string) (*entities.Relationship, error) {
 	return s.relationalDB.FindRelationshipBetween(ctx, sourceEntityID, targetEntityID)
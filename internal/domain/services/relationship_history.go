@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// historyFactLimit bounds the facts naming either entity that History
+// reads.
+const historyFactLimit = 1000
+
+// RelationshipEvent is one state of a fact linking two entities: the fact
+// as first recorded, or as a later correction, retcon, or update left it.
+type RelationshipEvent struct {
+	Time    time.Time           `json:"time"`
+	FactID  string              `json:"fact_id"`
+	Version int                 `json:"version"`
+	Change  entities.ChangeType `json:"change"`
+	Fact    entities.Fact       `json:"fact"`
+	Reason  string              `json:"reason,omitempty"`
+	// Until is when the next version replaced this one, or nil while it
+	// is the fact's current state.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// RelationshipHistory is how the relationship between two entities changed
+// over time.
+type RelationshipHistory struct {
+	Source *entities.Entity `json:"source"`
+	Target *entities.Entity `json:"target"`
+	// Relationship is the entities' current relationship, if any.
+	Relationship *entities.Relationship `json:"relationship,omitempty"`
+	// Events are the states of the facts linking the entities, oldest
+	// first.
+	Events []RelationshipEvent `json:"events"`
+}
+
+// History reconstructs how the relationship between two entities changed:
+// every fact with one as subject and the other as object, the
+// relationship's own fact among them, with each version recorded in its
+// history, in the order the versions were made. Facts without history
+// count as created when first stored.
+func (s *RelationshipService) History(ctx context.Context, worldID, sourceName, targetName string) (*RelationshipHistory, error) {
+	source, err := s.findEntity(ctx, worldID, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.findEntity(ctx, worldID, targetName)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, entities.Errorf(entities.ErrValidation, "%q and %q are the same entity", sourceName, targetName)
+	}
+
+	history := &RelationshipHistory{Source: source, Target: target, Events: []RelationshipEvent{}}
+	if history.Relationship, err = s.findEitherWay(ctx, source.ID, target.ID); err != nil {
+		return nil, err
+	}
+
+	names := uniqueNames(source.Name, sourceName, target.Name, targetName)
+	facts, err := s.vectorDB.ListByEntities(ctx, names, historyFactLimit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts: %w", err)
+	}
+
+	for i := range facts {
+		if !links(&facts[i], source, target) {
+			continue
+		}
+		events, err := s.factEvents(ctx, &facts[i])
+		if err != nil {
+			return nil, err
+		}
+		history.Events = append(history.Events, events...)
+	}
+
+	slices.SortStableFunc(history.Events, func(a, b RelationshipEvent) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+		return a.Version - b.Version
+	})
+	return history, nil
+}
+
+// findEntity finds a named entity, reporting a missing one as not found.
+func (s *RelationshipService) findEntity(ctx context.Context, worldID, name string) (*entities.Entity, error) {
+	entity, err := s.relationalDB.FindEntityByName(ctx, worldID, name)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity: %w", err)
+	}
+	if entity == nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "entity %q not found", name)
+	}
+	return entity, nil
+}
+
+// findEitherWay finds the relationship between two entities in either
+// direction.
+func (s *RelationshipService) findEitherWay(ctx context.Context, aID, bID string) (*entities.Relationship, error) {
+	rel, err := s.relationalDB.FindRelationshipBetween(ctx, aID, bID)
+	if err != nil || rel != nil {
+		return rel, err
+	}
+	return s.relationalDB.FindRelationshipBetween(ctx, bID, aID)
+}
+
+// factEvents returns the states fact has been in, oldest first, each
+// ending when the next began.
+func (s *RelationshipService) factEvents(ctx context.Context, fact *entities.Fact) ([]RelationshipEvent, error) {
+	versions, err := s.relationalDB.FindVersionsByFact(ctx, fact.ID)
+	if err != nil {
+		return nil, fmt.Errorf("finding versions of %s: %w", fact.ID, err)
+	}
+	if len(versions) == 0 {
+		event := RelationshipEvent{
+			Time:    fact.CreatedAt,
+			FactID:  fact.ID,
+			Version: 1,
+			Change:  entities.ChangeCreation,
+			Fact:    *fact,
+		}
+		event.Fact.Embedding, event.Fact.TextEmbedding = nil, nil
+		return []RelationshipEvent{event}, nil
+	}
+
+	events := make([]RelationshipEvent, len(versions))
+	// Versions come newest first.
+	for i := range versions {
+		v := &versions[len(versions)-1-i]
+		events[i] = RelationshipEvent{
+			Time:    v.CreatedAt,
+			FactID:  v.FactID,
+			Version: v.Version,
+			Change:  v.ChangeType,
+			Fact:    v.Data,
+			Reason:  v.Reason,
+		}
+		if i > 0 {
+			until := v.CreatedAt
+			events[i-1].Until = &until
+		}
+	}
+	return events, nil
+}
+
+// links reports whether fact has one entity as its subject and the other
+// as its object.
+func links(fact *entities.Fact, a, b *entities.Entity) bool {
+	subject := entities.NormalizeName(fact.Subject)
+	object := entities.NormalizeName(fact.Object)
+	return (subject == a.NormalizedName && object == b.NormalizedName) ||
+		(subject == b.NormalizedName && object == a.NormalizedName)
+}
+
+// uniqueNames returns names without repeats, in order.
+func uniqueNames(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		if !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
@@ -3,11 +3,13 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +77,10 @@ func (m *relTestVectorDB) SearchByType(_ context.Context, _ []float32, _ entitie
 	return nil, nil
 }
 
+func (m *relTestVectorDB) SearchBySubject(_ context.Context, _ []float32, _ string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+
 func (m *relTestVectorDB) Delete(_ context.Context, id string) error {
 	if m.deleteErr != nil {
 		return m.deleteErr
@@ -86,20 +92,59 @@ func (m *relTestVectorDB) Delete(_ context.Context, id string) error {
 func (m *relTestVectorDB) List(_ context.Context, _ int, _ uint64) ([]entities.Fact, error) {
 	return nil, nil
 }
+func (m *relTestVectorDB) ListPage(_ context.Context, _ ports.FactPageOptions) ([]entities.Fact, string, error) {
+	return nil, "", nil
+}
 func (m *relTestVectorDB) ListByType(_ context.Context, _ entities.FactType, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
+func (m *relTestVectorDB) SearchVector(_ context.Context, _ ports.VectorName, _ []float32, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) SearchKeywords(_ context.Context, _ string, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListFiltered(_ context.Context, _ ports.FactListOptions) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListByEntities(_ context.Context, names []string, _ int) ([]entities.Fact, error) {
+	var result []entities.Fact
+	for id := range m.facts {
+		fact := m.facts[id]
+		for _, name := range names {
+			if fact.Subject == name || fact.Object == name {
+				result = append(result, fact)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+func (m *relTestVectorDB) ListPending(_ context.Context, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListBySubject(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListByClaimKeys(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
 func (m *relTestVectorDB) ListBySource(_ context.Context, _ string, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
-func (m *relTestVectorDB) DeleteBySource(_ context.Context, _ string) error { return nil }
-func (m *relTestVectorDB) DeleteAll(_ context.Context) error                { return nil }
-func (m *relTestVectorDB) Count(_ context.Context) (uint64, error)          { return 0, nil }
+func (m *relTestVectorDB) CountBySubject(_ context.Context, _ []string) (uint64, error) {
+	return 0, nil
+}
+func (m *relTestVectorDB) DeleteBySource(_ context.Context, _ string) error            { return nil }
+func (m *relTestVectorDB) SetSourceArchived(_ context.Context, _ string, _ bool) error { return nil }
+func (m *relTestVectorDB) DeleteAll(_ context.Context) error                           { return nil }
+func (m *relTestVectorDB) Count(_ context.Context) (uint64, error)                     { return 0, nil }
 
 // relTestRelationalDB is a test mock for RelationalDB with relationship support.
 type relTestRelationalDB struct {
 	entities      map[string]*entities.Entity
 	relationships map[string]*entities.Relationship
+	versions      map[string][]entities.FactVersion
 	saveErr       error
 	deleteErr     error
 	findErr       error
@@ -188,6 +233,10 @@ func (m *relTestRelationalDB) SearchEntities(_ context.Context, _, _ string, _ i
 	return nil, nil
 }
 
+func (m *relTestRelationalDB) RenameEntity(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *relTestRelationalDB) DeleteEntity(_ context.Context, entityID string) error {
 	if m.deleteErr != nil {
 		return m.deleteErr
@@ -227,6 +276,64 @@ func (m *relTestRelationalDB) FindRelationshipsByEntity(_ context.Context, entit
 	return result, nil
 }
 
+func (m *relTestRelationalDB) ListRelationshipsByEntity(_ context.Context, entityID string, opts ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	var result []entities.Relationship
+	for _, rel := range m.relationships {
+		if opts.Type != "" && string(rel.Type) != opts.Type {
+			continue
+		}
+		if rel.SourceEntityID == entityID || (rel.TargetEntityID == entityID && rel.Bidirectional) {
+			result = append(result, *rel)
+		}
+	}
+	otherName := func(rel entities.Relationship) string {
+		otherID := rel.SourceEntityID
+		if rel.SourceEntityID == entityID {
+			otherID = rel.TargetEntityID
+		}
+		if e, ok := m.entities[otherID]; ok {
+			return e.NormalizedName
+		}
+		return ""
+	}
+	sort.Slice(result, func(i, j int) bool {
+		switch opts.Sort {
+		case ports.RelationshipSortType:
+			if result[i].Type != result[j].Type {
+				return result[i].Type < result[j].Type
+			}
+		case ports.RelationshipSortTarget:
+			if ni, nj := otherName(result[i]), otherName(result[j]); ni != nj {
+				return ni < nj
+			}
+		}
+		return result[i].ID < result[j].ID
+	})
+	if opts.Offset >= len(result) {
+		return []entities.Relationship{}, nil
+	}
+	result = result[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(result) {
+		result = result[:opts.Limit]
+	}
+	return result, nil
+}
+
+func (m *relTestRelationalDB) CountRelationshipsByEntity(ctx context.Context, entityID string, relType string) (int, error) {
+	if m.findErr != nil {
+		return 0, m.findErr
+	}
+	rels, err := m.ListRelationshipsByEntity(ctx, entityID, ports.RelationshipListOptions{Type: relType})
+	return len(rels), err
+}
+
+func (m *relTestRelationalDB) CountRelationshipsPerEntity(_ context.Context, _ string) (map[string]int, error) {
+	return nil, nil
+}
+
 func (m *relTestRelationalDB) FindRelationshipsByType(_ context.Context, _ string) ([]entities.Relationship, error) {
 	return nil, nil
 }
@@ -243,6 +350,10 @@ func (m *relTestRelationalDB) DeleteRelationshipsByEntity(_ context.Context, _ s
 	return nil
 }
 
+func (m *relTestRelationalDB) MergeEntities(_ context.Context, _, _ string) error {
+	return nil
+}
+
 func (m *relTestRelationalDB) FindRelationshipBetween(_ context.Context, sourceID, targetID string) (*entities.Relationship, error) {
 	if m.findErr != nil {
 		return nil, m.findErr
@@ -300,13 +411,16 @@ func (m *relTestRelationalDB) DeleteEntityType(_ context.Context, _ string) erro
 func (m *relTestRelationalDB) SaveVersion(_ context.Context, _ *entities.FactVersion) error {
 	return nil
 }
-func (m *relTestRelationalDB) FindVersionsByFact(_ context.Context, _ string) ([]entities.FactVersion, error) {
-	return nil, nil
+func (m *relTestRelationalDB) FindVersionsByFact(_ context.Context, factID string) ([]entities.FactVersion, error) {
+	return m.versions[factID], nil
 }
 func (m *relTestRelationalDB) FindLatestVersion(_ context.Context, _ string) (*entities.FactVersion, error) {
 	return nil, nil
 }
 func (m *relTestRelationalDB) CountVersions(_ context.Context, _ string) (int, error) { return 0, nil }
+func (m *relTestRelationalDB) ListVersionsAsOf(_ context.Context, _ time.Time) ([]entities.FactVersion, error) {
+	return nil, nil
+}
 func (m *relTestRelationalDB) LogAction(_ context.Context, _ string, _ string, _ map[string]any) error {
 	return nil
 }
@@ -316,6 +430,121 @@ func (m *relTestRelationalDB) FindAuditLog(_ context.Context, _ string) ([]entit
 func (m *relTestRelationalDB) FindAuditLogByAction(_ context.Context, _ string, _ int) ([]entities.AuditEntry, error) {
 	return nil, nil
 }
+func (m *relTestRelationalDB) SaveConflict(_ context.Context, _ *entities.Conflict) (bool, error) {
+	return true, nil
+}
+func (m *relTestRelationalDB) ListConflicts(_ context.Context, _ ports.ConflictListOptions) ([]entities.Conflict, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) ResolveConflict(_ context.Context, _ string, _ string, _ time.Time) error {
+	return nil
+}
+func (m *relTestRelationalDB) CountOpenConflicts(_ context.Context, _ []string) (map[string]int, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveTranslation(_ context.Context, _ *entities.Translation) (bool, error) {
+	return true, nil
+}
+func (m *relTestRelationalDB) ListTranslations(_ context.Context, _ []string) ([]entities.Translation, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveHealthSample(_ context.Context, _ *entities.HealthSample) error {
+	return nil
+}
+func (m *relTestRelationalDB) ListHealthSamples(_ context.Context, _ int) ([]entities.HealthSample, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) FindConsistencyVerdicts(_ context.Context, _ []string, _ time.Time) (map[string]entities.ConsistencyVerdict, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveConsistencyVerdicts(_ context.Context, _ []entities.ConsistencyVerdict) error {
+	return nil
+}
+func (m *relTestRelationalDB) PruneConsistencyVerdicts(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+func (m *relTestRelationalDB) SaveStyleTerm(_ context.Context, _ *entities.StyleTerm) error {
+	return nil
+}
+func (m *relTestRelationalDB) ListStyleTerms(_ context.Context) ([]entities.StyleTerm, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveSourceStats(_ context.Context, _ *entities.SourceStats) error {
+	return nil
+}
+func (m *relTestRelationalDB) ListSourceStats(_ context.Context) ([]entities.SourceStats, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveArchivedSource(_ context.Context, _ *entities.ArchivedSource) error {
+	return nil
+}
+func (m *relTestRelationalDB) DeleteArchivedSource(_ context.Context, _ string) error {
+	return nil
+}
+func (m *relTestRelationalDB) ListArchivedSources(_ context.Context) ([]entities.ArchivedSource, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) SaveFailedChunk(_ context.Context, _ *entities.FailedChunk) error {
+	return nil
+}
+func (m *relTestRelationalDB) ListFailedChunks(_ context.Context) ([]entities.FailedChunk, error) {
+	return nil, nil
+}
+func (m *relTestRelationalDB) DeleteFailedChunk(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) SaveUsage(_ context.Context, _ *entities.UsageRecord) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) SumUsage(_ context.Context, _ time.Time) ([]entities.UsageTotals, error) {
+	return nil, nil
+}
+
+func (m *relTestRelationalDB) SaveFactSubjects(_ context.Context, _ []entities.FactSubject) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) DeleteFactSubjects(_ context.Context, _ ports.FactSubjectFilter) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) ListFactIDsByEntities(_ context.Context, _ []string, _ int) ([]string, error) {
+	return nil, nil
+}
+
+func (m *relTestRelationalDB) SaveExtractionStat(_ context.Context, _ *entities.ExtractionStat) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) SumExtractionStats(_ context.Context) ([]entities.ModelStats, error) {
+	return nil, nil
+}
+
+func (m *relTestRelationalDB) FindIdempotencyRecord(_ context.Context, _ string) (*entities.IdempotencyRecord, error) {
+	return nil, nil
+}
+
+func (m *relTestRelationalDB) SaveIdempotencyRecord(_ context.Context, _ *entities.IdempotencyRecord) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) ClaimIdempotencyKey(_ context.Context, _ *entities.IdempotencyRecord, _ time.Time) (bool, error) {
+	return true, nil
+}
+
+func (m *relTestRelationalDB) ReleaseIdempotencyKey(_ context.Context, _ string) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) RecordChanges(_ context.Context, _ []entities.Change) error {
+	return nil
+}
+
+func (m *relTestRelationalDB) ListChanges(_ context.Context, _ int64, _ int) ([]entities.Change, error) {
+	return nil, nil
+}
 
 // relTestEmbedder is a test mock for Embedder.
 type relTestEmbedder struct {
@@ -406,6 +635,7 @@ func TestRelationshipService_Create(t *testing.T) {
 		_, err := svc.Create(ctx, testWorldID, "Alice", entities.RelationAlly, "Bob", false)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "relationship already exists")
+		assert.ErrorIs(t, err, entities.ErrConflict)
 	})
 
 	t.Run("embedding error rolls back", func(t *testing.T) {
@@ -508,6 +738,45 @@ func TestRelationshipService_List(t *testing.T) {
 	})
 }
 
+func TestRelationshipService_ListWithOptions(t *testing.T) {
+	t.Run("pages and filters relationships", func(t *testing.T) {
+		svc, _, relationalDB, _ := setupRelationshipTest()
+		ctx := context.Background()
+
+		for _, rel := range []*entities.Relationship{
+			{ID: "rel-1", SourceEntityID: "entity-1", TargetEntityID: "entity-2", Type: entities.RelationAlly},
+			{ID: "rel-2", SourceEntityID: "entity-1", TargetEntityID: "entity-3", Type: entities.RelationAlly},
+			{ID: "rel-3", SourceEntityID: "entity-1", TargetEntityID: "entity-4", Type: entities.RelationEnemy},
+		} {
+			relationalDB.relationships[rel.ID] = rel
+		}
+
+		rels, err := svc.ListWithOptions(ctx, "entity-1", ports.RelationshipListOptions{Type: "ally", Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, rels, 1)
+
+		count, err := svc.CountByEntity(ctx, "entity-1", "ally")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("rejects invalid sort", func(t *testing.T) {
+		svc, _, _, _ := setupRelationshipTest()
+
+		_, err := svc.ListWithOptions(context.Background(), "entity-1", ports.RelationshipListOptions{Sort: "age"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid sort order")
+		assert.ErrorIs(t, err, entities.ErrValidation)
+	})
+
+	t.Run("rejects negative offset", func(t *testing.T) {
+		svc, _, _, _ := setupRelationshipTest()
+
+		_, err := svc.ListWithOptions(context.Background(), "entity-1", ports.RelationshipListOptions{Offset: -1})
+		require.Error(t, err)
+	})
+}
+
 func TestRelationshipService_ListByName(t *testing.T) {
 	t.Run("returns relationships for entity by name", func(t *testing.T) {
 		svc, _, relationalDB, _ := setupRelationshipTest()
@@ -571,6 +840,48 @@ func TestRelationshipService_ListWithDepth(t *testing.T) {
 	})
 }
 
+func TestRelationshipService_Neighborhood(t *testing.T) {
+	svc, _, relationalDB, _ := setupRelationshipTest()
+	ctx := context.Background()
+
+	for _, name := range []string{"Frodo", "Sam"} {
+		id := "entity-" + entities.NormalizeName(name)
+		relationalDB.entities[id] = &entities.Entity{
+			ID:             id,
+			WorldID:        testWorldID,
+			Name:           name,
+			NormalizedName: entities.NormalizeName(name),
+		}
+	}
+	relationalDB.relationships["rel-1"] = &entities.Relationship{
+		ID:             "rel-1",
+		SourceEntityID: "entity-frodo",
+		TargetEntityID: "entity-sam",
+		Type:           entities.RelationAlly,
+	}
+
+	t.Run("includes entity and related entities", func(t *testing.T) {
+		result, err := svc.Neighborhood(ctx, testWorldID, "frodo", 1)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "Frodo", result[0].Name)
+		assert.Equal(t, "Sam", result[1].Name)
+	})
+
+	t.Run("depth 0 returns only the entity", func(t *testing.T) {
+		result, err := svc.Neighborhood(ctx, testWorldID, "Frodo", 0)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Frodo", result[0].Name)
+	})
+
+	t.Run("unknown entity", func(t *testing.T) {
+		_, err := svc.Neighborhood(ctx, testWorldID, "Sauron", 1)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
+
 func TestRelationshipService_FindBetween(t *testing.T) {
 	t.Run("finds direct relationship", func(t *testing.T) {
 		svc, _, relationalDB, _ := setupRelationshipTest()
@@ -631,3 +942,48 @@ func TestRelationshipService_Count(t *testing.T) {
 		assert.Equal(t, 2, count)
 	})
 }
+
+func TestRelationshipService_History(t *testing.T) {
+	svc, vectorDB, relationalDB, _ := setupRelationshipTest()
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rel, err := svc.Create(ctx, testWorldID, "Frodo", entities.RelationAlly, "Sam", true)
+	require.NoError(t, err)
+	relFact := vectorDB.facts[rel.ID]
+	relFact.CreatedAt = day.AddDate(0, 0, 2)
+	vectorDB.facts[rel.ID] = relFact
+
+	_, err = svc.Create(ctx, testWorldID, "Frodo", entities.RelationEnemy, "Gollum", false)
+	require.NoError(t, err)
+
+	vectorDB.facts["f1"] = entities.Fact{ID: "f1", Subject: "Sam", Predicate: "serves", Object: "frodo", SourceFile: "book2.md", CreatedAt: day}
+	relationalDB.versions = map[string][]entities.FactVersion{
+		"f1": {
+			{FactID: "f1", Version: 2, ChangeType: entities.ChangeRetcon, Reason: "friends, not servants",
+				Data: entities.Fact{Subject: "Sam", Predicate: "befriends", Object: "Frodo"}, CreatedAt: day.AddDate(0, 0, 5)},
+			{FactID: "f1", Version: 1, ChangeType: entities.ChangeCreation,
+				Data: entities.Fact{Subject: "Sam", Predicate: "serves", Object: "Frodo"}, CreatedAt: day},
+		},
+	}
+
+	history, err := svc.History(ctx, testWorldID, "sam", "Frodo")
+	require.NoError(t, err)
+	assert.Equal(t, "Sam", history.Source.Name)
+	require.NotNil(t, history.Relationship, "found in either direction")
+	assert.Equal(t, rel.ID, history.Relationship.ID)
+
+	var got []string
+	for _, e := range history.Events {
+		got = append(got, fmt.Sprintf("%s v%d %s", e.FactID, e.Version, e.Fact.Predicate))
+	}
+	assert.Equal(t, []string{"f1 v1 serves", rel.ID + " v1 ally", "f1 v2 befriends"}, got, "oldest first, Gollum left out")
+	require.NotNil(t, history.Events[0].Until)
+	assert.Equal(t, day.AddDate(0, 0, 5), *history.Events[0].Until)
+	assert.Nil(t, history.Events[2].Until, "the current state has no end")
+
+	_, err = svc.History(ctx, testWorldID, "Frodo", "Boromir")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+	_, err = svc.History(ctx, testWorldID, "Frodo", "frodo")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
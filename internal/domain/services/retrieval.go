@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// RetrievalStrategy selects the stored facts each fact is checked against
+// for consistency.
+type RetrievalStrategy string
+
+const (
+	// RetrievalByType checks against the most similar facts of the same type.
+	RetrievalByType RetrievalStrategy = "type"
+	// RetrievalBySubject checks against the most similar facts with exactly
+	// the same subject, of any type.
+	RetrievalBySubject RetrievalStrategy = "subject"
+	// RetrievalGlobal checks against the most similar facts of any type.
+	RetrievalGlobal RetrievalStrategy = "global"
+	// RetrievalGraph checks against facts mentioning the fact's subject or
+	// an entity related to it.
+	RetrievalGraph RetrievalStrategy = "graph"
+)
+
+// RetrievalStrategies lists the valid retrieval strategies.
+var RetrievalStrategies = []RetrievalStrategy{RetrievalByType, RetrievalBySubject, RetrievalGlobal, RetrievalGraph}
+
+// DefaultRetrievalLimit is how many stored facts each fact is checked
+// against when no limit is set.
+const DefaultRetrievalLimit = 5
+
+// IsValid reports whether s is a known strategy. Empty means RetrievalByType.
+func (s RetrievalStrategy) IsValid() bool {
+	if s == "" {
+		return true
+	}
+	for _, valid := range RetrievalStrategies {
+		if s == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRetrievalStrategy returns the named strategy, or a validation error
+// listing the valid ones.
+func ParseRetrievalStrategy(name string) (RetrievalStrategy, error) {
+	s := RetrievalStrategy(name)
+	if !s.IsValid() {
+		names := make([]string, len(RetrievalStrategies))
+		for i, valid := range RetrievalStrategies {
+			names[i] = string(valid)
+		}
+		return "", entities.Errorf(entities.ErrValidation, "invalid retrieval strategy %q (valid: %s)", name, strings.Join(names, ", "))
+	}
+	return s, nil
+}
+
+// Retrieval configures how the stored facts compared with each fact in a
+// consistency check are found. The zero value checks against the
+// DefaultRetrievalLimit most similar facts of the same type.
+type Retrieval struct {
+	Strategy RetrievalStrategy // Empty means RetrievalByType
+	Limit    int               // Stored facts per fact (0 = DefaultRetrievalLimit)
+
+	// Related returns the names of the entities related to the named one,
+	// for RetrievalGraph. Nil, or no entity by that name, falls back to
+	// facts mentioning the subject alone.
+	Related func(ctx context.Context, name string) ([]string, error)
+
+	// BatchSize is how many new facts one LLM call checks (0 =
+	// DefaultCheckBatchSize). More are split into several calls, up
+	// to Concurrency of them at a time (0 = DefaultConsistencyConcurrency).
+	BatchSize   int
+	Concurrency int
+
+	// Audit records how long each call took (nil = not recorded).
+	Audit auditLogger
+
+	// Prefilter reports obvious contradictions without the LLM and leaves
+	// unrelated facts out of its calls.
+	Prefilter Prefilter
+}
+
+// batchSize returns the number of new facts one LLM call checks.
+func (r Retrieval) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DefaultCheckBatchSize
+	}
+	return r.BatchSize
+}
+
+// concurrency returns the number of LLM calls run at a time.
+func (r Retrieval) concurrency() int {
+	if r.Concurrency <= 0 {
+		return DefaultConsistencyConcurrency
+	}
+	return r.Concurrency
+}
+
+// candidates returns the stored facts to check each of facts against,
+// deduplicated across facts. Facts sharing a claim key with one of facts
+// come first, up to limit per fact, then those the strategy finds.
+func (r Retrieval) candidates(ctx context.Context, vectorDB ports.VectorDB, facts []entities.Fact) ([]entities.Fact, error) {
+	limit := r.Limit
+	if limit <= 0 {
+		limit = DefaultRetrievalLimit
+	}
+
+	claimed, err := sameClaim(ctx, vectorDB, facts, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []entities.Fact
+	seen := make(map[string]bool)
+	for i := range claimed {
+		if !seen[claimed[i].ID] {
+			seen[claimed[i].ID] = true
+			all = append(all, claimed[i])
+		}
+	}
+
+	related := make(map[string][]string) // Subject to related names, looked up once
+	for i := range facts {
+		found, err := r.candidatesFor(ctx, vectorDB, &facts[i], limit, related)
+		if err != nil {
+			return nil, err
+		}
+		for j := range found {
+			if !seen[found[j].ID] {
+				seen[found[j].ID] = true
+				all = append(all, found[j])
+			}
+		}
+	}
+	return all, nil
+}
+
+func (r Retrieval) candidatesFor(ctx context.Context, vectorDB ports.VectorDB, fact *entities.Fact, limit int, related map[string][]string) ([]entities.Fact, error) {
+	switch r.Strategy {
+	case RetrievalGlobal:
+		found, err := vectorDB.Search(ctx, fact.Embedding, limit)
+		if err != nil {
+			return nil, fmt.Errorf("searching similar facts: %w", err)
+		}
+		return found, nil
+	case RetrievalBySubject:
+		found, err := vectorDB.SearchBySubject(ctx, fact.Embedding, fact.Subject, limit)
+		if err != nil {
+			return nil, fmt.Errorf("searching facts about %s: %w", fact.Subject, err)
+		}
+		return found, nil
+	case RetrievalGraph:
+		names, ok := related[fact.Subject]
+		if !ok {
+			names = []string{fact.Subject}
+			if r.Related != nil {
+				neighbors, err := r.Related(ctx, fact.Subject)
+				if err != nil {
+					return nil, fmt.Errorf("finding entities related to %q: %w", fact.Subject, err)
+				}
+				names = append(names, neighbors...)
+			}
+			related[fact.Subject] = names
+		}
+		return mentioning(ctx, vectorDB, names, limit)
+	default:
+		found, err := vectorDB.SearchByType(ctx, fact.Embedding, fact.Type, limit)
+		if err != nil {
+			return nil, fmt.Errorf("searching similar facts: %w", err)
+		}
+		return found, nil
+	}
+}
+
+// sameClaim returns the stored facts other than facts themselves sharing a
+// claim key with one of them, up to limit per distinct key, through an
+// exact payload filter rather than a similarity search.
+func sameClaim(ctx context.Context, vectorDB ports.VectorDB, facts []entities.Fact, limit int) ([]entities.Fact, error) {
+	var keys []string
+	checked := make(map[string]bool, len(facts))
+	for i := range facts {
+		checked[facts[i].ID] = true
+		if key := facts[i].ClaimKey(); !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	// Facts already stored find themselves, so leave room for them
+	found, err := vectorDB.ListByClaimKeys(ctx, keys, limit*len(keys)+len(facts))
+	if err != nil {
+		return nil, fmt.Errorf("listing facts with the same claim: %w", err)
+	}
+	others := found[:0]
+	for i := range found {
+		if !checked[found[i].ID] {
+			others = append(others, found[i])
+		}
+	}
+	return others, nil
+}
+
+// mentioning returns up to limit facts whose subject or object is one of
+// names, skipping facts pending review as searches do.
+func mentioning(ctx context.Context, vectorDB ports.VectorDB, names []string, limit int) ([]entities.Fact, error) {
+	found, err := vectorDB.ListByEntities(ctx, names, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts mentioning %s: %w", strings.Join(names, ", "), err)
+	}
+	active := found[:0]
+	for i := range found {
+		if !found[i].IsPending() {
+			active = append(active, found[i])
+		}
+	}
+	return active, nil
+}
+
+// RelatedEntityNames returns a Retrieval.Related func that looks up the
+// entities directly related to the named one in worldID.
+func RelatedEntityNames(relationalDB ports.RelationalDB, worldID string) func(ctx context.Context, name string) ([]string, error) {
+	return func(ctx context.Context, name string) ([]string, error) {
+		entity, err := relationalDB.FindEntityByName(ctx, worldID, name)
+		if err != nil {
+			return nil, fmt.Errorf("finding entity: %w", err)
+		}
+		if entity == nil {
+			return nil, nil
+		}
+
+		ids, err := relationalDB.FindRelatedEntities(ctx, entity.ID, 1)
+		if err != nil {
+			return nil, fmt.Errorf("finding related entities: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		related, err := relationalDB.FindEntitiesByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("loading related entities: %w", err)
+		}
+
+		names := make([]string, len(related))
+		for i, e := range related {
+			names[i] = e.Name
+		}
+		return names, nil
+	}
+}
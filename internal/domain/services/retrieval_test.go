@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func retrievalTestFacts() []entities.Fact {
+	return []entities.Fact{
+		{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"},
+		{ID: "b", Type: entities.FactTypeLocation, Subject: "Bag End", Predicate: "located_in", Object: "the Shire"},
+		{ID: "c", Type: entities.FactTypeEvent, Subject: "Frodo", Predicate: "left", Object: "the Shire"},
+		{ID: "d", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "gardener_of", Object: "Bag End"},
+		{ID: "e", Type: entities.FactTypeCharacter, Subject: "Frodo", Status: entities.FactStatusPending},
+	}
+}
+
+func TestRetrieval_Candidates(t *testing.T) {
+	newFact := entities.Fact{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Rivendell"}
+
+	tests := []struct {
+		name      string
+		retrieval Retrieval
+		want      []string
+	}{
+		{
+			name: "zero value searches the same type",
+			want: []string{"a", "d", "e"},
+		},
+		{
+			name:      "type with limit",
+			retrieval: Retrieval{Strategy: RetrievalByType, Limit: 1},
+			want:      []string{"a"},
+		},
+		{
+			name:      "subject searches facts of any type with the same subject",
+			retrieval: Retrieval{Strategy: RetrievalBySubject, Limit: 2},
+			want:      []string{"a", "c"},
+		},
+		{
+			name:      "global searches every type",
+			retrieval: Retrieval{Strategy: RetrievalGlobal, Limit: 3},
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name: "graph adds facts about related entities",
+			retrieval: Retrieval{
+				Strategy: RetrievalGraph,
+				Related: func(_ context.Context, name string) ([]string, error) {
+					return []string{"Sam"}, nil
+				},
+			},
+			want: []string{"a", "c", "d"},
+		},
+		{
+			name:      "graph without related entities falls back to the subject",
+			retrieval: Retrieval{Strategy: RetrievalGraph},
+			want:      []string{"a", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vectorDB := &mocks.VectorDB{Facts: retrievalTestFacts()}
+
+			found, err := tt.retrieval.candidates(context.Background(), vectorDB, []entities.Fact{newFact})
+			require.NoError(t, err)
+
+			ids := make([]string, len(found))
+			for i := range found {
+				ids[i] = found[i].ID
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestRetrieval_Candidates_SameClaim(t *testing.T) {
+	facts := append(retrievalTestFacts(),
+		entities.Fact{ID: "f", Type: entities.FactTypeEvent, Subject: "frodo", Predicate: "Lives_In", Object: "Crickhollow"},
+	)
+	vectorDB := &mocks.VectorDB{Facts: facts}
+	newFact := entities.Fact{ID: "a", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "Bag End"}
+
+	found, err := Retrieval{Limit: 1}.candidates(context.Background(), vectorDB, []entities.Fact{newFact})
+	require.NoError(t, err)
+	ids := make([]string, len(found))
+	for i := range found {
+		ids[i] = found[i].ID
+	}
+	assert.Equal(t, []string{"f", "a"}, ids, "a fact with the same claim comes first, whatever its type, then the search")
+}
+
+func TestRetrieval_Candidates_Graph(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: retrievalTestFacts()}
+	calls := 0
+	retrieval := Retrieval{
+		Strategy: RetrievalGraph,
+		Related: func(_ context.Context, name string) ([]string, error) {
+			calls++
+			return nil, nil
+		},
+	}
+	facts := []entities.Fact{{Subject: "Frodo"}, {Subject: "Frodo"}, {Subject: "Sam"}}
+
+	found, err := retrieval.candidates(context.Background(), vectorDB, facts)
+	require.NoError(t, err)
+	assert.Len(t, found, 3, "facts found for both subjects are listed once")
+	assert.Equal(t, 2, calls, "related entities are looked up once per subject")
+
+	retrieval.Related = func(context.Context, string) ([]string, error) {
+		return nil, errors.New("database locked")
+	}
+	_, err = retrieval.candidates(context.Background(), vectorDB, facts)
+	assert.ErrorContains(t, err, `finding entities related to "Frodo"`)
+}
+
+func TestRelatedEntityNames(t *testing.T) {
+	ctx := context.Background()
+	relationalDB := mocks.NewRelationalDB()
+	for _, e := range []*entities.Entity{
+		{ID: "frodo", WorldID: "middle-earth", Name: "Frodo", NormalizedName: "frodo"},
+		{ID: "sam", WorldID: "middle-earth", Name: "Sam", NormalizedName: "sam"},
+		{ID: "gollum", WorldID: "middle-earth", Name: "Gollum", NormalizedName: "gollum"},
+	} {
+		require.NoError(t, relationalDB.SaveEntity(ctx, e))
+	}
+	relationalDB.Relationships = []entities.Relationship{
+		{SourceEntityID: "frodo", TargetEntityID: "sam", Bidirectional: true},
+		{SourceEntityID: "gollum", TargetEntityID: "frodo"},
+	}
+	related := RelatedEntityNames(relationalDB, "middle-earth")
+
+	names, err := related(ctx, "frodo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sam"}, names)
+
+	names, err = related(ctx, "Sam")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Frodo"}, names)
+
+	names, err = related(ctx, "Gandalf")
+	require.NoError(t, err)
+	assert.Empty(t, names, "no entity by that name")
+}
+
+func TestParseRetrievalStrategy(t *testing.T) {
+	s, err := ParseRetrievalStrategy("graph")
+	require.NoError(t, err)
+	assert.Equal(t, RetrievalGraph, s)
+
+	s, err = ParseRetrievalStrategy("")
+	require.NoError(t, err)
+	assert.Equal(t, RetrievalStrategy(""), s, "empty means the default")
+
+	_, err = ParseRetrievalStrategy("random")
+	require.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), "valid: type, subject, global, graph")
+}
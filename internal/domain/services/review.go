@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// FactEdit holds corrections applied to a pending fact when it is accepted.
+// Empty fields keep the fact's current value.
+type FactEdit struct {
+	Subject   string
+	Predicate string
+	Object    string
+	Context   string
+}
+
+// IsEmpty reports whether the edit changes nothing.
+func (e FactEdit) IsEmpty() bool {
+	return e == FactEdit{}
+}
+
+// Version reasons recorded for review decisions.
+const (
+	reviewAcceptReason = "accepted in review"
+	reviewEditReason   = "edited in review"
+	reviewRejectReason = "rejected in review"
+)
+
+// ReviewService manages facts held for review because of low confidence.
+// Decisions are stored through the FactService, so they are versioned and
+// audited like manual changes.
+type ReviewService struct {
+	vectorDB ports.VectorDB
+	facts    *FactService
+}
+
+// NewReviewService creates a new review service.
+func NewReviewService(vectorDB ports.VectorDB, facts *FactService) *ReviewService {
+	return &ReviewService{
+		vectorDB: vectorDB,
+		facts:    facts,
+	}
+}
+
+// Pending returns facts awaiting review.
+func (s *ReviewService) Pending(ctx context.Context, limit int) ([]entities.Fact, error) {
+	facts, err := s.vectorDB.ListPending(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing pending facts: %w", err)
+	}
+	return facts, nil
+}
+
+// Accept makes a pending fact searchable.
+func (s *ReviewService) Accept(ctx context.Context, id string) (*entities.Fact, error) {
+	return s.Edit(ctx, id, FactEdit{})
+}
+
+// Edit applies corrections to a pending fact and accepts it. Changed facts
+// are re-embedded so searches match the corrected text.
+func (s *ReviewService) Edit(ctx context.Context, id string, edit FactEdit) (*entities.Fact, error) {
+	s.facts.writeMu.Lock()
+	defer s.facts.writeMu.Unlock()
+
+	fact, err := s.findPending(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := fact
+	reason := reviewAcceptReason
+	if !edit.IsEmpty() {
+		applyEdit(&updated, edit)
+		updated.Embedding, updated.TextEmbedding = nil, nil
+		reason = reviewEditReason
+	}
+	updated.Status = entities.FactStatusActive
+
+	if err := s.facts.store(ctx, &fact, &updated, reason); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// Reject deletes a pending fact, keeping its last state in the fact's
+// history.
+func (s *ReviewService) Reject(ctx context.Context, id string) error {
+	fact, err := s.findPending(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.facts.remove(ctx, &fact, reviewRejectReason)
+}
+
+// findPending retrieves a fact and checks that it awaits review.
+func (s *ReviewService) findPending(ctx context.Context, id string) (entities.Fact, error) {
+	fact, err := s.vectorDB.FindByID(ctx, id)
+	if err != nil {
+		return entities.Fact{}, fmt.Errorf("finding fact: %w", err)
+	}
+	if !fact.IsPending() {
+		return entities.Fact{}, entities.Errorf(entities.ErrConflict, "fact %s is not pending review", id)
+	}
+	return fact, nil
+}
+
+// applyEdit overwrites the fact fields the edit sets.
+func applyEdit(fact *entities.Fact, edit FactEdit) {
+	if edit.Subject != "" {
+		fact.Subject = edit.Subject
+	}
+	if edit.Predicate != "" {
+		fact.Predicate = edit.Predicate
+	}
+	if edit.Object != "" {
+		fact.Object = edit.Object
+	}
+	if edit.Context != "" {
+		fact.Context = edit.Context
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func newReviewTestService(facts ...entities.Fact) (*ReviewService, *mocks.VectorDB, *mocks.RelationalDB, *mocks.Embedder) {
+	vectorDB := &mocks.VectorDB{Facts: facts}
+	relationalDB := mocks.NewRelationalDB()
+	embedder := &mocks.Embedder{EmbeddingResult: []float32{0.5, 0.5}}
+	factService := NewFactService(embedder, vectorDB, relationalDB, newTestEntityTypeService())
+	factService.now = func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) }
+	return NewReviewService(vectorDB, factService), vectorDB, relationalDB, embedder
+}
+
+func TestReviewService_Pending(t *testing.T) {
+	svc, _, _, _ := newReviewTestService(
+		entities.Fact{ID: "a", Status: entities.FactStatusPending},
+		entities.Fact{ID: "b"},
+		entities.Fact{ID: "c", Status: entities.FactStatusPending},
+	)
+
+	facts, err := svc.Pending(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, facts, 2)
+	assert.Equal(t, "a", facts[0].ID)
+	assert.Equal(t, "c", facts[1].ID)
+}
+
+func TestReviewService_Accept(t *testing.T) {
+	svc, vectorDB, relationalDB, embedder := newReviewTestService(entities.Fact{
+		ID:        "a",
+		Subject:   "Frodo",
+		Predicate: "lives_in",
+		Object:    "the Shire",
+		Status:    entities.FactStatusPending,
+		Embedding: []float32{1, 0},
+	})
+
+	fact, err := svc.Accept(context.Background(), "a")
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.FactStatusActive, fact.Status)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), fact.UpdatedAt)
+	assert.Equal(t, []float32{1, 0}, fact.Embedding, "unchanged facts keep their embedding")
+	assert.Zero(t, embedder.EmbedBatchCallCount)
+	require.Len(t, vectorDB.SavedFacts, 1)
+	assert.Equal(t, entities.FactStatusActive, vectorDB.SavedFacts[0].Status)
+
+	latest, err := relationalDB.FindLatestVersion(context.Background(), "a")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "accepted in review", latest.Reason)
+	assert.Equal(t, entities.FactStatusActive, latest.Data.Status)
+	require.Len(t, relationalDB.AuditLog, 1)
+	assert.Equal(t, entities.AuditActionFactUpdate, relationalDB.AuditLog[0].Action)
+}
+
+func TestReviewService_Edit(t *testing.T) {
+	svc, vectorDB, relationalDB, embedder := newReviewTestService(entities.Fact{
+		ID:        "a",
+		Subject:   "Frodo",
+		Predicate: "lives_in",
+		Object:    "Shire",
+		Status:    entities.FactStatusPending,
+		Embedding: []float32{1, 0},
+	})
+
+	fact, err := svc.Edit(context.Background(), "a", FactEdit{Object: "the Shire"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Frodo", fact.Subject)
+	assert.Equal(t, "the Shire", fact.Object)
+	assert.Equal(t, entities.FactStatusActive, fact.Status)
+	assert.Equal(t, []float32{0.5, 0.5}, fact.Embedding)
+	assert.Equal(t, []string{"Frodo lives_in the Shire"}, embedder.EmbedBatchLastTexts)
+	require.Len(t, vectorDB.SavedFacts, 1)
+	assert.Equal(t, "the Shire", vectorDB.SavedFacts[0].Object)
+
+	latest, err := relationalDB.FindLatestVersion(context.Background(), "a")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, "edited in review", latest.Reason)
+	assert.Equal(t, "the Shire", latest.Data.Object)
+}
+
+func TestReviewService_Reject(t *testing.T) {
+	svc, vectorDB, relationalDB, _ := newReviewTestService(entities.Fact{ID: "a", Status: entities.FactStatusPending})
+
+	require.NoError(t, svc.Reject(context.Background(), "a"))
+	assert.Equal(t, []string{"a"}, vectorDB.DeletedIDs)
+
+	latest, err := relationalDB.FindLatestVersion(context.Background(), "a")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	assert.Equal(t, entities.ChangeDeletion, latest.ChangeType)
+	assert.Equal(t, "rejected in review", latest.Reason)
+	require.Len(t, relationalDB.AuditLog, 1)
+	assert.Equal(t, entities.AuditActionFactDelete, relationalDB.AuditLog[0].Action)
+}
+
+func TestReviewService_NotPending(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		act  func(svc *ReviewService, id string) error
+	}{
+		{"accept active", "active", func(svc *ReviewService, id string) error {
+			_, err := svc.Accept(context.Background(), id)
+			return err
+		}},
+		{"reject active", "active", func(svc *ReviewService, id string) error {
+			return svc.Reject(context.Background(), id)
+		}},
+		{"accept missing", "missing", func(svc *ReviewService, id string) error {
+			_, err := svc.Accept(context.Background(), id)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, vectorDB, _, _ := newReviewTestService(entities.Fact{ID: "active"})
+
+			assert.Error(t, tt.act(svc, tt.id))
+			assert.Empty(t, vectorDB.SavedFacts)
+			assert.Empty(t, vectorDB.DeletedIDs)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultSimilarityThreshold is the cosine similarity at or above which an
+// existing fact is reported as a likely duplicate of a new one.
+const DefaultSimilarityThreshold = 0.9
+
+// maxSimilarFacts caps how many likely duplicates are reported per fact.
+const maxSimilarFacts = 3
+
+// SimilarFact is an existing fact that closely matches a new one.
+type SimilarFact struct {
+	Fact       entities.Fact
+	Similarity float64 // Cosine similarity of the two facts' triples
+}
+
+// findSimilarFacts returns existing facts of the same type whose subject,
+// predicate, and object are at least threshold similar to fact's, most
+// similar first. fact must already be embedded; a stored copy of fact
+// itself is never reported.
+func findSimilarFacts(ctx context.Context, vectorDB ports.VectorDB, fact *entities.Fact, threshold float64) ([]SimilarFact, error) {
+	candidates, err := vectorDB.SearchVector(ctx, ports.VectorText, fact.TextEmbedding, fact.Type, maxSimilarFacts+1)
+	if err != nil {
+		return nil, fmt.Errorf("searching similar facts: %w", err)
+	}
+
+	var similar []SimilarFact
+	for i := range candidates {
+		if candidates[i].ID == fact.ID {
+			continue
+		}
+		score := tripleSimilarity(fact, &candidates[i])
+		if score >= threshold {
+			similar = append(similar, SimilarFact{Fact: candidates[i], Similarity: score})
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].Similarity > similar[j].Similarity
+	})
+	if len(similar) > maxSimilarFacts {
+		similar = similar[:maxSimilarFacts]
+	}
+	return similar, nil
+}
+
+// tripleSimilarity compares two facts by their triple embeddings, falling
+// back to the context embedding for collections that store only one.
+func tripleSimilarity(a, b *entities.Fact) float64 {
+	if len(b.TextEmbedding) > 0 {
+		return cosineSimilarity(a.TextEmbedding, b.TextEmbedding)
+	}
+	return cosineSimilarity(a.Embedding, b.Embedding)
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestFindSimilarFacts(t *testing.T) {
+	vectorDB := &mocks.VectorDB{VectorResults: map[ports.VectorName][]entities.Fact{
+		ports.VectorText: {
+			{ID: "new", TextEmbedding: []float32{1, 0}},
+			{ID: "unrelated", TextEmbedding: []float32{0, 1}},
+			{ID: "close", TextEmbedding: []float32{0.95, 0.31}},
+			{ID: "same", TextEmbedding: []float32{2, 0}},
+			{ID: "single-vector", Embedding: []float32{1, 0.1}},
+		},
+	}}
+	fact := &entities.Fact{ID: "new", Embedding: []float32{1, 0}, TextEmbedding: []float32{1, 0}}
+
+	similar, err := findSimilarFacts(context.Background(), vectorDB, fact, DefaultSimilarityThreshold)
+	require.NoError(t, err)
+
+	ids := make([]string, len(similar))
+	for i := range similar {
+		ids[i] = similar[i].Fact.ID
+	}
+	assert.Equal(t, []string{"same", "single-vector", "close"}, ids, "most similar first, the fact itself and unrelated facts skipped")
+	assert.InDelta(t, 1.0, similar[0].Similarity, 0.001)
+}
+
+func TestFindSimilarFacts_SearchError(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Err: entities.ErrBackendUnavailable}
+
+	_, err := findSimilarFacts(context.Background(), vectorDB, &entities.Fact{}, DefaultSimilarityThreshold)
+	require.ErrorIs(t, err, entities.ErrBackendUnavailable)
+	assert.Contains(t, err.Error(), "searching similar facts")
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// snapshotNameLayout names snapshots by their UTC creation time.
+const snapshotNameLayout = "20060102-150405"
+
+// SnapshotService creates, lists, and prunes world snapshots.
+type SnapshotService struct {
+	world      string
+	vectors    ports.VectorSnapshotter
+	relational ports.RelationalBackup
+	store      ports.SnapshotStore
+	now        func() time.Time
+}
+
+// NewSnapshotService creates a new SnapshotService for a world.
+func NewSnapshotService(
+	world string,
+	vectors ports.VectorSnapshotter,
+	relational ports.RelationalBackup,
+	store ports.SnapshotStore,
+) *SnapshotService {
+	return &SnapshotService{
+		world:      world,
+		vectors:    vectors,
+		relational: relational,
+		store:      store,
+		now:        time.Now,
+	}
+}
+
+// Create snapshots the vector collection and backs up the relational
+// database, recording the backup's checksum, schema version, and record
+// counts so it can be verified later. If the backup fails or does not
+// restore, the vector snapshot is removed again.
+func (s *SnapshotService) Create(ctx context.Context) (*entities.Snapshot, error) {
+	createdAt := s.now().UTC()
+	name := createdAt.Format(snapshotNameLayout)
+
+	vectorSnapshot, err := s.vectors.CreateSnapshot(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating vector snapshot: %w", err)
+	}
+
+	snapshot := &entities.Snapshot{
+		Name:           name,
+		World:          s.world,
+		VectorSnapshot: vectorSnapshot,
+		RelationalPath: s.store.BackupPath(name),
+		CreatedAt:      createdAt,
+	}
+
+	if err := s.relational.BackupTo(ctx, snapshot.RelationalPath); err != nil {
+		s.dropVectorSnapshot(ctx, vectorSnapshot)
+		return nil, fmt.Errorf("backing up relational database: %w", err)
+	}
+
+	if err := s.recordInspection(ctx, snapshot); err != nil {
+		s.dropVectorSnapshot(ctx, vectorSnapshot)
+		return nil, fmt.Errorf("checking relational backup: %w", err)
+	}
+
+	if err := s.store.Save(ctx, snapshot); err != nil {
+		s.dropVectorSnapshot(ctx, vectorSnapshot)
+		return nil, fmt.Errorf("recording snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// List returns the world's snapshots, newest first.
+func (s *SnapshotService) List(ctx context.Context) ([]entities.Snapshot, error) {
+	snapshots, err := s.store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Prune deletes all but the newest keep snapshots and returns the names
+// of those deleted. Keep must be at least 1.
+func (s *SnapshotService) Prune(ctx context.Context, keep int) ([]string, error) {
+	if keep < 1 {
+		return nil, entities.Errorf(entities.ErrValidation, "keep must be at least 1")
+	}
+
+	snapshots, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) <= keep {
+		return []string{}, nil
+	}
+
+	pruned := make([]string, 0, len(snapshots)-keep)
+	for _, snapshot := range snapshots[keep:] {
+		if err := s.vectors.DeleteSnapshot(ctx, snapshot.VectorSnapshot); err != nil {
+			return pruned, fmt.Errorf("deleting vector snapshot %s: %w", snapshot.VectorSnapshot, err)
+		}
+		//nolint:loopcall // One at a time, so a failed vector delete keeps its record to retry
+		if err := s.store.Delete(ctx, snapshot.Name); err != nil {
+			return pruned, fmt.Errorf("deleting snapshot %s: %w", snapshot.Name, err)
+		}
+		pruned = append(pruned, snapshot.Name)
+	}
+
+	return pruned, nil
+}
+
+// recordInspection test-restores the snapshot's relational backup and
+// records what verification later compares against.
+func (s *SnapshotService) recordInspection(ctx context.Context, snapshot *entities.Snapshot) error {
+	inspection, err := s.relational.InspectBackup(ctx, snapshot.RelationalPath)
+	if err != nil {
+		return err
+	}
+	if len(inspection.Integrity) > 0 {
+		return errors.New(inspection.Integrity[0])
+	}
+	if inspection.RestoreErr != nil {
+		return inspection.RestoreErr
+	}
+
+	snapshot.Checksum = inspection.Checksum
+	snapshot.SchemaVersion = inspection.SchemaVersion
+	snapshot.Counts = inspection.Counts
+	return nil
+}
+
+// Backup check statuses.
+const (
+	CheckPassed  = "pass"
+	CheckWarning = "warn"
+	CheckFailed  = "fail"
+	CheckSkipped = "skip"
+)
+
+// BackupCheck is the outcome of one backup verification check.
+type BackupCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+}
+
+// BackupVerification contains the result of verifying a backup.
+type BackupVerification struct {
+	Snapshot   entities.Snapshot          `json:"snapshot"`
+	Inspection *entities.BackupInspection `json:"-"`
+	Checks     []BackupCheck              `json:"checks"`
+}
+
+// Failed returns the number of failed checks.
+func (v *BackupVerification) Failed() int {
+	n := 0
+	for _, c := range v.Checks {
+		if c.Status == CheckFailed {
+			n++
+		}
+	}
+	return n
+}
+
+func (v *BackupVerification) add(name, status, detail string) {
+	v.Checks = append(v.Checks, BackupCheck{Name: name, Status: status, Detail: detail})
+}
+
+// Verify checks that a snapshot could be restored: its relational backup
+// matches the recorded checksum, passes an integrity check, has a schema
+// this build can read, restores into a temporary database with the
+// recorded record counts, and its vector snapshot still exists. Nothing in
+// the live world is modified. A snapshot with only RelationalPath set,
+// such as a backup file without its manifest, gets the checks that need no
+// recorded values.
+func (s *SnapshotService) Verify(ctx context.Context, snapshot *entities.Snapshot) (*BackupVerification, error) {
+	inspection, err := s.relational.InspectBackup(ctx, snapshot.RelationalPath)
+	if err != nil {
+		return nil, fmt.Errorf("inspecting relational backup: %w", err)
+	}
+	v := &BackupVerification{Snapshot: *snapshot, Inspection: inspection}
+
+	otherWorld := snapshot.World != "" && snapshot.World != s.world
+	if otherWorld {
+		v.add("world", CheckWarning, fmt.Sprintf("backup is of world %s, not %s", snapshot.World, s.world))
+	}
+
+	switch {
+	case snapshot.Checksum == "":
+		v.add("checksum", CheckSkipped, "no checksum recorded")
+	case snapshot.Checksum == inspection.Checksum:
+		v.add("checksum", CheckPassed, "sha256 "+inspection.Checksum)
+	default:
+		v.add("checksum", CheckFailed, fmt.Sprintf("sha256 %s, recorded %s", inspection.Checksum, snapshot.Checksum))
+	}
+
+	switch {
+	case !inspection.Opened:
+		v.add("integrity", CheckSkipped, "backup is not a readable database")
+	case len(inspection.Integrity) > 0:
+		v.add("integrity", CheckFailed, strings.Join(inspection.Integrity[:min(3, len(inspection.Integrity))], "; "))
+	default:
+		v.add("integrity", CheckPassed, "ok")
+	}
+
+	if inspection.Opened {
+		v.checkSchema(snapshot, inspection, s.relational.SchemaVersion())
+	}
+
+	restored := inspection.OK()
+	switch {
+	case len(inspection.Integrity) > 0:
+		v.add("restore", CheckSkipped, "backup failed the integrity check")
+	case inspection.RestoreErr != nil:
+		v.add("restore", CheckFailed, inspection.RestoreErr.Error())
+	default:
+		v.add("restore", CheckPassed, "restored into a temporary database")
+	}
+
+	if restored {
+		v.checkCounts(snapshot.Counts, inspection.Counts)
+	}
+
+	switch {
+	case snapshot.VectorSnapshot == "":
+		v.add("vectors", CheckSkipped, "no vector snapshot recorded")
+	case otherWorld:
+		v.add("vectors", CheckSkipped, "vector snapshot belongs to another world")
+	default:
+		names, err := s.vectors.ListSnapshots(ctx)
+		switch {
+		case err != nil:
+			v.add("vectors", CheckFailed, fmt.Sprintf("listing vector snapshots: %v", err))
+		case slices.Contains(names, snapshot.VectorSnapshot):
+			v.add("vectors", CheckPassed, "snapshot "+snapshot.VectorSnapshot+" exists")
+		default:
+			v.add("vectors", CheckFailed, "snapshot "+snapshot.VectorSnapshot+" not found")
+		}
+	}
+
+	return v, nil
+}
+
+// checkSchema compares the backup's schema version with the recorded one
+// and with the version this build supports.
+func (v *BackupVerification) checkSchema(snapshot *entities.Snapshot, inspection *entities.BackupInspection, current int) {
+	version := inspection.SchemaVersion
+	switch {
+	case snapshot.SchemaVersion != 0 && snapshot.SchemaVersion != version:
+		v.add("schema", CheckFailed, fmt.Sprintf("version %d, recorded %d", version, snapshot.SchemaVersion))
+	case version > current:
+		v.add("schema", CheckFailed, fmt.Sprintf("version %d is newer than the supported version %d", version, current))
+	case version < current:
+		v.add("schema", CheckWarning, fmt.Sprintf("version %d, upgraded to %d on restore", version, current))
+	default:
+		v.add("schema", CheckPassed, fmt.Sprintf("version %d", version))
+	}
+}
+
+// checkCounts compares the restored record counts with the recorded ones.
+// Tables added by a schema upgrade are not recorded and are ignored.
+func (v *BackupVerification) checkCounts(recorded, restored map[string]int) {
+	total := 0
+	for _, n := range restored {
+		total += n
+	}
+	summary := fmt.Sprintf("%d records in %d tables", total, len(restored))
+	if len(recorded) == 0 {
+		v.add("counts", CheckPassed, summary+" (no counts recorded)")
+		return
+	}
+
+	tables := make([]string, 0, len(recorded))
+	for table := range recorded {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var mismatches []string
+	for _, table := range tables {
+		if n, ok := restored[table]; !ok || n != recorded[table] {
+			mismatches = append(mismatches, fmt.Sprintf("%s: %d restored, %d recorded", table, n, recorded[table]))
+		}
+	}
+	if len(mismatches) > 0 {
+		v.add("counts", CheckFailed, strings.Join(mismatches, "; "))
+		return
+	}
+	v.add("counts", CheckPassed, summary)
+}
+
+// dropVectorSnapshot removes a vector snapshot left behind by a failed Create.
+func (s *SnapshotService) dropVectorSnapshot(ctx context.Context, name string) {
+	if err := s.vectors.DeleteSnapshot(ctx, name); err != nil {
+		log.Printf("warning: failed to remove vector snapshot %s: %v", name, err)
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+type fakeVectorSnapshotter struct {
+	snapshots map[string]bool
+	next      int
+	listErr   error
+}
+
+func (f *fakeVectorSnapshotter) CreateSnapshot(_ context.Context) (string, error) {
+	f.next++
+	name := "qdrant-" + string(rune('a'+f.next-1))
+	f.snapshots[name] = true
+	return name, nil
+}
+
+func (f *fakeVectorSnapshotter) DeleteSnapshot(_ context.Context, name string) error {
+	delete(f.snapshots, name)
+	return nil
+}
+
+func (f *fakeVectorSnapshotter) ListSnapshots(_ context.Context) ([]string, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	names := make([]string, 0, len(f.snapshots))
+	for name := range f.snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+type fakeRelationalBackup struct {
+	paths      []string
+	err        error
+	inspection entities.BackupInspection
+	inspectErr error
+}
+
+func (f *fakeRelationalBackup) BackupTo(_ context.Context, path string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.paths = append(f.paths, path)
+	return nil
+}
+
+func (f *fakeRelationalBackup) InspectBackup(_ context.Context, _ string) (*entities.BackupInspection, error) {
+	if f.inspectErr != nil {
+		return nil, f.inspectErr
+	}
+	inspection := f.inspection
+	return &inspection, nil
+}
+
+func (f *fakeRelationalBackup) SchemaVersion() int {
+	return 2
+}
+
+type fakeSnapshotStore struct {
+	snapshots map[string]entities.Snapshot
+}
+
+func (f *fakeSnapshotStore) BackupPath(name string) string {
+	return "/backups/" + name + ".db"
+}
+
+func (f *fakeSnapshotStore) Save(_ context.Context, snapshot *entities.Snapshot) error {
+	f.snapshots[snapshot.Name] = *snapshot
+	return nil
+}
+
+func (f *fakeSnapshotStore) List(_ context.Context) ([]entities.Snapshot, error) {
+	list := make([]entities.Snapshot, 0, len(f.snapshots))
+	for _, s := range f.snapshots {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.After(list[j].CreatedAt) })
+	return list, nil
+}
+
+func (f *fakeSnapshotStore) Delete(_ context.Context, name string) error {
+	delete(f.snapshots, name)
+	return nil
+}
+
+func newTestSnapshotService() (*SnapshotService, *fakeVectorSnapshotter, *fakeRelationalBackup, *fakeSnapshotStore) {
+	vectors := &fakeVectorSnapshotter{snapshots: map[string]bool{}}
+	relational := &fakeRelationalBackup{inspection: entities.BackupInspection{
+		Checksum:      "abc123",
+		Opened:        true,
+		SchemaVersion: 2,
+		Counts:        map[string]int{"entities": 3, "relationships": 1},
+	}}
+	store := &fakeSnapshotStore{snapshots: map[string]entities.Snapshot{}}
+	return NewSnapshotService("middle-earth", vectors, relational, store), vectors, relational, store
+}
+
+func TestSnapshotService_Create(t *testing.T) {
+	svc, vectors, relational, store := newTestSnapshotService()
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC) }
+
+	snapshot, err := svc.Create(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "20260301-030000", snapshot.Name)
+	assert.Equal(t, "middle-earth", snapshot.World)
+	assert.Equal(t, "qdrant-a", snapshot.VectorSnapshot)
+	assert.Equal(t, []string{"/backups/20260301-030000.db"}, relational.paths)
+	assert.Contains(t, store.snapshots, "20260301-030000")
+	assert.True(t, vectors.snapshots["qdrant-a"])
+
+	assert.Equal(t, "abc123", snapshot.Checksum)
+	assert.Equal(t, 2, snapshot.SchemaVersion)
+	assert.Equal(t, map[string]int{"entities": 3, "relationships": 1}, snapshot.Counts)
+}
+
+func TestSnapshotService_Create_RestoreFailure(t *testing.T) {
+	svc, vectors, relational, store := newTestSnapshotService()
+	relational.inspection.Integrity = []string{"row 3 missing from index"}
+
+	_, err := svc.Create(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 3 missing from index")
+	assert.Empty(t, vectors.snapshots, "vector snapshot should be removed")
+	assert.Empty(t, store.snapshots)
+}
+
+func TestSnapshotService_Create_BackupFailure(t *testing.T) {
+	svc, vectors, relational, store := newTestSnapshotService()
+	relational.err = errors.New("disk full")
+
+	_, err := svc.Create(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disk full")
+	assert.Empty(t, vectors.snapshots, "vector snapshot should be removed")
+	assert.Empty(t, store.snapshots)
+}
+
+func TestSnapshotService_Prune(t *testing.T) {
+	svc, vectors, _, store := newTestSnapshotService()
+	start := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	for day := range 4 {
+		svc.now = func() time.Time { return start.AddDate(0, 0, day) }
+		_, err := svc.Create(context.Background())
+		require.NoError(t, err)
+	}
+
+	pruned, err := svc.Prune(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"20260302-030000", "20260301-030000"}, pruned)
+	assert.Len(t, store.snapshots, 2)
+	assert.Len(t, vectors.snapshots, 2)
+	assert.True(t, vectors.snapshots["qdrant-d"])
+
+	t.Run("nothing to prune", func(t *testing.T) {
+		pruned, err := svc.Prune(context.Background(), 5)
+		require.NoError(t, err)
+		assert.Empty(t, pruned)
+	})
+
+	t.Run("keep must be positive", func(t *testing.T) {
+		_, err := svc.Prune(context.Background(), 0)
+		require.Error(t, err)
+	})
+}
+
+func TestSnapshotService_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(*entities.Snapshot, *fakeRelationalBackup, *fakeVectorSnapshotter)
+		want   map[string]string // Check name to status
+		failed int
+	}{
+		{
+			name: "verified",
+			want: map[string]string{
+				"checksum": CheckPassed, "integrity": CheckPassed, "schema": CheckPassed,
+				"restore": CheckPassed, "counts": CheckPassed, "vectors": CheckPassed,
+			},
+		},
+		{
+			name: "checksum mismatch",
+			setup: func(s *entities.Snapshot, _ *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				s.Checksum = "def456"
+			},
+			want:   map[string]string{"checksum": CheckFailed, "restore": CheckPassed},
+			failed: 1,
+		},
+		{
+			name: "count mismatch",
+			setup: func(_ *entities.Snapshot, r *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				r.inspection.Counts = map[string]int{"entities": 2, "relationships": 1, "fact_translations": 0}
+			},
+			want:   map[string]string{"counts": CheckFailed},
+			failed: 1,
+		},
+		{
+			name: "older schema",
+			setup: func(s *entities.Snapshot, r *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				s.SchemaVersion = 0
+				r.inspection.SchemaVersion = 1
+			},
+			want: map[string]string{"schema": CheckWarning},
+		},
+		{
+			name: "newer schema",
+			setup: func(s *entities.Snapshot, r *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				s.SchemaVersion = 3
+				r.inspection.SchemaVersion = 3
+				r.inspection.RestoreErr = errors.New("schema version 3 is newer than the supported version 2")
+			},
+			want:   map[string]string{"schema": CheckFailed, "restore": CheckFailed},
+			failed: 2,
+		},
+		{
+			name: "corrupt",
+			setup: func(_ *entities.Snapshot, r *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				r.inspection.Integrity = []string{"page 4 is never used"}
+			},
+			want:   map[string]string{"integrity": CheckFailed, "restore": CheckSkipped},
+			failed: 1,
+		},
+		{
+			name: "not a database",
+			setup: func(_ *entities.Snapshot, r *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				r.inspection = entities.BackupInspection{Checksum: "abc123", RestoreErr: errors.New("file is not a database")}
+			},
+			want:   map[string]string{"integrity": CheckSkipped, "restore": CheckFailed},
+			failed: 1,
+		},
+		{
+			name: "vector snapshot missing",
+			setup: func(_ *entities.Snapshot, _ *fakeRelationalBackup, v *fakeVectorSnapshotter) {
+				v.snapshots = map[string]bool{}
+			},
+			want:   map[string]string{"vectors": CheckFailed},
+			failed: 1,
+		},
+		{
+			name: "backup without manifest",
+			setup: func(s *entities.Snapshot, _ *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				*s = entities.Snapshot{RelationalPath: s.RelationalPath}
+			},
+			want: map[string]string{"checksum": CheckSkipped, "counts": CheckPassed, "vectors": CheckSkipped},
+		},
+		{
+			name: "another world",
+			setup: func(s *entities.Snapshot, _ *fakeRelationalBackup, _ *fakeVectorSnapshotter) {
+				s.World = "narnia"
+			},
+			want: map[string]string{"world": CheckWarning, "vectors": CheckSkipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, vectors, relational, _ := newTestSnapshotService()
+			snapshot, err := svc.Create(context.Background())
+			require.NoError(t, err)
+			if tt.setup != nil {
+				tt.setup(snapshot, relational, vectors)
+			}
+
+			verification, err := svc.Verify(context.Background(), snapshot)
+			require.NoError(t, err)
+
+			statuses := make(map[string]string)
+			for _, c := range verification.Checks {
+				statuses[c.Name] = c.Status
+			}
+			for name, status := range tt.want {
+				assert.Equal(t, status, statuses[name], name)
+			}
+			assert.Equal(t, tt.failed, verification.Failed())
+		})
+	}
+}
+
+func TestSnapshotService_Verify_Unreadable(t *testing.T) {
+	svc, _, relational, _ := newTestSnapshotService()
+	relational.inspectErr = entities.Errorf(entities.ErrNotFound, "backup /backups/x.db not found")
+
+	_, err := svc.Verify(context.Background(), &entities.Snapshot{RelationalPath: "/backups/x.db"})
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// minFlaggedWords is the fewest words a source needs before its fact
+// density is flagged, since a short note's density says little.
+const minFlaggedWords = 200
+
+// Fact densities further than densityFactor times from the median are
+// flagged as unusually low or high.
+const densityFactor = 2.0
+
+// DensityFlag marks a source whose fact density stands out from the rest.
+type DensityFlag string
+
+const (
+	// DensityLow sources yielded far fewer facts than usual, which may
+	// mean extraction failed for part of them.
+	DensityLow DensityFlag = "low"
+	// DensityHigh sources are unusually dense with lore.
+	DensityHigh DensityFlag = "high"
+)
+
+// SourceDensity is a source's recorded ingest with how its fact density
+// compares to the other sources'.
+type SourceDensity struct {
+	entities.SourceStats
+	Flag DensityFlag // Empty = typical
+}
+
+// SourceReport lists the ingested sources and their fact densities.
+type SourceReport struct {
+	Sources []SourceDensity
+	Median  float64 // Median facts per 1000 words of the sources long enough to flag
+}
+
+// SourceService records how long each ingested source was and how many
+// facts it yielded, to find sources that were under-extracted, keeps the
+// chunks whose facts could not be extracted to retry later, and archives
+// sources that should not be processed again.
+type SourceService struct {
+	relationalDB ports.RelationalDB
+	vectorDB     ports.VectorDB
+	now          func() time.Time
+}
+
+// NewSourceService creates a new source service.
+func NewSourceService(relationalDB ports.RelationalDB, vectorDB ports.VectorDB) *SourceService {
+	return &SourceService{
+		relationalDB: relationalDB,
+		vectorDB:     vectorDB,
+		now:          time.Now,
+	}
+}
+
+// Record stores the word and fact counts of a source's latest ingest.
+func (s *SourceService) Record(ctx context.Context, source string, words, facts int) error {
+	err := s.relationalDB.SaveSourceStats(ctx, &entities.SourceStats{
+		Source:     source,
+		Words:      words,
+		Facts:      facts,
+		IngestedAt: s.now(),
+	})
+	if err != nil {
+		return fmt.Errorf("recording source %s: %w", source, err)
+	}
+	return nil
+}
+
+// Report lists the recorded sources, flagging those at least
+// minFlaggedWords long whose density is far from the median.
+func (s *SourceService) Report(ctx context.Context) (*SourceReport, error) {
+	stats, err := s.relationalDB.ListSourceStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing sources: %w", err)
+	}
+
+	var densities []float64
+	for i := range stats {
+		if stats[i].Words >= minFlaggedWords {
+			densities = append(densities, stats[i].Density())
+		}
+	}
+
+	report := &SourceReport{
+		Sources: make([]SourceDensity, len(stats)),
+		Median:  median(densities),
+	}
+	for i := range stats {
+		report.Sources[i] = SourceDensity{SourceStats: stats[i]}
+		if stats[i].Words < minFlaggedWords || report.Median == 0 {
+			continue
+		}
+		switch density := stats[i].Density(); {
+		case density < report.Median/densityFactor:
+			report.Sources[i].Flag = DensityLow
+		case density > report.Median*densityFactor:
+			report.Sources[i].Flag = DensityHigh
+		}
+	}
+	return report, nil
+}
+
+// Quarantine keeps a chunk whose facts could not be extracted, to retry
+// later.
+func (s *SourceService) Quarantine(ctx context.Context, chunk *entities.FailedChunk) error {
+	now := s.now()
+	chunk.ID = uuid.New().String()
+	chunk.Attempts = 1
+	chunk.CreatedAt, chunk.UpdatedAt = now, now
+	if err := s.relationalDB.SaveFailedChunk(ctx, chunk); err != nil {
+		return fmt.Errorf("quarantining chunk %d of %s: %w", chunk.Index, chunk.Source, err)
+	}
+	return nil
+}
+
+// Quarantined returns the quarantined chunks, of one source or of all if
+// source is empty, ordered by source and position.
+func (s *SourceService) Quarantined(ctx context.Context, source string) ([]entities.FailedChunk, error) {
+	chunks, err := s.relationalDB.ListFailedChunks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing quarantined chunks: %w", err)
+	}
+	if source == "" {
+		return chunks, nil
+	}
+	return slices.DeleteFunc(chunks, func(c entities.FailedChunk) bool { return c.Source != source }), nil
+}
+
+// ClearQuarantine drops the quarantined chunks of a source, as when the
+// whole source is ingested again.
+func (s *SourceService) ClearQuarantine(ctx context.Context, source string) error {
+	chunks, err := s.Quarantined(ctx, source)
+	if err != nil {
+		return err
+	}
+	for i := range chunks {
+		if err := s.relationalDB.DeleteFailedChunk(ctx, chunks[i].ID); err != nil {
+			return fmt.Errorf("clearing quarantined chunk %d of %s: %w", chunks[i].Index, source, err)
+		}
+	}
+	return nil
+}
+
+// RetryFailed records another failed attempt at a quarantined chunk.
+func (s *SourceService) RetryFailed(ctx context.Context, chunk *entities.FailedChunk, cause error) error {
+	chunk.Error = cause.Error()
+	chunk.Attempts++
+	chunk.UpdatedAt = s.now()
+	if err := s.relationalDB.SaveFailedChunk(ctx, chunk); err != nil {
+		return fmt.Errorf("updating quarantined chunk %d of %s: %w", chunk.Index, chunk.Source, err)
+	}
+	return nil
+}
+
+// RetrySucceeded releases a quarantined chunk whose facts were extracted,
+// adding them to its source's recorded fact count.
+func (s *SourceService) RetrySucceeded(ctx context.Context, chunk *entities.FailedChunk, facts int) error {
+	if err := s.relationalDB.DeleteFailedChunk(ctx, chunk.ID); err != nil {
+		return fmt.Errorf("releasing quarantined chunk %d of %s: %w", chunk.Index, chunk.Source, err)
+	}
+
+	stats, err := s.relationalDB.ListSourceStats(ctx)
+	if err != nil {
+		return fmt.Errorf("listing sources: %w", err)
+	}
+	for i := range stats {
+		if stats[i].Source == chunk.Source {
+			stats[i].Facts += facts
+			if err := s.relationalDB.SaveSourceStats(ctx, &stats[i]); err != nil {
+				return fmt.Errorf("recording source %s: %w", chunk.Source, err)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// Archive marks a source as archived: its facts are kept, labeled as
+// archived, but the source is not ingested or replaced again, and its
+// quarantined chunks are dropped. A source with neither facts nor a
+// recorded ingest returns an ErrNotFound error.
+func (s *SourceService) Archive(ctx context.Context, source string) error {
+	known, err := s.known(ctx, source)
+	if err != nil {
+		return err
+	}
+	if !known {
+		return entities.Errorf(entities.ErrNotFound, "no facts or ingest recorded for source %s", source)
+	}
+
+	// Recorded first, so the source is no longer ingested even if labeling
+	// its facts fails; archiving it again finishes the labeling
+	if err := s.relationalDB.SaveArchivedSource(ctx, &entities.ArchivedSource{Source: source, ArchivedAt: s.now()}); err != nil {
+		return fmt.Errorf("archiving source %s: %w", source, err)
+	}
+	if err := s.LabelArchived(ctx, source); err != nil {
+		return err
+	}
+	return s.ClearQuarantine(ctx, source)
+}
+
+// Unarchive lets an archived source be ingested again and removes the
+// archived label from its facts.
+func (s *SourceService) Unarchive(ctx context.Context, source string) error {
+	if err := s.relationalDB.DeleteArchivedSource(ctx, source); err != nil {
+		return fmt.Errorf("unarchiving source %s: %w", source, err)
+	}
+	if err := s.vectorDB.SetSourceArchived(ctx, source, false); err != nil {
+		return fmt.Errorf("unlabeling facts of %s: %w", source, err)
+	}
+	return nil
+}
+
+// LabelArchived labels every fact of a source as archived, as when an
+// archived source is ingested anyway.
+func (s *SourceService) LabelArchived(ctx context.Context, source string) error {
+	if err := s.vectorDB.SetSourceArchived(ctx, source, true); err != nil {
+		return fmt.Errorf("labeling facts of %s: %w", source, err)
+	}
+	return nil
+}
+
+// Archived returns the archived sources, ordered by source.
+func (s *SourceService) Archived(ctx context.Context) ([]entities.ArchivedSource, error) {
+	sources, err := s.relationalDB.ListArchivedSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing archived sources: %w", err)
+	}
+	return sources, nil
+}
+
+// ArchivedSet returns the archived sources as a set.
+func (s *SourceService) ArchivedSet(ctx context.Context) (map[string]bool, error) {
+	sources, err := s.Archived(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(sources))
+	for i := range sources {
+		set[sources[i].Source] = true
+	}
+	return set, nil
+}
+
+// known reports whether a source has facts or a recorded ingest.
+func (s *SourceService) known(ctx context.Context, source string) (bool, error) {
+	stats, err := s.relationalDB.ListSourceStats(ctx)
+	if err != nil {
+		return false, fmt.Errorf("listing sources: %w", err)
+	}
+	if slices.ContainsFunc(stats, func(st entities.SourceStats) bool { return st.Source == source }) {
+		return true, nil
+	}
+	facts, err := s.vectorDB.ListBySource(ctx, source, 1)
+	if err != nil {
+		return false, fmt.Errorf("listing facts of %s: %w", source, err)
+	}
+	return len(facts) > 0, nil
+}
+
+// median returns the middle value, or the mean of the two middle values,
+// or 0 if there are none.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
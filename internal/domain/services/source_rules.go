@@ -0,0 +1,149 @@
+package services
+
+import (
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// SourceRule gives the facts of the sources matching Pattern metadata and
+// tags, so they need not be tagged by hand.
+type SourceRule struct {
+	// Pattern is a path.Match pattern for source paths. It matches a
+	// source if it matches the source's last path elements, or a
+	// directory's, so "book2" applies to every source in a book2
+	// directory and "drafts/*.md" to the Markdown files directly in one.
+	Pattern string
+
+	Metadata map[string]string
+	Tags     []string
+
+	// Draft makes the facts drafts, such as those of brainstorming notes,
+	// lasting DraftTTL from when they are extracted (0 = until promoted
+	// or purged).
+	Draft    bool
+	DraftTTL time.Duration
+}
+
+// SourceRules map source paths to the metadata their facts inherit. Every
+// rule matching a source applies, in order: a later rule's metadata
+// overrides an earlier one's, and tags accumulate.
+type SourceRules []SourceRule
+
+// Validate checks every rule has a well-formed pattern and gives something.
+func (r SourceRules) Validate() error {
+	for i, rule := range r {
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return entities.Errorf(entities.ErrValidation, "rule %d: pattern is required", i+1)
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return entities.Errorf(entities.ErrValidation, "rule %d: invalid pattern %q: %v", i+1, rule.Pattern, err)
+		}
+		if len(rule.Metadata) == 0 && len(rule.Tags) == 0 && !rule.Draft {
+			return entities.Errorf(entities.ErrValidation, "rule %d (%s): gives no metadata or tags", i+1, rule.Pattern)
+		}
+		if rule.DraftTTL < 0 || (rule.DraftTTL > 0 && !rule.Draft) {
+			return entities.Errorf(entities.ErrValidation, "rule %d (%s): a TTL must be positive and is only for drafts", i+1, rule.Pattern)
+		}
+		for key := range rule.Metadata {
+			if strings.TrimSpace(key) == "" {
+				return entities.Errorf(entities.ErrValidation, "rule %d (%s): metadata key must not be empty", i+1, rule.Pattern)
+			}
+		}
+		if slices.ContainsFunc(rule.Tags, func(tag string) bool { return strings.TrimSpace(tag) == "" }) {
+			return entities.Errorf(entities.ErrValidation, "rule %d (%s): tags must not be empty", i+1, rule.Pattern)
+		}
+	}
+	return nil
+}
+
+// draft reports whether the rules make the facts of source drafts, and for
+// how long. A later matching rule's TTL overrides an earlier one's.
+func (r SourceRules) draft(source string) (bool, time.Duration) {
+	var (
+		draft bool
+		ttl   time.Duration
+	)
+	for _, rule := range r {
+		if rule.Draft && matchesSource(rule.Pattern, source) {
+			draft, ttl = true, rule.DraftTTL
+		}
+	}
+	return draft, ttl
+}
+
+// Match returns the metadata and tags the rules give source, nil if none
+// match.
+func (r SourceRules) Match(source string) (map[string]string, []string) {
+	var (
+		metadata map[string]string
+		tags     []string
+	)
+	for _, rule := range r {
+		if !matchesSource(rule.Pattern, source) {
+			continue
+		}
+		if len(rule.Metadata) > 0 {
+			if metadata == nil {
+				metadata = make(map[string]string, len(rule.Metadata))
+			}
+			maps.Copy(metadata, rule.Metadata)
+		}
+		for _, tag := range rule.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return metadata, tags
+}
+
+// Apply gives each fact the metadata and tags of its source, and makes it
+// a draft if its source is one. Metadata the fact already has is kept.
+func (r SourceRules) Apply(facts []entities.Fact) {
+	if len(r) == 0 {
+		return
+	}
+	for i := range facts {
+		fact := &facts[i]
+		metadata, tags := r.Match(fact.SourceFile)
+		for key, value := range metadata {
+			if _, ok := fact.Metadata[key]; ok {
+				continue
+			}
+			if fact.Metadata == nil {
+				fact.Metadata = make(map[string]string, len(metadata))
+			}
+			fact.Metadata[key] = value
+		}
+		for _, tag := range tags {
+			if !slices.Contains(fact.Tags, tag) {
+				fact.Tags = append(fact.Tags, tag)
+			}
+		}
+		if draft, ttl := r.draft(fact.SourceFile); draft {
+			fact.Draft = true
+			if ttl > 0 {
+				fact.ExpiresAt = fact.CreatedAt.Add(ttl)
+			}
+		}
+	}
+}
+
+// matchesSource reports whether pattern matches the last elements of
+// source's path or of one of its directories.
+func matchesSource(pattern, source string) bool {
+	elems := strings.Split(strings.Trim(filepath.ToSlash(source), "/"), "/")
+	depth := strings.Count(strings.Trim(pattern, "/"), "/") + 1
+	for end := len(elems); end >= depth; end-- {
+		if matched, _ := path.Match(strings.Trim(pattern, "/"), strings.Join(elems[end-depth:end], "/")); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestSourceRules_Match(t *testing.T) {
+	rules := SourceRules{
+		{Pattern: "book2", Metadata: map[string]string{"book": "2"}},
+		{Pattern: "frodo-*.md", Metadata: map[string]string{"pov": "Frodo"}, Tags: []string{"draft"}},
+		{Pattern: "book2/drafts/*", Metadata: map[string]string{"book": "2b"}, Tags: []string{"draft", "unsorted"}},
+	}
+
+	tests := []struct {
+		name     string
+		source   string
+		metadata map[string]string
+		tags     []string
+	}{
+		{"directory", "/home/me/novel/book2/ch1.md", map[string]string{"book": "2"}, nil},
+		{"file name", "book1/frodo-ch3.md", map[string]string{"pov": "Frodo"}, []string{"draft"}},
+		{"later rules override", "/novel/book2/drafts/frodo-x.md", map[string]string{"book": "2b", "pov": "Frodo"}, []string{"draft", "unsorted"}},
+		{"no match", "book1/ch1.md", nil, nil},
+		{"not a partial name", "/novel/book22/ch1.md", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, tags := rules.Match(tt.source)
+			assert.Equal(t, tt.metadata, metadata)
+			assert.Equal(t, tt.tags, tags)
+		})
+	}
+}
+
+func TestSourceRules_Apply(t *testing.T) {
+	rules := SourceRules{{Pattern: "book2", Metadata: map[string]string{"book": "2", "pov": "Sam"}, Tags: []string{"draft"}}}
+	facts := []entities.Fact{
+		{SourceFile: "book2/ch1.md", Metadata: map[string]string{"pov": "Frodo"}, Tags: []string{"draft"}},
+		{SourceFile: "book1/ch1.md"},
+	}
+
+	rules.Apply(facts)
+	assert.Equal(t, map[string]string{"book": "2", "pov": "Frodo"}, facts[0].Metadata, "the fact's own metadata is kept")
+	assert.Equal(t, []string{"draft"}, facts[0].Tags)
+	assert.Nil(t, facts[1].Metadata)
+	assert.Nil(t, facts[1].Tags)
+}
+
+func TestSourceRules_ApplyDraft(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rules := SourceRules{
+		{Pattern: "notes", Draft: true},
+		{Pattern: "notes/brainstorm-*.md", Draft: true, DraftTTL: 24 * time.Hour},
+	}
+	facts := []entities.Fact{
+		{SourceFile: "notes/ideas.md", CreatedAt: created},
+		{SourceFile: "notes/brainstorm-1.md", CreatedAt: created},
+		{SourceFile: "book1/ch1.md", CreatedAt: created},
+	}
+
+	rules.Apply(facts)
+	assert.True(t, facts[0].Draft)
+	assert.True(t, facts[0].ExpiresAt.IsZero(), "no TTL, no expiry")
+	assert.True(t, facts[1].Draft)
+	assert.Equal(t, created.Add(24*time.Hour), facts[1].ExpiresAt)
+	assert.False(t, facts[2].Draft)
+}
+
+func TestSourceRules_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules SourceRules
+		err   string
+	}{
+		{"valid", SourceRules{{Pattern: "book*", Tags: []string{"draft"}}}, ""},
+		{"no pattern", SourceRules{{Tags: []string{"draft"}}}, "pattern is required"},
+		{"bad pattern", SourceRules{{Pattern: "[", Tags: []string{"draft"}}}, "invalid pattern"},
+		{"gives nothing", SourceRules{{Pattern: "book1"}}, "gives no metadata or tags"},
+		{"empty tag", SourceRules{{Pattern: "book1", Tags: []string{" "}}}, "tags must not be empty"},
+		{"draft only", SourceRules{{Pattern: "notes", Draft: true, DraftTTL: time.Hour}}, ""},
+		{"ttl without draft", SourceRules{{Pattern: "notes", Tags: []string{"idea"}, DraftTTL: time.Hour}}, "only for drafts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, entities.ErrValidation)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestExtractionService_SourceRules(t *testing.T) {
+	llm := &mocks.LLMClient{Facts: []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "carries", Object: "the ring"},
+	}}
+	svc, vectorDB := newMockExtractionService(llm)
+
+	_, err := svc.ExtractFromReader(context.Background(), strings.NewReader("Frodo carries the ring."), "/novel/book2/ch1.md", &ExtractionOptions{
+		Sources: SourceRules{{Pattern: "book2", Metadata: map[string]string{"book": "2"}, Tags: []string{"draft"}}},
+	})
+	require.NoError(t, err)
+	require.Len(t, vectorDB.SaveBatchLastFacts, 1)
+	assert.Equal(t, map[string]string{"book": "2"}, vectorDB.SaveBatchLastFacts[0].Metadata)
+	assert.Equal(t, []string{"draft"}, vectorDB.SaveBatchLastFacts[0].Tags)
+}
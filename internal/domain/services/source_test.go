@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func TestSourceService_RecordAndReport(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	svc := NewSourceService(relationalDB, &mocks.VectorDB{})
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for _, s := range []struct {
+		source       string
+		words, facts int
+	}{
+		{"ch1.md", 2000, 20},
+		{"ch2.md", 2000, 22},
+		{"ch3.md", 2000, 2},
+		{"ch4.md", 1000, 50},
+		{"ch5.md", 2000, 18},
+		{"note.md", 50, 0},
+	} {
+		require.NoError(t, svc.Record(ctx, s.source, s.words, s.facts))
+	}
+
+	report, err := svc.Report(ctx)
+	require.NoError(t, err)
+	require.Len(t, report.Sources, 6)
+	assert.InDelta(t, 10.0, report.Median, 0.001)
+	assert.Equal(t, now, report.Sources[0].IngestedAt)
+
+	flags := make(map[string]DensityFlag)
+	for _, s := range report.Sources {
+		flags[s.Source] = s.Flag
+	}
+	assert.Equal(t, map[string]DensityFlag{
+		"ch1.md":  "",
+		"ch2.md":  "",
+		"ch3.md":  DensityLow,
+		"ch4.md":  DensityHigh,
+		"ch5.md":  "",
+		"note.md": "",
+	}, flags, "sources too short to judge are not flagged")
+}
+
+func TestSourceService_Quarantine(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	svc := NewSourceService(relationalDB, &mocks.VectorDB{})
+	ctx := context.Background()
+
+	require.NoError(t, svc.Record(ctx, "ch1.md", 2000, 10))
+	for _, chunk := range []entities.FailedChunk{
+		{Source: "ch1.md", Index: 3, Text: "...", Error: "timeout"},
+		{Source: "ch1.md", Index: 1, Text: "...", Error: "timeout"},
+		{Source: "ch2.md", Index: 0, Text: "...", Error: "timeout"},
+	} {
+		require.NoError(t, svc.Quarantine(ctx, &chunk))
+	}
+
+	all, err := svc.Quarantined(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	chunks, err := svc.Quarantined(ctx, "ch1.md")
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, 1, chunks[0].Index)
+	assert.Equal(t, 1, chunks[0].Attempts)
+	assert.NotEmpty(t, chunks[0].ID)
+
+	require.NoError(t, svc.RetryFailed(ctx, &chunks[0], errors.New("invalid JSON")))
+	require.NoError(t, svc.RetrySucceeded(ctx, &chunks[1], 4))
+
+	chunks, err = svc.Quarantined(ctx, "ch1.md")
+	require.NoError(t, err)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, 2, chunks[0].Attempts)
+	assert.Equal(t, "invalid JSON", chunks[0].Error)
+
+	report, err := svc.Report(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 14, report.Sources[0].Facts, "recovered facts count toward the source")
+
+	require.NoError(t, svc.ClearQuarantine(ctx, "ch1.md"))
+	all, err = svc.Quarantined(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "ch2.md", all[0].Source)
+}
+
+func TestSourceService_Archive(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "1", SourceFile: "draft1.md"},
+		{ID: "2", SourceFile: "ch1.md"},
+	}}
+	svc := NewSourceService(relationalDB, vectorDB)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Quarantine(ctx, &entities.FailedChunk{Source: "draft1.md", Text: "...", Error: "timeout"}))
+	require.NoError(t, svc.Archive(ctx, "draft1.md"))
+	assert.True(t, vectorDB.Facts[0].Archived, "the source's facts are kept and labeled")
+	assert.False(t, vectorDB.Facts[1].Archived)
+	assert.Empty(t, relationalDB.FailedChunks, "its quarantined chunks are dropped")
+
+	archived, err := svc.ArchivedSet(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"draft1.md": true}, archived)
+
+	require.NoError(t, svc.Unarchive(ctx, "draft1.md"))
+	assert.False(t, vectorDB.Facts[0].Archived)
+	sources, err := svc.Archived(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+
+	assert.ErrorIs(t, svc.Unarchive(ctx, "draft1.md"), entities.ErrNotFound)
+	assert.ErrorIs(t, svc.Archive(ctx, "missing.md"), entities.ErrNotFound)
+}
+
+func TestMedian(t *testing.T) {
+	assert.Zero(t, median(nil))
+	assert.InDelta(t, 2.0, median([]float64{3, 1, 2}), 0.001)
+	assert.InDelta(t, 2.5, median([]float64{4, 1, 3, 2}), 0.001)
+}
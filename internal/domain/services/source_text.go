@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// DefaultMaxFileSize is the largest source ingested when no limit is
+// configured. A long novel is a few megabytes of text.
+const DefaultMaxFileSize entities.ByteSize = 64 << 20
+
+// sniffSize is how much of a source is read to detect its encoding.
+const sniffSize = 4096
+
+// textEncoding names an encoding source text is read from.
+type textEncoding string
+
+const (
+	encodingUTF8        textEncoding = "utf-8"
+	encodingUTF16LE     textEncoding = "utf-16le"
+	encodingUTF16BE     textEncoding = "utf-16be"
+	encodingWindows1252 textEncoding = "windows-1252"
+)
+
+// binarySignatures are the leading bytes of common formats that are not
+// text, with what to call them.
+var binarySignatures = []struct {
+	magic string
+	name  string
+}{
+	{"%PDF-", "a PDF document"},
+	{"PK\x03\x04", "a ZIP archive (such as a .docx or .epub)"},
+	{"\xD0\xCF\x11\xE0", "a legacy Office document"},
+	{"\x1F\x8B", "a gzip archive"},
+	{"\x89PNG", "a PNG image"},
+	{"\xFF\xD8\xFF", "a JPEG image"},
+	{"GIF8", "a GIF image"},
+}
+
+// errNUL reports a NUL byte found after the sniffed start of a source.
+var errNUL = entities.Errorf(entities.ErrValidation, "content has a NUL byte, so it is binary, not text")
+
+// ReadText returns a reader of the text in r as UTF-8. UTF-16 is detected
+// by its byte order mark or, without one, by the zero bytes of mostly
+// ASCII text, and text that is not valid UTF-8 is read as Windows-1252.
+// Binary content, such as a PDF, archive, or image, is rejected as a
+// validation error, and the reader fails once it has read more than
+// maxSize bytes of r (0 = DefaultMaxFileSize).
+func ReadText(r io.Reader, maxSize entities.ByteSize) (io.Reader, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxFileSize
+	}
+	buffered := bufio.NewReaderSize(&sizeLimitReader{r: r, limit: maxSize}, sniffSize)
+	head, err := buffered.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	encoding, bom, err := detectEncoding(head, len(head) < sniffSize)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := buffered.Discard(bom); err != nil {
+		return nil, err
+	}
+
+	// Invalid bytes become U+FFFD; NUL only appears in binary content.
+	var decoder transform.Transformer
+	switch encoding {
+	case encodingUTF16LE:
+		decoder = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	case encodingUTF16BE:
+		decoder = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder()
+	case encodingWindows1252:
+		decoder = charmap.Windows1252.NewDecoder()
+	default:
+		decoder = unicode.UTF8.NewDecoder()
+	}
+	return transform.NewReader(buffered, transform.Chain(decoder, nulRejecter{})), nil
+}
+
+// detectEncoding returns the encoding of text starting with head, and the
+// length of its byte order mark, if any. complete reports whether head is
+// the whole text. Content that is not text is a validation error.
+func detectEncoding(head []byte, complete bool) (textEncoding, int, error) {
+	switch {
+	case bytes.HasPrefix(head, []byte("\xFF\xFE\x00\x00")), bytes.HasPrefix(head, []byte("\x00\x00\xFE\xFF")):
+		return "", 0, entities.Errorf(entities.ErrValidation, "content is UTF-32 text, which is not supported; convert it to UTF-8")
+	case bytes.HasPrefix(head, []byte("\xEF\xBB\xBF")):
+		return encodingUTF8, 3, checkText(head[3:])
+	case bytes.HasPrefix(head, []byte("\xFF\xFE")):
+		return encodingUTF16LE, 2, nil
+	case bytes.HasPrefix(head, []byte("\xFE\xFF")):
+		return encodingUTF16BE, 2, nil
+	}
+
+	for _, sig := range binarySignatures {
+		if bytes.HasPrefix(head, []byte(sig.magic)) {
+			return "", 0, entities.Errorf(entities.ErrValidation, "content is %s, not text; convert it to plain text first", sig.name)
+		}
+	}
+
+	// ASCII text in UTF-16 has a zero byte in every other position, and
+	// few in the others.
+	var zeros [2]int
+	for i, b := range head {
+		if b == 0 {
+			zeros[i%2]++
+		}
+	}
+	pairs := len(head) / 2
+	switch {
+	case pairs > 0 && zeros[1] > pairs*2/5 && zeros[0] <= pairs/10:
+		return encodingUTF16LE, 0, nil
+	case pairs > 0 && zeros[0] > pairs*2/5 && zeros[1] <= pairs/10:
+		return encodingUTF16BE, 0, nil
+	}
+
+	if err := checkText(head); err != nil {
+		return "", 0, err
+	}
+	if !validUTF8Prefix(head, complete) {
+		return encodingWindows1252, 0, nil
+	}
+	return encodingUTF8, 0, nil
+}
+
+// checkText rejects head as binary if it has a NUL byte or is more than a
+// tenth control characters other than whitespace.
+func checkText(head []byte) error {
+	controls := 0
+	for _, b := range head {
+		switch {
+		case b == 0:
+			return errNUL
+		case b < 0x20 && b != '\t' && b != '\n' && b != '\r' && b != '\f' && b != '\v', b == 0x7F:
+			controls++
+		}
+	}
+	if controls*10 > len(head) {
+		return entities.Errorf(entities.ErrValidation, "content is mostly control characters, so it is binary, not text")
+	}
+	return nil
+}
+
+// validUTF8Prefix reports whether head is valid UTF-8, allowing it to end
+// partway through a character unless it is the whole text.
+func validUTF8Prefix(head []byte, complete bool) bool {
+	for len(head) > 0 {
+		r, size := utf8.DecodeRune(head)
+		if r == utf8.RuneError && size == 1 {
+			return !complete && !utf8.FullRune(head)
+		}
+		head = head[size:]
+	}
+	return true
+}
+
+// sizeLimitReader fails once more than limit bytes have been read.
+type sizeLimitReader struct {
+	r     io.Reader
+	limit entities.ByteSize
+	read  entities.ByteSize
+}
+
+// Read implements io.Reader.
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += entities.ByteSize(n)
+	if l.read > l.limit {
+		return n - int(l.read-l.limit), entities.Errorf(entities.ErrValidation, "content is larger than %s, the most read from one source", l.limit)
+	}
+	return n, err
+}
+
+// nulRejecter passes UTF-8 through, failing at a NUL byte.
+type nulRejecter struct{ transform.NopResetter }
+
+// Transform implements transform.Transformer.
+func (nulRejecter) Transform(dst, src []byte, _ bool) (nDst, nSrc int, err error) {
+	n := bytes.IndexByte(src, 0)
+	if n < 0 {
+		n = len(src)
+	}
+	if len(dst) < n {
+		nDst = copy(dst, src)
+		return nDst, nDst, transform.ErrShortDst
+	}
+	copy(dst, src[:n])
+	if n < len(src) {
+		return n, n, errNUL
+	}
+	return n, n, nil
+}
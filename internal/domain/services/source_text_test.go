@@ -0,0 +1,105 @@
+package services
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// encodeUTF16 returns text in UTF-16, big-endian if be is set.
+func encodeUTF16(text string, be bool) []byte {
+	var out []byte
+	for _, u := range utf16.Encode([]rune(text)) {
+		if be {
+			out = append(out, byte(u>>8), byte(u))
+		} else {
+			out = append(out, byte(u), byte(u>>8))
+		}
+	}
+	return out
+}
+
+// readText returns what ReadText reads from data.
+func readText(t *testing.T, data []byte, maxSize entities.ByteSize) (string, error) {
+	t.Helper()
+	r, err := ReadText(strings.NewReader(string(data)), maxSize)
+	if err != nil {
+		return "", err
+	}
+	text, err := io.ReadAll(r)
+	return string(text), err
+}
+
+func TestReadText(t *testing.T) {
+	const text = "Éowyn rode to Minas Tirith — “at dawn” 🐎."
+	long := strings.Repeat("The Riders of Rohan came at dawn.\n", 300) + text
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"UTF-8", []byte(text), text},
+		{"UTF-8 with BOM", append([]byte("\xEF\xBB\xBF"), text...), text},
+		{"UTF-16LE with BOM", append([]byte{0xFF, 0xFE}, encodeUTF16(text, false)...), text},
+		{"UTF-16BE with BOM", append([]byte{0xFE, 0xFF}, encodeUTF16(text, true)...), text},
+		{"UTF-16LE without BOM", encodeUTF16(long, false), long},
+		{"UTF-16BE without BOM", encodeUTF16(long, true), long},
+		{"Windows-1252", []byte("\x93Caf\xE9\x94 \x96 Eomer\x85"), "“Café” – Eomer…"},
+		{"invalid UTF-8 past the start", []byte(long + "\xFF"), long + "�"},
+		{"unpaired UTF-16 surrogate", append(append([]byte{0xFF, 0xFE}, encodeUTF16("Sam", false)...), 0x00, 0xD8, '!', 0x00), "Sam�!"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readText(t, tt.data, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReadText_RejectsBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"PDF", []byte("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n"), "a PDF document"},
+		{"docx", []byte("PK\x03\x04\x14\x00"), "a ZIP archive"},
+		{"PNG", []byte("\x89PNG\r\n\x1A\n"), "a PNG image"},
+		{"NUL at the start", []byte("Frodo\x00\x01\x02"), "NUL byte"},
+		{"NUL past the start", []byte(strings.Repeat("Sam. ", 1000) + "\x00"), "NUL byte"},
+		{"NUL in UTF-16", append([]byte{0xFF, 0xFE}, encodeUTF16("Sam\x00", false)...), "NUL byte"},
+		{"NUL in Windows-1252", []byte("Caf\xE9 " + strings.Repeat("Sam. ", 1000) + "\x00"), "NUL byte"},
+		{"control characters", []byte("\x01\x02\x03\x04abc"), "control characters"},
+		{"UTF-32", []byte("\xFF\xFE\x00\x00F\x00\x00\x00"), "UTF-32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readText(t, tt.data, 0)
+			require.ErrorIs(t, err, entities.ErrValidation)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestReadText_MaxSize(t *testing.T) {
+	text := strings.Repeat("Gimli swung his axe. ", 1000)
+
+	got, err := readText(t, []byte(text), entities.ByteSize(len(text)))
+	require.NoError(t, err)
+	assert.Equal(t, text, got, "a source of exactly the limit is read")
+
+	_, err = readText(t, []byte(text), entities.ByteSize(len(text)-1))
+	require.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), "larger than")
+}
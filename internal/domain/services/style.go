@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// maxStyleWords is the most words a spelling of a style sheet term may
+// span, so "Dragon Lord Of The North" can be matched against
+// "Dragonlord of the North".
+const maxStyleWords = 5
+
+// StyleService manages a world's style sheet: the canonical spellings,
+// capitalization, and hyphenation of its invented terms.
+type StyleService struct {
+	relationalDB ports.RelationalDB
+	now          func() time.Time
+}
+
+// NewStyleService creates a new style service.
+func NewStyleService(relationalDB ports.RelationalDB) *StyleService {
+	return &StyleService{
+		relationalDB: relationalDB,
+		now:          time.Now,
+	}
+}
+
+// Add records term's spelling as canonical, replacing any entry spelled
+// differently. source names where the spelling was established, if known.
+func (s *StyleService) Add(ctx context.Context, term, source string) (*entities.StyleTerm, error) {
+	term = strings.Join(strings.Fields(term), " ")
+	key := entities.StyleKey(term)
+	if key == "" {
+		return nil, entities.Errorf(entities.ErrValidation, "term is required")
+	}
+	if n := len(strings.Fields(term)); n > maxStyleWords {
+		return nil, entities.Errorf(entities.ErrValidation, "term %q has %d words (maximum %d)", term, n, maxStyleWords)
+	}
+
+	entry := &entities.StyleTerm{
+		Term:      term,
+		Key:       key,
+		Source:    strings.TrimSpace(source),
+		CreatedAt: s.now(),
+	}
+	if err := s.relationalDB.SaveStyleTerm(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// List returns the style sheet, ordered by term.
+func (s *StyleService) List(ctx context.Context) ([]entities.StyleTerm, error) {
+	return s.relationalDB.ListStyleTerms(ctx)
+}
+
+// Sheet loads the style sheet for checking facts against.
+func (s *StyleService) Sheet(ctx context.Context) (*StyleSheet, error) {
+	terms, err := s.relationalDB.ListStyleTerms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading style sheet: %w", err)
+	}
+
+	sheet := &StyleSheet{terms: make(map[string]entities.StyleTerm, len(terms))}
+	for i := range terms {
+		sheet.terms[terms[i].Key] = terms[i]
+	}
+	return sheet, nil
+}
+
+// StyleSheet checks facts against a world's canonical spellings.
+type StyleSheet struct {
+	terms map[string]entities.StyleTerm // By key
+}
+
+// Check returns the spellings in the facts' subjects, objects, and context
+// that match a style sheet term but are written differently, such as
+// "Dragonlord" for "Dragon Lord". Each spelling is reported once per fact.
+func (s *StyleSheet) Check(facts []entities.Fact) []entities.StyleIssue {
+	if s == nil || len(s.terms) == 0 {
+		return nil
+	}
+
+	var issues []entities.StyleIssue
+	for i := range facts {
+		seen := make(map[string]bool)
+		for _, text := range []string{facts[i].Subject, facts[i].Object, facts[i].Context} {
+			for _, found := range s.misspellings(text) {
+				if !seen[found] {
+					seen[found] = true
+					issues = append(issues, entities.StyleIssue{Fact: facts[i], Found: found, Term: s.terms[entities.StyleKey(found)]})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// misspellings returns the runs of words in text that match a term by
+// key but not by spelling. Longer runs are matched first, and words in a
+// matched run are not matched again.
+func (s *StyleSheet) misspellings(text string) []string {
+	var words []string
+	for _, w := range strings.Fields(text) {
+		w = strings.TrimFunc(w, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+
+	var found []string
+	for start := 0; start < len(words); {
+		matched := 0
+		for n := min(maxStyleWords, len(words)-start); n > 0 && matched == 0; n-- {
+			run := strings.Join(words[start:start+n], " ")
+			term, ok := s.terms[entities.StyleKey(run)]
+			if !ok {
+				continue
+			}
+			matched = n
+			if run != term.Term {
+				found = append(found, run)
+			}
+		}
+		start += max(matched, 1)
+	}
+	return found
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func newStyleTestService(t *testing.T, terms map[string]string) *StyleService {
+	t.Helper()
+	svc := NewStyleService(mocks.NewRelationalDB())
+	svc.now = func() time.Time { return conflictTestNow }
+	for term, source := range terms {
+		_, err := svc.Add(context.Background(), term, source)
+		require.NoError(t, err)
+	}
+	return svc
+}
+
+func TestStyleService_Add(t *testing.T) {
+	svc := newStyleTestService(t, nil)
+	ctx := context.Background()
+
+	term, err := svc.Add(ctx, "  Dragon   Lord ", "chapter2")
+	require.NoError(t, err)
+	assert.Equal(t, entities.StyleTerm{Term: "Dragon Lord", Key: "dragonlord", Source: "chapter2", CreatedAt: conflictTestNow}, *term)
+
+	_, err = svc.Add(ctx, "Dragon-Lord", "")
+	require.NoError(t, err)
+	terms, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, terms, 1, "a respelling replaces the entry")
+	assert.Equal(t, "Dragon-Lord", terms[0].Term)
+
+	_, err = svc.Add(ctx, " - ", "")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+	_, err = svc.Add(ctx, "the very long name of the north", "")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestStyleSheet_Check(t *testing.T) {
+	svc := newStyleTestService(t, map[string]string{"Dragon Lord": "chapter2", "Barad-dûr": ""})
+	sheet, err := svc.Sheet(context.Background())
+	require.NoError(t, err)
+
+	facts := []entities.Fact{
+		{ID: "1", Subject: "The Dragonlord", Predicate: "rules", Object: "the north", Context: "The dragon-lord rose. The Dragonlord fell."},
+		{ID: "2", Subject: "Dragon Lord", Predicate: "lives_in", Object: "Barad Dûr."},
+		{ID: "3", Subject: "Sauron", Predicate: "built", Object: "Barad-dûr"},
+	}
+	issues := sheet.Check(facts)
+	require.Len(t, issues, 3)
+
+	assert.Equal(t, "1", issues[0].Fact.ID)
+	assert.Equal(t, "Dragonlord", issues[0].Found)
+	assert.Equal(t, "'Dragonlord' previously spelled 'Dragon Lord' in chapter2", issues[0].Suggestion())
+	assert.Equal(t, "dragon-lord", issues[1].Found, "capitalization counts, and each spelling is reported once per fact")
+	assert.Equal(t, "Barad Dûr", issues[2].Found)
+	assert.Equal(t, "'Barad Dûr' is spelled 'Barad-dûr' in the style sheet", issues[2].Suggestion())
+}
+
+func TestStyleSheet_Check_Empty(t *testing.T) {
+	var sheet *StyleSheet
+	assert.Nil(t, sheet.Check([]entities.Fact{{Subject: "Dragonlord"}}))
+
+	sheet, err := newStyleTestService(t, nil).Sheet(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, sheet.Check([]entities.Fact{{Subject: "Dragonlord"}}))
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// MatchTier labels how a fact was found by a subject lookup.
+type MatchTier string
+
+const (
+	// MatchEntity facts are linked to the entity by that name, under any
+	// spelling merged into it.
+	MatchEntity MatchTier = "entity"
+	// MatchExact facts have the subject exactly as written.
+	MatchExact MatchTier = "exact"
+	// MatchFuzzy facts have the subject up to case, extra words, or typos.
+	MatchFuzzy MatchTier = "fuzzy"
+	// MatchSemantic facts are only semantically related to the subject.
+	MatchSemantic MatchTier = "semantic"
+)
+
+// SubjectMatch is a fact found by a subject lookup and the tier that found it.
+type SubjectMatch struct {
+	Fact entities.Fact
+	Tier MatchTier
+}
+
+// subjectCandidateOversample widens the keyword and semantic searches that
+// feed the fuzzy tier, since most of their results are filtered out.
+const subjectCandidateOversample = 4
+
+// FindBySubject returns facts about a subject, trying progressively looser
+// matches until limit facts are found: facts linked to the world's entity
+// by that name first, then exact subject matches, then fuzzy subject
+// matches, then semantic search. Each fact appears once, under the
+// strictest tier that found it.
+func (s *QueryService) FindBySubject(ctx context.Context, worldID, subject string, limit int) ([]SubjectMatch, error) {
+	subject = strings.TrimSpace(subject)
+	if subject == "" {
+		return nil, entities.Errorf(entities.ErrValidation, "subject is required")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	found := &subjectMatches{limit: limit, seen: make(map[string]bool)}
+
+	linked, err := s.linkedFacts(ctx, worldID, subject, limit)
+	if err != nil {
+		return nil, err
+	}
+	found.add(linked, MatchEntity)
+	if found.full() {
+		return found.matches, nil
+	}
+
+	exact, err := s.vectorDB.ListBySubject(ctx, []string{subject}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts by subject: %w", err)
+	}
+	found.add(exact, MatchExact)
+	if found.full() {
+		return found.matches, nil
+	}
+
+	candidateLimit := limit * subjectCandidateOversample
+
+	variants, err := s.vectorDB.ListBySubject(ctx, subjectVariants(subject), candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts by subject variants: %w", err)
+	}
+	keywords, err := s.vectorDB.SearchKeywords(ctx, subject, "", candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts by keywords: %w", err)
+	}
+	found.add(variants, MatchFuzzy)
+	found.add(filterBySubject(keywords, subject), MatchFuzzy)
+	if found.full() {
+		return found.matches, nil
+	}
+
+	embedding, err := s.embedder.Embed(ctx, subject)
+	if err != nil {
+		return nil, fmt.Errorf("generating query embedding: %w", err)
+	}
+	semantic, err := s.vectorDB.Search(ctx, embedding, candidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts: %w", err)
+	}
+	found.add(filterBySubject(semantic, subject), MatchFuzzy)
+	found.add(semantic, MatchSemantic)
+
+	return found.matches, nil
+}
+
+// linkedFacts returns up to limit facts linked to the world's entity named
+// subject, skipping facts pending review as subject listings do.
+func (s *QueryService) linkedFacts(ctx context.Context, worldID, subject string, limit int) ([]entities.Fact, error) {
+	entity, err := s.relationalDB.FindEntityByName(ctx, worldID, subject)
+	if err != nil {
+		return nil, fmt.Errorf("finding entity: %w", err)
+	}
+	if entity == nil {
+		return nil, nil
+	}
+
+	ids, err := s.relationalDB.ListFactIDsByEntities(ctx, []string{entity.ID}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing facts linked to %s: %w", entity.Name, err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	facts, err := s.vectorDB.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("finding facts linked to %s: %w", entity.Name, err)
+	}
+	return slices.DeleteFunc(facts, func(f entities.Fact) bool { return f.IsPending() }), nil
+}
+
+// subjectMatches collects facts up to a limit, skipping facts already found.
+type subjectMatches struct {
+	limit   int
+	seen    map[string]bool
+	matches []SubjectMatch
+}
+
+func (m *subjectMatches) add(facts []entities.Fact, tier MatchTier) {
+	for i := range facts {
+		if m.full() {
+			return
+		}
+		if m.seen[facts[i].ID] {
+			continue
+		}
+		m.seen[facts[i].ID] = true
+		m.matches = append(m.matches, SubjectMatch{Fact: facts[i], Tier: tier})
+	}
+}
+
+func (m *subjectMatches) full() bool {
+	return len(m.matches) >= m.limit
+}
+
+// subjectVariants returns common capitalizations of a subject other than
+// the one given, for exact payload matching.
+func subjectVariants(subject string) []string {
+	lower := strings.ToLower(subject)
+	words := strings.Fields(lower)
+	for i, w := range words {
+		first, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToTitle(first)) + w[size:]
+	}
+	title := strings.Join(words, " ")
+
+	var variants []string
+	for _, v := range []string{lower, title, strings.ToUpper(subject)} {
+		if v != subject && !slices.Contains(variants, v) {
+			variants = append(variants, v)
+		}
+	}
+	return variants
+}
+
+// filterBySubject keeps the facts whose subject fuzzily matches the query.
+func filterBySubject(facts []entities.Fact, query string) []entities.Fact {
+	var matched []entities.Fact
+	for i := range facts {
+		if subjectMatchesQuery(query, facts[i].Subject) {
+			matched = append(matched, facts[i])
+		}
+	}
+	return matched
+}
+
+// subjectMatchesQuery reports whether every word of the query matches a
+// word of the subject, ignoring case and allowing small typos. "frodo"
+// matches "Frodo Baggins" and "Frdo" matches "Frodo".
+func subjectMatchesQuery(query, subject string) bool {
+	queryWords := strings.Fields(entities.NormalizeName(query))
+	subjectWords := strings.Fields(entities.NormalizeName(subject))
+	if len(queryWords) == 0 || len(subjectWords) == 0 {
+		return false
+	}
+
+	for _, qw := range queryWords {
+		budget := typoBudget(qw)
+		if !slices.ContainsFunc(subjectWords, func(sw string) bool {
+			return levenshtein(qw, sw) <= budget
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// typoBudget is the number of edits a word may differ by and still match:
+// none for short words, one per four characters otherwise.
+func typoBudget(word string) int {
+	return len([]rune(word)) / 4
+}
+
+// levenshtein returns the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+)
+
+func subjectSearchFacts() []entities.Fact {
+	return []entities.Fact{
+		{ID: "1", Subject: "Frodo", Predicate: "is", Object: "a hobbit"},
+		{ID: "2", Subject: "frodo", Predicate: "lives_in", Object: "Bag End"},
+		{ID: "3", Subject: "Frodo Baggins", Predicate: "carries", Object: "the Ring"},
+		{ID: "4", Subject: "Frdo", Predicate: "fears", Object: "the Nazgul"},
+		{ID: "5", Subject: "Sam", Predicate: "follows", Object: "his master"},
+		{ID: "6", Subject: "Frodo", Predicate: "doubts", Object: "himself", Status: entities.FactStatusPending},
+	}
+}
+
+func TestQueryService_FindBySubject(t *testing.T) {
+	emb := &mocks.Embedder{EmbeddingResult: []float32{0.1, 0.2}}
+	// Semantic search in the mock returns facts in storage order, so leave
+	// the pending fact out of it as the real store would.
+	db := &mocks.VectorDB{Facts: subjectSearchFacts()[:5]}
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
+
+	matches, err := svc.FindBySubject(t.Context(), "w", "Frodo", 10)
+	require.NoError(t, err)
+
+	type got struct {
+		ID   string
+		Tier MatchTier
+	}
+	var results []got
+	for _, m := range matches {
+		results = append(results, got{m.Fact.ID, m.Tier})
+	}
+	assert.Equal(t, []got{
+		{"1", MatchExact},
+		{"2", MatchFuzzy},
+		{"3", MatchFuzzy},
+		{"4", MatchFuzzy},
+		{"5", MatchSemantic},
+	}, results)
+}
+
+func TestQueryService_FindBySubject_StopsWhenFull(t *testing.T) {
+	// A failing embedder proves the semantic tier is never reached.
+	emb := &mocks.Embedder{Err: errors.New("embedder unavailable")}
+	db := &mocks.VectorDB{Facts: subjectSearchFacts()}
+	svc := NewQueryService(emb, db, &mocks.RelationalDB{})
+
+	matches, err := svc.FindBySubject(t.Context(), "w", "Frodo", 2)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, MatchExact, matches[0].Tier)
+	assert.Equal(t, "1", matches[0].Fact.ID, "pending facts are skipped")
+	assert.Equal(t, MatchFuzzy, matches[1].Tier)
+}
+
+func TestQueryService_FindBySubject_LinkedEntity(t *testing.T) {
+	// A failing embedder proves the semantic tier is never reached.
+	emb := &mocks.Embedder{Err: errors.New("embedder unavailable")}
+	facts := append(subjectSearchFacts(), entities.Fact{ID: "7", Subject: "Mr. Underhill", Predicate: "stays_at", Object: "the Prancing Pony"})
+	db := &mocks.VectorDB{Facts: facts}
+	relationalDB := mocks.NewRelationalDB()
+	relationalDB.Entities["frodo"] = &entities.Entity{ID: "frodo", WorldID: "w", Name: "Frodo", NormalizedName: "frodo"}
+	relationalDB.FactSubjects = []entities.FactSubject{
+		{FactID: "7", EntityID: "frodo"},
+		{FactID: "6", EntityID: "frodo"},
+	}
+	svc := NewQueryService(emb, db, relationalDB)
+
+	matches, err := svc.FindBySubject(t.Context(), "w", "Frodo", 2)
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, SubjectMatch{Fact: facts[6], Tier: MatchEntity}, matches[0], "linked facts come first, pending ones skipped")
+	assert.Equal(t, "1", matches[1].Fact.ID)
+	assert.Equal(t, MatchExact, matches[1].Tier)
+}
+
+func TestQueryService_FindBySubject_EmptySubject(t *testing.T) {
+	svc := NewQueryService(&mocks.Embedder{}, &mocks.VectorDB{}, &mocks.RelationalDB{})
+
+	_, err := svc.FindBySubject(t.Context(), "w", "  ", 10)
+	assert.Error(t, err)
+}
+
+func TestSubjectMatchesQuery(t *testing.T) {
+	tests := []struct {
+		query   string
+		subject string
+		want    bool
+	}{
+		{"Frodo", "frodo", true},
+		{"Frodo", "Frodo Baggins", true},
+		{"frodo baggins", "Baggins, Frodo", true},
+		{"Frdo", "Frodo", true},
+		{"Gandalf", "Gandalf the Grey", true},
+		{"Sam", "Pam", false},
+		{"Frodo", "Sam", false},
+		{"", "Frodo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query+"/"+tt.subject, func(t *testing.T) {
+			assert.Equal(t, tt.want, subjectMatchesQuery(tt.query, tt.subject))
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"frodo", "frodo", 0},
+		{"frodo", "frdo", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, levenshtein(tt.a, tt.b), "%q vs %q", tt.a, tt.b)
+	}
+}
+
+func TestSubjectVariants(t *testing.T) {
+	assert.Equal(t, []string{"frodo baggins", "Frodo Baggins", "FRODO BAGGINS"}, subjectVariants("frodo Baggins"))
+	assert.Equal(t, []string{"frodo", "FRODO"}, subjectVariants("Frodo"))
+	assert.Equal(t, []string{"éowyn", "Éowyn", "ÉOWYN"}, subjectVariants("éOWYN"))
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// DefaultSweepWindow is how far back the first sweep looks for added
+	// facts, since there is no earlier sweep to continue from.
+	DefaultSweepWindow = 24 * time.Hour
+	// MaxSweepFacts is the most added facts one sweep checks.
+	MaxSweepFacts = 1000
+)
+
+// SweepOptions controls a consistency sweep.
+type SweepOptions struct {
+	World string // Named in the digest
+
+	// Retrieval selects the stored facts each added fact is checked against.
+	Retrieval Retrieval
+}
+
+// SweepResult contains the result of a consistency sweep.
+type SweepResult struct {
+	Since   time.Time // Facts added at or after this were checked
+	Checked int       // Facts checked
+	New     []ports.ConsistencyIssue
+
+	Notified int // Notifiers the digest was sent to
+}
+
+// SweepService checks the facts added since its last sweep for
+// contradictions, records them, and sends a digest of the new conflicts to
+// its notifiers, so contradictions are caught without a manual check.
+type SweepService struct {
+	vectorDB  ports.VectorDB
+	conflicts *ConflictService
+	notifiers []ports.Notifier
+	now       func() time.Time
+
+	mu   sync.Mutex
+	last time.Time // Start of the latest successful sweep
+}
+
+// NewSweepService creates a new sweep service.
+func NewSweepService(vectorDB ports.VectorDB, conflicts *ConflictService, notifiers []ports.Notifier) *SweepService {
+	return &SweepService{
+		vectorDB:  vectorDB,
+		conflicts: conflicts,
+		notifiers: notifiers,
+		now:       time.Now,
+	}
+}
+
+// Sweep checks the facts added since the last successful sweep, or within
+// DefaultSweepWindow for the first, and sends the digest if any new
+// conflicts were recorded. A failed notification does not make the sweep
+// check the same facts again; the conflicts stay recorded.
+func (s *SweepService) Sweep(ctx context.Context, opts *SweepOptions) (*SweepResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := s.now()
+	since := s.last
+	if since.IsZero() {
+		since = start.Add(-DefaultSweepWindow)
+	}
+
+	facts, err := s.vectorDB.ListFiltered(ctx, ports.FactListOptions{
+		Since: since,
+		Sort:  ports.FactSortCreated,
+		Limit: MaxSweepFacts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing facts added since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	checked, err := s.conflicts.CheckFacts(ctx, facts, &CheckOptions{Retrieval: opts.Retrieval})
+	if err != nil {
+		return nil, err
+	}
+	s.last = start
+
+	result := &SweepResult{
+		Since:   since,
+		Checked: checked.Checked,
+		New:     checked.New,
+	}
+	if len(result.New) == 0 {
+		return result, nil
+	}
+
+	subject, body := Digest(opts.World, result)
+	var errs []error
+	for _, n := range s.notifiers {
+		if err := n.Notify(ctx, subject, body); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result.Notified++
+	}
+	if err := errors.Join(errs...); err != nil {
+		return result, fmt.Errorf("sending consistency digest: %w", err)
+	}
+	return result, nil
+}
+
+// Digest returns the subject and body of the message reporting a sweep's
+// new conflicts.
+func Digest(world string, result *SweepResult) (string, string) {
+	subject := fmt.Sprintf("[lore] %d new contradiction(s) in %s", len(result.New), world)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "The consistency sweep of %s checked %d fact(s) added since %s and found %d new contradiction(s).\n",
+		world, result.Checked, result.Since.Format(time.RFC3339), len(result.New))
+	for i := range result.New {
+		issue := &result.New[i]
+		fmt.Fprintf(&b, "\n%s: %s\n", strings.ToUpper(issue.Severity), issue.Description)
+		fmt.Fprintf(&b, "  New:      %s\n", digestFact(&issue.NewFact))
+		fmt.Fprintf(&b, "  Existing: %s\n", digestFact(&issue.ExistingFact))
+	}
+	b.WriteString("\nSee 'lore conflicts list' to review them.\n")
+	return subject, b.String()
+}
+
+// digestFact describes a fact in a digest.
+func digestFact(fact *entities.Fact) string {
+	if fact.SourceFile == "" {
+		return fmt.Sprintf("%s %s %s", fact.Subject, fact.Predicate, fact.Object)
+	}
+	return fmt.Sprintf("%s %s %s (%s)", fact.Subject, fact.Predicate, fact.Object, fact.SourceFile)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// recordingNotifier records the messages sent to it.
+type recordingNotifier struct {
+	subjects []string
+	bodies   []string
+	err      error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, subject, body string) error {
+	n.subjects = append(n.subjects, subject)
+	n.bodies = append(n.bodies, body)
+	return n.err
+}
+
+func TestSweepService_Sweep(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "old", Subject: "Frodo", Predicate: "eye_color", Object: "blue", CreatedAt: conflictTestNow.Add(-48 * time.Hour)},
+		{ID: "new", Subject: "Frodo", Predicate: "eye_color", Object: "green", SourceFile: "ch2.md", CreatedAt: conflictTestNow.Add(-time.Hour)},
+	}}
+	issue := conflictIssue("new", "old")
+	issue.NewFact = vectorDB.Facts[1]
+	issue.ExistingFact = vectorDB.Facts[0]
+	llm := &mocks.LLMClient{Issues: []ports.ConsistencyIssue{issue}}
+	relationalDB := mocks.NewRelationalDB()
+
+	notifier := &recordingNotifier{}
+	svc := NewSweepService(vectorDB, newConflictTestService(llm, vectorDB, relationalDB), []ports.Notifier{notifier})
+	svc.now = func() time.Time { return conflictTestNow }
+
+	result, err := svc.Sweep(context.Background(), &SweepOptions{World: "middle-earth"})
+	require.NoError(t, err)
+	assert.Equal(t, conflictTestNow.Add(-DefaultSweepWindow), result.Since)
+	assert.Equal(t, 1, result.Checked, "facts older than the window are not checked")
+	require.Len(t, result.New, 1)
+	assert.Equal(t, 1, result.Notified)
+	require.Len(t, notifier.subjects, 1)
+	assert.Equal(t, "[lore] 1 new contradiction(s) in middle-earth", notifier.subjects[0])
+	assert.Contains(t, notifier.bodies[0], "MAJOR: eye colors differ")
+	assert.Contains(t, notifier.bodies[0], "New:      Frodo eye_color green (ch2.md)")
+	assert.Contains(t, notifier.bodies[0], "Existing: Frodo eye_color blue")
+	require.Len(t, relationalDB.Conflicts, 1)
+
+	// The next sweep continues from the last and records nothing new, so
+	// sends no digest
+	svc.now = func() time.Time { return conflictTestNow.Add(24 * time.Hour) }
+	vectorDB.Facts[1].CreatedAt = conflictTestNow
+	result, err = svc.Sweep(context.Background(), &SweepOptions{World: "middle-earth"})
+	require.NoError(t, err)
+	assert.Equal(t, conflictTestNow, result.Since)
+	assert.Equal(t, 1, result.Checked)
+	assert.Empty(t, result.New, "conflicts already recorded are not new")
+	assert.Len(t, notifier.subjects, 1)
+}
+
+func TestSweepService_NotifyError(t *testing.T) {
+	vectorDB := &mocks.VectorDB{Facts: []entities.Fact{
+		{ID: "a", Subject: "Frodo", CreatedAt: conflictTestNow},
+		{ID: "b", Subject: "Frodo", CreatedAt: conflictTestNow},
+	}}
+	llm := &mocks.LLMClient{Issues: []ports.ConsistencyIssue{conflictIssue("a", "b")}}
+
+	failing := &recordingNotifier{err: errors.New("smtp down")}
+	working := &recordingNotifier{}
+	svc := NewSweepService(vectorDB, newConflictTestService(llm, vectorDB, mocks.NewRelationalDB()), []ports.Notifier{failing, working})
+	svc.now = func() time.Time { return conflictTestNow }
+
+	result, err := svc.Sweep(context.Background(), &SweepOptions{World: "w"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "smtp down")
+	require.NotNil(t, result)
+	assert.Equal(t, 1, result.Notified, "a failing notifier does not stop the others")
+	assert.Len(t, working.subjects, 1)
+}
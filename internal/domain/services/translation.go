@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// DefaultTranslationThreshold is the cosine similarity at or above which
+	// two facts are sent to the LLM as a possible translation. It is lower
+	// than DefaultSimilarityThreshold because a sentence and its translation
+	// embed less closely than two wordings in one language, even with a
+	// multilingual embedding model.
+	DefaultTranslationThreshold = 0.8
+
+	// translationBatchSize is the number of pairs confirmed per LLM call.
+	translationBatchSize = 20
+
+	// maxTranslationCandidates is the number of nearest facts compared with
+	// each fact.
+	maxTranslationCandidates = 3
+
+	// translationScrollBatch is the number of facts read per scroll request.
+	translationScrollBatch = 256
+)
+
+// errTranslationLimit stops the scroll once enough facts have been read.
+var errTranslationLimit = errors.New("translation limit reached")
+
+// TranslationOptions controls a search for facts stated in two languages.
+type TranslationOptions struct {
+	Threshold float64 // Minimum similarity of a candidate pair (0 = DefaultTranslationThreshold)
+	Limit     int     // Maximum facts to scan (0 = all)
+	DryRun    bool    // Report confirmed pairs without linking them
+}
+
+// TranslationCandidate is a pair of facts close enough across languages to
+// be checked as a translation.
+type TranslationCandidate struct {
+	ports.FactPair
+	Similarity float64 // Cosine similarity of the two facts' triples
+}
+
+// TranslationResult contains the result of a search for translations.
+type TranslationResult struct {
+	Checked    int                    // Facts scanned
+	Candidates int                    // Pairs sent to the LLM
+	Confirmed  []TranslationCandidate // Pairs the LLM confirmed as translations
+	Linked     int                    // Confirmed pairs recorded as new links
+}
+
+// TranslationService finds facts that state the same thing in different
+// languages and links them, so that bilingual worlds do not report them as
+// duplicates or contradictions.
+type TranslationService struct {
+	admin        ports.VectorCollectionAdmin
+	vectorDB     ports.VectorDB
+	relationalDB ports.RelationalDB
+	llm          ports.LLMClient
+	now          func() time.Time
+}
+
+// NewTranslationService creates a new TranslationService.
+func NewTranslationService(admin ports.VectorCollectionAdmin, vectorDB ports.VectorDB, relationalDB ports.RelationalDB, llm ports.LLMClient) *TranslationService {
+	return &TranslationService{
+		admin:        admin,
+		vectorDB:     vectorDB,
+		relationalDB: relationalDB,
+		llm:          llm,
+		now:          time.Now,
+	}
+}
+
+// Detect compares each fact in collection with its nearest neighbours of
+// any type, asks the LLM which close pairs are translations of each other,
+// and links those unless opts.DryRun is set. Pairs already linked, and
+// pairs worded the same, which are duplicates, are not checked again.
+func (s *TranslationService) Detect(ctx context.Context, collection string, opts TranslationOptions) (*TranslationResult, error) {
+	if opts.Threshold < 0 || opts.Threshold > 1 {
+		return nil, entities.Errorf(entities.ErrValidation, "threshold must be between 0 and 1, got %g", opts.Threshold)
+	}
+	threshold := opts.Threshold
+	if threshold == 0 {
+		threshold = DefaultTranslationThreshold
+	}
+
+	links, err := s.relationalDB.ListTranslations(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing translations: %w", err)
+	}
+	seen := make(map[[2]string]bool, len(links))
+	for i := range links {
+		seen[factPairKey(links[i].FactID, links[i].OtherFactID)] = true
+	}
+
+	facts, err := s.loadFacts(ctx, collection, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	result := &TranslationResult{Checked: len(facts)}
+
+	var candidates []TranslationCandidate
+	for i := range facts {
+		found, err := s.candidates(ctx, &facts[i], threshold, seen)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+	}
+	result.Candidates = len(candidates)
+
+	for start := 0; start < len(candidates); start += translationBatchSize {
+		batch := candidates[start:min(start+translationBatchSize, len(candidates))]
+		pairs := make([]ports.FactPair, len(batch))
+		for i := range batch {
+			pairs[i] = batch[i].FactPair
+		}
+		confirmed, err := s.llm.ConfirmTranslations(ctx, pairs)
+		if err != nil {
+			return nil, fmt.Errorf("confirming translations: %w", err)
+		}
+		for _, i := range confirmed {
+			if i >= 0 && i < len(batch) {
+				result.Confirmed = append(result.Confirmed, batch[i])
+			}
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	for i := range result.Confirmed {
+		created, err := s.Link(ctx, result.Confirmed[i].Fact.ID, result.Confirmed[i].Other.ID)
+		if err != nil {
+			return result, err
+		}
+		if created {
+			result.Linked++
+		}
+	}
+	return result, nil
+}
+
+// Link records two facts as translations of each other and reports whether
+// the link is new.
+func (s *TranslationService) Link(ctx context.Context, factID, otherFactID string) (bool, error) {
+	if factID == "" || otherFactID == "" || factID == otherFactID {
+		return false, entities.Errorf(entities.ErrValidation, "a translation links two different facts")
+	}
+	key := factPairKey(factID, otherFactID)
+	created, err := s.relationalDB.SaveTranslation(ctx, &entities.Translation{
+		FactID:      key[0],
+		OtherFactID: key[1],
+		CreatedAt:   s.now(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("linking %s and %s as translations: %w", factID, otherFactID, err)
+	}
+	return created, nil
+}
+
+// candidates returns the stored facts close enough to fact to be its
+// translation, marking each pair as seen.
+func (s *TranslationService) candidates(ctx context.Context, fact *entities.Fact, threshold float64, seen map[[2]string]bool) ([]TranslationCandidate, error) {
+	embedding := fact.TextEmbedding
+	if len(embedding) == 0 {
+		embedding = fact.Embedding
+	}
+	// No type filter: extraction may type a fact differently in each language.
+	neighbours, err := s.vectorDB.SearchVector(ctx, ports.VectorText, embedding, "", maxTranslationCandidates+1)
+	if err != nil {
+		return nil, fmt.Errorf("searching facts near %s: %w", fact.ID, err)
+	}
+
+	var found []TranslationCandidate
+	for i := range neighbours {
+		other := &neighbours[i]
+		if other.ID == fact.ID || other.IsPending() || sameWording(fact, other) {
+			continue
+		}
+		key := factPairKey(fact.ID, other.ID)
+		if seen[key] {
+			continue
+		}
+		score := tripleSimilarity(fact, other)
+		if score < threshold {
+			continue
+		}
+		seen[key] = true
+		found = append(found, TranslationCandidate{
+			FactPair:   ports.FactPair{Fact: withoutEmbeddings(fact), Other: withoutEmbeddings(other)},
+			Similarity: score,
+		})
+	}
+	return found, nil
+}
+
+// loadFacts reads up to limit active facts that have an embedding, or all
+// of them if limit is 0.
+func (s *TranslationService) loadFacts(ctx context.Context, collection string, limit int) ([]entities.Fact, error) {
+	var facts []entities.Fact
+	err := s.admin.ScrollFacts(ctx, collection, translationScrollBatch, func(batch []entities.Fact) error {
+		for i := range batch {
+			if batch[i].IsPending() || (len(batch[i].Embedding) == 0 && len(batch[i].TextEmbedding) == 0) {
+				continue
+			}
+			if limit > 0 && len(facts) == limit {
+				return errTranslationLimit
+			}
+			facts = append(facts, batch[i])
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errTranslationLimit) {
+		return nil, fmt.Errorf("reading facts: %w", err)
+	}
+	return facts, nil
+}
+
+// sameWording reports whether two facts have the same subject, predicate,
+// and object up to case, which makes them duplicates rather than
+// translations.
+func sameWording(a, b *entities.Fact) bool {
+	return entities.NormalizeName(a.Subject) == entities.NormalizeName(b.Subject) &&
+		entities.NormalizeName(a.Predicate) == entities.NormalizeName(b.Predicate) &&
+		entities.NormalizeName(a.Object) == entities.NormalizeName(b.Object)
+}
+
+// withoutEmbeddings returns a copy of fact without its embeddings, which
+// the LLM does not need.
+func withoutEmbeddings(fact *entities.Fact) entities.Fact {
+	stripped := *fact
+	stripped.Embedding = nil
+	stripped.TextEmbedding = nil
+	return stripped
+}
+
+// factPairKey returns the IDs of a pair of facts in ID order, the order
+// conflicts and translations are stored in.
+func factPairKey(a, b string) [2]string {
+	if a > b {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
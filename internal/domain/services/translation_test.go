@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var translationTestNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// translationFacts returns an English fact, its French translation, an
+// English duplicate, an unrelated fact, and a pending fact.
+func translationFacts() []entities.Fact {
+	return []entities.Fact{
+		{ID: "en", Type: entities.FactTypeCharacter, Subject: "Jean", Predicate: "lives_in", Object: "Paris", TextEmbedding: []float32{1, 0, 0}},
+		{ID: "fr", Type: entities.FactTypeLocation, Subject: "Jean", Predicate: "vit_à", Object: "Paris", TextEmbedding: []float32{0.9, 0.3, 0}},
+		{ID: "dup", Type: entities.FactTypeCharacter, Subject: "jean", Predicate: "lives_in", Object: "paris", TextEmbedding: []float32{1, 0.01, 0}},
+		{ID: "other", Type: entities.FactTypeCharacter, Subject: "Marie", Predicate: "owns", Object: "a boat", TextEmbedding: []float32{0, 0, 1}},
+		{ID: "draft", Subject: "Jean", Status: entities.FactStatusPending, TextEmbedding: []float32{1, 0, 0}},
+	}
+}
+
+func newTranslationTestService(llm *mocks.LLMClient, relationalDB *mocks.RelationalDB) *TranslationService {
+	facts := translationFacts()
+	admin := newFakeCollectionAdmin()
+	admin.collections["world"] = facts
+	svc := NewTranslationService(admin, &mocks.VectorDB{Facts: facts}, relationalDB, llm)
+	svc.now = func() time.Time { return translationTestNow }
+	return svc
+}
+
+// confirmAll confirms every pair as a translation.
+func confirmAll(pairs []ports.FactPair) []int {
+	indexes := make([]int, len(pairs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+func TestTranslationService_Detect(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	relationalDB.Translations = []entities.Translation{{FactID: "dup", OtherFactID: "fr"}}
+	llm := &mocks.LLMClient{Translations: confirmAll}
+	svc := newTranslationTestService(llm, relationalDB)
+
+	result, err := svc.Detect(context.Background(), "world", TranslationOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Checked, "pending fact not scanned")
+	assert.Equal(t, 1, result.Candidates, "duplicate, linked, and distant pairs not checked")
+	require.Len(t, result.Confirmed, 1)
+	assert.Equal(t, "en", result.Confirmed[0].Fact.ID)
+	assert.Equal(t, "fr", result.Confirmed[0].Other.ID)
+	assert.Empty(t, result.Confirmed[0].Fact.TextEmbedding, "embeddings not sent to the LLM")
+	assert.Equal(t, 1, result.Linked)
+
+	require.Len(t, relationalDB.Translations, 2)
+	assert.Equal(t, entities.Translation{FactID: "en", OtherFactID: "fr", CreatedAt: translationTestNow}, relationalDB.Translations[1])
+}
+
+func TestTranslationService_Detect_Unconfirmed(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	llm := &mocks.LLMClient{}
+	svc := newTranslationTestService(llm, relationalDB)
+
+	result, err := svc.Detect(context.Background(), "world", TranslationOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Candidates)
+	require.Len(t, llm.ConfirmTranslationsPairs, 1, "candidates confirmed in one batch")
+	assert.Empty(t, result.Confirmed)
+	assert.Empty(t, relationalDB.Translations)
+}
+
+func TestTranslationService_Detect_DryRun(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	svc := newTranslationTestService(&mocks.LLMClient{Translations: confirmAll}, relationalDB)
+
+	result, err := svc.Detect(context.Background(), "world", TranslationOptions{DryRun: true})
+	require.NoError(t, err)
+
+	assert.Len(t, result.Confirmed, 2)
+	assert.Zero(t, result.Linked)
+	assert.Empty(t, relationalDB.Translations)
+}
+
+func TestTranslationService_Detect_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    TranslationOptions
+		llm     *mocks.LLMClient
+		wantErr string
+	}{
+		{
+			name:    "threshold out of range",
+			opts:    TranslationOptions{Threshold: 1.5},
+			llm:     &mocks.LLMClient{},
+			wantErr: "threshold must be between 0 and 1",
+		},
+		{
+			name:    "LLM failure",
+			llm:     &mocks.LLMClient{TranslationErr: errors.New("rate limited")},
+			wantErr: "confirming translations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTranslationTestService(tt.llm, mocks.NewRelationalDB())
+
+			_, err := svc.Detect(context.Background(), "world", tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestTranslationService_Link(t *testing.T) {
+	relationalDB := mocks.NewRelationalDB()
+	svc := newTranslationTestService(&mocks.LLMClient{}, relationalDB)
+	ctx := context.Background()
+
+	created, err := svc.Link(ctx, "fr", "en")
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = svc.Link(ctx, "en", "fr")
+	require.NoError(t, err)
+	assert.False(t, created, "pair already linked")
+
+	require.Len(t, relationalDB.Translations, 1)
+	assert.Equal(t, "en", relationalDB.Translations[0].FactID, "pair stored in ID order")
+
+	_, err = svc.Link(ctx, "en", "en")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// typedContradictionSeverity is the severity of contradictions between
+// typed objects. Values that differ outright are never a matter of
+// interpretation.
+const typedContradictionSeverity = "major"
+
+// detectObjectTypes sets each fact's ObjectType from its object.
+func detectObjectTypes(facts []entities.Fact) {
+	for i := range facts {
+		facts[i].ObjectType = entities.ParseObjectValue(facts[i].Object).Type
+	}
+}
+
+// typedContradictions compares new facts against existing facts with the
+// same subject and predicate, reporting those whose typed objects differ,
+// such as ages 30 and 31. Text objects are left to the LLM.
+func typedContradictions(newFacts, existingFacts []entities.Fact) []ports.ConsistencyIssue {
+	var issues []ports.ConsistencyIssue
+	reported := make(map[string]bool)
+
+	for i := range newFacts {
+		value := newFacts[i].ObjectValue()
+		if !value.IsTyped() {
+			continue
+		}
+		for j := range existingFacts {
+			if newFacts[i].ID == existingFacts[j].ID || !sameSubjectAndPredicate(&newFacts[i], &existingFacts[j]) {
+				continue
+			}
+			if !value.Contradicts(existingFacts[j].ObjectValue()) {
+				continue
+			}
+			issue := ports.ConsistencyIssue{
+				NewFact:      newFacts[i],
+				ExistingFact: existingFacts[j],
+				Description: fmt.Sprintf("%s %s %s, but an existing fact says %s",
+					newFacts[i].Subject, newFacts[i].Predicate, newFacts[i].Object, existingFacts[j].Object),
+				Severity: typedContradictionSeverity,
+			}
+			if key := issuePairKey(&issue); !reported[key] {
+				reported[key] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	return issues
+}
+
+// sameSubjectAndPredicate reports whether a and b state the same property
+// of the same subject.
+func sameSubjectAndPredicate(a, b *entities.Fact) bool {
+	return entities.NormalizeName(a.Subject) == entities.NormalizeName(b.Subject) &&
+		entities.NormalizeName(a.Predicate) == entities.NormalizeName(b.Predicate)
+}
+
+// issuePairKey identifies the pair of facts an issue is about, in either
+// order.
+func issuePairKey(issue *ports.ConsistencyIssue) string {
+	a, b := issue.NewFact.ID, issue.ExistingFact.ID
+	if a > b {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestDetectObjectTypes(t *testing.T) {
+	facts := []entities.Fact{{Object: "111 years"}, {Object: "1418-09-22"}, {Object: "yes"}, {Object: "Bag End"}}
+	detectObjectTypes(facts)
+
+	assert.Equal(t, entities.ObjectTypeNumber, facts[0].ObjectType)
+	assert.Equal(t, entities.ObjectTypeDate, facts[1].ObjectType)
+	assert.Equal(t, entities.ObjectTypeBoolean, facts[2].ObjectType)
+	assert.Equal(t, entities.ObjectTypeText, facts[3].ObjectType)
+}
+
+func TestTypedContradictions(t *testing.T) {
+	age33 := entities.Fact{ID: "a", Subject: "Frodo", Predicate: "age", Object: "33"}
+	age50 := entities.Fact{ID: "b", Subject: "frodo", Predicate: "Age", Object: "50"}
+	bilboAge := entities.Fact{ID: "c", Subject: "Bilbo", Predicate: "age", Object: "111"}
+	height := entities.Fact{ID: "d", Subject: "Frodo", Predicate: "height", Object: "4 feet"}
+	home := entities.Fact{ID: "e", Subject: "Frodo", Predicate: "age", Object: "unknown"}
+
+	issues := typedContradictions([]entities.Fact{age33}, []entities.Fact{age33, age50, bilboAge, height, home})
+	assert.Len(t, issues, 1, "only the same subject and predicate with a different value")
+	assert.Equal(t, "b", issues[0].ExistingFact.ID)
+	assert.Equal(t, typedContradictionSeverity, issues[0].Severity)
+	assert.Equal(t, "Frodo age 33, but an existing fact says 50", issues[0].Description)
+
+	both := []entities.Fact{age33, age50}
+	assert.Len(t, typedContradictions(both, both), 1, "a pair is reported once")
+
+	text := age50
+	text.ObjectType = entities.ObjectTypeText
+	assert.Empty(t, typedContradictions([]entities.Fact{age33}, []entities.Fact{text}), "objects detected as text are left to the LLM")
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// WorldRenameResult describes a completed rename.
+type WorldRenameResult struct {
+	OldCollection string
+	NewCollection string
+	// Warnings lists cleanup after the rename that failed, such as an old
+	// alias left behind. The rename itself succeeded.
+	Warnings []string
+}
+
+// WorldService renames worlds, keeping their data.
+type WorldService struct {
+	registry ports.WorldRegistry
+	storage  ports.WorldStorage
+}
+
+// NewWorldService creates a WorldService over the worlds of registry,
+// whose data storage keeps.
+func NewWorldService(registry ports.WorldRegistry, storage ports.WorldStorage) *WorldService {
+	return &WorldService{registry: registry, storage: storage}
+}
+
+// worldRename is a rename in progress. Each completed step registers how
+// to undo itself, so a failure part way through leaves the world as it
+// was.
+type worldRename struct {
+	registry         ports.WorldRegistry
+	storage          ports.WorldStorage
+	oldName, newName string
+	oldCollection    string
+	newCollection    string
+	oldIsAlias       bool // The old collection name is an alias, dropped once the rename is done
+	moveDir          bool
+	undo             []func() error
+}
+
+// Rename renames world oldName to newName without re-ingesting its facts:
+// its collection is reached through an alias named after the new world,
+// its directory is moved, its entities take the new world ID, and it is
+// registered under the new name. If a step fails, the earlier ones are
+// undone; undo steps that fail are joined to the error.
+func (s *WorldService) Rename(ctx context.Context, oldName, newName string) (*WorldRenameResult, error) {
+	r, err := s.plan(oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range []func(context.Context) error{r.alias, r.move, r.renameEntities, r.register} {
+		if err := step(ctx); err != nil {
+			return nil, r.rollback(err)
+		}
+	}
+
+	return &WorldRenameResult{
+		OldCollection: r.oldCollection,
+		NewCollection: r.newCollection,
+		Warnings:      r.cleanup(ctx),
+	}, nil
+}
+
+// plan checks the rename can be made and decides its steps.
+func (s *WorldService) plan(oldName, newName string) (*worldRename, error) {
+	collection, err := s.registry.Collection(oldName)
+	if err != nil {
+		return nil, entities.Errorf(entities.ErrNotFound, "world %q not found", oldName)
+	}
+	if oldName == newName {
+		return nil, entities.Errorf(entities.ErrValidation, "world is already named %q", newName)
+	}
+	if s.registry.Exists(newName) {
+		return nil, entities.Errorf(entities.ErrConflict, "world %q already exists", newName)
+	}
+
+	moveDir, err := s.storage.CanMoveDir(oldName, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	newCollection := s.registry.CollectionName(newName)
+	if other, ok := s.registry.CollectionOwner(newCollection); ok && other != oldName {
+		return nil, entities.Errorf(entities.ErrConflict, "world %q already uses collection %q; choose a different name", other, newCollection)
+	}
+
+	return &worldRename{
+		registry:      s.registry,
+		storage:       s.storage,
+		oldName:       oldName,
+		newName:       newName,
+		oldCollection: collection,
+		newCollection: newCollection,
+		moveDir:       moveDir,
+	}, nil
+}
+
+// alias reaches the world's collection through an alias named after the
+// new world, unless both names share a collection.
+func (r *worldRename) alias(ctx context.Context) error {
+	if r.newCollection == r.oldCollection {
+		return nil
+	}
+	isAlias, err := r.storage.AliasCollection(ctx, r.oldCollection, r.newCollection)
+	if err != nil {
+		return fmt.Errorf("aliasing qdrant collection: %w", err)
+	}
+	r.oldIsAlias = isAlias
+	r.undo = append(r.undo, func() error {
+		if err := r.storage.DeleteAlias(ctx, r.newCollection); err != nil {
+			return fmt.Errorf("removing alias %q: %w", r.newCollection, err)
+		}
+		return nil
+	})
+	return nil
+}
+
+// move moves the world's directory, if it has one of its own.
+func (r *worldRename) move(context.Context) error {
+	if !r.moveDir {
+		return nil
+	}
+	if err := r.storage.MoveDir(r.oldName, r.newName); err != nil {
+		return fmt.Errorf("moving world directory: %w", err)
+	}
+	r.undo = append(r.undo, func() error {
+		if err := r.storage.MoveDir(r.newName, r.oldName); err != nil {
+			return fmt.Errorf("moving the directory of %q back: %w", r.newName, err)
+		}
+		return nil
+	})
+	return nil
+}
+
+// renameEntities gives the world's entities the new world ID.
+func (r *worldRename) renameEntities(ctx context.Context) error {
+	found, err := r.storage.RenameEntities(ctx, r.newName, r.oldName, r.newName)
+	if err != nil {
+		return fmt.Errorf("updating sqlite database: %w", err)
+	}
+	if !found {
+		return nil
+	}
+	r.undo = append(r.undo, func() error {
+		if _, err := r.storage.RenameEntities(ctx, r.newName, r.newName, r.oldName); err != nil {
+			return fmt.Errorf("restoring entities of %q: %w", r.oldName, err)
+		}
+		return nil
+	})
+	return nil
+}
+
+// register registers the world under its new name. It is the last step,
+// so it has nothing to undo.
+func (r *worldRename) register(context.Context) error {
+	if err := r.registry.Rename(r.oldName, r.newName, r.newCollection); err != nil {
+		return fmt.Errorf("saving worlds: %w", err)
+	}
+	return nil
+}
+
+// rollback undoes the completed steps, latest first, and returns err with
+// any undo step that failed.
+func (r *worldRename) rollback(err error) error {
+	errs := []error{err}
+	for i := len(r.undo) - 1; i >= 0; i-- {
+		if undoErr := r.undo[i](); undoErr != nil {
+			errs = append(errs, undoErr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanup drops what the old name left once the new one is in place: the
+// old alias, since the collection is now reached through the new one, and
+// the old entity index, which is rebuilt under the new name when next
+// searched.
+func (r *worldRename) cleanup(ctx context.Context) []string {
+	if r.newCollection == r.oldCollection {
+		return nil
+	}
+
+	var warnings []string
+	if r.oldIsAlias {
+		if err := r.storage.DeleteAlias(ctx, r.oldCollection); err != nil {
+			warnings = append(warnings, fmt.Sprintf("could not remove old alias %q: %v", r.oldCollection, err))
+		}
+	}
+	if err := r.storage.DeleteEntityIndex(ctx, r.oldCollection); err != nil {
+		warnings = append(warnings, fmt.Sprintf("could not delete old entity index of %q: %v", r.oldCollection, err))
+	}
+	return warnings
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// fakeWorlds is a world registry and storage that records what is done
+// to it.
+type fakeWorlds struct {
+	collections map[string]string // World to collection
+	aliases     map[string]string // Alias to collection
+	dirs        map[string]bool
+	entities    map[string]string // Entity to world ID
+	saveErr     error
+	steps       []string
+}
+
+func newFakeWorlds() *fakeWorlds {
+	return &fakeWorlds{
+		collections: map[string]string{"shire": "lore_shire"},
+		aliases:     map[string]string{"lore_shire": "lore_shire_v2"},
+		dirs:        map[string]bool{"shire": true},
+		entities:    map[string]string{"frodo": "shire"},
+	}
+}
+
+func (f *fakeWorlds) Collection(world string) (string, error) {
+	collection, ok := f.collections[world]
+	if !ok {
+		return "", entities.Errorf(entities.ErrNotFound, "no world %s", world)
+	}
+	return collection, nil
+}
+
+func (f *fakeWorlds) Exists(world string) bool {
+	_, ok := f.collections[world]
+	return ok
+}
+
+func (f *fakeWorlds) CollectionOwner(collection string) (string, bool) {
+	for world, c := range f.collections {
+		if c == collection {
+			return world, true
+		}
+	}
+	return "", false
+}
+
+func (f *fakeWorlds) CollectionName(world string) string {
+	return "lore_" + world
+}
+
+func (f *fakeWorlds) Rename(oldName, newName, collection string) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	delete(f.collections, oldName)
+	f.collections[newName] = collection
+	f.steps = append(f.steps, "register "+newName)
+	return nil
+}
+
+func (f *fakeWorlds) AliasCollection(_ context.Context, collection, alias string) (bool, error) {
+	target, isAlias := f.aliases[collection]
+	if !isAlias {
+		target = collection
+	}
+	f.aliases[alias] = target
+	f.steps = append(f.steps, "alias "+alias)
+	return isAlias, nil
+}
+
+func (f *fakeWorlds) DeleteAlias(_ context.Context, alias string) error {
+	delete(f.aliases, alias)
+	f.steps = append(f.steps, "unalias "+alias)
+	return nil
+}
+
+func (f *fakeWorlds) DeleteEntityIndex(_ context.Context, collection string) error {
+	f.steps = append(f.steps, "drop index "+collection)
+	return nil
+}
+
+func (f *fakeWorlds) CanMoveDir(oldName, newName string) (bool, error) {
+	if f.dirs[newName] {
+		return false, entities.Errorf(entities.ErrConflict, "%s exists", newName)
+	}
+	return f.dirs[oldName], nil
+}
+
+func (f *fakeWorlds) MoveDir(oldName, newName string) error {
+	delete(f.dirs, oldName)
+	f.dirs[newName] = true
+	f.steps = append(f.steps, "move "+newName)
+	return nil
+}
+
+func (f *fakeWorlds) RenameEntities(_ context.Context, _, oldID, newID string) (bool, error) {
+	for id, world := range f.entities {
+		if world == oldID {
+			f.entities[id] = newID
+		}
+	}
+	f.steps = append(f.steps, "entities "+newID)
+	return true, nil
+}
+
+func TestWorldService_Rename(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("moves every part of the world", func(t *testing.T) {
+		worlds := newFakeWorlds()
+		svc := NewWorldService(worlds, worlds)
+
+		result, err := svc.Rename(ctx, "shire", "eriador")
+		require.NoError(t, err)
+		assert.Equal(t, "lore_shire", result.OldCollection)
+		assert.Equal(t, "lore_eriador", result.NewCollection)
+		assert.Empty(t, result.Warnings)
+
+		assert.Equal(t, map[string]string{"eriador": "lore_eriador"}, worlds.collections)
+		assert.Equal(t, map[string]string{"lore_eriador": "lore_shire_v2"}, worlds.aliases, "the old alias is dropped")
+		assert.Equal(t, map[string]bool{"eriador": true}, worlds.dirs)
+		assert.Equal(t, "eriador", worlds.entities["frodo"])
+		assert.Equal(t, []string{
+			"alias lore_eriador", "move eriador", "entities eriador", "register eriador",
+			"unalias lore_shire", "drop index lore_shire",
+		}, worlds.steps)
+	})
+
+	t.Run("a failed step undoes the earlier ones", func(t *testing.T) {
+		worlds := newFakeWorlds()
+		worlds.saveErr = errors.New("disk full")
+		svc := NewWorldService(worlds, worlds)
+
+		_, err := svc.Rename(ctx, "shire", "eriador")
+		require.ErrorContains(t, err, "disk full")
+
+		assert.Equal(t, map[string]string{"shire": "lore_shire"}, worlds.collections)
+		assert.Equal(t, map[string]string{"lore_shire": "lore_shire_v2"}, worlds.aliases)
+		assert.Equal(t, map[string]bool{"shire": true}, worlds.dirs)
+		assert.Equal(t, "shire", worlds.entities["frodo"])
+		assert.Equal(t, []string{
+			"alias lore_eriador", "move eriador", "entities eriador",
+			"entities shire", "move shire", "unalias lore_eriador",
+		}, worlds.steps)
+	})
+
+	t.Run("invalid renames change nothing", func(t *testing.T) {
+		worlds := newFakeWorlds()
+		worlds.collections["rohan"] = "lore_rohan"
+		worlds.collections["gondor"] = "lore_mordor"
+		worlds.dirs["mirkwood"] = true
+		svc := NewWorldService(worlds, worlds)
+
+		tests := []struct {
+			oldName, newName string
+			want             error
+		}{
+			{"moria", "khazad-dum", entities.ErrNotFound},
+			{"shire", "shire", entities.ErrValidation},
+			{"shire", "rohan", entities.ErrConflict},
+			{"shire", "mirkwood", entities.ErrConflict},
+			{"shire", "mordor", entities.ErrConflict},
+		}
+		for _, tt := range tests {
+			_, err := svc.Rename(ctx, tt.oldName, tt.newName)
+			require.ErrorIs(t, err, tt.want, "%s to %s", tt.oldName, tt.newName)
+		}
+		assert.Empty(t, worlds.steps)
+	})
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// AuthFile is the name of the file holding API tokens for 'lore serve'.
+const AuthFile = "auth.yaml"
+
+// tokenPrefix starts every generated token, so leaked tokens are easy to
+// recognize.
+const tokenPrefix = "lore_"
+
+// Scopes a token may have in a world.
+const (
+	// ScopeRead allows status, queries, and listing snapshots.
+	ScopeRead = "read"
+	// ScopeWrite also allows requests that change the world.
+	ScopeWrite = "write"
+)
+
+// AuthConfig holds the API tokens 'lore serve' accepts. With no tokens,
+// the API is open to anyone who can reach it.
+type AuthConfig struct {
+	Tokens []TokenEntry `yaml:"tokens,omitempty"`
+}
+
+// TokenEntry is one API token. Only a hash of the token is stored.
+type TokenEntry struct {
+	Name string `yaml:"name"`
+	// SHA256 is the hex-encoded SHA-256 hash of the token.
+	SHA256 string `yaml:"sha256"`
+	// Worlds maps each world the token may use to its scope.
+	Worlds    map[string]string `yaml:"worlds"`
+	CreatedAt time.Time         `yaml:"created_at,omitempty"`
+}
+
+// AuthFilePath returns the path to the auth file in a config directory.
+func AuthFilePath(configDir string) string {
+	return filepath.Join(configDir, AuthFile)
+}
+
+// LoadAuth loads API tokens from the config directory. A missing file
+// means no tokens.
+func LoadAuth(configDir string) (*AuthConfig, error) {
+	path := AuthFilePath(configDir)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &AuthConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading auth file: %w", err)
+	}
+
+	var cfg AuthConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing auth file: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Save writes the tokens to the auth file, readable only by its owner.
+func (a *AuthConfig) Save(configDir string) error {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("marshaling auth config: %w", err)
+	}
+
+	if err := os.WriteFile(AuthFilePath(configDir), data, 0600); err != nil {
+		return fmt.Errorf("writing auth file: %w", err)
+	}
+	return nil
+}
+
+// Validate checks token names are unique and every hash and scope is
+// well formed.
+func (a *AuthConfig) Validate() error {
+	seen := make(map[string]bool)
+	for _, t := range a.Tokens {
+		if t.Name == "" {
+			return entities.Errorf(entities.ErrValidation, "token name must not be empty")
+		}
+		if seen[t.Name] {
+			return entities.Errorf(entities.ErrValidation, "token %q is listed twice", t.Name)
+		}
+		seen[t.Name] = true
+
+		if b, err := hex.DecodeString(t.SHA256); err != nil || len(b) != sha256.Size {
+			return entities.Errorf(entities.ErrValidation, "token %q: sha256 must be a hex-encoded SHA-256 hash", t.Name)
+		}
+		if len(t.Worlds) == 0 {
+			return entities.Errorf(entities.ErrValidation, "token %q: must list at least one world", t.Name)
+		}
+		for world, scope := range t.Worlds {
+			if err := ValidateScope(scope); err != nil {
+				return entities.WithKind(entities.ErrValidation, fmt.Errorf("token %q, world %s: %w", t.Name, world, err))
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateScope checks scope is read or write.
+func ValidateScope(scope string) error {
+	if scope != ScopeRead && scope != ScopeWrite {
+		return fmt.Errorf("scope must be %s or %s, got %q", ScopeRead, ScopeWrite, scope)
+	}
+	return nil
+}
+
+// Enabled reports whether any tokens are configured, so requests must
+// present one.
+func (a *AuthConfig) Enabled() bool {
+	return len(a.Tokens) > 0
+}
+
+// Find returns the token with the given name, if any.
+func (a *AuthConfig) Find(name string) (*TokenEntry, bool) {
+	i := slices.IndexFunc(a.Tokens, func(t TokenEntry) bool { return t.Name == name })
+	if i < 0 {
+		return nil, false
+	}
+	return &a.Tokens[i], true
+}
+
+// Add generates a token named name with scope in world, records its hash,
+// and returns the token. The token cannot be recovered later.
+func (a *AuthConfig) Add(name, world, scope string) (string, error) {
+	if err := ValidateScope(scope); err != nil {
+		return "", entities.WithKind(entities.ErrValidation, err)
+	}
+	if _, ok := a.Find(name); ok {
+		return "", entities.Errorf(entities.ErrConflict, "token %q already exists (revoke it first)", name)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	token := tokenPrefix + hex.EncodeToString(secret)
+
+	a.Tokens = append(a.Tokens, TokenEntry{
+		Name:      name,
+		SHA256:    HashToken(token),
+		Worlds:    map[string]string{world: scope},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	})
+	return token, nil
+}
+
+// Revoke removes the named token, reporting whether it existed.
+func (a *AuthConfig) Revoke(name string) bool {
+	n := len(a.Tokens)
+	a.Tokens = slices.DeleteFunc(a.Tokens, func(t TokenEntry) bool { return t.Name == name })
+	return len(a.Tokens) < n
+}
+
+// Authorize returns the name and scope of the token in world. ok is false
+// if the token is unknown or has no scope in world.
+func (a *AuthConfig) Authorize(token, world string) (name, scope string, ok bool) {
+	sum := sha256.Sum256([]byte(token))
+	for _, t := range a.Tokens {
+		want, err := hex.DecodeString(t.SHA256)
+		if err != nil || subtle.ConstantTimeCompare(sum[:], want) != 1 {
+			continue
+		}
+		scope, ok := t.Worlds[world]
+		return t.Name, scope, ok
+	}
+	return "", "", false
+}
+
+// HashToken returns the hex-encoded SHA-256 hash stored for token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
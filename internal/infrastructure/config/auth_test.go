@@ -0,0 +1,110 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestAuthConfig_AddAndAuthorize(t *testing.T) {
+	auth := &AuthConfig{}
+	assert.False(t, auth.Enabled())
+
+	reader, err := auth.Add("co-writer", "shire", ScopeRead)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reader, "lore_"))
+	writer, err := auth.Add("editor", "shire", ScopeWrite)
+	require.NoError(t, err)
+	assert.NotEqual(t, reader, writer)
+	assert.True(t, auth.Enabled())
+
+	name, scope, ok := auth.Authorize(reader, "shire")
+	assert.True(t, ok)
+	assert.Equal(t, "co-writer", name)
+	assert.Equal(t, ScopeRead, scope)
+
+	_, scope, ok = auth.Authorize(writer, "shire")
+	assert.True(t, ok)
+	assert.Equal(t, ScopeWrite, scope)
+
+	name, _, ok = auth.Authorize(reader, "gondor")
+	assert.False(t, ok)
+	assert.Equal(t, "co-writer", name, "a known token without access to the world is named")
+
+	name, _, ok = auth.Authorize("lore_guess", "shire")
+	assert.False(t, ok)
+	assert.Empty(t, name)
+
+	_, err = auth.Add("co-writer", "shire", ScopeRead)
+	assert.ErrorIs(t, err, entities.ErrConflict)
+	_, err = auth.Add("admin", "shire", "admin")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+
+	assert.True(t, auth.Revoke("co-writer"))
+	assert.False(t, auth.Revoke("co-writer"))
+	_, _, ok = auth.Authorize(reader, "shire")
+	assert.False(t, ok, "revoked tokens are rejected")
+}
+
+func TestAuthConfig_SaveAndLoad(t *testing.T) {
+	configDir := t.TempDir()
+
+	empty, err := LoadAuth(configDir)
+	require.NoError(t, err)
+	assert.False(t, empty.Enabled(), "a missing file means no tokens")
+
+	auth := &AuthConfig{}
+	token, err := auth.Add("co-writer", "shire", ScopeRead)
+	require.NoError(t, err)
+	require.NoError(t, auth.Save(configDir))
+
+	info, err := os.Stat(AuthFilePath(configDir))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	data, err := os.ReadFile(AuthFilePath(configDir))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), token, "only the hash is stored")
+
+	loaded, err := LoadAuth(configDir)
+	require.NoError(t, err)
+	_, scope, ok := loaded.Authorize(token, "shire")
+	assert.True(t, ok)
+	assert.Equal(t, ScopeRead, scope)
+}
+
+func TestAuthConfig_Validate(t *testing.T) {
+	hash := HashToken("lore_secret")
+	tests := []struct {
+		name    string
+		tokens  []TokenEntry
+		wantErr string
+	}{
+		{name: "valid", tokens: []TokenEntry{{Name: "a", SHA256: hash, Worlds: map[string]string{"shire": "read"}}}},
+		{name: "no name", tokens: []TokenEntry{{SHA256: hash, Worlds: map[string]string{"shire": "read"}}}, wantErr: "name must not be empty"},
+		{name: "duplicate", tokens: []TokenEntry{
+			{Name: "a", SHA256: hash, Worlds: map[string]string{"shire": "read"}},
+			{Name: "a", SHA256: hash, Worlds: map[string]string{"shire": "write"}},
+		}, wantErr: "listed twice"},
+		{name: "plaintext token", tokens: []TokenEntry{{Name: "a", SHA256: "lore_secret", Worlds: map[string]string{"shire": "read"}}}, wantErr: "hex-encoded SHA-256"},
+		{name: "no worlds", tokens: []TokenEntry{{Name: "a", SHA256: hash}}, wantErr: "at least one world"},
+		{name: "bad scope", tokens: []TokenEntry{{Name: "a", SHA256: hash, Worlds: map[string]string{"shire": "admin"}}}, wantErr: `scope must be read or write, got "admin"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&AuthConfig{Tokens: tt.tokens}).Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorIs(t, err, entities.ErrValidation)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
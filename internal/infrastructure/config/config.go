@@ -2,13 +2,21 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 const (
@@ -18,8 +26,13 @@ const (
 	DefaultConfigFile = "config.yaml"
 	// DefaultWorldsFile is the default worlds file name.
 	DefaultWorldsFile = "worlds.yaml"
+	// HomeEnv names the environment variable that points at the config directory.
+	HomeEnv = "LORE_HOME"
 )
 
+// ErrConfigDirNotFound is returned when no config directory can be located.
+var ErrConfigDirNotFound = entities.Errorf(entities.ErrNotFound, "no lore config directory found")
+
 var (
 	// reNonAlphanumeric matches characters that aren't alphanumeric or underscore.
 	reNonAlphanumeric = regexp.MustCompile(`[^a-z0-9_]`)
@@ -33,6 +46,31 @@ type Config struct {
 	Embedder EmbedderConfig `yaml:"embedder,omitempty"`
 	Qdrant   QdrantConfig   `yaml:"qdrant,omitempty"`
 	SQLite   SQLiteConfig   `yaml:"sqlite,omitempty"`
+	Serve    ServeConfig    `yaml:"serve,omitempty"`
+	Review   ReviewConfig   `yaml:"review,omitempty"`
+	Ingest   IngestConfig   `yaml:"ingest,omitempty"`
+	Claims   ClaimsConfig   `yaml:"claims,omitempty"`
+	Notify   NotifyConfig   `yaml:"notify,omitempty"`
+	Export   ExportConfig   `yaml:"export,omitempty"`
+	Graph    GraphConfig    `yaml:"graph,omitempty"`
+
+	// Consistency configures how consistency checks find the stored facts
+	// to compare against.
+	Consistency ConsistencyConfig `yaml:"consistency,omitempty"`
+
+	// Recording records or replays OpenAI calls, for tests.
+	Recording RecordingConfig `yaml:"recording,omitempty"`
+
+	// Quota sets soft limits on each world's API spending and size.
+	Quota QuotaConfig `yaml:"quota,omitempty"`
+
+	// Profiles are named overrides of the LLM, embedder, and Qdrant
+	// settings, selected with --profile, $LORE_PROFILE, or DefaultProfile.
+	Profiles       map[string]ProfileConfig `yaml:"profiles,omitempty"`
+	DefaultProfile string                   `yaml:"default_profile,omitempty"`
+
+	// Profile is the name of the profile applied by Load, if any.
+	Profile string `yaml:"-"`
 }
 
 // LLMConfig holds configuration for the LLM provider.
@@ -40,6 +78,13 @@ type LLMConfig struct {
 	Provider string `yaml:"provider,omitempty"`
 	Model    string `yaml:"model,omitempty"`
 	APIKey   string `yaml:"api_key,omitempty"`
+	// Language is the language the source material is written in, as a
+	// name ("German") or ISO 639-1 code ("de"). Empty means English.
+	Language string `yaml:"language,omitempty"`
+	// ConsistencyCacheTTL is how long the verdict for a pair of facts
+	// checked for consistency is reused. Zero disables the cache.
+	ConsistencyCacheTTL time.Duration `yaml:"consistency_cache_ttl,omitempty"`
+	TimeoutConfig       `yaml:",inline"`
 }
 
 // EmbedderConfig holds configuration for the embedding provider.
@@ -47,6 +92,67 @@ type EmbedderConfig struct {
 	Provider string `yaml:"provider,omitempty"`
 	Model    string `yaml:"model,omitempty"`
 	APIKey   string `yaml:"api_key,omitempty"`
+	// Template is the text/template facts are embedded as, over the
+	// fact's fields, such as "{{title .Type}} fact: {{.Subject}}
+	// {{.Predicate}} {{.Object}}". Empty means the subject, predicate,
+	// object, and context. Changing it calls for 'lore migrate reindex
+	// --re-embed'.
+	Template      string `yaml:"template,omitempty"`
+	TimeoutConfig `yaml:",inline"`
+}
+
+// TimeoutConfig bounds how long calls to a backend may take.
+type TimeoutConfig struct {
+	// Timeout cancels a single call that runs longer. Zero disables it.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// SlowThreshold records calls at least this slow in the audit log as
+	// slow queries. Zero disables the log.
+	SlowThreshold time.Duration `yaml:"slow_threshold,omitempty"`
+}
+
+// Validate checks the durations are not negative.
+func (c TimeoutConfig) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative, got %s", c.Timeout)
+	}
+	if c.SlowThreshold < 0 {
+		return fmt.Errorf("slow_threshold must not be negative, got %s", c.SlowThreshold)
+	}
+	return nil
+}
+
+// QuotaConfig sets soft limits on each world. Commands warn when a world
+// exceeds one, or refuse to go on if Strict (--strict).
+type QuotaConfig struct {
+	// MonthlyBudget is the most to spend on API calls per calendar month,
+	// in USD, estimated from Prices. Zero means no limit.
+	MonthlyBudget float64 `yaml:"monthly_budget,omitempty"`
+	// MaxFacts is the most facts a world should hold. Zero means no limit.
+	MaxFacts int  `yaml:"max_facts,omitempty"`
+	Strict   bool `yaml:"strict,omitempty"`
+
+	Prices PricesConfig `yaml:"prices,omitempty"`
+}
+
+// PricesConfig holds what the APIs charge, in USD per million tokens.
+type PricesConfig struct {
+	LLMInput  float64 `yaml:"llm_input,omitempty"`
+	LLMOutput float64 `yaml:"llm_output,omitempty"`
+	Embedding float64 `yaml:"embedding,omitempty"`
+}
+
+// Validate checks no limit or price is negative.
+func (c QuotaConfig) Validate() error {
+	if c.MonthlyBudget < 0 {
+		return fmt.Errorf("monthly_budget must not be negative, got %g", c.MonthlyBudget)
+	}
+	if c.MaxFacts < 0 {
+		return fmt.Errorf("max_facts must not be negative, got %d", c.MaxFacts)
+	}
+	if c.Prices.LLMInput < 0 || c.Prices.LLMOutput < 0 || c.Prices.Embedding < 0 {
+		return errors.New("prices must not be negative")
+	}
+	return nil
 }
 
 // QdrantConfig holds configuration for the Qdrant vector database.
@@ -55,6 +161,85 @@ type QdrantConfig struct {
 	Port       int    `yaml:"port,omitempty"`
 	Collection string `yaml:"collection,omitempty"`
 	APIKey     string `yaml:"api_key,omitempty"`
+
+	TimeoutConfig `yaml:",inline"`
+
+	// Storage tuning. Applied when a collection is created; run
+	// 'lore migrate reindex' to apply changes to an existing world.
+	OnDiskVectors bool               `yaml:"on_disk_vectors,omitempty"`
+	OnDiskPayload bool               `yaml:"on_disk_payload,omitempty"`
+	HNSW          HNSWConfig         `yaml:"hnsw,omitempty"`
+	Quantization  QuantizationConfig `yaml:"quantization,omitempty"`
+}
+
+// HNSWConfig tunes the HNSW vector index. Zero values use Qdrant defaults.
+type HNSWConfig struct {
+	// M is the number of edges per node. Higher improves recall and uses more memory.
+	M uint64 `yaml:"m,omitempty"`
+	// EfConstruct is the candidate list size while building. Higher improves recall and slows indexing.
+	EfConstruct uint64 `yaml:"ef_construct,omitempty"`
+}
+
+// Quantization types supported by QuantizationConfig.
+const (
+	QuantizationScalar  = "scalar"
+	QuantizationProduct = "product"
+)
+
+// validCompressionRatios lists the product quantization compression ratios.
+var validCompressionRatios = []string{"x4", "x8", "x16", "x32", "x64"}
+
+// QuantizationConfig compresses stored vectors to reduce memory use.
+// An empty Type disables quantization.
+type QuantizationConfig struct {
+	Type string `yaml:"type,omitempty"` // scalar or product
+	// Quantile excludes outliers when computing scalar bounds (0.5-1.0, scalar only).
+	Quantile float32 `yaml:"quantile,omitempty"`
+	// Compression is the product quantization ratio: x4, x8, x16, x32, or x64 (default x16).
+	Compression string `yaml:"compression,omitempty"`
+	// AlwaysRAM keeps quantized vectors in memory even when originals are on disk.
+	AlwaysRAM bool `yaml:"always_ram,omitempty"`
+}
+
+// Validate checks that the quantization settings are consistent.
+func (q QuantizationConfig) Validate() error {
+	switch q.Type {
+	case "":
+		if q.Quantile != 0 || q.Compression != "" || q.AlwaysRAM {
+			return errors.New("quantization options set without a quantization type")
+		}
+	case QuantizationScalar:
+		if q.Compression != "" {
+			return errors.New("compression only applies to product quantization")
+		}
+		if q.Quantile != 0 && (q.Quantile < 0.5 || q.Quantile > 1) {
+			return fmt.Errorf("quantile must be between 0.5 and 1.0, got %v", q.Quantile)
+		}
+	case QuantizationProduct:
+		if q.Quantile != 0 {
+			return errors.New("quantile only applies to scalar quantization")
+		}
+		if q.Compression != "" && !slices.Contains(validCompressionRatios, q.Compression) {
+			return fmt.Errorf("invalid compression %q (valid: %s)", q.Compression, strings.Join(validCompressionRatios, ", "))
+		}
+	default:
+		return fmt.Errorf("invalid quantization type %q (valid: %s, %s)", q.Type, QuantizationScalar, QuantizationProduct)
+	}
+	return nil
+}
+
+// Validate checks the storage tuning options.
+func (c *QdrantConfig) Validate() error {
+	if err := c.Quantization.Validate(); err != nil {
+		return fmt.Errorf("quantization: %w", err)
+	}
+	if c.HNSW.M != 0 && c.HNSW.M < 4 {
+		return fmt.Errorf("hnsw.m must be at least 4, got %d", c.HNSW.M)
+	}
+	if c.HNSW.EfConstruct != 0 && c.HNSW.EfConstruct < 4 {
+		return fmt.Errorf("hnsw.ef_construct must be at least 4, got %d", c.HNSW.EfConstruct)
+	}
+	return nil
 }
 
 // SQLiteConfig holds configuration for the SQLite relational database.
@@ -62,6 +247,427 @@ type SQLiteConfig struct {
 	// Path is the file path to the SQLite database.
 	// For per-world databases, this is computed dynamically using SQLitePathForWorld.
 	Path string `yaml:"path,omitempty"`
+	// BusyTimeout is how long to wait for a lock held by another process
+	// before failing with "database is locked". Zero uses the default.
+	BusyTimeout time.Duration `yaml:"busy_timeout,omitempty"`
+}
+
+// DefaultBusyTimeout is the SQLite busy timeout used when none is configured.
+const DefaultBusyTimeout = 5 * time.Second
+
+// ServeConfig holds configuration for 'lore serve'.
+type ServeConfig struct {
+	// Addr is the HTTP listen address.
+	Addr      string          `yaml:"addr,omitempty"`
+	Snapshots SnapshotsConfig `yaml:"snapshots,omitempty"`
+	Health    HealthConfig    `yaml:"health,omitempty"`
+	Sweep     SweepConfig     `yaml:"sweep,omitempty"`
+	Changes   ChangesConfig   `yaml:"changes,omitempty"`
+	ReadCache ReadCacheConfig `yaml:"read_cache,omitempty"`
+	Refresh   RefreshConfig   `yaml:"refresh,omitempty"`
+}
+
+// RefreshConfig schedules re-embedding stale entity summaries in serve
+// mode, as 'lore refresh' would, for worlds searched with
+// 'lore entities search --semantic'.
+type RefreshConfig struct {
+	// Schedule is a cron expression or one of @hourly, @daily, @weekly,
+	// @monthly. Empty disables scheduled refreshes.
+	Schedule string `yaml:"schedule,omitempty"`
+	// MaxEntities is the most entities embedded per refresh (0 = all);
+	// the rest wait for the next one.
+	MaxEntities int `yaml:"max_entities,omitempty"`
+}
+
+// Validate checks the refresh budget. The schedule is parsed when the
+// server starts.
+func (c RefreshConfig) Validate() error {
+	if c.MaxEntities < 0 {
+		return fmt.Errorf("refresh.max_entities must not be negative, got %d", c.MaxEntities)
+	}
+	return nil
+}
+
+// ReadCacheConfig configures 'lore serve --read-cache', which answers
+// reads from an in-memory copy of the world's facts.
+type ReadCacheConfig struct {
+	// Schedule is a cron expression or one of @hourly, @daily, @weekly,
+	// @monthly on which the copy is refreshed.
+	Schedule string `yaml:"schedule,omitempty"`
+	// MaxStaleness is how old the copy may get, if refreshing it fails,
+	// before the server stops answering with 503 (0 = no limit).
+	MaxStaleness time.Duration `yaml:"max_staleness,omitempty"`
+}
+
+// Validate checks the read cache is refreshed and its staleness bound.
+func (c ReadCacheConfig) Validate() error {
+	if strings.TrimSpace(c.Schedule) == "" {
+		return fmt.Errorf("read_cache.schedule must not be empty")
+	}
+	if c.MaxStaleness < 0 {
+		return fmt.Errorf("read_cache.max_staleness must not be negative, got %s", c.MaxStaleness)
+	}
+	return nil
+}
+
+// ChangesConfig schedules appending the world's change feed to a JSONL
+// file in serve mode, for other systems to sync from.
+type ChangesConfig struct {
+	// Schedule is a cron expression or one of @hourly, @daily, @weekly,
+	// @monthly. Empty disables scheduled change exports.
+	Schedule string `yaml:"schedule,omitempty"`
+	// Path is the file changes are appended to. The cursor to resume
+	// from is kept beside it, in Path plus ".cursor".
+	Path string `yaml:"path,omitempty"`
+}
+
+// Validate checks a scheduled change export has somewhere to write. The
+// schedule is parsed when the server starts.
+func (c ChangesConfig) Validate() error {
+	if c.Schedule != "" && strings.TrimSpace(c.Path) == "" {
+		return fmt.Errorf("changes.path is required with changes.schedule")
+	}
+	return nil
+}
+
+// SweepConfig schedules consistency sweeps of recently added facts in
+// serve mode, whose new conflicts are sent as a digest to notify.
+type SweepConfig struct {
+	// Schedule is a cron expression or one of @hourly, @daily, @weekly,
+	// @monthly. Empty disables scheduled sweeps.
+	Schedule string `yaml:"schedule,omitempty"`
+}
+
+// HealthConfig schedules world health measurements in serve mode.
+type HealthConfig struct {
+	// Schedule is a cron expression or one of @hourly, @daily, @weekly,
+	// @monthly. Empty disables scheduled measurements.
+	Schedule string `yaml:"schedule,omitempty"`
+}
+
+// SnapshotsConfig schedules automatic world snapshots in serve mode.
+type SnapshotsConfig struct {
+	// Schedule is a cron expression (minute hour day-of-month month
+	// day-of-week) or one of @hourly, @daily, @weekly, @monthly.
+	// Empty disables scheduled snapshots.
+	Schedule string `yaml:"schedule,omitempty"`
+	// Keep is how many snapshots to retain; older ones are pruned.
+	Keep int `yaml:"keep,omitempty"`
+}
+
+// Validate checks the snapshot retention settings. The schedule is
+// parsed when the server starts.
+func (c SnapshotsConfig) Validate() error {
+	if c.Schedule != "" && c.Keep < 1 {
+		return fmt.Errorf("snapshots.keep must be at least 1, got %d", c.Keep)
+	}
+	return nil
+}
+
+// NotifyConfig holds where notifications, such as consistency digests,
+// are sent. Both a webhook and email may be configured.
+type NotifyConfig struct {
+	// Webhook is a URL that is POSTed a JSON body with "subject" and
+	// "text", as chat incoming webhooks accept.
+	Webhook string      `yaml:"webhook,omitempty"`
+	Email   EmailConfig `yaml:"email,omitempty"`
+	// Timeout cancels a single notification that runs longer. Zero
+	// disables it.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// EmailConfig holds the SMTP server notifications are emailed through. No
+// recipients disables email.
+type EmailConfig struct {
+	// SMTP is the server's host:port, e.g. smtp.example.com:587.
+	SMTP     string   `yaml:"smtp,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+}
+
+// Enabled reports whether email recipients are configured.
+func (c EmailConfig) Enabled() bool {
+	return len(c.To) > 0
+}
+
+// Validate checks the webhook is an HTTP URL and configured email has a
+// server and sender.
+func (c NotifyConfig) Validate() error {
+	if c.Webhook != "" {
+		u, err := url.Parse(c.Webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("notify.webhook must be an http or https URL, got %q", c.Webhook)
+		}
+	}
+	if c.Email.Enabled() {
+		if err := validateAddr(c.Email.SMTP); err != nil {
+			return fmt.Errorf("notify.email.smtp %w", err)
+		}
+		if c.Email.From == "" {
+			return fmt.Errorf("notify.email.from must not be empty")
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("notify.timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// ClaimsConfig holds configuration for statements characters make.
+type ClaimsConfig struct {
+	// Reliable lists the characters whose dialogue is canon. What other
+	// characters say is stored as their claims.
+	Reliable []string `yaml:"reliable,omitempty"`
+}
+
+// ReviewConfig holds configuration for the fact review queue.
+type ReviewConfig struct {
+	// Threshold is the confidence below which ingested facts are held
+	// for review instead of becoming searchable. Zero disables review.
+	Threshold float64 `yaml:"threshold,omitempty"`
+}
+
+// Validate checks the review threshold is a confidence value.
+func (c ReviewConfig) Validate() error {
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("review.threshold must be between 0 and 1, got %v", c.Threshold)
+	}
+	return nil
+}
+
+// IngestConfig bounds what ingestion reads. Sizes take units, such as
+// "64MB"; zero means the default.
+type IngestConfig struct {
+	// MaxFileSize is the largest source file ingested. Default 64MB.
+	MaxFileSize entities.ByteSize `yaml:"max_file_size,omitempty"`
+	// MaxChunkSize is the longest line, paragraph, or section read into
+	// one chunk before ingestion fails. Default 1MB.
+	MaxChunkSize entities.ByteSize `yaml:"max_chunk_size,omitempty"`
+}
+
+// Validate checks the sizes are not negative.
+func (c IngestConfig) Validate() error {
+	if c.MaxFileSize < 0 {
+		return fmt.Errorf("ingest.max_file_size must not be negative, got %d", c.MaxFileSize)
+	}
+	if c.MaxChunkSize < 0 {
+		return fmt.Errorf("ingest.max_chunk_size must not be negative, got %d", c.MaxChunkSize)
+	}
+	return nil
+}
+
+// RetrievalStrategies lists the ways consistency checks can find the stored
+// facts a fact is checked against.
+var RetrievalStrategies = []string{"type", "subject", "global", "graph"}
+
+// ConsistencyConfig holds configuration for consistency checks.
+type ConsistencyConfig struct {
+	// Retrieval is how the stored facts each fact is checked against are
+	// found: the most similar facts of the same type, the most similar
+	// facts with the same subject, the most similar facts of any type, or
+	// facts about the subject and the entities related to it. Empty means
+	// "type".
+	Retrieval string `yaml:"retrieval,omitempty"`
+	// Limit is how many stored facts each fact is checked against. Zero
+	// means 5.
+	Limit int `yaml:"limit,omitempty"`
+	// BatchSize is how many facts one LLM call checks; more are split
+	// among several calls. Zero means 20.
+	BatchSize int `yaml:"batch_size,omitempty"`
+	// Concurrency is how many of those calls are made at once. Zero
+	// means 4.
+	Concurrency int `yaml:"concurrency,omitempty"`
+
+	// SingleValued are predicates a subject has one object for, such as
+	// eye_color. Facts giving a subject two are reported without the LLM.
+	SingleValued []string `yaml:"single_valued,omitempty"`
+	// Exclusive are pairs of predicates a subject can't have both of, such
+	// as [is_alive, died_in], reported without the LLM.
+	Exclusive [][]string `yaml:"exclusive,omitempty"`
+	// KeepUnrelated sends facts sharing no entity with the fact checked to
+	// the LLM too, which otherwise only sees related pairs.
+	KeepUnrelated bool `yaml:"keep_unrelated,omitempty"`
+}
+
+// Validate checks the retrieval strategy is known, the limit, batch size,
+// and concurrency are not negative, and the declared predicates are named
+// and paired.
+func (c ConsistencyConfig) Validate() error {
+	if c.Retrieval != "" && !slices.Contains(RetrievalStrategies, c.Retrieval) {
+		return fmt.Errorf("consistency.retrieval must be one of %s, got %q", strings.Join(RetrievalStrategies, ", "), c.Retrieval)
+	}
+	if c.Limit < 0 {
+		return fmt.Errorf("consistency.limit must not be negative, got %d", c.Limit)
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("consistency.batch_size must not be negative, got %d", c.BatchSize)
+	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("consistency.concurrency must not be negative, got %d", c.Concurrency)
+	}
+	if slices.ContainsFunc(c.SingleValued, func(p string) bool { return strings.TrimSpace(p) == "" }) {
+		return fmt.Errorf("consistency.single_valued must not contain empty predicates")
+	}
+	for i, pair := range c.Exclusive {
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" || strings.TrimSpace(pair[1]) == "" {
+			return fmt.Errorf("consistency.exclusive[%d] must be a pair of predicates, got %v", i, pair)
+		}
+		if strings.EqualFold(strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])) {
+			return fmt.Errorf("consistency.exclusive[%d] pairs %q with itself", i, pair[0])
+		}
+	}
+	return nil
+}
+
+// ExportConfig holds configuration for exported facts.
+type ExportConfig struct {
+	// Namespace is the IRI that entity, predicate, and fact IRIs in RDF
+	// exports start with. Empty means urn:lore:<world>:.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Profiles are named selections of the facts to export, applied with
+	// 'lore export --for <name>' to share a world without spoilers.
+	Profiles map[string]ExportProfileConfig `yaml:"profiles,omitempty"`
+}
+
+// ExportProfileConfig selects the facts a reader may see.
+type ExportProfileConfig struct {
+	// Sources are the source files in reading order, as patterns such as
+	// "book1/*". Facts from other sources are left out. Empty means all.
+	Sources []string `yaml:"sources,omitempty"`
+	// Through is the last source revealed, a source file or one of the
+	// Sources patterns. Empty means every source in Sources.
+	Through string `yaml:"through,omitempty"`
+	// Types are the fact types revealed. Empty means all.
+	Types []string `yaml:"types,omitempty"`
+}
+
+// Validate checks the namespace is an absolute IRI that names can be
+// appended to, and each profile's source patterns are well formed.
+func (c ExportConfig) Validate() error {
+	if err := ValidateNamespace(c.Namespace); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Profiles)) {
+		profile := c.Profiles[name]
+		for _, pattern := range profile.Sources {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("export.profiles.%s: invalid source pattern %q", name, pattern)
+			}
+		}
+		if profile.Through != "" && len(profile.Sources) == 0 {
+			return fmt.Errorf("export.profiles.%s: through needs sources listed in reading order", name)
+		}
+	}
+	return nil
+}
+
+// ValidateNamespace checks an RDF namespace is an absolute IRI ending in
+// "/", "#", or ":", so that names appended to it form IRIs. The empty
+// namespace is valid and means the default.
+func ValidateNamespace(namespace string) error {
+	if namespace == "" {
+		return nil
+	}
+	u, err := url.Parse(namespace)
+	if err != nil || u.Scheme == "" {
+		return fmt.Errorf("namespace must be an absolute IRI such as https://example.org/lore/, got %q", namespace)
+	}
+	if !strings.ContainsAny(namespace[len(namespace)-1:], "/#:") {
+		return fmt.Errorf("namespace must end in /, #, or :, got %q", namespace)
+	}
+	return nil
+}
+
+// ProviderNeo4j is the graph database provider backed by Neo4j.
+const ProviderNeo4j = "neo4j"
+
+// GraphConfig holds configuration for the optional graph database that
+// mirrors entities and relationships. An empty provider disables it.
+type GraphConfig struct {
+	Provider string `yaml:"provider,omitempty"`
+	// URL is the HTTP endpoint of the server, e.g. http://localhost:7474.
+	URL      string `yaml:"url,omitempty"`
+	Database string `yaml:"database,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	// Timeout cancels a single request that runs longer. Zero disables it.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// Enabled reports whether a graph database is configured.
+func (c GraphConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// Validate checks a configured graph database has a supported provider and
+// an HTTP URL.
+func (c GraphConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Provider != ProviderNeo4j {
+		return fmt.Errorf("graph.provider must be %s, got %q", ProviderNeo4j, c.Provider)
+	}
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("graph.url must be an http or https URL such as http://localhost:7474, got %q", c.URL)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("graph.timeout must not be negative, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// Recording modes.
+const (
+	// RecordingModeRecord calls OpenAI and saves each exchange.
+	RecordingModeRecord = "record"
+	// RecordingModeReplay answers from saved exchanges and never calls
+	// OpenAI, so no API key is needed.
+	RecordingModeReplay = "replay"
+)
+
+// RecordingConfig records OpenAI calls to fixture files, or replays them,
+// so extraction and consistency checks can be tested deterministically.
+// An empty mode disables it.
+type RecordingConfig struct {
+	Mode string `yaml:"mode,omitempty"`
+	// Dir holds the fixtures. A relative path is relative to the config
+	// directory. Empty means "recordings".
+	Dir string `yaml:"dir,omitempty"`
+}
+
+// Enabled reports whether calls are recorded or replayed.
+func (c RecordingConfig) Enabled() bool {
+	return c.Mode != ""
+}
+
+// Replaying reports whether calls are answered from fixtures.
+func (c RecordingConfig) Replaying() bool {
+	return c.Mode == RecordingModeReplay
+}
+
+// Validate checks the mode is supported.
+func (c RecordingConfig) Validate() error {
+	switch c.Mode {
+	case "", RecordingModeRecord, RecordingModeReplay:
+		return nil
+	}
+	return fmt.Errorf("mode must be %s or %s, got %q", RecordingModeRecord, RecordingModeReplay, c.Mode)
+}
+
+// RecordingDir returns the directory holding recorded fixtures.
+func RecordingDir(configDir string, c RecordingConfig) string {
+	switch {
+	case c.Dir == "":
+		return filepath.Join(configDir, "recordings")
+	case filepath.IsAbs(c.Dir):
+		return c.Dir
+	}
+	return filepath.Join(configDir, c.Dir)
 }
 
 // Default returns a Config with default values.
@@ -70,25 +676,75 @@ func Default() *Config {
 		LLM: LLMConfig{
 			Provider: "openai",
 			Model:    "gpt-4o-mini",
+			// 30 days
+			ConsistencyCacheTTL: 720 * time.Hour,
+			TimeoutConfig: TimeoutConfig{
+				Timeout:       2 * time.Minute,
+				SlowThreshold: 30 * time.Second,
+			},
 		},
 		Embedder: EmbedderConfig{
 			Provider: "openai",
 			Model:    "text-embedding-3-small",
+			TimeoutConfig: TimeoutConfig{
+				Timeout:       30 * time.Second,
+				SlowThreshold: 5 * time.Second,
+			},
+		},
+		Quota: QuotaConfig{
+			// gpt-4o-mini and text-embedding-3-small
+			Prices: PricesConfig{LLMInput: 0.15, LLMOutput: 0.60, Embedding: 0.02},
 		},
 		Qdrant: QdrantConfig{
 			Host: "localhost",
 			Port: 6334,
+			TimeoutConfig: TimeoutConfig{
+				Timeout:       10 * time.Second,
+				SlowThreshold: time.Second,
+			},
+		},
+		SQLite: SQLiteConfig{
+			BusyTimeout: DefaultBusyTimeout,
+		},
+		Serve: ServeConfig{
+			Addr: "127.0.0.1:7777",
+			Snapshots: SnapshotsConfig{
+				Schedule: "0 3 * * *",
+				Keep:     7,
+			},
+			Health: HealthConfig{
+				Schedule: "@daily",
+			},
+			Sweep: SweepConfig{
+				Schedule: "0 2 * * *",
+			},
+			ReadCache: ReadCacheConfig{
+				Schedule:     "* * * * *",
+				MaxStaleness: 5 * time.Minute,
+			},
+			Refresh: RefreshConfig{
+				Schedule:    "@hourly",
+				MaxEntities: 200,
+			},
 		},
 	}
 }
 
-// Load loads configuration from the .lore directory in the given path.
-func Load(basePath string) (*Config, error) {
-	configFile := filepath.Join(basePath, DefaultConfigDir, DefaultConfigFile)
+// Load loads configuration from the given config directory, applying the
+// profile named by $LORE_PROFILE or the config's default_profile.
+func Load(configDir string) (*Config, error) {
+	return LoadProfile(configDir, "")
+}
+
+// LoadProfile loads configuration from the given config directory and
+// applies the named profile. An empty name falls back to $LORE_PROFILE,
+// then to the config's default_profile.
+func LoadProfile(configDir, profile string) (*Config, error) {
+	configFile := ConfigFilePath(configDir)
 
 	data, err := os.ReadFile(configFile)
 	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("config file not found: %s (run 'lore worlds create' first)", configFile)
+		return nil, entities.Errorf(entities.ErrNotFound, "config file not found: %s (run 'lore worlds create' first)", configFile)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("reading config file: %w", err)
@@ -97,13 +753,33 @@ func Load(basePath string) (*Config, error) {
 	// Start with defaults
 	cfg := Default()
 
-	if err := yaml.Unmarshal(data, cfg); err != nil {
+	v := &validation{}
+	if err := decodeStrict(data, cfg, v); err != nil {
 		return nil, fmt.Errorf("parsing config file: %w", err)
 	}
 
+	name, err := cfg.selectProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		if err := cfg.applyProfile(data, name); err != nil {
+			return nil, err
+		}
+	}
+
 	// Apply environment variable overrides
 	cfg.applyEnvOverrides()
 
+	cfg.validate(v)
+	source := configFile
+	if cfg.Profile != "" {
+		source = fmt.Sprintf("%s (profile %s)", configFile, cfg.Profile)
+	}
+	if err := v.err(source); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -122,35 +798,122 @@ func (c *Config) applyEnvOverrides() {
 			c.Qdrant.APIKey = key
 		}
 	}
+	if mode := os.Getenv("LORE_RECORDING"); mode != "" {
+		c.Recording.Mode = mode
+	}
+	if password := os.Getenv("LORE_SMTP_PASSWORD"); password != "" {
+		if c.Notify.Email.Password == "" {
+			c.Notify.Email.Password = password
+		}
+	}
+	if password := os.Getenv("NEO4J_PASSWORD"); password != "" {
+		if c.Graph.Password == "" {
+			c.Graph.Password = password
+		}
+	}
+}
+
+// ConfigDir returns the path to the .lore config directory of a project.
+func ConfigDir(projectPath string) string {
+	return filepath.Join(projectPath, DefaultConfigDir)
 }
 
-// ConfigDir returns the path to the .lore config directory.
-func ConfigDir(basePath string) string {
-	return filepath.Join(basePath, DefaultConfigDir)
+// FindConfigDir locates the config directory. An explicit directory takes
+// precedence, then $LORE_HOME; otherwise the nearest .lore directory holding a
+// config file is searched for from start up to the filesystem root, the way
+// git finds its repository. The result is an absolute path.
+func FindConfigDir(explicit, start string) (string, error) {
+	if explicit == "" {
+		explicit = os.Getenv(HomeEnv)
+	}
+	if explicit != "" {
+		return filepath.Abs(explicit)
+	}
+
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("resolving %s: %w", start, err)
+	}
+	for {
+		if Exists(ConfigDir(dir)) {
+			return ConfigDir(dir), nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%w in %s or any parent directory", ErrConfigDirNotFound, start)
+		}
+		dir = parent
+	}
 }
 
-// ConfigFilePath returns the path to the config file.
-func ConfigFilePath(basePath string) string {
-	return filepath.Join(basePath, DefaultConfigDir, DefaultConfigFile)
+// ConfigFilePath returns the path to the config file in a config directory.
+func ConfigFilePath(configDir string) string {
+	return filepath.Join(configDir, DefaultConfigFile)
 }
 
-// WorldsFilePath returns the path to the worlds file.
-func WorldsFilePath(basePath string) string {
-	return filepath.Join(basePath, DefaultConfigDir, DefaultWorldsFile)
+// WorldsFilePath returns the path to the worlds file in a config directory.
+func WorldsFilePath(configDir string) string {
+	return filepath.Join(configDir, DefaultWorldsFile)
 }
 
-// Exists checks if a lore config exists in the given path.
-func Exists(basePath string) bool {
-	configFile := filepath.Join(basePath, DefaultConfigDir, DefaultConfigFile)
-	_, err := os.Stat(configFile)
+// Exists checks if a config file exists in the given config directory.
+func Exists(configDir string) bool {
+	_, err := os.Stat(ConfigFilePath(configDir))
 	return err == nil
 }
 
-// SanitizeWorldName converts a world name to a valid collection suffix.
+// maxWorldNameLength caps sanitized names so world directories stay well
+// within Windows path length limits.
+const maxWorldNameLength = 48
+
+// windowsReservedNames cannot be used as file or directory names on Windows.
+var windowsReservedNames = []string{
+	"con", "prn", "aux", "nul",
+	"com1", "com2", "com3", "com4", "com5", "com6", "com7", "com8", "com9",
+	"lpt1", "lpt2", "lpt3", "lpt4", "lpt5", "lpt6", "lpt7", "lpt8", "lpt9",
+}
+
+// SanitizeWorldName converts a world name to a valid collection suffix and
+// directory name. Accented Latin, Cyrillic, and Greek letters are
+// transliterated; names with characters from other scripts get a short hash
+// of the original name appended so distinct names stay distinct.
 func SanitizeWorldName(name string) string {
-	// Convert to lowercase
-	name = strings.ToLower(name)
+	lower := strings.ToLower(strings.TrimSpace(name))
+	ascii, complete := transliterate(lower)
+	name = slugify(ascii)
 
+	if !complete {
+		name = joinNameParts(name, nameHash(lower))
+	}
+	if len(name) > maxWorldNameLength {
+		hash := nameHash(lower)
+		name = joinNameParts(strings.TrimRight(name[:maxWorldNameLength-len(hash)-1], "_"), hash)
+	}
+	if slices.Contains(windowsReservedNames, name) {
+		name += "_world"
+	}
+
+	if name == "" {
+		return "default"
+	}
+
+	return name
+}
+
+// legacySanitizeWorldName is SanitizeWorldName before transliteration,
+// which dropped every non-ASCII character. Worlds created back then keep
+// their directory under this name.
+func legacySanitizeWorldName(name string) string {
+	name = slugify(strings.ToLower(name))
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+// slugify reduces a lowercase string to ASCII letters, digits, and single
+// underscores, with no leading or trailing underscore.
+func slugify(name string) string {
 	// Replace spaces and hyphens with underscores
 	name = strings.ReplaceAll(name, " ", "_")
 	name = strings.ReplaceAll(name, "-", "_")
@@ -162,13 +925,20 @@ func SanitizeWorldName(name string) string {
 	name = reMultipleUnderscores.ReplaceAllString(name, "_")
 
 	// Trim leading/trailing underscores
-	name = strings.Trim(name, "_")
+	return strings.Trim(name, "_")
+}
 
-	if name == "" {
-		return "default"
-	}
+// nameHash returns a short, stable hash of a world name.
+func nameHash(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:4])
+}
 
-	return name
+func joinNameParts(prefix, suffix string) string {
+	if prefix == "" {
+		return "w_" + suffix
+	}
+	return prefix + "_" + suffix
 }
 
 // GenerateCollectionName creates a collection name for a world.
@@ -177,11 +947,39 @@ func GenerateCollectionName(worldName string) string {
 }
 
 // SQLitePathForWorld returns the SQLite database path for a given world.
-func SQLitePathForWorld(basePath, worldName string) string {
-	return filepath.Join(basePath, DefaultConfigDir, "worlds", SanitizeWorldName(worldName), "lore.db")
+func SQLitePathForWorld(configDir, worldName string) string {
+	return filepath.Join(WorldDir(configDir, worldName), "lore.db")
+}
+
+// SnapshotDirForWorld returns the directory holding a world's snapshots.
+func SnapshotDirForWorld(configDir, worldName string) string {
+	return filepath.Join(WorldDir(configDir, worldName), "snapshots")
+}
+
+// SessionDir returns the directory holding interactive session logs.
+func SessionDir(configDir string) string {
+	return filepath.Join(configDir, "sessions")
 }
 
-// WorldDir returns the directory path for a given world.
-func WorldDir(basePath, worldName string) string {
-	return filepath.Join(basePath, DefaultConfigDir, "worlds", SanitizeWorldName(worldName))
+// TraceDir returns the directory holding the trace files written by --trace.
+func TraceDir(configDir string) string {
+	return filepath.Join(configDir, "traces")
+}
+
+// WorldDir returns the directory path for a given world. A world created
+// before transliteration keeps using its existing directory.
+func WorldDir(configDir, worldName string) string {
+	dir := filepath.Join(configDir, "worlds", SanitizeWorldName(worldName))
+
+	legacy := legacySanitizeWorldName(worldName)
+	if legacy == "default" || legacy == SanitizeWorldName(worldName) {
+		return dir
+	}
+	legacyDir := filepath.Join(configDir, "worlds", legacy)
+	if _, err := os.Stat(dir); err != nil {
+		if _, err := os.Stat(legacyDir); err == nil {
+			return legacyDir
+		}
+	}
+	return dir
 }
@@ -1,9 +1,16 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 func TestSanitizeWorldName(t *testing.T) {
@@ -67,16 +74,72 @@ func TestSanitizeWorldName(t *testing.T) {
 			input:    "Iron-Throne (Book 1)",
 			expected: "iron_throne_book_1",
 		},
+		{
+			name:     "cyrillic transliterated",
+			input:    "Мир",
+			expected: "mir",
+		},
+		{
+			name:     "latin diacritics transliterated",
+			input:    "Café Überwald",
+			expected: "cafe_uberwald",
+		},
+		{
+			name:     "greek transliterated",
+			input:    "Ελλάδα",
+			expected: "ellada",
+		},
+		{
+			name:     "other scripts hashed",
+			input:    "世界",
+			expected: "w_" + nameHash("世界"),
+		},
+		{
+			name:     "partly transliterable names keep a hash",
+			input:    "Dragon 世界",
+			expected: "dragon_" + nameHash("dragon 世界"),
+		},
+		{
+			name:     "windows reserved name",
+			input:    "CON",
+			expected: "con_world",
+		},
+		{
+			name:     "long names truncated with a hash",
+			input:    strings.Repeat("chronicle ", 10),
+			expected: "chronicle_chronicle_chronicle_chronicle_" + nameHash(strings.TrimSpace(strings.Repeat("chronicle ", 10))),
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := SanitizeWorldName(tt.input)
 			assert.Equal(t, tt.expected, result)
+			assert.LessOrEqual(t, len(result), maxWorldNameLength)
 		})
 	}
 }
 
+func TestSanitizeWorldName_DistinctScripts(t *testing.T) {
+	assert.NotEqual(t, SanitizeWorldName("世界"), SanitizeWorldName("天下"))
+	assert.Equal(t, SanitizeWorldName("世界"), SanitizeWorldName(" 世界 "))
+}
+
+func TestWorldDir_Legacy(t *testing.T) {
+	configDir := t.TempDir()
+	assert.Equal(t, filepath.Join(configDir, "worlds", "cafe"), WorldDir(configDir, "Café"))
+
+	// Before transliteration, "Café" was stored as "caf".
+	legacyDir := filepath.Join(configDir, "worlds", "caf")
+	require.NoError(t, os.MkdirAll(legacyDir, 0755))
+	assert.Equal(t, legacyDir, WorldDir(configDir, "Café"))
+	assert.Equal(t, filepath.Join(legacyDir, "lore.db"), SQLitePathForWorld(configDir, "Café"))
+
+	// Names that used to map to "default" never fall back to it.
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "worlds", "default"), 0755))
+	assert.Equal(t, filepath.Join(configDir, "worlds", "mir"), WorldDir(configDir, "Мир"))
+}
+
 func TestGenerateCollectionName(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -122,6 +185,176 @@ func TestDefault(t *testing.T) {
 	assert.Equal(t, "text-embedding-3-small", cfg.Embedder.Model)
 	assert.Equal(t, "localhost", cfg.Qdrant.Host)
 	assert.Equal(t, 6334, cfg.Qdrant.Port)
+	assert.Equal(t, "127.0.0.1:7777", cfg.Serve.Addr)
+	assert.Equal(t, "0 3 * * *", cfg.Serve.Snapshots.Schedule)
+	assert.Equal(t, 7, cfg.Serve.Snapshots.Keep)
+	assert.Equal(t, "@daily", cfg.Serve.Health.Schedule)
+}
+
+func TestSnapshotsConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Serve.Snapshots.Validate())
+	assert.NoError(t, SnapshotsConfig{}.Validate(), "disabled schedule needs no retention")
+	assert.Error(t, SnapshotsConfig{Schedule: "@daily"}.Validate())
+}
+
+func TestChangesConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Serve.Changes.Validate(), "scheduled change exports are off by default")
+	assert.NoError(t, ChangesConfig{Schedule: "@hourly", Path: "changes.jsonl"}.Validate())
+	assert.Error(t, ChangesConfig{Schedule: "@hourly"}.Validate())
+}
+
+func TestReadCacheConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Serve.ReadCache.Validate())
+	assert.NoError(t, ReadCacheConfig{Schedule: "@hourly"}.Validate(), "no staleness limit")
+	assert.Error(t, ReadCacheConfig{}.Validate())
+	assert.Error(t, ReadCacheConfig{Schedule: "@hourly", MaxStaleness: -time.Minute}.Validate())
+}
+
+func TestRefreshConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Serve.Refresh.Validate())
+	assert.NoError(t, RefreshConfig{Schedule: "@hourly"}.Validate(), "no budget")
+	assert.Error(t, RefreshConfig{Schedule: "@hourly", MaxEntities: -1}.Validate())
+}
+
+func TestReviewConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Review.Validate(), "review is off by default")
+	assert.NoError(t, ReviewConfig{Threshold: 0.7}.Validate())
+	assert.Error(t, ReviewConfig{Threshold: 1.5}.Validate())
+	assert.Error(t, ReviewConfig{Threshold: -0.1}.Validate())
+}
+
+func TestIngestConfig(t *testing.T) {
+	assert.NoError(t, Default().Ingest.Validate(), "default limits")
+	assert.Error(t, IngestConfig{MaxFileSize: -1}.Validate())
+	assert.Error(t, IngestConfig{MaxChunkSize: -1}.Validate())
+
+	t.Setenv(ProfileEnv, "")
+	configDir := t.TempDir()
+	data := "ingest:\n  max_file_size: 256MB\n  max_chunk_size: 65536\n"
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, DefaultConfigFile), []byte(data), 0o600))
+
+	cfg, err := Load(configDir)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ByteSize(256<<20), cfg.Ingest.MaxFileSize)
+	assert.Equal(t, entities.ByteSize(64<<10), cfg.Ingest.MaxChunkSize)
+}
+
+func TestConsistencyConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Consistency.Validate(), "type retrieval by default")
+	assert.NoError(t, ConsistencyConfig{Retrieval: "graph", Limit: 10}.Validate())
+	assert.Error(t, ConsistencyConfig{Retrieval: "everything"}.Validate())
+	assert.Error(t, ConsistencyConfig{Limit: -1}.Validate())
+	assert.NoError(t, ConsistencyConfig{BatchSize: 50, Concurrency: 8}.Validate())
+	assert.Error(t, ConsistencyConfig{BatchSize: -1}.Validate())
+	assert.Error(t, ConsistencyConfig{Concurrency: -1}.Validate())
+	assert.NoError(t, ConsistencyConfig{SingleValued: []string{"eye_color"}, Exclusive: [][]string{{"is_alive", "died_in"}}}.Validate())
+	assert.Error(t, ConsistencyConfig{SingleValued: []string{" "}}.Validate())
+	assert.Error(t, ConsistencyConfig{Exclusive: [][]string{{"is_alive"}}}.Validate())
+	assert.Error(t, ConsistencyConfig{Exclusive: [][]string{{"is_alive", "Is_Alive"}}}.Validate())
+}
+
+func TestExportConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Export.Validate(), "default namespace is derived from the world")
+	assert.NoError(t, ExportConfig{Namespace: "https://example.org/lore/"}.Validate())
+	assert.NoError(t, ExportConfig{Namespace: "https://example.org/lore#"}.Validate())
+	assert.NoError(t, ExportConfig{Namespace: "urn:lore:middle-earth:"}.Validate())
+	assert.Error(t, ExportConfig{Namespace: "example.org/lore/"}.Validate(), "no scheme")
+	assert.Error(t, ExportConfig{Namespace: "https://example.org/lore"}.Validate(), "names cannot be appended")
+
+	assert.NoError(t, ExportConfig{Profiles: map[string]ExportProfileConfig{
+		"beta": {Sources: []string{"book1/*", "book2/*"}, Through: "book1/*"},
+	}}.Validate())
+	assert.Error(t, ExportConfig{Profiles: map[string]ExportProfileConfig{
+		"beta": {Sources: []string{"book1/["}},
+	}}.Validate(), "malformed pattern")
+	assert.Error(t, ExportConfig{Profiles: map[string]ExportProfileConfig{
+		"beta": {Through: "book1/ch3.md"},
+	}}.Validate(), "no reading order")
+}
+
+func TestGraphConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Graph.Validate(), "graph database is off by default")
+	assert.False(t, Default().Graph.Enabled())
+	assert.NoError(t, GraphConfig{Provider: ProviderNeo4j, URL: "http://localhost:7474"}.Validate())
+	assert.Error(t, GraphConfig{Provider: "arangodb", URL: "http://localhost:8529"}.Validate())
+	assert.Error(t, GraphConfig{Provider: ProviderNeo4j, URL: "bolt://localhost:7687"}.Validate(), "only the HTTP API is supported")
+	assert.Error(t, GraphConfig{Provider: ProviderNeo4j}.Validate())
+	assert.Error(t, GraphConfig{Provider: ProviderNeo4j, URL: "http://localhost:7474", Timeout: -1}.Validate())
+}
+
+func TestNotifyConfig_Validate(t *testing.T) {
+	assert.NoError(t, Default().Notify.Validate(), "notifications are off by default")
+
+	email := EmailConfig{SMTP: "smtp.example.com:587", From: "lore@example.com", To: []string{"team@example.com"}}
+	assert.NoError(t, NotifyConfig{Webhook: "https://hooks.example.com/T1", Email: email}.Validate())
+	assert.Error(t, NotifyConfig{Webhook: "hooks.example.com/T1"}.Validate())
+	assert.Error(t, NotifyConfig{Email: EmailConfig{SMTP: "smtp.example.com", From: "lore@example.com", To: email.To}}.Validate(), "port required")
+	assert.Error(t, NotifyConfig{Email: EmailConfig{SMTP: email.SMTP, To: email.To}}.Validate())
+	assert.NoError(t, NotifyConfig{Email: EmailConfig{SMTP: "unused"}}.Validate(), "email is off without recipients")
+	assert.Error(t, NotifyConfig{Timeout: -1}.Validate())
+}
+
+func TestQdrantConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     QdrantConfig
+		wantErr bool
+	}{
+		{name: "defaults", cfg: Default().Qdrant},
+		{
+			name: "scalar with quantile",
+			cfg:  QdrantConfig{Quantization: QuantizationConfig{Type: QuantizationScalar, Quantile: 0.99, AlwaysRAM: true}},
+		},
+		{
+			name: "product with compression",
+			cfg:  QdrantConfig{Quantization: QuantizationConfig{Type: QuantizationProduct, Compression: "x32"}},
+		},
+		{
+			name: "hnsw tuning",
+			cfg:  QdrantConfig{HNSW: HNSWConfig{M: 32, EfConstruct: 200}, OnDiskVectors: true},
+		},
+		{
+			name:    "unknown quantization type",
+			cfg:     QdrantConfig{Quantization: QuantizationConfig{Type: "binary"}},
+			wantErr: true,
+		},
+		{
+			name:    "options without type",
+			cfg:     QdrantConfig{Quantization: QuantizationConfig{AlwaysRAM: true}},
+			wantErr: true,
+		},
+		{
+			name:    "quantile out of range",
+			cfg:     QdrantConfig{Quantization: QuantizationConfig{Type: QuantizationScalar, Quantile: 0.2}},
+			wantErr: true,
+		},
+		{
+			name:    "compression on scalar",
+			cfg:     QdrantConfig{Quantization: QuantizationConfig{Type: QuantizationScalar, Compression: "x4"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid compression",
+			cfg:     QdrantConfig{Quantization: QuantizationConfig{Type: QuantizationProduct, Compression: "x3"}},
+			wantErr: true,
+		},
+		{
+			name:    "hnsw m too small",
+			cfg:     QdrantConfig{HNSW: HNSWConfig{M: 2}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
 }
 
 func TestConfigDir(t *testing.T) {
@@ -130,6 +363,52 @@ func TestConfigDir(t *testing.T) {
 }
 
 func TestConfigFilePath(t *testing.T) {
-	result := ConfigFilePath("/home/user/project")
+	result := ConfigFilePath("/home/user/project/.lore")
 	assert.Equal(t, "/home/user/project/.lore/config.yaml", result)
 }
+
+func TestFindConfigDir(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	nested := filepath.Join(project, "chapters", "one")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, WriteDefault(ConfigDir(project)))
+
+	elsewhere := filepath.Join(root, "elsewhere")
+	require.NoError(t, os.MkdirAll(elsewhere, 0755))
+
+	tests := []struct {
+		name     string
+		explicit string
+		home     string
+		start    string
+		want     string
+		wantErr  bool
+	}{
+		{name: "project root", start: project, want: ConfigDir(project)},
+		{name: "nested directory", start: nested, want: ConfigDir(project)},
+		{name: "not found", start: elsewhere, wantErr: true},
+		{name: "LORE_HOME", home: "/srv/lore", start: nested, want: "/srv/lore"},
+		{name: "explicit beats LORE_HOME", explicit: "/opt/lore", home: "/srv/lore", start: nested, want: "/opt/lore"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(HomeEnv, tt.home)
+
+			dir, err := FindConfigDir(tt.explicit, tt.start)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrConfigDirNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, dir)
+		})
+	}
+}
+
+func TestRecordingDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("/p/.lore", "recordings"), RecordingDir("/p/.lore", RecordingConfig{}))
+	assert.Equal(t, filepath.Join("/p/.lore", "fixtures"), RecordingDir("/p/.lore", RecordingConfig{Dir: "fixtures"}))
+	assert.Equal(t, "/srv/fixtures", RecordingDir("/p/.lore", RecordingConfig{Dir: "/srv/fixtures"}))
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// maxLanguageLength bounds llm.language, which is written into prompts.
+const maxLanguageLength = 40
+
+// languageNames maps ISO 639-1 codes to the English names the LLM is given.
+var languageNames = map[string]string{
+	"ar": "Arabic", "bg": "Bulgarian", "ca": "Catalan", "cs": "Czech", "da": "Danish",
+	"de": "German", "el": "Greek", "en": "English", "es": "Spanish", "et": "Estonian",
+	"fa": "Persian", "fi": "Finnish", "fr": "French", "he": "Hebrew", "hi": "Hindi",
+	"hr": "Croatian", "hu": "Hungarian", "id": "Indonesian", "it": "Italian", "ja": "Japanese",
+	"ko": "Korean", "lt": "Lithuanian", "lv": "Latvian", "nl": "Dutch", "no": "Norwegian",
+	"pl": "Polish", "pt": "Portuguese", "ro": "Romanian", "ru": "Russian", "sk": "Slovak",
+	"sl": "Slovenian", "sr": "Serbian", "sv": "Swedish", "th": "Thai", "tr": "Turkish",
+	"uk": "Ukrainian", "vi": "Vietnamese", "zh": "Chinese",
+}
+
+// LanguageName returns the name prompts use for a configured source
+// language: the English name of an ISO 639-1 code such as "de", or the
+// value as written. English and the empty string return "", since prompts
+// need no language instructions for English text.
+func LanguageName(language string) string {
+	language = strings.TrimSpace(language)
+	code := strings.ToLower(language)
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i] // "pt-BR" is Portuguese
+	}
+	if name, ok := languageNames[code]; ok {
+		language = name
+	}
+	if strings.EqualFold(language, "English") {
+		return ""
+	}
+	return language
+}
+
+// validateLanguage checks a source language is a short name or code that
+// can be written into a prompt.
+func validateLanguage(language string) error {
+	if len([]rune(language)) > maxLanguageLength {
+		return fmt.Errorf("must be a language name or code of at most %d characters, got %q", maxLanguageLength, language)
+	}
+	for _, r := range language {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("must be a single line, got %q", language)
+		}
+	}
+	return nil
+}
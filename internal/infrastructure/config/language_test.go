@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageName(t *testing.T) {
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "", want: ""},
+		{language: "en", want: ""},
+		{language: "English", want: ""},
+		{language: "de", want: "German"},
+		{language: "pt-BR", want: "Portuguese"},
+		{language: " Quenya ", want: "Quenya"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			assert.Equal(t, tt.want, LanguageName(tt.language))
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// ProfileEnv names the environment variable that selects a profile.
+const ProfileEnv = "LORE_PROFILE"
+
+// ProfileConfig overrides connection settings for one environment, such as
+// a local Qdrant on a laptop versus a shared team server. Only the keys set
+// in a profile replace the top-level values.
+type ProfileConfig struct {
+	LLM      LLMConfig      `yaml:"llm,omitempty"`
+	Embedder EmbedderConfig `yaml:"embedder,omitempty"`
+	Qdrant   QdrantConfig   `yaml:"qdrant,omitempty"`
+}
+
+// selectProfile picks the profile to apply: the explicit name, then
+// $LORE_PROFILE, then the config's default_profile. Empty means none.
+func (c *Config) selectProfile(explicit string) (string, error) {
+	name := explicit
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return "", nil
+	}
+
+	if _, ok := c.Profiles[name]; !ok {
+		if len(c.Profiles) == 0 {
+			return "", entities.Errorf(entities.ErrNotFound, "profile %q not found (no profiles are defined)", name)
+		}
+		return "", entities.Errorf(entities.ErrNotFound, "profile %q not found (available: %s)",
+			name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+	return name, nil
+}
+
+// applyProfile overlays the named profile from the raw config file onto the
+// top-level settings. The profile is decoded again from the file, on top of
+// the current values, so keys it leaves out keep their top-level value.
+func (c *Config) applyProfile(data []byte, name string) error {
+	var root yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil {
+		return fmt.Errorf("parsing config file: %w", err)
+	}
+
+	var doc *yaml.Node
+	if len(root.Content) > 0 {
+		doc = root.Content[0]
+	}
+	node := mappingValue(mappingValue(doc, "profiles"), name)
+	if node == nil {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	overlay := ProfileConfig{LLM: c.LLM, Embedder: c.Embedder, Qdrant: c.Qdrant}
+	if err := node.Decode(&overlay); err != nil {
+		return fmt.Errorf("applying profile %q: %w", name, err)
+	}
+
+	c.LLM, c.Embedder, c.Qdrant = overlay.LLM, overlay.Embedder, overlay.Qdrant
+	c.Profile = name
+	return nil
+}
+
+// mappingValue returns the value under key in a YAML mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileTestConfig = `llm:
+  model: gpt-4o-mini
+qdrant:
+  host: localhost
+  port: 6334
+default_profile: %s
+profiles:
+  local: {}
+  cloud:
+    llm:
+      model: gpt-4o
+      api_key: sk-cloud
+    qdrant:
+      host: qdrant.example.com
+      api_key: qd-cloud
+`
+
+func writeProfileTestConfig(t *testing.T, defaultProfile string) string {
+	t.Helper()
+	if defaultProfile == "" {
+		defaultProfile = `""`
+	}
+	configDir := t.TempDir()
+	data := fmt.Sprintf(profileTestConfig, defaultProfile)
+	require.NoError(t, os.WriteFile(ConfigFilePath(configDir), []byte(data), 0600))
+	return configDir
+}
+
+func TestLoadProfile(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaultProfile string
+		env            string
+		explicit       string
+		wantProfile    string
+		wantHost       string
+		wantModel      string
+	}{
+		{name: "no profile", wantHost: "localhost", wantModel: "gpt-4o-mini"},
+		{name: "explicit", explicit: "cloud", wantProfile: "cloud", wantHost: "qdrant.example.com", wantModel: "gpt-4o"},
+		{name: "LORE_PROFILE", env: "cloud", wantProfile: "cloud", wantHost: "qdrant.example.com", wantModel: "gpt-4o"},
+		{name: "explicit beats LORE_PROFILE", env: "cloud", explicit: "local", wantProfile: "local", wantHost: "localhost", wantModel: "gpt-4o-mini"},
+		{name: "default_profile", defaultProfile: "cloud", wantProfile: "cloud", wantHost: "qdrant.example.com", wantModel: "gpt-4o"},
+		{name: "LORE_PROFILE beats default_profile", defaultProfile: "cloud", env: "local", wantProfile: "local", wantHost: "localhost", wantModel: "gpt-4o-mini"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ProfileEnv, tt.env)
+			t.Setenv("OPENAI_API_KEY", "")
+			t.Setenv("QDRANT_API_KEY", "")
+			configDir := writeProfileTestConfig(t, tt.defaultProfile)
+
+			cfg, err := LoadProfile(configDir, tt.explicit)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantProfile, cfg.Profile)
+			assert.Equal(t, tt.wantHost, cfg.Qdrant.Host)
+			assert.Equal(t, tt.wantModel, cfg.LLM.Model)
+			assert.Equal(t, 6334, cfg.Qdrant.Port, "keys a profile leaves out keep their top-level value")
+			assert.Equal(t, "openai", cfg.LLM.Provider)
+		})
+	}
+}
+
+func TestLoadProfile_Secrets(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	t.Setenv("OPENAI_API_KEY", "sk-env")
+	t.Setenv("QDRANT_API_KEY", "")
+	configDir := writeProfileTestConfig(t, "")
+
+	cfg, err := LoadProfile(configDir, "cloud")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-cloud", cfg.LLM.APIKey, "profile keys win over the environment")
+	assert.Equal(t, "sk-env", cfg.Embedder.APIKey, "the environment fills keys the profile leaves unset")
+	assert.Equal(t, "qd-cloud", cfg.Qdrant.APIKey)
+}
+
+func TestLoadProfile_NotFound(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	configDir := writeProfileTestConfig(t, "")
+
+	_, err := LoadProfile(configDir, "staging")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `profile "staging" not found (available: cloud, local)`)
+}
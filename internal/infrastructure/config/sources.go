@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// SourcesFile is the name of the file mapping source paths to the metadata
+// their facts inherit when ingested.
+const SourcesFile = "sources.yaml"
+
+// SourcesConfig holds the rules giving ingested facts the metadata of
+// their source, such as:
+//
+//	rules:
+//	  - pattern: book2
+//	    metadata: {book: 2}
+//	  - pattern: "frodo-*.md"
+//	    metadata: {pov: Frodo}
+//	    tags: [draft]
+//	  - pattern: brainstorm
+//	    draft: true
+//	    ttl: 336h
+type SourcesConfig struct {
+	Rules []SourceRuleConfig `yaml:"rules"`
+}
+
+// SourceRuleConfig gives the facts of sources matching Pattern metadata,
+// tags, or draft status.
+type SourceRuleConfig struct {
+	Pattern  string            `yaml:"pattern"`
+	Metadata map[string]string `yaml:"metadata,omitempty"`
+	Tags     []string          `yaml:"tags,omitempty"`
+
+	// Draft makes the facts drafts, lasting TTL (0 = until promoted or
+	// purged).
+	Draft bool          `yaml:"draft,omitempty"`
+	TTL   time.Duration `yaml:"ttl,omitempty"`
+}
+
+// SourcesFilePath returns the path to the sources file in a config
+// directory.
+func SourcesFilePath(configDir string) string {
+	return filepath.Join(configDir, SourcesFile)
+}
+
+// LoadSources loads source rules from the config directory. A missing file
+// means no rules. Rules are validated by services.SourceRules.
+func LoadSources(configDir string) (*SourcesConfig, error) {
+	path := SourcesFilePath(configDir)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &SourcesConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sources file: %w", err)
+	}
+
+	var cfg SourcesConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, entities.Errorf(entities.ErrValidation, "parsing sources file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestLoadSources(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadSources(dir)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Rules, "a missing file means no rules")
+
+	require.NoError(t, os.WriteFile(SourcesFilePath(dir), []byte(`rules:
+  - pattern: book2
+    metadata: {book: 2}
+  - pattern: "frodo-*.md"
+    metadata: {pov: Frodo}
+    tags: [draft]
+`), 0o600))
+	cfg, err = LoadSources(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []SourceRuleConfig{
+		{Pattern: "book2", Metadata: map[string]string{"book": "2"}},
+		{Pattern: "frodo-*.md", Metadata: map[string]string{"pov": "Frodo"}, Tags: []string{"draft"}},
+	}, cfg.Rules)
+
+	require.NoError(t, os.WriteFile(SourcesFilePath(dir), []byte("rules: {"), 0o600))
+	_, err = LoadSources(dir)
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
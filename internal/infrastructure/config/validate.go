@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// LLM and embedding providers.
+const (
+	ProviderOpenAI = "openai"
+	// ProviderFake needs no network or API key: it extracts facts from
+	// simple sentences by template and embeds text by hashing. It is
+	// deterministic, for integration tests and CI.
+	ProviderFake = "fake"
+)
+
+// EmbeddingVectorSize is the dimension of the vectors collections are
+// created with.
+const EmbeddingVectorSize = 1536
+
+// embeddingDimensions lists the native vector size of known embedding models.
+var embeddingDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+// ShortensEmbeddings reports whether an embedding model can return vectors
+// shorter than its native size. Such models are asked for
+// EmbeddingVectorSize dimensions, so they fit any collection.
+func ShortensEmbeddings(model string) bool {
+	return strings.HasPrefix(model, "text-embedding-3-")
+}
+
+// ValidationError lists every problem found in a configuration, so they
+// can all be fixed in one pass.
+type ValidationError struct {
+	// Path is the config file the problems were found in, if known.
+	Path     string
+	Problems []string
+}
+
+// Is reports ValidationError as an entities.ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == entities.ErrValidation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid config")
+	if e.Path != "" {
+		b.WriteString(" ")
+		b.WriteString(e.Path)
+	}
+	b.WriteString(":")
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// validation collects problems and turns them into a ValidationError.
+type validation struct {
+	problems []string
+}
+
+func (v *validation) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// check records err, if any, under the given config section.
+func (v *validation) check(section string, err error) {
+	if err != nil {
+		v.addf("%s: %v", section, err)
+	}
+}
+
+func (v *validation) err(path string) error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Path: path, Problems: v.problems}
+}
+
+// Validate checks the configuration for invalid or inconsistent values and
+// reports all problems together as a *ValidationError. Credentials are
+// checked separately by ValidateCredentials.
+func (c *Config) Validate() error {
+	v := &validation{}
+	c.validate(v)
+	return v.err("")
+}
+
+func (c *Config) validate(v *validation) {
+	v.check("llm.provider", validateProvider(c.LLM.Provider))
+	v.check("llm", c.LLM.TimeoutConfig.Validate())
+	v.check("llm.language", validateLanguage(c.LLM.Language))
+	if c.LLM.ConsistencyCacheTTL < 0 {
+		v.addf("llm.consistency_cache_ttl: must not be negative, got %s", c.LLM.ConsistencyCacheTTL)
+	}
+	v.check("embedder.provider", validateProvider(c.Embedder.Provider))
+	v.check("embedder", c.Embedder.TimeoutConfig.Validate())
+	if dims, ok := embeddingDimensions[c.Embedder.Model]; ok && dims != EmbeddingVectorSize && !ShortensEmbeddings(c.Embedder.Model) {
+		v.addf("embedder.model: %s produces %d-dimensional vectors, but collections store %d (use a text-embedding-3 model or text-embedding-ada-002)",
+			c.Embedder.Model, dims, EmbeddingVectorSize)
+	}
+
+	if c.Qdrant.Host == "" {
+		v.addf("qdrant.host: must not be empty")
+	}
+	v.check("qdrant.port", validatePort(c.Qdrant.Port))
+	v.check("qdrant", c.Qdrant.Validate())
+	v.check("qdrant", c.Qdrant.TimeoutConfig.Validate())
+	if c.SQLite.BusyTimeout < 0 {
+		v.addf("sqlite.busy_timeout: must not be negative, got %s", c.SQLite.BusyTimeout)
+	}
+
+	if c.Serve.Addr != "" {
+		v.check("serve.addr", validateAddr(c.Serve.Addr))
+	}
+	v.check("serve", c.Serve.Snapshots.Validate())
+	v.check("serve", c.Serve.Changes.Validate())
+	v.check("serve", c.Serve.ReadCache.Validate())
+	v.check("serve", c.Serve.Refresh.Validate())
+	v.check("review", c.Review.Validate())
+	v.check("ingest", c.Ingest.Validate())
+	v.check("consistency", c.Consistency.Validate())
+	v.check("export", c.Export.Validate())
+	v.check("graph", c.Graph.Validate())
+	v.check("notify", c.Notify.Validate())
+	v.check("recording", c.Recording.Validate())
+	v.check("quota", c.Quota.Validate())
+}
+
+// ValidateCredentials checks that every configured provider has an API key.
+// Replayed calls need none.
+func (c *Config) ValidateCredentials() error {
+	v := &validation{}
+	if c.Recording.Replaying() {
+		return nil
+	}
+	if c.LLM.Provider == ProviderOpenAI && c.LLM.APIKey == "" {
+		v.addf("llm.api_key: required for provider %s (set it in config.yaml or export OPENAI_API_KEY)", ProviderOpenAI)
+	}
+	if c.Embedder.Provider == ProviderOpenAI && c.Embedder.APIKey == "" {
+		v.addf("embedder.api_key: required for provider %s (set it in config.yaml or export OPENAI_API_KEY)", ProviderOpenAI)
+	}
+	return v.err("")
+}
+
+func validateProvider(provider string) error {
+	if provider != "" && provider != ProviderOpenAI && provider != ProviderFake {
+		return fmt.Errorf("unsupported provider %q (supported: %s, %s)", provider, ProviderOpenAI, ProviderFake)
+	}
+	return nil
+}
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("must be between 1 and 65535, got %d", port)
+	}
+	return nil
+}
+
+func validateAddr(addr string) error {
+	_, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("must be host:port, got %q", addr)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("invalid port %q", portStr)
+	}
+	return validatePort(port)
+}
+
+// reUnknownField matches yaml.v3's error for keys with no matching field.
+var reUnknownField = regexp.MustCompile(`^(line \d+): field (\S+) not found in type \S+$`)
+
+// decodeStrict decodes a config file into cfg, recording unknown keys and
+// type mismatches as problems. Only syntax errors are returned.
+func decodeStrict(data []byte, cfg *Config, v *validation) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	err := dec.Decode(cfg)
+	var typeErr *yaml.TypeError
+	switch {
+	case err == nil, errors.Is(err, io.EOF):
+		return nil
+	case errors.As(err, &typeErr):
+		for _, msg := range typeErr.Errors {
+			if m := reUnknownField.FindStringSubmatch(msg); m != nil {
+				msg = fmt.Sprintf("%s: unknown key %q", m[1], m[2])
+			}
+			v.problems = append(v.problems, msg)
+		}
+		return nil
+	default:
+		return err
+	}
+}
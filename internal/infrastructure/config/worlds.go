@@ -1,13 +1,13 @@
 package config
 
 import (
-	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 // WorldsConfig holds dynamic world definitions (read/write).
@@ -17,13 +17,26 @@ type WorldsConfig struct {
 
 // WorldEntry holds configuration for a specific world.
 type WorldEntry struct {
-	Collection  string `yaml:"collection"`
-	Description string `yaml:"description,omitempty"`
+	Collection  string           `yaml:"collection"`
+	Description string           `yaml:"description,omitempty"`
+	Validation  ValidationConfig `yaml:"validation,omitempty"`
+
+	// EmbeddingTemplate is the embedder.template the world's facts were
+	// embedded with, so a changed template can be detected.
+	EmbeddingTemplate string `yaml:"embedding_template,omitempty"`
+}
+
+// ValidationConfig holds the import validation rules for a world.
+type ValidationConfig struct {
+	// Predicates restricts imported facts to this vocabulary (empty = any).
+	Predicates []string `yaml:"predicates,omitempty"`
+	// Strict requires fact subjects to match existing entities.
+	Strict bool `yaml:"strict,omitempty"`
 }
 
-// LoadWorlds loads world configuration from the .lore directory.
-func LoadWorlds(basePath string) (*WorldsConfig, error) {
-	worldsFile := filepath.Join(basePath, DefaultConfigDir, DefaultWorldsFile)
+// LoadWorlds loads world configuration from the given config directory.
+func LoadWorlds(configDir string) (*WorldsConfig, error) {
+	worldsFile := WorldsFilePath(configDir)
 
 	data, err := os.ReadFile(worldsFile)
 	if os.IsNotExist(err) {
@@ -49,9 +62,8 @@ func LoadWorlds(basePath string) (*WorldsConfig, error) {
 }
 
 // Save writes the worlds configuration to the worlds file.
-func (w *WorldsConfig) Save(basePath string) error {
-	configDir := filepath.Join(basePath, DefaultConfigDir)
-	worldsFile := filepath.Join(configDir, DefaultWorldsFile)
+func (w *WorldsConfig) Save(configDir string) error {
+	worldsFile := WorldsFilePath(configDir)
 
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
@@ -87,7 +99,7 @@ func (w *WorldsConfig) Remove(name string) {
 // Get returns the configuration for a specific world.
 func (w *WorldsConfig) Get(name string) (*WorldEntry, error) {
 	if len(w.Worlds) == 0 {
-		return nil, errors.New("no worlds configured")
+		return nil, entities.Errorf(entities.ErrNotFound, "no worlds configured")
 	}
 
 	entry, ok := w.Worlds[name]
@@ -105,7 +117,7 @@ func (w *WorldsConfig) Get(name string) (*WorldEntry, error) {
 				break
 			}
 		}
-		return nil, fmt.Errorf("world %q not found (available: %s)", name, b.String())
+		return nil, entities.Errorf(entities.ErrNotFound, "world %q not found (available: %s)", name, b.String())
 	}
 
 	return &entry, nil
@@ -129,9 +141,18 @@ func (w *WorldsConfig) Exists(name string) bool {
 	return ok
 }
 
-// WorldsExists checks if a worlds config file exists in the given path.
-func WorldsExists(basePath string) bool {
-	worldsFile := filepath.Join(basePath, DefaultConfigDir, DefaultWorldsFile)
-	_, err := os.Stat(worldsFile)
+// FindByCollection returns the name of the world stored in a collection.
+func (w *WorldsConfig) FindByCollection(collection string) (string, bool) {
+	for name, entry := range w.Worlds {
+		if entry.Collection == collection {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// WorldsExists checks if a worlds config file exists in the given config directory.
+func WorldsExists(configDir string) bool {
+	_, err := os.Stat(WorldsFilePath(configDir))
 	return err == nil
 }
@@ -3,26 +3,23 @@ package config
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 
 	"gopkg.in/yaml.v3"
 )
 
-// WriteDefault creates the .lore directory and writes default config files.
-func WriteDefault(basePath string) error {
-	return WriteDefaultWithWorld(basePath, "default", "")
+// WriteDefault creates the config directory and writes default config files.
+func WriteDefault(configDir string) error {
+	return WriteDefaultWithWorld(configDir, "default", "")
 }
 
-// WriteDefaultWithWorld creates the .lore directory and writes config files with the specified world.
-func WriteDefaultWithWorld(basePath string, worldName string, description string) error {
-	configDir := filepath.Join(basePath, DefaultConfigDir)
-
+// WriteDefaultWithWorld creates the config directory and writes config files with the specified world.
+func WriteDefaultWithWorld(configDir string, worldName string, description string) error {
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
 	}
 
 	// Write config.yaml (static infrastructure config)
-	if err := writeDefaultConfig(basePath); err != nil {
+	if err := writeDefaultConfig(configDir); err != nil {
 		return err
 	}
 
@@ -36,7 +33,7 @@ func WriteDefaultWithWorld(basePath string, worldName string, description string
 		},
 	}
 
-	if err := worlds.Save(basePath); err != nil {
+	if err := worlds.Save(configDir); err != nil {
 		return fmt.Errorf("writing worlds file: %w", err)
 	}
 
@@ -44,8 +41,8 @@ func WriteDefaultWithWorld(basePath string, worldName string, description string
 }
 
 // writeDefaultConfig writes the default config.yaml file.
-func writeDefaultConfig(basePath string) error {
-	configFile := filepath.Join(basePath, DefaultConfigDir, DefaultConfigFile)
+func writeDefaultConfig(configDir string) error {
+	configFile := ConfigFilePath(configDir)
 
 	if _, err := os.Stat(configFile); err == nil {
 		return fmt.Errorf("config file already exists: %s", configFile)
@@ -66,9 +63,8 @@ func writeDefaultConfig(basePath string) error {
 }
 
 // WriteConfig writes the given config to the config file.
-func WriteConfig(basePath string, cfg *Config) error {
-	configDir := filepath.Join(basePath, DefaultConfigDir)
-	configFile := filepath.Join(configDir, DefaultConfigFile)
+func WriteConfig(configDir string, cfg *Config) error {
+	configFile := ConfigFilePath(configDir)
 
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
@@ -0,0 +1,83 @@
+// Package dictionary provides a ports.Dictionary backed by a word list: a
+// built-in list of common English words, or a file with one word per line
+// such as /usr/share/dict/words.
+package dictionary
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed words.txt
+var englishWords string
+
+// suffixes are stripped, longest first, to find the word an inflection
+// comes from, so "dragons" and "burned" are known if "dragon" and "burn" are.
+var suffixes = []string{"'s", "ing", "ies", "es", "ed", "ly", "er", "s", "d"}
+
+// WordList implements ports.Dictionary with a set of words.
+type WordList struct {
+	words map[string]bool
+}
+
+// English returns the built-in list of common English words.
+func English() *WordList {
+	list, _ := read(strings.NewReader(englishWords)) // A strings.Reader cannot fail
+	return list
+}
+
+// Load reads a word list from path, one word per line.
+func Load(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening word list: %w", err)
+	}
+	defer f.Close()
+
+	list, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading word list %s: %w", path, err)
+	}
+	return list, nil
+}
+
+func read(r io.Reader) (*WordList, error) {
+	list := &WordList{words: make(map[string]bool)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" && !strings.HasPrefix(word, "#") {
+			list.words[word] = true
+		}
+	}
+	return list, scanner.Err()
+}
+
+// Contains reports whether word, in lowercase, is in the list or is an
+// inflection of a word in it.
+func (l *WordList) Contains(word string) bool {
+	if l.words[word] {
+		return true
+	}
+	for _, suffix := range suffixes {
+		stem, ok := strings.CutSuffix(word, suffix)
+		if !ok || len(stem) < 2 {
+			continue
+		}
+		if l.words[stem] || l.words[stem+"e"] || (suffix == "ies" && l.words[stem+"y"]) {
+			return true
+		}
+		if n := len(stem); n > 2 && stem[n-1] == stem[n-2] && l.words[stem[:n-1]] {
+			return true // Doubled consonant, as in "running"
+		}
+	}
+	return false
+}
+
+// Len returns the number of words in the list.
+func (l *WordList) Len() int {
+	return len(l.words)
+}
@@ -0,0 +1,36 @@
+package dictionary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWordList_Contains(t *testing.T) {
+	english := English()
+
+	for _, word := range []string{"dragon", "dragons", "burned", "rules", "running", "cities", "king's", "the"} {
+		assert.True(t, english.Contains(word), word)
+	}
+	for _, word := range []string{"frodo", "mordor", "dragonlord", "s", ""} {
+		assert.False(t, english.Contains(word), word)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words")
+	require.NoError(t, os.WriteFile(path, []byte("# comment\nMordor\n\n hobbit \n"), 0644))
+
+	list, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("mordor"), "words are lowercased")
+	assert.True(t, list.Contains("hobbits"))
+	assert.False(t, list.Contains("dragon"), "the built-in list is not included")
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package documents
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// docxBody is the part of a DOCX package holding the document's text.
+const docxBody = "word/document.xml"
+
+// docxText returns the text of the DOCX package in r, a paragraph per
+// paragraph. If Word recorded where it broke the pages when it last laid
+// the document out, or it has explicit page breaks, each page is numbered;
+// otherwise the text is one unnumbered page.
+func docxText(r io.ReaderAt, size, budget int64) ([]ports.DocumentPage, error) {
+	pkg, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, entities.Errorf(entities.ErrValidation, "not a DOCX package: %v", err)
+	}
+
+	var body *zip.File
+	for _, f := range pkg.File {
+		if f.Name == docxBody {
+			body = f
+			break
+		}
+	}
+	if body == nil {
+		return nil, entities.Errorf(entities.ErrValidation, "not a DOCX package: no %s (is it another Office format?)", docxBody)
+	}
+
+	rc, err := body.Open()
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", docxBody, err)
+	}
+	defer rc.Close()
+
+	limited := &io.LimitedReader{R: rc, N: budget + 1}
+	pages, err := docxPages(xml.NewDecoder(limited))
+	if limited.N <= 0 {
+		return nil, errTooLarge
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", docxBody, err)
+	}
+
+	if len(pages) == 1 {
+		return []ports.DocumentPage{{Text: pages[0]}}, nil
+	}
+	numbered := make([]ports.DocumentPage, len(pages))
+	for i, page := range pages {
+		numbered[i] = ports.DocumentPage{Number: i + 1, Text: page}
+	}
+	return numbered, nil
+}
+
+// docxPages returns the text of each page of a document body, a blank
+// line between paragraphs.
+func docxPages(decoder *xml.Decoder) ([]string, error) {
+	var (
+		pages  []string
+		page   strings.Builder
+		inText bool
+	)
+	// Word records an explicit page break again where it renders it, so
+	// a break with no text since the last is not a new page.
+	endPage := func() {
+		if text := strings.TrimSpace(page.String()); text != "" {
+			pages = append(pages, text)
+		}
+		page.Reset()
+	}
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				page.WriteString("\t")
+			case "br", "cr":
+				if docxAttr(t, "type") == "page" {
+					endPage()
+				} else {
+					page.WriteString("\n")
+				}
+			case "lastRenderedPageBreak":
+				endPage()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				page.WriteString("\n\n")
+			}
+		case xml.CharData:
+			if inText {
+				page.Write(t)
+			}
+		}
+	}
+	endPage()
+	return pages, nil
+}
+
+// docxAttr returns the value of an attribute of el, in any namespace.
+func docxAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package documents
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+const (
+	// maxPageTreeDepth bounds how deeply page tree nodes may nest.
+	maxPageTreeDepth = 64
+	// maxPageTreeNodes bounds the page tree nodes visited, so a malformed
+	// tree that refers back to itself ends.
+	maxPageTreeNodes = 1 << 20
+)
+
+// pdfPages returns the text of each page of the PDF in r, of size bytes,
+// leaving out pages without text, such as scanned images. The pages'
+// text together may be at most budget bytes.
+func pdfPages(r io.ReaderAt, size, budget int64) (pages []ports.DocumentPage, err error) {
+	// The parser panics on some malformed files; they are refused like
+	// any other malformed file rather than stopping the ingest
+	defer func() {
+		if p := recover(); p != nil {
+			pages, err = nil, entities.Errorf(entities.ErrValidation, "malformed PDF: %v", p)
+		}
+	}()
+
+	doc, err := pdf.NewReader(r, size)
+	if errors.Is(err, pdf.ErrInvalidPassword) {
+		return nil, entities.Errorf(entities.ErrValidation, "the PDF is encrypted; remove its password or export it as text first")
+	}
+	if err != nil {
+		return nil, entities.WithKind(entities.ErrValidation, err)
+	}
+
+	tree := &pdfPageTree{}
+	tree.walk(doc.Trailer().Key("Root").Key("Pages"), 0)
+	if len(tree.pages) == 0 {
+		return nil, entities.Errorf(entities.ErrValidation, "no pages found in the PDF")
+	}
+
+	for i, page := range tree.pages {
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			return nil, entities.Errorf(entities.ErrValidation, "page %d: %v", i+1, err)
+		}
+		if budget -= int64(len(text)); budget < 0 {
+			return nil, errTooLarge
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			pages = append(pages, ports.DocumentPage{Number: i + 1, Text: text})
+		}
+	}
+	return pages, nil
+}
+
+// pdfPageTree collects the pages of a PDF's page tree, in order.
+type pdfPageTree struct {
+	pages []pdf.Page
+	nodes int
+}
+
+// walk adds the pages under node, depth levels down the tree.
+func (t *pdfPageTree) walk(node pdf.Value, depth int) {
+	if t.nodes++; t.nodes > maxPageTreeNodes || depth > maxPageTreeDepth {
+		return
+	}
+	switch node.Key("Type").Name() {
+	case "Page":
+		t.pages = append(t.pages, pdf.Page{V: node})
+	case "Pages":
+		kids := node.Key("Kids")
+		for i := 0; i < kids.Len(); i++ {
+			t.walk(kids.Index(i), depth+1)
+		}
+	}
+}
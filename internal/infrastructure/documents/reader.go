@@ -0,0 +1,68 @@
+// Package documents extracts the text of PDF and Word documents for
+// ingestion.
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultMaxExtracted bounds the text extracted from one document, so a
+// small file cannot expand without limit.
+const DefaultMaxExtracted = 256 << 20
+
+// errTooLarge reports a document that decompresses to more than its
+// budget.
+var errTooLarge = entities.Errorf(entities.ErrValidation, "the document expands to more than %s", entities.ByteSize(DefaultMaxExtracted))
+
+// Reader extracts the text of PDF and DOCX files.
+type Reader struct{}
+
+var _ ports.DocumentReader = Reader{}
+
+// NewReader creates a reader of PDF and DOCX files.
+func NewReader() Reader {
+	return Reader{}
+}
+
+// CanRead implements ports.DocumentReader.
+func (Reader) CanRead(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".pdf", ".docx":
+		return true
+	}
+	return false
+}
+
+// Read implements ports.DocumentReader. The format is told by the
+// content, not the name. PDF pages are numbered; DOCX pages are too when
+// the document records where Word last broke its pages.
+func (Reader) Read(r io.ReaderAt, size int64) ([]ports.DocumentPage, error) {
+	head := make([]byte, 5)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reading document: %w", err)
+	}
+	head = head[:n]
+
+	var pages []ports.DocumentPage
+	switch {
+	case bytes.HasPrefix(head, []byte("%PDF-")):
+		if pages, err = pdfPages(r, size, DefaultMaxExtracted); err != nil {
+			return nil, fmt.Errorf("reading PDF: %w", err)
+		}
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		if pages, err = docxText(r, size, DefaultMaxExtracted); err != nil {
+			return nil, fmt.Errorf("reading DOCX: %w", err)
+		}
+	default:
+		return nil, entities.Errorf(entities.ErrValidation, "not a PDF or DOCX document")
+	}
+	return pages, nil
+}
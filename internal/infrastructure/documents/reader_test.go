@@ -0,0 +1,227 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// buildPDF returns a PDF of objects, numbered from 1, whose catalog is
+// object 1, with trailer added to its trailer dictionary.
+func buildPDF(trailer string, objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, trailer, xref)
+	return b.Bytes()
+}
+
+// flateStream returns a stream object of data, Flate-compressed.
+func flateStream(dict, data string) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	_, _ = w.Write([]byte(data))
+	_ = w.Close()
+	return fmt.Sprintf("<< %s /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", dict, b.Len(), b.String())
+}
+
+// buildDOCX returns a DOCX package whose document body is body.
+func buildDOCX(t *testing.T, body string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	f, err := w.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = io.WriteString(f, `<?xml version="1.0" encoding="UTF-8"?>`+
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`+
+		body+`</w:body></w:document>`)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return b.Bytes()
+}
+
+// read returns the pages Reader extracts from data.
+func read(data []byte) ([]ports.DocumentPage, error) {
+	return NewReader().Read(bytes.NewReader(data), int64(len(data)))
+}
+
+func TestReader_CanRead(t *testing.T) {
+	reader := NewReader()
+	assert.True(t, reader.CanRead("notes/Book One.pdf"))
+	assert.True(t, reader.CanRead("bible.DOCX"))
+	assert.False(t, reader.CanRead("chapter1.txt"))
+	assert.False(t, reader.CanRead("old.doc"))
+}
+
+func TestReader_PDF(t *testing.T) {
+	t.Run("pages with simple fonts", func(t *testing.T) {
+		data := buildPDF("",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R] /Count 3 /Resources << /Font << /F1 6 0 R >> >> >>",
+			"<< /Type /Page /Parent 2 0 R /Contents 7 0 R >>",
+			"<< /Type /Page /Parent 2 0 R >>",
+			"<< /Type /Page /Parent 2 0 R /Contents [8 0 R] >>",
+			"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+			flateStream("", "BT /F1 12 Tf 14 TL 72 700 Td (Aragorn is a ranger.) Tj T* (He walks north.) Tj ET BT (Caf\\351 \\(old\\)) Tj ET"),
+			flateStream("", "BT /F1 12 Tf 72 700 Td [(Gimli)-400( is a dwarf.)] TJ ET"),
+		)
+
+		pages, err := read(data)
+		require.NoError(t, err)
+		assert.Equal(t, []ports.DocumentPage{
+			{Number: 1, Text: "Aragorn is a ranger.\nHe walks north.\nCafé (old)"},
+			{Number: 3, Text: "Gimli is a dwarf."},
+		}, pages, "pages without text are left out")
+	})
+
+	t.Run("ToUnicode fonts", func(t *testing.T) {
+		cmap := "/CIDInit /ProcSet findresource begin begincmap\n" +
+			"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+			"1 beginbfchar <0003> <0020> endbfchar\n" +
+			"1 beginbfrange <0010> <0012> <00C9> endbfrange\n" +
+			"endcmap end"
+		data := buildPDF("",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+			flateStream("", "BT /F1 10 Tf <001000030011> Tj ET"),
+			"<< /Type /Font /Subtype /Type0 /ToUnicode 6 0 R >>",
+			flateStream("", cmap),
+		)
+
+		pages, err := read(data)
+		require.NoError(t, err)
+		assert.Equal(t, []ports.DocumentPage{{Number: 1, Text: "É Ê"}}, pages)
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		password := "<" + strings.Repeat("ab", 32) + ">"
+		data := buildPDF("/Encrypt 3 0 R /ID [<0123456789abcdef> <0123456789abcdef>]",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [] /Count 0 >>",
+			"<< /Filter /Standard /V 2 /R 3 /Length 128 /P -4 /O "+password+" /U "+password+" >>",
+		)
+
+		_, err := read(data)
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "encrypted")
+	})
+
+	t.Run("no pages", func(t *testing.T) {
+		_, err := read(buildPDF("",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [2 0 R] /Count 1 >>",
+		))
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "no pages")
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, data := range []string{
+			"%PDF-1.7\ngarbage",
+			"%PDF-1.7\n1 0 obj <<[<",
+			"%PDF-1.7\nstartxref\n999999\n%%EOF\n",
+		} {
+			_, err := read([]byte(data))
+			require.ErrorIs(t, err, entities.ErrValidation, "%q", data)
+		}
+	})
+
+	t.Run("expands past the budget", func(t *testing.T) {
+		data := buildPDF("",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+			flateStream("", strings.Repeat("BT (x) Tj ET ", 1000)),
+		)
+		_, err := pdfPages(bytes.NewReader(data), int64(len(data)), 1000)
+		require.ErrorIs(t, err, entities.ErrValidation)
+	})
+}
+
+func TestReader_DOCX(t *testing.T) {
+	t.Run("one page", func(t *testing.T) {
+		data := buildDOCX(t, `<w:p><w:r><w:t>Frodo</w:t></w:r><w:r><w:t xml:space="preserve"> left the Shire.</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>Sam</w:t><w:tab/><w:t>followed.</w:t></w:r></w:p>`)
+
+		pages, err := read(data)
+		require.NoError(t, err)
+		assert.Equal(t, []ports.DocumentPage{{Text: "Frodo left the Shire.\n\nSam\tfollowed."}}, pages)
+	})
+
+	t.Run("page breaks", func(t *testing.T) {
+		data := buildDOCX(t, `<w:p><w:r><w:t>Chapter one.</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`+
+			`<w:p><w:r><w:lastRenderedPageBreak/><w:t>Chapter two.</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t>More of it.</w:t></w:r><w:r><w:lastRenderedPageBreak/><w:t>Chapter three.</w:t></w:r></w:p>`)
+
+		pages, err := read(data)
+		require.NoError(t, err)
+		assert.Equal(t, []ports.DocumentPage{
+			{Number: 1, Text: "Chapter one."},
+			{Number: 2, Text: "Chapter two.\n\nMore of it."},
+			{Number: 3, Text: "Chapter three."},
+		}, pages)
+	})
+
+	t.Run("other zip packages", func(t *testing.T) {
+		var b bytes.Buffer
+		w := zip.NewWriter(&b)
+		_, err := w.Create("xl/workbook.xml")
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		_, err = read(b.Bytes())
+		require.ErrorIs(t, err, entities.ErrValidation)
+		assert.Contains(t, err.Error(), "word/document.xml")
+	})
+}
+
+func TestReader_NotADocument(t *testing.T) {
+	_, err := read([]byte("Just some text."))
+	require.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func FuzzReader(f *testing.F) {
+	f.Add([]byte("%PDF-1.7\n1 0 obj <<[<"))
+	f.Add(buildPDF("",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		flateStream("", "BT (Frodo left the Shire.) Tj ET"),
+	))
+	f.Add(buildPDF("",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [2 0 R 3 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		"<< /Length 9 >>\nstream\nBT (x) Tj\nendstream",
+	))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Malformed documents are refused, never crash the reader
+		pages, err := read(data)
+		if err != nil {
+			assert.Empty(t, pages)
+		}
+	})
+}
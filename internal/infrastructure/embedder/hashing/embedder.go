@@ -0,0 +1,97 @@
+// Package hashing provides an Embedder that needs no model or API key. It
+// hashes words and character trigrams into a fixed number of dimensions,
+// so texts sharing words or spellings embed close together. It has no
+// sense of meaning and is meant for demos and tests; it backs the fake
+// embedding provider.
+package hashing
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// DefaultDimensions is the vector size used when none is given.
+const DefaultDimensions = 256
+
+// trigramWeight scales character trigrams relative to whole words, so
+// exact words dominate while near spellings still score.
+const trigramWeight = 0.5
+
+// Embedder implements the Embedder interface with feature hashing.
+type Embedder struct {
+	dimensions int
+}
+
+// NewEmbedder creates a hashing embedder producing vectors of the given
+// size, or DefaultDimensions if size is not positive.
+func NewEmbedder(dimensions int) *Embedder {
+	if dimensions <= 0 {
+		dimensions = DefaultDimensions
+	}
+	return &Embedder{dimensions: dimensions}
+}
+
+// Dimensions returns the size of the vectors the embedder returns.
+func (e *Embedder) Dimensions() int {
+	return e.dimensions
+}
+
+// Embed returns the unit-length hashed feature vector of text. Text with
+// no letters or digits embeds as the zero vector.
+func (e *Embedder) Embed(_ context.Context, text string) ([]float32, error) {
+	vector := make([]float64, e.dimensions)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		e.add(vector, word, 1)
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			e.add(vector, string(padded[i:i+3]), trigramWeight)
+		}
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	result := make([]float32, e.dimensions)
+	if norm == 0 {
+		return result, nil
+	}
+	for i, v := range vector {
+		result[i] = float32(v / norm)
+	}
+	return result, nil
+}
+
+// EmbedBatch embeds each text in turn.
+func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		//nolint:loopcall // Hashing is local, so there is no round trip to save
+		embedding, err := e.Embed(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		embeddings[i] = embedding
+	}
+	return embeddings, nil
+}
+
+// add hashes feature to a dimension and a sign, which keeps collisions
+// from only ever adding up.
+func (e *Embedder) add(vector []float64, feature string, weight float64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(feature)) // hash.Hash never returns an error
+	sum := h.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(len(vector))] += weight
+}
@@ -0,0 +1,48 @@
+package hashing
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cosine(a, b []float32) float64 {
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+func TestEmbedder_Embed(t *testing.T) {
+	ctx := context.Background()
+	e := NewEmbedder(0)
+	assert.Equal(t, DefaultDimensions, e.Dimensions())
+
+	vectors, err := e.EmbedBatch(ctx, []string{
+		"Frodo carries the One Ring",
+		"frodo carries the ring!",
+		"Gandalf rides Shadowfax",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+
+	var norm float64
+	for _, v := range vectors[0] {
+		norm += float64(v) * float64(v)
+	}
+	assert.InDelta(t, 1, math.Sqrt(norm), 1e-6, "unit length")
+	assert.Greater(t, cosine(vectors[0], vectors[1]), cosine(vectors[0], vectors[2]), "shared words embed closer")
+
+	again, err := e.Embed(ctx, "Frodo carries the One Ring")
+	require.NoError(t, err)
+	assert.Equal(t, vectors[0], again, "deterministic")
+
+	empty, err := e.Embed(ctx, "  ...  ")
+	require.NoError(t, err)
+	assert.Len(t, empty, DefaultDimensions)
+	assert.Zero(t, cosine(empty, empty))
+}
@@ -5,40 +5,71 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 
 	"github.com/sashabaranov/go-openai"
 
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/openaierr"
 )
 
-// VectorSize is the dimension of text-embedding-3-small vectors.
-const VectorSize = 1536
+// VectorSize is the dimension of the vectors the embedder returns.
+const VectorSize = config.EmbeddingVectorSize
 
 // Embedder implements the Embedder interface using OpenAI.
 type Embedder struct {
-	client *openai.Client
-	model  openai.EmbeddingModel
+	client     *openai.Client
+	model      openai.EmbeddingModel
+	dimensions int // Requested vector size; zero for the model's native size
 }
 
 // NewEmbedder creates a new OpenAI embedder.
 func NewEmbedder(cfg config.EmbedderConfig) (*Embedder, error) {
+	return NewEmbedderWithHTTPClient(cfg, nil)
+}
+
+// NewEmbedderWithHTTPClient creates an OpenAI embedder that sends requests
+// with httpClient, such as one that records or replays them. A nil client
+// uses the default.
+func NewEmbedderWithHTTPClient(cfg config.EmbedderConfig, httpClient *http.Client) (*Embedder, error) {
 	if cfg.APIKey == "" {
 		return nil, errors.New("OpenAI API key is required")
 	}
 
-	client := openai.NewClient(cfg.APIKey)
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	if httpClient != nil {
+		clientConfig.HTTPClient = httpClient
+	}
+	client := openai.NewClientWithConfig(clientConfig)
 
 	model := openai.SmallEmbedding3
 	if cfg.Model != "" {
 		model = openai.EmbeddingModel(cfg.Model)
 	}
 
+	// Larger multilingual models such as text-embedding-3-large are
+	// shortened to the size collections store.
+	var dimensions int
+	if config.ShortensEmbeddings(string(model)) {
+		dimensions = VectorSize
+	}
+
 	return &Embedder{
-		client: client,
-		model:  model,
+		client:     client,
+		model:      model,
+		dimensions: dimensions,
 	}, nil
 }
 
+// Ping checks that OpenAI accepts the API key and serves the model.
+func (e *Embedder) Ping(ctx context.Context) error {
+	if _, err := e.client.GetModel(ctx, string(e.model)); err != nil {
+		return fmt.Errorf("checking OpenAI model %s: %w", e.model, openaierr.Classify(err))
+	}
+	return nil
+}
+
 // Embed generates a vector embedding for the given text.
 func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := e.EmbedBatch(ctx, []string{text})
@@ -60,12 +91,19 @@ func (e *Embedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32,
 	}
 
 	resp, err := e.client.CreateEmbeddings(ctx, openai.EmbeddingRequest{
-		Model: e.model,
-		Input: texts,
+		Model:      e.model,
+		Input:      texts,
+		Dimensions: e.dimensions,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("creating embeddings: %w", err)
+		return nil, fmt.Errorf("creating embeddings: %w", openaierr.Classify(err))
 	}
+	// Best effort: the call has been paid for either way.
+	_ = ports.RecordUsage(context.WithoutCancel(ctx), ports.TokenUsage{
+		Backend:      "embedder",
+		Model:        string(e.model),
+		PromptTokens: resp.Usage.PromptTokens,
+	})
 
 	embeddings := make([][]float32, len(resp.Data))
 	for i, data := range resp.Data {
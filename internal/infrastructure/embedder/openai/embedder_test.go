@@ -59,3 +59,22 @@ func TestVectorSize(t *testing.T) {
 	// Verify the constant matches OpenAI's text-embedding-3-small dimension
 	assert.Equal(t, 1536, VectorSize)
 }
+
+func TestNewEmbedder_Dimensions(t *testing.T) {
+	tests := []struct {
+		model string
+		want  int
+	}{
+		{model: "", want: VectorSize},
+		{model: "text-embedding-3-large", want: VectorSize},
+		{model: "text-embedding-ada-002", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			embedder, err := NewEmbedder(config.EmbedderConfig{APIKey: "test-key", Model: tt.model})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, embedder.dimensions)
+		})
+	}
+}
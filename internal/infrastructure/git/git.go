@@ -0,0 +1,168 @@
+// Package git reads which files of a repository changed since a revision,
+// so only those need ingesting again.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// ChangeStatus is how a file changed.
+type ChangeStatus string
+
+// Change statuses.
+const (
+	Added    ChangeStatus = "added"
+	Modified ChangeStatus = "modified"
+	Deleted  ChangeStatus = "deleted"
+	Renamed  ChangeStatus = "renamed"
+)
+
+// Change is a file that changed since a revision. Paths are absolute.
+type Change struct {
+	Status  ChangeStatus
+	Path    string // The file's path now, or where it was if deleted
+	OldPath string // Where a renamed file was
+}
+
+// ChangedFiles returns the files of the repository containing dir that
+// differ between rev and the working tree, including staged and unstaged
+// changes. Untracked files are not included.
+func ChangedFiles(ctx context.Context, dir, rev string) ([]Change, error) {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return nil, entities.Errorf(entities.ErrValidation, "invalid revision %q", rev)
+	}
+
+	if _, err := run(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, entities.Errorf(entities.ErrValidation, "unknown revision %q", rev)
+	}
+	return diff(ctx, dir, rev)
+}
+
+// StagedFiles returns the files of the repository containing dir whose
+// staged contents differ from the last commit: the changes a commit would
+// make now.
+func StagedFiles(ctx context.Context, dir string) ([]Change, error) {
+	return diff(ctx, dir, "--cached")
+}
+
+// Staged returns the contents of a file as staged in the index, which may
+// differ from the file on disk.
+func Staged(ctx context.Context, path string) ([]byte, error) {
+	// ":./name" is name in the index, relative to the directory git runs in
+	return run(ctx, filepath.Dir(path), "show", ":./"+filepath.ToSlash(filepath.Base(path)))
+}
+
+// HooksDir returns the directory git runs the hooks of the repository
+// containing dir from, honoring core.hooksPath.
+func HooksDir(ctx context.Context, dir string) (string, error) {
+	root, err := topLevel(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	out, err := run(ctx, root, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	// Relative to the top, unless core.hooksPath or the git directory is
+	// absolute
+	hooks := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(root, hooks)
+	}
+	return hooks, nil
+}
+
+// diff returns the changes git diff reports with args, run from the top of
+// the repository containing dir.
+func diff(ctx context.Context, dir string, args ...string) ([]Change, error) {
+	root, err := topLevel(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	args = append([]string{"diff", "--name-status", "-z", "-M", "--no-ext-diff"}, args...)
+	out, err := run(ctx, root, append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(root, out)
+}
+
+// parseNameStatus parses the NUL-separated output of git diff --name-status
+// -z, resolving its paths against root.
+func parseNameStatus(root string, out []byte) ([]Change, error) {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return nil, nil
+	}
+
+	var changes []Change
+	for i := 0; i < len(fields); i++ {
+		status := fields[i]
+		if status == "" || i+1 >= len(fields) {
+			return nil, fmt.Errorf("parsing git diff: unexpected %q", status)
+		}
+		i++
+		path := filepath.Join(root, filepath.FromSlash(fields[i]))
+
+		// Renames and copies name the original, then the new file
+		var newPath string
+		if status[0] == 'R' || status[0] == 'C' {
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("parsing git diff: %s has no destination", path)
+			}
+			i++
+			newPath = filepath.Join(root, filepath.FromSlash(fields[i]))
+		}
+
+		switch status[0] {
+		case 'A':
+			changes = append(changes, Change{Status: Added, Path: path})
+		case 'C':
+			changes = append(changes, Change{Status: Added, Path: newPath})
+		case 'M', 'T':
+			changes = append(changes, Change{Status: Modified, Path: path})
+		case 'D':
+			changes = append(changes, Change{Status: Deleted, Path: path})
+		case 'R':
+			changes = append(changes, Change{Status: Renamed, Path: newPath, OldPath: path})
+		}
+		// Unmerged entries have no content to ingest yet
+	}
+	return changes, nil
+}
+
+// topLevel returns the top directory of the repository containing dir.
+func topLevel(ctx context.Context, dir string) (string, error) {
+	out, err := run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// run runs git in dir, returning its output.
+func run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, entities.Errorf(entities.ErrValidation, "git is not installed")
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
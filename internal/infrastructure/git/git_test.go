@@ -0,0 +1,135 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	out := "M\x00book/one.txt\x00A\x00two.txt\x00D\x00old.txt\x00R087\x00a.txt\x00b.txt\x00C100\x00c.txt\x00d.txt\x00U\x00e.txt\x00"
+
+	changes, err := parseNameStatus("/repo", []byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Status: Modified, Path: filepath.FromSlash("/repo/book/one.txt")},
+		{Status: Added, Path: filepath.FromSlash("/repo/two.txt")},
+		{Status: Deleted, Path: filepath.FromSlash("/repo/old.txt")},
+		{Status: Renamed, Path: filepath.FromSlash("/repo/b.txt"), OldPath: filepath.FromSlash("/repo/a.txt")},
+		{Status: Added, Path: filepath.FromSlash("/repo/d.txt")},
+	}, changes, "unmerged files are skipped")
+
+	changes, err = parseNameStatus("/repo", nil)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = parseNameStatus("/repo", []byte("R100\x00a.txt\x00"))
+	assert.Error(t, err)
+}
+
+// testRepo is a git repository in a temporary directory.
+type testRepo struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	r := &testRepo{t: t, dir: t.TempDir()}
+	r.git("init", "-q")
+	return r
+}
+
+func (r *testRepo) git(args ...string) {
+	r.t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", r.dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+	out, err := cmd.CombinedOutput()
+	require.NoError(r.t, err, string(out))
+}
+
+func (r *testRepo) write(name, content string) {
+	r.t.Helper()
+	require.NoError(r.t, os.MkdirAll(filepath.Dir(filepath.Join(r.dir, name)), 0o755))
+	require.NoError(r.t, os.WriteFile(filepath.Join(r.dir, name), []byte(content), 0o600))
+}
+
+func TestChangedFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	dir, gitCmd, write := repo.dir, repo.git, repo.write
+
+	write("one.txt", "Frodo lived in Bag End.\n")
+	write("two.txt", "Sam was a gardener.\n")
+	write("three.txt", "Merry rode to Buckland with his friends, and did not come back for a long while.\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "-q", "-m", "first")
+
+	write("one.txt", "Frodo lived in Crickhollow.\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "two.txt")))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "book"), 0o755))
+	gitCmd("mv", "three.txt", "book/three.txt")
+	write("untracked.txt", "Pippin\n")
+
+	changes, err := ChangedFiles(t.Context(), filepath.Join(dir, "book"), "HEAD")
+	require.NoError(t, err)
+
+	root, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []Change{
+		{Status: Modified, Path: filepath.Join(root, "one.txt")},
+		{Status: Deleted, Path: filepath.Join(root, "two.txt")},
+		{Status: Renamed, Path: filepath.Join(root, "book", "three.txt"), OldPath: filepath.Join(root, "three.txt")},
+	}, changes, "the whole repository is compared, whichever directory is given")
+
+	_, err = ChangedFiles(t.Context(), dir, "no-such-rev")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+	_, err = ChangedFiles(t.Context(), dir, "--output=x")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestStagedFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.write("one.txt", "Frodo lived in Bag End.\n")
+	repo.write("two.txt", "Sam was a gardener.\n")
+	repo.git("add", ".")
+	repo.git("commit", "-q", "-m", "first")
+
+	repo.write("one.txt", "Frodo lived in Crickhollow.\n")
+	repo.git("add", "one.txt")
+	repo.write("one.txt", "Frodo lived in Rivendell.\n")
+	repo.write("two.txt", "Sam was a mayor.\n")
+
+	changes, err := StagedFiles(t.Context(), repo.dir)
+	require.NoError(t, err)
+	root, err := filepath.EvalSymlinks(repo.dir)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{{Status: Modified, Path: filepath.Join(root, "one.txt")}}, changes, "unstaged edits are left out")
+
+	staged, err := Staged(t.Context(), changes[0].Path)
+	require.NoError(t, err)
+	assert.Equal(t, "Frodo lived in Crickhollow.\n", string(staged))
+}
+
+func TestHooksDir(t *testing.T) {
+	repo := newTestRepo(t)
+	root, err := filepath.EvalSymlinks(repo.dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo.dir, "book"), 0o755))
+	hooks, err := HooksDir(t.Context(), filepath.Join(repo.dir, "book"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".git", "hooks"), hooks)
+
+	repo.git("config", "core.hooksPath", ".githooks")
+	hooks, err = HooksDir(t.Context(), filepath.Join(repo.dir, "book"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".githooks"), hooks)
+}
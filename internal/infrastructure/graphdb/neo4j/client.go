@@ -0,0 +1,236 @@
+package neo4j
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// defaultDatabase is the database used when none is configured.
+const defaultDatabase = "neo4j"
+
+// Client implements the GraphDB interface using Neo4j's HTTP transaction
+// API, so no driver is needed.
+type Client struct {
+	http     *http.Client
+	endpoint string // Commit endpoint of the configured database
+	username string
+	password string
+}
+
+// NewClient creates a new Neo4j client.
+func NewClient(cfg config.GraphConfig) (*Client, error) {
+	base, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing neo4j url: %w", err)
+	}
+	database := cfg.Database
+	if database == "" {
+		database = defaultDatabase
+	}
+
+	return &Client{
+		http:     &http.Client{Timeout: cfg.Timeout},
+		endpoint: base.JoinPath("db", database, "tx", "commit").String(),
+		username: cfg.Username,
+		password: cfg.Password,
+	}, nil
+}
+
+// Close releases idle connections.
+func (c *Client) Close() error {
+	c.http.CloseIdleConnections()
+	return nil
+}
+
+// SyncGraph makes the world's graph match the given entities and
+// relationships in a single transaction, removing entities and
+// relationships the graph has that are not given.
+func (c *Client) SyncGraph(ctx context.Context, worldID string, ents []*entities.Entity, rels []entities.Relationship) error {
+	// Schema changes cannot share a transaction with data changes.
+	if _, err := c.run(ctx, false, []statement{{Statement: constraintStatement}}); err != nil {
+		return fmt.Errorf("creating entity constraint: %w", err)
+	}
+
+	entityIDs := make([]string, len(ents))
+	entityParams := make([]map[string]any, len(ents))
+	for i, e := range ents {
+		entityIDs[i] = e.ID
+		entityParams[i] = map[string]any{"id": e.ID, "name": e.Name, "normalized_name": e.NormalizedName}
+	}
+
+	// Relationships are kept by ID and type, so one whose type changed is
+	// replaced rather than duplicated.
+	relKeys := make([]string, len(rels))
+	byType := make(map[string][]map[string]any)
+	for i := range rels {
+		r := &rels[i]
+		relType := relationshipType(r.Type)
+		relKeys[i] = r.ID + " " + relType
+		byType[relType] = append(byType[relType], map[string]any{
+			"id":            r.ID,
+			"source":        r.SourceEntityID,
+			"target":        r.TargetEntityID,
+			"bidirectional": r.Bidirectional,
+		})
+	}
+
+	statements := []statement{
+		{
+			Statement:  "MATCH (e:" + entityLabel + " {world: $world}) WHERE NOT e.id IN $ids DETACH DELETE e",
+			Parameters: map[string]any{"world": worldID, "ids": entityIDs},
+		},
+		{
+			Statement: "UNWIND $entities AS e MERGE (n:" + entityLabel + " {id: e.id}) " +
+				"SET n.world = $world, n.name = e.name, n.normalized_name = e.normalized_name",
+			Parameters: map[string]any{"world": worldID, "entities": entityParams},
+		},
+		{
+			Statement:  "MATCH (:" + entityLabel + " {world: $world})-[r]->() WHERE NOT r.id + ' ' + type(r) IN $keys DELETE r",
+			Parameters: map[string]any{"world": worldID, "keys": relKeys},
+		},
+	}
+
+	// Relationship types cannot be parameters, so each type gets a statement.
+	types := make([]string, 0, len(byType))
+	for relType := range byType {
+		types = append(types, relType)
+	}
+	sort.Strings(types)
+	for _, relType := range types {
+		statements = append(statements, statement{
+			Statement: "UNWIND $rels AS r MATCH (a:" + entityLabel + " {id: r.source}), (b:" + entityLabel + " {id: r.target}) " +
+				"MERGE (a)-[x:" + relType + " {id: r.id}]->(b) SET x.bidirectional = r.bidirectional",
+			Parameters: map[string]any{"rels": byType[relType]},
+		})
+	}
+
+	if _, err := c.run(ctx, false, statements); err != nil {
+		return fmt.Errorf("syncing graph: %w", err)
+	}
+	return nil
+}
+
+// Query runs a read-only Cypher query and returns its rows, keyed by column.
+func (c *Client) Query(ctx context.Context, query string, params map[string]any) ([]map[string]any, error) {
+	results, err := c.run(ctx, true, []statement{{Statement: query, Parameters: params}})
+	if err != nil {
+		return nil, fmt.Errorf("running query: %w", err)
+	}
+
+	var rows []map[string]any
+	for _, res := range results {
+		for _, d := range res.Data {
+			row := make(map[string]any, len(res.Columns))
+			for i, col := range res.Columns {
+				if i < len(d.Row) {
+					row[col] = d.Row[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// statement is a Cypher statement in the HTTP API's request format.
+type statement struct {
+	Statement  string         `json:"statement"`
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// result is one statement's result in the HTTP API's response format.
+type result struct {
+	Columns []string `json:"columns"`
+	Data    []struct {
+		Row []any `json:"row"`
+	} `json:"data"`
+}
+
+// apiError is an error reported by the HTTP API.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// run commits the statements in one transaction. Read-only transactions
+// are refused any writes by the server.
+func (c *Client) run(ctx context.Context, readOnly bool, statements []statement) ([]result, error) {
+	body, err := json.Marshal(map[string]any{"statements": statements})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling statements: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if readOnly {
+		req.Header.Set("access-mode", "READ")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, entities.WithKind(entities.ErrBackendUnavailable, fmt.Errorf("calling neo4j: %w", err))
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, entities.WithKind(entities.ErrBackendUnavailable, fmt.Errorf("neo4j returned %s", resp.Status))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("neo4j returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var parsed struct {
+		Results []result   `json:"results"`
+		Errors  []apiError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	if len(parsed.Errors) > 0 {
+		return nil, classifyError(parsed.Errors)
+	}
+	return parsed.Results, nil
+}
+
+// classifyError joins the API's errors, marking mistakes in the query as
+// entities.ErrValidation and transient failures as
+// entities.ErrBackendUnavailable.
+func classifyError(apiErrors []apiError) error {
+	errs := make([]error, len(apiErrors))
+	for i, e := range apiErrors {
+		errs[i] = fmt.Errorf("%s: %s", e.Code, e.Message)
+	}
+	err := errors.Join(errs...)
+
+	code := apiErrors[0].Code
+	switch {
+	case strings.HasPrefix(code, "Neo.ClientError.Statement."):
+		return entities.WithKind(entities.ErrValidation, err)
+	case strings.HasPrefix(code, "Neo.TransientError."):
+		return entities.WithKind(entities.ErrBackendUnavailable, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,145 @@
+package neo4j
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// fakeServer records requests and replies with a fixed body and status.
+type fakeServer struct {
+	status   int
+	body     string
+	requests []*http.Request
+	payloads []map[string][]statement
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload map[string][]statement
+	_ = json.NewDecoder(r.Body).Decode(&payload)
+	f.requests = append(f.requests, r)
+	f.payloads = append(f.payloads, payload)
+
+	if f.status != 0 {
+		w.WriteHeader(f.status)
+	}
+	_, _ = w.Write([]byte(f.body))
+}
+
+func newTestClient(t *testing.T, f *fakeServer) *Client {
+	t.Helper()
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(config.GraphConfig{
+		Provider: config.ProviderNeo4j,
+		URL:      server.URL,
+		Username: "neo4j",
+		Password: "secret",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestClient_Query(t *testing.T) {
+	f := &fakeServer{body: `{"results":[{"columns":["name","degree"],"data":[{"row":["Jean",2]},{"row":["Paris",1]}]}],"errors":[]}`}
+	client := newTestClient(t, f)
+
+	rows, err := client.Query(context.Background(), "MATCH (e:Entity) RETURN e.name AS name, COUNT { (e)--() } AS degree", map[string]any{"x": 1})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"name": "Jean", "degree": float64(2)},
+		{"name": "Paris", "degree": float64(1)},
+	}, rows)
+
+	require.Len(t, f.requests, 1)
+	req := f.requests[0]
+	assert.Equal(t, "/db/neo4j/tx/commit", req.URL.Path)
+	assert.Equal(t, "READ", req.Header.Get("access-mode"))
+	user, password, ok := req.BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "neo4j", user)
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, float64(1), f.payloads[0]["statements"][0].Parameters["x"])
+}
+
+func TestClient_SyncGraph(t *testing.T) {
+	f := &fakeServer{body: `{"results":[],"errors":[]}`}
+	client := newTestClient(t, f)
+
+	ents := []*entities.Entity{
+		{ID: "e1", Name: "Jean", NormalizedName: "jean"},
+		{ID: "e2", Name: "Paris", NormalizedName: "paris"},
+	}
+	rels := []entities.Relationship{
+		{ID: "r2", SourceEntityID: "e2", TargetEntityID: "e1", Type: "ruled_by"},
+		{ID: "r1", SourceEntityID: "e1", TargetEntityID: "e2", Type: "located_in"},
+	}
+	require.NoError(t, client.SyncGraph(context.Background(), "w", ents, rels))
+
+	require.Len(t, f.requests, 2, "constraint in its own transaction")
+	assert.Equal(t, constraintStatement, f.payloads[0]["statements"][0].Statement)
+	assert.Empty(t, f.requests[1].Header.Get("access-mode"))
+
+	statements := f.payloads[1]["statements"]
+	require.Len(t, statements, 5, "delete entities, merge entities, delete relationships, one merge per type")
+	assert.Equal(t, []any{"e1", "e2"}, statements[0].Parameters["ids"])
+	assert.Equal(t, []any{"r2 RULED_BY", "r1 LOCATED_IN"}, statements[2].Parameters["keys"])
+	assert.Contains(t, statements[3].Statement, ":LOCATED_IN ")
+	assert.Contains(t, statements[4].Statement, ":RULED_BY ")
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  *fakeServer
+		wantErr string
+		kind    error
+	}{
+		{
+			name:    "syntax error",
+			server:  &fakeServer{body: `{"results":[],"errors":[{"code":"Neo.ClientError.Statement.SyntaxError","message":"Invalid input"}]}`},
+			wantErr: "Invalid input",
+			kind:    entities.ErrValidation,
+		},
+		{
+			name:    "transient error",
+			server:  &fakeServer{body: `{"results":[],"errors":[{"code":"Neo.TransientError.General.DatabaseUnavailable","message":"unavailable"}]}`},
+			wantErr: "unavailable",
+			kind:    entities.ErrBackendUnavailable,
+		},
+		{
+			name:    "server error",
+			server:  &fakeServer{status: http.StatusServiceUnavailable},
+			wantErr: "503",
+			kind:    entities.ErrBackendUnavailable,
+		},
+		{
+			name:    "unauthorized",
+			server:  &fakeServer{status: http.StatusUnauthorized, body: `{"errors":[{"code":"Neo.ClientError.Security.Unauthorized"}]}`},
+			wantErr: "401",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.server)
+
+			_, err := client.Query(context.Background(), "RETURN 1", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			if tt.kind != nil {
+				assert.ErrorIs(t, err, tt.kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Package neo4j provides a GraphDB implementation using Neo4j's HTTP API,
+// and writes worlds as Cypher scripts that any Neo4j installation can load.
+package neo4j
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// entityLabel is the node label of every entity.
+const entityLabel = "Entity"
+
+// constraintStatement makes entity IDs unique, which also indexes them for
+// the lookups relationships are created with.
+const constraintStatement = "CREATE CONSTRAINT lore_entity_id IF NOT EXISTS FOR (e:" + entityLabel + ") REQUIRE e.id IS UNIQUE"
+
+// relationshipType returns the Cypher relationship type for a lore
+// relationship type: upper case, with anything but letters, digits, and
+// underscores replaced, e.g. located_in becomes LOCATED_IN.
+func relationshipType(relType entities.RelationType) string {
+	label := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, string(relType))
+	if label == "" {
+		return "RELATED_TO"
+	}
+	return label
+}
+
+// WriteScript writes a Cypher script that creates or updates the world's
+// entities and relationships. Statements use MERGE on IDs, so the script
+// can be loaded again after the world changes, e.g. with
+// cypher-shell -f world.cypher.
+func WriteScript(w io.Writer, worldID string, ents []*entities.Entity, rels []entities.Relationship) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// Lore world %s: %d entities, %d relationships.\n", worldID, len(ents), len(rels))
+	b.WriteString(constraintStatement + ";\n")
+
+	if len(ents) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range ents {
+		fmt.Fprintf(&b, "MERGE (e:%s {id: %s}) SET e.world = %s, e.name = %s, e.normalized_name = %s;\n",
+			entityLabel, cypherString(e.ID), cypherString(worldID), cypherString(e.Name), cypherString(e.NormalizedName))
+	}
+
+	if len(rels) > 0 {
+		b.WriteString("\n")
+	}
+	for i := range rels {
+		r := &rels[i]
+		fmt.Fprintf(&b, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) MERGE (a)-[r:%s {id: %s}]->(b) SET r.bidirectional = %s;\n",
+			entityLabel, cypherString(r.SourceEntityID), entityLabel, cypherString(r.TargetEntityID),
+			relationshipType(r.Type), cypherString(r.ID), strconv.FormatBool(r.Bidirectional))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// cypherString quotes s as a Cypher string literal.
+func cypherString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
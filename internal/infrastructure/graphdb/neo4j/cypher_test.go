@@ -0,0 +1,56 @@
+package neo4j
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestRelationshipType(t *testing.T) {
+	tests := []struct {
+		relType entities.RelationType
+		want    string
+	}{
+		{relType: "located_in", want: "LOCATED_IN"},
+		{relType: "ally of", want: "ALLY_OF"},
+		{relType: "knows`) DETACH DELETE", want: "KNOWS___DETACH_DELETE"},
+		{relType: "", want: "RELATED_TO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.relType), func(t *testing.T) {
+			assert.Equal(t, tt.want, relationshipType(tt.relType))
+		})
+	}
+}
+
+func TestCypherString(t *testing.T) {
+	assert.Equal(t, `'O\'Brien\\n\n'`, cypherString("O'Brien\\n\n"))
+}
+
+func TestWriteScript(t *testing.T) {
+	ents := []*entities.Entity{
+		{ID: "e1", Name: "Jean", NormalizedName: "jean"},
+		{ID: "e2", Name: "Paris", NormalizedName: "paris"},
+	}
+	rels := []entities.Relationship{
+		{ID: "r1", SourceEntityID: "e1", TargetEntityID: "e2", Type: "located_in", Bidirectional: false},
+	}
+
+	var b strings.Builder
+	require.NoError(t, WriteScript(&b, "w", ents, rels))
+
+	want := `// Lore world w: 2 entities, 1 relationships.
+` + constraintStatement + `;
+
+MERGE (e:Entity {id: 'e1'}) SET e.world = 'w', e.name = 'Jean', e.normalized_name = 'jean';
+MERGE (e:Entity {id: 'e2'}) SET e.world = 'w', e.name = 'Paris', e.normalized_name = 'paris';
+
+MATCH (a:Entity {id: 'e1'}), (b:Entity {id: 'e2'}) MERGE (a)-[r:LOCATED_IN {id: 'r1'}]->(b) SET r.bidirectional = false;
+`
+	assert.Equal(t, want, b.String())
+}
@@ -0,0 +1,259 @@
+// Package fake provides a deterministic LLMClient that needs no network,
+// for integration tests and CI. It extracts facts from simple English
+// sentences by template, such as "Frodo lives in the Shire." or "Gandalf is
+// a wizard.", and ignores sentences it does not recognize.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Confidence is given to every extracted fact.
+const Confidence = 0.9
+
+// maxSubjectWords skips sentences whose subject is too long to be a name.
+const maxSubjectWords = 5
+
+// maxSummaryNames bounds the running summary.
+const maxSummaryNames = 50
+
+// summaryPrefix starts every summary SummarizeChunk returns.
+const summaryPrefix = "Mentioned: "
+
+// template turns sentences matching pattern into facts. The pattern's
+// first group is the subject and its last the object; predicate may use
+// the groups between, such as $2.
+type template struct {
+	pattern   *regexp.Regexp
+	factType  entities.FactType
+	predicate string
+}
+
+func sentence(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)^(.+?) ` + pattern + `$`)
+}
+
+// templates are tried in order; the first match wins.
+var templates = []template{
+	{sentence(`was born in (?:the year )?(\d+)`), entities.FactTypeTimeline, "born_in_year"},
+	{sentence(`died in (?:the year )?(\d+)`), entities.FactTypeTimeline, "died_in_year"},
+	{sentence(`was (founded|built|crowned|destroyed) in (?:the year )?(\d+)`), entities.FactTypeTimeline, "${2}_in_year"},
+	{sentence(`was born in (.+)`), entities.FactTypeCharacter, "born_in"},
+	{sentence(`(?:lives|lived|dwells|dwelt) in (.+)`), entities.FactTypeCharacter, "lives_in"},
+	{sentence(`(?:is|was|lies) (?:located )?in (.+)`), entities.FactTypeLocation, "located_in"},
+	{sentence(`(?:is|was) (?:the |a |an )?(father|mother|son|daughter|brother|sister|husband|wife|friend|ally|enemy|rival|mentor|student|ruler|king|queen) of (.+)`), entities.FactTypeRelationship, "${2}_of"},
+	{sentence(`(must|cannot|can never|may not|may only|can only) (.+)`), entities.FactTypeRule, "$2"},
+	{sentence(`(fought|defeated|killed|met|married|founded|built|destroyed|betrayed|rescued|visited|attacked|discovered) (.+)`), entities.FactTypeEvent, "$2"},
+	{sentence(`(?:is|was) (?:a|an) (.+)`), entities.FactTypeCharacter, "is_a"},
+	{sentence(`(?:has|had) (.+)`), entities.FactTypeCharacter, "has"},
+	{sentence(`(?:is|was|are|were) (.+)`), entities.FactTypeCharacter, "is"},
+}
+
+// focusTypes lists the fact types each focused pass keeps.
+var focusTypes = map[ports.ExtractionFocus][]entities.FactType{
+	ports.FocusEvents:        {entities.FactTypeEvent, entities.FactTypeTimeline},
+	ports.FocusRelationships: {entities.FactTypeRelationship},
+	ports.FocusRules:         {entities.FactTypeRule},
+	ports.FocusCharacters:    {entities.FactTypeCharacter},
+	ports.FocusLocations:     {entities.FactTypeLocation},
+}
+
+// pronouns are the subjects and objects ResolveCoreferences replaces.
+var pronouns = map[string]bool{
+	"he": true, "she": true, "they": true, "it": true,
+	"him": true, "her": true, "them": true,
+}
+
+// reSentenceEnd splits text into sentences.
+var reSentenceEnd = regexp.MustCompile(`[.!?]+(?:\s+|$)|\n+`)
+
+// Client implements the LLMClient interface without a model.
+type Client struct{}
+
+// NewClient creates a new fake LLM client.
+func NewClient() *Client {
+	return &Client{}
+}
+
+// ExtractFacts extracts facts from the sentences of text it recognizes.
+func (c *Client) ExtractFacts(_ context.Context, text string, validTypes []string) ([]entities.Fact, error) {
+	return extract(text, validTypes, nil), nil
+}
+
+// ExtractFactsWithContext extracts facts like ExtractFacts; the prior
+// context is not needed to match templates.
+func (c *Client) ExtractFactsWithContext(ctx context.Context, text string, _ string, validTypes []string) ([]entities.Fact, error) {
+	return c.ExtractFacts(ctx, text, validTypes)
+}
+
+// ExtractFocused extracts only the facts of the kinds the focus is about.
+func (c *Client) ExtractFocused(_ context.Context, text string, _ string, focus ports.ExtractionFocus, validTypes []string) ([]entities.Fact, error) {
+	types, ok := focusTypes[focus]
+	if !ok {
+		return nil, fmt.Errorf("unknown extraction focus %q", focus)
+	}
+	return extract(text, validTypes, types), nil
+}
+
+// extract matches each sentence of text against the templates, keeping
+// facts whose type is valid and, if only is set, listed in it.
+func extract(text string, validTypes []string, only []entities.FactType) []entities.Fact {
+	var facts []entities.Fact
+	for _, s := range splitSentences(text) {
+		fact, ok := match(s)
+		if !ok {
+			continue
+		}
+		if len(validTypes) > 0 && !slices.Contains(validTypes, string(fact.Type)) {
+			continue
+		}
+		if only != nil && !slices.Contains(only, fact.Type) {
+			continue
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, s := range reSentenceEnd.Split(text, -1) {
+		if s = strings.TrimSpace(s); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
+
+func match(s string) (entities.Fact, bool) {
+	for _, t := range templates {
+		m := t.pattern.FindStringSubmatchIndex(s)
+		if m == nil {
+			continue
+		}
+		subject := s[m[2]:m[3]]
+		object := s[m[len(m)-2]:m[len(m)-1]]
+		if len(strings.Fields(subject)) > maxSubjectWords {
+			return entities.Fact{}, false
+		}
+		predicate := string(t.pattern.ExpandString(nil, t.predicate, s, m))
+		return entities.Fact{
+			Type:       t.factType,
+			Subject:    subject,
+			Predicate:  strings.ReplaceAll(strings.ToLower(predicate), " ", "_"),
+			Object:     object,
+			Context:    s + ".",
+			Confidence: Confidence,
+		}, true
+	}
+	return entities.Fact{}, false
+}
+
+// SummarizeChunk adds the subjects of text's facts to the names the
+// summary lists, keeping the most recent.
+func (c *Client) SummarizeChunk(_ context.Context, summary string, text string) (string, error) {
+	var names []string
+	if rest, ok := strings.CutPrefix(summary, summaryPrefix); ok {
+		names = strings.Split(strings.TrimSuffix(rest, "."), ", ")
+	}
+	facts := extract(text, nil, nil)
+	for i := range facts {
+		f := &facts[i]
+		if pronouns[strings.ToLower(f.Subject)] {
+			continue
+		}
+		names = slices.DeleteFunc(names, func(n string) bool { return n == f.Subject })
+		names = append(names, f.Subject)
+	}
+	if len(names) == 0 {
+		return summary, nil
+	}
+	if len(names) > maxSummaryNames {
+		names = names[len(names)-maxSummaryNames:]
+	}
+	return summaryPrefix + strings.Join(names, ", ") + ".", nil
+}
+
+// CheckConsistency reports new facts giving a subject a different value
+// for a predicate than an existing fact does. Events and relationships
+// are not checked, since a subject usually has many.
+func (c *Client) CheckConsistency(_ context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
+	var issues []ports.ConsistencyIssue
+	for i := range newFacts {
+		nf := &newFacts[i]
+		if nf.Type == entities.FactTypeEvent || nf.Type == entities.FactTypeRelationship {
+			continue
+		}
+		for j := range existingFacts {
+			ef := &existingFacts[j]
+			if !strings.EqualFold(nf.Subject, ef.Subject) || nf.Predicate != ef.Predicate || strings.EqualFold(nf.Object, ef.Object) {
+				continue
+			}
+			issues = append(issues, ports.ConsistencyIssue{
+				NewFact:      *nf,
+				ExistingFact: *ef,
+				Description:  fmt.Sprintf("%s %s %s, but it was %s", nf.Subject, nf.Predicate, nf.Object, ef.Object),
+				Severity:     "major",
+			})
+		}
+	}
+	return issues, nil
+}
+
+// ResolveCoreferences replaces pronouns with the most recent subject of
+// an earlier fact that is not itself a pronoun.
+func (c *Client) ResolveCoreferences(_ context.Context, _ string, facts []entities.Fact) ([]ports.CoreferenceResolution, error) {
+	var resolutions []ports.CoreferenceResolution
+	last := ""
+	for i := range facts {
+		f := &facts[i]
+		var r ports.CoreferenceResolution
+		if pronouns[strings.ToLower(f.Subject)] {
+			r.Subject = last
+		} else {
+			last = f.Subject
+		}
+		if pronouns[strings.ToLower(f.Object)] {
+			r.Object = last
+		}
+		if r.Subject != "" || r.Object != "" {
+			r.FactIndex = i
+			resolutions = append(resolutions, r)
+		}
+	}
+	return resolutions, nil
+}
+
+// LabelTopic names the facts' most common predicate.
+func (c *Client) LabelTopic(_ context.Context, facts []entities.Fact) (string, error) {
+	counts := make(map[string]int)
+	best := ""
+	for i := range facts {
+		f := &facts[i]
+		counts[f.Predicate]++
+		if counts[f.Predicate] > counts[best] {
+			best = f.Predicate
+		}
+	}
+	return strings.ReplaceAll(best, "_", " "), nil
+}
+
+// ConfirmTranslations confirms the pairs whose facts have the same subject
+// and object, since names are rarely translated.
+func (c *Client) ConfirmTranslations(_ context.Context, pairs []ports.FactPair) ([]int, error) {
+	var indexes []int
+	for i := range pairs {
+		p := &pairs[i]
+		if strings.EqualFold(p.Fact.Subject, p.Other.Subject) && strings.EqualFold(p.Fact.Object, p.Other.Object) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
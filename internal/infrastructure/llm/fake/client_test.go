@@ -0,0 +1,148 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var allTypes = entities.DefaultTypeNames()
+
+func TestClient_ExtractFacts(t *testing.T) {
+	tests := []struct {
+		text      string
+		factType  entities.FactType
+		subject   string
+		predicate string
+		object    string
+	}{
+		{"Frodo lives in the Shire.", entities.FactTypeCharacter, "Frodo", "lives_in", "the Shire"},
+		{"Gandalf is a wizard.", entities.FactTypeCharacter, "Gandalf", "is_a", "wizard"},
+		{"Bilbo was born in 2890.", entities.FactTypeTimeline, "Bilbo", "born_in_year", "2890"},
+		{"Minas Tirith was founded in the year 3320", entities.FactTypeTimeline, "Minas Tirith", "founded_in_year", "3320"},
+		{"Rivendell lies in Eriador!", entities.FactTypeLocation, "Rivendell", "located_in", "Eriador"},
+		{"Elrond is the father of Arwen.", entities.FactTypeRelationship, "Elrond", "father_of", "Arwen"},
+		{"Wizards cannot die of old age.", entities.FactTypeRule, "Wizards", "cannot", "die of old age"},
+		{"Aragorn defeated the Witch-king.", entities.FactTypeEvent, "Aragorn", "defeated", "the Witch-king"},
+		{"Sam has a rope.", entities.FactTypeCharacter, "Sam", "has", "a rope"},
+		{"Frodo is brave.", entities.FactTypeCharacter, "Frodo", "is", "brave"},
+	}
+
+	client := NewClient()
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			//nolint:loopcall // One call per case
+			facts, err := client.ExtractFacts(context.Background(), tt.text, allTypes)
+			require.NoError(t, err)
+			require.Len(t, facts, 1)
+			assert.Equal(t, tt.factType, facts[0].Type)
+			assert.Equal(t, tt.subject, facts[0].Subject)
+			assert.Equal(t, tt.predicate, facts[0].Predicate)
+			assert.Equal(t, tt.object, facts[0].Object)
+			assert.Equal(t, Confidence, facts[0].Confidence)
+		})
+	}
+}
+
+func TestClient_ExtractFacts_Filters(t *testing.T) {
+	text := "Frodo lives in the Shire. The wind howled all night long over the hills and far away. Frodo met Gandalf."
+	client := NewClient()
+
+	facts, err := client.ExtractFacts(context.Background(), text, allTypes)
+	require.NoError(t, err)
+	assert.Len(t, facts, 2, "unrecognized sentences are skipped")
+
+	facts, err = client.ExtractFacts(context.Background(), text, []string{"event"})
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "met", facts[0].Predicate)
+
+	facts, err = client.ExtractFocused(context.Background(), text, "", ports.FocusCharacters, allTypes)
+	require.NoError(t, err)
+	require.Len(t, facts, 1)
+	assert.Equal(t, "lives_in", facts[0].Predicate)
+
+	_, err = client.ExtractFocused(context.Background(), text, "", "weather", allTypes)
+	assert.Error(t, err)
+}
+
+func TestClient_SummarizeChunk(t *testing.T) {
+	client := NewClient()
+	ctx := context.Background()
+
+	summary, err := client.SummarizeChunk(ctx, "", "Frodo lives in the Shire. Sam is a gardener.")
+	require.NoError(t, err)
+	assert.Equal(t, "Mentioned: Frodo, Sam.", summary)
+
+	summary, err = client.SummarizeChunk(ctx, summary, "Gandalf met Frodo.")
+	require.NoError(t, err)
+	assert.Equal(t, "Mentioned: Frodo, Sam, Gandalf.", summary)
+}
+
+func TestClient_CheckConsistency(t *testing.T) {
+	existing := []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "the Shire"},
+		{Type: entities.FactTypeEvent, Subject: "Frodo", Predicate: "met", Object: "Sam"},
+	}
+	newFacts := []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "frodo", Predicate: "lives_in", Object: "Mordor"},
+		{Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "lives_in", Object: "The Shire"},
+		{Type: entities.FactTypeEvent, Subject: "Frodo", Predicate: "met", Object: "Gandalf"},
+	}
+
+	issues, err := NewClient().CheckConsistency(context.Background(), newFacts, existing)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "Mordor", issues[0].NewFact.Object)
+	assert.Equal(t, "the Shire", issues[0].ExistingFact.Object)
+}
+
+func TestClient_ResolveCoreferences(t *testing.T) {
+	facts := []entities.Fact{
+		{Subject: "He", Predicate: "is", Object: "tired"},
+		{Subject: "Frodo", Predicate: "lives_in", Object: "the Shire"},
+		{Subject: "He", Predicate: "met", Object: "Sam"},
+		{Subject: "Sam", Predicate: "follows", Object: "him"},
+	}
+
+	resolutions, err := NewClient().ResolveCoreferences(context.Background(), "", facts)
+	require.NoError(t, err)
+	assert.Equal(t, []ports.CoreferenceResolution{
+		{FactIndex: 2, Subject: "Frodo"},
+		{FactIndex: 3, Object: "Sam"},
+	}, resolutions)
+}
+
+func TestClient_LabelTopic(t *testing.T) {
+	facts := []entities.Fact{
+		{Predicate: "lives_in"},
+		{Predicate: "is_a"},
+		{Predicate: "lives_in"},
+	}
+
+	label, err := NewClient().LabelTopic(context.Background(), facts)
+	require.NoError(t, err)
+	assert.Equal(t, "lives in", label)
+}
+
+func TestClient_ConfirmTranslations(t *testing.T) {
+	pairs := []ports.FactPair{
+		{
+			Fact:  entities.Fact{Subject: "Jean", Predicate: "vit_à", Object: "Paris"},
+			Other: entities.Fact{Subject: "Jean", Predicate: "lives_in", Object: "Paris"},
+		},
+		{
+			Fact:  entities.Fact{Subject: "Jean", Predicate: "vit_à", Object: "Paris"},
+			Other: entities.Fact{Subject: "Jean", Predicate: "lives_in", Object: "Lyon"},
+		},
+	}
+
+	indexes, err := NewClient().ConfirmTranslations(context.Background(), pairs)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, indexes)
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -14,6 +16,7 @@ import (
 	"github.com/ersonp/lore-core/internal/domain/entities"
 	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/openaierr"
 )
 
 // buildExtractionPrompt creates an extraction prompt with the given valid types.
@@ -39,6 +42,67 @@ Output: [
 ]`, typeList)
 }
 
+// focusInstructions specialize the extraction prompt for each focus pass.
+var focusInstructions = map[ports.ExtractionFocus]string{
+	ports.FocusEvents: `Focus ONLY on events and time: what happened, when, in what order, and
+what caused what. Use predicates like "occurred_in", "happened_before",
+"happened_after", "caused", and "participated_in".`,
+	ports.FocusRelationships: `Focus ONLY on relationships between characters, groups, and places:
+family, alliances, rivalries, loyalties, membership, ownership, and rule.`,
+	ports.FocusRules: `Focus ONLY on the rules of the world: how magic, technology, or nature
+works, laws, customs, taboos, and limits that always hold. Use the world or
+system the rule governs as the subject.`,
+	ports.FocusCharacters: `Focus ONLY on characters: appearance, personality, abilities, titles,
+roles, and backgrounds.`,
+	ports.FocusLocations: `Focus ONLY on places: geography, climate, what lies where, who lives
+or rules there, and notable landmarks.`,
+}
+
+// buildFocusedPrompt creates an extraction prompt for one focus pass.
+// Focused passes are thorough: minor details of the focus kind are wanted.
+func buildFocusedPrompt(focus ports.ExtractionFocus, validTypes []string) (string, error) {
+	instructions, ok := focusInstructions[focus]
+	if !ok {
+		return "", fmt.Errorf("unknown extraction focus %q", focus)
+	}
+	return buildExtractionPrompt(validTypes) + "\n\n" + instructions +
+		"\nBe thorough: include minor details of this kind and skip everything else.", nil
+}
+
+// priorContextInstructions is appended to the extraction prompt when a
+// summary of earlier text is supplied.
+const priorContextInstructions = `
+
+The input may start with a "Story so far" section summarizing earlier text.
+Use it only to work out who or what the text refers to, and name subjects
+accordingly. Do NOT extract facts from the "Story so far" section itself.`
+
+// languageInstructions is appended to extraction prompts when the source
+// material is not written in English.
+const languageInstructions = `
+
+The text is written in %[1]s. Write subjects, objects, and context in %[1]s,
+spelling names exactly as the text does; never translate or transliterate
+names. Write predicates in English snake_case (e.g. "lives_in") so they match
+across languages.`
+
+// maxSummaryWords bounds the running summary carried between chunks.
+const maxSummaryWords = 200
+
+const summaryPrompt = `You maintain a running summary of a story so that later passages can be understood.
+
+Current summary:
+%s
+
+New passage:
+%s
+
+Rewrite the summary to include the characters, places, and events introduced in
+the new passage, keeping who is who clear (e.g. "Aragorn, a ranger also called
+Strider"). Keep it under %d words and drop details that are unlikely to matter later.
+
+Return ONLY the summary text.`
+
 const consistencyPrompt = `Compare these new facts against existing facts. Identify any inconsistencies or contradictions.
 
 New facts:
@@ -47,6 +111,10 @@ New facts:
 Existing facts:
 %s
 
+An existing fact's "sources" is how many source files assert it. A fact asserted by
+several sources is well established; when a new fact contradicts it, the new fact is
+the likely error.
+
 For each inconsistency found, return:
 - new_fact_index: Index of the conflicting new fact (0-based)
 - existing_fact_index: Index of the contradicted existing fact (0-based)
@@ -55,19 +123,78 @@ For each inconsistency found, return:
 
 Return ONLY a valid JSON array, no other text. Return empty array [] if no inconsistencies found.`
 
+const coreferencePrompt = `The facts below were extracted from the passage that follows. Some refer to
+people or things by pronoun ("he", "she", "they", "his sister") instead of by name.
+
+For each fact whose subject or object refers to someone or something named in the
+passage, return:
+- index: Index of the fact (0-based)
+- subject: The name the subject refers to (omit if unchanged)
+- object: The name the object refers to (omit if unchanged)
+
+Keep any words that are not part of the reference, e.g. "his sister" becomes
+"Frodo's sister". Only use names that appear in the passage.
+
+Facts:
+%s
+
+Passage:
+%s
+
+Return ONLY a valid JSON array, no other text. Return empty array [] if nothing can be resolved.`
+
+const topicPrompt = `The facts below about a fictional world were grouped together because they
+are about similar things.
+
+Facts:
+%s
+
+Name the theme they share in at most %d words, e.g. "Elven kingdoms" or
+"Frodo's journey to Mordor". Return ONLY the name, with no quotes or punctuation
+around it.`
+
+// maxTopicWords caps the length of topic labels.
+const maxTopicWords = 6
+
+const translationPrompt = `Each pair below holds two facts about a fictional world. Decide for each pair
+whether the two facts state the same thing in different languages, e.g.
+"Jean vit à Paris" and "Jean lives in Paris". Names may be spelled or
+transliterated differently from one language to the other.
+
+A pair is NOT a translation if both facts are in the same language, or if they
+say different or contradictory things.
+
+Pairs:
+%s
+
+Return ONLY a valid JSON array of the indexes (0-based) of the pairs that are
+translations, e.g. [0, 2], no other text. Return empty array [] if none are.`
+
 // Client implements the LLMClient interface using OpenAI.
 type Client struct {
-	client *openai.Client
-	model  string
+	client   *openai.Client
+	model    string
+	language string // Source language name; empty for English
 }
 
 // NewClient creates a new OpenAI LLM client.
 func NewClient(cfg config.LLMConfig) (*Client, error) {
+	return NewClientWithHTTPClient(cfg, nil)
+}
+
+// NewClientWithHTTPClient creates an OpenAI LLM client that sends requests
+// with httpClient, such as one that records or replays them. A nil client
+// uses the default.
+func NewClientWithHTTPClient(cfg config.LLMConfig, httpClient *http.Client) (*Client, error) {
 	if cfg.APIKey == "" {
 		return nil, errors.New("OpenAI API key is required")
 	}
 
-	client := openai.NewClient(cfg.APIKey)
+	clientConfig := openai.DefaultConfig(cfg.APIKey)
+	if httpClient != nil {
+		clientConfig.HTTPClient = httpClient
+	}
+	client := openai.NewClientWithConfig(clientConfig)
 
 	model := "gpt-4o-mini"
 	if cfg.Model != "" {
@@ -75,16 +202,74 @@ func NewClient(cfg config.LLMConfig) (*Client, error) {
 	}
 
 	return &Client{
-		client: client,
-		model:  model,
+		client:   client,
+		model:    model,
+		language: config.LanguageName(cfg.Language),
 	}, nil
 }
 
+// Ping checks that OpenAI accepts the API key and serves the model.
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.client.GetModel(ctx, c.model); err != nil {
+		return fmt.Errorf("checking OpenAI model %s: %w", c.model, openaierr.Classify(err))
+	}
+	return nil
+}
+
+// complete sends a chat completion request and records the tokens it used
+// with the context's usage meter.
+func (c *Client) complete(ctx context.Context, req *openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	resp, err := c.client.CreateChatCompletion(ctx, *req)
+	if err != nil {
+		return resp, err
+	}
+	// Best effort: the call has been paid for either way.
+	_ = ports.RecordUsage(context.WithoutCancel(ctx), ports.TokenUsage{
+		Backend:          "llm",
+		Model:            c.model,
+		PromptTokens:     resp.Usage.PromptTokens,
+		CompletionTokens: resp.Usage.CompletionTokens,
+	})
+	return resp, nil
+}
+
 // ExtractFacts extracts facts from the given text.
 func (c *Client) ExtractFacts(ctx context.Context, text string, validTypes []string) ([]entities.Fact, error) {
-	prompt := buildExtractionPrompt(validTypes)
+	return c.ExtractFactsWithContext(ctx, text, "", validTypes)
+}
+
+// ExtractFactsWithContext extracts facts from text, using priorContext to
+// resolve references to earlier text.
+func (c *Client) ExtractFactsWithContext(ctx context.Context, text string, priorContext string, validTypes []string) ([]entities.Fact, error) {
+	return c.extract(ctx, c.withLanguage(buildExtractionPrompt(validTypes)), text, priorContext)
+}
+
+// ExtractFocused runs an extraction pass specialized for one kind of fact.
+func (c *Client) ExtractFocused(ctx context.Context, text string, priorContext string, focus ports.ExtractionFocus, validTypes []string) ([]entities.Fact, error) {
+	prompt, err := buildFocusedPrompt(focus, validTypes)
+	if err != nil {
+		return nil, err
+	}
+	return c.extract(ctx, c.withLanguage(prompt), text, priorContext)
+}
+
+// withLanguage adds instructions for the source language to an extraction
+// prompt, if the source material is not in English.
+func (c *Client) withLanguage(prompt string) string {
+	if c.language == "" {
+		return prompt
+	}
+	return prompt + fmt.Sprintf(languageInstructions, c.language)
+}
 
-	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+// extract runs an extraction prompt over text and parses the facts returned.
+func (c *Client) extract(ctx context.Context, prompt string, text string, priorContext string) ([]entities.Fact, error) {
+	if priorContext != "" {
+		prompt += priorContextInstructions
+		text = buildContextualInput(priorContext, text)
+	}
+
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
 		Model: c.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
@@ -99,7 +284,7 @@ func (c *Client) ExtractFacts(ctx context.Context, text string, validTypes []str
 		Temperature: 0.1,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("calling OpenAI: %w", err)
+		return nil, fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
 	}
 
 	if len(resp.Choices) == 0 {
@@ -111,7 +296,7 @@ func (c *Client) ExtractFacts(ctx context.Context, text string, validTypes []str
 
 	var rawFacts []rawFact
 	if err := json.Unmarshal([]byte(content), &rawFacts); err != nil {
-		return nil, fmt.Errorf("parsing facts JSON: %w (response: %s)", err, content)
+		return nil, fmt.Errorf("parsing facts JSON: %w: %w (response: %s)", ports.ErrMalformedResponse, err, content)
 	}
 
 	facts := make([]entities.Fact, 0, len(rawFacts))
@@ -130,6 +315,42 @@ func (c *Client) ExtractFacts(ctx context.Context, text string, validTypes []str
 	return facts, nil
 }
 
+// buildContextualInput prepends a summary of earlier text to the text to extract from.
+func buildContextualInput(priorContext, text string) string {
+	return "Story so far:\n" + priorContext + "\n\nText:\n" + text
+}
+
+// SummarizeChunk folds text into the running summary.
+func (c *Client) SummarizeChunk(ctx context.Context, summary string, text string) (string, error) {
+	if summary == "" {
+		summary = "(empty)"
+	}
+	prompt := fmt.Sprintf(summaryPrompt, summary, text, maxSummaryWords)
+	if c.language != "" {
+		prompt += fmt.Sprintf("\nWrite the summary in %s, spelling names as the passage does.", c.language)
+	}
+
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", errors.New("no response from OpenAI")
+	}
+
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
+
 // CheckConsistency checks if new facts are consistent with existing facts.
 func (c *Client) CheckConsistency(ctx context.Context, newFacts []entities.Fact, existingFacts []entities.Fact) ([]ports.ConsistencyIssue, error) {
 	if len(newFacts) == 0 || len(existingFacts) == 0 {
@@ -148,7 +369,7 @@ func (c *Client) CheckConsistency(ctx context.Context, newFacts []entities.Fact,
 
 	prompt := fmt.Sprintf(consistencyPrompt, string(newFactsJSON), string(existingFactsJSON))
 
-	resp, err := c.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
 		Model: c.model,
 		Messages: []openai.ChatCompletionMessage{
 			{
@@ -159,7 +380,7 @@ func (c *Client) CheckConsistency(ctx context.Context, newFacts []entities.Fact,
 		Temperature: 0.1,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("calling OpenAI: %w", err)
+		return nil, fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
 	}
 
 	if len(resp.Choices) == 0 {
@@ -171,7 +392,7 @@ func (c *Client) CheckConsistency(ctx context.Context, newFacts []entities.Fact,
 
 	var rawIssues []rawConsistencyIssue
 	if err := json.Unmarshal([]byte(content), &rawIssues); err != nil {
-		return nil, fmt.Errorf("parsing consistency JSON: %w (response: %s)", err, content)
+		return nil, fmt.Errorf("parsing consistency JSON: %w: %w (response: %s)", ports.ErrMalformedResponse, err, content)
 	}
 
 	issues := make([]ports.ConsistencyIssue, 0, len(rawIssues))
@@ -192,6 +413,168 @@ func (c *Client) CheckConsistency(ctx context.Context, newFacts []entities.Fact,
 	return issues, nil
 }
 
+// ResolveCoreferences finds the names that pronouns in facts refer to.
+func (c *Client) ResolveCoreferences(ctx context.Context, text string, facts []entities.Fact) ([]ports.CoreferenceResolution, error) {
+	if len(facts) == 0 {
+		return nil, nil
+	}
+
+	factsJSON, err := json.Marshal(factsToRaw(facts))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling facts: %w", err)
+	}
+
+	prompt := fmt.Sprintf(coreferencePrompt, string(factsJSON), text)
+
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("no response from OpenAI")
+	}
+
+	return parseCoreferences(resp.Choices[0].Message.Content, len(facts))
+}
+
+// LabelTopic names the theme the given facts share.
+func (c *Client) LabelTopic(ctx context.Context, facts []entities.Fact) (string, error) {
+	if len(facts) == 0 {
+		return "", nil
+	}
+
+	factsJSON, err := json.Marshal(factsToRaw(facts))
+	if err != nil {
+		return "", fmt.Errorf("marshaling facts: %w", err)
+	}
+
+	prompt := fmt.Sprintf(topicPrompt, string(factsJSON), maxTopicWords)
+
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", errors.New("no response from OpenAI")
+	}
+
+	return cleanTopicLabel(resp.Choices[0].Message.Content), nil
+}
+
+// ConfirmTranslations returns the indexes of the pairs whose facts state
+// the same thing in different languages.
+func (c *Client) ConfirmTranslations(ctx context.Context, pairs []ports.FactPair) ([]int, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	raw := make([]rawFactPair, len(pairs))
+	for i := range pairs {
+		facts := factsToRaw([]entities.Fact{pairs[i].Fact, pairs[i].Other})
+		raw[i] = rawFactPair{Index: i, A: facts[0], B: facts[1]}
+	}
+	pairsJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling fact pairs: %w", err)
+	}
+
+	prompt := fmt.Sprintf(translationPrompt, string(pairsJSON))
+
+	resp, err := c.complete(ctx, &openai.ChatCompletionRequest{
+		Model: c.model,
+		Messages: []openai.ChatCompletionMessage{
+			{
+				Role:    openai.ChatMessageRoleUser,
+				Content: prompt,
+			},
+		},
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling OpenAI: %w", openaierr.Classify(err))
+	}
+
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("no response from OpenAI")
+	}
+
+	return parseTranslationIndexes(resp.Choices[0].Message.Content, len(pairs))
+}
+
+// parseTranslationIndexes parses the translation response, dropping
+// indexes that point outside the pairs and repeats, in ascending order.
+func parseTranslationIndexes(content string, pairCount int) ([]int, error) {
+	content = cleanJSONResponse(content)
+
+	var raw []int
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("parsing translation JSON: %w: %w (response: %s)", ports.ErrMalformedResponse, err, content)
+	}
+
+	slices.Sort(raw)
+	indexes := make([]int, 0, len(raw))
+	for _, i := range slices.Compact(raw) {
+		if i >= 0 && i < pairCount {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+// cleanTopicLabel strips the quotes and trailing punctuation models tend to
+// add around a label.
+func cleanTopicLabel(label string) string {
+	label = strings.TrimSpace(label)
+	label = strings.Trim(label, "\"'`*")
+	label = strings.TrimRight(label, ".")
+	return strings.TrimSpace(label)
+}
+
+// parseCoreferences parses the coreference response, dropping entries that
+// point outside the facts or change nothing.
+func parseCoreferences(content string, factCount int) ([]ports.CoreferenceResolution, error) {
+	content = cleanJSONResponse(content)
+
+	var raw []ports.CoreferenceResolution
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("parsing coreference JSON: %w: %w (response: %s)", ports.ErrMalformedResponse, err, content)
+	}
+
+	resolutions := make([]ports.CoreferenceResolution, 0, len(raw))
+	for _, r := range raw {
+		if r.FactIndex < 0 || r.FactIndex >= factCount {
+			continue
+		}
+		r.Subject = strings.TrimSpace(r.Subject)
+		r.Object = strings.TrimSpace(r.Object)
+		if r.Subject == "" && r.Object == "" {
+			continue
+		}
+		resolutions = append(resolutions, r)
+	}
+	return resolutions, nil
+}
+
 // rawFact is the JSON structure for extracted facts.
 type rawFact struct {
 	Type       string      `json:"type"`
@@ -200,6 +583,7 @@ type rawFact struct {
 	Object     interface{} `json:"object"`
 	Context    string      `json:"context,omitempty"`
 	Confidence float64     `json:"confidence"`
+	Sources    int         `json:"sources,omitempty"` // Set only for corroborated facts
 }
 
 // objectToString converts the object field to string (handles numbers from LLM).
@@ -229,6 +613,13 @@ type rawConsistencyIssue struct {
 	Severity          string `json:"severity"`
 }
 
+// rawFactPair is the JSON structure for a pair of facts to compare.
+type rawFactPair struct {
+	Index int     `json:"index"`
+	A     rawFact `json:"a"`
+	B     rawFact `json:"b"`
+}
+
 // factsToRaw converts entities to raw format for JSON.
 func factsToRaw(facts []entities.Fact) []rawFact {
 	raw := make([]rawFact, 0, len(facts))
@@ -240,11 +631,21 @@ func factsToRaw(facts []entities.Fact) []rawFact {
 			Object:     facts[i].Object,
 			Context:    facts[i].Context,
 			Confidence: facts[i].Confidence,
+			Sources:    corroboratingSources(&facts[i]),
 		})
 	}
 	return raw
 }
 
+// corroboratingSources returns the sources asserting fact, or 0 for a fact
+// asserted by one source alone so the field is omitted.
+func corroboratingSources(fact *entities.Fact) int {
+	if fact.SourceCount() < 2 {
+		return 0
+	}
+	return fact.SourceCount()
+}
+
 // cleanJSONResponse removes markdown code blocks if present.
 func cleanJSONResponse(content string) string {
 	content = strings.TrimSpace(content)
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 )
 
@@ -182,7 +183,146 @@ func TestFactsToRaw(t *testing.T) {
 	assert.Equal(t, "Mordor", raw[1].Subject)
 }
 
+func TestFactsToRaw_Sources(t *testing.T) {
+	raw := factsToRaw([]entities.Fact{
+		{Subject: "Frodo", Corroboration: 3},
+		{Subject: "Sam", Corroboration: 1},
+		{Subject: "Bilbo"},
+	})
+
+	require.Len(t, raw, 3)
+	assert.Equal(t, 3, raw[0].Sources)
+	assert.Zero(t, raw[1].Sources, "single-source facts omit sources")
+	assert.Zero(t, raw[2].Sources)
+}
+
 func TestFactsToRaw_Empty(t *testing.T) {
 	raw := factsToRaw([]entities.Fact{})
 	assert.Empty(t, raw)
 }
+
+func TestParseCoreferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ports.CoreferenceResolution
+		wantErr bool
+	}{
+		{
+			name:    "subject and object",
+			content: `[{"index": 0, "subject": "Frodo"}, {"index": 1, "object": " Sam "}]`,
+			want: []ports.CoreferenceResolution{
+				{FactIndex: 0, Subject: "Frodo"},
+				{FactIndex: 1, Object: "Sam"},
+			},
+		},
+		{
+			name:    "code block",
+			content: "```json\n[{\"index\": 1, \"subject\": \"Gandalf\"}]\n```",
+			want:    []ports.CoreferenceResolution{{FactIndex: 1, Subject: "Gandalf"}},
+		},
+		{
+			name:    "out of range and empty entries dropped",
+			content: `[{"index": 5, "subject": "Frodo"}, {"index": -1, "subject": "Sam"}, {"index": 0}]`,
+			want:    []ports.CoreferenceResolution{},
+		},
+		{
+			name:    "empty array",
+			content: `[]`,
+			want:    []ports.CoreferenceResolution{},
+		},
+		{
+			name:    "invalid JSON",
+			content: `not json`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCoreferences(tt.content, 2)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTranslationIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []int
+		wantErr bool
+	}{
+		{name: "indexes", content: `[2, 0]`, want: []int{0, 2}},
+		{name: "code block", content: "```json\n[1]\n```", want: []int{1}},
+		{name: "out of range and repeats dropped", content: `[3, -1, 1, 1]`, want: []int{1}},
+		{name: "empty array", content: `[]`, want: []int{}},
+		{name: "invalid JSON", content: `yes`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTranslationIndexes(tt.content, 3)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildContextualInput(t *testing.T) {
+	got := buildContextualInput("Strider is Aragorn.", "He drew his sword.")
+	assert.Equal(t, "Story so far:\nStrider is Aragorn.\n\nText:\nHe drew his sword.", got)
+}
+
+func TestBuildFocusedPrompt(t *testing.T) {
+	for _, focus := range ports.ExtractionFocuses {
+		t.Run(string(focus), func(t *testing.T) {
+			prompt, err := buildFocusedPrompt(focus, []string{"character", "event"})
+			require.NoError(t, err)
+			assert.Contains(t, prompt, "character, event")
+			assert.Contains(t, prompt, "Focus ONLY on")
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := buildFocusedPrompt("weather", nil)
+		require.Error(t, err)
+	})
+}
+
+func TestClient_WithLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		want     string
+	}{
+		{name: "English needs no instructions", language: "en"},
+		{name: "unset", language: ""},
+		{name: "code", language: "de", want: "The text is written in German."},
+		{name: "name", language: "Klingon", want: "The text is written in Klingon."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(config.LLMConfig{APIKey: "test-key", Language: tt.language})
+			require.NoError(t, err)
+
+			prompt := client.withLanguage("Extract facts.")
+			if tt.want == "" {
+				assert.Equal(t, "Extract facts.", prompt)
+				return
+			}
+			assert.Contains(t, prompt, tt.want)
+			assert.Contains(t, prompt, "never translate or transliterate")
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package openai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/recording"
+)
+
+// recordedClient returns a client that replays the responses recorded in
+// testdata/recordings. Run with LORE_RECORDING=record and OPENAI_API_KEY
+// set to call OpenAI and record them again.
+func recordedClient(t *testing.T) *Client {
+	t.Helper()
+	mode := recording.ModeReplay
+	key := os.Getenv("OPENAI_API_KEY")
+	if os.Getenv("LORE_RECORDING") == config.RecordingModeRecord {
+		if key == "" {
+			t.Skip("OPENAI_API_KEY is required to record")
+		}
+		mode = recording.ModeRecord
+	} else {
+		key = "replay"
+	}
+
+	transport := recording.NewTransport(mode, filepath.Join("testdata", "recordings"), nil)
+	client, err := NewClientWithHTTPClient(config.LLMConfig{APIKey: key, Model: "gpt-4o-mini"}, transport.Client())
+	require.NoError(t, err)
+	return client
+}
+
+var allTypes = []string{"character", "location", "event", "relationship", "rule", "timeline"}
+
+func TestClient_ExtractFacts_Recorded(t *testing.T) {
+	client := recordedClient(t)
+
+	facts, err := client.ExtractFacts(context.Background(),
+		"Frodo Baggins lives in Bag End, in the Shire. His uncle Bilbo gave him the One Ring, which Sauron forged in Mount Doom.",
+		allTypes)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, facts)
+	byPredicate := make(map[string]entities.Fact)
+	for _, f := range facts {
+		assert.Contains(t, allTypes, string(f.Type))
+		assert.NotEmpty(t, f.Subject)
+		assert.InDelta(t, 0.5, f.Confidence, 0.5)
+		byPredicate[f.Predicate] = f
+	}
+
+	home, ok := byPredicate["lives_in"]
+	require.True(t, ok, "extracts where Frodo lives")
+	assert.Equal(t, "Frodo Baggins", home.Subject)
+	assert.Equal(t, "Bag End", home.Object)
+}
+
+func TestClient_CheckConsistency_Recorded(t *testing.T) {
+	client := recordedClient(t)
+
+	existing := []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo Baggins", Predicate: "lives_in", Object: "Bag End", Confidence: 0.95},
+		{Type: entities.FactTypeCharacter, Subject: "Bilbo Baggins", Predicate: "uncle_of", Object: "Frodo Baggins", Confidence: 0.9},
+	}
+	newFacts := []entities.Fact{
+		{Type: entities.FactTypeCharacter, Subject: "Frodo Baggins", Predicate: "lives_in", Object: "Minas Tirith", Confidence: 0.9},
+		{Type: entities.FactTypeCharacter, Subject: "Frodo Baggins", Predicate: "carries", Object: "the One Ring", Confidence: 0.9},
+	}
+
+	issues, err := client.CheckConsistency(context.Background(), newFacts, existing)
+	require.NoError(t, err)
+
+	require.Len(t, issues, 1, "only the new home contradicts an existing fact")
+	assert.Equal(t, "Minas Tirith", issues[0].NewFact.Object)
+	assert.Equal(t, "Bag End", issues[0].ExistingFact.Object)
+	assert.NotEmpty(t, issues[0].Description)
+	assert.Contains(t, []string{"minor", "major", "critical"}, issues[0].Severity)
+}
+
+func TestClient_RateLimited_Recorded(t *testing.T) {
+	if os.Getenv("LORE_RECORDING") == config.RecordingModeRecord {
+		t.Skip("a rate-limited response cannot be recorded on demand")
+	}
+	client := recordedClient(t)
+
+	_, err := client.ExtractFacts(context.Background(), "Samwise Gamgee is a gardener.", allTypes)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, entities.ErrBackendUnavailable)
+}
+
+func TestClient_Unrecorded(t *testing.T) {
+	if os.Getenv("LORE_RECORDING") == config.RecordingModeRecord {
+		t.Skip("only replay fails for unrecorded requests")
+	}
+	client := recordedClient(t)
+
+	_, err := client.ExtractFacts(context.Background(), "Nothing was recorded for this text.", allTypes)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// sendMailFunc sends a message as smtp.SendMail does.
+type sendMailFunc func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+
+// Email sends notifications through an SMTP server.
+type Email struct {
+	cfg      config.EmailConfig
+	now      func() time.Time
+	sendMail sendMailFunc
+}
+
+// NewEmail creates an email notifier.
+func NewEmail(cfg config.EmailConfig) *Email {
+	return &Email{
+		cfg:      cfg,
+		now:      time.Now,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Notify implements ports.Notifier. The server is authenticated with when
+// a username is configured; smtp.SendMail upgrades to TLS when the server
+// offers it. Sending cannot be canceled once started.
+func (e *Email) Notify(ctx context.Context, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if e.cfg.Username != "" {
+		host, _, err := net.SplitHostPort(e.cfg.SMTP)
+		if err != nil {
+			return fmt.Errorf("parsing smtp address: %w", err)
+		}
+		auth = smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, host)
+	}
+
+	if err := e.sendMail(e.cfg.SMTP, auth, e.cfg.From, e.cfg.To, e.message(subject, body)); err != nil {
+		return fmt.Errorf("sending email to %s: %w", strings.Join(e.cfg.To, ", "), err)
+	}
+	return nil
+}
+
+// message returns the email with its headers, as plain text with CRLF line
+// endings.
+func (e *Email) message(subject, body string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", e.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// headerValue keeps a header value on one line, encoding it if it is not
+// ASCII.
+func headerValue(value string) string {
+	return mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(value), " "))
+}
@@ -0,0 +1,21 @@
+// Package notify sends notifications, such as consistency digests, by
+// webhook and email.
+package notify
+
+import (
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// New returns the notifiers configured in cfg, none if notifications are
+// off.
+func New(cfg config.NotifyConfig) []ports.Notifier {
+	var notifiers []ports.Notifier
+	if cfg.Webhook != "" {
+		notifiers = append(notifiers, NewWebhook(cfg.Webhook, cfg.Timeout))
+	}
+	if cfg.Email.Enabled() {
+		notifiers = append(notifiers, NewEmail(cfg.Email))
+	}
+	return notifiers
+}
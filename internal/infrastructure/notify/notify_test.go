@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+func TestNew(t *testing.T) {
+	assert.Empty(t, New(config.NotifyConfig{}))
+
+	notifiers := New(config.NotifyConfig{
+		Webhook: "https://hooks.example.com/T1",
+		Email:   config.EmailConfig{SMTP: "smtp.example.com:587", From: "lore@example.com", To: []string{"team@example.com"}},
+	})
+	require.Len(t, notifiers, 2)
+	assert.IsType(t, &Webhook{}, notifiers[0])
+	assert.IsType(t, &Email{}, notifiers[1])
+}
+
+func TestWebhook_Notify(t *testing.T) {
+	var got webhookMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	require.NoError(t, NewWebhook(server.URL, time.Second).Notify(context.Background(), "2 new contradictions", "details"))
+	assert.Equal(t, "2 new contradictions", got.Subject)
+	assert.Equal(t, "2 new contradictions\n\ndetails", got.Text)
+}
+
+func TestWebhook_NotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL, time.Second).Notify(context.Background(), "subject", "body")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "no such hook")
+}
+
+func TestEmail_Notify(t *testing.T) {
+	cfg := config.EmailConfig{
+		SMTP:     "smtp.example.com:587",
+		Username: "lore",
+		Password: "secret",
+		From:     "lore@example.com",
+		To:       []string{"a@example.com", "b@example.com"},
+	}
+	email := NewEmail(cfg)
+	email.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	var (
+		addr string
+		auth smtp.Auth
+		to   []string
+		msg  string
+	)
+	email.sendMail = func(a string, au smtp.Auth, from string, t []string, m []byte) error {
+		addr, auth, to, msg = a, au, t, string(m)
+		return nil
+	}
+
+	require.NoError(t, email.Notify(context.Background(), "Digest\nfor Éowyn", "line one\nline two\n"))
+	assert.Equal(t, cfg.SMTP, addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, cfg.To, to)
+	assert.Equal(t, "From: lore@example.com\r\n"+
+		"To: a@example.com, b@example.com\r\n"+
+		"Subject: =?utf-8?q?Digest_for_=C3=89owyn?=\r\n"+
+		"Date: Fri, 02 Jan 2026 03:04:05 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"\r\n"+
+		"line one\r\nline two\r\n", msg)
+}
+
+func TestEmail_NotifyWithoutAuth(t *testing.T) {
+	email := NewEmail(config.EmailConfig{SMTP: "localhost:25", From: "lore@localhost", To: []string{"me@localhost"}})
+	email.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Nil(t, a, "no username, no authentication")
+		return nil
+	}
+	require.NoError(t, email.Notify(context.Background(), "subject", "body"))
+}
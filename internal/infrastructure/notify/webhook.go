@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxErrorBody is how much of a failed response's body is reported.
+const maxErrorBody = 512
+
+// Webhook POSTs notifications to a URL as JSON.
+type Webhook struct {
+	http *http.Client
+	url  string
+}
+
+// webhookMessage is the JSON body of a webhook notification. "text" is
+// what chat incoming webhooks, such as Slack's, display.
+type webhookMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
+
+// NewWebhook creates a webhook notifier. A zero timeout disables it.
+func NewWebhook(url string, timeout time.Duration) *Webhook {
+	return &Webhook{
+		http: &http.Client{Timeout: timeout},
+		url:  url,
+	}
+}
+
+// Notify implements ports.Notifier. The text is the subject followed by
+// the body.
+func (w *Webhook) Notify(ctx context.Context, subject, body string) error {
+	data, err := json.Marshal(webhookMessage{Subject: subject, Text: subject + "\n\n" + body})
+	if err != nil {
+		return fmt.Errorf("marshaling webhook message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, bytes.TrimSpace(text))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package openaierr classifies errors from the OpenAI API client.
+package openaierr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/sashabaranov/go-openai"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// Classify marks err as entities.ErrBackendUnavailable if OpenAI could not
+// be reached, timed out, was rate limited, or failed with a server error.
+// Invalid requests are marked entities.ErrValidation. Other errors are
+// returned unchanged.
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return entities.WithKind(entities.ErrBackendUnavailable, err)
+	}
+
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	code := 0
+	switch {
+	case errors.As(err, &apiErr):
+		code = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		code = reqErr.HTTPStatusCode
+	}
+
+	switch {
+	case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+		return entities.WithKind(entities.ErrBackendUnavailable, err)
+	case code == http.StatusBadRequest:
+		return entities.WithKind(entities.ErrValidation, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,39 @@
+package openaierr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"rate limited", &openai.APIError{HTTPStatusCode: 429, Message: "slow down"}, entities.ErrBackendUnavailable},
+		{"server error", &openai.RequestError{HTTPStatusCode: 502, Err: errors.New("bad gateway")}, entities.ErrBackendUnavailable},
+		{"timeout", fmt.Errorf("posting: %w", context.DeadlineExceeded), entities.ErrBackendUnavailable},
+		{"bad request", &openai.APIError{HTTPStatusCode: 400, Message: "bad model"}, entities.ErrValidation},
+		{"unauthorized", &openai.APIError{HTTPStatusCode: 401, Message: "bad key"}, nil},
+		{"other", errors.New("boom"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Classify(tt.err)
+			assert.Equal(t, tt.want, entities.ErrorKind(err))
+			if tt.err != nil {
+				assert.Equal(t, tt.err.Error(), err.Error())
+			}
+		})
+	}
+}
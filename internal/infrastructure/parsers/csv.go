@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 // CSVParser parses facts from CSV format.
@@ -12,7 +14,7 @@ type CSVParser struct{}
 
 // Parse reads CSV from the reader and returns parsed facts.
 // Expected columns: type, subject, predicate, object, context, source_file, confidence
-func (p *CSVParser) Parse(r io.Reader) ([]RawFact, error) {
+func (p *CSVParser) Parse(r io.Reader) ([]entities.RawFact, error) {
 	reader := csv.NewReader(r)
 
 	colIndex, err := p.readHeader(reader)
@@ -46,8 +48,8 @@ func (p *CSVParser) readHeader(reader *csv.Reader) (map[string]int, error) {
 }
 
 // readRecords reads all data rows and converts them to RawFacts.
-func (p *CSVParser) readRecords(reader *csv.Reader, colIndex map[string]int) ([]RawFact, error) {
-	var facts []RawFact
+func (p *CSVParser) readRecords(reader *csv.Reader, colIndex map[string]int) ([]entities.RawFact, error) {
+	var facts []entities.RawFact
 	lineNum := 1 // Header is line 1
 
 	for {
@@ -71,8 +73,8 @@ func (p *CSVParser) readRecords(reader *csv.Reader, colIndex map[string]int) ([]
 }
 
 // parseRecord converts a CSV record to a RawFact.
-func (p *CSVParser) parseRecord(record []string, colIndex map[string]int, lineNum int) (RawFact, error) {
-	fact := RawFact{
+func (p *CSVParser) parseRecord(record []string, colIndex map[string]int, lineNum int) (entities.RawFact, error) {
+	fact := entities.RawFact{
 		Type:       getColumn(record, colIndex, "type"),
 		Subject:    getColumn(record, colIndex, "subject"),
 		Predicate:  getColumn(record, colIndex, "predicate"),
@@ -87,7 +89,7 @@ func (p *CSVParser) parseRecord(record []string, colIndex map[string]int, lineNu
 	if confStr != "" {
 		conf, err := strconv.ParseFloat(confStr, 64)
 		if err != nil {
-			return RawFact{}, fmt.Errorf("line %d: invalid confidence value %q: %w", lineNum, confStr, err)
+			return entities.RawFact{}, fmt.Errorf("line %d: invalid confidence value %q: %w", lineNum, confStr, err)
 		}
 		fact.Confidence = &conf
 	}
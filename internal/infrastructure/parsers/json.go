@@ -1,27 +1,83 @@
 package parsers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
-// JSONParser parses facts from JSON format.
+// JSONParser parses facts from JSON format. The input is either an array
+// of facts or a document object with facts, entities, and relationships.
 type JSONParser struct{}
 
 // Parse reads JSON from the reader and returns parsed facts.
-func (p *JSONParser) Parse(r io.Reader) ([]RawFact, error) {
-	var facts []RawFact
+func (p *JSONParser) Parse(r io.Reader) ([]entities.RawFact, error) {
+	doc, err := p.ParseDocument(r)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Facts, nil
+}
+
+// ParseDocument reads a JSON fact array or document object. JSON carries
+// no usable line numbers, so each item's LineNum is its 1-indexed
+// position within its section.
+func (p *JSONParser) ParseDocument(r io.Reader) (*entities.ImportDocument, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading JSON: %w", err)
+	}
 
-	decoder := json.NewDecoder(r)
-	if err := decoder.Decode(&facts); err != nil {
+	var doc jsonDocument
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &doc.Facts); err != nil {
 		return nil, fmt.Errorf("parsing JSON: %w", err)
 	}
 
 	// Set line numbers (array index + 1, 1-indexed)
-	for i := range facts {
-		facts[i].LineNum = i + 1
+	result := &entities.ImportDocument{
+		Facts:         doc.Facts,
+		Relationships: doc.Relationships,
+	}
+	for i := range result.Facts {
+		result.Facts[i].LineNum = i + 1
+	}
+	for i, entity := range doc.Entities {
+		entity.LineNum = i + 1
+		result.Entities = append(result.Entities, entities.RawEntity(entity))
+	}
+	for i := range result.Relationships {
+		result.Relationships[i].LineNum = i + 1
+	}
+
+	return result, nil
+}
+
+// jsonDocument is an import document as written in JSON.
+type jsonDocument struct {
+	Facts         []entities.RawFact         `json:"facts,omitempty"`
+	Entities      []jsonEntity               `json:"entities,omitempty"`
+	Relationships []entities.RawRelationship `json:"relationships,omitempty"`
+}
+
+// jsonEntity is a RawEntity written as a plain name or as an object.
+type jsonEntity entities.RawEntity
+
+// UnmarshalJSON accepts an entity as a plain name or as an object.
+func (e *jsonEntity) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &e.Name)
 	}
 
-	return facts, nil
+	type rawEntity entities.RawEntity // Avoids recursing into this method
+	return json.Unmarshal(data, (*rawEntity)(e))
 }
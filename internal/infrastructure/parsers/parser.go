@@ -5,32 +5,29 @@ import (
 	"io"
 	"path/filepath"
 	"strings"
-)
 
-// RawFact represents a fact parsed from an external source before validation.
-type RawFact struct {
-	ID         string   `json:"id,omitempty"`
-	Type       string   `json:"type"`
-	Subject    string   `json:"subject"`
-	Predicate  string   `json:"predicate"`
-	Object     string   `json:"object"`
-	Context    string   `json:"context,omitempty"`
-	SourceFile string   `json:"source_file,omitempty"`
-	Confidence *float64 `json:"confidence,omitempty"` // Pointer to distinguish 0 from unset
-	LineNum    int      `json:"-"`                    // Line number in source file (set by parser)
-}
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
 
 // Parser defines the interface for parsing facts from various formats.
 type Parser interface {
-	Parse(r io.Reader) ([]RawFact, error)
+	Parse(r io.Reader) ([]entities.RawFact, error)
+}
+
+// DocumentParser is a Parser that also reads full import documents.
+type DocumentParser interface {
+	Parser
+	ParseDocument(r io.Reader) (*entities.ImportDocument, error)
 }
 
 // ForFormat returns the appropriate parser for the given format.
-// Supported formats: "json", "csv".
+// Supported formats: "json", "yaml", "csv".
 func ForFormat(format string) Parser {
 	switch strings.ToLower(format) {
 	case "json":
 		return &JSONParser{}
+	case "yaml", "yml":
+		return &YAMLParser{}
 	case "csv":
 		return &CSVParser{}
 	default:
@@ -44,6 +41,8 @@ func ForFile(filename string) Parser {
 	switch ext {
 	case ".json":
 		return &JSONParser{}
+	case ".yaml", ".yml":
+		return &YAMLParser{}
 	case ".csv":
 		return &CSVParser{}
 	default:
@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
 )
 
 func TestJSONParser_Parse_ValidInput(t *testing.T) {
@@ -145,14 +147,133 @@ func TestCSVParser_Parse_Errors(t *testing.T) {
 	}
 }
 
+func TestJSONParser_ParseDocument(t *testing.T) {
+	input := `{
+		"entities": ["Frodo", {"name": "The Shire"}],
+		"facts": [{"type": "character", "subject": "Frodo", "predicate": "is a", "object": "hobbit"}],
+		"relationships": [{"source": "Frodo", "type": "located_in", "target": "The Shire", "bidirectional": true}]
+	}`
+
+	parser := &JSONParser{}
+	doc, err := parser.ParseDocument(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, doc.Entities, 2)
+	assert.Equal(t, "Frodo", doc.Entities[0].Name)
+	assert.Equal(t, "The Shire", doc.Entities[1].Name)
+	assert.Equal(t, 2, doc.Entities[1].LineNum)
+
+	require.Len(t, doc.Facts, 1)
+	assert.Equal(t, "hobbit", doc.Facts[0].Object)
+
+	require.Len(t, doc.Relationships, 1)
+	assert.Equal(t, entities.RawRelationship{Source: "Frodo", Type: "located_in", Target: "The Shire", Bidirectional: true, LineNum: 1}, doc.Relationships[0])
+
+	t.Run("unknown section", func(t *testing.T) {
+		_, err := parser.ParseDocument(strings.NewReader(`{"characters": []}`))
+		require.Error(t, err)
+	})
+
+	t.Run("fact array", func(t *testing.T) {
+		doc, err := parser.ParseDocument(strings.NewReader(`[{"type": "character", "subject": "Sam"}]`))
+		require.NoError(t, err)
+		require.Len(t, doc.Facts, 1)
+		assert.Empty(t, doc.Entities)
+	})
+}
+
+func TestYAMLParser_Parse_FactList(t *testing.T) {
+	input := `- type: character
+  subject: Gandalf
+  predicate: is a
+  object: wizard
+  source_file: lotr.txt
+  confidence: 0.9
+- type: location
+  subject: Rivendell
+  predicate: ruled by
+  object: Elrond
+`
+	parser := &YAMLParser{}
+	result, err := parser.Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	assert.Equal(t, "Gandalf", result[0].Subject)
+	assert.Equal(t, "lotr.txt", result[0].SourceFile)
+	require.NotNil(t, result[0].Confidence)
+	assert.Equal(t, 0.9, *result[0].Confidence)
+	assert.Equal(t, 1, result[0].LineNum)
+	assert.Equal(t, 7, result[1].LineNum)
+}
+
+func TestYAMLParser_ParseDocument(t *testing.T) {
+	input := `entities:
+  - Frodo
+  - name: The Shire
+facts:
+  - {type: character, subject: Frodo, predicate: is a, object: hobbit}
+relationships:
+  - source: Frodo
+    type: located_in
+    target: The Shire
+`
+	parser := &YAMLParser{}
+	doc, err := parser.ParseDocument(strings.NewReader(input))
+	require.NoError(t, err)
+
+	require.Len(t, doc.Entities, 2)
+	assert.Equal(t, entities.RawEntity{Name: "Frodo", LineNum: 2}, doc.Entities[0])
+	assert.Equal(t, entities.RawEntity{Name: "The Shire", LineNum: 3}, doc.Entities[1])
+
+	require.Len(t, doc.Facts, 1)
+	assert.Equal(t, 5, doc.Facts[0].LineNum)
+
+	require.Len(t, doc.Relationships, 1)
+	assert.Equal(t, "located_in", doc.Relationships[0].Type)
+	assert.Equal(t, 7, doc.Relationships[0].LineNum)
+}
+
+func TestYAMLParser_ParseDocument_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errMsg string
+	}{
+		{"unknown section", "characters: []\n", "unknown section"},
+		{"section not a list", "facts: {}\n", "must be a list"},
+		{"scalar document", "hello\n", "expected a list of facts or a document"},
+		{"invalid yaml", "facts: [\n", "parsing YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := &YAMLParser{}
+			_, err := parser.ParseDocument(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	t.Run("empty input", func(t *testing.T) {
+		parser := &YAMLParser{}
+		doc, err := parser.ParseDocument(strings.NewReader(""))
+		require.NoError(t, err)
+		assert.True(t, doc.IsEmpty())
+	})
+}
+
 func TestForFormat(t *testing.T) {
 	assert.IsType(t, &JSONParser{}, ForFormat("json"))
+	assert.IsType(t, &YAMLParser{}, ForFormat("yaml"))
 	assert.IsType(t, &CSVParser{}, ForFormat("csv"))
 	assert.Nil(t, ForFormat("unknown"))
 }
 
 func TestForFile(t *testing.T) {
 	assert.IsType(t, &JSONParser{}, ForFile("facts.json"))
+	assert.IsType(t, &YAMLParser{}, ForFile("bible.yaml"))
+	assert.IsType(t, &YAMLParser{}, ForFile("bible.yml"))
 	assert.IsType(t, &CSVParser{}, ForFile("data.csv"))
 	assert.Nil(t, ForFile("file.txt"))
 	assert.Nil(t, ForFile("noextension"))
@@ -0,0 +1,125 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// YAMLParser parses facts from YAML format. The input is either a list of
+// facts or a document mapping with facts, entities, and relationships.
+type YAMLParser struct{}
+
+// Parse reads YAML from the reader and returns parsed facts.
+func (p *YAMLParser) Parse(r io.Reader) ([]entities.RawFact, error) {
+	doc, err := p.ParseDocument(r)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Facts, nil
+}
+
+// ParseDocument reads a YAML fact list or document mapping. Each item's
+// LineNum is the line it starts on in the source file.
+func (p *YAMLParser) ParseDocument(r io.Reader) (*entities.ImportDocument, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
+		if errors.Is(err, io.EOF) {
+			return &entities.ImportDocument{}, nil
+		}
+		return nil, fmt.Errorf("parsing YAML: %w", err)
+	}
+
+	doc := &entities.ImportDocument{}
+	if len(root.Content) == 0 {
+		return doc, nil
+	}
+	node := root.Content[0]
+
+	switch node.Kind {
+	case yaml.SequenceNode:
+		facts, err := decodeYAMLFacts(node)
+		if err != nil {
+			return nil, err
+		}
+		doc.Facts = facts
+	case yaml.MappingNode:
+		if err := decodeYAMLSections(node, doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("parsing YAML: line %d: expected a list of facts or a document", node.Line)
+	}
+
+	return doc, nil
+}
+
+// decodeYAMLSections decodes the sections of a document mapping.
+func decodeYAMLSections(node *yaml.Node, doc *entities.ImportDocument) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if value.Kind != yaml.SequenceNode {
+			return fmt.Errorf("parsing YAML: line %d: section %q must be a list", value.Line, key.Value)
+		}
+
+		var err error
+		switch key.Value {
+		case "facts":
+			doc.Facts, err = decodeYAMLFacts(value)
+		case "entities":
+			doc.Entities, err = decodeYAMLEntities(value)
+		case "relationships":
+			doc.Relationships, err = decodeYAMLRelationships(value)
+		default:
+			return fmt.Errorf("parsing YAML: line %d: unknown section %q (valid: facts, entities, relationships)", key.Line, key.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeYAMLFacts(node *yaml.Node) ([]entities.RawFact, error) {
+	facts := make([]entities.RawFact, len(node.Content))
+	for i, item := range node.Content {
+		if err := item.Decode(&facts[i]); err != nil {
+			return nil, fmt.Errorf("parsing YAML: line %d: %w", item.Line, err)
+		}
+		facts[i].LineNum = item.Line
+	}
+	return facts, nil
+}
+
+// decodeYAMLEntities accepts entities as plain names or as mappings.
+func decodeYAMLEntities(node *yaml.Node) ([]entities.RawEntity, error) {
+	ents := make([]entities.RawEntity, len(node.Content))
+	for i, item := range node.Content {
+		var err error
+		if item.Kind == yaml.ScalarNode {
+			err = item.Decode(&ents[i].Name)
+		} else {
+			err = item.Decode(&ents[i])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing YAML: line %d: %w", item.Line, err)
+		}
+		ents[i].LineNum = item.Line
+	}
+	return ents, nil
+}
+
+func decodeYAMLRelationships(node *yaml.Node) ([]entities.RawRelationship, error) {
+	rels := make([]entities.RawRelationship, len(node.Content))
+	for i, item := range node.Content {
+		if err := item.Decode(&rels[i]); err != nil {
+			return nil, fmt.Errorf("parsing YAML: line %d: %w", item.Line, err)
+		}
+		rels[i].LineNum = item.Line
+	}
+	return rels, nil
+}
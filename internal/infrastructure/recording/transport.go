@@ -0,0 +1,203 @@
+// Package recording records HTTP exchanges with external APIs to fixture
+// files and replays them, so code that calls OpenAI can be tested against
+// real responses, deterministically and without network access.
+//
+// A fixture is named after the request's path and a hash of its method,
+// path, and body, so a request replays the response recorded for an
+// identical one. Request headers, including API keys, are never stored.
+package recording
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// Mode selects whether a Transport calls the API, records, or replays.
+type Mode string
+
+const (
+	// ModeOff passes requests through untouched.
+	ModeOff Mode = ""
+	// ModeRecord passes requests through and saves each exchange.
+	ModeRecord Mode = "record"
+	// ModeReplay answers requests from saved exchanges and never calls
+	// the API.
+	ModeReplay Mode = "replay"
+)
+
+// Transport is an http.RoundTripper that records or replays exchanges.
+type Transport struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+}
+
+// NewTransport creates a Transport keeping fixtures in dir. next makes
+// the real requests; nil uses http.DefaultTransport.
+func NewTransport(mode Mode, dir string, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{mode: mode, dir: dir, next: next}
+}
+
+// Client returns an HTTP client that sends requests through t.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+type fixture struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+type recordedResponse struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.mode == ModeOff {
+		return t.next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+	path := filepath.Join(t.dir, fixtureName(req, body))
+
+	if t.mode == ModeReplay {
+		return replay(req, path)
+	}
+	return t.record(req, body, path)
+}
+
+// fixtureName names a request's fixture, such as
+// chat-completions-1a2b3c4d5e6f7a8b.json.
+func fixtureName(req *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s?%s\n", req.Method, req.URL.Path, req.URL.RawQuery)
+	h.Write(body)
+
+	endpoint := strings.TrimPrefix(strings.Trim(req.URL.Path, "/"), "v1/")
+	endpoint = strings.ReplaceAll(endpoint, "/", "-")
+	if endpoint == "" {
+		endpoint = "root"
+	}
+	return endpoint + "-" + hex.EncodeToString(h.Sum(nil))[:16] + ".json"
+}
+
+func (t *Transport) record(req *http.Request, body []byte, path string) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	resp, err := t.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	f := fixture{
+		Request: recordedRequest{Method: req.Method, URL: req.URL.String(), Body: rawJSON(body)},
+		Response: recordedResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        rawJSON(respBody),
+		},
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("writing fixture: %w", err)
+	}
+	return resp, nil
+}
+
+func replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, entities.Errorf(entities.ErrNotFound, "no recorded response for %s %s: %s does not exist (record it first)", req.Method, req.URL.Path, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+
+	body := []byte(f.Response.Body)
+	if len(body) > 0 && body[0] == '"' {
+		var s string
+		if err := json.Unmarshal(body, &s); err != nil {
+			return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+		}
+		body = []byte(s)
+	}
+
+	header := make(http.Header)
+	if f.Response.ContentType != "" {
+		header.Set("Content-Type", f.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.Status, http.StatusText(f.Response.Status)),
+		StatusCode:    f.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// rawJSON stores JSON bodies as they are, so fixtures are readable, and
+// anything else as a JSON string.
+func rawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+	if json.Valid(b) {
+		return b
+	}
+	s, _ := json.Marshal(string(b))
+	return s
+}
@@ -0,0 +1,85 @@
+package recording
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func post(t *testing.T, client *http.Client, url, body string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestTransport_RecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(string(body), "fail") {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	dir := t.TempDir()
+
+	recorder := NewTransport(ModeRecord, dir, nil).Client()
+	resp, body := post(t, recorder, server.URL+"/v1/chat/completions", `{"q":"hello"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"echo":{"q":"hello"}}`, body)
+	resp, _ = post(t, recorder, server.URL+"/v1/chat/completions", `{"q":"fail"}`)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+
+	files, err := filepath.Glob(filepath.Join(dir, "chat-completions-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "sk-secret", "headers are not recorded")
+
+	server.Close()
+	player := NewTransport(ModeReplay, dir, nil).Client()
+	resp, body = post(t, player, server.URL+"/v1/chat/completions", `{"q":"hello"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"echo":{"q":"hello"}}`, body)
+	resp, _ = post(t, player, server.URL+"/v1/chat/completions", `{"q":"fail"}`)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, 2, calls, "replay never calls the server")
+}
+
+func TestTransport_ReplayMissing(t *testing.T) {
+	player := NewTransport(ModeReplay, t.TempDir(), nil).Client()
+	_, err := player.Post("http://api.example.com/v1/embeddings", "application/json", strings.NewReader(`{}`))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+	assert.Contains(t, err.Error(), "embeddings-")
+}
+
+func TestFixtureName(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodPost, "https://api.openai.com/v1/embeddings", nil)
+	b, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:1234/v1/embeddings", nil)
+
+	assert.Equal(t, fixtureName(a, []byte(`{"input":"x"}`)), fixtureName(b, []byte(`{"input":"x"}`)), "the host does not matter")
+	assert.NotEqual(t, fixtureName(a, []byte(`{"input":"x"}`)), fixtureName(a, []byte(`{"input":"y"}`)))
+	assert.True(t, strings.HasPrefix(fixtureName(a, nil), "embeddings-"))
+}
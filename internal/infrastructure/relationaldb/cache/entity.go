@@ -0,0 +1,245 @@
+// Package cache provides in-process caching decorators for ports.RelationalDB.
+package cache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// DefaultEntityCapacity is the number of entities cached per index when no
+// capacity is given.
+const DefaultEntityCapacity = 1024
+
+// nameKey identifies an entity by world and normalized name.
+type nameKey struct {
+	worldID string
+	name    string
+}
+
+// EntityCache is a ports.RelationalDB that caches entity lookups by ID and
+// by name. Entity writes made through it invalidate the affected entries;
+// writes made directly to the underlying database are not seen.
+// All other methods pass through unchanged.
+type EntityCache struct {
+	ports.RelationalDB
+
+	mu     sync.Mutex
+	byID   *lru[string, entities.Entity]
+	byName *lru[nameKey, entities.Entity]
+	// generation is bumped on every invalidation so lookups that raced
+	// with a write don't cache what they read.
+	generation uint64
+	hits       uint64
+	misses     uint64
+}
+
+// NewEntityCache wraps db with an entity cache holding up to capacity
+// entities per index. A capacity of zero or less uses DefaultEntityCapacity.
+func NewEntityCache(db ports.RelationalDB, capacity int) *EntityCache {
+	if capacity <= 0 {
+		capacity = DefaultEntityCapacity
+	}
+	return &EntityCache{
+		RelationalDB: db,
+		byID:         newLRU[string, entities.Entity](capacity),
+		byName:       newLRU[nameKey, entities.Entity](capacity),
+	}
+}
+
+// Stats returns the cache's hit, miss, and eviction counts.
+func (c *EntityCache) Stats() ports.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ports.CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.byID.evictions,
+		Size:      c.byID.len(),
+		Capacity:  c.byID.capacity,
+	}
+}
+
+// SaveEntity saves an entity and invalidates its cached entries.
+func (c *EntityCache) SaveEntity(ctx context.Context, entity *entities.Entity) error {
+	defer c.invalidate(entity.ID)
+	return c.RelationalDB.SaveEntity(ctx, entity)
+}
+
+// DeleteEntity deletes an entity and invalidates its cached entries.
+func (c *EntityCache) DeleteEntity(ctx context.Context, entityID string) error {
+	defer c.invalidate(entityID)
+	return c.RelationalDB.DeleteEntity(ctx, entityID)
+}
+
+// RenameEntity renames an entity and invalidates its cached entries.
+func (c *EntityCache) RenameEntity(ctx context.Context, entityID, name string) error {
+	defer c.invalidate(entityID)
+	return c.RelationalDB.RenameEntity(ctx, entityID, name)
+}
+
+// MergeEntities merges two entities and invalidates both entities' cached entries.
+func (c *EntityCache) MergeEntities(ctx context.Context, fromID, toID string) error {
+	defer c.invalidate(toID)
+	defer c.invalidate(fromID)
+	return c.RelationalDB.MergeEntities(ctx, fromID, toID)
+}
+
+// FindEntityByName finds an entity by name, from the cache if possible.
+func (c *EntityCache) FindEntityByName(ctx context.Context, worldID, name string) (*entities.Entity, error) {
+	key := nameKey{worldID: worldID, name: entities.NormalizeName(name)}
+	if entity, ok := c.lookupName(key); ok {
+		return entity, nil
+	}
+
+	gen := c.currentGeneration()
+	entity, err := c.RelationalDB.FindEntityByName(ctx, worldID, name)
+	if err != nil {
+		return nil, err
+	}
+	c.store(gen, entity)
+	return entity, nil
+}
+
+// FindOrCreateEntity finds an entity by name, from the cache if possible,
+// or creates it.
+func (c *EntityCache) FindOrCreateEntity(ctx context.Context, worldID, name string) (*entities.Entity, error) {
+	key := nameKey{worldID: worldID, name: entities.NormalizeName(name)}
+	if entity, ok := c.lookupName(key); ok {
+		return entity, nil
+	}
+
+	gen := c.currentGeneration()
+	entity, err := c.RelationalDB.FindOrCreateEntity(ctx, worldID, name)
+	if err != nil {
+		return nil, err
+	}
+	c.store(gen, entity)
+	return entity, nil
+}
+
+// FindEntityByID finds an entity by ID, from the cache if possible.
+func (c *EntityCache) FindEntityByID(ctx context.Context, entityID string) (*entities.Entity, error) {
+	c.mu.Lock()
+	cached, ok := c.byID.get(entityID)
+	c.count(ok)
+	c.mu.Unlock()
+	if ok {
+		return &cached, nil
+	}
+
+	gen := c.currentGeneration()
+	entity, err := c.RelationalDB.FindEntityByID(ctx, entityID)
+	if err != nil {
+		return nil, err
+	}
+	c.store(gen, entity)
+	return entity, nil
+}
+
+// FindEntitiesByIDs finds entities by ID, querying the database only for
+// those not in the cache. Results keep the database's behavior of omitting
+// missing IDs.
+func (c *EntityCache) FindEntitiesByIDs(ctx context.Context, ids []string) ([]*entities.Entity, error) {
+	found := make(map[string]*entities.Entity, len(ids))
+	var missing []string
+
+	c.mu.Lock()
+	for _, id := range ids {
+		if cached, ok := c.byID.get(id); ok {
+			found[id] = &cached
+			c.count(true)
+		} else {
+			missing = append(missing, id)
+			c.count(false)
+		}
+	}
+	gen := c.generation
+	c.mu.Unlock()
+
+	if len(missing) > 0 {
+		loaded, err := c.RelationalDB.FindEntitiesByIDs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, entity := range loaded {
+			found[entity.ID] = entity
+			c.store(gen, entity)
+		}
+	}
+
+	result := make([]*entities.Entity, 0, len(found))
+	for _, id := range ids {
+		if entity, ok := found[id]; ok {
+			result = append(result, entity)
+			delete(found, id) // Don't repeat duplicate IDs
+		}
+	}
+	return result, nil
+}
+
+// lookupName returns a copy of the cached entity for key.
+func (c *EntityCache) lookupName(key nameKey) (*entities.Entity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.byName.get(key)
+	c.count(ok)
+	if !ok {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// count records a hit or a miss. The caller must hold c.mu.
+func (c *EntityCache) count(hit bool) {
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+func (c *EntityCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// store caches a copy of entity under its ID and name, unless the cache was
+// invalidated since gen was read. Nil entities (not found) aren't cached.
+func (c *EntityCache) store(gen uint64, entity *entities.Entity) {
+	if entity == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.generation {
+		return
+	}
+	c.byID.add(entity.ID, *entity)
+	c.byName.add(nameKey{worldID: entity.WorldID, name: entity.NormalizedName}, *entity)
+}
+
+// Purge drops every cached entity, so writes made directly to the
+// underlying database are seen from then on.
+func (c *EntityCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.byID.removeFunc(func(string, entities.Entity) bool { return true })
+	c.byName.removeFunc(func(nameKey, entities.Entity) bool { return true })
+}
+
+// invalidate drops every cached entry for the entity with the given ID.
+func (c *EntityCache) invalidate(entityID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.byID.remove(entityID)
+	c.byName.removeFunc(func(_ nameKey, e entities.Entity) bool {
+		return e.ID == entityID
+	})
+}
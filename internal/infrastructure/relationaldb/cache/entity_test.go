@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/mocks"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// countingDB counts entity lookups that reach the database.
+type countingDB struct {
+	*mocks.RelationalDB
+	lookups int
+}
+
+func (d *countingDB) FindEntityByName(ctx context.Context, worldID, name string) (*entities.Entity, error) {
+	d.lookups++
+	return d.RelationalDB.FindEntityByName(ctx, worldID, name)
+}
+
+func (d *countingDB) FindEntityByID(ctx context.Context, entityID string) (*entities.Entity, error) {
+	d.lookups++
+	return d.RelationalDB.FindEntityByID(ctx, entityID)
+}
+
+func (d *countingDB) FindEntitiesByIDs(ctx context.Context, ids []string) ([]*entities.Entity, error) {
+	d.lookups += len(ids)
+	return d.RelationalDB.FindEntitiesByIDs(ctx, ids)
+}
+
+func newTestCache(t *testing.T, capacity int) (*EntityCache, *countingDB) {
+	t.Helper()
+	db := &countingDB{RelationalDB: mocks.NewRelationalDB()}
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		require.NoError(t, db.SaveEntity(context.Background(), &entities.Entity{
+			ID:             "id-" + name,
+			WorldID:        "w",
+			Name:           name,
+			NormalizedName: entities.NormalizeName(name),
+		}))
+	}
+	return NewEntityCache(db, capacity), db
+}
+
+func TestEntityCache_Lookups(t *testing.T) {
+	ctx := context.Background()
+	c, db := newTestCache(t, 10)
+
+	e, err := c.FindEntityByName(ctx, "w", "Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "id-Alice", e.ID)
+
+	// Cached by both name and ID after the first lookup.
+	_, err = c.FindEntityByName(ctx, "w", " ALICE ")
+	require.NoError(t, err)
+	e, err = c.FindEntityByID(ctx, "id-Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", e.Name)
+	assert.Equal(t, 1, db.lookups)
+
+	// Callers get copies and can't corrupt the cache.
+	e.Name = "changed"
+	e, err = c.FindEntityByID(ctx, "id-Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", e.Name)
+
+	// Not-found results aren't cached.
+	for range 2 {
+		e, err = c.FindEntityByName(ctx, "w", "Nobody")
+		require.NoError(t, err)
+		assert.Nil(t, e)
+	}
+	assert.Equal(t, 3, db.lookups)
+
+	assert.Equal(t, ports.CacheStats{Hits: 3, Misses: 3, Size: 1, Capacity: 10}, c.Stats())
+}
+
+func TestEntityCache_FindEntitiesByIDs(t *testing.T) {
+	ctx := context.Background()
+	c, db := newTestCache(t, 10)
+
+	_, err := c.FindEntityByID(ctx, "id-Bob")
+	require.NoError(t, err)
+
+	found, err := c.FindEntitiesByIDs(ctx, []string{"id-Alice", "id-Bob", "missing", "id-Alice"})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "Alice", found[0].Name)
+	assert.Equal(t, "Bob", found[1].Name)
+	assert.Equal(t, 1+3, db.lookups, "only IDs not in the cache reach the database")
+}
+
+func TestEntityCache_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	c, db := newTestCache(t, 10)
+
+	_, err := c.FindEntityByName(ctx, "w", "Alice")
+	require.NoError(t, err)
+
+	// Renaming drops the entry under the old name.
+	require.NoError(t, c.SaveEntity(ctx, &entities.Entity{
+		ID: "id-Alice", WorldID: "w", Name: "Alicia", NormalizedName: "alicia",
+	}))
+	e, err := c.FindEntityByName(ctx, "w", "Alice")
+	require.NoError(t, err)
+	assert.Nil(t, e)
+	e, err = c.FindEntityByID(ctx, "id-Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", e.Name)
+
+	require.NoError(t, c.DeleteEntity(ctx, "id-Alice"))
+	e, err = c.FindEntityByID(ctx, "id-Alice")
+	require.NoError(t, err)
+	assert.Nil(t, e)
+	assert.Equal(t, 4, db.lookups)
+	assert.Zero(t, c.Stats().Size)
+}
+
+func TestEntityCache_Purge(t *testing.T) {
+	ctx := context.Background()
+	c, db := newTestCache(t, 10)
+
+	_, err := c.FindEntityByName(ctx, "w", "Alice")
+	require.NoError(t, err)
+
+	// Another process renames the entity behind the cache's back.
+	require.NoError(t, db.SaveEntity(ctx, &entities.Entity{
+		ID: "id-Alice", WorldID: "w", Name: "Alicia", NormalizedName: "alicia",
+	}))
+	c.Purge()
+	assert.Zero(t, c.Stats().Size)
+
+	e, err := c.FindEntityByID(ctx, "id-Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", e.Name)
+}
+
+func TestEntityCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	c, db := newTestCache(t, 2)
+
+	for _, id := range []string{"id-Alice", "id-Bob", "id-Carol", "id-Alice"} {
+		_, err := c.FindEntityByID(ctx, id)
+		require.NoError(t, err)
+	}
+
+	stats := c.Stats()
+	assert.Equal(t, 4, db.lookups, "Alice was evicted before it was looked up again")
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(2), stats.Evictions)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import "container/list"
+
+// lru is a fixed-capacity map that evicts the least recently used entry.
+// It is not safe for concurrent use.
+type lru[K comparable, V any] struct {
+	capacity  int
+	order     *list.List // Front is most recently used
+	items     map[K]*list.Element
+	evictions uint64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](capacity int) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// get returns the value for key and marks it as recently used.
+func (c *lru[K, V]) get(key K) (V, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// add stores value under key, evicting the least recently used entry if
+// the cache is full.
+func (c *lru[K, V]) add(key K, value V) {
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+		c.evictions++
+	}
+}
+
+// remove deletes key from the cache.
+func (c *lru[K, V]) remove(key K) {
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+// removeFunc deletes every entry for which match returns true.
+func (c *lru[K, V]) removeFunc(match func(K, V) bool) {
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*lruEntry[K, V])
+		if match(entry.key, entry.value) {
+			c.order.Remove(elem)
+			delete(c.items, entry.key)
+		}
+		elem = next
+	}
+}
+
+func (c *lru[K, V]) len() int {
+	return c.order.Len()
+}
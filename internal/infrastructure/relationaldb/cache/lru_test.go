@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	c := newLRU[string, int](2)
+	c.add("a", 1)
+	c.add("b", 2)
+
+	// Touch "a" so "b" is the least recently used.
+	v, ok := c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	c.add("c", 3)
+	_, ok = c.get("b")
+	assert.False(t, ok, "least recently used entry is evicted")
+	assert.Equal(t, uint64(1), c.evictions)
+	assert.Equal(t, 2, c.len())
+
+	c.add("a", 10)
+	v, _ = c.get("a")
+	assert.Equal(t, 10, v, "add replaces an existing value")
+	assert.Equal(t, 2, c.len())
+
+	c.remove("a")
+	_, ok = c.get("a")
+	assert.False(t, ok)
+
+	c.add("d", 4)
+	c.removeFunc(func(_ string, v int) bool { return v > 3 })
+	assert.Equal(t, 1, c.len())
+	_, ok = c.get("c")
+	assert.True(t, ok)
+}
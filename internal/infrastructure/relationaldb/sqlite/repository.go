@@ -3,14 +3,21 @@ package sqlite
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 	"github.com/google/uuid"
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
@@ -24,19 +31,28 @@ func generateUUID() string {
 // timeNow returns the current time (can be mocked in tests).
 var timeNow = time.Now
 
+// schemaVersion is the schema version EnsureSchema brings databases up to,
+// recorded in SQLite's user_version. Databases created before versions were
+// recorded have version 0. Version 2 added health_samples.corroborated_facts.
+const schemaVersion = 2
+
 // Repository implements ports.RelationalDB using SQLite.
 type Repository struct {
 	db   *sql.DB
 	path string
 }
 
-// NewRepository creates a new SQLite repository.
-func NewRepository(cfg config.SQLiteConfig) (*Repository, error) {
+// DriverWrapper wraps the SQLite driver, such as to trace its statements.
+type DriverWrapper func(driver.Driver) driver.Driver
+
+// NewRepository creates a new SQLite repository. Its connections are opened
+// with the SQLite driver wrapped by wrappers, in order.
+func NewRepository(cfg config.SQLiteConfig, wrappers ...DriverWrapper) (*Repository, error) {
 	if cfg.Path == "" {
 		return nil, errors.New("sqlite path is required")
 	}
 
-	db, err := sql.Open("sqlite", cfg.Path)
+	db, err := openDB(cfg.Path, wrappers)
 	if err != nil {
 		return nil, fmt.Errorf("opening sqlite database: %w", err)
 	}
@@ -54,7 +70,11 @@ func NewRepository(cfg config.SQLiteConfig) (*Repository, error) {
 	}
 
 	// Set busy timeout to avoid "database is locked" errors
-	if _, err := db.Exec("PRAGMA busy_timeout = 5000"); err != nil {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = config.DefaultBusyTimeout
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", busyTimeout.Milliseconds())); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("setting busy timeout: %w", err)
 	}
@@ -65,19 +85,242 @@ func NewRepository(cfg config.SQLiteConfig) (*Repository, error) {
 	}, nil
 }
 
+// openDB opens the database at path with the SQLite driver wrapped by
+// wrappers.
+func openDB(path string, wrappers []DriverWrapper) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil || len(wrappers) == 0 {
+		return db, err
+	}
+
+	// Nothing is connected until first use, so the unwrapped handle only
+	// lends its driver.
+	drv := db.Driver()
+	db.Close()
+	for _, wrap := range wrappers {
+		drv = wrap(drv)
+	}
+	return sql.OpenDB(&connector{path: path, driver: drv}), nil
+}
+
+// connector opens connections to path with driver.
+type connector struct {
+	path   string
+	driver driver.Driver
+}
+
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.path)
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
 // Close closes the database connection.
 func (r *Repository) Close() error {
 	return r.db.Close()
 }
 
+// Ping checks that the database file can be read.
+func (r *Repository) Ping(ctx context.Context) error {
+	var n int
+	if err := r.db.QueryRowContext(ctx, "SELECT count(*) FROM sqlite_master").Scan(&n); err != nil {
+		return fmt.Errorf("reading database: %w", err)
+	}
+	return nil
+}
+
 // Path returns the database file path.
 func (r *Repository) Path() string {
 	return r.path
 }
 
-// EnsureSchema creates the database schema if it doesn't exist.
-func (r *Repository) EnsureSchema(ctx context.Context) error {
-	schema := `
+// BackupTo writes a consistent copy of the database to path using
+// VACUUM INTO. The directory is created if needed; path must not exist.
+func (r *Repository) BackupTo(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating backup directory: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("backing up database: %w", err)
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version this build reads and writes.
+func (r *Repository) SchemaVersion() int {
+	return schemaVersion
+}
+
+// InspectBackup checks the backup at path. See the package-level
+// InspectBackup; the repository's own database is not involved.
+func (r *Repository) InspectBackup(ctx context.Context, path string) (*entities.BackupInspection, error) {
+	return InspectBackup(ctx, path)
+}
+
+// InspectBackup checksums the backup at path, then copies it into a
+// temporary directory, checks the copy's integrity, brings it up to the
+// current schema, and counts its records. The backup is only read.
+func InspectBackup(ctx context.Context, path string) (*entities.BackupInspection, error) {
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		return nil, err
+	}
+	inspection := &entities.BackupInspection{Checksum: checksum}
+
+	dir, err := os.MkdirTemp("", "lore-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("creating restore directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	restored := filepath.Join(dir, filepath.Base(path))
+	if err := copyFile(path, restored); err != nil {
+		return nil, fmt.Errorf("copying backup: %w", err)
+	}
+
+	repo, err := NewRepository(config.SQLiteConfig{Path: restored})
+	if err != nil {
+		inspection.RestoreErr = err
+		return inspection, nil
+	}
+	defer repo.Close()
+
+	if err := repo.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&inspection.SchemaVersion); err != nil {
+		inspection.RestoreErr = fmt.Errorf("reading schema version: %w", err)
+		return inspection, nil
+	}
+	inspection.Opened = true
+	if inspection.Integrity, err = repo.integrityCheck(ctx); err != nil {
+		inspection.RestoreErr = err
+		return inspection, nil
+	}
+	if len(inspection.Integrity) > 0 {
+		return inspection, nil
+	}
+	if inspection.SchemaVersion > schemaVersion {
+		inspection.RestoreErr = fmt.Errorf("schema version %d is newer than the supported version %d", inspection.SchemaVersion, schemaVersion)
+		return inspection, nil
+	}
+	if err := repo.EnsureSchema(ctx); err != nil {
+		inspection.RestoreErr = fmt.Errorf("upgrading schema: %w", err)
+		return inspection, nil
+	}
+	if inspection.Counts, err = repo.tableCounts(ctx); err != nil {
+		inspection.RestoreErr = err
+	}
+	return inspection, nil
+}
+
+// integrityCheck returns the problems SQLite's integrity check finds.
+func (r *Repository) integrityCheck(ctx context.Context) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("scanning integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("checking integrity: %w", err)
+	}
+	return problems, nil
+}
+
+// tableCounts returns the number of records in each table.
+func (r *Repository) tableCounts(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing tables: %w", err)
+	}
+
+	counts := make(map[string]int, len(tables))
+	for _, table := range tables {
+		var n int
+		query := `SELECT COUNT(*) FROM "` + strings.ReplaceAll(table, `"`, `""`) + `"`
+		if err := r.db.QueryRowContext(ctx, query).Scan(&n); err != nil {
+			return nil, fmt.Errorf("counting %s: %w", table, err)
+		}
+		counts[table] = n
+	}
+	return counts, nil
+}
+
+// fileChecksum returns the hex-encoded SHA-256 of the file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", entities.Errorf(entities.ErrNotFound, "backup %s not found", path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("opening backup: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("reading backup: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// copyFile copies the file at src to dst, which must not exist.
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// RenameWorld moves every entity of a world to a new world ID.
+func (r *Repository) RenameWorld(ctx context.Context, oldID, newID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE entities SET world_id = ? WHERE world_id = ?", newID, oldID)
+	if err != nil {
+		return fmt.Errorf("renaming world %s to %s: %w", oldID, newID, err)
+	}
+	return nil
+}
+
+// schema creates the tables and indexes of the database, leaving those
+// that exist alone.
+const schema = `
 	-- Entities (named subjects that can have relationships)
 	CREATE TABLE IF NOT EXISTS entities (
 		id TEXT PRIMARY KEY,
@@ -103,6 +346,16 @@ func (r *Repository) EnsureSchema(ctx context.Context) error {
 	CREATE INDEX IF NOT EXISTS idx_relationships_target ON relationships(target_entity_id);
 	CREATE INDEX IF NOT EXISTS idx_relationships_type ON relationships(type);
 
+	-- Entity each fact is about, so an entity's facts are found by ID
+	-- rather than by matching subject text in the vector store
+	CREATE TABLE IF NOT EXISTS fact_subjects (
+		fact_id TEXT PRIMARY KEY,
+		entity_id TEXT NOT NULL REFERENCES entities(id) ON DELETE CASCADE,
+		source TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX IF NOT EXISTS idx_fact_subjects_entity ON fact_subjects(entity_id);
+	CREATE INDEX IF NOT EXISTS idx_fact_subjects_source ON fact_subjects(source);
+
 	-- Fact version history (tracks changes over time)
 	CREATE TABLE IF NOT EXISTS fact_versions (
 		id TEXT PRIMARY KEY,
@@ -116,6 +369,7 @@ func (r *Repository) EnsureSchema(ctx context.Context) error {
 	);
 	CREATE INDEX IF NOT EXISTS idx_fact_versions_fact ON fact_versions(fact_id);
 	CREATE INDEX IF NOT EXISTS idx_fact_versions_type ON fact_versions(change_type);
+	CREATE INDEX IF NOT EXISTS idx_fact_versions_created ON fact_versions(created_at);
 
 	-- Custom entity types (user-defined extensions to FactType)
 	CREATE TABLE IF NOT EXISTS entity_types (
@@ -135,12 +389,285 @@ func (r *Repository) EnsureSchema(ctx context.Context) error {
 	CREATE INDEX IF NOT EXISTS idx_audit_log_fact ON audit_log(fact_id);
 	CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action);
 	CREATE INDEX IF NOT EXISTS idx_audit_log_created ON audit_log(created_at);
+
+	-- Contradictions detected between pairs of facts
+	CREATE TABLE IF NOT EXISTS conflicts (
+		id TEXT PRIMARY KEY,
+		fact_id TEXT NOT NULL,
+		other_fact_id TEXT NOT NULL,
+		description TEXT,
+		severity TEXT,
+		status TEXT NOT NULL,
+		resolution TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		resolved_at TIMESTAMP,
+		UNIQUE(fact_id, other_fact_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_conflicts_fact ON conflicts(fact_id);
+	CREATE INDEX IF NOT EXISTS idx_conflicts_other ON conflicts(other_fact_id);
+	CREATE INDEX IF NOT EXISTS idx_conflicts_status ON conflicts(status);
+
+	-- Facts stating the same thing in different languages
+	CREATE TABLE IF NOT EXISTS fact_translations (
+		fact_id TEXT NOT NULL,
+		other_fact_id TEXT NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(fact_id, other_fact_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_fact_translations_other ON fact_translations(other_fact_id);
+
+	-- Consistency health measured over time
+	CREATE TABLE IF NOT EXISTS health_samples (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		recorded_at TIMESTAMP NOT NULL,
+		facts INTEGER NOT NULL,
+		open_conflicts INTEGER NOT NULL,
+		conflicting_facts INTEGER NOT NULL,
+		low_confidence_facts INTEGER NOT NULL,
+		stale_facts INTEGER NOT NULL,
+		corroborated_facts INTEGER NOT NULL DEFAULT 0,
+		entities INTEGER NOT NULL,
+		orphan_entities INTEGER NOT NULL,
+		score REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_health_samples_recorded ON health_samples(recorded_at);
+
+	-- Tokens used by each paid API call, for budgets
+	CREATE TABLE IF NOT EXISTS usage_records (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		recorded_at TIMESTAMP NOT NULL,
+		backend TEXT NOT NULL,
+		model TEXT NOT NULL DEFAULT '',
+		prompt_tokens INTEGER NOT NULL DEFAULT 0,
+		completion_tokens INTEGER NOT NULL DEFAULT 0,
+		cost REAL NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_usage_records_recorded ON usage_records(recorded_at);
+
+	-- How each extraction call went, to compare models on the world's material
+	CREATE TABLE IF NOT EXISTS extraction_stats (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		recorded_at TIMESTAMP NOT NULL,
+		model TEXT NOT NULL DEFAULT '',
+		facts INTEGER NOT NULL DEFAULT 0,
+		confidence_sum REAL NOT NULL DEFAULT 0,
+		parse_failed INTEGER NOT NULL DEFAULT 0
+	);
+
+	-- Outcomes of operations run with an idempotency key, so retries don't repeat them
+	CREATE TABLE IF NOT EXISTS idempotency_records (
+		key TEXT PRIMARY KEY,
+		operation TEXT NOT NULL,
+		request_hash TEXT NOT NULL,
+		result TEXT NOT NULL,
+		pending INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP NOT NULL
+	);
+
+	-- Change feed of facts and relationships, for syncing other systems.
+	-- Facts live in the vector store and are recorded by the application;
+	-- relationships are recorded here by triggers, so every statement that
+	-- changes them is covered.
+	CREATE TABLE IF NOT EXISTS changes (
+		seq INTEGER PRIMARY KEY AUTOINCREMENT,
+		kind TEXT NOT NULL,
+		op TEXT NOT NULL,
+		object_id TEXT NOT NULL DEFAULT '',
+		source TEXT NOT NULL DEFAULT '',
+		data TEXT NOT NULL DEFAULT '',
+		changed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+	);
+	CREATE TRIGGER IF NOT EXISTS relationships_changes_insert AFTER INSERT ON relationships
+	BEGIN
+		INSERT INTO changes (kind, op, object_id, data)
+		VALUES ('relationship', 'added', NEW.id, json_object(
+			'id', NEW.id, 'source_entity_id', NEW.source_entity_id, 'target_entity_id', NEW.target_entity_id,
+			'type', NEW.type, 'bidirectional', json(CASE WHEN NEW.bidirectional THEN 'true' ELSE 'false' END),
+			'created_at', NEW.created_at));
+	END;
+	CREATE TRIGGER IF NOT EXISTS relationships_changes_update AFTER UPDATE ON relationships
+	BEGIN
+		INSERT INTO changes (kind, op, object_id, data)
+		VALUES ('relationship', 'updated', NEW.id, json_object(
+			'id', NEW.id, 'source_entity_id', NEW.source_entity_id, 'target_entity_id', NEW.target_entity_id,
+			'type', NEW.type, 'bidirectional', json(CASE WHEN NEW.bidirectional THEN 'true' ELSE 'false' END),
+			'created_at', NEW.created_at));
+	END;
+	CREATE TRIGGER IF NOT EXISTS relationships_changes_delete AFTER DELETE ON relationships
+	BEGIN
+		INSERT INTO changes (kind, op, object_id) VALUES ('relationship', 'deleted', OLD.id);
+	END;
+
+	-- LLM consistency verdicts by fact pair, so unchanged pairs aren't rechecked
+	CREATE TABLE IF NOT EXISTS consistency_verdicts (
+		key TEXT PRIMARY KEY,
+		conflicting INTEGER NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		severity TEXT NOT NULL DEFAULT '',
+		checked_at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_consistency_verdicts_checked ON consistency_verdicts(checked_at);
+
+	-- Style sheet: canonical spellings of invented terms
+	CREATE TABLE IF NOT EXISTS style_terms (
+		key TEXT PRIMARY KEY,
+		term TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP NOT NULL
+	);
+
+	-- Length of each ingested source and the facts extracted from it
+	CREATE TABLE IF NOT EXISTS source_stats (
+		source TEXT PRIMARY KEY,
+		words INTEGER NOT NULL,
+		facts INTEGER NOT NULL,
+		ingested_at TIMESTAMP NOT NULL
+	);
+
+	-- Sources whose facts are kept but which are not ingested again
+	CREATE TABLE IF NOT EXISTS archived_sources (
+		source TEXT PRIMARY KEY,
+		archived_at TIMESTAMP NOT NULL
+	);
+
+	-- Chunks whose facts could not be extracted, kept to retry later
+	CREATE TABLE IF NOT EXISTS failed_chunks (
+		id TEXT PRIMARY KEY,
+		source TEXT NOT NULL,
+		chunk_index INTEGER NOT NULL,
+		page INTEGER NOT NULL DEFAULT 0,
+		text TEXT NOT NULL,
+		prior_context TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_failed_chunks_source ON failed_chunks(source, chunk_index);
 	`
 
+// EnsureSchema creates the database schema if it doesn't exist.
+func (r *Repository) EnsureSchema(ctx context.Context) error {
 	_, err := r.db.ExecContext(ctx, schema)
 	if err != nil {
 		return fmt.Errorf("creating schema: %w", err)
 	}
+	if err := r.addColumn(ctx, "health_samples", "corroborated_facts", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.addColumn(ctx, "failed_chunks", "page", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.addColumn(ctx, "idempotency_records", "pending", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := r.renormalizeEntityNames(ctx); err != nil {
+		return fmt.Errorf("renormalizing entity names: %w", err)
+	}
+	if err := r.utcVersionTimes(ctx); err != nil {
+		return fmt.Errorf("converting fact version times to UTC: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		return fmt.Errorf("recording schema version: %w", err)
+	}
+	return nil
+}
+
+// addColumn adds a column to a table created before the column existed.
+// Tables that already have it are left alone.
+func (r *Repository) addColumn(ctx context.Context, table, column, definition string) error {
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("inspecting %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("adding %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// renormalizeEntityNames brings the normalized names of entities stored
+// before name matching was Unicode-aware up to date. Only names with
+// non-ASCII characters can differ. An entity whose name now matches
+// another's is merged into it.
+func (r *Repository) renormalizeEntityNames(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, world_id, name, normalized_name
+		FROM entities
+		WHERE length(name) <> length(CAST(name AS BLOB))
+	`)
+	if err != nil {
+		return fmt.Errorf("querying entities: %w", err)
+	}
+
+	var stale []entities.Entity
+	for rows.Next() {
+		var e entities.Entity
+		if err := rows.Scan(&e.ID, &e.WorldID, &e.Name, &e.NormalizedName); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning entity: %w", err)
+		}
+		if e.NormalizedName != entities.NormalizeName(e.Name) {
+			stale = append(stale, e)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating entities: %w", err)
+	}
+
+	for _, e := range stale {
+		existing, err := r.FindEntityByName(ctx, e.WorldID, e.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != e.ID {
+			if err := r.MergeEntities(ctx, e.ID, existing.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err = r.db.ExecContext(ctx, `UPDATE entities SET normalized_name = ? WHERE id = ?`, entities.NormalizeName(e.Name), e.ID)
+		if err != nil {
+			return fmt.Errorf("updating entity %s: %w", e.ID, err)
+		}
+	}
+	return nil
+}
+
+// utcVersionTimes rewrites fact version times stored in a local zone,
+// before they were stored in UTC, so they compare correctly in SQL.
+func (r *Repository) utcVersionTimes(ctx context.Context) error {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, created_at FROM fact_versions WHERE created_at NOT LIKE '% +0000 UTC'`)
+	if err != nil {
+		return fmt.Errorf("querying fact versions: %w", err)
+	}
+
+	times := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var createdAt time.Time
+		if err := rows.Scan(&id, &createdAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning fact version: %w", err)
+		}
+		times[id] = createdAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating fact versions: %w", err)
+	}
+
+	for id, createdAt := range times {
+		//nolint:loopcall // Runs once, for versions stored before times were UTC
+		if _, err := r.db.ExecContext(ctx, `UPDATE fact_versions SET created_at = ? WHERE id = ?`, createdAt.UTC(), id); err != nil {
+			return fmt.Errorf("updating fact version %s: %w", id, err)
+		}
+	}
 	return nil
 }
 
@@ -360,7 +887,72 @@ func (r *Repository) DeleteEntity(ctx context.Context, entityID string) error {
 	}
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("entity not found: %s", entityID)
+		return entities.Errorf(entities.ErrNotFound, "entity not found: %s", entityID)
+	}
+	return nil
+}
+
+// MergeEntities moves every relationship and fact subject link of fromID
+// to toID and deletes fromID in a single transaction. Self-relationships and duplicates the move
+// creates are dropped, keeping the older relationship.
+func (r *Repository) MergeEntities(ctx context.Context, fromID, toID string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	statements := []struct {
+		what  string
+		query string
+		args  []any
+	}{
+		{"moving outgoing relationships", `UPDATE relationships SET source_entity_id = ? WHERE source_entity_id = ?`, []any{toID, fromID}},
+		{"moving incoming relationships", `UPDATE relationships SET target_entity_id = ? WHERE target_entity_id = ?`, []any{toID, fromID}},
+		{"dropping self-relationships", `DELETE FROM relationships WHERE source_entity_id = ? AND target_entity_id = ?`, []any{toID, toID}},
+		{"dropping duplicate relationships", `
+			DELETE FROM relationships
+			WHERE (source_entity_id = ? OR target_entity_id = ?)
+			  AND rowid NOT IN (
+				SELECT MIN(rowid) FROM relationships
+				GROUP BY source_entity_id, target_entity_id, type
+			  )`, []any{toID, toID}},
+		{"moving fact subjects", `UPDATE fact_subjects SET entity_id = ? WHERE entity_id = ?`, []any{toID, fromID}},
+	}
+	for _, s := range statements {
+		if _, err := tx.ExecContext(ctx, s.query, s.args...); err != nil {
+			return fmt.Errorf("%s: %w", s.what, err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM entities WHERE id = ?`, fromID)
+	if err != nil {
+		return fmt.Errorf("deleting entity: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return entities.Errorf(entities.ErrNotFound, "entity not found: %s", fromID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing merge: %w", err)
+	}
+	return nil
+}
+
+// RenameEntity changes an entity's name and the normalized name it is
+// found by.
+func (r *Repository) RenameEntity(ctx context.Context, entityID, name string) error {
+	query := `UPDATE entities SET name = ?, normalized_name = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, name, entities.NormalizeName(name), entityID)
+	if err != nil {
+		return fmt.Errorf("renaming entity: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return entities.Errorf(entities.ErrNotFound, "entity not found: %s", entityID)
 	}
 	return nil
 }
@@ -376,6 +968,110 @@ func (r *Repository) CountEntities(ctx context.Context, worldID string) (int, er
 	return count, nil
 }
 
+// SaveFactSubjects links facts to the entities they are about, replacing
+// the facts' earlier links.
+func (r *Repository) SaveFactSubjects(ctx context.Context, links []entities.FactSubject) (err error) {
+	if len(links) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `
+		INSERT INTO fact_subjects (fact_id, entity_id, source)
+		VALUES (?, ?, ?)
+		ON CONFLICT(fact_id) DO UPDATE SET
+			entity_id = excluded.entity_id,
+			source = excluded.source
+	`
+	for _, link := range links {
+		if _, err := tx.ExecContext(ctx, query, link.FactID, link.EntityID, link.SourceFile); err != nil {
+			return fmt.Errorf("saving fact subject: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing fact subjects: %w", err)
+	}
+	return nil
+}
+
+// DeleteFactSubjects removes the links the filter selects.
+func (r *Repository) DeleteFactSubjects(ctx context.Context, filter ports.FactSubjectFilter) error {
+	var (
+		conditions []string
+		args       []any
+	)
+	if len(filter.FactIDs) > 0 {
+		placeholders := make([]string, len(filter.FactIDs))
+		for i, id := range filter.FactIDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		conditions = append(conditions, fmt.Sprintf("fact_id IN (%s)", strings.Join(placeholders, ",")))
+	}
+	if filter.SourceFile != "" {
+		conditions = append(conditions, "source = ?")
+		args = append(args, filter.SourceFile)
+	}
+
+	query := `DELETE FROM fact_subjects`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("deleting fact subjects: %w", err)
+	}
+	return nil
+}
+
+// ListFactIDsByEntities returns the IDs of up to limit facts linked to any
+// of the entities, in the order they were linked.
+func (r *Repository) ListFactIDsByEntities(ctx context.Context, entityIDs []string, limit int) ([]string, error) {
+	if len(entityIDs) == 0 {
+		return []string{}, nil
+	}
+
+	placeholders := make([]string, len(entityIDs))
+	args := make([]any, 0, len(entityIDs)+1)
+	for i, id := range entityIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT fact_id
+		FROM fact_subjects
+		WHERE entity_id IN (%s)
+		ORDER BY rowid
+		LIMIT ?
+	`, strings.Join(placeholders, ","))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying fact subjects: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning fact subject: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // SaveRelationship saves or updates a relationship.
 func (r *Repository) SaveRelationship(ctx context.Context, rel *entities.Relationship) error {
 	query := `
@@ -413,18 +1109,115 @@ func (r *Repository) FindRelationshipsByEntity(ctx context.Context, entityID str
 	return r.queryRelationships(ctx, query, entityID, entityID)
 }
 
-// FindRelationshipsByType finds all relationships of a given type.
-func (r *Repository) FindRelationshipsByType(ctx context.Context, relType string) ([]entities.Relationship, error) {
-	query := `
-		SELECT id, source_entity_id, target_entity_id, type, bidirectional, created_at
-		FROM relationships
-		WHERE type = ?
-		ORDER BY created_at DESC
-	`
-	return r.queryRelationships(ctx, query, relType)
+// relationshipOrderClauses maps sort options to ORDER BY clauses.
+// The "other" alias is the entity on the opposite end from the listed entity.
+var relationshipOrderClauses = map[ports.RelationshipSort]string{
+	ports.RelationshipSortCreated: "r.created_at DESC, r.id ASC",
+	ports.RelationshipSortType:    "r.type ASC, r.created_at DESC, r.id ASC",
+	ports.RelationshipSortTarget:  "other.normalized_name ASC, r.type ASC, r.id ASC",
 }
 
-// DeleteRelationship deletes a relationship by ID.
+// ListRelationshipsByEntity lists relationships involving an entity with
+// filtering, ordering, and pagination applied in SQL.
+// Sorting by target joins the entities table on whichever end of the
+// relationship is not the listed entity.
+func (r *Repository) ListRelationshipsByEntity(ctx context.Context, entityID string, opts ports.RelationshipListOptions) ([]entities.Relationship, error) {
+	sortBy := opts.Sort
+	if sortBy == "" {
+		sortBy = ports.RelationshipSortCreated
+	}
+	orderClause, ok := relationshipOrderClauses[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid relationship sort: %s", sortBy)
+	}
+
+	// SQLite treats a negative LIMIT as "no limit"
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	query := fmt.Sprintf(`
+		SELECT r.id, r.source_entity_id, r.target_entity_id, r.type, r.bidirectional, r.created_at
+		FROM relationships r
+		LEFT JOIN entities other ON other.id = CASE
+			WHEN r.source_entity_id = ? THEN r.target_entity_id
+			ELSE r.source_entity_id
+		END
+		WHERE (r.source_entity_id = ? OR (r.target_entity_id = ? AND r.bidirectional = 1))
+		  AND (? = '' OR r.type = ?)
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, orderClause)
+
+	return r.queryRelationships(ctx, query,
+		entityID, entityID, entityID,
+		opts.Type, opts.Type,
+		limit, opts.Offset,
+	)
+}
+
+// CountRelationshipsByEntity counts relationships involving an entity,
+// optionally filtered by type.
+func (r *Repository) CountRelationshipsByEntity(ctx context.Context, entityID string, relType string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM relationships
+		WHERE (source_entity_id = ? OR (target_entity_id = ? AND bidirectional = 1))
+		  AND (? = '' OR type = ?)
+	`
+	var count int
+	err := r.db.QueryRowContext(ctx, query, entityID, entityID, relType, relType).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting relationships by entity: %w", err)
+	}
+	return count, nil
+}
+
+// CountRelationshipsPerEntity returns the relationship degree of every
+// entity in a world, keyed by entity ID.
+func (r *Repository) CountRelationshipsPerEntity(ctx context.Context, worldID string) (map[string]int, error) {
+	query := `
+		SELECT e.id, COUNT(rel.id)
+		FROM entities e
+		LEFT JOIN relationships rel
+		  ON rel.source_entity_id = e.id OR rel.target_entity_id = e.id
+		WHERE e.world_id = ?
+		GROUP BY e.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, worldID)
+	if err != nil {
+		return nil, fmt.Errorf("counting relationships per entity: %w", err)
+	}
+	defer rows.Close()
+
+	degrees := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scanning relationship count: %w", err)
+		}
+		degrees[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating relationship counts: %w", err)
+	}
+	return degrees, nil
+}
+
+// FindRelationshipsByType finds all relationships of a given type.
+func (r *Repository) FindRelationshipsByType(ctx context.Context, relType string) ([]entities.Relationship, error) {
+	query := `
+		SELECT id, source_entity_id, target_entity_id, type, bidirectional, created_at
+		FROM relationships
+		WHERE type = ?
+		ORDER BY created_at DESC
+	`
+	return r.queryRelationships(ctx, query, relType)
+}
+
+// DeleteRelationship deletes a relationship by ID.
 func (r *Repository) DeleteRelationship(ctx context.Context, id string) error {
 	query := `DELETE FROM relationships WHERE id = ?`
 	result, err := r.db.ExecContext(ctx, query, id)
@@ -433,7 +1226,7 @@ func (r *Repository) DeleteRelationship(ctx context.Context, id string) error {
 	}
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("relationship not found: %s", id)
+		return entities.Errorf(entities.ErrNotFound, "relationship not found: %s", id)
 	}
 	return nil
 }
@@ -594,7 +1387,7 @@ func (r *Repository) SaveVersion(ctx context.Context, version *entities.FactVers
 		string(version.ChangeType),
 		string(data),
 		version.Reason,
-		version.CreatedAt,
+		version.CreatedAt.UTC(),
 	)
 	if err != nil {
 		return fmt.Errorf("saving fact version: %w", err)
@@ -679,6 +1472,37 @@ func (r *Repository) CountVersions(ctx context.Context, factID string) (int, err
 	return count, nil
 }
 
+// ListVersionsAsOf returns the latest version at or before asOf of each
+// fact changed after asOf.
+func (r *Repository) ListVersionsAsOf(ctx context.Context, asOf time.Time) ([]entities.FactVersion, error) {
+	query := `
+		SELECT id, fact_id, version, change_type, data, reason, created_at
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY fact_id ORDER BY version DESC) AS rank
+			FROM fact_versions
+			WHERE created_at <= ?
+		)
+		WHERE rank = 1
+		  AND fact_id IN (SELECT fact_id FROM fact_versions WHERE created_at > ?)
+		ORDER BY fact_id
+	`
+	rows, err := r.db.QueryContext(ctx, query, asOf.UTC(), asOf.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("querying fact versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []entities.FactVersion
+	for rows.Next() {
+		v, err := r.scanFactVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
 // scanFactVersion is a helper to scan a fact version row.
 func (r *Repository) scanFactVersion(rows *sql.Rows) (*entities.FactVersion, error) {
 	var v entities.FactVersion
@@ -787,7 +1611,7 @@ func (r *Repository) DeleteEntityType(ctx context.Context, name string) error {
 	}
 	rows, _ := result.RowsAffected()
 	if rows == 0 {
-		return fmt.Errorf("entity type not found: %s", name)
+		return entities.Errorf(entities.ErrNotFound, "entity type not found: %s", name)
 	}
 	return nil
 }
@@ -881,3 +1705,853 @@ func (r *Repository) queryAuditLog(ctx context.Context, query string, args ...an
 	}
 	return entries, rows.Err()
 }
+
+// SaveConflict records a conflict. A pair of facts that is already recorded
+// is left as is, so resolved conflicts stay resolved.
+func (r *Repository) SaveConflict(ctx context.Context, conflict *entities.Conflict) (bool, error) {
+	query := `
+		INSERT INTO conflicts (id, fact_id, other_fact_id, description, severity, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(fact_id, other_fact_id) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		conflict.ID,
+		conflict.FactID,
+		conflict.OtherFactID,
+		conflict.Description,
+		conflict.Severity,
+		string(conflict.Status),
+		conflict.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("saving conflict: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ListConflicts lists conflicts, newest first.
+func (r *Repository) ListConflicts(ctx context.Context, opts ports.ConflictListOptions) ([]entities.Conflict, error) {
+	// SQLite treats a negative LIMIT as "no limit"
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	query := `
+		SELECT id, fact_id, other_fact_id, description, severity, status, resolution, created_at, resolved_at
+		FROM conflicts
+		WHERE (? = '' OR status = ?)
+		  AND (? = '' OR fact_id = ? OR other_fact_id = ?)
+		ORDER BY created_at DESC, id
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query,
+		string(opts.Status), string(opts.Status),
+		opts.FactID, opts.FactID, opts.FactID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("querying conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []entities.Conflict
+	for rows.Next() {
+		var c entities.Conflict
+		var status string
+		var description, severity, resolution sql.NullString
+		var resolvedAt sql.NullTime
+
+		if err := rows.Scan(
+			&c.ID,
+			&c.FactID,
+			&c.OtherFactID,
+			&description,
+			&severity,
+			&status,
+			&resolution,
+			&c.CreatedAt,
+			&resolvedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning conflict: %w", err)
+		}
+
+		c.Description = description.String
+		c.Severity = severity.String
+		c.Status = entities.ConflictStatus(status)
+		c.Resolution = resolution.String
+		c.ResolvedAt = resolvedAt.Time
+		conflicts = append(conflicts, c)
+	}
+	return conflicts, rows.Err()
+}
+
+// ResolveConflict marks a conflict as resolved.
+func (r *Repository) ResolveConflict(ctx context.Context, id string, resolution string, resolvedAt time.Time) error {
+	query := `UPDATE conflicts SET status = ?, resolution = ?, resolved_at = ? WHERE id = ?`
+	result, err := r.db.ExecContext(ctx, query, string(entities.ConflictResolved), resolution, resolvedAt, id)
+	if err != nil {
+		return fmt.Errorf("resolving conflict: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return entities.Errorf(entities.ErrNotFound, "conflict not found: %s", id)
+	}
+	return nil
+}
+
+// CountOpenConflicts returns the number of open conflicts each of the given
+// facts is part of. Facts without open conflicts are omitted.
+func (r *Repository) CountOpenConflicts(ctx context.Context, factIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(factIDs) == 0 {
+		return counts, nil
+	}
+
+	// Build placeholders for IN clause, used once per end of the pair
+	placeholders := make([]string, len(factIDs))
+	ids := make([]any, len(factIDs))
+	for i, id := range factIDs {
+		placeholders[i] = "?"
+		ids[i] = id
+	}
+	args := append([]any{string(entities.ConflictOpen)}, ids...)
+	args = append(args, string(entities.ConflictOpen))
+	args = append(args, ids...)
+
+	in := strings.Join(placeholders, ",")
+	query := fmt.Sprintf(`
+		SELECT id, COUNT(*) FROM (
+			SELECT fact_id AS id FROM conflicts WHERE status = ? AND fact_id IN (%s)
+			UNION ALL
+			SELECT other_fact_id AS id FROM conflicts WHERE status = ? AND other_fact_id IN (%s)
+		)
+		GROUP BY id
+	`, in, in)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("counting open conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("scanning conflict count: %w", err)
+		}
+		counts[id] = count
+	}
+	return counts, rows.Err()
+}
+
+// SaveTranslation links two facts as translations of each other. A pair
+// that is already linked is left as is.
+func (r *Repository) SaveTranslation(ctx context.Context, translation *entities.Translation) (bool, error) {
+	query := `
+		INSERT INTO fact_translations (fact_id, other_fact_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(fact_id, other_fact_id) DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, translation.FactID, translation.OtherFactID, translation.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("saving translation: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows > 0, nil
+}
+
+// ListTranslations lists the translation links involving any of the given
+// facts, or every link if no facts are given, oldest first.
+func (r *Repository) ListTranslations(ctx context.Context, factIDs []string) ([]entities.Translation, error) {
+	query := `SELECT fact_id, other_fact_id, created_at FROM fact_translations`
+	var args []any
+	if len(factIDs) > 0 {
+		placeholders := make([]string, len(factIDs))
+		ids := make([]any, len(factIDs))
+		for i, id := range factIDs {
+			placeholders[i] = "?"
+			ids[i] = id
+		}
+		in := strings.Join(placeholders, ",")
+		query += fmt.Sprintf(` WHERE fact_id IN (%s) OR other_fact_id IN (%s)`, in, in)
+		args = append(args, ids...)
+		args = append(args, ids...)
+	}
+	query += ` ORDER BY created_at, fact_id, other_fact_id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying translations: %w", err)
+	}
+	defer rows.Close()
+
+	var translations []entities.Translation
+	for rows.Next() {
+		var t entities.Translation
+		if err := rows.Scan(&t.FactID, &t.OtherFactID, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning translation: %w", err)
+		}
+		translations = append(translations, t)
+	}
+	return translations, rows.Err()
+}
+
+// SaveHealthSample records a health measurement and sets its ID.
+func (r *Repository) SaveHealthSample(ctx context.Context, sample *entities.HealthSample) error {
+	query := `
+		INSERT INTO health_samples (recorded_at, facts, open_conflicts, conflicting_facts,
+			low_confidence_facts, stale_facts, corroborated_facts, entities, orphan_entities, score)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		sample.RecordedAt,
+		sample.Facts,
+		sample.OpenConflicts,
+		sample.ConflictingFacts,
+		sample.LowConfidenceFacts,
+		sample.StaleFacts,
+		sample.CorroboratedFacts,
+		sample.Entities,
+		sample.OrphanEntities,
+		sample.Score,
+	)
+	if err != nil {
+		return fmt.Errorf("saving health sample: %w", err)
+	}
+	sample.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// ListHealthSamples returns the most recent health measurements, oldest first.
+func (r *Repository) ListHealthSamples(ctx context.Context, limit int) ([]entities.HealthSample, error) {
+	// SQLite treats a negative LIMIT as "no limit"
+	if limit <= 0 {
+		limit = -1
+	}
+
+	query := `
+		SELECT id, recorded_at, facts, open_conflicts, conflicting_facts,
+			low_confidence_facts, stale_facts, corroborated_facts, entities, orphan_entities, score
+		FROM (
+			SELECT * FROM health_samples
+			ORDER BY recorded_at DESC, id DESC
+			LIMIT ?
+		)
+		ORDER BY recorded_at, id
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("querying health samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []entities.HealthSample
+	for rows.Next() {
+		var s entities.HealthSample
+		if err := rows.Scan(
+			&s.ID,
+			&s.RecordedAt,
+			&s.Facts,
+			&s.OpenConflicts,
+			&s.ConflictingFacts,
+			&s.LowConfidenceFacts,
+			&s.StaleFacts,
+			&s.CorroboratedFacts,
+			&s.Entities,
+			&s.OrphanEntities,
+			&s.Score,
+		); err != nil {
+			return nil, fmt.Errorf("scanning health sample: %w", err)
+		}
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// SaveUsage records a call's API usage and sets its ID.
+func (r *Repository) SaveUsage(ctx context.Context, record *entities.UsageRecord) error {
+	query := `
+		INSERT INTO usage_records (recorded_at, backend, model, prompt_tokens, completion_tokens, cost)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		record.RecordedAt.UTC(),
+		record.Backend,
+		record.Model,
+		record.PromptTokens,
+		record.CompletionTokens,
+		record.Cost,
+	)
+	if err != nil {
+		return fmt.Errorf("saving usage record: %w", err)
+	}
+	record.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// SumUsage totals the usage recorded at or after since, by backend and model.
+func (r *Repository) SumUsage(ctx context.Context, since time.Time) ([]entities.UsageTotals, error) {
+	query := `
+		SELECT backend, model, COUNT(*), SUM(prompt_tokens), SUM(completion_tokens), SUM(cost)
+		FROM usage_records
+		WHERE recorded_at >= ?
+		GROUP BY backend, model
+		ORDER BY backend, model
+	`
+	rows, err := r.db.QueryContext(ctx, query, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("summing usage: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []entities.UsageTotals
+	for rows.Next() {
+		var t entities.UsageTotals
+		if err := rows.Scan(&t.Backend, &t.Model, &t.Calls, &t.PromptTokens, &t.CompletionTokens, &t.Cost); err != nil {
+			return nil, fmt.Errorf("scanning usage totals: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// SaveExtractionStat records how an extraction call went and sets its ID.
+func (r *Repository) SaveExtractionStat(ctx context.Context, stat *entities.ExtractionStat) error {
+	query := `
+		INSERT INTO extraction_stats (recorded_at, model, facts, confidence_sum, parse_failed)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		stat.RecordedAt.UTC(),
+		stat.Model,
+		stat.Facts,
+		stat.ConfidenceSum,
+		stat.ParseFailed,
+	)
+	if err != nil {
+		return fmt.Errorf("saving extraction stat: %w", err)
+	}
+	stat.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// SumExtractionStats totals the extraction calls recorded, by model.
+func (r *Repository) SumExtractionStats(ctx context.Context) ([]entities.ModelStats, error) {
+	query := `
+		SELECT model, COUNT(*), SUM(parse_failed), SUM(facts), SUM(confidence_sum)
+		FROM extraction_stats
+		GROUP BY model
+		ORDER BY model
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("summing extraction stats: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []entities.ModelStats
+	for rows.Next() {
+		var s entities.ModelStats
+		if err := rows.Scan(&s.Model, &s.Calls, &s.ParseFailures, &s.Facts, &s.ConfidenceSum); err != nil {
+			return nil, fmt.Errorf("scanning extraction stats: %w", err)
+		}
+		totals = append(totals, s)
+	}
+	return totals, rows.Err()
+}
+
+// FindIdempotencyRecord returns the record of an idempotency key, or nil
+// if the key has not been used.
+func (r *Repository) FindIdempotencyRecord(ctx context.Context, key string) (*entities.IdempotencyRecord, error) {
+	query := `
+		SELECT key, operation, request_hash, result, pending, created_at
+		FROM idempotency_records
+		WHERE key = ?
+	`
+	var record entities.IdempotencyRecord
+	var result string
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.Operation,
+		&record.RequestHash,
+		&result,
+		&record.Pending,
+		&record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying idempotency record: %w", err)
+	}
+	record.Result = []byte(result)
+	return &record, nil
+}
+
+// SaveIdempotencyRecord stores a record, replacing any with the same key.
+func (r *Repository) SaveIdempotencyRecord(ctx context.Context, record *entities.IdempotencyRecord) error {
+	query := `
+		INSERT INTO idempotency_records (key, operation, request_hash, result, pending, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			operation = excluded.operation,
+			request_hash = excluded.request_hash,
+			result = excluded.result,
+			pending = excluded.pending,
+			created_at = excluded.created_at
+	`
+	if _, err := r.db.ExecContext(ctx, query,
+		record.Key,
+		record.Operation,
+		record.RequestHash,
+		string(record.Result),
+		record.Pending,
+		record.CreatedAt.UTC(),
+	); err != nil {
+		return fmt.Errorf("saving idempotency record: %w", err)
+	}
+	return nil
+}
+
+// ClaimIdempotencyKey stores record unless its key has a record created
+// after cutoff, reporting whether it was stored. An expired record is
+// deleted first; the insert then succeeds for only one of concurrent
+// claims.
+func (r *Repository) ClaimIdempotencyKey(ctx context.Context, record *entities.IdempotencyRecord, cutoff time.Time) (bool, error) {
+	if _, err := r.db.ExecContext(ctx,
+		"DELETE FROM idempotency_records WHERE key = ? AND created_at <= ?",
+		record.Key, cutoff.UTC(),
+	); err != nil {
+		return false, fmt.Errorf("deleting expired idempotency record: %w", err)
+	}
+
+	query := `
+		INSERT INTO idempotency_records (key, operation, request_hash, result, pending, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query,
+		record.Key,
+		record.Operation,
+		record.RequestHash,
+		string(record.Result),
+		record.Pending,
+		record.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("claiming idempotency key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claiming idempotency key: %w", err)
+	}
+	return n == 1, nil
+}
+
+// ReleaseIdempotencyKey deletes the record of a key if it is pending.
+func (r *Repository) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_records WHERE key = ? AND pending = 1", key); err != nil {
+		return fmt.Errorf("releasing idempotency key: %w", err)
+	}
+	return nil
+}
+
+// RecordChanges appends fact changes to the change feed, setting each
+// one's Seq.
+func (r *Repository) RecordChanges(ctx context.Context, changes []entities.Change) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	query := `
+		INSERT INTO changes (kind, op, object_id, source, data, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	for i := range changes {
+		c := &changes[i]
+		data, err := changeData(c)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, query, string(c.Kind), string(c.Op), c.ID, c.Source, data, c.ChangedAt.UTC())
+		if err != nil {
+			return fmt.Errorf("recording change: %w", err)
+		}
+		if c.Seq, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("getting change seq: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing changes: %w", err)
+	}
+	return nil
+}
+
+// changeData encodes the state a change left, if any.
+func changeData(c *entities.Change) (string, error) {
+	var state any
+	switch {
+	case c.Fact != nil:
+		state = c.Fact
+	case c.Relationship != nil:
+		state = c.Relationship
+	default:
+		return "", nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("encoding change: %w", err)
+	}
+	return string(data), nil
+}
+
+// ListChanges returns up to limit changes recorded after the one with seq
+// after, oldest first.
+func (r *Repository) ListChanges(ctx context.Context, after int64, limit int) ([]entities.Change, error) {
+	query := `
+		SELECT seq, kind, op, object_id, source, data, changed_at
+		FROM changes
+		WHERE seq > ?
+		ORDER BY seq
+		LIMIT ?
+	`
+	rows, err := r.db.QueryContext(ctx, query, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []entities.Change
+	for rows.Next() {
+		var (
+			c    entities.Change
+			data string
+		)
+		if err := rows.Scan(&c.Seq, &c.Kind, &c.Op, &c.ID, &c.Source, &data, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("scanning change: %w", err)
+		}
+		if data != "" {
+			switch c.Kind {
+			case entities.ChangeKindFact:
+				c.Fact = &entities.Fact{}
+				err = json.Unmarshal([]byte(data), c.Fact)
+			case entities.ChangeKindRelationship:
+				c.Relationship, err = decodeChangedRelationship(data)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("decoding change %d: %w", c.Seq, err)
+			}
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// storedTimeLayout is how the driver stores time.Time values, as the
+// change triggers copy them.
+const storedTimeLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+// decodeChangedRelationship decodes a relationship as the change triggers
+// record it, with created_at as stored.
+func decodeChangedRelationship(data string) (*entities.Relationship, error) {
+	var recorded struct {
+		entities.Relationship
+		CreatedAt string `json:"created_at"`
+	}
+	if err := json.Unmarshal([]byte(data), &recorded); err != nil {
+		return nil, err
+	}
+	rel := recorded.Relationship
+	if recorded.CreatedAt != "" {
+		createdAt, err := time.Parse(storedTimeLayout, recorded.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing created_at: %w", err)
+		}
+		rel.CreatedAt = createdAt
+	}
+	return &rel, nil
+}
+
+// verdictLookupBatch caps the keys looked up per query, keeping each
+// query well under SQLite's limit on bound parameters.
+const verdictLookupBatch = 500
+
+// FindConsistencyVerdicts returns the verdicts with the given keys checked
+// at or after since, by key.
+func (r *Repository) FindConsistencyVerdicts(ctx context.Context, keys []string, since time.Time) (map[string]entities.ConsistencyVerdict, error) {
+	verdicts := make(map[string]entities.ConsistencyVerdict)
+	for start := 0; start < len(keys); start += verdictLookupBatch {
+		batch := keys[start:min(start+verdictLookupBatch, len(keys))]
+		args := make([]any, 0, len(batch)+1)
+		for _, key := range batch {
+			args = append(args, key)
+		}
+		// Stored in UTC, so times compare correctly as text
+		args = append(args, since.UTC())
+
+		query := fmt.Sprintf(`
+			SELECT key, conflicting, description, severity, checked_at
+			FROM consistency_verdicts
+			WHERE key IN (%s) AND checked_at >= ?
+		`, strings.TrimSuffix(strings.Repeat("?,", len(batch)), ","))
+		//nolint:loopcall // Batched to stay under SQLite's parameter limit
+		if err := r.scanConsistencyVerdicts(ctx, verdicts, query, args); err != nil {
+			return nil, err
+		}
+	}
+	return verdicts, nil
+}
+
+func (r *Repository) scanConsistencyVerdicts(ctx context.Context, into map[string]entities.ConsistencyVerdict, query string, args []any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("querying consistency verdicts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v entities.ConsistencyVerdict
+		if err := rows.Scan(&v.Key, &v.Conflicting, &v.Description, &v.Severity, &v.CheckedAt); err != nil {
+			return fmt.Errorf("scanning consistency verdict: %w", err)
+		}
+		into[v.Key] = v
+	}
+	return rows.Err()
+}
+
+// SaveConsistencyVerdicts stores verdicts in one transaction, replacing
+// any with the same key.
+func (r *Repository) SaveConsistencyVerdicts(ctx context.Context, verdicts []entities.ConsistencyVerdict) (err error) {
+	if len(verdicts) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO consistency_verdicts (key, conflicting, description, severity, checked_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			conflicting = excluded.conflicting,
+			description = excluded.description,
+			severity = excluded.severity,
+			checked_at = excluded.checked_at
+	`)
+	if err != nil {
+		return fmt.Errorf("preparing consistency verdict insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i := range verdicts {
+		v := &verdicts[i]
+		if _, err := stmt.ExecContext(ctx, v.Key, v.Conflicting, v.Description, v.Severity, v.CheckedAt.UTC()); err != nil {
+			return fmt.Errorf("saving consistency verdict: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing consistency verdicts: %w", err)
+	}
+	return nil
+}
+
+// PruneConsistencyVerdicts deletes verdicts checked before cutoff.
+func (r *Repository) PruneConsistencyVerdicts(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM consistency_verdicts WHERE checked_at < ?`, cutoff.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("pruning consistency verdicts: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return int(rows), nil
+}
+
+// SaveStyleTerm stores a style sheet entry, replacing any with the same key.
+func (r *Repository) SaveStyleTerm(ctx context.Context, term *entities.StyleTerm) error {
+	query := `
+		INSERT INTO style_terms (key, term, source, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(key) DO UPDATE SET
+			term = excluded.term,
+			source = excluded.source,
+			created_at = excluded.created_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, term.Key, term.Term, term.Source, term.CreatedAt.UTC()); err != nil {
+		return fmt.Errorf("saving style term: %w", err)
+	}
+	return nil
+}
+
+// ListStyleTerms returns the style sheet, ordered by term.
+func (r *Repository) ListStyleTerms(ctx context.Context) ([]entities.StyleTerm, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT key, term, source, created_at FROM style_terms ORDER BY term`)
+	if err != nil {
+		return nil, fmt.Errorf("querying style terms: %w", err)
+	}
+	defer rows.Close()
+
+	var terms []entities.StyleTerm
+	for rows.Next() {
+		var t entities.StyleTerm
+		if err := rows.Scan(&t.Key, &t.Term, &t.Source, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning style term: %w", err)
+		}
+		terms = append(terms, t)
+	}
+	return terms, rows.Err()
+}
+
+// SaveSourceStats records a source's latest ingest, replacing any earlier
+// record of the same source.
+func (r *Repository) SaveSourceStats(ctx context.Context, stats *entities.SourceStats) error {
+	query := `
+		INSERT INTO source_stats (source, words, facts, ingested_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			words = excluded.words,
+			facts = excluded.facts,
+			ingested_at = excluded.ingested_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, stats.Source, stats.Words, stats.Facts, stats.IngestedAt.UTC()); err != nil {
+		return fmt.Errorf("saving source stats: %w", err)
+	}
+	return nil
+}
+
+// ListSourceStats returns the recorded sources, ordered by source.
+func (r *Repository) ListSourceStats(ctx context.Context) ([]entities.SourceStats, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT source, words, facts, ingested_at FROM source_stats ORDER BY source`)
+	if err != nil {
+		return nil, fmt.Errorf("querying source stats: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []entities.SourceStats
+	for rows.Next() {
+		var s entities.SourceStats
+		if err := rows.Scan(&s.Source, &s.Words, &s.Facts, &s.IngestedAt); err != nil {
+			return nil, fmt.Errorf("scanning source stats: %w", err)
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// SaveArchivedSource records a source as archived, replacing any earlier
+// record of the same source.
+func (r *Repository) SaveArchivedSource(ctx context.Context, source *entities.ArchivedSource) error {
+	query := `
+		INSERT INTO archived_sources (source, archived_at)
+		VALUES (?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			archived_at = excluded.archived_at
+	`
+	if _, err := r.db.ExecContext(ctx, query, source.Source, source.ArchivedAt.UTC()); err != nil {
+		return fmt.Errorf("saving archived source: %w", err)
+	}
+	return nil
+}
+
+// DeleteArchivedSource removes a source's archived record.
+func (r *Repository) DeleteArchivedSource(ctx context.Context, source string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM archived_sources WHERE source = ?`, source)
+	if err != nil {
+		return fmt.Errorf("deleting archived source: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return entities.Errorf(entities.ErrNotFound, "source is not archived: %s", source)
+	}
+	return nil
+}
+
+// ListArchivedSources returns the archived sources, ordered by source.
+func (r *Repository) ListArchivedSources(ctx context.Context) ([]entities.ArchivedSource, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT source, archived_at FROM archived_sources ORDER BY source`)
+	if err != nil {
+		return nil, fmt.Errorf("querying archived sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []entities.ArchivedSource
+	for rows.Next() {
+		var s entities.ArchivedSource
+		if err := rows.Scan(&s.Source, &s.ArchivedAt); err != nil {
+			return nil, fmt.Errorf("scanning archived source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// SaveFailedChunk stores a quarantined chunk, replacing any with the same ID.
+func (r *Repository) SaveFailedChunk(ctx context.Context, chunk *entities.FailedChunk) error {
+	query := `
+		INSERT INTO failed_chunks (id, source, chunk_index, page, text, prior_context, error, attempts, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			error = excluded.error,
+			attempts = excluded.attempts,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		chunk.ID, chunk.Source, chunk.Index, chunk.Page, chunk.Text, chunk.PriorContext,
+		chunk.Error, chunk.Attempts, chunk.CreatedAt.UTC(), chunk.UpdatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("saving failed chunk: %w", err)
+	}
+	return nil
+}
+
+// ListFailedChunks returns the quarantined chunks, ordered by source and
+// position.
+func (r *Repository) ListFailedChunks(ctx context.Context) ([]entities.FailedChunk, error) {
+	query := `
+		SELECT id, source, chunk_index, page, text, prior_context, error, attempts, created_at, updated_at
+		FROM failed_chunks
+		ORDER BY source, chunk_index
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("querying failed chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []entities.FailedChunk
+	for rows.Next() {
+		var c entities.FailedChunk
+		if err := rows.Scan(&c.ID, &c.Source, &c.Index, &c.Page, &c.Text, &c.PriorContext, &c.Error, &c.Attempts, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning failed chunk: %w", err)
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks, rows.Err()
+}
+
+// DeleteFailedChunk removes a quarantined chunk.
+func (r *Repository) DeleteFailedChunk(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM failed_chunks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting failed chunk: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return entities.Errorf(entities.ErrNotFound, "failed chunk not found: %s", id)
+	}
+	return nil
+}
@@ -2,10 +2,15 @@ package sqlite
 
 import (
 	"context"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,6 +41,122 @@ func TestNewRepository(t *testing.T) {
 		_, err := NewRepository(config.SQLiteConfig{Path: ""})
 		require.Error(t, err)
 	})
+
+	t.Run("busy timeout", func(t *testing.T) {
+		for _, tt := range []struct {
+			configured time.Duration
+			wantMillis int
+		}{
+			{0, 5000},
+			{1500 * time.Millisecond, 1500},
+		} {
+			repo, err := NewRepository(config.SQLiteConfig{Path: ":memory:", BusyTimeout: tt.configured})
+			require.NoError(t, err)
+
+			var got int
+			require.NoError(t, repo.db.QueryRow("PRAGMA busy_timeout").Scan(&got))
+			assert.Equal(t, tt.wantMillis, got)
+			repo.Close()
+		}
+	})
+
+	t.Run("driver wrappers", func(t *testing.T) {
+		var wrapped []string
+		wrapper := func(name string) DriverWrapper {
+			return func(d driver.Driver) driver.Driver {
+				wrapped = append(wrapped, name)
+				return d
+			}
+		}
+
+		repo, err := NewRepository(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "lore.db")}, wrapper("first"), wrapper("second"))
+		require.NoError(t, err)
+		defer repo.Close()
+
+		require.NoError(t, repo.EnsureSchema(context.Background()))
+		assert.Equal(t, []string{"first", "second"}, wrapped)
+
+		var fk int
+		require.NoError(t, repo.db.QueryRow("PRAGMA foreign_keys").Scan(&fk))
+		assert.Equal(t, 1, fk, "pragmas are set through the wrapped driver")
+	})
+}
+
+func TestRepository_BackupTo(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := NewRepository(config.SQLiteConfig{Path: filepath.Join(dir, "lore.db")})
+	require.NoError(t, err)
+	defer repo.Close()
+	require.NoError(t, repo.EnsureSchema(ctx))
+
+	_, err = repo.FindOrCreateEntity(ctx, "world-1", "Gandalf")
+	require.NoError(t, err)
+
+	backupPath := filepath.Join(dir, "snapshots", "backup.db")
+	require.NoError(t, repo.BackupTo(ctx, backupPath))
+
+	backup, err := NewRepository(config.SQLiteConfig{Path: backupPath})
+	require.NoError(t, err)
+	defer backup.Close()
+
+	entity, err := backup.FindEntityByName(ctx, "world-1", "gandalf")
+	require.NoError(t, err)
+	require.NotNil(t, entity)
+	assert.Equal(t, "Gandalf", entity.Name)
+
+	t.Run("existing path fails", func(t *testing.T) {
+		require.Error(t, repo.BackupTo(ctx, backupPath))
+	})
+}
+
+func TestInspectBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	repo, err := NewRepository(config.SQLiteConfig{Path: filepath.Join(dir, "lore.db")})
+	require.NoError(t, err)
+	defer repo.Close()
+	require.NoError(t, repo.EnsureSchema(ctx))
+	for _, name := range []string{"Gandalf", "Frodo"} {
+		_, err := repo.FindOrCreateEntity(ctx, "world-1", name)
+		require.NoError(t, err)
+	}
+
+	backupPath := filepath.Join(dir, "backup.db")
+	require.NoError(t, repo.BackupTo(ctx, backupPath))
+	before, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+
+	inspection, err := repo.InspectBackup(ctx, backupPath)
+	require.NoError(t, err)
+	assert.True(t, inspection.OK())
+	assert.True(t, inspection.Opened)
+	assert.Len(t, inspection.Checksum, 64)
+	assert.Equal(t, repo.SchemaVersion(), inspection.SchemaVersion)
+	assert.Equal(t, 2, inspection.Counts["entities"])
+	assert.Contains(t, inspection.Counts, "relationships")
+
+	after, err := os.ReadFile(backupPath)
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "backup not modified")
+
+	t.Run("not a database", func(t *testing.T) {
+		path := filepath.Join(dir, "notes.db")
+		require.NoError(t, os.WriteFile(path, []byte("not a database, just some notes about hobbits"), 0600))
+
+		inspection, err := InspectBackup(ctx, path)
+		require.NoError(t, err)
+		assert.False(t, inspection.OK())
+		assert.False(t, inspection.Opened)
+		assert.Error(t, inspection.RestoreErr)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := InspectBackup(ctx, filepath.Join(dir, "missing.db"))
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+	})
 }
 
 func TestRepository_EnsureSchema(t *testing.T) {
@@ -51,6 +172,57 @@ func TestRepository_EnsureSchema(t *testing.T) {
 	}
 }
 
+func TestRepository_EnsureSchema_RenormalizesEntityNames(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	// Normalized names as stored before matching was Unicode-aware
+	for _, e := range []*entities.Entity{
+		{ID: "upper", WorldID: "w", Name: "ΟΔΥΣΣΕΥΣ", NormalizedName: "οδυσσευσ"},
+		{ID: "final", WorldID: "w", Name: "Οδυσσευς", NormalizedName: "οδυσσευς"},
+		{ID: "decomposed", WorldID: "w", Name: "Zoe\u0308", NormalizedName: "zoe\u0308"},
+		{ID: "ascii", WorldID: "w", Name: "Frodo", NormalizedName: "frodo"},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+	require.NoError(t, repo.SaveRelationship(ctx, &entities.Relationship{
+		ID: "r1", SourceEntityID: "final", TargetEntityID: "ascii", Type: entities.RelationAlly,
+	}))
+
+	require.NoError(t, repo.EnsureSchema(ctx))
+
+	merged, err := repo.FindEntityByID(ctx, "final")
+	require.NoError(t, err)
+	assert.Nil(t, merged, "entity matching another after renormalizing is merged")
+	found, err := repo.FindRelationshipBetween(ctx, "upper", "ascii")
+	require.NoError(t, err)
+	assert.NotNil(t, found)
+
+	zoe, err := repo.FindEntityByName(ctx, "w", "Zo\u00eb")
+	require.NoError(t, err)
+	require.NotNil(t, zoe)
+	assert.Equal(t, "decomposed", zoe.ID)
+}
+
+func TestRepository_EnsureSchema_UTCVersionTimes(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	// A version time stored in a local zone, as before times were UTC
+	local := time.Date(2026, 1, 10, 7, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	_, err := repo.db.ExecContext(ctx, `
+		INSERT INTO fact_versions (id, fact_id, version, change_type, data, reason, created_at)
+		VALUES ('v1', 'fact-a', 1, 'creation', '{}', '', ?)
+	`, local)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.EnsureSchema(ctx))
+
+	var stored string
+	require.NoError(t, repo.db.QueryRowContext(ctx, `SELECT CAST(created_at AS TEXT) FROM fact_versions WHERE id = 'v1'`).Scan(&stored))
+	assert.Equal(t, "2026-01-10 12:00:00 +0000 UTC", stored)
+}
+
 func TestRepository_EnsureSchema_Idempotent(t *testing.T) {
 	repo := setupTestRepo(t)
 
@@ -321,6 +493,258 @@ func TestRepository_CountRelationships(t *testing.T) {
 	})
 }
 
+func TestRepository_CountRelationshipsPerEntity(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	for _, e := range []*entities.Entity{
+		{ID: "hero", WorldID: "w", Name: "Hero", NormalizedName: "hero"},
+		{ID: "zed", WorldID: "w", Name: "Zed", NormalizedName: "zed"},
+		{ID: "loner", WorldID: "w", Name: "Loner", NormalizedName: "loner"},
+		{ID: "elsewhere", WorldID: "other", Name: "Elsewhere", NormalizedName: "elsewhere"},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+	for _, rel := range []*entities.Relationship{
+		{ID: "r1", SourceEntityID: "hero", TargetEntityID: "zed", Type: entities.RelationAlly, Bidirectional: true},
+		{ID: "r2", SourceEntityID: "zed", TargetEntityID: "hero", Type: entities.RelationEnemy},
+		{ID: "r3", SourceEntityID: "hero", TargetEntityID: "elsewhere", Type: entities.RelationSibling},
+	} {
+		require.NoError(t, repo.SaveRelationship(ctx, rel))
+	}
+
+	degrees, err := repo.CountRelationshipsPerEntity(ctx, "w")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"hero": 3, "zed": 2, "loner": 0}, degrees)
+}
+
+func TestRepository_MergeEntities(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	for _, e := range []*entities.Entity{
+		{ID: "shire", WorldID: "w", Name: "Shire", NormalizedName: "shire"},
+		{ID: "the-shire", WorldID: "w", Name: "The Shire", NormalizedName: "the shire"},
+		{ID: "frodo", WorldID: "w", Name: "Frodo", NormalizedName: "frodo"},
+		{ID: "sam", WorldID: "w", Name: "Sam", NormalizedName: "sam"},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+	for _, rel := range []*entities.Relationship{
+		{ID: "r1", SourceEntityID: "frodo", TargetEntityID: "shire", Type: entities.RelationLocatedIn},
+		{ID: "r2", SourceEntityID: "frodo", TargetEntityID: "the-shire", Type: entities.RelationLocatedIn},
+		{ID: "r3", SourceEntityID: "sam", TargetEntityID: "the-shire", Type: entities.RelationLocatedIn},
+		{ID: "r4", SourceEntityID: "the-shire", TargetEntityID: "shire", Type: entities.RelationAlly},
+	} {
+		require.NoError(t, repo.SaveRelationship(ctx, rel))
+	}
+
+	require.NoError(t, repo.MergeEntities(ctx, "the-shire", "shire"))
+
+	merged, err := repo.FindEntityByID(ctx, "the-shire")
+	require.NoError(t, err)
+	assert.Nil(t, merged)
+
+	total, err := repo.CountRelationships(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total, "duplicate and self relationships are dropped")
+
+	found, err := repo.FindRelationshipBetween(ctx, "sam", "shire")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "r3", found.ID)
+
+	found, err = repo.FindRelationshipBetween(ctx, "frodo", "shire")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "r1", found.ID)
+
+	err = repo.MergeEntities(ctx, "the-shire", "shire")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+}
+
+func TestRepository_RenameEntity(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	strider, err := repo.FindOrCreateEntity(ctx, "w", "Strider")
+	require.NoError(t, err)
+	_, err = repo.FindOrCreateEntity(ctx, "w", "Gandalf")
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RenameEntity(ctx, strider.ID, "Aragorn"))
+	found, err := repo.FindEntityByName(ctx, "w", "aragorn")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, strider.ID, found.ID)
+	assert.Equal(t, "Aragorn", found.Name)
+
+	old, err := repo.FindEntityByName(ctx, "w", "Strider")
+	require.NoError(t, err)
+	assert.Nil(t, old)
+
+	assert.Error(t, repo.RenameEntity(ctx, strider.ID, "Gandalf"), "names stay unique in a world")
+	assert.ErrorIs(t, repo.RenameEntity(ctx, "missing", "Frodo"), entities.ErrNotFound)
+}
+
+func TestRepository_FactSubjects(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	for _, e := range []*entities.Entity{
+		{ID: "shire", WorldID: "w", Name: "Shire", NormalizedName: "shire"},
+		{ID: "the-shire", WorldID: "w", Name: "The Shire", NormalizedName: "the shire"},
+		{ID: "frodo", WorldID: "w", Name: "Frodo", NormalizedName: "frodo"},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+	require.NoError(t, repo.SaveFactSubjects(ctx, []entities.FactSubject{
+		{FactID: "f1", EntityID: "shire", SourceFile: "a.md"},
+		{FactID: "f2", EntityID: "the-shire", SourceFile: "b.md"},
+		{FactID: "f3", EntityID: "frodo", SourceFile: "a.md"},
+		{FactID: "f4", EntityID: "shire", SourceFile: "b.md"},
+	}))
+
+	ids, err := repo.ListFactIDsByEntities(ctx, []string{"shire"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f1", "f4"}, ids)
+
+	// Saving a fact again replaces its link
+	require.NoError(t, repo.SaveFactSubjects(ctx, []entities.FactSubject{{FactID: "f4", EntityID: "frodo", SourceFile: "b.md"}}))
+	ids, err = repo.ListFactIDsByEntities(ctx, []string{"frodo"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f3", "f4"}, ids)
+
+	require.NoError(t, repo.MergeEntities(ctx, "the-shire", "shire"))
+	ids, err = repo.ListFactIDsByEntities(ctx, []string{"shire"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f1", "f2"}, ids, "merging moves the links")
+
+	ids, err = repo.ListFactIDsByEntities(ctx, []string{"shire", "frodo"}, 1)
+	require.NoError(t, err)
+	assert.Len(t, ids, 1)
+
+	require.NoError(t, repo.DeleteFactSubjects(ctx, ports.FactSubjectFilter{SourceFile: "a.md"}))
+	ids, err = repo.ListFactIDsByEntities(ctx, []string{"shire", "frodo"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f2", "f4"}, ids)
+
+	require.NoError(t, repo.DeleteFactSubjects(ctx, ports.FactSubjectFilter{FactIDs: []string{"f2"}}))
+	require.NoError(t, repo.DeleteEntity(ctx, "frodo"))
+	ids, err = repo.ListFactIDsByEntities(ctx, []string{"shire", "frodo"}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "deleting an entity removes its links")
+}
+
+func TestRepository_RenameWorld(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	for _, e := range []*entities.Entity{
+		{ID: "hero", WorldID: "old", Name: "Hero", NormalizedName: "hero"},
+		{ID: "zed", WorldID: "old", Name: "Zed", NormalizedName: "zed"},
+		{ID: "elsewhere", WorldID: "other", Name: "Elsewhere", NormalizedName: "elsewhere"},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+
+	require.NoError(t, repo.RenameWorld(ctx, "old", "new"))
+
+	for world, want := range map[string]int{"old": 0, "new": 2, "other": 1} {
+		count, err := repo.CountEntities(ctx, world)
+		require.NoError(t, err)
+		assert.Equal(t, want, count, world)
+	}
+
+	entity, err := repo.FindEntityByName(ctx, "new", "hero")
+	require.NoError(t, err)
+	require.NotNil(t, entity)
+	assert.Equal(t, "hero", entity.ID)
+}
+
+func TestRepository_ListRelationshipsByEntity(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	base := time.Now()
+	for _, e := range []*entities.Entity{
+		{ID: "hero", WorldID: "w", Name: "Hero", NormalizedName: "hero", CreatedAt: base},
+		{ID: "zed", WorldID: "w", Name: "Zed", NormalizedName: "zed", CreatedAt: base},
+		{ID: "bob", WorldID: "w", Name: "Bob", NormalizedName: "bob", CreatedAt: base},
+		{ID: "mia", WorldID: "w", Name: "Mia", NormalizedName: "mia", CreatedAt: base},
+	} {
+		require.NoError(t, repo.SaveEntity(ctx, e))
+	}
+
+	relationships := []*entities.Relationship{
+		{ID: "r1", SourceEntityID: "hero", TargetEntityID: "zed", Type: entities.RelationAlly, Bidirectional: true, CreatedAt: base},
+		{ID: "r2", SourceEntityID: "bob", TargetEntityID: "hero", Type: entities.RelationEnemy, Bidirectional: true, CreatedAt: base.Add(time.Minute)},
+		{ID: "r3", SourceEntityID: "hero", TargetEntityID: "mia", Type: entities.RelationAlly, Bidirectional: false, CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "r4", SourceEntityID: "mia", TargetEntityID: "hero", Type: entities.RelationSibling, Bidirectional: false, CreatedAt: base.Add(3 * time.Minute)},
+	}
+	for _, rel := range relationships {
+		require.NoError(t, repo.SaveRelationship(ctx, rel))
+	}
+
+	ids := func(rels []entities.Relationship) []string {
+		out := make([]string, 0, len(rels))
+		for i := range rels {
+			out = append(out, rels[i].ID)
+		}
+		return out
+	}
+
+	t.Run("default sort is newest first", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r3", "r2", "r1"}, ids(rels))
+	})
+
+	t.Run("sort by type", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{Sort: ports.RelationshipSortType})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r3", "r1", "r2"}, ids(rels))
+	})
+
+	t.Run("sort by target name", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{Sort: ports.RelationshipSortTarget})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r2", "r3", "r1"}, ids(rels))
+	})
+
+	t.Run("limit and offset", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{
+			Sort:   ports.RelationshipSortTarget,
+			Limit:  1,
+			Offset: 1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r3"}, ids(rels))
+	})
+
+	t.Run("offset past end returns empty", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{Offset: 10})
+		require.NoError(t, err)
+		assert.Empty(t, rels)
+	})
+
+	t.Run("filter by type", func(t *testing.T) {
+		rels, err := repo.ListRelationshipsByEntity(ctx, "hero", ports.RelationshipListOptions{Type: string(entities.RelationAlly)})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"r3", "r1"}, ids(rels))
+	})
+
+	t.Run("count matches filters", func(t *testing.T) {
+		count, err := repo.CountRelationshipsByEntity(ctx, "hero", "")
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		count, err = repo.CountRelationshipsByEntity(ctx, "hero", string(entities.RelationEnemy))
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
 func TestRepository_FactVersions(t *testing.T) {
 	repo := setupTestRepo(t)
 	ctx := context.Background()
@@ -388,6 +812,56 @@ func TestRepository_FactVersions(t *testing.T) {
 	})
 }
 
+func TestRepository_ListVersionsAsOf(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	save := func(id, factID string, version int, object string, at time.Time) {
+		t.Helper()
+		err := repo.SaveVersion(ctx, &entities.FactVersion{
+			ID:         id,
+			FactID:     factID,
+			Version:    version,
+			ChangeType: entities.ChangeUpdate,
+			Data:       entities.Fact{ID: factID, Subject: "Sauron", Predicate: "is", Object: object},
+			CreatedAt:  at,
+		})
+		require.NoError(t, err)
+	}
+
+	save("a1", "fact-a", 1, "a maia", day(1))
+	save("a2", "fact-a", 2, "the dark lord", day(10))
+	save("a3", "fact-a", 3, "defeated", day(30))
+	save("b1", "fact-b", 1, "a ring", day(20).In(time.FixedZone("local", -5*3600)))
+	save("b2", "fact-b", 2, "unmade", day(28))
+
+	tests := []struct {
+		name string
+		asOf time.Time
+		want map[string]string
+	}{
+		{"before any history", day(1).Add(-time.Hour), map[string]string{}},
+		{"first version", day(5), map[string]string{"fact-a": "a maia"}},
+		{"exact timestamp", day(10), map[string]string{"fact-a": "the dark lord"}},
+		{"version in another zone", day(20).Add(time.Hour), map[string]string{"fact-a": "the dark lord", "fact-b": "a ring"}},
+		{"unchanged since", day(31), map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, err := repo.ListVersionsAsOf(ctx, tt.asOf.In(time.FixedZone("test", 3600)))
+			require.NoError(t, err)
+
+			got := make(map[string]string)
+			for _, v := range versions {
+				got[v.FactID] = v.Data.Object
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestRepository_EntityTypes(t *testing.T) {
 	repo := setupTestRepo(t)
 	ctx := context.Background()
@@ -509,6 +983,327 @@ func TestRepository_AuditLog(t *testing.T) {
 	})
 }
 
+func TestRepository_Conflicts(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	conflict := func(id, a, b string, created time.Time) *entities.Conflict {
+		return &entities.Conflict{
+			ID:          id,
+			FactID:      a,
+			OtherFactID: b,
+			Description: "eye colors differ",
+			Severity:    "major",
+			Status:      entities.ConflictOpen,
+			CreatedAt:   created,
+		}
+	}
+
+	t.Run("save", func(t *testing.T) {
+		created, err := repo.SaveConflict(ctx, conflict("c1", "a", "b", base))
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		created, err = repo.SaveConflict(ctx, conflict("c2", "a", "c", base.Add(time.Hour)))
+		require.NoError(t, err)
+		assert.True(t, created)
+	})
+
+	t.Run("save existing pair is a no-op", func(t *testing.T) {
+		created, err := repo.SaveConflict(ctx, conflict("c3", "a", "b", base.Add(2*time.Hour)))
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	t.Run("list newest first", func(t *testing.T) {
+		conflicts, err := repo.ListConflicts(ctx, ports.ConflictListOptions{})
+		require.NoError(t, err)
+		require.Len(t, conflicts, 2)
+		assert.Equal(t, "c2", conflicts[0].ID)
+		assert.Equal(t, "c1", conflicts[1].ID)
+		assert.Equal(t, "eye colors differ", conflicts[1].Description)
+		assert.Equal(t, entities.ConflictOpen, conflicts[1].Status)
+		assert.True(t, base.Equal(conflicts[1].CreatedAt))
+	})
+
+	t.Run("list by fact with limit", func(t *testing.T) {
+		conflicts, err := repo.ListConflicts(ctx, ports.ConflictListOptions{FactID: "b"})
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "c1", conflicts[0].ID)
+
+		conflicts, err = repo.ListConflicts(ctx, ports.ConflictListOptions{FactID: "a", Limit: 1})
+		require.NoError(t, err)
+		assert.Len(t, conflicts, 1)
+	})
+
+	t.Run("count open", func(t *testing.T) {
+		counts, err := repo.CountOpenConflicts(ctx, []string{"a", "b", "z"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 2, "b": 1}, counts)
+
+		counts, err = repo.CountOpenConflicts(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		require.NoError(t, repo.ResolveConflict(ctx, "c1", "old draft", base.Add(24*time.Hour)))
+
+		conflicts, err := repo.ListConflicts(ctx, ports.ConflictListOptions{Status: entities.ConflictResolved})
+		require.NoError(t, err)
+		require.Len(t, conflicts, 1)
+		assert.Equal(t, "old draft", conflicts[0].Resolution)
+		assert.True(t, base.Add(24*time.Hour).Equal(conflicts[0].ResolvedAt))
+
+		counts, err := repo.CountOpenConflicts(ctx, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1}, counts)
+	})
+
+	t.Run("resolve missing", func(t *testing.T) {
+		err := repo.ResolveConflict(ctx, "missing", "", base)
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+	})
+}
+
+func TestRepository_Translations(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	created, err := repo.SaveTranslation(ctx, &entities.Translation{FactID: "a", OtherFactID: "b", CreatedAt: base})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.SaveTranslation(ctx, &entities.Translation{FactID: "c", OtherFactID: "d", CreatedAt: base.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.SaveTranslation(ctx, &entities.Translation{FactID: "a", OtherFactID: "b", CreatedAt: base.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	assert.False(t, created, "existing pair is a no-op")
+
+	translations, err := repo.ListTranslations(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, translations, 2)
+	assert.Equal(t, "a", translations[0].FactID)
+	assert.True(t, base.Equal(translations[0].CreatedAt))
+
+	translations, err = repo.ListTranslations(ctx, []string{"d", "z"})
+	require.NoError(t, err)
+	require.Len(t, translations, 1)
+	assert.Equal(t, "c", translations[0].FactID)
+	assert.Equal(t, "d", translations[0].OtherFactID)
+}
+
+func TestRepository_HealthSamples(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	samples, err := repo.ListHealthSamples(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+
+	for i := range 3 {
+		sample := &entities.HealthSample{
+			RecordedAt:         base.AddDate(0, 0, i),
+			Facts:              10 + i,
+			OpenConflicts:      2,
+			ConflictingFacts:   3,
+			LowConfidenceFacts: 1,
+			StaleFacts:         4,
+			CorroboratedFacts:  6,
+			Entities:           5,
+			OrphanEntities:     1,
+			Score:              70.5 + float64(i),
+		}
+		require.NoError(t, repo.SaveHealthSample(ctx, sample))
+		assert.Equal(t, int64(i+1), sample.ID)
+	}
+
+	samples, err = repo.ListHealthSamples(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+	assert.Equal(t, entities.HealthSample{
+		ID:                 1,
+		RecordedAt:         samples[0].RecordedAt,
+		Facts:              10,
+		OpenConflicts:      2,
+		ConflictingFacts:   3,
+		LowConfidenceFacts: 1,
+		StaleFacts:         4,
+		CorroboratedFacts:  6,
+		Entities:           5,
+		OrphanEntities:     1,
+		Score:              70.5,
+	}, samples[0])
+	assert.True(t, base.Equal(samples[0].RecordedAt))
+
+	samples, err = repo.ListHealthSamples(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, int64(2), samples[0].ID, "most recent two, oldest first")
+	assert.Equal(t, int64(3), samples[1].ID)
+}
+
+func TestRepository_Usage(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, record := range []*entities.UsageRecord{
+		{RecordedAt: march.Add(-time.Hour), Backend: "llm", Model: "gpt-4o-mini", PromptTokens: 900, Cost: 5},
+		{RecordedAt: march.Add(time.Hour), Backend: "llm", Model: "gpt-4o-mini", PromptTokens: 100, CompletionTokens: 20, Cost: 0.5},
+		{RecordedAt: march.Add(2 * time.Hour), Backend: "llm", Model: "gpt-4o-mini", PromptTokens: 200, CompletionTokens: 30, Cost: 0.25},
+		{RecordedAt: march.Add(3 * time.Hour), Backend: "embedder", Model: "text-embedding-3-small", PromptTokens: 1000, Cost: 0.125},
+	} {
+		require.NoError(t, repo.SaveUsage(ctx, record))
+		assert.NotZero(t, record.ID)
+	}
+
+	totals, err := repo.SumUsage(ctx, march)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.UsageTotals{
+		{Backend: "embedder", Model: "text-embedding-3-small", Calls: 1, PromptTokens: 1000, Cost: 0.125},
+		{Backend: "llm", Model: "gpt-4o-mini", Calls: 2, PromptTokens: 300, CompletionTokens: 50, Cost: 0.75},
+	}, totals, "usage before since is left out")
+
+	totals, err = repo.SumUsage(ctx, march.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Empty(t, totals)
+}
+
+func TestRepository_ExtractionStats(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	stats, err := repo.SumExtractionStats(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stats)
+
+	for _, stat := range []*entities.ExtractionStat{
+		{RecordedAt: now, Model: "llama3", Facts: 2, ConfidenceSum: 1.5},
+		{RecordedAt: now, Model: "gpt-4o-mini", Facts: 4, ConfidenceSum: 3.5},
+		{RecordedAt: now, Model: "llama3", ParseFailed: true},
+		{RecordedAt: now, Model: "gpt-4o-mini", Facts: 6, ConfidenceSum: 5},
+	} {
+		require.NoError(t, repo.SaveExtractionStat(ctx, stat))
+		assert.NotZero(t, stat.ID)
+	}
+
+	stats, err = repo.SumExtractionStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.ModelStats{
+		{Model: "gpt-4o-mini", Calls: 2, Facts: 10, ConfidenceSum: 8.5},
+		{Model: "llama3", Calls: 2, ParseFailures: 1, Facts: 2, ConfidenceSum: 1.5},
+	}, stats)
+}
+
+func TestRepository_IdempotencyRecords(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	record, err := repo.FindIdempotencyRecord(ctx, "job-1")
+	require.NoError(t, err)
+	assert.Nil(t, record)
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveIdempotencyRecord(ctx, &entities.IdempotencyRecord{
+		Key: "job-1", Operation: "ingest", RequestHash: "abc", Result: []byte(`{"facts":3}`), CreatedAt: created,
+	}))
+	require.NoError(t, repo.SaveIdempotencyRecord(ctx, &entities.IdempotencyRecord{
+		Key: "job-1", Operation: "ingest", RequestHash: "def", Result: []byte(`{"facts":4}`), CreatedAt: created.Add(time.Hour),
+	}))
+
+	record, err = repo.FindIdempotencyRecord(ctx, "job-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "ingest", record.Operation)
+	assert.Equal(t, "def", record.RequestHash, "replaced")
+	assert.JSONEq(t, `{"facts":4}`, string(record.Result))
+	assert.True(t, created.Add(time.Hour).Equal(record.CreatedAt))
+}
+
+func TestRepository_ClaimIdempotencyKey(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	claim := func(hash string, at time.Time) bool {
+		t.Helper()
+		claimed, err := repo.ClaimIdempotencyKey(ctx, &entities.IdempotencyRecord{
+			Key: "job-1", Operation: "ingest", RequestHash: hash, Pending: true, CreatedAt: at,
+		}, at.Add(-time.Hour))
+		require.NoError(t, err)
+		return claimed
+	}
+
+	assert.True(t, claim("abc", created))
+	assert.False(t, claim("def", created.Add(time.Minute)), "already claimed")
+
+	record, err := repo.FindIdempotencyRecord(ctx, "job-1")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.True(t, record.Pending)
+	assert.Equal(t, "abc", record.RequestHash)
+
+	require.NoError(t, repo.ReleaseIdempotencyKey(ctx, "job-1"))
+	assert.True(t, claim("def", created.Add(time.Minute)), "released")
+
+	require.NoError(t, repo.SaveIdempotencyRecord(ctx, &entities.IdempotencyRecord{
+		Key: "job-1", Operation: "ingest", RequestHash: "def", Result: []byte(`{"facts":3}`), CreatedAt: created,
+	}))
+	require.NoError(t, repo.ReleaseIdempotencyKey(ctx, "job-1"))
+	record, err = repo.FindIdempotencyRecord(ctx, "job-1")
+	require.NoError(t, err)
+	require.NotNil(t, record, "completed records are not released")
+	assert.False(t, record.Pending)
+
+	assert.False(t, claim("ghi", created.Add(time.Hour-time.Second)))
+	assert.True(t, claim("ghi", created.Add(time.Hour)), "expired")
+}
+
+func TestRepository_EnsureSchema_AddsHealthColumns(t *testing.T) {
+	repo, err := NewRepository(config.SQLiteConfig{Path: ":memory:"})
+	require.NoError(t, err)
+	defer repo.Close()
+	ctx := context.Background()
+
+	// health_samples as created before corroborated_facts existed
+	_, err = repo.db.ExecContext(ctx, `
+		CREATE TABLE health_samples (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			recorded_at TIMESTAMP NOT NULL,
+			facts INTEGER NOT NULL,
+			open_conflicts INTEGER NOT NULL,
+			conflicting_facts INTEGER NOT NULL,
+			low_confidence_facts INTEGER NOT NULL,
+			stale_facts INTEGER NOT NULL,
+			entities INTEGER NOT NULL,
+			orphan_entities INTEGER NOT NULL,
+			score REAL NOT NULL
+		);
+		INSERT INTO health_samples (recorded_at, facts, open_conflicts, conflicting_facts,
+			low_confidence_facts, stale_facts, entities, orphan_entities, score)
+		VALUES ('2024-03-01 12:00:00', 10, 0, 0, 0, 0, 2, 0, 100);
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.EnsureSchema(ctx))
+	require.NoError(t, repo.EnsureSchema(ctx), "upgrading twice is a no-op")
+
+	require.NoError(t, repo.SaveHealthSample(ctx, &entities.HealthSample{RecordedAt: time.Now(), Facts: 12, CorroboratedFacts: 3}))
+	samples, err := repo.ListHealthSamples(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	assert.Equal(t, 0, samples[0].CorroboratedFacts, "older samples default to none")
+	assert.Equal(t, 3, samples[1].CorroboratedFacts)
+}
+
 func TestRepository_Path(t *testing.T) {
 	repo, err := NewRepository(config.SQLiteConfig{Path: ":memory:"})
 	require.NoError(t, err)
@@ -516,3 +1311,204 @@ func TestRepository_Path(t *testing.T) {
 
 	assert.Equal(t, ":memory:", repo.Path())
 }
+
+func TestRepository_Ping(t *testing.T) {
+	repo, err := NewRepository(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "lore.db")})
+	require.NoError(t, err)
+
+	assert.NoError(t, repo.Ping(context.Background()))
+
+	require.NoError(t, repo.Close())
+	assert.Error(t, repo.Ping(context.Background()), "a closed database is not ready")
+}
+
+func TestRepository_ConsistencyVerdicts(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SaveConsistencyVerdicts(ctx, []entities.ConsistencyVerdict{
+		{Key: "old", CheckedAt: base.Add(-48 * time.Hour)},
+		{Key: "clean", CheckedAt: base},
+		{Key: "conflict", Conflicting: true, Description: "two homes", Severity: "major", CheckedAt: base},
+	}))
+	require.NoError(t, repo.SaveConsistencyVerdicts(ctx, nil))
+
+	found, err := repo.FindConsistencyVerdicts(ctx, []string{"old", "clean", "conflict", "missing"}, base.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, found, 2, "expired and missing keys are left out")
+	assert.False(t, found["clean"].Conflicting)
+	assert.Equal(t, "two homes", found["conflict"].Description)
+	assert.Equal(t, "major", found["conflict"].Severity)
+	assert.True(t, base.Equal(found["conflict"].CheckedAt))
+
+	// Rechecking replaces the verdict
+	require.NoError(t, repo.SaveConsistencyVerdicts(ctx, []entities.ConsistencyVerdict{
+		{Key: "conflict", CheckedAt: base.Add(time.Hour)},
+	}))
+	found, err = repo.FindConsistencyVerdicts(ctx, []string{"conflict"}, base)
+	require.NoError(t, err)
+	assert.False(t, found["conflict"].Conflicting)
+	assert.Empty(t, found["conflict"].Description)
+
+	pruned, err := repo.PruneConsistencyVerdicts(ctx, base.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	found, err = repo.FindConsistencyVerdicts(ctx, []string{"old"}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestRepository_FindConsistencyVerdicts_ManyKeys(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	keys := make([]string, verdictLookupBatch*2+1)
+	verdicts := make([]entities.ConsistencyVerdict, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("pair-%d", i)
+		verdicts[i] = entities.ConsistencyVerdict{Key: keys[i], CheckedAt: now}
+	}
+	require.NoError(t, repo.SaveConsistencyVerdicts(ctx, verdicts))
+
+	found, err := repo.FindConsistencyVerdicts(ctx, keys, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, found, len(keys))
+}
+
+func TestRepository_StyleTerms(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SaveStyleTerm(ctx, &entities.StyleTerm{Term: "Dragon Lord", Key: "dragonlord", CreatedAt: base}))
+	require.NoError(t, repo.SaveStyleTerm(ctx, &entities.StyleTerm{Term: "Barad-dûr", Key: "baraddûr", Source: "chapter1", CreatedAt: base}))
+	require.NoError(t, repo.SaveStyleTerm(ctx, &entities.StyleTerm{Term: "Dragon-Lord", Key: "dragonlord", Source: "chapter2", CreatedAt: base.Add(time.Hour)}))
+
+	terms, err := repo.ListStyleTerms(ctx)
+	require.NoError(t, err)
+	require.Len(t, terms, 2)
+	assert.Equal(t, "Barad-dûr", terms[0].Term)
+	assert.Equal(t, "Dragon-Lord", terms[1].Term, "same key replaces the spelling")
+	assert.Equal(t, "chapter2", terms[1].Source)
+	assert.True(t, base.Add(time.Hour).Equal(terms[1].CreatedAt))
+}
+
+func TestRepository_SourceStats(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SaveSourceStats(ctx, &entities.SourceStats{Source: "ch2.md", Words: 3000, Facts: 12, IngestedAt: base}))
+	require.NoError(t, repo.SaveSourceStats(ctx, &entities.SourceStats{Source: "ch1.md", Words: 2000, Facts: 5, IngestedAt: base}))
+	require.NoError(t, repo.SaveSourceStats(ctx, &entities.SourceStats{Source: "ch2.md", Words: 3100, Facts: 20, IngestedAt: base.Add(time.Hour)}))
+
+	sources, err := repo.ListSourceStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "ch1.md", sources[0].Source)
+	assert.Equal(t, "ch2.md", sources[1].Source)
+	assert.Equal(t, 3100, sources[1].Words, "a new ingest replaces the record")
+	assert.Equal(t, 20, sources[1].Facts)
+	assert.True(t, base.Add(time.Hour).Equal(sources[1].IngestedAt))
+}
+
+func TestRepository_ArchivedSources(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, repo.SaveArchivedSource(ctx, &entities.ArchivedSource{Source: "draft2.md", ArchivedAt: base}))
+	require.NoError(t, repo.SaveArchivedSource(ctx, &entities.ArchivedSource{Source: "draft1.md", ArchivedAt: base}))
+	require.NoError(t, repo.SaveArchivedSource(ctx, &entities.ArchivedSource{Source: "draft2.md", ArchivedAt: base.Add(time.Hour)}))
+
+	sources, err := repo.ListArchivedSources(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 2)
+	assert.Equal(t, "draft1.md", sources[0].Source)
+	assert.True(t, base.Add(time.Hour).Equal(sources[1].ArchivedAt), "archiving again replaces the record")
+
+	require.NoError(t, repo.DeleteArchivedSource(ctx, "draft1.md"))
+	assert.ErrorIs(t, repo.DeleteArchivedSource(ctx, "draft1.md"), entities.ErrNotFound)
+	sources, err = repo.ListArchivedSources(ctx)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, "draft2.md", sources[0].Source)
+}
+
+func TestRepository_FailedChunks(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	second := &entities.FailedChunk{ID: "b", Source: "ch1.md", Index: 4, Text: "later", Error: "timeout", Attempts: 1, CreatedAt: base, UpdatedAt: base}
+	require.NoError(t, repo.SaveFailedChunk(ctx, second))
+	require.NoError(t, repo.SaveFailedChunk(ctx, &entities.FailedChunk{ID: "a", Source: "ch1.md", Index: 1, Text: "earlier", PriorContext: "Frodo left", Error: "bad JSON", Attempts: 1, CreatedAt: base, UpdatedAt: base}))
+
+	second.Error, second.Attempts, second.UpdatedAt = "rate limited", 2, base.Add(time.Hour)
+	require.NoError(t, repo.SaveFailedChunk(ctx, second))
+
+	chunks, err := repo.ListFailedChunks(ctx)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "a", chunks[0].ID, "ordered by position")
+	assert.Equal(t, "Frodo left", chunks[0].PriorContext)
+	assert.Equal(t, "rate limited", chunks[1].Error)
+	assert.Equal(t, 2, chunks[1].Attempts)
+	assert.True(t, base.Equal(chunks[1].CreatedAt))
+
+	require.NoError(t, repo.DeleteFailedChunk(ctx, "a"))
+	require.ErrorIs(t, repo.DeleteFailedChunk(ctx, "a"), entities.ErrNotFound)
+	chunks, err = repo.ListFailedChunks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, chunks, 1)
+}
+
+func TestRepository_Changes(t *testing.T) {
+	repo := setupTestRepo(t)
+	ctx := context.Background()
+
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	changes := []entities.Change{
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpAdded, ID: "fact-1", Source: "ch1.md", ChangedAt: changedAt,
+			Fact: &entities.Fact{ID: "fact-1", Subject: "Frodo", Predicate: "lives_in", Object: "Shire"}},
+		{Kind: entities.ChangeKindFact, Op: entities.ChangeOpDeleted, Source: "ch2.md", ChangedAt: changedAt},
+	}
+	require.NoError(t, repo.RecordChanges(ctx, changes))
+	assert.Equal(t, int64(1), changes[0].Seq)
+	assert.Equal(t, int64(2), changes[1].Seq)
+
+	created := time.Date(2024, 3, 2, 9, 30, 0, 0, time.UTC)
+	rel := &entities.Relationship{ID: "rel-1", SourceEntityID: "e1", TargetEntityID: "e2", Type: entities.RelationAlly, CreatedAt: created}
+	require.NoError(t, repo.SaveRelationship(ctx, rel))
+	rel.Bidirectional = true
+	require.NoError(t, repo.SaveRelationship(ctx, rel))
+	require.NoError(t, repo.DeleteRelationship(ctx, "rel-1"))
+
+	listed, err := repo.ListChanges(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 5)
+
+	assert.Equal(t, "Frodo", listed[0].Fact.Subject)
+	assert.Equal(t, "ch1.md", listed[0].Source)
+	assert.True(t, changedAt.Equal(listed[0].ChangedAt))
+	assert.Empty(t, listed[1].ID, "every fact of a source deleted at once")
+	assert.Nil(t, listed[1].Fact)
+
+	assert.Equal(t, entities.ChangeOpAdded, listed[2].Op)
+	require.NotNil(t, listed[2].Relationship)
+	assert.Equal(t, entities.RelationAlly, listed[2].Relationship.Type)
+	assert.True(t, created.Equal(listed[2].Relationship.CreatedAt))
+	assert.False(t, listed[2].ChangedAt.IsZero())
+	assert.Equal(t, entities.ChangeOpUpdated, listed[3].Op)
+	assert.True(t, listed[3].Relationship.Bidirectional)
+	assert.Equal(t, entities.ChangeOpDeleted, listed[4].Op)
+	assert.Equal(t, "rel-1", listed[4].ID)
+	assert.Nil(t, listed[4].Relationship)
+
+	listed, err = repo.ListChanges(ctx, 3, 1)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, int64(4), listed[0].Seq)
+}
@@ -0,0 +1,270 @@
+// Package sessions records interactive sessions as JSON Lines transcripts
+// that can be listed and replayed.
+package sessions
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// fileExt is the extension of session log files.
+const fileExt = ".jsonl"
+
+// EventType identifies what a session log entry records.
+type EventType string
+
+const (
+	// EventStart opens a session and records its world and source.
+	EventStart EventType = "start"
+	// EventInput records text entered by the user.
+	EventInput EventType = "input"
+	// EventExtracted records the facts and conflicts found in an input.
+	EventExtracted EventType = "extracted"
+	// EventSaved records pending facts the user saved.
+	EventSaved EventType = "saved"
+	// EventDiscarded records pending facts the user discarded.
+	EventDiscarded EventType = "discarded"
+	// EventError records an input that could not be processed.
+	EventError EventType = "error"
+	// EventEnd closes a session.
+	EventEnd EventType = "end"
+)
+
+// Event is one entry in a session log.
+type Event struct {
+	Time      time.Time  `json:"time"`
+	Type      EventType  `json:"type"`
+	World     string     `json:"world,omitempty"`
+	Source    string     `json:"source,omitempty"`
+	Input     int        `json:"input,omitempty"` // Number of the input the entry belongs to, from 1
+	Text      string     `json:"text,omitempty"`
+	Facts     []Fact     `json:"facts,omitempty"`
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Fact is a fact as recorded in a session log, without its embeddings.
+type Fact struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	Object    string `json:"object"`
+	Pending   bool   `json:"pending,omitempty"`
+}
+
+// Conflict is a consistency issue as recorded in a session log.
+type Conflict struct {
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	FactID      string `json:"fact_id"`
+	Existing    Fact   `json:"existing"`
+}
+
+// NewFacts converts facts for logging.
+func NewFacts(facts []entities.Fact) []Fact {
+	logged := make([]Fact, len(facts))
+	for i := range facts {
+		logged[i] = newFact(&facts[i])
+	}
+	return logged
+}
+
+func newFact(fact *entities.Fact) Fact {
+	return Fact{
+		ID:        fact.ID,
+		Type:      string(fact.Type),
+		Subject:   fact.Subject,
+		Predicate: fact.Predicate,
+		Object:    fact.Object,
+		Pending:   fact.IsPending(),
+	}
+}
+
+// NewConflicts converts consistency issues for logging.
+func NewConflicts(issues []ports.ConsistencyIssue) []Conflict {
+	logged := make([]Conflict, len(issues))
+	for i := range issues {
+		logged[i] = Conflict{
+			Severity:    issues[i].Severity,
+			Description: issues[i].Description,
+			FactID:      issues[i].NewFact.ID,
+			Existing:    newFact(&issues[i].ExistingFact),
+		}
+	}
+	return logged
+}
+
+// Log appends events to a session log file.
+type Log struct {
+	file   *os.File
+	enc    *json.Encoder
+	inputs int
+	now    func() time.Time
+}
+
+// Create starts a new session log in dir, creating the directory if needed,
+// and records the start event.
+func Create(dir, world, source string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating session directory: %w", err)
+	}
+
+	now := time.Now
+	file, err := os.CreateTemp(dir, now().Format("20060102-150405")+"-*"+fileExt)
+	if err != nil {
+		return nil, fmt.Errorf("creating session log: %w", err)
+	}
+
+	l := &Log{file: file, enc: json.NewEncoder(file), now: now}
+	if err := l.write(&Event{Type: EventStart, World: world, Source: source}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path returns the path of the log file.
+func (l *Log) Path() string {
+	return l.file.Name()
+}
+
+// Input records text entered by the user and returns its input number.
+func (l *Log) Input(text string) (int, error) {
+	l.inputs++
+	return l.inputs, l.write(&Event{Type: EventInput, Input: l.inputs, Text: text})
+}
+
+// Extracted records the facts and conflicts found in an input.
+func (l *Log) Extracted(input int, facts []entities.Fact, issues []ports.ConsistencyIssue) error {
+	return l.write(&Event{Type: EventExtracted, Input: input, Facts: NewFacts(facts), Conflicts: NewConflicts(issues)})
+}
+
+// Failed records an input that could not be processed.
+func (l *Log) Failed(input int, err error) error {
+	return l.write(&Event{Type: EventError, Input: input, Error: err.Error()})
+}
+
+// Saved records pending facts the user saved.
+func (l *Log) Saved(facts []entities.Fact) error {
+	return l.write(&Event{Type: EventSaved, Facts: NewFacts(facts)})
+}
+
+// Discarded records pending facts the user discarded.
+func (l *Log) Discarded(facts []entities.Fact) error {
+	return l.write(&Event{Type: EventDiscarded, Facts: NewFacts(facts)})
+}
+
+// Close records the end of the session and closes the file.
+func (l *Log) Close() error {
+	err := l.write(&Event{Type: EventEnd})
+	if cerr := l.file.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("closing session log: %w", cerr)
+	}
+	return err
+}
+
+func (l *Log) write(event *Event) error {
+	event.Time = l.now().UTC()
+	if err := l.enc.Encode(event); err != nil {
+		return fmt.Errorf("writing session log: %w", err)
+	}
+	return nil
+}
+
+// Read parses a session log file.
+func Read(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, entities.Errorf(entities.ErrNotFound, "session log not found: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening session log: %w", err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, entities.Errorf(entities.ErrValidation, "%s line %d: invalid session log entry: %v", path, lineNum, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading session log: %w", err)
+	}
+	return events, nil
+}
+
+// Summary describes a recorded session.
+type Summary struct {
+	Path      string
+	Started   time.Time
+	World     string
+	Source    string
+	Inputs    int
+	Saved     int // Facts saved
+	Discarded int // Facts discarded
+	Conflicts int // Conflicts found across all inputs
+}
+
+// Summarize describes the session recorded by events.
+func Summarize(path string, events []Event) Summary {
+	summary := Summary{Path: path}
+	for i := range events {
+		switch events[i].Type {
+		case EventStart:
+			summary.Started = events[i].Time
+			summary.World = events[i].World
+			summary.Source = events[i].Source
+		case EventInput:
+			summary.Inputs++
+		case EventExtracted:
+			summary.Conflicts += len(events[i].Conflicts)
+		case EventSaved:
+			summary.Saved += len(events[i].Facts)
+		case EventDiscarded:
+			summary.Discarded += len(events[i].Facts)
+		}
+	}
+	return summary
+}
+
+// List returns the session log files in dir, newest first.
+func List(dir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading session directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	// Names start with the session's start time
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
@@ -0,0 +1,109 @@
+package sessions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+func TestLog_RoundTrip(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+
+	log, err := Create(dir, "middle-earth", "interactive")
+	require.NoError(t, err)
+
+	facts := []entities.Fact{
+		{ID: "f1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "eye_color", Object: "green", Embedding: []float32{0.1}},
+		{ID: "f2", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "is", Object: "loyal", Status: entities.FactStatusPending},
+	}
+	issues := []ports.ConsistencyIssue{{
+		NewFact:      facts[0],
+		ExistingFact: entities.Fact{ID: "old", Subject: "Frodo", Predicate: "eye_color", Object: "blue"},
+		Description:  "eye colors differ",
+		Severity:     "major",
+	}}
+
+	input, err := log.Input("Frodo has green eyes. Sam is loyal.")
+	require.NoError(t, err)
+	assert.Equal(t, 1, input)
+	require.NoError(t, log.Extracted(input, facts, issues))
+	require.NoError(t, log.Saved(facts[:1]))
+	require.NoError(t, log.Discarded(facts[1:]))
+
+	input, err = log.Input("Gandalf")
+	require.NoError(t, err)
+	assert.Equal(t, 2, input)
+	require.NoError(t, log.Failed(input, errors.New("rate limited")))
+	require.NoError(t, log.Close())
+
+	events, err := Read(log.Path())
+	require.NoError(t, err)
+
+	types := make([]EventType, len(events))
+	for i := range events {
+		types[i] = events[i].Type
+	}
+	assert.Equal(t, []EventType{EventStart, EventInput, EventExtracted, EventSaved, EventDiscarded, EventInput, EventError, EventEnd}, types)
+
+	assert.Equal(t, "middle-earth", events[0].World)
+	assert.Equal(t, "Frodo has green eyes. Sam is loyal.", events[1].Text)
+	require.Len(t, events[2].Facts, 2)
+	assert.Equal(t, Fact{ID: "f1", Type: "character", Subject: "Frodo", Predicate: "eye_color", Object: "green"}, events[2].Facts[0])
+	assert.True(t, events[2].Facts[1].Pending)
+	require.Len(t, events[2].Conflicts, 1)
+	assert.Equal(t, "f1", events[2].Conflicts[0].FactID)
+	assert.Equal(t, "blue", events[2].Conflicts[0].Existing.Object)
+	assert.Equal(t, "rate limited", events[6].Error)
+
+	data, err := os.ReadFile(log.Path())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "embedding", "embeddings are not logged")
+
+	summary := Summarize(log.Path(), events)
+	assert.Equal(t, "interactive", summary.Source)
+	assert.Equal(t, 2, summary.Inputs)
+	assert.Equal(t, 1, summary.Conflicts)
+	assert.Equal(t, 1, summary.Saved)
+	assert.Equal(t, 1, summary.Discarded)
+	assert.False(t, summary.Started.IsZero())
+}
+
+func TestRead_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Read(filepath.Join(dir, "missing.jsonl"))
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+
+	path := filepath.Join(dir, "bad.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"start\"}\n\nnot json\n"), 0600))
+	_, err = Read(path)
+	assert.ErrorIs(t, err, entities.ErrValidation)
+	assert.Contains(t, err.Error(), "line 3")
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+
+	paths, err := List(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+
+	for _, name := range []string{"20240101-090000-1.jsonl", "20240301-120000-2.jsonl", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "old.jsonl"), 0755))
+
+	paths, err = List(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "20240301-120000-2.jsonl"),
+		filepath.Join(dir, "20240101-090000-1.jsonl"),
+	}, paths)
+}
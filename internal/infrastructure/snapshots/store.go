@@ -0,0 +1,127 @@
+// Package snapshots provides a file-based catalog of world snapshots.
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// manifestExt is the extension of snapshot manifest files.
+const manifestExt = ".json"
+
+// FileStore implements ports.SnapshotStore with one JSON manifest and one
+// database backup per snapshot in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a FileStore rooted at dir. The directory is created
+// when the first snapshot is saved.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+// BackupPath returns where the relational backup for a snapshot is written.
+func (s *FileStore) BackupPath(name string) string {
+	return filepath.Join(s.dir, name+".db")
+}
+
+// Save records a snapshot manifest.
+func (s *FileStore) Save(_ context.Context, snapshot *entities.Snapshot) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating snapshot directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(s.manifestPath(snapshot.Name), data, 0600); err != nil {
+		return fmt.Errorf("writing snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// List returns all recorded snapshots, newest first.
+func (s *FileStore) List(_ context.Context) ([]entities.Snapshot, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []entities.Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+
+	snapshots := make([]entities.Snapshot, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), manifestExt) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot manifest: %w", err)
+		}
+
+		var snapshot entities.Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("parsing snapshot manifest %s: %w", entry.Name(), err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// Delete removes a snapshot manifest and its relational backup.
+func (s *FileStore) Delete(_ context.Context, name string) error {
+	for _, path := range []string{s.BackupPath(name), s.manifestPath(name)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// ReadManifest reads the snapshot manifest at path. If the recorded
+// relational backup is missing but a backup of the same name sits beside
+// the manifest, that one is used, so a snapshot directory copied elsewhere
+// can still be read.
+func ReadManifest(path string) (*entities.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, entities.Errorf(entities.ErrNotFound, "snapshot manifest %s not found", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading snapshot manifest: %w", err)
+	}
+
+	var snapshot entities.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, entities.WithKind(entities.ErrValidation, fmt.Errorf("parsing snapshot manifest %s: %w", path, err))
+	}
+
+	if _, err := os.Stat(snapshot.RelationalPath); err != nil {
+		beside := NewFileStore(filepath.Dir(path)).BackupPath(snapshot.Name)
+		if _, err := os.Stat(beside); err == nil {
+			snapshot.RelationalPath = beside
+		}
+	}
+	return &snapshot, nil
+}
+
+func (s *FileStore) manifestPath(name string) string {
+	return filepath.Join(s.dir, name+manifestExt)
+}
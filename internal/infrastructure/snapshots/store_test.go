@@ -0,0 +1,80 @@
+package snapshots
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store := NewFileStore(dir)
+
+	t.Run("missing directory lists nothing", func(t *testing.T) {
+		snapshots, err := store.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, snapshots)
+	})
+
+	older := entities.Snapshot{Name: "older", World: "w", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	newer := entities.Snapshot{Name: "newer", World: "w", CreatedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, store.Save(ctx, &older))
+	require.NoError(t, store.Save(ctx, &newer))
+	require.NoError(t, os.WriteFile(store.BackupPath("older"), []byte("db"), 0600))
+
+	t.Run("lists newest first", func(t *testing.T) {
+		snapshots, err := store.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, "newer", snapshots[0].Name)
+		assert.Equal(t, "older", snapshots[1].Name)
+	})
+
+	t.Run("delete removes manifest and backup", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "older"))
+		assert.NoFileExists(t, store.BackupPath("older"))
+
+		snapshots, err := store.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, "newer", snapshots[0].Name)
+	})
+}
+
+func TestReadManifest(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	snapshot := entities.Snapshot{Name: "20260301-030000", World: "w", RelationalPath: "/gone/20260301-030000.db", Checksum: "abc123"}
+	require.NoError(t, store.Save(ctx, &snapshot))
+	manifest := filepath.Join(dir, "20260301-030000.json")
+
+	t.Run("backup path as recorded", func(t *testing.T) {
+		got, err := ReadManifest(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", got.Checksum)
+		assert.Equal(t, "/gone/20260301-030000.db", got.RelationalPath)
+	})
+
+	t.Run("backup beside a moved manifest", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(store.BackupPath(snapshot.Name), []byte("db"), 0600))
+
+		got, err := ReadManifest(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, store.BackupPath(snapshot.Name), got.RelationalPath)
+	})
+
+	t.Run("missing manifest", func(t *testing.T) {
+		_, err := ReadManifest(filepath.Join(dir, "missing.json"))
+		assert.ErrorIs(t, err, entities.ErrNotFound)
+	})
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DialOption returns a gRPC dial option that traces each unary call, with
+// its request and reply. A nil Tracer returns an option that does nothing.
+func (t *Tracer) DialOption() grpc.DialOption {
+	if t == nil {
+		return grpc.EmptyDialOption{}
+	}
+	return grpc.WithChainUnaryInterceptor(t.intercept)
+}
+
+// intercept is a gRPC interceptor that traces the call.
+func (t *Tracer) intercept(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	var response any
+	if err == nil {
+		response = protoJSON(reply)
+	}
+	t.record(BackendQdrant, method, start, protoJSON(req), response, err)
+	return err
+}
+
+// protoJSON encodes a protobuf message as JSON for redact, or returns v
+// unchanged if it is not one.
+func protoJSON(v any) any {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return v
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	return data
+}
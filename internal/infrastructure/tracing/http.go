@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// transport is an http.RoundTripper that traces each exchange.
+type transport struct {
+	tracer *Tracer
+	next   http.RoundTripper
+}
+
+// Transport returns an http.RoundTripper that traces the requests it sends
+// through next, with their response bodies and status. Headers are not
+// traced. A nil next uses http.DefaultTransport; a nil Tracer returns next.
+func (t *Tracer) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t == nil {
+		return next
+	}
+	return &transport{tracer: t, next: next}
+}
+
+// httpResponse is a response as traced.
+type httpResponse struct {
+	Status int `json:"status"`
+	Body   any `json:"body,omitempty"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	op := req.Method + " " + req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.tracer.record(BackendHTTP, op, start, body, nil, err)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		t.tracer.record(BackendHTTP, op, start, body, httpResponse{Status: resp.StatusCode}, err)
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	t.tracer.record(BackendHTTP, op, start, body, httpResponse{Status: resp.StatusCode, Body: redact(respBody)}, nil)
+	return resp, nil
+}
@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// redacted replaces secrets.
+	redacted = "[REDACTED]"
+	// maxNumbers is the longest list of numbers written as is. Longer
+	// ones, such as embeddings, are summarized by their length.
+	maxNumbers = 16
+)
+
+// secretPattern matches API keys in strings, such as OpenAI's sk-... keys
+// and bearer tokens.
+var secretPattern = regexp.MustCompile(`\b(sk-[A-Za-z0-9_-]{8,}|Bearer\s+[A-Za-z0-9._~+/=-]{8,})`)
+
+// secretFields are the names of fields holding secrets, lowercased and
+// without separators.
+var secretFields = map[string]bool{
+	"apikey":        true,
+	"accesstoken":   true,
+	"authorization": true,
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+}
+
+// secretField reports whether a field of this name holds a secret.
+func secretField(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	return secretFields[name]
+}
+
+// redact returns v as a JSON-like value with its secrets masked and long
+// lists of numbers summarized. A []byte holding JSON is decoded first.
+func redact(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(v) == 0 {
+			return nil
+		}
+		var decoded any
+		if err := json.Unmarshal(v, &decoded); err != nil {
+			return redactString(string(v))
+		}
+		return redactJSON(decoded)
+	case string:
+		return redactString(v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return string(data)
+	}
+	return redactJSON(decoded)
+}
+
+// redactJSON masks the secrets in a decoded JSON value.
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for name, field := range v {
+			if _, ok := field.(string); ok && secretField(name) {
+				out[name] = redacted
+				continue
+			}
+			out[name] = redactJSON(field)
+		}
+		return out
+	case []any:
+		if len(v) > maxNumbers && allNumbers(v) {
+			return fmt.Sprintf("[%d numbers]", len(v))
+		}
+		out := make([]any, len(v))
+		for i := range v {
+			out[i] = redactJSON(v[i])
+		}
+		return out
+	case string:
+		return redactString(v)
+	default:
+		return v
+	}
+}
+
+// redactString replaces the API keys in s.
+func redactString(s string) string {
+	return secretPattern.ReplaceAllString(s, redacted)
+}
+
+func allNumbers(v []any) bool {
+	for _, e := range v {
+		if _, ok := e.(float64); !ok {
+			return false
+		}
+	}
+	return true
+}
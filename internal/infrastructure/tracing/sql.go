@@ -0,0 +1,224 @@
+package tracing
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxArgLength is the longest statement argument traced in full.
+const maxArgLength = 200
+
+// WrapDriver returns a database/sql driver that traces the statements run
+// through d, with their arguments. A nil Tracer returns d.
+func (t *Tracer) WrapDriver(d driver.Driver) driver.Driver {
+	if t == nil {
+		return d
+	}
+	return &tracedDriver{next: d, tracer: t}
+}
+
+type tracedDriver struct {
+	next   driver.Driver
+	tracer *Tracer
+}
+
+func (d *tracedDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.next.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, tracer: d.tracer}, nil
+}
+
+// tracedConn traces the statements run on a connection. Optional driver
+// interfaces the underlying connection lacks are emulated the way
+// database/sql would.
+type tracedConn struct {
+	driver.Conn
+	tracer *Tracer
+}
+
+// statementResult is an Exec result as traced.
+type statementResult struct {
+	RowsAffected int64 `json:"rows_affected"`
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.tracer.recordStatement(query, args, start, result, err)
+	return result, err
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.tracer.recordStatement(query, args, start, nil, err)
+	return rows, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracedStmt{Stmt: stmt, query: query, tracer: c.tracer}, nil
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var (
+		tx  driver.Tx
+		err error
+	)
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Begin() //nolint:staticcheck // Fallback for drivers without BeginTx
+	}
+	c.tracer.record(BackendSQLite, "BEGIN", start, nil, nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{Tx: tx, tracer: c.tracer}, nil
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+type tracedTx struct {
+	driver.Tx
+	tracer *Tracer
+}
+
+func (tx *tracedTx) Commit() error {
+	start := time.Now()
+	err := tx.Tx.Commit()
+	tx.tracer.record(BackendSQLite, "COMMIT", start, nil, nil, err)
+	return err
+}
+
+func (tx *tracedTx) Rollback() error {
+	start := time.Now()
+	err := tx.Tx.Rollback()
+	tx.tracer.record(BackendSQLite, "ROLLBACK", start, nil, nil, err)
+	return err
+}
+
+// tracedStmt traces each run of a prepared statement.
+type tracedStmt struct {
+	driver.Stmt
+	query  string
+	tracer *Tracer
+}
+
+func (s *tracedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		result driver.Result
+		err    error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.Exec(namedValues(args)) //nolint:staticcheck // Fallback for drivers without ExecContext
+	}
+	s.tracer.recordStatement(s.query, args, start, result, err)
+	return result, err
+}
+
+func (s *tracedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Query(namedValues(args)) //nolint:staticcheck // Fallback for drivers without QueryContext
+	}
+	s.tracer.recordStatement(s.query, args, start, nil, err)
+	return rows, err
+}
+
+// recordStatement traces a statement, on one line, with its arguments and
+// the rows an Exec changed.
+func (t *Tracer) recordStatement(query string, args []driver.NamedValue, start time.Time, result driver.Result, err error) {
+	var response any
+	if result != nil && err == nil {
+		if n, rerr := result.RowsAffected(); rerr == nil {
+			response = statementResult{RowsAffected: n}
+		}
+	}
+	op := strings.Join(strings.Fields(query), " ")
+	t.record(BackendSQLite, op, start, statementArgs(args), response, err)
+}
+
+// statementArgs returns args as traced: long strings are cut short and
+// blobs are summarized by their size.
+func statementArgs(args []driver.NamedValue) []any {
+	if len(args) == 0 {
+		return nil
+	}
+	out := make([]any, len(args))
+	for i, arg := range args {
+		switch v := arg.Value.(type) {
+		case []byte:
+			out[i] = fmt.Sprintf("[%d bytes]", len(v))
+		case string:
+			if len(v) > maxArgLength {
+				v = fmt.Sprintf("%s... (%d bytes)", v[:maxArgLength], len(v))
+			}
+			out[i] = v
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
@@ -0,0 +1,113 @@
+// Package tracing logs the calls lore makes to its backends, for debugging:
+// the LLM and embedding requests and responses, Qdrant gRPC calls, and
+// SQLite statements, each with its timing. Each run writes one JSON Lines
+// file that can be attached to a bug report.
+//
+// Secrets are redacted before anything is written: request headers are
+// never logged, fields named like keys, tokens, or passwords are masked, and
+// API keys in strings are replaced. Embedding vectors and other long lists
+// of numbers are summarized by their length.
+//
+// A nil *Tracer traces nothing, so callers need not check whether tracing
+// is on.
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Backends recorded in trace events.
+const (
+	BackendHTTP   = "http"
+	BackendQdrant = "qdrant"
+	BackendSQLite = "sqlite"
+)
+
+// Event is one traced call.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Backend  string    `json:"backend"`
+	Op       string    `json:"op"`
+	Duration float64   `json:"duration_ms"`
+	Request  any       `json:"request,omitempty"`
+	Response any       `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Tracer writes trace events as JSON Lines. It is safe for concurrent use.
+type Tracer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	path   string
+}
+
+// Open creates a trace file for this run in dir, creating dir if needed.
+func Open(dir string) (*Tracer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("creating trace directory: %w", err)
+	}
+
+	name := fmt.Sprintf("trace-%s-%d.jsonl", time.Now().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace file: %w", err)
+	}
+	return &Tracer{w: f, closer: f, path: path}, nil
+}
+
+// newTracer creates a Tracer writing to w.
+func newTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// Path returns the trace file's path, or "" if it does not write to a file.
+func (t *Tracer) Path() string {
+	if t == nil {
+		return ""
+	}
+	return t.path
+}
+
+// Close closes the trace file.
+func (t *Tracer) Close() error {
+	if t == nil || t.closer == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closer.Close()
+}
+
+// record writes a call to backend that started at start. Request and
+// response are redacted first. Tracing is best effort: a failure to write
+// never fails the call.
+func (t *Tracer) record(backend, op string, start time.Time, request, response any, err error) {
+	event := Event{
+		Time:     start.UTC(),
+		Backend:  backend,
+		Op:       op,
+		Duration: float64(time.Since(start).Microseconds()) / 1000,
+		Request:  redact(request),
+		Response: redact(response),
+	}
+	if err != nil {
+		event.Error = redactString(err.Error())
+	}
+
+	data, merr := json.Marshal(event)
+	if merr != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(append(data, '\n'))
+}
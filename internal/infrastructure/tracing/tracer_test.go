@@ -0,0 +1,199 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	_ "modernc.org/sqlite"
+)
+
+// events decodes the events written to buf.
+func events(t *testing.T, buf *bytes.Buffer) []Event {
+	t.Helper()
+	var out []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Event
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		out = append(out, e)
+	}
+	return out
+}
+
+func TestRedact(t *testing.T) {
+	vector := make([]float64, 32)
+	in := map[string]any{
+		"model":    "gpt-4o-mini",
+		"api_key":  "sk-abcdefghijklmnop",
+		"messages": []any{map[string]any{"role": "user", "content": "Frodo carries the Ring. key=sk-abcdefghijklmnop"}},
+		"filter":   map[string]any{"key": "source_file", "claim_key": "frodo|carries|ring"},
+		"vector":   vector,
+		"limit":    []int{1, 2, 3},
+	}
+
+	got := redact(in).(map[string]any)
+
+	assert.Equal(t, "gpt-4o-mini", got["model"])
+	assert.Equal(t, redacted, got["api_key"])
+	assert.Equal(t, "Frodo carries the Ring. key=[REDACTED]", got["messages"].([]any)[0].(map[string]any)["content"])
+	assert.Equal(t, map[string]any{"key": "source_file", "claim_key": "frodo|carries|ring"}, got["filter"], "ordinary keys are kept")
+	assert.Equal(t, "[32 numbers]", got["vector"])
+	assert.Equal(t, []any{1.0, 2.0, 3.0}, got["limit"], "short lists are kept")
+
+	assert.Equal(t, "not json [REDACTED]", redact([]byte("not json Bearer abcdefghijkl")))
+	assert.Nil(t, redact(nil))
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+
+	assert.Equal(t, http.DefaultTransport, tracer.Transport(nil))
+	assert.Equal(t, grpc.EmptyDialOption{}, tracer.DialOption())
+	assert.Empty(t, tracer.Path())
+	assert.NoError(t, tracer.Close())
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"input":"Frodo"}`, string(body), "the request body reaches the server")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"embedding":[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16]}]}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := &http.Client{Transport: newTracer(&buf).Transport(nil)}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/embeddings", strings.NewReader(`{"input":"Frodo"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer sk-abcdefghijklmnop")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "embedding", "the caller still reads the response")
+
+	got := events(t, &buf)
+	require.Len(t, got, 1)
+	assert.Equal(t, BackendHTTP, got[0].Backend)
+	assert.Equal(t, "POST "+server.URL+"/v1/embeddings", got[0].Op)
+	assert.Equal(t, map[string]any{"input": "Frodo"}, got[0].Request)
+	assert.Equal(t, map[string]any{
+		"status": 200.0,
+		"body":   map[string]any{"data": []any{map[string]any{"embedding": "[17 numbers]"}}},
+	}, got[0].Response)
+	assert.NotContains(t, buf.String(), "sk-abcdefghijklmnop", "headers are not traced")
+}
+
+func TestDialOption(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := newTracer(&buf)
+
+	req := &pb.GetCollectionInfoRequest{CollectionName: "lore_middle_earth"}
+	reply := &pb.HealthCheckReply{Title: "qdrant"}
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+	fail := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return errors.New("unavailable")
+	}
+
+	require.NoError(t, tracer.intercept(context.Background(), "/qdrant.Collections/Get", req, reply, nil, ok))
+	require.Error(t, tracer.intercept(context.Background(), "/qdrant.Collections/Get", req, reply, nil, fail))
+
+	got := events(t, &buf)
+	require.Len(t, got, 2)
+	assert.Equal(t, BackendQdrant, got[0].Backend)
+	assert.Equal(t, "/qdrant.Collections/Get", got[0].Op)
+	assert.Equal(t, map[string]any{"collectionName": "lore_middle_earth"}, got[0].Request)
+	assert.Equal(t, map[string]any{"title": "qdrant"}, got[0].Response)
+	assert.Nil(t, got[1].Response, "a failed call has no reply")
+	assert.Equal(t, "unavailable", got[1].Error)
+}
+
+func TestWrapDriver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	plain, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	drv := plain.Driver()
+	plain.Close()
+
+	var buf bytes.Buffer
+	db := sql.OpenDB(&testConnector{path: path, driver: newTracer(&buf).WrapDriver(drv)})
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err = db.ExecContext(ctx, "CREATE TABLE facts (\n\tid TEXT,\n\tobject TEXT\n)")
+	require.NoError(t, err)
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "INSERT INTO facts (id, object) VALUES (?, ?)", "f1", strings.Repeat("x", 300))
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit())
+
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT count(*) FROM facts WHERE id = ?", "f1").Scan(&n))
+	assert.Equal(t, 1, n)
+
+	got := events(t, &buf)
+	var ops []string
+	for _, e := range got {
+		assert.Equal(t, BackendSQLite, e.Backend)
+		ops = append(ops, e.Op)
+	}
+	assert.Equal(t, []string{
+		"CREATE TABLE facts ( id TEXT, object TEXT )",
+		"BEGIN",
+		"INSERT INTO facts (id, object) VALUES (?, ?)",
+		"COMMIT",
+		"SELECT count(*) FROM facts WHERE id = ?",
+	}, ops)
+
+	insert := got[2]
+	assert.Equal(t, map[string]any{"rows_affected": 1.0}, insert.Response)
+	args := insert.Request.([]any)
+	assert.Equal(t, "f1", args[0])
+	assert.Equal(t, strings.Repeat("x", maxArgLength)+"... (300 bytes)", args[1], "long arguments are cut short")
+}
+
+func TestOpen(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "traces")
+
+	tracer, err := Open(dir)
+	require.NoError(t, err)
+	tracer.record(BackendSQLite, "SELECT 1", time.Now(), nil, nil, nil)
+	require.NoError(t, tracer.Close())
+
+	assert.Equal(t, dir, filepath.Dir(tracer.Path()))
+	data, err := os.ReadFile(tracer.Path())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"op":"SELECT 1"`)
+}
+
+// testConnector opens connections to path with driver.
+type testConnector struct {
+	path   string
+	driver driver.Driver
+}
+
+func (c *testConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.path) }
+func (c *testConnector) Driver() driver.Driver                        { return c.driver }
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// replicaSyncBatch is how many facts a sync reads from the upstream
+// collection at a time.
+const replicaSyncBatch = 256
+
+// Replica is a read-only VectorDB holding a copy of a collection's facts,
+// embeddings included, so query-heavy instances answer reads without
+// loading the upstream store. Sync refreshes the copy; writes are refused.
+type Replica struct {
+	*Repository
+
+	admin        ports.VectorCollectionAdmin
+	collection   string
+	maxStaleness time.Duration
+	now          func() time.Time
+
+	syncMu sync.Mutex // Serializes syncs
+
+	mu       sync.Mutex
+	syncedAt time.Time
+	lastErr  error
+}
+
+// NewReplica creates an empty replica of collection, read through admin.
+// It is stale once its copy is older than maxStaleness (0 = never).
+func NewReplica(admin ports.VectorCollectionAdmin, collection string, maxStaleness time.Duration) *Replica {
+	return &Replica{
+		Repository:   NewRepository(),
+		admin:        admin,
+		collection:   collection,
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+	}
+}
+
+// Sync replaces the copy with the collection's current facts. Reads are
+// answered from the previous copy until it completes; if it fails, the
+// previous copy is kept.
+func (r *Replica) Sync(ctx context.Context) error {
+	r.syncMu.Lock()
+	defer r.syncMu.Unlock()
+
+	// The copy is as old as the moment reading it began
+	started := r.now()
+	var facts []entities.Fact
+	err := r.admin.ScrollFacts(ctx, r.collection, replicaSyncBatch, func(batch []entities.Fact) error {
+		facts = append(facts, batch...)
+		return nil
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.lastErr = err
+		return fmt.Errorf("syncing read cache: %w", err)
+	}
+	r.Replace(facts)
+	r.syncedAt = started
+	r.lastErr = nil
+	return nil
+}
+
+// Status reports how current the copy is.
+func (r *Replica) Status() ports.ReplicaStatus {
+	r.mu.Lock()
+	syncedAt, lastErr := r.syncedAt, r.lastErr
+	r.mu.Unlock()
+
+	r.Repository.mu.RLock()
+	count := len(r.facts)
+	r.Repository.mu.RUnlock()
+
+	status := ports.ReplicaStatus{
+		Facts:               count,
+		SyncedAt:            syncedAt,
+		MaxStalenessSeconds: int64(r.maxStaleness.Seconds()),
+		Stale:               r.stale(syncedAt),
+	}
+	if !syncedAt.IsZero() {
+		status.AgeSeconds = int64(r.now().Sub(syncedAt).Seconds())
+	}
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	}
+	return status
+}
+
+// Check fails while the copy is stale, as a readiness check.
+func (r *Replica) Check(_ context.Context) error {
+	r.mu.Lock()
+	syncedAt, lastErr := r.syncedAt, r.lastErr
+	r.mu.Unlock()
+
+	if !r.stale(syncedAt) {
+		return nil
+	}
+	if syncedAt.IsZero() {
+		return fmt.Errorf("read cache has not synced")
+	}
+	if lastErr != nil {
+		return fmt.Errorf("read cache is older than %s: %w", r.maxStaleness, lastErr)
+	}
+	return fmt.Errorf("read cache is older than %s", r.maxStaleness)
+}
+
+// stale reports whether a copy taken at syncedAt is too old to answer
+// from.
+func (r *Replica) stale(syncedAt time.Time) bool {
+	if syncedAt.IsZero() {
+		return true
+	}
+	return r.maxStaleness > 0 && r.now().Sub(syncedAt) > r.maxStaleness
+}
+
+// readOnly is the error every write to a replica returns.
+func readOnly() error {
+	return entities.Errorf(entities.ErrValidation, "the read cache is read-only; write through an instance without --read-cache")
+}
+
+// EnsureCollection refuses to write.
+func (r *Replica) EnsureCollection(context.Context, uint64) error { return readOnly() }
+
+// DeleteCollection refuses to write.
+func (r *Replica) DeleteCollection(context.Context) error { return readOnly() }
+
+// Save refuses to write.
+func (r *Replica) Save(context.Context, *entities.Fact) error { return readOnly() }
+
+// SaveBatch refuses to write.
+func (r *Replica) SaveBatch(context.Context, []entities.Fact) error { return readOnly() }
+
+// Delete refuses to write.
+func (r *Replica) Delete(context.Context, string) error { return readOnly() }
+
+// DeleteBySource refuses to write.
+func (r *Replica) DeleteBySource(context.Context, string) error { return readOnly() }
+
+// SetSourceArchived refuses to write.
+func (r *Replica) SetSourceArchived(context.Context, string, bool) error { return readOnly() }
+
+// DeleteAll refuses to write.
+func (r *Replica) DeleteAll(context.Context) error { return readOnly() }
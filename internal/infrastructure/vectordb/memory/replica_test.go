@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var _ ports.VectorDB = (*Replica)(nil)
+
+// upstream is a collection admin serving one collection's facts.
+type upstream struct {
+	ports.VectorCollectionAdmin
+	facts []entities.Fact
+	err   error
+}
+
+func (u *upstream) ScrollFacts(_ context.Context, _ string, batchSize int, fn func([]entities.Fact) error) error {
+	if u.err != nil {
+		return u.err
+	}
+	for start := 0; start < len(u.facts); start += batchSize {
+		if err := fn(u.facts[start:min(start+batchSize, len(u.facts))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestReplica_Sync(t *testing.T) {
+	ctx := context.Background()
+	source := &upstream{facts: testFacts()}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	replica := NewReplica(source, "world", 5*time.Minute)
+	replica.now = func() time.Time { return now }
+
+	assert.True(t, replica.Status().Stale, "stale until the first sync")
+	assert.Error(t, replica.Check(ctx))
+
+	require.NoError(t, replica.Sync(ctx))
+	results, err := replica.Search(ctx, []float32{1, 0}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(results), "embeddings are copied")
+
+	source.facts = source.facts[:2]
+	now = now.Add(time.Minute)
+	require.NoError(t, replica.Sync(ctx))
+	count, err := replica.Count(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), count, "facts deleted upstream are gone")
+
+	status := replica.Status()
+	assert.Equal(t, 2, status.Facts)
+	assert.Equal(t, now, status.SyncedAt)
+	assert.Equal(t, int64(300), status.MaxStalenessSeconds)
+	assert.False(t, status.Stale)
+	assert.NoError(t, replica.Check(ctx))
+
+	source.err = errors.New("qdrant down")
+	now = now.Add(10 * time.Minute)
+	require.Error(t, replica.Sync(ctx))
+	status = replica.Status()
+	assert.Equal(t, 2, status.Facts, "the previous copy is kept")
+	assert.Equal(t, int64(600), status.AgeSeconds)
+	assert.True(t, status.Stale)
+	assert.Equal(t, "qdrant down", status.LastError)
+	assert.ErrorContains(t, replica.Check(ctx), "qdrant down")
+}
+
+func TestReplica_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	replica := NewReplica(&upstream{}, "world", 0)
+
+	assert.ErrorIs(t, replica.Save(ctx, &entities.Fact{ID: "1"}), entities.ErrValidation)
+	assert.ErrorIs(t, replica.SaveBatch(ctx, testFacts()), entities.ErrValidation)
+	assert.ErrorIs(t, replica.Delete(ctx, "1"), entities.ErrValidation)
+	assert.ErrorIs(t, replica.DeleteAll(ctx), entities.ErrValidation)
+}
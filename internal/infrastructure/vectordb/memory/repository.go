@@ -0,0 +1,443 @@
+// Package memory provides a VectorDB implementation that keeps facts in
+// memory. Searches compare every fact, so it suits demos and tests rather
+// than large worlds; nothing survives the process.
+package memory
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// Repository implements the VectorDB interface in memory. It is safe for
+// concurrent use.
+type Repository struct {
+	mu    sync.RWMutex
+	facts map[string]entities.Fact
+	order []string // Fact IDs in the order first saved
+}
+
+// NewRepository creates an empty in-memory repository.
+func NewRepository() *Repository {
+	return &Repository{facts: make(map[string]entities.Fact)}
+}
+
+// EnsureCollection does nothing; the repository is always ready.
+func (r *Repository) EnsureCollection(_ context.Context, _ uint64) error {
+	return nil
+}
+
+// DeleteCollection removes every fact.
+func (r *Repository) DeleteCollection(ctx context.Context) error {
+	return r.DeleteAll(ctx)
+}
+
+// Save stores a fact, replacing any with the same ID.
+func (r *Repository) Save(ctx context.Context, fact *entities.Fact) error {
+	return r.SaveBatch(ctx, []entities.Fact{*fact})
+}
+
+// SaveBatch stores facts, replacing any with the same IDs.
+func (r *Repository) SaveBatch(_ context.Context, facts []entities.Fact) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range facts {
+		fact := facts[i]
+		fact.Embedding = slices.Clone(fact.Embedding)
+		fact.TextEmbedding = slices.Clone(fact.TextEmbedding)
+		if _, ok := r.facts[fact.ID]; !ok {
+			r.order = append(r.order, fact.ID)
+		}
+		r.facts[fact.ID] = fact
+	}
+	return nil
+}
+
+// FindByID retrieves a fact by its ID.
+func (r *Repository) FindByID(_ context.Context, id string) (entities.Fact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fact, ok := r.facts[id]
+	if !ok {
+		return entities.Fact{}, entities.Errorf(entities.ErrNotFound, "fact not found: %s", id)
+	}
+	return fact, nil
+}
+
+// ExistsByIDs checks which IDs exist.
+func (r *Repository) ExistsByIDs(_ context.Context, ids []string) (map[string]bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	exists := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if _, ok := r.facts[id]; ok {
+			exists[id] = true
+		}
+	}
+	return exists, nil
+}
+
+// FindByIDs retrieves the facts with the given IDs, without embeddings.
+func (r *Repository) FindByIDs(_ context.Context, ids []string) ([]entities.Fact, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	facts := make([]entities.Fact, 0, len(ids))
+	for _, id := range ids {
+		if fact, ok := r.facts[id]; ok {
+			facts = append(facts, withoutVectors(&fact))
+		}
+	}
+	return facts, nil
+}
+
+// Search performs a semantic search against the context embedding.
+func (r *Repository) Search(ctx context.Context, embedding []float32, limit int) ([]entities.Fact, error) {
+	return r.SearchVector(ctx, ports.VectorContext, embedding, "", limit)
+}
+
+// SearchByType performs a semantic search filtered by fact type.
+func (r *Repository) SearchByType(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return r.SearchVector(ctx, ports.VectorContext, embedding, factType, limit)
+}
+
+// SearchBySubject ranks facts whose subject exactly matches subject by
+// cosine similarity to the context embedding. Facts pending review are
+// excluded.
+func (r *Repository) SearchBySubject(_ context.Context, embedding []float32, subject string, limit int) ([]entities.Fact, error) {
+	return r.rank("", limit, func(fact *entities.Fact) float64 {
+		if fact.Subject != subject {
+			return math.Inf(-1)
+		}
+		return cosine(embedding, fact.Embedding)
+	}), nil
+}
+
+// SearchVector ranks facts by cosine similarity between embedding and the
+// named stored embedding, optionally filtered by fact type (empty = all
+// types). Facts pending review are excluded.
+func (r *Repository) SearchVector(_ context.Context, vector ports.VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return r.rank(factType, limit, func(fact *entities.Fact) float64 {
+		stored := fact.Embedding
+		if vector == ports.VectorText && len(fact.TextEmbedding) > 0 {
+			stored = fact.TextEmbedding
+		}
+		return cosine(embedding, stored)
+	}), nil
+}
+
+// SearchKeywords ranks facts by how many of the query's words their text
+// contains, optionally filtered by fact type (empty = all types). Facts
+// with none of the words, and facts pending review, are excluded.
+func (r *Repository) SearchKeywords(_ context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	terms := tokenize(query)
+	if len(terms) == 0 {
+		return []entities.Fact{}, nil
+	}
+
+	return r.rank(factType, limit, func(fact *entities.Fact) float64 {
+		counts := make(map[string]int)
+		for _, token := range tokenize(strings.Join([]string{fact.Subject, fact.Predicate, fact.Object, fact.Context}, " ")) {
+			counts[token]++
+		}
+		var score float64
+		for _, term := range terms {
+			if tf := float64(counts[term]); tf > 0 {
+				score += tf * 2.2 / (tf + 1.2) // BM25 term-frequency saturation
+			}
+		}
+		if score == 0 {
+			return math.Inf(-1)
+		}
+		return score
+	}), nil
+}
+
+// rank scores the active facts of factType and returns up to limit of
+// them, best first. Facts scored -Inf are left out.
+func (r *Repository) rank(factType entities.FactType, limit int, score func(*entities.Fact) float64) []entities.Fact {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type scored struct {
+		fact  entities.Fact
+		score float64
+	}
+	var results []scored
+	for _, id := range r.order {
+		fact := r.facts[id]
+		if fact.IsPending() || (factType != "" && fact.Type != factType) {
+			continue
+		}
+		if s := score(&fact); !math.IsInf(s, -1) {
+			results = append(results, scored{fact: fact, score: s})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	facts := make([]entities.Fact, 0, len(results))
+	for i := range results {
+		facts = append(facts, results[i].fact)
+	}
+	return truncate(facts, limit)
+}
+
+// Delete removes a fact by its ID.
+func (r *Repository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteWhere(func(fact *entities.Fact) bool { return fact.ID == id })
+	return nil
+}
+
+// List returns facts in the order they were first saved, skipping offset
+// of them.
+func (r *Repository) List(_ context.Context, limit int, offset uint64) ([]entities.Fact, error) {
+	facts := r.filter(func(*entities.Fact) bool { return true })
+	if offset >= uint64(len(facts)) {
+		return []entities.Fact{}, nil
+	}
+	return truncate(facts[offset:], limit), nil
+}
+
+// ListPage returns a page of facts in insertion order and the cursor of the
+// next page: the ID of its first fact.
+func (r *Repository) ListPage(_ context.Context, opts ports.FactPageOptions) ([]entities.Fact, string, error) {
+	facts := r.filter(func(fact *entities.Fact) bool {
+		return (opts.Type == "" || fact.Type == opts.Type) &&
+			(opts.SourceFile == "" || fact.SourceFile == opts.SourceFile) &&
+			(!opts.Drafts || fact.IsDraft())
+	})
+
+	start := 0
+	if opts.Cursor != "" {
+		start = slices.IndexFunc(facts, func(fact entities.Fact) bool { return fact.ID == opts.Cursor })
+		if start < 0 {
+			return nil, "", entities.Errorf(entities.ErrValidation, "invalid cursor %q", opts.Cursor)
+		}
+	}
+
+	facts = facts[start:]
+	if opts.Limit <= 0 || len(facts) <= opts.Limit {
+		return facts, "", nil
+	}
+	return facts[:opts.Limit], facts[opts.Limit].ID, nil
+}
+
+// ListByType returns facts of a type.
+func (r *Repository) ListByType(_ context.Context, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool { return fact.Type == factType }), limit), nil
+}
+
+// ListFiltered returns facts matching the given type and time range,
+// optionally ordered newest first by creation or update time.
+func (r *Repository) ListFiltered(_ context.Context, opts ports.FactListOptions) ([]entities.Fact, error) {
+	timeOf := func(fact *entities.Fact) time.Time {
+		if opts.TimeField() == "updated_at" {
+			return fact.UpdatedAt
+		}
+		return fact.CreatedAt
+	}
+
+	facts := r.filter(func(fact *entities.Fact) bool {
+		t := timeOf(fact)
+		return (opts.Type == "" || fact.Type == opts.Type) &&
+			(opts.Since.IsZero() || !t.Before(opts.Since)) &&
+			(opts.Until.IsZero() || !t.After(opts.Until))
+	})
+	if opts.Sort != "" {
+		sort.SliceStable(facts, func(i, j int) bool { return timeOf(&facts[i]).After(timeOf(&facts[j])) })
+	}
+	return truncate(facts, opts.Limit), nil
+}
+
+// ListPending returns facts awaiting review.
+func (r *Repository) ListPending(_ context.Context, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool { return fact.IsPending() }), limit), nil
+}
+
+// ListBySubject returns facts whose subject exactly matches one of the
+// given names. Facts pending review are skipped.
+func (r *Repository) ListBySubject(_ context.Context, subjects []string, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool {
+		return !fact.IsPending() && slices.Contains(subjects, fact.Subject)
+	}), limit), nil
+}
+
+// ListByClaimKeys returns facts whose claim key is one of keys. Facts
+// pending review are skipped.
+func (r *Repository) ListByClaimKeys(_ context.Context, keys []string, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool {
+		return !fact.IsPending() && slices.Contains(keys, fact.ClaimKey())
+	}), limit), nil
+}
+
+// ListBySource returns facts from a source file.
+func (r *Repository) ListBySource(_ context.Context, sourceFile string, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool { return fact.SourceFile == sourceFile }), limit), nil
+}
+
+// ListByEntities returns facts whose subject or object exactly matches
+// one of the given entity names.
+func (r *Repository) ListByEntities(_ context.Context, names []string, limit int) ([]entities.Fact, error) {
+	return truncate(r.filter(func(fact *entities.Fact) bool {
+		return slices.Contains(names, fact.Subject) || slices.Contains(names, fact.Object)
+	}), limit), nil
+}
+
+// DeleteBySource removes all facts from a source file.
+func (r *Repository) DeleteBySource(_ context.Context, sourceFile string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteWhere(func(fact *entities.Fact) bool { return fact.SourceFile == sourceFile })
+	return nil
+}
+
+// SetSourceArchived marks or unmarks every fact from a source file as
+// archived.
+func (r *Repository) SetSourceArchived(_ context.Context, sourceFile string, archived bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range r.order {
+		fact := r.facts[id]
+		if fact.SourceFile == sourceFile {
+			fact.Archived = archived
+			r.facts[id] = fact
+		}
+	}
+	return nil
+}
+
+// DeleteAll removes all facts.
+func (r *Repository) DeleteAll(_ context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.facts = make(map[string]entities.Fact)
+	r.order = nil
+	return nil
+}
+
+// Replace swaps every stored fact for facts at once, so readers see
+// either the old facts or the new ones.
+func (r *Repository) Replace(facts []entities.Fact) {
+	stored := make(map[string]entities.Fact, len(facts))
+	order := make([]string, 0, len(facts))
+	for i := range facts {
+		if _, ok := stored[facts[i].ID]; !ok {
+			order = append(order, facts[i].ID)
+		}
+		stored[facts[i].ID] = facts[i]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.facts = stored
+	r.order = order
+}
+
+// CountBySubject returns the number of facts whose subject exactly
+// matches one of the given names.
+func (r *Repository) CountBySubject(_ context.Context, subjects []string) (uint64, error) {
+	return uint64(len(r.filter(func(fact *entities.Fact) bool { return slices.Contains(subjects, fact.Subject) }))), nil
+}
+
+// Count returns the total number of facts.
+func (r *Repository) Count(_ context.Context) (uint64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return uint64(len(r.facts)), nil
+}
+
+// filter returns the facts keep accepts, in save order and without
+// embeddings, as Qdrant lists them.
+func (r *Repository) filter(keep func(*entities.Fact) bool) []entities.Fact {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	facts := make([]entities.Fact, 0)
+	for _, id := range r.order {
+		fact := r.facts[id]
+		if keep(&fact) {
+			facts = append(facts, withoutVectors(&fact))
+		}
+	}
+	return facts
+}
+
+// deleteWhere removes the facts match accepts. The caller holds the lock.
+func (r *Repository) deleteWhere(match func(*entities.Fact) bool) {
+	r.order = slices.DeleteFunc(r.order, func(id string) bool {
+		fact := r.facts[id]
+		if match(&fact) {
+			delete(r.facts, id)
+			return true
+		}
+		return false
+	})
+}
+
+// truncate returns at most limit facts, or all of them if limit is not
+// positive.
+func truncate(facts []entities.Fact, limit int) []entities.Fact {
+	if limit > 0 && len(facts) > limit {
+		return facts[:limit]
+	}
+	return facts
+}
+
+func withoutVectors(fact *entities.Fact) entities.Fact {
+	stripped := *fact
+	stripped.Embedding = nil
+	stripped.TextEmbedding = nil
+	return stripped
+}
+
+// cosine returns the cosine similarity of a and b, or 0 if either is zero
+// or their sizes differ.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// stopwords carry no keyword signal and are not matched.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "the": true, "to": true, "was": true, "were": true, "with": true,
+}
+
+// tokenize lowercases text and splits it into words, dropping stopwords.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return slices.DeleteFunc(words, func(w string) bool { return stopwords[w] })
+}
@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+var _ ports.VectorDB = (*Repository)(nil)
+
+func testFacts() []entities.Fact {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []entities.Fact{
+		{ID: "1", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "carries", Object: "the One Ring", SourceFile: "a.md", Embedding: []float32{1, 0}, CreatedAt: day},
+		{ID: "2", Type: entities.FactTypeLocation, Subject: "Shire", Predicate: "lies_in", Object: "Eriador", SourceFile: "b.md", Embedding: []float32{0, 1}, TextEmbedding: []float32{1, 0}, CreatedAt: day.AddDate(0, 0, 1)},
+		{ID: "3", Type: entities.FactTypeCharacter, Subject: "Sam", Predicate: "follows", Object: "Frodo", SourceFile: "a.md", Embedding: []float32{0.7, 0.7}, CreatedAt: day.AddDate(0, 0, 2)},
+		{ID: "4", Type: entities.FactTypeCharacter, Subject: "Frodo", Predicate: "fears", Object: "the Ring", Status: entities.FactStatusPending, Embedding: []float32{1, 0}, CreatedAt: day},
+	}
+}
+
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	r := NewRepository()
+	require.NoError(t, r.SaveBatch(context.Background(), testFacts()))
+	return r
+}
+
+func ids(facts []entities.Fact) []string {
+	result := make([]string, len(facts))
+	for i := range facts {
+		result[i] = facts[i].ID
+	}
+	return result
+}
+
+func TestRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	tests := []struct {
+		name     string
+		vector   ports.VectorName
+		factType entities.FactType
+		limit    int
+		want     []string
+	}{
+		{name: "context", vector: ports.VectorContext, want: []string{"1", "3", "2"}},
+		{name: "text falls back to context", vector: ports.VectorText, want: []string{"1", "2", "3"}},
+		{name: "type filter", vector: ports.VectorContext, factType: entities.FactTypeCharacter, want: []string{"1", "3"}},
+		{name: "limit", vector: ports.VectorContext, limit: 1, want: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			facts, err := r.SearchVector(ctx, tt.vector, []float32{1, 0}, tt.factType, tt.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ids(facts))
+		})
+	}
+}
+
+func TestRepository_SearchBySubject(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	facts, err := r.SearchBySubject(ctx, []float32{1, 0}, "Frodo", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(facts), "pending facts and other subjects excluded")
+
+	facts, err = r.SearchBySubject(ctx, []float32{1, 0}, "frodo", 10)
+	require.NoError(t, err)
+	assert.Empty(t, facts, "subjects match exactly")
+}
+
+func TestRepository_SearchKeywords(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	facts, err := r.SearchKeywords(ctx, "the ring of Frodo", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3"}, ids(facts), "pending and unmatched facts excluded")
+
+	facts, err = r.SearchKeywords(ctx, "the of", "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, facts)
+}
+
+func TestRepository_Lists(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	facts, err := r.List(ctx, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2", "3"}, ids(facts))
+	assert.Nil(t, facts[0].Embedding, "listed without embeddings")
+
+	facts, err = r.ListPending(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4"}, ids(facts))
+
+	facts, err = r.ListBySubject(ctx, []string{"Frodo"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(facts))
+
+	facts, err = r.ListByClaimKeys(ctx, []string{"frodo|carries", "frodo|fears"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(facts), "pending facts are skipped")
+
+	facts, err = r.ListByEntities(ctx, []string{"Frodo"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "4"}, ids(facts))
+
+	facts, err = r.ListFiltered(ctx, ports.FactListOptions{
+		Type:  entities.FactTypeCharacter,
+		Since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sort:  ports.FactSortCreated,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "1", "4"}, ids(facts))
+
+	n, err := r.CountBySubject(ctx, []string{"Frodo"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), n)
+}
+
+func TestRepository_ListPage(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	var pages [][]string
+	opts := ports.FactPageOptions{Limit: 3}
+	for {
+		facts, next, err := r.ListPage(ctx, opts)
+		require.NoError(t, err)
+		pages = append(pages, ids(facts))
+		if next == "" {
+			break
+		}
+		opts.Cursor = next
+	}
+	assert.Equal(t, [][]string{{"1", "2", "3"}, {"4"}}, pages)
+
+	facts, next, err := r.ListPage(ctx, ports.FactPageOptions{Type: entities.FactTypeCharacter, SourceFile: "a.md", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids(facts))
+	assert.Equal(t, "3", next)
+
+	_, _, err = r.ListPage(ctx, ports.FactPageOptions{Limit: 1, Cursor: "missing"})
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+func TestRepository_SaveAndDelete(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepository(t)
+
+	updated := testFacts()[0]
+	updated.Object = "Sting"
+	require.NoError(t, r.Save(ctx, &updated))
+
+	fact, err := r.FindByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Sting", fact.Object)
+	assert.Equal(t, []float32{1, 0}, fact.Embedding)
+
+	require.NoError(t, r.SetSourceArchived(ctx, "a.md", true))
+	fact, err = r.FindByID(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, fact.Archived)
+	assert.Equal(t, []float32{1, 0}, fact.Embedding, "archiving leaves the rest of the fact")
+
+	require.NoError(t, r.DeleteBySource(ctx, "a.md"))
+	require.NoError(t, r.Delete(ctx, "2"))
+
+	exists, err := r.ExistsByIDs(ctx, []string{"1", "2", "3", "4"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"4": true}, exists)
+
+	_, err = r.FindByID(ctx, "1")
+	assert.ErrorIs(t, err, entities.ErrNotFound)
+
+	require.NoError(t, r.DeleteAll(ctx))
+	n, err := r.Count(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
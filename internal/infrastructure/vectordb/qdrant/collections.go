@@ -0,0 +1,192 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/grpc"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// CollectionAdmin implements the VectorCollectionAdmin interface using Qdrant.
+type CollectionAdmin struct {
+	storage config.QdrantConfig
+	client  pb.CollectionsClient
+	points  pb.PointsClient
+	conn    *grpc.ClientConn
+}
+
+// NewCollectionAdmin creates a new Qdrant collection admin.
+// The Collection field of cfg is ignored; storage tuning options are
+// applied to collections it creates. opts are added to the connection's
+// dial options.
+func NewCollectionAdmin(cfg *config.QdrantConfig, opts ...grpc.DialOption) (*CollectionAdmin, error) {
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+
+	conn, err := dial(addr, opts)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to qdrant: %w", err)
+	}
+
+	return &CollectionAdmin{
+		storage: *cfg,
+		client:  pb.NewCollectionsClient(conn),
+		points:  pb.NewPointsClient(conn),
+		conn:    conn,
+	}, nil
+}
+
+// Close closes the gRPC connection.
+func (a *CollectionAdmin) Close() error {
+	if a.conn != nil {
+		return a.conn.Close()
+	}
+	return nil
+}
+
+// ResolveAlias returns the collection an alias points to,
+// or an empty string if the alias does not exist.
+func (a *CollectionAdmin) ResolveAlias(ctx context.Context, alias string) (string, error) {
+	resp, err := a.client.ListAliases(ctx, &pb.ListAliasesRequest{})
+	if err != nil {
+		return "", fmt.Errorf("listing aliases: %w", err)
+	}
+
+	for _, desc := range resp.Aliases {
+		if desc.AliasName == alias {
+			return desc.CollectionName, nil
+		}
+	}
+	return "", nil
+}
+
+// CollectionExists reports whether a physical collection exists.
+func (a *CollectionAdmin) CollectionExists(ctx context.Context, name string) (bool, error) {
+	resp, err := a.client.CollectionExists(ctx, &pb.CollectionExistsRequest{
+		CollectionName: name,
+	})
+	if err != nil {
+		return false, fmt.Errorf("checking collection %s: %w", name, err)
+	}
+	return resp.Result.GetExists(), nil
+}
+
+// CreateCollection creates an empty collection with the given vector size.
+func (a *CollectionAdmin) CreateCollection(ctx context.Context, name string, vectorSize uint64) error {
+	return a.repoFor(name).EnsureCollection(ctx, vectorSize)
+}
+
+// DeleteCollection removes a physical collection and any aliases to it.
+func (a *CollectionAdmin) DeleteCollection(ctx context.Context, name string) error {
+	_, err := a.client.Delete(ctx, &pb.DeleteCollection{
+		CollectionName: name,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting collection %s: %w", name, err)
+	}
+	return nil
+}
+
+// SwitchAlias atomically points an alias at a collection,
+// creating the alias if it does not exist.
+func (a *CollectionAdmin) SwitchAlias(ctx context.Context, alias, collection string) error {
+	current, err := a.ResolveAlias(ctx, alias)
+	if err != nil {
+		return err
+	}
+
+	// Qdrant applies all actions in a single request atomically, so readers
+	// never observe the alias missing between the delete and the create.
+	actions := make([]*pb.AliasOperations, 0, 2)
+	if current != "" {
+		actions = append(actions, &pb.AliasOperations{
+			Action: &pb.AliasOperations_DeleteAlias{
+				DeleteAlias: &pb.DeleteAlias{AliasName: alias},
+			},
+		})
+	}
+	actions = append(actions, &pb.AliasOperations{
+		Action: &pb.AliasOperations_CreateAlias{
+			CreateAlias: &pb.CreateAlias{
+				CollectionName: collection,
+				AliasName:      alias,
+			},
+		},
+	})
+
+	_, err = a.client.UpdateAliases(ctx, &pb.ChangeAliases{Actions: actions})
+	if err != nil {
+		return fmt.Errorf("switching alias %s to %s: %w", alias, collection, err)
+	}
+	return nil
+}
+
+// DeleteAlias removes an alias, leaving the collection behind it intact.
+func (a *CollectionAdmin) DeleteAlias(ctx context.Context, alias string) error {
+	_, err := a.client.UpdateAliases(ctx, &pb.ChangeAliases{
+		Actions: []*pb.AliasOperations{{
+			Action: &pb.AliasOperations_DeleteAlias{
+				DeleteAlias: &pb.DeleteAlias{AliasName: alias},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("deleting alias %s: %w", alias, err)
+	}
+	return nil
+}
+
+// ScrollFacts calls fn with successive batches of facts, embeddings included.
+func (a *CollectionAdmin) ScrollFacts(ctx context.Context, collection string, batchSize int, fn func([]entities.Fact) error) error {
+	var offset *pb.PointId
+	for {
+		resp, err := a.points.Scroll(ctx, &pb.ScrollPoints{
+			CollectionName: collection,
+			Limit:          pb.PtrOf(uint32(batchSize)),
+			Offset:         offset,
+			WithPayload: &pb.WithPayloadSelector{
+				SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+			},
+			WithVectors: &pb.WithVectorsSelector{
+				SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: true},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("scrolling %s: %w", collection, err)
+		}
+
+		if len(resp.Result) > 0 {
+			facts, err := retrievedPointsToFacts(resp.Result)
+			if err != nil {
+				return err
+			}
+			if err := fn(facts); err != nil {
+				return err
+			}
+		}
+
+		if resp.NextPageOffset == nil {
+			return nil
+		}
+		offset = resp.NextPageOffset
+	}
+}
+
+// SaveFacts stores facts with their embeddings in the named collection.
+func (a *CollectionAdmin) SaveFacts(ctx context.Context, collection string, facts []entities.Fact) error {
+	return a.repoFor(collection).SaveBatch(ctx, facts)
+}
+
+// repoFor returns a Repository bound to the named collection that shares
+// this admin's connection. The returned Repository must not be closed.
+func (a *CollectionAdmin) repoFor(collection string) *Repository {
+	return &Repository{
+		client:     a.client,
+		points:     a.points,
+		collection: collection,
+		storage:    a.storage,
+	}
+}
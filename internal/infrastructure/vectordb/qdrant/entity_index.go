@@ -0,0 +1,196 @@
+package qdrant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "github.com/qdrant/go-client/qdrant"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// EntityIndexSuffix is appended to a world's collection name to name the
+// collection of its entity embeddings.
+const EntityIndexSuffix = "_entities"
+
+// entityScrollBatch is how many points Fingerprints reads per request.
+const entityScrollBatch = 256
+
+// EntityIndex implements the EntityIndex interface using a Qdrant
+// collection beside the world's facts, one point per entity, with the
+// entity's ID as the point ID.
+type EntityIndex struct {
+	client     pb.CollectionsClient
+	points     pb.PointsClient
+	collection string
+}
+
+var _ ports.EntityIndex = (*EntityIndex)(nil)
+
+// EntityIndex returns the index of the entities of the repository's world.
+// It shares the repository's connection, so it must not outlive it.
+func (r *Repository) EntityIndex() *EntityIndex {
+	return &EntityIndex{
+		client:     r.client,
+		points:     r.points,
+		collection: r.collection + EntityIndexSuffix,
+	}
+}
+
+// Exists reports whether the collection exists.
+func (x *EntityIndex) Exists(ctx context.Context) (bool, error) {
+	resp, err := x.client.CollectionExists(ctx, &pb.CollectionExistsRequest{
+		CollectionName: x.collection,
+	})
+	if err != nil {
+		return false, fmt.Errorf("checking entity index: %w", err)
+	}
+	return resp.Result.GetExists(), nil
+}
+
+// EnsureIndex creates the collection if it doesn't exist.
+func (x *EntityIndex) EnsureIndex(ctx context.Context, vectorSize uint64) error {
+	exists, err := x.Exists(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = x.client.Create(ctx, &pb.CreateCollection{
+		CollectionName: x.collection,
+		VectorsConfig: pb.NewVectorsConfig(&pb.VectorParams{
+			Size:     vectorSize,
+			Distance: pb.Distance_Cosine,
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("creating entity index: %w", err)
+	}
+	return nil
+}
+
+// DeleteIndex removes the collection. An index never created is already
+// deleted.
+func (x *EntityIndex) DeleteIndex(ctx context.Context) error {
+	_, err := x.client.Delete(ctx, &pb.DeleteCollection{
+		CollectionName: x.collection,
+	})
+	if err != nil && !errors.Is(err, entities.ErrNotFound) {
+		return fmt.Errorf("deleting entity index: %w", err)
+	}
+	return nil
+}
+
+// SaveEntities stores entities' embeddings, replacing earlier ones.
+func (x *EntityIndex) SaveEntities(ctx context.Context, list []ports.IndexedEntity) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	points := make([]*pb.PointStruct, len(list))
+	for i, entity := range list {
+		points[i] = &pb.PointStruct{
+			Id:      pb.NewIDUUID(entity.EntityID),
+			Vectors: pb.NewVectorsDense(entity.Embedding),
+			Payload: map[string]*pb.Value{
+				"name":        {Kind: &pb.Value_StringValue{StringValue: entity.Name}},
+				"fingerprint": {Kind: &pb.Value_StringValue{StringValue: entity.Fingerprint}},
+			},
+		}
+	}
+
+	_, err := x.points.Upsert(ctx, &pb.UpsertPoints{
+		CollectionName: x.collection,
+		Points:         points,
+	})
+	if err != nil {
+		return fmt.Errorf("saving entity embeddings: %w", err)
+	}
+	return nil
+}
+
+// DeleteEntities removes the embeddings of entities by ID.
+func (x *EntityIndex) DeleteEntities(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	pointIDs := make([]*pb.PointId, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = pb.NewIDUUID(id)
+	}
+
+	_, err := x.points.Delete(ctx, &pb.DeletePoints{
+		CollectionName: x.collection,
+		Points: &pb.PointsSelector{
+			PointsSelectorOneOf: &pb.PointsSelector_Points{
+				Points: &pb.PointsIdsList{Ids: pointIDs},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("deleting entity embeddings: %w", err)
+	}
+	return nil
+}
+
+// Fingerprints returns the fingerprint of every indexed entity, by ID.
+func (x *EntityIndex) Fingerprints(ctx context.Context) (map[string]string, error) {
+	fingerprints := make(map[string]string)
+
+	var offset *pb.PointId
+	for {
+		resp, err := x.points.Scroll(ctx, &pb.ScrollPoints{
+			CollectionName: x.collection,
+			Limit:          pb.PtrOf(uint32(entityScrollBatch)),
+			Offset:         offset,
+			WithPayload: &pb.WithPayloadSelector{
+				SelectorOptions: &pb.WithPayloadSelector_Include{
+					Include: &pb.PayloadIncludeSelector{Fields: []string{"fingerprint"}},
+				},
+			},
+			WithVectors: &pb.WithVectorsSelector{
+				SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing entity embeddings: %w", err)
+		}
+
+		for _, point := range resp.Result {
+			fingerprints[point.Id.GetUuid()] = getStringValue(point.Payload, "fingerprint")
+		}
+
+		if resp.NextPageOffset == nil {
+			return fingerprints, nil
+		}
+		offset = resp.NextPageOffset
+	}
+}
+
+// SearchEntities returns the entities whose embeddings are most similar to
+// embedding, closest first.
+func (x *EntityIndex) SearchEntities(ctx context.Context, embedding []float32, limit int) ([]ports.EntityMatch, error) {
+	resp, err := x.points.Search(ctx, &pb.SearchPoints{
+		CollectionName: x.collection,
+		Vector:         embedding,
+		Limit:          uint64(limit),
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching entities: %w", err)
+	}
+
+	matches := make([]ports.EntityMatch, len(resp.Result))
+	for i, point := range resp.Result {
+		matches[i] = ports.EntityMatch{
+			EntityID: point.Id.GetUuid(),
+			Name:     getStringValue(point.Payload, "name"),
+			Score:    point.Score,
+		}
+	}
+	return matches, nil
+}
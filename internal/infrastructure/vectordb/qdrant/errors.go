@@ -0,0 +1,31 @@
+package qdrant
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// classifyErrors is a gRPC interceptor that marks the errors of every
+// Qdrant call with an entities error kind.
+func classifyErrors(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return classifyError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// classifyError marks err as entities.ErrBackendUnavailable if Qdrant could
+// not be reached or was overloaded, or entities.ErrNotFound if the
+// collection or point does not exist.
+func classifyError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return entities.WithKind(entities.ErrBackendUnavailable, err)
+	case codes.NotFound:
+		return entities.WithKind(entities.ErrNotFound, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,38 @@
+package qdrant
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), entities.ErrBackendUnavailable},
+		{"deadline", status.Error(codes.DeadlineExceeded, "deadline exceeded"), entities.ErrBackendUnavailable},
+		{"not found", status.Error(codes.NotFound, "collection missing"), entities.ErrNotFound},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad vector"), nil},
+		{"not a status", errors.New("boom"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyError(tt.err)
+			assert.Equal(t, tt.want, entities.ErrorKind(err))
+			if tt.err != nil {
+				assert.Equal(t, tt.err.Error(), err.Error())
+				assert.Equal(t, status.Code(tt.err), status.Code(err), "the gRPC status is kept")
+			}
+		})
+	}
+}
@@ -4,29 +4,45 @@ package qdrant
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	pb "github.com/qdrant/go-client/qdrant"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	"github.com/ersonp/lore-core/internal/infrastructure/config"
 )
 
+// timestampLayout is the format used for created_at/updated_at payload values.
+const timestampLayout = "2006-01-02T15:04:05Z07:00"
+
 // Repository implements the VectorDB interface using Qdrant.
 type Repository struct {
 	client     pb.CollectionsClient
 	points     pb.PointsClient
+	snapshots  pb.SnapshotsClient
 	collection string
+	storage    config.QdrantConfig
 	conn       *grpc.ClientConn
+
+	// Vector layout of the collection, detected on first use.
+	layoutMu    sync.Mutex
+	layoutKnown bool
+	vectors     vectorLayout
 }
 
-// NewRepository creates a new Qdrant repository.
-func NewRepository(cfg config.QdrantConfig) (*Repository, error) {
+// NewRepository creates a new Qdrant repository. opts are added to the
+// connection's dial options, such as to trace its calls.
+func NewRepository(cfg *config.QdrantConfig, opts ...grpc.DialOption) (*Repository, error) {
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := dial(addr, opts)
 	if err != nil {
 		return nil, fmt.Errorf("connecting to qdrant: %w", err)
 	}
@@ -34,11 +50,22 @@ func NewRepository(cfg config.QdrantConfig) (*Repository, error) {
 	return &Repository{
 		client:     pb.NewCollectionsClient(conn),
 		points:     pb.NewPointsClient(conn),
+		snapshots:  pb.NewSnapshotsClient(conn),
 		collection: cfg.Collection,
+		storage:    *cfg,
 		conn:       conn,
 	}, nil
 }
 
+// dial connects to Qdrant at addr, classifying the errors of every call.
+func dial(addr string, opts []grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(classifyErrors),
+	}, opts...)
+	return grpc.NewClient(addr, opts...)
+}
+
 // Close closes the gRPC connection.
 func (r *Repository) Close() error {
 	if r.conn != nil {
@@ -47,6 +74,14 @@ func (r *Repository) Close() error {
 	return nil
 }
 
+// Ping checks that Qdrant is reachable and healthy.
+func (r *Repository) Ping(ctx context.Context) error {
+	if _, err := pb.NewQdrantClient(r.conn).HealthCheck(ctx, &pb.HealthCheckRequest{}); err != nil {
+		return fmt.Errorf("checking qdrant health: %w", err)
+	}
+	return nil
+}
+
 // EnsureCollection creates the collection if it doesn't exist.
 func (r *Repository) EnsureCollection(ctx context.Context, vectorSize uint64) error {
 	_, err := r.client.Get(ctx, &pb.GetCollectionInfoRequest{
@@ -56,21 +91,15 @@ func (r *Repository) EnsureCollection(ctx context.Context, vectorSize uint64) er
 		return nil
 	}
 
-	_, err = r.client.Create(ctx, &pb.CreateCollection{
-		CollectionName: r.collection,
-		VectorsConfig: &pb.VectorsConfig{
-			Config: &pb.VectorsConfig_Params{
-				Params: &pb.VectorParams{
-					Size:     vectorSize,
-					Distance: pb.Distance_Cosine,
-				},
-			},
-		},
-	})
+	_, err = r.client.Create(ctx, createCollectionRequest(r.collection, vectorSize, &r.storage))
 	if err != nil {
 		return fmt.Errorf("creating collection: %w", err)
 	}
+	if err := r.ensureKeywordIndex(ctx, "claim_key"); err != nil {
+		return err
+	}
 
+	r.setLayout(currentLayout)
 	return nil
 }
 
@@ -81,6 +110,11 @@ func (r *Repository) Save(ctx context.Context, fact *entities.Fact) error {
 
 // SaveBatch stores multiple facts.
 func (r *Repository) SaveBatch(ctx context.Context, facts []entities.Fact) error {
+	layout, err := r.layout(ctx)
+	if err != nil {
+		return err
+	}
+
 	points := make([]*pb.PointStruct, 0, len(facts))
 
 	for i := range facts {
@@ -95,13 +129,7 @@ func (r *Repository) SaveBatch(ctx context.Context, facts []entities.Fact) error
 					Uuid: pointID,
 				},
 			},
-			Vectors: &pb.Vectors{
-				VectorsOptions: &pb.Vectors_Vector{
-					Vector: &pb.Vector{
-						Data: facts[i].Embedding,
-					},
-				},
-			},
+			Vectors: pointVectors(&facts[i], layout),
 			Payload: map[string]*pb.Value{
 				"type":        {Kind: &pb.Value_StringValue{StringValue: string(facts[i].Type)}},
 				"subject":     {Kind: &pb.Value_StringValue{StringValue: facts[i].Subject}},
@@ -110,15 +138,30 @@ func (r *Repository) SaveBatch(ctx context.Context, facts []entities.Fact) error
 				"context":     {Kind: &pb.Value_StringValue{StringValue: facts[i].Context}},
 				"source_file": {Kind: &pb.Value_StringValue{StringValue: facts[i].SourceFile}},
 				"source_line": {Kind: &pb.Value_IntegerValue{IntegerValue: int64(facts[i].SourceLine)}},
+				"source_page": {Kind: &pb.Value_IntegerValue{IntegerValue: int64(facts[i].SourcePage)}},
 				"confidence":  {Kind: &pb.Value_DoubleValue{DoubleValue: facts[i].Confidence}},
-				"created_at":  {Kind: &pb.Value_StringValue{StringValue: facts[i].CreatedAt.Format("2006-01-02T15:04:05Z07:00")}},
-				"updated_at":  {Kind: &pb.Value_StringValue{StringValue: facts[i].UpdatedAt.Format("2006-01-02T15:04:05Z07:00")}},
+				"status":      {Kind: &pb.Value_StringValue{StringValue: string(facts[i].Status)}},
+				"created_at":  {Kind: &pb.Value_StringValue{StringValue: facts[i].CreatedAt.Format(timestampLayout)}},
+				"updated_at":  {Kind: &pb.Value_StringValue{StringValue: facts[i].UpdatedAt.Format(timestampLayout)}},
+
+				"corroboration": {Kind: &pb.Value_IntegerValue{IntegerValue: int64(facts[i].Corroboration)}},
+				"asserted_by":   {Kind: &pb.Value_StringValue{StringValue: facts[i].AssertedBy}},
+				"claim":         {Kind: &pb.Value_BoolValue{BoolValue: facts[i].Claim}},
+				"claim_key":     {Kind: &pb.Value_StringValue{StringValue: facts[i].ClaimKey()}},
+				"archived":      {Kind: &pb.Value_BoolValue{BoolValue: facts[i].Archived}},
+				"draft":         {Kind: &pb.Value_BoolValue{BoolValue: facts[i].Draft}},
 			},
 		}
+		addObjectValue(point.Payload, &facts[i])
+		addKnownBy(point.Payload, &facts[i])
+		addMetadata(point.Payload, &facts[i])
+		if !facts[i].ExpiresAt.IsZero() {
+			point.Payload["expires_at"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: facts[i].ExpiresAt.Format(timestampLayout)}}
+		}
 		points = append(points, point)
 	}
 
-	_, err := r.points.Upsert(ctx, &pb.UpsertPoints{
+	_, err = r.points.Upsert(ctx, &pb.UpsertPoints{
 		CollectionName: r.collection,
 		Points:         points,
 	})
@@ -148,7 +191,7 @@ func (r *Repository) FindByID(ctx context.Context, id string) (entities.Fact, er
 	}
 
 	if len(resp.Result) == 0 {
-		return entities.Fact{}, fmt.Errorf("fact not found: %s", id)
+		return entities.Fact{}, entities.Errorf(entities.ErrNotFound, "fact not found: %s", id)
 	}
 
 	return pointToFact(resp.Result[0])
@@ -219,17 +262,62 @@ func (r *Repository) FindByIDs(ctx context.Context, ids []string) ([]entities.Fa
 
 // Search performs a semantic search and returns similar facts.
 func (r *Repository) Search(ctx context.Context, embedding []float32, limit int) ([]entities.Fact, error) {
-	resp, err := r.points.Search(ctx, &pb.SearchPoints{
-		CollectionName: r.collection,
-		Vector:         embedding,
-		Limit:          uint64(limit),
-		WithPayload: &pb.WithPayloadSelector{
-			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
-		},
-		WithVectors: &pb.WithVectorsSelector{
-			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: true},
+	return r.SearchVector(ctx, ports.VectorContext, embedding, "", limit)
+}
+
+// SearchByType performs a semantic search filtered by fact type.
+func (r *Repository) SearchByType(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	return r.SearchVector(ctx, ports.VectorContext, embedding, factType, limit)
+}
+
+// SearchBySubject performs a semantic search filtered to facts whose
+// subject exactly matches subject.
+func (r *Repository) SearchBySubject(ctx context.Context, embedding []float32, subject string, limit int) ([]entities.Fact, error) {
+	layout, err := r.layout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := newSearchRequest(r.collection, "", limit)
+	req.Vector = embedding
+	if layout.named {
+		req.VectorName = pb.PtrOf(string(ports.VectorContext))
+	}
+	req.Filter.Must = append(req.Filter.Must, &pb.Condition{
+		ConditionOneOf: &pb.Condition_Field{
+			Field: &pb.FieldCondition{
+				Key: "subject",
+				Match: &pb.Match{
+					MatchValue: &pb.Match_Keyword{Keyword: subject},
+				},
+			},
 		},
 	})
+
+	resp, err := r.points.Search(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("searching points by subject: %w", err)
+	}
+
+	return scoredPointsToFacts(resp.Result)
+}
+
+// SearchVector performs a semantic search against a specific stored
+// embedding, optionally filtered by fact type (empty = all types).
+// Collections without named vectors search their single embedding.
+func (r *Repository) SearchVector(ctx context.Context, vector ports.VectorName, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	layout, err := r.layout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := newSearchRequest(r.collection, factType, limit)
+	req.Vector = embedding
+	if layout.named {
+		req.VectorName = pb.PtrOf(string(vector))
+	}
+
+	resp, err := r.points.Search(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("searching points: %w", err)
 	}
@@ -237,27 +325,42 @@ func (r *Repository) Search(ctx context.Context, embedding []float32, limit int)
 	return scoredPointsToFacts(resp.Result)
 }
 
-// SearchByType performs a semantic search filtered by fact type.
-func (r *Repository) SearchByType(ctx context.Context, embedding []float32, factType entities.FactType, limit int) ([]entities.Fact, error) {
-	resp, err := r.points.Search(ctx, &pb.SearchPoints{
-		CollectionName: r.collection,
-		Vector:         embedding,
+// SearchKeywords performs a BM25-style keyword search over fact text,
+// optionally filtered by fact type (empty = all types). Collections
+// created without a keyword index return no results.
+func (r *Repository) SearchKeywords(ctx context.Context, query string, factType entities.FactType, limit int) ([]entities.Fact, error) {
+	layout, err := r.layout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	indices, values := encodeQuery(query)
+	if !layout.sparse || len(indices) == 0 {
+		return []entities.Fact{}, nil
+	}
+
+	req := newSearchRequest(r.collection, factType, limit)
+	req.Vector = values
+	req.SparseIndices = &pb.SparseIndices{Data: indices}
+	req.VectorName = pb.PtrOf(string(ports.VectorKeywords))
+
+	resp, err := r.points.Search(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("searching keywords: %w", err)
+	}
+
+	return scoredPointsToFacts(resp.Result)
+}
+
+// newSearchRequest builds a search request returning payloads and vectors,
+// optionally filtered by fact type. Facts pending review are excluded.
+// Callers set the query vector.
+func newSearchRequest(collection string, factType entities.FactType, limit int) *pb.SearchPoints {
+	req := &pb.SearchPoints{
+		CollectionName: collection,
 		Limit:          uint64(limit),
 		Filter: &pb.Filter{
-			Must: []*pb.Condition{
-				{
-					ConditionOneOf: &pb.Condition_Field{
-						Field: &pb.FieldCondition{
-							Key: "type",
-							Match: &pb.Match{
-								MatchValue: &pb.Match_Keyword{
-									Keyword: string(factType),
-								},
-							},
-						},
-					},
-				},
-			},
+			MustNot: []*pb.Condition{statusCondition(entities.FactStatusPending)},
 		},
 		WithPayload: &pb.WithPayloadSelector{
 			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
@@ -265,12 +368,40 @@ func (r *Repository) SearchByType(ctx context.Context, embedding []float32, fact
 		WithVectors: &pb.WithVectorsSelector{
 			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: true},
 		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("searching points by type: %w", err)
 	}
+	if factType != "" {
+		req.Filter.Must = []*pb.Condition{
+			{
+				ConditionOneOf: &pb.Condition_Field{
+					Field: &pb.FieldCondition{
+						Key: "type",
+						Match: &pb.Match{
+							MatchValue: &pb.Match_Keyword{
+								Keyword: string(factType),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	return req
+}
 
-	return scoredPointsToFacts(resp.Result)
+// statusCondition matches facts with the given review status.
+func statusCondition(status entities.FactStatus) *pb.Condition {
+	return &pb.Condition{
+		ConditionOneOf: &pb.Condition_Field{
+			Field: &pb.FieldCondition{
+				Key: "status",
+				Match: &pb.Match{
+					MatchValue: &pb.Match_Keyword{
+						Keyword: string(status),
+					},
+				},
+			},
+		},
+	}
 }
 
 // Delete removes a fact by its ID.
@@ -321,6 +452,78 @@ func (r *Repository) List(ctx context.Context, limit int, offset uint64) ([]enti
 	return retrievedPointsToFacts(resp.Result)
 }
 
+// ListPage returns a page of facts in point ID order and the cursor of the
+// next page. The cursor is the ID of the next page's first point, as
+// returned by Qdrant's scroll.
+func (r *Repository) ListPage(ctx context.Context, opts ports.FactPageOptions) ([]entities.Fact, string, error) {
+	var must []*pb.Condition
+	if opts.Type != "" {
+		must = append(must, keywordCondition("type", string(opts.Type)))
+	}
+	if opts.SourceFile != "" {
+		must = append(must, keywordCondition("source_file", opts.SourceFile))
+	}
+	if opts.Drafts {
+		must = append(must, &pb.Condition{
+			ConditionOneOf: &pb.Condition_Field{
+				Field: &pb.FieldCondition{
+					Key: "draft",
+					Match: &pb.Match{
+						MatchValue: &pb.Match_Boolean{Boolean: true},
+					},
+				},
+			},
+		})
+	}
+
+	req := &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(opts.Limit)),
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	}
+	if len(must) > 0 {
+		req.Filter = &pb.Filter{Must: must}
+	}
+	if opts.Cursor != "" {
+		if _, err := uuid.Parse(opts.Cursor); err != nil {
+			return nil, "", entities.Errorf(entities.ErrValidation, "invalid cursor %q", opts.Cursor)
+		}
+		req.Offset = &pb.PointId{PointIdOptions: &pb.PointId_Uuid{Uuid: opts.Cursor}}
+	}
+
+	resp, err := r.points.Scroll(ctx, req)
+	if err != nil {
+		return nil, "", fmt.Errorf("scrolling points: %w", err)
+	}
+
+	facts, err := retrievedPointsToFacts(resp.Result)
+	if err != nil {
+		return nil, "", err
+	}
+	return facts, resp.NextPageOffset.GetUuid(), nil
+}
+
+// keywordCondition matches points whose payload field equals value.
+func keywordCondition(field, value string) *pb.Condition {
+	return &pb.Condition{
+		ConditionOneOf: &pb.Condition_Field{
+			Field: &pb.FieldCondition{
+				Key: field,
+				Match: &pb.Match{
+					MatchValue: &pb.Match_Keyword{
+						Keyword: value,
+					},
+				},
+			},
+		},
+	}
+}
+
 // ListByType returns facts filtered by type.
 func (r *Repository) ListByType(ctx context.Context, factType entities.FactType, limit int) ([]entities.Fact, error) {
 	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
@@ -356,6 +559,210 @@ func (r *Repository) ListByType(ctx context.Context, factType entities.FactType,
 	return retrievedPointsToFacts(resp.Result)
 }
 
+// ListFiltered returns facts matching the given type and time range,
+// optionally ordered newest first by creation or update time.
+func (r *Repository) ListFiltered(ctx context.Context, opts ports.FactListOptions) ([]entities.Fact, error) {
+	timeField := opts.TimeField()
+
+	conditions := make([]*pb.Condition, 0, 2)
+	if opts.Type != "" {
+		conditions = append(conditions, &pb.Condition{
+			ConditionOneOf: &pb.Condition_Field{
+				Field: &pb.FieldCondition{
+					Key: "type",
+					Match: &pb.Match{
+						MatchValue: &pb.Match_Keyword{
+							Keyword: string(opts.Type),
+						},
+					},
+				},
+			},
+		})
+	}
+	if !opts.Since.IsZero() || !opts.Until.IsZero() {
+		dtRange := &pb.DatetimeRange{}
+		if !opts.Since.IsZero() {
+			dtRange.Gte = timestamppb.New(opts.Since)
+		}
+		if !opts.Until.IsZero() {
+			dtRange.Lte = timestamppb.New(opts.Until)
+		}
+		conditions = append(conditions, &pb.Condition{
+			ConditionOneOf: &pb.Condition_Field{
+				Field: &pb.FieldCondition{
+					Key:           timeField,
+					DatetimeRange: dtRange,
+				},
+			},
+		})
+	}
+
+	req := &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(opts.Limit)),
+		Filter:         &pb.Filter{Must: conditions},
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	}
+
+	if opts.Sort != "" {
+		// Qdrant can only order by fields that have a range-capable index.
+		if err := r.ensureDatetimeIndex(ctx, timeField); err != nil {
+			return nil, err
+		}
+		req.OrderBy = &pb.OrderBy{
+			Key:       timeField,
+			Direction: pb.Direction_Desc.Enum(),
+		}
+	}
+
+	resp, err := r.points.Scroll(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("scrolling points with filters: %w", err)
+	}
+
+	return retrievedPointsToFacts(resp.Result)
+}
+
+// ensureDatetimeIndex creates a datetime payload index on the given field.
+// Creating an index that already exists is a no-op in Qdrant.
+func (r *Repository) ensureDatetimeIndex(ctx context.Context, field string) error {
+	_, err := r.points.CreateFieldIndex(ctx, &pb.CreateFieldIndexCollection{
+		CollectionName: r.collection,
+		Wait:           pb.PtrOf(true),
+		FieldName:      field,
+		FieldType:      pb.FieldType_FieldTypeDatetime.Enum(),
+	})
+	if err != nil {
+		return fmt.Errorf("creating %s index: %w", field, err)
+	}
+	return nil
+}
+
+// ensureKeywordIndex creates a keyword payload index on the given field,
+// for exact-match filters. Creating an index that already exists is a
+// no-op in Qdrant.
+func (r *Repository) ensureKeywordIndex(ctx context.Context, field string) error {
+	_, err := r.points.CreateFieldIndex(ctx, &pb.CreateFieldIndexCollection{
+		CollectionName: r.collection,
+		Wait:           pb.PtrOf(true),
+		FieldName:      field,
+		FieldType:      pb.FieldType_FieldTypeKeyword.Enum(),
+	})
+	if err != nil {
+		return fmt.Errorf("creating %s index: %w", field, err)
+	}
+	return nil
+}
+
+// ListPending returns facts awaiting review.
+func (r *Repository) ListPending(ctx context.Context, limit int) ([]entities.Fact, error) {
+	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(limit)),
+		Filter: &pb.Filter{
+			Must: []*pb.Condition{statusCondition(entities.FactStatusPending)},
+		},
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scrolling pending points: %w", err)
+	}
+
+	return retrievedPointsToFacts(resp.Result)
+}
+
+// ListBySubject returns facts whose subject exactly matches one of the
+// given names. Facts pending review are skipped.
+func (r *Repository) ListBySubject(ctx context.Context, subjects []string, limit int) ([]entities.Fact, error) {
+	if len(subjects) == 0 {
+		return []entities.Fact{}, nil
+	}
+
+	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(limit)),
+		Filter: &pb.Filter{
+			Must: []*pb.Condition{
+				{
+					ConditionOneOf: &pb.Condition_Field{
+						Field: &pb.FieldCondition{
+							Key: "subject",
+							Match: &pb.Match{
+								MatchValue: &pb.Match_Keywords{
+									Keywords: &pb.RepeatedStrings{Strings: subjects},
+								},
+							},
+						},
+					},
+				},
+			},
+			MustNot: []*pb.Condition{statusCondition(entities.FactStatusPending)},
+		},
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scrolling points by subject: %w", err)
+	}
+
+	return retrievedPointsToFacts(resp.Result)
+}
+
+// ListByClaimKeys returns facts whose claim key is one of keys. Facts
+// pending review, and facts saved before claim keys were, are skipped.
+func (r *Repository) ListByClaimKeys(ctx context.Context, keys []string, limit int) ([]entities.Fact, error) {
+	if len(keys) == 0 {
+		return []entities.Fact{}, nil
+	}
+
+	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(limit)),
+		Filter: &pb.Filter{
+			Must: []*pb.Condition{
+				{
+					ConditionOneOf: &pb.Condition_Field{
+						Field: &pb.FieldCondition{
+							Key: "claim_key",
+							Match: &pb.Match{
+								MatchValue: &pb.Match_Keywords{
+									Keywords: &pb.RepeatedStrings{Strings: keys},
+								},
+							},
+						},
+					},
+				},
+			},
+			MustNot: []*pb.Condition{statusCondition(entities.FactStatusPending)},
+		},
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scrolling points by claim key: %w", err)
+	}
+
+	return retrievedPointsToFacts(resp.Result)
+}
+
 // ListBySource returns facts filtered by source file.
 func (r *Repository) ListBySource(ctx context.Context, sourceFile string, limit int) ([]entities.Fact, error) {
 	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
@@ -391,6 +798,48 @@ func (r *Repository) ListBySource(ctx context.Context, sourceFile string, limit
 	return retrievedPointsToFacts(resp.Result)
 }
 
+// ListByEntities retrieves facts whose subject or object exactly matches
+// one of the given entity names.
+func (r *Repository) ListByEntities(ctx context.Context, names []string, limit int) ([]entities.Fact, error) {
+	if len(names) == 0 {
+		return []entities.Fact{}, nil
+	}
+
+	matchNames := func(key string) *pb.Condition {
+		return &pb.Condition{
+			ConditionOneOf: &pb.Condition_Field{
+				Field: &pb.FieldCondition{
+					Key: key,
+					Match: &pb.Match{
+						MatchValue: &pb.Match_Keywords{
+							Keywords: &pb.RepeatedStrings{Strings: names},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	resp, err := r.points.Scroll(ctx, &pb.ScrollPoints{
+		CollectionName: r.collection,
+		Limit:          pb.PtrOf(uint32(limit)),
+		Filter: &pb.Filter{
+			Should: []*pb.Condition{matchNames("subject"), matchNames("object")},
+		},
+		WithPayload: &pb.WithPayloadSelector{
+			SelectorOptions: &pb.WithPayloadSelector_Enable{Enable: true},
+		},
+		WithVectors: &pb.WithVectorsSelector{
+			SelectorOptions: &pb.WithVectorsSelector_Enable{Enable: false},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scrolling points by entities: %w", err)
+	}
+
+	return retrievedPointsToFacts(resp.Result)
+}
+
 // DeleteBySource removes all facts from a source file.
 func (r *Repository) DeleteBySource(ctx context.Context, sourceFile string) error {
 	_, err := r.points.Delete(ctx, &pb.DeletePoints{
@@ -423,6 +872,42 @@ func (r *Repository) DeleteBySource(ctx context.Context, sourceFile string) erro
 	return nil
 }
 
+// SetSourceArchived marks or unmarks every fact from a source file as
+// archived, updating their payloads in place.
+func (r *Repository) SetSourceArchived(ctx context.Context, sourceFile string, archived bool) error {
+	_, err := r.points.SetPayload(ctx, &pb.SetPayloadPoints{
+		CollectionName: r.collection,
+		Payload: map[string]*pb.Value{
+			"archived": {Kind: &pb.Value_BoolValue{BoolValue: archived}},
+		},
+		PointsSelector: &pb.PointsSelector{
+			PointsSelectorOneOf: &pb.PointsSelector_Filter{
+				Filter: &pb.Filter{
+					Must: []*pb.Condition{
+						{
+							ConditionOneOf: &pb.Condition_Field{
+								Field: &pb.FieldCondition{
+									Key: "source_file",
+									Match: &pb.Match{
+										MatchValue: &pb.Match_Keyword{
+											Keyword: sourceFile,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("setting archived on points by source: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteAll removes all facts.
 func (r *Repository) DeleteAll(ctx context.Context) error {
 	_, err := r.points.Delete(ctx, &pb.DeletePoints{
@@ -456,6 +941,65 @@ func (r *Repository) Count(ctx context.Context) (uint64, error) {
 	return *resp.Result.PointsCount, nil
 }
 
+// CollectionInfo describes the state of a collection as reported by Qdrant.
+type CollectionInfo struct {
+	Status         string
+	Points         uint64
+	IndexedVectors uint64
+	Segments       uint64
+}
+
+// Info returns the collection's status, point count, and segment layout.
+func (r *Repository) Info(ctx context.Context) (CollectionInfo, error) {
+	resp, err := r.client.Get(ctx, &pb.GetCollectionInfoRequest{
+		CollectionName: r.collection,
+	})
+	if err != nil {
+		return CollectionInfo{}, fmt.Errorf("getting collection info: %w", err)
+	}
+
+	return CollectionInfo{
+		Status:         strings.ToLower(resp.Result.GetStatus().String()),
+		Points:         resp.Result.GetPointsCount(),
+		IndexedVectors: resp.Result.GetIndexedVectorsCount(),
+		Segments:       resp.Result.GetSegmentsCount(),
+	}, nil
+}
+
+// CountBySubject returns the number of facts whose subject exactly
+// matches one of the given names.
+func (r *Repository) CountBySubject(ctx context.Context, subjects []string) (uint64, error) {
+	if len(subjects) == 0 {
+		return 0, nil
+	}
+
+	resp, err := r.points.Count(ctx, &pb.CountPoints{
+		CollectionName: r.collection,
+		Filter: &pb.Filter{
+			Must: []*pb.Condition{
+				{
+					ConditionOneOf: &pb.Condition_Field{
+						Field: &pb.FieldCondition{
+							Key: "subject",
+							Match: &pb.Match{
+								MatchValue: &pb.Match_Keywords{
+									Keywords: &pb.RepeatedStrings{Strings: subjects},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Exact: pb.PtrOf(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting points by subject: %w", err)
+	}
+
+	return resp.Result.GetCount(), nil
+}
+
 // DeleteCollection removes the entire collection from Qdrant.
 func (r *Repository) DeleteCollection(ctx context.Context) error {
 	_, err := r.client.Delete(ctx, &pb.DeleteCollection{
@@ -491,14 +1035,7 @@ func pointToFact(point *pb.RetrievedPoint) (entities.Fact, error) {
 	}
 
 	payload := point.Payload
-	var embedding []float32
-	if point.Vectors != nil {
-		if vec := point.Vectors.GetVector(); vec != nil {
-			if dense := vec.GetDense(); dense != nil {
-				embedding = dense.Data
-			}
-		}
-	}
+	embedding, textEmbedding := outputEmbeddings(point.Vectors)
 
 	fact := entities.Fact{
 		ID:         id,
@@ -509,8 +1046,24 @@ func pointToFact(point *pb.RetrievedPoint) (entities.Fact, error) {
 		Context:    getStringValue(payload, "context"),
 		SourceFile: getStringValue(payload, "source_file"),
 		SourceLine: int(getIntValue(payload, "source_line")),
+		SourcePage: int(getIntValue(payload, "source_page")),
 		Confidence: getDoubleValue(payload, "confidence"),
+		Status:     entities.FactStatus(getStringValue(payload, "status")),
 		Embedding:  embedding,
+		CreatedAt:  getTimeValue(payload, "created_at"),
+		UpdatedAt:  getTimeValue(payload, "updated_at"),
+
+		TextEmbedding: textEmbedding,
+		Corroboration: int(getIntValue(payload, "corroboration")),
+		ObjectType:    entities.ObjectType(getStringValue(payload, "object_type")),
+		KnownBy:       getStringListValue(payload, "known_by"),
+		AssertedBy:    getStringValue(payload, "asserted_by"),
+		Claim:         getBoolValue(payload, "claim"),
+		Metadata:      getStringMapValue(payload, "metadata"),
+		Tags:          getStringListValue(payload, "tags"),
+		Archived:      getBoolValue(payload, "archived"),
+		Draft:         getBoolValue(payload, "draft"),
+		ExpiresAt:     getTimeValue(payload, "expires_at"),
 	}
 
 	return fact, nil
@@ -527,14 +1080,7 @@ func scoredPointsToFacts(points []*pb.ScoredPoint) ([]entities.Fact, error) {
 		}
 
 		payload := point.Payload
-		var embedding []float32
-		if point.Vectors != nil {
-			if vec := point.Vectors.GetVector(); vec != nil {
-				if dense := vec.GetDense(); dense != nil {
-					embedding = dense.Data
-				}
-			}
-		}
+		embedding, textEmbedding := outputEmbeddings(point.Vectors)
 
 		fact := entities.Fact{
 			ID:         id,
@@ -545,8 +1091,24 @@ func scoredPointsToFacts(points []*pb.ScoredPoint) ([]entities.Fact, error) {
 			Context:    getStringValue(payload, "context"),
 			SourceFile: getStringValue(payload, "source_file"),
 			SourceLine: int(getIntValue(payload, "source_line")),
+			SourcePage: int(getIntValue(payload, "source_page")),
 			Confidence: getDoubleValue(payload, "confidence"),
+			Status:     entities.FactStatus(getStringValue(payload, "status")),
 			Embedding:  embedding,
+			CreatedAt:  getTimeValue(payload, "created_at"),
+			UpdatedAt:  getTimeValue(payload, "updated_at"),
+
+			TextEmbedding: textEmbedding,
+			Corroboration: int(getIntValue(payload, "corroboration")),
+			ObjectType:    entities.ObjectType(getStringValue(payload, "object_type")),
+			KnownBy:       getStringListValue(payload, "known_by"),
+			AssertedBy:    getStringValue(payload, "asserted_by"),
+			Claim:         getBoolValue(payload, "claim"),
+			Metadata:      getStringMapValue(payload, "metadata"),
+			Tags:          getStringListValue(payload, "tags"),
+			Archived:      getBoolValue(payload, "archived"),
+			Draft:         getBoolValue(payload, "draft"),
+			ExpiresAt:     getTimeValue(payload, "expires_at"),
 		}
 		facts = append(facts, fact)
 	}
@@ -554,6 +1116,65 @@ func scoredPointsToFacts(points []*pb.ScoredPoint) ([]entities.Fact, error) {
 	return facts, nil
 }
 
+// addObjectValue stores the fact's object type and, for typed objects, its
+// value as structured fields, so numbers, dates, and booleans can be
+// filtered on without parsing the object text. Measurements also store
+// their dimension and amount in its base unit, so "6 feet" and "183 cm"
+// compare as equal.
+func addObjectValue(payload map[string]*pb.Value, fact *entities.Fact) {
+	if fact.ObjectType == "" {
+		return
+	}
+	payload["object_type"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: string(fact.ObjectType)}}
+
+	value := fact.ObjectValue()
+	switch value.Type {
+	case entities.ObjectTypeNumber:
+		payload["object_number"] = &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: value.Number}}
+		payload["object_unit"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: value.Unit}}
+		if dimension, amount, ok := value.Measure(); ok {
+			payload["object_dimension"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: string(dimension)}}
+			payload["object_measure"] = &pb.Value{Kind: &pb.Value_DoubleValue{DoubleValue: amount}}
+		}
+	case entities.ObjectTypeDate:
+		payload["object_date"] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: value.Date.Format(timestampLayout)}}
+	case entities.ObjectTypeBoolean:
+		payload["object_bool"] = &pb.Value{Kind: &pb.Value_BoolValue{BoolValue: value.Bool}}
+	}
+}
+
+// addKnownBy stores the IDs of the entities that know the fact. Common
+// knowledge stores nothing.
+func addKnownBy(payload map[string]*pb.Value, fact *entities.Fact) {
+	if len(fact.KnownBy) == 0 {
+		return
+	}
+	values := make([]*pb.Value, len(fact.KnownBy))
+	for i, id := range fact.KnownBy {
+		values[i] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: id}}
+	}
+	payload["known_by"] = &pb.Value{Kind: &pb.Value_ListValue{ListValue: &pb.ListValue{Values: values}}}
+}
+
+// addMetadata stores the metadata and tags the fact inherited from its
+// source. A fact without any stores nothing.
+func addMetadata(payload map[string]*pb.Value, fact *entities.Fact) {
+	if len(fact.Metadata) > 0 {
+		fields := make(map[string]*pb.Value, len(fact.Metadata))
+		for key, value := range fact.Metadata {
+			fields[key] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: value}}
+		}
+		payload["metadata"] = &pb.Value{Kind: &pb.Value_StructValue{StructValue: &pb.Struct{Fields: fields}}}
+	}
+	if len(fact.Tags) > 0 {
+		values := make([]*pb.Value, len(fact.Tags))
+		for i, tag := range fact.Tags {
+			values[i] = &pb.Value{Kind: &pb.Value_StringValue{StringValue: tag}}
+		}
+		payload["tags"] = &pb.Value{Kind: &pb.Value_ListValue{ListValue: &pb.ListValue{Values: values}}}
+	}
+}
+
 // Helper functions for payload extraction.
 func getStringValue(payload map[string]*pb.Value, key string) string {
 	if v, ok := payload[key]; ok {
@@ -562,6 +1183,13 @@ func getStringValue(payload map[string]*pb.Value, key string) string {
 	return ""
 }
 
+func getBoolValue(payload map[string]*pb.Value, key string) bool {
+	if v, ok := payload[key]; ok {
+		return v.GetBoolValue()
+	}
+	return false
+}
+
 func getIntValue(payload map[string]*pb.Value, key string) int64 {
 	if v, ok := payload[key]; ok {
 		return v.GetIntegerValue()
@@ -575,3 +1203,37 @@ func getDoubleValue(payload map[string]*pb.Value, key string) float64 {
 	}
 	return 0
 }
+
+func getStringListValue(payload map[string]*pb.Value, key string) []string {
+	v, ok := payload[key]
+	if !ok {
+		return nil
+	}
+	var values []string
+	for _, item := range v.GetListValue().GetValues() {
+		values = append(values, item.GetStringValue())
+	}
+	return values
+}
+
+func getStringMapValue(payload map[string]*pb.Value, key string) map[string]string {
+	fields := payload[key].GetStructValue().GetFields()
+	if len(fields) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(fields))
+	for k, v := range fields {
+		values[k] = v.GetStringValue()
+	}
+	return values
+}
+
+// getTimeValue parses a timestamp payload value, returning the zero time
+// for missing or malformed values.
+func getTimeValue(payload map[string]*pb.Value, key string) time.Time {
+	t, err := time.Parse(timestampLayout, getStringValue(payload, key))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,80 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/qdrant/go-client/qdrant"
+)
+
+// CreateSnapshot snapshots the collection and returns the snapshot name.
+// Snapshots are taken of the collection an alias points to, since Qdrant
+// does not snapshot through aliases.
+func (r *Repository) CreateSnapshot(ctx context.Context) (string, error) {
+	collection, err := r.physicalCollection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.snapshots.Create(ctx, &pb.CreateSnapshotRequest{
+		CollectionName: collection,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating snapshot of %s: %w", collection, err)
+	}
+	return resp.GetSnapshotDescription().GetName(), nil
+}
+
+// DeleteSnapshot removes a collection snapshot by name.
+func (r *Repository) DeleteSnapshot(ctx context.Context, name string) error {
+	collection, err := r.physicalCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.snapshots.Delete(ctx, &pb.DeleteSnapshotRequest{
+		CollectionName: collection,
+		SnapshotName:   name,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting snapshot %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListSnapshots returns the names of the collection's snapshots.
+func (r *Repository) ListSnapshots(ctx context.Context) ([]string, error) {
+	collection, err := r.physicalCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.snapshots.List(ctx, &pb.ListSnapshotsRequest{
+		CollectionName: collection,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing snapshots of %s: %w", collection, err)
+	}
+
+	names := make([]string, 0, len(resp.GetSnapshotDescriptions()))
+	for _, desc := range resp.GetSnapshotDescriptions() {
+		names = append(names, desc.GetName())
+	}
+	return names, nil
+}
+
+// physicalCollection returns the collection the repository's collection
+// name resolves to, following an alias if there is one.
+func (r *Repository) physicalCollection(ctx context.Context) (string, error) {
+	resp, err := r.client.ListAliases(ctx, &pb.ListAliasesRequest{})
+	if err != nil {
+		return "", fmt.Errorf("listing aliases: %w", err)
+	}
+
+	for _, desc := range resp.Aliases {
+		if desc.AliasName == r.collection {
+			return desc.CollectionName, nil
+		}
+	}
+	return r.collection, nil
+}
@@ -0,0 +1,85 @@
+package qdrant
+
+import (
+	"hash/fnv"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// bm25K1 controls term frequency saturation in document vectors.
+// Inverse document frequency is applied by Qdrant's IDF modifier.
+const bm25K1 = 1.2
+
+// stopwords are dropped before encoding; they carry no keyword signal.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "the": true, "to": true, "was": true, "were": true, "with": true,
+}
+
+// keywordText returns the fact text indexed for keyword search.
+func keywordText(fact *entities.Fact) string {
+	return strings.Join([]string{fact.Subject, fact.Predicate, fact.Object, fact.Context}, " ")
+}
+
+// tokenize lowercases text and splits it into words, dropping stopwords.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if !stopwords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// termIndex hashes a term to a sparse vector dimension.
+func termIndex(term string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(term)) // hash.Hash never returns an error
+	return h.Sum32()
+}
+
+// encodeDocument builds a sparse vector weighting each term by BM25
+// term-frequency saturation: tf*(k1+1)/(tf+k1).
+func encodeDocument(text string) (indices []uint32, values []float32) {
+	counts := make(map[uint32]float32)
+	for _, token := range tokenize(text) {
+		counts[termIndex(token)]++
+	}
+	return sortedSparse(counts, func(tf float32) float32 {
+		return tf * (bm25K1 + 1) / (tf + bm25K1)
+	})
+}
+
+// encodeQuery builds a sparse vector giving each distinct term weight 1.
+func encodeQuery(text string) (indices []uint32, values []float32) {
+	counts := make(map[uint32]float32)
+	for _, token := range tokenize(text) {
+		counts[termIndex(token)] = 1
+	}
+	return sortedSparse(counts, func(w float32) float32 { return w })
+}
+
+// sortedSparse converts term weights to parallel slices ordered by index.
+func sortedSparse(weights map[uint32]float32, weigh func(float32) float32) (indices []uint32, values []float32) {
+	indices = make([]uint32, 0, len(weights))
+	for idx := range weights {
+		indices = append(indices, idx)
+	}
+	slices.Sort(indices)
+
+	values = make([]float32, 0, len(indices))
+	for _, idx := range indices {
+		values = append(values, weigh(weights[idx]))
+	}
+	return indices, values
+}
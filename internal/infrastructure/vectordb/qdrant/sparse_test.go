@@ -0,0 +1,39 @@
+package qdrant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize("The Ring of Barad-dûr, forged in 1600!")
+	assert.Equal(t, []string{"ring", "barad", "dûr", "forged", "1600"}, tokens)
+}
+
+func TestEncodeDocument(t *testing.T) {
+	indices, values := encodeDocument("Frodo Frodo Baggins")
+	require.Len(t, indices, 2)
+	require.Len(t, values, 2)
+
+	assert.IsIncreasing(t, indices)
+
+	weights := map[uint32]float32{}
+	for i := range indices {
+		weights[indices[i]] = values[i]
+	}
+	// Repeated terms score higher but saturate below k1+1.
+	assert.Greater(t, weights[termIndex("frodo")], weights[termIndex("baggins")])
+	assert.Less(t, weights[termIndex("frodo")], float32(bm25K1+1))
+	assert.InDelta(t, 1.0, weights[termIndex("baggins")], 1e-6)
+}
+
+func TestEncodeQuery(t *testing.T) {
+	indices, values := encodeQuery("who is Frodo frodo")
+	require.Len(t, indices, 2)
+	assert.Equal(t, []float32{1, 1}, values)
+
+	indices, _ = encodeQuery("the of and")
+	assert.Empty(t, indices)
+}
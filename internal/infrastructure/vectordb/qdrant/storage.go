@@ -0,0 +1,119 @@
+package qdrant
+
+import (
+	pb "github.com/qdrant/go-client/qdrant"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ersonp/lore-core/internal/domain/ports"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+// compressionRatios maps config compression names to Qdrant ratios.
+var compressionRatios = map[string]pb.CompressionRatio{
+	"x4":  pb.CompressionRatio_x4,
+	"x8":  pb.CompressionRatio_x8,
+	"x16": pb.CompressionRatio_x16,
+	"x32": pb.CompressionRatio_x32,
+	"x64": pb.CompressionRatio_x64,
+}
+
+// createCollectionRequest builds a collection creation request with named
+// context and text vectors plus a sparse keyword vector, applying the
+// storage tuning options from cfg.
+// Unset options use Qdrant defaults.
+func createCollectionRequest(name string, vectorSize uint64, cfg *config.QdrantConfig) *pb.CreateCollection {
+	params := &pb.VectorParams{
+		Size:     vectorSize,
+		Distance: pb.Distance_Cosine,
+	}
+	if cfg.OnDiskVectors {
+		params.OnDisk = pb.PtrOf(true)
+	}
+	if hnsw := hnswConfig(cfg.HNSW); hnsw != nil {
+		params.HnswConfig = hnsw
+	}
+	if quant := quantizationConfig(cfg.Quantization); quant != nil {
+		params.QuantizationConfig = quant
+	}
+
+	// Every fact stores the same-sized context and text embeddings.
+	textParams := proto.Clone(params).(*pb.VectorParams)
+
+	req := &pb.CreateCollection{
+		CollectionName: name,
+		VectorsConfig: &pb.VectorsConfig{
+			Config: &pb.VectorsConfig_ParamsMap{
+				ParamsMap: &pb.VectorParamsMap{
+					Map: map[string]*pb.VectorParams{
+						string(ports.VectorContext): params,
+						string(ports.VectorText):    textParams,
+					},
+				},
+			},
+		},
+	}
+	if cfg.OnDiskPayload {
+		req.OnDiskPayload = pb.PtrOf(true)
+	}
+
+	// Keyword vectors carry term frequencies; Qdrant applies IDF at query time.
+	req.SparseVectorsConfig = &pb.SparseVectorConfig{
+		Map: map[string]*pb.SparseVectorParams{
+			string(ports.VectorKeywords): {Modifier: pb.Modifier_Idf.Enum()},
+		},
+	}
+	return req
+}
+
+// hnswConfig converts HNSW settings, returning nil when nothing is set.
+func hnswConfig(cfg config.HNSWConfig) *pb.HnswConfigDiff {
+	if cfg.M == 0 && cfg.EfConstruct == 0 {
+		return nil
+	}
+	diff := &pb.HnswConfigDiff{}
+	if cfg.M != 0 {
+		diff.M = pb.PtrOf(cfg.M)
+	}
+	if cfg.EfConstruct != 0 {
+		diff.EfConstruct = pb.PtrOf(cfg.EfConstruct)
+	}
+	return diff
+}
+
+// quantizationConfig converts quantization settings, returning nil when disabled.
+// Settings are assumed to have passed config.QuantizationConfig.Validate.
+func quantizationConfig(cfg config.QuantizationConfig) *pb.QuantizationConfig {
+	var alwaysRAM *bool
+	if cfg.AlwaysRAM {
+		alwaysRAM = pb.PtrOf(true)
+	}
+
+	switch cfg.Type {
+	case config.QuantizationScalar:
+		scalar := &pb.ScalarQuantization{
+			Type:      pb.QuantizationType_Int8,
+			AlwaysRam: alwaysRAM,
+		}
+		if cfg.Quantile != 0 {
+			scalar.Quantile = pb.PtrOf(cfg.Quantile)
+		}
+		return &pb.QuantizationConfig{
+			Quantization: &pb.QuantizationConfig_Scalar{Scalar: scalar},
+		}
+	case config.QuantizationProduct:
+		ratio, ok := compressionRatios[cfg.Compression]
+		if !ok {
+			ratio = pb.CompressionRatio_x16
+		}
+		return &pb.QuantizationConfig{
+			Quantization: &pb.QuantizationConfig_Product{
+				Product: &pb.ProductQuantization{
+					Compression: ratio,
+					AlwaysRam:   alwaysRAM,
+				},
+			},
+		}
+	default:
+		return nil
+	}
+}
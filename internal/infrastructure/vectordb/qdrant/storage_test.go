@@ -0,0 +1,88 @@
+package qdrant
+
+import (
+	"testing"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+)
+
+func TestCreateCollectionRequest_Defaults(t *testing.T) {
+	req := createCollectionRequest("lore_test", 1536, &config.QdrantConfig{})
+
+	assert.Equal(t, "lore_test", req.CollectionName)
+	assert.Nil(t, req.OnDiskPayload)
+
+	vectors := req.VectorsConfig.GetParamsMap().GetMap()
+	require.Len(t, vectors, 2)
+	require.Contains(t, vectors, "text")
+	params := vectors["context"]
+	require.NotNil(t, params)
+	assert.Equal(t, uint64(1536), params.Size)
+	assert.Equal(t, uint64(1536), vectors["text"].Size)
+
+	sparse := req.GetSparseVectorsConfig().GetMap()["keywords"]
+	require.NotNil(t, sparse)
+	assert.Equal(t, pb.Modifier_Idf, sparse.GetModifier())
+	assert.Equal(t, pb.Distance_Cosine, params.Distance)
+	assert.Nil(t, params.OnDisk)
+	assert.Nil(t, params.HnswConfig)
+	assert.Nil(t, params.QuantizationConfig)
+}
+
+func TestCreateCollectionRequest_Tuning(t *testing.T) {
+	cfg := config.QdrantConfig{
+		OnDiskVectors: true,
+		OnDiskPayload: true,
+		HNSW:          config.HNSWConfig{M: 32, EfConstruct: 256},
+		Quantization: config.QuantizationConfig{
+			Type:      config.QuantizationScalar,
+			Quantile:  0.99,
+			AlwaysRAM: true,
+		},
+	}
+
+	req := createCollectionRequest("lore_test", 8, &cfg)
+	assert.True(t, req.GetOnDiskPayload())
+
+	for name, params := range req.VectorsConfig.GetParamsMap().GetMap() {
+		t.Run(name, func(t *testing.T) {
+			assertTunedParams(t, params)
+		})
+	}
+}
+
+func assertTunedParams(t *testing.T, params *pb.VectorParams) {
+	t.Helper()
+	require.NotNil(t, params)
+	assert.True(t, params.GetOnDisk())
+	assert.Equal(t, uint64(32), params.HnswConfig.GetM())
+	assert.Equal(t, uint64(256), params.HnswConfig.GetEfConstruct())
+
+	scalar := params.QuantizationConfig.GetScalar()
+	require.NotNil(t, scalar)
+	assert.Equal(t, pb.QuantizationType_Int8, scalar.Type)
+	assert.InDelta(t, 0.99, scalar.GetQuantile(), 1e-6)
+	assert.True(t, scalar.GetAlwaysRam())
+}
+
+func TestQuantizationConfig_Product(t *testing.T) {
+	t.Run("explicit compression", func(t *testing.T) {
+		q := quantizationConfig(config.QuantizationConfig{Type: config.QuantizationProduct, Compression: "x64"})
+		require.NotNil(t, q.GetProduct())
+		assert.Equal(t, pb.CompressionRatio_x64, q.GetProduct().Compression)
+	})
+
+	t.Run("default compression", func(t *testing.T) {
+		q := quantizationConfig(config.QuantizationConfig{Type: config.QuantizationProduct})
+		require.NotNil(t, q.GetProduct())
+		assert.Equal(t, pb.CompressionRatio_x16, q.GetProduct().Compression)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.Nil(t, quantizationConfig(config.QuantizationConfig{}))
+	})
+}
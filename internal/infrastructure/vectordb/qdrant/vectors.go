@@ -0,0 +1,117 @@
+package qdrant
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/qdrant/go-client/qdrant"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
+)
+
+// vectorLayout describes which vectors a collection stores.
+type vectorLayout struct {
+	named  bool // Named context and text dense vectors
+	sparse bool // Sparse keyword vector
+}
+
+// currentLayout is the layout of collections created by this version.
+var currentLayout = vectorLayout{named: true, sparse: true}
+
+// layout reports which vectors the collection stores. Collections created
+// before named vectors hold a single unnamed vector and no keyword index;
+// the layout is looked up once and cached.
+func (r *Repository) layout(ctx context.Context) (vectorLayout, error) {
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
+
+	if r.layoutKnown {
+		return r.vectors, nil
+	}
+
+	resp, err := r.client.Get(ctx, &pb.GetCollectionInfoRequest{
+		CollectionName: r.collection,
+	})
+	if err != nil {
+		return vectorLayout{}, fmt.Errorf("getting collection info: %w", err)
+	}
+
+	params := resp.GetResult().GetConfig().GetParams()
+	r.vectors = vectorLayout{
+		named:  params.GetVectorsConfig().GetParamsMap() != nil,
+		sparse: params.GetSparseVectorsConfig().GetMap()[string(ports.VectorKeywords)] != nil,
+	}
+	r.layoutKnown = true
+	return r.vectors, nil
+}
+
+// setLayout records a known collection layout, skipping detection.
+func (r *Repository) setLayout(layout vectorLayout) {
+	r.layoutMu.Lock()
+	defer r.layoutMu.Unlock()
+
+	r.vectors = layout
+	r.layoutKnown = true
+}
+
+// pointVectors builds the vectors for a fact in the collection's layout.
+// A missing text embedding falls back to the context embedding.
+func pointVectors(fact *entities.Fact, layout vectorLayout) *pb.Vectors {
+	if !layout.named {
+		return &pb.Vectors{
+			VectorsOptions: &pb.Vectors_Vector{
+				Vector: &pb.Vector{Data: fact.Embedding},
+			},
+		}
+	}
+
+	text := fact.TextEmbedding
+	if len(text) == 0 {
+		text = fact.Embedding
+	}
+
+	vectors := map[string]*pb.Vector{
+		string(ports.VectorContext): {Data: fact.Embedding},
+		string(ports.VectorText):    {Data: text},
+	}
+	if layout.sparse {
+		if indices, values := encodeDocument(keywordText(fact)); len(indices) > 0 {
+			vectors[string(ports.VectorKeywords)] = pb.NewVectorSparse(indices, values)
+		}
+	}
+
+	return &pb.Vectors{
+		VectorsOptions: &pb.Vectors_Vectors{
+			Vectors: &pb.NamedVectors{Vectors: vectors},
+		},
+	}
+}
+
+// outputEmbeddings extracts the context and text embeddings from returned
+// vectors. Unnamed vectors are returned as the context embedding.
+func outputEmbeddings(vectors *pb.VectorsOutput) (embedding, textEmbedding []float32) {
+	if vectors == nil {
+		return nil, nil
+	}
+
+	if vec := vectors.GetVector(); vec != nil {
+		if dense := vec.GetDense(); dense != nil {
+			return dense.Data, nil
+		}
+		return nil, nil
+	}
+
+	named := vectors.GetVectors().GetVectors()
+	if vec := named[string(ports.VectorContext)]; vec != nil {
+		if dense := vec.GetDense(); dense != nil {
+			embedding = dense.Data
+		}
+	}
+	if vec := named[string(ports.VectorText)]; vec != nil {
+		if dense := vec.GetDense(); dense != nil {
+			textEmbedding = dense.Data
+		}
+	}
+	return embedding, textEmbedding
+}
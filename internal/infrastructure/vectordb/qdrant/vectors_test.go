@@ -0,0 +1,79 @@
+package qdrant
+
+import (
+	"testing"
+
+	pb "github.com/qdrant/go-client/qdrant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+func TestPointVectors(t *testing.T) {
+	t.Run("unnamed layout uses context embedding", func(t *testing.T) {
+		fact := &entities.Fact{Embedding: []float32{1}, TextEmbedding: []float32{2}}
+
+		vectors := pointVectors(fact, vectorLayout{})
+		assert.Equal(t, []float32{1}, vectors.GetVector().GetData())
+	})
+
+	t.Run("named layout stores both embeddings", func(t *testing.T) {
+		fact := &entities.Fact{Embedding: []float32{1}, TextEmbedding: []float32{2}}
+
+		named := pointVectors(fact, vectorLayout{named: true}).GetVectors().GetVectors()
+		require.Len(t, named, 2)
+		assert.Equal(t, []float32{1}, named["context"].GetData())
+		assert.Equal(t, []float32{2}, named["text"].GetData())
+	})
+
+	t.Run("missing text embedding falls back", func(t *testing.T) {
+		fact := &entities.Fact{Embedding: []float32{1}}
+
+		named := pointVectors(fact, vectorLayout{named: true}).GetVectors().GetVectors()
+		assert.Equal(t, []float32{1}, named["text"].GetData())
+	})
+
+	t.Run("sparse layout adds keyword vector", func(t *testing.T) {
+		fact := &entities.Fact{Subject: "Gandalf", Predicate: "wields", Object: "Glamdring", Embedding: []float32{1}}
+
+		named := pointVectors(fact, currentLayout).GetVectors().GetVectors()
+		require.Contains(t, named, "keywords")
+		assert.Len(t, named["keywords"].GetSparse().GetIndices(), 3)
+	})
+}
+
+func TestOutputEmbeddings(t *testing.T) {
+	dense := func(data ...float32) *pb.VectorOutput {
+		return &pb.VectorOutput{Vector: &pb.VectorOutput_Dense{Dense: &pb.DenseVector{Data: data}}}
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		embedding, text := outputEmbeddings(nil)
+		assert.Nil(t, embedding)
+		assert.Nil(t, text)
+	})
+
+	t.Run("unnamed", func(t *testing.T) {
+		embedding, text := outputEmbeddings(&pb.VectorsOutput{
+			VectorsOptions: &pb.VectorsOutput_Vector{Vector: dense(1, 2)},
+		})
+		assert.Equal(t, []float32{1, 2}, embedding)
+		assert.Nil(t, text)
+	})
+
+	t.Run("named", func(t *testing.T) {
+		embedding, text := outputEmbeddings(&pb.VectorsOutput{
+			VectorsOptions: &pb.VectorsOutput_Vectors{
+				Vectors: &pb.NamedVectorsOutput{
+					Vectors: map[string]*pb.VectorOutput{
+						"context": dense(1),
+						"text":    dense(2),
+					},
+				},
+			},
+		})
+		assert.Equal(t, []float32{1}, embedding)
+		assert.Equal(t, []float32{2}, text)
+	})
+}
@@ -0,0 +1,192 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+const (
+	// mediaWikiBatch is the most pages whose content the API returns per
+	// request.
+	mediaWikiBatch = 50
+	// mediaWikiTimeout bounds each API request.
+	mediaWikiTimeout = 30 * time.Second
+	// userAgent identifies requests, as Wikimedia's API policy asks.
+	userAgent = "lore-core (https://github.com/ersonp/lore-core)"
+)
+
+// MediaWikiOptions selects the pages to read from a MediaWiki.
+type MediaWikiOptions struct {
+	Titles   []string // Read these pages (empty = all articles, or Category)
+	Category string   // Read the articles in this category, without the "Category:" prefix
+	Limit    int      // Maximum pages to read (0 = all)
+}
+
+// MediaWiki reads pages through a MediaWiki's action API.
+type MediaWiki struct {
+	http *http.Client
+	api  string
+}
+
+// NewMediaWiki creates a client for the API at apiURL, such as
+// https://lotr.fandom.com/api.php.
+func NewMediaWiki(apiURL string) (*MediaWiki, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, entities.Errorf(entities.ErrValidation, "MediaWiki API must be an http or https URL such as https://example.org/w/api.php, got %q", apiURL)
+	}
+	return &MediaWiki{
+		http: &http.Client{Timeout: mediaWikiTimeout},
+		api:  apiURL,
+	}, nil
+}
+
+// mediaWikiResponse is the part of a query response that is read.
+type mediaWikiResponse struct {
+	Query struct {
+		Pages []struct {
+			Title     string `json:"title"`
+			FullURL   string `json:"fullurl"`
+			Missing   bool   `json:"missing"`
+			Revisions []struct {
+				Slots struct {
+					Main struct {
+						Content string `json:"content"`
+					} `json:"main"`
+				} `json:"slots"`
+			} `json:"revisions"`
+		} `json:"pages"`
+	} `json:"query"`
+	Continue map[string]string `json:"continue"`
+	Error    *struct {
+		Code string `json:"code"`
+		Info string `json:"info"`
+	} `json:"error"`
+}
+
+// Pages calls fn with each selected page, in the order the API lists them,
+// stopping at the first error. Redirects are followed and missing pages
+// skipped.
+func (m *MediaWiki) Pages(ctx context.Context, opts MediaWikiOptions, fn func(Page) error) error {
+	read := 0
+	for _, batch := range titleBatches(opts.Titles) {
+		params := m.params()
+		params.Set("titles", strings.Join(batch, "|"))
+		params.Set("redirects", "1")
+		if err := m.query(ctx, params, opts.Limit, &read, fn); err != nil {
+			return err
+		}
+	}
+	if len(opts.Titles) > 0 {
+		return nil
+	}
+
+	params := m.params()
+	if opts.Category != "" {
+		params.Set("generator", "categorymembers")
+		params.Set("gcmtitle", "Category:"+strings.TrimPrefix(opts.Category, "Category:"))
+		params.Set("gcmnamespace", "0")
+		params.Set("gcmlimit", strconv.Itoa(mediaWikiBatch))
+	} else {
+		params.Set("generator", "allpages")
+		params.Set("gapnamespace", "0")
+		params.Set("gapfilterredir", "nonredirects")
+		params.Set("gaplimit", strconv.Itoa(mediaWikiBatch))
+	}
+	return m.query(ctx, params, opts.Limit, &read, fn)
+}
+
+// params returns the parameters every query shares: page content and URLs,
+// as JSON.
+func (m *MediaWiki) params() url.Values {
+	return url.Values{
+		"action":        {"query"},
+		"format":        {"json"},
+		"formatversion": {"2"},
+		"prop":          {"revisions|info"},
+		"rvprop":        {"content"},
+		"rvslots":       {"main"},
+		"inprop":        {"url"},
+	}
+}
+
+// query runs a query, following its continuations, and calls fn with each
+// page until limit pages have been read in all.
+func (m *MediaWiki) query(ctx context.Context, params url.Values, limit int, read *int, fn func(Page) error) error {
+	for {
+		resp, err := m.get(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range resp.Query.Pages {
+			if p.Missing || len(p.Revisions) == 0 {
+				continue
+			}
+			if limit > 0 && *read >= limit {
+				return nil
+			}
+			*read++
+			if err := fn(ParseWikitext(p.Title, p.FullURL, p.Revisions[0].Slots.Main.Content)); err != nil {
+				return err
+			}
+		}
+
+		if len(resp.Continue) == 0 || (limit > 0 && *read >= limit) {
+			return nil
+		}
+		for k, v := range resp.Continue {
+			params.Set(k, v)
+		}
+	}
+}
+
+// get makes one API request.
+func (m *MediaWiki) get(ctx context.Context, params url.Values) (*mediaWikiResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.api+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := m.http.Do(req)
+	if err != nil {
+		return nil, entities.WithKind(entities.ErrBackendUnavailable, fmt.Errorf("calling MediaWiki API: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MediaWiki API returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	var parsed mediaWikiResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing MediaWiki API response: %w", err)
+	}
+	if parsed.Error != nil {
+		return nil, fmt.Errorf("MediaWiki API error %s: %s", parsed.Error.Code, parsed.Error.Info)
+	}
+	return &parsed, nil
+}
+
+// titleBatches splits titles into batches the API accepts in one request.
+func titleBatches(titles []string) [][]string {
+	var batches [][]string
+	for start := 0; start < len(titles); start += mediaWikiBatch {
+		batches = append(batches, titles[start:min(start+mediaWikiBatch, len(titles))])
+	}
+	return batches
+}
@@ -0,0 +1,123 @@
+// Package wiki reads pages from wiki platforms, MediaWiki and World Anvil,
+// as infobox fields to import as facts and prose to extract facts from.
+package wiki
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// maxFieldLength is the longest infobox value imported as a fact. Longer
+// values are prose and are extracted with the page's text instead.
+const maxFieldLength = 200
+
+// Field is one structured field of a page, such as an infobox row.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Page is a wiki article.
+type Page struct {
+	Title  string
+	URL    string  // Where the page is published, recorded as its facts' source
+	Type   string  // Infobox template or article template, e.g. "Infobox character"
+	Fields []Field // Structured fields, imported as facts directly
+	Text   string  // Prose with markup removed, for extraction
+}
+
+// Source returns the page's URL, or its title if it has none.
+func (p *Page) Source() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return p.Title
+}
+
+// Document returns the page as an import document: its title as an entity
+// and each field as a fact about it.
+func (p *Page) Document() *entities.ImportDocument {
+	doc := &entities.ImportDocument{Entities: []entities.RawEntity{{Name: p.Title}}}
+	factType := string(FactType(p.Type))
+	for i, f := range p.Fields {
+		doc.Facts = append(doc.Facts, entities.RawFact{
+			Type:       factType,
+			Subject:    p.Title,
+			Predicate:  Predicate(f.Name),
+			Object:     f.Value,
+			SourceFile: p.Source(),
+			LineNum:    i + 1,
+		})
+	}
+	return doc
+}
+
+// factTypeKeywords maps words in a template name to the fact type of its
+// pages' fields, checked in order.
+var factTypeKeywords = []struct {
+	words    []string
+	factType entities.FactType
+}{
+	{[]string{"battle", "war", "event", "conflict", "ceremony"}, entities.FactTypeEvent},
+	{[]string{"era", "age", "timeline", "date", "year"}, entities.FactTypeTimeline},
+	{[]string{"location", "place", "settlement", "city", "town", "country", "region", "realm", "kingdom", "planet", "building", "landmark", "geography", "continent"}, entities.FactTypeLocation},
+	{[]string{"law", "magic", "spell", "rule", "religion", "language", "technology"}, entities.FactTypeRule},
+}
+
+// FactType returns the fact type of fields from a template: an event for a
+// battle, a location for a settlement, and so on. Anything else, usually a
+// person or creature, is a character.
+func FactType(template string) entities.FactType {
+	words := strings.FieldsFunc(strings.ToLower(camelBoundary.ReplaceAllString(template, "$1 $2")), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, k := range factTypeKeywords {
+		for _, w := range words {
+			for _, keyword := range k.words {
+				if w == keyword || w == keyword+"s" {
+					return k.factType
+				}
+			}
+		}
+	}
+	return entities.FactTypeCharacter
+}
+
+var (
+	// camelBoundary matches a lowercase letter or digit followed by a capital.
+	camelBoundary = regexp.MustCompile(`([\p{Ll}\d])(\p{Lu})`)
+	// nonWord matches runs of anything but letters and digits.
+	nonWord = regexp.MustCompile(`[^\p{L}\d]+`)
+)
+
+// Predicate returns a field name as a predicate: lowercase words joined by
+// underscores, so "dateOfBirth" and "Date of birth" are both "date_of_birth".
+func Predicate(name string) string {
+	name = camelBoundary.ReplaceAllString(name, "${1}_${2}")
+	return strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// keepField reports whether a field's value is worth importing as a fact:
+// short, and not just numbers or symbols.
+func keepField(value string) bool {
+	return value != "" && len(value) <= maxFieldLength && strings.IndexFunc(value, unicode.IsLetter) >= 0
+}
+
+var (
+	// blankLines matches runs of blank lines.
+	blankLines = regexp.MustCompile(`\n[ \t]*(?:\n[ \t]*)+`)
+	// spaces matches runs of spaces and tabs.
+	spaces = regexp.MustCompile(`[ \t]+`)
+)
+
+// tidy collapses runs of spaces and blank lines, and trims each line.
+func tidy(text string) string {
+	lines := strings.Split(spaces.ReplaceAllString(text, " "), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,204 @@
+package wiki
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+const frodoWikitext = `{{Infobox character
+| name = Frodo Baggins
+| image = [[File:Frodo.jpg|250px]]
+| race = [[Hobbits|Hobbit]]
+| home = [[Bag End]], {{small|[[the Shire]]}}
+| weapon = [[Sting]]<ref>The Return of the King</ref>
+| born = 2968
+}}
+'''Frodo Baggins''' was a [[Hobbits|hobbit]] of [[the Shire]].<!-- stub -->
+
+== History ==
+* He inherited [[Bag End]] from [[Bilbo Baggins|Bilbo]].
+[[File:Ring.png|thumb|The [[One Ring]]]]
+See [https://example.org the letters].
+{| class="wikitable"
+| row
+|}
+[[Category:Ring-bearers]]`
+
+func TestParseWikitext(t *testing.T) {
+	page := ParseWikitext("Frodo Baggins", "https://wiki.example/Frodo", frodoWikitext)
+
+	assert.Equal(t, "Infobox character", page.Type)
+	assert.Equal(t, []Field{
+		{Name: "race", Value: "Hobbit"},
+		{Name: "home", Value: "Bag End"},
+		{Name: "weapon", Value: "Sting"},
+	}, page.Fields, "layout, numeric, and footnote content is dropped")
+	assert.Equal(t, "Frodo Baggins was a hobbit of the Shire.\n\nHistory\nHe inherited Bag End from Bilbo.\n\nSee the letters.", page.Text)
+}
+
+func TestPage_Document(t *testing.T) {
+	page := Page{
+		Title:  "Minas Tirith",
+		URL:    "https://wiki.example/Minas_Tirith",
+		Type:   "Infobox settlement",
+		Fields: []Field{{Name: "Ruled by", Value: "Stewards of Gondor"}},
+	}
+
+	doc := page.Document()
+	require.Len(t, doc.Entities, 1)
+	assert.Equal(t, "Minas Tirith", doc.Entities[0].Name)
+	require.Len(t, doc.Facts, 1)
+	assert.Equal(t, "location", doc.Facts[0].Type)
+	assert.Equal(t, "ruled_by", doc.Facts[0].Predicate)
+	assert.Equal(t, "Stewards of Gondor", doc.Facts[0].Object)
+	assert.Equal(t, page.URL, doc.Facts[0].SourceFile, "the page URL is the provenance")
+}
+
+func TestFactType(t *testing.T) {
+	assert.Equal(t, entities.FactTypeLocation, FactType("Infobox settlement"))
+	assert.Equal(t, entities.FactTypeLocation, FactType("Location"))
+	assert.Equal(t, entities.FactTypeEvent, FactType("Infobox battle"))
+	assert.Equal(t, entities.FactTypeEvent, FactType("MilitaryConflict"))
+	assert.Equal(t, entities.FactTypeRule, FactType("Spell"))
+	assert.Equal(t, entities.FactTypeCharacter, FactType("Person"))
+	assert.Equal(t, entities.FactTypeCharacter, FactType(""))
+}
+
+func TestPredicate(t *testing.T) {
+	assert.Equal(t, "date_of_birth", Predicate("dateOfBirth"))
+	assert.Equal(t, "date_of_birth", Predicate("Date of birth"))
+	assert.Equal(t, "hair_colour", Predicate(" hair-colour "))
+}
+
+func TestMediaWiki_Pages(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		requests = append(requests, r.URL.RawQuery)
+		assert.NotEmpty(t, r.Header.Get("User-Agent"))
+		assert.Equal(t, "allpages", q.Get("generator"))
+
+		page := map[string]any{
+			"title":     "Frodo Baggins",
+			"fullurl":   "https://wiki.example/Frodo_Baggins",
+			"revisions": []any{map[string]any{"slots": map[string]any{"main": map[string]any{"content": frodoWikitext}}}},
+		}
+		resp := map[string]any{"query": map[string]any{"pages": []any{page}}}
+		if q.Get("gapcontinue") == "" {
+			resp["continue"] = map[string]string{"gapcontinue": "Sam", "continue": "gapcontinue||"}
+		} else {
+			page["title"] = "Samwise Gamgee"
+			page["fullurl"] = "https://wiki.example/Samwise_Gamgee"
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	mw, err := NewMediaWiki(server.URL + "/api.php")
+	require.NoError(t, err)
+
+	var pages []Page
+	err = mw.Pages(context.Background(), MediaWikiOptions{}, func(p Page) error {
+		pages = append(pages, p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "Frodo Baggins", pages[0].Title)
+	assert.Equal(t, "https://wiki.example/Samwise_Gamgee", pages[1].URL)
+	assert.Equal(t, "Infobox character", pages[0].Type)
+	assert.Len(t, requests, 2, "continuations are followed")
+
+	requests = nil
+	pages = nil
+	err = mw.Pages(context.Background(), MediaWikiOptions{Limit: 1}, func(p Page) error {
+		pages = append(pages, p)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, pages, 1)
+	assert.Len(t, requests, 1, "no more requests once the limit is reached")
+}
+
+func TestMediaWiki_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Frodo|Sam", r.URL.Query().Get("titles"))
+		_, _ = w.Write([]byte(`{"error": {"code": "badvalue", "info": "Unrecognized value"}}`))
+	}))
+	defer server.Close()
+
+	mw, err := NewMediaWiki(server.URL)
+	require.NoError(t, err)
+	err = mw.Pages(context.Background(), MediaWikiOptions{Titles: []string{"Frodo", "Sam"}}, func(Page) error { return nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unrecognized value")
+
+	_, err = NewMediaWiki("lotr.fandom.com/api.php")
+	assert.ErrorIs(t, err, entities.ErrValidation)
+}
+
+const gimliArticle = `{
+	"title": "Gimli",
+	"url": "https://www.worldanvil.com/w/arda/a/gimli",
+	"entityClass": "Person",
+	"id": "1234",
+	"content": "[h1]Life[/h1][p]Son of @[Glóin](person:5678), he joined the [b]Fellowship[/b].[/p]",
+	"species": "[url:https://x]Dwarf[/url]",
+	"height": "137",
+	"biography": "Line one[br]Line two",
+	"isWip": false
+}`
+
+func TestReadWorldAnvil(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gimli.json"), []byte(gimliArticle), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "world.json"), []byte(`{"name": "Arda"}`), 0o600))
+
+	var pages []Page
+	require.NoError(t, ReadWorldAnvil(dir, func(p Page) error {
+		pages = append(pages, p)
+		return nil
+	}))
+	require.Len(t, pages, 1, "files that are not articles are skipped")
+
+	page := pages[0]
+	assert.Equal(t, "Gimli", page.Title)
+	assert.Equal(t, "https://www.worldanvil.com/w/arda/a/gimli", page.URL)
+	assert.Equal(t, "Person", page.Type)
+	assert.Equal(t, []Field{{Name: "species", Value: "Dwarf"}}, page.Fields)
+	assert.Equal(t, "Life\n\nSon of Glóin, he joined the Fellowship.\n\nLine one\nLine two", page.Text)
+}
+
+func TestReadWorldAnvil_Zip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("articles/gimli.json")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(gimliArticle))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	var titles []string
+	require.NoError(t, ReadWorldAnvil(path, func(p Page) error {
+		titles = append(titles, p.Title)
+		return nil
+	}))
+	assert.Equal(t, []string{"Gimli"}, titles)
+
+	err = ReadWorldAnvil(filepath.Join(filepath.Dir(path), "export.txt"), func(Page) error { return nil })
+	require.Error(t, err)
+}
@@ -0,0 +1,216 @@
+package wiki
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// skippedInfoboxFields are infobox parameters that describe the infobox's
+// own layout rather than the subject.
+var skippedInfoboxFields = map[string]bool{
+	"image": true, "image_size": true, "imagesize": true, "caption": true, "alt": true,
+	"logo": true, "map": true, "signature": true, "title": true, "name": true,
+	"width": true, "style": true, "color": true, "colour": true, "embed": true,
+}
+
+var (
+	comment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	// ref matches footnotes, which cite sources rather than state lore.
+	ref = regexp.MustCompile(`(?is)<ref[^>/]*/>|<ref[^>]*>.*?</ref>`)
+	// table matches wiki tables.
+	table = regexp.MustCompile(`(?s)\{\|.*?\n\|\}`)
+	// heading matches a section heading such as "== History ==".
+	heading = regexp.MustCompile(`(?m)^=+\s*(.*?)\s*=+\s*$`)
+	// listMarker matches the markers of list items and indented lines.
+	listMarker = regexp.MustCompile(`(?m)^[*#:;]+\s*`)
+	// externalLink matches an external link, with or without a label.
+	externalLink = regexp.MustCompile(`\[(?:https?:)?//[^\s\]]+(?:\s+([^\]]*))?\]`)
+	// emphasis matches bold and italic quotes.
+	emphasis = regexp.MustCompile(`'{2,}`)
+	// tag matches HTML tags.
+	tag = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	// magicWord matches behavior switches such as __TOC__.
+	magicWord = regexp.MustCompile(`__[A-Z]+__`)
+)
+
+// droppedLinkPrefixes are namespaces of links that embed or categorize
+// rather than link, and so are dropped from the text.
+var droppedLinkPrefixes = []string{"file:", "image:", "category:", "media:"}
+
+// ParseWikitext returns the page with the given wikitext: the parameters of
+// its infobox, the first template whose name starts with "Infobox", as
+// fields, and its text with templates and markup removed.
+func ParseWikitext(title, url, wikitext string) Page {
+	page := Page{Title: title, URL: url}
+
+	text := ref.ReplaceAllString(comment.ReplaceAllString(wikitext, ""), "")
+	var prose strings.Builder
+	rest := text
+	for {
+		start, end := nextTemplate(rest)
+		if start < 0 {
+			prose.WriteString(rest)
+			break
+		}
+		prose.WriteString(rest[:start])
+		name, params := splitTemplate(rest[start+2 : end-2])
+		if page.Type == "" && strings.HasPrefix(strings.ToLower(name), "infobox") {
+			page.Type = name
+			page.Fields = infoboxFields(params)
+		}
+		rest = rest[end:]
+	}
+
+	page.Text = plainWikitext(prose.String())
+	return page
+}
+
+// infoboxFields returns the named parameters of an infobox as fields,
+// skipping layout parameters and values that are empty or too long.
+func infoboxFields(params []string) []Field {
+	var fields []Field
+	for _, param := range params {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		if skippedInfoboxFields[strings.ToLower(name)] {
+			continue
+		}
+		// Removed templates may leave separators dangling, as in "Bag End, "
+		value = strings.TrimRight(strings.ReplaceAll(plainWikitext(removeTemplates(value)), "\n", ", "), " ,;")
+		if keepField(value) {
+			fields = append(fields, Field{Name: name, Value: value})
+		}
+	}
+	return fields
+}
+
+// nextTemplate returns where the first template in text starts and ends,
+// including its braces, or -1, -1 if there is none. Templates nest.
+func nextTemplate(text string) (int, int) {
+	start := strings.Index(text, "{{")
+	if start < 0 {
+		return -1, -1
+	}
+	depth := 0
+	for i := start; i < len(text)-1; i++ {
+		switch text[i : i+2] {
+		case "{{":
+			depth++
+			i++
+		case "}}":
+			depth--
+			i++
+			if depth == 0 {
+				return start, i + 1
+			}
+		}
+	}
+	// Unclosed: drop the rest
+	return start, len(text)
+}
+
+// removeTemplates removes every template from text.
+func removeTemplates(text string) string {
+	var b strings.Builder
+	for {
+		start, end := nextTemplate(text)
+		if start < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:start])
+		text = text[end:]
+	}
+}
+
+// splitTemplate splits the inside of a template at the pipes that are not
+// within a nested template or link, returning its name and parameters.
+func splitTemplate(inner string) (string, []string) {
+	var (
+		parts []string
+		depth int
+		last  int
+	)
+	for i := 0; i < len(inner); i++ {
+		switch {
+		case strings.HasPrefix(inner[i:], "{{"), strings.HasPrefix(inner[i:], "[["):
+			depth++
+			i++
+		case strings.HasPrefix(inner[i:], "}}"), strings.HasPrefix(inner[i:], "]]"):
+			depth--
+			i++
+		case inner[i] == '|' && depth == 0:
+			parts = append(parts, inner[last:i])
+			last = i + 1
+		}
+	}
+	parts = append(parts, inner[last:])
+	return strings.TrimSpace(parts[0]), parts[1:]
+}
+
+// plainWikitext returns wikitext without templates removed as plain text:
+// links become their labels, and tables, markup, and tags are dropped.
+func plainWikitext(text string) string {
+	text = table.ReplaceAllString(text, "")
+	text = replaceLinks(text)
+	text = externalLink.ReplaceAllString(text, "$1")
+	text = heading.ReplaceAllString(text, "$1")
+	text = listMarker.ReplaceAllString(text, "")
+	text = emphasis.ReplaceAllString(text, "")
+	text = magicWord.ReplaceAllString(text, "")
+	text = tag.ReplaceAllString(text, "")
+	return tidy(html.UnescapeString(text))
+}
+
+// replaceLinks replaces each internal link with its label, or its target
+// if it has none, and drops file and category links. Links nest within
+// file captions.
+func replaceLinks(text string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(text, "[[")
+		if start < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:start])
+
+		depth, end := 0, len(text)
+		for i := start; i < len(text)-1; i++ {
+			if text[i:i+2] == "[[" {
+				depth++
+				i++
+			} else if text[i:i+2] == "]]" {
+				depth--
+				i++
+				if depth == 0 {
+					end = i + 1
+					break
+				}
+			}
+		}
+
+		inner := strings.TrimSuffix(text[start+2:end], "]]")
+		b.WriteString(linkLabel(inner))
+		text = text[end:]
+	}
+}
+
+// linkLabel returns the text an internal link shows.
+func linkLabel(inner string) string {
+	target, label, hasLabel := strings.Cut(inner, "|")
+	lower := strings.ToLower(strings.TrimSpace(target))
+	for _, prefix := range droppedLinkPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return ""
+		}
+	}
+	if hasLabel {
+		return replaceLinks(label)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(target), ":")
+}
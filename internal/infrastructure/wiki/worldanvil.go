@@ -0,0 +1,183 @@
+package wiki
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ersonp/lore-core/internal/domain/entities"
+)
+
+// worldAnvilMetadata are the article keys that describe the article rather
+// than its subject, and are neither fields nor text.
+var worldAnvilMetadata = map[string]bool{
+	"id": true, "title": true, "slug": true, "url": true, "state": true,
+	"entityClass": true, "templateType": true, "icon": true, "excerpt": true,
+	"tags": true, "author": true, "world": true, "category": true, "folderId": true,
+	"creationDate": true, "updateDate": true, "publicationDate": true, "notificationDate": true,
+	"cover": true, "coverSource": true, "portrait": true, "flag": true, "position": true,
+	"likes": true, "views": true, "wordcount": true, "isWip": true, "isDraft": true,
+	"isEditable": true, "allowComments": true, "showSeeded": true, "css": true,
+	"displayCss": true, "customArticleTemplate": true, "editURL": true,
+	"metaTitle": true, "metaDescription": true, "subheading": true, "fullfooter": true,
+	"footnotes": true, "seeded": true,
+}
+
+var (
+	// mention matches a World Anvil mention such as @[Frodo](person:1234).
+	mention = regexp.MustCompile(`@\[([^\]]*)\]\([^)]*\)`)
+	// bbTag matches a BBCode tag such as [b], [/url], or [url:https://x].
+	bbTag = regexp.MustCompile(`\[/?[a-zA-Z][a-zA-Z0-9]*(?:[:=][^\]]*)?\]`)
+	// lineBreakTag matches BBCode and HTML line breaks.
+	lineBreakTag = regexp.MustCompile(`(?i)\[br\]|<br\s*/?>`)
+	// blockTag matches BBCode tags that start a new block.
+	blockTag = regexp.MustCompile(`(?i)\[/?(?:h[1-6]|p|quote|aloud|section|container|row|col|ul|ol|li|\*)(?:[:=][^\]]*)?\]`)
+)
+
+// ReadWorldAnvil calls fn with each article of a World Anvil export: a
+// .zip of the export, a directory it was unzipped into, or one article's
+// .json file. Each article's title, url, and entityClass are read; its
+// content and other long text are its text, and its other short text
+// fields, such as a person's species, are its fields. JSON files that are
+// not articles are skipped.
+func ReadWorldAnvil(path string, fn func(Page) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("accessing export: %w", err)
+	}
+
+	switch {
+	case info.IsDir():
+		var files []string
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".json") {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reading export: %w", err)
+		}
+		slices.Sort(files)
+		for _, f := range files {
+			if err := readWorldAnvilFile(f, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case strings.EqualFold(filepath.Ext(path), ".zip"):
+		return readWorldAnvilZip(path, fn)
+	case strings.EqualFold(filepath.Ext(path), ".json"):
+		return readWorldAnvilFile(path, fn)
+	}
+	return entities.Errorf(entities.ErrValidation, "World Anvil export must be a .zip, a directory, or a .json file: %s", path)
+}
+
+// readWorldAnvilZip reads the articles in a zipped export.
+func readWorldAnvilZip(path string, fn func(Page) error) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("opening export: %w", err)
+	}
+	defer archive.Close()
+
+	files := slices.Clone(archive.File)
+	slices.SortFunc(files, func(a, b *zip.File) int { return strings.Compare(a.Name, b.Name) })
+	for _, f := range files {
+		if f.FileInfo().IsDir() || !strings.EqualFold(filepath.Ext(f.Name), ".json") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("opening %s: %w", f.Name, err)
+		}
+		err = readWorldAnvilArticle(r, f.Name, fn)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readWorldAnvilFile reads an article from a JSON file.
+func readWorldAnvilFile(path string, fn func(Page) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer f.Close()
+	return readWorldAnvilArticle(f, path, fn)
+}
+
+// readWorldAnvilArticle calls fn with the article read from r, if it is one.
+func readWorldAnvilArticle(r io.Reader, name string, fn func(Page) error) error {
+	var article map[string]any
+	if err := json.NewDecoder(r).Decode(&article); err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	page, ok := worldAnvilPage(article)
+	if !ok {
+		return nil
+	}
+	return fn(page)
+}
+
+// worldAnvilPage returns an exported article as a page, or false if the
+// object is not an article.
+func worldAnvilPage(article map[string]any) (Page, bool) {
+	title, _ := article["title"].(string)
+	content, hasContent := article["content"].(string)
+	if strings.TrimSpace(title) == "" || !hasContent {
+		return Page{}, false
+	}
+
+	page := Page{Title: strings.TrimSpace(title)}
+	page.URL, _ = article["url"].(string)
+	page.Type, _ = article["entityClass"].(string)
+
+	texts := []string{plainBBCode(content)}
+	keys := make([]string, 0, len(article))
+	for k := range article {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		value, ok := article[k].(string)
+		if !ok || worldAnvilMetadata[k] || k == "content" {
+			continue
+		}
+		value = plainBBCode(value)
+		switch {
+		case keepField(value) && !strings.Contains(value, "\n"):
+			page.Fields = append(page.Fields, Field{Name: k, Value: value})
+		case len(value) > maxFieldLength || strings.Contains(value, "\n"):
+			texts = append(texts, value)
+		}
+	}
+
+	page.Text = tidy(strings.Join(texts, "\n\n"))
+	return page, true
+}
+
+// plainBBCode returns World Anvil BBCode as plain text: mentions become
+// their names and tags are dropped.
+func plainBBCode(text string) string {
+	text = mention.ReplaceAllString(text, "$1")
+	text = lineBreakTag.ReplaceAllString(text, "\n")
+	text = blockTag.ReplaceAllString(text, "\n")
+	text = bbTag.ReplaceAllString(text, "")
+	text = tag.ReplaceAllString(text, "")
+	return tidy(html.UnescapeString(text))
+}
@@ -0,0 +1,64 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ersonp/lore-core/internal/application/handlers"
+	"github.com/ersonp/lore-core/internal/domain/services"
+	"github.com/ersonp/lore-core/internal/infrastructure/config"
+	"github.com/ersonp/lore-core/internal/infrastructure/embedder/hashing"
+	"github.com/ersonp/lore-core/internal/infrastructure/llm/fake"
+	"github.com/ersonp/lore-core/internal/infrastructure/relationaldb/sqlite"
+)
+
+// TestPipeline_FakeProviders ingests a story and queries it end to end,
+// with the fake LLM and embedder standing in for OpenAI.
+func TestPipeline_FakeProviders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	cleanupFacts(t)
+	ctx := context.Background()
+
+	db, err := sqlite.NewRepository(config.SQLiteConfig{Path: filepath.Join(t.TempDir(), "test.db")})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.EnsureSchema(ctx))
+	entityTypes := services.NewEntityTypeService(db)
+	require.NoError(t, entityTypes.LoadDefaults(ctx))
+
+	llm := fake.NewClient()
+	embedder := hashing.NewEmbedder(config.EmbeddingVectorSize)
+	extraction := services.NewExtractionService(llm, embedder, testRepo, entityTypes)
+	ingest := handlers.NewIngestHandler(extraction, handlers.WithDisambiguation(services.NewDisambiguationService(db)), handlers.WithConflicts(services.NewConflictService(llm, testRepo, db)))
+
+	story := filepath.Join(t.TempDir(), "chapter1.txt")
+	require.NoError(t, os.WriteFile(story, []byte(
+		"Frodo lives in the Shire. Frodo is a hobbit. Gandalf is a wizard. Gandalf visited the Shire.",
+	), 0644))
+
+	result, err := ingest.HandleWithOptions(ctx, story, &handlers.IngestOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.FactsCount)
+
+	// A contradicting chapter is flagged against the facts already saved.
+	contradiction := filepath.Join(t.TempDir(), "chapter2.txt")
+	require.NoError(t, os.WriteFile(contradiction, []byte("Frodo lives in Mordor."), 0644))
+	result, err = ingest.HandleWithOptions(ctx, contradiction, &handlers.IngestOptions{CheckConsistency: true, CheckOnly: true})
+	require.NoError(t, err)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "the Shire", result.Issues[0].ExistingFact.Object)
+
+	query := handlers.NewQueryHandler(services.NewQueryService(embedder, testRepo, db))
+	found, err := query.Handle(ctx, "Frodo lives in the Shire", 1)
+	require.NoError(t, err)
+	require.Len(t, found.Facts, 1)
+	assert.Equal(t, "Frodo", found.Facts[0].Subject)
+	assert.Equal(t, "lives_in", found.Facts[0].Predicate)
+}
@@ -2,12 +2,14 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ersonp/lore-core/internal/domain/entities"
+	"github.com/ersonp/lore-core/internal/domain/ports"
 	embedder "github.com/ersonp/lore-core/internal/infrastructure/embedder/openai"
 )
 
@@ -60,6 +62,77 @@ func TestListByType(t *testing.T) {
 	assert.Equal(t, "Isengard", locations[0].Subject)
 }
 
+func TestListFiltered(t *testing.T) {
+	ctx := t.Context()
+	t.Cleanup(func() { cleanupFacts(t) })
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	facts := []entities.Fact{
+		{
+			ID:        uuid.New().String(),
+			Type:      entities.FactTypeCharacter,
+			Subject:   "Elrond",
+			Predicate: "rules",
+			Object:    "Rivendell",
+			CreatedAt: base,
+			UpdatedAt: base.Add(48 * time.Hour),
+			Embedding: make([]float32, embedder.VectorSize),
+		},
+		{
+			ID:        uuid.New().String(),
+			Type:      entities.FactTypeCharacter,
+			Subject:   "Arwen",
+			Predicate: "is daughter of",
+			Object:    "Elrond",
+			CreatedAt: base.Add(24 * time.Hour),
+			UpdatedAt: base.Add(24 * time.Hour),
+			Embedding: make([]float32, embedder.VectorSize),
+		},
+		{
+			ID:        uuid.New().String(),
+			Type:      entities.FactTypeLocation,
+			Subject:   "Rivendell",
+			Predicate: "is",
+			Object:    "a hidden valley",
+			CreatedAt: base.Add(-24 * time.Hour),
+			UpdatedAt: base.Add(-24 * time.Hour),
+			Embedding: make([]float32, embedder.VectorSize),
+		},
+	}
+	require.NoError(t, testRepo.SaveBatch(ctx, facts))
+
+	// Created on or after base, newest first
+	created, err := testRepo.ListFiltered(ctx, ports.FactListOptions{
+		Since: base,
+		Sort:  ports.FactSortCreated,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "Arwen", created[0].Subject)
+	assert.Equal(t, "Elrond", created[1].Subject)
+
+	// Updated most recently first
+	updated, err := testRepo.ListFiltered(ctx, ports.FactListOptions{
+		Type:  entities.FactTypeCharacter,
+		Sort:  ports.FactSortUpdated,
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, updated, 2)
+	assert.Equal(t, "Elrond", updated[0].Subject)
+	assert.Equal(t, base.Add(48*time.Hour), updated[0].UpdatedAt.UTC())
+
+	// Upper bound only
+	older, err := testRepo.ListFiltered(ctx, ports.FactListOptions{
+		Until: base.Add(-time.Hour),
+		Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, older, 1)
+	assert.Equal(t, "Rivendell", older[0].Subject)
+}
+
 func TestListBySource(t *testing.T) {
 	ctx := t.Context()
 	t.Cleanup(func() { cleanupFacts(t) })
@@ -227,3 +300,28 @@ func TestSearchByType(t *testing.T) {
 	assert.Len(t, results, 1)
 	assert.Equal(t, "Lothlorien", results[0].Subject)
 }
+
+func TestSearchBySubject(t *testing.T) {
+	ctx := t.Context()
+	t.Cleanup(func() { cleanupFacts(t) })
+
+	embedding := make([]float32, embedder.VectorSize)
+	for i := range embedding {
+		embedding[i] = 0.1
+	}
+
+	facts := []entities.Fact{
+		{ID: uuid.New().String(), Type: entities.FactTypeCharacter, Subject: "Galadriel", Predicate: "rules", Object: "Lothlorien", Embedding: embedding},
+		{ID: uuid.New().String(), Type: entities.FactTypeEvent, Subject: "Galadriel", Predicate: "refused", Object: "the One Ring", Embedding: embedding},
+		{ID: uuid.New().String(), Type: entities.FactTypeCharacter, Subject: "Celeborn", Predicate: "rules", Object: "Lothlorien", Embedding: embedding},
+	}
+	err := testRepo.SaveBatch(ctx, facts)
+	require.NoError(t, err)
+
+	results, err := testRepo.SearchBySubject(ctx, embedding, "Galadriel", 10)
+	require.NoError(t, err)
+	assert.Len(t, results, 2, "facts of every type about the subject")
+	for _, f := range results {
+		assert.Equal(t, "Galadriel", f.Subject)
+	}
+}
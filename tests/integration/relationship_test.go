@@ -76,6 +76,10 @@ func (m *relTestVectorDB) SearchByType(_ context.Context, _ []float32, _ entitie
 	return nil, nil
 }
 
+func (m *relTestVectorDB) SearchBySubject(_ context.Context, _ []float32, _ string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+
 func (m *relTestVectorDB) Delete(_ context.Context, id string) error {
 	delete(m.facts, id)
 	return nil
@@ -85,17 +89,50 @@ func (m *relTestVectorDB) List(_ context.Context, _ int, _ uint64) ([]entities.F
 	return nil, nil
 }
 
+func (m *relTestVectorDB) ListPage(_ context.Context, _ ports.FactPageOptions) ([]entities.Fact, string, error) {
+	return nil, "", nil
+}
+
 func (m *relTestVectorDB) ListByType(_ context.Context, _ entities.FactType, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
 
+func (m *relTestVectorDB) SearchVector(_ context.Context, _ ports.VectorName, _ []float32, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+
+func (m *relTestVectorDB) SearchKeywords(_ context.Context, _ string, _ entities.FactType, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+
+func (m *relTestVectorDB) ListFiltered(_ context.Context, _ ports.FactListOptions) ([]entities.Fact, error) {
+	return nil, nil
+}
+
+func (m *relTestVectorDB) ListByEntities(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+
+func (m *relTestVectorDB) ListPending(_ context.Context, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListBySubject(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
+func (m *relTestVectorDB) ListByClaimKeys(_ context.Context, _ []string, _ int) ([]entities.Fact, error) {
+	return nil, nil
+}
 func (m *relTestVectorDB) ListBySource(_ context.Context, _ string, _ int) ([]entities.Fact, error) {
 	return nil, nil
 }
 
-func (m *relTestVectorDB) DeleteBySource(_ context.Context, _ string) error { return nil }
-func (m *relTestVectorDB) DeleteAll(_ context.Context) error                { return nil }
-func (m *relTestVectorDB) Count(_ context.Context) (uint64, error)          { return 0, nil }
+func (m *relTestVectorDB) CountBySubject(_ context.Context, _ []string) (uint64, error) {
+	return 0, nil
+}
+func (m *relTestVectorDB) DeleteBySource(_ context.Context, _ string) error            { return nil }
+func (m *relTestVectorDB) SetSourceArchived(_ context.Context, _ string, _ bool) error { return nil }
+func (m *relTestVectorDB) DeleteAll(_ context.Context) error                           { return nil }
+func (m *relTestVectorDB) Count(_ context.Context) (uint64, error)                     { return 0, nil }
 
 // relTestEmbedder is a mock Embedder for integration tests.
 type relTestEmbedder struct{}
@@ -36,7 +36,7 @@ func TestMain(m *testing.M) {
 	}
 
 	var err error
-	testRepo, err = qdrant.NewRepository(cfg)
+	testRepo, err = qdrant.NewRepository(&cfg)
 	if err != nil {
 		panic("failed to create repository: " + err.Error())
 	}